@@ -0,0 +1,63 @@
+#ifndef __OSM_BPF_HELPERS_H
+#define __OSM_BPF_HELPERS_H
+
+// Declarations of the eBPF helpers used by the programs of OSM, so that the programs only depend on the UAPI headers
+// of the kernel
+
+#include <linux/bpf.h>
+
+#define SEC(name) __attribute__((section(name), used))
+
+#ifndef NULL
+#define NULL ((void *)0)
+#endif
+
+#ifndef __always_inline
+#define __always_inline inline __attribute__((always_inline))
+#endif
+
+// The programs are built for little endian architectures only, which all the architectures OSM is built for are
+#define bpf_ntohl(x) __builtin_bswap32(x)
+#define bpf_htonl(x) __builtin_bswap32(x)
+
+#define bpf_ntohs(x) __builtin_bswap16(x)
+#define bpf_htons(x) __builtin_bswap16(x)
+
+#define AF_INET 2
+#define SOCK_STREAM 1
+#define SOL_IP 0
+
+// Option of the SOL_IP level returning the original destination of a connection redirected by iptables, from
+// linux/netfilter_ipv4.h
+#define SO_ORIGINAL_DST 80
+
+// IPv4 socket address, from linux/in.h
+struct sockaddr_in
+{
+  __u16 sin_family;
+  __u16 sin_port;
+  __u32 sin_addr;
+  __u8 __pad[8];
+};
+
+// Definition of the maps declared in the maps section, as read by github.com/cilium/ebpf
+struct bpf_map_def
+{
+  unsigned int type;
+  unsigned int key_size;
+  unsigned int value_size;
+  unsigned int max_entries;
+  unsigned int map_flags;
+};
+
+static void *(*bpf_map_lookup_elem)(void *map, const void *key) = (void *)BPF_FUNC_map_lookup_elem;
+static long (*bpf_map_update_elem)(void *map, const void *key, const void *value, __u64 flags) = (void *)BPF_FUNC_map_update_elem;
+static long (*bpf_map_delete_elem)(void *map, const void *key) = (void *)BPF_FUNC_map_delete_elem;
+static __u64 (*bpf_get_current_uid_gid)(void) = (void *)BPF_FUNC_get_current_uid_gid;
+static __u64 (*bpf_get_current_ancestor_cgroup_id)(int ancestor_level) = (void *)BPF_FUNC_get_current_ancestor_cgroup_id;
+static __u64 (*bpf_get_socket_cookie)(void *ctx) = (void *)BPF_FUNC_get_socket_cookie;
+static long (*bpf_sock_hash_update)(struct bpf_sock_ops *skops, void *map, void *key, __u64 flags) = (void *)BPF_FUNC_sock_hash_update;
+static long (*bpf_msg_redirect_hash)(struct sk_msg_md *msg, void *map, void *key, __u64 flags) = (void *)BPF_FUNC_msg_redirect_hash;
+static __u64 (*bpf_get_netns_cookie)(void *ctx) = (void *)BPF_FUNC_get_netns_cookie;
+
+#endif
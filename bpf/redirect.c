@@ -0,0 +1,203 @@
+// eBPF programs redirecting the outbound TCP connections of the applications of the meshed pods to the outbound
+// listener of their sidecar proxy, in place of the REDIRECT rules programmed by iptables.
+//
+// The connect4 program rewrites the destination of the connections of the applications to the outbound listener
+// before they are established, and records their original destination by socket cookie. The sockops program records
+// the original destination by local port once the port of the connection is bound. The getsockopt program returns the
+// original destination to the sidecar proxy when it queries SO_ORIGINAL_DST on the accepted connection, as the
+// original_dst listener filter of Envoy does.
+//
+// The connections to the outbound listener are not redirected by the iptables rules of the pods, which skip the
+// traffic to the loopback interface, so the rules keep redirecting the connections established before the programs
+// are attached or after they are detached.
+
+#include "helpers.h"
+
+// Maximum depth of the cgroups of the pods in the cgroup v2 hierarchy of the node, including the cgroups of the
+// container running the kubelet on nodes such as kind nodes
+#define MAX_POD_CGROUP_LEVEL 8
+
+#define LOCALHOST_IP4 0x7f000001
+
+// Pod whose outbound connections are redirected, written by the loader
+struct redirect_pod
+{
+  // User ID of the sidecar proxy of the pod, whose connections are not redirected
+  __u32 proxy_uid;
+};
+
+// Original destination of a redirected connection
+struct origin
+{
+  // Address and port in network byte order
+  __u32 ip4;
+  __u32 port;
+};
+
+// Key of the original destination of a redirected connection once its local port is bound
+struct origin_key
+{
+  // Cookie of the network namespace of the pod, since the connections to the loopback interface of all the pods share
+  // the same addresses
+  __u64 netns;
+  // Local port of the connection of the application in host byte order
+  __u32 port;
+  __u32 __pad;
+};
+
+// Settings of the programs, written by the loader in user space
+struct redirect_settings
+{
+  // Port of the outbound listener of the sidecar proxies
+  __u32 outbound_listener_port;
+};
+
+// Pods whose outbound connections are redirected, keyed by the ID of their cgroup
+struct bpf_map_def SEC("maps") osm_redirect_pods = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(__u64),
+    .value_size = sizeof(struct redirect_pod),
+    .max_entries = 4096,
+};
+
+// Original destinations of the redirected connections whose local port is not bound yet, keyed by socket cookie
+struct bpf_map_def SEC("maps") osm_origin_by_cookie = {
+    .type = BPF_MAP_TYPE_LRU_HASH,
+    .key_size = sizeof(__u64),
+    .value_size = sizeof(struct origin),
+    .max_entries = 65535,
+};
+
+// Original destinations of the redirected connections, keyed by network namespace and local port
+struct bpf_map_def SEC("maps") osm_origin_by_port = {
+    .type = BPF_MAP_TYPE_LRU_HASH,
+    .key_size = sizeof(struct origin_key),
+    .value_size = sizeof(struct origin),
+    .max_entries = 65535,
+};
+
+// Settings of the programs at index 0, written by the loader
+struct bpf_map_def SEC("maps") osm_redirect_settings = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(__u32),
+    .value_size = sizeof(struct redirect_settings),
+    .max_entries = 1,
+};
+
+// Returns the pod of the current process, or NULL if its outbound connections are not redirected
+static __always_inline struct redirect_pod *current_pod(void)
+{
+#pragma unroll
+  for (int level = 1; level <= MAX_POD_CGROUP_LEVEL; level++)
+  {
+    __u64 cgroup_id = bpf_get_current_ancestor_cgroup_id(level);
+    if (cgroup_id == 0)
+    {
+      return NULL;
+    }
+    struct redirect_pod *pod = bpf_map_lookup_elem(&osm_redirect_pods, &cgroup_id);
+    if (pod != NULL)
+    {
+      return pod;
+    }
+  }
+  return NULL;
+}
+
+SEC("cgroup/connect4")
+int osm_connect4(struct bpf_sock_addr *ctx)
+{
+  __u32 index = 0;
+  struct redirect_settings *settings;
+  struct redirect_pod *pod;
+  struct origin origin = {};
+  __u64 cookie;
+
+  if (ctx->type != SOCK_STREAM || (bpf_ntohl(ctx->user_ip4) >> 24) == 127)
+  {
+    return 1;
+  }
+  settings = bpf_map_lookup_elem(&osm_redirect_settings, &index);
+  pod = current_pod();
+  if (settings == NULL || pod == NULL || (__u32)bpf_get_current_uid_gid() == pod->proxy_uid)
+  {
+    return 1;
+  }
+
+  origin.ip4 = ctx->user_ip4;
+  origin.port = ctx->user_port;
+  cookie = bpf_get_socket_cookie(ctx);
+  if (bpf_map_update_elem(&osm_origin_by_cookie, &cookie, &origin, BPF_ANY) != 0)
+  {
+    // The connection is left to the iptables rules if its original destination cannot be recorded
+    return 1;
+  }
+
+  ctx->user_ip4 = bpf_htonl(LOCALHOST_IP4);
+  ctx->user_port = bpf_htons(settings->outbound_listener_port);
+  return 1;
+}
+
+SEC("sockops")
+int osm_redirect_sockops(struct bpf_sock_ops *skops)
+{
+  struct origin_key key = {};
+  struct origin *origin;
+  __u64 cookie;
+
+  // The local port of the connection is bound when the SYN is about to be sent
+  if (skops->op != BPF_SOCK_OPS_TCP_CONNECT_CB || skops->family != AF_INET)
+  {
+    return 0;
+  }
+  cookie = bpf_get_socket_cookie(skops);
+  origin = bpf_map_lookup_elem(&osm_origin_by_cookie, &cookie);
+  if (origin == NULL)
+  {
+    return 0;
+  }
+
+  key.netns = bpf_get_netns_cookie(skops);
+  key.port = skops->local_port;
+  bpf_map_update_elem(&osm_origin_by_port, &key, origin, BPF_ANY);
+  bpf_map_delete_elem(&osm_origin_by_cookie, &cookie);
+  return 0;
+}
+
+SEC("cgroup/getsockopt")
+int osm_getsockopt(struct bpf_sockopt *ctx)
+{
+  struct origin_key key = {};
+  struct origin *origin;
+  struct sockaddr_in *addr;
+  struct bpf_sock *sk = ctx->sk;
+
+  if (ctx->level != SOL_IP || ctx->optname != SO_ORIGINAL_DST || sk == NULL || sk->family != AF_INET)
+  {
+    return 1;
+  }
+
+  // The remote port of the accepted connection is the local port of the connection of the application
+  key.netns = bpf_get_netns_cookie(ctx);
+  key.port = bpf_ntohs(sk->dst_port);
+  origin = bpf_map_lookup_elem(&osm_origin_by_port, &key);
+  if (origin == NULL)
+  {
+    return 1;
+  }
+
+  addr = ctx->optval;
+  if ((void *)(addr + 1) > ctx->optval_end)
+  {
+    return 1;
+  }
+  addr->sin_family = AF_INET;
+  addr->sin_port = origin->port;
+  addr->sin_addr = origin->ip4;
+  ctx->optlen = sizeof(*addr);
+  ctx->retval = 0;
+  bpf_map_delete_elem(&osm_origin_by_port, &key);
+  return 1;
+}
+
+char __license[] SEC("license") = "Apache-2.0";
@@ -0,0 +1,166 @@
+// eBPF programs accelerating the local hops of the traffic of the meshed pods: the data sent on a TCP connection whose
+// both ends are sockets of meshed pods of the node is copied to the receive queue of the socket of its peer, bypassing
+// the TCP/IP stack of the kernel.
+//
+// The sockops program records the established sockets of the meshed pods in a sockhash map, keyed by their 4-tuple
+// as seen by their peer. The sk_msg program redirects each message sent on a recorded socket to its peer, if the peer
+// is recorded. Messages whose peer is not recorded are passed to the TCP/IP stack.
+
+#include "helpers.h"
+
+// Key of the sockets recorded in the sockhash map
+struct sock_key
+{
+  // Cookie of the network namespace of the socket for connections over the loopback interface, whose addresses are
+  // shared by all the pods, 0 otherwise since the addresses of the pods are unique on the node
+  __u64 netns;
+  __u32 local_ip4;
+  __u32 remote_ip4;
+  // Ports in host byte order
+  __u32 local_port;
+  __u32 remote_port;
+};
+
+// Settings of the programs, written by the loader in user space
+struct settings
+{
+  // Port of the inbound listener of the sidecar proxies, to which the iptables rules of the meshed pods redirect
+  // their inbound traffic
+  __u32 inbound_listener_port;
+};
+
+// Established sockets of the meshed pods
+struct bpf_map_def SEC("maps") osm_sock_hash = {
+    .type = BPF_MAP_TYPE_SOCKHASH,
+    .key_size = sizeof(struct sock_key),
+    .value_size = sizeof(__u32),
+    .max_entries = 65535,
+};
+
+// IP addresses of the meshed pods of the node, written by the loader
+struct bpf_map_def SEC("maps") osm_meshed_pods = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(__u32),
+    .value_size = sizeof(__u32),
+    .max_entries = 4096,
+};
+
+// Inbound ports of the meshed pods whose traffic is not redirected to the sidecar proxy by their iptables rules,
+// written by the loader
+struct bpf_map_def SEC("maps") osm_passthrough_ports = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(__u32),
+    .value_size = sizeof(__u32),
+    .max_entries = 64,
+};
+
+// Settings of the programs at index 0, written by the loader
+struct bpf_map_def SEC("maps") osm_settings = {
+    .type = BPF_MAP_TYPE_ARRAY,
+    .key_size = sizeof(__u32),
+    .value_size = sizeof(struct settings),
+    .max_entries = 1,
+};
+
+static __always_inline int is_loopback(__u32 ip4)
+{
+  return (bpf_ntohl(ip4) >> 24) == 127;
+}
+
+// Returns the port on which meshed pods receive the connections to the given port: the inbound listener port of their
+// sidecar proxy, unless the port is a passthrough port
+static __always_inline __u32 inbound_port(__u32 port)
+{
+  __u32 index = 0;
+  struct settings *settings = bpf_map_lookup_elem(&osm_settings, &index);
+  if (settings == NULL || bpf_map_lookup_elem(&osm_passthrough_ports, &port) != NULL)
+  {
+    return port;
+  }
+  return settings->inbound_listener_port;
+}
+
+// Fills the key of the socket with the given context and 4-tuple, as seen by its peer, and returns 1 if the connection
+// of the socket is local to the node and can be accelerated, 0 otherwise.
+//
+// The connections to another meshed pod are redirected to the inbound listener of its sidecar proxy by its iptables
+// rules, so the remote port of the client end of such connections is the inbound listener port as seen by the server
+// end. The server ends of these connections can only be bound to the inbound listener port or a passthrough port,
+// which are the ports left unchanged by the redirection, so the client end is the end whose local port is changed.
+static __always_inline int get_sock_key(void *ctx, __u32 local_ip4, __u32 local_port, __u32 remote_ip4, __u32 remote_port, struct sock_key *key)
+{
+  key->local_ip4 = local_ip4;
+  key->remote_ip4 = remote_ip4;
+  key->local_port = local_port;
+  key->remote_port = remote_port;
+
+  if (is_loopback(local_ip4) && is_loopback(remote_ip4))
+  {
+    key->netns = bpf_get_netns_cookie(ctx);
+    return 1;
+  }
+
+  key->netns = 0;
+  if (bpf_map_lookup_elem(&osm_meshed_pods, &remote_ip4) == NULL)
+  {
+    return 0;
+  }
+  if (local_ip4 != remote_ip4 && inbound_port(local_port) != local_port)
+  {
+    // Client end of a connection to another meshed pod
+    key->remote_port = inbound_port(remote_port);
+  }
+  return 1;
+}
+
+SEC("sockops")
+int osm_sockops(struct bpf_sock_ops *skops)
+{
+  struct sock_key key = {};
+
+  if (skops->op != BPF_SOCK_OPS_ACTIVE_ESTABLISHED_CB && skops->op != BPF_SOCK_OPS_PASSIVE_ESTABLISHED_CB)
+  {
+    return 0;
+  }
+  if (skops->family != AF_INET)
+  {
+    return 0;
+  }
+
+  // The remote port is in network byte order in the upper 16 bits of the field
+  if (get_sock_key(skops, skops->local_ip4, skops->local_port, skops->remote_ip4, bpf_ntohl(skops->remote_port), &key))
+  {
+    // Sockets colliding with a recorded socket are not recorded, their messages are passed to the TCP/IP stack
+    bpf_sock_hash_update(skops, &osm_sock_hash, &key, BPF_NOEXIST);
+  }
+  return 0;
+}
+
+SEC("sk_msg")
+int osm_sk_msg(struct sk_msg_md *msg)
+{
+  struct sock_key key = {};
+  struct sock_key peer = {};
+
+  if (msg->family != AF_INET)
+  {
+    return SK_PASS;
+  }
+  if (!get_sock_key(msg, msg->local_ip4, msg->local_port, msg->remote_ip4, bpf_ntohl(msg->remote_port), &key))
+  {
+    return SK_PASS;
+  }
+
+  peer.netns = key.netns;
+  peer.local_ip4 = key.remote_ip4;
+  peer.remote_ip4 = key.local_ip4;
+  peer.local_port = key.remote_port;
+  peer.remote_port = key.local_port;
+
+  // The message is redirected to the receive queue of the peer if it is recorded. Otherwise, the redirection fails and
+  // the message is passed to the TCP/IP stack, since the verdict is the one of the program and not of the helper.
+  bpf_msg_redirect_hash(msg, &osm_sock_hash, &peer, BPF_F_INGRESS);
+  return SK_PASS;
+}
+
+char __license[] SEC("license") = "Apache-2.0";
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const completionDescription = `
+This command outputs the shell completion script of the osm CLI for the
+given shell (bash, zsh or fish).
+
+Besides subcommands and flags, the completion scripts complete the
+namespaces, meshed pods and meshed services given as arguments to the osm
+CLI by querying the Kubernetes cluster of the current kubeconfig context
+when completion is requested.
+`
+
+const completionExample = `
+# Load the completion of the osm CLI in the current bash shell
+source <(osm completion bash)
+
+# Load the completion of the osm CLI in every new zsh shell
+osm completion zsh > "${fpath[1]}/_osm"
+
+# Load the completion of the osm CLI in every new fish shell
+osm completion fish > ~/.config/fish/completions/osm.fish
+`
+
+func newCompletionCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion SHELL",
+		Short:     "output the shell completion script",
+		Long:      completionDescription,
+		Example:   completionExample,
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletion(out)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			default:
+				return errors.Errorf("Unsupported shell %s", args[0])
+			}
+		},
+	}
+
+	return cmd
+}
+
+// completionFunc returns a cobra completion function completing the names returned by the given list function. The
+// Kubernetes client is only created when completion is requested, and any error results in no completion.
+func completionFunc(list func(kubernetes.Interface, *cobra.Command, []string) ([]string, error)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		config, err := settings.RESTClientGetter().ToRESTConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		names, err := list(clientset, cmd, args)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// filterCompletions returns the sorted names starting with the given prefix
+func filterCompletions(names []string, toComplete string) []string {
+	var completions []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
+
+// completeArgs returns a cobra completion function completing positional arguments with the given function until the
+// command has the given number of arguments
+func completeArgs(maxArgs int, complete func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeNamespaces completes the names of all the namespaces in the cluster
+var completeNamespaces = completionFunc(listNamespaces)
+
+// completeMeshedNamespaces completes the names of the namespaces monitored by the mesh given by the mesh-name flag of
+// the command, or by any mesh if the command does not have this flag
+var completeMeshedNamespaces = completionFunc(listMeshedNamespaces)
+
+// completeMeshedPods completes the names of the meshed pods in the namespace given by the namespace flag of the command
+var completeMeshedPods = completionFunc(listMeshedPods)
+
+// completeNamespacedMeshedPods completes the meshed pods of all the namespaces as <namespace>/<pod>
+var completeNamespacedMeshedPods = completionFunc(listNamespacedMeshedPods)
+
+// completeServiceAccounts completes the names of the service accounts in the namespace given by the namespace flag of
+// the command
+var completeServiceAccounts = completionFunc(listServiceAccounts)
+
+// completeMeshedServices completes the services of the namespaces monitored by any mesh as <namespace>/<name>
+var completeMeshedServices = completionFunc(listMeshedServices)
+
+func listNamespaces(clientSet kubernetes.Interface, _ *cobra.Command, _ []string) ([]string, error) {
+	namespaces, err := clientSet.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+func listMeshedNamespaces(clientSet kubernetes.Interface, cmd *cobra.Command, _ []string) ([]string, error) {
+	selector := constants.OSMKubeResourceMonitorAnnotation
+	if meshNameFlag := cmd.Flags().Lookup("mesh-name"); meshNameFlag != nil && meshNameFlag.Value.String() != "" {
+		selector = fmt.Sprintf("%s=%s", constants.OSMKubeResourceMonitorAnnotation, meshNameFlag.Value.String())
+	}
+
+	namespaces, err := clientSet.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+func listMeshedPods(clientSet kubernetes.Interface, cmd *cobra.Command, _ []string) ([]string, error) {
+	pods, err := clientSet.CoreV1().Pods(namespaceFlagValue(cmd)).List(context.Background(), metav1.ListOptions{LabelSelector: constants.EnvoyUniqueIDLabelName})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return names, nil
+}
+
+func listServiceAccounts(clientSet kubernetes.Interface, cmd *cobra.Command, _ []string) ([]string, error) {
+	serviceAccounts, err := clientSet.CoreV1().ServiceAccounts(namespaceFlagValue(cmd)).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, sa := range serviceAccounts.Items {
+		names = append(names, sa.Name)
+	}
+	return names, nil
+}
+
+func listNamespacedMeshedPods(clientSet kubernetes.Interface, _ *cobra.Command, _ []string) ([]string, error) {
+	pods, err := clientSet.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{LabelSelector: constants.EnvoyUniqueIDLabelName})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Namespace+namespaceSeparator+pod.Name)
+	}
+	return names, nil
+}
+
+func listMeshedServices(clientSet kubernetes.Interface, cmd *cobra.Command, args []string) ([]string, error) {
+	namespaces, err := listMeshedNamespaces(clientSet, cmd, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ns := range namespaces {
+		services, err := clientSet.CoreV1().Services(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range services.Items {
+			names = append(names, svc.Namespace+namespaceSeparator+svc.Name)
+		}
+	}
+	return names, nil
+}
+
+// namespaceFlagValue returns the value of the namespace flag of the command, or the default namespace if it does not
+// have this flag
+func namespaceFlagValue(cmd *cobra.Command) string {
+	if namespaceFlag := cmd.Flags().Lookup("namespace"); namespaceFlag != nil {
+		return namespaceFlag.Value.String()
+	}
+	return metav1.NamespaceDefault
+}
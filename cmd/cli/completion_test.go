@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestCompletionCmd(t *testing.T) {
+	assert := tassert.New(t)
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out := new(bytes.Buffer)
+		root := &cobra.Command{Use: "osm"}
+		cmd := newCompletionCmd(out)
+		root.AddCommand(cmd)
+		root.SetArgs([]string{"completion", shell})
+
+		assert.Nil(root.Execute(), shell)
+		assert.Contains(out.String(), "osm", shell)
+	}
+
+	root := &cobra.Command{Use: "osm", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(newCompletionCmd(new(bytes.Buffer)))
+	root.SetArgs([]string{"completion", "tcsh"})
+	assert.NotNil(root.Execute())
+}
+
+func TestFilterCompletions(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal([]string{"bookbuyer", "bookstore", "bookwarehouse"}, filterCompletions([]string{"bookstore", "bookbuyer", "bookwarehouse"}, "book"))
+	assert.Equal([]string{"bookbuyer"}, filterCompletions([]string{"bookstore", "bookbuyer"}, "bookb"))
+	assert.Nil(filterCompletions([]string{"bookstore"}, "osm"))
+}
+
+func TestCompletionListers(t *testing.T) {
+	assert := tassert.New(t)
+
+	fakeClientSet := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMesh}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-mesh", Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: "other"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unmeshed"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bookstore-v1", Namespace: "bookstore", Labels: map[string]string{constants.EnvoyUniqueIDLabelName: "test"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "no-sidecar", Namespace: "bookstore"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "curl", Namespace: "unmeshed", Labels: map[string]string{constants.EnvoyUniqueIDLabelName: "test"}}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "curl", Namespace: "unmeshed"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"}},
+	)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("mesh-name", "", "")
+	cmd.Flags().String("namespace", metav1.NamespaceDefault, "")
+
+	names, err := listNamespaces(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"bookstore", "other-mesh", "unmeshed"}, names)
+
+	// Namespaces monitored by any mesh are listed when the mesh name is not set
+	names, err = listMeshedNamespaces(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"bookstore", "other-mesh"}, names)
+
+	// Only the namespaces monitored by the given mesh are listed when the mesh name is set
+	assert.Nil(cmd.Flags().Set("mesh-name", testMesh))
+	names, err = listMeshedNamespaces(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"bookstore"}, names)
+
+	names, err = listMeshedServices(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"bookstore/bookstore"}, names)
+
+	names, err = listNamespacedMeshedPods(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"bookstore/bookstore-v1", "unmeshed/curl"}, names)
+
+	// Pods and service accounts are listed in the namespace given by the namespace flag
+	names, err = listMeshedPods(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.Empty(names)
+	assert.Nil(cmd.Flags().Set("namespace", "bookstore"))
+	names, err = listMeshedPods(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"bookstore-v1"}, names)
+
+	names, err = listServiceAccounts(fakeClientSet, cmd, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"bookstore"}, names)
+}
+
+func TestCompleteArgs(t *testing.T) {
+	assert := tassert.New(t)
+
+	complete := completeArgs(1, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"bookstore"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	completions, directive := complete(&cobra.Command{}, nil, "")
+	assert.Equal([]string{"bookstore"}, completions)
+	assert.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+
+	// No more arguments are completed once the command has all of its arguments
+	completions, directive = complete(&cobra.Command{}, []string{"bookstore"}, "")
+	assert.Nil(completions)
+	assert.Equal(cobra.ShellCompDirectiveNoFileComp, directive)
+}
@@ -57,19 +57,23 @@ func newEnvCmd(out io.Writer) *cobra.Command {
 		Short: "osm client environment information",
 		Long:  envHelp,
 		Args:  cobra.NoArgs,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			envVars := settings.EnvVars()
 
-			// Sort the variables by alphabetical order.
-			// This allows for a constant output across calls to 'osm env'.
-			var keys []string
-			for k := range envVars {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, k := range keys {
-				fmt.Fprintf(out, "%s=\"%s\"\n", k, envVars[k])
-			}
+			// The variables are output as a JSON or YAML object keyed by variable name
+			return writeOutput(out, settings.Output(), envVars, func() error {
+				// Sort the variables by alphabetical order.
+				// This allows for a constant output across calls to 'osm env'.
+				var keys []string
+				for k := range envVars {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintf(out, "%s=\"%s\"\n", k, envVars[k])
+				}
+				return nil
+			})
 		},
 	}
 	return cmd
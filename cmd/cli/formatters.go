@@ -1,8 +1,13 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -13,6 +18,48 @@ const (
 	flags    = 0
 )
 
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
 func newTabWriter(out io.Writer) *tabwriter.Writer {
 	return tabwriter.NewWriter(out, minwidth, tabwidth, padding, padchar, flags)
 }
+
+// validateOutput returns an error if the given output format is not supported
+func validateOutput(format string) error {
+	switch format {
+	case "", outputJSON, outputYAML:
+		return nil
+	default:
+		return errors.Errorf("Invalid output format %q, must be %s or %s", format, outputJSON, outputYAML)
+	}
+}
+
+// writeOutput writes the given result of a command to out in the given output format, json or yaml. The result is
+// written in its human-readable form by writeText when no output format is given. The JSON field names of the result
+// are its schema in both formats.
+func writeOutput(out io.Writer, format string, result interface{}, writeText func() error) error {
+	if err := validateOutput(format); err != nil {
+		return err
+	}
+
+	switch format {
+	case outputJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.Errorf("Error marshalling output to JSON: %s", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case outputYAML:
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return errors.Errorf("Error marshalling output to YAML: %s", err)
+		}
+		fmt.Fprint(out, string(data))
+	default:
+		return writeText()
+	}
+	return nil
+}
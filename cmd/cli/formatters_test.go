@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestWriteOutput(t *testing.T) {
+	type result struct {
+		MeshName string   `json:"mesh_name"`
+		Pods     []string `json:"pods"`
+	}
+
+	testCases := []struct {
+		name        string
+		format      string
+		expectedOut string
+		expectedErr bool
+	}{
+		{
+			name:        "human-readable",
+			format:      "",
+			expectedOut: "osm: bookstore-v1\n",
+		},
+		{
+			name:        "json",
+			format:      outputJSON,
+			expectedOut: "{\n  \"mesh_name\": \"osm\",\n  \"pods\": [\n    \"bookstore-v1\"\n  ]\n}\n",
+		},
+		{
+			name:        "yaml",
+			format:      outputYAML,
+			expectedOut: "mesh_name: osm\npods:\n- bookstore-v1\n",
+		},
+		{
+			name:        "invalid format",
+			format:      "xml",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			err := writeOutput(out, tc.format, result{MeshName: "osm", Pods: []string{"bookstore-v1"}}, func() error {
+				out.WriteString("osm: bookstore-v1\n")
+				return nil
+			})
+
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedOut, out.String())
+		})
+	}
+}
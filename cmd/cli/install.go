@@ -52,6 +52,27 @@ Example:
 The mesh name is used in various ways like for naming Kubernetes resources as
 well as for adding a Kubernetes Namespace to the list of Namespaces a control
 plane should watch for sidecar injection of Envoy proxies.
+
+With the --gitops flag, the manifests of the control plane are written to
+stdout instead of being installed, so they can be committed to a Git
+repository and applied by a GitOps tool such as Argo CD or Flux. The
+manifests only depend on the given options, so rendering them again with
+the same options yields the same manifests.
+
+Example:
+  $ osm install --gitops > osm.yaml
+`
+
+// gitopsNamespaceManifest is the manifest of the namespace of the control plane written with the --gitops flag.
+// Helm labels the namespace it creates with its name, which the mutating webhook relies on to never inject
+// pods in the control plane namespace with a sidecar, so the rendered namespace is labeled the same way.
+const gitopsNamespaceManifest = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+  labels:
+    name: %s
 `
 const (
 	defaultCertificateManager            = "tresor"
@@ -113,6 +134,7 @@ type installCmd struct {
 	chartRequested                *chart.Chart
 	setOptions                    []string
 	atomic                        bool
+	gitops                        bool
 
 	// Toggle to enable/disable Prometheus installation
 	deployPrometheus bool
@@ -143,6 +165,11 @@ func newInstallCmd(config *helm.Configuration, out io.Writer) *cobra.Command {
 		Short: "install osm control plane",
 		Long:  installDesc,
 		RunE: func(_ *cobra.Command, args []string) error {
+			if inst.gitops {
+				// Manifests are rendered without accessing the cluster
+				return inst.run(config)
+			}
+
 			kubeconfig, err := settings.RESTClientGetter().ToRESTConfig()
 			if err != nil {
 				return errors.Errorf("Error fetching kubeconfig: %s", err)
@@ -187,6 +214,7 @@ func newInstallCmd(config *helm.Configuration, out io.Writer) *cobra.Command {
 	f.DurationVar(&inst.timeout, "timeout", 5*time.Minute, "Time to wait for installation and resources in a ready state, zero means no timeout")
 	f.StringArrayVar(&inst.setOptions, "set", nil, "Set arbitrary chart values not settable by another flag (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.BoolVar(&inst.atomic, "atomic", false, "Automatically clean up resources if installation fails")
+	f.BoolVar(&inst.gitops, "gitops", false, "Write the manifests of the control plane to stdout for a GitOps tool to apply instead of installing them")
 
 	return cmd
 }
@@ -202,6 +230,10 @@ func (i *installCmd) run(config *helm.Configuration) error {
 		return err
 	}
 
+	if i.gitops {
+		return i.renderManifests(config, values)
+	}
+
 	installClient := helm.NewInstall(config)
 	installClient.ReleaseName = i.meshName
 	installClient.Namespace = settings.Namespace()
@@ -216,6 +248,28 @@ func (i *installCmd) run(config *helm.Configuration) error {
 	fmt.Fprintf(i.out, "OSM installed successfully in namespace [%s] with mesh name [%s]\n", settings.Namespace(), i.meshName)
 	return nil
 }
+
+// renderManifests writes the manifests of the control plane, including the CRDs and the namespace, to the output.
+// The webhook configurations are rendered without a CA bundle, and the certificates and Envoy bootstrap configs are
+// not rendered: they are generated at runtime by the control plane, which adopts the webhook configurations applied
+// by the GitOps tool.
+func (i *installCmd) renderManifests(config *helm.Configuration, values map[string]interface{}) error {
+	installClient := helm.NewInstall(config)
+	installClient.ReleaseName = i.meshName
+	installClient.Namespace = settings.Namespace()
+	installClient.DryRun = true
+	installClient.ClientOnly = true
+	installClient.Replace = true
+	installClient.IncludeCRDs = true
+	rel, err := installClient.Run(i.chartRequested, values)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(i.out, gitopsNamespaceManifest, settings.Namespace(), settings.Namespace())
+	fmt.Fprint(i.out, rel.Manifest)
+	return nil
+}
 func (i *installCmd) loadOSMChart() error {
 	var err error
 	if i.chartPath != "" {
@@ -305,6 +359,21 @@ func (i *installCmd) validateOptions() error {
 		}
 	}
 
+	// validate the envoy log level type
+	if err := isValidEnvoyLogLevel(i.envoyLogLevel); err != nil {
+		return err
+	}
+
+	// validate certificate validity duration
+	if _, err := time.ParseDuration(i.serviceCertValidityDuration); err != nil {
+		return err
+	}
+
+	// the cluster is not accessed when rendering manifests for a GitOps tool, which owns the state of the cluster
+	if i.gitops {
+		return nil
+	}
+
 	// ensure no control plane exists in cluster with the same meshName
 	deploymentsClient := i.clientSet.AppsV1().Deployments("") // Get deployments from all namespaces
 	labelSelector := metav1.LabelSelector{MatchLabels: map[string]string{"meshName": i.meshName}}
@@ -332,16 +401,6 @@ func (i *installCmd) validateOptions() error {
 		return fmt.Errorf("Error ensuring no osm-controller running in namespace %s:%s", settings.Namespace(), err)
 	}
 
-	// validate the envoy log level type
-	if err := isValidEnvoyLogLevel(i.envoyLogLevel); err != nil {
-		return err
-	}
-
-	// validate certificate validity duration
-	if _, err := time.ParseDuration(i.serviceCertValidityDuration); err != nil {
-		return err
-	}
-
 	osmControllerDeployments, err = getControllerDeployments(i.clientSet)
 	if err != nil {
 		return err
@@ -688,6 +688,35 @@ func TestEnforceSingleMesh(t *testing.T) {
 	assert.Equal(out.String(), "OSM installed successfully in namespace [osm-system] with mesh name [osm]\n")
 }
 
+func TestInstallGitops(t *testing.T) {
+	assert := tassert.New(t)
+
+	render := func() string {
+		out := new(bytes.Buffer)
+		config := &helm.Configuration{
+			Log: func(format string, v ...interface{}) {},
+		}
+
+		install := getDefaultInstallCmd(out)
+		install.chartPath = testChartPath
+		install.gitops = true
+		// The cluster must not be accessed when rendering manifests
+		install.clientSet = nil
+
+		err := install.run(config)
+		assert.Nil(err)
+		return out.String()
+	}
+
+	manifests := render()
+	assert.Contains(manifests, fmt.Sprintf(gitopsNamespaceManifest, settings.Namespace(), settings.Namespace()))
+	assert.Contains(manifests, "kind: Deployment")
+	assert.NotContains(manifests, "OSM installed successfully")
+
+	// Rendering the manifests again with the same options must yield the same manifests
+	assert.Equal(manifests, render())
+}
+
 func TestEnforceSingleMeshRejectsNewMesh(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -22,9 +22,22 @@ This command will list all the osm control planes running in a Kubernetes cluste
 
 type meshListCmd struct {
 	out       io.Writer
+	output    string
 	clientSet kubernetes.Interface
 }
 
+// meshListOutput is the output of the mesh list command
+type meshListOutput struct {
+	Meshes []meshListItem `json:"meshes"`
+}
+
+// meshListItem is a control plane listed by the mesh list command
+type meshListItem struct {
+	Name           string   `json:"name"`
+	Namespace      string   `json:"namespace"`
+	ControllerPods []string `json:"controller_pods"`
+}
+
 func newMeshList(out io.Writer) *cobra.Command {
 	meshList := &meshListCmd{
 		out: out,
@@ -45,6 +58,7 @@ func newMeshList(out io.Writer) *cobra.Command {
 				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
 			}
 			meshList.clientSet = clientset
+			meshList.output = settings.Output()
 			return meshList.run()
 		},
 	}
@@ -57,23 +71,34 @@ func (l *meshListCmd) run() error {
 	if err != nil {
 		return errors.Errorf("Could not list deployments %v", err)
 	}
-	if len(list.Items) == 0 {
-		fmt.Fprintf(l.out, "No control planes found\n")
-		return nil
-	}
-
-	w := newTabWriter(l.out)
 
-	fmt.Fprintln(w, "\nMESH NAME\tNAMESPACE\tCONTROLLER PODS")
+	result := meshListOutput{Meshes: []meshListItem{}}
 	for _, elem := range list.Items {
 		m := elem.ObjectMeta.Labels["meshName"]
 		ns := elem.ObjectMeta.Namespace
 		x := getNamespacePods(l.clientSet, m, ns)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", m, ns, strings.Join(x["Pods"], ","))
+		pods := x["Pods"]
+		if pods == nil {
+			pods = []string{}
+		}
+		result.Meshes = append(result.Meshes, meshListItem{Name: m, Namespace: ns, ControllerPods: pods})
 	}
-	_ = w.Flush()
 
-	return nil
+	return writeOutput(l.out, l.output, result, func() error {
+		if len(result.Meshes) == 0 {
+			fmt.Fprintf(l.out, "No control planes found\n")
+			return nil
+		}
+
+		w := newTabWriter(l.out)
+
+		fmt.Fprintln(w, "\nMESH NAME\tNAMESPACE\tCONTROLLER PODS")
+		for _, mesh := range result.Meshes {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", mesh.Name, mesh.Namespace, strings.Join(mesh.ControllerPods, ","))
+		}
+		_ = w.Flush()
+		return nil
+	})
 }
 
 // getNamespacePods returns a map of controller pods
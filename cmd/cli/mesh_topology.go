@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/pkg/errors"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiAccessClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned"
+	smiSplitClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+const meshTopologyDescription = `
+This command exports the service graph declared by the traffic policies of a
+mesh, so that what the mesh allows can be visualized and reviewed.
+
+In SMI traffic policy mode, the graph has an edge from each source to the
+destination of every SMI TrafficTarget that has not expired, labeled with the
+routes it allows. In permissive traffic policy mode, the graph has an edge
+between every pair of service accounts of the meshed pods, since all traffic is
+allowed. SMI TrafficSplits are represented by edges from their root service to
+each of their backends, labeled with the weight of the backend.
+
+Only the policies in the namespaces monitored by the mesh are exported.
+
+The graph can be exported in the following formats:
+- dot: Graphviz DOT language, rendered with 'dot -Tsvg'
+- d2: D2 language, rendered with 'd2'
+- json: nodes and edges of the graph in JSON
+`
+
+const meshTopologyExample = `
+# Render the topology of the mesh named 'osm' as an SVG image with Graphviz
+osm mesh topology | dot -Tsvg > topology.svg
+
+# Export the topology of the mesh named 'my-mesh' in JSON
+osm mesh topology --mesh-name my-mesh --format json
+`
+
+const (
+	topologyFormatDot  = "dot"
+	topologyFormatD2   = "d2"
+	topologyFormatJSON = "json"
+
+	// Kinds of the edges of the topology
+	topologyEdgeAllow      = "allow"
+	topologyEdgeSplit      = "split"
+	topologyEdgePermissive = "permissive"
+
+	serviceKind = "Service"
+)
+
+// topologyNode is a service account or a service of the topology
+type topologyNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// topologyEdge is the traffic allowed or routed from a node to another by a policy
+type topologyEdge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Kind   string   `json:"kind"`
+	Policy string   `json:"policy,omitempty"`
+	Routes []string `json:"routes,omitempty"`
+	Weight *int     `json:"weight,omitempty"`
+}
+
+// meshTopology is the service graph declared by the traffic policies of a mesh
+type meshTopology struct {
+	MeshName                    string         `json:"mesh_name"`
+	PermissiveTrafficPolicyMode bool           `json:"permissive_traffic_policy_mode"`
+	Nodes                       []topologyNode `json:"nodes"`
+	Edges                       []topologyEdge `json:"edges"`
+}
+
+type meshTopologyCmd struct {
+	out             io.Writer
+	meshName        string
+	format          string
+	output          string
+	clientSet       kubernetes.Interface
+	smiAccessClient smiAccessClient.Interface
+	smiSplitClient  smiSplitClient.Interface
+}
+
+func newMeshTopology(out io.Writer) *cobra.Command {
+	topologyCmd := &meshTopologyCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "topology",
+		Short: "export the service graph declared by traffic policies",
+		Long:  meshTopologyDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			switch topologyCmd.format {
+			case topologyFormatDot, topologyFormatD2, topologyFormatJSON:
+			default:
+				return errors.Errorf("Invalid format %q, must be one of %s, %s or %s", topologyCmd.format, topologyFormatDot, topologyFormatD2, topologyFormatJSON)
+			}
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			topologyCmd.clientSet = clientset
+
+			accessClient, err := smiAccessClient.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not initialize SMI Access client: %s", err)
+			}
+			topologyCmd.smiAccessClient = accessClient
+
+			splitClient, err := smiSplitClient.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not initialize SMI Split client: %s", err)
+			}
+			topologyCmd.smiSplitClient = splitClient
+			topologyCmd.output = settings.Output()
+
+			return topologyCmd.run()
+		},
+		Example: meshTopologyExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&topologyCmd.meshName, "mesh-name", defaultMeshName, "name of the mesh to export the topology of")
+	f.StringVar(&topologyCmd.format, "format", topologyFormatDot, "output format, one of dot, d2 or json")
+
+	return cmd
+}
+
+func (cmd *meshTopologyCmd) run() error {
+	topology, err := cmd.getTopology()
+	if err != nil {
+		return err
+	}
+
+	// The global --output flag takes precedence over the graph format
+	if cmd.output != "" {
+		return writeOutput(cmd.out, cmd.output, topology, nil)
+	}
+
+	switch cmd.format {
+	case topologyFormatD2:
+		writeTopologyD2(cmd.out, topology)
+	case topologyFormatJSON:
+		jsonTopology, err := json.MarshalIndent(topology, "", "  ")
+		if err != nil {
+			return errors.Errorf("Error marshalling topology: %s", err)
+		}
+		fmt.Fprintln(cmd.out, string(jsonTopology))
+	default:
+		writeTopologyDot(cmd.out, topology)
+	}
+	return nil
+}
+
+// getTopology returns the service graph declared by the traffic policies in the namespaces monitored by the mesh
+func (cmd *meshTopologyCmd) getTopology() (*meshTopology, error) {
+	permissiveMode, err := isPermissiveModeEnabled(cmd.clientSet)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceList, err := cmd.clientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constants.OSMKubeResourceMonitorAnnotation, cmd.meshName),
+	})
+	if err != nil {
+		return nil, errors.Errorf("Error listing namespaces monitored by mesh %s: %s", cmd.meshName, err)
+	}
+	monitoredNamespaces := mapset.NewSet()
+	for _, ns := range namespaceList.Items {
+		monitoredNamespaces.Add(ns.Name)
+	}
+
+	topology := &meshTopology{
+		MeshName:                    cmd.meshName,
+		PermissiveTrafficPolicyMode: permissiveMode,
+	}
+	nodes := make(map[string]topologyNode)
+
+	if permissiveMode {
+		if err := cmd.addPermissiveEdges(topology, nodes, monitoredNamespaces); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := cmd.addTrafficTargetEdges(topology, nodes, monitoredNamespaces); err != nil {
+			return nil, err
+		}
+	}
+	if err := cmd.addTrafficSplitEdges(topology, nodes, monitoredNamespaces); err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].ID < topology.Nodes[j].ID
+	})
+	sort.SliceStable(topology.Edges, func(i, j int) bool {
+		if topology.Edges[i].From != topology.Edges[j].From {
+			return topology.Edges[i].From < topology.Edges[j].From
+		}
+		if topology.Edges[i].To != topology.Edges[j].To {
+			return topology.Edges[i].To < topology.Edges[j].To
+		}
+		return topology.Edges[i].Policy < topology.Edges[j].Policy
+	})
+
+	return topology, nil
+}
+
+// addPermissiveEdges adds an edge between every pair of service accounts of the meshed pods, allowed to communicate
+// with each other in permissive traffic policy mode
+func (cmd *meshTopologyCmd) addPermissiveEdges(topology *meshTopology, nodes map[string]topologyNode, monitoredNamespaces mapset.Set) error {
+	svcAccountIDs := mapset.NewSet()
+	for ns := range monitoredNamespaces.Iter() {
+		pods, err := cmd.clientSet.CoreV1().Pods(ns.(string)).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return errors.Errorf("Error listing pods in namespace %s: %s", ns, err)
+		}
+		for _, pod := range pods.Items {
+			if !isMeshedPod(pod) {
+				continue
+			}
+			svcAccountIDs.Add(addTopologyNode(nodes, serviceAccountKind, pod.Namespace, pod.Spec.ServiceAccountName))
+		}
+	}
+
+	for from := range svcAccountIDs.Iter() {
+		for to := range svcAccountIDs.Iter() {
+			if from == to {
+				continue
+			}
+			topology.Edges = append(topology.Edges, topologyEdge{
+				From: from.(string),
+				To:   to.(string),
+				Kind: topologyEdgePermissive,
+			})
+		}
+	}
+	return nil
+}
+
+// addTrafficTargetEdges adds an edge from each source to the destination of the TrafficTargets that have not expired
+// and apply at the current time
+func (cmd *meshTopologyCmd) addTrafficTargetEdges(topology *meshTopology, nodes map[string]topologyNode, monitoredNamespaces mapset.Set) error {
+	trafficTargets, err := cmd.smiAccessClient.AccessV1alpha3().TrafficTargets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Errorf("Error listing SMI TrafficTarget policies: %s", err)
+	}
+
+	for i := range trafficTargets.Items {
+		trafficTarget := &trafficTargets.Items[i]
+		if !monitoredNamespaces.Contains(trafficTarget.Namespace) {
+			continue
+		}
+		// Expired TrafficTargets, or with an invalid expiry, do not grant access
+		if expiry, expires, err := smi.GetTrafficTargetExpiry(trafficTarget); err != nil || (expires && !time.Now().Before(expiry)) {
+			continue
+		}
+		// TrafficTargets applying on a schedule only grant access during their time windows
+		if schedule, scheduled, err := smi.GetPolicySchedule(trafficTarget); err != nil || (scheduled && !schedule.IsActive(time.Now())) {
+			continue
+		}
+
+		spec := trafficTarget.Spec
+		if spec.Destination.Kind != serviceAccountKind {
+			continue
+		}
+		to := addTopologyNode(nodes, serviceAccountKind, spec.Destination.Namespace, spec.Destination.Name)
+		routes := getTrafficTargetRoutes(trafficTarget)
+		for _, source := range spec.Sources {
+			if source.Kind != serviceAccountKind {
+				continue
+			}
+			topology.Edges = append(topology.Edges, topologyEdge{
+				From:   addTopologyNode(nodes, serviceAccountKind, source.Namespace, source.Name),
+				To:     to,
+				Kind:   topologyEdgeAllow,
+				Policy: trafficTarget.Namespace + namespaceSeparator + trafficTarget.Name,
+				Routes: routes,
+			})
+		}
+	}
+	return nil
+}
+
+// addTrafficSplitEdges adds an edge from the root service of the TrafficSplits applying at the current time to each of
+// their backends
+func (cmd *meshTopologyCmd) addTrafficSplitEdges(topology *meshTopology, nodes map[string]topologyNode, monitoredNamespaces mapset.Set) error {
+	trafficSplits, err := cmd.smiSplitClient.SplitV1alpha2().TrafficSplits(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Errorf("Error listing SMI TrafficSplit policies: %s", err)
+	}
+
+	for i := range trafficSplits.Items {
+		trafficSplit := &trafficSplits.Items[i]
+		if !monitoredNamespaces.Contains(trafficSplit.Namespace) {
+			continue
+		}
+		// TrafficSplits applying on a schedule only split traffic during their time windows
+		if schedule, scheduled, err := smi.GetPolicySchedule(trafficSplit); err != nil || (scheduled && !schedule.IsActive(time.Now())) {
+			continue
+		}
+		from := addTopologyNode(nodes, serviceKind, trafficSplit.Namespace, trafficSplit.Spec.Service)
+		for _, backend := range trafficSplit.Spec.Backends {
+			weight := backend.Weight
+			topology.Edges = append(topology.Edges, topologyEdge{
+				From:   from,
+				To:     addTopologyNode(nodes, serviceKind, trafficSplit.Namespace, backend.Service),
+				Kind:   topologyEdgeSplit,
+				Policy: trafficSplit.Namespace + namespaceSeparator + trafficSplit.Name,
+				Weight: &weight,
+			})
+		}
+	}
+	return nil
+}
+
+// addTopologyNode adds the node of the given kind, namespace and name if it was not already added, and returns its ID
+func addTopologyNode(nodes map[string]topologyNode, kind, namespace, name string) string {
+	id := fmt.Sprintf("%s:%s%s%s", kind, namespace, namespaceSeparator, name)
+	if _, ok := nodes[id]; !ok {
+		nodes[id] = topologyNode{
+			ID:        id,
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+		}
+	}
+	return id
+}
+
+// getTrafficTargetRoutes returns the routes allowed by the rules of the given TrafficTarget, Ex.
+// HTTPRouteGroup bookstore-service-routes: buy-a-book,books-bought
+func getTrafficTargetRoutes(trafficTarget *smiAccess.TrafficTarget) []string {
+	var routes []string
+	for _, rule := range trafficTarget.Spec.Rules {
+		route := fmt.Sprintf("%s %s", rule.Kind, rule.Name)
+		if len(rule.Matches) > 0 {
+			route += ": " + strings.Join(rule.Matches, ",")
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// getTopologyNodeLabel returns the label of the given node, Ex. bookstore/bookstore-v1 for a service, and the
+// description of the service accounts matched by a wildcard source
+func getTopologyNodeLabel(node topologyNode) string {
+	switch {
+	case node.Namespace == smi.WildcardIdentity:
+		return "any service account"
+	case node.Name == smi.WildcardIdentity:
+		return fmt.Sprintf("any service account in %s", node.Namespace)
+	default:
+		return node.Namespace + namespaceSeparator + node.Name
+	}
+}
+
+// getTopologyEdgeLabel returns the label of the given edge: the policy and the routes it allows, or the weight of the
+// backend of a TrafficSplit
+func getTopologyEdgeLabel(edge topologyEdge) string {
+	lines := []string{edge.Policy}
+	lines = append(lines, edge.Routes...)
+	if edge.Weight != nil {
+		lines = append(lines, fmt.Sprintf("weight %d", *edge.Weight))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// getTopologyTitle returns the title of the graph of the given topology
+func getTopologyTitle(topology *meshTopology) string {
+	if topology.PermissiveTrafficPolicyMode {
+		return fmt.Sprintf("Mesh %s (permissive traffic policy mode)", topology.MeshName)
+	}
+	return fmt.Sprintf("Mesh %s (SMI traffic policy mode)", topology.MeshName)
+}
+
+// writeTopologyDot writes the given topology in the Graphviz DOT language. Service accounts are drawn as boxes and
+// services as ellipses, and the edges of TrafficSplits are dashed.
+func writeTopologyDot(out io.Writer, topology *meshTopology) {
+	fmt.Fprintf(out, "digraph %s {\n", strconv.Quote(topology.MeshName))
+	fmt.Fprintf(out, "  label=%s;\n", strconv.Quote(getTopologyTitle(topology)))
+	fmt.Fprintln(out, "  rankdir=LR;")
+	for _, node := range topology.Nodes {
+		shape := "box"
+		if node.Kind == serviceKind {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(out, "  %s [label=%s, shape=%s];\n", strconv.Quote(node.ID), strconv.Quote(getTopologyNodeLabel(node)), shape)
+	}
+	for _, edge := range topology.Edges {
+		attributes := []string{fmt.Sprintf("label=%s", strconv.Quote(getTopologyEdgeLabel(edge)))}
+		if edge.Kind == topologyEdgeSplit {
+			attributes = append(attributes, "style=dashed")
+		}
+		fmt.Fprintf(out, "  %s -> %s [%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), strings.Join(attributes, ", "))
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// writeTopologyD2 writes the given topology in the D2 language. Service accounts are drawn as rectangles and
+// services as ovals, and the edges of TrafficSplits are dashed.
+func writeTopologyD2(out io.Writer, topology *meshTopology) {
+	fmt.Fprintf(out, "# %s\n", getTopologyTitle(topology))
+	fmt.Fprintln(out, "direction: right")
+	for _, node := range topology.Nodes {
+		shape := "rectangle"
+		if node.Kind == serviceKind {
+			shape = "oval"
+		}
+		fmt.Fprintf(out, "%s: %s {shape: %s}\n", strconv.Quote(node.ID), strconv.Quote(getTopologyNodeLabel(node)), shape)
+	}
+	for _, edge := range topology.Edges {
+		line := fmt.Sprintf("%s -> %s", strconv.Quote(edge.From), strconv.Quote(edge.To))
+		if label := getTopologyEdgeLabel(edge); label != "" {
+			line += ": " + strconv.Quote(label)
+		}
+		if edge.Kind == topologyEdgeSplit {
+			line += " {style.stroke-dash: 3}"
+		}
+		fmt.Fprintln(out, line)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	fakeAccessClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned/fake"
+	fakeSplitClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func newTopologyNamespace(name, meshName string) *corev1.Namespace {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if meshName != "" {
+		ns.Labels = map[string]string{constants.OSMKubeResourceMonitorAnnotation: meshName}
+	}
+	return ns
+}
+
+func newTopologyConfigMap(permissiveMode string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "osm-system",
+			Name:      osmConfigMapName,
+		},
+		Data: map[string]string{
+			configurator.PermissiveTrafficPolicyModeKey: permissiveMode,
+		},
+	}
+}
+
+func newTopologyTrafficTarget(namespace, name string, sources ...smiAccess.IdentityBindingSubject) *smiAccess.TrafficTarget {
+	return &smiAccess.TrafficTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: smiAccess.TrafficTargetSpec{
+			Destination: smiAccess.IdentityBindingSubject{
+				Kind:      serviceAccountKind,
+				Name:      "bookstore",
+				Namespace: namespace,
+			},
+			Sources: sources,
+			Rules: []smiAccess.TrafficTargetRule{{
+				Kind:    httpRouteGroupKind,
+				Name:    "bookstore-routes",
+				Matches: []string{"buy-books"},
+			}},
+		},
+	}
+}
+
+func TestGetTopologySMIMode(t *testing.T) {
+	assert := tassert.New(t)
+
+	expiredTrafficTarget := newTopologyTrafficTarget("bookstore", "bookthief-access", smiAccess.IdentityBindingSubject{
+		Kind: serviceAccountKind, Name: "bookthief", Namespace: "bookthief",
+	})
+	expiredTrafficTarget.Annotations = map[string]string{
+		constants.TrafficTargetExpiresAtAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339),
+	}
+
+	cmd := &meshTopologyCmd{
+		out:      new(bytes.Buffer),
+		meshName: "osm",
+		format:   topologyFormatDot,
+		clientSet: fake.NewSimpleClientset(
+			newTopologyConfigMap("false"),
+			newTopologyNamespace("bookstore", "osm"),
+			newTopologyNamespace("bookbuyer", "osm"),
+			newTopologyNamespace("unmonitored", ""),
+		),
+		smiAccessClient: fakeAccessClient.NewSimpleClientset([]runtime.Object{
+			newTopologyTrafficTarget("bookstore", "bookstore-access",
+				smiAccess.IdentityBindingSubject{Kind: serviceAccountKind, Name: "bookbuyer", Namespace: "bookbuyer"},
+				smiAccess.IdentityBindingSubject{Kind: serviceAccountKind, Name: "*", Namespace: "*"},
+			),
+			expiredTrafficTarget,
+			newTopologyTrafficTarget("unmonitored", "bookstore-access",
+				smiAccess.IdentityBindingSubject{Kind: serviceAccountKind, Name: "bookbuyer", Namespace: "bookbuyer"},
+			),
+		}...),
+		smiSplitClient: fakeSplitClient.NewSimpleClientset(&smiSplit.TrafficSplit{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "bookstore",
+				Name:      "bookstore-split",
+			},
+			Spec: smiSplit.TrafficSplitSpec{
+				Service: "bookstore",
+				Backends: []smiSplit.TrafficSplitBackend{
+					{Service: "bookstore-v1", Weight: 90},
+					{Service: "bookstore-v2", Weight: 10},
+				},
+			},
+		}),
+	}
+
+	topology, err := cmd.getTopology()
+	assert.Nil(err)
+	assert.False(topology.PermissiveTrafficPolicyMode)
+
+	var nodeIDs []string
+	for _, node := range topology.Nodes {
+		nodeIDs = append(nodeIDs, node.ID)
+	}
+	assert.Equal([]string{
+		"Service:bookstore/bookstore",
+		"Service:bookstore/bookstore-v1",
+		"Service:bookstore/bookstore-v2",
+		"ServiceAccount:*/*",
+		"ServiceAccount:bookbuyer/bookbuyer",
+		"ServiceAccount:bookstore/bookstore",
+	}, nodeIDs)
+
+	weight90, weight10 := 90, 10
+	routes := []string{"HTTPRouteGroup bookstore-routes: buy-books"}
+	assert.Equal([]topologyEdge{
+		{From: "Service:bookstore/bookstore", To: "Service:bookstore/bookstore-v1", Kind: topologyEdgeSplit, Policy: "bookstore/bookstore-split", Weight: &weight90},
+		{From: "Service:bookstore/bookstore", To: "Service:bookstore/bookstore-v2", Kind: topologyEdgeSplit, Policy: "bookstore/bookstore-split", Weight: &weight10},
+		{From: "ServiceAccount:*/*", To: "ServiceAccount:bookstore/bookstore", Kind: topologyEdgeAllow, Policy: "bookstore/bookstore-access", Routes: routes},
+		{From: "ServiceAccount:bookbuyer/bookbuyer", To: "ServiceAccount:bookstore/bookstore", Kind: topologyEdgeAllow, Policy: "bookstore/bookstore-access", Routes: routes},
+	}, topology.Edges)
+}
+
+func TestGetTopologyPermissiveMode(t *testing.T) {
+	assert := tassert.New(t)
+
+	newPod := func(namespace, name, svcAccount string, meshed bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: svcAccount,
+			},
+		}
+		if meshed {
+			pod.Labels = map[string]string{constants.EnvoyUniqueIDLabelName: "uuid"}
+		}
+		return pod
+	}
+
+	cmd := &meshTopologyCmd{
+		out:      new(bytes.Buffer),
+		meshName: "osm",
+		format:   topologyFormatJSON,
+		clientSet: fake.NewSimpleClientset(
+			newTopologyConfigMap("true"),
+			newTopologyNamespace("bookstore", "osm"),
+			newTopologyNamespace("bookbuyer", "osm"),
+			newPod("bookstore", "bookstore-1", "bookstore", true),
+			newPod("bookstore", "bookstore-2", "bookstore", true),
+			newPod("bookbuyer", "bookbuyer", "bookbuyer", true),
+			newPod("bookbuyer", "unmeshed", "default", false),
+		),
+		// TrafficTargets are not enforced in permissive traffic policy mode
+		smiAccessClient: fakeAccessClient.NewSimpleClientset(newTopologyTrafficTarget("bookstore", "bookstore-access",
+			smiAccess.IdentityBindingSubject{Kind: serviceAccountKind, Name: "bookbuyer", Namespace: "bookbuyer"},
+		)),
+		smiSplitClient: fakeSplitClient.NewSimpleClientset(),
+	}
+
+	topology, err := cmd.getTopology()
+	assert.Nil(err)
+	assert.True(topology.PermissiveTrafficPolicyMode)
+	assert.Len(topology.Nodes, 2)
+	assert.Equal([]topologyEdge{
+		{From: "ServiceAccount:bookbuyer/bookbuyer", To: "ServiceAccount:bookstore/bookstore", Kind: topologyEdgePermissive},
+		{From: "ServiceAccount:bookstore/bookstore", To: "ServiceAccount:bookbuyer/bookbuyer", Kind: topologyEdgePermissive},
+	}, topology.Edges)
+}
+
+func TestWriteTopology(t *testing.T) {
+	assert := tassert.New(t)
+
+	weight := 100
+	topology := &meshTopology{
+		MeshName: "osm",
+		Nodes: []topologyNode{
+			{ID: "Service:bookstore/bookstore", Kind: serviceKind, Namespace: "bookstore", Name: "bookstore"},
+			{ID: "Service:bookstore/bookstore-v1", Kind: serviceKind, Namespace: "bookstore", Name: "bookstore-v1"},
+			{ID: "ServiceAccount:bookbuyer/*", Kind: serviceAccountKind, Namespace: "bookbuyer", Name: "*"},
+			{ID: "ServiceAccount:bookstore/bookstore", Kind: serviceAccountKind, Namespace: "bookstore", Name: "bookstore"},
+		},
+		Edges: []topologyEdge{
+			{From: "Service:bookstore/bookstore", To: "Service:bookstore/bookstore-v1", Kind: topologyEdgeSplit, Policy: "bookstore/bookstore-split", Weight: &weight},
+			{From: "ServiceAccount:bookbuyer/*", To: "ServiceAccount:bookstore/bookstore", Kind: topologyEdgeAllow, Policy: "bookstore/bookstore-access", Routes: []string{"TCPRoute bookstore-tcp"}},
+		},
+	}
+
+	out := new(bytes.Buffer)
+	writeTopologyDot(out, topology)
+	assert.Equal(`digraph "osm" {
+  label="Mesh osm (SMI traffic policy mode)";
+  rankdir=LR;
+  "Service:bookstore/bookstore" [label="bookstore/bookstore", shape=ellipse];
+  "Service:bookstore/bookstore-v1" [label="bookstore/bookstore-v1", shape=ellipse];
+  "ServiceAccount:bookbuyer/*" [label="any service account in bookbuyer", shape=box];
+  "ServiceAccount:bookstore/bookstore" [label="bookstore/bookstore", shape=box];
+  "Service:bookstore/bookstore" -> "Service:bookstore/bookstore-v1" [label="bookstore/bookstore-split\nweight 100", style=dashed];
+  "ServiceAccount:bookbuyer/*" -> "ServiceAccount:bookstore/bookstore" [label="bookstore/bookstore-access\nTCPRoute bookstore-tcp"];
+}
+`, out.String())
+
+	out.Reset()
+	writeTopologyD2(out, topology)
+	assert.Equal(`# Mesh osm (SMI traffic policy mode)
+direction: right
+"Service:bookstore/bookstore": "bookstore/bookstore" {shape: oval}
+"Service:bookstore/bookstore-v1": "bookstore/bookstore-v1" {shape: oval}
+"ServiceAccount:bookbuyer/*": "any service account in bookbuyer" {shape: rectangle}
+"ServiceAccount:bookstore/bookstore": "bookstore/bookstore" {shape: rectangle}
+"Service:bookstore/bookstore" -> "Service:bookstore/bookstore-v1": "bookstore/bookstore-split\nweight 100" {style.stroke-dash: 3}
+"ServiceAccount:bookbuyer/*" -> "ServiceAccount:bookstore/bookstore": "bookstore/bookstore-access\nTCPRoute bookstore-tcp"
+`, out.String())
+}
@@ -53,6 +53,7 @@ func newMetricsDisable(out io.Writer) *cobra.Command {
 
 	f := cmd.Flags()
 	f.StringSliceVar(&disableCmd.namespaces, "namespace", []string{}, "One or more namespaces to disable metrics on")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeMeshedNamespaces)
 
 	return cmd
 }
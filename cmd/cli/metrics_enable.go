@@ -57,6 +57,7 @@ func newMetricsEnable(out io.Writer) *cobra.Command {
 	//add mesh name flag
 	f := cmd.Flags()
 	f.StringSliceVar(&enableCmd.namespaces, "namespace", []string{}, "One or more namespaces to enable metrics on")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeMeshedNamespaces)
 
 	return cmd
 }
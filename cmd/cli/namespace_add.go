@@ -52,10 +52,11 @@ func newNamespaceAdd(out io.Writer) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "add NAMESPACE ...",
-		Short: "add namespace to mesh",
-		Long:  namespaceAddDescription,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               "add NAMESPACE ...",
+		Short:             "add namespace to mesh",
+		Long:              namespaceAddDescription,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeNamespaces,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			namespaceAdd.namespaces = args
 			config, err := settings.RESTClientGetter().ToRESTConfig()
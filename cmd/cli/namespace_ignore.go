@@ -35,10 +35,11 @@ func newNamespaceIgnore(out io.Writer) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "ignore NAMESPACE ...",
-		Short: "ignore namespace from participating in the mesh",
-		Long:  namespaceIgnoreDescription,
-		Args:  cobra.MinimumNArgs(1),
+		Use:               "ignore NAMESPACE ...",
+		Short:             "ignore namespace from participating in the mesh",
+		Long:              namespaceIgnoreDescription,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeNamespaces,
 		RunE: func(_ *cobra.Command, args []string) error {
 			ignoreCmd.namespaces = args
 			config, err := settings.RESTClientGetter().ToRESTConfig()
@@ -21,9 +21,27 @@ This command will list namespace information for all meshes. It is possible to f
 type namespaceListCmd struct {
 	out       io.Writer
 	meshName  string
+	output    string
 	clientSet kubernetes.Interface
 }
 
+// namespaceListOutput is the output of the namespace list command
+type namespaceListOutput struct {
+	Namespaces []namespaceListItem `json:"namespaces"`
+}
+
+// namespaceListItem is a namespace listed by the namespace list command
+type namespaceListItem struct {
+	Name string `json:"name"`
+	Mesh string `json:"mesh"`
+
+	// SidecarInjection is the value of the sidecar injection annotation of the namespace, if any
+	SidecarInjection string `json:"sidecar_injection,omitempty"`
+
+	// Ignored is true if the namespace is ignored by the mesh
+	Ignored bool `json:"ignored"`
+}
+
 func newNamespaceList(out io.Writer) *cobra.Command {
 	namespaceList := &namespaceListCmd{
 		out: out,
@@ -49,6 +67,7 @@ func newNamespaceList(out io.Writer) *cobra.Command {
 				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
 			}
 			namespaceList.clientSet = clientset
+			namespaceList.output = settings.Output()
 			return namespaceList.run()
 		},
 	}
@@ -66,33 +85,44 @@ func (l *namespaceListCmd) run() error {
 		return errors.Errorf("Could not list namespaces related to osm [%s]: %v", l.meshName, err)
 	}
 
-	if len(namespaces.Items) == 0 {
-		if l.meshName != "" {
-			fmt.Fprintf(l.out, "No namespaces in mesh [%s]\n", l.meshName)
-			return nil
-		}
-
-		fmt.Fprintf(l.out, "No namespaces in any mesh\n")
-		return nil
+	result := namespaceListOutput{Namespaces: []namespaceListItem{}}
+	for _, ns := range namespaces.Items {
+		_, ignored := ns.Labels[ignoreLabel]
+		result.Namespaces = append(result.Namespaces, namespaceListItem{
+			Name:             ns.Name,
+			Mesh:             ns.ObjectMeta.Labels[constants.OSMKubeResourceMonitorAnnotation],
+			SidecarInjection: ns.ObjectMeta.Annotations[constants.SidecarInjectionAnnotation],
+			Ignored:          ignored,
+		})
 	}
 
-	w := newTabWriter(l.out)
-	fmt.Fprintln(w, "NAMESPACE\tMESH\tSIDECAR-INJECTION")
-	for _, ns := range namespaces.Items {
-		osmName := ns.ObjectMeta.Labels[constants.OSMKubeResourceMonitorAnnotation]
-		sidecarInjectionEnabled, ok := ns.ObjectMeta.Annotations[constants.SidecarInjectionAnnotation]
-		if !ok {
-			sidecarInjectionEnabled = "-" // not set
-		}
-		if _, ignored := ns.Labels[ignoreLabel]; ignored {
-			sidecarInjectionEnabled = "disabled (ignored)"
+	return writeOutput(l.out, l.output, result, func() error {
+		if len(result.Namespaces) == 0 {
+			if l.meshName != "" {
+				fmt.Fprintf(l.out, "No namespaces in mesh [%s]\n", l.meshName)
+				return nil
+			}
+
+			fmt.Fprintf(l.out, "No namespaces in any mesh\n")
+			return nil
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n", ns.Name, osmName, sidecarInjectionEnabled)
-	}
-	_ = w.Flush()
+		w := newTabWriter(l.out)
+		fmt.Fprintln(w, "NAMESPACE\tMESH\tSIDECAR-INJECTION")
+		for _, ns := range result.Namespaces {
+			sidecarInjectionEnabled := ns.SidecarInjection
+			if sidecarInjectionEnabled == "" {
+				sidecarInjectionEnabled = "-" // not set
+			}
+			if ns.Ignored {
+				sidecarInjectionEnabled = "disabled (ignored)"
+			}
 
-	return nil
+			fmt.Fprintf(w, "%s\t%s\t%s\n", ns.Name, ns.Mesh, sidecarInjectionEnabled)
+		}
+		_ = w.Flush()
+		return nil
+	})
 }
 
 func (l *namespaceListCmd) selectNamespaces() (*v1.NamespaceList, error) {
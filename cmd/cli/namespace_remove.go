@@ -32,10 +32,11 @@ func newNamespaceRemove(out io.Writer) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "remove <NAMESPACE>",
-		Short: "remove namespace from mesh",
-		Long:  namespaceRemoveDescription,
-		Args:  cobra.ExactArgs(1),
+		Use:               "remove <NAMESPACE>",
+		Short:             "remove namespace from mesh",
+		Long:              namespaceRemoveDescription,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeArgs(1, completeMeshedNamespaces),
 		RunE: func(_ *cobra.Command, args []string) error {
 			namespaceRemove.namespace = args[0]
 			config, err := settings.RESTClientGetter().ToRESTConfig()
@@ -28,6 +28,9 @@ func newRootCmd(config *action.Configuration, in io.Reader, out io.Writer, args
 		Short:        "Install and manage Open Service Mesh",
 		Long:         globalUsage,
 		SilenceUsage: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateOutput(settings.Output())
+		},
 	}
 
 	cmd.PersistentFlags().AddGoFlagSet(goflag.CommandLine)
@@ -44,7 +47,9 @@ func newRootCmd(config *action.Configuration, in io.Reader, out io.Writer, args
 		newMetricsCmd(out),
 		newVersionCmd(out),
 		newProxyCmd(config, out),
+		newRolloutCmd(config, out),
 		newTrafficPolicyCmd(out),
+		newCompletionCmd(out),
 	)
 
 	_ = flags.Parse(args)
@@ -20,6 +20,8 @@ func newProxyCmd(config *action.Configuration, out io.Writer) *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 	cmd.AddCommand(newProxyGetCmd(config, out))
+	cmd.AddCommand(newProxyBootstrapCmd(out))
+	cmd.AddCommand(newProxyDarkLaunchReportCmd(config, out))
 
 	return cmd
 }
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/bootstrap"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const bootstrapCmdDescription = `
+This command generates the Envoy bootstrap configuration for a dev proxy.
+A dev proxy is an Envoy proxy running outside Kubernetes, for example on a
+developer's machine or in Docker Compose, that joins a local process to the
+mesh with the identity of the given service account.
+
+The dev proxy must be able to reach the OSM controller at the given xDS
+address, and the pods in the mesh at their pod IPs. The generated configuration
+contains the private key of the dev proxy's xDS certificate, and must be
+handled as a secret.
+
+Dev proxies are only supported when OSM uses the Tresor certificate manager.
+`
+
+const bootstrapCmdExample = `
+# Generate the bootstrap config of a dev proxy with the identity of the 'bookbuyer' service account
+# in the 'bookbuyer' namespace, connecting to the OSM controller port forwarded on localhost
+kubectl port-forward -n osm-system deploy/osm-controller 15128:15128
+osm proxy bootstrap bookbuyer -n bookbuyer --xds-address host.docker.internal:15128 -f bootstrap.yaml
+`
+
+const (
+	defaultCABundleSecretName = "osm-ca-bundle"
+	defaultDevProxyValidity   = 24 * time.Hour
+	defaultDevProxyXDSAddress = "localhost:15128"
+	defaultDevProxyName       = "dev"
+)
+
+type proxyBootstrapCmd struct {
+	out                io.Writer
+	clientSet          kubernetes.Interface
+	serviceAccount     string
+	namespace          string
+	name               string
+	xdsAddress         string
+	adminPort          int
+	caBundleSecretName string
+	validity           time.Duration
+	outFile            string
+}
+
+func newProxyBootstrapCmd(out io.Writer) *cobra.Command {
+	bootstrapCmd := &proxyBootstrapCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "bootstrap SERVICE_ACCOUNT",
+		Short:             "generate the bootstrap config of a dev proxy",
+		Long:              bootstrapCmdDescription,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeArgs(1, completeServiceAccounts),
+		RunE: func(_ *cobra.Command, args []string) error {
+			bootstrapCmd.serviceAccount = args[0]
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			bootstrapCmd.clientSet = clientset
+			return bootstrapCmd.run()
+		},
+		Example: bootstrapCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&bootstrapCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the service account")
+	f.StringVar(&bootstrapCmd.name, "name", defaultDevProxyName, "Name identifying the dev proxy in the control plane")
+	f.StringVar(&bootstrapCmd.xdsAddress, "xds-address", defaultDevProxyXDSAddress, "Address (host:port) at which the dev proxy reaches the OSM controller")
+	f.IntVar(&bootstrapCmd.adminPort, "admin-port", constants.EnvoyAdminPort, "Local port of the dev proxy's admin interface")
+	f.StringVar(&bootstrapCmd.caBundleSecretName, "ca-bundle-secret-name", defaultCABundleSecretName, "Name of the Kubernetes Secret for the OSM CA bundle")
+	f.DurationVar(&bootstrapCmd.validity, "validity", defaultDevProxyValidity, "Validity period of the dev proxy's xDS certificate")
+	f.StringVarP(&bootstrapCmd.outFile, "file", "f", "", "File to write the bootstrap config to")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+
+	return cmd
+}
+
+func (cmd *proxyBootstrapCmd) run() error {
+	host, portStr, err := net.SplitHostPort(cmd.xdsAddress)
+	if err != nil {
+		return errors.Errorf("Invalid xDS address %s: %s", cmd.xdsAddress, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return errors.Errorf("Invalid xDS port %s: %s", portStr, err)
+	}
+
+	config := bootstrap.DevProxyConfig{
+		ProxyID:        uuid.New(),
+		Name:           cmd.name,
+		ServiceAccount: service.K8sServiceAccount{Name: cmd.serviceAccount, Namespace: cmd.namespace},
+		XDSHost:        host,
+		XDSPort:        port,
+		AdminPort:      cmd.adminPort,
+	}
+
+	cert, err := bootstrap.IssueDevProxyCertificate(cmd.clientSet, settings.Namespace(), cmd.caBundleSecretName, config, cmd.validity)
+	if err != nil {
+		return errors.Errorf("Error issuing the dev proxy certificate using the CA bundle in secret %s/%s, dev proxies require the Tresor certificate manager: %s",
+			settings.Namespace(), cmd.caBundleSecretName, err)
+	}
+
+	bootstrapConfig, err := bootstrap.NewDevProxyBootstrapConfig(cert, config)
+	if err != nil {
+		return errors.Errorf("Error generating the dev proxy bootstrap config: %s", err)
+	}
+
+	if cmd.outFile == "" {
+		_, err = cmd.out.Write(bootstrapConfig)
+		return err
+	}
+
+	// The bootstrap config contains a private key, so it is only readable by the current user
+	if err := ioutil.WriteFile(cmd.outFile, bootstrapConfig, 0600); err != nil {
+		return errors.Errorf("Error writing file %s: %s", cmd.outFile, err)
+	}
+	fmt.Fprintf(cmd.out, "Wrote the bootstrap config of dev proxy %s with identity %s to %s\n", cmd.name, config.ServiceAccount, cmd.outFile)
+	return nil
+}
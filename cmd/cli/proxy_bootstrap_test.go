@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProxyBootstrapInvalidXDSAddress(t *testing.T) {
+	testCases := []string{
+		"localhost",
+		"localhost:port",
+	}
+
+	for _, xdsAddress := range testCases {
+		t.Run(xdsAddress, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			cmd := &proxyBootstrapCmd{
+				out:                new(bytes.Buffer),
+				clientSet:          fake.NewSimpleClientset(),
+				serviceAccount:     "bookbuyer",
+				namespace:          "bookbuyer",
+				name:               defaultDevProxyName,
+				xdsAddress:         xdsAddress,
+				caBundleSecretName: defaultCABundleSecretName,
+				validity:           defaultDevProxyValidity,
+			}
+
+			err := cmd.run()
+			assert.NotNil(err)
+			assert.Contains(err.Error(), "Invalid xDS")
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+const darkLaunchReportDescription = `
+This command compares the responses of a dark launched service and of its shadow
+service, as observed by the Envoy proxy sidecar of the given client pod.
+
+A service is dark launched by setting the 'openservicemesh.io/dark-launch-service'
+annotation on the service to the name of a shadow service in the same namespace:
+the client sidecars copy the requests to the service to the shadow service, and
+discard the responses of the shadow service.
+
+The report compares the number of requests, the rate of responses per status code
+class and the latency percentiles of both services, and the command exits with a
+non-zero status code if the shadow service regresses beyond the given thresholds.
+It is meant to be run before the shadow service is released.
+`
+
+const darkLaunchReportExample = `
+# Compare the responses of the dark launched 'bookstore' service in the 'bookstore' namespace and of its shadow service,
+# as observed by the pod 'bookbuyer-5ccf77f46d-rc5mg' in the 'bookbuyer' namespace
+osm proxy dark-launch-report bookbuyer-5ccf77f46d-rc5mg -n bookbuyer --service bookstore/bookstore
+`
+
+// darkLaunchStatsRegex matches the stats of the clusters of a dark launch in the text output of the Envoy stats endpoint
+var darkLaunchStatsRegex = regexp.MustCompile(`^cluster\.(.+)\.upstream_rq_(time|[1-5]xx): (.*)$`)
+
+// darkLaunchPercentileRegex matches a percentile of a histogram in the text output of the Envoy stats endpoint,
+// e.g. P99(nan,12.5) where the values are the percentile of the last interval and the cumulative percentile
+var darkLaunchPercentileRegex = regexp.MustCompile(`P([0-9.]+)\(([^,]+),([^)]+)\)`)
+
+type proxyDarkLaunchReportCmd struct {
+	out                  io.Writer
+	output               string
+	config               *rest.Config
+	clientSet            kubernetes.Interface
+	namespace            string
+	pod                  string
+	service              string
+	localPort            uint16
+	maxErrorRateIncrease float64
+	maxLatencyIncrease   float64
+}
+
+// darkLaunchReportOutput is the output of the proxy dark-launch-report command
+type darkLaunchReportOutput struct {
+	Services []darkLaunchServiceReport `json:"services"`
+}
+
+// darkLaunchServiceReport is the report of the responses of the primary or the shadow service of a dark launch
+type darkLaunchServiceReport struct {
+	Name     string `json:"name"`
+	Shadow   bool   `json:"shadow"`
+	Requests uint64 `json:"requests"`
+
+	// ResponseRates are the rates of the responses per status code class, between 0 and 1, keyed by class, e.g. 5xx
+	ResponseRates map[string]float64 `json:"response_rates"`
+
+	// LatencyPercentilesMs are the cumulative percentiles of the latency of the responses in milliseconds, keyed by
+	// percentile
+	LatencyPercentilesMs map[string]float64 `json:"latency_percentiles_ms"`
+}
+
+// darkLaunchClusterStats are the stats of the responses of a service observed by a client sidecar
+type darkLaunchClusterStats struct {
+	// responsesByClass is the number of responses per status code class, e.g. 5 for 5xx responses
+	responsesByClass map[int]uint64
+
+	// latencyPercentiles are the cumulative percentiles of the latency of the responses in milliseconds, keyed by
+	// percentile
+	latencyPercentiles map[string]float64
+}
+
+func newProxyDarkLaunchReportCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	reportCmd := &proxyDarkLaunchReportCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "dark-launch-report POD",
+		Short:             "compare a dark launched service to its shadow service",
+		Long:              darkLaunchReportDescription,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeArgs(1, completeMeshedPods),
+		RunE: func(_ *cobra.Command, args []string) error {
+			reportCmd.pod = args[0]
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			reportCmd.config = conf
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			reportCmd.clientSet = clientset
+			reportCmd.output = settings.Output()
+			return reportCmd.run()
+		},
+		Example: darkLaunchReportExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&reportCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of pod")
+	f.StringVar(&reportCmd.service, "service", "", "Dark launched service, as <namespace>/<name>")
+	f.Uint16VarP(&reportCmd.localPort, "local-port", "p", constants.EnvoyAdminPort, "Local port to use for port forwarding")
+	f.Float64Var(&reportCmd.maxErrorRateIncrease, "max-error-rate-increase", 1, "Maximum increase of the rate of 5xx responses of the shadow service, in percentage points")
+	f.Float64Var(&reportCmd.maxLatencyIncrease, "max-latency-increase", 20, "Maximum increase of the P99 latency of the shadow service, in percent")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeMeshedNamespaces)
+	_ = cmd.RegisterFlagCompletionFunc("service", completeMeshedServices)
+
+	return cmd
+}
+
+func (cmd *proxyDarkLaunchReportCmd) run() error {
+	svcNamespace, svcName, err := parseNamespacedName(cmd.service)
+	if err != nil {
+		return errors.Errorf("Invalid --service flag: %s", err)
+	}
+	svc, err := cmd.clientSet.CoreV1().Services(svcNamespace).Get(context.TODO(), svcName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Could not find service %s in namespace %s", svcName, svcNamespace)
+	}
+	shadowName, ok := svc.Annotations[constants.DarkLaunchServiceAnnotation]
+	if !ok {
+		return errors.Errorf("Service %s/%s is not dark launched, it has no %s annotation", svcNamespace, svcName, constants.DarkLaunchServiceAnnotation)
+	}
+	primaryCluster := svcNamespace + "/" + svcName
+	shadowCluster := svcNamespace + "/" + strings.TrimSpace(shadowName)
+
+	pod, err := cmd.clientSet.CoreV1().Pods(cmd.namespace).Get(context.TODO(), cmd.pod, metav1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Could not find pod %s in namespace %s", cmd.pod, cmd.namespace)
+	}
+	if !isMeshedPod(*pod) {
+		return errors.Errorf("Pod %s in namespace %s is not a part of a mesh", cmd.pod, cmd.namespace)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return errors.Errorf("Pod %s in namespace %s is not running", cmd.pod, cmd.namespace)
+	}
+
+	dialer, err := k8s.DialerToPod(cmd.config, cmd.clientSet, cmd.pod, cmd.namespace)
+	if err != nil {
+		return err
+	}
+
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", cmd.localPort, constants.EnvoyAdminPort))
+	if err != nil {
+		return errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	var stats string
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		query := url.Values{}
+		query.Set("filter", fmt.Sprintf(`^cluster\.(%s|%s)\.upstream_rq_(time|[1-5]xx)$`, regexp.QuoteMeta(primaryCluster), regexp.QuoteMeta(shadowCluster)))
+		statsURL := fmt.Sprintf("http://localhost:%d/stats?%s", cmd.localPort, query.Encode())
+
+		// #nosec G107: Potential HTTP request made with variable url
+		resp, err := http.Get(statsURL)
+		if err != nil {
+			return errors.Errorf("Error fetching url %s: %s", statsURL, err)
+		}
+		defer resp.Body.Close() //nolint: errcheck,gosec
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Errorf("Error reading stats: %s", err)
+		}
+		stats = string(body)
+		return nil
+	})
+	if err != nil {
+		return errors.Errorf("Error retrieving proxy stats for pod %s in namespace %s: %s", cmd.pod, cmd.namespace, err)
+	}
+
+	clusterStats := parseDarkLaunchStats(stats)
+	primary, shadow := clusterStats[primaryCluster], clusterStats[shadowCluster]
+	if err := cmd.printReport(primaryCluster, primary, shadowCluster, shadow); err != nil {
+		return err
+	}
+
+	return compareDarkLaunchStats(primary, shadow, cmd.maxErrorRateIncrease, cmd.maxLatencyIncrease)
+}
+
+func (cmd *proxyDarkLaunchReportCmd) printReport(primaryCluster string, primary darkLaunchClusterStats, shadowCluster string, shadow darkLaunchClusterStats) error {
+	result := darkLaunchReportOutput{
+		Services: []darkLaunchServiceReport{
+			newDarkLaunchServiceReport(primaryCluster, false, primary),
+			newDarkLaunchServiceReport(shadowCluster, true, shadow),
+		},
+	}
+
+	return writeOutput(cmd.out, cmd.output, result, func() error {
+		w := newTabWriter(cmd.out)
+		fmt.Fprintln(w, "SERVICE\tREQUESTS\t2XX\t3XX\t4XX\t5XX\tP50 (ms)\tP90 (ms)\tP99 (ms)")
+		for _, row := range []struct {
+			name  string
+			stats darkLaunchClusterStats
+		}{
+			{name: primaryCluster, stats: primary},
+			{name: shadowCluster + " (shadow)", stats: shadow},
+		} {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", row.name, row.stats.total(),
+				formatRate(row.stats.rate(2)), formatRate(row.stats.rate(3)), formatRate(row.stats.rate(4)), formatRate(row.stats.rate(5)),
+				formatLatency(row.stats.latencyPercentiles["50"]), formatLatency(row.stats.latencyPercentiles["90"]), formatLatency(row.stats.latencyPercentiles["99"]))
+		}
+		_ = w.Flush()
+		return nil
+	})
+}
+
+// newDarkLaunchServiceReport returns the report of the given stats of the responses of the service with the given name
+func newDarkLaunchServiceReport(name string, shadow bool, stats darkLaunchClusterStats) darkLaunchServiceReport {
+	report := darkLaunchServiceReport{
+		Name:                 name,
+		Shadow:               shadow,
+		Requests:             stats.total(),
+		ResponseRates:        make(map[string]float64),
+		LatencyPercentilesMs: make(map[string]float64),
+	}
+	for class := 2; class <= 5; class++ {
+		report.ResponseRates[fmt.Sprintf("%dxx", class)] = stats.rate(class)
+	}
+	for percentile, latency := range stats.latencyPercentiles {
+		report.LatencyPercentilesMs[percentile] = latency
+	}
+	return report
+}
+
+// parseDarkLaunchStats parses the text output of the Envoy stats endpoint into the stats of the responses of each
+// cluster, keyed by cluster name
+func parseDarkLaunchStats(stats string) map[string]darkLaunchClusterStats {
+	clusterStats := make(map[string]darkLaunchClusterStats)
+
+	scanner := bufio.NewScanner(strings.NewReader(stats))
+	for scanner.Scan() {
+		matches := darkLaunchStatsRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+		cluster, stat, value := matches[1], matches[2], matches[3]
+		s, ok := clusterStats[cluster]
+		if !ok {
+			s = darkLaunchClusterStats{
+				responsesByClass:   make(map[int]uint64),
+				latencyPercentiles: make(map[string]float64),
+			}
+			clusterStats[cluster] = s
+		}
+
+		if stat == "time" {
+			for _, percentile := range darkLaunchPercentileRegex.FindAllStringSubmatch(value, -1) {
+				cumulative, err := strconv.ParseFloat(percentile[3], 64)
+				if err != nil || math.IsNaN(cumulative) {
+					continue
+				}
+				s.latencyPercentiles[percentile[1]] = cumulative
+			}
+			continue
+		}
+
+		count, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		s.responsesByClass[int(stat[0]-'0')] = count
+	}
+
+	return clusterStats
+}
+
+// compareDarkLaunchStats returns an error if the rate of 5xx responses of the shadow service exceeds the rate of the
+// primary service by more than the given percentage points, or if its P99 latency exceeds the P99 latency of the
+// primary service by more than the given percentage
+func compareDarkLaunchStats(primary darkLaunchClusterStats, shadow darkLaunchClusterStats, maxErrorRateIncrease float64, maxLatencyIncrease float64) error {
+	if shadow.total() == 0 {
+		return errors.New("The shadow service has not responded to any request yet")
+	}
+
+	var regressions []string
+	if increase := (shadow.rate(5) - primary.rate(5)) * 100; increase > maxErrorRateIncrease {
+		regressions = append(regressions, fmt.Sprintf("the 5xx rate of the shadow service is %.2f percentage points higher", increase))
+	}
+	primaryP99, primaryOk := primary.latencyPercentiles["99"]
+	shadowP99, shadowOk := shadow.latencyPercentiles["99"]
+	if primaryOk && shadowOk && primaryP99 > 0 {
+		if increase := (shadowP99 - primaryP99) / primaryP99 * 100; increase > maxLatencyIncrease {
+			regressions = append(regressions, fmt.Sprintf("the P99 latency of the shadow service is %.0f%% higher", increase))
+		}
+	}
+
+	if len(regressions) > 0 {
+		return errors.Errorf("The shadow service regressed: %s", strings.Join(regressions, ", "))
+	}
+	return nil
+}
+
+// total returns the number of responses
+func (s darkLaunchClusterStats) total() uint64 {
+	var total uint64
+	for _, count := range s.responsesByClass {
+		total += count
+	}
+	return total
+}
+
+// rate returns the rate of responses with the given status code class, between 0 and 1
+func (s darkLaunchClusterStats) rate(class int) float64 {
+	total := s.total()
+	if total == 0 {
+		return 0
+	}
+	return float64(s.responsesByClass[class]) / float64(total)
+}
+
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%.2f%%", rate*100)
+}
+
+func formatLatency(latency float64) string {
+	if latency == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(latency, 'f', -1, 64)
+}
+
+// parseNamespacedName parses a name of the form <namespace>/<name>
+func parseNamespacedName(namespacedName string) (string, string, error) {
+	parts := strings.Split(namespacedName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("name %q must be of the form <namespace>/<name>", namespacedName)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+const darkLaunchStats = `
+cluster.bookstore/bookstore.upstream_rq_2xx: 970
+cluster.bookstore/bookstore.upstream_rq_4xx: 20
+cluster.bookstore/bookstore.upstream_rq_5xx: 10
+cluster.bookstore/bookstore-v2.upstream_rq_2xx: 940
+cluster.bookstore/bookstore-v2.upstream_rq_5xx: 60
+cluster.bookstore/bookstore.upstream_rq_time: P0(nan,1) P25(nan,2.5) P50(nan,5) P75(nan,7.5) P90(nan,9) P95(nan,9.5) P99(nan,10) P99.5(nan,10.5) P99.9(nan,11) P100(nan,12)
+cluster.bookstore/bookstore-v2.upstream_rq_time: P0(nan,1) P25(nan,3) P50(nan,6) P75(nan,9) P90(nan,11) P95(nan,12) P99(nan,15) P99.5(nan,16) P99.9(nan,17) P100(nan,18)
+`
+
+func TestParseDarkLaunchStats(t *testing.T) {
+	assert := tassert.New(t)
+
+	stats := parseDarkLaunchStats(darkLaunchStats)
+	assert.Len(stats, 2)
+
+	primary := stats["bookstore/bookstore"]
+	assert.Equal(uint64(1000), primary.total())
+	assert.Equal(0.97, primary.rate(2))
+	assert.Equal(0.01, primary.rate(5))
+	assert.Equal(5.0, primary.latencyPercentiles["50"])
+	assert.Equal(10.0, primary.latencyPercentiles["99"])
+
+	shadow := stats["bookstore/bookstore-v2"]
+	assert.Equal(uint64(1000), shadow.total())
+	assert.Equal(0.06, shadow.rate(5))
+	assert.Equal(15.0, shadow.latencyPercentiles["99"])
+
+	// Histograms without recorded values have no percentiles
+	stats = parseDarkLaunchStats("cluster.bookstore/bookstore.upstream_rq_time: No recorded values\n")
+	assert.Empty(stats["bookstore/bookstore"].latencyPercentiles)
+	assert.Equal(uint64(0), stats["bookstore/bookstore"].total())
+}
+
+func TestCompareDarkLaunchStats(t *testing.T) {
+	stats := parseDarkLaunchStats(darkLaunchStats)
+	primary, shadow := stats["bookstore/bookstore"], stats["bookstore/bookstore-v2"]
+
+	testCases := []struct {
+		name                 string
+		shadow               darkLaunchClusterStats
+		maxErrorRateIncrease float64
+		maxLatencyIncrease   float64
+		expectedErr          bool
+	}{
+		{
+			name:                 "within thresholds",
+			shadow:               shadow,
+			maxErrorRateIncrease: 10,
+			maxLatencyIncrease:   60,
+			expectedErr:          false,
+		},
+		{
+			name:                 "5xx rate regression",
+			shadow:               shadow,
+			maxErrorRateIncrease: 1,
+			maxLatencyIncrease:   60,
+			expectedErr:          true,
+		},
+		{
+			name:                 "latency regression",
+			shadow:               shadow,
+			maxErrorRateIncrease: 10,
+			maxLatencyIncrease:   20,
+			expectedErr:          true,
+		},
+		{
+			name:                 "no shadow responses",
+			shadow:               darkLaunchClusterStats{},
+			maxErrorRateIncrease: 10,
+			maxLatencyIncrease:   60,
+			expectedErr:          true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			err := compareDarkLaunchStats(primary, tc.shadow, tc.maxErrorRateIncrease, tc.maxLatencyIncrease)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestPrintDarkLaunchReport(t *testing.T) {
+	assert := tassert.New(t)
+
+	stats := parseDarkLaunchStats(darkLaunchStats)
+	out := new(bytes.Buffer)
+	cmd := &proxyDarkLaunchReportCmd{out: out}
+	err := cmd.printReport("bookstore/bookstore", stats["bookstore/bookstore"], "bookstore/bookstore-v2", stats["bookstore/bookstore-v2"])
+	assert.Nil(err)
+
+	assert.Contains(out.String(), "bookstore/bookstore-v2 (shadow)")
+	assert.Contains(out.String(), "6.00%")
+	assert.Contains(out.String(), "15")
+
+	// The report is output as JSON with the stats of each service
+	out.Reset()
+	cmd.output = outputJSON
+	err = cmd.printReport("bookstore/bookstore", stats["bookstore/bookstore"], "bookstore/bookstore-v2", stats["bookstore/bookstore-v2"])
+	assert.Nil(err)
+
+	var report darkLaunchReportOutput
+	assert.Nil(json.Unmarshal(out.Bytes(), &report))
+	assert.Len(report.Services, 2)
+	assert.Equal("bookstore/bookstore", report.Services[0].Name)
+	assert.False(report.Services[0].Shadow)
+	assert.Equal("bookstore/bookstore-v2", report.Services[1].Name)
+	assert.True(report.Services[1].Shadow)
+	assert.InDelta(0.06, report.Services[1].ResponseRates["5xx"], 0.0001)
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	assert := tassert.New(t)
+
+	namespace, name, err := parseNamespacedName("bookstore/bookstore")
+	assert.Nil(err)
+	assert.Equal("bookstore", namespace)
+	assert.Equal("bookstore", name)
+
+	for _, invalid := range []string{"", "bookstore", "bookstore/", "/bookstore", "a/b/c"} {
+		_, _, err := parseNamespacedName(invalid)
+		assert.NotNil(err, invalid)
+	}
+}
@@ -57,6 +57,13 @@ func newProxyGetCmd(config *action.Configuration, out io.Writer) *cobra.Command
 		Short: "get query for proxy",
 		Long:  getCmdDescription,
 		Args:  cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			// Only the POD argument is completed
+			if len(args) != 1 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeMeshedPods(cmd, args, toComplete)
+		},
 		RunE: func(_ *cobra.Command, args []string) error {
 			getCmd.query = args[0]
 			getCmd.pod = args[1]
@@ -81,6 +88,7 @@ func newProxyGetCmd(config *action.Configuration, out io.Writer) *cobra.Command
 	f.StringVarP(&getCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of pod")
 	f.StringVarP(&getCmd.outFile, "file", "f", "", "File to write output to")
 	f.Uint16VarP(&getCmd.localPort, "local-port", "p", constants.EnvoyAdminPort, "Local port to use for port forwarding")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeMeshedNamespaces)
 
 	return cmd
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+const rolloutCmdDescription = `
+This command consists of subcommands related to the rollout of changes
+to the mesh.
+`
+
+func newRolloutCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "mesh rollout operations",
+		Long:  rolloutCmdDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newRolloutStatusCmd(config, out))
+
+	return cmd
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+const rolloutStatusCmdDescription = `
+This command consists of subcommands reporting the progress of the rollout
+of changes to the mesh.
+`
+
+func newRolloutStatusCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "rollout progress of mesh changes",
+		Long:  rolloutStatusCmdDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newRolloutStatusConfigCmd(config, out))
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/debugger"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+const rolloutStatusConfigDescription = `
+This command reports the progress of the rollout of the configuration changes
+of the mesh to the Envoy proxy sidecars.
+
+The configuration changes coalesced by the OSM controller into a single update
+of the proxies are stamped with a configuration generation. A generation is
+applied by a proxy once the proxy acknowledges all the configuration sent to
+it for the generation or for a later generation. The command lists the most
+recent generations, the changes they include and the number of connected
+proxies that applied them, along with the proxies yet to apply the latest
+generation.
+
+The command requires the debug server of the OSM controller to be enabled.
+`
+
+const rolloutStatusConfigExample = `
+# Report the rollout progress of the recent configuration changes of the mesh in the 'osm-system' namespace
+osm rollout status config --osm-namespace osm-system
+`
+
+type rolloutStatusConfigCmd struct {
+	out       io.Writer
+	output    string
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	localPort uint16
+}
+
+// rolloutStatusConfigOutput is the output of the rollout status config command
+type rolloutStatusConfigOutput struct {
+	Generations []debugger.ConfigGenerationRollout `json:"generations"`
+}
+
+func newRolloutStatusConfigCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	statusCmd := &rolloutStatusConfigCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "rollout progress of configuration changes",
+		Long:  rolloutStatusConfigDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			statusCmd.config = conf
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			statusCmd.clientSet = clientset
+			statusCmd.output = settings.Output()
+			return statusCmd.run()
+		},
+		Example: rolloutStatusConfigExample,
+	}
+
+	f := cmd.Flags()
+	f.Uint16VarP(&statusCmd.localPort, "local-port", "p", constants.DebugPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *rolloutStatusConfigCmd) run() error {
+	controllerPod, err := cmd.getRunningControllerPod()
+	if err != nil {
+		return err
+	}
+
+	dialer, err := k8s.DialerToPod(cmd.config, cmd.clientSet, controllerPod.Name, controllerPod.Namespace)
+	if err != nil {
+		return err
+	}
+
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", cmd.localPort, constants.DebugPort))
+	if err != nil {
+		return errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	var rollouts []debugger.ConfigGenerationRollout
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		url := fmt.Sprintf("http://localhost:%d/debug/rollout", cmd.localPort)
+
+		// #nosec G107: Potential HTTP request made with variable url
+		resp, err := http.Get(url)
+		if err != nil {
+			return errors.Errorf("Error fetching url %s: %s", url, err)
+		}
+		defer resp.Body.Close() //nolint: errcheck
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("Error fetching url %s: %s", url, resp.Status)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&rollouts); err != nil {
+			return errors.Errorf("Error decoding configuration rollouts: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Errorf("Error retrieving configuration rollouts from pod %s in namespace %s: %s", controllerPod.Name, controllerPod.Namespace, err)
+	}
+
+	if rollouts == nil {
+		rollouts = []debugger.ConfigGenerationRollout{}
+	}
+	return writeOutput(cmd.out, cmd.output, rolloutStatusConfigOutput{Generations: rollouts}, func() error {
+		printConfigRollouts(cmd.out, rollouts)
+		return nil
+	})
+}
+
+// getRunningControllerPod returns a running OSM controller pod in the OSM namespace
+func (cmd *rolloutStatusConfigCmd) getRunningControllerPod() (*corev1.Pod, error) {
+	labelSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": constants.OSMControllerName}}
+	listOptions := metav1.ListOptions{
+		LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
+	}
+	pods, err := cmd.clientSet.CoreV1().Pods(settings.Namespace()).List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, errors.Errorf("Error listing OSM controller pods in namespace %s: %s", settings.Namespace(), err)
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, errors.Errorf("No running OSM controller pod found in namespace %s", settings.Namespace())
+}
+
+// printConfigRollouts prints the rollout progress of the given configuration generations, and the proxies yet to apply
+// the latest generation
+func printConfigRollouts(out io.Writer, rollouts []debugger.ConfigGenerationRollout) {
+	if len(rollouts) == 0 {
+		fmt.Fprintln(out, "No configuration change rolled out since the OSM controller started")
+		return
+	}
+
+	w := newTabWriter(out)
+	fmt.Fprintln(w, "GENERATION\tCREATED\tAPPLIED\tCHANGES\t")
+	for _, rollout := range rollouts {
+		fmt.Fprintf(w, "%d\t%s\t%d/%d\t%s\t\n", rollout.ID, rollout.CreatedAt.Format(time.RFC3339),
+			rollout.AppliedProxies, rollout.TotalProxies, strings.Join(rollout.Changes, ", "))
+	}
+	_ = w.Flush()
+
+	latest := rollouts[len(rollouts)-1]
+	if latest.AppliedProxies == latest.TotalProxies {
+		fmt.Fprintf(out, "\nGeneration %d is applied by all %d connected proxies\n", latest.ID, latest.TotalProxies)
+		return
+	}
+	fmt.Fprintf(out, "\nGeneration %d is pending on %d of %d connected proxies, including:\n",
+		latest.ID, latest.TotalProxies-latest.AppliedProxies, latest.TotalProxies)
+	for _, cn := range latest.PendingProxies {
+		fmt.Fprintf(out, "  %s\n", cn)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestPrintConfigRollouts(t *testing.T) {
+	createdAt := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		rollouts       []debugger.ConfigGenerationRollout
+		expectedOutput string
+	}{
+		{
+			name:           "no generation",
+			rollouts:       nil,
+			expectedOutput: "No configuration change rolled out since the OSM controller started\n",
+		},
+		{
+			name: "latest generation applied",
+			rollouts: []debugger.ConfigGenerationRollout{
+				{
+					ConfigGeneration: envoy.ConfigGeneration{ID: 1, CreatedAt: createdAt, Changes: []string{"service-added bookstore/bookstore"}},
+					AppliedProxies:   2,
+					TotalProxies:     2,
+				},
+			},
+			expectedOutput: "GENERATION   CREATED                APPLIED   CHANGES                             \n" +
+				"1            2021-03-01T00:00:00Z   2/2       service-added bookstore/bookstore   \n" +
+				"\nGeneration 1 is applied by all 2 connected proxies\n",
+		},
+		{
+			name: "latest generation pending",
+			rollouts: []debugger.ConfigGenerationRollout{
+				{
+					ConfigGeneration: envoy.ConfigGeneration{ID: 1, CreatedAt: createdAt, Changes: []string{"service-added bookstore/bookstore"}},
+					AppliedProxies:   2,
+					TotalProxies:     2,
+				},
+				{
+					ConfigGeneration: envoy.ConfigGeneration{ID: 2, CreatedAt: createdAt, Changes: []string{"service-updated bookstore/bookstore", "schedule-proxy-broadcast"}},
+					AppliedProxies:   1,
+					TotalProxies:     2,
+					PendingProxies:   []string{"bookstore"},
+				},
+			},
+			expectedOutput: "GENERATION   CREATED                APPLIED   CHANGES                                                         \n" +
+				"1            2021-03-01T00:00:00Z   2/2       service-added bookstore/bookstore                               \n" +
+				"2            2021-03-01T00:00:00Z   1/2       service-updated bookstore/bookstore, schedule-proxy-broadcast   \n" +
+				"\nGeneration 2 is pending on 1 of 2 connected proxies, including:\n" +
+				"  bookstore\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var out bytes.Buffer
+			printConfigRollouts(&out, tc.rollouts)
+			assert.Equal(tc.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestGetRunningControllerPod(t *testing.T) {
+	assert := tassert.New(t)
+
+	clientSet := fake.NewSimpleClientset()
+	cmd := &rolloutStatusConfigCmd{clientSet: clientSet}
+
+	_, err := cmd.getRunningControllerPod()
+	assert.Error(err)
+
+	for name, phase := range map[string]corev1.PodPhase{"osm-controller-pending": corev1.PodPending, "osm-controller-running": corev1.PodRunning} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: settings.Namespace(),
+				Labels:    map[string]string{"app": constants.OSMControllerName},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		_, err := clientSet.CoreV1().Pods(settings.Namespace()).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.Nil(err)
+	}
+
+	pod, err := cmd.getRunningControllerPod()
+	assert.Nil(err)
+	assert.Equal("osm-controller-running", pod.Name)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiAccessClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -40,22 +41,40 @@ const (
 
 type trafficPolicyCheckCmd struct {
 	out             io.Writer
+	output          string
 	sourcePod       string
 	destinationPod  string
 	clientSet       kubernetes.Interface
 	smiAccessClient smiAccessClient.Interface
 }
 
+// trafficPolicyCheckOutput is the output of the policy check-pods command
+type trafficPolicyCheckOutput struct {
+	// Source and Destination are the pods checked, as <namespace>/<pod>
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+
+	// Allowed is true if the source pod is allowed to send traffic to the destination pod
+	Allowed bool `json:"allowed"`
+
+	// PermissiveMode is true if the mesh allows all traffic in permissive traffic policy mode
+	PermissiveMode bool `json:"permissive_mode"`
+
+	// TrafficTargets are the names of the SMI TrafficTarget policies allowing the traffic
+	TrafficTargets []string `json:"traffic_targets"`
+}
+
 func newTrafficPolicyCheck(out io.Writer) *cobra.Command {
 	trafficPolicyCheckCmd := &trafficPolicyCheckCmd{
 		out: out,
 	}
 
 	cmd := &cobra.Command{
-		Use:   "check-pods SOURCE_POD DESTINATION_POD",
-		Short: "check-pods traffic policy",
-		Long:  trafficPolicyCheckDescription,
-		Args:  cobra.ExactArgs(2),
+		Use:               "check-pods SOURCE_POD DESTINATION_POD",
+		Short:             "check-pods traffic policy",
+		Long:              trafficPolicyCheckDescription,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeArgs(2, completeNamespacedMeshedPods),
 		RunE: func(_ *cobra.Command, args []string) error {
 			trafficPolicyCheckCmd.sourcePod = args[0]
 			trafficPolicyCheckCmd.destinationPod = args[1]
@@ -76,6 +95,7 @@ func newTrafficPolicyCheck(out io.Writer) *cobra.Command {
 				return errors.Errorf("Could not initialize SMI Access client: %s", err)
 			}
 			trafficPolicyCheckCmd.smiAccessClient = accessCliemt
+			trafficPolicyCheckCmd.output = settings.Output()
 
 			return trafficPolicyCheckCmd.run()
 		},
@@ -111,63 +131,81 @@ func (cmd *trafficPolicyCheckCmd) run() error {
 
 func (cmd *trafficPolicyCheckCmd) checkTrafficPolicy(srcPod, dstPod *corev1.Pod) error {
 	osmNamespace := settings.Namespace()
+	result := trafficPolicyCheckOutput{
+		Source:         fmt.Sprintf("%s/%s", srcPod.Namespace, srcPod.Name),
+		Destination:    fmt.Sprintf("%s/%s", dstPod.Namespace, dstPod.Name),
+		TrafficTargets: []string{},
+	}
 
 	// Check if permissive mode is enabled, in which case every meshed pod is allowed to communicate with each other
-	if permissiveMode, err := cmd.isPermissiveModeEnabled(); err != nil {
+	permissiveMode, err := cmd.isPermissiveModeEnabled()
+	if err != nil {
 		return errors.Errorf("Error checking if permissive mode is enabled: %s", err)
-	} else if permissiveMode {
-		fmt.Fprintf(cmd.out, "[+] Permissive mode enabled for mesh operated by osm-controller running in '%s' namespace\n\n "+
-			"[+] Pod '%s/%s' is allowed to communicate to pod '%s/%s'\n",
-			osmNamespace, srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name)
-		return nil
 	}
+	result.PermissiveMode = permissiveMode
+	result.Allowed = permissiveMode
 
 	// SMI traffic policy mode
-	fmt.Fprintf(cmd.out, "[+] SMI traffic policy mode enabled for mesh operated by osm-controller running in %s namespace\n\n", osmNamespace)
-	trafficTargets, err := cmd.smiAccessClient.AccessV1alpha3().TrafficTargets(dstPod.Namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return errors.Errorf("Error listing SMI TrafficTarget policies: %s", err)
-	}
-
-	var foundTrafficTarget bool
-	for _, trafficTarget := range trafficTargets.Items {
-		spec := trafficTarget.Spec
-		if spec.Destination.Kind != serviceAccountKind {
-			continue
+	var allowingTrafficTargets []smiAccess.TrafficTarget
+	if !permissiveMode {
+		trafficTargets, err := cmd.smiAccessClient.AccessV1alpha3().TrafficTargets(dstPod.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return errors.Errorf("Error listing SMI TrafficTarget policies: %s", err)
 		}
 
-		// Map traffic targets to the given pods
-		if spec.Destination.Name == dstPod.Spec.ServiceAccountName && spec.Destination.Namespace == dstPod.Namespace {
-			// The TrafficTarget destination is associated to 'dstPod'
+		for _, trafficTarget := range trafficTargets.Items {
+			spec := trafficTarget.Spec
+			if spec.Destination.Kind != serviceAccountKind {
+				continue
+			}
 
-			// Check if 'srcPod` is an allowed source to this destination
-			for _, source := range spec.Sources {
-				if source.Kind != serviceAccountKind {
-					continue
-				}
+			// Map traffic targets to the given pods
+			if spec.Destination.Name == dstPod.Spec.ServiceAccountName && spec.Destination.Namespace == dstPod.Namespace {
+				// The TrafficTarget destination is associated to 'dstPod'
 
-				if source.Name == srcPod.Spec.ServiceAccountName && source.Namespace == srcPod.Namespace {
-					fmt.Fprintf(cmd.out, "[+] Pod '%s/%s' is allowed to communicate to pod '%s/%s' via the SMI TrafficTarget policy %q:\n",
-						srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name, trafficTarget.Name)
-					foundTrafficTarget = true
+				// Check if 'srcPod` is an allowed source to this destination
+				for _, source := range spec.Sources {
+					if source.Kind != serviceAccountKind {
+						continue
+					}
 
-					target := trafficTarget // avoids gosec G601: Implicit memory aliasing in for loop
-					trafficTargetPolicy, err := yaml.Marshal(&target)
-					if err != nil {
-						return errors.Errorf("Failed to marshal TrafficTarget %s: %s", trafficTarget.Name, err)
+					if source.Name == srcPod.Spec.ServiceAccountName && source.Namespace == srcPod.Namespace {
+						allowingTrafficTargets = append(allowingTrafficTargets, trafficTarget)
+						result.TrafficTargets = append(result.TrafficTargets, trafficTarget.Name)
 					}
-					fmt.Fprintf(cmd.out, "---\n%s\n---\n", string(trafficTargetPolicy))
 				}
 			}
 		}
+		result.Allowed = len(allowingTrafficTargets) > 0
 	}
 
-	if !foundTrafficTarget {
-		fmt.Fprintf(cmd.out, "[+] Pod '%s/%s' is not allowed to communicate to pod '%s/%s', missing SMI TrafficTarget policy\n",
-			srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name)
-	}
+	return writeOutput(cmd.out, cmd.output, result, func() error {
+		if permissiveMode {
+			fmt.Fprintf(cmd.out, "[+] Permissive mode enabled for mesh operated by osm-controller running in '%s' namespace\n\n "+
+				"[+] Pod '%s/%s' is allowed to communicate to pod '%s/%s'\n",
+				osmNamespace, srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name)
+			return nil
+		}
+
+		fmt.Fprintf(cmd.out, "[+] SMI traffic policy mode enabled for mesh operated by osm-controller running in %s namespace\n\n", osmNamespace)
+		for i := range allowingTrafficTargets {
+			target := &allowingTrafficTargets[i]
+			fmt.Fprintf(cmd.out, "[+] Pod '%s/%s' is allowed to communicate to pod '%s/%s' via the SMI TrafficTarget policy %q:\n",
+				srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name, target.Name)
+
+			trafficTargetPolicy, err := yaml.Marshal(target)
+			if err != nil {
+				return errors.Errorf("Failed to marshal TrafficTarget %s: %s", target.Name, err)
+			}
+			fmt.Fprintf(cmd.out, "---\n%s\n---\n", string(trafficTargetPolicy))
+		}
 
-	return nil
+		if !result.Allowed {
+			fmt.Fprintf(cmd.out, "[+] Pod '%s/%s' is not allowed to communicate to pod '%s/%s', missing SMI TrafficTarget policy\n",
+				srcPod.Namespace, srcPod.Name, dstPod.Namespace, dstPod.Name)
+		}
+		return nil
+	})
 }
 
 func (cmd *trafficPolicyCheckCmd) getMeshedPod(namespace, podName string) (*corev1.Pod, error) {
@@ -183,8 +221,14 @@ func (cmd *trafficPolicyCheckCmd) getMeshedPod(namespace, podName string) (*core
 }
 
 func (cmd *trafficPolicyCheckCmd) isPermissiveModeEnabled() (bool, error) {
+	return isPermissiveModeEnabled(cmd.clientSet)
+}
+
+// isPermissiveModeEnabled returns whether permissive traffic policy mode is enabled in the ConfigMap of the mesh
+// operated by the osm-controller running in the namespace set in the CLI settings
+func isPermissiveModeEnabled(clientSet kubernetes.Interface) (bool, error) {
 	osmNamespace := settings.Namespace()
-	configMap, err := cmd.clientSet.CoreV1().ConfigMaps(osmNamespace).Get(context.TODO(), osmConfigMapName, metav1.GetOptions{})
+	configMap, err := clientSet.CoreV1().ConfigMaps(osmNamespace).Get(context.TODO(), osmConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		return false, errors.Errorf("Error checking if permissive mode is enabled: %s", err)
 	}
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	smiTrafficSpecClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned"
+	"github.com/spf13/cobra"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+const trafficPolicyLintDescription = `
+This command statically verifies the SMI traffic policies defined in the YAML
+or JSON files of a directory, and exits with a non-zero status code if any
+issues are found. It is meant to be run in CI pipelines before the policies
+are applied.
+
+The following checks are performed:
+- TrafficTarget, HTTPRouteGroup, TCPRoute and TrafficSplit resources use
+  the API versions supported by OSM
+- ServiceAccounts referenced by TrafficTarget sources and destinations, other than
+  wildcard sources, are defined in the given files, or exist in the cluster
+  when --cluster is set
+- Routes referenced by TrafficTarget rules are defined in the given files, or
+  exist in the cluster when --cluster is set, and the matches referenced by
+  the rules are defined by the HTTPRouteGroup
+- TrafficTarget expiry annotations are valid and not in the past
+- TrafficTarget and TrafficSplit schedule annotations are valid
+- TrafficSplit backends have valid weights
+`
+
+const trafficPolicyLintExample = `
+# Lint the traffic policies defined in the 'policies' directory
+osm policy lint policies/
+
+# Lint the traffic policies defined in the 'policies' directory, resolving references
+# that are not defined in the directory in the cluster
+osm policy lint policies/ --cluster
+`
+
+const (
+	trafficTargetKind  = "TrafficTarget"
+	httpRouteGroupKind = "HTTPRouteGroup"
+	tcpRouteKind       = "TCPRoute"
+	trafficSplitKind   = "TrafficSplit"
+)
+
+// lintedAPIGroups maps the API groups and kinds checked by the linter to the API version supported by OSM
+var lintedAPIGroups = map[schema.GroupKind]string{
+	{Group: smiAccess.SchemeGroupVersion.Group, Kind: trafficTargetKind}: smiAccess.SchemeGroupVersion.Version,
+	{Group: smiSpecs.SchemeGroupVersion.Group, Kind: httpRouteGroupKind}: smiSpecs.SchemeGroupVersion.Version,
+	{Group: smiSpecs.SchemeGroupVersion.Group, Kind: tcpRouteKind}:       smiSpecs.SchemeGroupVersion.Version,
+	{Group: smiSplit.SchemeGroupVersion.Group, Kind: trafficSplitKind}:   smiSplit.SchemeGroupVersion.Version,
+}
+
+type trafficPolicyLintCmd struct {
+	out                  io.Writer
+	output               string
+	dir                  string
+	cluster              bool
+	clientSet            kubernetes.Interface
+	smiTrafficSpecClient smiTrafficSpecClient.Interface
+
+	// warnings are the errors encountered while looking up references in the cluster
+	warnings []string
+}
+
+// trafficPolicyLintOutput is the output of the policy lint command
+type trafficPolicyLintOutput struct {
+	Issues   []string `json:"issues"`
+	Warnings []string `json:"warnings"`
+}
+
+// lintObject is a resource read from a file in the linted directory
+type lintObject struct {
+	file string
+	obj  *unstructured.Unstructured
+}
+
+// lintResources holds the resources read from the files in the linted directory
+type lintResources struct {
+	serviceAccounts map[string]bool
+	httpRouteGroups map[string]*smiSpecs.HTTPRouteGroup
+	tcpRoutes       map[string]bool
+	objects         []lintObject
+}
+
+func newTrafficPolicyLintCmd(out io.Writer) *cobra.Command {
+	lintCmd := &trafficPolicyLintCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "lint DIRECTORY",
+		Short: "lint traffic policies defined in a directory",
+		Long:  trafficPolicyLintDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			lintCmd.dir = args[0]
+			lintCmd.output = settings.Output()
+
+			if lintCmd.cluster {
+				config, err := settings.RESTClientGetter().ToRESTConfig()
+				if err != nil {
+					return errors.Errorf("Error fetching kubeconfig: %s", err)
+				}
+
+				clientset, err := kubernetes.NewForConfig(config)
+				if err != nil {
+					return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+				}
+				lintCmd.clientSet = clientset
+
+				specClient, err := smiTrafficSpecClient.NewForConfig(config)
+				if err != nil {
+					return errors.Errorf("Could not initialize SMI Specs client: %s", err)
+				}
+				lintCmd.smiTrafficSpecClient = specClient
+			}
+
+			return lintCmd.run()
+		},
+		Example: trafficPolicyLintExample,
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&lintCmd.cluster, "cluster", false, "Resolve references that are not defined in the given directory in the cluster")
+
+	return cmd
+}
+
+func (cmd *trafficPolicyLintCmd) run() error {
+	resources, issues, err := readLintResources(cmd.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range resources.objects {
+		for _, issue := range cmd.lint(resources, o.obj) {
+			issues = append(issues, fmt.Sprintf("%s: %s %s/%s: %s", o.file, o.obj.GetKind(), o.obj.GetNamespace(), o.obj.GetName(), issue))
+		}
+	}
+
+	result := trafficPolicyLintOutput{
+		Issues:   append([]string{}, issues...),
+		Warnings: append([]string{}, cmd.warnings...),
+	}
+	err = writeOutput(cmd.out, cmd.output, result, func() error {
+		for _, warning := range cmd.warnings {
+			fmt.Fprintf(cmd.out, "[!] %s\n", warning)
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(cmd.out, "[-] %s\n", issue)
+		}
+		if len(issues) == 0 {
+			fmt.Fprintf(cmd.out, "[+] No issues found in the traffic policies in %s\n", cmd.dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(issues) > 0 {
+		return errors.Errorf("Found %d issue(s) in the traffic policies in %s", len(issues), cmd.dir)
+	}
+	return nil
+}
+
+// readLintResources reads the resources defined in the YAML and JSON files of the given directory.
+// Files that cannot be decoded are returned as issues.
+func readLintResources(dir string) (*lintResources, []string, error) {
+	resources := &lintResources{
+		serviceAccounts: make(map[string]bool),
+		httpRouteGroups: make(map[string]*smiSpecs.HTTPRouteGroup),
+		tcpRoutes:       make(map[string]bool),
+	}
+	var issues []string
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Errorf("Error reading directory %s: %s", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		objects, err := decodeLintFile(file)
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("%s: error decoding file: %s", file, err))
+			continue
+		}
+
+		for _, obj := range objects {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(metav1.NamespaceDefault)
+			}
+			key := namespacedName(obj.GetNamespace(), obj.GetName())
+
+			switch obj.GetKind() {
+			case serviceAccountKind:
+				resources.serviceAccounts[key] = true
+			case httpRouteGroupKind:
+				routeGroup := &smiSpecs.HTTPRouteGroup{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, routeGroup); err == nil {
+					resources.httpRouteGroups[key] = routeGroup
+				}
+			case tcpRouteKind:
+				resources.tcpRoutes[key] = true
+			}
+			resources.objects = append(resources.objects, lintObject{file: file, obj: obj})
+		}
+	}
+
+	return resources, issues, nil
+}
+
+// decodeLintFile decodes all the resources defined in the given YAML or JSON file
+func decodeLintFile(file string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint: errcheck,gosec
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		// Skip empty documents
+		if len(obj) == 0 {
+			continue
+		}
+		objects = append(objects, &unstructured.Unstructured{Object: obj})
+	}
+	return objects, nil
+}
+
+// lint returns the issues found in the given resource
+func (cmd *trafficPolicyLintCmd) lint(resources *lintResources, obj *unstructured.Unstructured) []string {
+	gvk := obj.GroupVersionKind()
+	supportedVersion, ok := lintedAPIGroups[gvk.GroupKind()]
+	if !ok {
+		return nil
+	}
+	if gvk.Version != supportedVersion {
+		return []string{fmt.Sprintf("unsupported API version %s, OSM supports %s/%s", obj.GetAPIVersion(), gvk.Group, supportedVersion)}
+	}
+
+	switch gvk.Kind {
+	case trafficTargetKind:
+		trafficTarget := &smiAccess.TrafficTarget{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, trafficTarget); err != nil {
+			return []string{fmt.Sprintf("invalid resource: %s", err)}
+		}
+		return cmd.lintTrafficTarget(resources, trafficTarget)
+
+	case trafficSplitKind:
+		trafficSplit := &smiSplit.TrafficSplit{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, trafficSplit); err != nil {
+			return []string{fmt.Sprintf("invalid resource: %s", err)}
+		}
+		return lintTrafficSplit(trafficSplit)
+	}
+
+	return nil
+}
+
+func (cmd *trafficPolicyLintCmd) lintTrafficTarget(resources *lintResources, trafficTarget *smiAccess.TrafficTarget) []string {
+	var issues []string
+
+	if expiry, expires, err := smi.GetTrafficTargetExpiry(trafficTarget); err != nil {
+		issues = append(issues, fmt.Sprintf("invalid %s annotation, must be an RFC3339 time: %s", constants.TrafficTargetExpiresAtAnnotation, err))
+	} else if expires && !time.Now().Before(expiry) {
+		issues = append(issues, fmt.Sprintf("expired at %s and no longer grants access", expiry.Format(time.RFC3339)))
+	}
+	if _, _, err := smi.GetPolicySchedule(trafficTarget); err != nil {
+		issues = append(issues, fmt.Sprintf("invalid %s annotation: %s", constants.PolicyScheduleAnnotation, err))
+	}
+
+	if trafficTarget.Spec.Destination.Name == smi.WildcardIdentity {
+		issues = append(issues, fmt.Sprintf("wildcard %s not allowed as destination", serviceAccountKind))
+	}
+
+	subjects := append([]smiAccess.IdentityBindingSubject{trafficTarget.Spec.Destination}, trafficTarget.Spec.Sources...)
+	for _, subject := range subjects {
+		if subject.Name == smi.WildcardIdentity {
+			// Wildcard sources match service accounts that may not exist yet
+			if subject.Kind != serviceAccountKind {
+				issues = append(issues, fmt.Sprintf("invalid kind %q for identity %s/%s, must be %s", subject.Kind, subject.Namespace, subject.Name, serviceAccountKind))
+			}
+			continue
+		}
+		if subject.Namespace == smi.WildcardIdentity {
+			issues = append(issues, fmt.Sprintf("wildcard namespace requires wildcard name for %s %s", serviceAccountKind, subject.Name))
+			continue
+		}
+		if subject.Kind != serviceAccountKind {
+			issues = append(issues, fmt.Sprintf("invalid kind %q for identity %s/%s, must be %s", subject.Kind, subject.Namespace, subject.Name, serviceAccountKind))
+			continue
+		}
+		if subject.Namespace == "" {
+			issues = append(issues, fmt.Sprintf("namespace not specified for %s %s", serviceAccountKind, subject.Name))
+			continue
+		}
+		if !cmd.serviceAccountExists(resources, subject.Namespace, subject.Name) {
+			issues = append(issues, fmt.Sprintf("%s %s/%s not found", serviceAccountKind, subject.Namespace, subject.Name))
+		}
+	}
+
+	if len(trafficTarget.Spec.Rules) == 0 {
+		issues = append(issues, "no rules specified")
+	}
+	for _, rule := range trafficTarget.Spec.Rules {
+		switch rule.Kind {
+		case httpRouteGroupKind:
+			routeGroup := cmd.getHTTPRouteGroup(resources, trafficTarget.Namespace, rule.Name)
+			if routeGroup == nil {
+				issues = append(issues, fmt.Sprintf("%s %s/%s not found", httpRouteGroupKind, trafficTarget.Namespace, rule.Name))
+				continue
+			}
+			matchNames := make(map[string]bool)
+			for _, match := range routeGroup.Spec.Matches {
+				matchNames[match.Name] = true
+			}
+			for _, match := range rule.Matches {
+				if !matchNames[match] {
+					issues = append(issues, fmt.Sprintf("match %q not found in %s %s/%s", match, httpRouteGroupKind, trafficTarget.Namespace, rule.Name))
+				}
+			}
+
+		case tcpRouteKind:
+			if !cmd.tcpRouteExists(resources, trafficTarget.Namespace, rule.Name) {
+				issues = append(issues, fmt.Sprintf("%s %s/%s not found", tcpRouteKind, trafficTarget.Namespace, rule.Name))
+			}
+
+		default:
+			issues = append(issues, fmt.Sprintf("invalid kind %q for rule %s, must be %s or %s", rule.Kind, rule.Name, httpRouteGroupKind, tcpRouteKind))
+		}
+	}
+
+	return issues
+}
+
+func lintTrafficSplit(trafficSplit *smiSplit.TrafficSplit) []string {
+	var issues []string
+
+	if _, _, err := smi.GetPolicySchedule(trafficSplit); err != nil {
+		issues = append(issues, fmt.Sprintf("invalid %s annotation: %s", constants.PolicyScheduleAnnotation, err))
+	}
+
+	if trafficSplit.Spec.Service == "" {
+		issues = append(issues, "root service not specified")
+	}
+	if len(trafficSplit.Spec.Backends) == 0 {
+		issues = append(issues, "no backends specified")
+		return issues
+	}
+
+	totalWeight := 0
+	backends := make(map[string]bool)
+	for _, backend := range trafficSplit.Spec.Backends {
+		if backend.Service == "" {
+			issues = append(issues, "backend service not specified")
+		} else if backends[backend.Service] {
+			issues = append(issues, fmt.Sprintf("duplicate backend service %s", backend.Service))
+		}
+		backends[backend.Service] = true
+
+		if backend.Weight < 0 {
+			issues = append(issues, fmt.Sprintf("negative weight %d for backend service %s", backend.Weight, backend.Service))
+			continue
+		}
+		totalWeight += backend.Weight
+	}
+	if totalWeight == 0 {
+		issues = append(issues, "the sum of the backend weights must be greater than 0")
+	}
+
+	return issues
+}
+
+func (cmd *trafficPolicyLintCmd) serviceAccountExists(resources *lintResources, namespace, name string) bool {
+	if resources.serviceAccounts[namespacedName(namespace, name)] {
+		return true
+	}
+	if cmd.clientSet == nil {
+		return false
+	}
+
+	_, err := cmd.clientSet.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		cmd.warnings = append(cmd.warnings, fmt.Sprintf("Error looking up %s %s/%s in the cluster: %s", serviceAccountKind, namespace, name, err))
+	}
+	return err == nil
+}
+
+func (cmd *trafficPolicyLintCmd) getHTTPRouteGroup(resources *lintResources, namespace, name string) *smiSpecs.HTTPRouteGroup {
+	if routeGroup, ok := resources.httpRouteGroups[namespacedName(namespace, name)]; ok {
+		return routeGroup
+	}
+	if cmd.smiTrafficSpecClient == nil {
+		return nil
+	}
+
+	routeGroup, err := cmd.smiTrafficSpecClient.SpecsV1alpha4().HTTPRouteGroups(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			cmd.warnings = append(cmd.warnings, fmt.Sprintf("Error looking up %s %s/%s in the cluster: %s", httpRouteGroupKind, namespace, name, err))
+		}
+		return nil
+	}
+	return routeGroup
+}
+
+func (cmd *trafficPolicyLintCmd) tcpRouteExists(resources *lintResources, namespace, name string) bool {
+	if resources.tcpRoutes[namespacedName(namespace, name)] {
+		return true
+	}
+	if cmd.smiTrafficSpecClient == nil {
+		return false
+	}
+
+	_, err := cmd.smiTrafficSpecClient.SpecsV1alpha4().TCPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		cmd.warnings = append(cmd.warnings, fmt.Sprintf("Error looking up %s %s/%s in the cluster: %s", tcpRouteKind, namespace, name, err))
+	}
+	return err == nil
+}
+
+func namespacedName(namespace, name string) string {
+	return namespace + namespaceSeparator + name
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	fakeSpecClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned/fake"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const lintServiceAccounts = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: bookbuyer
+  namespace: bookbuyer
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: bookstore
+  namespace: bookstore
+`
+
+const lintRoutes = `
+apiVersion: specs.smi-spec.io/v1alpha4
+kind: HTTPRouteGroup
+metadata:
+  name: bookstore-service-routes
+  namespace: bookstore
+spec:
+  matches:
+  - name: buy-a-book
+    pathRegex: /buy
+    methods:
+    - GET
+---
+apiVersion: specs.smi-spec.io/v1alpha4
+kind: TCPRoute
+metadata:
+  name: tcp-route
+  namespace: bookstore
+spec:
+  matches:
+    ports:
+    - 8080
+`
+
+const lintTrafficTarget = `
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: bookbuyer-access-bookstore
+  namespace: bookstore
+spec:
+  destination:
+    kind: ServiceAccount
+    name: bookstore
+    namespace: bookstore
+  rules:
+  - kind: HTTPRouteGroup
+    name: bookstore-service-routes
+    matches:
+    - buy-a-book
+  - kind: TCPRoute
+    name: tcp-route
+  sources:
+  - kind: ServiceAccount
+    name: bookbuyer
+    namespace: bookbuyer
+`
+
+const lintValidTrafficSplit = `
+apiVersion: split.smi-spec.io/v1alpha2
+kind: TrafficSplit
+metadata:
+  name: bookstore-split
+  namespace: bookstore
+spec:
+  service: bookstore.bookstore
+  backends:
+  - service: bookstore-v1
+    weight: 90
+  - service: bookstore-v2
+    weight: 10
+`
+
+const lintInvalidTrafficTarget = `
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: invalid
+  namespace: bookstore
+spec:
+  destination:
+    kind: ServiceAccount
+    name: bookstore
+    namespace: bookstore
+  rules:
+  - kind: HTTPRouteGroup
+    name: bookstore-service-routes
+    matches:
+    - sell-a-book
+  - kind: HTTPRouteGroup
+    name: unknown-routes
+  - kind: TCPRoute
+    name: unknown-tcp-route
+  - kind: UDPRoute
+    name: udp-route
+  sources:
+  - kind: ServiceAccount
+    name: unknown
+    namespace: bookbuyer
+  - kind: Group
+    name: bookbuyer
+    namespace: bookbuyer
+`
+
+const lintWildcardTrafficTarget = `
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: wildcard
+  namespace: bookstore
+spec:
+  destination:
+    kind: ServiceAccount
+    name: "*"
+    namespace: bookstore
+  rules:
+  - kind: TCPRoute
+    name: tcp-route
+  sources:
+  - kind: ServiceAccount
+    name: "*"
+    namespace: bookbuyer
+  - kind: ServiceAccount
+    name: "*"
+    namespace: "*"
+  - kind: ServiceAccount
+    name: bookbuyer
+    namespace: "*"
+`
+
+const lintExpiredTrafficTarget = `
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: expired
+  namespace: bookstore
+  annotations:
+    openservicemesh.io/expires-at: "2021-01-01T00:00:00Z"
+spec:
+  destination:
+    kind: ServiceAccount
+    name: bookstore
+    namespace: bookstore
+  rules:
+  - kind: TCPRoute
+    name: tcp-route
+  sources:
+  - kind: ServiceAccount
+    name: bookbuyer
+    namespace: bookbuyer
+---
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: invalid-expiry
+  namespace: bookstore
+  annotations:
+    openservicemesh.io/expires-at: tomorrow
+spec:
+  destination:
+    kind: ServiceAccount
+    name: bookstore
+    namespace: bookstore
+  rules:
+  - kind: TCPRoute
+    name: tcp-route
+  sources:
+  - kind: ServiceAccount
+    name: bookbuyer
+    namespace: bookbuyer
+`
+
+const lintInvalidTrafficSplit = `
+apiVersion: split.smi-spec.io/v1alpha2
+kind: TrafficSplit
+metadata:
+  name: invalid
+  namespace: bookstore
+spec:
+  service: bookstore.bookstore
+  backends:
+  - service: bookstore-v1
+    weight: -10
+  - service: bookstore-v1
+    weight: 0
+`
+
+const lintInvalidSchedules = `
+apiVersion: access.smi-spec.io/v1alpha3
+kind: TrafficTarget
+metadata:
+  name: invalid-schedule
+  namespace: bookstore
+  annotations:
+    openservicemesh.io/active-schedule: "Mon-Fri 9am-5pm"
+spec:
+  destination:
+    kind: ServiceAccount
+    name: bookstore
+    namespace: bookstore
+  rules:
+  - kind: TCPRoute
+    name: tcp-route
+  sources:
+  - kind: ServiceAccount
+    name: bookbuyer
+    namespace: bookbuyer
+---
+apiVersion: split.smi-spec.io/v1alpha2
+kind: TrafficSplit
+metadata:
+  name: invalid-schedule
+  namespace: bookstore
+  annotations:
+    openservicemesh.io/active-schedule: "Someday 22:00-06:00"
+spec:
+  service: bookstore.bookstore
+  backends:
+  - service: bookstore-v1
+    weight: 50
+  - service: bookstore-v2
+    weight: 50
+`
+
+const lintUnsupportedVersion = `
+apiVersion: access.smi-spec.io/v1alpha2
+kind: TrafficTarget
+metadata:
+  name: old
+  namespace: bookstore
+`
+
+func writeLintFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestTrafficPolicyLint(t *testing.T) {
+	testCases := []struct {
+		name           string
+		files          map[string]string
+		expectedIssues int
+	}{
+		{
+			name: "valid policies",
+			files: map[string]string{
+				"accounts.yaml": lintServiceAccounts,
+				"routes.yml":    lintRoutes,
+				"target.yaml":   lintTrafficTarget,
+				"split.yaml":    lintValidTrafficSplit,
+				"README.md":     "not a manifest",
+			},
+			expectedIssues: 0,
+		},
+		{
+			name: "service accounts and routes not defined",
+			files: map[string]string{
+				"target.yaml": lintTrafficTarget,
+			},
+			expectedIssues: 4,
+		},
+		{
+			name: "invalid traffic target",
+			files: map[string]string{
+				"accounts.yaml": lintServiceAccounts,
+				"routes.yaml":   lintRoutes,
+				"target.yaml":   lintInvalidTrafficTarget,
+			},
+			// unknown match, unknown HTTPRouteGroup, unknown TCPRoute, invalid rule kind,
+			// unknown source, invalid source kind
+			expectedIssues: 6,
+		},
+		{
+			name: "wildcard traffic target",
+			files: map[string]string{
+				"routes.yaml": lintRoutes,
+				"target.yaml": lintWildcardTrafficTarget,
+			},
+			// wildcard destination, wildcard namespace without wildcard name
+			expectedIssues: 2,
+		},
+		{
+			name: "expired traffic targets",
+			files: map[string]string{
+				"accounts.yaml": lintServiceAccounts,
+				"routes.yaml":   lintRoutes,
+				"target.yaml":   lintExpiredTrafficTarget,
+			},
+			// expired, invalid expiry
+			expectedIssues: 2,
+		},
+		{
+			name: "invalid schedules",
+			files: map[string]string{
+				"accounts.yaml": lintServiceAccounts,
+				"routes.yaml":   lintRoutes,
+				"policies.yaml": lintInvalidSchedules,
+			},
+			// invalid TrafficTarget schedule, invalid TrafficSplit schedule
+			expectedIssues: 2,
+		},
+		{
+			name: "invalid traffic split",
+			files: map[string]string{
+				"split.yaml": lintInvalidTrafficSplit,
+			},
+			// negative weight, duplicate backend, zero total weight
+			expectedIssues: 3,
+		},
+		{
+			name: "unsupported API version",
+			files: map[string]string{
+				"target.yaml": lintUnsupportedVersion,
+			},
+			expectedIssues: 1,
+		},
+		{
+			name: "invalid YAML",
+			files: map[string]string{
+				"invalid.yaml": "kind: [TrafficTarget",
+			},
+			expectedIssues: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			cmd := &trafficPolicyLintCmd{
+				out: out,
+				dir: writeLintFiles(t, tc.files),
+			}
+
+			err := cmd.run()
+			assert.Equal(tc.expectedIssues > 0, err != nil)
+			assert.Equal(tc.expectedIssues, bytes.Count(out.Bytes(), []byte("[-] ")), out.String())
+		})
+	}
+}
+
+func TestTrafficPolicyLintCluster(t *testing.T) {
+	assert := tassert.New(t)
+
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer", Namespace: "bookbuyer"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"}},
+	)
+	fakeSpecs := fakeSpecClient.NewSimpleClientset()
+	_, err := fakeSpecs.SpecsV1alpha4().HTTPRouteGroups("bookstore").Create(context.TODO(), &smiSpecs.HTTPRouteGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore-service-routes", Namespace: "bookstore"},
+		Spec: smiSpecs.HTTPRouteGroupSpec{
+			Matches: []smiSpecs.HTTPMatch{{Name: "buy-a-book"}},
+		},
+	}, metav1.CreateOptions{})
+	assert.Nil(err)
+	_, err = fakeSpecs.SpecsV1alpha4().TCPRoutes("bookstore").Create(context.TODO(), &smiSpecs.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "tcp-route", Namespace: "bookstore"},
+	}, metav1.CreateOptions{})
+	assert.Nil(err)
+
+	out := new(bytes.Buffer)
+	cmd := &trafficPolicyLintCmd{
+		out:                  out,
+		dir:                  writeLintFiles(t, map[string]string{"target.yaml": lintTrafficTarget}),
+		cluster:              true,
+		clientSet:            fakeClient,
+		smiTrafficSpecClient: fakeSpecs,
+	}
+
+	err = cmd.run()
+	assert.Nil(err, out.String())
+}
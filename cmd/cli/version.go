@@ -13,6 +13,13 @@ const versionHelp = `
 This command prints out all the version information used by OSM
 `
 
+// versionOutput is the output of the version command
+type versionOutput struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+}
+
 // PrintCliVersion prints the version
 func PrintCliVersion(out io.Writer) {
 	_, _ = fmt.Fprintf(out, "Version: %s; Commit: %s; Date: %s\n", version.Version, version.GitCommit, version.BuildDate)
@@ -24,8 +31,12 @@ func newVersionCmd(out io.Writer) *cobra.Command {
 		Short: "osm cli version",
 		Long:  versionHelp,
 		Args:  cobra.NoArgs,
-		Run: func(cmd *cobra.Command, args []string) {
-			PrintCliVersion(out)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result := versionOutput{Version: version.Version, GitCommit: version.GitCommit, BuildDate: version.BuildDate}
+			return writeOutput(out, settings.Output(), result, func() error {
+				PrintCliVersion(out)
+				return nil
+			})
 		},
 	}
 	return cmd
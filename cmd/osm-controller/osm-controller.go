@@ -19,6 +19,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -32,21 +33,27 @@ import (
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/endpoint/providers/kube"
 	"github.com/openservicemesh/osm/pkg/envoy/ads"
+	"github.com/openservicemesh/osm/pkg/externalmetrics"
 	"github.com/openservicemesh/osm/pkg/featureflags"
 	"github.com/openservicemesh/osm/pkg/health"
 	"github.com/openservicemesh/osm/pkg/httpserver"
 	"github.com/openservicemesh/osm/pkg/ingress"
+	"github.com/openservicemesh/osm/pkg/introspection"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/lifecyclewebhook"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/policyreport"
+	"github.com/openservicemesh/osm/pkg/proxyimage"
 	"github.com/openservicemesh/osm/pkg/signals"
 	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/trafficmetrics"
+	"github.com/openservicemesh/osm/pkg/trustbundle"
+	"github.com/openservicemesh/osm/pkg/utils"
 	"github.com/openservicemesh/osm/pkg/version"
-)
-
-const (
-	xdsServerCertificateCommonName = "ads"
+	"github.com/openservicemesh/osm/pkg/wafmodule"
 )
 
 var (
@@ -60,6 +67,21 @@ var (
 
 	certProviderKind string
 
+	// The sidecar images injected by the injector, to detect proxies running outdated images
+	sidecarImage       string
+	sidecarImageByArch map[string]string
+
+	// Serve the SMI Traffic Metrics API as an aggregated API of the Kubernetes API server
+	enableTrafficMetrics bool
+
+	// Serve the External Metrics API as an aggregated API of the Kubernetes API server
+	enableExternalMetrics bool
+
+	// Serve the WAF WASM module packaged in the image, built from the pinned coraza-proxy-wasm release
+	wafModulePath    string
+	wafModuleVersion string
+	wafModuleSum     string
+
 	tresorOptions      providers.TresorOptions
 	vaultOptions       providers.VaultOptions
 	certManagerOptions providers.CertManagerOptions
@@ -71,9 +93,10 @@ var (
 )
 
 var (
-	flags = pflag.NewFlagSet(`osm-controller`, pflag.ExitOnError)
-	port  = flags.Int("port", constants.OSMControllerPort, "Aggregated Discovery Service port number.")
-	log   = logger.New("osm-controller/main")
+	flags             = pflag.NewFlagSet(`osm-controller`, pflag.ExitOnError)
+	port              = flags.Int("port", constants.OSMControllerPort, "Aggregated Discovery Service port number.")
+	introspectionPort = flags.Int("introspection-port", constants.OSMIntrospectionPort, "Introspection gRPC API port number.")
+	log               = logger.New("osm-controller/main")
 )
 
 func init() {
@@ -83,6 +106,13 @@ func init() {
 	flags.StringVar(&osmNamespace, "osm-namespace", "", "Namespace to which OSM belongs to.")
 	flags.StringVar(&webhookConfigName, "webhook-config-name", "", "Name of the MutatingWebhookConfiguration to be configured by osm-controller")
 	flags.StringVar(&osmConfigMapName, "osm-configmap-name", "osm-config", "Name of the OSM ConfigMap")
+	flags.StringVar(&sidecarImage, "sidecar-image", "", "Sidecar proxy Container image injected by the sidecar injector")
+	flags.StringToStringVar(&sidecarImageByArch, "sidecar-image-by-arch", nil, "Sidecar proxy Container image injected by the sidecar injector for pods constrained to a node architecture, of the form arch=image")
+	flags.BoolVar(&enableTrafficMetrics, "smi-traffic-metrics", false, "Serve the SMI Traffic Metrics API from the metrics of the Prometheus server configured in the OSM ConfigMap")
+	flags.BoolVar(&enableExternalMetrics, "external-metrics", false, "Serve the request rate and concurrency of the services through the External Metrics API from the metrics of the Prometheus server configured in the OSM ConfigMap")
+	flags.StringVar(&wafModulePath, "waf-module", "", "Path of the packaged WAF WASM module served to the sidecar proxies when no WAF module URL is configured in the OSM ConfigMap")
+	flags.StringVar(&wafModuleVersion, "waf-module-version", "", "Release of coraza-proxy-wasm the packaged WAF WASM module must be built from")
+	flags.StringVar(&wafModuleSum, "waf-module-sum", "", "Checksum in the Go checksum database of the source of the release of coraza-proxy-wasm the packaged WAF WASM module must be built from")
 
 	// Generic certificate manager/provider options
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
@@ -102,6 +132,8 @@ func init() {
 
 	// feature flags
 	flags.BoolVar(&optionalFeatures.WASMStats, "stats-wasm-experimental", false, "Enable a WebAssembly module that generates additional Envoy statistics.")
+	flags.BoolVar(&optionalFeatures.NodeProxyMode, "node-proxy-experimental", false, "Enable serving pods annotated for node proxy mode with a per-node proxy instead of a sidecar proxy.")
+	flags.BoolVar(&optionalFeatures.EgressGateway, "egress-gateway", false, "Enable routing the egress traffic of the sidecar proxies through the egress gateway.")
 
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = admissionv1.AddToScheme(scheme)
@@ -164,7 +196,24 @@ func main() {
 	}
 	log.Info().Msgf("Initial ConfigMap %s: %s", osmConfigMapName, string(configMap))
 
-	kubernetesClient, err := k8s.NewKubernetesController(kubeClient, meshName, stop)
+	// Serve the packaged WAF module to the sidecar proxies before they are programmed, when enabled
+	if wafModulePath != "" {
+		wafModule, err := wafmodule.Load(wafModulePath, wafModuleVersion, wafModuleSum, osmNamespace)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error loading the packaged WAF module")
+		}
+		wafModuleServer := httpserver.NewHTTPServer(constants.OSMWAFModulePort)
+		wafModuleServer.AddHandler(wafmodule.Path, wafModule)
+		if err := wafModuleServer.Start(); err != nil {
+			log.Fatal().Err(err).Msgf("Failed to start OSM WAF module HTTP server")
+		}
+		wafmodule.SetPackagedModule(wafModule)
+	}
+
+	// Post the mesh lifecycle events to the webhooks configured in the OSM ConfigMap
+	lifecyclewebhook.NewNotifier(kubeClient, cfg, osmNamespace).Start(stop)
+
+	kubernetesClient, err := k8s.NewKubernetesController(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), meshName, cfg, cfg, stop)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes Controller")
 	}
@@ -189,27 +238,44 @@ func main() {
 
 	endpointsProviders := []endpoint.Provider{kubeProvider}
 
-	ingressClient, err := ingress.NewIngressClient(kubeClient, kubernetesClient, stop, cfg)
+	ingressClient, err := ingress.NewIngressClient(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), kubernetesClient, stop, cfg)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Ingress monitor client")
 	}
 
+	policyClient, err := policy.NewPolicyClient(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), kubernetesClient, stop)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating policy monitor client")
+	}
+
 	meshCatalog := catalog.NewMeshCatalog(
 		kubernetesClient,
 		kubeClient,
 		meshSpec,
 		certManager,
 		ingressClient,
+		policyClient,
 		stop,
 		cfg,
 		endpointsProviders...)
 
+	// Report the status of the ingress resources OSM programs ingress policies for on the resources
+	meshCatalog.StartIngressStatusReporter(stop)
+
+	// Report the pods in node proxy mode whose traffic is not redirected to the node proxy of their node, when enabled
+	if featureflags.IsNodeProxyModeEnabled() {
+		meshCatalog.StartNodeProxyRedirectionReporter(stop)
+	}
+
+	// Publish the trust bundle of the mesh to the monitored namespaces, when enabled
+	trustbundle.NewPublisher(kubeClient, kubernetesClient, certManager, cfg).Start(stop)
+
 	// Create the configMap validating webhook
-	if err := configurator.NewValidatingWebhook(kubeClient, certManager, osmNamespace, webhookConfigName, stop); err != nil {
+	if err := configurator.NewValidatingWebhook(kubeClient, cfg, certManager, osmNamespace, webhookConfigName, stop); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating osm-config validating webhook")
 	}
 
-	adsCert, err := certManager.IssueCertificate(xdsServerCertificateCommonName, constants.XDSCertificateValidityPeriod)
+	adsCert, err := certManager.IssueCertificate(constants.XDSServerCertificateCommonName, constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing XDS certificate to ADS server")
 	}
@@ -220,6 +286,32 @@ func main() {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing ADS server")
 	}
 
+	// Create and start the introspection gRPC service. Its certificate is valid for the DNS name of the osm-controller
+	// service, which clients connect to.
+	introspectionCert, err := certManager.IssueCertificate(utils.GetControlPlaneCommonName(constants.OSMControllerName, osmNamespace), constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing certificate to introspection server")
+	}
+	imageChecker := proxyimage.NewChecker(kubernetesClient, cfg, sidecarImage, sidecarImageByArch)
+	introspectionServer := introspection.NewServer(meshCatalog, meshCatalog, certDebugger, imageChecker, cfg)
+	if err := introspectionServer.Start(ctx, cancel, *introspectionPort, introspectionCert); err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing introspection server")
+	}
+
+	// Serve the SMI Traffic Metrics API, registered with the Kubernetes API server by the APIService of the chart
+	if enableTrafficMetrics {
+		if err := trafficmetrics.NewServer(cfg).Start(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), certManager, osmNamespace, stop); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing SMI Traffic Metrics API server")
+		}
+	}
+
+	// Serve the External Metrics API for autoscaling, registered with the Kubernetes API server by the APIService of the chart
+	if enableExternalMetrics {
+		if err := externalmetrics.NewServer(cfg).Start(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), certManager, osmNamespace, stop); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing External Metrics API server")
+		}
+	}
+
 	// Initialize OSM's http service server
 	httpServer := httpserver.NewHTTPServer(constants.OSMHTTPServerPort)
 
@@ -242,9 +334,22 @@ func main() {
 
 	// Create DebugServer and start its config event listener.
 	// Listener takes care to start and stop the debug server as appropriate
-	debugConfig := debugger.NewDebugConfig(certDebugger, xdsServer, meshCatalog, kubeConfig, kubeClient, cfg, kubernetesClient)
+	policyReporter := policyreport.NewReporter(meshSpec, kubernetesClient, cfg)
+	// The debug server requires mTLS with the control plane certificate of the controller when enabled in osm-config
+	controlPlaneTLSConfig, err := utils.NewControlPlaneTLSConfig(certManager, constants.OSMControllerName, osmNamespace)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing control plane certificate")
+	}
+	debugConfig := debugger.NewDebugConfig(certDebugger, xdsServer, meshCatalog, policyReporter, kubeConfig, kubeClient, cfg, kubernetesClient, controlPlaneTLSConfig)
 	debugConfig.StartDebugServerConfigListener()
 
+	// Serve the OSM ConfigMap to the other components of the control plane, only over mTLS with control plane identities
+	configServer := httpserver.NewHTTPSServer(constants.OSMControlPlaneConfigPort, controlPlaneTLSConfig)
+	configServer.AddHandler(configurator.ConfigMapPath, configurator.NewConfigMapHandler(cfg))
+	if err := configServer.Start(); err != nil {
+		log.Fatal().Err(err).Msgf("Failed to start OSM control plane config server")
+	}
+
 	<-stop
 	log.Info().Msgf("Stopping osm-controller %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
 }
@@ -255,10 +360,28 @@ func startMetricsStore() {
 		metricsstore.DefaultMetricsStore.K8sAPIEventCounter,
 		metricsstore.DefaultMetricsStore.K8sMonitoredNamespaceCount,
 		metricsstore.DefaultMetricsStore.K8sMeshPodCount,
+		metricsstore.DefaultMetricsStore.K8sMeshLimit,
+		metricsstore.DefaultMetricsStore.K8sMeshSize,
+		metricsstore.DefaultMetricsStore.K8sNamespaceNotAdmittedCount,
+		metricsstore.DefaultMetricsStore.SMITemporaryAccessGrantCount,
 		metricsstore.DefaultMetricsStore.ProxyConnectCount,
 		metricsstore.DefaultMetricsStore.ProxyConfigUpdateTime,
+		metricsstore.DefaultMetricsStore.ProxyConfigThrottledCount,
+		metricsstore.DefaultMetricsStore.ProxyConfigInvalidCount,
+		metricsstore.DefaultMetricsStore.ProxyResourceConflictCount,
+		metricsstore.DefaultMetricsStore.ProxyRBACDenyCount,
+		metricsstore.DefaultMetricsStore.ProxyRBACShadowDenyCount,
+		metricsstore.DefaultMetricsStore.ProxyEgressRequestCount,
+		metricsstore.DefaultMetricsStore.ProxyEgressBytesCount,
+		metricsstore.DefaultMetricsStore.ProxyEgressAuditDenyCount,
 		metricsstore.DefaultMetricsStore.CertIssuedCount,
 		metricsstore.DefaultMetricsStore.CertIssuedTime,
+		metricsstore.DefaultMetricsStore.CertRotationPropagationTime,
+		metricsstore.DefaultMetricsStore.ConfigGeneration,
+		metricsstore.DefaultMetricsStore.ConfigGenerationPropagationTime,
+		metricsstore.DefaultMetricsStore.ConfigGenerationSkippedCount,
+		metricsstore.DefaultMetricsStore.NodeProxyUnredirectedPodCount,
+		metricsstore.DefaultMetricsStore.ProcessCollector,
 	)
 }
 
@@ -0,0 +1,127 @@
+// Package main implements the main entrypoint for osm-ebpf.
+// osm-ebpf runs on every node of the cluster and loads the eBPF programs accelerating the local hops of the traffic of
+// the meshed pods running on its node. The traffic of the pods is left to the TCP/IP stack if the node does not
+// support the eBPF acceleration.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/ebpf"
+	"github.com/openservicemesh/osm/pkg/httpserver"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity      string
+	kubeConfigFile string
+	nodeName       string
+	cgroupPath     string
+	redirect       bool
+	port           uint16
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-ebpf`, pflag.ExitOnError)
+	log   = logger.New("osm-ebpf/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&kubeConfigFile, "kubeconfig", "", "Path to Kubernetes config file.")
+	flags.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node whose meshed pods are accelerated, defaults to the NODE_NAME env variable")
+	flags.StringVar(&cgroupPath, "cgroup-path", "/sys/fs/cgroup", "Path at which the cgroup v2 hierarchy of the node is mounted")
+	flags.BoolVar(&redirect, "redirect-outbound", false, "Redirect the outbound connections of the applications of the meshed pods to their sidecar proxy with eBPF instead of iptables (experimental)")
+	flags.Uint16Var(&port, "port", constants.OSMHTTPServerPort, "Port on which the metrics are served")
+}
+
+func main() {
+	log.Info().Msgf("Starting osm-ebpf %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	// Initialize kube config and client
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error creating kube config (kubeconfig=%s)", kubeConfigFile)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
+
+	stop := signals.RegisterExitHandlers()
+
+	metricsstore.DefaultMetricsStore.Start(
+		metricsstore.DefaultMetricsStore.EBPFAccelerationEnabled,
+		metricsstore.DefaultMetricsStore.EBPFAcceleratedPodCount,
+		metricsstore.DefaultMetricsStore.EBPFRedirectedPodCount,
+	)
+
+	// The traffic of the meshed pods keeps traversing the TCP/IP stack when the eBPF programs cannot be loaded. The
+	// process keeps running to report it instead of restarting, which would not change the outcome on this node.
+	accelerator, err := ebpf.NewAccelerator(kubeClient, nodeName, cgroupPath, redirect, stop)
+	switch {
+	case errors.Is(err, ebpf.ErrNotSupported):
+		log.Warn().Err(err).Msgf("Node %s does not support the eBPF acceleration, the traffic of its meshed pods is not accelerated", nodeName)
+		metricsstore.DefaultMetricsStore.EBPFAccelerationEnabled.Set(0)
+	case err != nil:
+		log.Error().Err(err).Msgf("Error loading the eBPF programs on node %s, the traffic of its meshed pods is not accelerated", nodeName)
+		metricsstore.DefaultMetricsStore.EBPFAccelerationEnabled.Set(0)
+	default:
+		accelerator.Start(stop)
+	}
+
+	/*
+	 * Initialize osm-ebpf's HTTP server
+	 */
+	httpServer := httpserver.NewHTTPServer(port)
+	// Metrics
+	httpServer.AddHandler("/metrics", metricsstore.DefaultMetricsStore.Handler())
+	// Version
+	httpServer.AddHandler("/version", version.GetVersionHandler())
+	// Start HTTP server
+	err = httpServer.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to start OSM metrics HTTP server")
+	}
+
+	<-stop
+	log.Info().Msgf("Stopping osm-ebpf %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
+
+// validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
+func validateCLIParams() error {
+	if nodeName == "" {
+		return errors.New("Please specify the node name using --node-name or the NODE_NAME env variable")
+	}
+
+	if cgroupPath == "" {
+		return errors.New("Please specify the path of the cgroup v2 hierarchy using --cgroup-path")
+	}
+
+	return nil
+}
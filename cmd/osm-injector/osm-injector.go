@@ -23,6 +23,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/featureflags"
 	"github.com/openservicemesh/osm/pkg/httpserver"
 	"github.com/openservicemesh/osm/pkg/injector"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
@@ -30,6 +31,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/utils"
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
@@ -42,8 +44,15 @@ var (
 	caBundleSecretName string
 	osmConfigMapName   string
 
+	// readConfigFromController sets whether the OSM ConfigMap is read from osm-controller over mTLS rather than from the
+	// Kubernetes API server
+	readConfigFromController bool
+
 	injectorConfig injector.Config
 
+	// feature flag options
+	optionalFeatures featureflags.OptionalFeatures
+
 	certProviderKind string
 
 	tresorOptions      providers.TresorOptions
@@ -65,11 +74,13 @@ func init() {
 	flags.StringVar(&osmNamespace, "osm-namespace", "", "Namespace to which OSM belongs to.")
 	flags.StringVar(&webhookConfigName, "webhook-config-name", "", "Name of the MutatingWebhookConfiguration to be configured by osm-injector")
 	flags.StringVar(&osmConfigMapName, "osm-configmap-name", "osm-config", "Name of the OSM ConfigMap")
+	flags.BoolVar(&readConfigFromController, "read-config-from-controller", false, "Read the OSM ConfigMap from osm-controller over mTLS with control plane certificates issued by the mesh CA")
 
 	// sidecar injector options
 	flags.IntVar(&injectorConfig.ListenPort, "webhook-port", constants.InjectorWebhookPort, "Webhook port for sidecar-injector")
 	flags.StringVar(&injectorConfig.InitContainerImage, "init-container-image", "", "InitContainer image")
 	flags.StringVar(&injectorConfig.SidecarImage, "sidecar-image", "", "Sidecar proxy Container image")
+	flags.StringToStringVar(&injectorConfig.SidecarImageByArch, "sidecar-image-by-arch", nil, "Sidecar proxy Container image to use for pods constrained to a node architecture, of the form arch=image")
 
 	// Generic certificate manager/provider options
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
@@ -87,6 +98,10 @@ func init() {
 	flags.StringVar(&certManagerOptions.IssuerKind, "cert-manager-issuer-kind", "Issuer", "cert-manager issuer kind")
 	flags.StringVar(&certManagerOptions.IssuerGroup, "cert-manager-issuer-group", "cert-manager.io", "cert-manager issuer group")
 
+	// feature flags
+	flags.BoolVar(&optionalFeatures.NodeProxyMode, "node-proxy-experimental", false, "Enable serving pods annotated for node proxy mode with a per-node proxy instead of a sidecar proxy.")
+	flags.BoolVar(&optionalFeatures.EgressGateway, "egress-gateway", false, "Enable routing the egress traffic of the sidecar proxies through the egress gateway.")
+
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = admissionv1.AddToScheme(scheme)
 }
@@ -100,6 +115,8 @@ func main() {
 		log.Fatal().Err(err).Msg("Error setting log level")
 	}
 
+	featureflags.Initialize(optionalFeatures)
+
 	// Initialize kube config and client
 	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
 	if err != nil {
@@ -134,16 +151,20 @@ func main() {
 		metricsstore.DefaultMetricsStore.CertIssuedTime,
 	)
 
-	// Initialize Configurator to watch osm-config ConfigMap
-	cfg := configurator.NewConfigurator(kubeClient, stop, osmNamespace, osmConfigMapName)
-	configMap, err := cfg.GetConfigMap()
-	if err != nil {
-		log.Error().Err(err).Msgf("Error parsing ConfigMap %s", osmConfigMapName)
+	// Initialize Configurator to watch osm-config ConfigMap. When read from osm-controller, the ConfigMap is only read once
+	// the certificate manager issues the control plane certificate of the injector.
+	var cfg configurator.Configurator
+	var remoteCfg *configurator.RemoteClient
+	if readConfigFromController {
+		controllerAddr := fmt.Sprintf("%s:%d", utils.GetControlPlaneCommonName(constants.OSMControllerName, osmNamespace), constants.OSMControlPlaneConfigPort)
+		remoteCfg = configurator.NewRemoteConfigurator(controllerAddr, osmNamespace, osmConfigMapName)
+		cfg = remoteCfg
+	} else {
+		cfg = configurator.NewConfigurator(kubeClient, stop, osmNamespace, osmConfigMapName)
 	}
-	log.Debug().Msgf("Initial ConfigMap %s: %s", osmConfigMapName, string(configMap))
 
 	// Initialize kubernetes.Controller to watch kubernetes resources
-	kubeController, err := k8s.NewKubernetesController(kubeClient, meshName, stop, k8s.Namespaces)
+	kubeController, err := k8s.NewKubernetesController(kubeClient, nil, meshName, cfg, nil, stop, k8s.Namespaces)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes Controller")
 	}
@@ -158,6 +179,19 @@ func main() {
 			"Error initializing certificate manager of kind %s", certProviderKind)
 	}
 
+	if remoteCfg != nil {
+		controlPlaneTLSConfig, err := utils.NewControlPlaneTLSConfig(certManager, constants.OSMInjectorName, osmNamespace)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing control plane certificate")
+		}
+		remoteCfg.Start(controlPlaneTLSConfig, stop)
+	}
+	configMap, err := cfg.GetConfigMap()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing ConfigMap %s", osmConfigMapName)
+	}
+	log.Debug().Msgf("Initial ConfigMap %s: %s", osmConfigMapName, string(configMap))
+
 	// Initialize the sidecar injector webhook
 	if err := injector.NewMutatingWebhook(injectorConfig, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookConfigName, stop, cfg); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating sidecar injector webhook")
@@ -0,0 +1,110 @@
+// Package main implements the main entrypoint for osm-metrics-aggregator.
+// osm-metrics-aggregator runs on every node of the cluster, scrapes the sidecar proxies of the meshed pods running on
+// its node, and exposes their metrics aggregated per workload to Prometheus.
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/httpserver"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsaggregator"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity      string
+	kubeConfigFile string
+	nodeName       string
+	port           uint16
+	scrapeInterval time.Duration
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-metrics-aggregator`, pflag.ExitOnError)
+	log   = logger.New("osm-metrics-aggregator/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&kubeConfigFile, "kubeconfig", "", "Path to Kubernetes config file.")
+	flags.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node whose sidecar proxies are scraped, defaults to the NODE_NAME env variable")
+	flags.Uint16Var(&port, "port", constants.OSMHTTPServerPort, "Port on which the aggregated metrics are served")
+	flags.DurationVar(&scrapeInterval, "scrape-interval", 10*time.Second, "Interval at which the sidecar proxies are scraped")
+}
+
+func main() {
+	log.Info().Msgf("Starting osm-metrics-aggregator %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	// Initialize kube config and client
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error creating kube config (kubeconfig=%s)", kubeConfigFile)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
+
+	stop := signals.RegisterExitHandlers()
+
+	aggregator, err := metricsaggregator.NewAggregator(kubeClient, nodeName, scrapeInterval, stop)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating the metrics aggregator")
+	}
+	aggregator.Start(stop)
+
+	/*
+	 * Initialize osm-metrics-aggregator's HTTP server
+	 */
+	httpServer := httpserver.NewHTTPServer(port)
+	// Aggregated metrics of the sidecar proxies
+	httpServer.AddHandler("/metrics", aggregator.Handler())
+	// Version
+	httpServer.AddHandler("/version", version.GetVersionHandler())
+	// Start HTTP server
+	err = httpServer.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to start OSM metrics HTTP server")
+	}
+
+	<-stop
+	log.Info().Msgf("Stopping osm-metrics-aggregator %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
+
+// validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
+func validateCLIParams() error {
+	if nodeName == "" {
+		return errors.New("Please specify the node name using --node-name or the NODE_NAME env variable")
+	}
+
+	if scrapeInterval <= 0 {
+		return errors.Errorf("Please specify a positive scrape interval using --scrape-interval, got %s", scrapeInterval)
+	}
+
+	return nil
+}
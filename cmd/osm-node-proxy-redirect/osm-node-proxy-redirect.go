@@ -0,0 +1,126 @@
+// Package main implements the main entrypoint for osm-node-proxy-redirect.
+// osm-node-proxy-redirect runs next to the per-node proxy of every node of the cluster when the experimental node proxy
+// mode is enabled, and redirects the traffic of the node proxy mode pods of its node to the per-node proxy.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/health"
+	"github.com/openservicemesh/osm/pkg/httpserver"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/nodeproxy"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity      string
+	kubeConfigFile string
+	nodeName       string
+	nodeIP         string
+	meshName       string
+	port           uint16
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-node-proxy-redirect`, pflag.ExitOnError)
+	log   = logger.New("osm-node-proxy-redirect/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&kubeConfigFile, "kubeconfig", "", "Path to Kubernetes config file.")
+	flags.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node whose node proxy mode pods are redirected, defaults to the NODE_NAME env variable")
+	flags.StringVar(&nodeIP, "node-ip", os.Getenv("NODE_IP"), "IPv4 address of the node on which the node proxy listens, defaults to the NODE_IP env variable")
+	flags.StringVar(&meshName, "mesh-name", "", "OSM mesh name")
+	flags.Uint16Var(&port, "port", constants.NodeProxyRedirectPort, "Port on which the health probes and metrics are served")
+}
+
+func main() {
+	log.Info().Msgf("Starting osm-node-proxy-redirect %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+	if err := validateCLIParams(); err != nil {
+		log.Fatal().Err(err).Msg("Error validating CLI parameters")
+	}
+
+	// Initialize kube config and client
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error creating kube config (kubeconfig=%s)", kubeConfigFile)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
+
+	stop := signals.RegisterExitHandlers()
+
+	metricsstore.DefaultMetricsStore.Start(
+		metricsstore.DefaultMetricsStore.NodeProxyRedirectedPodCount,
+	)
+
+	redirector, err := nodeproxy.NewRedirector(kubeClient, nodeName, nodeIP, meshName, stop)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Error initializing the redirection of the node proxy mode pods of node %s", nodeName)
+	}
+	redirector.Start(stop)
+
+	/*
+	 * Initialize osm-node-proxy-redirect's HTTP server
+	 */
+	probes := []health.Probes{redirector}
+	httpServer := httpserver.NewHTTPServer(port)
+	httpServer.AddHandlers(map[string]http.Handler{
+		"/health/ready": health.ReadinessHandler(probes, nil),
+		"/health/alive": health.LivenessHandler(probes, nil),
+		"/metrics":      metricsstore.DefaultMetricsStore.Handler(),
+		"/version":      version.GetVersionHandler(),
+	})
+	// Start HTTP server
+	err = httpServer.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to start OSM metrics/probes HTTP server")
+	}
+
+	<-stop
+	<-redirector.Done()
+	log.Info().Msgf("Stopping osm-node-proxy-redirect %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
+
+// validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
+func validateCLIParams() error {
+	if nodeName == "" {
+		return errors.New("Please specify the node name using --node-name or the NODE_NAME env variable")
+	}
+
+	if nodeIP == "" {
+		return errors.New("Please specify the node IP address using --node-ip or the NODE_IP env variable")
+	}
+
+	if meshName == "" {
+		return errors.New("Please specify the mesh name using --mesh-name")
+	}
+
+	return nil
+}
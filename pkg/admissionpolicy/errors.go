@@ -0,0 +1,6 @@
+package admissionpolicy
+
+import "github.com/pkg/errors"
+
+// ErrInvalidPolicies is the error returned when the Rego policies in the admission policy ConfigMap cannot be compiled
+var ErrInvalidPolicies = errors.New("invalid admission policies")
@@ -0,0 +1,136 @@
+package admissionpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+// NewEvaluator returns a new Evaluator for the Rego policies in the given ConfigMap. The ConfigMap is watched by an
+// informer until the stop channel is closed, so that admission requests are evaluated without reading it from the API server.
+func NewEvaluator(kubeClient kubernetes.Interface, namespace, configMapName string, stop <-chan struct{}) *Evaluator {
+	// Ensure this informer exclusively watches only the admission policy ConfigMap
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient,
+		k8s.DefaultKubeEventResyncInterval, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", configMapName).String()
+		}))
+	configMaps := informerFactory.Core().V1().ConfigMaps()
+	informer := configMaps.Informer()
+
+	go informer.Run(stop)
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		log.Error().Msgf("Failed initial cache sync for admission policy ConfigMap %s/%s informer", namespace, configMapName)
+	}
+
+	return &Evaluator{
+		lister:        configMaps.Lister(),
+		namespace:     namespace,
+		configMapName: configMapName,
+	}
+}
+
+// Evaluate evaluates the given admission request against the admission policies, and returns the sorted messages of
+// the policies denying the request. The request is available to the policies as 'input.request'.
+// No message is returned if the admission policy ConfigMap does not exist.
+func (e *Evaluator) Evaluate(req *admissionv1.AdmissionRequest) ([]string, error) {
+	ctx := context.Background()
+
+	query, err := e.getPreparedQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		return nil, nil
+	}
+
+	input, err := toInput(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, errors.Errorf("Error evaluating admission policies: %s", err)
+	}
+
+	var denials []string
+	for _, result := range resultSet {
+		for _, expression := range result.Expressions {
+			messages, ok := expression.Value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, msg := range messages {
+				denials = append(denials, fmt.Sprint(msg))
+			}
+		}
+	}
+	sort.Strings(denials)
+	return denials, nil
+}
+
+// getPreparedQuery returns the deny query prepared with the policies in the admission policy ConfigMap, or nil if
+// the ConfigMap does not exist. The query is only prepared again when the resource version of the ConfigMap changes.
+func (e *Evaluator) getPreparedQuery(ctx context.Context) (*rego.PreparedEvalQuery, error) {
+	configMap, err := e.lister.ConfigMaps(e.namespace).Get(e.configMapName)
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("Error getting admission policy ConfigMap %s/%s: %s", e.namespace, e.configMapName, err)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.preparedQuery != nil && e.resourceVersion == configMap.ResourceVersion {
+		return e.preparedQuery, nil
+	}
+
+	options := []func(*rego.Rego){rego.Query(DenyQuery)}
+	for name, module := range configMap.Data {
+		if !strings.HasSuffix(name, regoFileExtension) {
+			continue
+		}
+		options = append(options, rego.Module(name, module))
+	}
+
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error compiling admission policies in ConfigMap %s/%s", e.namespace, e.configMapName)
+		return nil, errors.Wrap(ErrInvalidPolicies, err.Error())
+	}
+
+	log.Info().Msgf("Compiled admission policies in ConfigMap %s/%s at resource version %s", e.namespace, e.configMapName, configMap.ResourceVersion)
+	e.preparedQuery = &query
+	e.resourceVersion = configMap.ResourceVersion
+	return e.preparedQuery, nil
+}
+
+// toInput returns the Rego input for the given admission request, with the request as generic JSON values
+func toInput(req *admissionv1.AdmissionRequest) (map[string]interface{}, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var request map[string]interface{}
+	if err := json.Unmarshal(reqBytes, &request); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"request": request}, nil
+}
@@ -0,0 +1,183 @@
+package admissionpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testNamespace     = "osm-system"
+	testConfigMapName = "osm-admission-policies"
+)
+
+const noWildcardSourcesPolicy = `
+package osm.admission
+
+deny[msg] {
+	input.request.kind.kind == "TrafficTarget"
+	source := input.request.object.spec.sources[_]
+	source.name == "*"
+	msg := sprintf("TrafficTarget %s must not use wildcard sources", [input.request.name])
+}
+`
+
+const requiredLabelPolicy = `
+package osm.admission
+
+deny[msg] {
+	not input.request.object.metadata.labels.team
+	msg := sprintf("%s %s must have a team label", [input.request.kind.kind, input.request.name])
+}
+`
+
+func newTrafficTargetRequest(t *testing.T, labels map[string]string, sourceName string) *admissionv1.AdmissionRequest {
+	trafficTarget := smiAccess.TrafficTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookstore",
+			Namespace: "bookstore",
+			Labels:    labels,
+		},
+		Spec: smiAccess.TrafficTargetSpec{
+			Sources: []smiAccess.IdentityBindingSubject{
+				{Kind: "ServiceAccount", Name: sourceName, Namespace: "bookbuyer"},
+			},
+		},
+	}
+	raw, err := json.Marshal(trafficTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &admissionv1.AdmissionRequest{
+		UID:       "11111111-2222-3333-4444-555555555555",
+		Kind:      metav1.GroupVersionKind{Group: "access.smi-spec.io", Version: "v1alpha3", Kind: "TrafficTarget"},
+		Name:      "bookstore",
+		Namespace: "bookstore",
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		policies        map[string]string
+		req             *admissionv1.AdmissionRequest
+		expectedDenials []string
+		expectedError   error
+	}{
+		{
+			name:            "no admission policy ConfigMap",
+			policies:        nil,
+			req:             newTrafficTargetRequest(t, nil, "*"),
+			expectedDenials: nil,
+		},
+		{
+			name: "request allowed by the policies",
+			policies: map[string]string{
+				"wildcards.rego": noWildcardSourcesPolicy,
+				"labels.rego":    requiredLabelPolicy,
+			},
+			req:             newTrafficTargetRequest(t, map[string]string{"team": "bookstore"}, "bookbuyer"),
+			expectedDenials: nil,
+		},
+		{
+			name: "request denied by the policies",
+			policies: map[string]string{
+				"wildcards.rego": noWildcardSourcesPolicy,
+				"labels.rego":    requiredLabelPolicy,
+			},
+			req: newTrafficTargetRequest(t, nil, "*"),
+			expectedDenials: []string{
+				"TrafficTarget bookstore must have a team label",
+				"TrafficTarget bookstore must not use wildcard sources",
+			},
+		},
+		{
+			name: "keys other than Rego policies are ignored",
+			policies: map[string]string{
+				"README.md": "not a policy",
+			},
+			req:             newTrafficTargetRequest(t, nil, "*"),
+			expectedDenials: nil,
+		},
+		{
+			name: "invalid policy",
+			policies: map[string]string{
+				"invalid.rego": "package osm.admission\ndeny[msg] {",
+			},
+			req:           newTrafficTargetRequest(t, nil, "*"),
+			expectedError: ErrInvalidPolicies,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset()
+			if tc.policies != nil {
+				_, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testConfigMapName,
+						Namespace: testNamespace,
+					},
+					Data: tc.policies,
+				}, metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+
+			stop := make(chan struct{})
+			defer close(stop)
+
+			evaluator := NewEvaluator(kubeClient, testNamespace, testConfigMapName, stop)
+			denials, err := evaluator.Evaluate(tc.req)
+			assert.Equal(tc.expectedError, errors.Cause(err))
+			assert.Equal(tc.expectedDenials, denials)
+		})
+	}
+}
+
+func TestEvaluatePoliciesUpdated(t *testing.T) {
+	assert := tassert.New(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            testConfigMapName,
+			Namespace:       testNamespace,
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{"labels.rego": requiredLabelPolicy},
+	}
+	kubeClient := fake.NewSimpleClientset(configMap)
+	stop := make(chan struct{})
+	defer close(stop)
+	evaluator := NewEvaluator(kubeClient, testNamespace, testConfigMapName, stop)
+	req := newTrafficTargetRequest(t, map[string]string{"team": "bookstore"}, "*")
+
+	denials, err := evaluator.Evaluate(req)
+	assert.Nil(err)
+	assert.Empty(denials)
+
+	configMap.ResourceVersion = "2"
+	configMap.Data = map[string]string{"wildcards.rego": noWildcardSourcesPolicy}
+	updatedConfigMap, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.Nil(err)
+
+	// The updated policies are evaluated once the informer observes the new resource version of the ConfigMap
+	assert.Eventually(func() bool {
+		denials, err := evaluator.Evaluate(req)
+		return err == nil && len(denials) == 1 && denials[0] == "TrafficTarget bookstore must not use wildcard sources"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(updatedConfigMap.ResourceVersion, evaluator.resourceVersion)
+}
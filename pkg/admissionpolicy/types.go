@@ -0,0 +1,33 @@
+// Package admissionpolicy implements the evaluation of operator supplied Rego policies against the admission
+// requests of mesh resources, so that custom governance rules can be enforced when mesh policies are authored.
+package admissionpolicy
+
+import (
+	"sync"
+
+	"github.com/open-policy-agent/opa/rego"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("admission-policy")
+
+const (
+	// DenyQuery is the query evaluated against admission requests. Policies must define the partial set rule
+	// 'deny' in the 'osm.admission' package, with a message for each reason the request must be denied.
+	DenyQuery = "data.osm.admission.deny"
+
+	// regoFileExtension is the extension of the keys of the admission policy ConfigMap holding Rego policies
+	regoFileExtension = ".rego"
+)
+
+// Evaluator evaluates admission requests against the Rego policies stored in the admission policy ConfigMap
+type Evaluator struct {
+	lister          listersv1.ConfigMapLister
+	namespace       string
+	configMapName   string
+	preparedQuery   *rego.PreparedEvalQuery
+	resourceVersion string
+	mutex           sync.Mutex
+}
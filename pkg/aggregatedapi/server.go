@@ -0,0 +1,210 @@
+package aggregatedapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// apiServiceResource is the resource of the APIServices registering aggregated APIs with the Kubernetes API server
+var apiServiceResource = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// NewServer returns a server of the aggregated API with the given name, registered by the given APIService and served
+// on the given port by the given handler
+func NewServer(name, apiServiceName string, port int, handler http.Handler) *Server {
+	return &Server{
+		name:           name,
+		apiServiceName: apiServiceName,
+		port:           port,
+		handler:        handler,
+	}
+}
+
+// Start serves the API over TLS until the stop channel is closed, and registers the CA of the certificate of the server
+// with the APIService of the API. Only the requests proxied by the Kubernetes API server, which authenticates and
+// authorizes the requests of the users of the API, are served.
+func (s *Server) Start(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, certManager certificate.Manager, osmNamespace string, stop <-chan struct{}) error {
+	clientCAs, allowedNames, err := getRequestHeaderClientCAs(kubeClient)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting the request header CA of the Kubernetes API server")
+		return err
+	}
+	s.allowedNames = allowedNames
+
+	cert, err := certManager.IssueCertificate(utils.GetControlPlaneCommonName(constants.OSMControllerName, osmNamespace), constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing certificate for the %s server", s.name)
+		return err
+	}
+	keyPair, err := tls.X509KeyPair(cert.GetCertificateChain(), cert.GetPrivateKey())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing the certificate of the %s server", s.name)
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.port),
+		Handler: s,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{keyPair},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS12,
+		},
+	}
+
+	log.Info().Msgf("Starting %s server on port: %d", s.name, s.port)
+	go func() {
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msgf("%s server failed", s.name)
+		}
+	}()
+	go func() {
+		<-stop
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msgf("Error shutting down %s server", s.name)
+		}
+	}()
+
+	if err := updateAPIServiceCABundle(dynamicClient, s.apiServiceName, cert); err != nil {
+		log.Error().Err(err).Msgf("Error configuring APIService %s", s.apiServiceName)
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP serves the GET requests of the allowed clients with the handler of the API
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	log.Trace().Msgf("Received %s request: Method=%v, URL=%v", s.name, req.Method, req.URL)
+
+	if !s.isAllowedClient(req) {
+		WriteStatus(w, http.StatusForbidden, fmt.Sprintf("Client certificate is not allowed to proxy requests to the %s", s.name))
+		return
+	}
+	if req.Method != http.MethodGet {
+		WriteStatus(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s is not supported", req.Method))
+		return
+	}
+	s.handler.ServeHTTP(w, req)
+}
+
+// isAllowedClient returns true if the client certificate of the given request has one of the names allowed for the
+// Kubernetes API server
+func (s *Server) isAllowedClient(req *http.Request) bool {
+	if len(s.allowedNames) == 0 {
+		return true
+	}
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return false
+	}
+	commonName := req.TLS.PeerCertificates[0].Subject.CommonName
+	for _, name := range s.allowedNames {
+		if name == commonName {
+			return true
+		}
+	}
+	return false
+}
+
+// getRequestHeaderClientCAs returns the CA pool of the client certificates the Kubernetes API server authenticates with
+// when proxying requests to aggregated APIs, and the common names allowed for the certificates, any if empty
+func getRequestHeaderClientCAs(kubeClient kubernetes.Interface) (*x509.CertPool, []string, error) {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(context.Background(), requestHeaderConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Error getting ConfigMap %s/%s", metav1.NamespaceSystem, requestHeaderConfigMap)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM([]byte(configMap.Data[requestHeaderClientCAKey])) {
+		return nil, nil, errors.Errorf("No request header CA found in key %s of ConfigMap %s/%s", requestHeaderClientCAKey, metav1.NamespaceSystem, requestHeaderConfigMap)
+	}
+
+	var allowedNames []string
+	if names := configMap.Data[requestHeaderAllowedNamesKey]; names != "" {
+		if err := json.Unmarshal([]byte(names), &allowedNames); err != nil {
+			return nil, nil, errors.Wrapf(err, "Error parsing key %s of ConfigMap %s/%s", requestHeaderAllowedNamesKey, metav1.NamespaceSystem, requestHeaderConfigMap)
+		}
+	}
+	return clientCAs, allowedNames, nil
+}
+
+// updateAPIServiceCABundle sets the CA bundle of the given APIService to the CA of the given certificate of the server
+func updateAPIServiceCABundle(dynamicClient dynamic.Interface, apiServiceName string, cert certificate.Certificater) error {
+	patchJSON, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"caBundle": cert.GetIssuingCA(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := dynamicClient.Resource(apiServiceResource).Patch(context.Background(), apiServiceName, types.MergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "Error updating CA bundle of APIService %s", apiServiceName)
+	}
+
+	log.Info().Msgf("Finished updating CA bundle of APIService %s", apiServiceName)
+	return nil
+}
+
+// NewAPIResourceList returns the list of the given resources served by the given group version, for the discovery of
+// the API
+func NewAPIResourceList(groupVersion string, resources ...metav1.APIResource) *metav1.APIResourceList {
+	return &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: groupVersion,
+		APIResources: resources,
+	}
+}
+
+// WriteJSON writes the given object as a JSON response
+func WriteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("Error writing aggregated API response")
+	}
+}
+
+// WriteStatus writes a failure Status with the given code and message, as returned by the Kubernetes API
+func WriteStatus(w http.ResponseWriter, code int, message string) {
+	status := &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Code:     int32(code),
+	}
+	switch code {
+	case http.StatusBadRequest:
+		status.Reason = metav1.StatusReasonBadRequest
+	case http.StatusNotFound:
+		status.Reason = metav1.StatusReasonNotFound
+	case http.StatusForbidden:
+		status.Reason = metav1.StatusReasonForbidden
+	case http.StatusMethodNotAllowed:
+		status.Reason = metav1.StatusReasonMethodNotAllowed
+	case http.StatusServiceUnavailable:
+		status.Reason = metav1.StatusReasonServiceUnavailable
+	default:
+		status.Reason = metav1.StatusReasonInternalError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Error().Err(err).Msg("Error writing aggregated API response")
+	}
+}
@@ -0,0 +1,123 @@
+package aggregatedapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/tests/certificates"
+)
+
+const testPath = "/apis/metrics.example.com/v1"
+
+func newRequest(method, commonName string) *http.Request {
+	req := httptest.NewRequest(method, testPath, nil)
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: commonName}}},
+	}
+	return req
+}
+
+func TestServeHTTP(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, NewAPIResourceList("metrics.example.com/v1"))
+	})
+
+	testCases := []struct {
+		name           string
+		req            *http.Request
+		expectedStatus int
+		expectedKind   string
+	}{
+		{
+			name:           "allowed client",
+			req:            newRequest(http.MethodGet, "front-proxy-client"),
+			expectedStatus: http.StatusOK,
+			expectedKind:   "APIResourceList",
+		},
+		{
+			name:           "client not allowed",
+			req:            newRequest(http.MethodGet, "bookbuyer"),
+			expectedStatus: http.StatusForbidden,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "unsupported method",
+			req:            newRequest(http.MethodPost, "front-proxy-client"),
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedKind:   "Status",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			s := NewServer("Test API", "v1.metrics.example.com", 0, handler)
+			s.allowedNames = []string{"front-proxy-client"}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, tc.req)
+
+			assert.Equal(tc.expectedStatus, w.Code)
+			var typeMeta metav1.TypeMeta
+			assert.Nil(json.Unmarshal(w.Body.Bytes(), &typeMeta))
+			assert.Equal(tc.expectedKind, typeMeta.Kind)
+		})
+	}
+}
+
+func TestIsAllowedClient(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Any client certificate is allowed if no name is
+	s := &Server{}
+	assert.True(s.isAllowedClient(newRequest(http.MethodGet, "front-proxy-client")))
+
+	s.allowedNames = []string{"front-proxy-client", "aggregator"}
+	assert.True(s.isAllowedClient(newRequest(http.MethodGet, "aggregator")))
+	assert.False(s.isAllowedClient(newRequest(http.MethodGet, "bookbuyer")))
+	assert.False(s.isAllowedClient(httptest.NewRequest(http.MethodGet, testPath, nil)))
+}
+
+func TestGetRequestHeaderClientCAs(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: requestHeaderConfigMap, Namespace: metav1.NamespaceSystem},
+		Data: map[string]string{
+			requestHeaderClientCAKey:     certificates.SampleCertificatePEM,
+			requestHeaderAllowedNamesKey: `["front-proxy-client"]`,
+		},
+	})
+	clientCAs, allowedNames, err := getRequestHeaderClientCAs(kubeClient)
+	assert.Nil(err)
+	assert.NotNil(clientCAs)
+	assert.Equal([]string{"front-proxy-client"}, allowedNames)
+
+	// The ConfigMap is required
+	_, _, err = getRequestHeaderClientCAs(fake.NewSimpleClientset())
+	assert.NotNil(err)
+}
+
+func TestWriteStatus(t *testing.T) {
+	assert := tassert.New(t)
+
+	w := httptest.NewRecorder()
+	WriteStatus(w, http.StatusBadRequest, "Invalid label selector")
+
+	assert.Equal(http.StatusBadRequest, w.Code)
+	var status metav1.Status
+	assert.Nil(json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(metav1.StatusFailure, status.Status)
+	assert.Equal(metav1.StatusReasonBadRequest, status.Reason)
+	assert.Equal("Invalid label selector", status.Message)
+}
@@ -0,0 +1,42 @@
+// Package aggregatedapi implements what the APIs served by osm-controller as aggregated APIs of the Kubernetes API
+// server have in common: the TLS server only serving the requests proxied by the Kubernetes API server, the
+// registration of the CA of its certificate with the APIService of the API, and the responses in the format of the
+// Kubernetes API.
+package aggregatedapi
+
+import (
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("aggregated-api")
+
+const (
+	// requestHeaderConfigMap is the ConfigMap in the kube-system namespace holding the CA and the names of the client
+	// certificates the Kubernetes API server authenticates with when proxying requests to aggregated APIs
+	requestHeaderConfigMap = "extension-apiserver-authentication"
+
+	requestHeaderClientCAKey     = "requestheader-client-ca-file"
+	requestHeaderAllowedNamesKey = "requestheader-allowed-names"
+)
+
+// Server serves an aggregated API with the given handler, once the requests are authenticated as proxied by the
+// Kubernetes API server.
+type Server struct {
+	// name is the name of the API, for logging
+	name string
+
+	// apiServiceName is the name of the APIService registering the API with the Kubernetes API server
+	apiServiceName string
+
+	// port is the port the API is served on
+	port int
+
+	// handler serves the authenticated GET requests for the API
+	handler http.Handler
+
+	// allowedNames are the common names of the client certificates the Kubernetes API server may present, any name
+	// being allowed if empty
+	allowedNames []string
+}
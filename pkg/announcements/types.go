@@ -119,6 +119,13 @@ const (
 	// TrafficTargetUpdated is the type of announcement emitted when we observe an update to a Kubernetes TrafficTarget
 	TrafficTargetUpdated AnnouncementType = "traffictarget-updated"
 
+	// TrafficTargetExpired is the type of announcement emitted when a Kubernetes TrafficTarget granting temporary access expires
+	TrafficTargetExpired AnnouncementType = "traffictarget-expired"
+
+	// PolicyScheduleBoundary is the type of announcement emitted when an SMI policy applying on a schedule starts or
+	// stops applying at the boundary of one of its time windows
+	PolicyScheduleBoundary AnnouncementType = "policy-schedule-boundary"
+
 	// ---
 
 	// ConfigMapAdded is the type of announcement emitted when we observe an addition of a Kubernetes ConfigMap
@@ -141,8 +148,101 @@ const (
 	// IngressUpdated is the type of announcement emitted when we observe an update to a Kubernetes Ingress
 	IngressUpdated AnnouncementType = "ingress-updated"
 
+	// ---
+
+	// IngressBackendAdded is the type of announcement emitted when we observe an addition of an OSM IngressBackend
+	IngressBackendAdded AnnouncementType = "ingressbackend-added"
+
+	// IngressBackendDeleted the type of announcement emitted when we observe the deletion of an OSM IngressBackend
+	IngressBackendDeleted AnnouncementType = "ingressbackend-deleted"
+
+	// IngressBackendUpdated is the type of announcement emitted when we observe an update to an OSM IngressBackend
+	IngressBackendUpdated AnnouncementType = "ingressbackend-updated"
+
+	// ---
+
+	// EgressAdded is the type of announcement emitted when we observe an addition of an OSM Egress
+	EgressAdded AnnouncementType = "egress-added"
+
+	// EgressDeleted the type of announcement emitted when we observe the deletion of an OSM Egress
+	EgressDeleted AnnouncementType = "egress-deleted"
+
+	// EgressUpdated is the type of announcement emitted when we observe an update to an OSM Egress
+	EgressUpdated AnnouncementType = "egress-updated"
+
+	// ---
+
+	// RetryPolicyAdded is the type of announcement emitted when we observe an addition of an OSM Retry
+	RetryPolicyAdded AnnouncementType = "retrypolicy-added"
+
+	// RetryPolicyDeleted the type of announcement emitted when we observe the deletion of an OSM Retry
+	RetryPolicyDeleted AnnouncementType = "retrypolicy-deleted"
+
+	// RetryPolicyUpdated is the type of announcement emitted when we observe an update to an OSM Retry
+	RetryPolicyUpdated AnnouncementType = "retrypolicy-updated"
+
+	// ---
+
+	// UpstreamTrafficSettingAdded is the type of announcement emitted when we observe an addition of an OSM
+	// UpstreamTrafficSetting
+	UpstreamTrafficSettingAdded AnnouncementType = "upstreamtrafficsetting-added"
+
+	// UpstreamTrafficSettingDeleted the type of announcement emitted when we observe the deletion of an OSM
+	// UpstreamTrafficSetting
+	UpstreamTrafficSettingDeleted AnnouncementType = "upstreamtrafficsetting-deleted"
+
+	// UpstreamTrafficSettingUpdated is the type of announcement emitted when we observe an update to an OSM
+	// UpstreamTrafficSetting
+	UpstreamTrafficSettingUpdated AnnouncementType = "upstreamtrafficsetting-updated"
+
+	// ---
+
+	// BandwidthLimitAdded is the type of announcement emitted when we observe an addition of an OSM BandwidthLimit
+	BandwidthLimitAdded AnnouncementType = "bandwidthlimit-added"
+
+	// BandwidthLimitDeleted the type of announcement emitted when we observe the deletion of an OSM BandwidthLimit
+	BandwidthLimitDeleted AnnouncementType = "bandwidthlimit-deleted"
+
+	// BandwidthLimitUpdated is the type of announcement emitted when we observe an update to an OSM BandwidthLimit
+	BandwidthLimitUpdated AnnouncementType = "bandwidthlimit-updated"
+
+	// ---
+
+	// GatewayAdded is the type of announcement emitted when we observe an addition of a Gateway API Gateway
+	GatewayAdded AnnouncementType = "gateway-added"
+
+	// GatewayDeleted the type of announcement emitted when we observe the deletion of a Gateway API Gateway
+	GatewayDeleted AnnouncementType = "gateway-deleted"
+
+	// GatewayUpdated is the type of announcement emitted when we observe an update to a Gateway API Gateway
+	GatewayUpdated AnnouncementType = "gateway-updated"
+
+	// ---
+
+	// GatewayHTTPRouteAdded is the type of announcement emitted when we observe an addition of a Gateway API HTTPRoute
+	GatewayHTTPRouteAdded AnnouncementType = "gateway-httproute-added"
+
+	// GatewayHTTPRouteDeleted the type of announcement emitted when we observe the deletion of a Gateway API HTTPRoute
+	GatewayHTTPRouteDeleted AnnouncementType = "gateway-httproute-deleted"
+
+	// GatewayHTTPRouteUpdated is the type of announcement emitted when we observe an update to a Gateway API HTTPRoute
+	GatewayHTTPRouteUpdated AnnouncementType = "gateway-httproute-updated"
+
+	// ---
+
 	// CertificateRotated is the type of announcement emitted when a certificate is rotated by the certificate provider
 	CertificateRotated AnnouncementType = "certificate-rotated"
+
+	// ---
+
+	// ProxyConnected is the type of announcement emitted when a proxy connects to the control plane
+	ProxyConnected AnnouncementType = "proxy-connected"
+
+	// ProxyDisconnected is the type of announcement emitted when a proxy disconnects from the control plane
+	ProxyDisconnected AnnouncementType = "proxy-disconnected"
+
+	// ProxyConfigRejected is the type of announcement emitted when a proxy rejects the configuration sent by the control plane
+	ProxyConfigRejected AnnouncementType = "proxy-config-rejected"
 )
 
 // Announcement is a struct for messages between various components of OSM signaling a need for a change in Envoy proxy configuration
@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GatewayKind is the kind of the Gateway API type
+	GatewayKind = "Gateway"
+
+	// RouteSelectSame selects the routes in the namespace of the Gateway
+	RouteSelectSame = "Same"
+
+	// RouteSelectAll selects the routes in all namespaces
+	RouteSelectAll = "All"
+
+	// RouteSelectSelector selects the routes in the namespaces matching a label selector
+	RouteSelectSelector = "Selector"
+)
+
+// Gateway is the type used to represent a load balancer exposing routes to clients outside of the cluster.
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the Gateway
+	Spec GatewaySpec `json:"spec"`
+}
+
+// GatewaySpec is the type used to represent the specification of a Gateway.
+type GatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of the Gateway
+	GatewayClassName string `json:"gatewayClassName"`
+
+	// Listeners are the logical endpoints the Gateway accepts traffic on
+	Listeners []Listener `json:"listeners"`
+}
+
+// Listener is the type used to represent a logical endpoint of a Gateway.
+type Listener struct {
+	// Hostname is the hostname of the requests accepted by the listener, all hostnames when not set
+	Hostname *string `json:"hostname,omitempty"`
+
+	// Port is the port the listener accepts traffic on
+	Port int32 `json:"port"`
+
+	// Protocol is the protocol of the traffic accepted by the listener
+	Protocol string `json:"protocol"`
+
+	// Routes selects the routes bound to the listener
+	Routes RouteBindingSelector `json:"routes"`
+}
+
+// RouteBindingSelector is the type used to represent the routes a listener binds to.
+type RouteBindingSelector struct {
+	// Namespaces selects the namespaces of the routes, the namespace of the Gateway when not set
+	Namespaces *RouteNamespaces `json:"namespaces,omitempty"`
+
+	// Selector selects the routes by their labels, all routes when not set
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Group is the API group of the routes, gateway.networking.k8s.io when not set
+	Group *string `json:"group,omitempty"`
+
+	// Kind is the kind of the routes
+	Kind string `json:"kind"`
+}
+
+// RouteNamespaces is the type used to represent the namespaces of the routes a listener binds to.
+type RouteNamespaces struct {
+	// From is one of RouteSelectSame, RouteSelectAll or RouteSelectSelector, RouteSelectSame when not set
+	From *string `json:"from,omitempty"`
+
+	// Selector selects the namespaces by their labels when From is RouteSelectSelector
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HTTPRouteKind is the kind of the HTTPRoute API type
+	HTTPRouteKind = "HTTPRoute"
+
+	// GatewayAllowAll allows the route to bind to Gateways in all namespaces
+	GatewayAllowAll = "All"
+
+	// GatewayAllowFromList allows the route to bind to the Gateways it references
+	GatewayAllowFromList = "FromList"
+
+	// GatewayAllowSameNamespace allows the route to bind to the Gateways in its namespace
+	GatewayAllowSameNamespace = "SameNamespace"
+
+	// PathMatchExact matches the request path exactly
+	PathMatchExact = "Exact"
+
+	// PathMatchPrefix matches the request path by its prefix, element wise
+	PathMatchPrefix = "Prefix"
+
+	// PathMatchRegularExpression matches the request path with a regular expression
+	PathMatchRegularExpression = "RegularExpression"
+
+	// PathMatchImplementationSpecific matches the request path in a way specific to the implementation
+	PathMatchImplementationSpecific = "ImplementationSpecific"
+
+	// HeaderMatchExact matches the value of a request header exactly
+	HeaderMatchExact = "Exact"
+
+	// HeaderMatchRegularExpression matches the value of a request header with a regular expression
+	HeaderMatchRegularExpression = "RegularExpression"
+)
+
+// HTTPRoute is the type used to represent the routing of HTTP requests accepted by Gateways to backend services.
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the HTTPRoute
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec is the type used to represent the specification of an HTTPRoute.
+type HTTPRouteSpec struct {
+	// Gateways restricts the Gateways the route may bind to
+	Gateways *RouteGateways `json:"gateways,omitempty"`
+
+	// Hostnames are the hostnames of the requests matched by the route, all hostnames when not set
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Rules are the rules routing the matched requests to backends
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// RouteGateways is the type used to represent the Gateways a route may bind to.
+type RouteGateways struct {
+	// Allow is one of GatewayAllowAll, GatewayAllowFromList or GatewayAllowSameNamespace, GatewayAllowSameNamespace
+	// when not set
+	Allow *string `json:"allow,omitempty"`
+
+	// GatewayRefs are the Gateways the route may bind to when Allow is GatewayAllowFromList
+	GatewayRefs []GatewayReference `json:"gatewayRefs,omitempty"`
+}
+
+// GatewayReference is the type used to represent a reference to a Gateway.
+type GatewayReference struct {
+	// Name is the name of the Gateway
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway
+	Namespace string `json:"namespace"`
+}
+
+// HTTPRouteRule is the type used to represent a rule of an HTTPRoute.
+type HTTPRouteRule struct {
+	// Matches are the conditions of the requests matched by the rule, any of which must be met. All requests are
+	// matched when not set.
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+
+	// ForwardTo are the backends the matched requests are forwarded to
+	ForwardTo []HTTPRouteForwardTo `json:"forwardTo,omitempty"`
+}
+
+// HTTPRouteMatch is the type used to represent the conditions of the requests matched by a rule, all of which must
+// be met.
+type HTTPRouteMatch struct {
+	// Path matches the request path, matching the path prefix / when not set
+	Path *HTTPPathMatch `json:"path,omitempty"`
+
+	// Headers matches the request headers
+	Headers *HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// HTTPPathMatch is the type used to represent the match of a request path.
+type HTTPPathMatch struct {
+	// Type is one of PathMatchExact, PathMatchPrefix, PathMatchRegularExpression or PathMatchImplementationSpecific,
+	// PathMatchPrefix when not set
+	Type *string `json:"type,omitempty"`
+
+	// Value is the path matched, / when not set
+	Value *string `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch is the type used to represent the match of request headers.
+type HTTPHeaderMatch struct {
+	// Type is one of HeaderMatchExact or HeaderMatchRegularExpression, HeaderMatchExact when not set
+	Type *string `json:"type,omitempty"`
+
+	// Values maps the names of the headers to the values matched
+	Values map[string]string `json:"values"`
+}
+
+// HTTPRouteForwardTo is the type used to represent a backend the requests matched by a rule are forwarded to.
+type HTTPRouteForwardTo struct {
+	// ServiceName is the name of the service, in the namespace of the route
+	ServiceName *string `json:"serviceName,omitempty"`
+
+	// Port is the port of the service
+	Port *int32 `json:"port,omitempty"`
+
+	// Weight is the proportion of the matched requests forwarded to the backend
+	Weight *int32 `json:"weight,omitempty"`
+}
@@ -0,0 +1,17 @@
+// Package v1alpha1 contains the subset of the v1alpha1 API version of the Kubernetes Gateway API group
+// (gateway.networking.k8s.io) used by OSM to program the backends of HTTP routes. The types mirror the fields of the
+// upstream API types that OSM reads, and are populated from the unstructured resources watched by the ingress client.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the API types in this package
+var SchemeGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1alpha1"}
+
+// GatewayResource is the group version resource of the Gateway API type
+var GatewayResource = SchemeGroupVersion.WithResource("gateways")
+
+// HTTPRouteResource is the group version resource of the HTTPRoute API type
+var HTTPRouteResource = SchemeGroupVersion.WithResource("httproutes")
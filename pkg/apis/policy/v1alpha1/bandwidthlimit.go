@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BandwidthLimitKind is the kind of the BandwidthLimit API type
+	BandwidthLimitKind = "BandwidthLimit"
+)
+
+// BandwidthLimit is the type used to represent the limits of the rate at which the HTTP responses sent and received by
+// a service are transferred. It is created in the namespace of the service.
+type BandwidthLimit struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the BandwidthLimit
+	Spec BandwidthLimitSpec `json:"spec"`
+}
+
+// BandwidthLimitSpec is the type used to represent the specification of a BandwidthLimit. The limits are in KiB/s, and
+// the limits that are not set do not limit the rate.
+type BandwidthLimitSpec struct {
+	// Host is the fully qualified domain name of the service, such as bookstore.bookstore.svc.cluster.local
+	Host string `json:"host"`
+
+	// IngressKiBps limits the rate of each HTTP response received by the service from its upstream services
+	IngressKiBps *uint64 `json:"ingressKiBps,omitempty"`
+
+	// EgressKiBps limits the rate of each HTTP response sent by the service to its clients
+	EgressKiBps *uint64 `json:"egressKiBps,omitempty"`
+
+	// Routes are the limits of the HTTP responses sent by the service to the requests matching routes, overriding
+	// EgressKiBps
+	Routes []RouteBandwidthLimitSpec `json:"routes,omitempty"`
+}
+
+// RouteBandwidthLimitSpec is the type used to represent the limit of the HTTP responses sent by a service to the requests
+// matching matches of an HTTPRouteGroup.
+type RouteBandwidthLimitSpec struct {
+	// HTTPRouteGroup is the name of the HTTPRouteGroup, in the namespace of the BandwidthLimit, defining the matches
+	HTTPRouteGroup string `json:"httpRouteGroup"`
+
+	// Matches are the names of the matches of the HTTPRouteGroup the limit applies to, all of them if empty
+	Matches []string `json:"matches,omitempty"`
+
+	// EgressKiBps limits the rate of each HTTP response sent by the service to the requests matching the matches
+	EgressKiBps uint64 `json:"egressKiBps"`
+}
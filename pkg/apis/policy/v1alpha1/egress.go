@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EgressKind is the kind of the Egress API type
+const EgressKind = "Egress"
+
+// Egress is the type used to represent the external destinations the pods of the given service accounts are allowed
+// to reach.
+type Egress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the Egress
+	Spec EgressSpec `json:"spec"`
+}
+
+// EgressSpec is the type used to represent the specification of an Egress.
+type EgressSpec struct {
+	// Sources are the service accounts whose pods are allowed to reach the external destinations
+	Sources []EgressSourceSpec `json:"sources"`
+
+	// Hosts are the host names of the external destinations, matched by the host of the HTTP requests sent to the
+	// ports with the ProtocolHTTP protocol and by the server name of the TLS connections to the ports with the
+	// ProtocolHTTPS protocol. A wildcard domain, such as *.example.com, matches any subdomain of the domain.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// IPAddresses are the CIDR ranges of the addresses of the external destinations, matched by the destination
+	// address of the connections to the ports of any protocol
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// Ports are the ports of the external destinations, and the protocol of the traffic sent to them, one of
+	// ProtocolHTTP, ProtocolHTTPS or ProtocolTCP
+	Ports []PortSpec `json:"ports"`
+
+	// TLS originates TLS connections to the Hosts for the plaintext HTTP requests sent to them on the ports with the
+	// ProtocolHTTP protocol, so that the requests leave the mesh encrypted while the applications remain unaware of TLS
+	TLS *EgressTLSSpec `json:"tls,omitempty"`
+
+	// HTTPProxy makes the sidecar proxies of the sources act as an explicit HTTP proxy for the Hosts, such as configured
+	// by the HTTP_PROXY and HTTPS_PROXY environment variables of legacy applications. The sidecar proxies forward the
+	// plaintext HTTP requests for the Hosts on the ports with the ProtocolHTTP protocol, and tunnel the HTTP CONNECT
+	// requests to the Hosts on the ports with the ProtocolHTTPS protocol, that they receive on their localhost HTTP
+	// proxy port. Wildcard domains cannot be reached through the HTTP proxy.
+	HTTPProxy bool `json:"httpProxy,omitempty"`
+}
+
+// EgressTLSSpec is the type used to represent the TLS connections originated by the sidecar proxies of the sources to
+// the hosts of an Egress.
+type EgressTLSSpec struct {
+	// Port is the port of the hosts the TLS connections are originated to, 443 when not set
+	Port uint32 `json:"port,omitempty"`
+
+	// CABundleSecret is the secret, in the namespace of the Egress unless specified, storing the CA bundle validating
+	// the certificates of the hosts under the 'ca.crt' key. The certificates are validated with the system CA bundle of
+	// the sidecar proxies when not set.
+	CABundleSecret *SecretReference `json:"caBundleSecret,omitempty"`
+
+	// MeshClientCert presents the certificate issued by the mesh to the service account of the source to the hosts
+	MeshClientCert bool `json:"meshClientCert,omitempty"`
+
+	// ClientCertSecret is the secret, in the namespace of the Egress unless specified, storing the client certificate
+	// presented to the hosts under the 'tls.crt' and 'tls.key' keys. It takes precedence over MeshClientCert.
+	ClientCertSecret *SecretReference `json:"clientCertSecret,omitempty"`
+}
+
+// EgressSourceSpec is the type used to represent a source allowed to reach the external destinations of an Egress.
+type EgressSourceSpec struct {
+	// Kind is the kind of the source, KindServiceAccount
+	Kind string `json:"kind"`
+
+	// Name is the name of the service account of the source
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the service account of the source
+	Namespace string `json:"namespace"`
+}
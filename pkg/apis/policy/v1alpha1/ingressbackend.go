@@ -0,0 +1,107 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IngressBackendKind is the kind of the IngressBackend API type
+	IngressBackendKind = "IngressBackend"
+
+	// KindServiceAccount is the kind of an ingress source authenticated with the certificate issued by the mesh to
+	// the pods of the given service account
+	KindServiceAccount = "ServiceAccount"
+
+	// KindAuthenticatedPrincipal is the kind of an ingress source authenticated with a client certificate whose
+	// subject alternative name is the given principal
+	KindAuthenticatedPrincipal = "AuthenticatedPrincipal"
+
+	// KindIPRange is the kind of an ingress source identified by its address being in the given CIDR range
+	KindIPRange = "IPRange"
+
+	// ProtocolHTTP is the protocol of a backend port receiving plaintext HTTP traffic from ingress
+	ProtocolHTTP = "http"
+
+	// ProtocolHTTPS is the protocol of a backend port receiving HTTPS traffic from ingress
+	ProtocolHTTPS = "https"
+
+	// ProtocolTCP is the protocol of a backend port receiving plaintext TCP traffic from ingress, proxied to the backend
+	// without HTTP routing
+	ProtocolTCP = "tcp"
+
+	// ProtocolTLSPassthrough is the protocol of a backend port receiving TLS traffic from ingress that is not terminated
+	// by the backend's sidecar, and is proxied to the backend as is
+	ProtocolTLSPassthrough = "tls-passthrough"
+)
+
+// IngressBackend is the type used to represent the ingress sources allowed to reach the backends of ingress resources.
+type IngressBackend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the IngressBackend
+	Spec IngressBackendSpec `json:"spec"`
+}
+
+// IngressBackendSpec is the type used to represent the specification of an IngressBackend.
+type IngressBackendSpec struct {
+	// Backends are the services, in the namespace of the IngressBackend, receiving traffic from ingress
+	Backends []BackendSpec `json:"backends"`
+
+	// Sources are the ingress sources allowed to reach the backends
+	Sources []IngressSourceSpec `json:"sources"`
+
+	// TLS is the TLS configuration of the traffic received from ingress on the backend ports with the ProtocolHTTPS protocol
+	TLS *IngressBackendTLSSpec `json:"tls,omitempty"`
+}
+
+// IngressBackendTLSSpec is the type used to represent the TLS configuration of an IngressBackend.
+type IngressBackendTLSSpec struct {
+	// RequireClientCert requires the ingress sources to present a client certificate
+	RequireClientCert bool `json:"requireClientCert,omitempty"`
+
+	// CABundleSecret is the secret, in the namespace of the IngressBackend unless specified, storing the CA bundle
+	// validating the client certificates under the 'ca.crt' key. The client certificates are validated with the
+	// mesh's root certificate when not set.
+	CABundleSecret *SecretReference `json:"caBundleSecret,omitempty"`
+}
+
+// SecretReference is the type used to represent a reference to a Kubernetes secret.
+type SecretReference struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the secret
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// BackendSpec is the type used to represent a service receiving traffic from ingress.
+type BackendSpec struct {
+	// Name is the name of the service
+	Name string `json:"name"`
+
+	// Port is the target port of the service receiving traffic from ingress
+	Port PortSpec `json:"port"`
+}
+
+// PortSpec is the type used to represent the port of a backend, or of an external destination.
+type PortSpec struct {
+	// Number is the port number
+	Number uint32 `json:"number"`
+
+	// Protocol is the protocol of the traffic on the port, one of ProtocolHTTP, ProtocolHTTPS, ProtocolTCP or
+	// ProtocolTLSPassthrough
+	Protocol string `json:"protocol"`
+}
+
+// IngressSourceSpec is the type used to represent an ingress source allowed to reach the backends.
+type IngressSourceSpec struct {
+	// Kind is the kind of the source, one of KindServiceAccount, KindAuthenticatedPrincipal or KindIPRange
+	Kind string `json:"kind"`
+
+	// Name is the name of the service account, the principal or the CIDR range of the source
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the service account of a source of kind KindServiceAccount
+	Namespace string `json:"namespace,omitempty"`
+}
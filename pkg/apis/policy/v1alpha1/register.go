@@ -0,0 +1,26 @@
+// Package v1alpha1 contains the v1alpha1 API version of the policy.openservicemesh.io API group, which configures
+// the trust between the mesh and the components outside of it, and the retries and limits of the traffic within the
+// mesh.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the API types in this package
+var SchemeGroupVersion = schema.GroupVersion{Group: "policy.openservicemesh.io", Version: "v1alpha1"}
+
+// IngressBackendResource is the group version resource of the IngressBackend API type
+var IngressBackendResource = SchemeGroupVersion.WithResource("ingressbackends")
+
+// EgressResource is the group version resource of the Egress API type
+var EgressResource = SchemeGroupVersion.WithResource("egresses")
+
+// RetryResource is the group version resource of the Retry API type
+var RetryResource = SchemeGroupVersion.WithResource("retries")
+
+// UpstreamTrafficSettingResource is the group version resource of the UpstreamTrafficSetting API type
+var UpstreamTrafficSettingResource = SchemeGroupVersion.WithResource("upstreamtrafficsettings")
+
+// BandwidthLimitResource is the group version resource of the BandwidthLimit API type
+var BandwidthLimitResource = SchemeGroupVersion.WithResource("bandwidthlimits")
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RetryKind is the kind of the Retry API type
+	RetryKind = "Retry"
+
+	// KindService is the kind of a destination of a Retry identified by the service the requests are sent to
+	KindService = "Service"
+)
+
+// Retry is the type used to represent the retries of the requests sent by the pods of a service account to the
+// given destination services.
+type Retry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the Retry
+	Spec RetrySpec `json:"spec"`
+}
+
+// RetrySpec is the type used to represent the specification of a Retry.
+type RetrySpec struct {
+	// Source is the service account whose pods retry the requests
+	Source RetrySourceSpec `json:"source"`
+
+	// Destinations are the services the retried requests are sent to
+	Destinations []RetryDestinationSpec `json:"destinations"`
+
+	// RetryPolicy is the retry policy of the requests sent by the source to the destinations
+	RetryPolicy RetryPolicySpec `json:"retryPolicy"`
+}
+
+// RetrySourceSpec is the type used to represent the source retrying the requests of a Retry.
+type RetrySourceSpec struct {
+	// Kind is the kind of the source, KindServiceAccount
+	Kind string `json:"kind"`
+
+	// Name is the name of the service account of the source
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the service account of the source
+	Namespace string `json:"namespace"`
+}
+
+// RetryDestinationSpec is the type used to represent a destination of the retried requests of a Retry.
+type RetryDestinationSpec struct {
+	// Kind is the kind of the destination, KindService
+	Kind string `json:"kind"`
+
+	// Name is the name of the service
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the service
+	Namespace string `json:"namespace"`
+}
+
+// RetryPolicySpec is the type used to represent the retry policy of a Retry.
+type RetryPolicySpec struct {
+	// RetryOn is the comma separated list of the Envoy retry conditions the requests are retried on, such as
+	// 5xx,reset,connect-failure
+	RetryOn string `json:"retryOn"`
+
+	// NumRetries is the max number of retries of a request, 1 when not set
+	NumRetries uint32 `json:"numRetries,omitempty"`
+
+	// PerTryTimeout is the timeout of each try of a request, including the first one. The tries time out with the
+	// request when not set.
+	PerTryTimeout *metav1.Duration `json:"perTryTimeout,omitempty"`
+
+	// RetryBackoffBaseInterval is the base interval of the exponential back-off between the retries, 25ms when not set
+	RetryBackoffBaseInterval *metav1.Duration `json:"retryBackoffBaseInterval,omitempty"`
+
+	// RetryBackoffMaxInterval is the max interval between the retries, 10 times the base interval when not set
+	RetryBackoffMaxInterval *metav1.Duration `json:"retryBackoffMaxInterval,omitempty"`
+}
@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// UpstreamTrafficSettingKind is the kind of the UpstreamTrafficSetting API type
+	UpstreamTrafficSettingKind = "UpstreamTrafficSetting"
+)
+
+// UpstreamTrafficSetting is the type used to represent the settings of the traffic sent by the clients of the mesh to
+// an upstream service. It is created in the namespace of the upstream service.
+type UpstreamTrafficSetting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the UpstreamTrafficSetting
+	Spec UpstreamTrafficSettingSpec `json:"spec"`
+}
+
+// UpstreamTrafficSettingSpec is the type used to represent the specification of an UpstreamTrafficSetting.
+type UpstreamTrafficSettingSpec struct {
+	// Host is the fully qualified domain name of the upstream service, such as bookstore.bookstore.svc.cluster.local
+	Host string `json:"host"`
+
+	// ConnectionSettings are the limits of the connections and requests of each client to the upstream service
+	ConnectionSettings *ConnectionSettingsSpec `json:"connectionSettings,omitempty"`
+}
+
+// ConnectionSettingsSpec is the type used to represent the connection settings of an UpstreamTrafficSetting.
+type ConnectionSettingsSpec struct {
+	// TCP are the limits of the connections to the upstream service
+	TCP *TCPConnectionSettings `json:"tcp,omitempty"`
+
+	// HTTP are the limits of the HTTP requests to the upstream service
+	HTTP *HTTPConnectionSettings `json:"http,omitempty"`
+}
+
+// TCPConnectionSettings is the type used to represent the limits of the connections to an upstream service.
+type TCPConnectionSettings struct {
+	// MaxConnections is the max number of connections of a client to the upstream service
+	MaxConnections *uint32 `json:"maxConnections,omitempty"`
+}
+
+// HTTPConnectionSettings is the type used to represent the limits of the HTTP requests to an upstream service.
+type HTTPConnectionSettings struct {
+	// MaxPendingRequests is the max number of requests of a client queued while waiting for a connection to the
+	// upstream service
+	MaxPendingRequests *uint32 `json:"maxPendingRequests,omitempty"`
+
+	// MaxRequests is the max number of concurrent requests of a client to the upstream service
+	MaxRequests *uint32 `json:"maxRequests,omitempty"`
+
+	// MaxRequestsPerConnection is the max number of requests sent by a client over a single connection to the upstream
+	// service
+	MaxRequestsPerConnection *uint32 `json:"maxRequestsPerConnection,omitempty"`
+
+	// MaxRetries is the max number of concurrent retries of a client to the upstream service
+	MaxRetries *uint32 `json:"maxRetries,omitempty"`
+}
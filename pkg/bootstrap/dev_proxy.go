@@ -0,0 +1,187 @@
+package bootstrap
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// certificatesOrganization is the organization of the certificates issued for dev proxies
+	certificatesOrganization = "Open Service Mesh"
+)
+
+// IssueDevProxyCertificate issues the xDS certificate of a dev proxy, signed by the mesh root certificate stored
+// in the given CA bundle secret. Only the Tresor certificate manager stores the root certificate's private key
+// in the CA bundle secret, so dev proxies are not supported with other certificate managers.
+func IssueDevProxyCertificate(kubeClient kubernetes.Interface, osmNamespace, caBundleSecretName string, config DevProxyConfig, validityPeriod time.Duration) (certificate.Certificater, error) {
+	certManager, err := NewDevProxyCertManager(kubeClient, osmNamespace, caBundleSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := catalog.NewDevProxyCertCommonName(config.ProxyID, config.ServiceAccount.Name, config.ServiceAccount.Namespace)
+	return certManager.IssueCertificate(cn, validityPeriod)
+}
+
+// NewDevProxyCertManager returns a certificate manager issuing certificates signed by the mesh root certificate
+// stored in the given CA bundle secret, to issue the xDS certificates of several dev proxies.
+func NewDevProxyCertManager(kubeClient kubernetes.Interface, osmNamespace, caBundleSecretName string) (certificate.Manager, error) {
+	rootCert, err := providers.GetCertFromKubernetes(osmNamespace, caBundleSecretName, kubeClient)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error loading the mesh root certificate from secret %s/%s", osmNamespace, caBundleSecretName)
+		return nil, err
+	}
+
+	// The certificate manager is only used to issue certificates, so it is never configured
+	// to rotate certificates nor to validate certificate requests.
+	certManager, err := tresor.NewCertManager(rootCert, certificatesOrganization, nil, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating a certificate manager from the mesh root certificate")
+		return nil, err
+	}
+	return certManager, nil
+}
+
+// NewDevProxyBootstrapConfig returns the Envoy bootstrap configuration of a dev proxy with the given xDS certificate.
+func NewDevProxyBootstrapConfig(cert certificate.Certificater, config DevProxyConfig) ([]byte, error) {
+	m := map[interface{}]interface{}{
+		// The node is set in the bootstrap config as the dev proxy is not started by the sidecar injector.
+		// It has the format of the Envoy service node of a sidecar, with the dev proxy's name in place of the pod name.
+		"node": map[string]interface{}{
+			"id":      GetDevProxyServiceNodeID(config),
+			"cluster": strings.Join([]string{config.ServiceAccount.Name, config.ServiceAccount.Namespace}, "."),
+		},
+
+		"admin": map[string]interface{}{
+			"access_log_path": "/dev/stdout",
+			"address": map[string]interface{}{
+				"socket_address": map[string]string{
+					"address":    constants.LocalhostIPAddress,
+					"port_value": strconv.Itoa(config.AdminPort),
+				},
+			},
+		},
+
+		"dynamic_resources": map[string]interface{}{
+			"ads_config": map[string]interface{}{
+				"api_type":              "GRPC",
+				"transport_api_version": "V3",
+				"grpc_services": []map[string]interface{}{
+					{
+						"envoy_grpc": map[string]interface{}{
+							"cluster_name": constants.OSMControllerName,
+						},
+					},
+				},
+				"set_node_on_first_message_only": true,
+			},
+			"cds_config": map[string]interface{}{
+				"ads":                  map[string]string{},
+				"resource_api_version": "V3",
+			},
+			"lds_config": map[string]interface{}{
+				"ads":                  map[string]string{},
+				"resource_api_version": "V3",
+			},
+		},
+
+		"static_resources": map[string]interface{}{
+			"clusters": []map[string]interface{}{
+				getXDSCluster(cert, config),
+			},
+		},
+	}
+
+	configYAML, err := yaml.Marshal(&m)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshaling Envoy config struct into YAML")
+		return nil, err
+	}
+	return configYAML, nil
+}
+
+// GetDevProxyServiceNodeID returns the Envoy service node ID of a dev proxy
+func GetDevProxyServiceNodeID(config DevProxyConfig) string {
+	proxyID := config.ProxyID.String()
+	items := []string{
+		proxyID,
+		config.ServiceAccount.Namespace,
+		"", // A dev proxy does not have a pod IP
+		config.ServiceAccount.Name,
+		proxyID,
+		config.Name,
+		constants.DevProxyWorkloadKind,
+		config.Name,
+	}
+
+	return strings.Join(items, constants.EnvoyServiceNodeSeparator)
+}
+
+func getXDSCluster(cert certificate.Certificater, config DevProxyConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                   constants.OSMControllerName,
+		"connect_timeout":        "0.25s",
+		"type":                   "LOGICAL_DNS",
+		"http2_protocol_options": map[string]string{},
+		"transport_socket": map[string]interface{}{
+			"name": "envoy.transport_sockets.tls",
+			"typed_config": map[string]interface{}{
+				"@type": "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.UpstreamTlsContext",
+				"common_tls_context": map[string]interface{}{
+					"alpn_protocols": []string{
+						"h2",
+					},
+					"validation_context": map[string]interface{}{
+						"trusted_ca": map[string]interface{}{
+							"inline_bytes": base64.StdEncoding.EncodeToString(cert.GetIssuingCA()),
+						},
+					},
+					"tls_params": map[string]interface{}{
+						"tls_minimum_protocol_version": "TLSv1_2",
+						"tls_maximum_protocol_version": "TLSv1_3",
+					},
+					"tls_certificates": []map[string]interface{}{
+						{
+							"certificate_chain": map[string]interface{}{
+								"inline_bytes": base64.StdEncoding.EncodeToString(cert.GetCertificateChain()),
+							},
+							"private_key": map[string]interface{}{
+								"inline_bytes": base64.StdEncoding.EncodeToString(cert.GetPrivateKey()),
+							},
+						},
+					},
+				},
+			},
+		},
+		"load_assignment": map[string]interface{}{
+			"cluster_name": constants.OSMControllerName,
+			"endpoints": []map[string]interface{}{
+				{
+					"lb_endpoints": []map[string]interface{}{
+						{
+							"endpoint": map[string]interface{}{
+								"address": map[string]interface{}{
+									"socket_address": map[string]interface{}{
+										"address":    config.XDSHost,
+										"port_value": config.XDSPort,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var devProxyConfig = DevProxyConfig{
+	ProxyID:        uuid.New(),
+	Name:           "laptop",
+	ServiceAccount: service.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"},
+	XDSHost:        "localhost",
+	XDSPort:        constants.OSMControllerPort,
+	AdminPort:      constants.EnvoyAdminPort,
+}
+
+func TestIssueDevProxyCertificate(t *testing.T) {
+	assert := tassert.New(t)
+
+	osmNamespace := "osm-system"
+	caBundleSecretName := "osm-ca-bundle"
+	kubeClient := fake.NewSimpleClientset()
+
+	// The root certificate has not been created
+	cert, err := IssueDevProxyCertificate(kubeClient, osmNamespace, caBundleSecretName, devProxyConfig, 1*time.Hour)
+	assert.NotNil(err)
+	assert.Nil(cert)
+
+	rootCert, err := tresor.NewCA("Fake Tresor CN", 1*time.Hour, "US", "CA", "Open Service Mesh")
+	assert.Nil(err)
+	_, err = kubeClient.CoreV1().Secrets(osmNamespace).Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      caBundleSecretName,
+			Namespace: osmNamespace,
+		},
+		Data: map[string][]byte{
+			constants.KubernetesOpaqueSecretCAKey:             rootCert.GetCertificateChain(),
+			constants.KubernetesOpaqueSecretRootPrivateKeyKey: rootCert.GetPrivateKey(),
+			constants.KubernetesOpaqueSecretCAExpiration:      []byte(rootCert.GetExpiration().Format(constants.TimeDateLayout)),
+		},
+	}, metav1.CreateOptions{})
+	assert.Nil(err)
+
+	cert, err = IssueDevProxyCertificate(kubeClient, osmNamespace, caBundleSecretName, devProxyConfig, 1*time.Hour)
+	assert.Nil(err)
+	assert.Equal(catalog.NewDevProxyCertCommonName(devProxyConfig.ProxyID, "bookbuyer", "bookbuyer"), cert.GetCommonName())
+	assert.Equal(rootCert.GetCertificateChain(), cert.GetIssuingCA())
+
+	// The control plane must recognize the proxy with the certificate as a dev proxy
+	assert.True(envoy.NewProxy(cert.GetCommonName(), cert.GetSerialNumber(), nil).IsDevProxy())
+}
+
+func TestNewDevProxyBootstrapConfig(t *testing.T) {
+	assert := tassert.New(t)
+
+	configYAML, err := NewDevProxyBootstrapConfig(tresor.NewFakeCertificate(), devProxyConfig)
+	assert.Nil(err)
+
+	var bootstrapConfig struct {
+		Node struct {
+			ID      string `yaml:"id"`
+			Cluster string `yaml:"cluster"`
+		} `yaml:"node"`
+		StaticResources struct {
+			Clusters []struct {
+				Name string `yaml:"name"`
+			} `yaml:"clusters"`
+		} `yaml:"static_resources"`
+	}
+	assert.Nil(yaml.Unmarshal(configYAML, &bootstrapConfig))
+	assert.Equal("bookbuyer.bookbuyer", bootstrapConfig.Node.Cluster)
+	assert.Len(bootstrapConfig.StaticResources.Clusters, 1)
+	assert.Equal(constants.OSMControllerName, bootstrapConfig.StaticResources.Clusters[0].Name)
+	assert.Contains(string(configYAML), "address: localhost")
+
+	// The node reports the identity and the name of the dev proxy
+	meta, err := envoy.ParseEnvoyServiceNodeID(bootstrapConfig.Node.ID)
+	assert.Nil(err)
+	assert.Equal(devProxyConfig.ServiceAccount, meta.ServiceAccount)
+	assert.Equal("laptop", meta.Name)
+	assert.Equal(constants.DevProxyWorkloadKind, meta.WorkloadKind)
+}
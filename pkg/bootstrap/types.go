@@ -0,0 +1,34 @@
+// Package bootstrap implements the Envoy bootstrap configuration for dev proxies. A dev proxy is an Envoy proxy
+// running outside Kubernetes, for example on a developer's machine or in Docker Compose, that joins a local
+// process to a remote mesh for development and debugging.
+package bootstrap
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var (
+	log = logger.New("bootstrap")
+)
+
+// DevProxyConfig is the configuration of a dev proxy
+type DevProxyConfig struct {
+	// ProxyID uniquely identifies the dev proxy, and is part of its xDS certificate's common name
+	ProxyID uuid.UUID
+
+	// Name identifies the dev proxy in the control plane's logs and debug endpoints
+	Name string
+
+	// ServiceAccount is the identity of the local process in the mesh
+	ServiceAccount service.K8sServiceAccount
+
+	// Host and port at which the dev proxy reaches the OSM controller's xDS server
+	XDSHost string
+	XDSPort int
+
+	// AdminPort is the local port of the Envoy admin interface
+	AdminPort int
+}
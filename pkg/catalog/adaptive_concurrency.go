@@ -0,0 +1,18 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetAdaptiveConcurrencyPolicy returns the adaptive concurrency limits shedding the load of the given service. The
+// mesh-wide policy configured in the OSM ConfigMap is overridden by the annotations of the namespace of the service,
+// then by the annotations of the service.
+func (mc *MeshCatalog) GetAdaptiveConcurrencyPolicy(svc service.MeshService) trafficpolicy.AdaptiveConcurrencyPolicy {
+	overrides := mc.getServiceOverrides(svc)
+	return trafficpolicy.AdaptiveConcurrencyPolicy{
+		Enabled:             overrides.Bool(constants.AdaptiveConcurrencyAnnotation, mc.configurator.IsAdaptiveConcurrencyEnabled()),
+		MaxConcurrencyLimit: overrides.Uint32(constants.AdaptiveConcurrencyMaxLimitAnnotation, mc.configurator.GetAdaptiveConcurrencyMaxLimit(), 1),
+	}
+}
@@ -0,0 +1,105 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetAdaptiveConcurrencyPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name                 string
+		namespaceAnnotations map[string]string
+		annotations          map[string]string
+		expectedPolicy       trafficpolicy.AdaptiveConcurrencyPolicy
+	}{
+		{
+			name:        "mesh-wide policy without annotations",
+			annotations: nil,
+			expectedPolicy: trafficpolicy.AdaptiveConcurrencyPolicy{
+				Enabled:             true,
+				MaxConcurrencyLimit: 1000,
+			},
+		},
+		{
+			name: "mesh-wide policy overridden by annotations",
+			annotations: map[string]string{
+				constants.AdaptiveConcurrencyAnnotation:         "false",
+				constants.AdaptiveConcurrencyMaxLimitAnnotation: "200",
+			},
+			expectedPolicy: trafficpolicy.AdaptiveConcurrencyPolicy{
+				Enabled:             false,
+				MaxConcurrencyLimit: 200,
+			},
+		},
+		{
+			name: "invalid annotations are ignored",
+			annotations: map[string]string{
+				constants.AdaptiveConcurrencyAnnotation:         "maybe",
+				constants.AdaptiveConcurrencyMaxLimitAnnotation: "0",
+			},
+			expectedPolicy: trafficpolicy.AdaptiveConcurrencyPolicy{
+				Enabled:             true,
+				MaxConcurrencyLimit: 1000,
+			},
+		},
+		{
+			name: "mesh-wide policy overridden by namespace annotations, overridden by service annotations",
+			namespaceAnnotations: map[string]string{
+				constants.AdaptiveConcurrencyAnnotation:         "false",
+				constants.AdaptiveConcurrencyMaxLimitAnnotation: "200",
+			},
+			annotations: map[string]string{
+				constants.AdaptiveConcurrencyAnnotation: "true",
+			},
+			expectedPolicy: trafficpolicy.AdaptiveConcurrencyPolicy{
+				Enabled:             true,
+				MaxConcurrencyLimit: 200,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{
+				configurator:   mockCfg,
+				kubeController: mockKubeController,
+			}
+
+			mockCfg.EXPECT().IsAdaptiveConcurrencyEnabled().Return(true).Times(1)
+			mockCfg.EXPECT().GetAdaptiveConcurrencyMaxLimit().Return(uint32(1000)).Times(1)
+			mockKubeController.EXPECT().GetNamespace(svc.Namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Namespace,
+					Annotations: tc.namespaceAnnotations,
+				},
+			}).Times(1)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetAdaptiveConcurrencyPolicy(svc))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetBandwidthLimitForService returns the limits of the rate of the HTTP response data received and sent by the given
+// service, as set by its BandwidthLimit resource. The route limits of the matches of an HTTPRouteGroup listed by
+// several routes of the BandwidthLimit are the ones of the first route.
+func (mc *MeshCatalog) GetBandwidthLimitForService(svc service.MeshService) trafficpolicy.BandwidthLimit {
+	bandwidthLimit := mc.policyController.GetBandwidthLimit(svc)
+	if bandwidthLimit == nil {
+		return trafficpolicy.BandwidthLimit{}
+	}
+
+	var limit trafficpolicy.BandwidthLimit
+	if bandwidthLimit.Spec.IngressKiBps != nil {
+		limit.IngressKiBps = *bandwidthLimit.Spec.IngressKiBps
+	}
+	if bandwidthLimit.Spec.EgressKiBps != nil {
+		limit.EgressKiBps = *bandwidthLimit.Spec.EgressKiBps
+	}
+
+	for _, route := range bandwidthLimit.Spec.Routes {
+		if route.EgressKiBps == 0 {
+			log.Error().Msgf("Ignoring route limit of HTTPRouteGroup %s without limit in BandwidthLimit %s/%s", route.HTTPRouteGroup, bandwidthLimit.Namespace, bandwidthLimit.Name)
+			continue
+		}
+
+		matches := route.Matches
+		if len(matches) == 0 {
+			matches = mc.getHTTPRouteGroupMatchNames(bandwidthLimit.Namespace, route.HTTPRouteGroup)
+		}
+
+		specName := mc.getTrafficSpecName(httpRouteGroupKind, bandwidthLimit.Namespace, route.HTTPRouteGroup)
+		for _, match := range matches {
+			if limit.RouteEgressKiBps == nil {
+				limit.RouteEgressKiBps = make(map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]uint64)
+			}
+			if limit.RouteEgressKiBps[specName] == nil {
+				limit.RouteEgressKiBps[specName] = make(map[trafficpolicy.TrafficSpecMatchName]uint64)
+			}
+			matchName := trafficpolicy.TrafficSpecMatchName(match)
+			if _, ok := limit.RouteEgressKiBps[specName][matchName]; !ok {
+				limit.RouteEgressKiBps[specName][matchName] = route.EgressKiBps
+			}
+		}
+	}
+
+	return limit
+}
+
+// getHTTPRouteGroupMatchNames returns the names of the matches of the given HTTPRouteGroup, or nil if it does not exist
+func (mc *MeshCatalog) getHTTPRouteGroupMatchNames(namespace, name string) []string {
+	for _, routeGroup := range mc.meshSpec.ListHTTPTrafficSpecs() {
+		if routeGroup.Namespace != namespace || routeGroup.Name != name {
+			continue
+		}
+
+		var matches []string
+		for _, match := range routeGroup.Spec.Matches {
+			matches = append(matches, match.Name)
+		}
+		return matches
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetBandwidthLimitForService(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: tests.HTTPRouteGroup.Namespace}
+	ingressKiBps := uint64(512)
+	egressKiBps := uint64(1024)
+	specName := trafficpolicy.TrafficSpecName("HTTPRouteGroup/" + tests.HTTPRouteGroup.Namespace + "/" + tests.RouteGroupName)
+
+	testCases := []struct {
+		name           string
+		bandwidthLimit *policyV1alpha1.BandwidthLimit
+		expectedLimit  trafficpolicy.BandwidthLimit
+	}{
+		{
+			name:           "service without BandwidthLimit",
+			bandwidthLimit: nil,
+			expectedLimit:  trafficpolicy.BandwidthLimit{},
+		},
+		{
+			name: "ingress and egress limits",
+			bandwidthLimit: &policyV1alpha1.BandwidthLimit{
+				Spec: policyV1alpha1.BandwidthLimitSpec{
+					Host:         svc.ServerName(),
+					IngressKiBps: &ingressKiBps,
+					EgressKiBps:  &egressKiBps,
+				},
+			},
+			expectedLimit: trafficpolicy.BandwidthLimit{IngressKiBps: 512, EgressKiBps: 1024},
+		},
+		{
+			name: "route limits",
+			bandwidthLimit: &policyV1alpha1.BandwidthLimit{
+				ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: svc.Namespace},
+				Spec: policyV1alpha1.BandwidthLimitSpec{
+					Host:        svc.ServerName(),
+					EgressKiBps: &egressKiBps,
+					Routes: []policyV1alpha1.RouteBandwidthLimitSpec{
+						{HTTPRouteGroup: tests.RouteGroupName, Matches: []string{tests.BuyBooksMatchName}, EgressKiBps: 128},
+						// The limit of all the matches of the HTTPRouteGroup does not override the limit of the first route
+						{HTTPRouteGroup: tests.RouteGroupName, EgressKiBps: 256},
+						// Routes of unknown HTTPRouteGroups and without limit are ignored
+						{HTTPRouteGroup: "unknown", EgressKiBps: 64},
+						{HTTPRouteGroup: tests.RouteGroupName, Matches: []string{tests.SellBooksMatchName}},
+					},
+				},
+			},
+			expectedLimit: trafficpolicy.BandwidthLimit{
+				EgressKiBps: 1024,
+				RouteEgressKiBps: map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]uint64{
+					specName: {
+						tests.BuyBooksMatchName:            128,
+						tests.SellBooksMatchName:           256,
+						tests.WildcardWithHeadersMatchName: 256,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mc := &MeshCatalog{policyController: mockPolicyMonitor, meshSpec: mockMeshSpec}
+
+			mockPolicyMonitor.EXPECT().GetBandwidthLimit(svc).Return(tc.bandwidthLimit).Times(1)
+			mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return([]*spec.HTTPRouteGroup{&tests.HTTPRouteGroup}).AnyTimes()
+
+			assert.Equal(tc.expectedLimit, mc.GetBandwidthLimitForService(svc))
+		})
+	}
+}
+
+func TestBuildInboundPoliciesWithRouteBandwidthLimit(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	mc := MeshCatalog{
+		kubeController:     mockKubeController,
+		meshSpec:           mockMeshSpec,
+		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+		policyController:   mockPolicyMonitor,
+		configurator:       mockConfigurator,
+	}
+
+	svc := service.MeshService{Name: "bookstore", Namespace: tests.HTTPRouteGroup.Namespace}
+	mockKubeController.EXPECT().GetService(svc).Return(tests.NewServiceFixture(svc.Name, svc.Namespace, map[string]string{})).AnyTimes()
+	mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return([]*spec.HTTPRouteGroup{&tests.HTTPRouteGroup}).AnyTimes()
+	mockEndpointProvider.EXPECT().GetID().Return("fake").AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(svc).Return(&policyV1alpha1.BandwidthLimit{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: svc.Namespace},
+		Spec: policyV1alpha1.BandwidthLimitSpec{
+			Host: svc.ServerName(),
+			Routes: []policyV1alpha1.RouteBandwidthLimitSpec{
+				{HTTPRouteGroup: tests.RouteGroupName, Matches: []string{tests.BuyBooksMatchName}, EgressKiBps: 128},
+			},
+		},
+	}).AnyTimes()
+
+	trafficTarget := tests.NewSMITrafficTarget("bookbuyer", svc.Namespace, "bookstore", svc.Namespace)
+	policies := mc.buildInboundPolicies(&trafficTarget, svc)
+	assert.Len(policies, 1)
+
+	// Only the responses to the requests matching the limited match of the HTTPRouteGroup are limited
+	limits := make(map[string]uint64)
+	for _, rule := range policies[0].Rules {
+		limits[rule.Route.HTTPRouteMatch.Path] = rule.Route.BandwidthLimitKiBps
+	}
+	assert.Equal(map[string]uint64{
+		tests.BookstoreBuyHTTPRoute.Path:  128,
+		tests.BookstoreSellHTTPRoute.Path: 0,
+	}, limits)
+}
@@ -0,0 +1,186 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// maxConfigGenerations is the number of the most recent configuration generations kept to report their rollout
+	maxConfigGenerations = 20
+
+	// maxConfigGenerationChanges is the number of changes described for a configuration generation
+	maxConfigGenerationChanges = 20
+)
+
+// GetLatestConfigGeneration returns the configuration generation last broadcast to the proxies, or nil if no
+// generation was broadcast yet
+func (mc *MeshCatalog) GetLatestConfigGeneration() *envoy.ConfigGeneration {
+	mc.configGenerationsLock.RLock()
+	defer mc.configGenerationsLock.RUnlock()
+
+	if len(mc.configGenerations) == 0 {
+		return nil
+	}
+	generation := mc.configGenerations[len(mc.configGenerations)-1]
+	return &generation
+}
+
+// ListConfigGenerations lists the most recent configuration generations broadcast to the proxies, the oldest first
+func (mc *MeshCatalog) ListConfigGenerations() []envoy.ConfigGeneration {
+	mc.configGenerationsLock.RLock()
+	defer mc.configGenerationsLock.RUnlock()
+
+	return append([]envoy.ConfigGeneration(nil), mc.configGenerations...)
+}
+
+// newConfigGeneration stamps a new configuration generation including the given changes, affecting the proxies of the
+// services in the given scope or any proxy when the scope is nil, and records it among the most recent generations
+func (mc *MeshCatalog) newConfigGeneration(changes []string, scope mapset.Set) envoy.ConfigGeneration {
+	mc.configGenerationsLock.Lock()
+	defer mc.configGenerationsLock.Unlock()
+
+	generation := envoy.ConfigGeneration{
+		ID:        1,
+		CreatedAt: time.Now(),
+		Changes:   changes,
+	}
+	if scope != nil {
+		generation.Services = []service.MeshService{}
+		for svcInterface := range scope.Iter() {
+			generation.Services = append(generation.Services, svcInterface.(service.MeshService))
+		}
+		sort.Slice(generation.Services, func(i, j int) bool {
+			return generation.Services[i].String() < generation.Services[j].String()
+		})
+	}
+	if len(mc.configGenerations) > 0 {
+		generation.ID = mc.configGenerations[len(mc.configGenerations)-1].ID + 1
+	}
+
+	mc.configGenerations = append(mc.configGenerations, generation)
+	if len(mc.configGenerations) > maxConfigGenerations {
+		mc.configGenerations = mc.configGenerations[len(mc.configGenerations)-maxConfigGenerations:]
+	}
+	metricsstore.DefaultMetricsStore.ConfigGeneration.Set(float64(generation.ID))
+	return generation
+}
+
+// addConfigGenerationChange appends the description of the change announced by the given message to the given changes,
+// unless it is already described or the maximum number of changes is reached
+func addConfigGenerationChange(changes []string, psubMessage events.PubSubMessage) []string {
+	if len(changes) >= maxConfigGenerationChanges {
+		return changes
+	}
+
+	change := psubMessage.AnnouncementType.String()
+	obj := psubMessage.NewObj
+	if obj == nil {
+		obj = psubMessage.OldObj
+	}
+	if meta, ok := obj.(metav1.Object); ok {
+		name := meta.GetName()
+		if meta.GetNamespace() != "" {
+			name = fmt.Sprintf("%s/%s", meta.GetNamespace(), meta.GetName())
+		}
+		change = fmt.Sprintf("%s %s", change, name)
+	}
+
+	for _, existing := range changes {
+		if existing == change {
+			return changes
+		}
+	}
+	return append(changes, change)
+}
+
+// addConfigGenerationScope adds the services whose proxies are affected by the change announced by the given message to
+// the given scope. It returns nil, so that the configuration generation is sent to all the proxies, when the scope is
+// nil or the change may affect any proxy. Only the changes of ingress resources, which only affect the proxies of their
+// backend services, are scoped.
+func addConfigGenerationScope(scope mapset.Set, psubMessage events.PubSubMessage) mapset.Set {
+	if scope == nil {
+		return nil
+	}
+	switch psubMessage.AnnouncementType {
+	case a.IngressAdded, a.IngressDeleted, a.IngressUpdated:
+	default:
+		return nil
+	}
+
+	for _, obj := range []interface{}{psubMessage.OldObj, psubMessage.NewObj} {
+		if obj == nil {
+			continue
+		}
+		services, ok := getIngressBackendServices(obj)
+		if !ok {
+			return nil
+		}
+		for _, svc := range services {
+			scope.Add(svc)
+		}
+	}
+	return scope
+}
+
+// getIngressBackendServices returns the services the given ingress resource routes to, and false if the given object is
+// not an ingress resource
+func getIngressBackendServices(obj interface{}) ([]service.MeshService, bool) {
+	var serviceNames []string
+	var namespace string
+	switch ingress := obj.(type) {
+	case *networkingV1beta1.Ingress:
+		namespace = ingress.Namespace
+		if ingress.Spec.Backend != nil {
+			serviceNames = append(serviceNames, ingress.Spec.Backend.ServiceName)
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, ingressPath := range rule.HTTP.Paths {
+				serviceNames = append(serviceNames, ingressPath.Backend.ServiceName)
+			}
+		}
+
+	case *networkingV1.Ingress:
+		namespace = ingress.Namespace
+		if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+			serviceNames = append(serviceNames, backend.Service.Name)
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, ingressPath := range rule.HTTP.Paths {
+				// A backend may reference a resource other than a service, such as a storage bucket
+				if ingressPath.Backend.Service != nil {
+					serviceNames = append(serviceNames, ingressPath.Backend.Service.Name)
+				}
+			}
+		}
+
+	default:
+		return nil, false
+	}
+
+	var services []service.MeshService
+	for _, name := range serviceNames {
+		if name != "" {
+			services = append(services, service.MeshService{Name: name, Namespace: namespace})
+		}
+	}
+	return services, true
+}
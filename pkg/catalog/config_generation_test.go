@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestConfigGenerations(t *testing.T) {
+	assert := tassert.New(t)
+	mc := &MeshCatalog{}
+
+	assert.Nil(mc.GetLatestConfigGeneration())
+	assert.Empty(mc.ListConfigGenerations())
+
+	first := mc.newConfigGeneration([]string{"service-updated bookstore/bookstore"}, nil)
+	assert.Equal(uint64(1), first.ID)
+	assert.Equal([]string{"service-updated bookstore/bookstore"}, first.Changes)
+	assert.Nil(first.Services)
+	assert.Equal(&first, mc.GetLatestConfigGeneration())
+
+	// The services of a scoped generation are sorted
+	scoped := mc.newConfigGeneration([]string{"ingress-updated bookstore/bookstore"}, mapset.NewSet(
+		service.MeshService{Name: "bookstore-v2", Namespace: "bookstore"},
+		service.MeshService{Name: "bookstore-v1", Namespace: "bookstore"},
+	))
+	assert.Equal([]service.MeshService{
+		{Name: "bookstore-v1", Namespace: "bookstore"},
+		{Name: "bookstore-v2", Namespace: "bookstore"},
+	}, scoped.Services)
+
+	// Only the most recent generations are kept
+	for i := 0; i < maxConfigGenerations; i++ {
+		mc.newConfigGeneration(nil, nil)
+	}
+	generations := mc.ListConfigGenerations()
+	assert.Len(generations, maxConfigGenerations)
+	assert.Equal(uint64(3), generations[0].ID)
+	assert.Equal(uint64(maxConfigGenerations+2), mc.GetLatestConfigGeneration().ID)
+}
+
+func TestAddConfigGenerationChange(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore-ns"}}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bookstore-ns"}}
+
+	var changes []string
+	changes = addConfigGenerationChange(changes, events.PubSubMessage{AnnouncementType: a.ServiceUpdated, OldObj: svc, NewObj: svc})
+	changes = addConfigGenerationChange(changes, events.PubSubMessage{AnnouncementType: a.NamespaceDeleted, OldObj: ns})
+	changes = addConfigGenerationChange(changes, events.PubSubMessage{AnnouncementType: a.ScheduleProxyBroadcast})
+	// Changes already described are not repeated
+	changes = addConfigGenerationChange(changes, events.PubSubMessage{AnnouncementType: a.ServiceUpdated, OldObj: svc, NewObj: svc})
+
+	assert.Equal([]string{
+		"service-updated bookstore-ns/bookstore",
+		"namespace-deleted bookstore-ns",
+		"schedule-proxy-broadcast",
+	}, changes)
+
+	// The number of changes described is bounded
+	for i := 0; i < maxConfigGenerationChanges; i++ {
+		changes = addConfigGenerationChange(changes, events.PubSubMessage{
+			AnnouncementType: a.ServiceAdded,
+			NewObj:           &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: string(rune('a' + i)), Namespace: "bookstore-ns"}},
+		})
+	}
+	assert.Len(changes, maxConfigGenerationChanges)
+}
+
+func TestAddConfigGenerationScope(t *testing.T) {
+	ingressV1beta1 := &networkingV1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "bookstore"},
+		Spec: networkingV1beta1.IngressSpec{
+			Backend: &networkingV1beta1.IngressBackend{ServiceName: "bookstore-v1"},
+		},
+	}
+	ingressV1 := &networkingV1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "bookstore"},
+		Spec: networkingV1.IngressSpec{
+			Rules: []networkingV1.IngressRule{
+				{
+					IngressRuleValue: networkingV1.IngressRuleValue{
+						HTTP: &networkingV1.HTTPIngressRuleValue{
+							Paths: []networkingV1.HTTPIngressPath{
+								{Backend: networkingV1.IngressBackend{Service: &networkingV1.IngressServiceBackend{Name: "bookstore-v2"}}},
+								{Backend: networkingV1.IngressBackend{Resource: &corev1.TypedLocalObjectReference{Name: "bucket"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		scope         mapset.Set
+		messages      []events.PubSubMessage
+		expectedScope mapset.Set
+	}{
+		{
+			name:  "ingress changes are scoped to the backend services of the old and new ingress resources",
+			scope: mapset.NewSet(),
+			messages: []events.PubSubMessage{
+				{AnnouncementType: a.IngressUpdated, OldObj: ingressV1beta1, NewObj: ingressV1},
+			},
+			expectedScope: mapset.NewSet(
+				service.MeshService{Name: "bookstore-v1", Namespace: "bookstore"},
+				service.MeshService{Name: "bookstore-v2", Namespace: "bookstore"},
+			),
+		},
+		{
+			name:  "ingress deletions are scoped to the backend services of the deleted ingress resource",
+			scope: mapset.NewSet(),
+			messages: []events.PubSubMessage{
+				{AnnouncementType: a.IngressDeleted, OldObj: ingressV1beta1},
+			},
+			expectedScope: mapset.NewSet(service.MeshService{Name: "bookstore-v1", Namespace: "bookstore"}),
+		},
+		{
+			name:  "other changes are not scoped",
+			scope: mapset.NewSet(),
+			messages: []events.PubSubMessage{
+				{AnnouncementType: a.IngressAdded, NewObj: ingressV1},
+				{AnnouncementType: a.ServiceUpdated, NewObj: &corev1.Service{}},
+			},
+			expectedScope: nil,
+		},
+		{
+			name:  "ingress changes are not scoped once the scope is lost",
+			scope: nil,
+			messages: []events.PubSubMessage{
+				{AnnouncementType: a.IngressAdded, NewObj: ingressV1},
+			},
+			expectedScope: nil,
+		},
+		{
+			name:  "ingress changes of unknown objects are not scoped",
+			scope: mapset.NewSet(),
+			messages: []events.PubSubMessage{
+				{AnnouncementType: a.IngressDeleted, OldObj: "unknown"},
+			},
+			expectedScope: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			scope := tc.scope
+			for _, message := range tc.messages {
+				scope = addConfigGenerationScope(scope, message)
+			}
+
+			if tc.expectedScope == nil {
+				assert.Nil(scope)
+				return
+			}
+			assert.True(tc.expectedScope.Equal(scope))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const (
+	// defaultDarkLaunchPercentage is the percentage of the requests copied to a shadow service when the dark launch
+	// percentage annotation is not set
+	defaultDarkLaunchPercentage = 100
+
+	// shadowHostSuffix is the suffix Envoy appends to the host of the requests it copies to a shadow service
+	shadowHostSuffix = "-shadow"
+)
+
+// listDarkLaunches returns the dark launches set by the dark launch annotations of the services in the mesh, keyed by
+// the name of the outbound traffic policy of the primary service for a proxy in the given namespace
+func (mc *MeshCatalog) listDarkLaunches(sourceNamespace string) map[string]trafficpolicy.DarkLaunchPolicy {
+	darkLaunches := make(map[string]trafficpolicy.DarkLaunchPolicy)
+	for _, k8sSvc := range mc.kubeController.ListServices() {
+		darkLaunch, ok := getDarkLaunchPolicy(k8sSvc)
+		if !ok {
+			continue
+		}
+		svc := utils.K8sSvcToMeshSvc(k8sSvc)
+		darkLaunches[buildPolicyName(svc, svc.Namespace == sourceNamespace)] = darkLaunch
+	}
+	return darkLaunches
+}
+
+// addDarkLaunchShadowHostnames adds the hostnames of the dark launched services, suffixed as the host of the requests
+// copied to their shadow services, to the inbound traffic policies of the given upstream services that are shadow
+// services, so that the copied requests are routed by the proxies of the shadow services
+func (mc *MeshCatalog) addDarkLaunchShadowHostnames(inboundPolicies []*trafficpolicy.InboundTrafficPolicy, upstreamServices []service.MeshService) {
+	for _, k8sSvc := range mc.kubeController.ListServices() {
+		darkLaunch, ok := getDarkLaunchPolicy(k8sSvc)
+		if !ok {
+			continue
+		}
+
+		for _, upstreamSvc := range upstreamServices {
+			if service.ClusterName(upstreamSvc.String()) != darkLaunch.ShadowCluster {
+				continue
+			}
+			policyName := buildPolicyName(upstreamSvc, false)
+			for _, policy := range inboundPolicies {
+				if policy.Name != policyName {
+					continue
+				}
+				for _, hostname := range kubernetes.GetHostnamesForService(k8sSvc, true) {
+					policy.Hostnames = append(policy.Hostnames, hostname+shadowHostSuffix)
+				}
+			}
+		}
+	}
+}
+
+// getDarkLaunchPolicy returns the dark launch set by the annotations of the given service. The second return value is
+// false if the service is not dark launched, or if its annotations are invalid.
+func getDarkLaunchPolicy(k8sSvc *corev1.Service) (trafficpolicy.DarkLaunchPolicy, bool) {
+	shadowName, ok := k8sSvc.Annotations[constants.DarkLaunchServiceAnnotation]
+	if !ok {
+		return trafficpolicy.DarkLaunchPolicy{}, false
+	}
+
+	shadowName = strings.TrimSpace(shadowName)
+	if len(validation.IsDNS1035Label(shadowName)) != 0 || shadowName == k8sSvc.Name {
+		log.Error().Msgf("Invalid annotation value for key %q on service %s/%s: %s",
+			constants.DarkLaunchServiceAnnotation, k8sSvc.Namespace, k8sSvc.Name, shadowName)
+		return trafficpolicy.DarkLaunchPolicy{}, false
+	}
+
+	percentage := uint64(defaultDarkLaunchPercentage)
+	if percentageStr, ok := k8sSvc.Annotations[constants.DarkLaunchPercentageAnnotation]; ok {
+		var err error
+		percentage, err = strconv.ParseUint(strings.TrimSpace(percentageStr), 10, 32)
+		if err != nil || percentage == 0 || percentage > 100 {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on service %s/%s: %s",
+				constants.DarkLaunchPercentageAnnotation, k8sSvc.Namespace, k8sSvc.Name, percentageStr)
+			return trafficpolicy.DarkLaunchPolicy{}, false
+		}
+	}
+
+	shadow := service.MeshService{
+		Name:      shadowName,
+		Namespace: k8sSvc.Namespace,
+	}
+	return trafficpolicy.DarkLaunchPolicy{
+		ShadowCluster: service.ClusterName(shadow.String()),
+		Percentage:    uint32(percentage),
+	}, true
+}
@@ -0,0 +1,153 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func newDarkLaunchedService(name string, namespace string, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestGetDarkLaunchPolicy(t *testing.T) {
+	testCases := []struct {
+		name               string
+		annotations        map[string]string
+		expectedDarkLaunch trafficpolicy.DarkLaunchPolicy
+		expectedOk         bool
+	}{
+		{
+			name:        "service without annotation",
+			annotations: nil,
+			expectedOk:  false,
+		},
+		{
+			name:        "shadow service with the default percentage",
+			annotations: map[string]string{constants.DarkLaunchServiceAnnotation: "bookstore-v2"},
+			expectedDarkLaunch: trafficpolicy.DarkLaunchPolicy{
+				ShadowCluster: "bookstore-ns/bookstore-v2",
+				Percentage:    100,
+			},
+			expectedOk: true,
+		},
+		{
+			name: "shadow service with a percentage",
+			annotations: map[string]string{
+				constants.DarkLaunchServiceAnnotation:    "bookstore-v2",
+				constants.DarkLaunchPercentageAnnotation: "25",
+			},
+			expectedDarkLaunch: trafficpolicy.DarkLaunchPolicy{
+				ShadowCluster: "bookstore-ns/bookstore-v2",
+				Percentage:    25,
+			},
+			expectedOk: true,
+		},
+		{
+			name:        "invalid shadow service name",
+			annotations: map[string]string{constants.DarkLaunchServiceAnnotation: "bookstore-ns/bookstore-v2"},
+			expectedOk:  false,
+		},
+		{
+			name:        "service shadowing itself",
+			annotations: map[string]string{constants.DarkLaunchServiceAnnotation: "bookstore"},
+			expectedOk:  false,
+		},
+		{
+			name: "percentage above 100",
+			annotations: map[string]string{
+				constants.DarkLaunchServiceAnnotation:    "bookstore-v2",
+				constants.DarkLaunchPercentageAnnotation: "150",
+			},
+			expectedOk: false,
+		},
+		{
+			name: "zero percentage",
+			annotations: map[string]string{
+				constants.DarkLaunchServiceAnnotation:    "bookstore-v2",
+				constants.DarkLaunchPercentageAnnotation: "0",
+			},
+			expectedOk: false,
+		},
+		{
+			name: "invalid percentage",
+			annotations: map[string]string{
+				constants.DarkLaunchServiceAnnotation:    "bookstore-v2",
+				constants.DarkLaunchPercentageAnnotation: "half",
+			},
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			darkLaunch, ok := getDarkLaunchPolicy(newDarkLaunchedService("bookstore", "bookstore-ns", tc.annotations))
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedDarkLaunch, darkLaunch)
+		})
+	}
+}
+
+func TestListDarkLaunches(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{kubeController: mockKubeController}
+
+	mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{
+		newDarkLaunchedService("bookstore", "bookstore-ns", map[string]string{constants.DarkLaunchServiceAnnotation: "bookstore-v2"}),
+		newDarkLaunchedService("bookstore-v2", "bookstore-ns", nil),
+		newDarkLaunchedService("bookbuyer", "bookbuyer-ns", map[string]string{constants.DarkLaunchServiceAnnotation: "bookbuyer-v2"}),
+	}).Times(1)
+
+	expected := map[string]trafficpolicy.DarkLaunchPolicy{
+		"bookstore.bookstore-ns": {ShadowCluster: "bookstore-ns/bookstore-v2", Percentage: 100},
+		"bookbuyer":              {ShadowCluster: "bookbuyer-ns/bookbuyer-v2", Percentage: 100},
+	}
+	assert.Equal(expected, mc.listDarkLaunches("bookbuyer-ns"))
+}
+
+func TestAddDarkLaunchShadowHostnames(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{kubeController: mockKubeController}
+
+	primary := newDarkLaunchedService("bookstore", "bookstore-ns", map[string]string{constants.DarkLaunchServiceAnnotation: "bookstore-v2"})
+	mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{primary}).Times(1)
+
+	shadowPolicy := trafficpolicy.NewInboundTrafficPolicy("bookstore-v2.bookstore-ns", []string{"bookstore-v2"})
+	otherPolicy := trafficpolicy.NewInboundTrafficPolicy("bookbuyer.bookstore-ns", []string{"bookbuyer"})
+	mc.addDarkLaunchShadowHostnames([]*trafficpolicy.InboundTrafficPolicy{shadowPolicy, otherPolicy}, []service.MeshService{
+		{Name: "bookstore-v2", Namespace: "bookstore-ns"},
+		{Name: "bookbuyer", Namespace: "bookstore-ns"},
+	})
+
+	expectedHostnames := []string{"bookstore-v2"}
+	for _, hostname := range k8s.GetHostnamesForService(primary, true) {
+		expectedHostnames = append(expectedHostnames, hostname+"-shadow")
+	}
+	assert.Equal(expectedHostnames, shadowPolicy.Hostnames)
+	assert.Contains(shadowPolicy.Hostnames, "bookstore.bookstore-ns-shadow")
+	assert.Equal([]string{"bookbuyer"}, otherPolicy.Hostnames)
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	mapset "github.com/deckarep/golang-set"
+
 	a "github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/kubernetes/events"
 )
@@ -37,9 +39,17 @@ func (mc *MeshCatalog) dispatcher() {
 		a.ServiceAdded, a.ServiceDeleted, a.ServiceUpdated, // service
 		a.ServiceAccountAdded, a.ServiceAccountDeleted, a.ServiceAccountUpdated, // serviceaccount
 		a.TrafficSplitAdded, a.TrafficSplitDeleted, a.TrafficSplitUpdated, // traffic split
-		a.TrafficTargetAdded, a.TrafficTargetDeleted, a.TrafficTargetUpdated, // traffic target
+		a.TrafficTargetAdded, a.TrafficTargetDeleted, a.TrafficTargetUpdated, a.TrafficTargetExpired, // traffic target
 		a.IngressAdded, a.IngressDeleted, a.IngressUpdated, // Ingress
+		a.IngressBackendAdded, a.IngressBackendDeleted, a.IngressBackendUpdated, // IngressBackend
+		a.EgressAdded, a.EgressDeleted, a.EgressUpdated, // Egress
+		a.RetryPolicyAdded, a.RetryPolicyDeleted, a.RetryPolicyUpdated, // Retry
+		a.UpstreamTrafficSettingAdded, a.UpstreamTrafficSettingDeleted, a.UpstreamTrafficSettingUpdated, // UpstreamTrafficSetting
+		a.BandwidthLimitAdded, a.BandwidthLimitDeleted, a.BandwidthLimitUpdated, // BandwidthLimit
+		a.GatewayAdded, a.GatewayDeleted, a.GatewayUpdated, // Gateway API Gateway
+		a.GatewayHTTPRouteAdded, a.GatewayHTTPRouteDeleted, a.GatewayHTTPRouteUpdated, // Gateway API HTTPRoute
 		a.TCPRouteAdded, a.TCPRouteDeleted, a.TCPRouteUpdated, // TCProute
+		a.PolicyScheduleBoundary, // SMI policy schedules
 	)
 
 	// State and channels for event-coalescing
@@ -47,6 +57,12 @@ func (mc *MeshCatalog) dispatcher() {
 	chanMovingDeadline := make(<-chan time.Time)
 	chanMaxDeadline := make(<-chan time.Time)
 
+	// Changes coalesced into the scheduled broadcast, stamped with a new configuration generation when it is published
+	var changes []string
+	// Services whose proxies are affected by the changes coalesced into the scheduled broadcast, or nil if the changes
+	// may affect any proxy
+	var scope mapset.Set
+
 	// tl;dr "When a broadcast request is scheduled, we will wait (3s) in case we receive another broadcast request
 	// during this delay that can be coalesced (and restart the (3s) count if we do) up to a maximum of (15s) delay"
 
@@ -80,8 +96,10 @@ func (mc *MeshCatalog) dispatcher() {
 			// - detected a config delta
 			// - another module requested a broadcast through ScheduleProxyBroadcast
 			if delta || psubMessage.AnnouncementType == a.ScheduleProxyBroadcast {
+				changes = addConfigGenerationChange(changes, psubMessage)
 				if !broadcastScheduled {
 					broadcastScheduled = true
+					scope = mapset.NewSet()
 					chanMaxDeadline = time.After(maxBroadcastDeadlineTime)
 					chanMovingDeadline = time.After(maxGraceDeadlineTime)
 					log.Info().Msg("Broadcast scheduled by config changes")
@@ -89,6 +107,7 @@ func (mc *MeshCatalog) dispatcher() {
 					// If a broadcast is already scheduled, just reset the moving deadline
 					chanMovingDeadline = time.After(maxGraceDeadlineTime)
 				}
+				scope = addConfigGenerationScope(scope, psubMessage)
 			} else {
 				// Do nothing on non-delta updates
 				continue
@@ -96,24 +115,32 @@ func (mc *MeshCatalog) dispatcher() {
 
 		// A select-fallthrough doesn't exist, we are copying some code here
 		case <-chanMovingDeadline:
-			log.Info().Msgf("Moving deadline trigger - Broadcast envoy update")
+			generation := mc.newConfigGeneration(changes, scope)
+			log.Info().Msgf("Moving deadline trigger - Broadcast envoy update for configuration generation %d", generation.ID)
 			events.GetPubSubInstance().Publish(events.PubSubMessage{
 				AnnouncementType: a.ProxyBroadcast,
+				NewObj:           generation,
 			})
 
-			// broadcast done, reset timer channels
+			// broadcast done, reset timer channels and changes
 			broadcastScheduled = false
+			changes = nil
+			scope = nil
 			chanMovingDeadline = make(<-chan time.Time)
 			chanMaxDeadline = make(<-chan time.Time)
 
 		case <-chanMaxDeadline:
-			log.Info().Msgf("Max deadline trigger - Broadcast envoy update")
+			generation := mc.newConfigGeneration(changes, scope)
+			log.Info().Msgf("Max deadline trigger - Broadcast envoy update for configuration generation %d", generation.ID)
 			events.GetPubSubInstance().Publish(events.PubSubMessage{
 				AnnouncementType: a.ProxyBroadcast,
+				NewObj:           generation,
 			})
 
-			// broadcast done, reset timer channels
+			// broadcast done, reset timer channels and changes
 			broadcastScheduled = false
+			changes = nil
+			scope = nil
 			chanMovingDeadline = make(<-chan time.Time)
 			chanMaxDeadline = make(<-chan time.Time)
 		}
@@ -0,0 +1,303 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// defaultEgressTLSOriginationPort is the port of the external hosts TLS is originated to when an Egress does not specify it
+const defaultEgressTLSOriginationPort = 443
+
+// GetEgressPolicy returns the external destinations the given service account is allowed to reach, per port, as
+// specified by the Egress resources listing the service account as a source. Hosts, including wildcard domains, are
+// matched on the ports with the http and https protocols, and IP ranges on the ports of any protocol. Nil is returned when no Egress resource lists
+// the service account.
+func (mc *MeshCatalog) GetEgressPolicy(svcAccount service.K8sServiceAccount) *trafficpolicy.EgressPolicy {
+	return newEgressPolicy(mc.policyController.ListEgressPoliciesForSourceIdentity(svcAccount))
+}
+
+// GetEgressGatewayPolicy returns the external destinations any service account is allowed to reach, per port, as
+// specified by all the Egress resources. The egress gateway forwards the egress traffic of the sidecar proxies, which
+// only send it the traffic allowed by the Egress policies of their service account. Nil is returned when there is no
+// Egress resource.
+func (mc *MeshCatalog) GetEgressGatewayPolicy() *trafficpolicy.EgressPolicy {
+	return newEgressPolicy(mc.policyController.ListEgressPolicies())
+}
+
+// IsEgressAuditEnabled returns true if the Egress policies of the given proxy are evaluated in audit mode rather than
+// enforced: the egress connections to the destinations they do not allow are reported to the controller and still
+// forwarded to their original destination, instead of being denied. Egress policies are only evaluated in audit mode
+// when egress is disabled for the proxy, as egress to any destination is allowed otherwise. Both settings can be
+// overridden by the annotations of the namespace of the pod of the proxy and of the pod.
+func (mc *MeshCatalog) IsEgressAuditEnabled(proxy *envoy.Proxy) bool {
+	overrides := mc.GetProxyOverrides(proxy)
+	if overrides.Bool(constants.EgressAnnotation, mc.configurator.IsEgressEnabled()) {
+		return false
+	}
+	return overrides.Bool(constants.EgressAuditAnnotation, mc.configurator.IsEgressAuditModeEnabled())
+}
+
+// newEgressPolicy returns the external destinations allowed by the given Egress resources, or nil if there are none
+func newEgressPolicy(egresses []*policyV1alpha1.Egress) *trafficpolicy.EgressPolicy {
+	if len(egresses) == 0 {
+		return nil
+	}
+
+	policy := &trafficpolicy.EgressPolicy{
+		HTTPHosts:  make(map[uint32][]string),
+		HTTPSHosts: make(map[uint32][]string),
+		IPRanges:   make(map[uint32][]string),
+	}
+	httpHosts := make(map[uint32]map[string]bool)
+	httpsHosts := make(map[uint32]map[string]bool)
+	ipRanges := make(map[uint32]map[string]bool)
+	httpProxyHosts := make(map[uint32]map[string]bool)
+	httpsProxyHosts := make(map[uint32]map[string]bool)
+
+	for _, egress := range egresses {
+		name := fmt.Sprintf("%s/%s", egress.Namespace, egress.Name)
+		policy.Names = append(policy.Names, name)
+
+		var hosts []string
+		for _, specHost := range egress.Spec.Hosts {
+			host, err := envoy.NormalizeHost(specHost)
+			if err != nil || !isValidEgressHost(host) {
+				log.Error().Msgf("Ignoring host %q in Egress %s, must be a host name or a wildcard domain such as *.example.com", specHost, name)
+				continue
+			}
+			hosts = append(hosts, host)
+		}
+
+		var cidrs []string
+		for _, cidr := range egress.Spec.IPAddresses {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				log.Error().Err(err).Msgf("Ignoring IP address range in Egress %s, invalid CIDR range %s", name, cidr)
+				continue
+			}
+			cidrs = append(cidrs, cidr)
+		}
+
+		var proxyHosts []string
+		if egress.Spec.HTTPProxy {
+			proxyHosts = getHTTPProxyHosts(hosts, name)
+		}
+
+		var tlsOrigination *trafficpolicy.EgressTLSOrigination
+		if egress.Spec.TLS != nil {
+			tlsOrigination = newEgressTLSOrigination(egress)
+		}
+
+		for _, port := range egress.Spec.Ports {
+			protocol := strings.ToLower(port.Protocol)
+			switch protocol {
+			case policyV1alpha1.ProtocolHTTP:
+				if tlsOrigination != nil {
+					addTLSOriginatedHosts(policy, httpHosts, port.Number, hosts, tlsOrigination, name)
+				} else {
+					addToPortSet(httpHosts, port.Number, hosts...)
+				}
+				addToPortSet(httpProxyHosts, port.Number, proxyHosts...)
+			case policyV1alpha1.ProtocolHTTPS:
+				addToPortSet(httpsHosts, port.Number, hosts...)
+				addToPortSet(httpsProxyHosts, port.Number, proxyHosts...)
+			case policyV1alpha1.ProtocolTCP:
+				// The destination host of plaintext TCP connections is unknown to the proxy
+				if len(hosts) > 0 {
+					log.Warn().Msgf("Ignoring hosts of port %d in Egress %s, hosts cannot be matched on ports with the %s protocol", port.Number, name, protocol)
+				}
+			default:
+				log.Error().Msgf("Ignoring port %d in Egress %s, unsupported protocol %s", port.Number, name, port.Protocol)
+				continue
+			}
+			addToPortSet(ipRanges, port.Number, cidrs...)
+		}
+	}
+
+	for port, hosts := range httpHosts {
+		policy.HTTPHosts[port] = sortedSetKeys(hosts)
+	}
+	for port, hosts := range httpsHosts {
+		policy.HTTPSHosts[port] = sortedSetKeys(hosts)
+	}
+	for port, cidrs := range ipRanges {
+		policy.IPRanges[port] = sortedSetKeys(cidrs)
+	}
+	if len(httpProxyHosts) > 0 {
+		policy.HTTPProxyHosts = make(map[uint32][]string)
+		for port, hosts := range httpProxyHosts {
+			policy.HTTPProxyHosts[port] = sortedSetKeys(hosts)
+		}
+	}
+	if len(httpsProxyHosts) > 0 {
+		policy.HTTPSProxyHosts = make(map[uint32][]string)
+		for port, hosts := range httpsProxyHosts {
+			policy.HTTPSProxyHosts[port] = sortedSetKeys(hosts)
+		}
+	}
+
+	return policy
+}
+
+// getHTTPProxyHosts returns the given hosts of the Egress with the given name that can be reached through the explicit
+// HTTP proxy of the sidecar proxies. The connections to wildcard domains are forwarded to their original destination,
+// which is the HTTP proxy itself for the applications using it, so wildcard domains are ignored.
+func getHTTPProxyHosts(hosts []string, egressName string) []string {
+	var proxyHosts []string
+	for _, host := range hosts {
+		if envoy.IsWildcardHost(host) {
+			log.Warn().Msgf("Ignoring wildcard host %s in Egress %s, wildcard domains cannot be reached through the HTTP proxy", host, egressName)
+			continue
+		}
+		proxyHosts = append(proxyHosts, host)
+	}
+	return proxyHosts
+}
+
+// newEgressTLSOrigination returns the TLS connections originated to the hosts of the given Egress, which must specify
+// TLS origination
+func newEgressTLSOrigination(egress *policyV1alpha1.Egress) *trafficpolicy.EgressTLSOrigination {
+	tls := egress.Spec.TLS
+	tlsOrigination := &trafficpolicy.EgressTLSOrigination{
+		Port:           tls.Port,
+		MeshClientCert: tls.MeshClientCert,
+	}
+	if tlsOrigination.Port == 0 {
+		tlsOrigination.Port = defaultEgressTLSOriginationPort
+	}
+	if tls.CABundleSecret != nil {
+		tlsOrigination.CABundleSecret = getEgressSecretName(tls.CABundleSecret, egress.Namespace)
+	}
+	if tls.ClientCertSecret != nil {
+		tlsOrigination.ClientCertSecret = getEgressSecretName(tls.ClientCertSecret, egress.Namespace)
+		tlsOrigination.MeshClientCert = false
+	}
+	return tlsOrigination
+}
+
+// getEgressSecretName returns the namespaced name of the given secret referenced by an Egress in the given namespace
+func getEgressSecretName(secret *policyV1alpha1.SecretReference, egressNamespace string) string {
+	namespace := secret.Namespace
+	if namespace == "" {
+		namespace = egressNamespace
+	}
+	return fmt.Sprintf("%s/%s", namespace, secret.Name)
+}
+
+// addTLSOriginatedHosts adds the given hosts of the given HTTP port to the given set of HTTP hosts, and their given TLS
+// origination to the given policy. TLS origination takes precedence over the Egress resources allowing plaintext
+// requests to the same host and port, and the first of the Egress resources originating TLS to the same host and port
+// differently is applied. The host a wildcard domain is resolved to by the application is unknown, so TLS cannot be
+// originated to wildcard domains, which are ignored.
+func addTLSOriginatedHosts(policy *trafficpolicy.EgressPolicy, httpHosts map[uint32]map[string]bool, port uint32, hosts []string, tlsOrigination *trafficpolicy.EgressTLSOrigination, egressName string) {
+	for _, host := range hosts {
+		if envoy.IsWildcardHost(host) {
+			log.Warn().Msgf("Ignoring wildcard host %s of port %d in Egress %s, TLS cannot be originated to wildcard domains", host, port, egressName)
+			continue
+		}
+		addToPortSet(httpHosts, port, host)
+
+		if policy.TLSOriginations == nil {
+			policy.TLSOriginations = make(map[uint32]map[string]*trafficpolicy.EgressTLSOrigination)
+		}
+		if policy.TLSOriginations[port] == nil {
+			policy.TLSOriginations[port] = make(map[string]*trafficpolicy.EgressTLSOrigination)
+		}
+		if existing, ok := policy.TLSOriginations[port][host]; ok {
+			if *existing != *tlsOrigination {
+				log.Warn().Msgf("Ignoring TLS origination of host %s on port %d in Egress %s, conflicting with the TLS origination of another Egress", host, port, egressName)
+			}
+			continue
+		}
+		policy.TLSOriginations[port][host] = tlsOrigination
+	}
+}
+
+// GetEgressCABundle returns the CA bundle validating the certificates of external hosts stored in the secret with the
+// given namespaced name, referenced by an Egress
+func (mc *MeshCatalog) GetEgressCABundle(secretName string) ([]byte, error) {
+	secret, err := mc.getSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle, ok := secret.Data[constants.KubernetesOpaqueSecretCAKey]
+	if !ok || len(caBundle) == 0 {
+		return nil, errors.Errorf("Secret %s does not have a CA bundle under the %s key", secretName, constants.KubernetesOpaqueSecretCAKey)
+	}
+
+	return caBundle, nil
+}
+
+// GetEgressClientCertificate returns the certificate chain and the private key of the client certificate presented to
+// external hosts stored in the secret with the given namespaced name, referenced by an Egress
+func (mc *MeshCatalog) GetEgressClientCertificate(secretName string) ([]byte, []byte, error) {
+	secret, err := mc.getSecret(secretName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certChain, key := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certChain) == 0 || len(key) == 0 {
+		return nil, nil, errors.Errorf("Secret %s does not have a client certificate under the %s and %s keys", secretName, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+
+	return certChain, key, nil
+}
+
+// getSecret returns the Kubernetes secret with the given namespaced name
+func (mc *MeshCatalog) getSecret(secretName string) (*corev1.Secret, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(secretName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid secret name %s", secretName)
+	}
+
+	secret, err := mc.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting secret %s", secretName)
+	}
+	return secret, nil
+}
+
+// isValidEgressHost returns true if the given host is a host name, or a wildcard domain such as *.example.com matching
+// its subdomains
+func isValidEgressHost(host string) bool {
+	domain := strings.TrimPrefix(host, envoy.WildcardHostPrefix)
+	return domain != "" && !strings.ContainsAny(domain, "*:/")
+}
+
+// addToPortSet adds the given values to the set of the given port
+func addToPortSet(portSets map[uint32]map[string]bool, port uint32, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	if portSets[port] == nil {
+		portSets[port] = make(map[string]bool)
+	}
+	for _, value := range values {
+		portSets[port][value] = true
+	}
+}
+
+// sortedSetKeys returns the values of the given set in sorted order
+func sortedSetKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,34 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// IsEgressGateway returns true if the given proxy is the egress gateway, i.e. its xDS certificate was issued
+// for the egress gateway service account in the OSM namespace.
+func (mc *MeshCatalog) IsEgressGateway(proxy *envoy.Proxy) bool {
+	svcAccount, err := GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		return false
+	}
+
+	return svcAccount == GetEgressGatewayServiceAccount(mc.configurator.GetOSMNamespace())
+}
+
+// GetEgressGatewayServiceAccount returns the service account of the egress gateway in the given OSM namespace
+func GetEgressGatewayServiceAccount(osmNamespace string) service.K8sServiceAccount {
+	return service.K8sServiceAccount{
+		Name:      constants.EgressGatewayName,
+		Namespace: osmNamespace,
+	}
+}
+
+// GetEgressGatewayService returns the service fronting the egress gateway in the given OSM namespace
+func GetEgressGatewayService(osmNamespace string) service.MeshService {
+	return service.MeshService{
+		Name:      constants.EgressGatewayName,
+		Namespace: osmNamespace,
+	}
+}
@@ -0,0 +1,36 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestIsEgressGateway(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	mc := MeshCatalog{configurator: mockConfigurator}
+
+	egressGateway := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.EgressGatewayName, "osm-system"), "", nil)
+	assert.True(mc.IsEgressGateway(egressGateway))
+
+	otherNamespaceProxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.EgressGatewayName, "default"), "", nil)
+	assert.False(mc.IsEgressGateway(otherNamespaceProxy))
+
+	nodeProxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.NodeProxyName, "osm-system"), "", nil)
+	assert.False(mc.IsEgressGateway(nodeProxy))
+
+	invalidProxy := envoy.NewProxy(certificate.CommonName("invalid"), "", nil)
+	assert.False(mc.IsEgressGateway(invalidProxy))
+}
@@ -0,0 +1,339 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressPolicy(t *testing.T) {
+	svcAccount := service.K8sServiceAccount{Name: "curl", Namespace: "curl"}
+
+	testCases := []struct {
+		name           string
+		egresses       []*policyV1alpha1.Egress
+		expectedPolicy *trafficpolicy.EgressPolicy
+	}{
+		{
+			name:           "no Egress lists the service account",
+			egresses:       nil,
+			expectedPolicy: nil,
+		},
+		{
+			name: "Egress lists the service account",
+			egresses: []*policyV1alpha1.Egress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "curl"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts:       []string{"HTTPBin.org", "*.httpbin.org", "httpbin.org:80", "*", "api.*.httpbin.org"},
+						IPAddresses: []string{"203.0.113.0/24", "not-a-cidr"},
+						Ports: []policyV1alpha1.PortSpec{
+							{Number: 80, Protocol: "HTTP"},
+							{Number: 443, Protocol: "https"},
+							{Number: 5432, Protocol: "tcp"},
+							{Number: 53, Protocol: "udp"},
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.EgressPolicy{
+				Names:      []string{"curl/httpbin"},
+				HTTPHosts:  map[uint32][]string{80: {"*.httpbin.org", "httpbin.org"}},
+				HTTPSHosts: map[uint32][]string{443: {"*.httpbin.org", "httpbin.org"}},
+				IPRanges:   map[uint32][]string{80: {"203.0.113.0/24"}, 443: {"203.0.113.0/24"}, 5432: {"203.0.113.0/24"}},
+			},
+		},
+		{
+			name: "hosts are normalized",
+			egresses: []*policyV1alpha1.Egress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "books", Namespace: "curl"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"Bücher.example.", "xn--bcher-kva.example", "*.Example.com", "-invalid.example.com"},
+						Ports: []policyV1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.EgressPolicy{
+				Names:      []string{"curl/books"},
+				HTTPHosts:  map[uint32][]string{},
+				HTTPSHosts: map[uint32][]string{443: {"*.example.com", "xn--bcher-kva.example"}},
+				IPRanges:   map[uint32][]string{},
+			},
+		},
+		{
+			name: "Egress resources listing the service account are merged",
+			egresses: []*policyV1alpha1.Egress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "curl"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"httpbin.org"},
+						Ports: []policyV1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "egress"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts:       []string{"example.com", "httpbin.org"},
+						IPAddresses: []string{"10.0.0.0/8"},
+						Ports: []policyV1alpha1.PortSpec{
+							{Number: 443, Protocol: "https"},
+							{Number: 8080, Protocol: "http"},
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.EgressPolicy{
+				Names:      []string{"curl/httpbin", "egress/external"},
+				HTTPHosts:  map[uint32][]string{8080: {"example.com", "httpbin.org"}},
+				HTTPSHosts: map[uint32][]string{443: {"example.com", "httpbin.org"}},
+				IPRanges:   map[uint32][]string{443: {"10.0.0.0/8"}, 8080: {"10.0.0.0/8"}},
+			},
+		},
+		{
+			name: "Egress resources originating TLS to the hosts of their HTTP ports",
+			egresses: []*policyV1alpha1.Egress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "curl"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"httpbin.org", "*.httpbin.org"},
+						Ports: []policyV1alpha1.PortSpec{
+							{Number: 80, Protocol: "http"},
+							{Number: 443, Protocol: "https"},
+						},
+						TLS: &policyV1alpha1.EgressTLSSpec{
+							CABundleSecret: &policyV1alpha1.SecretReference{Name: "httpbin-ca"},
+							MeshClientCert: true,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "egress"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"example.com", "httpbin.org"},
+						Ports: []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+						TLS: &policyV1alpha1.EgressTLSSpec{
+							Port:             8443,
+							MeshClientCert:   true,
+							ClientCertSecret: &policyV1alpha1.SecretReference{Name: "client", Namespace: "certs"},
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.EgressPolicy{
+				Names:      []string{"curl/httpbin", "egress/external"},
+				HTTPHosts:  map[uint32][]string{80: {"example.com", "httpbin.org"}},
+				HTTPSHosts: map[uint32][]string{443: {"*.httpbin.org", "httpbin.org"}},
+				IPRanges:   map[uint32][]string{},
+				TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+					80: {
+						// The first Egress originating TLS to httpbin.org is applied, the wildcard domain is ignored
+						"httpbin.org": {Port: 443, CABundleSecret: "curl/httpbin-ca", MeshClientCert: true},
+						"example.com": {Port: 8443, ClientCertSecret: "certs/client"},
+					},
+				},
+			},
+		},
+		{
+			name: "Egress resources reached through the HTTP proxy",
+			egresses: []*policyV1alpha1.Egress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "curl"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"httpbin.org", "*.httpbin.org"},
+						Ports: []policyV1alpha1.PortSpec{
+							{Number: 80, Protocol: "http"},
+							{Number: 443, Protocol: "https"},
+						},
+						HTTPProxy: true,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "egress"},
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"example.com"},
+						Ports: []policyV1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.EgressPolicy{
+				Names:      []string{"curl/httpbin", "egress/external"},
+				HTTPHosts:  map[uint32][]string{80: {"*.httpbin.org", "httpbin.org"}},
+				HTTPSHosts: map[uint32][]string{443: {"*.httpbin.org", "example.com", "httpbin.org"}},
+				IPRanges:   map[uint32][]string{},
+				// Only the hosts of the Egress enabling the HTTP proxy are reached through it, wildcard domains are ignored
+				HTTPProxyHosts:  map[uint32][]string{80: {"httpbin.org"}},
+				HTTPSProxyHosts: map[uint32][]string{443: {"httpbin.org"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+			mockPolicyMonitor.EXPECT().ListEgressPoliciesForSourceIdentity(svcAccount).Return(tc.egresses).Times(1)
+
+			mc := &MeshCatalog{policyController: mockPolicyMonitor}
+			assert.Equal(tc.expectedPolicy, mc.GetEgressPolicy(svcAccount))
+		})
+	}
+}
+
+func TestGetEgressGatewayPolicy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mc := &MeshCatalog{policyController: mockPolicyMonitor}
+
+	mockPolicyMonitor.EXPECT().ListEgressPolicies().Return(nil).Times(1)
+	assert.Nil(mc.GetEgressGatewayPolicy())
+
+	// The Egress resources of all the service accounts are merged
+	mockPolicyMonitor.EXPECT().ListEgressPolicies().Return([]*policyV1alpha1.Egress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "curl"},
+			Spec: policyV1alpha1.EgressSpec{
+				Sources: []policyV1alpha1.EgressSourceSpec{{Kind: policyV1alpha1.KindServiceAccount, Name: "curl", Namespace: "curl"}},
+				Hosts:   []string{"httpbin.org"},
+				Ports:   []policyV1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "bookbuyer"},
+			Spec: policyV1alpha1.EgressSpec{
+				Sources: []policyV1alpha1.EgressSourceSpec{{Kind: policyV1alpha1.KindServiceAccount, Name: "bookbuyer", Namespace: "bookbuyer"}},
+				Hosts:   []string{"example.com"},
+				Ports:   []policyV1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+			},
+		},
+	}).Times(1)
+	assert.Equal(&trafficpolicy.EgressPolicy{
+		Names:      []string{"curl/httpbin", "bookbuyer/example"},
+		HTTPHosts:  map[uint32][]string{},
+		HTTPSHosts: map[uint32][]string{443: {"example.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{},
+	}, mc.GetEgressGatewayPolicy())
+}
+
+func TestIsEgressAuditEnabled(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{configurator: mockConfigurator, kubeController: mockKubeController}
+
+	// The mesh-wide configuration applies to the proxies without a pod
+	devProxy := envoy.NewProxy(NewDevProxyCertCommonName(uuid.New(), tests.BookbuyerServiceAccountName, tests.Namespace), "123456", nil)
+
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	mockConfigurator.EXPECT().IsEgressAuditModeEnabled().Return(false).Times(1)
+	assert.False(mc.IsEgressAuditEnabled(devProxy))
+
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	mockConfigurator.EXPECT().IsEgressAuditModeEnabled().Return(true).Times(1)
+	assert.True(mc.IsEgressAuditEnabled(devProxy))
+
+	// Egress policies are not evaluated when egress is allowed to any destination
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(true).Times(1)
+	assert.False(mc.IsEgressAuditEnabled(devProxy))
+
+	// The annotation of the namespace of the pod of the proxy overrides the mesh-wide configuration
+	proxyUUID := uuid.New()
+	pod := tests.NewPodFixture(tests.Namespace, "pod-0", tests.BookbuyerServiceAccountName, map[string]string{
+		constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+	})
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tests.Namespace,
+			Annotations: map[string]string{constants.EgressAuditAnnotation: "false"},
+		},
+	}
+	proxy := envoy.NewProxy(NewCertCommonNameWithProxyID(proxyUUID, tests.BookbuyerServiceAccountName, tests.Namespace), "123456", nil)
+
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{&pod}).Times(1)
+	mockKubeController.EXPECT().GetNamespace(tests.Namespace).Return(namespace).Times(1)
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	mockConfigurator.EXPECT().IsEgressAuditModeEnabled().Return(true).Times(1)
+	assert.False(mc.IsEgressAuditEnabled(proxy))
+}
+
+func TestGetEgressCABundle(t *testing.T) {
+	assert := tassert.New(t)
+
+	mc := &MeshCatalog{
+		kubeClient: testclient.NewSimpleClientset(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "httpbin-ca", Namespace: "curl"},
+				Data:       map[string][]byte{constants.KubernetesOpaqueSecretCAKey: []byte("ca-bundle")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-ca", Namespace: "curl"},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+		),
+	}
+
+	caBundle, err := mc.GetEgressCABundle("curl/httpbin-ca")
+	assert.Nil(err)
+	assert.Equal([]byte("ca-bundle"), caBundle)
+
+	_, err = mc.GetEgressCABundle("curl/no-ca")
+	assert.NotNil(err)
+
+	_, err = mc.GetEgressCABundle("curl/missing")
+	assert.NotNil(err)
+}
+
+func TestGetEgressClientCertificate(t *testing.T) {
+	assert := tassert.New(t)
+
+	mc := &MeshCatalog{
+		kubeClient: testclient.NewSimpleClientset(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "curl"},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte("cert"),
+					corev1.TLSPrivateKeyKey: []byte("key"),
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-key", Namespace: "curl"},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert")},
+			},
+		),
+	}
+
+	certChain, key, err := mc.GetEgressClientCertificate("curl/client")
+	assert.Nil(err)
+	assert.Equal([]byte("cert"), certChain)
+	assert.Equal([]byte("key"), key)
+
+	_, _, err = mc.GetEgressClientCertificate("curl/no-key")
+	assert.NotNil(err)
+
+	_, _, err = mc.GetEgressClientCertificate("curl/missing")
+	assert.NotNil(err)
+}
@@ -1,10 +1,19 @@
 package catalog
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
+const (
+	// anyTopologyKey is the topology key that matches any endpoint of a service
+	anyTopologyKey = "*"
+)
+
 // ListEndpointsForService returns the list of provider endpoints corresponding to a service
 func (mc *MeshCatalog) ListEndpointsForService(svc service.MeshService) ([]endpoint.Endpoint, error) {
 	var endpoints []endpoint.Endpoint
@@ -54,14 +63,21 @@ func (mc *MeshCatalog) ListAllowedEndpointsForService(downstreamIdentity service
 
 	// allowedEndpoints comprises of only those endpoints from outboundEndpoints that matches the endpoints from listEndpointsforIdentity
 	// i.e. only those interseting endpoints are taken into cosideration
+	// An endpoint must only be listed once even if the pod backing it is reported by multiple providers.
 	var allowedEndpoints []endpoint.Endpoint
+	added := make(map[string]struct{})
 	for _, destSvcAccount := range destSvcAccounts {
 		podEndpoints := mc.listEndpointsforIdentity(destSvcAccount)
 		for _, ep := range outboundEndpoints {
 			for _, podIP := range podEndpoints {
-				if ep.IP.Equal(podIP.IP) {
-					allowedEndpoints = append(allowedEndpoints, ep)
+				if !ep.IP.Equal(podIP.IP) {
+					continue
+				}
+				if _, ok := added[ep.String()]; ok {
+					continue
 				}
+				added[ep.String()] = struct{}{}
+				allowedEndpoints = append(allowedEndpoints, ep)
 			}
 		}
 	}
@@ -81,3 +97,75 @@ func (mc *MeshCatalog) listEndpointsforIdentity(sa service.K8sServiceAccount) []
 	}
 	return endpoints
 }
+
+// FilterEndpointsByTopology returns the subset of the given endpoints of an upstream service that the downstream proxy
+// with the given certificate should route to, based on the internal traffic policy and the topology keys of the
+// upstream service.
+// A Local internal traffic policy restricts the endpoints to the ones on the node of the downstream proxy, and no
+// endpoints are returned if there are none, as is the case for kube-proxy.
+// Otherwise, the topology keys are evaluated in order, and the endpoints matching the first key with at least one
+// endpoint are returned. If no key matches, no endpoints are returned, as is the case for kube-proxy.
+// Only the 'kubernetes.io/hostname' and '*' topology keys are supported.
+func (mc *MeshCatalog) FilterEndpointsByTopology(downstreamCN certificate.CommonName, upstreamSvc service.MeshService, endpoints []endpoint.Endpoint) []endpoint.Endpoint {
+	k8sSvc := mc.kubeController.GetService(upstreamSvc)
+	if k8sSvc == nil {
+		return endpoints
+	}
+	internalTrafficPolicyLocal := mc.kubeController.GetServiceInternalTrafficPolicy(upstreamSvc) == k8s.ServiceInternalTrafficPolicyLocal
+	if !internalTrafficPolicyLocal && len(k8sSvc.Spec.TopologyKeys) == 0 {
+		return endpoints
+	}
+
+	downstreamPod, err := GetPodFromCertificate(downstreamCN, mc.kubeController)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up pod for proxy with certificate CN=%s, ignoring the topology of service %s", downstreamCN, upstreamSvc)
+		return endpoints
+	}
+
+	if internalTrafficPolicyLocal {
+		nodeLocalEndpoints := mc.filterEndpointsByNode(endpoints, downstreamPod.Spec.NodeName)
+		if len(nodeLocalEndpoints) == 0 {
+			log.Debug().Msgf("No endpoints of service %s with a Local internal traffic policy on the node of proxy with certificate CN=%s",
+				upstreamSvc, downstreamCN)
+		}
+		return nodeLocalEndpoints
+	}
+
+	for _, topologyKey := range k8sSvc.Spec.TopologyKeys {
+		switch topologyKey {
+		case anyTopologyKey:
+			return endpoints
+
+		case corev1.LabelHostname:
+			nodeLocalEndpoints := mc.filterEndpointsByNode(endpoints, downstreamPod.Spec.NodeName)
+			if len(nodeLocalEndpoints) > 0 {
+				return nodeLocalEndpoints
+			}
+
+		default:
+			log.Warn().Msgf("Unsupported topology key %s on service %s, skipping it", topologyKey, upstreamSvc)
+		}
+	}
+
+	log.Debug().Msgf("No endpoints of service %s match its topology keys %v for proxy with certificate CN=%s",
+		upstreamSvc, k8sSvc.Spec.TopologyKeys, downstreamCN)
+	return nil
+}
+
+// filterEndpointsByNode returns the endpoints backed by pods scheduled on the given node
+func (mc *MeshCatalog) filterEndpointsByNode(endpoints []endpoint.Endpoint, nodeName string) []endpoint.Endpoint {
+	if nodeName == "" {
+		return nil
+	}
+
+	var nodeLocalEndpoints []endpoint.Endpoint
+	for _, ep := range endpoints {
+		for _, pod := range mc.kubeController.ListPodsByIP(ep.IP.String()) {
+			if pod.Spec.NodeName == nodeName {
+				nodeLocalEndpoints = append(nodeLocalEndpoints, ep)
+				break
+			}
+		}
+	}
+	return nodeLocalEndpoints
+}
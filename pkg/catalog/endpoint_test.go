@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"testing"
 
@@ -16,6 +17,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
@@ -198,3 +200,107 @@ func TestListAllowedEndpointsForService(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterEndpointsByTopology(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyUUID := uuid.New()
+	downstreamPod := tests.NewPodFixture(tests.Namespace, "bookbuyer", tests.BookbuyerServiceAccountName,
+		map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID.String()})
+	downstreamPod.Spec.NodeName = "node-1"
+	downstreamCN := certificate.CommonName(fmt.Sprintf("%s.%s.%s", proxyUUID, tests.BookbuyerServiceAccountName, tests.Namespace))
+
+	localPod := tests.NewPodFixture(tests.Namespace, "bookstore-local", tests.BookstoreServiceAccountName, nil)
+	localPod.Spec.NodeName = "node-1"
+	localPod.Status.PodIPs = []v1.PodIP{{IP: "10.0.0.1"}}
+	remotePod := tests.NewPodFixture(tests.Namespace, "bookstore-remote", tests.BookstoreServiceAccountName, nil)
+	remotePod.Spec.NodeName = "node-2"
+	remotePod.Status.PodIPs = []v1.PodIP{{IP: "10.0.0.2"}}
+
+	localEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 80}
+	remoteEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.2"), Port: 80}
+	allEndpoints := []endpoint.Endpoint{localEndpoint, remoteEndpoint}
+
+	testCases := []struct {
+		name                  string
+		internalTrafficPolicy string
+		topologyKeys          []string
+		endpoints             []endpoint.Endpoint
+		expectedEndpoints     []endpoint.Endpoint
+	}{
+		{
+			name:              "service without topology keys",
+			topologyKeys:      nil,
+			endpoints:         allEndpoints,
+			expectedEndpoints: allEndpoints,
+		},
+		{
+			name:              "node-local endpoints are preferred",
+			topologyKeys:      []string{v1.LabelHostname, "*"},
+			endpoints:         allEndpoints,
+			expectedEndpoints: []endpoint.Endpoint{localEndpoint},
+		},
+		{
+			name:              "fall back to any endpoint when there are no node-local endpoints",
+			topologyKeys:      []string{v1.LabelHostname, "*"},
+			endpoints:         []endpoint.Endpoint{remoteEndpoint},
+			expectedEndpoints: []endpoint.Endpoint{remoteEndpoint},
+		},
+		{
+			name:              "no endpoints when node-local is required and there are no node-local endpoints",
+			topologyKeys:      []string{v1.LabelHostname},
+			endpoints:         []endpoint.Endpoint{remoteEndpoint},
+			expectedEndpoints: nil,
+		},
+		{
+			name:              "unsupported topology keys are skipped",
+			topologyKeys:      []string{v1.LabelZoneFailureDomainStable, "*"},
+			endpoints:         allEndpoints,
+			expectedEndpoints: allEndpoints,
+		},
+		{
+			name:                  "only node-local endpoints with a Local internal traffic policy",
+			internalTrafficPolicy: k8s.ServiceInternalTrafficPolicyLocal,
+			endpoints:             allEndpoints,
+			expectedEndpoints:     []endpoint.Endpoint{localEndpoint},
+		},
+		{
+			name:                  "no endpoints with a Local internal traffic policy and no node-local endpoints",
+			internalTrafficPolicy: k8s.ServiceInternalTrafficPolicyLocal,
+			endpoints:             []endpoint.Endpoint{remoteEndpoint},
+			expectedEndpoints:     nil,
+		},
+		{
+			name:                  "all endpoints with a Cluster internal traffic policy",
+			internalTrafficPolicy: k8s.ServiceInternalTrafficPolicyCluster,
+			endpoints:             allEndpoints,
+			expectedEndpoints:     allEndpoints,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{
+				kubeController: mockKubeController,
+			}
+
+			svc := tests.NewServiceFixture(tests.BookstoreV1ServiceName, tests.Namespace, nil)
+			svc.Spec.TopologyKeys = tc.topologyKeys
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(svc).Times(1)
+			internalTrafficPolicy := tc.internalTrafficPolicy
+			if internalTrafficPolicy == "" {
+				internalTrafficPolicy = k8s.ServiceInternalTrafficPolicyCluster
+			}
+			mockKubeController.EXPECT().GetServiceInternalTrafficPolicy(tests.BookstoreV1Service).Return(internalTrafficPolicy).Times(1)
+			mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{&downstreamPod, &localPod, &remotePod}).AnyTimes()
+			mockKubeController.EXPECT().ListPodsByIP("10.0.0.1").Return([]*v1.Pod{&localPod}).AnyTimes()
+			mockKubeController.EXPECT().ListPodsByIP("10.0.0.2").Return([]*v1.Pod{&remotePod}).AnyTimes()
+
+			actual := mc.FilterEndpointsByTopology(downstreamCN, tests.BookstoreV1Service, tc.endpoints)
+			assert.Equal(tc.expectedEndpoints, actual)
+		})
+	}
+}
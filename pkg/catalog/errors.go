@@ -26,4 +26,13 @@ var (
 
 	// ErrServiceNotFound is an error for when OSM cannot find a service.
 	ErrServiceNotFound = errors.New("service not found")
+
+	// ErrNodeProxyPodNotFound is an error for when OSM cannot find the pod of a per-node proxy.
+	ErrNodeProxyPodNotFound = errors.New("did not find pod for node proxy")
+
+	// ErrNodeProxyAddressMismatch is an error for when a per-node proxy did not connect from the IP of its pod.
+	ErrNodeProxyAddressMismatch = errors.New("node proxy address does not match its pod")
+
+	// ErrWAFRulesetNotFound is an error for when OSM cannot find the WAF ruleset referenced by a service.
+	ErrWAFRulesetNotFound = errors.New("WAF ruleset not found")
 )
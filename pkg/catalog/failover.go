@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetFailoverPolicy returns the backup endpoints that the traffic to the given service fails over to, as set by the
+// failover annotations of the service. Nil is returned if the service has no backup endpoints, or if its annotations
+// are invalid.
+func (mc *MeshCatalog) GetFailoverPolicy(svc service.MeshService) *trafficpolicy.FailoverPolicy {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	endpointsStr, ok := k8sSvc.Annotations[constants.FailoverEndpointsAnnotation]
+	if !ok {
+		return nil
+	}
+
+	endpoints, err := parseFailoverEndpoints(endpointsStr)
+	if err != nil {
+		log.Error().Err(err).Msgf("Invalid annotation value for key %q on service %s: %s", constants.FailoverEndpointsAnnotation, svc, endpointsStr)
+		return nil
+	}
+
+	policy := &trafficpolicy.FailoverPolicy{
+		Endpoints: endpoints,
+	}
+
+	if thresholdStr, ok := k8sSvc.Annotations[constants.FailoverThresholdAnnotation]; ok {
+		threshold, err := strconv.ParseUint(strings.TrimSpace(thresholdStr), 10, 32)
+		if err != nil || threshold == 0 || threshold > 100 {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on service %s: %s", constants.FailoverThresholdAnnotation, svc, thresholdStr)
+			return nil
+		}
+		policy.Threshold = uint32(threshold)
+	}
+
+	return policy
+}
+
+// parseFailoverEndpoints parses a comma separated list of <IP>:<port> backup endpoints
+func parseFailoverEndpoints(endpointsStr string) ([]endpoint.Endpoint, error) {
+	var endpoints []endpoint.Endpoint
+	for _, endpointStr := range strings.Split(endpointsStr, ",") {
+		host, portStr, err := net.SplitHostPort(strings.TrimSpace(endpointStr))
+		if err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return nil, errors.Errorf("%q is not an IP address", host)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			return nil, errors.Errorf("%q is not a valid port", portStr)
+		}
+		endpoints = append(endpoints, endpoint.Endpoint{
+			IP:   ip,
+			Port: endpoint.Port(port),
+		})
+	}
+	return endpoints, nil
+}
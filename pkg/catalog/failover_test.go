@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetFailoverPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPolicy *trafficpolicy.FailoverPolicy
+	}{
+		{
+			name:           "service without annotation",
+			annotations:    nil,
+			expectedPolicy: nil,
+		},
+		{
+			name:        "backup endpoints with the default threshold",
+			annotations: map[string]string{constants.FailoverEndpointsAnnotation: "203.0.113.10:80, 203.0.113.11:8080"},
+			expectedPolicy: &trafficpolicy.FailoverPolicy{
+				Endpoints: []endpoint.Endpoint{
+					{IP: net.ParseIP("203.0.113.10"), Port: 80},
+					{IP: net.ParseIP("203.0.113.11"), Port: 8080},
+				},
+			},
+		},
+		{
+			name: "IPv6 backup endpoint with a threshold",
+			annotations: map[string]string{
+				constants.FailoverEndpointsAnnotation: "[2001:db8::10]:80",
+				constants.FailoverThresholdAnnotation: "50",
+			},
+			expectedPolicy: &trafficpolicy.FailoverPolicy{
+				Endpoints: []endpoint.Endpoint{
+					{IP: net.ParseIP("2001:db8::10"), Port: 80},
+				},
+				Threshold: 50,
+			},
+		},
+		{
+			name:           "backup endpoint without port",
+			annotations:    map[string]string{constants.FailoverEndpointsAnnotation: "203.0.113.10"},
+			expectedPolicy: nil,
+		},
+		{
+			name:           "backup endpoint with a hostname",
+			annotations:    map[string]string{constants.FailoverEndpointsAnnotation: "bookstore.example.com:80"},
+			expectedPolicy: nil,
+		},
+		{
+			name:           "backup endpoint with an invalid port",
+			annotations:    map[string]string{constants.FailoverEndpointsAnnotation: "203.0.113.10:70000"},
+			expectedPolicy: nil,
+		},
+		{
+			name: "threshold above 100",
+			annotations: map[string]string{
+				constants.FailoverEndpointsAnnotation: "203.0.113.10:80",
+				constants.FailoverThresholdAnnotation: "150",
+			},
+			expectedPolicy: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetFailoverPolicy(svc))
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/endpoint/providers/kube"
 	"github.com/openservicemesh/osm/pkg/ingress"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -27,11 +28,13 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface) *MeshCatalog {
 		mockCtrl           *gomock.Controller
 		mockKubeController *k8s.MockController
 		mockIngressMonitor *ingress.MockMonitor
+		mockPolicyMonitor  *policy.MockMonitor
 	)
 
 	mockCtrl = gomock.NewController(ginkgo.GinkgoT())
 	mockKubeController = k8s.NewMockController(mockCtrl)
 	mockIngressMonitor = ingress.NewMockMonitor(mockCtrl)
+	mockPolicyMonitor = policy.NewMockMonitor(mockCtrl)
 
 	meshSpec := smi.NewFakeMeshSpecClient()
 
@@ -46,7 +49,14 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface) *MeshCatalog {
 
 	certManager := tresor.NewFakeCertManager(cfg)
 
-	mockIngressMonitor.EXPECT().GetIngressResources(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockIngressMonitor.EXPECT().GetIngressBackend(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockIngressMonitor.EXPECT().GetHTTPRoutes(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().ListRetryPoliciesForSourceIdentity(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().GetUpstreamTrafficSetting(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
 
 	// #1683 tracks potential improvements to the following dynamic mocks
 	mockKubeController.EXPECT().ListServices().DoAndReturn(func() []*corev1.Service {
@@ -61,6 +71,15 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface) *MeshCatalog {
 
 		return services
 	}).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace(gomock.Any()).DoAndReturn(func(ns string) *corev1.Namespace {
+		// play pretend this call queries a controller cache
+		vv, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+
+		return vv
+	}).AnyTimes()
 	mockKubeController.EXPECT().ListServiceAccounts().DoAndReturn(func() []*corev1.ServiceAccount {
 		// play pretend this call queries a controller cache
 		var serviceAccounts []*corev1.ServiceAccount
@@ -95,6 +114,8 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface) *MeshCatalog {
 		return podRet
 	}).AnyTimes()
 
+	mockKubeController.EXPECT().GetServiceInternalTrafficPolicy(gomock.Any()).Return(k8s.ServiceInternalTrafficPolicyCluster).AnyTimes()
+	mockKubeController.EXPECT().GetEndpoints(gomock.Any()).DoAndReturn(getFakeEndpoints).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV1Service.Namespace).Return(true).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV2Service.Namespace).Return(true).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookbuyerService.Namespace).Return(true).AnyTimes()
@@ -104,7 +125,7 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface) *MeshCatalog {
 	mockKubeController.EXPECT().ListServiceAccountsForService(tests.BookbuyerService).Return([]service.K8sServiceAccount{tests.BookbuyerServiceAccount}, nil).AnyTimes()
 
 	return NewMeshCatalog(mockKubeController, kubeClient, meshSpec, certManager,
-		mockIngressMonitor, stop, cfg, endpointProviders...)
+		mockIngressMonitor, mockPolicyMonitor, stop, cfg, endpointProviders...)
 }
 
 func newFakeMeshCatalog() *MeshCatalog {
@@ -112,11 +133,16 @@ func newFakeMeshCatalog() *MeshCatalog {
 		mockCtrl           *gomock.Controller
 		mockKubeController *k8s.MockController
 		mockIngressMonitor *ingress.MockMonitor
+		mockPolicyMonitor  *policy.MockMonitor
 	)
 
 	mockCtrl = gomock.NewController(ginkgo.GinkgoT())
 	mockKubeController = k8s.NewMockController(mockCtrl)
 	mockIngressMonitor = ingress.NewMockMonitor(mockCtrl)
+	mockPolicyMonitor = policy.NewMockMonitor(mockCtrl)
+	mockPolicyMonitor.EXPECT().ListRetryPoliciesForSourceIdentity(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().GetUpstreamTrafficSetting(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
 
 	meshSpec := smi.NewFakeMeshSpecClient()
 
@@ -192,6 +218,15 @@ func newFakeMeshCatalog() *MeshCatalog {
 
 		return services
 	}).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace(gomock.Any()).DoAndReturn(func(ns string) *corev1.Namespace {
+		// simulate lookup on controller cache
+		vv, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+
+		return vv
+	}).AnyTimes()
 	mockKubeController.EXPECT().GetService(gomock.Any()).DoAndReturn(func(msh service.MeshService) *corev1.Service {
 		// simulate lookup on controller cache
 		vv, err := kubeClient.CoreV1().Services(msh.Namespace).Get(context.TODO(), msh.Name, metav1.GetOptions{})
@@ -202,6 +237,8 @@ func newFakeMeshCatalog() *MeshCatalog {
 
 		return vv
 	}).AnyTimes()
+	mockKubeController.EXPECT().GetServiceInternalTrafficPolicy(gomock.Any()).Return(k8s.ServiceInternalTrafficPolicyCluster).AnyTimes()
+	mockKubeController.EXPECT().GetEndpoints(gomock.Any()).DoAndReturn(getFakeEndpoints).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV1Service.Namespace).Return(true).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV2Service.Namespace).Return(true).AnyTimes()
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookbuyerService.Namespace).Return(true).AnyTimes()
@@ -209,5 +246,15 @@ func newFakeMeshCatalog() *MeshCatalog {
 	mockKubeController.EXPECT().ListMonitoredNamespaces().Return(listExpectedNs, nil).AnyTimes()
 
 	return NewMeshCatalog(mockKubeController, kubeClient, meshSpec, certManager,
-		mockIngressMonitor, stop, cfg, endpointProviders...)
+		mockIngressMonitor, mockPolicyMonitor, stop, cfg, endpointProviders...)
+}
+
+// getFakeEndpoints returns the endpoints of the given service fixture, resolving its named target port to the service port
+func getFakeEndpoints(msh service.MeshService) (*corev1.Endpoints, error) {
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: msh.Name, Namespace: msh.Namespace},
+		Subsets: []corev1.EndpointSubset{{
+			Ports: []corev1.EndpointPort{{Name: "servicePort", Port: tests.ServicePort}},
+		}},
+	}, nil
 }
@@ -0,0 +1,125 @@
+// +build gofuzz
+
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/golang/mock/gomock"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/ingress"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests/fuzz"
+)
+
+const (
+	// maxFuzzIngresses is the max number of ingress resources derived from fuzzed data
+	maxFuzzIngresses = 8
+
+	// maxFuzzIngressRules is the max number of rules of an ingress resource derived from fuzzed data
+	maxFuzzIngressRules = 64
+
+	// maxFuzzIngressPaths is the max number of paths of an ingress rule derived from fuzzed data
+	maxFuzzIngressPaths = 256
+
+	// maxFuzzStringLen is the max length of a string derived from fuzzed data
+	maxFuzzStringLen = 64
+)
+
+// fuzzPathTypes are the path types of ingress paths derived from fuzzed data, including an invalid path type
+var fuzzPathTypes = []networkingV1beta1.PathType{
+	networkingV1beta1.PathTypeExact,
+	networkingV1beta1.PathTypePrefix,
+	networkingV1beta1.PathTypeImplementationSpecific,
+	"Invalid",
+}
+
+// fuzzReporter reports the failures of the mocks used by fuzz targets by panicking, which the fuzzer reports as a crash
+type fuzzReporter struct{}
+
+func (fuzzReporter) Errorf(format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func (fuzzReporter) Fatalf(format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+// FuzzGetIngressPoliciesForService fuzzes the computation of the inbound traffic policies of a service from ingress
+// resources, with malformed paths, unusual hosts and large numbers of rules
+func FuzzGetIngressPoliciesForService(data []byte) int {
+	c := fuzz.NewConsumer(data)
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	var ingresses []*networkingV1beta1.Ingress
+	for i := c.Intn(maxFuzzIngresses + 1); i > 0; i-- {
+		ingresses = append(ingresses, newFuzzIngress(c, svc))
+	}
+
+	mockCtrl := gomock.NewController(fuzzReporter{})
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(ingresses, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(nil, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(nil, nil).Times(1)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().GetService(svc).Return(nil).AnyTimes()
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	mc := &MeshCatalog{
+		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
+	}
+
+	policies, err := mc.GetIngressPoliciesForService(svc)
+	if err != nil || len(policies) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// newFuzzIngress returns an ingress resource derived from fuzzed data, whose backends are mostly the given service
+func newFuzzIngress(c *fuzz.Consumer, svc service.MeshService) *networkingV1beta1.Ingress {
+	ingress := &networkingV1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.String(maxFuzzStringLen),
+			Namespace: svc.Namespace,
+		},
+	}
+
+	if c.Bool() {
+		ingress.Spec.Backend = &networkingV1beta1.IngressBackend{ServiceName: c.Pick(maxFuzzStringLen, svc.Name)}
+	}
+
+	for i := c.Intn(maxFuzzIngressRules + 1); i > 0; i-- {
+		rule := networkingV1beta1.IngressRule{Host: c.Pick(maxFuzzStringLen, fuzz.Hosts...)}
+		// Rules may not specify HTTP paths
+		if c.Bool() {
+			rule.HTTP = &networkingV1beta1.HTTPIngressRuleValue{}
+			for j := c.Intn(maxFuzzIngressPaths + 1); j > 0; j-- {
+				path := networkingV1beta1.HTTPIngressPath{
+					Path:    c.Pick(maxFuzzStringLen, fuzz.Paths...),
+					Backend: networkingV1beta1.IngressBackend{ServiceName: c.Pick(maxFuzzStringLen, svc.Name)},
+				}
+				// Paths may not specify a path type
+				if c.Bool() {
+					pathType := fuzzPathTypes[c.Intn(len(fuzzPathTypes))]
+					path.PathType = &pathType
+				}
+				rule.HTTP.Paths = append(rule.HTTP.Paths, path)
+			}
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, rule)
+	}
+
+	return ingress
+}
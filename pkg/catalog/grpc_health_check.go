@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	set "github.com/deckarep/golang-set"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// grpcHealthCheckPath is the path of the requests of the gRPC health checking protocol
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// grpcHealthCheckRouteMatch is the HTTP route match of the requests of the gRPC health checking protocol
+var grpcHealthCheckRouteMatch = trafficpolicy.HTTPRouteMatch{
+	Path:          grpcHealthCheckPath,
+	PathMatchType: trafficpolicy.PathMatchExact,
+	Methods:       []string{constants.WildcardHTTPMethod},
+}
+
+// GetGRPCHealthCheckPolicy returns the active health checks of the endpoints of the given service with the gRPC health
+// checking protocol, as set by the gRPC health check annotations of the service. Nil is returned if the service is not
+// health checked.
+func (mc *MeshCatalog) GetGRPCHealthCheckPolicy(svc service.MeshService) *trafficpolicy.GRPCHealthCheckPolicy {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	serviceName, ok := k8sSvc.Annotations[constants.GRPCHealthCheckAnnotation]
+	if !ok {
+		return nil
+	}
+
+	policy := &trafficpolicy.GRPCHealthCheckPolicy{
+		ServiceName: strings.TrimSpace(serviceName),
+		Authority:   fmt.Sprintf("%s.%s", svc.Name, svc.Namespace),
+	}
+
+	if gate, ok := k8sSvc.Annotations[constants.GRPCHealthCheckGateAnnotation]; ok {
+		gateNewEndpoints, err := strconv.ParseBool(strings.TrimSpace(gate))
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on service %s: %s", constants.GRPCHealthCheckGateAnnotation, svc, gate)
+		}
+		policy.GateNewEndpoints = gateNewEndpoints
+	}
+
+	return policy
+}
+
+// addGRPCHealthCheckRules allows the gRPC health checks of the downstream proxies to reach the given upstream services
+// that are health checked, regardless of the routes allowed by SMI Traffic Targets, by adding a rule for the health
+// check requests to their inbound traffic policies
+func (mc *MeshCatalog) addGRPCHealthCheckRules(inboundPolicies []*trafficpolicy.InboundTrafficPolicy, upstreamServices []service.MeshService) {
+	for _, upstreamSvc := range upstreamServices {
+		if mc.GetGRPCHealthCheckPolicy(upstreamSvc) == nil {
+			continue
+		}
+
+		policyName := buildPolicyName(upstreamSvc, false)
+		for _, policy := range inboundPolicies {
+			if policy.Name != policyName {
+				continue
+			}
+			route := *trafficpolicy.NewRouteWeightedCluster(grpcHealthCheckRouteMatch, []service.WeightedCluster{getDefaultWeightedClusterForService(upstreamSvc)})
+			// The health check rule goes first, so that the health check requests are not matched by the rules of
+			// broader routes only allowed for some downstream service accounts
+			policy.Rules = append([]*trafficpolicy.Rule{{
+				Route:                  route,
+				AllowedServiceAccounts: set.NewSet(wildcardServiceAccount),
+			}}, policy.Rules...)
+		}
+	}
+}
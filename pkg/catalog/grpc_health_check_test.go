@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"testing"
+
+	set "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func newGRPCHealthCheckedService(svc service.MeshService, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        svc.Name,
+			Namespace:   svc.Namespace,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestGetGRPCHealthCheckPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPolicy *trafficpolicy.GRPCHealthCheckPolicy
+	}{
+		{
+			name:           "service without annotation",
+			annotations:    nil,
+			expectedPolicy: nil,
+		},
+		{
+			name:        "overall server health",
+			annotations: map[string]string{constants.GRPCHealthCheckAnnotation: ""},
+			expectedPolicy: &trafficpolicy.GRPCHealthCheckPolicy{
+				Authority: "bookstore.bookstore-ns",
+			},
+		},
+		{
+			name: "gRPC service health with new endpoints gated",
+			annotations: map[string]string{
+				constants.GRPCHealthCheckAnnotation:     "bookstore.v1.Bookstore",
+				constants.GRPCHealthCheckGateAnnotation: "true",
+			},
+			expectedPolicy: &trafficpolicy.GRPCHealthCheckPolicy{
+				ServiceName:      "bookstore.v1.Bookstore",
+				Authority:        "bookstore.bookstore-ns",
+				GateNewEndpoints: true,
+			},
+		},
+		{
+			name: "invalid gate",
+			annotations: map[string]string{
+				constants.GRPCHealthCheckAnnotation:     "bookstore.v1.Bookstore",
+				constants.GRPCHealthCheckGateAnnotation: "always",
+			},
+			expectedPolicy: &trafficpolicy.GRPCHealthCheckPolicy{
+				ServiceName: "bookstore.v1.Bookstore",
+				Authority:   "bookstore.bookstore-ns",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(newGRPCHealthCheckedService(svc, tc.annotations)).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetGRPCHealthCheckPolicy(svc))
+		})
+	}
+}
+
+func TestAddGRPCHealthCheckRules(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{kubeController: mockKubeController}
+
+	checked := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	unchecked := service.MeshService{Name: "bookbuyer", Namespace: "bookstore-ns"}
+	mockKubeController.EXPECT().GetService(checked).Return(newGRPCHealthCheckedService(checked, map[string]string{constants.GRPCHealthCheckAnnotation: ""})).Times(1)
+	mockKubeController.EXPECT().GetService(unchecked).Return(newGRPCHealthCheckedService(unchecked, nil)).Times(1)
+
+	booksRoute := trafficpolicy.HTTPRouteMatch{
+		Path:          "/books",
+		PathMatchType: trafficpolicy.PathMatchRegex,
+		Methods:       []string{"GET"},
+	}
+	checkedPolicy := trafficpolicy.NewInboundTrafficPolicy("bookstore.bookstore-ns", []string{"bookstore"})
+	checkedPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(booksRoute, []service.WeightedCluster{getDefaultWeightedClusterForService(checked)}), service.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer-ns"})
+	uncheckedPolicy := trafficpolicy.NewInboundTrafficPolicy("bookbuyer.bookstore-ns", []string{"bookbuyer"})
+	uncheckedPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(booksRoute, []service.WeightedCluster{getDefaultWeightedClusterForService(unchecked)}), service.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer-ns"})
+
+	mc.addGRPCHealthCheckRules([]*trafficpolicy.InboundTrafficPolicy{checkedPolicy, uncheckedPolicy}, []service.MeshService{checked, unchecked})
+
+	// The health check rule is the first rule of the health checked service, and allows every downstream service account
+	assert.Len(checkedPolicy.Rules, 2)
+	assert.Equal(grpcHealthCheckRouteMatch, checkedPolicy.Rules[0].Route.HTTPRouteMatch)
+	assert.Equal(set.NewSet(getDefaultWeightedClusterForService(checked)), checkedPolicy.Rules[0].Route.WeightedClusters)
+	assert.Equal(set.NewSet(wildcardServiceAccount), checkedPolicy.Rules[0].AllowedServiceAccounts)
+	assert.Equal(booksRoute, checkedPolicy.Rules[1].Route.HTTPRouteMatch)
+
+	assert.Len(uncheckedPolicy.Rules, 1)
+}
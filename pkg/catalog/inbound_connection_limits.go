@@ -0,0 +1,18 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetInboundConnectionPolicy returns the limits of the connections the sidecar proxy of the given service opens to the
+// service, set by the annotations of the namespace of the service, overridden by the annotations of the service. The
+// connections are not limited by default.
+func (mc *MeshCatalog) GetInboundConnectionPolicy(svc service.MeshService) trafficpolicy.InboundConnectionPolicy {
+	overrides := mc.getServiceOverrides(svc)
+	return trafficpolicy.InboundConnectionPolicy{
+		MaxConnections:           overrides.Uint32(constants.InboundMaxConnectionsAnnotation, 0, 1),
+		MaxRequestsPerConnection: overrides.Uint32(constants.InboundMaxRequestsPerConnectionAnnotation, 0, 1),
+	}
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetInboundConnectionPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name                 string
+		namespaceAnnotations map[string]string
+		annotations          map[string]string
+		expectedPolicy       trafficpolicy.InboundConnectionPolicy
+	}{
+		{
+			name:           "connections not limited without annotations",
+			annotations:    nil,
+			expectedPolicy: trafficpolicy.InboundConnectionPolicy{},
+		},
+		{
+			name: "connections limited by annotations",
+			annotations: map[string]string{
+				constants.InboundMaxConnectionsAnnotation:           "10",
+				constants.InboundMaxRequestsPerConnectionAnnotation: "1",
+			},
+			expectedPolicy: trafficpolicy.InboundConnectionPolicy{
+				MaxConnections:           10,
+				MaxRequestsPerConnection: 1,
+			},
+		},
+		{
+			name: "invalid annotations are ignored",
+			annotations: map[string]string{
+				constants.InboundMaxConnectionsAnnotation:           "-1",
+				constants.InboundMaxRequestsPerConnectionAnnotation: "0",
+			},
+			expectedPolicy: trafficpolicy.InboundConnectionPolicy{},
+		},
+		{
+			name: "namespace annotations overridden by service annotations",
+			namespaceAnnotations: map[string]string{
+				constants.InboundMaxConnectionsAnnotation:           "100",
+				constants.InboundMaxRequestsPerConnectionAnnotation: "1",
+			},
+			annotations: map[string]string{
+				constants.InboundMaxConnectionsAnnotation: "20",
+			},
+			expectedPolicy: trafficpolicy.InboundConnectionPolicy{
+				MaxConnections:           20,
+				MaxRequestsPerConnection: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{
+				kubeController: mockKubeController,
+			}
+
+			mockKubeController.EXPECT().GetNamespace(svc.Namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Namespace,
+					Annotations: tc.namespaceAnnotations,
+				},
+			}).Times(1)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetInboundConnectionPolicy(svc))
+		})
+	}
+}
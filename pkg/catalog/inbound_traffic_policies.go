@@ -10,28 +10,48 @@ import (
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
-// ListInboundTrafficPolicies returns all inbound traffic policies
-// 1. from service discovery for permissive mode
-// 2. for the given service account and upstream services from SMI Traffic Target and Traffic Split
+// ListInboundTrafficPolicies returns all inbound traffic policies related to the given service account and upstream
+// services, compiled from the policy set returned by ListInboundPolicySet
 func (mc *MeshCatalog) ListInboundTrafficPolicies(upstreamIdentity service.K8sServiceAccount, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
+	return mc.ListInboundPolicySet(upstreamIdentity, upstreamServices).Policies()
+}
+
+// ListInboundPolicySet returns the set of the inbound traffic policies built for the given service account and
+// upstream services from each of their sources, not compiled yet for the policies of other sources to be added to it
+// 1. from service discovery for permissive mode
+// 2. from SMI Traffic Target and Traffic Split
+// The policies of the upstream services that are shadow services of dark launches also accept the copied requests, and
+// in SMI mode the policies of the upstream services health checked with gRPC also accept the health check requests.
+func (mc *MeshCatalog) ListInboundPolicySet(upstreamIdentity service.K8sServiceAccount, upstreamServices []service.MeshService) *trafficpolicy.InboundPolicySet {
+	policies := trafficpolicy.NewInboundPolicySet()
 	if mc.configurator.IsPermissiveTrafficPolicyMode() {
-		inboundPolicies := []*trafficpolicy.InboundTrafficPolicy{}
+		permissivePolicies := []*trafficpolicy.InboundTrafficPolicy{}
 		for _, svc := range upstreamServices {
-			inboundPolicies = trafficpolicy.MergeInboundPolicies(false, inboundPolicies, mc.buildInboundPermissiveModePolicies(svc)...)
+			permissivePolicies = append(permissivePolicies, mc.buildInboundPermissiveModePolicies(svc)...)
 		}
-		return inboundPolicies
+		mc.addDarkLaunchShadowHostnames(permissivePolicies, upstreamServices)
+		policies.Add(trafficpolicy.PermissiveSource, permissivePolicies...)
+		return policies
 	}
 
-	inbound := mc.listInboundPoliciesFromTrafficTargets(upstreamIdentity, upstreamServices)
-	inboundPoliciesFRomSplits := mc.listInboundPoliciesForTrafficSplits(upstreamIdentity, upstreamServices)
-	inbound = trafficpolicy.MergeInboundPolicies(false, inbound, inboundPoliciesFRomSplits...)
-	return inbound
+	// The shadow hostnames and health check rules are added to the policies of each source before they are compiled,
+	// for the policies of the same service to keep the same hostnames and be merged together
+	trafficTargetPolicies := mc.listInboundPoliciesFromTrafficTargets(upstreamIdentity, upstreamServices)
+	trafficSplitPolicies := mc.listInboundPoliciesForTrafficSplits(upstreamIdentity, upstreamServices)
+	for _, sourcePolicies := range [][]*trafficpolicy.InboundTrafficPolicy{trafficTargetPolicies, trafficSplitPolicies} {
+		mc.addDarkLaunchShadowHostnames(sourcePolicies, upstreamServices)
+		mc.addGRPCHealthCheckRules(sourcePolicies, upstreamServices)
+	}
+	policies.Add(trafficpolicy.TrafficTargetSource, trafficTargetPolicies...)
+	policies.Add(trafficpolicy.TrafficSplitSource, trafficSplitPolicies...)
+	return policies
 }
 
 // listInboundPoliciesFromTrafficTargets builds inbound traffic policies for all inbound services
-// when the given service account matches a destination in the Traffic Target resource
+// when the given service account matches a destination in the Traffic Target resource. The policies are not merged,
+// they are compiled with the policies of the other sources.
 func (mc *MeshCatalog) listInboundPoliciesFromTrafficTargets(upstreamIdentity service.K8sServiceAccount, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
-	inboundPolicies := []*trafficpolicy.InboundTrafficPolicy{}
+	policies := []*trafficpolicy.InboundTrafficPolicy{}
 
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
@@ -43,18 +63,19 @@ func (mc *MeshCatalog) listInboundPoliciesFromTrafficTargets(upstreamIdentity se
 		}
 
 		for _, svc := range upstreamServices {
-			inboundPolicies = trafficpolicy.MergeInboundPolicies(false, inboundPolicies, mc.buildInboundPolicies(t, svc)...)
+			policies = append(policies, mc.buildInboundPolicies(t, svc)...)
 		}
 	}
 
-	return inboundPolicies
+	return policies
 }
 
 // listInboundPoliciesForTrafficSplits loops through all SMI TrafficTarget resources and returns inbound policies for apex services based on the following conditions:
 // 1. the given upstream identity matches the destination specified in a TrafficTarget resource
 // 2. the given list of upstream services are backends specified in a TrafficSplit resource
+// The policies are not merged, they are compiled with the policies of the other sources.
 func (mc *MeshCatalog) listInboundPoliciesForTrafficSplits(upstreamIdentity service.K8sServiceAccount, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
-	inboundPolicies := []*trafficpolicy.InboundTrafficPolicy{}
+	policies := []*trafficpolicy.InboundTrafficPolicy{}
 
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
@@ -66,7 +87,7 @@ func (mc *MeshCatalog) listInboundPoliciesForTrafficSplits(upstreamIdentity serv
 		}
 
 		// fetch all routes referenced in traffic target
-		routeMatches, err := mc.routesFromRules(t.Spec.Rules, t.Namespace)
+		routes, err := mc.trafficSpecRoutesFromRules(t.Spec.Rules, t.Namespace)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error finding route matches from TrafficTarget %s in namespace %s", t.Name, t.Namespace)
 			continue
@@ -88,24 +109,34 @@ func (mc *MeshCatalog) listInboundPoliciesForTrafficSplits(upstreamIdentity serv
 				}
 				servicePolicy := trafficpolicy.NewInboundTrafficPolicy(buildPolicyName(apexService, apexService.Namespace == upstreamIdentity.Namespace), hostnames)
 				weightedCluster := getDefaultWeightedClusterForService(upstreamSvc)
+				// The requests to the apex service time out after its timeouts, and the responses of the backend are
+				// limited to its route bandwidth limits
+				apexTimeouts := mc.GetTimeoutPolicy(apexService)
+				routeBandwidthLimits := mc.GetBandwidthLimitForService(upstreamSvc).RouteEgressKiBps
+				addRule := servicePolicy.AddRule
+				if mc.isTrafficTargetInShadow(t) {
+					addRule = servicePolicy.AddShadowRule
+				}
 
-				for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(t.Spec.Sources) {
-					for _, routeMatch := range routeMatches {
-						servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount)
+				for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(mc.expandTrafficTargetSources(t.Spec.Sources)) {
+					for _, route := range routes {
+						inboundRoute := newTimedRoute(route.match, []service.WeightedCluster{weightedCluster}, route.timeouts, apexTimeouts)
+						inboundRoute.BandwidthLimitKiBps = routeBandwidthLimits[route.specName][route.matchName]
+						addRule(inboundRoute, sourceServiceAccount)
 					}
 				}
-				inboundPolicies = trafficpolicy.MergeInboundPolicies(false, inboundPolicies, servicePolicy)
+				policies = append(policies, servicePolicy)
 			}
 		}
 	}
-	return inboundPolicies
+	return policies
 }
 
 func (mc *MeshCatalog) buildInboundPolicies(t *access.TrafficTarget, svc service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
 	inboundPolicies := []*trafficpolicy.InboundTrafficPolicy{}
 
 	// fetch all routes referenced in traffic target
-	routeMatches, err := mc.routesFromRules(t.Spec.Rules, t.Namespace)
+	routes, err := mc.trafficSpecRoutesFromRules(t.Spec.Rules, t.Namespace)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error finding route matches from TrafficTarget %s in namespace %s", t.Name, t.Namespace)
 		return inboundPolicies
@@ -119,10 +150,19 @@ func (mc *MeshCatalog) buildInboundPolicies(t *access.TrafficTarget, svc service
 
 	servicePolicy := trafficpolicy.NewInboundTrafficPolicy(buildPolicyName(svc, false), hostnames)
 	weightedCluster := getDefaultWeightedClusterForService(svc)
+	serviceTimeouts := mc.GetTimeoutPolicy(svc)
+	routeBandwidthLimits := mc.GetBandwidthLimitForService(svc).RouteEgressKiBps
+	// The routes of a TrafficTarget in shadow mode are allowed to any service account, restricted to its sources in shadow
+	addRule := servicePolicy.AddRule
+	if mc.isTrafficTargetInShadow(t) {
+		addRule = servicePolicy.AddShadowRule
+	}
 
-	for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(t.Spec.Sources) {
-		for _, routeMatch := range routeMatches {
-			servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount)
+	for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(mc.expandTrafficTargetSources(t.Spec.Sources)) {
+		for _, route := range routes {
+			inboundRoute := newTimedRoute(route.match, []service.WeightedCluster{weightedCluster}, route.timeouts, serviceTimeouts)
+			inboundRoute.BandwidthLimitKiBps = routeBandwidthLimits[route.specName][route.matchName]
+			addRule(inboundRoute, sourceServiceAccount)
 		}
 	}
 
@@ -147,16 +187,41 @@ func (mc *MeshCatalog) buildInboundPermissiveModePolicies(svc service.MeshServic
 
 	// Add a wildcard route to accept traffic from any service account (wildcard service account)
 	// A wildcard service account will program an RBAC policy for this rule that allows ANY downstream service account
-	servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{weightedCluster}), wildcardServiceAccount)
+	servicePolicy.AddRule(newTimedRoute(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{weightedCluster}, mc.GetTimeoutPolicy(svc)), wildcardServiceAccount)
 	inboundPolicies = append(inboundPolicies, servicePolicy)
 
 	return inboundPolicies
 }
 
+// trafficSpecRoute is an HTTP route match of an HTTPRouteGroup, with the timeouts of the requests matching it set by
+// the route timeouts annotation of the HTTPRouteGroup, or nil if they are not set. The names of the HTTPRouteGroup and
+// of the match identify the route in the route bandwidth limits of the services.
+type trafficSpecRoute struct {
+	match     trafficpolicy.HTTPRouteMatch
+	timeouts  *trafficpolicy.TimeoutPolicy
+	specName  trafficpolicy.TrafficSpecName
+	matchName trafficpolicy.TrafficSpecMatchName
+}
+
 // routesFromRules takes a set of traffic target rules and the namespace of the traffic target and returns a list of
 //	http route matches (trafficpolicy.HTTPRouteMatch)
 func (mc *MeshCatalog) routesFromRules(rules []access.TrafficTargetRule, trafficTargetNamespace string) ([]trafficpolicy.HTTPRouteMatch, error) {
+	trafficSpecRoutes, err := mc.trafficSpecRoutesFromRules(rules, trafficTargetNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	routes := []trafficpolicy.HTTPRouteMatch{}
+	for _, route := range trafficSpecRoutes {
+		routes = append(routes, route.match)
+	}
+	return routes, nil
+}
+
+// trafficSpecRoutesFromRules returns the routes of the given traffic target rules like routesFromRules, along with the
+// timeouts of the requests matching them
+func (mc *MeshCatalog) trafficSpecRoutesFromRules(rules []access.TrafficTargetRule, trafficTargetNamespace string) ([]trafficSpecRoute, error) {
+	routes := []trafficSpecRoute{}
 
 	specMatchRoute, err := mc.getHTTPPathsPerRoute() // returns map[traffic_spec_name]map[match_name]trafficpolicy.HTTPRoute
 	if err != nil {
@@ -168,12 +233,19 @@ func (mc *MeshCatalog) routesFromRules(rules []access.TrafficTargetRule, traffic
 		return routes, nil
 	}
 
+	specMatchTimeouts := mc.getRouteTimeoutsPerRoute()
 	for _, rule := range rules {
 		trafficSpecName := mc.getTrafficSpecName("HTTPRouteGroup", trafficTargetNamespace, rule.Name)
 		for _, match := range rule.Matches {
-			matchedRoute, found := specMatchRoute[trafficSpecName][trafficpolicy.TrafficSpecMatchName(match)]
+			matchName := trafficpolicy.TrafficSpecMatchName(match)
+			matchedRoute, found := specMatchRoute[trafficSpecName][matchName]
 			if found {
-				routes = append(routes, matchedRoute)
+				routes = append(routes, trafficSpecRoute{
+					match:     matchedRoute,
+					timeouts:  specMatchTimeouts[trafficSpecName][matchName],
+					specName:  trafficSpecName,
+					matchName: matchName,
+				})
 			} else {
 				log.Debug().Msgf("No matching trafficpolicy.HTTPRoute found for match name %s in Traffic Spec %s (in namespace %s)", match, trafficSpecName, trafficTargetNamespace)
 			}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
@@ -17,6 +18,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -332,13 +334,16 @@ func TestListInboundTrafficPolicies(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				configurator:       mockConfigurator,
+				policyController:   mockPolicyMonitor,
 			}
+			mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
 
 			services := []*corev1.Service{}
 			for _, meshSvc := range tc.meshServices {
@@ -355,7 +360,6 @@ func TestListInboundTrafficPolicies(t *testing.T) {
 					k8sSvcAccount := tests.NewServiceAccountFixture(sa.Name, sa.Namespace)
 					serviceAccounts = append(serviceAccounts, k8sSvcAccount)
 				}
-				mockKubeController.EXPECT().ListServices().Return(services).AnyTimes()
 				mockKubeController.EXPECT().ListServiceAccounts().Return(serviceAccounts).AnyTimes()
 			} else {
 				mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return([]*spec.HTTPRouteGroup{&tc.trafficSpec}).AnyTimes()
@@ -364,7 +368,9 @@ func TestListInboundTrafficPolicies(t *testing.T) {
 				mockMeshSpec.EXPECT().ListTrafficTargets().Return([]*access.TrafficTarget{&trafficTarget}).AnyTimes()
 			}
 
+			mockKubeController.EXPECT().ListServices().Return(services).AnyTimes()
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
+			mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
 			actual := mc.ListInboundTrafficPolicies(tc.upstreamSA, tc.upstreamServices)
 			assert.ElementsMatch(tc.expectedInboundPolicies, actual)
 		})
@@ -379,12 +385,18 @@ func TestListInboundPoliciesForTrafficSplits(t *testing.T) {
 	mockKubeController := k8s.NewMockController(mockCtrl)
 	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	mc := MeshCatalog{
 		kubeController:     mockKubeController,
 		meshSpec:           mockMeshSpec,
 		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+		policyController:   mockPolicyMonitor,
+		configurator:       mockConfigurator,
 	}
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
 
 	testCases := []struct {
 		name                    string
@@ -709,12 +721,18 @@ func TestBuildInboundPolicies(t *testing.T) {
 	mockKubeController := k8s.NewMockController(mockCtrl)
 	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	mc := MeshCatalog{
 		kubeController:     mockKubeController,
 		meshSpec:           mockMeshSpec,
 		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+		policyController:   mockPolicyMonitor,
+		configurator:       mockConfigurator,
 	}
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
 
 	testCases := []struct {
 		name                    string
@@ -989,7 +1007,7 @@ func TestBuildInboundPermissiveModePolicies(t *testing.T) {
 			k8sService := tests.NewServiceFixture(tc.meshService.Name, tc.meshService.Namespace, map[string]string{})
 
 			mockEndpointProvider.EXPECT().GetID().Return("fake").AnyTimes()
-			mockKubeController.EXPECT().GetService(tc.meshService).Return(k8sService)
+			mockKubeController.EXPECT().GetService(tc.meshService).Return(k8sService).AnyTimes()
 			actual := mc.buildInboundPermissiveModePolicies(tc.meshService)
 			assert.Len(actual, len(tc.expectedInboundPolicies))
 			assert.ElementsMatch(tc.expectedInboundPolicies, actual)
@@ -1005,12 +1023,18 @@ func TestListInboundPoliciesFromTrafficTargets(t *testing.T) {
 	mockKubeController := k8s.NewMockController(mockCtrl)
 	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	mc := MeshCatalog{
 		kubeController:     mockKubeController,
 		meshSpec:           mockMeshSpec,
 		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+		policyController:   mockPolicyMonitor,
+		configurator:       mockConfigurator,
 	}
+	mockPolicyMonitor.EXPECT().GetBandwidthLimit(gomock.Any()).Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
 
 	testCases := []struct {
 		name                    string
@@ -2,12 +2,23 @@ package catalog
 
 import (
 	"fmt"
+	"net"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
-	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	"github.com/pkg/errors"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	gatewayV1alpha1 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -23,8 +34,17 @@ const (
 
 	// commonRegexChars is a string comprising of characters commonly used in a regex
 	// It is used to guess whether a path specified appears as a regex.
-	// It is used as a fallback to match ingress paths whose PathType is set to be ImplementationSpecific.
+	// It is used to match ingress paths whose PathType is set to be ImplementationSpecific with the auto path match.
 	commonRegexChars = `^$*+[]%|`
+
+	// wildcardHostPrefix is the prefix of a wildcard host, matching any DNS label
+	wildcardHostPrefix = "*."
+
+	// authorityHeader is the name of the pseudo-header carrying the host of HTTP requests
+	authorityHeader = ":authority"
+
+	// optionalPortRegex matches the optional port of the authority of a request
+	optionalPortRegex = `(:[0-9]+)?`
 )
 
 // Ensure the regex patteren for prefix matching for path elements compiles
@@ -38,92 +58,664 @@ var wildcardServiceAccount = service.K8sServiceAccount{}
 func (mc *MeshCatalog) GetIngressPoliciesForService(svc service.MeshService) ([]*trafficpolicy.InboundTrafficPolicy, error) {
 	inboundIngressPolicies := []*trafficpolicy.InboundTrafficPolicy{}
 
-	ingresses, err := mc.ingressMonitor.GetIngressResources(svc)
+	ingressesV1beta1, err := mc.ingressMonitor.GetIngressNetworkingV1beta1(svc)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed to get ingress resources for service %s", svc)
+		log.Error().Err(err).Msgf("Failed to get networking.k8s.io/v1beta1 ingress resources for service %s", svc)
 		return inboundIngressPolicies, err
 	}
-	if len(ingresses) == 0 {
+	ingressesV1, err := mc.ingressMonitor.GetIngressNetworkingV1(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get networking.k8s.io/v1 ingress resources for service %s", svc)
+		return inboundIngressPolicies, err
+	}
+	httpRoutes, err := mc.ingressMonitor.GetHTTPRoutes(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get HTTPRoute resources for service %s", svc)
+		return inboundIngressPolicies, err
+	}
+	if len(ingressesV1beta1) == 0 && len(ingressesV1) == 0 && len(httpRoutes) == 0 {
 		log.Trace().Msgf("No ingress resources found for service %s", svc)
 		return inboundIngressPolicies, err
 	}
 
-	ingressWeightedCluster := getDefaultWeightedClusterForService(svc)
+	ingressWeightedClusters := mc.getIngressWeightedClusters(svc)
+	ingressPortClusters := mc.getIngressPortClusters(svc, ingressWeightedClusters)
+	getBackendClusters := func(port string) []service.WeightedCluster {
+		if portCluster, ok := ingressPortClusters[port]; ok {
+			return []service.WeightedCluster{portCluster}
+		}
+		return ingressWeightedClusters
+	}
 
-	for _, ingress := range ingresses {
+	policies := trafficpolicy.NewInboundPolicySet()
+	for _, ingress := range ingressesV1beta1 {
+		routeSettings := getIngressRouteSettings(ingress.ObjectMeta)
+		pathMatch := mc.getImplementationSpecificPathMatch(ingress.ObjectMeta)
 		if ingress.Spec.Backend != nil && ingress.Spec.Backend.ServiceName == svc.Name {
-			wildcardIngressPolicy := trafficpolicy.NewInboundTrafficPolicy(buildIngressPolicyName(ingress.ObjectMeta.Name, ingress.ObjectMeta.Namespace, constants.WildcardHTTPMethod), []string{constants.WildcardHTTPMethod})
-			wildcardIngressPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{ingressWeightedCluster}), wildcardServiceAccount)
-			inboundIngressPolicies = trafficpolicy.MergeInboundPolicies(false, inboundIngressPolicies, wildcardIngressPolicy)
+			policies.Add(trafficpolicy.IngressSource, buildIngressDefaultBackendPolicy(ingress.ObjectMeta, routeSettings, getBackendClusters(ingress.Spec.Backend.ServicePort.String())))
 		}
 
 		for _, rule := range ingress.Spec.Rules {
-			domain := rule.Host
-			if domain == "" {
-				domain = constants.WildcardHTTPMethod
+			// A rule without HTTP paths, such as a rule only specifying a host, has no backend to route to
+			if rule.HTTP == nil {
+				continue
+			}
+
+			host, hostRegex, err := getIngressHostMatch(ingress.ObjectMeta, rule.Host)
+			if err != nil {
+				log.Error().Err(err).Msgf("Ignoring rule for host %q in ingress resource %s/%s", rule.Host, ingress.Namespace, ingress.Name)
+				continue
 			}
-			ingressPolicy := trafficpolicy.NewInboundTrafficPolicy(buildIngressPolicyName(ingress.ObjectMeta.Name, ingress.ObjectMeta.Namespace, domain), []string{domain})
+			ingressPolicy := newIngressRulePolicy(ingress.ObjectMeta, host)
 
 			for _, ingressPath := range rule.HTTP.Paths {
 				if ingressPath.Backend.ServiceName != svc.Name {
 					continue
 				}
 
-				httpRouteMatch := trafficpolicy.HTTPRouteMatch{
-					Methods: []string{constants.WildcardHTTPMethod},
+				// Default ingress path type to PathTypeImplementationSpecific if unspecified
+				pathType := networkingV1.PathTypeImplementationSpecific
+				if ingressPath.PathType != nil {
+					pathType = networkingV1.PathType(*ingressPath.PathType)
+				}
+
+				httpRouteMatch, err := getIngressRouteMatch(ingressPath.Path, pathType, pathMatch)
+				if err != nil {
+					log.Error().Err(err).Msgf("Ignoring path %s in ingress resource %s/%s", ingressPath.Path, ingress.Namespace, ingress.Name)
+					continue
+				}
+				httpRouteMatch.PathRewrite, err = getIngressPathRewrite(ingress.ObjectMeta, ingressPath.Path, pathType, pathMatch)
+				if err != nil {
+					log.Error().Err(err).Msgf("Ignoring path %s in ingress resource %s/%s", ingressPath.Path, ingress.Namespace, ingress.Name)
+					continue
+				}
+				httpRouteMatch = withHostMatch(httpRouteMatch, hostRegex)
+				ingressPolicy.AddRule(routeSettings.newRoute(httpRouteMatch, getBackendClusters(ingressPath.Backend.ServicePort.String())), wildcardServiceAccount)
+			}
+
+			// Only create an ingress policy if the ingress policy resulted in valid rules
+			if len(ingressPolicy.Rules) > 0 {
+				policies.Add(trafficpolicy.IngressSource, ingressPolicy)
+			}
+		}
+	}
+
+	for _, ingress := range ingressesV1 {
+		routeSettings := getIngressRouteSettings(ingress.ObjectMeta)
+		pathMatch := mc.getImplementationSpecificPathMatch(ingress.ObjectMeta)
+		if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil && backend.Service.Name == svc.Name {
+			policies.Add(trafficpolicy.IngressSource, buildIngressDefaultBackendPolicy(ingress.ObjectMeta, routeSettings, getBackendClusters(getIngressServiceBackendPort(backend.Service.Port))))
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			// A rule without HTTP paths, such as a rule only specifying a host, has no backend to route to
+			if rule.HTTP == nil {
+				continue
+			}
+
+			host, hostRegex, err := getIngressHostMatch(ingress.ObjectMeta, rule.Host)
+			if err != nil {
+				log.Error().Err(err).Msgf("Ignoring rule for host %q in ingress resource %s/%s", rule.Host, ingress.Namespace, ingress.Name)
+				continue
+			}
+			ingressPolicy := newIngressRulePolicy(ingress.ObjectMeta, host)
+
+			for _, ingressPath := range rule.HTTP.Paths {
+				// A backend may reference a resource other than a service, such as a storage bucket
+				if ingressPath.Backend.Service == nil || ingressPath.Backend.Service.Name != svc.Name {
+					continue
 				}
 
 				// Default ingress path type to PathTypeImplementationSpecific if unspecified
-				pathType := networkingV1beta1.PathTypeImplementationSpecific
+				pathType := networkingV1.PathTypeImplementationSpecific
 				if ingressPath.PathType != nil {
 					pathType = *ingressPath.PathType
 				}
 
-				switch pathType {
-				case networkingV1beta1.PathTypeExact:
-					// Exact match
-					// Request /foo matches path /foo, not /foobar or /foo/bar
-					httpRouteMatch.Path = ingressPath.Path
-					httpRouteMatch.PathMatchType = trafficpolicy.PathMatchExact
-
-				case networkingV1beta1.PathTypePrefix:
-					// Element wise prefix match
-					// Request /foo matches path /foo and /foo/bar, not /foobar
-					httpRouteMatch.Path = ingressPath.Path + prefixMatchPathElementsRegex
-					httpRouteMatch.PathMatchType = trafficpolicy.PathMatchRegex
-
-				case networkingV1beta1.PathTypeImplementationSpecific:
-					httpRouteMatch.Path = ingressPath.Path
-					// If the path looks like a regex, use regex matching.
-					// Else use string based prefix matching.
-					if strings.ContainsAny(ingressPath.Path, commonRegexChars) {
-						// Path contains regex characters, use regex matching for the path
-						// Request /foo/bar matches path /foo.*
-						httpRouteMatch.PathMatchType = trafficpolicy.PathMatchRegex
-					} else {
-						// String based prefix path matching
-						// Request /foo matches /foo/bar and /foobar
-						httpRouteMatch.PathMatchType = trafficpolicy.PathMatchPrefix
-					}
-
-				default:
-					log.Error().Msgf("Invalid pathType=%s unspecified for path %s in ingress resource %s/%s, ignoring this path", *ingressPath.PathType, ingressPath.Path, ingress.Namespace, ingress.Name)
+				httpRouteMatch, err := getIngressRouteMatch(ingressPath.Path, pathType, pathMatch)
+				if err != nil {
+					log.Error().Err(err).Msgf("Ignoring path %s in ingress resource %s/%s", ingressPath.Path, ingress.Namespace, ingress.Name)
 					continue
 				}
-
-				ingressPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(httpRouteMatch, []service.WeightedCluster{ingressWeightedCluster}), wildcardServiceAccount)
+				httpRouteMatch.PathRewrite, err = getIngressPathRewrite(ingress.ObjectMeta, ingressPath.Path, pathType, pathMatch)
+				if err != nil {
+					log.Error().Err(err).Msgf("Ignoring path %s in ingress resource %s/%s", ingressPath.Path, ingress.Namespace, ingress.Name)
+					continue
+				}
+				httpRouteMatch = withHostMatch(httpRouteMatch, hostRegex)
+				ingressPolicy.AddRule(routeSettings.newRoute(httpRouteMatch, getBackendClusters(getIngressServiceBackendPort(ingressPath.Backend.Service.Port))), wildcardServiceAccount)
 			}
 
 			// Only create an ingress policy if the ingress policy resulted in valid rules
 			if len(ingressPolicy.Rules) > 0 {
-				inboundIngressPolicies = trafficpolicy.MergeInboundPolicies(false, inboundIngressPolicies, ingressPolicy)
+				policies.Add(trafficpolicy.IngressSource, ingressPolicy)
+			}
+		}
+	}
+
+	for _, httpRoute := range httpRoutes {
+		policies.Add(trafficpolicy.IngressSource, buildHTTPRoutePolicies(httpRoute, svc, mc.getImplementationSpecificPathMatch(httpRoute.ObjectMeta), getBackendClusters)...)
+	}
+	return policies.Policies(), nil
+}
+
+// getIngressWeightedClusters returns the weighted clusters the requests received from ingress for the given service are
+// routed to. When the service is the apex service of an SMI TrafficSplit, the requests are split between the backends of
+// the first TrafficSplit of the service by their weights, consistently with the outbound traffic policies, so that canary
+// rollouts also apply to the traffic entering the mesh through ingress.
+func (mc *MeshCatalog) getIngressWeightedClusters(svc service.MeshService) []service.WeightedCluster {
+	for _, split := range mc.meshSpec.ListTrafficSplits() {
+		apexService := service.MeshService{
+			Name:      kubernetes.GetServiceFromHostname(split.Spec.Service),
+			Namespace: split.Namespace,
+		}
+		if !apexService.Equals(svc) {
+			continue
+		}
+
+		var weightedClusters []service.WeightedCluster
+		for _, backend := range split.Spec.Backends {
+			// A backend without weight does not receive any request
+			if backend.Weight <= 0 {
+				continue
+			}
+			backendService := service.MeshService{Name: backend.Service, Namespace: split.Namespace}
+			weightedClusters = append(weightedClusters, service.WeightedCluster{
+				ClusterName: service.ClusterName(backendService.String()),
+				Weight:      backend.Weight,
+			})
+		}
+		if len(weightedClusters) > 0 {
+			return weightedClusters
+		}
+		log.Warn().Msgf("TrafficSplit %s/%s has no backend with a weight, routing the requests received from ingress to service %s", split.Namespace, split.Name, svc)
+		break
+	}
+	return []service.WeightedCluster{getDefaultWeightedClusterForService(svc)}
+}
+
+// getIngressPortClusters returns the clusters of the target ports of the given service, by the number and the name of
+// the ports of the service forwarding traffic to them, so that the requests received from ingress for a port of a
+// service with multiple target ports are only routed to the target port of this port. The requests for a service
+// with a single target port, or split between the backends of a TrafficSplit, are routed to the given weighted clusters.
+func (mc *MeshCatalog) getIngressPortClusters(svc service.MeshService, ingressWeightedClusters []service.WeightedCluster) map[string]service.WeightedCluster {
+	if len(ingressWeightedClusters) != 1 || ingressWeightedClusters[0] != getDefaultWeightedClusterForService(svc) {
+		return nil
+	}
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	portClusters := make(map[string]service.WeightedCluster)
+	targetPorts := make(map[uint32]bool)
+	for _, portSpec := range k8sSvc.Spec.Ports {
+		targetPort, err := mc.getTargetPort(svc, portSpec)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error resolving target port of port %d of service %s, routing the requests received from ingress for it to all the ports of the service", portSpec.Port, svc)
+			continue
+		}
+		targetPorts[targetPort] = true
+		portCluster := service.WeightedCluster{
+			ClusterName: service.ClusterName(envoy.GetClusterNameForPort(targetPort)),
+			Weight:      constants.ClusterWeightAcceptAll,
+		}
+		portClusters[strconv.Itoa(int(portSpec.Port))] = portCluster
+		if portSpec.Name != "" {
+			portClusters[portSpec.Name] = portCluster
+		}
+	}
+	if len(targetPorts) < 2 {
+		return nil
+	}
+	return portClusters
+}
+
+// getIngressServiceBackendPort returns the port of the service referenced by a networking.k8s.io/v1 ingress backend,
+// by number or by name
+func getIngressServiceBackendPort(port networkingV1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return strconv.Itoa(int(port.Number))
+}
+
+// buildHTTPRoutePolicies returns the policies routing the requests received from ingress for the hostnames of the
+// given Gateway API HTTPRoute to the given service, for the rules of the route forwarding requests to the service.
+// The requests are split between the backends of a rule by the Gateway, so the weights of the backends do not apply
+// to the policies of the service. The requests matching a rule are routed to the clusters returned by getBackendClusters
+// for the port of the service the rule forwards requests to. The ImplementationSpecific paths of the route are matched
+// with the given path match.
+func buildHTTPRoutePolicies(httpRoute *gatewayV1alpha1.HTTPRoute, svc service.MeshService, pathMatch string, getBackendClusters func(port string) []service.WeightedCluster) []*trafficpolicy.InboundTrafficPolicy {
+	var routes []trafficpolicy.RouteWeightedClusters
+	for _, rule := range httpRoute.Spec.Rules {
+		var backendClusters []service.WeightedCluster
+		for _, forwardTo := range rule.ForwardTo {
+			if forwardTo.ServiceName != nil && *forwardTo.ServiceName == svc.Name {
+				var port string
+				if forwardTo.Port != nil {
+					port = strconv.Itoa(int(*forwardTo.Port))
+				}
+				backendClusters = getBackendClusters(port)
+				break
+			}
+		}
+		if backendClusters == nil {
+			continue
+		}
+
+		// A rule without matches matches all requests
+		if len(rule.Matches) == 0 {
+			rule.Matches = []gatewayV1alpha1.HTTPRouteMatch{{}}
+		}
+		for _, match := range rule.Matches {
+			httpRouteMatch, err := getHTTPRouteMatch(match, pathMatch)
+			if err != nil {
+				log.Error().Err(err).Msgf("Ignoring match in HTTPRoute %s/%s", httpRoute.Namespace, httpRoute.Name)
+				continue
+			}
+			routes = append(routes, *trafficpolicy.NewRouteWeightedCluster(httpRouteMatch, backendClusters))
+		}
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	hostnames := httpRoute.Spec.Hostnames
+	if len(hostnames) == 0 {
+		hostnames = []string{""}
+	}
+
+	var policies []*trafficpolicy.InboundTrafficPolicy
+	for _, hostname := range hostnames {
+		host, hostRegex, err := getIngressHostMatch(httpRoute.ObjectMeta, hostname)
+		if err != nil {
+			log.Error().Err(err).Msgf("Ignoring hostname %q in HTTPRoute %s/%s", hostname, httpRoute.Namespace, httpRoute.Name)
+			continue
+		}
+		policy := newIngressRulePolicy(httpRoute.ObjectMeta, host)
+		for _, route := range routes {
+			policy.AddRule(trafficpolicy.RouteWeightedClusters{
+				HTTPRouteMatch:   withHostMatch(route.HTTPRouteMatch, hostRegex),
+				WeightedClusters: route.WeightedClusters,
+			}, wildcardServiceAccount)
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// getHTTPRouteMatch returns the route match for the given match of a Gateway API HTTPRoute. Paths and header values
+// matched exactly or by prefix are escaped, as the route matches are regular expressions. ImplementationSpecific paths
+// are matched with the given path match.
+func getHTTPRouteMatch(match gatewayV1alpha1.HTTPRouteMatch, pathMatch string) (trafficpolicy.HTTPRouteMatch, error) {
+	pathType := gatewayV1alpha1.PathMatchPrefix
+	path := "/"
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			pathType = *match.Path.Type
+		}
+		if match.Path.Value != nil {
+			path = *match.Path.Value
+		}
+	}
+
+	var httpRouteMatch trafficpolicy.HTTPRouteMatch
+	switch pathType {
+	case gatewayV1alpha1.PathMatchExact:
+		httpRouteMatch = trafficpolicy.HTTPRouteMatch{
+			Methods:       []string{constants.WildcardHTTPMethod},
+			Path:          path,
+			PathMatchType: trafficpolicy.PathMatchExact,
+		}
+
+	case gatewayV1alpha1.PathMatchPrefix:
+		// Element wise prefix match, where the prefix / matches all paths
+		// Request /foo matches path /foo and /foo/bar, not /foobar
+		httpRouteMatch = trafficpolicy.HTTPRouteMatch{
+			Methods:       []string{constants.WildcardHTTPMethod},
+			Path:          regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + prefixMatchPathElementsRegex,
+			PathMatchType: trafficpolicy.PathMatchRegex,
+		}
+
+	case gatewayV1alpha1.PathMatchRegularExpression:
+		if _, err := regexp.Compile(path); err != nil {
+			return httpRouteMatch, errors.Wrapf(err, "Invalid path regular expression %s", path)
+		}
+		httpRouteMatch = trafficpolicy.HTTPRouteMatch{
+			Methods:       []string{constants.WildcardHTTPMethod},
+			Path:          path,
+			PathMatchType: trafficpolicy.PathMatchRegex,
+		}
+
+	case gatewayV1alpha1.PathMatchImplementationSpecific:
+		var err error
+		if httpRouteMatch, err = getIngressRouteMatch(path, networkingV1.PathTypeImplementationSpecific, pathMatch); err != nil {
+			return httpRouteMatch, err
+		}
+
+	default:
+		return httpRouteMatch, errors.Errorf("Invalid path match type %s", pathType)
+	}
+
+	if match.Headers == nil || len(match.Headers.Values) == 0 {
+		return httpRouteMatch, nil
+	}
+
+	headerType := gatewayV1alpha1.HeaderMatchExact
+	if match.Headers.Type != nil {
+		headerType = *match.Headers.Type
+	}
+	httpRouteMatch.Headers = make(map[string]string, len(match.Headers.Values))
+	for name, value := range match.Headers.Values {
+		switch headerType {
+		case gatewayV1alpha1.HeaderMatchExact:
+			httpRouteMatch.Headers[name] = regexp.QuoteMeta(value)
+		case gatewayV1alpha1.HeaderMatchRegularExpression:
+			httpRouteMatch.Headers[name] = value
+		default:
+			return httpRouteMatch, errors.Errorf("Invalid header match type %s", headerType)
+		}
+	}
+	return httpRouteMatch, nil
+}
+
+// buildIngressDefaultBackendPolicy returns the policy routing all the requests received from ingress to the default
+// backend of the given ingress resource, with the given route settings of the resource
+func buildIngressDefaultBackendPolicy(ingressMeta metav1.ObjectMeta, routeSettings ingressRouteSettings, ingressWeightedClusters []service.WeightedCluster) *trafficpolicy.InboundTrafficPolicy {
+	wildcardIngressPolicy := trafficpolicy.NewInboundTrafficPolicy(buildIngressPolicyName(ingressMeta.Name, ingressMeta.Namespace, constants.WildcardHTTPMethod), []string{constants.WildcardHTTPMethod})
+	wildcardIngressPolicy.AddRule(routeSettings.newRoute(trafficpolicy.WildCardRouteMatch, ingressWeightedClusters), wildcardServiceAccount)
+	return wildcardIngressPolicy
+}
+
+// newIngressRulePolicy returns an empty policy for the requests received from ingress for the host of a rule of the
+// given ingress resource
+func newIngressRulePolicy(ingressMeta metav1.ObjectMeta, host string) *trafficpolicy.InboundTrafficPolicy {
+	domain := host
+	if domain == "" {
+		domain = constants.WildcardHTTPMethod
+	}
+	return trafficpolicy.NewInboundTrafficPolicy(buildIngressPolicyName(ingressMeta.Name, ingressMeta.Namespace, domain), []string{domain})
+}
+
+// getIngressHostMatch returns the given host of a rule of the given ingress resource or HTTPRoute normalized with
+// envoy.NormalizeHost, so that the rules for the same host written differently share a policy, along with the regular
+// expression the authority of the requests matching the rule must match, or an empty string if the domain of the rule
+// is sufficient. The domain of a wildcard host such as *.example.com matches any number of DNS labels, while the
+// wildcard only matches a single DNS label per the Kubernetes ingress specification. The rules without a host only
+// match the hosts matching the regular expression of the IngressHostRegexAnnotation annotation, when set. Hosts are
+// case-insensitive, so the regular expressions ignore the case of the authority.
+func getIngressHostMatch(meta metav1.ObjectMeta, host string) (string, string, error) {
+	host, err := envoy.NormalizeHost(host)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.HasPrefix(host, wildcardHostPrefix) {
+		return host, `(?i)[^.]+` + regexp.QuoteMeta(strings.TrimPrefix(host, "*")) + optionalPortRegex, nil
+	}
+	if host != "" {
+		return host, "", nil
+	}
+
+	hostRegex, ok := meta.Annotations[constants.IngressHostRegexAnnotation]
+	if !ok {
+		return host, "", nil
+	}
+	if _, err := regexp.Compile(hostRegex); err != nil {
+		return "", "", errors.Wrapf(err, "Invalid regular expression %q in annotation %s", hostRegex, constants.IngressHostRegexAnnotation)
+	}
+	return host, "(?i:" + hostRegex + ")" + optionalPortRegex, nil
+}
+
+// withHostMatch returns the given route match restricted to the requests whose authority matches the given regular
+// expression, or the route match itself if the regular expression is empty
+func withHostMatch(httpRouteMatch trafficpolicy.HTTPRouteMatch, hostRegex string) trafficpolicy.HTTPRouteMatch {
+	if hostRegex == "" {
+		return httpRouteMatch
+	}
+	headers := map[string]string{authorityHeader: hostRegex}
+	for k, v := range httpRouteMatch.Headers {
+		headers[k] = v
+	}
+	httpRouteMatch.Headers = headers
+	return httpRouteMatch
+}
+
+// getIngressPathRewrite returns the rewrite of the path of the requests matching the given path of an ingress resource,
+// as specified by the IngressPathRewriteAnnotation annotation of the resource, or nil if the path is not rewritten.
+// The part of the path matching an Exact or Prefix path is replaced by the rewritten path. When the rewritten path ends
+// with a slash, it also replaces the slash following a Prefix path, so that /api/v1/foo is rewritten to /foo for the
+// /api/v1 path and the / rewritten path. ImplementationSpecific paths are rewritten as Exact or Prefix paths when matched
+// exactly or by prefix with the given path match. Paths matched with a regular expression cannot be rewritten.
+func getIngressPathRewrite(meta metav1.ObjectMeta, path string, pathType networkingV1.PathType, pathMatch string) (*trafficpolicy.PathRewrite, error) {
+	rewrite, ok := meta.Annotations[constants.IngressPathRewriteAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	if !strings.HasPrefix(rewrite, "/") {
+		return nil, errors.Errorf("Rewritten path %q in annotation %s does not start with /", rewrite, constants.IngressPathRewriteAnnotation)
+	}
+
+	// Backslashes are escaped as they introduce the capture groups of the pattern in the substitution
+	pathRewrite := &trafficpolicy.PathRewrite{
+		Substitution: strings.ReplaceAll(rewrite, `\`, `\\`),
+	}
+	if pathType == networkingV1.PathTypeImplementationSpecific {
+		resolvedPathMatch, err := resolveImplementationSpecificPathMatch(path, pathMatch)
+		if err != nil {
+			return nil, err
+		}
+		switch resolvedPathMatch {
+		case constants.ImplementationSpecificPathMatchExact:
+			pathType = networkingV1.PathTypeExact
+		case constants.ImplementationSpecificPathMatchPrefix:
+			pathType = networkingV1.PathTypePrefix
+		}
+	}
+	switch pathType {
+	case networkingV1.PathTypeExact:
+		pathRewrite.Pattern = "^" + regexp.QuoteMeta(path) + "$"
+
+	case networkingV1.PathTypePrefix:
+		pathRewrite.Pattern = "^" + regexp.QuoteMeta(strings.TrimSuffix(path, "/"))
+		if strings.HasSuffix(rewrite, "/") {
+			pathRewrite.Pattern += "/?"
+		}
+
+	default:
+		return nil, errors.Errorf("Path %s is a regular expression and cannot be rewritten", path)
+	}
+	return pathRewrite, nil
+}
+
+// getIngressRouteMatch returns the route match for the given ingress path and path type, matching ImplementationSpecific
+// paths with the given path match. The path types of the networking.k8s.io/v1beta1 and networking.k8s.io/v1 API versions
+// share the same values.
+func getIngressRouteMatch(path string, pathType networkingV1.PathType, pathMatch string) (trafficpolicy.HTTPRouteMatch, error) {
+	httpRouteMatch := trafficpolicy.HTTPRouteMatch{
+		Methods: []string{constants.WildcardHTTPMethod},
+	}
+
+	switch pathType {
+	case networkingV1.PathTypeExact:
+		// Exact match
+		// Request /foo matches path /foo, not /foobar or /foo/bar
+		httpRouteMatch.Path = path
+		httpRouteMatch.PathMatchType = trafficpolicy.PathMatchExact
+
+	case networkingV1.PathTypePrefix:
+		// Element wise prefix match
+		// Request /foo matches path /foo and /foo/bar, not /foobar
+		httpRouteMatch.Path = path + prefixMatchPathElementsRegex
+		httpRouteMatch.PathMatchType = trafficpolicy.PathMatchRegex
+
+	case networkingV1.PathTypeImplementationSpecific:
+		httpRouteMatch.Path = path
+		resolvedPathMatch, err := resolveImplementationSpecificPathMatch(path, pathMatch)
+		if err != nil {
+			return httpRouteMatch, err
+		}
+		switch resolvedPathMatch {
+		case constants.ImplementationSpecificPathMatchExact:
+			// Exact match
+			// Request /foo matches path /foo, not /foobar or /foo/bar
+			httpRouteMatch.PathMatchType = trafficpolicy.PathMatchExact
+
+		case constants.ImplementationSpecificPathMatchRegex:
+			// Regex matching for the path
+			// Request /foo/bar matches path /foo.*
+			if _, err := regexp.Compile(path); err != nil {
+				return httpRouteMatch, errors.Wrapf(err, "Invalid path regular expression %s", path)
 			}
+			httpRouteMatch.PathMatchType = trafficpolicy.PathMatchRegex
+
+		default:
+			// String based prefix path matching
+			// Request /foo matches /foo/bar and /foobar
+			httpRouteMatch.PathMatchType = trafficpolicy.PathMatchPrefix
+		}
+
+	default:
+		return httpRouteMatch, errors.Errorf("Invalid pathType=%s", pathType)
+	}
+
+	return httpRouteMatch, nil
+}
+
+// getImplementationSpecificPathMatch returns how the ImplementationSpecific paths of the ingress resource or Gateway API
+// HTTPRoute with the given metadata are matched, set by its ImplementationSpecificPathMatchAnnotation annotation or for
+// the whole mesh in the OSM ConfigMap. An invalid annotation is returned as is, and rejected when matching the paths.
+func (mc *MeshCatalog) getImplementationSpecificPathMatch(meta metav1.ObjectMeta) string {
+	if pathMatch, ok := meta.Annotations[constants.ImplementationSpecificPathMatchAnnotation]; ok {
+		return strings.ToLower(strings.TrimSpace(pathMatch))
+	}
+	return mc.configurator.GetImplementationSpecificPathMatch()
+}
+
+// resolveImplementationSpecificPathMatch returns whether the given ImplementationSpecific path is matched exactly, by
+// prefix or as a regular expression with the given path match. The auto path match guesses whether the path is a
+// regular expression from the characters it contains, and matches it by string prefix otherwise.
+func resolveImplementationSpecificPathMatch(path, pathMatch string) (string, error) {
+	switch pathMatch {
+	case constants.ImplementationSpecificPathMatchExact, constants.ImplementationSpecificPathMatchPrefix, constants.ImplementationSpecificPathMatchRegex:
+		return pathMatch, nil
+
+	case constants.ImplementationSpecificPathMatchAuto, "":
+		if strings.ContainsAny(path, commonRegexChars) {
+			return constants.ImplementationSpecificPathMatchRegex, nil
 		}
+		return constants.ImplementationSpecificPathMatchPrefix, nil
+
+	default:
+		return "", errors.Errorf("Invalid path match %q for ImplementationSpecific path %s, must be one of %s", pathMatch, path, strings.Join(configurator.ValidImplementationSpecificPathMatches, ", "))
 	}
-	return inboundIngressPolicies, nil
 }
 
 func buildIngressPolicyName(name, namespace, host string) string {
 	policyName := fmt.Sprintf("%s.%s|%s", name, namespace, host)
 	return policyName
 }
+
+// GetIngressForwardedHeaderPolicy returns the forwarded header policy applied to requests received by the given service
+// from ingress. The mesh-wide policy configured in the OSM ConfigMap is overridden by the annotations of the namespace
+// of the service, then by the annotations of the service.
+func (mc *MeshCatalog) GetIngressForwardedHeaderPolicy(svc service.MeshService) trafficpolicy.ForwardedHeaderPolicy {
+	overrides := mc.getServiceOverrides(svc)
+	return trafficpolicy.ForwardedHeaderPolicy{
+		XFFNumTrustedHops: overrides.Uint32(constants.XFFNumTrustedHopsAnnotation, mc.configurator.GetXFFNumTrustedHops(), 0),
+		UseRemoteAddress:  overrides.Bool(constants.UseRemoteAddressAnnotation, mc.configurator.UseRemoteAddress()),
+		SkipXFFAppend:     overrides.Bool(constants.SkipXFFAppendAnnotation, mc.configurator.SkipXFFAppend()),
+	}
+}
+
+// GetIngressBackendPolicy returns the ingress sources allowed to reach the given service, and the ports and protocols
+// they reach it on, as specified by the IngressBackend resource listing the service as a backend. Ingress traffic to the
+// service is not restricted when no IngressBackend lists the service, in which case nil is returned.
+func (mc *MeshCatalog) GetIngressBackendPolicy(svc service.MeshService) (*trafficpolicy.IngressBackendPolicy, error) {
+	ingressBackend, err := mc.ingressMonitor.GetIngressBackend(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get IngressBackend resource for service %s", svc)
+		return nil, err
+	}
+	if ingressBackend == nil {
+		return nil, nil
+	}
+
+	policy := &trafficpolicy.IngressBackendPolicy{
+		Name:  fmt.Sprintf("%s/%s", ingressBackend.Namespace, ingressBackend.Name),
+		Ports: make(map[uint32]string),
+	}
+
+	for _, backend := range ingressBackend.Spec.Backends {
+		if backend.Name != svc.Name {
+			continue
+		}
+		protocol := strings.ToLower(backend.Port.Protocol)
+		switch protocol {
+		case policyV1alpha1.ProtocolHTTP, policyV1alpha1.ProtocolHTTPS, policyV1alpha1.ProtocolTCP, policyV1alpha1.ProtocolTLSPassthrough:
+			policy.Ports[backend.Port.Number] = protocol
+		default:
+			log.Error().Msgf("Ignoring port %d of service %s in IngressBackend %s, unsupported protocol %s", backend.Port.Number, svc, policy.Name, backend.Port.Protocol)
+		}
+	}
+
+	for _, source := range ingressBackend.Spec.Sources {
+		switch source.Kind {
+		case policyV1alpha1.KindServiceAccount:
+			namespace := source.Namespace
+			if namespace == "" {
+				namespace = ingressBackend.Namespace
+			}
+			svcAccount := service.K8sServiceAccount{Name: source.Name, Namespace: namespace}
+			policy.Principals = append(policy.Principals, identity.GetKubernetesServiceIdentity(svcAccount, identity.ClusterLocalTrustDomain).String())
+
+		case policyV1alpha1.KindAuthenticatedPrincipal:
+			policy.Principals = append(policy.Principals, source.Name)
+
+		case policyV1alpha1.KindIPRange:
+			if _, _, err := net.ParseCIDR(source.Name); err != nil {
+				log.Error().Err(err).Msgf("Ignoring source in IngressBackend %s, invalid CIDR range %s", policy.Name, source.Name)
+				continue
+			}
+			policy.IPRanges = append(policy.IPRanges, source.Name)
+
+		default:
+			log.Error().Msgf("Ignoring source in IngressBackend %s, unsupported kind %s", policy.Name, source.Kind)
+		}
+	}
+
+	if tls := ingressBackend.Spec.TLS; tls != nil {
+		policy.RequireClientCert = tls.RequireClientCert
+		if tls.CABundleSecret != nil {
+			namespace := tls.CABundleSecret.Namespace
+			if namespace == "" {
+				namespace = ingressBackend.Namespace
+			}
+			policy.CABundleSecret = fmt.Sprintf("%s/%s", namespace, tls.CABundleSecret.Name)
+		}
+	}
+	// Authenticated principals can only be verified from the client certificates of the ingress sources
+	if len(policy.Principals) > 0 {
+		policy.RequireClientCert = true
+	}
+
+	sort.Strings(policy.Principals)
+	sort.Strings(policy.IPRanges)
+	return policy, nil
+}
+
+// GetIngressCABundle returns the CA bundle validating the client certificates of ingress sources stored in the secret
+// with the given namespaced name, referenced by an IngressBackend
+func (mc *MeshCatalog) GetIngressCABundle(secretName string) ([]byte, error) {
+	secret, err := mc.getSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle, ok := secret.Data[constants.KubernetesOpaqueSecretCAKey]
+	if !ok || len(caBundle) == 0 {
+		return nil, errors.Errorf("Secret %s does not have a CA bundle under the %s key", secretName, constants.KubernetesOpaqueSecretCAKey)
+	}
+
+	return caBundle, nil
+}
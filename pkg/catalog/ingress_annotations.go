@@ -0,0 +1,221 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// ingressAnnotationSetting is a setting of the traffic received from ingress that ingress controllers configure with annotations
+type ingressAnnotationSetting int
+
+const (
+	// backendProtocolSetting is the protocol of the traffic sent by the ingress controller to the backends
+	backendProtocolSetting ingressAnnotationSetting = iota
+
+	// maxRequestBytesSetting is the maximum size of the body of the requests accepted by the ingress controller
+	maxRequestBytesSetting
+
+	// sslRedirectSetting redirects HTTP requests to HTTPS, which is done by the ingress controller before the requests
+	// reach the backends and does not require any configuration of the backends
+	sslRedirectSetting
+)
+
+// ingressAnnotationSettings maps the annotations of the ingress controllers understood by OSM to the setting they configure.
+// The backend protocol annotations of Contour and Traefik are set on the backend service rather than the ingress resource.
+var ingressAnnotationSettings = map[string]ingressAnnotationSetting{
+	// NGINX ingress controller
+	"nginx.ingress.kubernetes.io/backend-protocol":   backendProtocolSetting,
+	"nginx.ingress.kubernetes.io/proxy-body-size":    maxRequestBytesSetting,
+	"nginx.ingress.kubernetes.io/ssl-redirect":       sslRedirectSetting,
+	"nginx.ingress.kubernetes.io/force-ssl-redirect": sslRedirectSetting,
+
+	// Contour
+	"projectcontour.io/upstream-protocol.tls":  backendProtocolSetting,
+	"ingress.kubernetes.io/force-ssl-redirect": sslRedirectSetting,
+
+	// Traefik
+	"traefik.ingress.kubernetes.io/service.serversscheme": backendProtocolSetting,
+	"traefik.ingress.kubernetes.io/redirect-entry-point":  sslRedirectSetting,
+}
+
+// ingressControllerAnnotationPrefixes are the prefixes of the annotations of the ingress controllers whose annotations
+// are understood by OSM. Annotations with these prefixes not listed in ingressAnnotationSettings are reported as unsupported.
+var ingressControllerAnnotationPrefixes = []string{
+	"nginx.ingress.kubernetes.io/",
+	"projectcontour.io/",
+	"traefik.ingress.kubernetes.io/",
+}
+
+// requestSizeRegex matches a request size in the format of the NGINX ingress controller, a number of bytes optionally
+// followed by the k, m or g unit
+var requestSizeRegex = regexp.MustCompile(`^([0-9]+)([kKmMgG]?)$`)
+
+// GetIngressAnnotationPolicy returns the settings applied to the traffic received by the given service from ingress,
+// as specified by the annotations of the NGINX, Contour and Traefik ingress controllers on the ingress resources of the
+// service and on the service. Annotations of these ingress controllers that OSM does not support are logged and ignored.
+// When the ingress resources of the service specify different backend protocols, the protocol of the first ingress
+// resource by name applies. The body of the requests is only limited when all the ingress resources of the service
+// limit it, to the largest of their limits.
+func (mc *MeshCatalog) GetIngressAnnotationPolicy(svc service.MeshService) trafficpolicy.IngressAnnotationPolicy {
+	annotationsBySource := make(map[string]map[string]string)
+	ingressesV1beta1, err := mc.ingressMonitor.GetIngressNetworkingV1beta1(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get networking.k8s.io/v1beta1 ingress resources for service %s", svc)
+	}
+	for _, ingress := range ingressesV1beta1 {
+		annotationsBySource[fmt.Sprintf("ingress %s/%s", ingress.Namespace, ingress.Name)] = ingress.Annotations
+	}
+	ingressesV1, err := mc.ingressMonitor.GetIngressNetworkingV1(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to get networking.k8s.io/v1 ingress resources for service %s", svc)
+	}
+	for _, ingress := range ingressesV1 {
+		annotationsBySource[fmt.Sprintf("ingress %s/%s", ingress.Namespace, ingress.Name)] = ingress.Annotations
+	}
+
+	var sources []string
+	for source := range annotationsBySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var policy trafficpolicy.IngressAnnotationPolicy
+	limitedRequestBytes := len(sources) > 0
+	for _, source := range sources {
+		sourcePolicy := parseIngressAnnotations(source, annotationsBySource[source])
+		if sourcePolicy.BackendProtocol != "" {
+			if policy.BackendProtocol == "" {
+				policy.BackendProtocol = sourcePolicy.BackendProtocol
+			} else if policy.BackendProtocol != sourcePolicy.BackendProtocol {
+				log.Warn().Msgf("Ignoring backend protocol %s of %s for service %s, conflicting with backend protocol %s",
+					sourcePolicy.BackendProtocol, source, svc, policy.BackendProtocol)
+			}
+		}
+		if sourcePolicy.MaxRequestBytes == 0 {
+			limitedRequestBytes = false
+		} else if sourcePolicy.MaxRequestBytes > policy.MaxRequestBytes {
+			policy.MaxRequestBytes = sourcePolicy.MaxRequestBytes
+		}
+	}
+	if !limitedRequestBytes {
+		policy.MaxRequestBytes = 0
+	}
+
+	// The backend protocol annotations of the service apply when the ingress resources do not specify one
+	if k8sSvc := mc.kubeController.GetService(svc); k8sSvc != nil {
+		svcPolicy := parseIngressAnnotations(fmt.Sprintf("service %s", svc), k8sSvc.Annotations)
+		if policy.BackendProtocol == "" {
+			policy.BackendProtocol = svcPolicy.BackendProtocol
+		}
+	}
+
+	return policy
+}
+
+// parseIngressAnnotations returns the settings specified by the given ingress controller annotations of the given
+// source, an ingress resource or a service, ignoring the annotations that are not supported or have invalid values
+func parseIngressAnnotations(source string, annotations map[string]string) trafficpolicy.IngressAnnotationPolicy {
+	var policy trafficpolicy.IngressAnnotationPolicy
+
+	// Iterate the annotations in order, so that the same annotations are reported on every update
+	var keys []string
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := annotations[key]
+		setting, ok := ingressAnnotationSettings[key]
+		if !ok {
+			for _, prefix := range ingressControllerAnnotationPrefixes {
+				if strings.HasPrefix(key, prefix) {
+					log.Warn().Msgf("Ignoring annotation %s of %s, not supported by OSM", key, source)
+					break
+				}
+			}
+			continue
+		}
+
+		switch setting {
+		case backendProtocolSetting:
+			protocol, err := getIngressBackendProtocol(key, value)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Ignoring annotation %s of %s", key, source)
+				continue
+			}
+			policy.BackendProtocol = protocol
+
+		case maxRequestBytesSetting:
+			maxRequestBytes, err := parseRequestSize(value)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Ignoring annotation %s of %s", key, source)
+				continue
+			}
+			policy.MaxRequestBytes = maxRequestBytes
+
+		case sslRedirectSetting:
+			log.Trace().Msgf("Annotation %s of %s is applied by the ingress controller", key, source)
+		}
+	}
+
+	return policy
+}
+
+// getIngressBackendProtocol returns the backend protocol, http or https, specified by the given backend protocol annotation
+func getIngressBackendProtocol(key string, value string) (string, error) {
+	// Contour lists the ports of the service receiving TLS traffic
+	if key == "projectcontour.io/upstream-protocol.tls" {
+		if strings.TrimSpace(value) == "" {
+			return "", errors.New("No port specified")
+		}
+		return policyV1alpha1.ProtocolHTTPS, nil
+	}
+
+	switch strings.ToLower(value) {
+	case policyV1alpha1.ProtocolHTTP:
+		return policyV1alpha1.ProtocolHTTP, nil
+	case policyV1alpha1.ProtocolHTTPS:
+		return policyV1alpha1.ProtocolHTTPS, nil
+	default:
+		return "", errors.Errorf("Unsupported backend protocol %s", value)
+	}
+}
+
+// parseRequestSize returns the number of bytes of the given request size, in the format of the NGINX ingress controller.
+// A size of 0 does not limit the size of the requests.
+func parseRequestSize(size string) (uint32, error) {
+	matches := requestSizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, errors.Errorf("Invalid request size %s", size)
+	}
+
+	bytes, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid request size %s", size)
+	}
+	var shift uint
+	switch strings.ToLower(matches[2]) {
+	case "k":
+		shift = 10
+	case "m":
+		shift = 20
+	case "g":
+		shift = 30
+	}
+	if bytes > math.MaxUint32>>shift {
+		return 0, errors.Errorf("Request size %s exceeds the maximum of %d bytes", size, uint32(math.MaxUint32))
+	}
+
+	return uint32(bytes << shift), nil
+}
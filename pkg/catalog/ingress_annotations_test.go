@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/ingress"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetIngressAnnotationPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	newIngressV1 := func(name string, annotations map[string]string) *networkingV1.Ingress {
+		return &networkingV1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: svc.Namespace, Annotations: annotations},
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		ingressesV1beta1   []*networkingV1beta1.Ingress
+		ingressesV1        []*networkingV1.Ingress
+		serviceAnnotations map[string]string
+		expectedPolicy     trafficpolicy.IngressAnnotationPolicy
+	}{
+		{
+			name:           "no annotations",
+			ingressesV1:    []*networkingV1.Ingress{newIngressV1("ingress-1", nil)},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{},
+		},
+		{
+			name: "NGINX annotations",
+			ingressesV1: []*networkingV1.Ingress{newIngressV1("ingress-1", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+				"nginx.ingress.kubernetes.io/proxy-body-size":  "8m",
+				"nginx.ingress.kubernetes.io/ssl-redirect":     "true",
+				"nginx.ingress.kubernetes.io/rewrite-target":   "/",
+			})},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "https", MaxRequestBytes: 8 << 20},
+		},
+		{
+			name: "unsupported backend protocol",
+			ingressesV1: []*networkingV1.Ingress{newIngressV1("ingress-1", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
+			})},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{},
+		},
+		{
+			name: "backend protocol of the first ingress resource by name applies",
+			ingressesV1beta1: []*networkingV1beta1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ingress-2", Namespace: svc.Namespace, Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/backend-protocol": "HTTP",
+					}},
+				},
+			},
+			ingressesV1: []*networkingV1.Ingress{newIngressV1("ingress-1", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS",
+			})},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "https"},
+		},
+		{
+			name: "largest request body limit applies",
+			ingressesV1: []*networkingV1.Ingress{
+				newIngressV1("ingress-1", map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "1m"}),
+				newIngressV1("ingress-2", map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "512k"}),
+			},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{MaxRequestBytes: 1 << 20},
+		},
+		{
+			name: "request body is not limited when an ingress resource does not limit it",
+			ingressesV1: []*networkingV1.Ingress{
+				newIngressV1("ingress-1", map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "1m"}),
+				newIngressV1("ingress-2", nil),
+			},
+			expectedPolicy: trafficpolicy.IngressAnnotationPolicy{},
+		},
+		{
+			name:               "Contour backend protocol annotation of the service",
+			ingressesV1:        []*networkingV1.Ingress{newIngressV1("ingress-1", nil)},
+			serviceAnnotations: map[string]string{"projectcontour.io/upstream-protocol.tls": "443,https"},
+			expectedPolicy:     trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "https"},
+		},
+		{
+			name: "backend protocol of the ingress resources overrides the Traefik annotation of the service",
+			ingressesV1: []*networkingV1.Ingress{newIngressV1("ingress-1", map[string]string{
+				"nginx.ingress.kubernetes.io/backend-protocol": "HTTP",
+			})},
+			serviceAnnotations: map[string]string{"traefik.ingress.kubernetes.io/service.serversscheme": "https"},
+			expectedPolicy:     trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "http"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(tc.ingressesV1beta1, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(tc.ingressesV1, nil).Times(1)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: svc.Name, Namespace: svc.Namespace, Annotations: tc.serviceAnnotations},
+			}).Times(1)
+
+			mc := &MeshCatalog{
+				ingressMonitor: mockIngressMonitor,
+				kubeController: mockKubeController,
+			}
+			assert.Equal(tc.expectedPolicy, mc.GetIngressAnnotationPolicy(svc))
+		})
+	}
+}
+
+func TestParseRequestSize(t *testing.T) {
+	testCases := []struct {
+		size          string
+		expectedBytes uint32
+		expectedErr   bool
+	}{
+		{size: "0", expectedBytes: 0},
+		{size: "1024", expectedBytes: 1024},
+		{size: "8k", expectedBytes: 8 << 10},
+		{size: "8M", expectedBytes: 8 << 20},
+		{size: "2g", expectedBytes: 2 << 30},
+		{size: "4g", expectedErr: true},
+		{size: "1.5m", expectedErr: true},
+		{size: "", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.size, func(t *testing.T) {
+			assert := tassert.New(t)
+			bytes, err := parseRequestSize(tc.size)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedBytes, bytes)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// envoyRetryConditions are the retry conditions supported by Envoy for HTTP and gRPC requests
+var envoyRetryConditions = map[string]bool{
+	"5xx":                        true,
+	"gateway-error":              true,
+	"reset":                      true,
+	"connect-failure":            true,
+	"envoy-ratelimited":          true,
+	"retriable-4xx":              true,
+	"refused-stream":             true,
+	"retriable-status-codes":     true,
+	"retriable-headers":          true,
+	"http3-post-connect-failure": true,
+	"cancelled":                  true,
+	"deadline-exceeded":          true,
+	"internal":                   true,
+	"resource-exhausted":         true,
+	"unavailable":                true,
+}
+
+// ingressRouteSettings are the settings of the routes of an ingress resource specified by the annotations of the resource
+type ingressRouteSettings struct {
+	retryPolicy *trafficpolicy.RetryPolicy
+	timeout     *time.Duration
+}
+
+// getIngressRouteSettings returns the retry policy and the timeout of the requests matching the rules of the given ingress
+// resource. Invalid annotations are logged and ignored, so that the requests are still routed with the default retry
+// policy and timeout of the proxy.
+func getIngressRouteSettings(meta metav1.ObjectMeta) ingressRouteSettings {
+	var settings ingressRouteSettings
+	var err error
+	if settings.retryPolicy, err = getIngressRetryPolicy(meta); err != nil {
+		log.Error().Err(err).Msgf("Ignoring retry policy of ingress resource %s/%s", meta.Namespace, meta.Name)
+	}
+	if settings.timeout, err = parseDurationAnnotation(meta, constants.IngressTimeoutAnnotation); err != nil {
+		log.Error().Err(err).Msgf("Ignoring timeout of ingress resource %s/%s", meta.Namespace, meta.Name)
+	}
+	return settings
+}
+
+// newRoute returns the route for the given route match and weighted clusters with the settings of the ingress resource
+func (s ingressRouteSettings) newRoute(httpRouteMatch trafficpolicy.HTTPRouteMatch, weightedClusters []service.WeightedCluster) trafficpolicy.RouteWeightedClusters {
+	route := trafficpolicy.NewRouteWeightedCluster(httpRouteMatch, weightedClusters)
+	route.RetryPolicy = s.retryPolicy
+	route.Timeout = s.timeout
+	return *route
+}
+
+// getIngressRetryPolicy returns the retry policy specified by the IngressRetryOnAnnotation, IngressNumRetriesAnnotation
+// and IngressPerTryTimeoutAnnotation annotations of the given ingress resource, or nil if the requests are not retried
+func getIngressRetryPolicy(meta metav1.ObjectMeta) (*trafficpolicy.RetryPolicy, error) {
+	retryOn, ok := meta.Annotations[constants.IngressRetryOnAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	conditions, err := parseRetryConditions(retryOn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid retry conditions in annotation %s", constants.IngressRetryOnAnnotation)
+	}
+	retryPolicy := &trafficpolicy.RetryPolicy{
+		RetryOn: conditions,
+	}
+
+	if numRetries, ok := meta.Annotations[constants.IngressNumRetriesAnnotation]; ok {
+		value, err := strconv.ParseUint(strings.TrimSpace(numRetries), 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid number of retries %q in annotation %s", numRetries, constants.IngressNumRetriesAnnotation)
+		}
+		retryPolicy.NumRetries = uint32(value)
+	}
+
+	perTryTimeout, err := parseDurationAnnotation(meta, constants.IngressPerTryTimeoutAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	retryPolicy.PerTryTimeout = perTryTimeout
+
+	return retryPolicy, nil
+}
+
+// parseRetryConditions returns the given comma separated list of Envoy retry conditions without spaces, or an error if
+// a condition is not supported by Envoy
+func parseRetryConditions(retryOn string) (string, error) {
+	var conditions []string
+	for _, condition := range strings.Split(retryOn, ",") {
+		condition = strings.TrimSpace(condition)
+		if !envoyRetryConditions[condition] {
+			return "", errors.Errorf("Unsupported retry condition %q", condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, ","), nil
+}
+
+// parseDurationAnnotation returns the duration specified by the given annotation of the given resource, or nil if the
+// annotation is not set
+func parseDurationAnnotation(meta metav1.ObjectMeta, annotation string) (*time.Duration, error) {
+	value, ok := meta.Annotations[annotation]
+	if !ok {
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid duration %q in annotation %s", value, annotation)
+	}
+	if duration < 0 {
+		return nil, errors.Errorf("Negative duration %q in annotation %s", value, annotation)
+	}
+	return &duration, nil
+}
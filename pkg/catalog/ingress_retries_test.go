@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetIngressRouteSettings(t *testing.T) {
+	twoSeconds := 2 * time.Second
+	thirtySeconds := 30 * time.Second
+	noTimeout := time.Duration(0)
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedSettings ingressRouteSettings
+	}{
+		{
+			name:             "no retries and default timeout without annotations",
+			annotations:      nil,
+			expectedSettings: ingressRouteSettings{},
+		},
+		{
+			name: "retries and timeout set by annotations",
+			annotations: map[string]string{
+				constants.IngressRetryOnAnnotation:       "5xx, connect-failure",
+				constants.IngressNumRetriesAnnotation:    "3",
+				constants.IngressPerTryTimeoutAnnotation: "2s",
+				constants.IngressTimeoutAnnotation:       "30s",
+			},
+			expectedSettings: ingressRouteSettings{
+				retryPolicy: &trafficpolicy.RetryPolicy{
+					RetryOn:       "5xx,connect-failure",
+					NumRetries:    3,
+					PerTryTimeout: &twoSeconds,
+				},
+				timeout: &thirtySeconds,
+			},
+		},
+		{
+			name: "timeout disabled by annotation",
+			annotations: map[string]string{
+				constants.IngressTimeoutAnnotation: "0s",
+			},
+			expectedSettings: ingressRouteSettings{
+				timeout: &noTimeout,
+			},
+		},
+		{
+			name: "number of retries ignored without retry conditions",
+			annotations: map[string]string{
+				constants.IngressNumRetriesAnnotation: "3",
+			},
+			expectedSettings: ingressRouteSettings{},
+		},
+		{
+			name: "retry policy with unsupported retry condition ignored",
+			annotations: map[string]string{
+				constants.IngressRetryOnAnnotation: "5xx,on-failure",
+				constants.IngressTimeoutAnnotation: "30s",
+			},
+			expectedSettings: ingressRouteSettings{
+				timeout: &thirtySeconds,
+			},
+		},
+		{
+			name: "retry policy with invalid number of retries ignored",
+			annotations: map[string]string{
+				constants.IngressRetryOnAnnotation:    "5xx",
+				constants.IngressNumRetriesAnnotation: "-1",
+			},
+			expectedSettings: ingressRouteSettings{},
+		},
+		{
+			name: "invalid timeouts ignored",
+			annotations: map[string]string{
+				constants.IngressRetryOnAnnotation:       "reset",
+				constants.IngressPerTryTimeoutAnnotation: "2",
+				constants.IngressTimeoutAnnotation:       "-30s",
+			},
+			expectedSettings: ingressRouteSettings{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			meta := metav1.ObjectMeta{Name: "ingress", Namespace: "bookstore-ns", Annotations: tc.annotations}
+			assert.Equal(tc.expectedSettings, getIngressRouteSettings(meta))
+		})
+	}
+}
+
+func TestIngressRouteSettingsNewRoute(t *testing.T) {
+	assert := tassert.New(t)
+
+	timeout := 30 * time.Second
+	settings := ingressRouteSettings{
+		retryPolicy: &trafficpolicy.RetryPolicy{RetryOn: "5xx"},
+		timeout:     &timeout,
+	}
+	weightedCluster := service.WeightedCluster{ClusterName: "bookstore-ns/bookstore", Weight: 100}
+
+	route := settings.newRoute(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{weightedCluster})
+
+	assert.Equal(trafficpolicy.WildCardRouteMatch, route.HTTPRouteMatch)
+	assert.True(route.WeightedClusters.Contains(weightedCluster))
+	assert.Equal(settings.retryPolicy, route.RetryPolicy)
+	assert.Equal(&timeout, route.Timeout)
+}
@@ -0,0 +1,349 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// ingressStatusResyncInterval is the interval at which the status of all the ingress resources is reported again,
+// catching up with the status updates that failed
+const ingressStatusResyncInterval = 10 * time.Minute
+
+// ingressCondition is the condition of an ingress resource, or of a rule of an ingress resource, reported in its
+// IngressStatusAnnotation annotation
+type ingressCondition string
+
+const (
+	// ingressAccepted is the condition of the rules programmed by OSM, and of the ingress resources whose rules were
+	// all programmed
+	ingressAccepted ingressCondition = "Accepted"
+
+	// ingressPartiallyProgrammed is the condition of the ingress resources with both accepted and rejected rules
+	ingressPartiallyProgrammed ingressCondition = "PartiallyProgrammed"
+
+	// ingressRejected is the condition of the rules ignored by OSM, and of the ingress resources with no accepted rule
+	ingressRejected ingressCondition = "Rejected"
+)
+
+// Reasons of the rejection of a rule of an ingress resource
+const (
+	ingressReasonUnsupportedBackend = "UnsupportedBackend"
+	ingressReasonInvalidHost        = "InvalidHost"
+	ingressReasonInvalidPath        = "InvalidPath"
+	ingressReasonBackendNotInMesh   = "BackendNotInMesh"
+)
+
+// ingressStatus is the status of an ingress resource written to its IngressStatusAnnotation annotation
+type ingressStatus struct {
+	// ObservedGeneration is the generation of the ingress resource the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Condition is the condition of the ingress resource as a whole
+	Condition ingressCondition `json:"condition"`
+
+	// Rules is the status of each path of the rules of the ingress resource, and of its default backend
+	Rules []ingressRuleStatus `json:"rules,omitempty"`
+}
+
+// ingressRuleStatus is the status of a path of a rule, or of the default backend, of an ingress resource
+type ingressRuleStatus struct {
+	// Host is the host of the rule, empty for the default backend and the rules matching all hosts
+	Host string `json:"host,omitempty"`
+
+	// Path is the path of the rule, empty for the default backend
+	Path string `json:"path,omitempty"`
+
+	// PathMatch is how the path of the rule is matched when its path type is ImplementationSpecific, one of exact,
+	// prefix or regex
+	PathMatch string `json:"pathMatch,omitempty"`
+
+	// Backend is the service, and port when specified, the requests matching the rule are routed to
+	Backend string `json:"backend"`
+
+	// Condition is the condition of the rule
+	Condition ingressCondition `json:"condition"`
+
+	// Reason is the reason of the rejection of the rule
+	Reason string `json:"reason,omitempty"`
+
+	// Message describes why the rule was rejected
+	Message string `json:"message,omitempty"`
+}
+
+// ingressBackendRef is a backend of an ingress resource, independent of the API version of the resource
+type ingressBackendRef struct {
+	host     string
+	path     string
+	pathType networkingV1.PathType
+
+	// isDefault is true for the default backend of the ingress resource, which has no host nor path
+	isDefault bool
+
+	// serviceName is the name of the backend service, empty when the backend is not a service
+	serviceName string
+
+	// port is the port of the backend service by number or by name, empty when not specified
+	port string
+}
+
+// StartIngressStatusReporter reports the status of the ingress resources OSM programs ingress policies for in their
+// IngressStatusAnnotation annotation, and keeps it in sync with the ingress resources and their backends until the stop
+// channel is closed
+func (mc *MeshCatalog) StartIngressStatusReporter(stop <-chan struct{}) {
+	eventSub := events.GetPubSubInstance().Subscribe(
+		a.IngressAdded, a.IngressUpdated,
+		a.ServiceAdded, a.ServiceUpdated, a.ServiceDeleted,
+		a.NamespaceAdded, a.NamespaceUpdated,
+		a.ConfigMapUpdated, // The ingress class of OSM is set in the OSM ConfigMap
+	)
+
+	go func() {
+		defer events.GetPubSubInstance().Unsub(eventSub)
+		ticker := time.NewTicker(ingressStatusResyncInterval)
+		defer ticker.Stop()
+
+		mc.reportIngressStatus()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				mc.reportIngressStatus()
+			case _, ok := <-eventSub:
+				if !ok {
+					return
+				}
+				// The status is computed from the caches of the controller and only written when it changed, so all
+				// the ingress resources are reported again on every event
+				mc.reportIngressStatus()
+			}
+		}
+	}()
+}
+
+// reportIngressStatus writes the status of the ingress resources OSM programs ingress policies for to their
+// IngressStatusAnnotation annotation, when it changed
+func (mc *MeshCatalog) reportIngressStatus() {
+	for _, ingress := range mc.ingressMonitor.ListIngressesNetworkingV1beta1() {
+		mc.updateIngressStatus(ingress.ObjectMeta, mc.getIngressStatusNetworkingV1beta1(ingress), func(patch []byte) error {
+			_, err := mc.kubeClient.NetworkingV1beta1().Ingresses(ingress.Namespace).Patch(context.Background(), ingress.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+	}
+	for _, ingress := range mc.ingressMonitor.ListIngressesNetworkingV1() {
+		mc.updateIngressStatus(ingress.ObjectMeta, mc.getIngressStatusNetworkingV1(ingress), func(patch []byte) error {
+			_, err := mc.kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Patch(context.Background(), ingress.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+	}
+}
+
+// updateIngressStatus patches the IngressStatusAnnotation annotation of the ingress resource with the given metadata
+// with the given status, unless the annotation is up to date. Patching the annotation does not change the generation of
+// the resource, so that the status only changes with the resource and its backends.
+func (mc *MeshCatalog) updateIngressStatus(meta metav1.ObjectMeta, status ingressStatus, patchIngress func(patch []byte) error) {
+	value, err := json.Marshal(status)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshaling the status of ingress resource %s/%s", meta.Namespace, meta.Name)
+		return
+	}
+	if meta.Annotations[constants.IngressStatusAnnotation] == string(value) {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				constants.IngressStatusAnnotation: string(value),
+			},
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshaling the status patch of ingress resource %s/%s", meta.Namespace, meta.Name)
+		return
+	}
+	if err := patchIngress(patch); err != nil {
+		log.Error().Err(err).Msgf("Error updating the status of ingress resource %s/%s", meta.Namespace, meta.Name)
+		return
+	}
+	log.Debug().Msgf("Updated the status of ingress resource %s/%s to %s", meta.Namespace, meta.Name, status.Condition)
+
+	// The path match is set for an ImplementationSpecific path, which may be matched differently than guessed from its
+	// characters when the path match is not auto
+	for _, ruleStatus := range status.Rules {
+		if ruleStatus.PathMatch == "" {
+			continue
+		}
+		guessedPathMatch, _ := resolveImplementationSpecificPathMatch(ruleStatus.Path, constants.ImplementationSpecificPathMatchAuto)
+		if guessedPathMatch != ruleStatus.PathMatch {
+			events.GenericEventRecorder().WarnEvent(events.IngressPathMatchOverridden,
+				"ImplementationSpecific path %s of ingress resource %s/%s is matched as %s, instead of %s as guessed from its characters",
+				ruleStatus.Path, meta.Namespace, meta.Name, ruleStatus.PathMatch, guessedPathMatch)
+		}
+	}
+}
+
+// getIngressStatusNetworkingV1beta1 returns the status of the given networking.k8s.io/v1beta1 ingress resource
+func (mc *MeshCatalog) getIngressStatusNetworkingV1beta1(ingress *networkingV1beta1.Ingress) ingressStatus {
+	var backends []ingressBackendRef
+	if backend := ingress.Spec.Backend; backend != nil {
+		backends = append(backends, ingressBackendRef{
+			isDefault:   true,
+			serviceName: backend.ServiceName,
+			port:        backend.ServicePort.String(),
+		})
+	}
+	for _, rule := range ingress.Spec.Rules {
+		// A rule without HTTP paths, such as a rule only specifying a host, has no backend to route to
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, ingressPath := range rule.HTTP.Paths {
+			// Default ingress path type to PathTypeImplementationSpecific if unspecified
+			pathType := networkingV1.PathTypeImplementationSpecific
+			if ingressPath.PathType != nil {
+				pathType = networkingV1.PathType(*ingressPath.PathType)
+			}
+			backends = append(backends, ingressBackendRef{
+				host:        rule.Host,
+				path:        ingressPath.Path,
+				pathType:    pathType,
+				serviceName: ingressPath.Backend.ServiceName,
+				port:        ingressPath.Backend.ServicePort.String(),
+			})
+		}
+	}
+	return mc.getIngressStatus(ingress.ObjectMeta, backends)
+}
+
+// getIngressStatusNetworkingV1 returns the status of the given networking.k8s.io/v1 ingress resource
+func (mc *MeshCatalog) getIngressStatusNetworkingV1(ingress *networkingV1.Ingress) ingressStatus {
+	var backends []ingressBackendRef
+	if backend := ingress.Spec.DefaultBackend; backend != nil {
+		backendRef := newIngressServiceBackendRef(backend.Service)
+		backendRef.isDefault = true
+		backends = append(backends, backendRef)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		// A rule without HTTP paths, such as a rule only specifying a host, has no backend to route to
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, ingressPath := range rule.HTTP.Paths {
+			// Default ingress path type to PathTypeImplementationSpecific if unspecified
+			pathType := networkingV1.PathTypeImplementationSpecific
+			if ingressPath.PathType != nil {
+				pathType = *ingressPath.PathType
+			}
+			backendRef := newIngressServiceBackendRef(ingressPath.Backend.Service)
+			backendRef.host = rule.Host
+			backendRef.path = ingressPath.Path
+			backendRef.pathType = pathType
+			backends = append(backends, backendRef)
+		}
+	}
+	return mc.getIngressStatus(ingress.ObjectMeta, backends)
+}
+
+// newIngressServiceBackendRef returns the reference to the given service backend of a networking.k8s.io/v1 ingress
+// resource. The service backend is nil when the backend is a resource.
+func newIngressServiceBackendRef(backend *networkingV1.IngressServiceBackend) ingressBackendRef {
+	if backend == nil {
+		return ingressBackendRef{}
+	}
+	backendRef := ingressBackendRef{serviceName: backend.Name}
+	if backend.Port.Name != "" || backend.Port.Number != 0 {
+		backendRef.port = getIngressServiceBackendPort(backend.Port)
+	}
+	return backendRef
+}
+
+// getIngressStatus returns the status of the ingress resource with the given metadata and backends. The ingress
+// resource is accepted when all its rules are accepted, and rejected when none of them is.
+func (mc *MeshCatalog) getIngressStatus(meta metav1.ObjectMeta, backends []ingressBackendRef) ingressStatus {
+	status := ingressStatus{
+		ObservedGeneration: meta.Generation,
+	}
+
+	accepted := 0
+	for _, backend := range backends {
+		ruleStatus := mc.getIngressRuleStatus(meta, backend)
+		if ruleStatus.Condition == ingressAccepted {
+			accepted++
+		}
+		status.Rules = append(status.Rules, ruleStatus)
+	}
+
+	switch {
+	case accepted == 0:
+		status.Condition = ingressRejected
+	case accepted < len(status.Rules):
+		status.Condition = ingressPartiallyProgrammed
+	default:
+		status.Condition = ingressAccepted
+	}
+	return status
+}
+
+// getIngressRuleStatus returns the status of the rule of the ingress resource with the given metadata routing requests
+// to the given backend, validated as when the ingress policies of the backend are built
+func (mc *MeshCatalog) getIngressRuleStatus(meta metav1.ObjectMeta, backend ingressBackendRef) ingressRuleStatus {
+	ruleStatus := ingressRuleStatus{
+		Host:      backend.host,
+		Path:      backend.path,
+		Backend:   backend.serviceName,
+		Condition: ingressAccepted,
+	}
+	if backend.port != "" {
+		ruleStatus.Backend = fmt.Sprintf("%s:%s", backend.serviceName, backend.port)
+	}
+	reject := func(reason, message string) ingressRuleStatus {
+		ruleStatus.Condition = ingressRejected
+		ruleStatus.Reason = reason
+		ruleStatus.Message = message
+		return ruleStatus
+	}
+
+	if backend.serviceName == "" {
+		return reject(ingressReasonUnsupportedBackend, "Only service backends are supported")
+	}
+	if !backend.isDefault {
+		if _, _, err := getIngressHostMatch(meta, backend.host); err != nil {
+			return reject(ingressReasonInvalidHost, err.Error())
+		}
+		pathMatch := mc.getImplementationSpecificPathMatch(meta)
+		if backend.pathType == networkingV1.PathTypeImplementationSpecific {
+			resolvedPathMatch, err := resolveImplementationSpecificPathMatch(backend.path, pathMatch)
+			if err != nil {
+				return reject(ingressReasonInvalidPath, err.Error())
+			}
+			ruleStatus.PathMatch = resolvedPathMatch
+		}
+		if _, err := getIngressRouteMatch(backend.path, backend.pathType, pathMatch); err != nil {
+			return reject(ingressReasonInvalidPath, err.Error())
+		}
+		if _, err := getIngressPathRewrite(meta, backend.path, backend.pathType, pathMatch); err != nil {
+			return reject(ingressReasonInvalidPath, err.Error())
+		}
+	}
+
+	// The requests for a port the service does not have are routed to all the ports of the service, so that only the
+	// service itself must exist
+	svc := service.MeshService{Name: backend.serviceName, Namespace: meta.Namespace}
+	if mc.kubeController.GetService(svc) == nil {
+		return reject(ingressReasonBackendNotInMesh, fmt.Sprintf("Service %s not found in the namespaces monitored by OSM", svc))
+	}
+	return ruleStatus
+}
@@ -0,0 +1,370 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/ingress"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func newIngressStatusTestService(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testns",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+}
+
+func newIngressStatusTestBackend(name string, port int32) networkingV1.IngressBackend {
+	return networkingV1.IngressBackend{
+		Service: &networkingV1.IngressServiceBackend{
+			Name: name,
+			Port: networkingV1.ServiceBackendPort{Number: port},
+		},
+	}
+}
+
+func TestGetIngressStatusNetworkingV1(t *testing.T) {
+	prefix := networkingV1.PathTypePrefix
+	invalid := networkingV1.PathType("Regex")
+	implementationSpecific := networkingV1.PathTypeImplementationSpecific
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		spec           networkingV1.IngressSpec
+		expectedStatus ingressStatus
+	}{
+		{
+			name: "all the rules are accepted",
+			spec: networkingV1.IngressSpec{
+				DefaultBackend: &networkingV1.IngressBackend{
+					Service: &networkingV1.IngressServiceBackend{
+						Name: "foo",
+						Port: networkingV1.ServiceBackendPort{Name: "http"},
+					},
+				},
+				Rules: []networkingV1.IngressRule{
+					{
+						Host: "foo.com",
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/foo", PathType: &prefix, Backend: newIngressStatusTestBackend("foo", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressAccepted,
+				Rules: []ingressRuleStatus{
+					{Backend: "foo:http", Condition: ingressAccepted},
+					{Host: "foo.com", Path: "/foo", Backend: "foo:80", Condition: ingressAccepted},
+				},
+			},
+		},
+		{
+			name: "rules with a backend not in the mesh are rejected",
+			spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/foo", PathType: &prefix, Backend: newIngressStatusTestBackend("foo", 80)},
+									{Path: "/bar", PathType: &prefix, Backend: newIngressStatusTestBackend("bar", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressPartiallyProgrammed,
+				Rules: []ingressRuleStatus{
+					{Path: "/foo", Backend: "foo:80", Condition: ingressAccepted},
+					{Path: "/bar", Backend: "bar:80", Condition: ingressRejected, Reason: ingressReasonBackendNotInMesh, Message: "Service testns/bar not found in the namespaces monitored by OSM"},
+				},
+			},
+		},
+		{
+			name: "rules with an invalid path or a resource backend are rejected",
+			spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/foo", PathType: &prefix, Backend: networkingV1.IngressBackend{
+										Resource: &corev1.TypedLocalObjectReference{Kind: "StorageBucket", Name: "static"},
+									}},
+									{Path: "/foo.*", PathType: &invalid, Backend: newIngressStatusTestBackend("foo", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressRejected,
+				Rules: []ingressRuleStatus{
+					{Path: "/foo", Condition: ingressRejected, Reason: ingressReasonUnsupportedBackend, Message: "Only service backends are supported"},
+					{Path: "/foo.*", Backend: "foo:80", Condition: ingressRejected, Reason: ingressReasonInvalidPath, Message: "Invalid pathType=Regex"},
+				},
+			},
+		},
+		{
+			name: "implementation specific paths are matched as set by the annotation",
+			annotations: map[string]string{
+				constants.ImplementationSpecificPathMatchAnnotation: "exact",
+			},
+			spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/c++", PathType: &implementationSpecific, Backend: newIngressStatusTestBackend("foo", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressAccepted,
+				Rules: []ingressRuleStatus{
+					{Path: "/c++", PathMatch: constants.ImplementationSpecificPathMatchExact, Backend: "foo:80", Condition: ingressAccepted},
+				},
+			},
+		},
+		{
+			name: "implementation specific paths are rejected with an invalid path match annotation",
+			annotations: map[string]string{
+				constants.ImplementationSpecificPathMatchAnnotation: "glob",
+			},
+			spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/foo", PathType: &implementationSpecific, Backend: newIngressStatusTestBackend("foo", 80)},
+									{Path: "/bar", PathType: &prefix, Backend: newIngressStatusTestBackend("foo", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressPartiallyProgrammed,
+				Rules: []ingressRuleStatus{
+					{Path: "/foo", Backend: "foo:80", Condition: ingressRejected, Reason: ingressReasonInvalidPath, Message: `Invalid path match "glob" for ImplementationSpecific path /foo, must be one of auto, exact, prefix, regex`},
+					{Path: "/bar", Backend: "foo:80", Condition: ingressAccepted},
+				},
+			},
+		},
+		{
+			name: "rules with an invalid host are rejected",
+			annotations: map[string]string{
+				constants.IngressHostRegexAnnotation: "(",
+			},
+			spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{Path: "/foo", PathType: &prefix, Backend: newIngressStatusTestBackend("foo", 80)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedStatus: ingressStatus{
+				ObservedGeneration: 2,
+				Condition:          ingressRejected,
+				Rules: []ingressRuleStatus{
+					{Path: "/foo", Backend: "foo:80", Condition: ingressRejected, Reason: ingressReasonInvalidHost},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().GetService(service.MeshService{Name: "foo", Namespace: "testns"}).Return(newIngressStatusTestService("foo")).AnyTimes()
+			mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+			meshCatalog := &MeshCatalog{
+				kubeController: mockKubeController,
+				configurator:   mockCfg,
+			}
+
+			status := meshCatalog.getIngressStatusNetworkingV1(&networkingV1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ingress-1",
+					Namespace:   "testns",
+					Generation:  2,
+					Annotations: tc.annotations,
+				},
+				Spec: tc.spec,
+			})
+
+			// The message of an invalid host is the error of the regular expression package
+			for i, ruleStatus := range status.Rules {
+				if ruleStatus.Reason == ingressReasonInvalidHost {
+					assert.NotEmpty(ruleStatus.Message)
+					status.Rules[i].Message = ""
+				}
+			}
+			assert.Equal(tc.expectedStatus, status)
+		})
+	}
+}
+
+func TestGetIngressStatusNetworkingV1beta1(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().GetService(service.MeshService{Name: "foo", Namespace: "testns"}).Return(newIngressStatusTestService("foo")).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	meshCatalog := &MeshCatalog{
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
+	}
+
+	status := meshCatalog.getIngressStatusNetworkingV1beta1(&networkingV1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ingress-1",
+			Namespace:  "testns",
+			Generation: 1,
+		},
+		Spec: networkingV1beta1.IngressSpec{
+			Backend: &networkingV1beta1.IngressBackend{
+				ServiceName: "bar",
+				ServicePort: intstr.FromInt(80),
+			},
+			Rules: []networkingV1beta1.IngressRule{
+				{
+					Host: "foo.com",
+					IngressRuleValue: networkingV1beta1.IngressRuleValue{
+						HTTP: &networkingV1beta1.HTTPIngressRuleValue{
+							Paths: []networkingV1beta1.HTTPIngressPath{
+								{
+									Path: "/foo",
+									Backend: networkingV1beta1.IngressBackend{
+										ServiceName: "foo",
+										ServicePort: intstr.FromString("http"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	assert.Equal(ingressStatus{
+		ObservedGeneration: 1,
+		Condition:          ingressPartiallyProgrammed,
+		Rules: []ingressRuleStatus{
+			{Backend: "bar:80", Condition: ingressRejected, Reason: ingressReasonBackendNotInMesh, Message: "Service testns/bar not found in the namespaces monitored by OSM"},
+			{Host: "foo.com", Path: "/foo", PathMatch: constants.ImplementationSpecificPathMatchPrefix, Backend: "foo:http", Condition: ingressAccepted},
+		},
+	}, status)
+}
+
+func TestReportIngressStatus(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ing := &networkingV1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ingress-1",
+			Namespace:  "testns",
+			Generation: 1,
+		},
+		Spec: networkingV1.IngressSpec{
+			DefaultBackend: &networkingV1.IngressBackend{
+				Service: &networkingV1.IngressServiceBackend{
+					Name: "foo",
+					Port: networkingV1.ServiceBackendPort{Number: 80},
+				},
+			},
+		},
+	}
+	kubeClient := testclient.NewSimpleClientset(ing)
+
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(newIngressStatusTestService("foo")).AnyTimes()
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	meshCatalog := &MeshCatalog{
+		ingressMonitor: mockIngressMonitor,
+		kubeController: mockKubeController,
+		kubeClient:     kubeClient,
+		configurator:   mockCfg,
+	}
+
+	// The status of the ingress resource is written to its annotation
+	mockIngressMonitor.EXPECT().ListIngressesNetworkingV1beta1().Return(nil).Times(2)
+	mockIngressMonitor.EXPECT().ListIngressesNetworkingV1().Return([]*networkingV1.Ingress{ing}).Times(1)
+	meshCatalog.reportIngressStatus()
+
+	updated, err := kubeClient.NetworkingV1().Ingresses("testns").Get(context.Background(), "ingress-1", metav1.GetOptions{})
+	assert.Nil(err)
+	var status ingressStatus
+	assert.Nil(json.Unmarshal([]byte(updated.Annotations[constants.IngressStatusAnnotation]), &status))
+	assert.Equal(ingressStatus{
+		ObservedGeneration: 1,
+		Condition:          ingressAccepted,
+		Rules:              []ingressRuleStatus{{Backend: "foo:80", Condition: ingressAccepted}},
+	}, status)
+	actions := len(kubeClient.Actions())
+
+	// The status is not written again when it did not change
+	mockIngressMonitor.EXPECT().ListIngressesNetworkingV1().Return([]*networkingV1.Ingress{updated}).Times(1)
+	meshCatalog.reportIngressStatus()
+	assert.Len(kubeClient.Actions(), actions)
+}
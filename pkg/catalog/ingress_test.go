@@ -6,15 +6,25 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
 	networkingV1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	testclient "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/pointer"
 
+	gatewayV1alpha1 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/ingress"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -28,9 +38,18 @@ func TestGetIngressPoliciesForService(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
 	meshCatalog := &MeshCatalog{
 		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
 	}
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
 
 	type testCase struct {
 		name                    string
@@ -699,20 +718,1049 @@ func TestGetIngressPoliciesForService(t *testing.T) {
 			},
 			excpectError: false,
 		},
+		{
+			name: "Ingress rule without HTTP paths",
+			svc:  service.MeshService{Name: "foo", Namespace: "testns"},
+			ingresses: []*networkingV1beta1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-1",
+						Namespace: "testns",
+						Annotations: map[string]string{
+							constants.OSMKubeResourceMonitorAnnotation: "enabled",
+						},
+					},
+					Spec: networkingV1beta1.IngressSpec{
+						Rules: []networkingV1beta1.IngressRule{
+							{
+								Host: "fake1.com",
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{},
+			excpectError:            false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(tc.svc).Return(tc.ingresses, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(tc.svc).Return(nil, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetHTTPRoutes(tc.svc).Return(nil, nil).Times(1)
+
+			actualPolicies, err := meshCatalog.GetIngressPoliciesForService(tc.svc)
+
+			assert.Equal(tc.excpectError, err != nil)
+			assert.ElementsMatch(tc.expectedTrafficPolicies, actualPolicies)
+		})
+	}
+}
+
+func TestGetIngressPoliciesForServiceNetworkingV1(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	meshCatalog := &MeshCatalog{
+		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
+	}
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+
+	svc := service.MeshService{Name: "foo", Namespace: "testns"}
+	fooBackend := networkingV1.IngressBackend{
+		Service: &networkingV1.IngressServiceBackend{
+			Name: "foo",
+			Port: networkingV1.ServiceBackendPort{
+				Number: fakeIngressPort,
+			},
+		},
+	}
+	fooWeightedCluster := mapset.NewSet(service.WeightedCluster{
+		ClusterName: "testns/foo",
+		Weight:      100,
+	})
+
+	testCases := []struct {
+		name                    string
+		ingresses               []*networkingV1.Ingress
+		expectedTrafficPolicies []*trafficpolicy.InboundTrafficPolicy
+	}{
+		{
+			name: "Ingress rule with a default backend and service backends",
+			ingresses: []*networkingV1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-1",
+						Namespace: "testns",
+					},
+					Spec: networkingV1.IngressSpec{
+						DefaultBackend: &fooBackend,
+						Rules: []networkingV1.IngressRule{
+							{
+								Host: "fake1.com",
+								IngressRuleValue: networkingV1.IngressRuleValue{
+									HTTP: &networkingV1.HTTPIngressRuleValue{
+										Paths: []networkingV1.HTTPIngressPath{
+											{
+												Path:     "/fake1-path1",
+												PathType: (*networkingV1.PathType)(pointer.StringPtr(string(networkingV1.PathTypePrefix))),
+												Backend:  fooBackend,
+											},
+											{
+												Path:    "/fake1-path2",
+												Backend: fooBackend,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{
+				{
+					Name: "ingress-1.testns|*",
+					Hostnames: []string{
+						constants.WildcardHTTPMethod,
+					},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch:   trafficpolicy.WildCardRouteMatch,
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+				{
+					Name: "ingress-1.testns|fake1.com",
+					Hostnames: []string{
+						"fake1.com",
+					},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake1-path1" + prefixMatchPathElementsRegex,
+									PathMatchType: trafficpolicy.PathMatchRegex,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake1-path2",
+									PathMatchType: trafficpolicy.PathMatchPrefix,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Ingress rule with a resource backend and a backend for another service",
+			ingresses: []*networkingV1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-1",
+						Namespace: "testns",
+					},
+					Spec: networkingV1.IngressSpec{
+						Rules: []networkingV1.IngressRule{
+							{
+								Host: "fake1.com",
+								IngressRuleValue: networkingV1.IngressRuleValue{
+									HTTP: &networkingV1.HTTPIngressRuleValue{
+										Paths: []networkingV1.HTTPIngressPath{
+											{
+												Path: "/static",
+												Backend: networkingV1.IngressBackend{
+													Resource: &corev1.TypedLocalObjectReference{
+														Kind: "StorageBucket",
+														Name: "static-assets",
+													},
+												},
+											},
+											{
+												Path: "/bar",
+												Backend: networkingV1.IngressBackend{
+													Service: &networkingV1.IngressServiceBackend{
+														Name: "bar",
+														Port: networkingV1.ServiceBackendPort{
+															Name: "http",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{},
+		},
+		{
+			name: "Ingress rule with ImplementationSpecific paths matched as set by the annotation",
+			ingresses: []*networkingV1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-1",
+						Namespace: "testns",
+						Annotations: map[string]string{
+							constants.ImplementationSpecificPathMatchAnnotation: "exact",
+						},
+					},
+					Spec: networkingV1.IngressSpec{
+						Rules: []networkingV1.IngressRule{
+							{
+								Host: "fake1.com",
+								IngressRuleValue: networkingV1.IngressRuleValue{
+									HTTP: &networkingV1.HTTPIngressRuleValue{
+										Paths: []networkingV1.HTTPIngressPath{
+											{
+												Path:    "/docs/c++",
+												Backend: fooBackend,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{
+				{
+					Name: "ingress-1.testns|fake1.com",
+					Hostnames: []string{
+						"fake1.com",
+					},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/docs/c++",
+									PathMatchType: trafficpolicy.PathMatchExact,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Ingress rule with ImplementationSpecific paths and an invalid path match annotation",
+			ingresses: []*networkingV1.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-1",
+						Namespace: "testns",
+						Annotations: map[string]string{
+							constants.ImplementationSpecificPathMatchAnnotation: "glob",
+						},
+					},
+					Spec: networkingV1.IngressSpec{
+						Rules: []networkingV1.IngressRule{
+							{
+								Host: "fake1.com",
+								IngressRuleValue: networkingV1.IngressRuleValue{
+									HTTP: &networkingV1.HTTPIngressRuleValue{
+										Paths: []networkingV1.HTTPIngressPath{
+											{
+												Path:    "/docs/*",
+												Backend: fooBackend,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(nil, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(tc.ingresses, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(nil, nil).Times(1)
+
+			actualPolicies, err := meshCatalog.GetIngressPoliciesForService(svc)
+
+			assert.Nil(err)
+			assert.ElementsMatch(tc.expectedTrafficPolicies, actualPolicies)
+		})
+	}
+}
+
+func TestGetIngressPoliciesForServiceHTTPRoute(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	meshCatalog := &MeshCatalog{
+		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
+	}
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+
+	svc := service.MeshService{Name: "foo", Namespace: "testns"}
+	fooWeightedCluster := mapset.NewSet(service.WeightedCluster{
+		ClusterName: "testns/foo",
+		Weight:      100,
+	})
+	exact := gatewayV1alpha1.PathMatchExact
+	regularExpression := gatewayV1alpha1.PathMatchRegularExpression
+
+	testCases := []struct {
+		name                    string
+		httpRoutes              []*gatewayV1alpha1.HTTPRoute
+		expectedTrafficPolicies []*trafficpolicy.InboundTrafficPolicy
+	}{
+		{
+			name: "HTTPRoute with hostnames and rules forwarding to the service",
+			httpRoutes: []*gatewayV1alpha1.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "route-1",
+						Namespace: "testns",
+					},
+					Spec: gatewayV1alpha1.HTTPRouteSpec{
+						Hostnames: []string{"fake1.com", "fake2.com"},
+						Rules: []gatewayV1alpha1.HTTPRouteRule{
+							{
+								Matches: []gatewayV1alpha1.HTTPRouteMatch{
+									{
+										Path: &gatewayV1alpha1.HTTPPathMatch{Type: &exact, Value: pointer.StringPtr("/fake-path1")},
+										Headers: &gatewayV1alpha1.HTTPHeaderMatch{
+											Values: map[string]string{"version": "v1.2"},
+										},
+									},
+								},
+								ForwardTo: []gatewayV1alpha1.HTTPRouteForwardTo{
+									{ServiceName: pointer.StringPtr("foo"), Port: &fakeIngressPort},
+								},
+							},
+							{
+								Matches: []gatewayV1alpha1.HTTPRouteMatch{
+									{
+										Path: &gatewayV1alpha1.HTTPPathMatch{Value: pointer.StringPtr("/fake-path2/")},
+									},
+								},
+								ForwardTo: []gatewayV1alpha1.HTTPRouteForwardTo{
+									{ServiceName: pointer.StringPtr("foo"), Port: &fakeIngressPort, Weight: pointer.Int32Ptr(80)},
+									{ServiceName: pointer.StringPtr("bar"), Port: &fakeIngressPort, Weight: pointer.Int32Ptr(20)},
+								},
+							},
+							{
+								// Forwards to a different service
+								ForwardTo: []gatewayV1alpha1.HTTPRouteForwardTo{
+									{ServiceName: pointer.StringPtr("bar"), Port: &fakeIngressPort},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{
+				{
+					Name:      "route-1.testns|fake1.com",
+					Hostnames: []string{"fake1.com"},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake-path1",
+									PathMatchType: trafficpolicy.PathMatchExact,
+									Methods:       []string{constants.WildcardHTTPMethod},
+									Headers:       map[string]string{"version": `v1\.2`},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake-path2" + prefixMatchPathElementsRegex,
+									PathMatchType: trafficpolicy.PathMatchRegex,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+				{
+					Name:      "route-1.testns|fake2.com",
+					Hostnames: []string{"fake2.com"},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake-path1",
+									PathMatchType: trafficpolicy.PathMatchExact,
+									Methods:       []string{constants.WildcardHTTPMethod},
+									Headers:       map[string]string{"version": `v1\.2`},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          "/fake-path2" + prefixMatchPathElementsRegex,
+									PathMatchType: trafficpolicy.PathMatchRegex,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTPRoute without hostnames and matches",
+			httpRoutes: []*gatewayV1alpha1.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "route-2",
+						Namespace: "testns",
+					},
+					Spec: gatewayV1alpha1.HTTPRouteSpec{
+						Rules: []gatewayV1alpha1.HTTPRouteRule{
+							{
+								ForwardTo: []gatewayV1alpha1.HTTPRouteForwardTo{
+									{ServiceName: pointer.StringPtr("foo"), Port: &fakeIngressPort},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{
+				{
+					Name:      "route-2.testns|*",
+					Hostnames: []string{constants.WildcardHTTPMethod},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+									Path:          prefixMatchPathElementsRegex,
+									PathMatchType: trafficpolicy.PathMatchRegex,
+									Methods:       []string{constants.WildcardHTTPMethod},
+								},
+								WeightedClusters: fooWeightedCluster,
+							},
+							AllowedServiceAccounts: mapset.NewSet(wildcardServiceAccount),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTPRoute with an invalid path regular expression",
+			httpRoutes: []*gatewayV1alpha1.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "route-3",
+						Namespace: "testns",
+					},
+					Spec: gatewayV1alpha1.HTTPRouteSpec{
+						Rules: []gatewayV1alpha1.HTTPRouteRule{
+							{
+								Matches: []gatewayV1alpha1.HTTPRouteMatch{
+									{
+										Path: &gatewayV1alpha1.HTTPPathMatch{Type: &regularExpression, Value: pointer.StringPtr("/fake(")},
+									},
+								},
+								ForwardTo: []gatewayV1alpha1.HTTPRouteForwardTo{
+									{ServiceName: pointer.StringPtr("foo"), Port: &fakeIngressPort},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTrafficPolicies: []*trafficpolicy.InboundTrafficPolicy{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(nil, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(nil, nil).Times(1)
+			mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(tc.httpRoutes, nil).Times(1)
+
+			actualPolicies, err := meshCatalog.GetIngressPoliciesForService(svc)
+
+			assert.Nil(err)
+			assert.ElementsMatch(tc.expectedTrafficPolicies, actualPolicies)
+		})
+	}
+}
+
+func TestGetIngressPoliciesForServiceMultiplePorts(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	meshCatalog := &MeshCatalog{
+		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		kubeController: mockKubeController,
+		configurator:   mockCfg,
+	}
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+
+	svc := service.MeshService{Name: "foo", Namespace: "testns"}
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Name: "admin", Port: 9090},
+			},
+		},
+	}).AnyTimes()
+
+	ingresses := []*networkingV1.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ingress-1",
+				Namespace: "testns",
+			},
+			Spec: networkingV1.IngressSpec{
+				Rules: []networkingV1.IngressRule{
+					{
+						Host: "fake1.com",
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{
+										Path: "/fake1-path1",
+										Backend: networkingV1.IngressBackend{
+											Service: &networkingV1.IngressServiceBackend{
+												Name: "foo",
+												Port: networkingV1.ServiceBackendPort{Number: 80},
+											},
+										},
+									},
+									{
+										Path: "/fake1-path2",
+										Backend: networkingV1.IngressBackend{
+											Service: &networkingV1.IngressServiceBackend{
+												Name: "foo",
+												Port: networkingV1.ServiceBackendPort{Name: "admin"},
+											},
+										},
+									},
+									{
+										Path: "/fake1-path3",
+										Backend: networkingV1.IngressBackend{
+											Service: &networkingV1.IngressServiceBackend{
+												Name: "foo",
+												Port: networkingV1.ServiceBackendPort{Name: "unknown"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(nil, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(ingresses, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(nil, nil).Times(1)
 
-	for i, tc := range testCases {
-		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			mockIngressMonitor.EXPECT().GetIngressResources(tc.svc).Return(tc.ingresses, nil).Times(1)
+	actualPolicies, err := meshCatalog.GetIngressPoliciesForService(svc)
+	assert.Nil(err)
+	assert.Len(actualPolicies, 1)
+	assert.Len(actualPolicies[0].Rules, 3)
 
-			actualPolicies, err := meshCatalog.GetIngressPoliciesForService(tc.svc)
+	// The requests for a port of the service are only routed to the target port of the port
+	assert.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: "port-8080", Weight: 100}), actualPolicies[0].Rules[0].Route.WeightedClusters)
+	assert.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: "port-9090", Weight: 100}), actualPolicies[0].Rules[1].Route.WeightedClusters)
 
-			assert.Equal(tc.excpectError, err != nil)
-			assert.ElementsMatch(tc.expectedTrafficPolicies, actualPolicies)
+	// The requests for an unknown port are routed to all the ports of the service
+	assert.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: "testns/foo", Weight: 100}), actualPolicies[0].Rules[2].Route.WeightedClusters)
+}
+
+func TestGetIngressPortClusters(t *testing.T) {
+	svc := service.MeshService{Name: "foo", Namespace: "testns"}
+	defaultClusters := []service.WeightedCluster{getDefaultWeightedClusterForService(svc)}
+
+	testCases := []struct {
+		name                    string
+		ports                   []corev1.ServicePort
+		ingressWeightedClusters []service.WeightedCluster
+		expectedPortClusters    map[string]service.WeightedCluster
+	}{
+		{
+			name: "ports of a service with multiple target ports",
+			ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 81, TargetPort: intstr.FromInt(8081)},
+			},
+			ingressWeightedClusters: defaultClusters,
+			expectedPortClusters: map[string]service.WeightedCluster{
+				"80":   {ClusterName: "port-8080", Weight: 100},
+				"http": {ClusterName: "port-8080", Weight: 100},
+				"81":   {ClusterName: "port-8081", Weight: 100},
+			},
+		},
+		{
+			name: "ports of a service with a single target port",
+			ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Name: "http-alt", Port: 8080},
+			},
+			ingressWeightedClusters: defaultClusters,
+			expectedPortClusters:    nil,
+		},
+		{
+			name: "ports of a service split between the backends of a TrafficSplit",
+			ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 81, TargetPort: intstr.FromInt(8081)},
+			},
+			ingressWeightedClusters: []service.WeightedCluster{
+				{ClusterName: "testns/foo-v1", Weight: 50},
+				{ClusterName: "testns/foo-v2", Weight: 50},
+			},
+			expectedPortClusters: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: tc.ports,
+				},
+			}).AnyTimes()
+			mc := &MeshCatalog{
+				kubeController: mockKubeController,
+			}
+
+			assert.Equal(tc.expectedPortClusters, mc.getIngressPortClusters(svc, tc.ingressWeightedClusters))
+		})
+	}
+}
+
+func TestGetIngressWeightedClusters(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	noWeightSplit := split.TrafficSplit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "no-weight",
+			Namespace: tests.Namespace,
+		},
+		Spec: split.TrafficSplitSpec{
+			Service: tests.BookstoreApexServiceName,
+			Backends: []split.TrafficSplitBackend{
+				{Service: tests.BookstoreV1ServiceName, Weight: 0},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                     string
+		svc                      service.MeshService
+		trafficSplits            []*split.TrafficSplit
+		expectedWeightedClusters []service.WeightedCluster
+	}{
+		{
+			name:          "service is not the apex service of a traffic split",
+			svc:           tests.BookstoreV1Service,
+			trafficSplits: []*split.TrafficSplit{&tests.TrafficSplit},
+			expectedWeightedClusters: []service.WeightedCluster{
+				{ClusterName: service.ClusterName(tests.BookstoreV1Service.String()), Weight: constants.ClusterWeightAcceptAll},
+			},
+		},
+		{
+			name:          "service is the apex service of a traffic split",
+			svc:           tests.BookstoreApexService,
+			trafficSplits: []*split.TrafficSplit{&tests.TrafficSplit},
+			expectedWeightedClusters: []service.WeightedCluster{
+				{ClusterName: service.ClusterName(tests.BookstoreV1Service.String()), Weight: tests.Weight90},
+				{ClusterName: service.ClusterName(tests.BookstoreV2Service.String()), Weight: tests.Weight10},
+			},
+		},
+		{
+			name:          "only the first traffic split of the apex service applies",
+			svc:           tests.BookstoreApexService,
+			trafficSplits: []*split.TrafficSplit{&noWeightSplit, &tests.TrafficSplit},
+			expectedWeightedClusters: []service.WeightedCluster{
+				{ClusterName: service.ClusterName(tests.BookstoreApexService.String()), Weight: constants.ClusterWeightAcceptAll},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mc := &MeshCatalog{
+				meshSpec: mockMeshSpec,
+			}
+			mockMeshSpec.EXPECT().ListTrafficSplits().Return(tc.trafficSplits).AnyTimes()
+
+			actual := mc.getIngressWeightedClusters(tc.svc)
+			assert.ElementsMatch(tc.expectedWeightedClusters, actual)
+		})
+	}
+}
+
+func TestGetIngressHostMatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		host          string
+		expectedHost  string
+		expectedRegex string
+		expectedError bool
+	}{
+		{
+			name:          "literal host",
+			host:          "foo.example.com",
+			expectedHost:  "foo.example.com",
+			expectedRegex: "",
+		},
+		{
+			name:          "literal host is normalized",
+			host:          "Foo.Bücher.example.",
+			expectedHost:  "foo.xn--bcher-kva.example",
+			expectedRegex: "",
+		},
+		{
+			name:          "wildcard host matches a single DNS label",
+			host:          "*.example.com",
+			expectedHost:  "*.example.com",
+			expectedRegex: `(?i)[^.]+\.example\.com(:[0-9]+)?`,
+		},
+		{
+			name:          "wildcard host is normalized",
+			host:          "*.Bücher.example",
+			expectedHost:  "*.xn--bcher-kva.example",
+			expectedRegex: `(?i)[^.]+\.xn--bcher-kva\.example(:[0-9]+)?`,
+		},
+		{
+			name:          "invalid host",
+			host:          "-foo.example.com",
+			expectedError: true,
+		},
+		{
+			name:          "rule without host and without annotation",
+			host:          "",
+			expectedHost:  "",
+			expectedRegex: "",
+		},
+		{
+			name:          "rule without host matching the annotation",
+			annotations:   map[string]string{constants.IngressHostRegexAnnotation: `tenant-[a-z0-9]+\.example\.com`},
+			host:          "",
+			expectedHost:  "",
+			expectedRegex: `(?i:tenant-[a-z0-9]+\.example\.com)(:[0-9]+)?`,
+		},
+		{
+			name:          "annotation does not apply to rules with a host",
+			annotations:   map[string]string{constants.IngressHostRegexAnnotation: `tenant-[a-z0-9]+\.example\.com`},
+			host:          "foo.example.com",
+			expectedHost:  "foo.example.com",
+			expectedRegex: "",
+		},
+		{
+			name:          "invalid regular expression",
+			annotations:   map[string]string{constants.IngressHostRegexAnnotation: `tenant-(`},
+			host:          "",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			host, hostRegex, err := getIngressHostMatch(metav1.ObjectMeta{Annotations: tc.annotations}, tc.host)
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedHost, host)
+			assert.Equal(tc.expectedRegex, hostRegex)
+		})
+	}
+
+	// The authority of the requests is matched regardless of its case
+	_, hostRegex, err := getIngressHostMatch(metav1.ObjectMeta{}, "*.example.com")
+	tassert.Nil(t, err)
+	tassert.Regexp(t, "^(?:"+hostRegex+")$", "Foo.EXAMPLE.com:8080")
+}
+
+func TestWithHostMatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	httpRouteMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          "/foo",
+		PathMatchType: trafficpolicy.PathMatchExact,
+		Headers:       map[string]string{"version": "v1"},
+	}
+
+	// An empty regular expression does not restrict the route match
+	assert.Equal(httpRouteMatch, withHostMatch(httpRouteMatch, ""))
+
+	// The headers of the given route match are not modified, as they may be shared by the route matches of several hosts
+	actual := withHostMatch(httpRouteMatch, `[^.]+\.example\.com(:[0-9]+)?`)
+	assert.Equal(map[string]string{"version": "v1", authorityHeader: `[^.]+\.example\.com(:[0-9]+)?`}, actual.Headers)
+	assert.Equal(map[string]string{"version": "v1"}, httpRouteMatch.Headers)
+}
+
+func TestGetIngressPathRewrite(t *testing.T) {
+	testCases := []struct {
+		name                string
+		rewrite             *string
+		path                string
+		pathType            networkingV1.PathType
+		pathMatch           string
+		expectedPathRewrite *trafficpolicy.PathRewrite
+		expectedError       bool
+	}{
+		{
+			name:                "no annotation",
+			path:                "/api/v1",
+			pathType:            networkingV1.PathTypePrefix,
+			expectedPathRewrite: nil,
+		},
+		{
+			name:     "prefix path stripped",
+			rewrite:  pointer.StringPtr("/"),
+			path:     "/api/v1/",
+			pathType: networkingV1.PathTypePrefix,
+			expectedPathRewrite: &trafficpolicy.PathRewrite{
+				Pattern:      `^/api/v1/?`,
+				Substitution: "/",
+			},
+		},
+		{
+			name:     "prefix path rewritten",
+			rewrite:  pointer.StringPtr("/v2"),
+			path:     "/api/v1",
+			pathType: networkingV1.PathTypePrefix,
+			expectedPathRewrite: &trafficpolicy.PathRewrite{
+				Pattern:      `^/api/v1`,
+				Substitution: "/v2",
+			},
+		},
+		{
+			name:     "exact path rewritten",
+			rewrite:  pointer.StringPtr("/status"),
+			path:     "/api/v1.0/status",
+			pathType: networkingV1.PathTypeExact,
+			expectedPathRewrite: &trafficpolicy.PathRewrite{
+				Pattern:      `^/api/v1\.0/status$`,
+				Substitution: "/status",
+			},
+		},
+		{
+			name:     "implementation specific string prefix rewritten",
+			rewrite:  pointer.StringPtr("/v2"),
+			path:     "/api/v1",
+			pathType: networkingV1.PathTypeImplementationSpecific,
+			expectedPathRewrite: &trafficpolicy.PathRewrite{
+				Pattern:      `^/api/v1`,
+				Substitution: "/v2",
+			},
+		},
+		{
+			name:          "implementation specific regular expression cannot be rewritten",
+			rewrite:       pointer.StringPtr("/v2"),
+			path:          "/api/v[0-9]+",
+			pathType:      networkingV1.PathTypeImplementationSpecific,
+			expectedError: true,
+		},
+		{
+			name:      "implementation specific path matched exactly rewritten",
+			rewrite:   pointer.StringPtr("/v2"),
+			path:      "/api/v1+",
+			pathType:  networkingV1.PathTypeImplementationSpecific,
+			pathMatch: constants.ImplementationSpecificPathMatchExact,
+			expectedPathRewrite: &trafficpolicy.PathRewrite{
+				Pattern:      `^/api/v1\+$`,
+				Substitution: "/v2",
+			},
+		},
+		{
+			name:          "implementation specific path matched as a regular expression cannot be rewritten",
+			rewrite:       pointer.StringPtr("/v2"),
+			path:          "/api/v1",
+			pathType:      networkingV1.PathTypeImplementationSpecific,
+			pathMatch:     constants.ImplementationSpecificPathMatchRegex,
+			expectedError: true,
+		},
+		{
+			name:          "rewritten path is not absolute",
+			rewrite:       pointer.StringPtr("v2"),
+			path:          "/api/v1",
+			pathType:      networkingV1.PathTypePrefix,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			meta := metav1.ObjectMeta{}
+			if tc.rewrite != nil {
+				meta.Annotations = map[string]string{constants.IngressPathRewriteAnnotation: *tc.rewrite}
+			}
+			actual, err := getIngressPathRewrite(meta, tc.path, tc.pathType, tc.pathMatch)
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedPathRewrite, actual)
+		})
+	}
+}
+
+func TestGetIngressRouteMatchImplementationSpecific(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		path                  string
+		pathMatch             string
+		expectedPathMatchType trafficpolicy.PathMatchType
+		expectedError         bool
+	}{
+		{
+			name:                  "auto path match guesses a prefix",
+			path:                  "/foo",
+			pathMatch:             constants.ImplementationSpecificPathMatchAuto,
+			expectedPathMatchType: trafficpolicy.PathMatchPrefix,
+		},
+		{
+			name:                  "auto path match guesses a regular expression",
+			path:                  "/foo/%20bar",
+			pathMatch:             constants.ImplementationSpecificPathMatchAuto,
+			expectedPathMatchType: trafficpolicy.PathMatchRegex,
+		},
+		{
+			name:                  "exact path match",
+			path:                  "/foo/%20bar",
+			pathMatch:             constants.ImplementationSpecificPathMatchExact,
+			expectedPathMatchType: trafficpolicy.PathMatchExact,
+		},
+		{
+			name:                  "prefix path match",
+			path:                  "/c++",
+			pathMatch:             constants.ImplementationSpecificPathMatchPrefix,
+			expectedPathMatchType: trafficpolicy.PathMatchPrefix,
+		},
+		{
+			name:                  "regex path match",
+			path:                  "/foo/.*",
+			pathMatch:             constants.ImplementationSpecificPathMatchRegex,
+			expectedPathMatchType: trafficpolicy.PathMatchRegex,
+		},
+		{
+			name:          "regex path match with an invalid regular expression",
+			path:          "/foo/[",
+			pathMatch:     constants.ImplementationSpecificPathMatchRegex,
+			expectedError: true,
+		},
+		{
+			name:          "invalid path match",
+			path:          "/foo",
+			pathMatch:     "glob",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := getIngressRouteMatch(tc.path, networkingV1.PathTypeImplementationSpecific, tc.pathMatch)
+			assert.Equal(tc.expectedError, err != nil)
+			if err == nil {
+				assert.Equal(tc.path, actual.Path)
+				assert.Equal(tc.expectedPathMatchType, actual.PathMatchType)
+			}
 		})
 	}
 }
 
+func TestGetImplementationSpecificPathMatch(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mc := &MeshCatalog{
+		configurator: mockCfg,
+	}
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchPrefix).Times(1)
+
+	// The annotation overrides the mesh-wide path match
+	assert.Equal(constants.ImplementationSpecificPathMatchRegex, mc.getImplementationSpecificPathMatch(metav1.ObjectMeta{
+		Annotations: map[string]string{constants.ImplementationSpecificPathMatchAnnotation: " Regex "},
+	}))
+	assert.Equal(constants.ImplementationSpecificPathMatchPrefix, mc.getImplementationSpecificPathMatch(metav1.ObjectMeta{}))
+}
+
 func TestBuildIngressPolicyName(t *testing.T) {
 	assert := tassert.New(t)
 	testCases := []struct {
@@ -742,3 +1790,234 @@ func TestBuildIngressPolicyName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIngressForwardedHeaderPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name                 string
+		namespaceAnnotations map[string]string
+		annotations          map[string]string
+		expectedPolicy       trafficpolicy.ForwardedHeaderPolicy
+	}{
+		{
+			name:        "mesh-wide policy without annotations",
+			annotations: nil,
+			expectedPolicy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 1,
+				UseRemoteAddress:  false,
+				SkipXFFAppend:     false,
+			},
+		},
+		{
+			name: "mesh-wide policy overridden by annotations",
+			annotations: map[string]string{
+				constants.XFFNumTrustedHopsAnnotation: "2",
+				constants.UseRemoteAddressAnnotation:  "true",
+				constants.SkipXFFAppendAnnotation:     "true",
+			},
+			expectedPolicy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 2,
+				UseRemoteAddress:  true,
+				SkipXFFAppend:     true,
+			},
+		},
+		{
+			name: "invalid annotations are ignored",
+			annotations: map[string]string{
+				constants.XFFNumTrustedHopsAnnotation: "-1",
+				constants.UseRemoteAddressAnnotation:  "maybe",
+			},
+			expectedPolicy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 1,
+				UseRemoteAddress:  false,
+				SkipXFFAppend:     false,
+			},
+		},
+		{
+			name: "mesh-wide policy overridden by namespace annotations, overridden by service annotations",
+			namespaceAnnotations: map[string]string{
+				constants.XFFNumTrustedHopsAnnotation: "3",
+				constants.UseRemoteAddressAnnotation:  "true",
+			},
+			annotations: map[string]string{
+				constants.XFFNumTrustedHopsAnnotation: "2",
+			},
+			expectedPolicy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 2,
+				UseRemoteAddress:  true,
+				SkipXFFAppend:     false,
+			},
+		},
+		{
+			name: "invalid service annotations fall back to namespace annotations",
+			namespaceAnnotations: map[string]string{
+				constants.UseRemoteAddressAnnotation: "true",
+			},
+			annotations: map[string]string{
+				constants.UseRemoteAddressAnnotation: "maybe",
+			},
+			expectedPolicy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 1,
+				UseRemoteAddress:  true,
+				SkipXFFAppend:     false,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{
+				configurator:   mockCfg,
+				kubeController: mockKubeController,
+			}
+
+			mockCfg.EXPECT().GetXFFNumTrustedHops().Return(uint32(1)).Times(1)
+			mockCfg.EXPECT().UseRemoteAddress().Return(false).Times(1)
+			mockCfg.EXPECT().SkipXFFAppend().Return(false).Times(1)
+			mockKubeController.EXPECT().GetNamespace(svc.Namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Namespace,
+					Annotations: tc.namespaceAnnotations,
+				},
+			}).Times(1)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetIngressForwardedHeaderPolicy(svc))
+		})
+	}
+}
+
+func TestGetIngressBackendPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name           string
+		ingressBackend *policyV1alpha1.IngressBackend
+		expectedPolicy *trafficpolicy.IngressBackendPolicy
+	}{
+		{
+			name:           "no IngressBackend lists the service",
+			ingressBackend: nil,
+			expectedPolicy: nil,
+		},
+		{
+			name: "IngressBackend lists the service",
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{Name: "bookstore-ingress", Namespace: "bookstore-ns"},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 80, Protocol: "http"}},
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 443, Protocol: "HTTPS"}},
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 5432, Protocol: "tcp"}},
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 8443, Protocol: "TLS-Passthrough"}},
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 9000, Protocol: "udp"}},
+						{Name: "bookstore-v2", Port: policyV1alpha1.PortSpec{Number: 8080, Protocol: "http"}},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{Kind: policyV1alpha1.KindServiceAccount, Name: "ingress-nginx", Namespace: "ingress-ns"},
+						{Kind: policyV1alpha1.KindServiceAccount, Name: "gateway"},
+						{Kind: policyV1alpha1.KindAuthenticatedPrincipal, Name: "gateway.example.com"},
+						{Kind: policyV1alpha1.KindIPRange, Name: "10.0.0.0/8"},
+						{Kind: policyV1alpha1.KindIPRange, Name: "not-a-cidr"},
+						{Kind: "Unknown", Name: "unknown"},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:  "bookstore-ns/bookstore-ingress",
+				Ports: map[uint32]string{80: "http", 443: "https", 5432: "tcp", 8443: "tls-passthrough"},
+				Principals: []string{
+					"gateway.bookstore-ns.cluster.local",
+					"gateway.example.com",
+					"ingress-nginx.ingress-ns.cluster.local",
+				},
+				IPRanges:          []string{"10.0.0.0/8"},
+				RequireClientCert: true,
+			},
+		},
+		{
+			name: "IngressBackend requires client certificates validated with a CA bundle",
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{Name: "bookstore-ingress", Namespace: "bookstore-ns"},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 443, Protocol: "https"}},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{Kind: policyV1alpha1.KindIPRange, Name: "10.0.0.0/8"},
+					},
+					TLS: &policyV1alpha1.IngressBackendTLSSpec{
+						RequireClientCert: true,
+						CABundleSecret:    &policyV1alpha1.SecretReference{Name: "ingress-ca"},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:              "bookstore-ns/bookstore-ingress",
+				Ports:             map[uint32]string{443: "https"},
+				IPRanges:          []string{"10.0.0.0/8"},
+				RequireClientCert: true,
+				CABundleSecret:    "bookstore-ns/ingress-ca",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+			mockIngressMonitor.EXPECT().GetIngressBackend(svc).Return(tc.ingressBackend, nil).Times(1)
+			meshCatalog := &MeshCatalog{
+				ingressMonitor: mockIngressMonitor,
+			}
+
+			policy, err := meshCatalog.GetIngressBackendPolicy(svc)
+			assert.Nil(err)
+			assert.Equal(tc.expectedPolicy, policy)
+		})
+	}
+}
+
+func TestGetIngressCABundle(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := testclient.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-ca", Namespace: "bookstore-ns"},
+			Data:       map[string][]byte{constants.KubernetesOpaqueSecretCAKey: []byte("ca-bundle")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-ca", Namespace: "bookstore-ns"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert")},
+		},
+	)
+	meshCatalog := &MeshCatalog{
+		kubeClient: kubeClient,
+	}
+
+	caBundle, err := meshCatalog.GetIngressCABundle("bookstore-ns/ingress-ca")
+	assert.Nil(err)
+	assert.Equal([]byte("ca-bundle"), caBundle)
+
+	_, err = meshCatalog.GetIngressCABundle("bookstore-ns/no-ca")
+	assert.NotNil(err)
+
+	_, err = meshCatalog.GetIngressCABundle("bookstore-ns/missing")
+	assert.NotNil(err)
+}
@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	certificate "github.com/openservicemesh/osm/pkg/certificate"
+	configurator "github.com/openservicemesh/osm/pkg/configurator"
 	endpoint "github.com/openservicemesh/osm/pkg/endpoint"
 	envoy "github.com/openservicemesh/osm/pkg/envoy"
 	service "github.com/openservicemesh/osm/pkg/service"
@@ -17,6 +18,7 @@ import (
 	v1alpha3 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	v1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	v1alpha2 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	v1 "k8s.io/api/core/v1"
 )
 
 // MockMeshCataloger is a mock of MeshCataloger interface
@@ -54,6 +56,221 @@ func (mr *MockMeshCatalogerMockRecorder) ExpectProxy(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpectProxy", reflect.TypeOf((*MockMeshCataloger)(nil).ExpectProxy), arg0)
 }
 
+// FilterEndpointsByTopology mocks base method
+func (m *MockMeshCataloger) FilterEndpointsByTopology(arg0 certificate.CommonName, arg1 service.MeshService, arg2 []endpoint.Endpoint) []endpoint.Endpoint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterEndpointsByTopology", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]endpoint.Endpoint)
+	return ret0
+}
+
+// FilterEndpointsByTopology indicates an expected call of FilterEndpointsByTopology
+func (mr *MockMeshCatalogerMockRecorder) FilterEndpointsByTopology(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterEndpointsByTopology", reflect.TypeOf((*MockMeshCataloger)(nil).FilterEndpointsByTopology), arg0, arg1, arg2)
+}
+
+// GetAdaptiveConcurrencyPolicy mocks base method
+func (m *MockMeshCataloger) GetAdaptiveConcurrencyPolicy(arg0 service.MeshService) trafficpolicy.AdaptiveConcurrencyPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdaptiveConcurrencyPolicy", arg0)
+	ret0, _ := ret[0].(trafficpolicy.AdaptiveConcurrencyPolicy)
+	return ret0
+}
+
+// GetAdaptiveConcurrencyPolicy indicates an expected call of GetAdaptiveConcurrencyPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetAdaptiveConcurrencyPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdaptiveConcurrencyPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetAdaptiveConcurrencyPolicy), arg0)
+}
+
+// GetBandwidthLimitForService mocks base method
+func (m *MockMeshCataloger) GetBandwidthLimitForService(arg0 service.MeshService) trafficpolicy.BandwidthLimit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBandwidthLimitForService", arg0)
+	ret0, _ := ret[0].(trafficpolicy.BandwidthLimit)
+	return ret0
+}
+
+// GetBandwidthLimitForService indicates an expected call of GetBandwidthLimitForService
+func (mr *MockMeshCatalogerMockRecorder) GetBandwidthLimitForService(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBandwidthLimitForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetBandwidthLimitForService), arg0)
+}
+
+// GetEgressCABundle mocks base method
+func (m *MockMeshCataloger) GetEgressCABundle(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressCABundle", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEgressCABundle indicates an expected call of GetEgressCABundle
+func (mr *MockMeshCatalogerMockRecorder) GetEgressCABundle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressCABundle", reflect.TypeOf((*MockMeshCataloger)(nil).GetEgressCABundle), arg0)
+}
+
+// GetEgressClientCertificate mocks base method
+func (m *MockMeshCataloger) GetEgressClientCertificate(arg0 string) ([]byte, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressClientCertificate", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEgressClientCertificate indicates an expected call of GetEgressClientCertificate
+func (mr *MockMeshCatalogerMockRecorder) GetEgressClientCertificate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressClientCertificate", reflect.TypeOf((*MockMeshCataloger)(nil).GetEgressClientCertificate), arg0)
+}
+
+// GetEgressGatewayPolicy mocks base method
+func (m *MockMeshCataloger) GetEgressGatewayPolicy() *trafficpolicy.EgressPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressGatewayPolicy")
+	ret0, _ := ret[0].(*trafficpolicy.EgressPolicy)
+	return ret0
+}
+
+// GetEgressGatewayPolicy indicates an expected call of GetEgressGatewayPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetEgressGatewayPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressGatewayPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetEgressGatewayPolicy))
+}
+
+// GetEgressPolicy mocks base method
+func (m *MockMeshCataloger) GetEgressPolicy(arg0 service.K8sServiceAccount) *trafficpolicy.EgressPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.EgressPolicy)
+	return ret0
+}
+
+// GetEgressPolicy indicates an expected call of GetEgressPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetEgressPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetEgressPolicy), arg0)
+}
+
+// GetFailoverPolicy mocks base method
+func (m *MockMeshCataloger) GetFailoverPolicy(arg0 service.MeshService) *trafficpolicy.FailoverPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailoverPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.FailoverPolicy)
+	return ret0
+}
+
+// GetFailoverPolicy indicates an expected call of GetFailoverPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetFailoverPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailoverPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetFailoverPolicy), arg0)
+}
+
+// GetGRPCHealthCheckPolicy mocks base method
+func (m *MockMeshCataloger) GetGRPCHealthCheckPolicy(arg0 service.MeshService) *trafficpolicy.GRPCHealthCheckPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGRPCHealthCheckPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.GRPCHealthCheckPolicy)
+	return ret0
+}
+
+// GetGRPCHealthCheckPolicy indicates an expected call of GetGRPCHealthCheckPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetGRPCHealthCheckPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGRPCHealthCheckPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetGRPCHealthCheckPolicy), arg0)
+}
+
+// GetInboundConnectionPolicy mocks base method
+func (m *MockMeshCataloger) GetInboundConnectionPolicy(arg0 service.MeshService) trafficpolicy.InboundConnectionPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundConnectionPolicy", arg0)
+	ret0, _ := ret[0].(trafficpolicy.InboundConnectionPolicy)
+	return ret0
+}
+
+// GetInboundConnectionPolicy indicates an expected call of GetInboundConnectionPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetInboundConnectionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundConnectionPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetInboundConnectionPolicy), arg0)
+}
+
+// GetInboundProxyProtocolPorts mocks base method
+func (m *MockMeshCataloger) GetInboundProxyProtocolPorts(arg0 service.MeshService) []uint32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundProxyProtocolPorts", arg0)
+	ret0, _ := ret[0].([]uint32)
+	return ret0
+}
+
+// GetInboundProxyProtocolPorts indicates an expected call of GetInboundProxyProtocolPorts
+func (mr *MockMeshCatalogerMockRecorder) GetInboundProxyProtocolPorts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundProxyProtocolPorts", reflect.TypeOf((*MockMeshCataloger)(nil).GetInboundProxyProtocolPorts), arg0)
+}
+
+// GetIngressAnnotationPolicy mocks base method
+func (m *MockMeshCataloger) GetIngressAnnotationPolicy(arg0 service.MeshService) trafficpolicy.IngressAnnotationPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressAnnotationPolicy", arg0)
+	ret0, _ := ret[0].(trafficpolicy.IngressAnnotationPolicy)
+	return ret0
+}
+
+// GetIngressAnnotationPolicy indicates an expected call of GetIngressAnnotationPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetIngressAnnotationPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressAnnotationPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressAnnotationPolicy), arg0)
+}
+
+// GetIngressBackendPolicy mocks base method
+func (m *MockMeshCataloger) GetIngressBackendPolicy(arg0 service.MeshService) (*trafficpolicy.IngressBackendPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressBackendPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.IngressBackendPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngressBackendPolicy indicates an expected call of GetIngressBackendPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetIngressBackendPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressBackendPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressBackendPolicy), arg0)
+}
+
+// GetIngressCABundle mocks base method
+func (m *MockMeshCataloger) GetIngressCABundle(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressCABundle", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngressCABundle indicates an expected call of GetIngressCABundle
+func (mr *MockMeshCatalogerMockRecorder) GetIngressCABundle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressCABundle", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressCABundle), arg0)
+}
+
+// GetIngressForwardedHeaderPolicy mocks base method
+func (m *MockMeshCataloger) GetIngressForwardedHeaderPolicy(arg0 service.MeshService) trafficpolicy.ForwardedHeaderPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressForwardedHeaderPolicy", arg0)
+	ret0, _ := ret[0].(trafficpolicy.ForwardedHeaderPolicy)
+	return ret0
+}
+
+// GetIngressForwardedHeaderPolicy indicates an expected call of GetIngressForwardedHeaderPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetIngressForwardedHeaderPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressForwardedHeaderPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressForwardedHeaderPolicy), arg0)
+}
+
 // GetIngressPoliciesForService mocks base method
 func (m *MockMeshCataloger) GetIngressPoliciesForService(arg0 service.MeshService) ([]*trafficpolicy.InboundTrafficPolicy, error) {
 	m.ctrl.T.Helper()
@@ -69,19 +286,46 @@ func (mr *MockMeshCatalogerMockRecorder) GetIngressPoliciesForService(arg0 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressPoliciesForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressPoliciesForService), arg0)
 }
 
-// GetPortToProtocolMappingForService mocks base method
-func (m *MockMeshCataloger) GetPortToProtocolMappingForService(arg0 service.MeshService) (map[uint32]string, error) {
+// GetLatestConfigGeneration mocks base method
+func (m *MockMeshCataloger) GetLatestConfigGeneration() *envoy.ConfigGeneration {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPortToProtocolMappingForService", arg0)
-	ret0, _ := ret[0].(map[uint32]string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "GetLatestConfigGeneration")
+	ret0, _ := ret[0].(*envoy.ConfigGeneration)
+	return ret0
+}
+
+// GetLatestConfigGeneration indicates an expected call of GetLatestConfigGeneration
+func (mr *MockMeshCatalogerMockRecorder) GetLatestConfigGeneration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestConfigGeneration", reflect.TypeOf((*MockMeshCataloger)(nil).GetLatestConfigGeneration))
+}
+
+// GetProxyMemoryLimit mocks base method
+func (m *MockMeshCataloger) GetProxyMemoryLimit(arg0 *envoy.Proxy) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyMemoryLimit", arg0)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetProxyMemoryLimit indicates an expected call of GetProxyMemoryLimit
+func (mr *MockMeshCatalogerMockRecorder) GetProxyMemoryLimit(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyMemoryLimit", reflect.TypeOf((*MockMeshCataloger)(nil).GetProxyMemoryLimit), arg0)
+}
+
+// GetProxyOverrides mocks base method
+func (m *MockMeshCataloger) GetProxyOverrides(arg0 *envoy.Proxy) configurator.Overrides {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyOverrides", arg0)
+	ret0, _ := ret[0].(configurator.Overrides)
+	return ret0
 }
 
-// GetPortToProtocolMappingForService indicates an expected call of GetPortToProtocolMappingForService
-func (mr *MockMeshCatalogerMockRecorder) GetPortToProtocolMappingForService(arg0 interface{}) *gomock.Call {
+// GetProxyOverrides indicates an expected call of GetProxyOverrides
+func (mr *MockMeshCatalogerMockRecorder) GetProxyOverrides(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortToProtocolMappingForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetPortToProtocolMappingForService), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyOverrides", reflect.TypeOf((*MockMeshCataloger)(nil).GetProxyOverrides), arg0)
 }
 
 // GetResolvableServiceEndpoints mocks base method
@@ -143,19 +387,131 @@ func (mr *MockMeshCatalogerMockRecorder) GetServicesFromEnvoyCertificate(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServicesFromEnvoyCertificate", reflect.TypeOf((*MockMeshCataloger)(nil).GetServicesFromEnvoyCertificate), arg0)
 }
 
-// GetTargetPortToProtocolMappingForService mocks base method
-func (m *MockMeshCataloger) GetTargetPortToProtocolMappingForService(arg0 service.MeshService) (map[uint32]string, error) {
+// GetTimeoutPolicy mocks base method
+func (m *MockMeshCataloger) GetTimeoutPolicy(arg0 service.MeshService) *trafficpolicy.TimeoutPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeoutPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.TimeoutPolicy)
+	return ret0
+}
+
+// GetTimeoutPolicy indicates an expected call of GetTimeoutPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetTimeoutPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeoutPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetTimeoutPolicy), arg0)
+}
+
+// GetTrafficPriority mocks base method
+func (m *MockMeshCataloger) GetTrafficPriority(arg0 service.MeshService) string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTargetPortToProtocolMappingForService", arg0)
-	ret0, _ := ret[0].(map[uint32]string)
+	ret := m.ctrl.Call(m, "GetTrafficPriority", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTrafficPriority indicates an expected call of GetTrafficPriority
+func (mr *MockMeshCatalogerMockRecorder) GetTrafficPriority(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficPriority", reflect.TypeOf((*MockMeshCataloger)(nil).GetTrafficPriority), arg0)
+}
+
+// GetUpstreamConnectionPolicy mocks base method
+func (m *MockMeshCataloger) GetUpstreamConnectionPolicy(arg0 service.MeshService) *trafficpolicy.UpstreamConnectionPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamConnectionPolicy", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.UpstreamConnectionPolicy)
+	return ret0
+}
+
+// GetUpstreamConnectionPolicy indicates an expected call of GetUpstreamConnectionPolicy
+func (mr *MockMeshCatalogerMockRecorder) GetUpstreamConnectionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamConnectionPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetUpstreamConnectionPolicy), arg0)
+}
+
+// GetUpstreamProxyProtocolVersion mocks base method
+func (m *MockMeshCataloger) GetUpstreamProxyProtocolVersion(arg0 service.MeshService) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamProxyProtocolVersion", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetUpstreamProxyProtocolVersion indicates an expected call of GetUpstreamProxyProtocolVersion
+func (mr *MockMeshCatalogerMockRecorder) GetUpstreamProxyProtocolVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamProxyProtocolVersion", reflect.TypeOf((*MockMeshCataloger)(nil).GetUpstreamProxyProtocolVersion), arg0)
+}
+
+// GetWAFRulesetForService mocks base method
+func (m *MockMeshCataloger) GetWAFRulesetForService(arg0 service.MeshService) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWAFRulesetForService", arg0)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTargetPortToProtocolMappingForService indicates an expected call of GetTargetPortToProtocolMappingForService
-func (mr *MockMeshCatalogerMockRecorder) GetTargetPortToProtocolMappingForService(arg0 interface{}) *gomock.Call {
+// GetWAFRulesetForService indicates an expected call of GetWAFRulesetForService
+func (mr *MockMeshCatalogerMockRecorder) GetWAFRulesetForService(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWAFRulesetForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetWAFRulesetForService), arg0)
+}
+
+// IsEgressAuditEnabled mocks base method
+func (m *MockMeshCataloger) IsEgressAuditEnabled(arg0 *envoy.Proxy) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEgressAuditEnabled", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEgressAuditEnabled indicates an expected call of IsEgressAuditEnabled
+func (mr *MockMeshCatalogerMockRecorder) IsEgressAuditEnabled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressAuditEnabled", reflect.TypeOf((*MockMeshCataloger)(nil).IsEgressAuditEnabled), arg0)
+}
+
+// IsEgressGateway mocks base method
+func (m *MockMeshCataloger) IsEgressGateway(arg0 *envoy.Proxy) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEgressGateway", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEgressGateway indicates an expected call of IsEgressGateway
+func (mr *MockMeshCatalogerMockRecorder) IsEgressGateway(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressGateway", reflect.TypeOf((*MockMeshCataloger)(nil).IsEgressGateway), arg0)
+}
+
+// IsExternalPlaintextTrafficAllowed mocks base method
+func (m *MockMeshCataloger) IsExternalPlaintextTrafficAllowed(arg0 service.MeshService) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsExternalPlaintextTrafficAllowed", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsExternalPlaintextTrafficAllowed indicates an expected call of IsExternalPlaintextTrafficAllowed
+func (mr *MockMeshCatalogerMockRecorder) IsExternalPlaintextTrafficAllowed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExternalPlaintextTrafficAllowed", reflect.TypeOf((*MockMeshCataloger)(nil).IsExternalPlaintextTrafficAllowed), arg0)
+}
+
+// IsNodeProxy mocks base method
+func (m *MockMeshCataloger) IsNodeProxy(arg0 *envoy.Proxy) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNodeProxy", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNodeProxy indicates an expected call of IsNodeProxy
+func (mr *MockMeshCatalogerMockRecorder) IsNodeProxy(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetPortToProtocolMappingForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetTargetPortToProtocolMappingForService), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNodeProxy", reflect.TypeOf((*MockMeshCataloger)(nil).IsNodeProxy), arg0)
 }
 
 // ListAllowedEndpointsForService mocks base method
@@ -217,6 +573,21 @@ func (mr *MockMeshCatalogerMockRecorder) ListAllowedOutboundServicesForIdentity(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllowedOutboundServicesForIdentity", reflect.TypeOf((*MockMeshCataloger)(nil).ListAllowedOutboundServicesForIdentity), arg0)
 }
 
+// ListContainerPortsFromEnvoyCertificate mocks base method
+func (m *MockMeshCataloger) ListContainerPortsFromEnvoyCertificate(arg0 certificate.CommonName) ([]uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContainerPortsFromEnvoyCertificate", arg0)
+	ret0, _ := ret[0].([]uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContainerPortsFromEnvoyCertificate indicates an expected call of ListContainerPortsFromEnvoyCertificate
+func (mr *MockMeshCatalogerMockRecorder) ListContainerPortsFromEnvoyCertificate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainerPortsFromEnvoyCertificate", reflect.TypeOf((*MockMeshCataloger)(nil).ListContainerPortsFromEnvoyCertificate), arg0)
+}
+
 // ListEndpointsForService mocks base method
 func (m *MockMeshCataloger) ListEndpointsForService(arg0 service.MeshService) ([]endpoint.Endpoint, error) {
 	m.ctrl.T.Helper()
@@ -232,6 +603,20 @@ func (mr *MockMeshCatalogerMockRecorder) ListEndpointsForService(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpointsForService", reflect.TypeOf((*MockMeshCataloger)(nil).ListEndpointsForService), arg0)
 }
 
+// ListInboundPolicySet mocks base method
+func (m *MockMeshCataloger) ListInboundPolicySet(arg0 service.K8sServiceAccount, arg1 []service.MeshService) *trafficpolicy.InboundPolicySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInboundPolicySet", arg0, arg1)
+	ret0, _ := ret[0].(*trafficpolicy.InboundPolicySet)
+	return ret0
+}
+
+// ListInboundPolicySet indicates an expected call of ListInboundPolicySet
+func (mr *MockMeshCatalogerMockRecorder) ListInboundPolicySet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInboundPolicySet", reflect.TypeOf((*MockMeshCataloger)(nil).ListInboundPolicySet), arg0, arg1)
+}
+
 // ListInboundTrafficPolicies mocks base method
 func (m *MockMeshCataloger) ListInboundTrafficPolicies(arg0 service.K8sServiceAccount, arg1 []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
 	m.ctrl.T.Helper()
@@ -289,6 +674,35 @@ func (mr *MockMeshCatalogerMockRecorder) ListOutboundTrafficPolicies(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboundTrafficPolicies", reflect.TypeOf((*MockMeshCataloger)(nil).ListOutboundTrafficPolicies), arg0)
 }
 
+// ListPodsForNodeProxy mocks base method
+func (m *MockMeshCataloger) ListPodsForNodeProxy(arg0 *envoy.Proxy) ([]*v1.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPodsForNodeProxy", arg0)
+	ret0, _ := ret[0].([]*v1.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPodsForNodeProxy indicates an expected call of ListPodsForNodeProxy
+func (mr *MockMeshCatalogerMockRecorder) ListPodsForNodeProxy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPodsForNodeProxy", reflect.TypeOf((*MockMeshCataloger)(nil).ListPodsForNodeProxy), arg0)
+}
+
+// ListRoutingPolicies mocks base method
+func (m *MockMeshCataloger) ListRoutingPolicies(arg0 service.K8sServiceAccount) *trafficpolicy.RoutingPolicies {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoutingPolicies", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.RoutingPolicies)
+	return ret0
+}
+
+// ListRoutingPolicies indicates an expected call of ListRoutingPolicies
+func (mr *MockMeshCatalogerMockRecorder) ListRoutingPolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoutingPolicies", reflect.TypeOf((*MockMeshCataloger)(nil).ListRoutingPolicies), arg0)
+}
+
 // ListSMIPolicies mocks base method
 func (m *MockMeshCataloger) ListSMIPolicies() ([]*v1alpha2.TrafficSplit, []service.K8sServiceAccount, []*v1alpha4.HTTPRouteGroup, []*v1alpha3.TrafficTarget) {
 	m.ctrl.T.Helper()
@@ -321,6 +735,21 @@ func (mr *MockMeshCatalogerMockRecorder) ListServiceAccountsForService(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccountsForService", reflect.TypeOf((*MockMeshCataloger)(nil).ListServiceAccountsForService), arg0)
 }
 
+// ListServicePorts mocks base method
+func (m *MockMeshCataloger) ListServicePorts(arg0 service.MeshService) ([]service.MeshService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServicePorts", arg0)
+	ret0, _ := ret[0].([]service.MeshService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServicePorts indicates an expected call of ListServicePorts
+func (mr *MockMeshCatalogerMockRecorder) ListServicePorts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicePorts", reflect.TypeOf((*MockMeshCataloger)(nil).ListServicePorts), arg0)
+}
+
 // RegisterProxy mocks base method
 func (m *MockMeshCataloger) RegisterProxy(arg0 *envoy.Proxy) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// nodeProxyRedirectionCheckInterval is the interval at which the redirection of the traffic of the pods in node proxy
+// mode to the node proxy of their node is checked
+const nodeProxyRedirectionCheckInterval = 30 * time.Second
+
+// IsNodeProxyModePod returns true if the given pod is annotated to be served by the per-node proxy on its node
+// instead of a sidecar proxy.
+func IsNodeProxyModePod(pod *corev1.Pod) bool {
+	return strings.ToLower(pod.Annotations[constants.ProxyModeAnnotation]) == constants.ProxyModeNode
+}
+
+// IsNodeProxy returns true if the given proxy is a per-node proxy, i.e. its xDS certificate was issued
+// for the node proxy service account in the OSM namespace.
+func (mc *MeshCatalog) IsNodeProxy(proxy *envoy.Proxy) bool {
+	svcAccount, err := GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		return false
+	}
+
+	return svcAccount == service.K8sServiceAccount{
+		Name:      constants.NodeProxyName,
+		Namespace: mc.configurator.GetOSMNamespace(),
+	}
+}
+
+// ListPodsForNodeProxy returns the pods in node proxy mode that are scheduled on the node of the given per-node proxy.
+func (mc *MeshCatalog) ListPodsForNodeProxy(proxy *envoy.Proxy) ([]*corev1.Pod, error) {
+	nodeName, err := mc.getNodeProxyNodeName(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	for _, pod := range mc.kubeController.ListPods() {
+		if pod.Spec.NodeName != nodeName || pod.Spec.HostNetwork || pod.Status.PodIP == "" {
+			continue
+		}
+		if !IsNodeProxyModePod(pod) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+// getNodeProxyNodeName returns the name of the node the given per-node proxy runs on.
+// All per-node proxies share a single xDS certificate, so the node cannot be derived from the certificate.
+// Instead, the node proxy pod is looked up using the pod metadata sent by the proxy, and the proxy's connection
+// address is verified to be the IP of that pod. Node proxies run in the host network, so this is the node's IP.
+func (mc *MeshCatalog) getNodeProxyNodeName(proxy *envoy.Proxy) (string, error) {
+	if !proxy.HasPodMetadata() || proxy.PodMetadata.Name == "" {
+		log.Error().Msgf("Pod metadata has not been recorded for node proxy with certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return "", ErrNodeProxyPodNotFound
+	}
+
+	osmNamespace := mc.configurator.GetOSMNamespace()
+	pod, err := mc.kubeClient.CoreV1().Pods(osmNamespace).Get(context.Background(), proxy.PodMetadata.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error retrieving node proxy pod %s/%s", osmNamespace, proxy.PodMetadata.Name)
+		return "", ErrNodeProxyPodNotFound
+	}
+
+	if string(pod.UID) != proxy.PodMetadata.UID || pod.Spec.ServiceAccountName != constants.NodeProxyName {
+		log.Error().Msgf("Pod %s/%s with UID=%s and ServiceAccount=%s is not the node proxy pod with UID=%s",
+			pod.Namespace, pod.Name, pod.UID, pod.Spec.ServiceAccountName, proxy.PodMetadata.UID)
+		return "", ErrNodeProxyPodNotFound
+	}
+
+	if proxy.GetIP() == nil {
+		return "", ErrNodeProxyAddressMismatch
+	}
+	host, _, err := net.SplitHostPort(proxy.GetIP().String())
+	if err != nil || host != pod.Status.PodIP {
+		log.Error().Msgf("Node proxy with certificate SerialNumber=%s connected from %s, expected the IP of pod %s/%s: %s",
+			proxy.GetCertificateSerialNumber(), proxy.GetIP(), pod.Namespace, pod.Name, pod.Status.PodIP)
+		return "", ErrNodeProxyAddressMismatch
+	}
+
+	return pod.Spec.NodeName, nil
+}
+
+// StartNodeProxyRedirectionReporter reports the pods in node proxy mode running on nodes where the redirection of their
+// traffic to the node proxy is not programmed, at the check interval until the stop channel is closed. The traffic of
+// these pods bypasses the mesh: it is neither encrypted nor authorized.
+func (mc *MeshCatalog) StartNodeProxyRedirectionReporter(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(nodeProxyRedirectionCheckInterval)
+		defer ticker.Stop()
+
+		unredirected := mc.reportNodeProxyRedirection(nil)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				unredirected = mc.reportNodeProxyRedirection(unredirected)
+			}
+		}
+	}()
+}
+
+// reportNodeProxyRedirection sets the metric of the number of running pods in node proxy mode whose traffic is not
+// redirected to the node proxy of their node, and records an event for each pod that was not in the given previously
+// unredirected pods. It returns the unredirected pods.
+func (mc *MeshCatalog) reportNodeProxyRedirection(previous map[types.UID]bool) map[types.UID]bool {
+	redirectedNodes, err := mc.listNodeProxyRedirectedNodes()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing the nodes where the traffic of the pods in node proxy mode is redirected")
+		return previous
+	}
+
+	unredirected := make(map[types.UID]bool)
+	for _, pod := range mc.kubeController.ListPods() {
+		if !IsNodeProxyModePod(pod) || pod.Spec.HostNetwork || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if redirectedNodes[pod.Spec.NodeName] {
+			continue
+		}
+		unredirected[pod.UID] = true
+		if !previous[pod.UID] {
+			events.GenericEventRecorder().WarnEvent(events.NodeProxyRedirectionMissing,
+				"Pod %s/%s is in node proxy mode but the redirection of its traffic to the node proxy is not programmed on node %s, its traffic bypasses the mesh",
+				pod.Namespace, pod.Name, pod.Spec.NodeName)
+		}
+	}
+	metricsstore.DefaultMetricsStore.NodeProxyUnredirectedPodCount.Set(float64(len(unredirected)))
+
+	return unredirected
+}
+
+// listNodeProxyRedirectedNodes returns the names of the nodes whose node proxy pod has a ready redirect container,
+// which is ready once the redirection of the traffic of the pods in node proxy mode of the node is programmed
+func (mc *MeshCatalog) listNodeProxyRedirectedNodes() (map[string]bool, error) {
+	nodeProxyPods, err := mc.kubeClient.CoreV1().Pods(mc.configurator.GetOSMNamespace()).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"app": constants.NodeProxyName}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]bool)
+	for _, pod := range nodeProxyPods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == constants.NodeProxyRedirectName && status.Ready {
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+	}
+	return nodes, nil
+}
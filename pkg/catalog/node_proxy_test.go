@@ -0,0 +1,258 @@
+package catalog
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestIsNodeProxyModePod(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:        "pod without proxy mode annotation",
+			annotations: nil,
+			expected:    false,
+		},
+		{
+			name:        "pod in sidecar proxy mode",
+			annotations: map[string]string{constants.ProxyModeAnnotation: constants.ProxyModeSidecar},
+			expected:    false,
+		},
+		{
+			name:        "pod in node proxy mode",
+			annotations: map[string]string{constants.ProxyModeAnnotation: "Node"},
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			assert.Equal(tc.expected, IsNodeProxyModePod(pod))
+		})
+	}
+}
+
+func TestIsNodeProxy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	mc := MeshCatalog{configurator: mockConfigurator}
+
+	nodeProxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.NodeProxyName, "osm-system"), "", nil)
+	assert.True(mc.IsNodeProxy(nodeProxy))
+
+	otherNamespaceProxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.NodeProxyName, "default"), "", nil)
+	assert.False(mc.IsNodeProxy(otherNamespaceProxy))
+
+	sidecarProxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), "bookstore", "default"), "", nil)
+	assert.False(mc.IsNodeProxy(sidecarProxy))
+
+	invalidProxy := envoy.NewProxy(certificate.CommonName("invalid"), "", nil)
+	assert.False(mc.IsNodeProxy(invalidProxy))
+}
+
+func TestListPodsForNodeProxy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	osmNamespace := "osm-system"
+	nodeProxyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osm-node-proxy-abcde",
+			Namespace: osmNamespace,
+			UID:       types.UID("node-proxy-uid"),
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: constants.NodeProxyName,
+			NodeName:           "node-1",
+			HostNetwork:        true,
+		},
+		Status: corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+
+	newPod := func(name, nodeName, podIP string, nodeProxyMode bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{PodIP: podIP},
+		}
+		if nodeProxyMode {
+			pod.Annotations = map[string]string{constants.ProxyModeAnnotation: constants.ProxyModeNode}
+		}
+		return pod
+	}
+	localPod := newPod("local", "node-1", "10.244.1.1", true)
+	localSidecarPod := newPod("local-sidecar", "node-1", "10.244.1.2", false)
+	localPendingPod := newPod("local-pending", "node-1", "", true)
+	remotePod := newPod("remote", "node-2", "10.244.2.1", true)
+
+	testCases := []struct {
+		name          string
+		podMetadata   *envoy.PodMetadata
+		proxyAddr     net.Addr
+		expectedPods  []*corev1.Pod
+		expectedError error
+	}{
+		{
+			name:          "pod metadata not yet recorded",
+			podMetadata:   nil,
+			proxyAddr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000},
+			expectedPods:  nil,
+			expectedError: ErrNodeProxyPodNotFound,
+		},
+		{
+			name:          "pod UID does not match the node proxy pod",
+			podMetadata:   &envoy.PodMetadata{UID: "other-uid", Name: nodeProxyPod.Name},
+			proxyAddr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000},
+			expectedPods:  nil,
+			expectedError: ErrNodeProxyPodNotFound,
+		},
+		{
+			name:          "proxy connected from an address other than its node",
+			podMetadata:   &envoy.PodMetadata{UID: string(nodeProxyPod.UID), Name: nodeProxyPod.Name},
+			proxyAddr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 40000},
+			expectedPods:  nil,
+			expectedError: ErrNodeProxyAddressMismatch,
+		},
+		{
+			name:          "only running node proxy mode pods on the same node are listed",
+			podMetadata:   &envoy.PodMetadata{UID: string(nodeProxyPod.UID), Name: nodeProxyPod.Name},
+			proxyAddr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000},
+			expectedPods:  []*corev1.Pod{localPod},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := testclient.NewSimpleClientset()
+			_, err := kubeClient.CoreV1().Pods(osmNamespace).Create(context.TODO(), nodeProxyPod, metav1.CreateOptions{})
+			assert.Nil(err)
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetOSMNamespace().Return(osmNamespace).AnyTimes()
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{localPod, localSidecarPod, localPendingPod, remotePod}).AnyTimes()
+
+			mc := MeshCatalog{
+				kubeClient:     kubeClient,
+				kubeController: mockKubeController,
+				configurator:   mockConfigurator,
+			}
+
+			proxy := envoy.NewProxy(NewCertCommonNameWithProxyID(uuid.New(), constants.NodeProxyName, osmNamespace), "", tc.proxyAddr)
+			proxy.PodMetadata = tc.podMetadata
+			if tc.podMetadata != nil {
+				proxy.PodMetadata.ServiceAccount = service.K8sServiceAccount{Name: constants.NodeProxyName, Namespace: osmNamespace}
+			}
+
+			pods, err := mc.ListPodsForNodeProxy(proxy)
+			assert.Equal(tc.expectedError, err)
+			assert.ElementsMatch(tc.expectedPods, pods)
+		})
+	}
+}
+
+func TestReportNodeProxyRedirection(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	osmNamespace := "osm-system"
+	newNodeProxyPod := func(nodeName string, redirectReady bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osm-node-proxy-" + nodeName,
+				Namespace: osmNamespace,
+				Labels:    map[string]string{"app": constants.NodeProxyName},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "envoy", Ready: true},
+					{Name: constants.NodeProxyRedirectName, Ready: redirectReady},
+				},
+			},
+		}
+	}
+
+	newPod := func(name, nodeName string, nodeProxyMode bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+			},
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if nodeProxyMode {
+			pod.Annotations = map[string]string{constants.ProxyModeAnnotation: constants.ProxyModeNode}
+		}
+		return pod
+	}
+	redirectedPod := newPod("redirected", "node-1", true)
+	notReadyPod := newPod("redirect-not-ready", "node-2", true)
+	noNodeProxyPod := newPod("no-node-proxy", "node-3", true)
+	sidecarPod := newPod("sidecar", "node-3", false)
+	pendingPod := newPod("pending", "node-3", true)
+	pendingPod.Status.Phase = corev1.PodPending
+
+	kubeClient := testclient.NewSimpleClientset(newNodeProxyPod("node-1", true), newNodeProxyPod("node-2", false))
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return(osmNamespace).AnyTimes()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{redirectedPod, notReadyPod, noNodeProxyPod, sidecarPod, pendingPod}).AnyTimes()
+
+	mc := MeshCatalog{
+		kubeClient:     kubeClient,
+		kubeController: mockKubeController,
+		configurator:   mockConfigurator,
+	}
+
+	unredirected := mc.reportNodeProxyRedirection(nil)
+	assert.Equal(map[types.UID]bool{notReadyPod.UID: true, noNodeProxyPod.UID: true}, unredirected)
+	assert.Equal(float64(2), testutil.ToFloat64(metricsstore.DefaultMetricsStore.NodeProxyUnredirectedPodCount))
+
+	// The pods are no longer reported once the redirection is programmed on their node
+	_, err := kubeClient.CoreV1().Pods(osmNamespace).Update(context.TODO(), newNodeProxyPod("node-2", true), metav1.UpdateOptions{})
+	assert.Nil(err)
+	_, err = kubeClient.CoreV1().Pods(osmNamespace).Create(context.TODO(), newNodeProxyPod("node-3", true), metav1.CreateOptions{})
+	assert.Nil(err)
+
+	unredirected = mc.reportNodeProxyRedirection(unredirected)
+	assert.Empty(unredirected)
+	assert.Equal(float64(0), testutil.ToFloat64(metricsstore.DefaultMetricsStore.NodeProxyUnredirectedPodCount))
+}
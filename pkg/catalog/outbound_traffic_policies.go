@@ -14,25 +14,26 @@ import (
 // ListOutboundTrafficPolicies returns all outbound traffic policies
 // 1. from service discovery for permissive mode
 // 2. for the given service account from SMI Traffic Target and Traffic Split
+// The requests of the routes of the policies are retried by the Retry resources of the given service account.
 func (mc *MeshCatalog) ListOutboundTrafficPolicies(downstreamIdentity service.K8sServiceAccount) []*trafficpolicy.OutboundTrafficPolicy {
+	policies := trafficpolicy.NewOutboundPolicySet()
 	if mc.configurator.IsPermissiveTrafficPolicyMode() {
-		outboundPolicies := []*trafficpolicy.OutboundTrafficPolicy{}
-		mergedPolicies := trafficpolicy.MergeOutboundPolicies(outboundPolicies, mc.buildOutboundPermissiveModePolicies()...)
-		outboundPolicies = mergedPolicies
-		return outboundPolicies
+		policies.Add(trafficpolicy.PermissiveSource, mc.buildOutboundPermissiveModePolicies()...)
+	} else {
+		// The weighted clusters of the routes of the TrafficSplits override the ones of the TrafficTargets
+		policies.Add(trafficpolicy.TrafficTargetSource, mc.listOutboundPoliciesForTrafficTargets(downstreamIdentity)...)
+		policies.Add(trafficpolicy.TrafficSplitSource, mc.listOutboundTrafficPoliciesForTrafficSplits(downstreamIdentity.Namespace)...)
 	}
 
-	outbound := mc.listOutboundPoliciesForTrafficTargets(downstreamIdentity)
-	outboundPoliciesFromSplits := mc.listOutboundTrafficPoliciesForTrafficSplits(downstreamIdentity.Namespace)
-	outbound = trafficpolicy.MergeOutboundPolicies(outbound, outboundPoliciesFromSplits...)
-
+	outbound := policies.Policies()
+	mc.addRetryPolicies(outbound, downstreamIdentity)
 	return outbound
 }
 
 // listOutboundPoliciesForTrafficTargets loops through all SMI Traffic Target resources and returns outbound traffic policies
 // when the given service account matches a source in the Traffic Target resource
 func (mc *MeshCatalog) listOutboundPoliciesForTrafficTargets(downstreamIdentity service.K8sServiceAccount) []*trafficpolicy.OutboundTrafficPolicy {
-	outboundPolicies := []*trafficpolicy.OutboundTrafficPolicy{}
+	policies := trafficpolicy.NewOutboundPolicySet()
 
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
@@ -40,14 +41,13 @@ func (mc *MeshCatalog) listOutboundPoliciesForTrafficTargets(downstreamIdentity
 		}
 
 		for _, source := range t.Spec.Sources {
-			if source.Name == downstreamIdentity.Name && source.Namespace == downstreamIdentity.Namespace { // found outbound
-				mergedPolicies := trafficpolicy.MergeOutboundPolicies(outboundPolicies, mc.buildOutboundPolicies(downstreamIdentity, t)...)
-				outboundPolicies = mergedPolicies
+			if trafficTargetSourceMatches(source, downstreamIdentity) { // found outbound
+				policies.Add(trafficpolicy.TrafficTargetSource, mc.buildOutboundPolicies(downstreamIdentity, t)...)
 				break
 			}
 		}
 	}
-	return outboundPolicies
+	return policies.Policies()
 }
 
 func (mc *MeshCatalog) listOutboundTrafficPoliciesForTrafficSplits(sourceNamespace string) []*trafficpolicy.OutboundTrafficPolicy {
@@ -69,6 +69,11 @@ func (mc *MeshCatalog) listOutboundTrafficPoliciesForTrafficSplits(sourceNamespa
 
 		weightedClusters := []service.WeightedCluster{}
 		for _, backend := range split.Spec.Backends {
+			// A backend without weight does not receive any request, such as the canary of a rollout that has not
+			// started or was aborted by a progressive delivery controller
+			if backend.Weight <= 0 {
+				continue
+			}
 			ms := service.MeshService{Name: backend.Service, Namespace: split.ObjectMeta.Namespace}
 			wc := service.WeightedCluster{
 				ClusterName: service.ClusterName(ms.String()),
@@ -76,9 +81,16 @@ func (mc *MeshCatalog) listOutboundTrafficPoliciesForTrafficSplits(sourceNamespa
 			}
 			weightedClusters = append(weightedClusters, wc)
 		}
+		if len(weightedClusters) == 0 {
+			// The total weight of the clusters of a route must not be zero, so the requests are routed to the apex
+			// service itself, consistently with the requests received from ingress
+			log.Warn().Msgf("TrafficSplit %s/%s has no backend with a weight, routing its requests to service %s", split.Namespace, split.Name, svc)
+			weightedClusters = append(weightedClusters, getDefaultWeightedClusterForService(svc))
+		}
 
-		rwc := trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, weightedClusters)
-		policy.Routes = []*trafficpolicy.RouteWeightedClusters{rwc}
+		// The requests to the apex service time out after its timeouts, regardless of the backend they are routed to
+		rwc := newTimedRoute(trafficpolicy.WildCardRouteMatch, weightedClusters, mc.GetTimeoutPolicy(svc))
+		policy.Routes = []*trafficpolicy.RouteWeightedClusters{&rwc}
 
 		if apexServices.Contains(svc) {
 			log.Error().Msgf("Skipping Traffic Split policy %s in namespaces %s as there is already a traffic split policy for apex service %v", split.Name, split.Namespace, svc)
@@ -99,7 +111,7 @@ func (mc *MeshCatalog) ListAllowedOutboundServicesForIdentity(identity service.K
 	serviceSet := mapset.NewSet()
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		for _, source := range t.Spec.Sources {
-			if source.Name == identity.Name && source.Namespace == identity.Namespace { // found outbound
+			if trafficTargetSourceMatches(source, identity) { // found outbound
 				destServices, err := mc.GetServicesForServiceAccount(service.K8sServiceAccount{
 					Name:      t.Spec.Destination.Name,
 					Namespace: t.Spec.Destination.Namespace,
@@ -145,6 +157,7 @@ func (mc *MeshCatalog) buildOutboundPermissiveModePolicies() []*trafficpolicy.Ou
 			log.Error().Err(err).Msgf("Error adding route to outbound policy in permissive mode for destination %s(%s)", destService.Name, destService.Namespace)
 			continue
 		}
+		mc.setServiceTimeouts(policy, destService)
 		outPolicies = append(outPolicies, policy)
 	}
 	return outPolicies
@@ -174,6 +187,7 @@ func (mc *MeshCatalog) buildOutboundPolicies(source service.K8sServiceAccount, t
 			log.Error().Err(err).Msgf("Error adding Route to outbound policy for source %s(%s) and destination %s (%s)", source.Name, source.Namespace, destService.Name, destService.Namespace)
 			continue
 		}
+		mc.setServiceTimeouts(policy, destService)
 
 		outPolicies = append(outPolicies, policy)
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -257,6 +258,7 @@ func TestListOutboundTrafficPolicies(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
 
 			mockEndpointProvider.EXPECT().GetID().Return("fake").AnyTimes()
 
@@ -288,11 +290,14 @@ func TestListOutboundTrafficPolicies(t *testing.T) {
 				mockKubeController.EXPECT().GetService(tests.BookstoreApexService).Return(tests.NewServiceFixture(tests.BookstoreApexService.Name, tests.BookstoreApexService.Namespace, map[string]string{})).AnyTimes()
 			}
 
+			mockPolicyMonitor.EXPECT().ListRetryPoliciesForSourceIdentity(tc.downstreamSA).Return(nil).AnyTimes()
+
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				configurator:       mockConfigurator,
+				policyController:   mockPolicyMonitor,
 			}
 
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
@@ -403,6 +408,44 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 		"apex-split-1.baz.svc.cluster.local:8888",
 	}
 
+	testSplitWithoutWeight := split.TrafficSplit{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "bar",
+		},
+		Spec: split.TrafficSplitSpec{
+			Service: "apex-split-1",
+			Backends: []split.TrafficSplitBackend{
+				{
+					Service: tests.BookstoreV1ServiceName,
+					Weight:  0,
+				},
+				{
+					Service: tests.BookstoreV2ServiceName,
+					Weight:  100,
+				},
+			},
+		},
+	}
+
+	testSplitWithoutAnyWeight := split.TrafficSplit{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "bar",
+		},
+		Spec: split.TrafficSplitSpec{
+			Service: "apex-split-1",
+			Backends: []split.TrafficSplitBackend{
+				{
+					Service: tests.BookstoreV1ServiceName,
+					Weight:  0,
+				},
+				{
+					Service: tests.BookstoreV2ServiceName,
+					Weight:  0,
+				},
+			},
+		},
+	}
+
 	testCases := []struct {
 		name             string
 		sourceNamespace  string
@@ -601,6 +644,56 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "traffic split with a backend without weight",
+			sourceNamespace: "foo",
+			trafficsplits:   []*split.TrafficSplit{&testSplitWithoutWeight},
+			apexMeshServices: []service.MeshService{
+				{
+					Name:      "apex-split-1",
+					Namespace: "bar",
+				},
+			},
+			expectedPolicies: []*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      "apex-split-1.bar",
+					Hostnames: testSplit1NamespacedHostnames,
+					Routes: []*trafficpolicy.RouteWeightedClusters{
+						{
+							HTTPRouteMatch: tests.WildCardRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/bookstore-v2", Weight: 100},
+							}),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "traffic split without any backend with a weight",
+			sourceNamespace: "foo",
+			trafficsplits:   []*split.TrafficSplit{&testSplitWithoutAnyWeight},
+			apexMeshServices: []service.MeshService{
+				{
+					Name:      "apex-split-1",
+					Namespace: "bar",
+				},
+			},
+			expectedPolicies: []*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      "apex-split-1.bar",
+					Hostnames: testSplit1NamespacedHostnames,
+					Routes: []*trafficpolicy.RouteWeightedClusters{
+						{
+							HTTPRouteMatch: tests.WildCardRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/apex-split-1", Weight: 100},
+							}),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -767,7 +860,7 @@ func TestBuildOutboundPermissiveModePolicies(t *testing.T) {
 				svcFixture := tests.NewServiceFixture(name, namespace, map[string]string{})
 				k8sServices = append(k8sServices, svcFixture)
 				meshSvc := tests.NewMeshServiceFixture(name, namespace)
-				mockKubeController.EXPECT().GetService(meshSvc).Return(svcFixture)
+				mockKubeController.EXPECT().GetService(meshSvc).Return(svcFixture).AnyTimes()
 			}
 
 			mockEndpointProvider.EXPECT().GetID().Return("fake").AnyTimes()
@@ -0,0 +1,35 @@
+package catalog
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// GetTrafficPriority returns the priority class of the traffic to the given service, as set by the traffic priority
+// annotation of the service. The normal priority class is returned if the annotation is not set or invalid.
+func (mc *MeshCatalog) GetTrafficPriority(svc service.MeshService) string {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return constants.TrafficPriorityNormal
+	}
+	return GetServiceTrafficPriority(k8sSvc)
+}
+
+// GetServiceTrafficPriority returns the priority class set by the traffic priority annotation of the given Kubernetes
+// service. The normal priority class is returned if the annotation is not set or invalid.
+func GetServiceTrafficPriority(k8sSvc *corev1.Service) string {
+	priority := strings.ToLower(strings.TrimSpace(k8sSvc.Annotations[constants.TrafficPriorityAnnotation]))
+	switch priority {
+	case "":
+		return constants.TrafficPriorityNormal
+	case constants.TrafficPriorityCritical, constants.TrafficPriorityNormal, constants.TrafficPriorityBestEffort:
+		return priority
+	default:
+		log.Error().Msgf("Invalid annotation value for key %q on service %s/%s: %s", constants.TrafficPriorityAnnotation, k8sSvc.Namespace, k8sSvc.Name, priority)
+		return constants.TrafficPriorityNormal
+	}
+}
@@ -0,0 +1,66 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetTrafficPriority(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedPriority string
+	}{
+		{
+			name:             "service without annotation",
+			annotations:      nil,
+			expectedPriority: constants.TrafficPriorityNormal,
+		},
+		{
+			name:             "critical",
+			annotations:      map[string]string{constants.TrafficPriorityAnnotation: "critical"},
+			expectedPriority: constants.TrafficPriorityCritical,
+		},
+		{
+			name:             "best-effort is case insensitive",
+			annotations:      map[string]string{constants.TrafficPriorityAnnotation: "Best-Effort"},
+			expectedPriority: constants.TrafficPriorityBestEffort,
+		},
+		{
+			name:             "invalid priority",
+			annotations:      map[string]string{constants.TrafficPriorityAnnotation: "urgent"},
+			expectedPriority: constants.TrafficPriorityNormal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPriority, mc.GetTrafficPriority(svc))
+		})
+	}
+}
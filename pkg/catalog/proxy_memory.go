@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// GetProxyMemoryLimit returns the memory limit in bytes of the Envoy container of the pod of the given proxy, or 0 if
+// the container has no memory limit or the proxy is not backed by a pod
+func (mc *MeshCatalog) GetProxyMemoryLimit(proxy *envoy.Proxy) int64 {
+	if proxy.IsDevProxy() {
+		return 0
+	}
+
+	pod, err := GetPodFromCertificate(proxy.GetCertificateCommonName(), mc.kubeController)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up the pod of proxy with SerialNumber=%s, ignoring its memory limit", proxy.GetCertificateSerialNumber())
+		return 0
+	}
+	return getEnvoyContainerMemoryLimit(pod)
+}
+
+// getEnvoyContainerMemoryLimit returns the memory limit in bytes of the Envoy container of the given pod, or 0 if the
+// container has no memory limit
+func getEnvoyContainerMemoryLimit(pod *corev1.Pod) int64 {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != constants.EnvoyContainerName {
+			continue
+		}
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			return limit.Value()
+		}
+	}
+	return 0
+}
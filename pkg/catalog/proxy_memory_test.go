@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetEnvoyContainerMemoryLimit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		containers    []corev1.Container
+		expectedLimit int64
+	}{
+		{
+			name:          "pod without containers",
+			expectedLimit: 0,
+		},
+		{
+			name:          "envoy container without memory limit",
+			containers:    []corev1.Container{{Name: constants.EnvoyContainerName}},
+			expectedLimit: 0,
+		},
+		{
+			name: "envoy container with a memory limit",
+			containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				},
+				{
+					Name: constants.EnvoyContainerName,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+					},
+				},
+			},
+			expectedLimit: 128 * 1024 * 1024,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: tc.containers}}
+			assert.Equal(tc.expectedLimit, getEnvoyContainerMemoryLimit(pod))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// GetInboundProxyProtocolPorts returns the target ports of the given service on which the PROXY protocol is required,
+// as listed by the inbound PROXY protocol ports annotation of the service. Invalid ports are ignored.
+func (mc *MeshCatalog) GetInboundProxyProtocolPorts(svc service.MeshService) []uint32 {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	portsStr, ok := k8sSvc.Annotations[constants.InboundProxyProtocolPortsAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var ports []uint32
+	for _, portStr := range strings.Split(portsStr, ",") {
+		portStr = strings.TrimSpace(portStr)
+		if portStr == "" {
+			continue
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			log.Error().Err(err).Msgf("Invalid port %q in annotation %q on service %s", portStr, constants.InboundProxyProtocolPortsAnnotation, svc)
+			continue
+		}
+		ports = append(ports, uint32(port))
+	}
+
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// GetUpstreamProxyProtocolVersion returns the version of the PROXY protocol clients in the mesh must emit on connections
+// to the given service, as set by the upstream PROXY protocol annotation of the service. An empty version is returned
+// if the PROXY protocol must not be emitted.
+func (mc *MeshCatalog) GetUpstreamProxyProtocolVersion(svc service.MeshService) string {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return ""
+	}
+
+	version := strings.ToLower(strings.TrimSpace(k8sSvc.Annotations[constants.UpstreamProxyProtocolAnnotation]))
+	switch version {
+	case "", constants.ProxyProtocolV1, constants.ProxyProtocolV2:
+		return version
+	default:
+		log.Error().Msgf("Invalid annotation value for key %q on service %s: %s", constants.UpstreamProxyProtocolAnnotation, svc, version)
+		return ""
+	}
+}
@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetInboundProxyProtocolPorts(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		expectedPorts []uint32
+	}{
+		{
+			name:          "service without annotation",
+			annotations:   nil,
+			expectedPorts: nil,
+		},
+		{
+			name:          "ports are sorted",
+			annotations:   map[string]string{constants.InboundProxyProtocolPortsAnnotation: "8443, 80"},
+			expectedPorts: []uint32{80, 8443},
+		},
+		{
+			name:          "invalid ports are ignored",
+			annotations:   map[string]string{constants.InboundProxyProtocolPortsAnnotation: "80,http,0,70000,"},
+			expectedPorts: []uint32{80},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPorts, mc.GetInboundProxyProtocolPorts(svc))
+		})
+	}
+}
+
+func TestGetUpstreamProxyProtocolVersion(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		expectedVersion string
+	}{
+		{
+			name:            "service without annotation",
+			annotations:     nil,
+			expectedVersion: "",
+		},
+		{
+			name:            "version 1",
+			annotations:     map[string]string{constants.UpstreamProxyProtocolAnnotation: "v1"},
+			expectedVersion: constants.ProxyProtocolV1,
+		},
+		{
+			name:            "version 2 is case insensitive",
+			annotations:     map[string]string{constants.UpstreamProxyProtocolAnnotation: "V2"},
+			expectedVersion: constants.ProxyProtocolV2,
+		},
+		{
+			name:            "invalid version",
+			annotations:     map[string]string{constants.UpstreamProxyProtocolAnnotation: "v3"},
+			expectedVersion: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedVersion, mc.GetUpstreamProxyProtocolVersion(svc))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// addRetryPolicies sets the retry policies of the Retry resources whose source is the given downstream identity on the
+// routes of the given outbound traffic policies of their destination services, including the routes to the backends
+// of the TrafficSplits of the services. When several Retry resources specify the same destination, the first one by
+// namespace and name applies. Invalid Retry resources are logged and ignored.
+func (mc *MeshCatalog) addRetryPolicies(policies []*trafficpolicy.OutboundTrafficPolicy, downstreamIdentity service.K8sServiceAccount) {
+	for _, retry := range mc.policyController.ListRetryPoliciesForSourceIdentity(downstreamIdentity) {
+		retryPolicy, err := newRetryPolicy(retry.Spec.RetryPolicy)
+		if err != nil {
+			log.Error().Err(err).Msgf("Ignoring invalid retry policy of Retry %s/%s", retry.Namespace, retry.Name)
+			continue
+		}
+
+		for _, destination := range retry.Spec.Destinations {
+			if destination.Kind != policyV1alpha1.KindService {
+				log.Error().Msgf("Ignoring destination of unsupported kind %q of Retry %s/%s", destination.Kind, retry.Namespace, retry.Name)
+				continue
+			}
+
+			// The service.namespace hostname is a hostname of the outbound policy of the service regardless of the
+			// namespace of the downstream identity
+			hostname := fmt.Sprintf("%s.%s", destination.Name, destination.Namespace)
+			for _, policy := range policies {
+				if !hasHostname(policy.Hostnames, hostname) {
+					continue
+				}
+				for _, route := range policy.Routes {
+					if route.RetryPolicy == nil {
+						route.RetryPolicy = retryPolicy
+					}
+				}
+			}
+		}
+	}
+}
+
+// newRetryPolicy returns the retry policy of the routes for the given retry policy of a Retry resource
+func newRetryPolicy(spec policyV1alpha1.RetryPolicySpec) (*trafficpolicy.RetryPolicy, error) {
+	conditions, err := parseRetryConditions(spec.RetryOn)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy := &trafficpolicy.RetryPolicy{
+		RetryOn:    conditions,
+		NumRetries: spec.NumRetries,
+	}
+	if retryPolicy.PerTryTimeout, err = getRetryDuration(spec.PerTryTimeout, "perTryTimeout"); err != nil {
+		return nil, err
+	}
+	if retryPolicy.RetryBackoffBaseInterval, err = getRetryDuration(spec.RetryBackoffBaseInterval, "retryBackoffBaseInterval"); err != nil {
+		return nil, err
+	}
+	if retryPolicy.RetryBackoffMaxInterval, err = getRetryDuration(spec.RetryBackoffMaxInterval, "retryBackoffMaxInterval"); err != nil {
+		return nil, err
+	}
+	if base, max := retryPolicy.RetryBackoffBaseInterval, retryPolicy.RetryBackoffMaxInterval; base != nil && max != nil && *max < *base {
+		return nil, errors.Errorf("Max interval %s of the retry back-off is less than its base interval %s", *max, *base)
+	}
+	return retryPolicy, nil
+}
+
+// getRetryDuration returns the given duration of the given field of a retry policy, or nil if it is not set. Envoy
+// requires the durations to be positive.
+func getRetryDuration(duration *metav1.Duration, field string) (*time.Duration, error) {
+	if duration == nil {
+		return nil, nil
+	}
+	if duration.Duration <= 0 {
+		return nil, errors.Errorf("Non-positive duration %s of %s", duration.Duration, field)
+	}
+	value := duration.Duration
+	return &value, nil
+}
+
+// hasHostname returns true if the given hostname is one of the given hostnames
+func hasHostname(hostnames []string, hostname string) bool {
+	for _, h := range hostnames {
+		if h == hostname {
+			return true
+		}
+	}
+	return false
+}
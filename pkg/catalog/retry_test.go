@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestAddRetryPolicies(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newRetry := func(name string, retryOn string, destinations ...policyV1alpha1.RetryDestinationSpec) *policyV1alpha1.Retry {
+		return &policyV1alpha1.Retry{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tests.BookbuyerServiceAccount.Namespace},
+			Spec: policyV1alpha1.RetrySpec{
+				Source: policyV1alpha1.RetrySourceSpec{
+					Kind:      policyV1alpha1.KindServiceAccount,
+					Name:      tests.BookbuyerServiceAccount.Name,
+					Namespace: tests.BookbuyerServiceAccount.Namespace,
+				},
+				Destinations: destinations,
+				RetryPolicy:  policyV1alpha1.RetryPolicySpec{RetryOn: retryOn, NumRetries: 3},
+			},
+		}
+	}
+	bookstoreV1 := policyV1alpha1.RetryDestinationSpec{Kind: policyV1alpha1.KindService, Name: tests.BookstoreV1ServiceName, Namespace: tests.Namespace}
+	bookstoreV2 := policyV1alpha1.RetryDestinationSpec{Kind: policyV1alpha1.KindService, Name: tests.BookstoreV2ServiceName, Namespace: tests.Namespace}
+
+	mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+	mockPolicyMonitor.EXPECT().ListRetryPoliciesForSourceIdentity(tests.BookbuyerServiceAccount).Return([]*policyV1alpha1.Retry{
+		newRetry("invalid", "5xx,unknown", bookstoreV2),
+		newRetry("bookstore-v1", "5xx", bookstoreV1, policyV1alpha1.RetryDestinationSpec{Kind: "Pod", Name: "bookstore-v2", Namespace: tests.Namespace}),
+		newRetry("bookstore", "reset", bookstoreV1),
+	}).Times(1)
+	mc := &MeshCatalog{policyController: mockPolicyMonitor}
+
+	newPolicy := func(name string, hostnames []string) *trafficpolicy.OutboundTrafficPolicy {
+		return &trafficpolicy.OutboundTrafficPolicy{
+			Name:      name,
+			Hostnames: hostnames,
+			Routes: []*trafficpolicy.RouteWeightedClusters{
+				{
+					HTTPRouteMatch:   tests.WildCardRouteMatch,
+					WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+			},
+		}
+	}
+	policies := []*trafficpolicy.OutboundTrafficPolicy{
+		newPolicy("bookstore-v1.default", tests.BookstoreV1Hostnames),
+		newPolicy("bookstore-v2.default", tests.BookstoreV2Hostnames),
+	}
+	mc.addRetryPolicies(policies, tests.BookbuyerServiceAccount)
+
+	// The retry policy of the first valid Retry resource of the destination applies to the routes of its policy
+	assert.Equal(&trafficpolicy.RetryPolicy{RetryOn: "5xx", NumRetries: 3}, policies[0].Routes[0].RetryPolicy)
+
+	// Invalid Retry resources and destinations are ignored
+	assert.Nil(policies[1].Routes[0].RetryPolicy)
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	tenMilliseconds := 10 * time.Millisecond
+	oneSecond := time.Second
+
+	testCases := []struct {
+		name                string
+		spec                policyV1alpha1.RetryPolicySpec
+		expectedRetryPolicy *trafficpolicy.RetryPolicy
+		expectedErr         bool
+	}{
+		{
+			name:                "retry conditions",
+			spec:                policyV1alpha1.RetryPolicySpec{RetryOn: "5xx, reset,connect-failure"},
+			expectedRetryPolicy: &trafficpolicy.RetryPolicy{RetryOn: "5xx,reset,connect-failure"},
+		},
+		{
+			name: "retry policy with back-off",
+			spec: policyV1alpha1.RetryPolicySpec{
+				RetryOn:                  "5xx",
+				NumRetries:               5,
+				PerTryTimeout:            &metav1.Duration{Duration: oneSecond},
+				RetryBackoffBaseInterval: &metav1.Duration{Duration: tenMilliseconds},
+				RetryBackoffMaxInterval:  &metav1.Duration{Duration: oneSecond},
+			},
+			expectedRetryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:                  "5xx",
+				NumRetries:               5,
+				PerTryTimeout:            &oneSecond,
+				RetryBackoffBaseInterval: &tenMilliseconds,
+				RetryBackoffMaxInterval:  &oneSecond,
+			},
+		},
+		{
+			name:        "unsupported retry condition",
+			spec:        policyV1alpha1.RetryPolicySpec{RetryOn: "5xx,always"},
+			expectedErr: true,
+		},
+		{
+			name:        "non-positive per try timeout",
+			spec:        policyV1alpha1.RetryPolicySpec{RetryOn: "5xx", PerTryTimeout: &metav1.Duration{}},
+			expectedErr: true,
+		},
+		{
+			name: "max interval of the back-off less than its base interval",
+			spec: policyV1alpha1.RetryPolicySpec{
+				RetryOn:                  "5xx",
+				RetryBackoffBaseInterval: &metav1.Duration{Duration: oneSecond},
+				RetryBackoffMaxInterval:  &metav1.Duration{Duration: tenMilliseconds},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			retryPolicy, err := newRetryPolicy(tc.spec)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedRetryPolicy, retryPolicy)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"fmt"
+
+	mapset "github.com/deckarep/golang-set"
+
+	"github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// ListRoutingPolicies returns the SMI TrafficTargets allowing HTTP traffic to the given identity, and the SMI TrafficSplits
+// routing the outbound traffic of the given identity, so that the traffic stats of its proxies can be attributed to them.
+// No TrafficTargets are returned in permissive traffic policy mode, where traffic is not allowed by TrafficTargets.
+// The dark launches of the services the outbound traffic of the proxy is copied to are also returned.
+func (mc *MeshCatalog) ListRoutingPolicies(proxyIdentity service.K8sServiceAccount) *trafficpolicy.RoutingPolicies {
+	routingPolicies := &trafficpolicy.RoutingPolicies{
+		InboundTrafficTargets: make(map[string][]trafficpolicy.HTTPRouteMatch),
+		OutboundTrafficSplits: make(map[string]string),
+		OutboundDarkLaunches:  mc.listDarkLaunches(proxyIdentity.Namespace),
+	}
+
+	if !mc.configurator.IsPermissiveTrafficPolicyMode() {
+		for _, t := range mc.meshSpec.ListTrafficTargets() {
+			if !isValidTrafficTarget(t) {
+				continue
+			}
+
+			if trafficTargetIdentityToSvcAccount(t.Spec.Destination) != proxyIdentity {
+				continue
+			}
+
+			routeMatches, err := mc.routesFromRules(t.Spec.Rules, t.Namespace)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error finding route matches from TrafficTarget %s in namespace %s", t.Name, t.Namespace)
+				continue
+			}
+			if len(routeMatches) == 0 {
+				continue
+			}
+			routingPolicies.InboundTrafficTargets[fmt.Sprintf("%s/%s", t.Namespace, t.Name)] = routeMatches
+		}
+	}
+
+	// Only the first TrafficSplit of an apex service routes its traffic, consistently with the outbound traffic policies
+	apexServices := mapset.NewSet()
+	for _, split := range mc.meshSpec.ListTrafficSplits() {
+		apexService := service.MeshService{
+			Name:      kubernetes.GetServiceFromHostname(split.Spec.Service),
+			Namespace: split.Namespace,
+		}
+		if apexServices.Contains(apexService) {
+			continue
+		}
+		apexServices.Add(apexService)
+
+		policyName := buildPolicyName(apexService, proxyIdentity.Namespace == apexService.Namespace)
+		routingPolicies.OutboundTrafficSplits[policyName] = fmt.Sprintf("%s/%s", split.Namespace, split.Name)
+	}
+
+	return routingPolicies
+}
@@ -0,0 +1,129 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestListRoutingPolicies(t *testing.T) {
+	bookstoreSplit := &split.TrafficSplit{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "bookstore-split",
+			Namespace: tests.Namespace,
+		},
+		Spec: split.TrafficSplitSpec{
+			Service: tests.BookstoreApexServiceName,
+		},
+	}
+	shadowedSplit := bookstoreSplit.DeepCopy()
+	shadowedSplit.Name = "bookstore-split-shadowed"
+
+	otherNamespaceSplit := bookstoreSplit.DeepCopy()
+	otherNamespaceSplit.Name = "bookstore-split-other"
+	otherNamespaceSplit.Namespace = "other"
+
+	darkLaunchedService := &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        tests.BookstoreV1ServiceName,
+			Namespace:   tests.Namespace,
+			Annotations: map[string]string{constants.DarkLaunchServiceAnnotation: tests.BookstoreV2ServiceName},
+		},
+	}
+	expectedDarkLaunches := map[string]trafficpolicy.DarkLaunchPolicy{
+		tests.BookstoreV1ServiceName: {
+			ShadowCluster: service.ClusterName(tests.Namespace + "/" + tests.BookstoreV2ServiceName),
+			Percentage:    100,
+		},
+	}
+
+	testCases := []struct {
+		name                    string
+		proxyIdentity           service.K8sServiceAccount
+		permissiveMode          bool
+		expectedRoutingPolicies *trafficpolicy.RoutingPolicies
+	}{
+		{
+			name:           "TrafficTargets allowing traffic to the proxy identity",
+			proxyIdentity:  tests.BookstoreServiceAccount,
+			permissiveMode: false,
+			expectedRoutingPolicies: &trafficpolicy.RoutingPolicies{
+				InboundTrafficTargets: map[string][]trafficpolicy.HTTPRouteMatch{
+					tests.Namespace + "/" + tests.TrafficTargetName: {tests.BookstoreBuyHTTPRoute, tests.BookstoreSellHTTPRoute},
+				},
+				OutboundTrafficSplits: map[string]string{
+					"bookstore-apex":       tests.Namespace + "/bookstore-split",
+					"bookstore-apex.other": "other/bookstore-split-other",
+				},
+				OutboundDarkLaunches: expectedDarkLaunches,
+			},
+		},
+		{
+			name:           "no TrafficTargets allowing traffic to the proxy identity",
+			proxyIdentity:  tests.BookbuyerServiceAccount,
+			permissiveMode: false,
+			expectedRoutingPolicies: &trafficpolicy.RoutingPolicies{
+				InboundTrafficTargets: map[string][]trafficpolicy.HTTPRouteMatch{},
+				OutboundTrafficSplits: map[string]string{
+					"bookstore-apex":       tests.Namespace + "/bookstore-split",
+					"bookstore-apex.other": "other/bookstore-split-other",
+				},
+				OutboundDarkLaunches: expectedDarkLaunches,
+			},
+		},
+		{
+			name:           "TrafficTargets are ignored in permissive mode",
+			proxyIdentity:  tests.BookstoreServiceAccount,
+			permissiveMode: true,
+			expectedRoutingPolicies: &trafficpolicy.RoutingPolicies{
+				InboundTrafficTargets: map[string][]trafficpolicy.HTTPRouteMatch{},
+				OutboundTrafficSplits: map[string]string{
+					"bookstore-apex":       tests.Namespace + "/bookstore-split",
+					"bookstore-apex.other": "other/bookstore-split-other",
+				},
+				OutboundDarkLaunches: expectedDarkLaunches,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			mc := MeshCatalog{
+				meshSpec:       mockMeshSpec,
+				configurator:   mockConfigurator,
+				kubeController: mockKubeController,
+			}
+
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficTargets().Return([]*access.TrafficTarget{&tests.TrafficTarget, &tests.BookstoreV2TrafficTarget}).AnyTimes()
+			mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return([]*spec.HTTPRouteGroup{&tests.HTTPRouteGroup}).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficSplits().Return([]*split.TrafficSplit{bookstoreSplit, shadowedSplit, otherNamespaceSplit}).AnyTimes()
+			mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{darkLaunchedService}).AnyTimes()
+
+			actual := mc.ListRoutingPolicies(tc.proxyIdentity)
+			assert.Equal(tc.expectedRoutingPolicies, actual)
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package catalog
 
 import (
-	"reflect"
+	"sort"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -72,6 +75,10 @@ func (mc *MeshCatalog) GetServicesForServiceAccount(sa service.K8sServiceAccount
 		services = append(services, providerServices...)
 	}
 
+	// Different providers may report the same service for a service account, and multiple services may
+	// select the workloads running as this service account. Each service must only be listed once.
+	services = service.DedupMeshServices(services)
+
 	if len(services) == 0 {
 		return nil, ErrServiceNotFoundForAnyProvider
 	}
@@ -85,56 +92,109 @@ func (mc *MeshCatalog) ListServiceAccountsForService(svc service.MeshService) ([
 	return mc.kubeController.ListServiceAccountsForService(svc)
 }
 
-// GetTargetPortToProtocolMappingForService returns a mapping of the service's ports to their corresponding application protocol.
-// The ports returned are the actual ports on which the application exposes the service derived from the service's endpoints,
-// ie. 'spec.ports[].targetPort' instead of 'spec.ports[].port' for a Kubernetes service.
-// The function ensures the port:protocol mapping is the same across different endpoint providers for the service, and returns
-// an error otherwise.
-func (mc *MeshCatalog) GetTargetPortToProtocolMappingForService(svc service.MeshService) (map[uint32]string, error) {
-	var portToProtocolMap, previous map[uint32]string
+// ListServicePorts returns a MeshService per port of the given service, with the port used by downstream clients in their
+// requests, the target port of the service's endpoints the requests are forwarded to, and the application protocol of the
+// port set, ie. 'spec.ports[].port', 'spec.ports[].targetPort' and 'spec.ports[].appProtocol' for a Kubernetes service.
+// Carrying both ports on the same object leaves no ambiguity on which of the two a port refers to. The ports are sorted
+// by port.
+func (mc *MeshCatalog) ListServicePorts(svc service.MeshService) ([]service.MeshService, error) {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil, errors.Wrapf(ErrServiceNotFound, "Error retrieving k8s service %s", svc)
+	}
 
-	for _, provider := range mc.endpointsProviders {
-		current, err := provider.GetTargetPortToProtocolMappingForService(svc)
+	var svcPorts []service.MeshService
+	for _, portSpec := range k8sSvc.Spec.Ports {
+		var appProtocol string
+		if portSpec.AppProtocol != nil {
+			appProtocol = *portSpec.AppProtocol
+		} else {
+			appProtocol = kubernetes.GetAppProtocolFromPortName(portSpec.Name)
+		}
+
+		targetPort, err := mc.getTargetPort(svc, portSpec)
 		if err != nil {
-			return nil, err
+			log.Error().Err(err).Msgf("Error resolving target port of port %d of service %s", portSpec.Port, svc)
 		}
 
-		if previous != nil && !reflect.DeepEqual(previous, current) {
-			log.Error().Msgf("Service %s does not have the same port:protocol map across providers: expected=%v, got=%v", svc, previous, current)
-			return nil, errors.Errorf("Service %s does not have the same port:protocol mapping across providers", svc)
+		svcPorts = append(svcPorts, service.MeshService{
+			Namespace:  svc.Namespace,
+			Name:       svc.Name,
+			Port:       uint32(portSpec.Port),
+			TargetPort: targetPort,
+			Protocol:   appProtocol,
+		})
+	}
+
+	sort.Slice(svcPorts, func(i, j int) bool {
+		return svcPorts[i].Port < svcPorts[j].Port
+	})
+
+	return svcPorts, nil
+}
+
+// getTargetPort resolves the target port of the given port of a Kubernetes service to a port number. A named target port
+// is resolved from the ports of the service's endpoints, which are named after the service port they serve.
+func (mc *MeshCatalog) getTargetPort(svc service.MeshService, portSpec corev1.ServicePort) (uint32, error) {
+	if portSpec.TargetPort.Type == intstr.Int {
+		if portSpec.TargetPort.IntVal == 0 {
+			// The target port defaults to the port when it is not specified
+			return uint32(portSpec.Port), nil
 		}
-		previous = current
+		return uint32(portSpec.TargetPort.IntVal), nil
 	}
-	portToProtocolMap = previous
-	if portToProtocolMap == nil {
-		return nil, errors.Errorf("Error fetching port:protocol mapping for service %s", svc)
+
+	endpoints, err := mc.kubeController.GetEndpoints(svc)
+	if err != nil {
+		return 0, err
+	}
+	if endpoints != nil {
+		for _, subset := range endpoints.Subsets {
+			for _, endpointPort := range subset.Ports {
+				if endpointPort.Name == portSpec.Name {
+					return uint32(endpointPort.Port), nil
+				}
+			}
+		}
 	}
 
-	return portToProtocolMap, nil
+	return 0, errors.Errorf("Named target port %s of service %s not found in its endpoints", portSpec.TargetPort.StrVal, svc)
 }
 
-// GetPortToProtocolMappingForService returns a mapping of the service's ports to their corresponding application protocol,
-// where the ports returned are the ones used by downstream clients in their requests. This can be different from the ports
-// actually exposed by the application binary, ie. 'spec.ports[].port' instead of 'spec.ports[].targetPort' for a Kubernetes service.
-func (mc *MeshCatalog) GetPortToProtocolMappingForService(svc service.MeshService) (map[uint32]string, error) {
-	portToProtocolMap := make(map[uint32]string)
-
+// IsExternalPlaintextTrafficAllowed returns true if the given NodePort or LoadBalancer service is annotated to accept
+// plaintext traffic originating outside the mesh. Such traffic is rejected by the inbound listener by default since it
+// does not match any of the mTLS filter chains. It is not allowed on the backends of an ingress, as plaintext traffic on
+// their ports is already handled by the ingress filter chains.
+func (mc *MeshCatalog) IsExternalPlaintextTrafficAllowed(svc service.MeshService) bool {
 	k8sSvc := mc.kubeController.GetService(svc)
 	if k8sSvc == nil {
-		return nil, errors.Wrapf(ErrServiceNotFound, "Error retrieving k8s service %s", svc)
+		return false
 	}
 
-	for _, portSpec := range k8sSvc.Spec.Ports {
-		var appProtocol string
-		if portSpec.AppProtocol != nil {
-			appProtocol = *portSpec.AppProtocol
-		} else {
-			appProtocol = kubernetes.GetAppProtocolFromPortName(portSpec.Name)
-		}
-		portToProtocolMap[uint32(portSpec.Port)] = appProtocol
+	if k8sSvc.Spec.Type != corev1.ServiceTypeNodePort && k8sSvc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return false
+	}
+
+	allow := strings.ToLower(k8sSvc.Annotations[constants.ExternalPlaintextTrafficAnnotation])
+	switch allow {
+	case "enabled", "yes", "true":
+	case "", "disabled", "no", "false":
+		return false
+	default:
+		log.Error().Msgf("Invalid annotation value for key %q on service %s: %s", constants.ExternalPlaintextTrafficAnnotation, svc, allow)
+		return false
 	}
 
-	return portToProtocolMap, nil
+	ingressPolicies, err := mc.GetIngressPoliciesForService(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up ingress policies for service %s, not allowing external plaintext traffic", svc)
+		return false
+	}
+	if len(ingressPolicies) > 0 {
+		log.Warn().Msgf("Not allowing external plaintext traffic for service %s as it is a backend for an ingress", svc)
+		return false
+	}
+	return true
 }
 
 // listMeshServices returns all services in the mesh
@@ -159,6 +219,12 @@ func (mc *MeshCatalog) getServiceHostnames(meshService service.MeshService, same
 	return hostnames, nil
 }
 
+// getServiceOverrides returns the overrides of the mesh-wide configuration for the given service, by the annotations of
+// its namespace and then of the service itself
+func (mc *MeshCatalog) getServiceOverrides(meshService service.MeshService) configurator.Overrides {
+	return configurator.NewOverrides(mc.kubeController.GetNamespace(meshService.Namespace), mc.kubeController.GetService(meshService))
+}
+
 func getDefaultWeightedClusterForService(meshService service.MeshService) service.WeightedCluster {
 	return service.WeightedCluster{
 		ClusterName: service.ClusterName(meshService.String()),
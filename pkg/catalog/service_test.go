@@ -9,10 +9,14 @@ import (
 	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/kubernetes"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -213,6 +217,58 @@ func TestIsTrafficSplitBackendService(t *testing.T) {
 	}
 }
 
+func TestGetServicesForServiceAccount(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sa := service.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
+	svc1 := service.MeshService{Name: "foo", Namespace: "ns-1"}
+	svc2 := service.MeshService{Name: "foo-grpc", Namespace: "ns-1"}
+
+	testCases := []struct {
+		name              string
+		provider1Services []service.MeshService
+		provider2Services []service.MeshService
+		expectedServices  []service.MeshService
+		expectedError     error
+	}{
+		{
+			name:              "multiple services selecting the same workload are listed once across providers",
+			provider1Services: []service.MeshService{svc1, svc2},
+			provider2Services: []service.MeshService{svc2, svc1},
+			expectedServices:  []service.MeshService{svc1, svc2},
+			expectedError:     nil,
+		},
+		{
+			name:              "no services found for the service account",
+			provider1Services: nil,
+			provider2Services: nil,
+			expectedServices:  nil,
+			expectedError:     ErrServiceNotFoundForAnyProvider,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockProvider1 := endpoint.NewMockProvider(mockCtrl)
+			mockProvider2 := endpoint.NewMockProvider(mockCtrl)
+			mc := &MeshCatalog{
+				endpointsProviders: []endpoint.Provider{mockProvider1, mockProvider2},
+			}
+
+			mockProvider1.EXPECT().GetID().Return("provider-1").AnyTimes()
+			mockProvider2.EXPECT().GetID().Return("provider-2").AnyTimes()
+			mockProvider1.EXPECT().GetServicesForServiceAccount(sa).Return(tc.provider1Services, nil).Times(1)
+			mockProvider2.EXPECT().GetServicesForServiceAccount(sa).Return(tc.provider2Services, nil).Times(1)
+
+			services, err := mc.GetServicesForServiceAccount(sa)
+			assert.Equal(tc.expectedServices, services)
+			assert.Equal(tc.expectedError, err)
+		})
+	}
+}
+
 func TestListServiceAccountsForService(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -256,105 +312,7 @@ func TestListServiceAccountsForService(t *testing.T) {
 	}
 }
 
-func TestGetPortToProtocolMappingForService(t *testing.T) {
-	assert := tassert.New(t)
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	type endpointProviderConfig struct {
-		provider          *endpoint.MockProvider
-		portToProtocolMap map[uint32]string
-		err               error
-	}
-
-	testCases := []struct {
-		name                      string
-		providerConfigs           []endpointProviderConfig
-		expectedPortToProtocolMap map[uint32]string
-		expectError               bool
-	}{
-		{
-			// Test case 1
-			name: "multiple providers correctly returning the same port:protocol mapping",
-			providerConfigs: []endpointProviderConfig{
-				{
-					// provider 1
-					provider:          endpoint.NewMockProvider(mockCtrl),
-					portToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-					err:               nil,
-				},
-				{
-					// provider 2
-					provider:          endpoint.NewMockProvider(mockCtrl),
-					portToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-					err:               nil,
-				},
-			},
-			expectedPortToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-			expectError:               false,
-		},
-
-		{
-			// Test case 2
-			name: "multiple providers incorrectly returning different port:protocol mapping",
-			providerConfigs: []endpointProviderConfig{
-				{
-					// provider 1
-					provider:          endpoint.NewMockProvider(mockCtrl),
-					portToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-					err:               nil,
-				},
-				{
-					// provider 2
-					provider:          endpoint.NewMockProvider(mockCtrl),
-					portToProtocolMap: map[uint32]string{80: "tcp", 90: "http"},
-					err:               nil,
-				},
-			},
-			expectedPortToProtocolMap: nil,
-			expectError:               true,
-		},
-
-		{
-			// Test case 3
-			name: "single provider correctly returning port:protocol mapping",
-			providerConfigs: []endpointProviderConfig{
-				{
-					// provider 1
-					provider:          endpoint.NewMockProvider(mockCtrl),
-					portToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-					err:               nil,
-				},
-			},
-			expectedPortToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-			expectError:               false,
-		},
-	}
-
-	testSvc := service.MeshService{Name: "foo", Namespace: "bar"}
-
-	for i, tc := range testCases {
-		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			// Create a list of providers for catalog and mock their calls based on the given config
-			var allProviders []endpoint.Provider
-			for _, providerConfig := range tc.providerConfigs {
-				allProviders = append(allProviders, providerConfig.provider)
-				providerConfig.provider.EXPECT().GetTargetPortToProtocolMappingForService(testSvc).Return(providerConfig.portToProtocolMap, providerConfig.err).Times(1)
-			}
-
-			mc := &MeshCatalog{
-				endpointsProviders: allProviders,
-			}
-
-			actualPortToProtocolMap, err := mc.GetTargetPortToProtocolMappingForService(testSvc)
-
-			assert.Equal(tc.expectError, err != nil)
-			assert.Equal(tc.expectedPortToProtocolMap, actualPortToProtocolMap)
-		})
-	}
-}
-
-func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
+func TestListServicePorts(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -366,11 +324,16 @@ func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
 	appProtocolTCP := "tcp"
 	appProtocolHTTP := "http"
 
+	newSvcPort := func(port, targetPort uint32, protocol string) service.MeshService {
+		return service.MeshService{Namespace: svc.Namespace, Name: svc.Name, Port: port, TargetPort: targetPort, Protocol: protocol}
+	}
+
 	testCases := []struct {
-		name                      string
-		service                   *corev1.Service
-		expectedPortToProtocolMap map[uint32]string
-		expectError               bool
+		name             string
+		service          *corev1.Service
+		endpoints        *corev1.Endpoints
+		expectedSvcPorts []service.MeshService
+		expectError      bool
 	}{
 		{
 			// Test case 1
@@ -383,27 +346,21 @@ func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
 						{
-							Name: "port1",
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.String,
-								IntVal: 8080,
-							},
-							Port: 80,
+							Name:       "port2",
+							TargetPort: intstr.FromInt(9090),
+							Protocol:   corev1.ProtocolTCP,
+							Port:       90,
 						},
 						{
-							Name: "port2",
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.String,
-								IntVal: 9090,
-							},
-							Protocol: corev1.ProtocolTCP,
-							Port:     90,
+							Name:       "port1",
+							TargetPort: intstr.FromInt(8080),
+							Port:       80,
 						},
 					},
 				},
 			},
-			expectedPortToProtocolMap: map[uint32]string{80: "http", 90: "http"},
-			expectError:               false,
+			expectedSvcPorts: []service.MeshService{newSvcPort(80, 8080, "http"), newSvcPort(90, 9090, "http")},
+			expectError:      false,
 		},
 
 		{
@@ -417,33 +374,27 @@ func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
 						{
-							Name: "port1",
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.String,
-								IntVal: 8080,
-							},
+							Name:        "port1",
+							TargetPort:  intstr.FromInt(8080),
 							AppProtocol: &appProtocolHTTP,
 							Port:        80,
 						},
 						{
-							Name: "port2",
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.String,
-								IntVal: 9090,
-							},
+							Name:        "port2",
+							TargetPort:  intstr.FromInt(9090),
 							Port:        90,
 							AppProtocol: &appProtocolTCP,
 						},
 					},
 				},
 			},
-			expectedPortToProtocolMap: map[uint32]string{80: "http", 90: "tcp"},
-			expectError:               false,
+			expectedSvcPorts: []service.MeshService{newSvcPort(80, 8080, "http"), newSvcPort(90, 9090, "tcp")},
+			expectError:      false,
 		},
 
 		{
 			// Test case 3
-			name: "service with appProtocol and named port specified",
+			name: "service with appProtocol and named port specified, and no target port",
 			service: &corev1.Service{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      svc.Name,
@@ -452,27 +403,64 @@ func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
 						{
-							Name: "http-port1",
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.String,
-								IntVal: 8080,
-							},
+							Name:        "http-port1",
 							AppProtocol: &appProtocolTCP, // takes precedence over 'Name'
 							Port:        80,
 						},
 					},
 				},
 			},
-			expectedPortToProtocolMap: map[uint32]string{80: "tcp"},
-			expectError:               false,
+			expectedSvcPorts: []service.MeshService{newSvcPort(80, 80, "tcp")},
+			expectError:      false,
 		},
 
 		{
 			// Test case 4
-			name:                      "service doesn't exist",
-			service:                   nil,
-			expectedPortToProtocolMap: nil,
-			expectError:               true,
+			name: "service with named target ports resolved from its endpoints",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svc.Name,
+					Namespace: svc.Namespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "http-web",
+							TargetPort: intstr.FromString("web"),
+							Port:       80,
+						},
+						{
+							Name:       "tcp-db",
+							TargetPort: intstr.FromString("db"),
+							Port:       5432,
+						},
+					},
+				},
+			},
+			endpoints: &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      svc.Name,
+					Namespace: svc.Namespace,
+				},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Ports: []corev1.EndpointPort{
+							{Name: "http-web", Port: 8080},
+						},
+					},
+				},
+			},
+			// The target port of 'tcp-db' is not found in the endpoints
+			expectedSvcPorts: []service.MeshService{newSvcPort(80, 8080, "http"), newSvcPort(5432, 0, "tcp")},
+			expectError:      false,
+		},
+
+		{
+			// Test case 5
+			name:             "service doesn't exist",
+			service:          nil,
+			expectedSvcPorts: nil,
+			expectError:      true,
 		},
 	}
 
@@ -484,15 +472,160 @@ func TestGetPortToProtocolMappingForResolvableService(t *testing.T) {
 			}
 
 			mockKubeController.EXPECT().GetService(svc).Return(tc.service).Times(1)
+			mockKubeController.EXPECT().GetEndpoints(svc).Return(tc.endpoints, nil).AnyTimes()
 
-			actualPortToProtocolMap, err := mc.GetPortToProtocolMappingForService(svc)
+			actualSvcPorts, err := mc.ListServicePorts(svc)
 
 			assert.Equal(tc.expectError, err != nil)
-			assert.Equal(tc.expectedPortToProtocolMap, actualPortToProtocolMap)
+			assert.Equal(tc.expectedSvcPorts, actualSvcPorts)
 		})
 	}
 }
 
+func TestIsExternalPlaintextTrafficAllowed(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	testCases := []struct {
+		name        string
+		serviceType corev1.ServiceType
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:        "NodePort service with annotation enabled",
+			serviceType: corev1.ServiceTypeNodePort,
+			annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "enabled"},
+			expected:    true,
+		},
+		{
+			name:        "LoadBalancer service with annotation set to true",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "True"},
+			expected:    true,
+		},
+		{
+			name:        "LoadBalancer service without annotation",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			annotations: nil,
+			expected:    false,
+		},
+		{
+			name:        "NodePort service with annotation disabled",
+			serviceType: corev1.ServiceTypeNodePort,
+			annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "disabled"},
+			expected:    false,
+		},
+		{
+			name:        "NodePort service with invalid annotation value",
+			serviceType: corev1.ServiceTypeNodePort,
+			annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "invalid"},
+			expected:    false,
+		},
+		{
+			name:        "ClusterIP service with annotation enabled",
+			serviceType: corev1.ServiceTypeClusterIP,
+			annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "enabled"},
+			expected:    false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockKubeController := kubernetes.NewMockController(mockCtrl)
+			mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+			mc := &MeshCatalog{
+				kubeController: mockKubeController,
+				ingressMonitor: mockIngressMonitor,
+			}
+
+			// The service is not an ingress backend
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(nil, nil).AnyTimes()
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(nil, nil).AnyTimes()
+			mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(nil, nil).AnyTimes()
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+				Spec: corev1.ServiceSpec{
+					Type: tc.serviceType,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expected, mc.IsExternalPlaintextTrafficAllowed(svc))
+		})
+	}
+
+	// Service that doesn't exist
+	mockKubeController := kubernetes.NewMockController(mockCtrl)
+	mc := &MeshCatalog{
+		kubeController: mockKubeController,
+	}
+	mockKubeController.EXPECT().GetService(svc).Return(nil).Times(1)
+	assert.False(mc.IsExternalPlaintextTrafficAllowed(svc))
+}
+
+func TestIsExternalPlaintextTrafficAllowedForIngressBackend(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+
+	mockKubeController := kubernetes.NewMockController(mockCtrl)
+	mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mc := &MeshCatalog{
+		kubeController: mockKubeController,
+		ingressMonitor: mockIngressMonitor,
+		meshSpec:       mockMeshSpec,
+		configurator:   mockCfg,
+	}
+
+	mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        svc.Name,
+			Namespace:   svc.Namespace,
+			Annotations: map[string]string{constants.ExternalPlaintextTrafficAnnotation: "enabled"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort,
+		},
+	}).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockCfg.EXPECT().GetImplementationSpecificPathMatch().Return(constants.ImplementationSpecificPathMatchAuto).AnyTimes()
+
+	// Plaintext traffic on the ports of an ingress backend is handled by the ingress filter chains
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return([]*networkingV1beta1.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ingress",
+				Namespace: svc.Namespace,
+			},
+			Spec: networkingV1beta1.IngressSpec{
+				Backend: &networkingV1beta1.IngressBackend{
+					ServiceName: svc.Name,
+					ServicePort: intstr.FromInt(80),
+				},
+			},
+		},
+	}, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1(svc).Return(nil, nil).Times(1)
+	mockIngressMonitor.EXPECT().GetHTTPRoutes(svc).Return(nil, nil).Times(1)
+	assert.False(mc.IsExternalPlaintextTrafficAllowed(svc))
+
+	// Plaintext traffic is not allowed when the ingress resources of the service cannot be looked up
+	mockIngressMonitor.EXPECT().GetIngressNetworkingV1beta1(svc).Return(nil, errors.New("error")).Times(1)
+	assert.False(mc.IsExternalPlaintextTrafficAllowed(svc))
+}
+
 func TestListMeshServices(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// routeTimeoutsSpec is the type used to represent the timeouts of a match in the route timeouts annotation of an
+// HTTPRouteGroup
+type routeTimeoutsSpec struct {
+	RequestTimeout    string `json:"requestTimeout,omitempty"`
+	StreamIdleTimeout string `json:"streamIdleTimeout,omitempty"`
+}
+
+// GetTimeoutPolicy returns the timeouts of the requests to the given service, as set by the timeout annotations of the
+// service. Nil is returned if the service does not set any timeout. Invalid annotations are logged and ignored, so that
+// the timeouts of the proxy apply.
+func (mc *MeshCatalog) GetTimeoutPolicy(svc service.MeshService) *trafficpolicy.TimeoutPolicy {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	var policy trafficpolicy.TimeoutPolicy
+	var err error
+	if policy.RequestTimeout, err = parseDurationAnnotation(k8sSvc.ObjectMeta, constants.RequestTimeoutAnnotation); err != nil {
+		log.Error().Err(err).Msgf("Ignoring request timeout of service %s", svc)
+	}
+	if policy.StreamIdleTimeout, err = parseDurationAnnotation(k8sSvc.ObjectMeta, constants.StreamIdleTimeoutAnnotation); err != nil {
+		log.Error().Err(err).Msgf("Ignoring stream idle timeout of service %s", svc)
+	}
+	if policy.IdleTimeout, err = parseDurationAnnotation(k8sSvc.ObjectMeta, constants.IdleTimeoutAnnotation); err != nil {
+		log.Error().Err(err).Msgf("Ignoring idle timeout of service %s", svc)
+	}
+
+	if policy == (trafficpolicy.TimeoutPolicy{}) {
+		return nil
+	}
+	return &policy
+}
+
+// getRouteTimeoutsPerRoute returns the timeouts of the matches of the HTTPRouteGroups, as set by their route timeouts
+// annotation. The HTTPRouteGroups with an invalid annotation are logged and ignored.
+func (mc *MeshCatalog) getRouteTimeoutsPerRoute() map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy {
+	routeTimeouts := make(map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy)
+	for _, routeGroup := range mc.meshSpec.ListHTTPTrafficSpecs() {
+		timeouts, err := getRouteTimeouts(routeGroup)
+		if err != nil {
+			log.Error().Err(err).Msgf("Ignoring route timeouts of HTTPRouteGroup %s/%s", routeGroup.Namespace, routeGroup.Name)
+			continue
+		}
+		if len(timeouts) == 0 {
+			continue
+		}
+		routeTimeouts[mc.getTrafficSpecName(httpRouteGroupKind, routeGroup.Namespace, routeGroup.Name)] = timeouts
+	}
+	return routeTimeouts
+}
+
+// getRouteTimeouts returns the timeouts of the matches of the given HTTPRouteGroup specified by its route timeouts
+// annotation, or nil if the annotation is not set
+func getRouteTimeouts(routeGroup *spec.HTTPRouteGroup) (map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy, error) {
+	value, ok := routeGroup.Annotations[constants.RouteTimeoutsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var specs map[string]routeTimeoutsSpec
+	if err := json.Unmarshal([]byte(value), &specs); err != nil {
+		return nil, errors.Wrapf(err, "Invalid JSON in annotation %s", constants.RouteTimeoutsAnnotation)
+	}
+
+	timeouts := make(map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy)
+	for matchName, timeoutsSpec := range specs {
+		policy := &trafficpolicy.TimeoutPolicy{}
+		var err error
+		if policy.RequestTimeout, err = parseTimeout(timeoutsSpec.RequestTimeout); err != nil {
+			return nil, errors.Wrapf(err, "Invalid requestTimeout of match %s", matchName)
+		}
+		if policy.StreamIdleTimeout, err = parseTimeout(timeoutsSpec.StreamIdleTimeout); err != nil {
+			return nil, errors.Wrapf(err, "Invalid streamIdleTimeout of match %s", matchName)
+		}
+		timeouts[trafficpolicy.TrafficSpecMatchName(matchName)] = policy
+	}
+	return timeouts, nil
+}
+
+// parseTimeout returns the given timeout, or nil if it is empty
+func parseTimeout(value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return nil, err
+	}
+	if timeout < 0 {
+		return nil, errors.Errorf("Negative timeout %q", value)
+	}
+	return &timeout, nil
+}
+
+// newTimedRoute returns the route for the given route match and weighted clusters, whose requests time out after the
+// timeouts of the first given timeout policy setting them. The timeouts of a match of an HTTPRouteGroup are thereby
+// given before the ones of the service to override them.
+func newTimedRoute(httpRouteMatch trafficpolicy.HTTPRouteMatch, weightedClusters []service.WeightedCluster, timeoutPolicies ...*trafficpolicy.TimeoutPolicy) trafficpolicy.RouteWeightedClusters {
+	route := trafficpolicy.NewRouteWeightedCluster(httpRouteMatch, weightedClusters)
+	setRouteTimeouts(route, timeoutPolicies...)
+	return *route
+}
+
+// setServiceTimeouts sets the timeouts of the routes of the given outbound policy to the ones of the given service
+func (mc *MeshCatalog) setServiceTimeouts(policy *trafficpolicy.OutboundTrafficPolicy, svc service.MeshService) {
+	timeouts := mc.GetTimeoutPolicy(svc)
+	for _, route := range policy.Routes {
+		setRouteTimeouts(route, timeouts)
+	}
+}
+
+// setRouteTimeouts sets the timeouts of the given route that are not set yet to the ones of the first given timeout
+// policy setting them
+func setRouteTimeouts(route *trafficpolicy.RouteWeightedClusters, timeoutPolicies ...*trafficpolicy.TimeoutPolicy) {
+	for _, policy := range timeoutPolicies {
+		if policy == nil {
+			continue
+		}
+		if route.Timeout == nil {
+			route.Timeout = policy.RequestTimeout
+		}
+		if route.StreamIdleTimeout == nil {
+			route.StreamIdleTimeout = policy.StreamIdleTimeout
+		}
+	}
+}
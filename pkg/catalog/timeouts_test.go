@@ -0,0 +1,205 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetTimeoutPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	fiveMinutes := 5 * time.Minute
+	oneHour := time.Hour
+	disabled := time.Duration(0)
+
+	testCases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedPolicy *trafficpolicy.TimeoutPolicy
+	}{
+		{
+			name:           "service without annotation",
+			annotations:    nil,
+			expectedPolicy: nil,
+		},
+		{
+			name: "all timeouts",
+			annotations: map[string]string{
+				constants.RequestTimeoutAnnotation:    "5m",
+				constants.StreamIdleTimeoutAnnotation: "1h",
+				constants.IdleTimeoutAnnotation:       "0s",
+			},
+			expectedPolicy: &trafficpolicy.TimeoutPolicy{
+				RequestTimeout:    &fiveMinutes,
+				StreamIdleTimeout: &oneHour,
+				IdleTimeout:       &disabled,
+			},
+		},
+		{
+			name: "invalid timeouts are ignored",
+			annotations: map[string]string{
+				constants.RequestTimeoutAnnotation:    "5 minutes",
+				constants.StreamIdleTimeoutAnnotation: "-1h",
+				constants.IdleTimeoutAnnotation:       "5m",
+			},
+			expectedPolicy: &trafficpolicy.TimeoutPolicy{
+				IdleTimeout: &fiveMinutes,
+			},
+		},
+		{
+			name:           "only invalid timeouts",
+			annotations:    map[string]string{constants.RequestTimeoutAnnotation: "forever"},
+			expectedPolicy: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mc := &MeshCatalog{kubeController: mockKubeController}
+
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetTimeoutPolicy(svc))
+		})
+	}
+}
+
+func TestGetRouteTimeouts(t *testing.T) {
+	tenMinutes := 10 * time.Minute
+	oneHour := time.Hour
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedTimeouts map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy
+		expectedErr      bool
+	}{
+		{
+			name:             "HTTPRouteGroup without annotation",
+			annotations:      nil,
+			expectedTimeouts: nil,
+		},
+		{
+			name: "timeouts of matches",
+			annotations: map[string]string{
+				constants.RouteTimeoutsAnnotation: `{"upload": {"requestTimeout": "10m", "streamIdleTimeout": "1h"}, "download": {"streamIdleTimeout": "1h"}}`,
+			},
+			expectedTimeouts: map[trafficpolicy.TrafficSpecMatchName]*trafficpolicy.TimeoutPolicy{
+				"upload":   {RequestTimeout: &tenMinutes, StreamIdleTimeout: &oneHour},
+				"download": {StreamIdleTimeout: &oneHour},
+			},
+		},
+		{
+			name:        "invalid JSON",
+			annotations: map[string]string{constants.RouteTimeoutsAnnotation: `upload=10m`},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid timeout",
+			annotations: map[string]string{constants.RouteTimeoutsAnnotation: `{"upload": {"requestTimeout": "10"}}`},
+			expectedErr: true,
+		},
+		{
+			name:        "negative timeout",
+			annotations: map[string]string{constants.RouteTimeoutsAnnotation: `{"upload": {"streamIdleTimeout": "-1h"}}`},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			routeGroup := &spec.HTTPRouteGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "routes", Namespace: "default", Annotations: tc.annotations},
+			}
+			timeouts, err := getRouteTimeouts(routeGroup)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedTimeouts, timeouts)
+		})
+	}
+}
+
+func TestTrafficSpecRoutesFromRules(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mc := &MeshCatalog{meshSpec: mockMeshSpec}
+
+	routeGroup := tests.HTTPRouteGroup
+	routeGroup.Annotations = map[string]string{
+		constants.RouteTimeoutsAnnotation: `{"` + tests.BuyBooksMatchName + `": {"requestTimeout": "0s"}}`,
+	}
+	mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return([]*spec.HTTPRouteGroup{&routeGroup}).AnyTimes()
+
+	routes, err := mc.trafficSpecRoutesFromRules([]access.TrafficTargetRule{
+		{
+			Kind:    "HTTPRouteGroup",
+			Name:    tests.RouteGroupName,
+			Matches: []string{tests.BuyBooksMatchName, tests.SellBooksMatchName},
+		},
+	}, tests.Namespace)
+	assert.Nil(err)
+
+	disabled := time.Duration(0)
+	specName := mc.getTrafficSpecName("HTTPRouteGroup", tests.Namespace, tests.RouteGroupName)
+	assert.Equal([]trafficSpecRoute{
+		{
+			match:     tests.BookstoreBuyHTTPRoute,
+			timeouts:  &trafficpolicy.TimeoutPolicy{RequestTimeout: &disabled},
+			specName:  specName,
+			matchName: tests.BuyBooksMatchName,
+		},
+		{
+			match:     tests.BookstoreSellHTTPRoute,
+			specName:  specName,
+			matchName: tests.SellBooksMatchName,
+		},
+	}, routes)
+}
+
+func TestNewTimedRoute(t *testing.T) {
+	assert := tassert.New(t)
+
+	oneMinute := time.Minute
+	tenMinutes := 10 * time.Minute
+	oneHour := time.Hour
+	weightedClusters := []service.WeightedCluster{tests.BookstoreV1DefaultWeightedCluster}
+
+	// The timeouts of the proxy apply to the routes without timeout policy
+	route := newTimedRoute(tests.BookstoreBuyHTTPRoute, weightedClusters, nil, nil)
+	assert.Equal(*trafficpolicy.NewRouteWeightedCluster(tests.BookstoreBuyHTTPRoute, weightedClusters), route)
+
+	// The timeouts of a match override the ones of the service
+	route = newTimedRoute(tests.BookstoreBuyHTTPRoute, weightedClusters,
+		&trafficpolicy.TimeoutPolicy{RequestTimeout: &tenMinutes},
+		&trafficpolicy.TimeoutPolicy{RequestTimeout: &oneMinute, StreamIdleTimeout: &oneHour, IdleTimeout: &oneHour})
+	assert.Equal(&tenMinutes, route.Timeout)
+	assert.Equal(&oneHour, route.StreamIdleTimeout)
+}
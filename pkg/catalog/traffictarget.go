@@ -5,9 +5,11 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -56,11 +58,12 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream service.K8sS
 		trafficTarget := trafficpolicy.TrafficTargetWithRoutes{
 			Name:        fmt.Sprintf("%s/%s", t.Namespace, t.Name),
 			Destination: destinationIdentity,
+			Shadow:      mc.isTrafficTargetInShadow(t),
 		}
 
 		// Source identifies for this traffic target
 		var sourceIdentities []identity.ServiceIdentity
-		for _, source := range t.Spec.Sources {
+		for _, source := range mc.expandTrafficTargetSources(t.Spec.Sources) {
 			srcIdentity := trafficTargetIdentityToServiceIdentity(source)
 			sourceIdentities = append(sourceIdentities, srcIdentity)
 		}
@@ -99,7 +102,7 @@ func (mc *MeshCatalog) getAllowedDirectionalServiceAccounts(svcAccount service.K
 				// This TrafficTarget has a destination that does not match the given service account, ignore it
 				continue
 			}
-			for _, source := range spec.Sources {
+			for _, source := range mc.expandTrafficTargetSources(spec.Sources) {
 				if source.Kind != serviceAccountKind {
 					// Destination kind is not valid
 					log.Error().Msgf("Applied TrafficTarget policy %s has invalid Source kind: %s", trafficTarget.Name, spec.Destination.Kind)
@@ -119,7 +122,7 @@ func (mc *MeshCatalog) getAllowedDirectionalServiceAccounts(svcAccount service.K
 					continue
 				}
 
-				if source.Name != svcAccount.Name || source.Namespace != svcAccount.Namespace {
+				if !trafficTargetSourceMatches(source, svcAccount) {
 					// This TrafficTarget source does not match the given service account, ignore it
 					continue
 				}
@@ -149,6 +152,52 @@ func trafficTargetIdentityToServiceIdentity(identitySubject smiAccess.IdentityBi
 	return identity.GetKubernetesServiceIdentity(svcAccount, identity.ClusterLocalTrustDomain)
 }
 
+// isWildcardTrafficTargetSource returns true if the given TrafficTarget source matches more than one service account
+func isWildcardTrafficTargetSource(source smiAccess.IdentityBindingSubject) bool {
+	return source.Name == smi.WildcardIdentity
+}
+
+// trafficTargetSourceMatches returns true if the given TrafficTarget source matches the given service account.
+// A source with the wildcard name matches any service account in its namespace, and a source with the wildcard
+// name and namespace matches any service account.
+func trafficTargetSourceMatches(source smiAccess.IdentityBindingSubject, svcAccount service.K8sServiceAccount) bool {
+	if isWildcardTrafficTargetSource(source) {
+		return source.Namespace == smi.WildcardIdentity || source.Namespace == svcAccount.Namespace
+	}
+	return source.Name == svcAccount.Name && source.Namespace == svcAccount.Namespace
+}
+
+// expandTrafficTargetSources returns the given TrafficTarget sources, with wildcard sources replaced by the
+// service accounts they match in the namespaces monitored by the mesh
+func (mc *MeshCatalog) expandTrafficTargetSources(sources []smiAccess.IdentityBindingSubject) []smiAccess.IdentityBindingSubject {
+	var expanded []smiAccess.IdentityBindingSubject
+	var serviceAccounts []*corev1.ServiceAccount
+
+	for _, source := range sources {
+		if !isWildcardTrafficTargetSource(source) {
+			expanded = append(expanded, source)
+			continue
+		}
+
+		if serviceAccounts == nil {
+			serviceAccounts = mc.kubeController.ListServiceAccounts()
+		}
+		for _, sa := range serviceAccounts {
+			svcAccount := service.K8sServiceAccount{Name: sa.Name, Namespace: sa.Namespace}
+			if !trafficTargetSourceMatches(source, svcAccount) {
+				continue
+			}
+			expanded = append(expanded, smiAccess.IdentityBindingSubject{
+				Kind:      source.Kind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			})
+		}
+	}
+
+	return expanded
+}
+
 // trafficTargetIdentitiesToSvcAccounts returns a list of Service Accounts from the given list of identities from a Traffic Target
 func trafficTargetIdentitiesToSvcAccounts(identities []smiAccess.IdentityBindingSubject) []service.K8sServiceAccount {
 	serviceAccountsMap := map[service.K8sServiceAccount]bool{}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"fmt"
+	"time"
+
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+// trafficTargetShadow is the shadow mode window of the current generation of an SMI TrafficTarget
+type trafficTargetShadow struct {
+	uid        types.UID
+	generation int64
+
+	// since is the time the shadow mode window of the generation started
+	since time.Time
+
+	// end is the time the shadow mode window ends, at which the timer fires to enforce the TrafficTarget
+	end   time.Time
+	timer *time.Timer
+}
+
+// isTrafficTargetInShadow returns true if the given TrafficTarget is evaluated in shadow mode rather than enforced.
+// A TrafficTarget is in shadow mode for the duration configured with traffic_target_shadow_duration after it is created,
+// or after the controller observes a change of its spec. Changes made before the controller started are not known, so
+// the TrafficTargets observed at startup are only in shadow mode for the duration following their creation.
+func (mc *MeshCatalog) isTrafficTargetInShadow(t *access.TrafficTarget) bool {
+	duration := mc.configurator.GetTrafficTargetShadowDuration()
+	if duration <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	key := fmt.Sprintf("%s/%s", t.Namespace, t.Name)
+
+	mc.trafficTargetShadowsLock.Lock()
+	defer mc.trafficTargetShadowsLock.Unlock()
+
+	if mc.trafficTargetShadows == nil {
+		mc.trafficTargetShadows = make(map[string]*trafficTargetShadow)
+	}
+
+	shadow, ok := mc.trafficTargetShadows[key]
+	switch {
+	case !ok || shadow.uid != t.UID:
+		if ok && shadow.timer != nil {
+			shadow.timer.Stop()
+		}
+		shadow = &trafficTargetShadow{
+			uid:        t.UID,
+			generation: t.Generation,
+			since:      t.CreationTimestamp.Time,
+		}
+		mc.trafficTargetShadows[key] = shadow
+
+	case shadow.generation != t.Generation:
+		shadow.generation = t.Generation
+		shadow.since = now
+	}
+
+	end := shadow.since.Add(duration)
+	if !now.Before(end) {
+		return false
+	}
+
+	// Schedule a broadcast at the end of the window, so that the TrafficTarget is enforced without waiting for another
+	// change. The end of the window moves when the TrafficTarget or the configured duration changes.
+	if !shadow.end.Equal(end) {
+		if shadow.timer != nil {
+			shadow.timer.Stop()
+		}
+		log.Info().Msgf("TrafficTarget %s is evaluated in shadow mode until %s", key, end.Format(time.RFC3339))
+		shadow.end = end
+		shadow.timer = time.AfterFunc(end.Sub(now), func() {
+			log.Info().Msgf("Shadow mode of TrafficTarget %s ended at %s, enforcing it", key, end.Format(time.RFC3339))
+			events.GetPubSubInstance().Publish(events.PubSubMessage{
+				AnnouncementType: announcements.ScheduleProxyBroadcast,
+				NewObj:           nil,
+				OldObj:           nil,
+			})
+		})
+	}
+	return true
+}
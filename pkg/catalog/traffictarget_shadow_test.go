@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func TestIsTrafficTargetInShadow(t *testing.T) {
+	newTrafficTarget := func(uid string, generation int64, created time.Time) *smiAccess.TrafficTarget {
+		return &smiAccess.TrafficTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "bookstore",
+				Namespace:         "bookstore-ns",
+				UID:               types.UID(uid),
+				Generation:        generation,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		shadowDuration time.Duration
+		observed       []*smiAccess.TrafficTarget
+		expectedShadow []bool
+	}{
+		{
+			name:           "shadow mode disabled",
+			shadowDuration: 0,
+			observed:       []*smiAccess.TrafficTarget{newTrafficTarget("a", 1, time.Now())},
+			expectedShadow: []bool{false},
+		},
+		{
+			name:           "TrafficTarget created within the shadow duration",
+			shadowDuration: time.Hour,
+			observed:       []*smiAccess.TrafficTarget{newTrafficTarget("a", 1, time.Now().Add(-time.Minute))},
+			expectedShadow: []bool{true},
+		},
+		{
+			name:           "TrafficTarget created before the shadow duration",
+			shadowDuration: time.Hour,
+			observed:       []*smiAccess.TrafficTarget{newTrafficTarget("a", 1, time.Now().Add(-2*time.Hour))},
+			expectedShadow: []bool{false},
+		},
+		{
+			name:           "TrafficTarget changed after it was observed",
+			shadowDuration: time.Hour,
+			observed: []*smiAccess.TrafficTarget{
+				newTrafficTarget("a", 1, time.Now().Add(-2*time.Hour)),
+				newTrafficTarget("a", 1, time.Now().Add(-2*time.Hour)),
+				newTrafficTarget("a", 2, time.Now().Add(-2*time.Hour)),
+				newTrafficTarget("a", 2, time.Now().Add(-2*time.Hour)),
+			},
+			expectedShadow: []bool{false, false, true, true},
+		},
+		{
+			name:           "TrafficTarget recreated with the same name",
+			shadowDuration: time.Hour,
+			observed: []*smiAccess.TrafficTarget{
+				newTrafficTarget("a", 1, time.Now().Add(-2*time.Hour)),
+				newTrafficTarget("b", 1, time.Now()),
+			},
+			expectedShadow: []bool{false, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockCfg.EXPECT().GetTrafficTargetShadowDuration().Return(tc.shadowDuration).AnyTimes()
+			mc := &MeshCatalog{configurator: mockCfg}
+
+			for i, trafficTarget := range tc.observed {
+				assert.Equal(tc.expectedShadow[i], mc.isTrafficTargetInShadow(trafficTarget))
+			}
+
+			// Stop the timers scheduled to enforce the TrafficTargets
+			for _, shadow := range mc.trafficTargetShadows {
+				if shadow.timer != nil {
+					shadow.timer.Stop()
+				}
+			}
+		})
+	}
+}
@@ -3,15 +3,18 @@ package catalog
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/identity"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -448,6 +451,88 @@ func TestTrafficTargetIdentitiesToSvcAccounts(t *testing.T) {
 	assert.ElementsMatch(expected, actual)
 }
 
+func TestTrafficTargetSourceMatches(t *testing.T) {
+	svcAccount := service.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
+
+	testCases := []struct {
+		name     string
+		source   smiAccess.IdentityBindingSubject
+		expected bool
+	}{
+		{
+			name:     "same service account",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "sa-1", Namespace: "ns-1"},
+			expected: true,
+		},
+		{
+			name:     "other service account",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "sa-2", Namespace: "ns-1"},
+			expected: false,
+		},
+		{
+			name:     "any service account in the same namespace",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "*", Namespace: "ns-1"},
+			expected: true,
+		},
+		{
+			name:     "any service account in another namespace",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "*", Namespace: "ns-2"},
+			expected: false,
+		},
+		{
+			name:     "any service account in any namespace",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "*", Namespace: "*"},
+			expected: true,
+		},
+		{
+			name:     "wildcard namespace without wildcard name",
+			source:   smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "sa-1", Namespace: "*"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, trafficTargetSourceMatches(tc.source, svcAccount))
+		})
+	}
+}
+
+func TestExpandTrafficTargetSources(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().ListServiceAccounts().Return([]*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-1", Namespace: "ns-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-2", Namespace: "ns-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-3", Namespace: "ns-2"}},
+	}).Times(1)
+
+	meshCatalog := MeshCatalog{
+		kubeController: mockKubeController,
+	}
+
+	sources := []smiAccess.IdentityBindingSubject{
+		{Kind: "ServiceAccount", Name: "sa-4", Namespace: "ns-3"},
+		{Kind: "ServiceAccount", Name: "*", Namespace: "ns-1"},
+		{Kind: "ServiceAccount", Name: "*", Namespace: "*"},
+	}
+
+	expected := []smiAccess.IdentityBindingSubject{
+		{Kind: "ServiceAccount", Name: "sa-4", Namespace: "ns-3"},
+		{Kind: "ServiceAccount", Name: "sa-1", Namespace: "ns-1"},
+		{Kind: "ServiceAccount", Name: "sa-2", Namespace: "ns-1"},
+		{Kind: "ServiceAccount", Name: "sa-1", Namespace: "ns-1"},
+		{Kind: "ServiceAccount", Name: "sa-2", Namespace: "ns-1"},
+		{Kind: "ServiceAccount", Name: "sa-3", Namespace: "ns-2"},
+	}
+
+	assert.Equal(expected, meshCatalog.expandTrafficTargetSources(sources))
+}
+
 func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -867,6 +952,7 @@ func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 			}
 
 			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
 
 			// Mock TrafficTargets returned by MeshSpec, should return all TrafficTargets relevant for this test
 			mockMeshSpec.EXPECT().ListTrafficTargets().Return(tc.trafficTargets).AnyTimes()
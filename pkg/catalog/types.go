@@ -12,6 +12,7 @@ import (
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -21,6 +22,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/ingress"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -36,6 +38,7 @@ type MeshCatalog struct {
 	meshSpec           smi.MeshSpec
 	certManager        certificate.Manager
 	ingressMonitor     ingress.Monitor
+	policyController   policy.Monitor
 	configurator       configurator.Configurator
 
 	expectedProxies     sync.Map
@@ -56,6 +59,14 @@ type MeshCatalog struct {
 
 	// Maintain a mapping of pod UID to certificate SerialNumber of the Envoy on the given pod
 	podUIDToCertificateSerialNumber sync.Map
+
+	// The most recent configuration generations broadcast to the proxies, the oldest first
+	configGenerations     []envoy.ConfigGeneration
+	configGenerationsLock sync.RWMutex
+
+	// The shadow mode windows of SMI TrafficTargets, keyed by <namespace>/<name>
+	trafficTargetShadows     map[string]*trafficTargetShadow
+	trafficTargetShadowsLock sync.Mutex
 }
 
 // MeshCataloger is the mechanism by which the Service Mesh controller discovers all Envoy proxies connected to the catalog.
@@ -66,6 +77,10 @@ type MeshCataloger interface {
 	// ListInboundTrafficPolicies returns all inbound traffic policies related to the given service account and inbound services
 	ListInboundTrafficPolicies(service.K8sServiceAccount, []service.MeshService) []*trafficpolicy.InboundTrafficPolicy
 
+	// ListInboundPolicySet returns the set of the inbound traffic policies related to the given service account and
+	// inbound services, built from each of their sources and not compiled yet
+	ListInboundPolicySet(service.K8sServiceAccount, []service.MeshService) *trafficpolicy.InboundPolicySet
+
 	// ListOutboundTrafficPolicies returns all outbound traffic policies related to the given service account
 	ListOutboundTrafficPolicies(service.K8sServiceAccount) []*trafficpolicy.OutboundTrafficPolicy
 
@@ -90,6 +105,10 @@ type MeshCataloger interface {
 	// ListAllowedEndpointsForService returns the list of endpoints backing a service and its allowed service accounts
 	ListAllowedEndpointsForService(service.K8sServiceAccount, service.MeshService) ([]endpoint.Endpoint, error)
 
+	// FilterEndpointsByTopology returns the subset of the given endpoints of an upstream service that the downstream proxy
+	// should route to, based on the topology keys of the upstream service
+	FilterEndpointsByTopology(certificate.CommonName, service.MeshService, []endpoint.Endpoint) []endpoint.Endpoint
+
 	// GetResolvableServiceEndpoints returns the resolvable set of endpoint over which a service is accessible using its FQDN.
 	// These are the endpoint destinations we'd expect client applications sends the traffic towards to, when attempting to
 	// reach a specific service.
@@ -102,6 +121,10 @@ type MeshCataloger interface {
 	// GetServicesFromEnvoyCertificate returns a list of services the given Envoy is a member of based on the certificate provided, which is a cert issued to an Envoy for XDS communication (not Envoy-to-Envoy).
 	GetServicesFromEnvoyCertificate(certificate.CommonName) ([]service.MeshService, error)
 
+	// ListContainerPortsFromEnvoyCertificate returns the sorted TCP ports declared by the application containers of the pod the
+	// given Envoy is fronting.
+	ListContainerPortsFromEnvoyCertificate(certificate.CommonName) ([]uint32, error)
+
 	// RegisterProxy registers a newly connected proxy with the service mesh catalog.
 	RegisterProxy(*envoy.Proxy)
 
@@ -114,21 +137,106 @@ type MeshCataloger interface {
 	// GetIngressPoliciesForService returns the inbound traffic policies associated with an ingress service
 	GetIngressPoliciesForService(service.MeshService) ([]*trafficpolicy.InboundTrafficPolicy, error)
 
+	// GetIngressForwardedHeaderPolicy returns the forwarded header policy applied to requests received by the given service from ingress
+	GetIngressForwardedHeaderPolicy(service.MeshService) trafficpolicy.ForwardedHeaderPolicy
+
+	// GetIngressAnnotationPolicy returns the ingress controller settings, specified by annotations, applied to the traffic received by the given service from ingress
+	GetIngressAnnotationPolicy(service.MeshService) trafficpolicy.IngressAnnotationPolicy
+
+	// GetIngressBackendPolicy returns the ingress sources allowed to reach the given service, or nil if ingress traffic to the service is not restricted
+	GetIngressBackendPolicy(service.MeshService) (*trafficpolicy.IngressBackendPolicy, error)
+
+	// GetEgressPolicy returns the external destinations the given service account is allowed to reach, or nil if no Egress resource lists it as a source
+	GetEgressPolicy(service.K8sServiceAccount) *trafficpolicy.EgressPolicy
+
+	// GetEgressGatewayPolicy returns the external destinations allowed by all the Egress resources, or nil if there are none
+	GetEgressGatewayPolicy() *trafficpolicy.EgressPolicy
+
+	// IsEgressAuditEnabled returns true if the egress connections of the given proxy not allowed by its Egress policies are reported rather than denied
+	IsEgressAuditEnabled(*envoy.Proxy) bool
+
+	// GetEgressCABundle returns the CA bundle stored in the secret with the given namespaced name, validating the certificates of external hosts
+	GetEgressCABundle(string) ([]byte, error)
+
+	// GetEgressClientCertificate returns the certificate chain and private key stored in the secret with the given namespaced name, presented to external hosts
+	GetEgressClientCertificate(string) ([]byte, []byte, error)
+
+	// GetIngressCABundle returns the CA bundle stored in the secret with the given namespaced name, validating the client certificates of ingress sources
+	GetIngressCABundle(string) ([]byte, error)
+
 	// ListMonitoredNamespaces lists namespaces monitored by the control plane
 	ListMonitoredNamespaces() []string
 
-	// GetTargetPortToProtocolMappingForService returns a mapping of the service's ports to their corresponding application protocol.
-	// The ports returned are the actual ports on which the application exposes the service derived from the service's endpoints,
-	// ie. 'spec.ports[].targetPort' instead of 'spec.ports[].port' for a Kubernetes service.
-	GetTargetPortToProtocolMappingForService(service.MeshService) (map[uint32]string, error)
+	// GetLatestConfigGeneration returns the configuration generation last broadcast to the proxies, or nil if none was
+	GetLatestConfigGeneration() *envoy.ConfigGeneration
+
+	// ListServicePorts returns a MeshService per port of the given service, with the port, target port and application
+	// protocol of the port set, sorted by port
+	ListServicePorts(service.MeshService) ([]service.MeshService, error)
+
+	// IsExternalPlaintextTrafficAllowed returns true if the given NodePort or LoadBalancer service is annotated to accept
+	// plaintext traffic originating outside the mesh
+	IsExternalPlaintextTrafficAllowed(service.MeshService) bool
 
-	// GetTargetPortToProtocolMappingForService returns a mapping of the service's ports to their corresponding application protocol,
-	// where the ports returned are the ones used by downstream clients in their requests. This can be different from the ports
-	// actually exposed by the application binary, ie. 'spec.ports[].port' instead of 'spec.ports[].targetPort' for a Kubernetes service.
-	GetPortToProtocolMappingForService(service.MeshService) (map[uint32]string, error)
+	// GetWAFRulesetForService returns the SecLang directives of the WAF ruleset referenced by the given service,
+	// or an empty ruleset if the WAF is not enabled for the service
+	GetWAFRulesetForService(service.MeshService) (string, error)
+
+	// GetInboundProxyProtocolPorts returns the target ports of the given service on which the PROXY protocol is required
+	GetInboundProxyProtocolPorts(service.MeshService) []uint32
+
+	// GetUpstreamProxyProtocolVersion returns the version of the PROXY protocol clients must emit on connections to the
+	// given service, or an empty version if the PROXY protocol must not be emitted
+	GetUpstreamProxyProtocolVersion(service.MeshService) string
+
+	// GetBandwidthLimitForService returns the limits of the rate of the HTTP response data received and sent by the
+	// given service
+	GetBandwidthLimitForService(service.MeshService) trafficpolicy.BandwidthLimit
+
+	// GetAdaptiveConcurrencyPolicy returns the adaptive concurrency limits shedding the load of the given service
+	GetAdaptiveConcurrencyPolicy(service.MeshService) trafficpolicy.AdaptiveConcurrencyPolicy
+
+	// GetInboundConnectionPolicy returns the limits of the connections the sidecar proxy of the given service opens to
+	// the service
+	GetInboundConnectionPolicy(service.MeshService) trafficpolicy.InboundConnectionPolicy
+
+	// GetTrafficPriority returns the priority class of the traffic to the given service
+	GetTrafficPriority(service.MeshService) string
+
+	// GetGRPCHealthCheckPolicy returns the gRPC health checks of the given service, or nil if it is not health checked
+	GetGRPCHealthCheckPolicy(service.MeshService) *trafficpolicy.GRPCHealthCheckPolicy
+
+	// GetFailoverPolicy returns the backup endpoints the traffic to the given service fails over to, or nil if it has none
+	GetFailoverPolicy(service.MeshService) *trafficpolicy.FailoverPolicy
+
+	// GetTimeoutPolicy returns the timeouts of the requests to the given service, or nil if it does not set any
+	GetTimeoutPolicy(service.MeshService) *trafficpolicy.TimeoutPolicy
+
+	// GetUpstreamConnectionPolicy returns the limits of the connections and requests of each client to the given
+	// service, or nil if it does not set any
+	GetUpstreamConnectionPolicy(service.MeshService) *trafficpolicy.UpstreamConnectionPolicy
+
+	// IsNodeProxy returns true if the given proxy is an experimental per-node proxy
+	IsNodeProxy(*envoy.Proxy) bool
+
+	// ListPodsForNodeProxy returns the pods in node proxy mode that are scheduled on the node of the given per-node proxy
+	ListPodsForNodeProxy(*envoy.Proxy) ([]*corev1.Pod, error)
+
+	// IsEgressGateway returns true if the given proxy is the egress gateway
+	IsEgressGateway(*envoy.Proxy) bool
+
+	// GetProxyOverrides returns the overrides of the mesh-wide configuration for the given proxy by the annotations of the namespace of its pod and of the pod
+	GetProxyOverrides(*envoy.Proxy) configurator.Overrides
+
+	// GetProxyMemoryLimit returns the memory limit in bytes of the Envoy container of the pod of the given proxy, or 0 if it has none
+	GetProxyMemoryLimit(*envoy.Proxy) int64
 
 	// ListInboundTrafficTargetsWithRoutes returns a list traffic target objects composed of its routes for the given destination service account
 	ListInboundTrafficTargetsWithRoutes(service.K8sServiceAccount) ([]trafficpolicy.TrafficTargetWithRoutes, error)
+
+	// ListRoutingPolicies returns the SMI TrafficTargets and TrafficSplits routing the traffic of the given identity, and
+	// the dark launches its outbound traffic is copied to
+	ListRoutingPolicies(service.K8sServiceAccount) *trafficpolicy.RoutingPolicies
 }
 type expectedProxy struct {
 	// The time the certificate, identified by CN, for the expected proxy was issued on
@@ -0,0 +1,32 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetUpstreamConnectionPolicy returns the limits of the connections and requests of each client to the given service,
+// as set by the connection settings of the UpstreamTrafficSetting of the service. Nil is returned if the service has no
+// UpstreamTrafficSetting, or if it does not set any limit.
+func (mc *MeshCatalog) GetUpstreamConnectionPolicy(svc service.MeshService) *trafficpolicy.UpstreamConnectionPolicy {
+	upstreamTrafficSetting := mc.policyController.GetUpstreamTrafficSetting(svc)
+	if upstreamTrafficSetting == nil || upstreamTrafficSetting.Spec.ConnectionSettings == nil {
+		return nil
+	}
+
+	var policy trafficpolicy.UpstreamConnectionPolicy
+	if tcp := upstreamTrafficSetting.Spec.ConnectionSettings.TCP; tcp != nil {
+		policy.MaxConnections = tcp.MaxConnections
+	}
+	if http := upstreamTrafficSetting.Spec.ConnectionSettings.HTTP; http != nil {
+		policy.MaxPendingRequests = http.MaxPendingRequests
+		policy.MaxRequests = http.MaxRequests
+		policy.MaxRequestsPerConnection = http.MaxRequestsPerConnection
+		policy.MaxRetries = http.MaxRetries
+	}
+
+	if policy == (trafficpolicy.UpstreamConnectionPolicy{}) {
+		return nil
+	}
+	return &policy
+}
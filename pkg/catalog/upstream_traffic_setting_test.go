@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetUpstreamConnectionPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	maxConnections := uint32(100)
+	maxPendingRequests := uint32(10)
+	maxRequests := uint32(200)
+	maxRequestsPerConnection := uint32(1)
+	maxRetries := uint32(2)
+
+	testCases := []struct {
+		name                   string
+		upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+		expectedPolicy         *trafficpolicy.UpstreamConnectionPolicy
+	}{
+		{
+			name:                   "service without UpstreamTrafficSetting",
+			upstreamTrafficSetting: nil,
+			expectedPolicy:         nil,
+		},
+		{
+			name: "UpstreamTrafficSetting without connection settings",
+			upstreamTrafficSetting: &policyV1alpha1.UpstreamTrafficSetting{
+				Spec: policyV1alpha1.UpstreamTrafficSettingSpec{Host: svc.ServerName()},
+			},
+			expectedPolicy: nil,
+		},
+		{
+			name: "UpstreamTrafficSetting with empty connection settings",
+			upstreamTrafficSetting: &policyV1alpha1.UpstreamTrafficSetting{
+				Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+					Host: svc.ServerName(),
+					ConnectionSettings: &policyV1alpha1.ConnectionSettingsSpec{
+						HTTP: &policyV1alpha1.HTTPConnectionSettings{},
+					},
+				},
+			},
+			expectedPolicy: nil,
+		},
+		{
+			name: "TCP connection settings",
+			upstreamTrafficSetting: &policyV1alpha1.UpstreamTrafficSetting{
+				Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+					Host: svc.ServerName(),
+					ConnectionSettings: &policyV1alpha1.ConnectionSettingsSpec{
+						TCP: &policyV1alpha1.TCPConnectionSettings{MaxConnections: &maxConnections},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.UpstreamConnectionPolicy{MaxConnections: &maxConnections},
+		},
+		{
+			name: "TCP and HTTP connection settings",
+			upstreamTrafficSetting: &policyV1alpha1.UpstreamTrafficSetting{
+				Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+					Host: svc.ServerName(),
+					ConnectionSettings: &policyV1alpha1.ConnectionSettingsSpec{
+						TCP: &policyV1alpha1.TCPConnectionSettings{MaxConnections: &maxConnections},
+						HTTP: &policyV1alpha1.HTTPConnectionSettings{
+							MaxPendingRequests:       &maxPendingRequests,
+							MaxRequests:              &maxRequests,
+							MaxRequestsPerConnection: &maxRequestsPerConnection,
+							MaxRetries:               &maxRetries,
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.UpstreamConnectionPolicy{
+				MaxConnections:           &maxConnections,
+				MaxPendingRequests:       &maxPendingRequests,
+				MaxRequests:              &maxRequests,
+				MaxRequestsPerConnection: &maxRequestsPerConnection,
+				MaxRetries:               &maxRetries,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockPolicyMonitor := policy.NewMockMonitor(mockCtrl)
+			mc := &MeshCatalog{policyController: mockPolicyMonitor}
+
+			mockPolicyMonitor.EXPECT().GetUpstreamTrafficSetting(svc).Return(tc.upstreamTrafficSetting).Times(1)
+
+			assert.Equal(tc.expectedPolicy, mc.GetUpstreamConnectionPolicy(svc))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// GetWAFRulesetForService returns the SecLang directives of the WAF ruleset referenced by the given service with the
+// WAF ruleset annotation. An empty ruleset is returned if the service does not enable the WAF.
+func (mc *MeshCatalog) GetWAFRulesetForService(svc service.MeshService) (string, error) {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return "", nil
+	}
+
+	configMapName := strings.TrimSpace(k8sSvc.Annotations[constants.WAFRulesetAnnotation])
+	if configMapName == "" {
+		return "", nil
+	}
+
+	configMap, err := mc.kubeClient.CoreV1().ConfigMaps(svc.Namespace).Get(context.Background(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error retrieving WAF ruleset ConfigMap %s/%s referenced by service %s", svc.Namespace, configMapName, svc)
+		return "", errors.Wrapf(ErrWAFRulesetNotFound, "%s/%s", svc.Namespace, configMapName)
+	}
+
+	ruleset, ok := configMap.Data[constants.WAFRulesetConfigMapKey]
+	if !ok || strings.TrimSpace(ruleset) == "" {
+		log.Error().Msgf("WAF ruleset ConfigMap %s/%s referenced by service %s has no %s key", svc.Namespace, configMapName, svc, constants.WAFRulesetConfigMapKey)
+		return "", errors.Wrapf(ErrWAFRulesetNotFound, "%s/%s: missing key %s", svc.Namespace, configMapName, constants.WAFRulesetConfigMapKey)
+	}
+
+	return ruleset, nil
+}
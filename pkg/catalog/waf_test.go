@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetWAFRulesetForService(t *testing.T) {
+	svc := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	const ruleset = "SecRuleEngine On\nSecRule ARGS \"@contains attack\" \"id:1,phase:2,deny,status:403\""
+
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		configMapData   map[string]string
+		expectedRuleset string
+		expectedError   error
+	}{
+		{
+			name:            "service without annotation",
+			annotations:     nil,
+			expectedRuleset: "",
+		},
+		{
+			name:            "service referencing a ruleset",
+			annotations:     map[string]string{constants.WAFRulesetAnnotation: "waf-rules"},
+			configMapData:   map[string]string{constants.WAFRulesetConfigMapKey: ruleset},
+			expectedRuleset: ruleset,
+		},
+		{
+			name:          "service referencing a missing ConfigMap",
+			annotations:   map[string]string{constants.WAFRulesetAnnotation: "missing"},
+			configMapData: map[string]string{constants.WAFRulesetConfigMapKey: ruleset},
+			expectedError: ErrWAFRulesetNotFound,
+		},
+		{
+			name:          "service referencing a ConfigMap without rules",
+			annotations:   map[string]string{constants.WAFRulesetAnnotation: "waf-rules"},
+			configMapData: map[string]string{"other": ruleset},
+			expectedError: ErrWAFRulesetNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			kubeClient := fake.NewSimpleClientset()
+			if tc.configMapData != nil {
+				_, err := kubeClient.CoreV1().ConfigMaps(svc.Namespace).Create(context.TODO(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "waf-rules", Namespace: svc.Namespace},
+					Data:       tc.configMapData,
+				}, metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        svc.Name,
+					Namespace:   svc.Namespace,
+					Annotations: tc.annotations,
+				},
+			}).Times(1)
+
+			mc := &MeshCatalog{
+				kubeController: mockKubeController,
+				kubeClient:     kubeClient,
+			}
+
+			actual, err := mc.GetWAFRulesetForService(svc)
+			assert.Equal(tc.expectedError, errors.Cause(err))
+			assert.Equal(tc.expectedRuleset, actual)
+		})
+	}
+}
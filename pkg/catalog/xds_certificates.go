@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
@@ -9,7 +10,9 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 )
@@ -40,6 +43,41 @@ func (mc *MeshCatalog) GetServicesFromEnvoyCertificate(cn certificate.CommonName
 	return meshServices, nil
 }
 
+// ListContainerPortsFromEnvoyCertificate returns the sorted TCP ports declared by the application containers of the pod
+// the given Envoy is fronting. This is used to program pods that are not selected by any service, which are otherwise
+// only reachable over their declared container ports.
+func (mc *MeshCatalog) ListContainerPortsFromEnvoyCertificate(cn certificate.CommonName) ([]uint32, error) {
+	pod, err := GetPodFromCertificate(cn, mc.kubeController)
+	if err != nil {
+		return nil, err
+	}
+
+	var ports []uint32
+	containerPorts := make(map[uint32]bool)
+	for _, container := range pod.Spec.Containers {
+		if container.Name == constants.EnvoyContainerName {
+			// Ports declared on the sidecar are not application ports
+			continue
+		}
+		for _, port := range container.Ports {
+			if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+				continue
+			}
+			// Multiple containers can declare the same port
+			if containerPorts[uint32(port.ContainerPort)] {
+				continue
+			}
+			containerPorts[uint32(port.ContainerPort)] = true
+			ports = append(ports, uint32(port.ContainerPort))
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i] < ports[j]
+	})
+	return ports, nil
+}
+
 func kubernetesServicesToMeshServices(kubernetesServices []v1.Service) (meshServices []service.MeshService) {
 	for _, svc := range kubernetesServices {
 		meshServices = append(meshServices, service.MeshService{
@@ -57,6 +95,23 @@ func listServiceNames(meshServices []service.MeshService) (serviceNames []string
 	return serviceNames
 }
 
+// GetProxyOverrides returns the overrides of the mesh-wide configuration for the given proxy, by the annotations of the
+// namespace of its pod and then of the pod itself. The mesh-wide configuration is not overridden for the proxies that
+// are not backed by a pod.
+func (mc *MeshCatalog) GetProxyOverrides(proxy *envoy.Proxy) configurator.Overrides {
+	if proxy.IsDevProxy() {
+		return configurator.NewOverrides()
+	}
+
+	pod, err := GetPodFromCertificate(proxy.GetCertificateCommonName(), mc.kubeController)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up the pod of proxy with SerialNumber=%s, ignoring the overrides of the mesh-wide configuration",
+			proxy.GetCertificateSerialNumber())
+		return configurator.NewOverrides()
+	}
+	return configurator.NewOverrides(mc.kubeController.GetNamespace(pod.Namespace), pod)
+}
+
 // GetPodFromCertificate returns the Kubernetes Pod object for a given certificate.
 func GetPodFromCertificate(cn certificate.CommonName, kubecontroller k8s.Controller) (*v1.Pod, error) {
 	cnMeta, err := getCertificateCommonNameMeta(cn)
@@ -82,10 +137,8 @@ func GetPodFromCertificate(cn certificate.CommonName, kubecontroller k8s.Control
 		return nil, ErrDidNotFindPodForCertificate
 	}
 
-	// --- CONVENTION ---
-	// By Open Service Mesh convention the number of services a pod can belong to is 1
-	// This is a limitation we set in place in order to make the mesh easy to understand and reason about.
-	// When a pod belongs to more than one service XDS will not program the Envoy proxy, leaving it out of the mesh.
+	// The proxy UUID label is unique per pod, so a certificate must map to exactly one pod.
+	// Note: a pod may be selected by any number of services; the services for the pod are resolved separately.
 	if len(pods) > 1 {
 		log.Error().Msgf("Found more than one pod with label %s = %s in namespace %s. There can be only one!",
 			constants.EnvoyUniqueIDLabelName, cnMeta.ProxyUUID, cnMeta.Namespace)
@@ -159,6 +212,12 @@ func NewCertCommonNameWithProxyID(proxyUUID uuid.UUID, serviceAccount, namespace
 	return certificate.CommonName(strings.Join([]string{proxyUUID.String(), serviceAccount, namespace}, constants.DomainDelimiter))
 }
 
+// NewDevProxyCertCommonName returns a newly generated CommonName for the xDS certificate of a dev proxy of the form:
+// <ProxyUUID>.<serviceAccount>.<namespace>.dev-proxy
+func NewDevProxyCertCommonName(proxyUUID uuid.UUID, serviceAccount, namespace string) certificate.CommonName {
+	return certificate.CommonName(strings.Join([]string{proxyUUID.String(), serviceAccount, namespace, constants.DevProxyCertificateCommonNameSuffix}, constants.DomainDelimiter))
+}
+
 // GetServiceAccountFromProxyCertificate returns the ServiceAccount information encoded in the certificate CN
 func GetServiceAccountFromProxyCertificate(cn certificate.CommonName) (service.K8sServiceAccount, error) {
 	var svcAccount service.K8sServiceAccount
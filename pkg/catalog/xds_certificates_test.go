@@ -3,10 +3,12 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/onsi/ginkgo"
+	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	testclient "k8s.io/client-go/kubernetes/fake"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -458,3 +461,39 @@ var _ = Describe("Test XDS certificate tooling", func() {
 		})
 	})
 })
+
+func TestGetProxyOverrides(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{kubeController: mockKubeController}
+
+	proxyUUID := uuid.New()
+	pod := tests.NewPodFixture(tests.Namespace, "pod-0", tests.BookbuyerServiceAccountName, map[string]string{
+		constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+	})
+	pod.Annotations = map[string]string{constants.EgressAnnotation: "true"}
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tests.Namespace,
+			Annotations: map[string]string{constants.EgressAnnotation: "false", constants.EnvoyLogLevelAnnotation: "debug"},
+		},
+	}
+	proxy := envoy.NewProxy(NewCertCommonNameWithProxyID(proxyUUID, tests.BookbuyerServiceAccountName, tests.Namespace), "123456", nil)
+
+	// The annotations of the pod override the annotations of its namespace
+	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{&pod}).Times(1)
+	mockKubeController.EXPECT().GetNamespace(tests.Namespace).Return(namespace).Times(1)
+	overrides := mc.GetProxyOverrides(proxy)
+	assert.True(overrides.Bool(constants.EgressAnnotation, false))
+	assert.Equal("debug", overrides.String(constants.EnvoyLogLevelAnnotation, "error"))
+
+	// The mesh-wide configuration applies to the proxies without a pod
+	mockKubeController.EXPECT().ListPods().Return(nil).Times(1)
+	assert.False(mc.GetProxyOverrides(proxy).Bool(constants.EgressAnnotation, false))
+
+	devProxy := envoy.NewProxy(proxy.GetCertificateCommonName()+"."+constants.DevProxyCertificateCommonNameSuffix, "123456", nil)
+	assert.True(mc.GetProxyOverrides(devProxy).Bool(constants.EgressAnnotation, true))
+}
@@ -9,3 +9,7 @@ var errEncodeCert = errors.New("encode cert")
 var errMarshalPrivateKey = errors.New("marshal private key")
 var errNoCertificateInPEM = errors.New("no certificate in PEM")
 var errNoPrivateKeyInPEM = errors.New("no private Key in PEM")
+
+// ErrIssuanceNotAllowed is the error returned when a certificate request is rejected by the issuance policy of a
+// certificate manager
+var ErrIssuanceNotAllowed = errors.New("certificate issuance not allowed by policy")
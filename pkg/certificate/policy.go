@@ -0,0 +1,111 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IssuancePolicy is the policy the certificates requested from a certificate manager are validated against before
+// they are signed. It is a defense in depth against the issuance of certificates the mesh does not use, should the
+// issuance path ever be exposed.
+type IssuancePolicy struct {
+	// MaxValidityPeriod is the longest validity period of the issued certificates
+	MaxValidityPeriod time.Duration
+
+	// AllowedKeyUsage is the key usages the issued certificates may have
+	AllowedKeyUsage x509.KeyUsage
+
+	// AllowedExtKeyUsages is the extended key usages the issued certificates may have
+	AllowedExtKeyUsages []x509.ExtKeyUsage
+
+	// MaxChainDepth is the maximum number of certificates in the chain of the issued certificates, the issued
+	// certificate included. No maximum is enforced when zero.
+	MaxChainDepth int
+
+	// ValidateIdentity returns an error if the common name of a requested certificate is not an identity of the mesh.
+	// No identity is rejected when nil.
+	ValidateIdentity func(CommonName) error
+}
+
+// IssuanceRequest is the content of a certificate request validated against an IssuancePolicy
+type IssuanceRequest struct {
+	CommonName     CommonName
+	DNSNames       []string
+	ValidityPeriod time.Duration
+	KeyUsage       x509.KeyUsage
+	ExtKeyUsages   []x509.ExtKeyUsage
+	IsCA           bool
+}
+
+// Validate returns an error wrapping ErrIssuanceNotAllowed if the given certificate request is not allowed by the
+// policy. A nil policy allows all requests.
+func (p *IssuancePolicy) Validate(req IssuanceRequest) error {
+	if p == nil {
+		return nil
+	}
+
+	if req.IsCA {
+		return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s requests a CA certificate", req.CommonName)
+	}
+
+	if p.MaxValidityPeriod > 0 && req.ValidityPeriod > p.MaxValidityPeriod {
+		return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s requests a validity period of %v, longer than %v", req.CommonName, req.ValidityPeriod, p.MaxValidityPeriod)
+	}
+
+	// The only SAN of the certificates issued for the mesh is their common name
+	for _, dnsName := range req.DNSNames {
+		if dnsName != req.CommonName.String() {
+			return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s requests SAN %s", req.CommonName, dnsName)
+		}
+	}
+
+	if disallowed := req.KeyUsage &^ p.AllowedKeyUsage; disallowed != 0 {
+		return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s requests key usage %d", req.CommonName, disallowed)
+	}
+
+	for _, extKeyUsage := range req.ExtKeyUsages {
+		if !p.isExtKeyUsageAllowed(extKeyUsage) {
+			return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s requests extended key usage %d", req.CommonName, extKeyUsage)
+		}
+	}
+
+	if p.ValidateIdentity != nil {
+		if err := p.ValidateIdentity(req.CommonName); err != nil {
+			return errors.Wrapf(ErrIssuanceNotAllowed, "CN=%s is not an identity of the mesh: %v", req.CommonName, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateChain returns an error wrapping ErrIssuanceNotAllowed if the given PEM encoded chain of an issued
+// certificate is deeper than allowed by the policy. A nil policy allows all chains.
+func (p *IssuancePolicy) ValidateChain(chainPEM []byte) error {
+	if p == nil || p.MaxChainDepth == 0 {
+		return nil
+	}
+
+	depth := 0
+	for block, rest := pem.Decode(chainPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == TypeCertificate {
+			depth++
+		}
+	}
+
+	if depth > p.MaxChainDepth {
+		return errors.Wrapf(ErrIssuanceNotAllowed, "issued certificate chain has a depth of %d, deeper than %d", depth, p.MaxChainDepth)
+	}
+	return nil
+}
+
+func (p *IssuancePolicy) isExtKeyUsageAllowed(extKeyUsage x509.ExtKeyUsage) bool {
+	for _, allowed := range p.AllowedExtKeyUsages {
+		if extKeyUsage == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/tests/certificates"
+)
+
+func TestIssuancePolicyValidate(t *testing.T) {
+	policy := &IssuancePolicy{
+		MaxValidityPeriod:   24 * time.Hour,
+		AllowedKeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		AllowedExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		ValidateIdentity: func(cn CommonName) error {
+			if cn == "unknown.bookstore.cluster.local" {
+				return errors.New("service account not found")
+			}
+			return nil
+		},
+	}
+	validRequest := IssuanceRequest{
+		CommonName:     "bookstore.bookstore.cluster.local",
+		DNSNames:       []string{"bookstore.bookstore.cluster.local"},
+		ValidityPeriod: time.Hour,
+		KeyUsage:       x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	testCases := []struct {
+		name        string
+		modify      func(*IssuanceRequest)
+		expectError bool
+	}{
+		{
+			name:        "valid request",
+			modify:      func(*IssuanceRequest) {},
+			expectError: false,
+		},
+		{
+			name:        "CA certificate",
+			modify:      func(req *IssuanceRequest) { req.IsCA = true },
+			expectError: true,
+		},
+		{
+			name:        "validity period too long",
+			modify:      func(req *IssuanceRequest) { req.ValidityPeriod = 48 * time.Hour },
+			expectError: true,
+		},
+		{
+			name:        "SAN other than the common name",
+			modify:      func(req *IssuanceRequest) { req.DNSNames = append(req.DNSNames, "bookbuyer.bookbuyer.cluster.local") },
+			expectError: true,
+		},
+		{
+			name:        "key usage not allowed",
+			modify:      func(req *IssuanceRequest) { req.KeyUsage |= x509.KeyUsageCertSign },
+			expectError: true,
+		},
+		{
+			name:        "extended key usage not allowed",
+			modify:      func(req *IssuanceRequest) { req.ExtKeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning} },
+			expectError: true,
+		},
+		{
+			name: "unknown identity",
+			modify: func(req *IssuanceRequest) {
+				req.CommonName = "unknown.bookstore.cluster.local"
+				req.DNSNames = []string{"unknown.bookstore.cluster.local"}
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := validRequest
+			req.DNSNames = append([]string(nil), validRequest.DNSNames...)
+			tc.modify(&req)
+
+			err := policy.Validate(req)
+			assert.Equal(tc.expectError, err != nil)
+			if tc.expectError {
+				assert.True(errors.Is(err, ErrIssuanceNotAllowed))
+			}
+		})
+	}
+
+	// A nil policy allows all requests
+	var nilPolicy *IssuancePolicy
+	assert := tassert.New(t)
+	assert.Nil(nilPolicy.Validate(IssuanceRequest{IsCA: true}))
+}
+
+func TestIssuancePolicyValidateChain(t *testing.T) {
+	assert := tassert.New(t)
+
+	cert := []byte(certificates.SampleCertificatePEM + "\n")
+	chain := append(append([]byte{}, cert...), cert...)
+
+	policy := &IssuancePolicy{MaxChainDepth: 1}
+	assert.Nil(policy.ValidateChain(cert))
+	assert.True(errors.Is(policy.ValidateChain(chain), ErrIssuanceNotAllowed))
+
+	policy.MaxChainDepth = 0
+	assert.Nil(policy.ValidateChain(chain))
+}
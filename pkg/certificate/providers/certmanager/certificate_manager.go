@@ -195,6 +195,11 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		},
 	}
 
+	if err := cm.validateCertificateRequest(cr, csrDER); err != nil {
+		log.Error().Err(err).Msgf("Rejected request for certificate with CN=%s", cn)
+		return nil, err
+	}
+
 	cr, err = cm.client.Create(context.TODO(), cr, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
@@ -208,17 +213,23 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		return nil, err
 	}
 
-	cert, err := cm.certificaterFromCertificateRequest(cr, privKeyPEM)
-	if err != nil {
-		return nil, err
-	}
-
 	defer func() {
 		if err := cm.client.Delete(context.TODO(), cr.Name, metav1.DeleteOptions{}); err != nil {
 			log.Error().Err(err).Msgf("failed to delete CertificateRequest %s/%s", cm.namespace, cr.Name)
 		}
 	}()
 
+	// The issuer may sign certificates with intermediate CAs, returned in the chain of the issued certificate
+	if err := cm.policy.ValidateChain(cr.Status.Certificate); err != nil {
+		log.Error().Err(err).Msgf("Rejected certificate issued for CertificateRequest %s/%s", cm.namespace, cr.Name)
+		return nil, err
+	}
+
+	cert, err := cm.certificaterFromCertificateRequest(cr, privKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
 	cm.cacheLock.Lock()
 	defer cm.cacheLock.Unlock()
 	cm.cache[cert.GetCommonName()] = cert
@@ -226,6 +237,32 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 	return cert, nil
 }
 
+// validateCertificateRequest validates the given CertificateRequest and its CSR against the issuance policy
+func (cm *CertManager) validateCertificateRequest(cr *cmapi.CertificateRequest, csrDER []byte) error {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return fmt.Errorf("error parsing x509 certificate request: %s", err)
+	}
+
+	req := certificate.IssuanceRequest{
+		CommonName: certificate.CommonName(csr.Subject.CommonName),
+		DNSNames:   csr.DNSNames,
+		IsCA:       cr.Spec.IsCA,
+	}
+	if cr.Spec.Duration != nil {
+		req.ValidityPeriod = cr.Spec.Duration.Duration
+	}
+	for _, usage := range cr.Spec.Usages {
+		keyUsage, ok := keyUsages[usage]
+		if !ok {
+			return fmt.Errorf("unsupported key usage %s in CertificateRequest for CN=%s", usage, req.CommonName)
+		}
+		req.KeyUsage |= keyUsage
+	}
+
+	return cm.policy.Validate(req)
+}
+
 // NewCertManager will construct a new certificate.Certificater implemented
 // using Jetstack's cert-manager,
 func NewCertManager(
@@ -234,6 +271,7 @@ func NewCertManager(
 	namespace string,
 	issuerRef cmmeta.ObjectReference,
 	cfg configurator.Configurator,
+	policy *certificate.IssuancePolicy,
 ) (*CertManager, error) {
 	informerFactory := cminformers.NewSharedInformerFactory(client, time.Second*30)
 	crLister := informerFactory.Certmanager().V1beta1().CertificateRequests().Lister().CertificateRequests(namespace)
@@ -249,10 +287,11 @@ func NewCertManager(
 		issuerRef: issuerRef,
 		crLister:  crLister,
 		cfg:       cfg,
+		policy:    policy,
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(cm).Start(checkCertificateExpirationInterval)
+	rotor.New(cm, cfg).Start(checkCertificateExpirationInterval)
 
 	return cm, nil
 }
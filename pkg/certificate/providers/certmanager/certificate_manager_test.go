@@ -109,7 +109,11 @@ var _ = Describe("Test cert-manager Certificate Manager", func() {
 			}
 		})
 
-		cm, newCertError := NewCertManager(rootCertificator, fakeClient, "osm-system", cmmeta.ObjectReference{Name: "osm-ca"}, mockConfigurator)
+		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(validity).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
+
+		cm, newCertError := NewCertManager(rootCertificator, fakeClient, "osm-system", cmmeta.ObjectReference{Name: "osm-ca"}, mockConfigurator, nil)
 		It("should get an issued certificate from the cache", func() {
 			Expect(newCertError).ToNot(HaveOccurred())
 			cert, issueCertificateError := cm.IssueCertificate(cn, validity)
@@ -2,9 +2,11 @@
 package certmanager
 
 import (
+	"crypto/x509"
 	"sync"
 	"time"
 
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/typed/certmanager/v1beta1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1beta1"
@@ -26,6 +28,12 @@ const (
 
 var (
 	log = logger.New("cert-manager")
+
+	// keyUsages maps the key usages of the CertificateRequests created by OSM to x509 key usages
+	keyUsages = map[cmapi.KeyUsage]x509.KeyUsage{
+		cmapi.UsageKeyEncipherment:  x509.KeyUsageKeyEncipherment,
+		cmapi.UsageDigitalSignature: x509.KeyUsageDigitalSignature,
+	}
 )
 
 // CertManager implements certificate.Manager
@@ -52,6 +60,9 @@ type CertManager struct {
 	crLister cmlisters.CertificateRequestNamespaceLister
 
 	cfg configurator.Configurator
+
+	// The policy the CertificateRequests are validated against before they are created
+	policy *certificate.IssuancePolicy
 }
 
 // Certificate implements certificate.Certificater
@@ -21,6 +21,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/debugger"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
@@ -177,6 +178,7 @@ func GetCertificateFromSecret(ns string, secretName string, cert certificate.Cer
 		},
 		Data: secretData,
 	}
+	k8s.SetControlPlaneGeneratedMetadata(&secret.ObjectMeta)
 
 	if _, err := kubeClient.CoreV1().Secrets(ns).Create(context.TODO(), secret, metav1.CreateOptions{}); err == nil {
 		log.Info().Msg("CA created in kubernetes")
@@ -227,7 +229,7 @@ func (c *Config) getTresorOSMCertificateManager() (certificate.Manager, debugger
 		return nil, nil, errors.Errorf("Failed to synchronize certificate on Secrets API : %v", err)
 	}
 
-	certManager, err := tresor.NewCertManager(rootCert, rootCertOrganization, c.cfg)
+	certManager, err := tresor.NewCertManager(rootCert, rootCertOrganization, c.cfg, c.getIssuancePolicy(tresorMaxChainDepth))
 	if err != nil {
 		return nil, nil, errors.Errorf("Failed to instantiate Tresor as a Certificate Manager")
 	}
@@ -285,7 +287,7 @@ func (c *Config) getHashiVaultOSMCertificateManager(options VaultOptions) (certi
 
 	// A Vault address would have the following shape: "http://vault.default.svc.cluster.local:8200"
 	vaultAddr := fmt.Sprintf("%s://%s:%d", options.VaultProtocol, options.VaultHost, options.VaultPort)
-	vaultCertManager, err := vault.NewCertManager(vaultAddr, options.VaultToken, options.VaultRole, c.cfg)
+	vaultCertManager, err := vault.NewCertManager(vaultAddr, options.VaultToken, options.VaultRole, c.cfg, c.getIssuancePolicy(externalIssuerMaxChainDepth))
 	if err != nil {
 		return nil, nil, errors.Errorf("Error instantiating Hashicorp Vault as a Certificate Manager: %+v", err)
 	}
@@ -319,7 +321,7 @@ func (c *Config) getCertManagerOSMCertificateManager(options CertManagerOptions)
 		Name:  options.IssuerName,
 		Kind:  options.IssuerKind,
 		Group: options.IssuerGroup,
-	}, c.cfg)
+	}, c.cfg, c.getIssuancePolicy(externalIssuerMaxChainDepth))
 	if err != nil {
 		return nil, nil, errors.Errorf("Error instantiating Jetstack cert-manager as a Certificate Manager: %+v", err)
 	}
@@ -27,6 +27,9 @@ func TestGetCertificateManager(t *testing.T) {
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	mockConfigurator.EXPECT().IsDebugServerEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(24 * time.Hour).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
 
 	testCases := []struct {
 		name string
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+const (
+	// tresorMaxChainDepth is the maximum depth of the chain of the certificates issued by Tresor, which signs
+	// them with the root certificate
+	tresorMaxChainDepth = 1
+
+	// externalIssuerMaxChainDepth is the maximum depth of the chain of the certificates issued by Vault and
+	// cert-manager, which may sign them with intermediate CAs
+	externalIssuerMaxChainDepth = 3
+
+	// kubernetesServiceDomain is the last label of the DNS names of Kubernetes services
+	kubernetesServiceDomain = "svc"
+)
+
+// getIssuancePolicy returns the policy the certificates requested from the certificate manager are validated against
+func (c *Config) getIssuancePolicy(maxChainDepth int) *certificate.IssuancePolicy {
+	return &certificate.IssuancePolicy{
+		MaxValidityPeriod:   constants.XDSCertificateValidityPeriod,
+		AllowedKeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		AllowedExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		MaxChainDepth:       maxChainDepth,
+		ValidateIdentity:    c.validateIdentity,
+	}
+}
+
+// validateIdentity returns an error if the given common name is not the identity of an existing service account,
+// or of a control plane service. The common names of the certificates issued for the mesh are of the form:
+//   - <svc-account>.<namespace>.<trust-domain> for service certificates
+//   - <proxy-UUID>.<svc-account>.<namespace> for the xDS certificates of the proxies
+//   - <service>.<osm-namespace>.svc for the certificates of the control plane services
+func (c *Config) validateIdentity(cn certificate.CommonName) error {
+	if cn == constants.XDSServerCertificateCommonName {
+		return nil
+	}
+
+	chunks := strings.Split(cn.String(), constants.DomainDelimiter)
+	var svcAccount, namespace string
+	switch {
+	case strings.HasSuffix(cn.String(), constants.DomainDelimiter+identity.ClusterLocalTrustDomain) && len(chunks) == 4:
+		svcAccount, namespace = chunks[0], chunks[1]
+
+	case len(chunks) == 3 && chunks[2] == kubernetesServiceDomain:
+		if chunks[1] != c.providerNamespace {
+			return errors.Errorf("service %s/%s is not a control plane service", chunks[1], chunks[0])
+		}
+		return nil
+
+	case len(chunks) == 3:
+		if _, err := uuid.Parse(chunks[0]); err != nil {
+			return errors.Errorf("invalid proxy UUID %s", chunks[0])
+		}
+		svcAccount, namespace = chunks[1], chunks[2]
+
+	default:
+		return errors.New("unknown common name format")
+	}
+
+	if _, err := c.kubeClient.CoreV1().ServiceAccounts(namespace).Get(context.Background(), svcAccount, metav1.GetOptions{}); err != nil {
+		return errors.Wrapf(err, "error getting service account %s/%s", namespace, svcAccount)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestValidateIdentity(t *testing.T) {
+	c := &Config{
+		kubeClient: fake.NewSimpleClientset(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"},
+		}),
+		providerNamespace: "osm-system",
+	}
+
+	testCases := []struct {
+		cn          certificate.CommonName
+		expectError bool
+	}{
+		{cn: "bookstore.bookstore.cluster.local", expectError: false},
+		{cn: "bookbuyer.bookbuyer.cluster.local", expectError: true},
+		{cn: certificate.CommonName(fmt.Sprintf("%s.bookstore.bookstore", uuid.New())), expectError: false},
+		{cn: certificate.CommonName(fmt.Sprintf("%s.bookbuyer.bookbuyer", uuid.New())), expectError: true},
+		{cn: "not-a-uuid.bookstore.bookstore", expectError: true},
+		{cn: "osm-injector.osm-system.svc", expectError: false},
+		{cn: "osm-injector.bookstore.svc", expectError: true},
+		{cn: constants.XDSServerCertificateCommonName, expectError: false},
+		{cn: "www.example.com", expectError: true},
+		{cn: "localhost", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cn.String(), func(t *testing.T) {
+			assert := tassert.New(t)
+			err := c.validateIdentity(tc.cn)
+			assert.Equal(tc.expectError, err != nil, "%v", err)
+		})
+	}
+}
@@ -42,8 +42,9 @@ func (c Certificate) GetSerialNumber() certificate.SerialNumber {
 	return c.serialNumber
 }
 
-// NewCertManager creates a new CertManager with the passed CA and CA Private Key
-func NewCertManager(ca certificate.Certificater, certificatesOrganization string, cfg configurator.Configurator) (*CertManager, error) {
+// NewCertManager creates a new CertManager with the passed CA and CA Private Key.
+// The requested certificates are validated against the given issuance policy, unless nil.
+func NewCertManager(ca certificate.Certificater, certificatesOrganization string, cfg configurator.Configurator, policy *certificate.IssuancePolicy) (*CertManager, error) {
 	if ca == nil {
 		return nil, errNoIssuingCA
 	}
@@ -55,10 +56,12 @@ func NewCertManager(ca certificate.Certificater, certificatesOrganization string
 		certificatesOrganization: certificatesOrganization,
 
 		cfg: cfg,
+
+		policy: policy,
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(&certManager).Start(checkCertificateExpirationInterval)
+	rotor.New(&certManager, cfg).Start(checkCertificateExpirationInterval)
 
 	return &certManager, nil
 }
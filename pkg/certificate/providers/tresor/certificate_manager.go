@@ -50,6 +50,18 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		BasicConstraintsValid: true,
 	}
 
+	if err := cm.policy.Validate(certificate.IssuanceRequest{
+		CommonName:     cn,
+		DNSNames:       template.DNSNames,
+		ValidityPeriod: template.NotAfter.Sub(template.NotBefore),
+		KeyUsage:       template.KeyUsage,
+		ExtKeyUsages:   template.ExtKeyUsage,
+		IsCA:           template.IsCA,
+	}); err != nil {
+		log.Error().Err(err).Msgf("Rejected request for certificate with CN=%s", cn)
+		return nil, err
+	}
+
 	x509Root, err := certificate.DecodePEMCertificate(cm.ca.GetCertificateChain())
 	if err != nil {
 		log.Error().Err(err).Msg("Error decoding Root Certificate's PEM")
@@ -72,6 +84,11 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		return nil, err
 	}
 
+	if err := cm.policy.ValidateChain(certPEM); err != nil {
+		log.Error().Err(err).Msgf("Rejected certificate with SerialNumber=%s", serialNumber)
+		return nil, err
+	}
+
 	privKeyPEM, err := certificate.EncodeKeyDERtoPEM(certPrivKey)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error encoding private key for certificate with SerialNumber=%s", serialNumber)
@@ -1,11 +1,13 @@
 package tresor
 
 import (
+	"crypto/x509"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
@@ -33,12 +35,14 @@ var _ = Describe("Test Certificate Manager", func() {
 
 		mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(validity).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
 
 		rootCert, err := NewCA(cn, 1*time.Hour, rootCertCountry, rootCertLocality, rootCertOrganization)
 		if err != nil {
 			GinkgoT().Fatalf("Error loading CA from files %s and %s: %s", rootCertPem, rootKeyPem, err.Error())
 		}
-		m, newCertError := NewCertManager(rootCert, "org", mockConfigurator)
+		m, newCertError := NewCertManager(rootCert, "org", mockConfigurator, nil)
 		It("should issue a certificate", func() {
 			Expect(newCertError).ToNot(HaveOccurred())
 			cert, issueCertificateError := m.IssueCertificate(serviceFQDN, validity)
@@ -75,12 +79,14 @@ var _ = Describe("Test Certificate Manager", func() {
 
 		mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(validity).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
 
 		rootCert, err := NewCA(cn, validity, rootCertCountry, rootCertLocality, rootCertOrganization)
 		if err != nil {
 			GinkgoT().Fatalf("Error loading CA from files %s and %s: %s", rootCertPem, rootKeyPem, err.Error())
 		}
-		m, newCertError := NewCertManager(rootCert, "org", mockConfigurator)
+		m, newCertError := NewCertManager(rootCert, "org", mockConfigurator, nil)
 		It("should get an issued certificate from the cache", func() {
 			Expect(newCertError).ToNot(HaveOccurred())
 			cert, issueCertificateError := m.IssueCertificate(serviceFQDN, validity)
@@ -92,4 +98,39 @@ var _ = Describe("Test Certificate Manager", func() {
 			Expect(cachedCert).To(Equal(cert))
 		})
 	})
+
+	Context("Test issuing a certificate rejected by the issuance policy", func() {
+		validity := 1 * time.Hour
+		cn := certificate.CommonName("Test CA")
+
+		mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
+		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(validity).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
+
+		rootCert, err := NewCA(cn, validity, "US", "CA", "Open Service Mesh Tresor")
+		if err != nil {
+			GinkgoT().Fatalf("Error creating CA: %s", err.Error())
+		}
+		policy := &certificate.IssuancePolicy{
+			MaxValidityPeriod: validity,
+			AllowedKeyUsage:   x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+			AllowedExtKeyUsages: []x509.ExtKeyUsage{
+				x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth,
+			},
+			MaxChainDepth: 1,
+		}
+		m, newCertError := NewCertManager(rootCert, "org", mockConfigurator, policy)
+		It("should issue a certificate allowed by the policy", func() {
+			Expect(newCertError).ToNot(HaveOccurred())
+			_, issueCertificateError := m.IssueCertificate(serviceFQDN, validity)
+			Expect(issueCertificateError).ToNot(HaveOccurred())
+		})
+
+		It("should not issue a certificate with a validity period longer than allowed by the policy", func() {
+			Expect(newCertError).ToNot(HaveOccurred())
+			_, issueCertificateError := m.IssueCertificate("d.e.f", 2*validity)
+			Expect(errors.Is(issueCertificateError, certificate.ErrIssuanceNotAllowed)).To(BeTrue())
+		})
+	})
 })
@@ -40,6 +40,9 @@ type CertManager struct {
 	certificatesOrganization string
 
 	cfg configurator.Configurator
+
+	// The policy the requested certificates are validated against before they are signed
+	policy *certificate.IssuancePolicy
 }
 
 // Certificate implements certificate.Certificater
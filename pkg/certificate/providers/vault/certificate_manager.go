@@ -33,10 +33,12 @@ const (
 )
 
 // NewCertManager implements certificate.Manager and wraps a Hashi Vault with methods to allow easy certificate issuance.
-func NewCertManager(vaultAddr, token string, role string, cfg configurator.Configurator) (*CertManager, error) {
+// The requested certificates are validated against the given issuance policy, unless nil.
+func NewCertManager(vaultAddr, token string, role string, cfg configurator.Configurator, policy *certificate.IssuancePolicy) (*CertManager, error) {
 	c := &CertManager{
-		role: vaultRole(role),
-		cfg:  cfg,
+		role:   vaultRole(role),
+		cfg:    cfg,
+		policy: policy,
 	}
 	config := api.DefaultConfig()
 	config.Address = vaultAddr
@@ -50,7 +52,8 @@ func NewCertManager(vaultAddr, token string, role string, cfg configurator.Confi
 
 	c.client.SetToken(token)
 
-	issuingCA, serialNumber, err := c.getIssuingCA(c.issue)
+	// The temporary certificate issued to determine the issuing CA is not validated against the issuance policy
+	issuingCA, serialNumber, err := c.getIssuingCA(c.requestCertificate)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +67,7 @@ func NewCertManager(vaultAddr, token string, role string, cfg configurator.Confi
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(c).Start(checkCertificateExpirationInterval)
+	rotor.New(c, cfg).Start(checkCertificateExpirationInterval)
 
 	return c, nil
 }
@@ -85,6 +88,31 @@ func (cm *CertManager) getIssuingCA(issue func(certificate.CommonName, time.Dura
 }
 
 func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Duration) (certificate.Certificater, error) {
+	// The key usages of the certificates issued by Vault are defined by the Vault role, only the common name and
+	// validity period are requested by OSM.
+	if err := cm.policy.Validate(certificate.IssuanceRequest{
+		CommonName:     cn,
+		ValidityPeriod: validityPeriod,
+	}); err != nil {
+		log.Error().Err(err).Msgf("Rejected request for certificate with CN=%s", cn)
+		return nil, err
+	}
+
+	cert, err := cm.requestCertificate(cn, validityPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cm.policy.ValidateChain(cert.GetCertificateChain()); err != nil {
+		log.Error().Err(err).Msgf("Rejected certificate with SerialNumber=%s issued by Vault", cert.GetSerialNumber())
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// requestCertificate requests a new certificate from Vault
+func (cm *CertManager) requestCertificate(cn certificate.CommonName, validityPeriod time.Duration) (certificate.Certificater, error) {
 	secret, err := cm.client.Logical().Write(getIssueURL(cm.role).String(), getIssuanceData(cn, validityPeriod))
 	if err != nil {
 		log.Error().Err(err).Msgf("Error issuing new certificate for CN=%s", cn)
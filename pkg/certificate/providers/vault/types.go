@@ -26,6 +26,9 @@ type CertManager struct {
 	role vaultRole
 
 	cfg configurator.Configurator
+
+	// The policy the requested certificates are validated against before they are requested from Vault
+	policy *certificate.IssuancePolicy
 }
 
 type vaultRole string
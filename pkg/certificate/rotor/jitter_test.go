@@ -0,0 +1,126 @@
+package rotor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func newMockCertificates(mockCtrl *gomock.Controller, count int, expiration time.Time) []certificate.Certificater {
+	var certs []certificate.Certificater
+	for i := 0; i < count; i++ {
+		cert := certificate.NewMockCertificater(mockCtrl)
+		cert.EXPECT().GetCommonName().Return(certificate.CommonName(fmt.Sprintf("svc-%d.ns.cluster.local", i))).AnyTimes()
+		cert.EXPECT().GetSerialNumber().Return(certificate.SerialNumber(fmt.Sprintf("%x", 1000000+i*7919))).AnyTimes()
+		cert.EXPECT().GetExpiration().Return(expiration).AnyTimes()
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+func TestRotationJitter(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	maxJitter := 10 * time.Minute
+	certs := newMockCertificates(mockCtrl, 1000, time.Now().Add(time.Hour))
+
+	// The jitter of a certificate is the same on every check, and the jitter of the certificates is spread
+	// across the whole jitter window.
+	buckets := make([]int, 10)
+	for _, cert := range certs {
+		jitter := rotationJitter(cert, maxJitter)
+		assert.Equal(jitter, rotationJitter(cert, maxJitter))
+		assert.True(jitter >= 0 && jitter < maxJitter)
+		buckets[jitter*10/maxJitter]++
+	}
+	for i, count := range buckets {
+		assert.InDelta(100, count, 50, "jitter bucket %d", i)
+	}
+
+	assert.Equal(time.Duration(0), rotationJitter(certs[0], 0))
+}
+
+func TestCheckAndRotate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		validity    time.Duration
+		renewBefore time.Duration
+		jitter      time.Duration
+		expiresIn   time.Duration
+		minRotated  int
+		maxRotated  int
+	}{
+		{
+			name:        "no certificate expiring",
+			validity:    time.Hour,
+			renewBefore: 15 * time.Minute,
+			jitter:      10 * time.Minute,
+			expiresIn:   30 * time.Minute,
+			minRotated:  0,
+			maxRotated:  0,
+		},
+		{
+			name:        "certificates issued at the same time rotated across the jitter window",
+			validity:    time.Hour,
+			renewBefore: 15 * time.Minute,
+			jitter:      10 * time.Minute,
+			expiresIn:   20 * time.Minute,
+			minRotated:  400,
+			maxRotated:  600,
+		},
+		{
+			name:        "all certificates expiring",
+			validity:    time.Hour,
+			renewBefore: 15 * time.Minute,
+			jitter:      10 * time.Minute,
+			expiresIn:   15 * time.Minute,
+			minRotated:  1000,
+			maxRotated:  1000,
+		},
+		{
+			name:        "renew before exceeding half of the validity period",
+			validity:    time.Hour,
+			renewBefore: 45 * time.Minute,
+			jitter:      10 * time.Minute,
+			expiresIn:   40 * time.Minute,
+			minRotated:  0,
+			maxRotated:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCertManager := certificate.NewMockManager(mockCtrl)
+			mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(tc.validity).AnyTimes()
+			mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(tc.renewBefore).AnyTimes()
+			mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(tc.jitter).AnyTimes()
+
+			certs := newMockCertificates(mockCtrl, 1000, time.Now().Add(tc.expiresIn))
+			mockCertManager.EXPECT().ListCertificates().Return(certs, nil).Times(1)
+
+			rotated := 0
+			mockCertManager.EXPECT().RotateCertificate(gomock.Any()).DoAndReturn(func(cn certificate.CommonName) (certificate.Certificater, error) {
+				rotated++
+				return certs[0], nil
+			}).AnyTimes()
+
+			New(mockCertManager, mockConfigurator).checkAndRotate()
+
+			assert.GreaterOrEqual(rotated, tc.minRotated)
+			assert.LessOrEqual(rotated, tc.maxRotated)
+		})
+	}
+}
@@ -1,27 +1,27 @@
 package rotor
 
 import (
-	"math/rand"
+	"hash/fnv"
 	"time"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
 )
 
 const (
-	// How much earlier (before expiration) should a certificate be renewed
+	// How much earlier (before expiration) should a certificate be renewed, when not configured
 	renewBeforeCertExpires = 30 * time.Second
 
 	// So that we do not renew all certs at the same time - add noise.
-	// These define the min and max of the seconds of noise to be added
-	// to the early certificate renewal.
-	minNoiseSeconds = 1
-	maxNoiseSeconds = 5
+	// This defines the max of the noise added to the early certificate renewal, when not configured.
+	maxRotationJitter = 5 * time.Second
 )
 
 // New creates and starts a new facility for automatic certificate rotation.
-func New(certManager certificate.Manager) *CertRotor {
+func New(certManager certificate.Manager, cfg configurator.Configurator) *CertRotor {
 	return &CertRotor{
 		certManager: certManager,
+		cfg:         cfg,
 	}
 }
 
@@ -44,15 +44,17 @@ func (r *CertRotor) checkAndRotate() {
 		log.Error().Err(err).Msgf("Error listing all certificates")
 	}
 
+	renewBefore, jitter := r.getRotationSettings()
+
 	for _, cert := range certs {
-		shouldRotate := ShouldRotate(cert)
+		shouldRotate := shouldRotate(cert, renewBefore, jitter)
 
 		word := map[bool]string{true: "will", false: "will not"}[shouldRotate]
-		log.Trace().Msgf("Cert %s %s be rotated; expires in %+v; renewBeforeCertExpires is %+v",
+		log.Trace().Msgf("Cert %s %s be rotated; expires in %+v; renewBefore is %+v",
 			cert.GetCommonName(),
 			word,
 			time.Until(cert.GetExpiration()),
-			renewBeforeCertExpires)
+			renewBefore)
 
 		if shouldRotate {
 			// Remove the certificate from the cache of the certificate manager
@@ -66,14 +68,49 @@ func (r *CertRotor) checkAndRotate() {
 	}
 }
 
-// ShouldRotate determines whether a certificate should be rotated.
+// getRotationSettings returns how long before their expiration certificates are rotated, and the max of the noise
+// added to the early renewal.
+func (r *CertRotor) getRotationSettings() (time.Duration, time.Duration) {
+	// Certificate managers created without a configurator only issue certificates rotated with the default settings
+	if r.cfg == nil {
+		return renewBeforeCertExpires, maxRotationJitter
+	}
+
+	renewBefore := r.cfg.GetServiceCertRenewBefore()
+	jitter := r.cfg.GetServiceCertRotationJitter()
+
+	// A certificate renewed earlier than half of its validity period would be renewed again right away,
+	// so that the rotation of short-lived certificates is bounded.
+	if validity := r.cfg.GetServiceCertValidityPeriod(); renewBefore+jitter > validity/2 {
+		log.Error().Msgf("Certificate renewBefore %+v and rotation jitter %+v exceed half of the certificate validity period %+v; using renewBefore %+v and rotation jitter %+v",
+			renewBefore, jitter, validity, renewBeforeCertExpires, maxRotationJitter)
+		return renewBeforeCertExpires, maxRotationJitter
+	}
+
+	return renewBefore, jitter
+}
+
+// ShouldRotate determines whether a certificate should be rotated, based on the default rotation settings.
 func ShouldRotate(cert certificate.Certificater) bool {
+	return shouldRotate(cert, renewBeforeCertExpires, maxRotationJitter)
+}
+
+func shouldRotate(cert certificate.Certificater, renewBefore time.Duration, maxJitter time.Duration) bool {
 	// The certificate is going to expire at a timestamp T
-	// We want to renew earlier. How much earlier is defined in renewBeforeCertExpires.
-	// We add a few seconds noise to the early renew period so that certificates that may have been
+	// We want to renew earlier. How much earlier is defined in renewBefore.
+	// We add some noise to the early renew period so that certificates that may have been
 	// created at the same time are not renewed at the exact same time.
+	return time.Until(cert.GetExpiration()) <= renewBefore+rotationJitter(cert, maxJitter)
+}
 
-	intNoise := rand.Intn(maxNoiseSeconds-minNoiseSeconds) + minNoiseSeconds /* #nosec G404 */
-	secondsNoise := time.Duration(intNoise) * time.Second
-	return time.Until(cert.GetExpiration()) <= (renewBeforeCertExpires + secondsNoise)
+// rotationJitter returns the noise added to the early renewal of the given certificate, between 0 and maxJitter.
+// The noise is derived from the serial number of the certificate, so that it is the same on every check of the
+// certificate and spread evenly across certificates.
+func rotationJitter(cert certificate.Certificater, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(cert.GetSerialNumber()))
+	return time.Duration(h.Sum64() % uint64(maxJitter))
 }
@@ -49,6 +49,8 @@ var _ = Describe("Test Rotor", func() {
 
 		mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(1 * time.Hour).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRenewBefore().Return(30 * time.Second).AnyTimes()
+		mockConfigurator.EXPECT().GetServiceCertRotationJitter().Return(5 * time.Second).AnyTimes()
 
 		certManager := tresor.NewFakeCertManager(mockConfigurator)
 
@@ -76,7 +78,7 @@ var _ = Describe("Test Rotor", func() {
 			done := make(chan interface{})
 
 			start := time.Now()
-			rotor.New(certManager).Start(360 * time.Second)
+			rotor.New(certManager, mockConfigurator).Start(360 * time.Second)
 			// Wait for one certificate rotation to be announced and terminate
 			<-certAnnouncement
 			close(done)
@@ -3,6 +3,7 @@ package rotor
 
 import (
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
 
@@ -13,4 +14,5 @@ var (
 // CertRotor is a simple facility, which rotates expired certificates.
 type CertRotor struct {
 	certManager certificate.Manager
+	cfg         configurator.Configurator
 }
@@ -57,6 +57,7 @@ const (
 // EnvSettings describes all of the cli environment settings
 type EnvSettings struct {
 	namespace string
+	output    string
 	config    *genericclioptions.ConfigFlags
 }
 
@@ -83,6 +84,7 @@ func envOr(name, defaultVal string) string {
 // AddFlags binds flags to the given flagset.
 func (s *EnvSettings) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.namespace, "osm-namespace", s.namespace, "namespace for osm control plane")
+	fs.StringVar(&s.output, "output", "", "output format of the command results, json or yaml, human-readable if not set")
 }
 
 // EnvVars returns a map of all OSM related environment variables
@@ -97,6 +99,11 @@ func (s *EnvSettings) RESTClientGetter() genericclioptions.RESTClientGetter {
 	return s.config
 }
 
+// Output gets the output format of the command results, or an empty string for human-readable output
+func (s *EnvSettings) Output() string {
+	return s.output
+}
+
 // Namespace gets the namespace from the configuration
 func (s *EnvSettings) Namespace() string {
 	if ns, _, err := s.config.ToRawKubeConfigLoader().Namespace(); err == nil {
@@ -97,3 +97,15 @@ func TestRESTClientGetter(t *testing.T) {
 	env := New()
 	tassert.Same(t, env.config, env.RESTClientGetter())
 }
+
+func TestOutput(t *testing.T) {
+	assert := tassert.New(t)
+
+	env := New()
+	flags := pflag.NewFlagSet("test-output", pflag.ContinueOnError)
+	env.AddFlags(flags)
+	assert.Equal("", env.Output())
+
+	assert.Nil(flags.Parse([]string{"--output=json"}))
+	assert.Equal("json", env.Output())
+}
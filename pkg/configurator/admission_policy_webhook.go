@@ -0,0 +1,80 @@
+package configurator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+const (
+	// AdmissionPolicyWebhookName is the name of the validating webhook used for evaluating mesh resources against the admission policies
+	AdmissionPolicyWebhookName = "osm-admission-policy-webhook.k8s.io"
+
+	// webhookValidateAdmissionPolicy is the HTTP path at which the webhook expects to receive mesh resource events
+	webhookValidateAdmissionPolicy = "/validate-admission-policy"
+)
+
+func (whc *webhookConfig) admissionPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	log.Trace().Msgf("Received admission policy validating webhook request: Method=%v, URL=%v", req.Method, req.URL)
+
+	admissionRequestBody, err := webhook.GetAdmissionRequestBody(w, req)
+	if err != nil {
+		// Error was already logged and written to the ResponseWriter
+		return
+	}
+
+	requestForNamespace, admissionResp := whc.getAdmissionReqResp(admissionRequestBody, whc.validateAdmissionPolicy)
+
+	resp, err := json.Marshal(&admissionResp)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error marshalling admission response: %s", err), http.StatusInternalServerError)
+		log.Error().Err(err).Msgf("Error marshalling admission response; Responded to admission request for mesh resource in namespace %s with HTTP %v", requestForNamespace, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(resp); err != nil {
+		log.Error().Err(err).Msgf("Error writing admission response for mesh resource in namespace %s", requestForNamespace)
+	}
+}
+
+// validateAdmissionPolicy evaluates the mesh resource in the given request against the admission policies, and
+// denies the request if any policy denies it. The request is denied if the policies cannot be evaluated.
+func (whc *webhookConfig) validateAdmissionPolicy(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req == nil {
+		log.Error().Msg("nil admission request")
+		return webhook.AdmissionError(errNilAdmissionRequest)
+	}
+
+	denials, err := whc.admissionPolicy.Evaluate(req)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error evaluating admission policies for %s %s/%s", req.Kind.Kind, req.Namespace, req.Name)
+		resp := webhook.AdmissionError(err)
+		resp.UID = req.UID
+		return resp
+	}
+
+	resp := &admissionv1.AdmissionResponse{
+		Allowed: true,
+		Result:  &metav1.Status{Reason: ""},
+		UID:     req.UID,
+	}
+	if len(denials) == 0 {
+		return resp
+	}
+
+	log.Warn().Msgf("Admission policies denied %s %s/%s: %s", req.Kind.Kind, req.Namespace, req.Name, strings.Join(denials, "; "))
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: strings.Join(denials, "; "),
+	}
+	return resp
+}
@@ -0,0 +1,111 @@
+package configurator
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/admissionpolicy"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestValidateAdmissionPolicy(t *testing.T) {
+	const osmNamespace = "osm-system"
+
+	policy := `
+package osm.admission
+
+deny[msg] {
+	source := input.request.object.spec.sources[_]
+	source.name == "*"
+	msg := "wildcard sources are not allowed"
+}
+`
+
+	testCases := []struct {
+		name            string
+		policies        map[string]string
+		sourceName      string
+		expectedAllowed bool
+		expectedCode    int32
+	}{
+		{
+			name:            "allowed without admission policies",
+			policies:        nil,
+			sourceName:      "*",
+			expectedAllowed: true,
+		},
+		{
+			name:            "allowed by the admission policies",
+			policies:        map[string]string{"wildcards.rego": policy},
+			sourceName:      "bookbuyer",
+			expectedAllowed: true,
+		},
+		{
+			name:            "denied by the admission policies",
+			policies:        map[string]string{"wildcards.rego": policy},
+			sourceName:      "*",
+			expectedAllowed: false,
+			expectedCode:    http.StatusForbidden,
+		},
+		{
+			name:            "denied when the admission policies are invalid",
+			policies:        map[string]string{"invalid.rego": "package"},
+			sourceName:      "bookbuyer",
+			expectedAllowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset()
+			if tc.policies != nil {
+				kubeClient = fake.NewSimpleClientset(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      constants.AdmissionPolicyConfigMap,
+						Namespace: osmNamespace,
+					},
+					Data: tc.policies,
+				})
+			}
+
+			stop := make(chan struct{})
+			defer close(stop)
+
+			wh := &webhookConfig{
+				kubeClient:      kubeClient,
+				admissionPolicy: admissionpolicy.NewEvaluator(kubeClient, osmNamespace, constants.AdmissionPolicyConfigMap, stop),
+			}
+
+			raw, err := json.Marshal(smiAccess.TrafficTarget{
+				ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"},
+				Spec: smiAccess.TrafficTargetSpec{
+					Sources: []smiAccess.IdentityBindingSubject{
+						{Kind: "ServiceAccount", Name: tc.sourceName, Namespace: "bookbuyer"},
+					},
+				},
+			})
+			assert.Nil(err)
+
+			resp := wh.validateAdmissionPolicy(&admissionv1.AdmissionRequest{
+				UID:       "11111111-2222-3333-4444-555555555555",
+				Kind:      metav1.GroupVersionKind{Group: "access.smi-spec.io", Version: "v1alpha3", Kind: "TrafficTarget"},
+				Name:      "bookstore",
+				Namespace: "bookstore",
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.Equal(tc.expectedAllowed, resp.Allowed)
+			assert.Equal(tc.expectedCode, resp.Result.Code)
+		})
+	}
+}
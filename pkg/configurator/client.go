@@ -32,6 +32,13 @@ const (
 	// useHTTPSIngressKey is the key name used for HTTPS ingress in the ConfigMap
 	useHTTPSIngressKey = "use_https_ingress"
 
+	// ingressClassKey is the key name used to specify the class of the ingress resources OSM programs ingress policies for
+	ingressClassKey = "ingress_class"
+
+	// implementationSpecificPathMatchKey is the key name used to specify how the ImplementationSpecific paths of ingress
+	// resources are matched
+	implementationSpecificPathMatchKey = "implementation_specific_path_match"
+
 	// tracingEnableKey is the key name used for tracing in the ConfigMap
 	tracingEnableKey = "tracing_enable"
 
@@ -58,6 +65,127 @@ const (
 
 	// configResyncInterval is the key name used to configure the resync interval for regular proxy broadcast updates
 	configResyncInterval = "config_resync_interval"
+
+	// maxProxyConfigSizeKey is the key name used to specify the maximum size of the config sent to a proxy
+	maxProxyConfigSizeKey = "max_proxy_config_size"
+
+	// sidecarImageDigestKey is the key name used to specify the digest the injected sidecar image must be pinned to
+	sidecarImageDigestKey = "sidecar_image_digest"
+
+	// sidecarImageCosignPublicKeyKey is the key name used to specify the public key the cosign signature of the injected sidecar image must be verified with
+	sidecarImageCosignPublicKeyKey = "sidecar_image_cosign_public_key"
+
+	// unmeshedPodPolicyKey is the key name used to specify how pods excluded from the mesh are admitted in namespaces enabled for sidecar injection
+	unmeshedPodPolicyKey = "unmeshed_pod_policy"
+
+	// excludedNamespacesKey is the key name used to specify the namespaces excluded from the mesh regardless of their labels
+	excludedNamespacesKey = "excluded_namespaces"
+
+	// policyOwnershipKey is the key name used to specify which namespaces may author SMI TrafficTargets for a destination
+	policyOwnershipKey = "policy_ownership"
+
+	// wafModuleURLKey is the key name used to specify the URL the WAF WASM module is fetched from by sidecar proxies
+	wafModuleURLKey = "waf_module_url"
+
+	// wafModuleSHA256Key is the key name used to specify the SHA256 checksum of the WAF WASM module
+	wafModuleSHA256Key = "waf_module_sha256"
+
+	// forwardClientCertDetailsKey is the key name used to specify the client certificate fields forwarded to applications
+	forwardClientCertDetailsKey = "forward_client_cert_details"
+
+	// xffNumTrustedHopsKey is the key name used to specify the number of trusted proxy hops in the x-forwarded-for header
+	xffNumTrustedHopsKey = "xff_num_trusted_hops"
+
+	// useRemoteAddressKey is the key name used to specify whether the remote address of the connection is used as the client address
+	useRemoteAddressKey = "use_remote_address"
+
+	// skipXFFAppendKey is the key name used to specify whether appending the remote address to the x-forwarded-for header is skipped
+	skipXFFAppendKey = "skip_xff_append"
+
+	// rbacDenyReportingKey is the key name used to specify whether sidecar proxies report the requests denied by RBAC policies
+	rbacDenyReportingKey = "rbac_deny_reporting"
+
+	// policyUsageMetricsURLKey is the key name used to specify the URL of the Prometheus server the traffic policy usage is queried from
+	policyUsageMetricsURLKey = "policy_usage_metrics_url"
+
+	// unusedPolicyWindowKey is the key name used to specify the window over which a traffic policy without traffic is reported as unused
+	unusedPolicyWindowKey = "unused_policy_window"
+
+	// trafficTargetShadowDurationKey is the key name used to specify how long new or changed SMI TrafficTargets are
+	// evaluated in shadow mode before being enforced
+	trafficTargetShadowDurationKey = "traffic_target_shadow_duration"
+
+	// policyRecorderKey is the key name used to specify whether sidecar proxies report the requests observed in permissive
+	// traffic policy mode to the policy recorder
+	policyRecorderKey = "policy_recorder"
+
+	// introspectionAllowedClientsKey is the key name used to specify the common names of the client certificates allowed
+	// to call the introspection API of the controller
+	introspectionAllowedClientsKey = "introspection_allowed_clients"
+
+	// lifecycleWebhookURLsKey is the key name used to specify the URLs the mesh lifecycle events are posted to
+	lifecycleWebhookURLsKey = "lifecycle_webhook_urls"
+
+	// lifecycleWebhookEventsKey is the key name used to specify the types of the mesh lifecycle events posted to the
+	// lifecycle webhooks
+	lifecycleWebhookEventsKey = "lifecycle_webhook_events"
+
+	// serviceCertRenewBeforeKey is the key name used to specify how long before their expiration service certificates are rotated
+	serviceCertRenewBeforeKey = "service_cert_renew_before"
+
+	// serviceCertRotationJitterKey is the key name used to specify the maximum delay added to the early rotation of
+	// service certificates, spreading the rotation of certificates issued at the same time
+	serviceCertRotationJitterKey = "service_cert_rotation_jitter"
+
+	// publishTrustBundleKey is the key name used to specify whether the trust bundle of the mesh is published to a
+	// ConfigMap in every monitored namespace
+	publishTrustBundleKey = "publish_trust_bundle"
+
+	// controlPlaneMTLSKey is the key name used to specify whether the servers of the control plane only accept clients
+	// authenticated with certificates issued by the mesh CA for control plane identities
+	controlPlaneMTLSKey = "control_plane_mtls"
+
+	// adaptiveConcurrencyKey is the key name used to specify whether sidecar proxies shed the load of overloaded services
+	// with adaptive concurrency limits, unless overridden for a service
+	adaptiveConcurrencyKey = "adaptive_concurrency"
+
+	// adaptiveConcurrencyMaxLimitKey is the key name used to specify the maximum concurrency limit sidecar proxies
+	// allow when shedding load with adaptive concurrency limits, unless overridden for a service
+	adaptiveConcurrencyMaxLimitKey = "adaptive_concurrency_max_limit"
+
+	// proxyUIDKey is the key name used to specify the user ID the injected sidecar proxies run as
+	proxyUIDKey = "proxy_uid"
+
+	// proxyGIDKey is the key name used to specify the group ID the injected sidecar proxies run as
+	proxyGIDKey = "proxy_gid"
+
+	// proxySeccompProfileKey is the key name used to specify the seccomp profile of the injected containers
+	proxySeccompProfileKey = "proxy_seccomp_profile"
+
+	// proxyAppArmorProfileKey is the key name used to specify the AppArmor profile of the injected containers
+	proxyAppArmorProfileKey = "proxy_apparmor_profile"
+
+	// proxySELinuxOptionsKey is the key name used to specify the SELinux options of the injected containers
+	proxySELinuxOptionsKey = "proxy_selinux_options"
+
+	// maxMonitoredNamespacesKey is the key name used to specify the maximum number of namespaces admitted to the mesh
+	maxMonitoredNamespacesKey = "max_monitored_namespaces"
+
+	// maxServicesKey is the key name used to specify the number of services in the mesh beyond which no new namespace
+	// is admitted to the mesh
+	maxServicesKey = "max_services"
+
+	// maxProxiesKey is the key name used to specify the number of proxies in the mesh beyond which no new namespace is
+	// admitted to the mesh
+	maxProxiesKey = "max_proxies"
+
+	// egressMetricsKey is the key name used to specify whether sidecar proxies report their egress traffic to the
+	// controller, which aggregates it in metrics per external host
+	egressMetricsKey = "egress_metrics"
+
+	// egressAuditModeKey is the key name used to specify whether the egress connections not allowed by Egress policies
+	// are reported and still allowed, rather than denied, when egress is disabled
+	egressAuditModeKey = "egress_audit_mode"
 )
 
 // NewConfigurator implements configurator.Configurator and creates the Kubernetes client to manage namespaces.
@@ -79,6 +207,7 @@ func newConfigurator(kubeClient kubernetes.Interface, stop <-chan struct{}, osmN
 		cacheSynced:      make(chan interface{}),
 		osmNamespace:     osmNamespace,
 		osmConfigMapName: osmConfigMapName,
+		kubeClient:       kubeClient,
 	}
 
 	informerName := "ConfigMap"
@@ -92,6 +221,7 @@ func newConfigurator(kubeClient kubernetes.Interface, stop <-chan struct{}, osmN
 
 	// Start listener
 	go client.configMapListener()
+	go client.runStagedRolloutPromoter(stop)
 
 	client.run(stop)
 
@@ -126,7 +256,7 @@ func (c *Client) configMapListener() {
 				events.GetPubSubInstance().Publish(events.PubSubMessage{
 					AnnouncementType: announcements.ScheduleProxyBroadcast,
 					OldObj:           nil,
-					NewObj:           nil,
+					NewObj:           psubMsg.NewObj,
 				})
 
 			case announcements.ConfigMapDeleted:
@@ -135,7 +265,7 @@ func (c *Client) configMapListener() {
 					psubMsg.AnnouncementType)
 				events.GetPubSubInstance().Publish(events.PubSubMessage{
 					AnnouncementType: announcements.ScheduleProxyBroadcast,
-					OldObj:           nil,
+					OldObj:           psubMsg.OldObj,
 					NewObj:           nil,
 				})
 
@@ -159,19 +289,39 @@ func (c *Client) configMapListener() {
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.Egress != newConfigMap.Egress)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.PermissiveTrafficPolicyMode != newConfigMap.PermissiveTrafficPolicyMode)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.UseHTTPSIngress != newConfigMap.UseHTTPSIngress)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.IngressClass != newConfigMap.IngressClass)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.ImplementationSpecificPathMatch != newConfigMap.ImplementationSpecificPathMatch)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.TracingEnable != newConfigMap.TracingEnable)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.TracingAddress != newConfigMap.TracingAddress)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.TracingEndpoint != newConfigMap.TracingEndpoint)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.TracingPort != newConfigMap.TracingPort)
 				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.PrometheusScraping != newConfigMap.PrometheusScraping)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.MaxProxyConfigSize != newConfigMap.MaxProxyConfigSize)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.ExcludedNamespaces != newConfigMap.ExcludedNamespaces)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.WAFModuleURL != newConfigMap.WAFModuleURL)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.WAFModuleSHA256 != newConfigMap.WAFModuleSHA256)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.ForwardClientCertDetails != newConfigMap.ForwardClientCertDetails)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.XFFNumTrustedHops != newConfigMap.XFFNumTrustedHops)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.UseRemoteAddress != newConfigMap.UseRemoteAddress)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.SkipXFFAppend != newConfigMap.SkipXFFAppend)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.RBACDenyReporting != newConfigMap.RBACDenyReporting)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.PolicyRecorder != newConfigMap.PolicyRecorder)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.TrafficTargetShadowDuration != newConfigMap.TrafficTargetShadowDuration)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.AdaptiveConcurrency != newConfigMap.AdaptiveConcurrency)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.AdaptiveConcurrencyMaxLimit != newConfigMap.AdaptiveConcurrencyMaxLimit)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.EgressMetrics != newConfigMap.EgressMetrics)
+				triggerGlobalBroadcast = triggerGlobalBroadcast || (prevConfigMap.EgressAuditMode != newConfigMap.EgressAuditMode)
+
+				// Changes of the proxies the ConfigMap applies to in a staged rollout require a global broadcast as well
+				triggerGlobalBroadcast = cf.updateStagedRollout(prevConfigMapObj, newConfigMapObj) || triggerGlobalBroadcast
 
 				if triggerGlobalBroadcast {
 					log.Debug().Msgf("[%s] OSM ConfigMap update triggered global proxy broadcast",
 						psubMsg.AnnouncementType)
 					events.GetPubSubInstance().Publish(events.PubSubMessage{
 						AnnouncementType: announcements.ScheduleProxyBroadcast,
-						OldObj:           nil,
-						NewObj:           nil,
+						OldObj:           prevConfigMapObj,
+						NewObj:           newConfigMapObj,
 					})
 				} else {
 					log.Trace().Msgf("[%s] configmap update, NOT triggering global proxy broadcast",
@@ -203,6 +353,12 @@ type osmConfig struct {
 	// UseHTTPSIngress is a bool toggle enabling HTTPS protocol between ingress and backend pods
 	UseHTTPSIngress bool `yaml:"use_https_ingress"`
 
+	// IngressClass is the class of the ingress resources ingress policies are programmed for, all ingress resources if empty
+	IngressClass string `yaml:"ingress_class"`
+
+	// ImplementationSpecificPathMatch is how the ImplementationSpecific paths of ingress resources are matched
+	ImplementationSpecificPathMatch string `yaml:"implementation_specific_path_match"`
+
 	// TracingEnabled is a bool toggle used to enable or disable tracing
 	TracingEnable bool `yaml:"tracing_enable"`
 
@@ -230,6 +386,127 @@ type osmConfig struct {
 
 	// ConfigResyncInterval is a flag to configure resync interval for regular proxy broadcast updates
 	ConfigResyncInterval string `yaml:"config_resync_interval"`
+
+	// MaxProxyConfigSize is the maximum total size of the CDS, LDS and RDS responses sent to a proxy, expressed as a
+	// Kubernetes quantity (Ex: 8Mi). Responses growing the config beyond this size are withheld to avoid pushing the proxy
+	// past its memory limit.
+	MaxProxyConfigSize string `yaml:"max_proxy_config_size"`
+
+	// SidecarImageDigest is the digest the injected sidecar image must be pinned to, of the form sha256:<hex>
+	SidecarImageDigest string `yaml:"sidecar_image_digest"`
+
+	// SidecarImageCosignPublicKey is the PEM encoded public key the cosign signature of the injected sidecar image must be verified with
+	SidecarImageCosignPublicKey string `yaml:"sidecar_image_cosign_public_key"`
+
+	// UnmeshedPodPolicy determines how pods excluded from the mesh are admitted in namespaces enabled for sidecar injection,
+	// one of allow, audit or deny
+	UnmeshedPodPolicy string `yaml:"unmeshed_pod_policy"`
+
+	// ExcludedNamespaces is the comma separated list of namespaces excluded from the mesh regardless of their labels.
+	// A name ending with '*' excludes all namespaces with the given prefix.
+	ExcludedNamespaces string `yaml:"excluded_namespaces"`
+
+	// PolicyOwnership determines which namespaces may author SMI TrafficTargets for a destination,
+	// one of destination-namespace or any-namespace
+	PolicyOwnership string `yaml:"policy_ownership"`
+
+	// WAFModuleURL is the HTTP(S) URL the WAF WASM module is fetched from by sidecar proxies
+	WAFModuleURL string `yaml:"waf_module_url"`
+
+	// WAFModuleSHA256 is the hex encoded SHA256 checksum the fetched WAF WASM module must match
+	WAFModuleSHA256 string `yaml:"waf_module_sha256"`
+
+	// ForwardClientCertDetails is the comma separated list of fields of the verified client certificate forwarded to
+	// applications in the x-forwarded-client-cert header, among subject, uri, dns, cert and chain
+	ForwardClientCertDetails string `yaml:"forward_client_cert_details"`
+
+	// XFFNumTrustedHops is the number of proxy hops in front of the sidecar, whose addresses in the x-forwarded-for header are trusted
+	XFFNumTrustedHops int `yaml:"xff_num_trusted_hops"`
+
+	// UseRemoteAddress is a bool toggle used to determine the client address from the remote address of the connection
+	// instead of the x-forwarded-for header
+	UseRemoteAddress bool `yaml:"use_remote_address"`
+
+	// SkipXFFAppend is a bool toggle used to skip appending the remote address of the connection to the x-forwarded-for header
+	SkipXFFAppend bool `yaml:"skip_xff_append"`
+
+	// RBACDenyReporting is a bool toggle used to report the requests denied by RBAC policies to the controller
+	RBACDenyReporting bool `yaml:"rbac_deny_reporting"`
+
+	// PolicyUsageMetricsURL is the URL of the Prometheus server scraping the sidecar proxies, queried for the traffic
+	// matched by SMI policies
+	PolicyUsageMetricsURL string `yaml:"policy_usage_metrics_url"`
+
+	// UnusedPolicyWindow is a string that defines the window over which an SMI policy without traffic is reported as unused
+	UnusedPolicyWindow string `yaml:"unused_policy_window"`
+
+	// TrafficTargetShadowDuration is a string that defines how long new or changed SMI TrafficTargets are evaluated in
+	// shadow mode before being enforced
+	TrafficTargetShadowDuration string `yaml:"traffic_target_shadow_duration"`
+
+	// PolicyRecorder is a bool toggle used to record the requests observed in permissive traffic policy mode as SMI policies
+	PolicyRecorder bool `yaml:"policy_recorder"`
+
+	// IntrospectionAllowedClients is a comma separated list of the common names of the client certificates allowed to call
+	// the introspection API of the controller
+	IntrospectionAllowedClients string `yaml:"introspection_allowed_clients"`
+
+	// LifecycleWebhookURLs is a comma separated list of the URLs the mesh lifecycle events are posted to
+	LifecycleWebhookURLs string `yaml:"lifecycle_webhook_urls"`
+
+	// LifecycleWebhookEvents is a comma separated list of the types of the mesh lifecycle events posted to the lifecycle
+	// webhooks, all types if empty
+	LifecycleWebhookEvents string `yaml:"lifecycle_webhook_events"`
+
+	// ServiceCertRenewBefore is a string that defines how long before their expiration service certificates are rotated
+	ServiceCertRenewBefore string `yaml:"service_cert_renew_before"`
+
+	// ServiceCertRotationJitter is a string that defines the maximum delay added to the early rotation of service
+	// certificates, spreading the rotation of certificates issued at the same time
+	ServiceCertRotationJitter string `yaml:"service_cert_rotation_jitter"`
+
+	// PublishTrustBundle is a bool toggle used to publish the trust bundle of the mesh to the monitored namespaces
+	PublishTrustBundle bool `yaml:"publish_trust_bundle"`
+
+	// ControlPlaneMTLS is a bool toggle used to require mTLS between the components of the control plane
+	ControlPlaneMTLS bool `yaml:"control_plane_mtls"`
+
+	// AdaptiveConcurrency is a bool toggle used to shed the load of overloaded services with adaptive concurrency limits
+	AdaptiveConcurrency bool `yaml:"adaptive_concurrency"`
+
+	// AdaptiveConcurrencyMaxLimit is the maximum concurrency limit allowed by adaptive concurrency limits
+	AdaptiveConcurrencyMaxLimit int `yaml:"adaptive_concurrency_max_limit"`
+
+	// ProxyUID is the user ID the injected sidecar proxies run as, and whose traffic is not redirected to the proxy
+	ProxyUID int `yaml:"proxy_uid"`
+
+	// ProxyGID is the group ID the injected sidecar proxies run as
+	ProxyGID int `yaml:"proxy_gid"`
+
+	// ProxySeccompProfile is the seccomp profile of the injected containers
+	ProxySeccompProfile string `yaml:"proxy_seccomp_profile"`
+
+	// ProxyAppArmorProfile is the AppArmor profile of the injected containers
+	ProxyAppArmorProfile string `yaml:"proxy_apparmor_profile"`
+
+	// ProxySELinuxOptions is the SELinux context of the injected containers
+	ProxySELinuxOptions string `yaml:"proxy_selinux_options"`
+
+	// MaxMonitoredNamespaces is the maximum number of namespaces admitted to the mesh
+	MaxMonitoredNamespaces int `yaml:"max_monitored_namespaces"`
+
+	// MaxServices is the number of services in the mesh beyond which no new namespace is admitted to the mesh
+	MaxServices int `yaml:"max_services"`
+
+	// MaxProxies is the number of proxies in the mesh beyond which no new namespace is admitted to the mesh
+	MaxProxies int `yaml:"max_proxies"`
+
+	// EgressMetrics is a bool toggle used to report the egress traffic of sidecar proxies to the controller
+	EgressMetrics bool `yaml:"egress_metrics"`
+
+	// EgressAuditMode is a bool toggle used to report the egress connections not allowed by Egress policies rather than
+	// deny them
+	EgressAuditMode bool `yaml:"egress_audit_mode"`
 }
 
 func (c *Client) run(stop <-chan struct{}) {
@@ -252,18 +529,11 @@ func (c *Client) getConfigMapCacheKey() string {
 
 // Returns the current ConfigMap
 func (c *Client) getConfigMap() *osmConfig {
-	configMapCacheKey := c.getConfigMapCacheKey()
-	item, exists, err := c.cache.GetByKey(configMapCacheKey)
-	if err != nil {
-		log.Error().Err(err).Msgf("Error getting ConfigMap from cache with key %s", configMapCacheKey)
-		return &osmConfig{}
-	}
-
-	if !exists {
-		log.Warn().Msgf("ConfigMap %s does not exist. Default config values will be used.", configMapCacheKey)
+	configMap := c.GetOSMConfigMap()
+	if configMap == nil {
+		log.Warn().Msgf("ConfigMap %s does not exist. Default config values will be used.", c.getConfigMapCacheKey())
 		return &osmConfig{}
 	}
-	configMap := item.(*v1.ConfigMap)
 
 	return parseOSMConfigMap(configMap)
 }
@@ -278,12 +548,50 @@ func parseOSMConfigMap(configMap *v1.ConfigMap) *osmConfig {
 	osmConfigMap.EnableDebugServer, _ = GetBoolValueForKey(configMap, enableDebugServer)
 	osmConfigMap.PrometheusScraping, _ = GetBoolValueForKey(configMap, prometheusScrapingKey)
 	osmConfigMap.UseHTTPSIngress, _ = GetBoolValueForKey(configMap, useHTTPSIngressKey)
+	osmConfigMap.IngressClass, _ = GetStringValueForKey(configMap, ingressClassKey)
+	osmConfigMap.ImplementationSpecificPathMatch, _ = GetStringValueForKey(configMap, implementationSpecificPathMatchKey)
 	osmConfigMap.TracingEnable, _ = GetBoolValueForKey(configMap, tracingEnableKey)
 	osmConfigMap.EnvoyLogLevel, _ = GetStringValueForKey(configMap, envoyLogLevel)
 	osmConfigMap.ServiceCertValidityDuration, _ = GetStringValueForKey(configMap, serviceCertValidityDurationKey)
 	osmConfigMap.OutboundIPRangeExclusionList, _ = GetStringValueForKey(configMap, outboundIPRangeExclusionListKey)
 	osmConfigMap.EnablePrivilegedInitContainer, _ = GetBoolValueForKey(configMap, enablePrivilegedInitContainer)
 	osmConfigMap.ConfigResyncInterval, _ = GetStringValueForKey(configMap, configResyncInterval)
+	osmConfigMap.MaxProxyConfigSize, _ = GetStringValueForKey(configMap, maxProxyConfigSizeKey)
+	osmConfigMap.SidecarImageDigest, _ = GetStringValueForKey(configMap, sidecarImageDigestKey)
+	osmConfigMap.SidecarImageCosignPublicKey, _ = GetStringValueForKey(configMap, sidecarImageCosignPublicKeyKey)
+	osmConfigMap.UnmeshedPodPolicy, _ = GetStringValueForKey(configMap, unmeshedPodPolicyKey)
+	osmConfigMap.ExcludedNamespaces, _ = GetStringValueForKey(configMap, excludedNamespacesKey)
+	osmConfigMap.PolicyOwnership, _ = GetStringValueForKey(configMap, policyOwnershipKey)
+	osmConfigMap.WAFModuleURL, _ = GetStringValueForKey(configMap, wafModuleURLKey)
+	osmConfigMap.WAFModuleSHA256, _ = GetStringValueForKey(configMap, wafModuleSHA256Key)
+	osmConfigMap.ForwardClientCertDetails, _ = GetStringValueForKey(configMap, forwardClientCertDetailsKey)
+	osmConfigMap.XFFNumTrustedHops, _ = GetIntValueForKey(configMap, xffNumTrustedHopsKey)
+	osmConfigMap.UseRemoteAddress, _ = GetBoolValueForKey(configMap, useRemoteAddressKey)
+	osmConfigMap.SkipXFFAppend, _ = GetBoolValueForKey(configMap, skipXFFAppendKey)
+	osmConfigMap.RBACDenyReporting, _ = GetBoolValueForKey(configMap, rbacDenyReportingKey)
+	osmConfigMap.PolicyUsageMetricsURL, _ = GetStringValueForKey(configMap, policyUsageMetricsURLKey)
+	osmConfigMap.UnusedPolicyWindow, _ = GetStringValueForKey(configMap, unusedPolicyWindowKey)
+	osmConfigMap.TrafficTargetShadowDuration, _ = GetStringValueForKey(configMap, trafficTargetShadowDurationKey)
+	osmConfigMap.PolicyRecorder, _ = GetBoolValueForKey(configMap, policyRecorderKey)
+	osmConfigMap.IntrospectionAllowedClients, _ = GetStringValueForKey(configMap, introspectionAllowedClientsKey)
+	osmConfigMap.LifecycleWebhookURLs, _ = GetStringValueForKey(configMap, lifecycleWebhookURLsKey)
+	osmConfigMap.LifecycleWebhookEvents, _ = GetStringValueForKey(configMap, lifecycleWebhookEventsKey)
+	osmConfigMap.ServiceCertRenewBefore, _ = GetStringValueForKey(configMap, serviceCertRenewBeforeKey)
+	osmConfigMap.ServiceCertRotationJitter, _ = GetStringValueForKey(configMap, serviceCertRotationJitterKey)
+	osmConfigMap.PublishTrustBundle, _ = GetBoolValueForKey(configMap, publishTrustBundleKey)
+	osmConfigMap.ControlPlaneMTLS, _ = GetBoolValueForKey(configMap, controlPlaneMTLSKey)
+	osmConfigMap.AdaptiveConcurrency, _ = GetBoolValueForKey(configMap, adaptiveConcurrencyKey)
+	osmConfigMap.AdaptiveConcurrencyMaxLimit, _ = GetIntValueForKey(configMap, adaptiveConcurrencyMaxLimitKey)
+	osmConfigMap.ProxyUID, _ = GetIntValueForKey(configMap, proxyUIDKey)
+	osmConfigMap.ProxyGID, _ = GetIntValueForKey(configMap, proxyGIDKey)
+	osmConfigMap.ProxySeccompProfile, _ = GetStringValueForKey(configMap, proxySeccompProfileKey)
+	osmConfigMap.ProxyAppArmorProfile, _ = GetStringValueForKey(configMap, proxyAppArmorProfileKey)
+	osmConfigMap.ProxySELinuxOptions, _ = GetStringValueForKey(configMap, proxySELinuxOptionsKey)
+	osmConfigMap.MaxMonitoredNamespaces, _ = GetIntValueForKey(configMap, maxMonitoredNamespacesKey)
+	osmConfigMap.MaxServices, _ = GetIntValueForKey(configMap, maxServicesKey)
+	osmConfigMap.MaxProxies, _ = GetIntValueForKey(configMap, maxProxiesKey)
+	osmConfigMap.EgressMetrics, _ = GetBoolValueForKey(configMap, egressMetricsKey)
+	osmConfigMap.EgressAuditMode, _ = GetBoolValueForKey(configMap, egressAuditModeKey)
 
 	if osmConfigMap.TracingEnable {
 		osmConfigMap.TracingAddress, _ = GetStringValueForKey(configMap, tracingAddressKey)
@@ -54,20 +54,58 @@ var _ = Describe("Test OSM ConfigMap parsing", func() {
 
 		It("Tag matches const key for all fields of OSM ConfigMap struct", func() {
 			fieldNameTag := map[string]string{
-				"PermissiveTrafficPolicyMode":   PermissiveTrafficPolicyModeKey,
-				"Egress":                        egressKey,
-				"EnableDebugServer":             enableDebugServer,
-				"PrometheusScraping":            prometheusScrapingKey,
-				"TracingEnable":                 tracingEnableKey,
-				"TracingAddress":                tracingAddressKey,
-				"TracingPort":                   tracingPortKey,
-				"TracingEndpoint":               tracingEndpointKey,
-				"UseHTTPSIngress":               useHTTPSIngressKey,
-				"EnvoyLogLevel":                 envoyLogLevel,
-				"ServiceCertValidityDuration":   serviceCertValidityDurationKey,
-				"OutboundIPRangeExclusionList":  outboundIPRangeExclusionListKey,
-				"EnablePrivilegedInitContainer": enablePrivilegedInitContainer,
-				"ConfigResyncInterval":          configResyncInterval,
+				"PermissiveTrafficPolicyMode":     PermissiveTrafficPolicyModeKey,
+				"Egress":                          egressKey,
+				"EnableDebugServer":               enableDebugServer,
+				"PrometheusScraping":              prometheusScrapingKey,
+				"TracingEnable":                   tracingEnableKey,
+				"TracingAddress":                  tracingAddressKey,
+				"TracingPort":                     tracingPortKey,
+				"TracingEndpoint":                 tracingEndpointKey,
+				"UseHTTPSIngress":                 useHTTPSIngressKey,
+				"IngressClass":                    ingressClassKey,
+				"ImplementationSpecificPathMatch": implementationSpecificPathMatchKey,
+				"EnvoyLogLevel":                   envoyLogLevel,
+				"ServiceCertValidityDuration":     serviceCertValidityDurationKey,
+				"OutboundIPRangeExclusionList":    outboundIPRangeExclusionListKey,
+				"EnablePrivilegedInitContainer":   enablePrivilegedInitContainer,
+				"ConfigResyncInterval":            configResyncInterval,
+				"MaxProxyConfigSize":              maxProxyConfigSizeKey,
+				"SidecarImageDigest":              sidecarImageDigestKey,
+				"SidecarImageCosignPublicKey":     sidecarImageCosignPublicKeyKey,
+				"UnmeshedPodPolicy":               unmeshedPodPolicyKey,
+				"ExcludedNamespaces":              excludedNamespacesKey,
+				"PolicyOwnership":                 policyOwnershipKey,
+				"WAFModuleURL":                    wafModuleURLKey,
+				"WAFModuleSHA256":                 wafModuleSHA256Key,
+				"ForwardClientCertDetails":        forwardClientCertDetailsKey,
+				"XFFNumTrustedHops":               xffNumTrustedHopsKey,
+				"UseRemoteAddress":                useRemoteAddressKey,
+				"SkipXFFAppend":                   skipXFFAppendKey,
+				"RBACDenyReporting":               rbacDenyReportingKey,
+				"PolicyUsageMetricsURL":           policyUsageMetricsURLKey,
+				"UnusedPolicyWindow":              unusedPolicyWindowKey,
+				"TrafficTargetShadowDuration":     trafficTargetShadowDurationKey,
+				"PolicyRecorder":                  policyRecorderKey,
+				"IntrospectionAllowedClients":     introspectionAllowedClientsKey,
+				"LifecycleWebhookURLs":            lifecycleWebhookURLsKey,
+				"LifecycleWebhookEvents":          lifecycleWebhookEventsKey,
+				"ServiceCertRenewBefore":          serviceCertRenewBeforeKey,
+				"ServiceCertRotationJitter":       serviceCertRotationJitterKey,
+				"PublishTrustBundle":              publishTrustBundleKey,
+				"ControlPlaneMTLS":                controlPlaneMTLSKey,
+				"AdaptiveConcurrency":             adaptiveConcurrencyKey,
+				"AdaptiveConcurrencyMaxLimit":     adaptiveConcurrencyMaxLimitKey,
+				"ProxyUID":                        proxyUIDKey,
+				"ProxyGID":                        proxyGIDKey,
+				"ProxySeccompProfile":             proxySeccompProfileKey,
+				"ProxyAppArmorProfile":            proxyAppArmorProfileKey,
+				"ProxySELinuxOptions":             proxySELinuxOptionsKey,
+				"MaxMonitoredNamespaces":          maxMonitoredNamespacesKey,
+				"MaxServices":                     maxServicesKey,
+				"MaxProxies":                      maxProxiesKey,
+				"EgressMetrics":                   egressMetricsKey,
+				"EgressAuditMode":                 egressAuditModeKey,
 			}
 			t := reflect.TypeOf(osmConfig{})
 
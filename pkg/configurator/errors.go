@@ -5,4 +5,5 @@ import "github.com/pkg/errors"
 var (
 	errMissingKeyInConfigMap = errors.New("missing key in ConfigMap")
 	errNilAdmissionRequest   = errors.New("nil admission request")
+	errInvalidPercentage     = errors.New("percentage must be between 0 and 100")
 )
@@ -6,12 +6,28 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/wafmodule"
 )
 
 const (
 	// defaultServiceCertValidityDuration is the default validity duration for service certificates
 	defaultServiceCertValidityDuration = 24 * time.Hour
+
+	// defaultUnusedPolicyWindow is the default window over which an SMI policy without traffic is reported as unused
+	defaultUnusedPolicyWindow = 7 * 24 * time.Hour
+
+	// defaultServiceCertRenewBefore is the default duration before their expiration service certificates are rotated
+	defaultServiceCertRenewBefore = 30 * time.Second
+
+	// defaultServiceCertRotationJitter is the default maximum delay added to the early rotation of service certificates
+	defaultServiceCertRotationJitter = 5 * time.Second
+
+	// defaultAdaptiveConcurrencyMaxLimit is the default maximum concurrency limit allowed by adaptive concurrency limits
+	defaultAdaptiveConcurrencyMaxLimit = 1000
 )
 
 // The functions in this file implement the configurator.Configurator interface
@@ -35,6 +51,20 @@ func (c *Client) GetConfigMap() ([]byte, error) {
 	return cm, nil
 }
 
+// GetOSMConfigMap returns the OSM ConfigMap, nil if it does not exist
+func (c *Client) GetOSMConfigMap() *corev1.ConfigMap {
+	configMapCacheKey := c.getConfigMapCacheKey()
+	item, exists, err := c.cache.GetByKey(configMapCacheKey)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting ConfigMap from cache with key %s", configMapCacheKey)
+		return nil
+	}
+	if !exists {
+		return nil
+	}
+	return item.(*corev1.ConfigMap)
+}
+
 // IsPermissiveTrafficPolicyMode tells us whether the OSM Control Plane is in permissive mode,
 // where all existing traffic is allowed to flow as it is,
 // or it is in SMI Spec mode, in which only traffic between source/destinations
@@ -95,6 +125,27 @@ func (c *Client) UseHTTPSIngress() bool {
 	return c.getConfigMap().UseHTTPSIngress
 }
 
+// GetIngressClass returns the class of the ingress resources ingress policies are programmed for, or an empty string
+// to program ingress policies for all ingress resources
+func (c *Client) GetIngressClass() string {
+	return strings.TrimSpace(c.getConfigMap().IngressClass)
+}
+
+// GetImplementationSpecificPathMatch returns how the ImplementationSpecific paths of ingress resources are matched.
+// Returns the auto match, guessing whether a path is a regular expression, if unset or invalid.
+func (c *Client) GetImplementationSpecificPathMatch() string {
+	pathMatch := strings.ToLower(strings.TrimSpace(c.getConfigMap().ImplementationSpecificPathMatch))
+	switch pathMatch {
+	case constants.ImplementationSpecificPathMatchExact, constants.ImplementationSpecificPathMatchPrefix, constants.ImplementationSpecificPathMatchRegex:
+		return pathMatch
+	case constants.ImplementationSpecificPathMatchAuto, "":
+		return constants.ImplementationSpecificPathMatchAuto
+	default:
+		log.Error().Msgf("Invalid ImplementationSpecific path match %s=%s, defaulting to %s", implementationSpecificPathMatchKey, pathMatch, constants.ImplementationSpecificPathMatchAuto)
+		return constants.ImplementationSpecificPathMatchAuto
+	}
+}
+
 // GetEnvoyLogLevel returns the envoy log level
 func (c *Client) GetEnvoyLogLevel() string {
 	logLevel := c.getConfigMap().EnvoyLogLevel
@@ -147,3 +198,376 @@ func (c *Client) GetConfigResyncInterval() time.Duration {
 	}
 	return duration
 }
+
+// GetMaxProxyConfigSize returns the maximum total size in bytes of the CDS, LDS and RDS responses sent to a proxy.
+// If unset or non-parsable, returns 0 which indicates there is no limit.
+func (c *Client) GetMaxProxyConfigSize() int64 {
+	maxSizeStr := c.getConfigMap().MaxProxyConfigSize
+	if maxSizeStr == "" {
+		return 0
+	}
+	maxSize, err := resource.ParseQuantity(maxSizeStr)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing max proxy config size %s=%s", maxProxyConfigSizeKey, maxSizeStr)
+		return 0
+	}
+	return maxSize.Value()
+}
+
+// GetSidecarImageDigest returns the digest the injected sidecar image must be pinned to.
+// An empty value indicates the sidecar image is not pinned.
+func (c *Client) GetSidecarImageDigest() string {
+	return strings.TrimSpace(c.getConfigMap().SidecarImageDigest)
+}
+
+// GetSidecarImageCosignPublicKey returns the PEM encoded public key the cosign signature of the injected sidecar image must be verified with.
+// An empty value indicates the signature of the sidecar image is not verified.
+func (c *Client) GetSidecarImageCosignPublicKey() string {
+	return strings.TrimSpace(c.getConfigMap().SidecarImageCosignPublicKey)
+}
+
+// GetUnmeshedPodPolicy returns the policy applied to pods excluded from the mesh in namespaces enabled for sidecar injection.
+// Returns the allow policy if unset or invalid.
+func (c *Client) GetUnmeshedPodPolicy() string {
+	policy := strings.ToLower(strings.TrimSpace(c.getConfigMap().UnmeshedPodPolicy))
+	switch policy {
+	case constants.UnmeshedPodPolicyAudit, constants.UnmeshedPodPolicyDeny:
+		return policy
+	case constants.UnmeshedPodPolicyAllow, "":
+		return constants.UnmeshedPodPolicyAllow
+	default:
+		log.Error().Msgf("Invalid unmeshed pod policy %s=%s, defaulting to %s", unmeshedPodPolicyKey, policy, constants.UnmeshedPodPolicyAllow)
+		return constants.UnmeshedPodPolicyAllow
+	}
+}
+
+// GetPolicyOwnership returns which namespaces may author SMI TrafficTargets for a destination.
+// Returns the destination namespace ownership if unset or invalid.
+func (c *Client) GetPolicyOwnership() string {
+	ownership := strings.ToLower(strings.TrimSpace(c.getConfigMap().PolicyOwnership))
+	switch ownership {
+	case constants.PolicyOwnershipAnyNamespace:
+		return ownership
+	case constants.PolicyOwnershipDestinationNamespace, "":
+		return constants.PolicyOwnershipDestinationNamespace
+	default:
+		log.Error().Msgf("Invalid policy ownership %s=%s, defaulting to %s", policyOwnershipKey, ownership, constants.PolicyOwnershipDestinationNamespace)
+		return constants.PolicyOwnershipDestinationNamespace
+	}
+}
+
+// GetWAFModuleURL returns the URL the WAF WASM module is fetched from by sidecar proxies, the URL of the module packaged
+// in the controller image if none is configured and the controller serves it.
+// An empty value indicates the WAF is disabled for all services.
+func (c *Client) GetWAFModuleURL() string {
+	if moduleURL := strings.TrimSpace(c.getConfigMap().WAFModuleURL); moduleURL != "" {
+		return moduleURL
+	}
+	if packaged := wafmodule.GetPackagedModule(); packaged != nil {
+		return packaged.URL
+	}
+	return ""
+}
+
+// GetWAFModuleSHA256 returns the hex encoded SHA256 checksum the fetched WAF WASM module must match
+func (c *Client) GetWAFModuleSHA256() string {
+	if strings.TrimSpace(c.getConfigMap().WAFModuleURL) != "" {
+		return strings.ToLower(strings.TrimSpace(c.getConfigMap().WAFModuleSHA256))
+	}
+	if packaged := wafmodule.GetPackagedModule(); packaged != nil {
+		return packaged.SHA256
+	}
+	return ""
+}
+
+// GetForwardClientCertDetails returns the fields of the verified client certificate forwarded to applications in the
+// x-forwarded-client-cert header. Invalid fields are ignored.
+func (c *Client) GetForwardClientCertDetails() []string {
+	var fields []string
+	for _, field := range strings.Split(c.getConfigMap().ForwardClientCertDetails, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !isValidClientCertField(field) {
+			log.Error().Msgf("Invalid client certificate field in %s: %s", forwardClientCertDetailsKey, field)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// GetXFFNumTrustedHops returns the number of proxy hops in front of sidecar proxies, whose addresses in the
+// x-forwarded-for header are trusted to determine the original client address
+func (c *Client) GetXFFNumTrustedHops() uint32 {
+	hops := c.getConfigMap().XFFNumTrustedHops
+	if hops < 0 {
+		log.Error().Msgf("Invalid number of trusted hops %s=%d, defaulting to 0", xffNumTrustedHopsKey, hops)
+		return 0
+	}
+	return uint32(hops)
+}
+
+// UseRemoteAddress returns whether sidecar proxies determine the client address from the remote address of the
+// connection instead of the x-forwarded-for header
+func (c *Client) UseRemoteAddress() bool {
+	return c.getConfigMap().UseRemoteAddress
+}
+
+// SkipXFFAppend returns whether sidecar proxies skip appending the remote address of the connection to the
+// x-forwarded-for header
+func (c *Client) SkipXFFAppend() bool {
+	return c.getConfigMap().SkipXFFAppend
+}
+
+// IsRBACDenyReportingEnabled returns whether sidecar proxies report the requests denied by RBAC policies to the controller
+func (c *Client) IsRBACDenyReportingEnabled() bool {
+	return c.getConfigMap().RBACDenyReporting
+}
+
+// IsEgressMetricsEnabled returns whether sidecar proxies report their egress traffic to the controller
+func (c *Client) IsEgressMetricsEnabled() bool {
+	return c.getConfigMap().EgressMetrics
+}
+
+// IsEgressAuditModeEnabled returns whether the egress connections not allowed by Egress policies are reported and still
+// allowed, rather than denied, when egress is disabled
+func (c *Client) IsEgressAuditModeEnabled() bool {
+	return c.getConfigMap().EgressAuditMode
+}
+
+// GetPolicyUsageMetricsURL returns the URL of the Prometheus server queried for the traffic matched by SMI policies.
+// An empty URL means the usage of the policies is not known.
+func (c *Client) GetPolicyUsageMetricsURL() string {
+	return strings.TrimSpace(c.getConfigMap().PolicyUsageMetricsURL)
+}
+
+// GetUnusedPolicyWindow returns the window over which an SMI policy without traffic is reported as unused
+func (c *Client) GetUnusedPolicyWindow() time.Duration {
+	windowStr := c.getConfigMap().UnusedPolicyWindow
+	if windowStr == "" {
+		return defaultUnusedPolicyWindow
+	}
+	window, err := time.ParseDuration(windowStr)
+	if err != nil || window <= 0 {
+		log.Error().Err(err).Msgf("Error parsing unused policy window %s=%s", unusedPolicyWindowKey, windowStr)
+		return defaultUnusedPolicyWindow
+	}
+	return window
+}
+
+// GetTrafficTargetShadowDuration returns how long new or changed SMI TrafficTargets are evaluated in shadow mode before
+// being enforced. A zero duration means TrafficTargets are enforced as soon as they are created or changed.
+func (c *Client) GetTrafficTargetShadowDuration() time.Duration {
+	durationStr := c.getConfigMap().TrafficTargetShadowDuration
+	if durationStr == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration < 0 {
+		log.Error().Err(err).Msgf("Error parsing TrafficTarget shadow duration %s=%s", trafficTargetShadowDurationKey, durationStr)
+		return 0
+	}
+	return duration
+}
+
+// IsPolicyRecorderEnabled returns whether the requests observed in permissive traffic policy mode are recorded as SMI policies
+func (c *Client) IsPolicyRecorderEnabled() bool {
+	return c.getConfigMap().PolicyRecorder
+}
+
+// GetIntrospectionAllowedClients returns the common names of the client certificates allowed to call the introspection
+// API of the controller
+func (c *Client) GetIntrospectionAllowedClients() []string {
+	return parseCommaSeparatedList(c.getConfigMap().IntrospectionAllowedClients)
+}
+
+// GetLifecycleWebhookURLs returns the URLs the mesh lifecycle events are posted to
+func (c *Client) GetLifecycleWebhookURLs() []string {
+	return parseCommaSeparatedList(c.getConfigMap().LifecycleWebhookURLs)
+}
+
+// GetLifecycleWebhookEvents returns the types of the mesh lifecycle events posted to the lifecycle webhooks, all types
+// if empty
+func (c *Client) GetLifecycleWebhookEvents() []string {
+	return parseCommaSeparatedList(c.getConfigMap().LifecycleWebhookEvents)
+}
+
+// GetServiceCertRenewBefore returns how long before their expiration service certificates are rotated, and a default in
+// case of invalid duration
+func (c *Client) GetServiceCertRenewBefore() time.Duration {
+	durationStr := c.getConfigMap().ServiceCertRenewBefore
+	if durationStr == "" {
+		return defaultServiceCertRenewBefore
+	}
+	renewBefore, err := time.ParseDuration(durationStr)
+	if err != nil || renewBefore < 0 {
+		log.Error().Err(err).Msgf("Error parsing service certificate renew before duration %s=%s", serviceCertRenewBeforeKey, durationStr)
+		return defaultServiceCertRenewBefore
+	}
+	return renewBefore
+}
+
+// GetServiceCertRotationJitter returns the maximum delay added to the early rotation of service certificates, and a
+// default in case of invalid duration
+func (c *Client) GetServiceCertRotationJitter() time.Duration {
+	durationStr := c.getConfigMap().ServiceCertRotationJitter
+	if durationStr == "" {
+		return defaultServiceCertRotationJitter
+	}
+	jitter, err := time.ParseDuration(durationStr)
+	if err != nil || jitter < 0 {
+		log.Error().Err(err).Msgf("Error parsing service certificate rotation jitter %s=%s", serviceCertRotationJitterKey, durationStr)
+		return defaultServiceCertRotationJitter
+	}
+	return jitter
+}
+
+// IsTrustBundlePublished returns whether the trust bundle of the mesh is published to the monitored namespaces
+func (c *Client) IsTrustBundlePublished() bool {
+	return c.getConfigMap().PublishTrustBundle
+}
+
+// IsControlPlaneMTLSEnabled returns whether the servers of the control plane require mTLS with control plane identities
+func (c *Client) IsControlPlaneMTLSEnabled() bool {
+	return c.getConfigMap().ControlPlaneMTLS
+}
+
+// IsAdaptiveConcurrencyEnabled returns whether sidecar proxies shed the load of overloaded services with adaptive
+// concurrency limits, unless overridden for a service
+func (c *Client) IsAdaptiveConcurrencyEnabled() bool {
+	return c.getConfigMap().AdaptiveConcurrency
+}
+
+// GetAdaptiveConcurrencyMaxLimit returns the maximum concurrency limit allowed by adaptive concurrency limits, unless
+// overridden for a service, and a default in case of invalid limit
+func (c *Client) GetAdaptiveConcurrencyMaxLimit() uint32 {
+	limit := c.getConfigMap().AdaptiveConcurrencyMaxLimit
+	if limit <= 0 {
+		return defaultAdaptiveConcurrencyMaxLimit
+	}
+	return uint32(limit)
+}
+
+// GetProxyUID returns the user ID the injected sidecar proxies run as, and a default in case of invalid user ID.
+// The proxies never run as root.
+func (c *Client) GetProxyUID() int64 {
+	uid := c.getConfigMap().ProxyUID
+	if uid <= 0 {
+		return constants.EnvoyUID
+	}
+	return int64(uid)
+}
+
+// GetProxyGID returns the group ID the injected sidecar proxies run as, and a default in case of invalid group ID
+func (c *Client) GetProxyGID() int64 {
+	gid := c.getConfigMap().ProxyGID
+	if gid <= 0 {
+		return constants.EnvoyGID
+	}
+	return int64(gid)
+}
+
+// GetExcludedNamespaces returns the namespaces excluded from the mesh regardless of their labels.
+// A name ending with '*' excludes all namespaces with the given prefix.
+func (c *Client) GetExcludedNamespaces() []string {
+	return parseCommaSeparatedList(c.getConfigMap().ExcludedNamespaces)
+}
+
+// IsExcludedNamespace returns true if the given namespace is excluded from the mesh regardless of its labels
+func (c *Client) IsExcludedNamespace(namespace string) bool {
+	for _, excluded := range c.GetExcludedNamespaces() {
+		if prefix := strings.TrimSuffix(excluded, "*"); prefix != excluded {
+			if strings.HasPrefix(namespace, prefix) {
+				return true
+			}
+		} else if namespace == excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMaxMonitoredNamespaces returns the maximum number of namespaces admitted to the mesh.
+// A value of 0 indicates there is no limit.
+func (c *Client) GetMaxMonitoredNamespaces() int {
+	return getMeshLimit(maxMonitoredNamespacesKey, c.getConfigMap().MaxMonitoredNamespaces)
+}
+
+// GetMaxServices returns the number of services in the mesh beyond which no new namespace is admitted to the mesh.
+// A value of 0 indicates there is no limit.
+func (c *Client) GetMaxServices() int {
+	return getMeshLimit(maxServicesKey, c.getConfigMap().MaxServices)
+}
+
+// GetMaxProxies returns the number of proxies in the mesh beyond which no new namespace is admitted to the mesh.
+// A value of 0 indicates there is no limit.
+func (c *Client) GetMaxProxies() int {
+	return getMeshLimit(maxProxiesKey, c.getConfigMap().MaxProxies)
+}
+
+// getMeshLimit returns the given limit on the size of the mesh, or 0 (no limit) in case of invalid limit
+func getMeshLimit(key string, limit int) int {
+	if limit < 0 {
+		log.Error().Msgf("Invalid limit %s=%d, defaulting to no limit", key, limit)
+		return 0
+	}
+	return limit
+}
+
+// parseCommaSeparatedList returns the non-empty elements of the given comma separated list, without surrounding spaces
+func parseCommaSeparatedList(listStr string) []string {
+	var elements []string
+	for _, element := range strings.Split(listStr, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// GetProxySeccompProfile returns the seccomp profile of the containers injected in the pods of the mesh, and the
+// profile of the container runtime by default or in case of invalid profile
+func (c *Client) GetProxySeccompProfile() *corev1.SeccompProfile {
+	value := c.getConfigMap().ProxySeccompProfile
+	if value == "" {
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	profile, err := parseSeccompProfile(value)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing %s=%s, using the profile of the container runtime", proxySeccompProfileKey, value)
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	return profile
+}
+
+// GetProxyAppArmorProfile returns the AppArmor profile of the containers injected in the pods of the mesh, or an empty
+// string if the containers run with the default profile of the node
+func (c *Client) GetProxyAppArmorProfile() string {
+	value := c.getConfigMap().ProxyAppArmorProfile
+	if value == "" {
+		return ""
+	}
+	profile, err := parseAppArmorProfile(value)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing %s=%s, using the default profile of the nodes", proxyAppArmorProfileKey, value)
+		return ""
+	}
+	return profile
+}
+
+// GetProxySELinuxOptions returns the SELinux options of the containers injected in the pods of the mesh, or nil if the
+// containers run with the SELinux context assigned by the container runtime
+func (c *Client) GetProxySELinuxOptions() *corev1.SELinuxOptions {
+	value := c.getConfigMap().ProxySELinuxOptions
+	if value == "" {
+		return nil
+	}
+	options, err := parseSELinuxOptions(value)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing %s=%s, using the SELinux context assigned by the container runtime", proxySELinuxOptionsKey, value)
+		return nil
+	}
+	return options
+}
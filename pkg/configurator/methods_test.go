@@ -14,6 +14,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/wafmodule"
 )
 
 func TestGetConfigMapCacheKey(t *testing.T) {
@@ -264,6 +265,558 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.Equal(interval, time.Duration(0))
 			},
 		},
+		{
+			name:                 "GetMaxProxyConfigSize",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(0), cfg.GetMaxProxyConfigSize())
+			},
+			updatedConfigMapData: map[string]string{
+				maxProxyConfigSizeKey: "8Mi",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(8*1024*1024), cfg.GetMaxProxyConfigSize())
+			},
+		},
+		{
+			name:                 "NegativeGetMaxProxyConfigSize",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(0), cfg.GetMaxProxyConfigSize())
+			},
+			updatedConfigMapData: map[string]string{
+				maxProxyConfigSizeKey: "not-a-quantity",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(0), cfg.GetMaxProxyConfigSize())
+			},
+		},
+		{
+			name:                 "GetSidecarImageVerification",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetSidecarImageDigest())
+				assert.Equal("", cfg.GetSidecarImageCosignPublicKey())
+			},
+			updatedConfigMapData: map[string]string{
+				sidecarImageDigestKey:          " sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef ",
+				sidecarImageCosignPublicKeyKey: "-----BEGIN PUBLIC KEY-----\nkey\n-----END PUBLIC KEY-----\n",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", cfg.GetSidecarImageDigest())
+				assert.Equal("-----BEGIN PUBLIC KEY-----\nkey\n-----END PUBLIC KEY-----", cfg.GetSidecarImageCosignPublicKey())
+			},
+		},
+		{
+			name:                 "GetUnmeshedPodPolicy",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.UnmeshedPodPolicyAllow, cfg.GetUnmeshedPodPolicy())
+			},
+			updatedConfigMapData: map[string]string{
+				unmeshedPodPolicyKey: "Deny",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.UnmeshedPodPolicyDeny, cfg.GetUnmeshedPodPolicy())
+			},
+		},
+		{
+			name:                 "GetPolicyOwnership",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.PolicyOwnershipDestinationNamespace, cfg.GetPolicyOwnership())
+			},
+			updatedConfigMapData: map[string]string{
+				policyOwnershipKey: "any-namespace",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.PolicyOwnershipAnyNamespace, cfg.GetPolicyOwnership())
+			},
+		},
+		{
+			name:                 "GetWAFModule",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetWAFModuleURL())
+				assert.Equal("", cfg.GetWAFModuleSHA256())
+			},
+			updatedConfigMapData: map[string]string{
+				wafModuleURLKey:    " https://example.com/coraza.wasm ",
+				wafModuleSHA256Key: "0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF0123456789ABCDEF",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("https://example.com/coraza.wasm", cfg.GetWAFModuleURL())
+				assert.Equal("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", cfg.GetWAFModuleSHA256())
+			},
+		},
+		{
+			name:                 "GetWAFModule with packaged module",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				wafmodule.SetPackagedModule(&wafmodule.Module{
+					URL:    "http://osm-controller.osm-system.svc.cluster.local:9097/coraza-proxy-wasm.wasm",
+					SHA256: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+				})
+				assert.Equal("http://osm-controller.osm-system.svc.cluster.local:9097/coraza-proxy-wasm.wasm", cfg.GetWAFModuleURL())
+				assert.Equal("fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210", cfg.GetWAFModuleSHA256())
+			},
+			updatedConfigMapData: map[string]string{
+				wafModuleURLKey:    "https://example.com/coraza.wasm",
+				wafModuleSHA256Key: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				// The configured module takes precedence over the packaged module
+				assert.Equal("https://example.com/coraza.wasm", cfg.GetWAFModuleURL())
+				assert.Equal("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", cfg.GetWAFModuleSHA256())
+				wafmodule.SetPackagedModule(nil)
+			},
+		},
+		{
+			name:                 "GetIngressClass",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetIngressClass())
+			},
+			updatedConfigMapData: map[string]string{
+				ingressClassKey: " osm ",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("osm", cfg.GetIngressClass())
+			},
+		},
+		{
+			name:                 "GetImplementationSpecificPathMatch",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.ImplementationSpecificPathMatchAuto, cfg.GetImplementationSpecificPathMatch())
+			},
+			updatedConfigMapData: map[string]string{
+				implementationSpecificPathMatchKey: " Regex ",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.ImplementationSpecificPathMatchRegex, cfg.GetImplementationSpecificPathMatch())
+			},
+		},
+		{
+			name:                 "GetForwardClientCertDetails",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetForwardClientCertDetails())
+			},
+			updatedConfigMapData: map[string]string{
+				forwardClientCertDetailsKey: "Subject, dns,invalid,",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{constants.ClientCertFieldSubject, constants.ClientCertFieldDNS}, cfg.GetForwardClientCertDetails())
+			},
+		},
+		{
+			name:                 "GetForwardedHeaderSettings",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(0), cfg.GetXFFNumTrustedHops())
+				assert.False(cfg.UseRemoteAddress())
+				assert.False(cfg.SkipXFFAppend())
+			},
+			updatedConfigMapData: map[string]string{
+				xffNumTrustedHopsKey: "2",
+				useRemoteAddressKey:  "true",
+				skipXFFAppendKey:     "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(2), cfg.GetXFFNumTrustedHops())
+				assert.True(cfg.UseRemoteAddress())
+				assert.True(cfg.SkipXFFAppend())
+			},
+		},
+		{
+			name:                 "NegativeGetXFFNumTrustedHops",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(0), cfg.GetXFFNumTrustedHops())
+			},
+			updatedConfigMapData: map[string]string{
+				xffNumTrustedHopsKey: "-1",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(0), cfg.GetXFFNumTrustedHops())
+			},
+		},
+		{
+			name:                 "IsRBACDenyReportingEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsRBACDenyReportingEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				rbacDenyReportingKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsRBACDenyReportingEnabled())
+			},
+		},
+		{
+			name:                 "IsEgressMetricsEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsEgressMetricsEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				egressMetricsKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsEgressMetricsEnabled())
+			},
+		},
+		{
+			name:                 "IsEgressAuditModeEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsEgressAuditModeEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				egressAuditModeKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsEgressAuditModeEnabled())
+			},
+		},
+		{
+			name:                 "GetPolicyUsageMetricsURL",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetPolicyUsageMetricsURL())
+			},
+			updatedConfigMapData: map[string]string{
+				policyUsageMetricsURLKey: " http://osm-prometheus.osm-system.svc:7070 ",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("http://osm-prometheus.osm-system.svc:7070", cfg.GetPolicyUsageMetricsURL())
+			},
+		},
+		{
+			name:                 "GetUnusedPolicyWindow",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultUnusedPolicyWindow, cfg.GetUnusedPolicyWindow())
+			},
+			updatedConfigMapData: map[string]string{
+				unusedPolicyWindowKey: "24h",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(24*time.Hour, cfg.GetUnusedPolicyWindow())
+			},
+		},
+		{
+			name: "GetUnusedPolicyWindow with invalid window",
+			initialConfigMapData: map[string]string{
+				unusedPolicyWindowKey: "-1h",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultUnusedPolicyWindow, cfg.GetUnusedPolicyWindow())
+			},
+			updatedConfigMapData: map[string]string{
+				unusedPolicyWindowKey: "invalid",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultUnusedPolicyWindow, cfg.GetUnusedPolicyWindow())
+			},
+		},
+		{
+			name:                 "GetTrafficTargetShadowDuration",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetTrafficTargetShadowDuration())
+			},
+			updatedConfigMapData: map[string]string{
+				trafficTargetShadowDurationKey: "48h",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(48*time.Hour, cfg.GetTrafficTargetShadowDuration())
+			},
+		},
+		{
+			name: "GetTrafficTargetShadowDuration with invalid duration",
+			initialConfigMapData: map[string]string{
+				trafficTargetShadowDurationKey: "-1h",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetTrafficTargetShadowDuration())
+			},
+			updatedConfigMapData: map[string]string{
+				trafficTargetShadowDurationKey: "2d",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetTrafficTargetShadowDuration())
+			},
+		},
+		{
+			name:                 "IsPolicyRecorderEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsPolicyRecorderEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				policyRecorderKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsPolicyRecorderEnabled())
+			},
+		},
+		{
+			name:                 "GetIntrospectionAllowedClients",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetIntrospectionAllowedClients())
+			},
+			updatedConfigMapData: map[string]string{
+				introspectionAllowedClientsKey: "portal.platform.cluster.local, ,inventory.platform.cluster.local",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{"portal.platform.cluster.local", "inventory.platform.cluster.local"}, cfg.GetIntrospectionAllowedClients())
+			},
+		},
+		{
+			name:                 "GetLifecycleWebhookURLs",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetLifecycleWebhookURLs())
+			},
+			updatedConfigMapData: map[string]string{
+				lifecycleWebhookURLsKey: "https://hooks.example.com/osm, http://ticketing.platform.svc/events",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{"https://hooks.example.com/osm", "http://ticketing.platform.svc/events"}, cfg.GetLifecycleWebhookURLs())
+			},
+		},
+		{
+			name:                 "GetLifecycleWebhookEvents",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetLifecycleWebhookEvents())
+			},
+			updatedConfigMapData: map[string]string{
+				lifecycleWebhookEventsKey: "certificate-rotated,proxy-config-rejected",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{"certificate-rotated", "proxy-config-rejected"}, cfg.GetLifecycleWebhookEvents())
+			},
+		},
+		{
+			name:                 "GetServiceCertRenewBefore",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultServiceCertRenewBefore, cfg.GetServiceCertRenewBefore())
+			},
+			updatedConfigMapData: map[string]string{
+				serviceCertRenewBeforeKey: "15m",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(15*time.Minute, cfg.GetServiceCertRenewBefore())
+			},
+		},
+		{
+			name: "GetServiceCertRenewBefore with invalid duration",
+			initialConfigMapData: map[string]string{
+				serviceCertRenewBeforeKey: "-1m",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultServiceCertRenewBefore, cfg.GetServiceCertRenewBefore())
+			},
+			updatedConfigMapData: map[string]string{
+				serviceCertRenewBeforeKey: "invalid",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultServiceCertRenewBefore, cfg.GetServiceCertRenewBefore())
+			},
+		},
+		{
+			name:                 "GetServiceCertRotationJitter",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultServiceCertRotationJitter, cfg.GetServiceCertRotationJitter())
+			},
+			updatedConfigMapData: map[string]string{
+				serviceCertRotationJitterKey: "10m",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(10*time.Minute, cfg.GetServiceCertRotationJitter())
+			},
+		},
+		{
+			name: "GetServiceCertRotationJitter without jitter",
+			initialConfigMapData: map[string]string{
+				serviceCertRotationJitterKey: "0s",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetServiceCertRotationJitter())
+			},
+			updatedConfigMapData: map[string]string{
+				serviceCertRotationJitterKey: "invalid",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultServiceCertRotationJitter, cfg.GetServiceCertRotationJitter())
+			},
+		},
+		{
+			name:                 "IsTrustBundlePublished",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsTrustBundlePublished())
+			},
+			updatedConfigMapData: map[string]string{
+				publishTrustBundleKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsTrustBundlePublished())
+			},
+		},
+		{
+			name:                 "IsControlPlaneMTLSEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsControlPlaneMTLSEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				controlPlaneMTLSKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsControlPlaneMTLSEnabled())
+			},
+		},
+		{
+			name:                 "IsAdaptiveConcurrencyEnabled",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsAdaptiveConcurrencyEnabled())
+			},
+			updatedConfigMapData: map[string]string{
+				adaptiveConcurrencyKey: "true",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsAdaptiveConcurrencyEnabled())
+			},
+		},
+		{
+			name:                 "GetAdaptiveConcurrencyMaxLimit",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(defaultAdaptiveConcurrencyMaxLimit), cfg.GetAdaptiveConcurrencyMaxLimit())
+			},
+			updatedConfigMapData: map[string]string{
+				adaptiveConcurrencyMaxLimitKey: "200",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(uint32(200), cfg.GetAdaptiveConcurrencyMaxLimit())
+			},
+		},
+		{
+			name:                 "GetMeshLimits",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(0, cfg.GetMaxMonitoredNamespaces())
+				assert.Equal(0, cfg.GetMaxServices())
+				assert.Equal(0, cfg.GetMaxProxies())
+			},
+			updatedConfigMapData: map[string]string{
+				maxMonitoredNamespacesKey: "50",
+				maxServicesKey:            "2000",
+				maxProxiesKey:             "-1",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(50, cfg.GetMaxMonitoredNamespaces())
+				assert.Equal(2000, cfg.GetMaxServices())
+				assert.Equal(0, cfg.GetMaxProxies())
+			},
+		},
+		{
+			name:                 "GetProxyUID",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.EnvoyUID, cfg.GetProxyUID())
+			},
+			updatedConfigMapData: map[string]string{
+				proxyUIDKey: "2102",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(2102), cfg.GetProxyUID())
+			},
+		},
+		{
+			name: "GetProxyGID",
+			initialConfigMapData: map[string]string{
+				proxyGIDKey: "0",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.EnvoyGID, cfg.GetProxyGID())
+			},
+			updatedConfigMapData: map[string]string{
+				proxyGIDKey: "2102",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(int64(2102), cfg.GetProxyGID())
+			},
+		},
+		{
+			name:                 "GetProxySeccompProfile",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(&v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}, cfg.GetProxySeccompProfile())
+			},
+			updatedConfigMapData: map[string]string{
+				proxySeccompProfileKey: "localhost/profiles/envoy.json",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				localhostProfile := "profiles/envoy.json"
+				assert.Equal(&v1.SeccompProfile{Type: v1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}, cfg.GetProxySeccompProfile())
+			},
+		},
+		{
+			name: "GetProxyAppArmorProfile",
+			initialConfigMapData: map[string]string{
+				proxyAppArmorProfileKey: "invalid",
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetProxyAppArmorProfile())
+			},
+			updatedConfigMapData: map[string]string{
+				proxyAppArmorProfileKey: "runtime/default",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("runtime/default", cfg.GetProxyAppArmorProfile())
+			},
+		},
+		{
+			name:                 "GetProxySELinuxOptions",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetProxySELinuxOptions())
+			},
+			updatedConfigMapData: map[string]string{
+				proxySELinuxOptionsKey: "::container_t:s0:c123,c456",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(&v1.SELinuxOptions{Type: "container_t", Level: "s0:c123,c456"}, cfg.GetProxySELinuxOptions())
+			},
+		},
+		{
+			name:                 "IsExcludedNamespace",
+			initialConfigMapData: map[string]string{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetExcludedNamespaces())
+				assert.False(cfg.IsExcludedNamespace("kube-system"))
+			},
+			updatedConfigMapData: map[string]string{
+				excludedNamespacesKey: "kube-system, openshift-*",
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{"kube-system", "openshift-*"}, cfg.GetExcludedNamespaces())
+				assert.True(cfg.IsExcludedNamespace("kube-system"))
+				assert.True(cfg.IsExcludedNamespace("openshift-monitoring"))
+				assert.False(cfg.IsExcludedNamespace("kube-public"))
+				assert.False(cfg.IsExcludedNamespace("openshift"))
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -9,6 +9,7 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	v1 "k8s.io/api/core/v1"
 )
 
 // MockConfigurator is a mock of Configurator interface
@@ -77,6 +78,132 @@ func (mr *MockConfiguratorMockRecorder) GetEnvoyLogLevel() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvoyLogLevel", reflect.TypeOf((*MockConfigurator)(nil).GetEnvoyLogLevel))
 }
 
+// GetExcludedNamespaces mocks base method
+func (m *MockConfigurator) GetExcludedNamespaces() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExcludedNamespaces")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetExcludedNamespaces indicates an expected call of GetExcludedNamespaces
+func (mr *MockConfiguratorMockRecorder) GetExcludedNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExcludedNamespaces", reflect.TypeOf((*MockConfigurator)(nil).GetExcludedNamespaces))
+}
+
+// GetForwardClientCertDetails mocks base method
+func (m *MockConfigurator) GetForwardClientCertDetails() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForwardClientCertDetails")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetForwardClientCertDetails indicates an expected call of GetForwardClientCertDetails
+func (mr *MockConfiguratorMockRecorder) GetForwardClientCertDetails() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForwardClientCertDetails", reflect.TypeOf((*MockConfigurator)(nil).GetForwardClientCertDetails))
+}
+
+// GetImplementationSpecificPathMatch mocks base method
+func (m *MockConfigurator) GetImplementationSpecificPathMatch() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImplementationSpecificPathMatch")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetImplementationSpecificPathMatch indicates an expected call of GetImplementationSpecificPathMatch
+func (mr *MockConfiguratorMockRecorder) GetImplementationSpecificPathMatch() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImplementationSpecificPathMatch", reflect.TypeOf((*MockConfigurator)(nil).GetImplementationSpecificPathMatch))
+}
+
+// GetIngressClass mocks base method
+func (m *MockConfigurator) GetIngressClass() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressClass")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetIngressClass indicates an expected call of GetIngressClass
+func (mr *MockConfiguratorMockRecorder) GetIngressClass() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressClass", reflect.TypeOf((*MockConfigurator)(nil).GetIngressClass))
+}
+
+// GetMaxMonitoredNamespaces mocks base method
+func (m *MockConfigurator) GetMaxMonitoredNamespaces() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxMonitoredNamespaces")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetMaxMonitoredNamespaces indicates an expected call of GetMaxMonitoredNamespaces
+func (mr *MockConfiguratorMockRecorder) GetMaxMonitoredNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxMonitoredNamespaces", reflect.TypeOf((*MockConfigurator)(nil).GetMaxMonitoredNamespaces))
+}
+
+// GetMaxProxies mocks base method
+func (m *MockConfigurator) GetMaxProxies() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxProxies")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetMaxProxies indicates an expected call of GetMaxProxies
+func (mr *MockConfiguratorMockRecorder) GetMaxProxies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxProxies", reflect.TypeOf((*MockConfigurator)(nil).GetMaxProxies))
+}
+
+// GetMaxProxyConfigSize mocks base method
+func (m *MockConfigurator) GetMaxProxyConfigSize() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxProxyConfigSize")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetMaxProxyConfigSize indicates an expected call of GetMaxProxyConfigSize
+func (mr *MockConfiguratorMockRecorder) GetMaxProxyConfigSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxProxyConfigSize", reflect.TypeOf((*MockConfigurator)(nil).GetMaxProxyConfigSize))
+}
+
+// GetMaxServices mocks base method
+func (m *MockConfigurator) GetMaxServices() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxServices")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetMaxServices indicates an expected call of GetMaxServices
+func (mr *MockConfiguratorMockRecorder) GetMaxServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxServices", reflect.TypeOf((*MockConfigurator)(nil).GetMaxServices))
+}
+
+// GetOSMConfigMap mocks base method
+func (m *MockConfigurator) GetOSMConfigMap() *v1.ConfigMap {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOSMConfigMap")
+	ret0, _ := ret[0].(*v1.ConfigMap)
+	return ret0
+}
+
+// GetOSMConfigMap indicates an expected call of GetOSMConfigMap
+func (mr *MockConfiguratorMockRecorder) GetOSMConfigMap() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOSMConfigMap", reflect.TypeOf((*MockConfigurator)(nil).GetOSMConfigMap))
+}
+
 // GetOSMNamespace mocks base method
 func (m *MockConfigurator) GetOSMNamespace() string {
 	m.ctrl.T.Helper()
@@ -105,6 +232,20 @@ func (mr *MockConfiguratorMockRecorder) GetOutboundIPRangeExclusionList() *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundIPRangeExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundIPRangeExclusionList))
 }
 
+// GetPolicyOwnership mocks base method
+func (m *MockConfigurator) GetPolicyOwnership() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyOwnership")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetPolicyOwnership indicates an expected call of GetPolicyOwnership
+func (mr *MockConfiguratorMockRecorder) GetPolicyOwnership() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyOwnership", reflect.TypeOf((*MockConfigurator)(nil).GetPolicyOwnership))
+}
+
 // GetServiceCertValidityPeriod mocks base method
 func (m *MockConfigurator) GetServiceCertValidityPeriod() time.Duration {
 	m.ctrl.T.Helper()
@@ -119,6 +260,34 @@ func (mr *MockConfiguratorMockRecorder) GetServiceCertValidityPeriod() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceCertValidityPeriod", reflect.TypeOf((*MockConfigurator)(nil).GetServiceCertValidityPeriod))
 }
 
+// GetSidecarImageCosignPublicKey mocks base method
+func (m *MockConfigurator) GetSidecarImageCosignPublicKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarImageCosignPublicKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSidecarImageCosignPublicKey indicates an expected call of GetSidecarImageCosignPublicKey
+func (mr *MockConfiguratorMockRecorder) GetSidecarImageCosignPublicKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarImageCosignPublicKey", reflect.TypeOf((*MockConfigurator)(nil).GetSidecarImageCosignPublicKey))
+}
+
+// GetSidecarImageDigest mocks base method
+func (m *MockConfigurator) GetSidecarImageDigest() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarImageDigest")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSidecarImageDigest indicates an expected call of GetSidecarImageDigest
+func (mr *MockConfiguratorMockRecorder) GetSidecarImageDigest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarImageDigest", reflect.TypeOf((*MockConfigurator)(nil).GetSidecarImageDigest))
+}
+
 // GetTracingEndpoint mocks base method
 func (m *MockConfigurator) GetTracingEndpoint() string {
 	m.ctrl.T.Helper()
@@ -161,6 +330,62 @@ func (mr *MockConfiguratorMockRecorder) GetTracingPort() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracingPort", reflect.TypeOf((*MockConfigurator)(nil).GetTracingPort))
 }
 
+// GetUnmeshedPodPolicy mocks base method
+func (m *MockConfigurator) GetUnmeshedPodPolicy() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnmeshedPodPolicy")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetUnmeshedPodPolicy indicates an expected call of GetUnmeshedPodPolicy
+func (mr *MockConfiguratorMockRecorder) GetUnmeshedPodPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnmeshedPodPolicy", reflect.TypeOf((*MockConfigurator)(nil).GetUnmeshedPodPolicy))
+}
+
+// GetWAFModuleSHA256 mocks base method
+func (m *MockConfigurator) GetWAFModuleSHA256() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWAFModuleSHA256")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetWAFModuleSHA256 indicates an expected call of GetWAFModuleSHA256
+func (mr *MockConfiguratorMockRecorder) GetWAFModuleSHA256() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWAFModuleSHA256", reflect.TypeOf((*MockConfigurator)(nil).GetWAFModuleSHA256))
+}
+
+// GetWAFModuleURL mocks base method
+func (m *MockConfigurator) GetWAFModuleURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWAFModuleURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetWAFModuleURL indicates an expected call of GetWAFModuleURL
+func (mr *MockConfiguratorMockRecorder) GetWAFModuleURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWAFModuleURL", reflect.TypeOf((*MockConfigurator)(nil).GetWAFModuleURL))
+}
+
+// GetXFFNumTrustedHops mocks base method
+func (m *MockConfigurator) GetXFFNumTrustedHops() uint32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetXFFNumTrustedHops")
+	ret0, _ := ret[0].(uint32)
+	return ret0
+}
+
+// GetXFFNumTrustedHops indicates an expected call of GetXFFNumTrustedHops
+func (mr *MockConfiguratorMockRecorder) GetXFFNumTrustedHops() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXFFNumTrustedHops", reflect.TypeOf((*MockConfigurator)(nil).GetXFFNumTrustedHops))
+}
+
 // IsDebugServerEnabled mocks base method
 func (m *MockConfigurator) IsDebugServerEnabled() bool {
 	m.ctrl.T.Helper()
@@ -175,6 +400,20 @@ func (mr *MockConfiguratorMockRecorder) IsDebugServerEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDebugServerEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsDebugServerEnabled))
 }
 
+// IsEgressAuditModeEnabled mocks base method
+func (m *MockConfigurator) IsEgressAuditModeEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEgressAuditModeEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEgressAuditModeEnabled indicates an expected call of IsEgressAuditModeEnabled
+func (mr *MockConfiguratorMockRecorder) IsEgressAuditModeEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressAuditModeEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsEgressAuditModeEnabled))
+}
+
 // IsEgressEnabled mocks base method
 func (m *MockConfigurator) IsEgressEnabled() bool {
 	m.ctrl.T.Helper()
@@ -189,6 +428,34 @@ func (mr *MockConfiguratorMockRecorder) IsEgressEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsEgressEnabled))
 }
 
+// IsEgressMetricsEnabled mocks base method
+func (m *MockConfigurator) IsEgressMetricsEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEgressMetricsEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsEgressMetricsEnabled indicates an expected call of IsEgressMetricsEnabled
+func (mr *MockConfiguratorMockRecorder) IsEgressMetricsEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressMetricsEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsEgressMetricsEnabled))
+}
+
+// IsExcludedNamespace mocks base method
+func (m *MockConfigurator) IsExcludedNamespace(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsExcludedNamespace", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsExcludedNamespace indicates an expected call of IsExcludedNamespace
+func (mr *MockConfiguratorMockRecorder) IsExcludedNamespace(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExcludedNamespace", reflect.TypeOf((*MockConfigurator)(nil).IsExcludedNamespace), arg0)
+}
+
 // IsPermissiveTrafficPolicyMode mocks base method
 func (m *MockConfigurator) IsPermissiveTrafficPolicyMode() bool {
 	m.ctrl.T.Helper()
@@ -231,6 +498,286 @@ func (mr *MockConfiguratorMockRecorder) IsPrometheusScrapingEnabled() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPrometheusScrapingEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsPrometheusScrapingEnabled))
 }
 
+// IsRBACDenyReportingEnabled mocks base method
+func (m *MockConfigurator) IsRBACDenyReportingEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRBACDenyReportingEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRBACDenyReportingEnabled indicates an expected call of IsRBACDenyReportingEnabled
+func (mr *MockConfiguratorMockRecorder) IsRBACDenyReportingEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRBACDenyReportingEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsRBACDenyReportingEnabled))
+}
+
+// GetPolicyUsageMetricsURL mocks base method
+func (m *MockConfigurator) GetPolicyUsageMetricsURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyUsageMetricsURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetPolicyUsageMetricsURL indicates an expected call of GetPolicyUsageMetricsURL
+func (mr *MockConfiguratorMockRecorder) GetPolicyUsageMetricsURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyUsageMetricsURL", reflect.TypeOf((*MockConfigurator)(nil).GetPolicyUsageMetricsURL))
+}
+
+// GetUnusedPolicyWindow mocks base method
+func (m *MockConfigurator) GetUnusedPolicyWindow() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedPolicyWindow")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetUnusedPolicyWindow indicates an expected call of GetUnusedPolicyWindow
+func (mr *MockConfiguratorMockRecorder) GetUnusedPolicyWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedPolicyWindow", reflect.TypeOf((*MockConfigurator)(nil).GetUnusedPolicyWindow))
+}
+
+// GetTrafficTargetShadowDuration mocks base method
+func (m *MockConfigurator) GetTrafficTargetShadowDuration() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrafficTargetShadowDuration")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetTrafficTargetShadowDuration indicates an expected call of GetTrafficTargetShadowDuration
+func (mr *MockConfiguratorMockRecorder) GetTrafficTargetShadowDuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficTargetShadowDuration", reflect.TypeOf((*MockConfigurator)(nil).GetTrafficTargetShadowDuration))
+}
+
+// IsPolicyRecorderEnabled mocks base method
+func (m *MockConfigurator) IsPolicyRecorderEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPolicyRecorderEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPolicyRecorderEnabled indicates an expected call of IsPolicyRecorderEnabled
+func (mr *MockConfiguratorMockRecorder) IsPolicyRecorderEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPolicyRecorderEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsPolicyRecorderEnabled))
+}
+
+// GetIntrospectionAllowedClients mocks base method
+func (m *MockConfigurator) GetIntrospectionAllowedClients() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIntrospectionAllowedClients")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetIntrospectionAllowedClients indicates an expected call of GetIntrospectionAllowedClients
+func (mr *MockConfiguratorMockRecorder) GetIntrospectionAllowedClients() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntrospectionAllowedClients", reflect.TypeOf((*MockConfigurator)(nil).GetIntrospectionAllowedClients))
+}
+
+// GetLifecycleWebhookURLs mocks base method
+func (m *MockConfigurator) GetLifecycleWebhookURLs() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLifecycleWebhookURLs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetLifecycleWebhookURLs indicates an expected call of GetLifecycleWebhookURLs
+func (mr *MockConfiguratorMockRecorder) GetLifecycleWebhookURLs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifecycleWebhookURLs", reflect.TypeOf((*MockConfigurator)(nil).GetLifecycleWebhookURLs))
+}
+
+// GetLifecycleWebhookEvents mocks base method
+func (m *MockConfigurator) GetLifecycleWebhookEvents() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLifecycleWebhookEvents")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetLifecycleWebhookEvents indicates an expected call of GetLifecycleWebhookEvents
+func (mr *MockConfiguratorMockRecorder) GetLifecycleWebhookEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifecycleWebhookEvents", reflect.TypeOf((*MockConfigurator)(nil).GetLifecycleWebhookEvents))
+}
+
+// GetServiceCertRenewBefore mocks base method
+func (m *MockConfigurator) GetServiceCertRenewBefore() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceCertRenewBefore")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetServiceCertRenewBefore indicates an expected call of GetServiceCertRenewBefore
+func (mr *MockConfiguratorMockRecorder) GetServiceCertRenewBefore() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceCertRenewBefore", reflect.TypeOf((*MockConfigurator)(nil).GetServiceCertRenewBefore))
+}
+
+// GetServiceCertRotationJitter mocks base method
+func (m *MockConfigurator) GetServiceCertRotationJitter() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceCertRotationJitter")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetServiceCertRotationJitter indicates an expected call of GetServiceCertRotationJitter
+func (mr *MockConfiguratorMockRecorder) GetServiceCertRotationJitter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceCertRotationJitter", reflect.TypeOf((*MockConfigurator)(nil).GetServiceCertRotationJitter))
+}
+
+// IsTrustBundlePublished mocks base method
+func (m *MockConfigurator) IsTrustBundlePublished() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTrustBundlePublished")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsTrustBundlePublished indicates an expected call of IsTrustBundlePublished
+func (mr *MockConfiguratorMockRecorder) IsTrustBundlePublished() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTrustBundlePublished", reflect.TypeOf((*MockConfigurator)(nil).IsTrustBundlePublished))
+}
+
+// IsControlPlaneMTLSEnabled mocks base method
+func (m *MockConfigurator) IsControlPlaneMTLSEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsControlPlaneMTLSEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsControlPlaneMTLSEnabled indicates an expected call of IsControlPlaneMTLSEnabled
+func (mr *MockConfiguratorMockRecorder) IsControlPlaneMTLSEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsControlPlaneMTLSEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsControlPlaneMTLSEnabled))
+}
+
+// IsAdaptiveConcurrencyEnabled mocks base method
+func (m *MockConfigurator) IsAdaptiveConcurrencyEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdaptiveConcurrencyEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAdaptiveConcurrencyEnabled indicates an expected call of IsAdaptiveConcurrencyEnabled
+func (mr *MockConfiguratorMockRecorder) IsAdaptiveConcurrencyEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdaptiveConcurrencyEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsAdaptiveConcurrencyEnabled))
+}
+
+// GetAdaptiveConcurrencyMaxLimit mocks base method
+func (m *MockConfigurator) GetAdaptiveConcurrencyMaxLimit() uint32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdaptiveConcurrencyMaxLimit")
+	ret0, _ := ret[0].(uint32)
+	return ret0
+}
+
+// GetAdaptiveConcurrencyMaxLimit indicates an expected call of GetAdaptiveConcurrencyMaxLimit
+func (mr *MockConfiguratorMockRecorder) GetAdaptiveConcurrencyMaxLimit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdaptiveConcurrencyMaxLimit", reflect.TypeOf((*MockConfigurator)(nil).GetAdaptiveConcurrencyMaxLimit))
+}
+
+// GetProxyUID mocks base method
+func (m *MockConfigurator) GetProxyUID() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyUID")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetProxyUID indicates an expected call of GetProxyUID
+func (mr *MockConfiguratorMockRecorder) GetProxyUID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyUID", reflect.TypeOf((*MockConfigurator)(nil).GetProxyUID))
+}
+
+// GetProxyGID mocks base method
+func (m *MockConfigurator) GetProxyGID() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyGID")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetProxyGID indicates an expected call of GetProxyGID
+func (mr *MockConfiguratorMockRecorder) GetProxyGID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyGID", reflect.TypeOf((*MockConfigurator)(nil).GetProxyGID))
+}
+
+// GetProxySeccompProfile mocks base method
+func (m *MockConfigurator) GetProxySeccompProfile() *v1.SeccompProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxySeccompProfile")
+	ret0, _ := ret[0].(*v1.SeccompProfile)
+	return ret0
+}
+
+// GetProxySeccompProfile indicates an expected call of GetProxySeccompProfile
+func (mr *MockConfiguratorMockRecorder) GetProxySeccompProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxySeccompProfile", reflect.TypeOf((*MockConfigurator)(nil).GetProxySeccompProfile))
+}
+
+// GetProxyAppArmorProfile mocks base method
+func (m *MockConfigurator) GetProxyAppArmorProfile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyAppArmorProfile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetProxyAppArmorProfile indicates an expected call of GetProxyAppArmorProfile
+func (mr *MockConfiguratorMockRecorder) GetProxyAppArmorProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyAppArmorProfile", reflect.TypeOf((*MockConfigurator)(nil).GetProxyAppArmorProfile))
+}
+
+// GetProxySELinuxOptions mocks base method
+func (m *MockConfigurator) GetProxySELinuxOptions() *v1.SELinuxOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxySELinuxOptions")
+	ret0, _ := ret[0].(*v1.SELinuxOptions)
+	return ret0
+}
+
+// GetProxySELinuxOptions indicates an expected call of GetProxySELinuxOptions
+func (mr *MockConfiguratorMockRecorder) GetProxySELinuxOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxySELinuxOptions", reflect.TypeOf((*MockConfigurator)(nil).GetProxySELinuxOptions))
+}
+
+// GetStagedRollout mocks base method
+func (m *MockConfigurator) GetStagedRollout() *StagedRollout {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStagedRollout")
+	ret0, _ := ret[0].(*StagedRollout)
+	return ret0
+}
+
+// GetStagedRollout indicates an expected call of GetStagedRollout
+func (mr *MockConfiguratorMockRecorder) GetStagedRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStagedRollout", reflect.TypeOf((*MockConfigurator)(nil).GetStagedRollout))
+}
+
 // IsTracingEnabled mocks base method
 func (m *MockConfigurator) IsTracingEnabled() bool {
 	m.ctrl.T.Helper()
@@ -245,6 +792,20 @@ func (mr *MockConfiguratorMockRecorder) IsTracingEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTracingEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsTracingEnabled))
 }
 
+// SkipXFFAppend mocks base method
+func (m *MockConfigurator) SkipXFFAppend() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkipXFFAppend")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SkipXFFAppend indicates an expected call of SkipXFFAppend
+func (mr *MockConfiguratorMockRecorder) SkipXFFAppend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipXFFAppend", reflect.TypeOf((*MockConfigurator)(nil).SkipXFFAppend))
+}
+
 // UseHTTPSIngress mocks base method
 func (m *MockConfigurator) UseHTTPSIngress() bool {
 	m.ctrl.T.Helper()
@@ -258,3 +819,17 @@ func (mr *MockConfiguratorMockRecorder) UseHTTPSIngress() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseHTTPSIngress", reflect.TypeOf((*MockConfigurator)(nil).UseHTTPSIngress))
 }
+
+// UseRemoteAddress mocks base method
+func (m *MockConfigurator) UseRemoteAddress() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRemoteAddress")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UseRemoteAddress indicates an expected call of UseRemoteAddress
+func (mr *MockConfiguratorMockRecorder) UseRemoteAddress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRemoteAddress", reflect.TypeOf((*MockConfigurator)(nil).UseRemoteAddress))
+}
@@ -0,0 +1,109 @@
+package configurator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Overrides resolves the configuration of a workload through the override hierarchy of the mesh: the mesh-wide value
+// configured in the OSM ConfigMap is overridden by the annotation of the workload's namespace, which is itself
+// overridden by the annotation of the workload, such as its service or pod.
+type Overrides struct {
+	// objects are the Kubernetes objects whose annotations override the mesh-wide configuration, ordered from the
+	// least to the most specific
+	objects []metav1.Object
+}
+
+// NewOverrides returns the overrides of the mesh-wide configuration by the annotations of the given Kubernetes objects,
+// ordered from the least to the most specific, typically the namespace and then the workload. Nil objects are ignored.
+func NewOverrides(objects ...metav1.Object) Overrides {
+	var overrides Overrides
+	for _, obj := range objects {
+		if obj == nil || reflect.ValueOf(obj).IsNil() {
+			continue
+		}
+		overrides.objects = append(overrides.objects, obj)
+	}
+	return overrides
+}
+
+// Bool returns the value of the given boolean annotation of the most specific object setting a valid value, or the
+// given mesh-wide value if no object sets a valid value
+func (o Overrides) Bool(key string, meshValue bool) bool {
+	value := meshValue
+	o.resolve(key, func(annotation string) error {
+		parsed, err := strconv.ParseBool(annotation)
+		if err != nil {
+			return err
+		}
+		value = parsed
+		return nil
+	})
+	return value
+}
+
+// Uint32 returns the value of the given integer annotation of the most specific object setting a valid value of at
+// least minValue, or the given mesh-wide value if no object sets a valid value
+func (o Overrides) Uint32(key string, meshValue uint32, minValue uint32) uint32 {
+	value := meshValue
+	o.resolve(key, func(annotation string) error {
+		parsed, err := strconv.ParseUint(annotation, 10, 32)
+		if err != nil {
+			return err
+		}
+		if uint32(parsed) < minValue {
+			return errors.Errorf("%d is less than %d", parsed, minValue)
+		}
+		value = uint32(parsed)
+		return nil
+	})
+	return value
+}
+
+// String returns the value of the given string annotation of the most specific object setting one of the given valid
+// values, or the given mesh-wide value if no object sets a valid value. Any value is valid if no valid value is given.
+func (o Overrides) String(key string, meshValue string, validValues ...string) string {
+	value := meshValue
+	o.resolve(key, func(annotation string) error {
+		if len(validValues) == 0 {
+			value = annotation
+			return nil
+		}
+		for _, validValue := range validValues {
+			if annotation == validValue {
+				value = annotation
+				return nil
+			}
+		}
+		return errors.Errorf("%q is not one of %s", annotation, strings.Join(validValues, ", "))
+	})
+	return value
+}
+
+// resolve calls the given parse function on the value of the given annotation of each object setting it, from the
+// least to the most specific object, so that the most specific valid value is parsed last. Invalid values are logged
+// and skipped, so that the value of a less specific object applies.
+func (o Overrides) resolve(key string, parse func(string) error) {
+	for _, obj := range o.objects {
+		annotation, ok := obj.GetAnnotations()[key]
+		if !ok {
+			continue
+		}
+		if err := parse(strings.TrimSpace(annotation)); err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on %s: %s", key, objectName(obj), annotation)
+		}
+	}
+}
+
+// objectName returns the namespaced name of the given namespaced object, or the name of the given cluster-scoped object
+func objectName(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,72 @@
+package configurator
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOverrides(t *testing.T) {
+	assert := tassert.New(t)
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "bookstore-ns",
+			Annotations: map[string]string{
+				"bool":            "true",
+				"uint32":          "10",
+				"string":          "debug",
+				"overridden-bool": "true",
+				"invalid-string":  "error",
+			},
+		},
+	}
+	workload := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookstore",
+			Namespace: "bookstore-ns",
+			Annotations: map[string]string{
+				"overridden-bool": "false",
+				"uint32":          "20",
+				"invalid-string":  "verbose",
+				"below-minimum":   "0",
+			},
+		},
+	}
+	overrides := NewOverrides(namespace, workload)
+
+	// Values not overridden are the mesh-wide values
+	assert.False(overrides.Bool("unset", false))
+	assert.Equal(uint32(5), overrides.Uint32("unset", 5, 0))
+	assert.Equal("info", overrides.String("unset", "info"))
+
+	// Namespace annotations override the mesh-wide values
+	assert.True(overrides.Bool("bool", false))
+	assert.Equal("debug", overrides.String("string", "info", ValidEnvoyLogLevels...))
+
+	// Workload annotations override the namespace annotations
+	assert.False(overrides.Bool("overridden-bool", false))
+	assert.Equal(uint32(20), overrides.Uint32("uint32", 5, 0))
+
+	// Invalid workload annotations fall back to the namespace annotations
+	assert.Equal("error", overrides.String("invalid-string", "info", ValidEnvoyLogLevels...))
+	assert.Equal(uint32(5), overrides.Uint32("below-minimum", 5, 1))
+}
+
+func TestNewOverridesWithNilObjects(t *testing.T) {
+	assert := tassert.New(t)
+
+	var namespace *corev1.Namespace
+	workload := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "bookstore",
+			Namespace:   "bookstore-ns",
+			Annotations: map[string]string{"bool": "true"},
+		},
+	}
+
+	assert.True(NewOverrides(namespace, workload).Bool("bool", false))
+	assert.False(NewOverrides(namespace, nil).Bool("bool", false))
+}
@@ -0,0 +1,110 @@
+package configurator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+const (
+	// PolicyOwnershipWebhookName is the name of the validating webhook used for validating the namespace SMI TrafficTargets are authored in
+	PolicyOwnershipWebhookName = "osm-policy-ownership-webhook.k8s.io"
+
+	// webhookValidateTrafficTarget is the HTTP path at which the webhook expects to receive TrafficTarget events
+	webhookValidateTrafficTarget = "/validate-traffic-target"
+)
+
+func (whc *webhookConfig) trafficTargetHandler(w http.ResponseWriter, req *http.Request) {
+	log.Trace().Msgf("Received policy ownership validating webhook request: Method=%v, URL=%v", req.Method, req.URL)
+
+	admissionRequestBody, err := webhook.GetAdmissionRequestBody(w, req)
+	if err != nil {
+		// Error was already logged and written to the ResponseWriter
+		return
+	}
+
+	requestForNamespace, admissionResp := whc.getAdmissionReqResp(admissionRequestBody, whc.validateTrafficTarget)
+
+	resp, err := json.Marshal(&admissionResp)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error marshalling admission response: %s", err), http.StatusInternalServerError)
+		log.Error().Err(err).Msgf("Error marshalling admission response; Responded to admission request for TrafficTarget in namespace %s with HTTP %v", requestForNamespace, http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := w.Write(resp); err != nil {
+		log.Error().Err(err).Msgf("Error writing admission response for TrafficTarget in namespace %s", requestForNamespace)
+	}
+}
+
+// validateTrafficTarget validates that the TrafficTarget in the given request is authored in a namespace allowed to author
+// policies for its destination. With the destination namespace policy ownership, a TrafficTarget must be authored in the
+// namespace of its destination, or in a namespace listed in the policy delegates annotation of the destination namespace.
+func (whc *webhookConfig) validateTrafficTarget(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req == nil {
+		log.Error().Msg("nil admission request")
+		return webhook.AdmissionError(errNilAdmissionRequest)
+	}
+
+	var trafficTarget smiAccess.TrafficTarget
+	if err := json.Unmarshal(req.Object.Raw, &trafficTarget); err != nil {
+		log.Error().Err(err).Msgf("Error unmarshaling request to TrafficTarget in namespace %s", req.Namespace)
+		return webhook.AdmissionError(err)
+	}
+
+	resp := &admissionv1.AdmissionResponse{
+		Allowed: true,
+		Result:  &metav1.Status{Reason: ""},
+		UID:     req.UID,
+	}
+
+	if whc.cfg.GetPolicyOwnership() == constants.PolicyOwnershipAnyNamespace {
+		return resp
+	}
+
+	destinationNamespace := trafficTarget.Spec.Destination.Namespace
+	if destinationNamespace == "" || destinationNamespace == req.Namespace {
+		return resp
+	}
+
+	ns, err := whc.kubeClient.CoreV1().Namespaces().Get(context.Background(), destinationNamespace, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		log.Error().Err(err).Msgf("Error getting destination namespace %s of TrafficTarget %s/%s", destinationNamespace, req.Namespace, req.Name)
+		return webhook.AdmissionError(err)
+	}
+	if err == nil && isPolicyDelegate(ns.Annotations[constants.PolicyDelegatesAnnotation], req.Namespace) {
+		return resp
+	}
+
+	log.Warn().Msgf("Rejecting TrafficTarget %s/%s with destination in namespace %s, which does not delegate policy authoring to namespace %s",
+		req.Namespace, req.Name, destinationNamespace, req.Namespace)
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusForbidden,
+		Reason: metav1.StatusReasonForbidden,
+		Message: fmt.Sprintf("TrafficTarget with destination in namespace %s must be authored in namespace %s, or in a namespace listed in the %s annotation of namespace %s",
+			destinationNamespace, destinationNamespace, constants.PolicyDelegatesAnnotation, destinationNamespace),
+	}
+	return resp
+}
+
+// isPolicyDelegate returns true if the given namespace is listed in the given comma separated list of delegate namespaces
+func isPolicyDelegate(delegates string, namespace string) bool {
+	for _, delegate := range strings.Split(delegates, ",") {
+		if strings.TrimSpace(delegate) == namespace {
+			return true
+		}
+	}
+	return false
+}
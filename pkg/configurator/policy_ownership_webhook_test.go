@@ -0,0 +1,137 @@
+package configurator
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestValidateTrafficTarget(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "bookstore",
+				Annotations: map[string]string{constants.PolicyDelegatesAnnotation: "platform, bookstore-admin"},
+			},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bookwarehouse",
+			},
+		},
+	)
+
+	testCases := []struct {
+		name                 string
+		ownership            string
+		namespace            string
+		destinationNamespace string
+		expectedAllowed      bool
+	}{
+		{
+			name:                 "authored in the destination namespace",
+			ownership:            constants.PolicyOwnershipDestinationNamespace,
+			namespace:            "bookstore",
+			destinationNamespace: "bookstore",
+			expectedAllowed:      true,
+		},
+		{
+			name:                 "authored in a namespace the destination namespace delegates to",
+			ownership:            constants.PolicyOwnershipDestinationNamespace,
+			namespace:            "bookstore-admin",
+			destinationNamespace: "bookstore",
+			expectedAllowed:      true,
+		},
+		{
+			name:                 "authored in a namespace the destination namespace does not delegate to",
+			ownership:            constants.PolicyOwnershipDestinationNamespace,
+			namespace:            "bookbuyer",
+			destinationNamespace: "bookstore",
+			expectedAllowed:      false,
+		},
+		{
+			name:                 "authored in another namespace when the destination namespace does not delegate",
+			ownership:            constants.PolicyOwnershipDestinationNamespace,
+			namespace:            "bookstore",
+			destinationNamespace: "bookwarehouse",
+			expectedAllowed:      false,
+		},
+		{
+			name:                 "destination namespace does not exist",
+			ownership:            constants.PolicyOwnershipDestinationNamespace,
+			namespace:            "bookstore",
+			destinationNamespace: "unknown",
+			expectedAllowed:      false,
+		},
+		{
+			name:                 "authored in any namespace",
+			ownership:            constants.PolicyOwnershipAnyNamespace,
+			namespace:            "bookbuyer",
+			destinationNamespace: "bookstore",
+			expectedAllowed:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetPolicyOwnership().Return(tc.ownership).Times(1)
+
+			wh := &webhookConfig{
+				kubeClient: kubeClient,
+				cfg:        mockConfigurator,
+			}
+
+			trafficTarget := smiAccess.TrafficTarget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "traffic-target",
+					Namespace: tc.namespace,
+				},
+				Spec: smiAccess.TrafficTargetSpec{
+					Destination: smiAccess.IdentityBindingSubject{
+						Kind:      "ServiceAccount",
+						Name:      "bookstore",
+						Namespace: tc.destinationNamespace,
+					},
+				},
+			}
+			raw, err := json.Marshal(trafficTarget)
+			assert.Nil(err)
+
+			resp := wh.validateTrafficTarget(&admissionv1.AdmissionRequest{
+				UID:       "11111111-2222-3333-4444-555555555555",
+				Name:      trafficTarget.Name,
+				Namespace: tc.namespace,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.Equal(tc.expectedAllowed, resp.Allowed)
+			if !tc.expectedAllowed {
+				assert.Equal(int32(http.StatusForbidden), resp.Result.Code)
+			}
+		})
+	}
+}
+
+func TestIsPolicyDelegate(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.True(isPolicyDelegate("platform", "platform"))
+	assert.True(isPolicyDelegate("platform, bookstore-admin", "bookstore-admin"))
+	assert.False(isPolicyDelegate("platform", "bookbuyer"))
+	assert.False(isPolicyDelegate("", "bookbuyer"))
+}
@@ -0,0 +1,167 @@
+package configurator
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+const (
+	// ConfigMapPath is the path on which osm-controller serves the OSM ConfigMap to the other components of the control plane
+	ConfigMapPath = "/osm-config"
+
+	// remoteConfigMapPollInterval is the interval at which the OSM ConfigMap is read from osm-controller
+	remoteConfigMapPollInterval = 5 * time.Second
+
+	// remoteConfigMapTimeout is the timeout of the requests for the OSM ConfigMap
+	remoteConfigMapTimeout = 5 * time.Second
+)
+
+// NewConfigMapHandler returns the handler serving the OSM ConfigMap watched by the given configurator
+func NewConfigMapHandler(cfg Configurator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		configMap := cfg.GetOSMConfigMap()
+		if configMap == nil {
+			http.Error(w, "OSM ConfigMap not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(configMap); err != nil {
+			log.Error().Err(err).Msg("Error writing OSM ConfigMap")
+		}
+	})
+}
+
+// RemoteClient is a configurator of the OSM ConfigMap read from osm-controller rather than from the Kubernetes API
+// server, for the components of the control plane whose config reads are authenticated with mTLS.
+type RemoteClient struct {
+	*Client
+	httpClient *http.Client
+	url        string
+}
+
+// NewRemoteConfigurator returns a configurator of the OSM ConfigMap served by osm-controller at the given address. The
+// default config values are used until Start reads the ConfigMap.
+func NewRemoteConfigurator(controllerAddr, osmNamespace, osmConfigMapName string) *RemoteClient {
+	return &RemoteClient{
+		Client: &Client{
+			cache:            cache.NewStore(cache.MetaNamespaceKeyFunc),
+			osmNamespace:     osmNamespace,
+			osmConfigMapName: osmConfigMapName,
+		},
+		url: fmt.Sprintf("https://%s%s", controllerAddr, ConfigMapPath),
+	}
+}
+
+// Start reads the OSM ConfigMap from osm-controller with the given TLS configuration, and keeps reading it until the
+// stop channel is closed. It returns once the ConfigMap is first read, retrying while osm-controller is unavailable.
+func (c *RemoteClient) Start(tlsConfig *tls.Config, stop <-chan struct{}) {
+	c.httpClient = &http.Client{
+		Timeout:   remoteConfigMapTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	ticker := time.NewTicker(remoteConfigMapPollInterval)
+	for {
+		err := c.update()
+		if err == nil {
+			break
+		}
+		log.Error().Err(err).Msgf("Error reading ConfigMap %s from %s, retrying in %s", c.getConfigMapCacheKey(), c.url, remoteConfigMapPollInterval)
+		select {
+		case <-ticker.C:
+		case <-stop:
+			ticker.Stop()
+			return
+		}
+	}
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.update(); err != nil {
+					log.Error().Err(err).Msgf("Error reading ConfigMap %s from %s", c.getConfigMapCacheKey(), c.url)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// update reads the OSM ConfigMap from osm-controller and announces its changes
+func (c *RemoteClient) update() error {
+	configMap, err := c.getRemoteConfigMap()
+	if err != nil {
+		return err
+	}
+
+	prevConfigMap := c.GetOSMConfigMap()
+	switch {
+	case configMap == nil && prevConfigMap == nil:
+		return nil
+
+	case configMap == nil:
+		if err := c.cache.Delete(prevConfigMap); err != nil {
+			return err
+		}
+		events.GetPubSubInstance().Publish(events.PubSubMessage{
+			AnnouncementType: announcements.ConfigMapDeleted,
+			OldObj:           prevConfigMap,
+		})
+
+	case prevConfigMap == nil:
+		if err := c.cache.Add(configMap); err != nil {
+			return err
+		}
+		events.GetPubSubInstance().Publish(events.PubSubMessage{
+			AnnouncementType: announcements.ConfigMapAdded,
+			NewObj:           configMap,
+		})
+
+	case configMap.ResourceVersion != prevConfigMap.ResourceVersion:
+		if err := c.cache.Update(configMap); err != nil {
+			return err
+		}
+		events.GetPubSubInstance().Publish(events.PubSubMessage{
+			AnnouncementType: announcements.ConfigMapUpdated,
+			OldObj:           prevConfigMap,
+			NewObj:           configMap,
+		})
+	}
+	return nil
+}
+
+// getRemoteConfigMap returns the OSM ConfigMap served by osm-controller, nil if it does not exist
+func (c *RemoteClient) getRemoteConfigMap() (*v1.ConfigMap, error) {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	configMap := &v1.ConfigMap{}
+	if err := json.NewDecoder(resp.Body).Decode(configMap); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding ConfigMap %s", c.getConfigMapCacheKey())
+	}
+	return configMap, nil
+}
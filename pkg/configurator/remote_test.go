@@ -0,0 +1,89 @@
+package configurator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+func TestNewConfigMapHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: osmNamespace, Name: osmConfigMapName, ResourceVersion: "1"},
+		Data:       map[string]string{egressKey: "true"},
+	}
+	c := newStaticConfigurator(osmNamespace, osmConfigMapName, configMap)
+
+	w := httptest.NewRecorder()
+	NewConfigMapHandler(c).ServeHTTP(w, httptest.NewRequest(http.MethodGet, ConfigMapPath, nil))
+	assert.Equal(http.StatusOK, w.Code)
+	served := &v1.ConfigMap{}
+	assert.Nil(json.Unmarshal(w.Body.Bytes(), served))
+	assert.Equal(configMap, served)
+
+	// The ConfigMap may not exist
+	c = &Client{cache: cache.NewStore(cache.MetaNamespaceKeyFunc), osmNamespace: osmNamespace, osmConfigMapName: osmConfigMapName}
+	w = httptest.NewRecorder()
+	NewConfigMapHandler(c).ServeHTTP(w, httptest.NewRequest(http.MethodGet, ConfigMapPath, nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestRemoteClientUpdate(t *testing.T) {
+	assert := tassert.New(t)
+
+	controllerCfg := &Client{cache: cache.NewStore(cache.MetaNamespaceKeyFunc), osmNamespace: osmNamespace, osmConfigMapName: osmConfigMapName}
+	controller := httptest.NewTLSServer(NewConfigMapHandler(controllerCfg))
+	defer controller.Close()
+
+	c := NewRemoteConfigurator(strings.TrimPrefix(controller.URL, "https://"), osmNamespace, osmConfigMapName)
+	c.httpClient = controller.Client()
+	announcementsCh := events.GetPubSubInstance().Subscribe(announcements.ConfigMapAdded, announcements.ConfigMapUpdated, announcements.ConfigMapDeleted)
+	defer events.GetPubSubInstance().Unsub(announcementsCh)
+
+	// The default config values are used while the ConfigMap does not exist
+	assert.Nil(c.update())
+	assert.Nil(c.GetOSMConfigMap())
+	assert.False(c.IsEgressEnabled())
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: osmNamespace, Name: osmConfigMapName, ResourceVersion: "1"},
+		Data:       map[string]string{egressKey: "true"},
+	}
+	assert.Nil(controllerCfg.cache.Add(configMap))
+	assert.Nil(c.update())
+	assert.True(c.IsEgressEnabled())
+	assert.Equal(announcements.ConfigMapAdded, (<-announcementsCh).(events.PubSubMessage).AnnouncementType)
+
+	// Only the changes of the ConfigMap are announced
+	assert.Nil(c.update())
+	updated := configMap.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Data[egressKey] = "false"
+	assert.Nil(controllerCfg.cache.Update(updated))
+	assert.Nil(c.update())
+	assert.False(c.IsEgressEnabled())
+	msg := (<-announcementsCh).(events.PubSubMessage)
+	assert.Equal(announcements.ConfigMapUpdated, msg.AnnouncementType)
+	assert.Equal(configMap, msg.OldObj)
+	assert.Equal(updated, msg.NewObj)
+
+	assert.Nil(controllerCfg.cache.Delete(updated))
+	assert.Nil(c.update())
+	assert.Nil(c.GetOSMConfigMap())
+	assert.Equal(announcements.ConfigMapDeleted, (<-announcementsCh).(events.PubSubMessage).AnnouncementType)
+
+	// Errors of osm-controller are reported
+	controller.Close()
+	assert.NotNil(c.update())
+}
@@ -0,0 +1,71 @@
+package configurator
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// runtimeDefaultProfile is the profile of the container runtime
+	runtimeDefaultProfile = "runtime/default"
+
+	// unconfinedProfile runs the containers without a profile
+	unconfinedProfile = "unconfined"
+
+	// localhostProfilePrefix is the prefix of the profiles loaded on the nodes
+	localhostProfilePrefix = "localhost/"
+)
+
+// parseSeccompProfile returns the seccomp profile specified as runtime/default, unconfined, or localhost/<path> with
+// the path of a profile relative to the seccomp profile root of the kubelet
+func parseSeccompProfile(value string) (*corev1.SeccompProfile, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == runtimeDefaultProfile:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}, nil
+
+	case value == unconfinedProfile:
+		return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}, nil
+
+	case strings.HasPrefix(value, localhostProfilePrefix) && len(value) > len(localhostProfilePrefix):
+		localhostProfile := strings.TrimPrefix(value, localhostProfilePrefix)
+		return &corev1.SeccompProfile{
+			Type:             corev1.SeccompProfileTypeLocalhost,
+			LocalhostProfile: &localhostProfile,
+		}, nil
+
+	default:
+		return nil, errors.Errorf("Invalid seccomp profile %q, must be %s, %s or %s<path>", value, runtimeDefaultProfile, unconfinedProfile, localhostProfilePrefix)
+	}
+}
+
+// parseAppArmorProfile returns the AppArmor profile specified as runtime/default, unconfined, or localhost/<name> with
+// the name of a profile loaded on the nodes
+func parseAppArmorProfile(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == runtimeDefaultProfile || value == unconfinedProfile || (strings.HasPrefix(value, localhostProfilePrefix) && len(value) > len(localhostProfilePrefix)) {
+		return value, nil
+	}
+	return "", errors.Errorf("Invalid AppArmor profile %q, must be %s, %s or %s<name>", value, runtimeDefaultProfile, unconfinedProfile, localhostProfilePrefix)
+}
+
+// parseSELinuxOptions returns the SELinux options specified as a user:role:type:level SELinux context, whose empty
+// parts are not set. The level, such as s0:c123,c456, may contain colons.
+func parseSELinuxOptions(value string) (*corev1.SELinuxOptions, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), ":", 4)
+	if len(parts) != 4 {
+		return nil, errors.Errorf("Invalid SELinux options %q, must be a user:role:type:level SELinux context", value)
+	}
+	options := &corev1.SELinuxOptions{
+		User:  parts[0],
+		Role:  parts[1],
+		Type:  parts[2],
+		Level: parts[3],
+	}
+	if *options == (corev1.SELinuxOptions{}) {
+		return nil, errors.Errorf("Invalid SELinux options %q, at least one part of the SELinux context must be set", value)
+	}
+	return options, nil
+}
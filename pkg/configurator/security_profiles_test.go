@@ -0,0 +1,123 @@
+package configurator
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseSeccompProfile(t *testing.T) {
+	localhostProfile := "profiles/envoy.json"
+
+	testCases := []struct {
+		value           string
+		expectedProfile *corev1.SeccompProfile
+		expectErr       bool
+	}{
+		{
+			value:           "runtime/default",
+			expectedProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		{
+			value:           " unconfined ",
+			expectedProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+		},
+		{
+			value:           "localhost/profiles/envoy.json",
+			expectedProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile},
+		},
+		{
+			value:     "localhost/",
+			expectErr: true,
+		},
+		{
+			value:     "RuntimeDefault",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			profile, err := parseSeccompProfile(tc.value)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedProfile, profile)
+		})
+	}
+}
+
+func TestParseAppArmorProfile(t *testing.T) {
+	testCases := []struct {
+		value           string
+		expectedProfile string
+		expectErr       bool
+	}{
+		{
+			value:           "runtime/default",
+			expectedProfile: "runtime/default",
+		},
+		{
+			value:           "unconfined",
+			expectedProfile: "unconfined",
+		},
+		{
+			value:           "localhost/k8s-envoy",
+			expectedProfile: "localhost/k8s-envoy",
+		},
+		{
+			value:     "k8s-envoy",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			profile, err := parseAppArmorProfile(tc.value)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedProfile, profile)
+		})
+	}
+}
+
+func TestParseSELinuxOptions(t *testing.T) {
+	testCases := []struct {
+		value           string
+		expectedOptions *corev1.SELinuxOptions
+		expectErr       bool
+	}{
+		{
+			value:           "system_u:system_r:container_t:s0",
+			expectedOptions: &corev1.SELinuxOptions{User: "system_u", Role: "system_r", Type: "container_t", Level: "s0"},
+		},
+		{
+			value:           "::spc_t:",
+			expectedOptions: &corev1.SELinuxOptions{Type: "spc_t"},
+		},
+		{
+			value:           ":::s0:c123,c456",
+			expectedOptions: &corev1.SELinuxOptions{Level: "s0:c123,c456"},
+		},
+		{
+			value:     ":::",
+			expectErr: true,
+		},
+		{
+			value:     "spc_t",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			options, err := parseSELinuxOptions(tc.value)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedOptions, options)
+		})
+	}
+}
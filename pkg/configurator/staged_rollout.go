@@ -0,0 +1,275 @@
+package configurator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+const (
+	// defaultStagedRolloutSoakPeriod is the default time a change is soaked by the canary proxies before it is promoted
+	defaultStagedRolloutSoakPeriod = 10 * time.Minute
+
+	// defaultStagedRolloutMax5xxPercentage is the default percentage of 5xx responses of the canary proxies above which a
+	// change is not promoted
+	defaultStagedRolloutMax5xxPercentage = 1.0
+
+	// stagedRolloutCheckInterval is the interval at which the staged rollout in progress is checked for promotion
+	stagedRolloutCheckInterval = 30 * time.Second
+)
+
+// StagedRollout is a change of the OSM ConfigMap rolled out to a subset of the proxies, the canary proxies, before it
+// is promoted to all the proxies
+type StagedRollout struct {
+	// Namespaces are the namespaces whose proxies are canary proxies
+	Namespaces map[string]bool
+
+	// Percentage is the percentage of the proxies of the other namespaces that are canary proxies
+	Percentage uint32
+
+	// SoakPeriod is the time the change is soaked by the canary proxies before it is promoted
+	SoakPeriod time.Duration
+
+	// Max5xxPercentage is the percentage of 5xx responses of the canary proxies above which the change is not promoted
+	Max5xxPercentage float64
+
+	// StartedAt is the time the staged rollout started
+	StartedAt time.Time
+
+	// Stable is the configurator of the OSM ConfigMap preceding the change, applied to the proxies that are not canary
+	// proxies
+	Stable Configurator
+
+	// stableConfigMap is the OSM ConfigMap preceding the change
+	stableConfigMap *v1.ConfigMap
+
+	// rejections is the number of xDS responses rejected by the canary proxies since the staged rollout started,
+	// accessed atomically
+	rejections uint64
+
+	// halted is set once the change is found unhealthy at the end of the soak period
+	halted bool
+}
+
+// IsCanary returns true if the proxy of the pod with the given namespace and name is a canary proxy, that applies the
+// change before it is promoted
+func (r *StagedRollout) IsCanary(namespace string, podName string) bool {
+	if r.Namespaces[namespace] {
+		return true
+	}
+	if r.Percentage == 0 {
+		return false
+	}
+	// Proxies are selected consistently by the hash of their pod, so that a proxy remains a canary proxy across updates
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(fmt.Sprintf("%s/%s", namespace, podName)))
+	return hash.Sum32()%100 < r.Percentage
+}
+
+// RecordRejection records that a canary proxy rejected an xDS response. A change rejected by canary proxies is not
+// promoted.
+func (r *StagedRollout) RecordRejection() {
+	atomic.AddUint64(&r.rejections, 1)
+}
+
+// GetStagedRollout returns the staged rollout of the change of the OSM ConfigMap in progress, or nil if no change is
+// being rolled out in stages
+func (c *Client) GetStagedRollout() *StagedRollout {
+	c.stagedRolloutLock.RLock()
+	defer c.stagedRolloutLock.RUnlock()
+	return c.stagedRollout
+}
+
+// updateStagedRollout starts, updates, promotes or cancels the staged rollout of the changes of the OSM ConfigMap upon
+// its update from prevConfigMap to newConfigMap. It returns true if the proxies the change applies to changed.
+func (c *Client) updateStagedRollout(prevConfigMap *v1.ConfigMap, newConfigMap *v1.ConfigMap) bool {
+	c.stagedRolloutLock.Lock()
+	defer c.stagedRolloutLock.Unlock()
+
+	inProgress := c.stagedRollout != nil
+	stableConfigMap := prevConfigMap
+	if inProgress {
+		stableConfigMap = c.stagedRollout.stableConfigMap
+	}
+
+	if reflect.DeepEqual(stableConfigMap.Data, newConfigMap.Data) {
+		if inProgress {
+			log.Info().Msgf("Staged rollout of the change of ConfigMap %s cancelled: the change was reverted", c.getConfigMapCacheKey())
+		}
+		c.stagedRollout = nil
+		return inProgress
+	}
+
+	rollout := c.newStagedRollout(newConfigMap)
+	if rollout == nil {
+		if inProgress {
+			log.Info().Msgf("Staged rollout of the change of ConfigMap %s promoted: the staged rollout annotations were removed", c.getConfigMapCacheKey())
+		}
+		c.stagedRollout = nil
+		return inProgress
+	}
+
+	rollout.stableConfigMap = stableConfigMap
+	rollout.Stable = newStaticConfigurator(c.osmNamespace, c.osmConfigMapName, stableConfigMap)
+	c.stagedRollout = rollout
+	log.Info().Msgf("Staged rollout of the change of ConfigMap %s started: namespaces %v and %d%% of the proxies first, promoted after %s",
+		c.getConfigMapCacheKey(), rollout.Namespaces, rollout.Percentage, rollout.SoakPeriod)
+	return true
+}
+
+// newStagedRollout returns the staged rollout of the changes of the given OSM ConfigMap set by its staged rollout
+// annotations, or nil if its changes are not rolled out in stages
+func (c *Client) newStagedRollout(configMap *v1.ConfigMap) *StagedRollout {
+	rollout := &StagedRollout{
+		Namespaces:       make(map[string]bool),
+		SoakPeriod:       defaultStagedRolloutSoakPeriod,
+		Max5xxPercentage: defaultStagedRolloutMax5xxPercentage,
+		StartedAt:        time.Now(),
+	}
+	annotations := configMap.Annotations
+
+	if namespaces, ok := annotations[constants.StagedRolloutNamespacesAnnotation]; ok {
+		for _, namespace := range strings.Split(namespaces, ",") {
+			if namespace = strings.TrimSpace(namespace); namespace != "" {
+				rollout.Namespaces[namespace] = true
+			}
+		}
+	}
+
+	if selector, ok := annotations[constants.StagedRolloutNamespaceSelectorAnnotation]; ok {
+		namespaces, err := c.kubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{LabelSelector: strings.TrimSpace(selector)})
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on ConfigMap %s: %s", constants.StagedRolloutNamespaceSelectorAnnotation, c.getConfigMapCacheKey(), selector)
+		} else {
+			for _, namespace := range namespaces.Items {
+				rollout.Namespaces[namespace.Name] = true
+			}
+		}
+	}
+
+	if percentage, ok := annotations[constants.StagedRolloutPercentageAnnotation]; ok {
+		parsed, err := strconv.ParseUint(strings.TrimSpace(percentage), 10, 32)
+		if err == nil && parsed > 100 {
+			err = errInvalidPercentage
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on ConfigMap %s: %s", constants.StagedRolloutPercentageAnnotation, c.getConfigMapCacheKey(), percentage)
+		} else {
+			rollout.Percentage = uint32(parsed)
+		}
+	}
+
+	if soakPeriod, ok := annotations[constants.StagedRolloutSoakPeriodAnnotation]; ok {
+		parsed, err := time.ParseDuration(strings.TrimSpace(soakPeriod))
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on ConfigMap %s: %s", constants.StagedRolloutSoakPeriodAnnotation, c.getConfigMapCacheKey(), soakPeriod)
+		} else {
+			rollout.SoakPeriod = parsed
+		}
+	}
+
+	if max5xxPercentage, ok := annotations[constants.StagedRolloutMax5xxPercentageAnnotation]; ok {
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(max5xxPercentage), 64)
+		if err == nil && (parsed < 0 || parsed > 100) {
+			err = errInvalidPercentage
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid annotation value for key %q on ConfigMap %s: %s", constants.StagedRolloutMax5xxPercentageAnnotation, c.getConfigMapCacheKey(), max5xxPercentage)
+		} else {
+			rollout.Max5xxPercentage = parsed
+		}
+	}
+
+	if len(rollout.Namespaces) == 0 && rollout.Percentage == 0 {
+		return nil
+	}
+	return rollout
+}
+
+// runStagedRolloutPromoter periodically promotes the change of the staged rollout in progress to all the proxies once
+// it was soaked by the canary proxies without being found unhealthy
+func (c *Client) runStagedRolloutPromoter(stop <-chan struct{}) {
+	ticker := time.NewTicker(stagedRolloutCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.promoteStagedRollout()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// promoteStagedRollout promotes the change of the staged rollout in progress to all the proxies if its soak period is
+// over and the canary proxies are healthy. A change found unhealthy is halted: it remains applied to the canary proxies
+// only, until it is reverted or its staged rollout annotations are removed.
+func (c *Client) promoteStagedRollout() {
+	c.stagedRolloutLock.RLock()
+	rollout := c.stagedRollout
+	if rollout == nil || rollout.halted || time.Since(rollout.StartedAt) < rollout.SoakPeriod {
+		c.stagedRolloutLock.RUnlock()
+		return
+	}
+	rejections := atomic.LoadUint64(&rollout.rejections)
+	c.stagedRolloutLock.RUnlock()
+
+	unhealthyReason := ""
+	if rejections > 0 {
+		unhealthyReason = fmt.Sprintf("%d xDS responses were rejected by the canary proxies", rejections)
+	} else if metricsURL := c.GetPolicyUsageMetricsURL(); metricsURL != "" {
+		percentage, err := getCanary5xxPercentage(metricsURL, rollout)
+		if err != nil {
+			// The health of the canary proxies is unknown, the promotion is retried at the next check
+			log.Error().Err(err).Msgf("Error querying the 5xx responses of the canary proxies of the staged rollout of ConfigMap %s", c.getConfigMapCacheKey())
+			return
+		}
+		if percentage > rollout.Max5xxPercentage {
+			unhealthyReason = fmt.Sprintf("%.2f%% of the responses of the canary proxies were 5xx, above %.2f%%", percentage, rollout.Max5xxPercentage)
+		}
+	}
+
+	c.stagedRolloutLock.Lock()
+	defer c.stagedRolloutLock.Unlock()
+	if c.stagedRollout != rollout {
+		// The staged rollout was updated in the meantime
+		return
+	}
+
+	if unhealthyReason != "" {
+		rollout.halted = true
+		log.Error().Msgf("Staged rollout of the change of ConfigMap %s halted: %s; revert the change, or remove the staged rollout annotations to promote it",
+			c.getConfigMapCacheKey(), unhealthyReason)
+		return
+	}
+
+	c.stagedRollout = nil
+	log.Info().Msgf("Staged rollout of the change of ConfigMap %s promoted to all the proxies after %s", c.getConfigMapCacheKey(), rollout.SoakPeriod)
+	events.GetPubSubInstance().Publish(events.PubSubMessage{
+		AnnouncementType: announcements.ScheduleProxyBroadcast,
+	})
+}
+
+// newStaticConfigurator returns a configurator of the given OSM ConfigMap, not watching its changes
+func newStaticConfigurator(osmNamespace, osmConfigMapName string, configMap *v1.ConfigMap) *Client {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	_ = store.Add(configMap)
+	return &Client{
+		cache:            store,
+		osmNamespace:     osmNamespace,
+		osmConfigMapName: osmConfigMapName,
+	}
+}
@@ -0,0 +1,54 @@
+package configurator
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/promquery"
+)
+
+// canaryResponsesMetric is the metric of the sidecar proxies counting the responses of their upstream clusters per
+// response code class
+const canaryResponsesMetric = "envoy_cluster_upstream_rq_xx"
+
+// prometheusQueryTimeout is the timeout of the queries of the responses of the canary proxies to Prometheus
+const prometheusQueryTimeout = 30 * time.Second
+
+// getCanary5xxPercentage returns the percentage of 5xx responses among the responses of the upstream clusters of the
+// canary proxies of the given staged rollout since it started, as scraped by the Prometheus server at the given URL
+func getCanary5xxPercentage(metricsURL string, rollout *StagedRollout) (float64, error) {
+	window := int64(time.Since(rollout.StartedAt).Seconds())
+	client := &http.Client{Timeout: prometheusQueryTimeout}
+
+	total, err := getCanaryResponseCount(client, metricsURL, rollout, fmt.Sprintf(`%s[%ds]`, canaryResponsesMetric, window))
+	if err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	errors5xx, err := getCanaryResponseCount(client, metricsURL, rollout, fmt.Sprintf(`%s{envoy_response_code_class="5"}[%ds]`, canaryResponsesMetric, window))
+	if err != nil {
+		return 0, err
+	}
+	return 100 * errors5xx / total, nil
+}
+
+// getCanaryResponseCount returns the increase of the given range vector selector over the pods of the canary proxies of
+// the given staged rollout
+func getCanaryResponseCount(client *http.Client, metricsURL string, rollout *StagedRollout, selector string) (float64, error) {
+	query := fmt.Sprintf(`sum by (source_namespace, source_pod_name) (increase(%s))`, selector)
+	samples, err := promquery.Query(client, metricsURL, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var count float64
+	for _, sample := range samples {
+		if rollout.IsCanary(sample.Labels["source_namespace"], sample.Labels["source_pod_name"]) {
+			count += sample.Value
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,266 @@
+package configurator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func newStagedRolloutConfigMap(data map[string]string, annotations map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   osmNamespace,
+			Name:        osmConfigMapName,
+			Annotations: annotations,
+		},
+		Data: data,
+	}
+}
+
+func TestNewStagedRollout(t *testing.T) {
+	kubeClient := testclient.NewSimpleClientset()
+	for name, labels := range map[string]map[string]string{"canary-1": {"stage": "canary"}, "canary-2": {"stage": "canary"}, "prod": {"stage": "prod"}} {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		_, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+		tassert.Nil(t, err)
+	}
+	c := &Client{osmNamespace: osmNamespace, osmConfigMapName: osmConfigMapName, kubeClient: kubeClient}
+
+	testCases := []struct {
+		name                     string
+		annotations              map[string]string
+		expectedNil              bool
+		expectedNamespaces       map[string]bool
+		expectedPercentage       uint32
+		expectedSoakPeriod       time.Duration
+		expectedMax5xxPercentage float64
+	}{
+		{
+			name:        "no staged rollout annotation",
+			annotations: nil,
+			expectedNil: true,
+		},
+		{
+			name:                     "namespaces with defaults",
+			annotations:              map[string]string{constants.StagedRolloutNamespacesAnnotation: "bookstore, bookbuyer"},
+			expectedNamespaces:       map[string]bool{"bookstore": true, "bookbuyer": true},
+			expectedSoakPeriod:       defaultStagedRolloutSoakPeriod,
+			expectedMax5xxPercentage: defaultStagedRolloutMax5xxPercentage,
+		},
+		{
+			name: "namespace selector and percentage",
+			annotations: map[string]string{
+				constants.StagedRolloutNamespaceSelectorAnnotation: "stage=canary",
+				constants.StagedRolloutPercentageAnnotation:        "10",
+				constants.StagedRolloutSoakPeriodAnnotation:        "1h",
+				constants.StagedRolloutMax5xxPercentageAnnotation:  "0.5",
+			},
+			expectedNamespaces:       map[string]bool{"canary-1": true, "canary-2": true},
+			expectedPercentage:       10,
+			expectedSoakPeriod:       time.Hour,
+			expectedMax5xxPercentage: 0.5,
+		},
+		{
+			name: "invalid values",
+			annotations: map[string]string{
+				constants.StagedRolloutNamespacesAnnotation:       "bookstore",
+				constants.StagedRolloutPercentageAnnotation:       "150",
+				constants.StagedRolloutSoakPeriodAnnotation:       "forever",
+				constants.StagedRolloutMax5xxPercentageAnnotation: "-1",
+			},
+			expectedNamespaces:       map[string]bool{"bookstore": true},
+			expectedSoakPeriod:       defaultStagedRolloutSoakPeriod,
+			expectedMax5xxPercentage: defaultStagedRolloutMax5xxPercentage,
+		},
+		{
+			name:        "invalid percentage only",
+			annotations: map[string]string{constants.StagedRolloutPercentageAnnotation: "half"},
+			expectedNil: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			rollout := c.newStagedRollout(newStagedRolloutConfigMap(nil, tc.annotations))
+			if tc.expectedNil {
+				assert.Nil(rollout)
+				return
+			}
+			assert.NotNil(rollout)
+			assert.Equal(tc.expectedNamespaces, rollout.Namespaces)
+			assert.Equal(tc.expectedPercentage, rollout.Percentage)
+			assert.Equal(tc.expectedSoakPeriod, rollout.SoakPeriod)
+			assert.Equal(tc.expectedMax5xxPercentage, rollout.Max5xxPercentage)
+		})
+	}
+}
+
+func TestIsCanary(t *testing.T) {
+	assert := tassert.New(t)
+
+	rollout := &StagedRollout{Namespaces: map[string]bool{"bookstore": true}}
+	assert.True(rollout.IsCanary("bookstore", "bookstore-v1-1234"))
+	assert.False(rollout.IsCanary("bookbuyer", "bookbuyer-1234"))
+
+	// The canary proxies selected by percentage are selected consistently, in roughly the given proportion
+	rollout.Percentage = 20
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		podName := fmt.Sprintf("bookbuyer-%d", i)
+		isCanary := rollout.IsCanary("bookbuyer", podName)
+		assert.Equal(isCanary, rollout.IsCanary("bookbuyer", podName))
+		if isCanary {
+			canaries++
+		}
+	}
+	assert.InDelta(200, canaries, 50)
+}
+
+func TestUpdateStagedRollout(t *testing.T) {
+	assert := tassert.New(t)
+	c := &Client{osmNamespace: osmNamespace, osmConfigMapName: osmConfigMapName, kubeClient: testclient.NewSimpleClientset()}
+	staged := map[string]string{constants.StagedRolloutNamespacesAnnotation: "bookstore"}
+
+	stable := newStagedRolloutConfigMap(map[string]string{egressKey: "false"}, staged)
+	changed := newStagedRolloutConfigMap(map[string]string{egressKey: "true"}, staged)
+	changedAgain := newStagedRolloutConfigMap(map[string]string{egressKey: "true", tracingEnableKey: "true"}, staged)
+
+	// A change without staged rollout annotations applies to all the proxies
+	assert.False(c.updateStagedRollout(newStagedRolloutConfigMap(stable.Data, nil), newStagedRolloutConfigMap(changed.Data, nil)))
+	assert.Nil(c.GetStagedRollout())
+
+	// A change with staged rollout annotations is applied to the canary proxies, the other proxies keep the stable config
+	assert.True(c.updateStagedRollout(stable, changed))
+	rollout := c.GetStagedRollout()
+	assert.NotNil(rollout)
+	assert.False(rollout.Stable.IsEgressEnabled())
+
+	// A further change keeps the config preceding the staged rollout as the stable config
+	assert.True(c.updateStagedRollout(changed, changedAgain))
+	rollout = c.GetStagedRollout()
+	assert.NotNil(rollout)
+	assert.False(rollout.Stable.IsEgressEnabled())
+	assert.False(rollout.Stable.IsTracingEnabled())
+
+	// Reverting the change cancels the staged rollout
+	assert.True(c.updateStagedRollout(changedAgain, stable))
+	assert.Nil(c.GetStagedRollout())
+
+	// Removing the staged rollout annotations promotes the change
+	assert.True(c.updateStagedRollout(stable, changed))
+	assert.True(c.updateStagedRollout(changed, newStagedRolloutConfigMap(changed.Data, nil)))
+	assert.Nil(c.GetStagedRollout())
+}
+
+func TestPromoteStagedRollout(t *testing.T) {
+	testCases := []struct {
+		name             string
+		soaked           bool
+		rejections       uint64
+		prometheusBody   func(query string) string
+		expectedPromoted bool
+		expectedHalted   bool
+	}{
+		{
+			name:             "soak period not over",
+			soaked:           false,
+			expectedPromoted: false,
+		},
+		{
+			name:             "healthy canary proxies",
+			soaked:           true,
+			expectedPromoted: true,
+		},
+		{
+			name:             "rejected by canary proxies",
+			soaked:           true,
+			rejections:       1,
+			expectedPromoted: false,
+			expectedHalted:   true,
+		},
+		{
+			name:   "5xx responses of canary proxies above the maximum",
+			soaked: true,
+			prometheusBody: func(query string) string {
+				count := "100"
+				if strings.Contains(query, `envoy_response_code_class="5"`) {
+					count = "2"
+				}
+				// Only the responses of the canary proxies are counted
+				return `{"status":"success","data":{"resultType":"vector","result":[` +
+					`{"metric":{"source_namespace":"bookstore","source_pod_name":"bookstore-v1"},"value":[1614556800,"` + count + `"]},` +
+					`{"metric":{"source_namespace":"bookbuyer","source_pod_name":"bookbuyer"},"value":[1614556800,"1000"]}]}}`
+			},
+			expectedPromoted: false,
+			expectedHalted:   true,
+		},
+		{
+			name:   "5xx responses of canary proxies below the maximum",
+			soaked: true,
+			prometheusBody: func(query string) string {
+				count := "1000"
+				if strings.Contains(query, `envoy_response_code_class="5"`) {
+					count = "5"
+				}
+				return `{"status":"success","data":{"resultType":"vector","result":[` +
+					`{"metric":{"source_namespace":"bookstore","source_pod_name":"bookstore-v1"},"value":[1614556800,"` + count + `"]}]}}`
+			},
+			expectedPromoted: true,
+		},
+		{
+			name:   "5xx responses unknown",
+			soaked: true,
+			prometheusBody: func(query string) string {
+				return `{"status":"error","errorType":"bad_data","error":"invalid query"}`
+			},
+			expectedPromoted: false,
+			expectedHalted:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			data := map[string]string{}
+			if tc.prometheusBody != nil {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal("/api/v1/query", r.URL.Path)
+					_, _ = fmt.Fprint(w, tc.prometheusBody(r.URL.Query().Get("query")))
+				}))
+				defer server.Close()
+				data[policyUsageMetricsURLKey] = server.URL
+			}
+
+			c := newStaticConfigurator(osmNamespace, osmConfigMapName, newStagedRolloutConfigMap(data, nil))
+			rollout := &StagedRollout{
+				Namespaces:       map[string]bool{"bookstore": true},
+				SoakPeriod:       time.Hour,
+				Max5xxPercentage: defaultStagedRolloutMax5xxPercentage,
+				StartedAt:        time.Now(),
+				rejections:       tc.rejections,
+			}
+			if tc.soaked {
+				rollout.StartedAt = time.Now().Add(-2 * time.Hour)
+			}
+			c.stagedRollout = rollout
+
+			c.promoteStagedRollout()
+			assert.Equal(tc.expectedPromoted, c.GetStagedRollout() == nil)
+			assert.Equal(tc.expectedHalted, rollout.halted)
+		})
+	}
+}
@@ -2,8 +2,11 @@
 package configurator
 
 import (
+	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/openservicemesh/osm/pkg/logger"
@@ -20,6 +23,11 @@ type Client struct {
 	informer         cache.SharedIndexInformer
 	cache            cache.Store
 	cacheSynced      chan interface{}
+	kubeClient       kubernetes.Interface
+
+	// stagedRollout is the staged rollout of the change of the ConfigMap in progress, nil if none is in progress
+	stagedRollout     *StagedRollout
+	stagedRolloutLock sync.RWMutex
 }
 
 // Configurator is the controller interface for K8s namespaces
@@ -30,6 +38,9 @@ type Configurator interface {
 	// GetConfigMap returns the ConfigMap in pretty JSON (human readable)
 	GetConfigMap() ([]byte, error)
 
+	// GetOSMConfigMap returns the OSM ConfigMap, nil if it does not exist
+	GetOSMConfigMap() *corev1.ConfigMap
+
 	// IsPermissiveTrafficPolicyMode determines whether we are in "allow-all" mode or SMI policy (block by default) mode
 	IsPermissiveTrafficPolicyMode() bool
 
@@ -57,6 +68,14 @@ type Configurator interface {
 	// UseHTTPSIngress determines whether protocol used for traffic from ingress to backend pods should be HTTPS.
 	UseHTTPSIngress() bool
 
+	// GetIngressClass returns the class of the ingress resources ingress policies are programmed for, or an empty string
+	// to program ingress policies for all ingress resources
+	GetIngressClass() string
+
+	// GetImplementationSpecificPathMatch returns how the ImplementationSpecific paths of ingress resources are matched,
+	// one of the constants.ImplementationSpecificPathMatch* values
+	GetImplementationSpecificPathMatch() string
+
 	// GetEnvoyLogLevel returns the envoy log level
 	GetEnvoyLogLevel() string
 
@@ -72,4 +91,142 @@ type Configurator interface {
 	// GetConfigResyncInterval returns the duration for resync interval.
 	// If error or non-parsable value, returns 0 duration
 	GetConfigResyncInterval() time.Duration
+
+	// GetMaxProxyConfigSize returns the maximum total size in bytes of the CDS, LDS and RDS responses sent to a proxy
+	// under memory pressure.
+	// A value of 0 indicates there is no limit.
+	GetMaxProxyConfigSize() int64
+
+	// GetSidecarImageDigest returns the digest the injected sidecar image must be pinned to.
+	// An empty value indicates the sidecar image is not pinned.
+	GetSidecarImageDigest() string
+
+	// GetSidecarImageCosignPublicKey returns the PEM encoded public key the cosign signature of the injected sidecar image must be verified with.
+	// An empty value indicates the signature of the sidecar image is not verified.
+	GetSidecarImageCosignPublicKey() string
+
+	// GetUnmeshedPodPolicy returns the policy applied to pods excluded from the mesh in namespaces enabled for sidecar injection,
+	// one of constants.UnmeshedPodPolicyAllow, constants.UnmeshedPodPolicyAudit or constants.UnmeshedPodPolicyDeny
+	GetUnmeshedPodPolicy() string
+
+	// GetExcludedNamespaces returns the namespaces excluded from the mesh regardless of their labels.
+	// A name ending with '*' excludes all namespaces with the given prefix.
+	GetExcludedNamespaces() []string
+
+	// IsExcludedNamespace returns true if the given namespace is excluded from the mesh regardless of its labels
+	IsExcludedNamespace(namespace string) bool
+
+	// GetMaxMonitoredNamespaces returns the maximum number of namespaces admitted to the mesh.
+	// A value of 0 indicates there is no limit.
+	GetMaxMonitoredNamespaces() int
+
+	// GetMaxServices returns the number of services in the mesh beyond which no new namespace is admitted to the mesh.
+	// A value of 0 indicates there is no limit.
+	GetMaxServices() int
+
+	// GetMaxProxies returns the number of proxies in the mesh beyond which no new namespace is admitted to the mesh.
+	// A value of 0 indicates there is no limit.
+	GetMaxProxies() int
+
+	// GetPolicyOwnership returns which namespaces may author SMI TrafficTargets for a destination,
+	// one of constants.PolicyOwnershipDestinationNamespace or constants.PolicyOwnershipAnyNamespace
+	GetPolicyOwnership() string
+
+	// GetWAFModuleURL returns the URL the WAF WASM module is fetched from by sidecar proxies.
+	// An empty value indicates the WAF is disabled for all services.
+	GetWAFModuleURL() string
+
+	// GetWAFModuleSHA256 returns the hex encoded SHA256 checksum the fetched WAF WASM module must match
+	GetWAFModuleSHA256() string
+
+	// GetForwardClientCertDetails returns the fields of the verified client certificate forwarded to applications in the
+	// x-forwarded-client-cert header, among the constants.ClientCertField* values. An empty list indicates the header
+	// is removed from requests instead.
+	GetForwardClientCertDetails() []string
+
+	// GetXFFNumTrustedHops returns the number of proxy hops in front of sidecar proxies, whose addresses in the
+	// x-forwarded-for header are trusted to determine the original client address
+	GetXFFNumTrustedHops() uint32
+
+	// UseRemoteAddress returns whether sidecar proxies determine the client address from the remote address of the
+	// connection instead of the x-forwarded-for header
+	UseRemoteAddress() bool
+
+	// SkipXFFAppend returns whether sidecar proxies skip appending the remote address of the connection to the
+	// x-forwarded-for header
+	SkipXFFAppend() bool
+
+	// IsRBACDenyReportingEnabled returns whether sidecar proxies report the requests denied by RBAC policies to the controller
+	IsRBACDenyReportingEnabled() bool
+
+	// IsEgressMetricsEnabled returns whether sidecar proxies report their egress traffic to the controller
+	IsEgressMetricsEnabled() bool
+
+	// IsEgressAuditModeEnabled returns whether the egress connections not allowed by Egress policies are reported rather than denied
+	IsEgressAuditModeEnabled() bool
+
+	// GetPolicyUsageMetricsURL returns the URL of the Prometheus server queried for the traffic matched by SMI policies
+	GetPolicyUsageMetricsURL() string
+
+	// GetUnusedPolicyWindow returns the window over which an SMI policy without traffic is reported as unused
+	GetUnusedPolicyWindow() time.Duration
+
+	// GetTrafficTargetShadowDuration returns how long new or changed SMI TrafficTargets are evaluated in shadow mode
+	// before being enforced
+	GetTrafficTargetShadowDuration() time.Duration
+
+	// IsPolicyRecorderEnabled returns whether the requests observed in permissive traffic policy mode are recorded as SMI policies
+	IsPolicyRecorderEnabled() bool
+
+	// GetIntrospectionAllowedClients returns the common names of the client certificates allowed to call the introspection
+	// API of the controller
+	GetIntrospectionAllowedClients() []string
+
+	// GetLifecycleWebhookURLs returns the URLs the mesh lifecycle events are posted to
+	GetLifecycleWebhookURLs() []string
+
+	// GetLifecycleWebhookEvents returns the types of the mesh lifecycle events posted to the lifecycle webhooks, all types
+	// if empty
+	GetLifecycleWebhookEvents() []string
+
+	// GetServiceCertRenewBefore returns how long before their expiration service certificates are rotated
+	GetServiceCertRenewBefore() time.Duration
+
+	// GetServiceCertRotationJitter returns the maximum delay added to the early rotation of service certificates
+	GetServiceCertRotationJitter() time.Duration
+
+	// IsTrustBundlePublished returns whether the trust bundle of the mesh is published to the monitored namespaces
+	IsTrustBundlePublished() bool
+
+	// IsControlPlaneMTLSEnabled returns whether the servers of the control plane require mTLS with control plane identities
+	IsControlPlaneMTLSEnabled() bool
+
+	// IsAdaptiveConcurrencyEnabled returns whether sidecar proxies shed the load of overloaded services with adaptive
+	// concurrency limits, unless overridden for a service
+	IsAdaptiveConcurrencyEnabled() bool
+
+	// GetAdaptiveConcurrencyMaxLimit returns the maximum concurrency limit allowed by adaptive concurrency limits,
+	// unless overridden for a service
+	GetAdaptiveConcurrencyMaxLimit() uint32
+
+	// GetProxyUID returns the user ID the injected sidecar proxies run as
+	GetProxyUID() int64
+
+	// GetProxyGID returns the group ID the injected sidecar proxies run as
+	GetProxyGID() int64
+
+	// GetProxySeccompProfile returns the seccomp profile of the containers injected in the pods of the mesh
+	GetProxySeccompProfile() *corev1.SeccompProfile
+
+	// GetProxyAppArmorProfile returns the AppArmor profile of the containers injected in the pods of the mesh, or an
+	// empty string if the containers run with the default profile of the node
+	GetProxyAppArmorProfile() string
+
+	// GetProxySELinuxOptions returns the SELinux options of the containers injected in the pods of the mesh, or nil if
+	// the containers run with the SELinux context assigned by the container runtime
+	GetProxySELinuxOptions() *corev1.SELinuxOptions
+
+	// GetStagedRollout returns the staged rollout of the change of the OSM ConfigMap in progress, or nil if no change is
+	// being rolled out in stages
+	GetStagedRollout() *StagedRollout
 }
@@ -2,11 +2,15 @@ package configurator
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -14,14 +18,19 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/openservicemesh/osm/pkg/admissionpolicy"
+	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
 	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
@@ -30,11 +39,26 @@ var (
 	deserializer = codecs.UniversalDeserializer()
 
 	// boolFields are the fields in osm-config that take in a boolean
-	boolFields = []string{"egress", "enable_debug_server", "permissive_traffic_policy_mode", "prometheus_scraping", "tracing_enable", "use_https_ingress", "enable_privileged_init_container"}
+	boolFields = []string{"egress", "enable_debug_server", "permissive_traffic_policy_mode", "prometheus_scraping", "tracing_enable", "use_https_ingress", "enable_privileged_init_container", "use_remote_address", "skip_xff_append", "rbac_deny_reporting", "policy_recorder", "publish_trust_bundle", "control_plane_mtls", "adaptive_concurrency", "egress_metrics", "egress_audit_mode"}
 
 	// ValidEnvoyLogLevels is a list of envoy log levels
 	ValidEnvoyLogLevels = []string{"trace", "debug", "info", "warning", "warn", "error", "critical", "off"}
 
+	// ValidUnmeshedPodPolicies is a list of unmeshed pod policies
+	ValidUnmeshedPodPolicies = []string{constants.UnmeshedPodPolicyAllow, constants.UnmeshedPodPolicyAudit, constants.UnmeshedPodPolicyDeny}
+
+	// ValidPolicyOwnerships is a list of policy ownerships
+	ValidPolicyOwnerships = []string{constants.PolicyOwnershipDestinationNamespace, constants.PolicyOwnershipAnyNamespace}
+
+	// ValidImplementationSpecificPathMatches is a list of matches of the ImplementationSpecific paths of ingress resources
+	ValidImplementationSpecificPathMatches = []string{constants.ImplementationSpecificPathMatchAuto, constants.ImplementationSpecificPathMatchExact, constants.ImplementationSpecificPathMatchPrefix, constants.ImplementationSpecificPathMatchRegex}
+
+	// ValidClientCertFields is the list of valid client certificate fields that can be forwarded to applications
+	ValidClientCertFields = []string{constants.ClientCertFieldSubject, constants.ClientCertFieldURI, constants.ClientCertFieldDNS, constants.ClientCertFieldCert, constants.ClientCertFieldChain}
+
+	// ValidLifecycleWebhookEvents is the list of the types of the mesh lifecycle events that can be posted to the lifecycle webhooks
+	ValidLifecycleWebhookEvents = []string{announcements.CertificateRotated.String(), announcements.ProxyConnected.String(), announcements.ProxyDisconnected.String(), announcements.ProxyConfigRejected.String()}
+
 	// defaultFields are the default fields in osm-config
 	defaultFields = []string{"egress", "enable_debug_server", "permissive_traffic_policy_mode", "prometheus_scraping", "use_https_ingress", "envoy_log_level", "service_cert_validity_duration", "tracing_enable", "enable_privileged_init_container"}
 )
@@ -66,6 +90,57 @@ const (
 
 	mustBeValidIPRange = ": must be a list of valid IP addresses of the form a.b.c.d/x"
 
+	// mustBeValidQuantity is the reason for denial for incorrect syntax for a resource quantity field
+	mustBeValidQuantity = ": must be a valid quantity of the form <number><suffix> (Ex: 8Mi)"
+
+	// mustBeValidDigest is the reason for denial for incorrect syntax for an image digest field
+	mustBeValidDigest = ": must be a valid image digest of the form sha256:<hex>"
+
+	// mustBeValidCosignPublicKey is the reason for denial for an invalid cosign public key field
+	mustBeValidCosignPublicKey = ": must be a PEM encoded ECDSA public key"
+
+	// mustBeValidNamespaceList is the reason for denial for excluded_namespaces field
+	mustBeValidNamespaceList = ": must be a comma separated list of namespace names, optionally ending with '*' to match a prefix"
+
+	// mustBeValidUnmeshedPodPolicy is the reason for denial for unmeshed_pod_policy field
+	mustBeValidUnmeshedPodPolicy = ": must be one of allow, audit or deny"
+
+	// mustBeValidPolicyOwnership is the reason for denial for policy_ownership field
+	mustBeValidPolicyOwnership = ": must be one of destination-namespace or any-namespace"
+
+	// mustBeValidImplementationSpecificPathMatch is the reason for denial for implementation_specific_path_match field
+	mustBeValidImplementationSpecificPathMatch = ": must be one of auto, exact, prefix or regex"
+
+	// mustBeValidModuleURL is the reason for denial for URL fields such as waf_module_url
+	mustBeValidModuleURL = ": must be an absolute http or https URL"
+
+	// mustBeValidSHA256 is the reason for denial for waf_module_sha256 field
+	mustBeValidSHA256 = ": must be a hex encoded SHA256 checksum"
+
+	// mustBeValidClientCertFields is the reason for denial for forward_client_cert_details field
+	mustBeValidClientCertFields = ": must be a comma separated list of subject, uri, dns, cert or chain"
+
+	// mustBeValidURLList is the reason for denial for lifecycle_webhook_urls field
+	mustBeValidURLList = ": must be a comma separated list of absolute http or https URLs"
+
+	// mustBeValidLifecycleWebhookEvents is the reason for denial for lifecycle_webhook_events field
+	mustBeValidLifecycleWebhookEvents = ": must be a comma separated list of certificate-rotated, proxy-connected, proxy-disconnected or proxy-config-rejected"
+
+	// mustBeNonNegativeInt is the reason for denial for an integer field that cannot be negative
+	mustBeNonNegativeInt = ": must be a non-negative integer"
+
+	// mustBePositiveInt is the reason for denial for an integer field that must be greater than zero
+	mustBePositiveInt = ": must be a positive integer"
+
+	// mustBeValidSeccompProfile is the reason for denial for proxy_seccomp_profile field
+	mustBeValidSeccompProfile = ": must be one of runtime/default, unconfined or localhost/<path>"
+
+	// mustBeValidAppArmorProfile is the reason for denial for proxy_apparmor_profile field
+	mustBeValidAppArmorProfile = ": must be one of runtime/default, unconfined or localhost/<name>"
+
+	// mustBeValidSELinuxOptions is the reason for denial for proxy_selinux_options field
+	mustBeValidSELinuxOptions = ": must be a user:role:type:level SELinux context"
+
 	// cannotChangeMetadata is the reason for denial for changes to configmap metadata
 	cannotChangeMetadata = ": cannot change metadata"
 
@@ -78,14 +153,16 @@ const (
 )
 
 type webhookConfig struct {
-	kubeClient   kubernetes.Interface
-	cert         certificate.Certificater
-	certManager  certificate.Manager
-	osmNamespace string
+	kubeClient      kubernetes.Interface
+	cfg             Configurator
+	admissionPolicy *admissionpolicy.Evaluator
+	cert            certificate.Certificater
+	certManager     certificate.Manager
+	osmNamespace    string
 }
 
 // NewValidatingWebhook  starts a new web server handling requests from the  ValidatingWebhookConfiguration
-func NewValidatingWebhook(kubeClient kubernetes.Interface, certManager certificate.Manager, osmNamespace, webhookConfigName string, stop <-chan struct{}) error {
+func NewValidatingWebhook(kubeClient kubernetes.Interface, cfg Configurator, certManager certificate.Manager, osmNamespace, webhookConfigName string, stop <-chan struct{}) error {
 	cn := certificate.CommonName(fmt.Sprintf("%s.%s.svc", validatorServiceName, osmNamespace))
 	cert, err := certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
 	if err != nil {
@@ -94,10 +171,12 @@ func NewValidatingWebhook(kubeClient kubernetes.Interface, certManager certifica
 	}
 
 	whc := &webhookConfig{
-		kubeClient:   kubeClient,
-		certManager:  certManager,
-		osmNamespace: osmNamespace,
-		cert:         cert,
+		kubeClient:      kubeClient,
+		cfg:             cfg,
+		admissionPolicy: admissionpolicy.NewEvaluator(kubeClient, osmNamespace, constants.AdmissionPolicyConfigMap, stop),
+		certManager:     certManager,
+		osmNamespace:    osmNamespace,
+		cert:            cert,
 	}
 
 	// Start the ValidatingWebhook web server
@@ -118,13 +197,15 @@ func (whc *webhookConfig) runValidatingWebhook(stop <-chan struct{}) {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc(webhookUpdateConfigMap, whc.configMapHandler)
+	mux.HandleFunc(webhookValidateTrafficTarget, whc.trafficTargetHandler)
+	mux.HandleFunc(webhookValidateAdmissionPolicy, whc.admissionPolicyHandler)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", listenPort),
 		Handler: mux,
 	}
 
-	log.Info().Msgf("Starting configmap webhook server on port: %v", listenPort)
+	log.Info().Msgf("Starting validating webhook server on port: %v", listenPort)
 
 	go func() {
 		if whc.cert == nil {
@@ -167,7 +248,7 @@ func (whc *webhookConfig) configMapHandler(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	requestForNamespace, admissionResp := whc.getAdmissionReqResp(admissionRequestBody)
+	requestForNamespace, admissionResp := whc.getAdmissionReqResp(admissionRequestBody, whc.validateConfigMap)
 
 	resp, err := json.Marshal(&admissionResp)
 	if err != nil {
@@ -181,13 +262,13 @@ func (whc *webhookConfig) configMapHandler(w http.ResponseWriter, req *http.Requ
 	}
 }
 
-func (whc *webhookConfig) getAdmissionReqResp(admissionRequestBody []byte) (requestForNamespace string, admissionResp admissionv1.AdmissionReview) {
+func (whc *webhookConfig) getAdmissionReqResp(admissionRequestBody []byte, validate func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) (requestForNamespace string, admissionResp admissionv1.AdmissionReview) {
 	var admissionReq admissionv1.AdmissionReview
 	if _, _, err := deserializer.Decode(admissionRequestBody, nil, &admissionReq); err != nil {
 		log.Error().Err(err).Msg("Error decoding admission request body")
 		admissionResp.Response = webhook.AdmissionError(err)
 	} else {
-		admissionResp.Response = whc.validateConfigMap(admissionReq.Request)
+		admissionResp.Response = validate(admissionReq.Request)
 	}
 	admissionResp.TypeMeta = admissionReq.TypeMeta
 	admissionResp.Kind = admissionReq.Kind
@@ -252,7 +333,8 @@ func (whc *webhookConfig) validateFields(configMap corev1.ConfigMap, resp *admis
 		if field == "envoy_log_level" && !checkEnvoyLogLevels(field, value) {
 			reasonForDenial(resp, mustBeValidLogLvl, field)
 		}
-		if field == "service_cert_validity_duration" || field == "config_resync_interval" {
+		if field == "service_cert_validity_duration" || field == "config_resync_interval" || field == unusedPolicyWindowKey ||
+			field == serviceCertRenewBeforeKey || field == serviceCertRotationJitterKey || field == trafficTargetShadowDurationKey {
 			_, err := time.ParseDuration(value)
 			if err != nil {
 				reasonForDenial(resp, mustBeValidTime, field)
@@ -270,6 +352,76 @@ func (whc *webhookConfig) validateFields(configMap corev1.ConfigMap, resp *admis
 		if field == outboundIPRangeExclusionListKey && !checkOutboundIPRangeExclusionList(value) {
 			reasonForDenial(resp, mustBeValidIPRange, field)
 		}
+		if field == maxProxyConfigSizeKey {
+			if _, err := resource.ParseQuantity(value); err != nil {
+				reasonForDenial(resp, mustBeValidQuantity, field)
+			}
+		}
+		if field == sidecarImageDigestKey && strings.TrimSpace(value) != "" {
+			if _, err := imageverifier.ParseDigest(strings.TrimSpace(value)); err != nil {
+				reasonForDenial(resp, mustBeValidDigest, field)
+			}
+		}
+		if field == sidecarImageCosignPublicKeyKey && strings.TrimSpace(value) != "" {
+			if _, err := imageverifier.ParseCosignPublicKey(value); err != nil {
+				reasonForDenial(resp, mustBeValidCosignPublicKey, field)
+			}
+		}
+		if field == unmeshedPodPolicyKey && !checkUnmeshedPodPolicy(value) {
+			reasonForDenial(resp, mustBeValidUnmeshedPodPolicy, field)
+		}
+		if field == excludedNamespacesKey && !checkExcludedNamespaces(value) {
+			reasonForDenial(resp, mustBeValidNamespaceList, field)
+		}
+		if field == policyOwnershipKey && !checkPolicyOwnership(value) {
+			reasonForDenial(resp, mustBeValidPolicyOwnership, field)
+		}
+		if field == implementationSpecificPathMatchKey && !checkImplementationSpecificPathMatch(value) {
+			reasonForDenial(resp, mustBeValidImplementationSpecificPathMatch, field)
+		}
+		if field == wafModuleURLKey && strings.TrimSpace(value) != "" && !checkModuleURL(value) {
+			reasonForDenial(resp, mustBeValidModuleURL, field)
+		}
+		if field == policyUsageMetricsURLKey && strings.TrimSpace(value) != "" && !checkModuleURL(value) {
+			reasonForDenial(resp, mustBeValidModuleURL, field)
+		}
+		if field == wafModuleSHA256Key && strings.TrimSpace(value) != "" && !checkSHA256(value) {
+			reasonForDenial(resp, mustBeValidSHA256, field)
+		}
+		if field == xffNumTrustedHopsKey || field == maxMonitoredNamespacesKey || field == maxServicesKey || field == maxProxiesKey {
+			if hops, err := strconv.ParseUint(value, 10, 32); err != nil || hops > math.MaxInt32 {
+				reasonForDenial(resp, mustBeNonNegativeInt, field)
+			}
+		}
+		if field == adaptiveConcurrencyMaxLimitKey || field == proxyUIDKey || field == proxyGIDKey {
+			if number, err := strconv.ParseUint(value, 10, 32); err != nil || number == 0 || number > math.MaxInt32 {
+				reasonForDenial(resp, mustBePositiveInt, field)
+			}
+		}
+		if field == proxySeccompProfileKey && strings.TrimSpace(value) != "" {
+			if _, err := parseSeccompProfile(value); err != nil {
+				reasonForDenial(resp, mustBeValidSeccompProfile, field)
+			}
+		}
+		if field == proxyAppArmorProfileKey && strings.TrimSpace(value) != "" {
+			if _, err := parseAppArmorProfile(value); err != nil {
+				reasonForDenial(resp, mustBeValidAppArmorProfile, field)
+			}
+		}
+		if field == proxySELinuxOptionsKey && strings.TrimSpace(value) != "" {
+			if _, err := parseSELinuxOptions(value); err != nil {
+				reasonForDenial(resp, mustBeValidSELinuxOptions, field)
+			}
+		}
+		if field == forwardClientCertDetailsKey && !checkClientCertFields(value) {
+			reasonForDenial(resp, mustBeValidClientCertFields, field)
+		}
+		if field == lifecycleWebhookURLsKey && !checkURLList(value) {
+			reasonForDenial(resp, mustBeValidURLList, field)
+		}
+		if field == lifecycleWebhookEventsKey && !checkLifecycleWebhookEvents(value) {
+			reasonForDenial(resp, mustBeValidLifecycleWebhookEvents, field)
+		}
 	}
 
 	defConfigMap, _ := whc.kubeClient.CoreV1().ConfigMaps(whc.osmNamespace).Get(context.TODO(), constants.OSMConfigMap, metav1.GetOptions{})
@@ -298,6 +450,118 @@ func checkEnvoyLogLevels(configMapField, configMapValue string) bool {
 	return valid
 }
 
+// checkUnmeshedPodPolicy checks that the field value is a valid unmeshed pod policy
+func checkUnmeshedPodPolicy(configMapValue string) bool {
+	for _, policy := range ValidUnmeshedPodPolicies {
+		if strings.ToLower(strings.TrimSpace(configMapValue)) == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPolicyOwnership checks that the field value is a valid policy ownership
+func checkPolicyOwnership(configMapValue string) bool {
+	for _, ownership := range ValidPolicyOwnerships {
+		if strings.ToLower(strings.TrimSpace(configMapValue)) == ownership {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImplementationSpecificPathMatch checks that the field value is a valid match of ImplementationSpecific paths
+func checkImplementationSpecificPathMatch(configMapValue string) bool {
+	for _, pathMatch := range ValidImplementationSpecificPathMatches {
+		if strings.ToLower(strings.TrimSpace(configMapValue)) == pathMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// checkClientCertFields checks that the field value is a list of valid client certificate fields
+func checkClientCertFields(fieldsStr string) bool {
+	for _, field := range strings.Split(fieldsStr, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field != "" && !isValidClientCertField(field) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidClientCertField returns true if the given field is a valid client certificate field
+func isValidClientCertField(field string) bool {
+	for _, valid := range ValidClientCertFields {
+		if field == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// checkModuleURL checks that the field value is an absolute http or https URL
+func checkModuleURL(configMapValue string) bool {
+	u, err := url.Parse(strings.TrimSpace(configMapValue))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != ""
+}
+
+// checkURLList checks that the field value is a list of absolute http or https URLs
+func checkURLList(urlsStr string) bool {
+	for _, u := range parseCommaSeparatedList(urlsStr) {
+		if !checkModuleURL(u) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkLifecycleWebhookEvents checks that the field value is a list of valid lifecycle event types
+func checkLifecycleWebhookEvents(eventsStr string) bool {
+	for _, event := range parseCommaSeparatedList(eventsStr) {
+		valid := false
+		for _, validEvent := range ValidLifecycleWebhookEvents {
+			if event == validEvent {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSHA256 checks that the field value is a hex encoded SHA256 checksum
+func checkSHA256(configMapValue string) bool {
+	checksum, err := hex.DecodeString(strings.TrimSpace(configMapValue))
+	return err == nil && len(checksum) == sha256.Size
+}
+
+// checkExcludedNamespaces checks that the field value is a list of valid namespace names or prefixes
+func checkExcludedNamespaces(namespacesStr string) bool {
+	for _, ns := range parseCommaSeparatedList(namespacesStr) {
+		prefix := strings.TrimSuffix(ns, "*")
+		if prefix == "" {
+			// A lone '*' would exclude every namespace
+			return false
+		}
+		if prefix != ns {
+			// A prefix is a valid namespace name once completed with a valid suffix
+			prefix += "a"
+		}
+		if len(validation.IsDNS1123Label(prefix)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func checkOutboundIPRangeExclusionList(ipRangesStr string) bool {
 	exclusionList := strings.Split(ipRangesStr, ",")
 	for i := range exclusionList {
@@ -357,19 +621,28 @@ func getPartialValidatingWebhookConfiguration(webhookName string, cert certifica
 // It is necessary to perform this patch because the original ValidatingWebhookConfig YAML does not contain the root certificate.
 func updateValidatingWebhookCABundle(cert certificate.Certificater, webhookName string, clientSet kubernetes.Interface) error {
 	vwc := clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations()
-	if _, err := vwc.Get(context.Background(), webhookName, metav1.GetOptions{}); err != nil {
+	existing, err := vwc.Get(context.Background(), webhookName, metav1.GetOptions{})
+	if err != nil {
 		log.Error().Err(err).Msgf("Error getting ValidatingWebhookConfiguration %s; Will not update CA Bundle for webhook", webhookName)
 		return err
 	}
 
-	patchJSON, err := json.Marshal(getPartialValidatingWebhookConfiguration(ValidatingWebhookName, cert, webhookName))
-	if err != nil {
-		return err
-	}
+	// Only the webhooks defined in the ValidatingWebhookConfiguration are patched, as a ValidatingWebhookConfiguration
+	// installed by a previous version may not define all of them
+	for _, webhook := range existing.Webhooks {
+		if webhook.Name != ValidatingWebhookName && webhook.Name != PolicyOwnershipWebhookName && webhook.Name != AdmissionPolicyWebhookName {
+			continue
+		}
 
-	if _, err = vwc.Patch(context.Background(), webhookName, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
-		log.Error().Err(err).Msgf("Error updating CA Bundle for ValidatingWebhookConfiguration %s", webhookName)
-		return err
+		patchJSON, err := json.Marshal(getPartialValidatingWebhookConfiguration(webhook.Name, cert, webhookName))
+		if err != nil {
+			return err
+		}
+
+		if _, err = vwc.Patch(context.Background(), webhookName, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
+			log.Error().Err(err).Msgf("Error updating CA Bundle for webhook %s in ValidatingWebhookConfiguration %s", webhook.Name, webhookName)
+			return err
+		}
 	}
 
 	log.Info().Msgf("Finished updating CA Bundle for ValidatingWebhookConfiguration %s", webhookName)
@@ -85,7 +85,7 @@ func TestNewValidatingWebhook(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
-			res := NewValidatingWebhook(kubeClient, certManager, whc.osmNamespace, tc.webhookName, stop)
+			res := NewValidatingWebhook(kubeClient, nil, certManager, whc.osmNamespace, tc.webhookName, stop)
 			_ = tc.mockCall
 			assert.Equal(tc.expErr, res.Error())
 		})
@@ -110,7 +110,7 @@ func TestConfigMapHandler(t *testing.T) {
 func TestGetAdmissionReqResp(t *testing.T) {
 	assert := tassert.New(t)
 
-	requestForNamespace, admissionResp := whc.getAdmissionReqResp([]byte(admissionRequestBody))
+	requestForNamespace, admissionResp := whc.getAdmissionReqResp([]byte(admissionRequestBody), whc.validateConfigMap)
 
 	expectedAdmissionResponse := admissionv1.AdmissionReview{
 		TypeMeta: metav1.TypeMeta{Kind: "AdmissionReview", APIVersion: "admission.k8s.io/v1"},
@@ -350,6 +350,637 @@ func TestValidateFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			testName: "Accept configmap with valid max proxy config size",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"max_proxy_config_size": "8Mi",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid max proxy config size",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"max_proxy_config_size": "8 megabytes",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidQuantity,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid sidecar image digest",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"sidecar_image_digest": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid sidecar image digest",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"sidecar_image_digest": "v1.17.1",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidDigest,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid sidecar image cosign public key",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"sidecar_image_cosign_public_key": "not a key",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidCosignPublicKey,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid unmeshed pod policy",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"unmeshed_pod_policy": "deny",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid unmeshed pod policy",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"unmeshed_pod_policy": "block",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidUnmeshedPodPolicy,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid excluded namespaces",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"excluded_namespaces": "kube-system, openshift-*",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap excluding all namespaces",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"excluded_namespaces": "kube-system,*",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidNamespaceList,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid excluded namespace name",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"excluded_namespaces": "Kube_System",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidNamespaceList,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid policy ownership",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"policy_ownership": "any-namespace",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid policy ownership",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"policy_ownership": "source-namespace",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidPolicyOwnership,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid ImplementationSpecific path match",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"implementation_specific_path_match": "regex",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid ImplementationSpecific path match",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"implementation_specific_path_match": "glob",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidImplementationSpecificPathMatch,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid WAF module",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"waf_module_url":    "https://example.com/coraza.wasm",
+					"waf_module_sha256": "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid WAF module URL",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"waf_module_url": "/coraza.wasm",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidModuleURL,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid WAF module checksum",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"waf_module_sha256": "sha256:0123",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidSHA256,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid policy usage settings",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"policy_usage_metrics_url": "http://osm-prometheus.osm-system.svc:7070",
+					"unused_policy_window":     "72h",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid policy usage metrics URL",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"policy_usage_metrics_url": "osm-prometheus:7070",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidModuleURL,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid unused policy window",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"unused_policy_window": "7d",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidTime,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid TrafficTarget shadow duration",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"traffic_target_shadow_duration": "24h",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid TrafficTarget shadow duration",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"traffic_target_shadow_duration": "1w",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidTime,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid client certificate fields",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"forward_client_cert_details": "subject, dns,URI",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid client certificate fields",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"forward_client_cert_details": "dns,serviceaccount",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidClientCertFields,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid lifecycle webhooks",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"lifecycle_webhook_urls":   "https://hooks.example.com/osm, http://ticketing.platform.svc/events",
+					"lifecycle_webhook_events": "certificate-rotated,proxy-config-rejected",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid lifecycle webhook URLs",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"lifecycle_webhook_urls": "https://hooks.example.com/osm,hooks.example.com",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidURLList,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid lifecycle webhook events",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"lifecycle_webhook_events": "proxy-connected,pod-added",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidLifecycleWebhookEvents,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid certificate rotation settings",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"service_cert_validity_duration": "1h",
+					"service_cert_renew_before":      "15m",
+					"service_cert_rotation_jitter":   "5m",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid certificate rotation jitter",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"service_cert_rotation_jitter": "5",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidTime,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with valid forwarded header settings",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"xff_num_trusted_hops": "1",
+					"use_remote_address":   "true",
+					"skip_xff_append":      "false",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result:  &metav1.Status{Reason: ""},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid RBAC deny reporting setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"rbac_deny_reporting": "enabled",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid egress metrics setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"egress_metrics": "on",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid egress audit mode setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"egress_audit_mode": "audit",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid policy recorder setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"policy_recorder": "yes",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid trust bundle publishing setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"publish_trust_bundle": "enabled",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid control plane mTLS setting",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"control_plane_mtls": "required",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeBool,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with negative number of trusted hops",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"xff_num_trusted_hops": "-1",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeNonNegativeInt,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with adaptive concurrency max limit",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"adaptive_concurrency":           "true",
+					"adaptive_concurrency_max_limit": "200",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result: &metav1.Status{
+					Reason: "",
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with proxy user and group IDs",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"proxy_uid": "2102",
+					"proxy_gid": "2102",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result: &metav1.Status{
+					Reason: "",
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with root proxy user ID",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"proxy_uid": "0",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBePositiveInt,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with security profiles of the injected containers",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"proxy_seccomp_profile":  "localhost/profiles/envoy.json",
+					"proxy_apparmor_profile": "runtime/default",
+					"proxy_selinux_options":  "::container_t:s0",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result: &metav1.Status{
+					Reason: "",
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid seccomp profile",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"proxy_seccomp_profile": "RuntimeDefault",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidSeccompProfile,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with invalid SELinux options",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"proxy_selinux_options": "container_t",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeValidSELinuxOptions,
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with zero adaptive concurrency max limit",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"adaptive_concurrency_max_limit": "0",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBePositiveInt,
+				},
+			},
+		},
+		{
+			testName: "Accept configmap with mesh limits",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"max_monitored_namespaces": "50",
+					"max_services":             "0",
+					"max_proxies":              "5000",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: true,
+				Result: &metav1.Status{
+					Reason: "",
+				},
+			},
+		},
+		{
+			testName: "Reject configmap with negative max services",
+			configMap: corev1.ConfigMap{
+				Data: map[string]string{
+					"max_services": "-10",
+				},
+			},
+			expRes: &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: mustBeNonNegativeInt,
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
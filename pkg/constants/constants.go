@@ -10,6 +10,9 @@ const (
 	// WildcardIPAddr is a string constant.
 	WildcardIPAddr = "0.0.0.0"
 
+	// WildcardIPv6Addr is the IPv6 wildcard address.
+	WildcardIPv6Addr = "::"
+
 	// EnvoyAdminPort is Envoy's admin port
 	EnvoyAdminPort = 15000
 
@@ -31,18 +34,38 @@ const (
 	// EnvoyOutboundListenerPortName is Envoy's outbound listener port name.
 	EnvoyOutboundListenerPortName = "proxy-outbound"
 
+	// EnvoyHTTPProxyListenerPort is the localhost port on which Envoy acts as an explicit HTTP proxy for the external
+	// hosts of the Egress policies enabling it
+	EnvoyHTTPProxyListenerPort = 15004
+
+	// EnvoyLifecyclePort is the port on which Envoy serves the lifecycle API to the containers of its pod
+	EnvoyLifecyclePort = 15020
+
+	// EnvoyLifecycleURLEnvVar is the environment variable set on the containers of a pod to the URL of the lifecycle API
+	// of its sidecar proxy
+	EnvoyLifecycleURLEnvVar = "OSM_PROXY_LIFECYCLE_URL"
+
 	// EnvoyUID is the Envoy's User ID
 	EnvoyUID int64 = 1500
 
+	// EnvoyGID is the Envoy's Group ID
+	EnvoyGID int64 = 1500
+
 	// LocalhostIPAddress is the local host address.
 	LocalhostIPAddress = "127.0.0.1"
 
+	// LocalhostIPv6Address is the IPv6 local host address.
+	LocalhostIPv6Address = "::1"
+
 	// EnvoyMetricsCluster is the cluster name of the Prometheus metrics cluster
 	EnvoyMetricsCluster = "envoy-metrics-cluster"
 
 	// EnvoyTracingCluster is the default name to refer to the tracing cluster.
 	EnvoyTracingCluster = "envoy-tracing-cluster"
 
+	// EnvoyWAFModuleCluster is the cluster name used by sidecar proxies to fetch the WAF WASM module
+	EnvoyWAFModuleCluster = "envoy-waf-module-cluster"
+
 	// DefaultTracingEndpoint is the default endpoint route.
 	DefaultTracingEndpoint = "/api/v2/spans"
 
@@ -58,6 +81,15 @@ const (
 	// EnvoyPrometheusInboundListenerPort is Envoy's inbound listener port number for prometheus
 	EnvoyPrometheusInboundListenerPort = 15010
 
+	// EnvoyLivenessProbePort is the port on which Envoy serves the rewritten HTTP liveness probes of the pod
+	EnvoyLivenessProbePort = 15901
+
+	// EnvoyReadinessProbePort is the port on which Envoy serves the rewritten HTTP readiness probes of the pod
+	EnvoyReadinessProbePort = 15902
+
+	// EnvoyStartupProbePort is the port on which Envoy serves the rewritten HTTP startup probes of the pod
+	EnvoyStartupProbePort = 15903
+
 	// InjectorWebhookPort is the port on which the sidecar injection webhook listens
 	InjectorWebhookPort = 9090
 
@@ -70,9 +102,31 @@ const (
 	// OSMControllerName is the name of the OSM Controller (formerly ADS service).
 	OSMControllerName = "osm-controller"
 
+	// OSMInjectorName is the name of the OSM sidecar injector.
+	OSMInjectorName = "osm-injector"
+
 	// OSMControllerPort is the port on which XDS listens for new connections.
 	OSMControllerPort = 15128
 
+	// OSMIntrospectionPort is the port on which the controller serves its introspection gRPC API
+	OSMIntrospectionPort = 15129
+
+	// OSMTrafficMetricsPort is the port on which the controller serves the SMI Traffic Metrics API to the Kubernetes API server
+	OSMTrafficMetricsPort = 9094
+
+	// OSMExternalMetricsPort is the port on which the controller serves the External Metrics API to the Kubernetes API server
+	OSMExternalMetricsPort = 9095
+
+	// OSMControlPlaneConfigPort is the port on which the controller serves the OSM ConfigMap to the other components of the
+	// control plane over mTLS
+	OSMControlPlaneConfigPort = 9096
+
+	// OSMWAFModulePort is the port on which the controller serves the packaged WAF WASM module to the sidecar proxies
+	OSMWAFModulePort = 9097
+
+	// EgressGatewayPort is the port on which the egress gateway accepts the egress traffic of the sidecar proxies
+	EgressGatewayPort = uint32(15006)
+
 	// PrometheusScrapePath is the path for prometheus to scrap envoy metrics from
 	PrometheusScrapePath = "/stats/prometheus"
 
@@ -82,6 +136,9 @@ const (
 	// CertificationAuthorityRootValidityPeriod is when the root certificate expires
 	CertificationAuthorityRootValidityPeriod = 87600 * time.Hour // a decade
 
+	// XDSServerCertificateCommonName is the common name of the certificate of the xDS server of the OSM controller
+	XDSServerCertificateCommonName = "ads"
+
 	// XDSCertificateValidityPeriod is the TTL of the certificates used for Envoy to xDS communication.
 	XDSCertificateValidityPeriod = 87600 * time.Hour // a decade
 
@@ -109,6 +166,10 @@ const (
 	// EnvoyUniqueIDLabelName is the label applied to pods with the unique ID of the Envoy sidecar.
 	EnvoyUniqueIDLabelName = "osm-proxy-uuid"
 
+	// UnmeshedPodLabel is the label applied to pods excluded from the mesh with the reason of the exclusion,
+	// when the unmeshed pod policy is UnmeshedPodPolicyAudit.
+	UnmeshedPodLabel = "openservicemesh.io/unmeshed"
+
 	// TimeDateLayout is the layout for time.Parse used in this repo
 	TimeDateLayout = "2006-01-02T15:04:05.000Z"
 
@@ -135,12 +196,61 @@ const (
 	// InitContainerName is the name of the init container
 	InitContainerName = "osm-init"
 
+	// AppArmorProfileAnnotationPrefix is the prefix of the pod annotation setting the AppArmor profile of the container
+	// whose name follows the prefix
+	AppArmorProfileAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
 	// EnvoyServiceNodeSeparator is the character separating the strings used to create an Envoy service node parameter.
 	// Example use: envoy --service-node 52883c80-6e0d-4c64-b901-cbcb75134949/bookstore/10.144.2.91/bookstore-v1/bookstore-v1
 	EnvoyServiceNodeSeparator = "/"
 
+	// DevProxyWorkloadKind is the workload kind in the Envoy service node ID of a proxy running outside Kubernetes
+	// to join a local development process to the mesh.
+	DevProxyWorkloadKind = "DevProxy"
+
+	// DevProxyCertificateCommonNameSuffix is the last label of the common name of the xDS certificate of a proxy running
+	// outside Kubernetes to join a local development process to the mesh. The certificates of sidecars never have it.
+	DevProxyCertificateCommonNameSuffix = "dev-proxy"
+
 	// OSMConfigMap is the name of the OSM ConfigMap
 	OSMConfigMap = "osm-config"
+
+	// AdmissionPolicyConfigMap is the name of the ConfigMap holding the Rego policies mesh resources are evaluated against at admission
+	AdmissionPolicyConfigMap = "osm-admission-policies"
+
+	// NodeProxyName is the name of the experimental per-node proxy DaemonSet and its service account.
+	NodeProxyName = "osm-node-proxy"
+
+	// NodeProxyRedirectName is the name of the container of the per-node proxy DaemonSet programming the redirection of the
+	// traffic of the node proxy mode pods of its node to the per-node proxy.
+	NodeProxyRedirectName = "osm-node-proxy-redirect"
+
+	// NodeProxyRedirectPort is the port on which the node proxy redirect container serves its health probes and metrics.
+	// The container runs in the network namespace of the node, so the port must not conflict with the ports of the node.
+	NodeProxyRedirectPort = 15131
+
+	// NodeProxyBootstrapSecretName is the name of the secret holding the Envoy bootstrap config of the per-node proxies.
+	NodeProxyBootstrapSecretName = "osm-node-proxy-bootstrap-config"
+
+	// EgressGatewayName is the name of the egress gateway Deployment, its service account and its Service.
+	EgressGatewayName = "osm-egress-gateway"
+
+	// EgressGatewayBootstrapSecretName is the name of the secret holding the Envoy bootstrap config of the egress gateway.
+	EgressGatewayBootstrapSecretName = "osm-egress-gateway-bootstrap-config"
+
+	// LifecycleWebhookSecretName is the name of the secret holding the key the lifecycle events posted to webhooks are
+	// signed with.
+	LifecycleWebhookSecretName = "osm-lifecycle-webhook"
+
+	// LifecycleWebhookSecretKey is the key of the secret holding the key the lifecycle events are signed with.
+	LifecycleWebhookSecretKey = "hmac-key"
+
+	// TrustBundleConfigMapName is the name of the ConfigMap the trust bundle of the mesh is published to in every
+	// monitored namespace.
+	TrustBundleConfigMapName = "osm-trust-bundle"
+
+	// TrustBundleConfigMapKey is the key of the ConfigMap holding the PEM encoded trust bundle of the mesh.
+	TrustBundleConfigMapKey = "ca.crt"
 )
 
 // Annotations used by the controller
@@ -150,6 +260,278 @@ const (
 
 	// MetricsAnnotation is the annotation used for enabling/disabling metrics
 	MetricsAnnotation = "openservicemesh.io/metrics"
+
+	// ExternalPlaintextTrafficAnnotation is the annotation used on a NodePort or LoadBalancer service to allow
+	// plaintext traffic originating outside the mesh to reach the service's pods
+	ExternalPlaintextTrafficAnnotation = "openservicemesh.io/external-plaintext-traffic"
+
+	// ProxyModeAnnotation is the annotation used on a pod to select how the pod is proxied, one of ProxyModeSidecar or ProxyModeNode
+	ProxyModeAnnotation = "openservicemesh.io/proxy-mode"
+
+	// TrafficTargetExpiresAtAnnotation is the annotation used on an SMI TrafficTarget to grant temporary access, set to
+	// the RFC3339 time at which the TrafficTarget expires and stops granting access
+	TrafficTargetExpiresAtAnnotation = "openservicemesh.io/expires-at"
+
+	// PolicyScheduleAnnotation is the annotation used on an SMI TrafficTarget or TrafficSplit to only apply it during
+	// the comma separated list of recurring time windows of the annotation, e.g. "Mon-Fri 22:00-06:00" in UTC
+	PolicyScheduleAnnotation = "openservicemesh.io/active-schedule"
+
+	// PolicyDelegatesAnnotation is the annotation used on a namespace to list the namespaces, separated by commas,
+	// allowed to author SMI TrafficTargets with a destination in the namespace
+	PolicyDelegatesAnnotation = "openservicemesh.io/policy-delegates"
+
+	// XFFNumTrustedHopsAnnotation is the annotation used on a service to override the number of trusted proxy hops in
+	// the x-forwarded-for header of the requests received by the service from ingress
+	XFFNumTrustedHopsAnnotation = "openservicemesh.io/xff-num-trusted-hops"
+
+	// UseRemoteAddressAnnotation is the annotation used on a service to override whether the client address of the requests
+	// received by the service from ingress is determined from the remote address of the connection
+	UseRemoteAddressAnnotation = "openservicemesh.io/use-remote-address"
+
+	// SkipXFFAppendAnnotation is the annotation used on a service to override whether the remote address of the connection
+	// is appended to the x-forwarded-for header of the requests received by the service from ingress
+	SkipXFFAppendAnnotation = "openservicemesh.io/skip-xff-append"
+
+	// WAFRulesetAnnotation is the annotation used on a service to enable the WAF on its inbound HTTP traffic, set to
+	// the name of the ConfigMap in the service's namespace holding the WAF ruleset
+	WAFRulesetAnnotation = "openservicemesh.io/waf-ruleset"
+
+	// WAFRulesetConfigMapKey is the key of the WAF ruleset ConfigMap holding the SecLang directives of the ruleset
+	WAFRulesetConfigMapKey = "rules.conf"
+
+	// InboundProxyProtocolPortsAnnotation is the annotation used on a service to require the PROXY protocol on the given
+	// comma separated list of target ports of the service, so that the original client address of connections
+	// forwarded by L4 load balancers is preserved
+	InboundProxyProtocolPortsAnnotation = "openservicemesh.io/inbound-proxy-protocol-ports"
+
+	// UpstreamProxyProtocolAnnotation is the annotation used on a service to make clients in the mesh emit the PROXY
+	// protocol, in the version given by the annotation, on connections to the service
+	UpstreamProxyProtocolAnnotation = "openservicemesh.io/upstream-proxy-protocol"
+
+	// AdaptiveConcurrencyAnnotation is the annotation used on a service to override whether the sidecar proxies of the
+	// service's pods shed load with adaptive concurrency limits when the service is overloaded
+	AdaptiveConcurrencyAnnotation = "openservicemesh.io/adaptive-concurrency"
+
+	// AdaptiveConcurrencyMaxLimitAnnotation is the annotation used on a service to override the maximum concurrency
+	// limit allowed by the adaptive concurrency limits of the service
+	AdaptiveConcurrencyMaxLimitAnnotation = "openservicemesh.io/adaptive-concurrency-max-limit"
+
+	// TrafficPriorityAnnotation is the annotation used on a service to set the priority class of the traffic to the
+	// service, one of TrafficPriorityCritical, TrafficPriorityNormal or TrafficPriorityBestEffort
+	TrafficPriorityAnnotation = "openservicemesh.io/traffic-priority"
+
+	// DarkLaunchServiceAnnotation is the annotation used on a service to dark launch the service with the given name in
+	// the same namespace: the shadow service receives a copy of the requests to the service, and its responses are discarded
+	DarkLaunchServiceAnnotation = "openservicemesh.io/dark-launch-service"
+
+	// DarkLaunchPercentageAnnotation is the annotation used on a dark launched service to set the percentage of its
+	// requests copied to the shadow service, 100 by default
+	DarkLaunchPercentageAnnotation = "openservicemesh.io/dark-launch-percentage"
+
+	// GRPCHealthCheckAnnotation is the annotation used on a service to actively health check its endpoints with the gRPC
+	// health checking protocol, for the gRPC service with the given name, or for the whole server if the value is empty
+	GRPCHealthCheckAnnotation = "openservicemesh.io/grpc-health-check"
+
+	// GRPCHealthCheckGateAnnotation is the annotation used on a gRPC health checked service to determine whether new
+	// endpoints of the service receive traffic only once they passed their first health check
+	GRPCHealthCheckGateAnnotation = "openservicemesh.io/grpc-health-check-gate"
+
+	// FailoverEndpointsAnnotation is the annotation used on a service to set the comma separated list of backup endpoints,
+	// as <IP>:<port>, that the clients of the service fail over to when the endpoints of the service are unhealthy
+	FailoverEndpointsAnnotation = "openservicemesh.io/failover-endpoints"
+
+	// FailoverThresholdAnnotation is the annotation used on a service with backup endpoints to set the percentage of
+	// healthy endpoints of the service below which its traffic starts failing over to the backup endpoints
+	FailoverThresholdAnnotation = "openservicemesh.io/failover-threshold"
+
+	// EnvoyLogLevelAnnotation is the annotation used on a namespace or a pod to override the log level of the sidecar
+	// proxies of the pods
+	EnvoyLogLevelAnnotation = "openservicemesh.io/envoy-log-level"
+
+	// EgressAnnotation is the annotation used on a namespace or a pod to override whether the sidecar proxies of the pods
+	// allow egress traffic to any destination outside the mesh, as allowed mesh-wide by the egress setting of the OSM
+	// ConfigMap. Set to true or false.
+	EgressAnnotation = "openservicemesh.io/egress"
+
+	// EgressAuditAnnotation is the annotation used on a namespace or a pod to override whether the sidecar proxies of the
+	// pods report the egress connections not allowed by Egress policies rather than deny them, as enabled mesh-wide by
+	// the egress_audit_mode setting of the OSM ConfigMap. Set to true or false.
+	EgressAuditAnnotation = "openservicemesh.io/egress-audit"
+
+	// StagedRolloutNamespacesAnnotation is the annotation used on the OSM ConfigMap to roll out its changes to the sidecar
+	// proxies of the given comma separated namespaces first
+	StagedRolloutNamespacesAnnotation = "openservicemesh.io/staged-rollout-namespaces"
+
+	// StagedRolloutNamespaceSelectorAnnotation is the annotation used on the OSM ConfigMap to roll out its changes to the
+	// sidecar proxies of the namespaces matching the given label selector first
+	StagedRolloutNamespaceSelectorAnnotation = "openservicemesh.io/staged-rollout-namespace-selector"
+
+	// StagedRolloutPercentageAnnotation is the annotation used on the OSM ConfigMap to roll out its changes to the given
+	// percentage of the sidecar proxies first
+	StagedRolloutPercentageAnnotation = "openservicemesh.io/staged-rollout-percentage"
+
+	// StagedRolloutSoakPeriodAnnotation is the annotation used on the OSM ConfigMap to set how long a change is soaked by
+	// the first sidecar proxies before it is promoted to all the sidecar proxies
+	StagedRolloutSoakPeriodAnnotation = "openservicemesh.io/staged-rollout-soak-period"
+
+	// StagedRolloutMax5xxPercentageAnnotation is the annotation used on the OSM ConfigMap to set the percentage of 5xx
+	// responses of the first sidecar proxies above which a change is not promoted
+	StagedRolloutMax5xxPercentageAnnotation = "openservicemesh.io/staged-rollout-max-5xx-percentage"
+
+	// IngressHostRegexAnnotation is the annotation used on an ingress resource or a Gateway API HTTPRoute to restrict its
+	// rules without a host to the requests whose host matches the given regular expression
+	IngressHostRegexAnnotation = "openservicemesh.io/ingress-host-regex"
+
+	// IngressPathRewriteAnnotation is the annotation used on an ingress resource to rewrite the part of the path of the
+	// requests matching the path of a rule of the resource with the given path
+	IngressPathRewriteAnnotation = "openservicemesh.io/ingress-path-rewrite"
+
+	// ImplementationSpecificPathMatchAnnotation is the annotation used on an ingress resource or a Gateway API HTTPRoute
+	// to match its ImplementationSpecific paths exactly, by prefix or as regular expressions, overriding the mesh-wide
+	// match set in the OSM ConfigMap
+	ImplementationSpecificPathMatchAnnotation = "openservicemesh.io/implementation-specific-path-match"
+
+	// IngressRetryOnAnnotation is the annotation used on an ingress resource to retry the requests matching its rules on
+	// the given comma separated list of Envoy retry conditions, such as 5xx,reset,connect-failure
+	IngressRetryOnAnnotation = "openservicemesh.io/ingress-retry-on"
+
+	// IngressNumRetriesAnnotation is the annotation used on an ingress resource to set the maximum number of retries of
+	// the requests matching its rules, when retried with IngressRetryOnAnnotation
+	IngressNumRetriesAnnotation = "openservicemesh.io/ingress-num-retries"
+
+	// IngressPerTryTimeoutAnnotation is the annotation used on an ingress resource to set the timeout, as a duration such
+	// as 2s, of each try of the requests matching its rules, when retried with IngressRetryOnAnnotation
+	IngressPerTryTimeoutAnnotation = "openservicemesh.io/ingress-per-try-timeout"
+
+	// IngressTimeoutAnnotation is the annotation used on an ingress resource to set the timeout, as a duration such as 30s,
+	// of the requests matching its rules, including their retries. A timeout of 0s disables the timeout.
+	IngressTimeoutAnnotation = "openservicemesh.io/ingress-timeout"
+
+	// IngressStatusAnnotation is the annotation written by OSM on the ingress resources it programs ingress policies for,
+	// reporting as JSON whether each rule of the resource was accepted or rejected
+	IngressStatusAnnotation = "openservicemesh.io/ingress-status"
+
+	// RequestTimeoutAnnotation is the annotation used on a service to set the timeout, as a duration such as 5m, of the
+	// requests to the service, including their retries. A timeout of 0s disables the timeout.
+	RequestTimeoutAnnotation = "openservicemesh.io/request-timeout"
+
+	// StreamIdleTimeoutAnnotation is the annotation used on a service to set how long, as a duration such as 1h, a request
+	// to the service may go without sending or receiving data before it times out. A timeout of 0s disables the timeout.
+	StreamIdleTimeoutAnnotation = "openservicemesh.io/stream-idle-timeout"
+
+	// IdleTimeoutAnnotation is the annotation used on a service to set how long, as a duration such as 10m, the
+	// connections of the sidecar proxies to the service are kept open without requests. A timeout of 0s disables the timeout.
+	IdleTimeoutAnnotation = "openservicemesh.io/idle-timeout"
+
+	// RouteTimeoutsAnnotation is the annotation used on an SMI HTTPRouteGroup to set the timeouts of the requests matching
+	// its matches, as a JSON object mapping the names of the matches to their requestTimeout and streamIdleTimeout,
+	// overriding the timeouts of the destination service
+	RouteTimeoutsAnnotation = "openservicemesh.io/route-timeouts"
+
+	// InboundMaxConnectionsAnnotation is the annotation used on a service or a namespace to limit the number of
+	// connections each sidecar proxy of the service opens to the service
+	InboundMaxConnectionsAnnotation = "openservicemesh.io/inbound-max-connections"
+
+	// InboundMaxRequestsPerConnectionAnnotation is the annotation used on a service or a namespace to limit the number
+	// of requests each sidecar proxy of the service sends to the service over a single connection
+	InboundMaxRequestsPerConnectionAnnotation = "openservicemesh.io/inbound-max-requests-per-connection"
+)
+
+// Values for the upstream PROXY protocol annotation
+const (
+	// ProxyProtocolV1 is the human readable version 1 of the PROXY protocol
+	ProxyProtocolV1 = "v1"
+
+	// ProxyProtocolV2 is the binary version 2 of the PROXY protocol
+	ProxyProtocolV2 = "v2"
+)
+
+// Values for the traffic priority annotation
+const (
+	// TrafficPriorityCritical is the priority class of traffic that must survive partial outages, allowed deeper queues
+	// and more retries than other traffic
+	TrafficPriorityCritical = "critical"
+
+	// TrafficPriorityNormal is the default priority class of traffic
+	TrafficPriorityNormal = "normal"
+
+	// TrafficPriorityBestEffort is the priority class of batch traffic, shed first when the upstream service is overloaded
+	TrafficPriorityBestEffort = "best-effort"
+)
+
+// Values for the proxy mode annotation
+const (
+	// ProxyModeSidecar is the default proxy mode, where the pod is injected with a sidecar proxy
+	ProxyModeSidecar = "sidecar"
+
+	// ProxyModeNode is the experimental proxy mode, where the pod is served by the per-node proxy on its node
+	ProxyModeNode = "node"
+)
+
+// Values for the unmeshed pod policy, which determines how pods excluded from the mesh in namespaces enabled for sidecar injection are admitted
+const (
+	// UnmeshedPodPolicyAllow is the default unmeshed pod policy, where unmeshed pods are admitted
+	UnmeshedPodPolicyAllow = "allow"
+
+	// UnmeshedPodPolicyAudit is the unmeshed pod policy where unmeshed pods are admitted and labeled with UnmeshedPodLabel
+	UnmeshedPodPolicyAudit = "audit"
+
+	// UnmeshedPodPolicyDeny is the unmeshed pod policy where unmeshed pods are rejected
+	UnmeshedPodPolicyDeny = "deny"
+)
+
+// Reasons for a pod to be excluded from the mesh, used as values of UnmeshedPodLabel
+const (
+	// UnmeshedReasonOptOut is the reason for a pod explicitly annotated to disable sidecar injection
+	UnmeshedReasonOptOut = "opt-out"
+
+	// UnmeshedReasonHostNetwork is the reason for a pod using the host network, which cannot be injected with a sidecar
+	UnmeshedReasonHostNetwork = "host-network"
+)
+
+// Values for the policy ownership, which determines which namespaces may author SMI TrafficTargets for a destination
+const (
+	// PolicyOwnershipDestinationNamespace is the default policy ownership, where a TrafficTarget must be authored in the
+	// namespace of its destination, or in a namespace its destination namespace delegates to with PolicyDelegatesAnnotation
+	PolicyOwnershipDestinationNamespace = "destination-namespace"
+
+	// PolicyOwnershipAnyNamespace is the policy ownership where a TrafficTarget may be authored in any namespace
+	PolicyOwnershipAnyNamespace = "any-namespace"
+)
+
+// Values for the match of the ImplementationSpecific paths of ingress resources and Gateway API HTTPRoutes
+const (
+	// ImplementationSpecificPathMatchAuto is the default match, where a path is matched as a regular expression when it
+	// contains characters commonly used in regular expressions, and by string prefix otherwise
+	ImplementationSpecificPathMatchAuto = "auto"
+
+	// ImplementationSpecificPathMatchExact matches the paths exactly
+	ImplementationSpecificPathMatchExact = "exact"
+
+	// ImplementationSpecificPathMatchPrefix matches the paths by string prefix, so that /foo matches /foo/bar and /foobar
+	ImplementationSpecificPathMatchPrefix = "prefix"
+
+	// ImplementationSpecificPathMatchRegex matches the paths as RE2 regular expressions
+	ImplementationSpecificPathMatchRegex = "regex"
+)
+
+// Fields of the verified client certificate that can be forwarded to applications in the x-forwarded-client-cert header
+const (
+	// ClientCertFieldSubject is the subject of the client certificate
+	ClientCertFieldSubject = "subject"
+
+	// ClientCertFieldURI is the URI type Subject Alternative Names of the client certificate
+	ClientCertFieldURI = "uri"
+
+	// ClientCertFieldDNS is the DNS type Subject Alternative Names of the client certificate, which holds the identity
+	// of the client in the form <service-account>.<namespace>.cluster.local
+	ClientCertFieldDNS = "dns"
+
+	// ClientCertFieldCert is the entire URL encoded PEM client certificate
+	ClientCertFieldCert = "cert"
+
+	// ClientCertFieldChain is the entire URL encoded PEM client certificate chain
+	ClientCertFieldChain = "chain"
 )
 
 // Annotations used for Metrics
@@ -171,3 +553,35 @@ const (
 	OSMAppInstanceLabelKey = "app.kubernetes.io/instance"
 	OSMAppVersionLabelKey  = "app.kubernetes.io/version"
 )
+
+// Metadata of the Kubernetes resources generated at runtime by the OSM control plane.
+const (
+	// OSMAppManagedByLabelKey is the label key identifying the manager of a resource
+	OSMAppManagedByLabelKey = "app.kubernetes.io/managed-by"
+
+	// OSMAppManagedByLabelValue is the label value identifying resources generated at runtime by the OSM control plane,
+	// as opposed to the resources installed by Helm or a GitOps tool
+	OSMAppManagedByLabelValue = "osm-control-plane"
+
+	// ArgoCDCompareOptionsAnnotation is the annotation used by Argo CD to configure how a resource is compared to Git
+	ArgoCDCompareOptionsAnnotation = "argocd.argoproj.io/compare-options"
+
+	// ArgoCDSyncOptionsAnnotation is the annotation used by Argo CD to configure how a resource is synced
+	ArgoCDSyncOptionsAnnotation = "argocd.argoproj.io/sync-options"
+)
+
+// Tags of the Envoy stats attributed to SMI policies and external hosts, extracted from the names of the stats by the
+// bootstrap config of the proxies.
+const (
+	// TrafficTargetStatsTag is the tag of the stats of the requests allowed by an SMI TrafficTarget
+	TrafficTargetStatsTag = "osm_traffic_target"
+
+	// TrafficSplitStatsTag is the tag of the stats of the requests routed by an SMI TrafficSplit
+	TrafficSplitStatsTag = "osm_traffic_split"
+
+	// EgressHostStatsTag is the tag of the stats of the cluster of an external host allowed by an Egress policy
+	EgressHostStatsTag = "osm_egress_host"
+
+	// EgressPortStatsTag is the tag of the stats of the cluster of an external host allowed by an Egress policy on a port
+	EgressPortStatsTag = "osm_egress_port"
+)
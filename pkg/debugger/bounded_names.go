@@ -0,0 +1,33 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// boundedNameQueryKey is the query parameter looking up the name a single bounded name was generated from
+const boundedNameQueryKey = "name"
+
+func (ds DebugConfig) getBoundedNamesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mappings := envoy.ListBoundedNames()
+		if bounded := r.URL.Query().Get(boundedNameQueryKey); bounded != "" {
+			name, ok := envoy.LookupBoundedName(bounded)
+			if !ok {
+				http.Error(w, fmt.Sprintf("Unknown bounded name %s", bounded), http.StatusNotFound)
+				return
+			}
+			mappings = []envoy.BoundedNameMapping{{BoundedName: bounded, Name: name}}
+		}
+
+		jsonMappings, err := json.Marshal(mappings)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling bounded names %+v", mappings)
+		}
+
+		_, _ = fmt.Fprint(w, string(jsonMappings))
+	})
+}
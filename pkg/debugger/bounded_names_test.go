@@ -0,0 +1,40 @@
+package debugger
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getBoundedNamesHandler through HTTP handler returns the names bounded names were generated from
+func TestBoundedNamesHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	name := "inbound_virtual-host|bookstore.bookstore-ns|" + strings.Repeat("a", envoy.MaxNameLength)
+	bounded := envoy.BoundedName(name)
+	ds := DebugConfig{}
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getBoundedNamesHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/bounded-names", nil))
+	assert.Equal(200, responseRecorder.Code)
+
+	var mappings []envoy.BoundedNameMapping
+	assert.Nil(json.Unmarshal(responseRecorder.Body.Bytes(), &mappings))
+	assert.Contains(mappings, envoy.BoundedNameMapping{BoundedName: bounded, Name: name})
+
+	responseRecorder = httptest.NewRecorder()
+	ds.getBoundedNamesHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/bounded-names?name="+url.QueryEscape(bounded), nil))
+	assert.Equal(200, responseRecorder.Code)
+	assert.Nil(json.Unmarshal(responseRecorder.Body.Bytes(), &mappings))
+	assert.Equal([]envoy.BoundedNameMapping{{BoundedName: bounded, Name: name}}, mappings)
+
+	responseRecorder = httptest.NewRecorder()
+	ds.getBoundedNamesHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/bounded-names?name=unknown", nil))
+	assert.Equal(404, responseRecorder.Code)
+}
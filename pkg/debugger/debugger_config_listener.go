@@ -15,40 +15,60 @@ func (d *DebugConfig) StartDebugServerConfigListener() {
 		announcements.ConfigMapDeleted,
 		announcements.ConfigMapUpdated)
 
-	// This is the Debug server
-	httpDebugServer := httpserver.NewHTTPServer(constants.DebugPort)
-	httpDebugServer.AddHandlers(d.GetHandlers())
-
 	// Run config listener
-	go func(cfgSubChannel chan interface{}, dConf *DebugConfig, httpServ *httpserver.HTTPServer) {
+	go func(cfgSubChannel chan interface{}, dConf *DebugConfig) {
 		// Bootstrap after subscribing
+		var httpDebugServer *httpserver.HTTPServer
 		started := false
-
-		if d.configurator.IsDebugServerEnabled() {
-			if err := httpDebugServer.Start(); err != nil {
-				log.Error().Err(err).Msgf("error starting debug server")
-			}
-			started = true
-		}
+		mutualTLS := false
 
 		for {
-			<-cfgSubChannel
-			isDbgSrvEnabled := d.configurator.IsDebugServerEnabled()
+			isDbgSrvEnabled := dConf.configurator.IsDebugServerEnabled()
+			isMutualTLS := dConf.isMutualTLSRequired()
 
+			// The server is restarted to switch between plaintext HTTP and mTLS
+			if started && (!isDbgSrvEnabled || isMutualTLS != mutualTLS) {
+				if err := httpDebugServer.Stop(); err != nil {
+					log.Error().Err(err).Msgf("error stopping debug server")
+				} else {
+					started = false
+				}
+			}
 			if isDbgSrvEnabled && !started {
+				httpDebugServer = dConf.newDebugServer(isMutualTLS)
 				if err := httpDebugServer.Start(); err != nil {
 					log.Error().Err(err).Msgf("error starting debug server")
 				} else {
 					started = true
+					mutualTLS = isMutualTLS
 				}
 			}
-			if !isDbgSrvEnabled && started {
-				if err := httpDebugServer.Stop(); err != nil {
-					log.Error().Err(err).Msgf("error stopping debug server")
-				} else {
-					started = false
-				}
-			}
+
+			<-cfgSubChannel
 		}
-	}(ch, d, httpDebugServer)
+	}(ch, d)
+}
+
+// isMutualTLSRequired returns whether the debug server only accepts clients authenticated with control plane identities
+func (d *DebugConfig) isMutualTLSRequired() bool {
+	if !d.configurator.IsControlPlaneMTLSEnabled() {
+		return false
+	}
+	if d.tlsConfig == nil {
+		log.Error().Msg("Control plane mTLS is enabled but the debug server has no TLS configuration, serving plaintext HTTP")
+		return false
+	}
+	return true
+}
+
+// newDebugServer creates the debug server, serving HTTPS and rejecting plaintext callers when mutualTLS is set
+func (d *DebugConfig) newDebugServer(mutualTLS bool) *httpserver.HTTPServer {
+	var httpDebugServer *httpserver.HTTPServer
+	if mutualTLS {
+		httpDebugServer = httpserver.NewHTTPSServer(constants.DebugPort, d.tlsConfig)
+	} else {
+		httpDebugServer = httpserver.NewHTTPServer(constants.DebugPort)
+	}
+	httpDebugServer.AddHandlers(d.GetHandlers())
+	return httpDebugServer
 }
@@ -0,0 +1,100 @@
+package debugger
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+const (
+	// lastPushPath is the path of the diffs of the xDS responses last sent to a proxy, followed by the common name of
+	// the certificate of the proxy
+	lastPushPath = "/debug/lastpush/"
+
+	// lastPushTypeQueryKey is the query parameter restricting the diffs to a type of xDS response, Ex. rds
+	lastPushTypeQueryKey = "type"
+)
+
+func (ds DebugConfig) getLastPushHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		proxies := ds.meshCatalogDebugger.ListConnectedProxies()
+
+		cn := certificate.CommonName(strings.TrimPrefix(r.URL.Path, lastPushPath))
+		if cn == "" {
+			printLastPushProxies(w, proxies)
+			return
+		}
+		proxy, ok := proxies[cn]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Proxy with CN=%s is not connected", cn), http.StatusNotFound)
+			return
+		}
+
+		var typeURI envoy.TypeURI
+		if shortName := r.URL.Query().Get(lastPushTypeQueryKey); shortName != "" {
+			for uri, name := range envoy.XDSShortURINames {
+				if strings.EqualFold(name, shortName) {
+					typeURI = uri
+				}
+			}
+			if typeURI == envoy.TypeWildcard {
+				http.Error(w, fmt.Sprintf("Invalid xDS type %s, must be one of cds, eds, lds, rds or sds", shortName), http.StatusBadRequest)
+				return
+			}
+		}
+
+		_, _ = fmt.Fprintf(w, "Last %d xDS responses of each type sent to proxy with CN=%s, the most recent first\n", envoy.MaxPushHistory, cn)
+		for _, diff := range proxy.GetPushDiffs() {
+			if typeURI != envoy.TypeWildcard && diff.TypeURI != typeURI {
+				continue
+			}
+			printPushDiff(w, diff)
+		}
+	})
+}
+
+func printLastPushProxies(w http.ResponseWriter, proxies map[certificate.CommonName]*envoy.Proxy) {
+	var commonNames []string
+	for cn := range proxies {
+		commonNames = append(commonNames, cn.String())
+	}
+	sort.Strings(commonNames)
+
+	_, _ = fmt.Fprintf(w, "Connected proxies (%d), see %s<CN> for the xDS responses last sent to a proxy:\n", len(commonNames), lastPushPath)
+	for _, cn := range commonNames {
+		_, _ = fmt.Fprintf(w, "%s%s\n", lastPushPath, cn)
+	}
+}
+
+func printPushDiff(w http.ResponseWriter, diff envoy.PushDiff) {
+	xdsShortName := envoy.XDSShortURINames[diff.TypeURI]
+	_, _ = fmt.Fprintf(w, "\n[%s] version %d sent at %s", xdsShortName, diff.Version, diff.SentAt.Format("2006-01-02T15:04:05.000Z07:00"))
+	if diff.PreviousVersion == 0 {
+		_, _ = fmt.Fprint(w, ", no previous version recorded\n")
+	} else {
+		_, _ = fmt.Fprintf(w, ", compared to version %d\n", diff.PreviousVersion)
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		_, _ = fmt.Fprintf(w, "  no changes, %d resources unchanged\n", diff.Unchanged)
+		return
+	}
+	for _, name := range diff.Added {
+		_, _ = fmt.Fprintf(w, "  + %s\n", name)
+	}
+	for _, name := range diff.Removed {
+		_, _ = fmt.Fprintf(w, "  - %s\n", name)
+	}
+	for _, change := range diff.Changed {
+		_, _ = fmt.Fprintf(w, "  ~ %s: %s changed\n", change.Name, strings.Join(change.Fields, ", "))
+		for _, detail := range change.Details {
+			_, _ = fmt.Fprintf(w, "      %s\n", detail)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "  %d added, %d removed, %d changed, %d unchanged\n", len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+}
@@ -0,0 +1,97 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getLastPushHandler through HTTP handler returns the diffs of the xDS responses last sent to a proxy
+func TestLastPushHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cluster := func(name string) *any.Any {
+		marshalled, err := ptypes.MarshalAny(&xds_cluster.Cluster{Name: name})
+		assert.Nil(err)
+		return marshalled
+	}
+	proxy := envoy.NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+	proxy.RecordPush(envoy.TypeCDS, 1, []*any.Any{cluster("bookstore/bookstore-v1")})
+	proxy.RecordPush(envoy.TypeCDS, 2, []*any.Any{cluster("bookstore/bookstore-v2")})
+
+	mock := NewMockMeshCatalogDebugger(mockCtrl)
+	mock.EXPECT().ListConnectedProxies().Return(map[certificate.CommonName]*envoy.Proxy{
+		"bookbuyer": proxy,
+	}).AnyTimes()
+
+	ds := DebugConfig{
+		meshCatalogDebugger: mock,
+	}
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		expectedStatus       int
+		expectedContains     []string
+		expectedNotContained []string
+	}{
+		{
+			name:             "list the connected proxies",
+			url:              "/debug/lastpush/",
+			expectedStatus:   200,
+			expectedContains: []string{"/debug/lastpush/bookbuyer"},
+		},
+		{
+			name:           "diffs of the responses sent to a proxy",
+			url:            "/debug/lastpush/bookbuyer",
+			expectedStatus: 200,
+			expectedContains: []string{
+				"[CDS] version 2",
+				"compared to version 1",
+				"  + bookstore/bookstore-v2",
+				"  - bookstore/bookstore-v1",
+				"1 added, 1 removed, 0 changed, 0 unchanged",
+			},
+		},
+		{
+			name:                 "diffs of the responses of a type",
+			url:                  "/debug/lastpush/bookbuyer?type=rds",
+			expectedStatus:       200,
+			expectedNotContained: []string{"[CDS]"},
+		},
+		{
+			name:           "invalid type",
+			url:            "/debug/lastpush/bookbuyer?type=xds",
+			expectedStatus: 400,
+		},
+		{
+			name:           "proxy not connected",
+			url:            "/debug/lastpush/bookstore",
+			expectedStatus: 404,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			responseRecorder := httptest.NewRecorder()
+			ds.getLastPushHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", tc.url, nil))
+			assert.Equal(tc.expectedStatus, responseRecorder.Code)
+			for _, expected := range tc.expectedContains {
+				assert.Contains(responseRecorder.Body.String(), expected)
+			}
+			for _, notExpected := range tc.expectedNotContained {
+				assert.NotContains(responseRecorder.Body.String(), notExpected)
+			}
+		})
+	}
+}
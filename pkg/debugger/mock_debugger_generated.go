@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/openservicemesh/osm/pkg/debugger (interfaces: CertificateManagerDebugger,MeshCatalogDebugger,XDSDebugger)
+// Source: github.com/openservicemesh/osm/pkg/debugger (interfaces: CertificateManagerDebugger,MeshCatalogDebugger,PolicyReporter,XDSDebugger)
 
 // Package debugger is a generated GoMock package.
 package debugger
@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	certificate "github.com/openservicemesh/osm/pkg/certificate"
 	envoy "github.com/openservicemesh/osm/pkg/envoy"
+	policyreport "github.com/openservicemesh/osm/pkg/policyreport"
 	service "github.com/openservicemesh/osm/pkg/service"
 	v1alpha3 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	v1alpha4 "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
@@ -77,6 +78,20 @@ func (m *MockMeshCatalogDebugger) EXPECT() *MockMeshCatalogDebuggerMockRecorder
 	return m.recorder
 }
 
+// ListConfigGenerations mocks base method
+func (m *MockMeshCatalogDebugger) ListConfigGenerations() []envoy.ConfigGeneration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigGenerations")
+	ret0, _ := ret[0].([]envoy.ConfigGeneration)
+	return ret0
+}
+
+// ListConfigGenerations indicates an expected call of ListConfigGenerations
+func (mr *MockMeshCatalogDebuggerMockRecorder) ListConfigGenerations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigGenerations", reflect.TypeOf((*MockMeshCatalogDebugger)(nil).ListConfigGenerations))
+}
+
 // ListConnectedProxies mocks base method
 func (m *MockMeshCatalogDebugger) ListConnectedProxies() map[certificate.CommonName]*envoy.Proxy {
 	m.ctrl.T.Helper()
@@ -150,6 +165,43 @@ func (mr *MockMeshCatalogDebuggerMockRecorder) ListSMIPolicies() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSMIPolicies", reflect.TypeOf((*MockMeshCatalogDebugger)(nil).ListSMIPolicies))
 }
 
+// MockPolicyReporter is a mock of PolicyReporter interface
+type MockPolicyReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyReporterMockRecorder
+}
+
+// MockPolicyReporterMockRecorder is the mock recorder for MockPolicyReporter
+type MockPolicyReporterMockRecorder struct {
+	mock *MockPolicyReporter
+}
+
+// NewMockPolicyReporter creates a new mock instance
+func NewMockPolicyReporter(ctrl *gomock.Controller) *MockPolicyReporter {
+	mock := &MockPolicyReporter{ctrl: ctrl}
+	mock.recorder = &MockPolicyReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPolicyReporter) EXPECT() *MockPolicyReporterMockRecorder {
+	return m.recorder
+}
+
+// GetPolicyReport mocks base method
+func (m *MockPolicyReporter) GetPolicyReport() *policyreport.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyReport")
+	ret0, _ := ret[0].(*policyreport.Report)
+	return ret0
+}
+
+// GetPolicyReport indicates an expected call of GetPolicyReport
+func (mr *MockPolicyReporterMockRecorder) GetPolicyReport() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyReport", reflect.TypeOf((*MockPolicyReporter)(nil).GetPolicyReport))
+}
+
 // MockXDSDebugger is a mock of XDSDebugger interface
 type MockXDSDebugger struct {
 	ctrl     *gomock.Controller
@@ -186,3 +238,31 @@ func (mr *MockXDSDebuggerMockRecorder) GetXDSLog() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetXDSLog", reflect.TypeOf((*MockXDSDebugger)(nil).GetXDSLog))
 }
+
+// ListRBACDenials mocks base method
+func (m *MockXDSDebugger) ListRBACDenials() []envoy.RBACDenial {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRBACDenials")
+	ret0, _ := ret[0].([]envoy.RBACDenial)
+	return ret0
+}
+
+// ListRBACDenials indicates an expected call of ListRBACDenials
+func (mr *MockXDSDebuggerMockRecorder) ListRBACDenials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRBACDenials", reflect.TypeOf((*MockXDSDebugger)(nil).ListRBACDenials))
+}
+
+// ListObservedRequests mocks base method
+func (m *MockXDSDebugger) ListObservedRequests() []envoy.ObservedRequest {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObservedRequests")
+	ret0, _ := ret[0].([]envoy.ObservedRequest)
+	return ret0
+}
+
+// ListObservedRequests indicates an expected call of ListObservedRequests
+func (mr *MockXDSDebuggerMockRecorder) ListObservedRequests() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObservedRequests", reflect.TypeOf((*MockXDSDebugger)(nil).ListObservedRequests))
+}
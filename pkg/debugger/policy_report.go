@@ -0,0 +1,23 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (ds DebugConfig) getPolicyReportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := ds.policyReporter.GetPolicyReport()
+
+		jsonReport, err := json.Marshal(report)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling policy report %+v", report)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonReport))
+	})
+}
@@ -0,0 +1,49 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/policyreport"
+)
+
+// Tests getPolicyReportHandler through HTTP handler returns the policy report in JSON
+func TestPolicyReportHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mock := NewMockPolicyReporter(mockCtrl)
+	mock.EXPECT().GetPolicyReport().Return(&policyreport.Report{
+		GeneratedAt: time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Window:      "168h0m0s",
+		UnusedPolicies: []policyreport.Policy{
+			{Kind: "TrafficTarget", Namespace: "bookstore", Name: "bookbuyer-access-bookstore"},
+		},
+		InvalidReferences: []policyreport.InvalidReference{
+			{
+				Policy:        policyreport.Policy{Kind: "TrafficSplit", Namespace: "bookstore", Name: "bookstore-split"},
+				ReferenceKind: "Service",
+				Reference:     "bookstore/bookstore-v3",
+				Reason:        "not found",
+			},
+		},
+	}).Times(1)
+
+	ds := DebugConfig{
+		policyReporter: mock,
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getPolicyReportHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/policy-report", nil))
+	assert.Equal(200, responseRecorder.Code)
+	assert.Equal("application/json", responseRecorder.Header().Get("Content-Type"))
+	assert.Equal(`{"generated_at":"2021-03-01T00:00:00Z","window":"168h0m0s",`+
+		`"unused_policies":[{"kind":"TrafficTarget","namespace":"bookstore","name":"bookbuyer-access-bookstore"}],`+
+		`"invalid_references":[{"kind":"TrafficSplit","namespace":"bookstore","name":"bookstore-split","reference_kind":"Service","reference":"bookstore/bookstore-v3","reason":"not found"}]}`,
+		responseRecorder.Body.String())
+}
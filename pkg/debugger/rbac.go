@@ -0,0 +1,88 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// defaultTopRBACDenials is the default number of denied principals and denials listed by the RBAC denials handler
+const defaultTopRBACDenials = 10
+
+type deniedPrincipal struct {
+	SourceIdentity string `json:"source_identity"`
+	Count          uint64 `json:"count"`
+}
+
+type rbacDenials struct {
+	TopDeniedPrincipals []deniedPrincipal  `json:"top_denied_principals"`
+	Denials             []envoy.RBACDenial `json:"denials"`
+}
+
+func (ds DebugConfig) getRBACDenialsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		top := defaultTopRBACDenials
+		if topStr := r.URL.Query().Get("top"); topStr != "" {
+			var err error
+			if top, err = strconv.Atoi(topStr); err != nil || top <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid value %q for top, must be a positive integer", topStr), http.StatusBadRequest)
+				return
+			}
+		}
+
+		// The requests that would be denied by TrafficTargets in shadow mode are listed instead of the denied requests
+		// when shadow is set
+		shadow := false
+		if shadowStr := r.URL.Query().Get("shadow"); shadowStr != "" {
+			var err error
+			if shadow, err = strconv.ParseBool(shadowStr); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid value %q for shadow, must be a boolean", shadowStr), http.StatusBadRequest)
+				return
+			}
+		}
+
+		var denials []envoy.RBACDenial
+		for _, denial := range ds.xdsDebugger.ListRBACDenials() {
+			if denial.Shadow == shadow {
+				denials = append(denials, denial)
+			}
+		}
+
+		countByPrincipal := make(map[string]uint64)
+		for _, denial := range denials {
+			countByPrincipal[denial.SourceIdentity] += denial.Count
+		}
+		var principals []deniedPrincipal
+		for source, count := range countByPrincipal {
+			principals = append(principals, deniedPrincipal{SourceIdentity: source, Count: count})
+		}
+		sort.Slice(principals, func(i, j int) bool {
+			if principals[i].Count != principals[j].Count {
+				return principals[i].Count > principals[j].Count
+			}
+			return principals[i].SourceIdentity < principals[j].SourceIdentity
+		})
+
+		d := rbacDenials{
+			TopDeniedPrincipals: principals,
+			Denials:             denials,
+		}
+		if len(d.TopDeniedPrincipals) > top {
+			d.TopDeniedPrincipals = d.TopDeniedPrincipals[:top]
+		}
+		if len(d.Denials) > top {
+			d.Denials = d.Denials[:top]
+		}
+
+		jsonDenials, err := json.Marshal(d)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling RBAC denials %+v", d)
+		}
+
+		_, _ = fmt.Fprint(w, string(jsonDenials))
+	})
+}
@@ -0,0 +1,84 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getRBACDenialsHandler through HTTP handler returns the top denied principals and denials
+func TestRBACDenialsHandler(t *testing.T) {
+	lastDenied := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	denials := []envoy.RBACDenial{
+		{SourceIdentity: "bookthief.default.cluster.local", DestinationIdentity: "bookstore.default.cluster.local", Route: "bookstore|GET|/books-bought", Count: 5, LastDenied: lastDenied},
+		{SourceIdentity: "bookbuyer.default.cluster.local", DestinationIdentity: "bookstore.default.cluster.local", Route: "bookstore|POST|/buy", Count: 3, LastDenied: lastDenied},
+		{SourceIdentity: "bookthief.default.cluster.local", DestinationIdentity: "bookwarehouse.default.cluster.local", Route: "bookwarehouse|GET|/", Count: 1, LastDenied: lastDenied},
+		{SourceIdentity: "bookbuyer.default.cluster.local", DestinationIdentity: "bookwarehouse.default.cluster.local", Route: "bookwarehouse|GET|/", Count: 2, LastDenied: lastDenied, Shadow: true},
+	}
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		expectedCode         int
+		expectedResponseBody string
+	}{
+		{
+			name:         "all denials",
+			url:          "/debug/rbac-denials",
+			expectedCode: 200,
+			expectedResponseBody: `{"top_denied_principals":[{"source_identity":"bookthief.default.cluster.local","count":6},{"source_identity":"bookbuyer.default.cluster.local","count":3}],` +
+				`"denials":[{"source_identity":"bookthief.default.cluster.local","destination_identity":"bookstore.default.cluster.local","route":"bookstore|GET|/books-bought","count":5,"last_denied":"2021-03-01T00:00:00Z"},` +
+				`{"source_identity":"bookbuyer.default.cluster.local","destination_identity":"bookstore.default.cluster.local","route":"bookstore|POST|/buy","count":3,"last_denied":"2021-03-01T00:00:00Z"},` +
+				`{"source_identity":"bookthief.default.cluster.local","destination_identity":"bookwarehouse.default.cluster.local","route":"bookwarehouse|GET|/","count":1,"last_denied":"2021-03-01T00:00:00Z"}]}`,
+		},
+		{
+			name:         "top denial",
+			url:          "/debug/rbac-denials?top=1",
+			expectedCode: 200,
+			expectedResponseBody: `{"top_denied_principals":[{"source_identity":"bookthief.default.cluster.local","count":6}],` +
+				`"denials":[{"source_identity":"bookthief.default.cluster.local","destination_identity":"bookstore.default.cluster.local","route":"bookstore|GET|/books-bought","count":5,"last_denied":"2021-03-01T00:00:00Z"}]}`,
+		},
+		{
+			name:         "shadow denials",
+			url:          "/debug/rbac-denials?shadow=true",
+			expectedCode: 200,
+			expectedResponseBody: `{"top_denied_principals":[{"source_identity":"bookbuyer.default.cluster.local","count":2}],` +
+				`"denials":[{"source_identity":"bookbuyer.default.cluster.local","destination_identity":"bookwarehouse.default.cluster.local","route":"bookwarehouse|GET|/","count":2,"last_denied":"2021-03-01T00:00:00Z","shadow":true}]}`,
+		},
+		{
+			name:                 "invalid shadow",
+			url:                  "/debug/rbac-denials?shadow=maybe",
+			expectedCode:         400,
+			expectedResponseBody: "Invalid value \"maybe\" for shadow, must be a boolean\n",
+		},
+		{
+			name:                 "invalid top",
+			url:                  "/debug/rbac-denials?top=none",
+			expectedCode:         400,
+			expectedResponseBody: "Invalid value \"none\" for top, must be a positive integer\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mock := NewMockXDSDebugger(mockCtrl)
+			mock.EXPECT().ListRBACDenials().Return(denials).AnyTimes()
+
+			ds := DebugConfig{
+				xdsDebugger: mock,
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			ds.getRBACDenialsHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", tc.url, nil))
+			assert.Equal(tc.expectedCode, responseRecorder.Code)
+			assert.Equal(tc.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
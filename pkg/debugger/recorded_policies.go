@@ -0,0 +1,24 @@
+package debugger
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/policyrecorder"
+)
+
+func (ds DebugConfig) getRecordedPoliciesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policies := policyrecorder.GeneratePolicies(ds.xdsDebugger.ListObservedRequests())
+
+		policiesYAML, err := policyrecorder.MarshalPolicies(policies)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling recorded policies %+v", policies)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = fmt.Fprint(w, string(policiesYAML))
+	})
+}
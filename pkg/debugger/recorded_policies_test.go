@@ -0,0 +1,41 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getRecordedPoliciesHandler through HTTP handler returns the policies generated from the observed requests in YAML
+func TestRecordedPoliciesHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mock := NewMockXDSDebugger(mockCtrl)
+	mock.EXPECT().ListObservedRequests().Return([]envoy.ObservedRequest{
+		{
+			SourceIdentity:      "bookbuyer.bookbuyer.cluster.local",
+			DestinationIdentity: "bookstore.bookstore.cluster.local",
+			Method:              "GET",
+			Path:                "/books-bought",
+			Count:               1,
+		},
+	}).Times(1)
+
+	ds := DebugConfig{
+		xdsDebugger: mock,
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getRecordedPoliciesHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/recorded-policies", nil))
+	assert.Equal(200, responseRecorder.Code)
+	assert.Equal("application/yaml", responseRecorder.Header().Get("Content-Type"))
+	assert.Contains(responseRecorder.Body.String(), "kind: HTTPRouteGroup\n")
+	assert.Contains(responseRecorder.Body.String(), "---\n")
+	assert.Contains(responseRecorder.Body.String(), "name: bookbuyer-bookbuyer-access-bookstore\n")
+}
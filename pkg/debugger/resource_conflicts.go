@@ -0,0 +1,29 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func (ds DebugConfig) getResourceConflictsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conflicts := []envoy.ResourceConflict{}
+		for _, proxy := range ds.meshCatalogDebugger.ListConnectedProxies() {
+			conflicts = append(conflicts, proxy.GetResourceConflicts()...)
+		}
+		sort.SliceStable(conflicts, func(i, j int) bool {
+			return conflicts[i].Proxy < conflicts[j].Proxy
+		})
+
+		jsonConflicts, err := json.Marshal(conflicts)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling resource conflicts %+v", conflicts)
+		}
+
+		_, _ = fmt.Fprint(w, string(jsonConflicts))
+	})
+}
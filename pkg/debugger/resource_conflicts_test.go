@@ -0,0 +1,50 @@
+package debugger
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getResourceConflictsHandler through HTTP handler returns the resource conflicts of the connected proxies
+func TestResourceConflictsHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	conflicting := envoy.NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+	envoy.RemoveConflictingResources(conflicting, envoy.ClusterResourceKind, []envoy.NamedResource{
+		{Name: "bookstore/bookstore", Source: "upstream service bookstore/bookstore"},
+		{Name: "bookstore/bookstore", Source: "local service bookstore/bookstore"},
+	})
+	healthy := envoy.NewProxy(certificate.CommonName("bookstore"), certificate.SerialNumber("2"), nil)
+
+	mock := NewMockMeshCatalogDebugger(mockCtrl)
+	mock.EXPECT().ListConnectedProxies().Return(map[certificate.CommonName]*envoy.Proxy{
+		"bookbuyer": conflicting,
+		"bookstore": healthy,
+	}).Times(1)
+
+	ds := DebugConfig{
+		meshCatalogDebugger: mock,
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getResourceConflictsHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/resource-conflicts", nil))
+	assert.Equal(200, responseRecorder.Code)
+
+	var conflicts []envoy.ResourceConflict
+	assert.Nil(json.Unmarshal(responseRecorder.Body.Bytes(), &conflicts))
+	assert.Len(conflicts, 1)
+	assert.Equal(certificate.CommonName("bookbuyer"), conflicts[0].Proxy)
+	assert.Equal(envoy.ClusterResourceKind, conflicts[0].Kind)
+	assert.Equal("bookstore/bookstore", conflicts[0].Name)
+	assert.Equal("local service bookstore/bookstore", conflicts[0].KeptSource)
+	assert.Equal([]string{"upstream service bookstore/bookstore"}, conflicts[0].DroppedSources)
+}
@@ -0,0 +1,63 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// maxPendingProxies is the number of proxies yet to apply a configuration generation listed by the rollout handler
+const maxPendingProxies = 10
+
+// ConfigGenerationRollout is the rollout progress of a configuration generation to the connected proxies
+type ConfigGenerationRollout struct {
+	envoy.ConfigGeneration
+
+	// AppliedProxies is the number of connected proxies that acknowledged the generation or a later generation
+	AppliedProxies int `json:"applied_proxies"`
+
+	// TotalProxies is the number of connected proxies
+	TotalProxies int `json:"total_proxies"`
+
+	// PendingProxies lists the certificate common names of connected proxies yet to apply the generation, up to
+	// maxPendingProxies
+	PendingProxies []string `json:"pending_proxies,omitempty"`
+}
+
+func (ds DebugConfig) getRolloutHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxies := ds.meshCatalogDebugger.ListConnectedProxies()
+		var commonNames []string
+		for cn := range proxies {
+			commonNames = append(commonNames, cn.String())
+		}
+		sort.Strings(commonNames)
+
+		var rollouts []ConfigGenerationRollout
+		for _, generation := range ds.meshCatalogDebugger.ListConfigGenerations() {
+			rollout := ConfigGenerationRollout{
+				ConfigGeneration: generation,
+				TotalProxies:     len(commonNames),
+			}
+			for _, cn := range commonNames {
+				if proxies[certificate.CommonName(cn)].GetAppliedConfigGeneration() >= generation.ID {
+					rollout.AppliedProxies++
+				} else if len(rollout.PendingProxies) < maxPendingProxies {
+					rollout.PendingProxies = append(rollout.PendingProxies, cn)
+				}
+			}
+			rollouts = append(rollouts, rollout)
+		}
+
+		jsonRollouts, err := json.Marshal(rollouts)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshalling configuration rollouts %+v", rollouts)
+		}
+
+		_, _ = fmt.Fprint(w, string(jsonRollouts))
+	})
+}
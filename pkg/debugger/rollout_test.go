@@ -0,0 +1,47 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// Tests getRolloutHandler through HTTP handler returns the rollout progress of each configuration generation
+func TestRolloutHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	createdAt := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	upToDate := envoy.NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+	upToDate.SetPendingConfigGeneration(envoy.ConfigGeneration{ID: 2})
+	outdated := envoy.NewProxy(certificate.CommonName("bookstore"), certificate.SerialNumber("2"), nil)
+	outdated.SetPendingConfigGeneration(envoy.ConfigGeneration{ID: 1})
+
+	mock := NewMockMeshCatalogDebugger(mockCtrl)
+	mock.EXPECT().ListConnectedProxies().Return(map[certificate.CommonName]*envoy.Proxy{
+		"bookbuyer": upToDate,
+		"bookstore": outdated,
+	}).Times(1)
+	mock.EXPECT().ListConfigGenerations().Return([]envoy.ConfigGeneration{
+		{ID: 1, CreatedAt: createdAt, Changes: []string{"service-added bookstore/bookstore"}},
+		{ID: 2, CreatedAt: createdAt, Changes: []string{"traffictarget-updated bookstore/bookstore"}},
+	}).Times(1)
+
+	ds := DebugConfig{
+		meshCatalogDebugger: mock,
+	}
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getRolloutHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/debug/rollout", nil))
+	assert.Equal(200, responseRecorder.Code)
+	assert.Equal(`[{"id":1,"created_at":"2021-03-01T00:00:00Z","changes":["service-added bookstore/bookstore"],"applied_proxies":2,"total_proxies":2},`+
+		`{"id":2,"created_at":"2021-03-01T00:00:00Z","changes":["traffictarget-updated bookstore/bookstore"],"applied_proxies":1,"total_proxies":2,"pending_proxies":["bookstore"]}]`,
+		responseRecorder.Body.String())
+}
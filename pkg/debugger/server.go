@@ -1,6 +1,7 @@
 package debugger
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/pprof"
 
@@ -14,13 +15,20 @@ import (
 // GetHandlers implements DebugConfig interface and returns the rest of URLs and the handling functions.
 func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 	handlers := map[string]http.Handler{
-		"/debug/certs":         ds.getCertHandler(),
-		"/debug/xds":           ds.getXDSHandler(),
-		"/debug/proxy":         ds.getProxies(),
-		"/debug/policies":      ds.getSMIPoliciesHandler(),
-		"/debug/config":        ds.getOSMConfigHandler(),
-		"/debug/namespaces":    ds.getMonitoredNamespacesHandler(),
-		"/debug/feature-flags": ds.getFeatureFlags(),
+		"/debug/certs":              ds.getCertHandler(),
+		"/debug/xds":                ds.getXDSHandler(),
+		"/debug/proxy":              ds.getProxies(),
+		"/debug/policies":           ds.getSMIPoliciesHandler(),
+		"/debug/config":             ds.getOSMConfigHandler(),
+		"/debug/namespaces":         ds.getMonitoredNamespacesHandler(),
+		"/debug/feature-flags":      ds.getFeatureFlags(),
+		"/debug/rbac-denials":       ds.getRBACDenialsHandler(),
+		"/debug/policy-report":      ds.getPolicyReportHandler(),
+		"/debug/recorded-policies":  ds.getRecordedPoliciesHandler(),
+		"/debug/rollout":            ds.getRolloutHandler(),
+		"/debug/resource-conflicts": ds.getResourceConflictsHandler(),
+		"/debug/bounded-names":      ds.getBoundedNamesHandler(),
+		"/debug/lastpush/":          ds.getLastPushHandler(),
 
 		// Pprof handlers
 		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
@@ -37,11 +45,13 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 }
 
 // NewDebugConfig returns an implementation of DebugConfig interface.
-func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebugger, meshCatalogDebugger MeshCatalogDebugger, kubeConfig *rest.Config, kubeClient kubernetes.Interface, cfg configurator.Configurator, kubeController k8s.Controller) DebugConfig {
+// The debug server requires mTLS with the given TLS configuration when control plane mTLS is enabled.
+func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebugger, meshCatalogDebugger MeshCatalogDebugger, policyReporter PolicyReporter, kubeConfig *rest.Config, kubeClient kubernetes.Interface, cfg configurator.Configurator, kubeController k8s.Controller, tlsConfig *tls.Config) DebugConfig {
 	return DebugConfig{
 		certDebugger:        certDebugger,
 		xdsDebugger:         xdsDebugger,
 		meshCatalogDebugger: meshCatalogDebugger,
+		policyReporter:      policyReporter,
 		kubeClient:          kubeClient,
 		kubeController:      kubeController,
 
@@ -49,5 +59,6 @@ func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebu
 		kubeConfig: kubeConfig,
 
 		configurator: cfg,
+		tlsConfig:    tlsConfig,
 	}
 }
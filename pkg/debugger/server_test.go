@@ -19,6 +19,7 @@ func TestGetHandlers(t *testing.T) {
 	mockCertDebugger := NewMockCertificateManagerDebugger(mockCtrl)
 	mockXdsDebugger := NewMockXDSDebugger(mockCtrl)
 	mockCatalogDebugger := NewMockMeshCatalogDebugger(mockCtrl)
+	mockPolicyReporter := NewMockPolicyReporter(mockCtrl)
 	mockConfig := configurator.NewMockConfigurator(mockCtrl)
 	client := testclient.NewSimpleClientset()
 	mockKubeController := k8s.NewMockController(mockCtrl)
@@ -26,10 +27,12 @@ func TestGetHandlers(t *testing.T) {
 	ds := NewDebugConfig(mockCertDebugger,
 		mockXdsDebugger,
 		mockCatalogDebugger,
+		mockPolicyReporter,
 		nil,
 		client,
 		mockConfig,
-		mockKubeController)
+		mockKubeController,
+		nil)
 
 	handlers := ds.GetHandlers()
 
@@ -40,6 +43,13 @@ func TestGetHandlers(t *testing.T) {
 		"/debug/policies",
 		"/debug/config",
 		"/debug/namespaces",
+		"/debug/rbac-denials",
+		"/debug/policy-report",
+		"/debug/recorded-policies",
+		"/debug/rollout",
+		"/debug/resource-conflicts",
+		"/debug/bounded-names",
+		"/debug/lastpush/",
 		// Pprof handlers
 		"/debug/pprof/",
 		"/debug/pprof/cmdline",
@@ -2,6 +2,7 @@
 package debugger
 
 import (
+	"crypto/tls"
 	"time"
 
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
@@ -15,6 +16,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/policyreport"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
@@ -25,10 +27,12 @@ type DebugConfig struct {
 	certDebugger        CertificateManagerDebugger
 	xdsDebugger         XDSDebugger
 	meshCatalogDebugger MeshCatalogDebugger
+	policyReporter      PolicyReporter
 	kubeConfig          *rest.Config
 	kubeClient          kubernetes.Interface
 	kubeController      k8s.Controller
 	configurator        configurator.Configurator
+	tlsConfig           *tls.Config
 }
 
 // CertificateManagerDebugger is an interface with methods for debugging certificate issuance.
@@ -53,10 +57,25 @@ type MeshCatalogDebugger interface {
 
 	// ListMonitoredNamespaces lists the namespaces that the control plan knows about.
 	ListMonitoredNamespaces() []string
+
+	// ListConfigGenerations lists the most recent configuration generations broadcast to the proxies, the oldest first.
+	ListConfigGenerations() []envoy.ConfigGeneration
+}
+
+// PolicyReporter is an interface providing debugging server with reports of the SMI policies that can be cleaned up.
+type PolicyReporter interface {
+	// GetPolicyReport returns a report of the unused SMI policies and of the SMI policies referencing resources that do not exist.
+	GetPolicyReport() *policyreport.Report
 }
 
 // XDSDebugger is an interface providing debugging server with methods introspecting XDS.
 type XDSDebugger interface {
 	// GetXDSLog returns a log of the XDS responses sent to Envoy proxies.
 	GetXDSLog() *map[certificate.CommonName]map[envoy.TypeURI][]time.Time
+
+	// ListRBACDenials returns the records of the requests denied by the RBAC policies of proxies, the most denied first.
+	ListRBACDenials() []envoy.RBACDenial
+
+	// ListObservedRequests returns the records of the requests observed by proxies in permissive traffic policy mode.
+	ListObservedRequests() []envoy.ObservedRequest
 }
@@ -0,0 +1,264 @@
+package ebpf
+
+import (
+	"bytes"
+	"net"
+	"time"
+
+	bpf "github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// passthroughPorts are the inbound ports of the meshed pods excluded from the redirection to the inbound listener of
+// their sidecar proxy by their iptables rules
+var passthroughPorts = []uint32{
+	constants.EnvoyPrometheusInboundListenerPort,
+	constants.EnvoyLivenessProbePort,
+	constants.EnvoyReadinessProbePort,
+	constants.EnvoyStartupProbePort,
+}
+
+// NewAccelerator loads the eBPF programs accelerating the local hops of the traffic of the meshed pods running on the
+// given node, whose cgroups are found in the cgroup v2 hierarchy mounted at the given root. The outbound connections of
+// the applications of the pods are also redirected to their sidecar proxy if redirect is set. It returns an error
+// wrapping ErrNotSupported if the node does not support the acceleration.
+func NewAccelerator(kubeClient kubernetes.Interface, nodeName string, cgroupRoot string, redirect bool, stop <-chan struct{}) (*Accelerator, error) {
+	if err := CheckSupport(cgroupRoot); err != nil {
+		return nil, err
+	}
+	if len(sockmapObjectBytes) == 0 {
+		return nil, errors.Wrap(ErrNotSupported, "The eBPF programs were not embedded at build time")
+	}
+
+	spec, err := bpf.LoadCollectionSpecFromReader(bytes.NewReader([]byte(sockmapObjectBytes)))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing the eBPF programs")
+	}
+	collection, err := bpf.NewCollection(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading the eBPF programs")
+	}
+
+	a := &Accelerator{
+		nodeName:   nodeName,
+		cgroupRoot: cgroupRoot,
+		collection: collection,
+		sockops:    collection.Programs[sockopsProgramName],
+		meshedPods: collection.Maps[meshedPodsMapName],
+		pods:       make(map[types.UID]*acceleratedPod),
+	}
+	if err := a.init(); err != nil {
+		collection.Close()
+		return nil, err
+	}
+	if redirect {
+		// The outbound connections keep being redirected by iptables if the redirect programs cannot be loaded
+		if a.redirect, err = newRedirector(); err != nil {
+			log.Error().Err(err).Msgf("Error loading the eBPF redirect programs on node %s, the outbound connections of its meshed pods are redirected by iptables", nodeName)
+		}
+	}
+
+	// Only watch the meshed pods scheduled on this node
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+			listOptions.LabelSelector = constants.EnvoyUniqueIDLabelName
+		}))
+	informer := informerFactory.Core().V1().Pods().Informer()
+	go informer.Run(stop)
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		a.close()
+		return nil, errors.Errorf("Failed to sync the pods of node %s", nodeName)
+	}
+	a.podStore = informer.GetStore()
+
+	return a, nil
+}
+
+// init writes the settings and passthrough ports of the programs, and attaches the sk_msg program to the sockhash map
+func (a *Accelerator) init() error {
+	if a.sockops == nil || a.meshedPods == nil {
+		return errors.Errorf("Program %s or map %s missing from the eBPF programs", sockopsProgramName, meshedPodsMapName)
+	}
+	skMsg := a.collection.Programs[skMsgProgramName]
+	sockHash := a.collection.Maps[sockHashMapName]
+	passthrough := a.collection.Maps[passthroughPortsMapName]
+	settings := a.collection.Maps[settingsMapName]
+	if skMsg == nil || sockHash == nil || passthrough == nil || settings == nil {
+		return errors.New("Programs or maps missing from the eBPF programs")
+	}
+
+	for _, port := range passthroughPorts {
+		if err := passthrough.Put(port, uint32(1)); err != nil {
+			return errors.Wrapf(err, "Error writing passthrough port %d to map %s", port, passthroughPortsMapName)
+		}
+	}
+	if err := settings.Put(uint32(0), uint32(constants.EnvoyInboundListenerPort)); err != nil {
+		return errors.Wrapf(err, "Error writing the settings to map %s", settingsMapName)
+	}
+
+	// The sk_msg program is detached when the sockhash map is released, once the accelerator exits
+	err := link.RawAttachProgram(link.RawAttachProgramOptions{
+		Target:  sockHash.FD(),
+		Program: skMsg,
+		Attach:  bpf.AttachSkMsgVerdict,
+	})
+	return errors.Wrapf(err, "Error attaching program %s to map %s", skMsgProgramName, sockHashMapName)
+}
+
+// Start attaches the eBPF programs to the cgroups of the meshed pods of the node at the reconcile interval, until the
+// stop channel is closed. The programs are then detached from all the pods and unloaded.
+func (a *Accelerator) Start(stop <-chan struct{}) {
+	metricsstore.DefaultMetricsStore.EBPFAccelerationEnabled.Set(1)
+	go func() {
+		ticker := time.NewTicker(reconcileInterval)
+		defer ticker.Stop()
+
+		a.reconcile()
+		for {
+			select {
+			case <-stop:
+				a.close()
+				return
+			case <-ticker.C:
+				a.reconcile()
+			}
+		}
+	}()
+}
+
+// reconcile records the IP addresses of the running meshed pods of the node and attaches the sockops program to their
+// cgroups, and forgets the pods that are no longer running
+func (a *Accelerator) reconcile() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	running := make(map[types.UID]bool)
+	for _, obj := range a.podStore.List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		ip := net.ParseIP(pod.Status.PodIP).To4()
+		if ip == nil {
+			// Pods without an IPv4 address are not accelerated
+			continue
+		}
+		running[pod.UID] = true
+
+		p, ok := a.pods[pod.UID]
+		if !ok {
+			p = &acceleratedPod{}
+			copy(p.ip[:], ip)
+			if err := a.meshedPods.Put(p.ip, uint32(1)); err != nil {
+				log.Error().Err(err).Msgf("Error recording the IP address of pod %s/%s", pod.Namespace, pod.Name)
+				continue
+			}
+			a.pods[pod.UID] = p
+		}
+		if p.sockopsLink == nil {
+			a.attach(pod, p)
+		}
+	}
+
+	for uid, p := range a.pods {
+		if !running[uid] {
+			a.forget(uid, p)
+		}
+	}
+
+	var accelerated, redirected int
+	for _, p := range a.pods {
+		if p.sockopsLink != nil {
+			accelerated++
+		}
+		if p.redirectLinks != nil {
+			redirected++
+		}
+	}
+	metricsstore.DefaultMetricsStore.EBPFAcceleratedPodCount.Set(float64(accelerated))
+	metricsstore.DefaultMetricsStore.EBPFRedirectedPodCount.Set(float64(redirected))
+}
+
+// attach attaches the programs to the cgroup of the given pod, if found. The outbound connections of pods that cannot
+// be redirected are left to their iptables rules.
+func (a *Accelerator) attach(pod *corev1.Pod, p *acceleratedPod) {
+	cgroup, err := findPodCgroup(a.cgroupRoot, pod.UID)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Error finding the cgroup of pod %s/%s, retrying in %s", pod.Namespace, pod.Name, reconcileInterval)
+		return
+	}
+	if p.cgroupID, err = getCgroupID(cgroup); err != nil {
+		log.Warn().Err(err).Msgf("Error finding the cgroup of pod %s/%s, retrying in %s", pod.Namespace, pod.Name, reconcileInterval)
+		return
+	}
+	p.sockopsLink, err = link.AttachCgroup(link.CgroupOptions{
+		Path:    cgroup,
+		Attach:  bpf.AttachCGroupSockOps,
+		Program: a.sockops,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error attaching program %s to the cgroup of pod %s/%s", sockopsProgramName, pod.Namespace, pod.Name)
+		return
+	}
+	log.Info().Msgf("Accelerating pod %s/%s", pod.Namespace, pod.Name)
+
+	if a.redirect == nil {
+		return
+	}
+	proxyUID, ok := getRedirectProxyUID(pod)
+	if !ok {
+		log.Info().Msgf("Outbound connections of pod %s/%s are redirected by iptables: the pod has outbound IP range exclusions or its proxy has no user ID", pod.Namespace, pod.Name)
+		return
+	}
+	if p.redirectLinks, err = a.redirect.attach(cgroup, p.cgroupID, proxyUID); err != nil {
+		log.Error().Err(err).Msgf("Error redirecting the outbound connections of pod %s/%s, they are redirected by iptables", pod.Namespace, pod.Name)
+		return
+	}
+	log.Info().Msgf("Redirecting the outbound connections of pod %s/%s", pod.Namespace, pod.Name)
+}
+
+// forget detaches the programs from the cgroup of the given pod and removes its IP address from the meshed pods
+func (a *Accelerator) forget(uid types.UID, p *acceleratedPod) {
+	if p.redirectLinks != nil {
+		a.redirect.detach(p.cgroupID, p.redirectLinks)
+	}
+	if p.sockopsLink != nil {
+		if err := p.sockopsLink.Close(); err != nil {
+			log.Error().Err(err).Msgf("Error detaching program %s from the cgroup of pod with UID %s", sockopsProgramName, uid)
+		}
+	}
+	if err := a.meshedPods.Delete(p.ip); err != nil && !errors.Is(err, bpf.ErrKeyNotExist) {
+		log.Error().Err(err).Msgf("Error removing the IP address of pod with UID %s", uid)
+	}
+	delete(a.pods, uid)
+}
+
+// close detaches the eBPF programs from all the pods and unloads them
+func (a *Accelerator) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for uid, p := range a.pods {
+		a.forget(uid, p)
+	}
+	a.collection.Close()
+	if a.redirect != nil {
+		a.redirect.close()
+	}
+	metricsstore.DefaultMetricsStore.EBPFAcceleratedPodCount.Set(0)
+	metricsstore.DefaultMetricsStore.EBPFRedirectedPodCount.Set(0)
+	metricsstore.DefaultMetricsStore.EBPFAccelerationEnabled.Set(0)
+}
@@ -0,0 +1,49 @@
+package ebpf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxPodCgroupDepth is the maximum depth of the cgroups of pods below the root of the cgroup v2 hierarchy, such as
+// kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-besteffort.slice/kubelet-kubepods-besteffort-pod<uid>.slice
+const maxPodCgroupDepth = 4
+
+// findPodCgroup returns the path of the cgroup of the pod with the given UID in the cgroup v2 hierarchy mounted at the
+// given root. The kubelet names the cgroup of a pod after its UID: pod<uid> with the cgroupfs cgroup driver, and
+// kubepods-<qos class>-pod<uid>.slice with the systemd cgroup driver, with the dashes of the UID replaced by
+// underscores. Only the cgroups of the kubelet are searched, whose names contain kubepods or kubelet, or are the QoS
+// classes of the pods with the cgroupfs cgroup driver.
+func findPodCgroup(root string, uid types.UID) (string, error) {
+	cgroupfsName := "pod" + string(uid)
+	systemdSuffix := "-pod" + strings.ReplaceAll(string(uid), "-", "_") + ".slice"
+
+	dirs := []string{root}
+	for depth := 0; depth < maxPodCgroupDepth && len(dirs) > 0; depth++ {
+		var subdirs []string
+		for _, dir := range dirs {
+			entries, err := ioutil.ReadDir(dir)
+			if err != nil {
+				return "", errors.Wrapf(err, "Error reading cgroup %s", dir)
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				name := entry.Name()
+				if name == cgroupfsName || strings.HasSuffix(name, systemdSuffix) {
+					return filepath.Join(dir, name), nil
+				}
+				if strings.Contains(name, "kubepods") || strings.Contains(name, "kubelet") || name == "besteffort" || name == "burstable" {
+					subdirs = append(subdirs, filepath.Join(dir, name))
+				}
+			}
+		}
+		dirs = subdirs
+	}
+	return "", errors.Errorf("Cgroup of pod with UID %s not found in %s", uid, root)
+}
@@ -0,0 +1,73 @@
+package ebpf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFindPodCgroup(t *testing.T) {
+	const uid types.UID = "8e2f8e62-5e0c-4ee4-a6b4-a0b4f2a4b4f2"
+
+	testCases := []struct {
+		name         string
+		dirs         []string
+		expectedPath string
+		expectErr    bool
+	}{
+		{
+			name: "cgroupfs cgroup driver",
+			dirs: []string{
+				"kubepods/besteffort/pod8e2f8e62-5e0c-4ee4-a6b4-a0b4f2a4b4f2/0123456789abcdef",
+				"kubepods/burstable/pod11111111-2222-3333-4444-555555555555",
+			},
+			expectedPath: "kubepods/besteffort/pod8e2f8e62-5e0c-4ee4-a6b4-a0b4f2a4b4f2",
+		},
+		{
+			name: "systemd cgroup driver",
+			dirs: []string{
+				"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8e2f8e62_5e0c_4ee4_a6b4_a0b4f2a4b4f2.slice/cri-containerd-0123456789abcdef.scope",
+				"system.slice/containerd.service",
+			},
+			expectedPath: "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8e2f8e62_5e0c_4ee4_a6b4_a0b4f2a4b4f2.slice",
+		},
+		{
+			name: "kind node",
+			dirs: []string{
+				"kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-besteffort.slice/kubelet-kubepods-besteffort-pod8e2f8e62_5e0c_4ee4_a6b4_a0b4f2a4b4f2.slice",
+			},
+			expectedPath: "kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-besteffort.slice/kubelet-kubepods-besteffort-pod8e2f8e62_5e0c_4ee4_a6b4_a0b4f2a4b4f2.slice",
+		},
+		{
+			name: "cgroup outside of the cgroups of the kubelet",
+			dirs: []string{
+				"system.slice/pod8e2f8e62-5e0c-4ee4-a6b4-a0b4f2a4b4f2",
+				"kubepods/burstable/pod11111111-2222-3333-4444-555555555555",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			root, err := ioutil.TempDir("", "cgroup")
+			assert.Nil(err)
+			defer os.RemoveAll(root) //nolint: errcheck
+			for _, dir := range tc.dirs {
+				assert.Nil(os.MkdirAll(filepath.Join(root, dir), 0750))
+			}
+
+			path, err := findPodCgroup(root, uid)
+			assert.Equal(tc.expectErr, err != nil)
+			if !tc.expectErr {
+				assert.Equal(filepath.Join(root, tc.expectedPath), path)
+			}
+		})
+	}
+}
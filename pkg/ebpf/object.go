@@ -0,0 +1,28 @@
+package ebpf
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	// sockmapObjectBytes is the ELF object of the eBPF programs built from bpf/sockmap.c, set base64 encoded at build
+	// time
+	sockmapObjectBytes string
+
+	// redirectObjectBytes is the ELF object of the eBPF programs built from bpf/redirect.c, set base64 encoded at build
+	// time
+	redirectObjectBytes string
+)
+
+func init() {
+	sockmapObjectBytes = decodeObject(sockmapObjectBytes)
+	redirectObjectBytes = decodeObject(redirectObjectBytes)
+}
+
+func decodeObject(encoded string) string {
+	b64 := base64.NewDecoder(base64.StdEncoding, strings.NewReader(encoded))
+	b, _ := ioutil.ReadAll(b64)
+	return string(b)
+}
@@ -0,0 +1,154 @@
+package ebpf
+
+import (
+	"bytes"
+	"strings"
+
+	bpf "github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// redirector attaches the eBPF programs redirecting the outbound connections of the applications of the meshed pods to
+// the outbound listener of their sidecar proxy
+type redirector struct {
+	collection *bpf.Collection
+	pods       *bpf.Map
+	programs   []redirectProgram
+}
+
+// redirectProgram is a program of the redirect object attached to the cgroups of the pods
+type redirectProgram struct {
+	name    string
+	program *bpf.Program
+	attach  bpf.AttachType
+}
+
+// redirectPod is the value of the pods map of the redirect object
+type redirectPod struct {
+	ProxyUID uint32
+}
+
+// newRedirector loads the eBPF programs redirecting the outbound connections of the applications of the meshed pods
+func newRedirector() (*redirector, error) {
+	if len(redirectObjectBytes) == 0 {
+		return nil, errors.Wrap(ErrNotSupported, "The eBPF redirect programs were not embedded at build time")
+	}
+
+	spec, err := bpf.LoadCollectionSpecFromReader(bytes.NewReader([]byte(redirectObjectBytes)))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing the eBPF redirect programs")
+	}
+	collection, err := bpf.NewCollection(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading the eBPF redirect programs")
+	}
+
+	r := &redirector{
+		collection: collection,
+		pods:       collection.Maps[redirectPodsMapName],
+		programs: []redirectProgram{
+			{name: connect4ProgramName, program: collection.Programs[connect4ProgramName], attach: bpf.AttachCGroupInet4Connect},
+			{name: redirectSockopsProgramName, program: collection.Programs[redirectSockopsProgramName], attach: bpf.AttachCGroupSockOps},
+			{name: getsockoptProgramName, program: collection.Programs[getsockoptProgramName], attach: bpf.AttachCGroupGetsockopt},
+		},
+	}
+	settings := collection.Maps[redirectSettingsMapName]
+	if r.pods == nil || settings == nil {
+		collection.Close()
+		return nil, errors.New("Maps missing from the eBPF redirect programs")
+	}
+	for _, p := range r.programs {
+		if p.program == nil {
+			collection.Close()
+			return nil, errors.Errorf("Program %s missing from the eBPF redirect programs", p.name)
+		}
+	}
+	if err := settings.Put(uint32(0), uint32(constants.EnvoyOutboundListenerPort)); err != nil {
+		collection.Close()
+		return nil, errors.Wrapf(err, "Error writing the settings to map %s", redirectSettingsMapName)
+	}
+
+	return r, nil
+}
+
+// attach redirects the outbound connections of the applications of the pod with the given cgroup, whose sidecar proxy
+// runs as the given user ID, and returns the attachments of the programs to its cgroup
+func (r *redirector) attach(cgroup string, cgroupID uint64, proxyUID uint32) ([]link.Link, error) {
+	var links []link.Link
+	for _, p := range r.programs {
+		l, err := link.AttachCgroup(link.CgroupOptions{
+			Path:    cgroup,
+			Attach:  p.attach,
+			Program: p.program,
+		})
+		if err != nil {
+			closeLinks(links)
+			return nil, errors.Wrapf(err, "Error attaching program %s to cgroup %s", p.name, cgroup)
+		}
+		links = append(links, l)
+	}
+
+	// The connections of the pod are only redirected once all the programs are attached
+	if err := r.pods.Put(cgroupID, redirectPod{ProxyUID: proxyUID}); err != nil {
+		closeLinks(links)
+		return nil, errors.Wrapf(err, "Error recording cgroup %s in map %s", cgroup, redirectPodsMapName)
+	}
+	return links, nil
+}
+
+// detach stops redirecting the connections of the pod with the given cgroup ID and detaches the given attachments of
+// the programs to its cgroup
+func (r *redirector) detach(cgroupID uint64, links []link.Link) {
+	if err := r.pods.Delete(cgroupID); err != nil && !errors.Is(err, bpf.ErrKeyNotExist) {
+		log.Error().Err(err).Msgf("Error removing cgroup with ID %d from map %s", cgroupID, redirectPodsMapName)
+	}
+	closeLinks(links)
+}
+
+func (r *redirector) close() {
+	r.collection.Close()
+}
+
+func closeLinks(links []link.Link) {
+	for _, l := range links {
+		if err := l.Close(); err != nil {
+			log.Error().Err(err).Msg("Error detaching eBPF program")
+		}
+	}
+}
+
+// getCgroupID returns the ID of the cgroup at the given path, which is the inode number of its directory in the cgroup
+// v2 hierarchy
+func getCgroupID(cgroup string) (uint64, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(cgroup, &stat); err != nil {
+		return 0, errors.Wrapf(err, "Error getting the ID of cgroup %s", cgroup)
+	}
+	return stat.Ino, nil
+}
+
+// getRedirectProxyUID returns the user ID of the sidecar proxy of the given pod, and whether the outbound connections
+// of its applications can be redirected by the eBPF programs. The connections of pods with outbound IP range
+// exclusions are left to their iptables rules, since the programs do not exclude any destination.
+func getRedirectProxyUID(pod *corev1.Pod) (uint32, bool) {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == constants.InitContainerName && strings.Contains(strings.Join(container.Args, " "), "-I PROXY_OUTPUT") {
+			return 0, false
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != constants.EnvoyContainerName {
+			continue
+		}
+		if container.SecurityContext == nil || container.SecurityContext.RunAsUser == nil {
+			return 0, false
+		}
+		return uint32(*container.SecurityContext.RunAsUser), true
+	}
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package ebpf
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetRedirectProxyUID(t *testing.T) {
+	proxyUID := int64(1500)
+
+	testCases := []struct {
+		name             string
+		pod              *corev1.Pod
+		expectedProxyUID uint32
+		expectedOk       bool
+	}{
+		{
+			name: "proxy with a user ID",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: constants.InitContainerName, Args: []string{"-c", "$IPTABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT"}},
+					},
+					Containers: []corev1.Container{
+						{Name: "app"},
+						{Name: constants.EnvoyContainerName, SecurityContext: &corev1.SecurityContext{RunAsUser: &proxyUID}},
+					},
+				},
+			},
+			expectedProxyUID: 1500,
+			expectedOk:       true,
+		},
+		{
+			name: "pod with outbound IP range exclusions",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: constants.InitContainerName, Args: []string{"-c", "$IPTABLES -t nat -I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN"}},
+					},
+					Containers: []corev1.Container{
+						{Name: constants.EnvoyContainerName, SecurityContext: &corev1.SecurityContext{RunAsUser: &proxyUID}},
+					},
+				},
+			},
+			expectedOk: false,
+		},
+		{
+			name: "proxy without a user ID",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: constants.EnvoyContainerName},
+					},
+				},
+			},
+			expectedOk: false,
+		},
+		{
+			name: "pod without a proxy",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "app"},
+					},
+				},
+			},
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			proxyUID, ok := getRedirectProxyUID(tc.pod)
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedProxyUID, proxyUID)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package ebpf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// mountsFile lists the mounts of the mount namespace of the process
+const mountsFile = "/proc/self/mounts"
+
+// kernelVersion is the major and minor version of a Linux kernel
+type kernelVersion struct {
+	major int
+	minor int
+}
+
+// minKernelVersion is the first kernel version supporting all the helpers used by the eBPF programs, the most recent
+// one being bpf_get_netns_cookie in sockops and sk_msg programs
+var minKernelVersion = kernelVersion{major: 5, minor: 15}
+
+func (v kernelVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+func (v kernelVersion) atLeast(other kernelVersion) bool {
+	return v.major > other.major || (v.major == other.major && v.minor >= other.minor)
+}
+
+// CheckSupport returns an error wrapping ErrNotSupported if the node does not support the eBPF acceleration: its
+// kernel must be recent enough to load the programs, and the cgroup v2 hierarchy must be mounted at the given path
+func CheckSupport(cgroupRoot string) error {
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		return errors.Wrap(err, "Error getting the kernel release of the node")
+	}
+	release := unix.ByteSliceToString(uname.Release[:])
+	version, err := parseKernelVersion(release)
+	if err != nil {
+		return err
+	}
+	if !version.atLeast(minKernelVersion) {
+		return errors.Wrapf(ErrNotSupported, "Kernel %s is older than %s", release, minKernelVersion)
+	}
+
+	mounts, err := os.Open(mountsFile)
+	if err != nil {
+		return errors.Wrapf(err, "Error reading the mounts of the node from %s", mountsFile)
+	}
+	defer mounts.Close() //nolint: errcheck,gosec
+
+	isCgroup2, err := isCgroup2Mount(mounts, cgroupRoot)
+	if err != nil {
+		return err
+	}
+	if !isCgroup2 {
+		return errors.Wrapf(ErrNotSupported, "The cgroup v2 hierarchy is not mounted at %s", cgroupRoot)
+	}
+	return nil
+}
+
+// parseKernelVersion returns the version of the kernel with the given release, such as 5.15.0-91-generic
+func parseKernelVersion(release string) (kernelVersion, error) {
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) < 2 {
+		return kernelVersion{}, errors.Errorf("Invalid kernel release %s", release)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return kernelVersion{}, errors.Errorf("Invalid major version in kernel release %s", release)
+	}
+	// The minor version may be followed by a suffix when the release has no patch version, such as 5.15-rc1
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return kernelVersion{}, errors.Errorf("Invalid minor version in kernel release %s", release)
+	}
+	return kernelVersion{major: major, minor: minor}, nil
+}
+
+// isCgroup2Mount returns true if the given mounts, in the format of /proc/self/mounts, mount the cgroup v2 hierarchy
+// at the given path
+func isCgroup2Mount(mounts io.Reader, path string) (bool, error) {
+	path = filepath.Clean(path)
+	scanner := bufio.NewScanner(mounts)
+	for scanner.Scan() {
+		// Each line is formatted as: <device> <mount point> <file system type> <options> <dump> <pass>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		if filepath.Clean(fields[1]) == path && fields[2] == "cgroup2" {
+			return true, nil
+		}
+	}
+	return false, errors.Wrap(scanner.Err(), "Error reading the mounts of the node")
+}
@@ -0,0 +1,96 @@
+package ebpf
+
+import (
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestParseKernelVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		release         string
+		expectedVersion kernelVersion
+		expectErr       bool
+	}{
+		{
+			name:            "release with patch version and suffix",
+			release:         "5.15.0-91-generic",
+			expectedVersion: kernelVersion{major: 5, minor: 15},
+		},
+		{
+			name:            "release without patch version",
+			release:         "6.1-rc1",
+			expectedVersion: kernelVersion{major: 6, minor: 1},
+		},
+		{
+			name:      "release without minor version",
+			release:   "5",
+			expectErr: true,
+		},
+		{
+			name:      "invalid major version",
+			release:   "v5.15.0",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			version, err := parseKernelVersion(tc.release)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestKernelVersionAtLeast(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.True(kernelVersion{major: 5, minor: 15}.atLeast(minKernelVersion))
+	assert.True(kernelVersion{major: 6, minor: 0}.atLeast(minKernelVersion))
+	assert.False(kernelVersion{major: 5, minor: 10}.atLeast(minKernelVersion))
+	assert.False(kernelVersion{major: 4, minor: 19}.atLeast(minKernelVersion))
+}
+
+func TestIsCgroup2Mount(t *testing.T) {
+	mounts := `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0
+tmpfs /host/sys/fs/cgroup tmpfs ro,nosuid,nodev,noexec,mode=755 0 0
+`
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "cgroup v2 hierarchy",
+			path:     "/sys/fs/cgroup/",
+			expected: true,
+		},
+		{
+			name:     "other file system",
+			path:     "/host/sys/fs/cgroup",
+			expected: false,
+		},
+		{
+			name:     "no mount",
+			path:     "/host/cgroup",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			isCgroup2, err := isCgroup2Mount(strings.NewReader(mounts), tc.path)
+			assert.Nil(err)
+			assert.Equal(tc.expected, isCgroup2)
+		})
+	}
+}
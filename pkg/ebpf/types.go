@@ -0,0 +1,82 @@
+// Package ebpf implements the eBPF acceleration of the local hops of the traffic of the meshed pods. The accelerator
+// runs on every node of the cluster and attaches eBPF programs to the cgroups of the meshed pods of its node, which
+// copy the data sent between the applications and their sidecar proxy, and between the sidecar proxies of the node,
+// directly to the receive queue of the destination socket instead of traversing the TCP/IP stack of the kernel. The
+// accelerator can also redirect the outbound connections of the applications to their sidecar proxy, in place of the
+// iptables rules programmed by the init container of the pods.
+package ebpf
+
+import (
+	"sync"
+	"time"
+
+	bpf "github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("ebpf")
+
+// ErrNotSupported is returned when the node does not support the eBPF acceleration, in which case the traffic of the
+// meshed pods of the node keeps traversing the TCP/IP stack
+var ErrNotSupported = errors.New("eBPF acceleration is not supported")
+
+const (
+	// reconcileInterval is the interval at which the programs are attached to the cgroups of the meshed pods of the
+	// node. Pods are accelerated within this interval of the creation of their cgroup.
+	reconcileInterval = 5 * time.Second
+
+	// Names of the programs and maps of the sockmap object
+	sockopsProgramName      = "osm_sockops"
+	skMsgProgramName        = "osm_sk_msg"
+	sockHashMapName         = "osm_sock_hash"
+	meshedPodsMapName       = "osm_meshed_pods"
+	passthroughPortsMapName = "osm_passthrough_ports"
+	settingsMapName         = "osm_settings"
+
+	// Names of the programs and maps of the redirect object
+	connect4ProgramName        = "osm_connect4"
+	redirectSockopsProgramName = "osm_redirect_sockops"
+	getsockoptProgramName      = "osm_getsockopt"
+	redirectPodsMapName        = "osm_redirect_pods"
+	redirectSettingsMapName    = "osm_redirect_settings"
+)
+
+// Accelerator attaches the eBPF programs accelerating the local hops of the traffic of the meshed pods to the cgroups of
+// the meshed pods running on a node
+type Accelerator struct {
+	nodeName   string
+	cgroupRoot string
+	podStore   cache.Store
+
+	collection *bpf.Collection
+	sockops    *bpf.Program
+	meshedPods *bpf.Map
+
+	// redirect redirects the outbound connections of the applications of the meshed pods to their sidecar proxy, nil
+	// if the redirection is disabled
+	redirect *redirector
+
+	mu sync.Mutex
+	// pods are the meshed pods of the node whose IP address is recorded, keyed by UID
+	pods map[types.UID]*acceleratedPod
+}
+
+// acceleratedPod is a meshed pod of the node known to the accelerator
+type acceleratedPod struct {
+	ip [4]byte
+
+	// cgroupID is the ID of the cgroup of the pod, 0 until its cgroup is found
+	cgroupID uint64
+
+	// sockopsLink is the attachment of the sockops program to the cgroup of the pod, nil until its cgroup is found
+	sockopsLink link.Link
+
+	// redirectLinks are the attachments of the redirect programs to the cgroup of the pod, nil if its outbound
+	// connections are not redirected
+	redirectLinks []link.Link
+}
@@ -0,0 +1,50 @@
+package ads
+
+import (
+	"net"
+	"strconv"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// recordEgressAuditHTTPRequest logs and counts the given access log entry of a request sent by the given source to an
+// external host not allowed by its Egress policies, which is forwarded to the host in egress audit mode.
+func (s *Server) recordEgressAuditHTTPRequest(source identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry) {
+	log.Warn().
+		Str("source_identity", source.String()).
+		Str("authority", entry.GetRequest().GetAuthority()).
+		Str("destination", getSocketAddress(entry.GetCommonProperties().GetDownstreamLocalAddress())).
+		Str("method", entry.GetRequest().GetRequestMethod().String()).
+		Str("path", entry.GetRequest().GetPath()).
+		Msg("Egress request would be denied by Egress policies in audit mode")
+
+	host := s.getEgressMetricHost(source, entry.GetCommonProperties(), entry.GetRequest().GetAuthority())
+	metricsstore.DefaultMetricsStore.ProxyEgressAuditDenyCount.WithLabelValues(source.String(), host).Inc()
+}
+
+// recordEgressAuditTCPConnection logs and counts the given access log entry of a connection from the given source to an
+// external destination not allowed by its Egress policies, which is forwarded to the destination in egress audit mode.
+func (s *Server) recordEgressAuditTCPConnection(source identity.ServiceIdentity, entry *xds_accesslog_data.TCPAccessLogEntry) {
+	log.Warn().
+		Str("source_identity", source.String()).
+		Str("destination", getSocketAddress(entry.GetCommonProperties().GetDownstreamLocalAddress())).
+		Str("requested_server_name", entry.GetCommonProperties().GetTlsProperties().GetTlsSniHostname()).
+		Msg("Egress connection would be denied by Egress policies in audit mode")
+
+	host := s.getEgressMetricHost(source, entry.GetCommonProperties(), "")
+	metricsstore.DefaultMetricsStore.ProxyEgressAuditDenyCount.WithLabelValues(source.String(), host).Inc()
+}
+
+// getSocketAddress returns the given socket address as host:port, Ex. the original destination of a connection
+// redirected to the outbound listener, or an empty string if it is not a socket address
+func getSocketAddress(address *xds_core.Address) string {
+	socketAddress := address.GetSocketAddress()
+	if socketAddress == nil {
+		return ""
+	}
+	return net.JoinHostPort(socketAddress.GetAddress(), strconv.FormatUint(uint64(socketAddress.GetPortValue()), 10))
+}
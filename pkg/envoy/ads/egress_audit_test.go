@@ -0,0 +1,68 @@
+package ads
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func TestRecordEgressAudit(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		egressMetricHosts: make(map[string]bool),
+	}
+	source := identity.ServiceIdentity("bookbuyer.default.cluster.local")
+	originalDestination := &xds_core.Address{
+		Address: &xds_core.Address_SocketAddress{
+			SocketAddress: &xds_core.SocketAddress{
+				Address:       "203.0.113.10",
+				PortSpecifier: &xds_core.SocketAddress_PortValue{PortValue: 5432},
+			},
+		},
+	}
+
+	assert.NotPanics(func() {
+		s.recordEgressAuditHTTPRequest(source, &xds_accesslog_data.HTTPAccessLogEntry{
+			CommonProperties: &xds_accesslog_data.AccessLogCommon{UpstreamCluster: "passthrough-outbound"},
+			Request:          &xds_accesslog_data.HTTPRequestProperties{Authority: "example.com", Path: "/"},
+		})
+		s.recordEgressAuditTCPConnection(source, &xds_accesslog_data.TCPAccessLogEntry{
+			CommonProperties: &xds_accesslog_data.AccessLogCommon{
+				UpstreamCluster:        "passthrough-outbound",
+				UpstreamRemoteAddress:  originalDestination,
+				DownstreamLocalAddress: originalDestination,
+			},
+		})
+	})
+	// The requests and connections are counted per requested host or IP address
+	assert.Len(s.egressMetricHosts, 2)
+	assert.True(s.egressMetricHosts["bookbuyer.default.cluster.local|example.com"])
+	assert.True(s.egressMetricHosts["bookbuyer.default.cluster.local|203.0.113.10"])
+}
+
+func TestGetSocketAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("203.0.113.10:443", getSocketAddress(&xds_core.Address{
+		Address: &xds_core.Address_SocketAddress{
+			SocketAddress: &xds_core.SocketAddress{
+				Address:       "203.0.113.10",
+				PortSpecifier: &xds_core.SocketAddress_PortValue{PortValue: 443},
+			},
+		},
+	}))
+	assert.Equal("[2001:db8::1]:80", getSocketAddress(&xds_core.Address{
+		Address: &xds_core.Address_SocketAddress{
+			SocketAddress: &xds_core.SocketAddress{
+				Address:       "2001:db8::1",
+				PortSpecifier: &xds_core.SocketAddress_PortValue{PortValue: 80},
+			},
+		},
+	}))
+	assert.Empty(getSocketAddress(nil))
+}
@@ -0,0 +1,58 @@
+package ads
+
+import (
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
+)
+
+// isEgressGateway returns true if the given proxy is the egress gateway and the egress gateway is enabled
+func (s *Server) isEgressGateway(proxy *envoy.Proxy) bool {
+	return featureflags.IsEgressGatewayEnabled() && s.catalog.IsEgressGateway(proxy)
+}
+
+// newEmptyEgressGatewayResponse creates an empty Discovery Response for the xDS types not used by the egress gateway.
+// The egress gateway only programs an L4 listener forwarding traffic to external destinations, so it does not
+// reference any EDS or RDS resources.
+func newEmptyEgressGatewayResponse(_ catalog.MeshCataloger, _ *envoy.Proxy, request *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	return &xds_discovery.DiscoveryResponse{
+		TypeUrl: request.TypeUrl,
+	}, nil
+}
+
+// makeRequestForAllEgressGatewaySecrets constructs an SDS DiscoveryRequest as if the egress gateway sent it.
+// The request contains:
+//
+// 1. The service certificate presented by the egress gateway to the sidecar proxies: service-cert:<osm-namespace>/osm-egress-gateway
+// 2. The root validation certificate to validate the sidecar proxies during mTLS handshake: root-cert-for-mtls-inbound:<osm-namespace>/osm-egress-gateway
+// 3. The CA bundles and client certificates to originate TLS to external hosts configured by the Egress policies: root-cert-for-egress-tls:<namespace>/<secret-name> and egress-client-cert:<namespace>/<secret-name>
+func makeRequestForAllEgressGatewaySecrets(osmNamespace string, meshCatalog catalog.MeshCataloger) *xds_discovery.DiscoveryRequest {
+	svcAccount := catalog.GetEgressGatewayServiceAccount(osmNamespace)
+
+	discoveryRequest := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+	}
+	for _, certType := range []envoy.SDSCertType{envoy.ServiceCertType, envoy.RootCertTypeForMTLSInbound} {
+		discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, envoy.SDSCert{
+			Name:     svcAccount.String(),
+			CertType: certType,
+		}.String())
+	}
+	discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, getEgressTLSSecretNames(meshCatalog.GetEgressGatewayPolicy())...)
+
+	return discoveryRequest
+}
+
+// getEgressGatewayRootCertName returns the name of the root validation certificate used by the sidecar proxies to
+// validate the egress gateway in the given OSM namespace during mTLS handshake:
+// root-cert-for-mtls-outbound:<osm-namespace>/osm-egress-gateway
+func getEgressGatewayRootCertName(osmNamespace string) string {
+	return envoy.SDSCert{
+		Name:     catalog.GetEgressGatewayService(osmNamespace).String(),
+		CertType: envoy.RootCertTypeForMTLSOutbound,
+	}.String()
+}
@@ -0,0 +1,45 @@
+package ads
+
+import (
+	"testing"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestMakeRequestForAllEgressGatewaySecrets(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(nil)
+	expected := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+		ResourceNames: []string{
+			"service-cert:osm-system/osm-egress-gateway",
+			"root-cert-for-mtls-inbound:osm-system/osm-egress-gateway",
+		},
+	}
+	assert.Equal(expected, makeRequestForAllEgressGatewaySecrets("osm-system", mockCatalog))
+
+	// The secrets originating TLS to external hosts are requested
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(&trafficpolicy.EgressPolicy{
+		TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+			80: {"httpbin.org": {Port: 443, CABundleSecret: "curl/httpbin-ca", ClientCertSecret: "curl/httpbin-client"}},
+		},
+	})
+	expected.ResourceNames = append(expected.ResourceNames,
+		"root-cert-for-egress-tls:curl/httpbin-ca",
+		"egress-client-cert:curl/httpbin-client",
+	)
+	assert.Equal(expected, makeRequestForAllEgressGatewaySecrets("osm-system", mockCatalog))
+
+	assert.Equal("root-cert-for-mtls-outbound:osm-system/osm-egress-gateway", getEgressGatewayRootCertName("osm-system"))
+}
@@ -0,0 +1,95 @@
+package ads
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// maxEgressMetricHosts is the maximum number of source and external host combinations the egress metrics are labeled
+	// with, to bound the cardinality of the metrics when proxies connect to a large number of external IP addresses
+	maxEgressMetricHosts = 1000
+
+	// egressMetricOtherHost is the host label of the egress traffic beyond maxEgressMetricHosts
+	egressMetricOtherHost = "other"
+
+	// egressBytesSent and egressBytesReceived are the values of the direction label of the egress bytes metric
+	egressBytesSent     = "sent"
+	egressBytesReceived = "received"
+)
+
+// recordEgressHTTPRequest counts the given access log entry of a request sent by the given source to an external host
+func (s *Server) recordEgressHTTPRequest(source identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry) {
+	host := s.getEgressMetricHost(source, entry.GetCommonProperties(), entry.GetRequest().GetAuthority())
+
+	metricsstore.DefaultMetricsStore.ProxyEgressRequestCount.
+		WithLabelValues(source.String(), host, getResponseClass(entry.GetResponse().GetResponseCode().GetValue())).Inc()
+
+	// The bytes are accounted from the point of view of the proxy receiving the request from the application
+	request, response := entry.GetRequest(), entry.GetResponse()
+	addEgressBytes(source, host, request.GetRequestHeadersBytes()+request.GetRequestBodyBytes(),
+		response.GetResponseHeadersBytes()+response.GetResponseBodyBytes())
+}
+
+// recordEgressTCPConnection counts the given access log entry of a connection from the given source to an external host
+func (s *Server) recordEgressTCPConnection(source identity.ServiceIdentity, entry *xds_accesslog_data.TCPAccessLogEntry) {
+	host := s.getEgressMetricHost(source, entry.GetCommonProperties(), "")
+
+	// The bytes received by the proxy from the application are sent to the external host, and vice versa
+	addEgressBytes(source, host, entry.GetConnectionProperties().GetReceivedBytes(), entry.GetConnectionProperties().GetSentBytes())
+}
+
+// getEgressMetricHost returns the external host the egress metrics of the given access log entry are labeled with:
+// the host of the cluster of the external host, or the host requested by the application for the clusters shared by
+// wildcard domains, or the IP address connected to for the clusters of IP ranges.
+func (s *Server) getEgressMetricHost(source identity.ServiceIdentity, common *xds_accesslog_data.AccessLogCommon, authority string) string {
+	host, ok := envoy.GetEgressHostFromClusterName(common.GetUpstreamCluster())
+	if !ok {
+		switch {
+		case authority != "":
+			host = authority
+			if authorityHost, _, err := net.SplitHostPort(authority); err == nil {
+				host = authorityHost
+			}
+		case common.GetTlsProperties().GetTlsSniHostname() != "":
+			host = common.GetTlsProperties().GetTlsSniHostname()
+		default:
+			host = common.GetUpstreamRemoteAddress().GetSocketAddress().GetAddress()
+		}
+	}
+	if host == "" {
+		host = common.GetUpstreamCluster()
+	}
+
+	key := strings.Join([]string{source.String(), host}, "|")
+	s.egressMetricHostsMutex.Lock()
+	defer s.egressMetricHostsMutex.Unlock()
+
+	if !s.egressMetricHosts[key] {
+		if len(s.egressMetricHosts) >= maxEgressMetricHosts {
+			return egressMetricOtherHost
+		}
+		s.egressMetricHosts[key] = true
+	}
+	return host
+}
+
+func addEgressBytes(source identity.ServiceIdentity, host string, sent, received uint64) {
+	metricsstore.DefaultMetricsStore.ProxyEgressBytesCount.WithLabelValues(source.String(), host, egressBytesSent).Add(float64(sent))
+	metricsstore.DefaultMetricsStore.ProxyEgressBytesCount.WithLabelValues(source.String(), host, egressBytesReceived).Add(float64(received))
+}
+
+// getResponseClass returns the class of the given HTTP response code, Ex. 2xx, or none if no response was received
+func getResponseClass(code uint32) string {
+	if code < 100 || code > 599 {
+		return "none"
+	}
+	return fmt.Sprintf("%dxx", code/100)
+}
@@ -0,0 +1,89 @@
+package ads
+
+import (
+	"fmt"
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func TestGetEgressMetricHost(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		egressMetricHosts: make(map[string]bool),
+	}
+	source := identity.ServiceIdentity("bookbuyer.default.cluster.local")
+
+	// The host of the cluster of an external host
+	assert.Equal("httpbin.org", s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress:httpbin.org:443",
+	}, "httpbin.org:443"))
+
+	// The host requested by the application for the cluster shared by wildcard domains
+	assert.Equal("api.github.com", s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress-wildcard-hosts:443",
+		TlsProperties:   &xds_accesslog_data.TLSProperties{TlsSniHostname: "api.github.com"},
+	}, ""))
+	assert.Equal("example.com", s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress-wildcard-hosts:80",
+	}, "example.com:80"))
+
+	// The IP address connected to for the cluster of IP ranges
+	assert.Equal("203.0.113.10", s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress-ip-ranges:5432",
+		UpstreamRemoteAddress: &xds_core.Address{
+			Address: &xds_core.Address_SocketAddress{
+				SocketAddress: &xds_core.SocketAddress{Address: "203.0.113.10"},
+			},
+		},
+	}, ""))
+	assert.Len(s.egressMetricHosts, 4)
+
+	// The hosts beyond the maximum number of hosts are aggregated
+	for i := len(s.egressMetricHosts); i < maxEgressMetricHosts; i++ {
+		s.egressMetricHosts[fmt.Sprintf("%s|host-%d", source, i)] = true
+	}
+	assert.Equal(egressMetricOtherHost, s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress:example.org:443",
+	}, ""))
+	// The hosts already labeled are still labeled with their host
+	assert.Equal("httpbin.org", s.getEgressMetricHost(source, &xds_accesslog_data.AccessLogCommon{
+		UpstreamCluster: "egress:httpbin.org:443",
+	}, ""))
+}
+
+func TestRecordEgressTraffic(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		egressMetricHosts: make(map[string]bool),
+	}
+	source := identity.ServiceIdentity("bookbuyer.default.cluster.local")
+
+	assert.NotPanics(func() {
+		s.recordEgressHTTPRequest(source, &xds_accesslog_data.HTTPAccessLogEntry{
+			CommonProperties: &xds_accesslog_data.AccessLogCommon{UpstreamCluster: "egress:httpbin.org:80"},
+			Request:          &xds_accesslog_data.HTTPRequestProperties{RequestHeadersBytes: 120},
+			Response:         &xds_accesslog_data.HTTPResponseProperties{ResponseBodyBytes: 512},
+		})
+		s.recordEgressTCPConnection(source, &xds_accesslog_data.TCPAccessLogEntry{
+			CommonProperties:     &xds_accesslog_data.AccessLogCommon{UpstreamCluster: "egress:httpbin.org:443"},
+			ConnectionProperties: &xds_accesslog_data.ConnectionProperties{ReceivedBytes: 100, SentBytes: 2048},
+		})
+	})
+	assert.Len(s.egressMetricHosts, 1)
+}
+
+func TestGetResponseClass(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("2xx", getResponseClass(200))
+	assert.Equal("3xx", getResponseClass(304))
+	assert.Equal("5xx", getResponseClass(503))
+	assert.Equal("none", getResponseClass(0))
+}
@@ -7,3 +7,4 @@ import (
 var errUnknownTypeURL = errors.New("unknown TypeUrl")
 var errCreatingResponse = errors.New("creating response")
 var errGrpcClosed = errors.New("grpc closed")
+var errInvalidResponse = errors.New("response has invalid resources")
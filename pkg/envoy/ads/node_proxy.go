@@ -0,0 +1,62 @@
+package ads
+
+import (
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// isNodeProxy returns true if the given proxy is a per-node proxy and node proxy mode is enabled
+func (s *Server) isNodeProxy(proxy *envoy.Proxy) bool {
+	return featureflags.IsNodeProxyModeEnabled() && s.catalog.IsNodeProxy(proxy)
+}
+
+// newEmptyNodeProxyResponse creates an empty Discovery Response for the xDS types not used by per-node proxies.
+// Node proxies only program L4 listeners forwarding traffic to its original destination, so they do not
+// reference any EDS or RDS resources.
+func newEmptyNodeProxyResponse(_ catalog.MeshCataloger, _ *envoy.Proxy, request *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	return &xds_discovery.DiscoveryResponse{
+		TypeUrl: request.TypeUrl,
+	}, nil
+}
+
+// makeRequestForAllNodeProxySecrets constructs an SDS DiscoveryRequest as if a per-node proxy sent it.
+// For each service account of the node proxy mode pods on the proxy's node, the request contains:
+//
+// 1. The service certificate presented on behalf of the pods: service-cert:<namespace>/<service-account>
+// 2. The root validation certificate to validate downstream clients during mTLS handshake: root-cert-for-mtls-inbound:<namespace>/<service-account>
+// 3. The root validation certificate to validate upstreams during mTLS handshake: root-cert-for-mtls-outbound:<namespace>/<service-account>
+func makeRequestForAllNodeProxySecrets(proxy *envoy.Proxy, meshCatalog catalog.MeshCataloger) *xds_discovery.DiscoveryRequest {
+	pods, err := meshCatalog.ListPodsForNodeProxy(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing pods for node proxy with SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil
+	}
+
+	discoveryRequest := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+	}
+
+	svcAccounts := make(map[service.K8sServiceAccount]bool)
+	for _, pod := range pods {
+		svcAccount := service.K8sServiceAccount{Name: pod.Spec.ServiceAccountName, Namespace: pod.Namespace}
+		if svcAccounts[svcAccount] {
+			continue
+		}
+		svcAccounts[svcAccount] = true
+
+		for _, certType := range []envoy.SDSCertType{envoy.ServiceCertType, envoy.RootCertTypeForMTLSInbound, envoy.RootCertTypeForMTLSOutbound} {
+			discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, envoy.SDSCert{
+				Name:     svcAccount.String(),
+				CertType: certType,
+			}.String())
+		}
+	}
+
+	return discoveryRequest
+}
@@ -0,0 +1,69 @@
+package ads
+
+import (
+	"testing"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestMakeRequestForAllNodeProxySecrets(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	proxy := envoy.NewProxy("", "", nil)
+
+	newPod := func(name, svcAccount string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: svcAccount,
+			},
+		}
+	}
+
+	// Pods of a node proxy that has not been resolved to a node
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return(nil, catalog.ErrNodeProxyPodNotFound)
+	assert.Nil(makeRequestForAllNodeProxySecrets(proxy, mockCatalog))
+
+	pods := []*corev1.Pod{
+		newPod("bookbuyer-1", "bookbuyer"),
+		newPod("bookbuyer-2", "bookbuyer"),
+		newPod("bookstore-1", "bookstore"),
+	}
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return(pods, nil)
+
+	expected := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+		ResourceNames: []string{
+			"service-cert:default/bookbuyer",
+			"root-cert-for-mtls-inbound:default/bookbuyer",
+			"root-cert-for-mtls-outbound:default/bookbuyer",
+			"service-cert:default/bookstore",
+			"root-cert-for-mtls-inbound:default/bookstore",
+			"root-cert-for-mtls-outbound:default/bookstore",
+		},
+	}
+	assert.Equal(expected, makeRequestForAllNodeProxySecrets(proxy, mockCatalog))
+}
+
+func TestNewEmptyNodeProxyResponse(t *testing.T) {
+	assert := tassert.New(t)
+
+	request := &xds_discovery.DiscoveryRequest{TypeUrl: string(envoy.TypeEDS)}
+	actual, err := newEmptyNodeProxyResponse(nil, nil, request, nil, nil)
+	assert.Nil(err)
+	assert.Equal(string(envoy.TypeEDS), actual.TypeUrl)
+	assert.Empty(actual.Resources)
+}
@@ -0,0 +1,83 @@
+package ads
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+// maxObservedRequestRecords is the maximum number of source, destination, method and path combinations requests are
+// recorded for, to bound the memory used by the records when requests are sent to a large number of distinct paths
+const maxObservedRequestRecords = 10000
+
+// recordObservedRequest records the given access log entry of the given destination if the request was sent by a proxy
+// in the mesh. Requests without a client certificate, such as the requests from ingress, are not recorded.
+func (s *Server) recordObservedRequest(destination identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry) {
+	source := getSubjectCommonName(entry.GetCommonProperties().GetTlsProperties().GetPeerCertificateProperties().GetSubject())
+	if source == "" {
+		return
+	}
+
+	method := entry.GetRequest().GetRequestMethod()
+	if method == xds_core.RequestMethod_METHOD_UNSPECIFIED {
+		return
+	}
+
+	// The query string is not part of the path matched by SMI HTTPRouteGroups
+	path := entry.GetRequest().GetPath()
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
+	}
+
+	key := strings.Join([]string{source, destination.String(), method.String(), path}, "|")
+	s.observedRequestsMutex.Lock()
+	defer s.observedRequestsMutex.Unlock()
+
+	request, ok := s.observedRequests[key]
+	if !ok {
+		if len(s.observedRequests) >= maxObservedRequestRecords {
+			return
+		}
+		request = &envoy.ObservedRequest{
+			SourceIdentity:      source,
+			DestinationIdentity: destination.String(),
+			Method:              method.String(),
+			Path:                path,
+		}
+		s.observedRequests[key] = request
+	}
+	request.Count++
+	request.LastObserved = time.Now()
+}
+
+// ListObservedRequests implements XDSDebugger interface and returns the records of the requests observed by proxies in
+// permissive traffic policy mode, sorted by source, destination, path and method.
+func (s *Server) ListObservedRequests() []envoy.ObservedRequest {
+	s.observedRequestsMutex.Lock()
+	defer s.observedRequestsMutex.Unlock()
+
+	var requests []envoy.ObservedRequest
+	for _, request := range s.observedRequests {
+		requests = append(requests, *request)
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].SourceIdentity != requests[j].SourceIdentity {
+			return requests[i].SourceIdentity < requests[j].SourceIdentity
+		}
+		if requests[i].DestinationIdentity != requests[j].DestinationIdentity {
+			return requests[i].DestinationIdentity < requests[j].DestinationIdentity
+		}
+		if requests[i].Path != requests[j].Path {
+			return requests[i].Path < requests[j].Path
+		}
+		return requests[i].Method < requests[j].Method
+	})
+	return requests
+}
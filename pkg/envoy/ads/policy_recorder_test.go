@@ -0,0 +1,55 @@
+package ads
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func newObservedHTTPAccessLogEntry(peerSubject string, method xds_core.RequestMethod, path string) *xds_accesslog_data.HTTPAccessLogEntry {
+	entry := newHTTPAccessLogEntry(peerSubject, "", "via_upstream")
+	entry.Request.RequestMethod = method
+	entry.Request.Path = path
+	return entry
+}
+
+func TestRecordObservedRequest(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		observedRequests: make(map[string]*envoy.ObservedRequest),
+	}
+	destination := identity.ServiceIdentity("bookstore.bookstore.cluster.local")
+	bookbuyerSubject := "CN=bookbuyer.bookbuyer.cluster.local,O=Open Service Mesh"
+	bookthiefSubject := "CN=bookthief.bookthief.cluster.local,O=Open Service Mesh"
+
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry(bookbuyerSubject, xds_core.RequestMethod_GET, "/books-bought"))
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry(bookbuyerSubject, xds_core.RequestMethod_GET, "/books-bought?page=2"))
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry(bookbuyerSubject, xds_core.RequestMethod_POST, "/books-bought"))
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry(bookthiefSubject, xds_core.RequestMethod_GET, "/buy-a-book/new"))
+	// Requests without a client certificate or method are not recorded
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry("", xds_core.RequestMethod_GET, "/books-bought"))
+	s.recordObservedRequest(destination, newObservedHTTPAccessLogEntry(bookthiefSubject, xds_core.RequestMethod_METHOD_UNSPECIFIED, "/books-bought"))
+
+	requests := s.ListObservedRequests()
+	assert.Len(requests, 3)
+
+	assert.Equal("bookbuyer.bookbuyer.cluster.local", requests[0].SourceIdentity)
+	assert.Equal("bookstore.bookstore.cluster.local", requests[0].DestinationIdentity)
+	assert.Equal("GET", requests[0].Method)
+	assert.Equal("/books-bought", requests[0].Path)
+	assert.Equal(uint64(2), requests[0].Count)
+	assert.False(requests[0].LastObserved.IsZero())
+
+	assert.Equal("bookbuyer.bookbuyer.cluster.local", requests[1].SourceIdentity)
+	assert.Equal("POST", requests[1].Method)
+	assert.Equal(uint64(1), requests[1].Count)
+
+	assert.Equal("bookthief.bookthief.cluster.local", requests[2].SourceIdentity)
+	assert.Equal("/buy-a-book/new", requests[2].Path)
+}
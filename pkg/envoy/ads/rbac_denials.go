@@ -0,0 +1,193 @@
+package ads
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	xds_accesslog_service "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const (
+	// rbacDeniedResponseCodeDetailsPrefix is the prefix of the response code details of the requests denied by the RBAC filter
+	rbacDeniedResponseCodeDetailsPrefix = "rbac_access_denied"
+
+	// rbacShadowEngineResultKey is the key of the result of the shadow rules of the RBAC filter in the dynamic metadata
+	// of a request, and rbacShadowDeniedResult is the result of the requests the shadow rules would deny
+	rbacShadowEngineResultKey = "shadow_engine_result"
+	rbacShadowDeniedResult    = "denied"
+
+	// maxRBACDenialRecords is the maximum number of source, destination and route combinations denials are recorded for,
+	// to bound the memory used by the records when a large number of clients are denied
+	maxRBACDenialRecords = 1000
+)
+
+// StreamAccessLogs implements accesslog.AccessLogServiceServer, and records the requests denied by the RBAC policies
+// of the proxy streaming the access logs of its inbound HTTP requests, the requests that would be denied by the RBAC
+// policies of SMI TrafficTargets in shadow mode, or the requests observed by the proxy when the access logs are
+// streamed to the policy recorder. The access logs of the egress traffic of the proxy are aggregated in metrics per
+// external host, and the egress traffic not allowed by its Egress policies in audit mode is logged and counted.
+func (s *Server) StreamAccessLogs(server xds_accesslog_service.AccessLogService_StreamAccessLogsServer) error {
+	certCommonName, _, err := utils.ValidateClient(server.Context(), nil)
+	if err != nil {
+		return errors.Wrap(err, "Could not start Access Log Service gRPC stream for Envoy proxy")
+	}
+
+	svcAccount, err := catalog.GetServiceAccountFromProxyCertificate(certCommonName)
+	if err != nil {
+		return errors.Wrapf(err, "Error getting service account from certificate with CN=%s", certCommonName)
+	}
+	destination := identity.GetKubernetesServiceIdentity(svcAccount, identity.ClusterLocalTrustDomain)
+
+	// The log name is only sent in the first message of the stream
+	var logName string
+	for {
+		msg, err := server.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.GetIdentifier() != nil {
+			logName = msg.GetIdentifier().GetLogName()
+		}
+
+		// The proxy streaming the access logs is the source of its egress traffic
+		switch logName {
+		case envoy.EgressMetricsAccessLogName:
+			for _, entry := range msg.GetHttpLogs().GetLogEntry() {
+				s.recordEgressHTTPRequest(destination, entry)
+			}
+			for _, entry := range msg.GetTcpLogs().GetLogEntry() {
+				s.recordEgressTCPConnection(destination, entry)
+			}
+			continue
+		case envoy.EgressAuditAccessLogName:
+			for _, entry := range msg.GetHttpLogs().GetLogEntry() {
+				s.recordEgressAuditHTTPRequest(destination, entry)
+			}
+			for _, entry := range msg.GetTcpLogs().GetLogEntry() {
+				s.recordEgressAuditTCPConnection(destination, entry)
+			}
+			continue
+		}
+
+		for _, entry := range msg.GetHttpLogs().GetLogEntry() {
+			switch logName {
+			case envoy.PolicyRecorderAccessLogName:
+				s.recordObservedRequest(destination, entry)
+			case envoy.RBACShadowDenialAccessLogName:
+				s.recordRBACShadowDenial(destination, entry)
+			default:
+				s.recordRBACDenial(destination, entry)
+			}
+		}
+	}
+}
+
+// recordRBACDenial logs, counts and records the given access log entry of the given destination if the request was
+// denied by the RBAC filter.
+func (s *Server) recordRBACDenial(destination identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry) {
+	if !strings.HasPrefix(entry.GetResponse().GetResponseCodeDetails(), rbacDeniedResponseCodeDetailsPrefix) {
+		return
+	}
+	s.addRBACDenial(destination, entry, false)
+}
+
+// recordRBACShadowDenial logs, counts and records the given access log entry of the given destination if the request
+// would be denied by the shadow rules of the RBAC filter, derived from the SMI TrafficTargets in shadow mode.
+func (s *Server) recordRBACShadowDenial(destination identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry) {
+	rbacMetadata := entry.GetCommonProperties().GetMetadata().GetFilterMetadata()[wellknown.HTTPRoleBasedAccessControl]
+	if rbacMetadata.GetFields()[rbacShadowEngineResultKey].GetStringValue() != rbacShadowDeniedResult {
+		return
+	}
+	s.addRBACDenial(destination, entry, true)
+}
+
+// addRBACDenial logs, counts and records the given access log entry of the given destination as a request denied by
+// the RBAC filter, or that would be denied by its shadow rules if shadow is true.
+func (s *Server) addRBACDenial(destination identity.ServiceIdentity, entry *xds_accesslog_data.HTTPAccessLogEntry, shadow bool) {
+	source := getSubjectCommonName(entry.GetCommonProperties().GetTlsProperties().GetPeerCertificateProperties().GetSubject())
+	route := entry.GetCommonProperties().GetRouteName()
+
+	msg := "Request denied by RBAC policy"
+	counter := metricsstore.DefaultMetricsStore.ProxyRBACDenyCount
+	if shadow {
+		msg = "Request would be denied by RBAC policy of a TrafficTarget in shadow mode"
+		counter = metricsstore.DefaultMetricsStore.ProxyRBACShadowDenyCount
+	}
+
+	log.Warn().
+		Str("source_identity", source).
+		Str("destination_identity", destination.String()).
+		Str("route", route).
+		Str("method", entry.GetRequest().GetRequestMethod().String()).
+		Str("path", entry.GetRequest().GetPath()).
+		Msg(msg)
+
+	counter.WithLabelValues(source, destination.String(), route).Inc()
+
+	key := strings.Join([]string{source, destination.String(), route, strconv.FormatBool(shadow)}, "|")
+	s.rbacDenialsMutex.Lock()
+	defer s.rbacDenialsMutex.Unlock()
+
+	denial, ok := s.rbacDenials[key]
+	if !ok {
+		if len(s.rbacDenials) >= maxRBACDenialRecords {
+			return
+		}
+		denial = &envoy.RBACDenial{
+			SourceIdentity:      source,
+			DestinationIdentity: destination.String(),
+			Route:               route,
+			Shadow:              shadow,
+		}
+		s.rbacDenials[key] = denial
+	}
+	denial.Count++
+	denial.LastDenied = time.Now()
+}
+
+// ListRBACDenials implements XDSDebugger interface and returns the records of the requests denied by the RBAC policies
+// of proxies, or that would be denied by the RBAC policies of TrafficTargets in shadow mode, the most denied first.
+func (s *Server) ListRBACDenials() []envoy.RBACDenial {
+	s.rbacDenialsMutex.Lock()
+	defer s.rbacDenialsMutex.Unlock()
+
+	var denials []envoy.RBACDenial
+	for _, denial := range s.rbacDenials {
+		denials = append(denials, *denial)
+	}
+
+	sort.Slice(denials, func(i, j int) bool {
+		if denials[i].Count != denials[j].Count {
+			return denials[i].Count > denials[j].Count
+		}
+		return denials[i].LastDenied.After(denials[j].LastDenied)
+	})
+	return denials
+}
+
+// getSubjectCommonName returns the common name of the given certificate subject, Ex. bookbuyer.bookbuyer.cluster.local
+// for the subject CN=bookbuyer.bookbuyer.cluster.local,O=Open Service Mesh
+func getSubjectCommonName(subject string) string {
+	for _, attribute := range strings.Split(subject, ",") {
+		if strings.HasPrefix(attribute, "CN=") {
+			return strings.TrimPrefix(attribute, "CN=")
+		}
+	}
+	return subject
+}
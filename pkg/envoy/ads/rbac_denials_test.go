@@ -0,0 +1,110 @@
+package ads
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func newHTTPAccessLogEntry(peerSubject, route, responseCodeDetails string) *xds_accesslog_data.HTTPAccessLogEntry {
+	return &xds_accesslog_data.HTTPAccessLogEntry{
+		CommonProperties: &xds_accesslog_data.AccessLogCommon{
+			TlsProperties: &xds_accesslog_data.TLSProperties{
+				PeerCertificateProperties: &xds_accesslog_data.TLSProperties_CertificateProperties{
+					Subject: peerSubject,
+				},
+			},
+			RouteName: route,
+		},
+		Request: &xds_accesslog_data.HTTPRequestProperties{
+			Path: "/books-bought",
+		},
+		Response: &xds_accesslog_data.HTTPResponseProperties{
+			ResponseCodeDetails: responseCodeDetails,
+		},
+	}
+}
+
+func TestRecordRBACDenial(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		rbacDenials: make(map[string]*envoy.RBACDenial),
+	}
+	destination := identity.ServiceIdentity("bookstore.default.cluster.local")
+	bookbuyerSubject := "CN=bookbuyer.default.cluster.local,O=Open Service Mesh"
+	bookthiefSubject := "CN=bookthief.default.cluster.local,O=Open Service Mesh"
+	deniedDetails := "rbac_access_denied_matched_policy[none]"
+
+	s.recordRBACDenial(destination, newHTTPAccessLogEntry(bookbuyerSubject, "bookstore|GET|/books-bought", deniedDetails))
+	s.recordRBACDenial(destination, newHTTPAccessLogEntry(bookthiefSubject, "bookstore|GET|/books-bought", deniedDetails))
+	s.recordRBACDenial(destination, newHTTPAccessLogEntry(bookthiefSubject, "bookstore|GET|/books-bought", deniedDetails))
+	// Requests not denied by the RBAC filter are not recorded
+	s.recordRBACDenial(destination, newHTTPAccessLogEntry(bookthiefSubject, "bookstore|GET|/books-bought", "via_upstream"))
+
+	denials := s.ListRBACDenials()
+	assert.Len(denials, 2)
+
+	assert.Equal("bookthief.default.cluster.local", denials[0].SourceIdentity)
+	assert.Equal("bookstore.default.cluster.local", denials[0].DestinationIdentity)
+	assert.Equal("bookstore|GET|/books-bought", denials[0].Route)
+	assert.Equal(uint64(2), denials[0].Count)
+	assert.False(denials[0].LastDenied.IsZero())
+
+	assert.Equal("bookbuyer.default.cluster.local", denials[1].SourceIdentity)
+	assert.Equal(uint64(1), denials[1].Count)
+}
+
+func TestRecordRBACShadowDenial(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{
+		rbacDenials: make(map[string]*envoy.RBACDenial),
+	}
+	destination := identity.ServiceIdentity("bookstore.default.cluster.local")
+	bookthiefSubject := "CN=bookthief.default.cluster.local,O=Open Service Mesh"
+
+	newShadowEntry := func(result string) *xds_accesslog_data.HTTPAccessLogEntry {
+		entry := newHTTPAccessLogEntry(bookthiefSubject, "bookstore|GET|/books-bought", "via_upstream")
+		entry.CommonProperties.Metadata = &xds_core.Metadata{
+			FilterMetadata: map[string]*structpb.Struct{
+				wellknown.HTTPRoleBasedAccessControl: {
+					Fields: map[string]*structpb.Value{
+						"shadow_engine_result": {Kind: &structpb.Value_StringValue{StringValue: result}},
+					},
+				},
+			},
+		}
+		return entry
+	}
+
+	s.recordRBACShadowDenial(destination, newShadowEntry("denied"))
+	// Requests allowed by the shadow rules are not recorded
+	s.recordRBACShadowDenial(destination, newShadowEntry("allowed"))
+	// A request denied by the RBAC filter is recorded separately from the requests that would be denied
+	s.recordRBACDenial(destination, newHTTPAccessLogEntry(bookthiefSubject, "bookstore|GET|/books-bought", "rbac_access_denied_matched_policy[none]"))
+
+	denials := s.ListRBACDenials()
+	assert.Len(denials, 2)
+	for _, denial := range denials {
+		assert.Equal("bookthief.default.cluster.local", denial.SourceIdentity)
+		assert.Equal(uint64(1), denial.Count)
+	}
+	assert.NotEqual(denials[0].Shadow, denials[1].Shadow)
+}
+
+func TestGetSubjectCommonName(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("bookbuyer.default.cluster.local", getSubjectCommonName("CN=bookbuyer.default.cluster.local,O=Open Service Mesh"))
+	assert.Equal("bookbuyer.default.cluster.local", getSubjectCommonName("O=Open Service Mesh,CN=bookbuyer.default.cluster.local"))
+	assert.Equal("O=Open Service Mesh", getSubjectCommonName("O=Open Service Mesh"))
+	assert.Equal("", getSubjectCommonName(""))
+}
@@ -6,9 +6,11 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
 )
 
 const (
@@ -16,28 +18,41 @@ const (
 	ADSUpdateStr = "ADS"
 )
 
-// Wrapper to create and send a discovery response to an envoy server
+// Wrapper to send a discovery response, whose generation started at the given time, to an envoy server
 func (s *Server) sendTypeResponse(tURI envoy.TypeURI,
 	proxy *envoy.Proxy, server *xds_discovery.AggregatedDiscoveryService_StreamAggregatedResourcesServer,
-	req *xds_discovery.DiscoveryRequest, cfg configurator.Configurator) error {
+	discoveryResponse *xds_discovery.DiscoveryResponse, startedAt time.Time, cfg configurator.Configurator) error {
 	// Tracks the success of this TypeURI response operation; accounts also for receipt on envoy server side
 	success := false
 	xdsShortName := envoy.XDSShortURINames[tURI]
-	defer xdsPathTimeTrack(time.Now(), log.Debug(), xdsShortName, proxy.GetCertificateSerialNumber().String(), &success)
+	defer xdsPathTimeTrack(startedAt, log.Debug(), xdsShortName, proxy.GetCertificateSerialNumber().String(), &success)
 
-	log.Trace().Msgf("[%s] Creating response for proxy with SerialNumber=%s on Pod with UID=%s", xdsShortName, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+	discoveryResponse.Nonce = proxy.SetNewNonce(tURI)
+	discoveryResponse.VersionInfo = strconv.FormatUint(proxy.IncrementLastSentVersion(tURI), 10)
 
-	discoveryResponse, err := s.newAggregatedDiscoveryResponse(proxy, req, cfg)
-	if err != nil {
-		log.Error().Err(err).Msgf("[%s] Failed to create response for proxy with SerialNumber=%s on Pod with UID=%s", xdsShortName, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
-		return err
-	}
+	// NOTE: Never log entire 'response' - will contain secrets!
+	log.Trace().Msgf("Constructed %s response: VersionInfo=%s", discoveryResponse.TypeUrl, discoveryResponse.VersionInfo)
 
 	if err := (*server).Send(discoveryResponse); err != nil {
 		log.Error().Err(err).Msgf("[%s] Error sending to proxy with SerialNumber=%s on Pod with UID=%s", xdsShortName, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		return err
 	}
 
+	// The responses sent to the proxy are recorded for the debug server to tell what each response changed
+	if s.cfg.IsDebugServerEnabled() {
+		version, _ := strconv.ParseUint(discoveryResponse.VersionInfo, 10, 64)
+		proxy.RecordPush(tURI, version, discoveryResponse.Resources)
+	}
+
+	// The responses whose size is limited are recorded to tell whether the next ones only add resources
+	if isThrottledTypeURI(tURI) {
+		if cfg.GetMaxProxyConfigSize() > 0 {
+			proxy.RecordSentConfig(tURI, int64(proto.Size(discoveryResponse)), discoveryResponse.Resources)
+		} else {
+			proxy.ForgetSentConfig(tURI)
+		}
+	}
+
 	success = true // read by deferred function
 	return nil
 }
@@ -55,8 +70,9 @@ func (s *Server) sendResponse(typeURIsToSend mapset.Set,
 		defer xdsPathTimeTrack(time.Now(), log.Info(), ADSUpdateStr, proxy.GetCertificateSerialNumber().String(), &success)
 	}
 
-	// Order is important: CDS, EDS, LDS, RDS
-	// See: https://github.com/envoyproxy/go-control-plane/issues/59
+	// All the responses are generated before any is sent, so that the CDS, LDS and RDS responses can be withheld together
+	responses := make(map[envoy.TypeURI]*xds_discovery.DiscoveryResponse)
+	startedAt := make(map[envoy.TypeURI]time.Time)
 	for _, typeURI := range envoy.XDSResponseOrder {
 		if !typeURIsToSend.Contains(typeURI) {
 			continue
@@ -65,11 +81,22 @@ func (s *Server) sendResponse(typeURIsToSend mapset.Set,
 		// Handle request when is not provided, and the SDS case
 		var finalReq *xds_discovery.DiscoveryRequest
 		if request == nil || request.TypeUrl == envoy.TypeWildcard.String() {
-			if typeURI == envoy.TypeSDS {
+			if typeURI == envoy.TypeSDS && s.isNodeProxy(proxy) {
+				finalReq = makeRequestForAllNodeProxySecrets(proxy, s.catalog)
+				if finalReq == nil {
+					continue
+				}
+			} else if typeURI == envoy.TypeSDS && s.isEgressGateway(proxy) {
+				finalReq = makeRequestForAllEgressGatewaySecrets(s.osmNamespace, s.catalog)
+			} else if typeURI == envoy.TypeSDS {
 				finalReq = makeRequestForAllSecrets(proxy, s.catalog)
 				if finalReq == nil {
 					continue
 				}
+				// The sidecar proxies validate the egress gateway they forward their egress traffic to
+				if featureflags.IsEgressGatewayEnabled() {
+					finalReq.ResourceNames = append(finalReq.ResourceNames, getEgressGatewayRootCertName(s.osmNamespace))
+				}
 			} else {
 				finalReq = &xds_discovery.DiscoveryRequest{TypeUrl: string(typeURI)}
 			}
@@ -77,10 +104,36 @@ func (s *Server) sendResponse(typeURIsToSend mapset.Set,
 			finalReq = request
 		}
 
-		err := s.sendTypeResponse(typeURI, proxy, server, finalReq, cfg)
+		startedAt[typeURI] = time.Now()
+		log.Trace().Msgf("[%s] Creating response for proxy with SerialNumber=%s on Pod with UID=%s", envoy.XDSShortURINames[typeURI], proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+		response, err := s.newAggregatedDiscoveryResponse(proxy, finalReq, cfg)
 		if err != nil {
 			log.Error().Err(err).Msgf("Failed to create %s update for Proxy %s",
 				envoy.XDSShortURINames[typeURI], proxy.GetCertificateCommonName())
+			typeSuccess := false
+			xdsPathTimeTrack(startedAt[typeURI], log.Debug(), envoy.XDSShortURINames[typeURI], proxy.GetCertificateSerialNumber().String(), &typeSuccess)
+			success = false
+			continue
+		}
+		responses[typeURI] = response
+	}
+
+	if s.isConfigThrottled(proxy, responses, cfg) {
+		for _, typeURI := range throttledTypeURIs {
+			delete(responses, typeURI)
+		}
+		success = false
+	}
+
+	for _, typeURI := range envoy.XDSResponseOrder {
+		response, ok := responses[typeURI]
+		if !ok {
+			continue
+		}
+		err := s.sendTypeResponse(typeURI, proxy, server, response, startedAt[typeURI], cfg)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to send %s update for Proxy %s",
+				envoy.XDSShortURINames[typeURI], proxy.GetCertificateCommonName())
 			success = false
 		}
 	}
@@ -88,9 +141,26 @@ func (s *Server) sendResponse(typeURIsToSend mapset.Set,
 	return nil
 }
 
+// isThrottledTypeURI returns true if the size of the xDS responses of the given type is limited by the max proxy config
+// size
+func isThrottledTypeURI(typeURI envoy.TypeURI) bool {
+	for _, throttled := range throttledTypeURIs {
+		if typeURI == throttled {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) newAggregatedDiscoveryResponse(proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator) (*xds_discovery.DiscoveryResponse, error) {
 	typeURL := envoy.TypeURI(request.TypeUrl)
-	handler, ok := s.xdsHandlers[typeURL]
+	handlers := s.xdsHandlers
+	if s.isNodeProxy(proxy) {
+		handlers = s.nodeProxyXDSHandlers
+	} else if s.isEgressGateway(proxy) {
+		handlers = s.egressGatewayXDSHandlers
+	}
+	handler, ok := handlers[typeURL]
 	if !ok {
 		log.Error().Msgf("Responder for TypeUrl %s is not implemented", request.TypeUrl)
 		return nil, errUnknownTypeURL
@@ -113,11 +183,9 @@ func (s *Server) newAggregatedDiscoveryResponse(proxy *envoy.Proxy, request *xds
 		return nil, errCreatingResponse
 	}
 
-	response.Nonce = proxy.SetNewNonce(typeURL)
-	response.VersionInfo = strconv.FormatUint(proxy.IncrementLastSentVersion(typeURL), 10)
-
-	// NOTE: Never log entire 'response' - will contain secrets!
-	log.Trace().Msgf("Constructed %s response: VersionInfo=%s", response.TypeUrl, response.VersionInfo)
+	if isResponseInvalid(proxy, response) {
+		return nil, errInvalidResponse
+	}
 
 	return response, nil
 }
@@ -117,9 +117,18 @@ var _ = Describe("Test ADS response functions", func() {
 		mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+		mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+		mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+		mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+		mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(certDuration).AnyTimes()
 		mockConfigurator.EXPECT().IsDebugServerEnabled().Return(true).AnyTimes()
+		mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(int64(0)).AnyTimes()
 
 		It("returns Aggregated Discovery Service response", func() {
 			s := NewADSServer(mc, true, tests.Namespace, mockConfigurator, mockCertManager)
@@ -163,7 +172,7 @@ var _ = Describe("Test ADS response functions", func() {
 			var tmpResource *any.Any
 
 			proxyServiceCert := xds_auth.Secret{}
-			tmpResource = (*actualResponses)[4].Resources[0]
+			tmpResource = (*actualResponses)[4].Resources[2]
 			err = ptypes.UnmarshalAny(tmpResource, &proxyServiceCert)
 			Expect(err).To(BeNil())
 			Expect(proxyServiceCert.Name).To(Equal(envoy.SDSCert{
@@ -172,7 +181,7 @@ var _ = Describe("Test ADS response functions", func() {
 			}.String()))
 
 			serverRootCertTypeForMTLSInbound := xds_auth.Secret{}
-			tmpResource = (*actualResponses)[4].Resources[1]
+			tmpResource = (*actualResponses)[4].Resources[0]
 			err = ptypes.UnmarshalAny(tmpResource, &serverRootCertTypeForMTLSInbound)
 			Expect(err).To(BeNil())
 			Expect(serverRootCertTypeForMTLSInbound.Name).To(Equal(envoy.SDSCert{
@@ -181,7 +190,7 @@ var _ = Describe("Test ADS response functions", func() {
 			}.String()))
 
 			serverRootCertTypeForHTTPS := xds_auth.Secret{}
-			tmpResource = (*actualResponses)[4].Resources[2]
+			tmpResource = (*actualResponses)[4].Resources[1]
 			err = ptypes.UnmarshalAny(tmpResource, &serverRootCertTypeForHTTPS)
 			Expect(err).To(BeNil())
 			Expect(serverRootCertTypeForHTTPS.Name).To(Equal(envoy.SDSCert{
@@ -203,9 +212,18 @@ var _ = Describe("Test ADS response functions", func() {
 		mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+		mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+		mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+		mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+		mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+		mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(certDuration).AnyTimes()
 		mockConfigurator.EXPECT().IsDebugServerEnabled().Return(true).AnyTimes()
+		mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(int64(0)).AnyTimes()
 
 		It("returns Aggregated Discovery Service response", func() {
 			s := NewADSServer(mc, true, tests.Namespace, mockConfigurator, mockCertManager)
@@ -232,7 +250,7 @@ var _ = Describe("Test ADS response functions", func() {
 			var tmpResource *any.Any
 
 			proxyServiceCert := xds_auth.Secret{}
-			tmpResource = sdsResponse.Resources[0]
+			tmpResource = sdsResponse.Resources[2]
 			err = ptypes.UnmarshalAny(tmpResource, &proxyServiceCert)
 			Expect(err).To(BeNil())
 			Expect(proxyServiceCert.Name).To(Equal(envoy.SDSCert{
@@ -241,7 +259,7 @@ var _ = Describe("Test ADS response functions", func() {
 			}.String()))
 
 			serverRootCertTypeForMTLSInbound := xds_auth.Secret{}
-			tmpResource = sdsResponse.Resources[1]
+			tmpResource = sdsResponse.Resources[0]
 			err = ptypes.UnmarshalAny(tmpResource, &serverRootCertTypeForMTLSInbound)
 			Expect(err).To(BeNil())
 			Expect(serverRootCertTypeForMTLSInbound.Name).To(Equal(envoy.SDSCert{
@@ -250,7 +268,7 @@ var _ = Describe("Test ADS response functions", func() {
 			}.String()))
 
 			serverRootCertTypeForHTTPS := xds_auth.Secret{}
-			tmpResource = sdsResponse.Resources[2]
+			tmpResource = sdsResponse.Resources[1]
 			err = ptypes.UnmarshalAny(tmpResource, &serverRootCertTypeForHTTPS)
 			Expect(err).To(BeNil())
 			Expect(serverRootCertTypeForHTTPS.Name).To(Equal(envoy.SDSCert{
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // makeRequestForAllSecrets constructs an SDS DiscoveryRequest as if an Envoy proxy sent it.
@@ -16,6 +17,8 @@ import (
 // 3. Server's service certificate when this proxy is an upstream: service-cert:<namespace>/<server-service-name>
 // 4. Server's root validation certificate to validate downstream clients during mTLS handshake: root-cert-for-mtls-inbound:<namespace>/<server-service-name>
 // 5. Server's root validation certificate to validate downstream clients during TLS handshake: root-cert-https:<namespace>/<server-service-name>
+// 6. Server's CA bundle to validate ingress clients configured by an IngressBackend: root-cert-for-ingress-mtls:<namespace>/<secret-name>
+// 7. Client's CA bundle and client certificate to originate TLS to external hosts configured by an Egress: root-cert-for-egress-tls:<namespace>/<secret-name> and egress-client-cert:<namespace>/<secret-name>
 //
 // This request will be sent to SDS which will return certificates encoded in SDS secrets corresponding to the resource names
 // encoded in the DiscoveryRequest this function creates and returns.
@@ -76,5 +79,53 @@ func makeRequestForAllSecrets(proxy *envoy.Proxy, meshCatalog catalog.MeshCatalo
 		discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, upstreamRootCertResource)
 	}
 
+	// Create the SDS certs referenced by the TLS originations of the Egress policies of this proxy
+	discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, getEgressTLSSecretNames(meshCatalog.GetEgressPolicy(proxyIdentity))...)
+
+	// Create an SDS validation cert corresponding to the CA bundle configured by the IngressBackend of each service of
+	// this proxy. Each cert is used to validate the client certificates presented by the ingress sources.
+	proxyServices, err := meshCatalog.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up services for proxy with SerialNumber=%s on Pod with UID=%s",
+			proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+		return discoveryRequest
+	}
+	caBundleSecrets := make(map[string]bool)
+	for _, proxyService := range proxyServices {
+		policy, err := meshCatalog.GetIngressBackendPolicy(proxyService)
+		if err != nil || policy == nil || policy.CABundleSecret == "" || caBundleSecrets[policy.CABundleSecret] {
+			continue
+		}
+		caBundleSecrets[policy.CABundleSecret] = true
+		discoveryRequest.ResourceNames = append(discoveryRequest.ResourceNames, envoy.SDSCert{
+			Name:     policy.CABundleSecret,
+			CertType: envoy.RootCertTypeForIngressMTLS,
+		}.String())
+	}
+
 	return discoveryRequest
 }
+
+// getEgressTLSSecretNames returns the names of the SDS certs originating TLS to the external hosts of the given egress
+// policy: the CA bundles validating the external hosts, and the client certificates presented to them
+func getEgressTLSSecretNames(egressPolicy *trafficpolicy.EgressPolicy) []string {
+	if egressPolicy == nil {
+		return nil
+	}
+
+	var names []string
+	caBundleSecrets, clientCertSecrets := egressPolicy.GetTLSOriginationSecrets()
+	for _, secret := range caBundleSecrets {
+		names = append(names, envoy.SDSCert{
+			Name:     secret,
+			CertType: envoy.RootCertTypeForEgressTLS,
+		}.String())
+	}
+	for _, secret := range clientCertSecrets {
+		names = append(names, envoy.SDSCert{
+			Name:     secret,
+			CertType: envoy.EgressClientCertType,
+		}.String())
+	}
+	return names
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestMakeRequestForAllSecrets(t *testing.T) {
@@ -27,6 +28,8 @@ func TestMakeRequestForAllSecrets(t *testing.T) {
 		proxySvcAccount          service.K8sServiceAccount
 		proxyServices            []service.MeshService
 		allowedOutboundServices  []service.MeshService
+		ingressBackendPolicies   map[service.MeshService]*trafficpolicy.IngressBackendPolicy
+		egressPolicy             *trafficpolicy.EgressPolicy
 		expectedDiscoveryRequest *xds_discovery.DiscoveryRequest
 	}
 
@@ -132,12 +135,68 @@ func TestMakeRequestForAllSecrets(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "scenario where the services of the proxy have IngressBackends with a CA bundle",
+			proxySvcAccount: proxySvcAccount,
+			proxyServices: []service.MeshService{
+				{Name: "service-1", Namespace: "ns-1"},
+				{Name: "service-4", Namespace: "ns-1"},
+			},
+			allowedOutboundServices: nil,
+			ingressBackendPolicies: map[service.MeshService]*trafficpolicy.IngressBackendPolicy{
+				{Name: "service-1", Namespace: "ns-1"}: {CABundleSecret: "ns-1/ingress-ca"},
+				{Name: "service-4", Namespace: "ns-1"}: {CABundleSecret: "ns-1/ingress-ca"},
+			},
+			expectedDiscoveryRequest: &xds_discovery.DiscoveryRequest{
+				TypeUrl: string(envoy.TypeSDS),
+				ResourceNames: []string{
+					// 1. Proxy's own cert to present to peer during mTLS/TLS handshake
+					"service-cert:ns-1/test-sa",
+
+					// 4. Inbound validation certs to validate downstreams
+					"root-cert-for-mtls-inbound:ns-1/test-sa",
+					"root-cert-https:ns-1/test-sa",
+
+					// 5. CA bundle shared by the IngressBackends of the services to validate ingress clients
+					"root-cert-for-ingress-mtls:ns-1/ingress-ca",
+				},
+			},
+		},
+		{
+			name:            "scenario where the Egress policies of the proxy originate TLS to external hosts",
+			proxySvcAccount: proxySvcAccount,
+			egressPolicy: &trafficpolicy.EgressPolicy{
+				TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+					80:   {"httpbin.org": {Port: 443, CABundleSecret: "ns-1/httpbin-ca", ClientCertSecret: "ns-1/httpbin-client"}},
+					8080: {"httpbin.org": {Port: 8443, CABundleSecret: "ns-1/httpbin-ca"}},
+				},
+			},
+			expectedDiscoveryRequest: &xds_discovery.DiscoveryRequest{
+				TypeUrl: string(envoy.TypeSDS),
+				ResourceNames: []string{
+					// 1. Proxy's own cert to present to peer during mTLS/TLS handshake
+					"service-cert:ns-1/test-sa",
+
+					// 4. Inbound validation certs to validate downstreams
+					"root-cert-for-mtls-inbound:ns-1/test-sa",
+					"root-cert-https:ns-1/test-sa",
+
+					// 6. CA bundle and client certificate to originate TLS to external hosts
+					"root-cert-for-egress-tls:ns-1/httpbin-ca",
+					"egress-client-cert:ns-1/httpbin-client",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return(tc.proxyServices, nil).Times(0)
+			mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return(tc.proxyServices, nil).Times(1)
+			for _, proxyService := range tc.proxyServices {
+				mockCatalog.EXPECT().GetIngressBackendPolicy(proxyService).Return(tc.ingressBackendPolicies[proxyService], nil).Times(1)
+			}
 			mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tc.proxySvcAccount).Return(tc.allowedOutboundServices).Times(1)
+			mockCatalog.EXPECT().GetEgressPolicy(tc.proxySvcAccount).Return(tc.egressPolicy).Times(1)
 
 			actual := makeRequestForAllSecrets(testProxy, mockCatalog)
 
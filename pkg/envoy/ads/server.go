@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	xds_accesslog_service "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
@@ -33,11 +34,29 @@ func NewADSServer(meshCatalog catalog.MeshCataloger, enableDebug bool, osmNamesp
 			envoy.TypeLDS: lds.NewResponse,
 			envoy.TypeSDS: sds.NewResponse,
 		},
-		osmNamespace:   osmNamespace,
-		cfg:            cfg,
-		certManager:    certManager,
-		xdsMapLogMutex: sync.Mutex{},
-		xdsLog:         make(map[certificate.CommonName]map[envoy.TypeURI][]time.Time),
+		nodeProxyXDSHandlers: map[envoy.TypeURI]func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager) (*xds_discovery.DiscoveryResponse, error){
+			envoy.TypeEDS: newEmptyNodeProxyResponse,
+			envoy.TypeCDS: cds.NewNodeProxyResponse,
+			envoy.TypeRDS: newEmptyNodeProxyResponse,
+			envoy.TypeLDS: lds.NewNodeProxyResponse,
+			envoy.TypeSDS: sds.NewNodeProxyResponse,
+		},
+		egressGatewayXDSHandlers: map[envoy.TypeURI]func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager) (*xds_discovery.DiscoveryResponse, error){
+			envoy.TypeEDS: newEmptyEgressGatewayResponse,
+			envoy.TypeCDS: cds.NewEgressGatewayResponse,
+			envoy.TypeRDS: newEmptyEgressGatewayResponse,
+			envoy.TypeLDS: lds.NewEgressGatewayResponse,
+			envoy.TypeSDS: sds.NewEgressGatewayResponse,
+		},
+		osmNamespace:      osmNamespace,
+		cfg:               cfg,
+		certManager:       certManager,
+		xdsMapLogMutex:    sync.Mutex{},
+		xdsLog:            make(map[certificate.CommonName]map[envoy.TypeURI][]time.Time),
+		rbacDenials:       make(map[string]*envoy.RBACDenial),
+		observedRequests:  make(map[string]*envoy.ObservedRequest),
+		egressMetricHosts: make(map[string]bool),
+		proxyStatsURL:     getProxyStatsURL,
 	}
 
 	return &server
@@ -59,6 +78,7 @@ func (s *Server) Start(ctx context.Context, cancel context.CancelFunc, port int,
 	}
 
 	xds_discovery.RegisterAggregatedDiscoveryServiceServer(grpcServer, s)
+	xds_accesslog_service.RegisterAccessLogServiceServer(grpcServer, s)
 	go utils.GrpcServe(ctx, grpcServer, lis, cancel, ServerType, nil)
 	s.ready = true
 
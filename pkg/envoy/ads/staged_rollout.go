@@ -0,0 +1,30 @@
+package ads
+
+import (
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// getConfiguratorForProxy returns the configurator the configuration of the given proxy is generated with. While a
+// change of the OSM ConfigMap is rolled out in stages, the proxies that are not canary proxies keep the configuration
+// preceding the change, including the proxies whose pod is not known yet.
+func (s *Server) getConfiguratorForProxy(proxy *envoy.Proxy) configurator.Configurator {
+	rollout := s.cfg.GetStagedRollout()
+	if rollout == nil {
+		return s.cfg
+	}
+	if proxy.PodMetadata != nil && rollout.IsCanary(proxy.PodMetadata.Namespace, proxy.PodMetadata.Name) {
+		return s.cfg
+	}
+	return rollout.Stable
+}
+
+// recordStagedRolloutRejection records the rejection of an xDS response by the given proxy if it is a canary proxy of
+// the staged rollout in progress
+func (s *Server) recordStagedRolloutRejection(proxy *envoy.Proxy) {
+	rollout := s.cfg.GetStagedRollout()
+	if rollout == nil || proxy.PodMetadata == nil || !rollout.IsCanary(proxy.PodMetadata.Namespace, proxy.PodMetadata.Name) {
+		return
+	}
+	rollout.RecordRejection()
+}
@@ -0,0 +1,74 @@
+package ads
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestGetConfiguratorForProxy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	stable := configurator.NewMockConfigurator(mockCtrl)
+	rollout := &configurator.StagedRollout{
+		Namespaces: map[string]bool{"bookstore": true},
+		Stable:     stable,
+	}
+
+	testCases := []struct {
+		name           string
+		rollout        *configurator.StagedRollout
+		podMetadata    *envoy.PodMetadata
+		expectedStable bool
+	}{
+		{
+			name:           "no staged rollout in progress",
+			rollout:        nil,
+			podMetadata:    &envoy.PodMetadata{Namespace: "bookbuyer", Name: "bookbuyer-1234"},
+			expectedStable: false,
+		},
+		{
+			name:           "canary proxy",
+			rollout:        rollout,
+			podMetadata:    &envoy.PodMetadata{Namespace: "bookstore", Name: "bookstore-v1-1234"},
+			expectedStable: false,
+		},
+		{
+			name:           "proxy that is not a canary proxy",
+			rollout:        rollout,
+			podMetadata:    &envoy.PodMetadata{Namespace: "bookbuyer", Name: "bookbuyer-1234"},
+			expectedStable: true,
+		},
+		{
+			name:           "proxy whose pod is not known",
+			rollout:        rollout,
+			podMetadata:    nil,
+			expectedStable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetStagedRollout().Return(tc.rollout).Times(1)
+			s := &Server{cfg: mockConfigurator}
+
+			proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+			proxy.PodMetadata = tc.podMetadata
+
+			if tc.expectedStable {
+				assert.Same(stable, s.getConfiguratorForProxy(proxy))
+			} else {
+				assert.Same(mockConfigurator, s.getConfiguratorForProxy(proxy))
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -41,8 +42,16 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 	//       When this arrives we will call RegisterProxy() a second time - this time with Pod context!
 	proxy := envoy.NewProxy(certCommonName, certSerialNumber, utils.GetIPFromContext(server.Context()))
 	s.catalog.RegisterProxy(proxy) // First of Two invocations.  Second one will be during xDS hand-shake!
+	events.GetPubSubInstance().Publish(events.PubSubMessage{
+		AnnouncementType: announcements.ProxyConnected,
+		NewObj:           proxy,
+	})
 
 	defer s.catalog.UnregisterProxy(proxy)
+	defer events.GetPubSubInstance().Publish(events.PubSubMessage{
+		AnnouncementType: announcements.ProxyDisconnected,
+		OldObj:           proxy,
+	})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -54,6 +63,9 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 	// and any gRPC error states.
 	go receive(requests, &server, proxy, quit, s.catalog)
 
+	// The memory pressure of the proxy is refreshed in the background, and read when its config may be throttled
+	go s.refreshMemoryPressure(proxy, ctx.Done())
+
 	// Register to Envoy global broadcast updates
 	broadcastUpdate := events.GetPubSubInstance().Subscribe(announcements.ProxyBroadcast)
 
@@ -69,10 +81,14 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 		envoy.TypeLDS,
 		envoy.TypeRDS,
 		envoy.TypeSDS),
-		proxy, &server, nil, s.cfg)
+		proxy, &server, nil, s.getConfiguratorForProxy(proxy))
 	if err != nil {
 		log.Error().Err(err).Msgf("Initial sendResponse for proxy %s returned error", proxy.GetCertificateSerialNumber())
 	}
+	// The initial responses include the configuration generation last broadcast to the proxies
+	if generation := s.catalog.GetLatestConfigGeneration(); generation != nil {
+		proxy.SetPendingConfigGeneration(*generation)
+	}
 
 	for {
 		select {
@@ -99,6 +115,15 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 			if discoveryRequest.ErrorDetail != nil {
 				log.Error().Msgf("[NACK] DiscoveryRequest error from Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s: %s",
 					proxy.GetCertificateSerialNumber(), proxy.GetPodUID(), discoveryRequest.ErrorDetail)
+				s.recordStagedRolloutRejection(proxy)
+				events.GetPubSubInstance().Publish(events.PubSubMessage{
+					AnnouncementType: announcements.ProxyConfigRejected,
+					NewObj: &envoy.ConfigRejection{
+						Proxy:   proxy,
+						TypeURL: discoveryRequest.TypeUrl,
+						Message: discoveryRequest.ErrorDetail.GetMessage(),
+					},
+				})
 				// NOTE(draychev): We could also return errEnvoyError - but it seems appropriate to also ignore this request and continue on.
 				continue
 			}
@@ -137,6 +162,22 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 
 			proxy.SetLastAppliedVersion(typeURL, ackVersion)
 
+			if typeURL == envoy.TypeSDS {
+				if rotatedAt, ok := proxy.AcknowledgeCertificateRotation(ackVersion); ok {
+					propagationTime := time.Since(rotatedAt)
+					metricsstore.DefaultMetricsStore.CertRotationPropagationTime.WithLabelValues().Observe(propagationTime.Seconds())
+					log.Debug().Msgf("Rotated certificate acknowledged by Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s after %+v",
+						proxy.GetCertificateSerialNumber(), proxy.GetPodUID(), propagationTime)
+				}
+			}
+
+			if generation, ok := proxy.AcknowledgeConfigGeneration(typeURL, ackVersion); ok {
+				propagationTime := time.Since(generation.CreatedAt)
+				metricsstore.DefaultMetricsStore.ConfigGenerationPropagationTime.WithLabelValues().Observe(propagationTime.Seconds())
+				log.Debug().Msgf("Configuration generation %d acknowledged by Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s after %+v",
+					generation.ID, proxy.GetCertificateSerialNumber(), proxy.GetPodUID(), propagationTime)
+			}
+
 			// In the DiscoveryRequest we have a VersionInfo field.
 			// When this is smaller or equal to what we last sent to this proxy - it is
 			// interpreted as an acknowledgement of a previously sent request.
@@ -185,14 +226,24 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 				xdsUpdatePaths = mapset.NewSetWith(typeURL)
 			}
 
-			err = s.sendResponse(xdsUpdatePaths, proxy, &server, &discoveryRequest, s.cfg)
+			err = s.sendResponse(xdsUpdatePaths, proxy, &server, &discoveryRequest, s.getConfiguratorForProxy(proxy))
 			if err != nil {
 				log.Error().Err(err).Msgf("Failed to create and send %s update to Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s",
 					envoy.XDSShortURINames[typeURL], proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 				continue
 			}
 
-		case <-broadcastUpdate:
+		case broadcastMsg := <-broadcastUpdate:
+			generation, hasGeneration := getBroadcastConfigGeneration(broadcastMsg)
+			if hasGeneration && !s.isProxyAffectedByConfigGeneration(proxy, generation) {
+				log.Debug().Msgf("Skipping broadcast of configuration generation %d for Proxy SerialNumber=%s UID=%s, not affected by its changes",
+					generation.ID, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+				metricsstore.DefaultMetricsStore.ConfigGenerationSkippedCount.Inc()
+				// The configuration of the proxy already includes the generation once the responses last sent are acknowledged
+				proxy.SetPendingConfigGeneration(generation)
+				continue
+			}
+
 			log.Info().Msgf("Broadcast wake for Proxy SerialNumber=%s UID=%s", proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 			err := s.sendResponse(mapset.NewSetWith(
 				envoy.TypeCDS,
@@ -200,21 +251,32 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 				envoy.TypeLDS,
 				envoy.TypeRDS,
 				envoy.TypeSDS),
-				proxy, &server, nil, s.cfg)
+				proxy, &server, nil, s.getConfiguratorForProxy(proxy))
 			if err != nil {
 				log.Error().Err(err).Msgf("Failed to create and send ADS update to Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s",
 					proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 				continue
 			}
+			// The rollout of the broadcast configuration generation is tracked until the proxy acknowledges the responses
+			// sent for it
+			if hasGeneration {
+				proxy.SetPendingConfigGeneration(generation)
+			}
 
 		case certUpdateMsg := <-certAnnouncement:
-			certificate := certUpdateMsg.(events.PubSubMessage).NewObj.(certificate.Certificater)
-			if isCNforProxy(proxy, certificate.GetCommonName()) {
+			rotatedAt := time.Now()
+			// Certificates rotated around the same time are pushed to the proxy with a single SDS response
+			certUpdateMsgs := append([]interface{}{certUpdateMsg}, drainAnnouncements(certAnnouncement)...)
+			if s.isAnyCertForProxy(proxy, certUpdateMsgs) {
 				// The CN whose corresponding certificate was updated (rotated) by the certificate provider is associated
-				// with this proxy, so update the secrets corresponding to this certificate via SDS.
-				log.Debug().Msgf("Certificate has been updated for proxy with SerialNumber=%s, UID=%s", proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+				// with this proxy, so push the secrets corresponding to this certificate via SDS right away, instead of
+				// waiting for the proxy to request them.
+				log.Debug().Msgf("Certificate has been updated for proxy with SerialNumber=%s, UID=%s; %d certificate rotation(s) batched",
+					proxy.GetCertificateSerialNumber(), proxy.GetPodUID(), len(certUpdateMsgs))
+				// The rotation propagation time is observed when the proxy acknowledges an SDS response sent from now on
+				proxy.SetPendingCertificateRotation(rotatedAt, proxy.GetLastSentVersion(envoy.TypeSDS)+1)
 				// Empty DiscoveryRequest should create the SDS specific request
-				err := s.sendResponse(mapset.NewSetWith(envoy.TypeSDS), proxy, &server, nil, s.cfg)
+				err := s.sendResponse(mapset.NewSetWith(envoy.TypeSDS), proxy, &server, nil, s.getConfiguratorForProxy(proxy))
 				if err != nil {
 					log.Error().Err(err).Msgf("Failed to create and send SDS update to Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s",
 						proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
@@ -225,23 +287,104 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 	}
 }
 
-// isCNforProxy returns true if the given CN for the workload certificate matches the given proxy's identity.
+// drainAnnouncements returns the announcements already pending on the given channel, without waiting for new ones
+func drainAnnouncements(announcementCh <-chan interface{}) []interface{} {
+	var pending []interface{}
+	for {
+		select {
+		case msg := <-announcementCh:
+			pending = append(pending, msg)
+		default:
+			return pending
+		}
+	}
+}
+
+// isAnyCertForProxy returns true if the certificate of any of the given certificate rotation announcements is
+// associated with the given proxy
+func (s *Server) isAnyCertForProxy(proxy *envoy.Proxy, certUpdateMsgs []interface{}) bool {
+	for _, msg := range certUpdateMsgs {
+		psubMsg, ok := msg.(events.PubSubMessage)
+		if !ok {
+			log.Error().Msgf("Unexpected message type %T received on certificate rotation subscription", msg)
+			continue
+		}
+		cert, ok := psubMsg.NewObj.(certificate.Certificater)
+		if !ok {
+			log.Error().Msgf("Unexpected object type %T for rotated certificate", psubMsg.NewObj)
+			continue
+		}
+		if s.isCNforProxy(proxy, cert.GetCommonName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCNforProxy returns true if the given CN for the workload certificate matches the given proxy's identity, or for
+// node proxies, the identity of one of the pods fronted by the node proxy.
 // Proxy identity corresponds to the k8s service account, while the workload certificate is of the form
 // <svc-account>.<namespace>.<trust-domain>.
-func isCNforProxy(proxy *envoy.Proxy, cn certificate.CommonName) bool {
+func (s *Server) isCNforProxy(proxy *envoy.Proxy, cn certificate.CommonName) bool {
+	// Workload certificate CN is of the form <svc-account>.<namespace>.<trust-domain>
+	chunks := strings.Split(cn.String(), constants.DomainDelimiter)
+	if len(chunks) < 3 {
+		return false
+	}
+	identityForCN := service.K8sServiceAccount{Name: chunks[0], Namespace: chunks[1]}
+
+	if s.isNodeProxy(proxy) {
+		pods, err := s.catalog.ListPodsForNodeProxy(proxy)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error listing pods for node proxy with SerialNumber=%s", proxy.GetCertificateSerialNumber())
+			return false
+		}
+		for _, pod := range pods {
+			if identityForCN == (service.K8sServiceAccount{Name: pod.Spec.ServiceAccountName, Namespace: pod.Namespace}) {
+				return true
+			}
+		}
+		return false
+	}
+
 	proxyIdentity, err := catalog.GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
 		log.Error().Err(err).Msgf("Error looking up proxy identity for proxy with SerialNumber=%s on Pod with UID=%s",
 			proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		return false
 	}
+	return identityForCN == proxyIdentity
+}
 
-	// Workload certificate CN is of the form <svc-account>.<namespace>.<trust-domain>
-	chunks := strings.Split(cn.String(), constants.DomainDelimiter)
-	if len(chunks) < 3 {
-		return false
+// getBroadcastConfigGeneration returns the configuration generation of the given broadcast announcement, and false if
+// the announcement does not have one
+func getBroadcastConfigGeneration(broadcastMsg interface{}) (envoy.ConfigGeneration, bool) {
+	psubMsg, ok := broadcastMsg.(events.PubSubMessage)
+	if !ok {
+		return envoy.ConfigGeneration{}, false
 	}
+	generation, ok := psubMsg.NewObj.(envoy.ConfigGeneration)
+	return generation, ok
+}
 
-	identityForCN := service.K8sServiceAccount{Name: chunks[0], Namespace: chunks[1]}
-	return identityForCN == proxyIdentity
+// isProxyAffectedByConfigGeneration returns true if the changes of the given configuration generation may affect the
+// configuration of the given proxy, which is the case unless the generation is scoped to services the proxy does not front
+func (s *Server) isProxyAffectedByConfigGeneration(proxy *envoy.Proxy, generation envoy.ConfigGeneration) bool {
+	if generation.Services == nil || s.isNodeProxy(proxy) || s.isEgressGateway(proxy) {
+		return true
+	}
+
+	proxyServices, err := s.catalog.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		// The services of a proxy not backed by a pod are unknown
+		return true
+	}
+	for _, proxyService := range proxyServices {
+		for _, svc := range generation.Services {
+			if proxyService.Name == svc.Name && proxyService.Namespace == svc.Namespace {
+				return true
+			}
+		}
+	}
+	return false
 }
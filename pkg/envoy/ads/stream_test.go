@@ -4,11 +4,18 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 func TestIsCNForProxy(t *testing.T) {
@@ -44,10 +51,155 @@ func TestIsCNForProxy(t *testing.T) {
 		},
 	}
 
+	s := &Server{}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			actual := isCNforProxy(tc.proxy, tc.cn)
+			actual := s.isCNforProxy(tc.proxy, tc.cn)
 			assert.Equal(tc.expected, actual)
 		})
 	}
 }
+
+func TestIsCNForNodeProxy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oldNodeProxyFlag := featureflags.Features.NodeProxyMode
+	featureflags.Features.NodeProxyMode = true
+	defer func() {
+		featureflags.Features.NodeProxyMode = oldNodeProxyFlag
+	}()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	s := &Server{catalog: mockCatalog}
+	proxy := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.osm-node-proxy.osm-system", uuid.New())), "123456", nil)
+
+	mockCatalog.EXPECT().IsNodeProxy(proxy).Return(true).AnyTimes()
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return([]*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer-1", Namespace: "bookbuyer"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "bookbuyer"},
+		},
+	}, nil).AnyTimes()
+
+	// The certificates of the pods fronted by a node proxy belong to the node proxy
+	assert.True(s.isCNforProxy(proxy, "bookbuyer.bookbuyer.cluster.local"))
+	assert.False(s.isCNforProxy(proxy, "bookstore.bookstore.cluster.local"))
+	assert.False(s.isCNforProxy(proxy, "osm-node-proxy.osm-system.cluster.local"))
+}
+
+func TestDrainAnnouncements(t *testing.T) {
+	assert := tassert.New(t)
+
+	announcementCh := make(chan interface{}, 3)
+	assert.Empty(drainAnnouncements(announcementCh))
+
+	announcementCh <- "a"
+	announcementCh <- "b"
+	assert.Equal([]interface{}{"a", "b"}, drainAnnouncements(announcementCh))
+	assert.Empty(announcementCh)
+}
+
+func TestIsAnyCertForProxy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newCertUpdateMsg := func(cn certificate.CommonName) events.PubSubMessage {
+		cert := certificate.NewMockCertificater(mockCtrl)
+		cert.EXPECT().GetCommonName().Return(cn).AnyTimes()
+		return events.PubSubMessage{NewObj: cert}
+	}
+
+	s := &Server{}
+	proxy := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.bookbuyer.bookbuyer", uuid.New())), "123456", nil)
+
+	assert.False(s.isAnyCertForProxy(proxy, nil))
+	assert.False(s.isAnyCertForProxy(proxy, []interface{}{
+		newCertUpdateMsg("bookstore.bookstore.cluster.local"),
+		"unexpected message",
+	}))
+	assert.True(s.isAnyCertForProxy(proxy, []interface{}{
+		newCertUpdateMsg("bookstore.bookstore.cluster.local"),
+		newCertUpdateMsg("bookbuyer.bookbuyer.cluster.local"),
+	}))
+}
+
+func TestIsProxyAffectedByConfigGeneration(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	s := &Server{catalog: mockCatalog}
+	proxy := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.bookstore.bookstore", uuid.New())), "123456", nil)
+	devProxy := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.bookstore.bookstore", uuid.New())), "654321", nil)
+
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName()).Return([]service.MeshService{
+		{Name: "bookstore", Namespace: "bookstore"},
+		{Name: "bookstore-v1", Namespace: "bookstore"},
+	}, nil).AnyTimes()
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(devProxy.GetCertificateCommonName()).Return(nil, catalog.ErrDidNotFindPodForCertificate).AnyTimes()
+
+	testCases := []struct {
+		name           string
+		proxy          *envoy.Proxy
+		services       []service.MeshService
+		expectedResult bool
+	}{
+		{
+			name:           "generation not scoped",
+			proxy:          proxy,
+			services:       nil,
+			expectedResult: true,
+		},
+		{
+			name:           "generation scoped to a service of the proxy",
+			proxy:          proxy,
+			services:       []service.MeshService{{Name: "bookstore-v1", Namespace: "bookstore"}},
+			expectedResult: true,
+		},
+		{
+			name:           "generation scoped to other services",
+			proxy:          proxy,
+			services:       []service.MeshService{{Name: "bookstore-v1", Namespace: "bookstore-canary"}},
+			expectedResult: false,
+		},
+		{
+			name:           "generation scoped to no service",
+			proxy:          proxy,
+			services:       []service.MeshService{},
+			expectedResult: false,
+		},
+		{
+			name:           "proxy whose services are unknown",
+			proxy:          devProxy,
+			services:       []service.MeshService{{Name: "bookstore-v1", Namespace: "bookstore"}},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			generation := envoy.ConfigGeneration{ID: 1, Services: tc.services}
+			assert.Equal(tc.expectedResult, s.isProxyAffectedByConfigGeneration(tc.proxy, generation))
+		})
+	}
+}
+
+func TestGetBroadcastConfigGeneration(t *testing.T) {
+	assert := tassert.New(t)
+
+	generation := envoy.ConfigGeneration{ID: 3}
+	actual, ok := getBroadcastConfigGeneration(events.PubSubMessage{NewObj: generation})
+	assert.True(ok)
+	assert.Equal(generation, actual)
+
+	_, ok = getBroadcastConfigGeneration(events.PubSubMessage{})
+	assert.False(ok)
+
+	_, ok = getBroadcastConfigGeneration("not a message")
+	assert.False(ok)
+}
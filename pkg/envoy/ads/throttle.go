@@ -0,0 +1,230 @@
+package ads
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// highMemoryPressure is the share of its memory limit a proxy must be using for the responses growing its config
+	// beyond the max proxy config size to be withheld from it
+	highMemoryPressure = 0.8
+
+	// throttledEventInterval is the minimum interval between the warning events recorded for the responses withheld
+	// from the same proxy
+	throttledEventInterval = 5 * time.Minute
+
+	// proxyStatsTimeout is the timeout of the requests for the stats of a proxy
+	proxyStatsTimeout = 2 * time.Second
+
+	// memoryPressureRefreshInterval is the interval at which the memory pressure of the proxies is refreshed
+	memoryPressureRefreshInterval = 15 * time.Second
+
+	// fixedHeapPressureStat is the pressure in percent reported by the fixed heap resource monitor of the overload
+	// manager of a proxy, when it is configured
+	fixedHeapPressureStat = "envoy_overload_envoy_resource_monitors_fixed_heap_pressure"
+
+	// memoryAllocatedStat is the memory in bytes currently allocated by a proxy
+	memoryAllocatedStat = "envoy_server_memory_allocated"
+)
+
+// throttledTypeURIs are the types of the xDS responses whose size is limited by the max proxy config size. The clusters,
+// listeners and routes reference each other, so their responses are withheld together: pushing some of them without
+// the others would leave the proxy referencing resources it does not have.
+var throttledTypeURIs = []envoy.TypeURI{envoy.TypeCDS, envoy.TypeLDS, envoy.TypeRDS}
+
+// proxyStatsClient is the HTTP client requesting the stats of the proxies
+var proxyStatsClient = &http.Client{Timeout: proxyStatsTimeout}
+
+// logPrometheusScrapingDisabled logs once that the memory pressure of the proxies is unknown because their stats are
+// not exposed
+var logPrometheusScrapingDisabled sync.Once
+
+// getProxyStatsURL returns the URL of the stats of the proxy with the given IP, exposed by its Prometheus listener
+func getProxyStatsURL(ip string) string {
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(ip, fmt.Sprint(constants.EnvoyPrometheusInboundListenerPort)), constants.PrometheusScrapePath)
+}
+
+// isConfigThrottled determines whether the CDS, LDS and RDS responses among the given responses must be withheld from
+// the proxy, because they grow its config beyond the max proxy config size configured on the mesh while the proxy
+// reports running close to its memory limit. The size of the config of the proxy is the total size of its CDS, LDS and
+// RDS responses, the last ones sent for the types not among the given responses. Pushing an oversized config to such a
+// proxy can get the sidecar OOM killed, so the proxy is instead left with its last applied config and a warning event
+// is recorded.
+//
+// The responses are only withheld when they all only add resources to the ones the proxy has: a response removing or
+// changing a resource may narrow the access of the proxy, and withholding it would leave the proxy with access the
+// policies no longer grant. The proxies whose memory pressure is unknown are not throttled.
+func (s *Server) isConfigThrottled(proxy *envoy.Proxy, responses map[envoy.TypeURI]*xds_discovery.DiscoveryResponse, cfg configurator.Configurator) bool {
+	maxSize := cfg.GetMaxProxyConfigSize()
+	if maxSize <= 0 {
+		return false
+	}
+
+	var configSize int64
+	var typeURIs []envoy.TypeURI
+	for _, typeURI := range throttledTypeURIs {
+		response, ok := responses[typeURI]
+		if !ok {
+			configSize += proxy.GetSentConfigSize(typeURI)
+			continue
+		}
+		configSize += int64(proto.Size(response))
+		typeURIs = append(typeURIs, typeURI)
+	}
+	if len(typeURIs) == 0 || configSize <= maxSize {
+		return false
+	}
+
+	pressure, ok := proxy.GetMemoryPressure()
+	if !ok || pressure < highMemoryPressure {
+		return false
+	}
+
+	shortNames := make([]string, 0, len(typeURIs))
+	for _, typeURI := range typeURIs {
+		if !proxy.IsAdditiveConfig(typeURI, responses[typeURI].Resources) {
+			log.Debug().Msgf("[%s] Response removes or changes resources of proxy with SerialNumber=%s using %.0f%% of its memory limit, not throttling its config of %d bytes",
+				envoy.XDSShortURINames[typeURI], proxy.GetCertificateSerialNumber(), pressure*100, configSize)
+			return false
+		}
+		shortNames = append(shortNames, envoy.XDSShortURINames[typeURI])
+	}
+
+	for _, shortName := range shortNames {
+		metricsstore.DefaultMetricsStore.ProxyConfigThrottledCount.WithLabelValues(shortName).Inc()
+	}
+	if proxy.ShouldRecordThrottledEvent(time.Now(), throttledEventInterval) {
+		events.GenericEventRecorder().WarnEvent(events.ProxyConfigThrottled,
+			"[%s] Withholding responses growing the config to %d bytes, exceeding the max proxy config size of %d bytes, from proxy using %.0f%% of its memory limit with SerialNumber=%s on Pod with UID=%s",
+			strings.Join(shortNames, ","), configSize, maxSize, pressure*100, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+	}
+
+	return true
+}
+
+// refreshMemoryPressure refreshes the memory pressure of the given proxy at the refresh interval until the stop channel
+// is closed, while a max proxy config size is configured. The stats of the proxy are requested off the xDS push path,
+// so that a slow or unreachable proxy does not delay the responses pushed to it.
+func (s *Server) refreshMemoryPressure(proxy *envoy.Proxy, stop <-chan struct{}) {
+	ticker := time.NewTicker(memoryPressureRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		cfg := s.getConfiguratorForProxy(proxy)
+		if cfg.GetMaxProxyConfigSize() <= 0 {
+			proxy.ForgetMemoryPressure()
+		} else if !cfg.IsPrometheusScrapingEnabled() {
+			logPrometheusScrapingDisabled.Do(func() {
+				log.Warn().Msg("The max proxy config size is only enforced when Prometheus scraping is enabled, as the memory usage of the proxies is read from their Prometheus listener")
+			})
+			proxy.ForgetMemoryPressure()
+		} else if pressure, err := s.getMemoryPressure(proxy); err != nil {
+			log.Debug().Err(err).Msgf("Error getting the memory pressure of proxy with SerialNumber=%s, its config is not throttled", proxy.GetCertificateSerialNumber())
+			proxy.ForgetMemoryPressure()
+		} else {
+			proxy.SetMemoryPressure(pressure)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// getMemoryPressure returns the share of its memory limit the given proxy reports using. The pressure reported by the
+// fixed heap resource monitor of the overload manager of the proxy is used when it is configured, otherwise the memory
+// allocated by the proxy is compared to the memory limit of its container. The stats of the proxy are requested from
+// its Prometheus listener, which is only programmed when Prometheus scraping is enabled.
+func (s *Server) getMemoryPressure(proxy *envoy.Proxy) (float64, error) {
+	ip := getProxyIP(proxy)
+	if ip == "" {
+		return 0, errors.New("Unknown proxy IP")
+	}
+
+	stats, err := getProxyStats(s.proxyStatsURL(ip))
+	if err != nil {
+		return 0, err
+	}
+
+	if pressure, ok := getGaugeValue(stats, fixedHeapPressureStat); ok {
+		return pressure / 100, nil
+	}
+
+	allocated, ok := getGaugeValue(stats, memoryAllocatedStat)
+	if !ok {
+		return 0, errors.Errorf("Proxy does not report the %s stat", memoryAllocatedStat)
+	}
+	limit := s.catalog.GetProxyMemoryLimit(proxy)
+	if limit <= 0 {
+		return 0, errors.New("Proxy has no memory limit")
+	}
+	return allocated / float64(limit), nil
+}
+
+// getProxyIP returns the IP of the pod of the given proxy, or the IP of its xDS connection if its pod is unknown
+func getProxyIP(proxy *envoy.Proxy) string {
+	if proxy.HasPodMetadata() && proxy.PodMetadata.IP != "" {
+		return proxy.PodMetadata.IP
+	}
+	if proxy.GetIP() == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(proxy.GetIP().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// getProxyStats returns the metric families exposed in the Prometheus text format at the given URL
+func getProxyStats(url string) (map[string]*dto.MetricFamily, error) {
+	resp, err := proxyStatsClient.Get(url) //nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// getGaugeValue returns the value of the given gauge of the given metric families, and false if it is not reported.
+// The stats are parsed as untyped when they are not preceded by their TYPE comment.
+func getGaugeValue(families map[string]*dto.MetricFamily, name string) (float64, bool) {
+	family, ok := families[name]
+	if !ok || len(family.GetMetric()) == 0 {
+		return 0, false
+	}
+	metric := family.GetMetric()[0]
+	switch {
+	case metric.GetGauge() != nil:
+		return metric.GetGauge().GetValue(), true
+	case metric.GetUntyped() != nil:
+		return metric.GetUntyped().GetValue(), true
+	default:
+		return 0, false
+	}
+}
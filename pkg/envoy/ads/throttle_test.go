@@ -0,0 +1,341 @@
+package ads
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestIsConfigThrottled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newResponse := func(typeURI envoy.TypeURI, resources ...string) *xds_discovery.DiscoveryResponse {
+		response := &xds_discovery.DiscoveryResponse{TypeUrl: string(typeURI)}
+		for _, name := range resources {
+			resource, err := ptypes.MarshalAny(&wrappers.StringValue{Value: name})
+			tassert.Nil(t, err)
+			response.Resources = append(response.Resources, resource)
+		}
+		return response
+	}
+	sentCDS := newResponse(envoy.TypeCDS, "cluster-1")
+	sentLDS := newResponse(envoy.TypeLDS, "listener-1")
+
+	testCases := []struct {
+		name              string
+		maxSize           int64
+		pressure          float64
+		pressureKnown     bool
+		responses         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse
+		expectedThrottled bool
+	}{
+		{
+			name:              "no max proxy config size configured",
+			maxSize:           0,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2")},
+			expectedThrottled: false,
+		},
+		{
+			name:              "config within max proxy config size",
+			maxSize:           1024,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2")},
+			expectedThrottled: false,
+		},
+		{
+			name:              "config exceeds max proxy config size of a proxy whose memory pressure is unknown",
+			maxSize:           1,
+			pressureKnown:     false,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2")},
+			expectedThrottled: false,
+		},
+		{
+			name:              "config exceeds max proxy config size of a proxy under low memory pressure",
+			maxSize:           1,
+			pressure:          0.42,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2")},
+			expectedThrottled: false,
+		},
+		{
+			name:              "response adding resources to a proxy under high memory pressure",
+			maxSize:           1,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2")},
+			expectedThrottled: true,
+		},
+		{
+			name:          "responses adding resources to a proxy under high memory pressure",
+			maxSize:       1,
+			pressure:      0.95,
+			pressureKnown: true,
+			responses: map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{
+				envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2"),
+				envoy.TypeLDS: newResponse(envoy.TypeLDS, "listener-1", "listener-2"),
+			},
+			expectedThrottled: true,
+		},
+		{
+			name:              "response removing resources from a proxy under high memory pressure",
+			maxSize:           1,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS)},
+			expectedThrottled: false,
+		},
+		{
+			name:              "response changing resources of a proxy under high memory pressure",
+			maxSize:           1,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1-changed", "cluster-2")},
+			expectedThrottled: false,
+		},
+		{
+			name:          "responses adding and removing resources of a proxy under high memory pressure",
+			maxSize:       1,
+			pressure:      0.95,
+			pressureKnown: true,
+			responses: map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{
+				envoy.TypeCDS: newResponse(envoy.TypeCDS, "cluster-1", "cluster-2"),
+				envoy.TypeLDS: newResponse(envoy.TypeLDS),
+			},
+			expectedThrottled: false,
+		},
+		{
+			name:              "response of a type whose previous response was not recorded",
+			maxSize:           1,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeRDS: newResponse(envoy.TypeRDS, "route-1")},
+			expectedThrottled: false,
+		},
+		{
+			name:              "response of a type whose size is not limited",
+			maxSize:           1,
+			pressure:          0.95,
+			pressureKnown:     true,
+			responses:         map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeEDS: newResponse(envoy.TypeEDS, "cluster-1", "cluster-2")},
+			expectedThrottled: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(tc.maxSize).Times(1)
+
+			s := &Server{}
+			proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5678})
+			proxy.RecordSentConfig(envoy.TypeCDS, int64(proto.Size(sentCDS)), sentCDS.Resources)
+			proxy.RecordSentConfig(envoy.TypeLDS, int64(proto.Size(sentLDS)), sentLDS.Resources)
+			if tc.pressureKnown {
+				proxy.SetMemoryPressure(tc.pressure)
+			}
+
+			assert.Equal(tc.expectedThrottled, s.isConfigThrottled(proxy, tc.responses, mockConfigurator))
+		})
+	}
+}
+
+func TestIsConfigThrottledSize(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sentLDS := &xds_discovery.DiscoveryResponse{TypeUrl: string(envoy.TypeLDS), VersionInfo: "1"}
+	cds := &xds_discovery.DiscoveryResponse{TypeUrl: string(envoy.TypeCDS), VersionInfo: "1"}
+	sentLDSSize := int64(proto.Size(sentLDS))
+	cdsSize := int64(proto.Size(cds))
+
+	proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+	proxy.RecordSentConfig(envoy.TypeCDS, 0, nil)
+	proxy.RecordSentConfig(envoy.TypeLDS, sentLDSSize, sentLDS.Resources)
+	proxy.SetMemoryPressure(0.95)
+	s := &Server{}
+
+	// The size of the config includes the size of the last LDS response sent to the proxy
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(cdsSize + sentLDSSize).Times(1)
+	assert.False(s.isConfigThrottled(proxy, map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: cds}, mockConfigurator))
+
+	mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(cdsSize + sentLDSSize - 1).Times(1)
+	assert.True(s.isConfigThrottled(proxy, map[envoy.TypeURI]*xds_discovery.DiscoveryResponse{envoy.TypeCDS: cds}, mockConfigurator))
+}
+
+func TestGetMemoryPressure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name             string
+		stats            string
+		memoryLimit      int64
+		expectedPressure float64
+		expectedErr      bool
+	}{
+		{
+			name:        "stats unavailable",
+			expectedErr: true,
+		},
+		{
+			name:             "fixed heap pressure",
+			stats:            fmt.Sprintf("# TYPE %s gauge\n%s 95\n", fixedHeapPressureStat, fixedHeapPressureStat),
+			expectedPressure: 0.95,
+		},
+		{
+			name:             "untyped fixed heap pressure",
+			stats:            fmt.Sprintf("%s 42\n", fixedHeapPressureStat),
+			expectedPressure: 0.42,
+		},
+		{
+			name:        "memory allocated by a proxy without memory limit",
+			stats:       fmt.Sprintf("# TYPE %s gauge\n%s 900\n", memoryAllocatedStat, memoryAllocatedStat),
+			memoryLimit: 0,
+			expectedErr: true,
+		},
+		{
+			name:             "memory allocated by a proxy with a memory limit",
+			stats:            fmt.Sprintf("# TYPE %s gauge\n%s 900\n", memoryAllocatedStat, memoryAllocatedStat),
+			memoryLimit:      1000,
+			expectedPressure: 0.9,
+		},
+		{
+			name:        "no memory stat",
+			stats:       "# TYPE envoy_server_uptime gauge\nenvoy_server_uptime 10\n",
+			memoryLimit: 1000,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			statsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.stats == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(tc.stats))
+			}))
+			defer statsServer.Close()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockCatalog.EXPECT().GetProxyMemoryLimit(gomock.Any()).Return(tc.memoryLimit).AnyTimes()
+
+			s := &Server{
+				catalog: mockCatalog,
+				proxyStatsURL: func(ip string) string {
+					return statsServer.URL
+				},
+			}
+			proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5678})
+
+			pressure, err := s.getMemoryPressure(proxy)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.InDelta(tc.expectedPressure, pressure, 0.0001)
+		})
+	}
+}
+
+func TestRefreshMemoryPressure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	statsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf("%s 95\n", fixedHeapPressureStat)))
+	}))
+	defer statsServer.Close()
+
+	testCases := []struct {
+		name                  string
+		maxSize               int64
+		prometheusScraping    bool
+		expectedPressureKnown bool
+		expectedPressure      float64
+	}{
+		{
+			name:                  "no max proxy config size configured",
+			maxSize:               0,
+			prometheusScraping:    true,
+			expectedPressureKnown: false,
+		},
+		{
+			name:                  "prometheus scraping disabled",
+			maxSize:               1,
+			prometheusScraping:    false,
+			expectedPressureKnown: false,
+		},
+		{
+			name:                  "max proxy config size configured",
+			maxSize:               1,
+			prometheusScraping:    true,
+			expectedPressureKnown: true,
+			expectedPressure:      0.95,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetStagedRollout().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetMaxProxyConfigSize().Return(tc.maxSize).AnyTimes()
+			mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(tc.prometheusScraping).AnyTimes()
+
+			s := &Server{
+				cfg: mockConfigurator,
+				proxyStatsURL: func(ip string) string {
+					return statsServer.URL
+				},
+			}
+			proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5678})
+			proxy.SetMemoryPressure(0.5)
+
+			// The memory pressure is refreshed before the stop channel is read
+			stop := make(chan struct{})
+			close(stop)
+			s.refreshMemoryPressure(proxy, stop)
+
+			pressure, ok := proxy.GetMemoryPressure()
+			assert.Equal(tc.expectedPressureKnown, ok)
+			assert.Equal(tc.expectedPressure, pressure)
+		})
+	}
+}
+
+func TestGetProxyIP(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+	assert.Empty(getProxyIP(proxy))
+
+	proxy = envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5678})
+	assert.Equal("10.0.0.1", getProxyIP(proxy))
+
+	proxy.PodMetadata = &envoy.PodMetadata{IP: "10.0.0.2"}
+	assert.Equal("10.0.0.2", getProxyIP(proxy))
+}
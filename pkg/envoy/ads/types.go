@@ -28,4 +28,26 @@ type Server struct {
 	cfg            configurator.Configurator
 	certManager    certificate.Manager
 	ready          bool
+
+	// rbacDenials are the records of the requests denied by the RBAC policies of proxies, keyed by source, destination and route
+	rbacDenials      map[string]*envoy.RBACDenial
+	rbacDenialsMutex sync.Mutex
+
+	// observedRequests are the records of the requests observed by proxies in permissive traffic policy mode, keyed by
+	// source, destination, method and path
+	observedRequests      map[string]*envoy.ObservedRequest
+	observedRequestsMutex sync.Mutex
+
+	// egressMetricHosts are the source and external host combinations the egress metrics are labeled with
+	egressMetricHosts      map[string]bool
+	egressMetricHostsMutex sync.Mutex
+
+	// nodeProxyXDSHandlers are the xDS handlers for per-node proxies
+	nodeProxyXDSHandlers map[envoy.TypeURI]func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager) (*xds_discovery.DiscoveryResponse, error)
+
+	// proxyStatsURL returns the URL of the stats of the proxy with the given IP
+	proxyStatsURL func(string) string
+
+	// egressGatewayXDSHandlers are the xDS handlers for the egress gateway
+	egressGatewayXDSHandlers map[envoy.TypeURI]func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager) (*xds_discovery.DiscoveryResponse, error)
 }
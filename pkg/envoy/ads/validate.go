@@ -0,0 +1,58 @@
+package ads
+
+import (
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// validator is implemented by the Envoy API messages, whose Validate method checks the message against the
+// protoc-gen-validate rules of the Envoy API
+type validator interface {
+	Validate() error
+}
+
+// isResponseInvalid determines whether the given xDS response must be withheld from the proxy because one of its
+// resources violates the validation rules of the Envoy API.
+// The proxy would reject such a response, and every proxy receiving the same generated config would reject it again
+// on each update. The invalid resources are instead reported by the controller, named after the source of their
+// config, and the proxy is left with its last applied config.
+func isResponseInvalid(proxy *envoy.Proxy, response *xds_discovery.DiscoveryResponse) bool {
+	typeURI := envoy.TypeURI(response.TypeUrl)
+	invalid := false
+
+	for _, resource := range response.Resources {
+		var dynamic ptypes.DynamicAny
+		if err := ptypes.UnmarshalAny(resource, &dynamic); err != nil {
+			log.Error().Err(err).Msgf("[%s] Error unmarshaling resource of type %s for proxy with SerialNumber=%s on Pod with UID=%s",
+				envoy.XDSShortURINames[typeURI], resource.TypeUrl, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			invalid = true
+			continue
+		}
+
+		v, ok := dynamic.Message.(validator)
+		if !ok {
+			continue
+		}
+		// NOTE: Never log the entire resource - SDS resources contain secrets!
+		if err := v.Validate(); err != nil {
+			log.Error().Err(err).Msgf("[%s] Resource %q generated for proxy with SerialNumber=%s on Pod with UID=%s is invalid",
+				envoy.XDSShortURINames[typeURI], envoy.GetResourceName(dynamic.Message), proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			invalid = true
+		}
+	}
+
+	if !invalid {
+		return false
+	}
+
+	metricsstore.DefaultMetricsStore.ProxyConfigInvalidCount.WithLabelValues(envoy.XDSShortURINames[typeURI]).Inc()
+	events.GenericEventRecorder().WarnEvent(events.ProxyConfigInvalid,
+		"[%s] Withholding response with invalid resources for proxy with SerialNumber=%s on Pod with UID=%s",
+		envoy.XDSShortURINames[typeURI], proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+
+	return true
+}
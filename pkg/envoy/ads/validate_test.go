@@ -0,0 +1,73 @@
+package ads
+
+import (
+	"testing"
+
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestIsResponseInvalid(t *testing.T) {
+	proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+
+	testCases := []struct {
+		name            string
+		loadAssignments []*xds_endpoint.ClusterLoadAssignment
+		expectedInvalid bool
+	}{
+		{
+			name:            "no resources",
+			loadAssignments: nil,
+			expectedInvalid: false,
+		},
+		{
+			name: "valid resources",
+			loadAssignments: []*xds_endpoint.ClusterLoadAssignment{
+				{ClusterName: "bookstore/bookstore"},
+				{ClusterName: "bookstore/bookstore-v2"},
+			},
+			expectedInvalid: false,
+		},
+		{
+			name: "invalid resource",
+			loadAssignments: []*xds_endpoint.ClusterLoadAssignment{
+				{ClusterName: "bookstore/bookstore"},
+				// The cluster name is required
+				{ClusterName: ""},
+			},
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			response := &xds_discovery.DiscoveryResponse{TypeUrl: string(envoy.TypeEDS)}
+			for _, loadAssignment := range tc.loadAssignments {
+				resource, err := ptypes.MarshalAny(loadAssignment)
+				assert.Nil(err)
+				response.Resources = append(response.Resources, resource)
+			}
+
+			assert.Equal(tc.expectedInvalid, isResponseInvalid(proxy, response))
+		})
+	}
+}
+
+func TestIsResponseInvalidUnknownType(t *testing.T) {
+	assert := tassert.New(t)
+	proxy := envoy.NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+
+	response := &xds_discovery.DiscoveryResponse{
+		TypeUrl:   string(envoy.TypeCDS),
+		Resources: []*any.Any{{TypeUrl: "type.googleapis.com/unknown.Type"}},
+	}
+	assert.True(isResponseInvalid(proxy, response))
+}
@@ -1,6 +1,7 @@
 package cds
 
 import (
+	"strings"
 	"time"
 
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -84,8 +85,15 @@ func getSyntheticCluster(name string) *xds_cluster.Cluster {
 	}
 }
 
-// getLocalServiceCluster returns an Envoy Cluster corresponding to the local service
-func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName service.MeshService, clusterName string) (*xds_cluster.Cluster, error) {
+// getLocalServiceCluster returns an Envoy Cluster corresponding to the local service, which the inbound HTTP routes of
+// the service forward requests to. The ports of the service serving TCP are not part of this cluster, since each of them
+// is forwarded to by the filter chain of the port with a local cluster of its own.
+func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName service.MeshService, clusterName string, ipv6 bool) (*xds_cluster.Cluster, error) {
+	localAddr, dnsLookupFamily := constants.WildcardIPAddr, xds_cluster.Cluster_V4_ONLY
+	if ipv6 {
+		localAddr, dnsLookupFamily = constants.WildcardIPv6Addr, xds_cluster.Cluster_V6_ONLY
+	}
+
 	xdsCluster := xds_cluster.Cluster{
 		// The name must match the domain being cURLed in the demo
 		Name:           clusterName,
@@ -96,7 +104,7 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
 			Type: xds_cluster.Cluster_STRICT_DNS,
 		},
-		DnsLookupFamily: xds_cluster.Cluster_V4_ONLY,
+		DnsLookupFamily: dnsLookupFamily,
 		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
 			// NOTE: results.MeshService is the top level service that is cURLed.
 			ClusterName: clusterName,
@@ -108,13 +116,16 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 		Http2ProtocolOptions: &xds_core.Http2ProtocolOptions{},
 	}
 
-	ports, err := catalog.GetTargetPortToProtocolMappingForService(proxyServiceName)
+	svcPorts, err := catalog.ListServicePorts(proxyServiceName)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to get ports for service %s", proxyServiceName)
 		return nil, err
 	}
 
-	for port := range ports {
+	for _, svcPort := range service.DedupTargetPorts(svcPorts) {
+		if isTCPAppProtocol(svcPort.Protocol) {
+			continue
+		}
 		localityEndpoint := &xds_endpoint.LocalityLbEndpoints{
 			Locality: &xds_core.Locality{
 				Zone: "zone",
@@ -122,7 +133,7 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 			LbEndpoints: []*xds_endpoint.LbEndpoint{{
 				HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
 					Endpoint: &xds_endpoint.Endpoint{
-						Address: envoy.GetAddress(constants.WildcardIPAddr, port),
+						Address: envoy.GetAddress(localAddr, svcPort.TargetPort),
 					},
 				},
 				LoadBalancingWeight: &wrappers.UInt32Value{
@@ -136,6 +147,39 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 	return &xdsCluster, nil
 }
 
+// getLocalPortCluster returns an Envoy Cluster corresponding to the given application port on the local pod.
+// This is used to forward inbound TCP traffic on a service port, and inbound traffic to pods that are not selected
+// by any service.
+func getLocalPortCluster(port uint32, ipv6 bool) *xds_cluster.Cluster {
+	clusterName := envoy.GetLocalClusterNameForPort(port)
+	return &xds_cluster.Cluster{
+		Name:           clusterName,
+		AltStatName:    clusterName,
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_STATIC,
+		},
+		LbPolicy: xds_cluster.Cluster_ROUND_ROBIN,
+		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+			ClusterName: clusterName,
+			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(envoy.GetLocalhostIPAddress(ipv6), port),
+							},
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: constants.ClusterWeightAcceptAll, // Local cluster accepts all traffic
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
 // getPrometheusCluster returns an Envoy Cluster responsible for scraping metrics by Prometheus
 func getPrometheusCluster() *xds_cluster.Cluster {
 	return &xds_cluster.Cluster{
@@ -166,3 +210,8 @@ func getPrometheusCluster() *xds_cluster.Cluster {
 		},
 	}
 }
+
+// isTCPAppProtocol returns true if the given application protocol of a service port is proxied as opaque TCP traffic
+func isTCPAppProtocol(appProtocol string) bool {
+	return strings.EqualFold(appProtocol, tcpAppProtocol)
+}
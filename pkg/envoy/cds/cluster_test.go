@@ -74,23 +74,29 @@ func TestGetLocalServiceCluster(t *testing.T) {
 		Namespace: "bookbuyer-ns",
 	}
 
+	newSvcPort := func(port, targetPort uint32, protocol string) service.MeshService {
+		return service.MeshService{Namespace: proxyService.Namespace, Name: proxyService.Name, Port: port, TargetPort: targetPort, Protocol: protocol}
+	}
+
 	mockCtrl := gomock.NewController(t)
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
 
 	testCases := []struct {
-		name                             string
-		proxyService                     service.MeshService
-		portToProtocolMapping            map[uint32]string
-		expectedLocalityLbEndpoints      []*xds_endpoint.LocalityLbEndpoints
-		expectedLbPolicy                 xds_cluster.Cluster_LbPolicy
-		expectedProtocolSelection        xds_cluster.Cluster_ClusterProtocolSelection
-		expectedPortToProtocolMappingErr bool
-		expectedErr                      bool
+		name                        string
+		proxyService                service.MeshService
+		svcPorts                    []service.MeshService
+		ipv6                        bool
+		expectedLocalityLbEndpoints []*xds_endpoint.LocalityLbEndpoints
+		expectedDNSLookupFamily     xds_cluster.Cluster_DnsLookupFamily
+		expectedLbPolicy            xds_cluster.Cluster_LbPolicy
+		expectedProtocolSelection   xds_cluster.Cluster_ClusterProtocolSelection
+		expectedListServicePortsErr bool
+		expectedErr                 bool
 	}{
 		{
-			name:                  "when service returns a single port",
-			proxyService:          proxyService,
-			portToProtocolMapping: map[uint32]string{uint32(8080): "something"},
+			name:         "when service returns a single port",
+			proxyService: proxyService,
+			svcPorts:     []service.MeshService{newSvcPort(80, 8080, "something")},
 			expectedLocalityLbEndpoints: []*xds_endpoint.LocalityLbEndpoints{
 				{
 					Locality: &xds_core.Locality{
@@ -108,28 +114,105 @@ func TestGetLocalServiceCluster(t *testing.T) {
 					}},
 				},
 			},
-			expectedPortToProtocolMappingErr: false,
-			expectedErr:                      false,
+			expectedDNSLookupFamily:     xds_cluster.Cluster_V4_ONLY,
+			expectedListServicePortsErr: false,
+			expectedErr:                 false,
 		},
 		{
-			name:                             "when err fetching ports",
-			proxyService:                     proxyService,
-			portToProtocolMapping:            map[uint32]string{},
-			expectedLocalityLbEndpoints:      []*xds_endpoint.LocalityLbEndpoints{},
-			expectedPortToProtocolMappingErr: true,
-			expectedErr:                      true,
+			name:         "when the proxy's pod has an IPv6 address",
+			proxyService: proxyService,
+			svcPorts:     []service.MeshService{newSvcPort(80, 8080, "something")},
+			ipv6:         true,
+			expectedLocalityLbEndpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					Locality: &xds_core.Locality{
+						Zone: "zone",
+					},
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(constants.WildcardIPv6Addr, uint32(8080)),
+							},
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: constants.ClusterWeightAcceptAll, // Local cluster accepts all traffic
+						},
+					}},
+				},
+			},
+			expectedDNSLookupFamily:     xds_cluster.Cluster_V6_ONLY,
+			expectedListServicePortsErr: false,
+			expectedErr:                 false,
+		},
+		{
+			name:         "when service has an HTTP port and a TCP port",
+			proxyService: proxyService,
+			svcPorts:     []service.MeshService{newSvcPort(80, 8080, "http"), newSvcPort(5432, 5432, "TCP")},
+			expectedLocalityLbEndpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					Locality: &xds_core.Locality{
+						Zone: "zone",
+					},
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(constants.WildcardIPAddr, uint32(8080)),
+							},
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: constants.ClusterWeightAcceptAll, // Local cluster accepts all traffic
+						},
+					}},
+				},
+			},
+			expectedDNSLookupFamily:     xds_cluster.Cluster_V4_ONLY,
+			expectedListServicePortsErr: false,
+			expectedErr:                 false,
+		},
+		{
+			name:         "when multiple service ports share a target port",
+			proxyService: proxyService,
+			svcPorts:     []service.MeshService{newSvcPort(80, 8080, "http"), newSvcPort(8080, 8080, "http")},
+			expectedLocalityLbEndpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					Locality: &xds_core.Locality{
+						Zone: "zone",
+					},
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(constants.WildcardIPAddr, uint32(8080)),
+							},
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: constants.ClusterWeightAcceptAll, // Local cluster accepts all traffic
+						},
+					}},
+				},
+			},
+			expectedDNSLookupFamily:     xds_cluster.Cluster_V4_ONLY,
+			expectedListServicePortsErr: false,
+			expectedErr:                 false,
+		},
+		{
+			name:                        "when err fetching ports",
+			proxyService:                proxyService,
+			svcPorts:                    nil,
+			expectedLocalityLbEndpoints: []*xds_endpoint.LocalityLbEndpoints{},
+			expectedListServicePortsErr: true,
+			expectedErr:                 true,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.expectedPortToProtocolMappingErr {
-				mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tc.proxyService).Return(tc.portToProtocolMapping, errors.New("error")).Times(1)
+			if tc.expectedListServicePortsErr {
+				mockCatalog.EXPECT().ListServicePorts(tc.proxyService).Return(tc.svcPorts, errors.New("error")).Times(1)
 			} else {
-				mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tc.proxyService).Return(tc.portToProtocolMapping, nil).Times(1)
+				mockCatalog.EXPECT().ListServicePorts(tc.proxyService).Return(tc.svcPorts, nil).Times(1)
 			}
 
-			cluster, err := getLocalServiceCluster(mockCatalog, tc.proxyService, clusterName)
+			cluster, err := getLocalServiceCluster(mockCatalog, tc.proxyService, clusterName, tc.ipv6)
 
 			if tc.expectedErr {
 				assert.NotNil(err)
@@ -142,7 +225,7 @@ func TestGetLocalServiceCluster(t *testing.T) {
 				assert.Equal(xds_cluster.Cluster_ROUND_ROBIN, cluster.LbPolicy)
 				assert.Equal(&xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_STRICT_DNS}, cluster.ClusterDiscoveryType)
 				assert.Equal(true, cluster.RespectDnsTtl)
-				assert.Equal(xds_cluster.Cluster_V4_ONLY, cluster.DnsLookupFamily)
+				assert.Equal(tc.expectedDNSLookupFamily, cluster.DnsLookupFamily)
 				assert.Equal(xds_cluster.Cluster_USE_DOWNSTREAM_PROTOCOL, cluster.ProtocolSelection)
 				assert.Equal(len(tc.expectedLocalityLbEndpoints), len(cluster.LoadAssignment.Endpoints))
 				assert.ElementsMatch(tc.expectedLocalityLbEndpoints, cluster.LoadAssignment.Endpoints)
@@ -227,3 +310,22 @@ func TestGetOutboundPassthroughCluster(t *testing.T) {
 
 	assert.Equal(expectedCluster, actual)
 }
+
+func TestGetLocalPortCluster(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := getLocalPortCluster(8080, false)
+
+	assert.Equal("port-8080-local", actual.Name)
+	assert.Equal("port-8080-local", actual.AltStatName)
+	assert.Equal(&xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_STATIC}, actual.ClusterDiscoveryType)
+	assert.Equal("port-8080-local", actual.LoadAssignment.ClusterName)
+	assert.Len(actual.LoadAssignment.Endpoints, 1)
+	assert.Len(actual.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+	assert.Equal(envoy.GetAddress(constants.LocalhostIPAddress, 8080),
+		actual.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+
+	actual = getLocalPortCluster(8080, true)
+	assert.Equal(envoy.GetAddress(constants.LocalhostIPv6Address, 8080),
+		actual.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+}
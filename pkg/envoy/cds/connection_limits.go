@@ -0,0 +1,28 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// setInboundConnectionLimits limits the connections of the given local cluster to the service fronted by the proxy.
+// Limiting the requests per connection to 1 along with the connections bounds the number of concurrent requests the
+// service receives, including the requests multiplexed by HTTP/2 clients on a single downstream connection.
+func setInboundConnectionLimits(cluster *xds_cluster.Cluster, policy trafficpolicy.InboundConnectionPolicy) {
+	if policy.MaxConnections > 0 {
+		cluster.CircuitBreakers = &xds_cluster.CircuitBreakers{
+			Thresholds: []*xds_cluster.CircuitBreakers_Thresholds{
+				{
+					Priority:       xds_core.RoutingPriority_DEFAULT,
+					MaxConnections: &wrappers.UInt32Value{Value: policy.MaxConnections},
+				},
+			},
+		}
+	}
+	if policy.MaxRequestsPerConnection > 0 {
+		cluster.MaxRequestsPerConnection = &wrappers.UInt32Value{Value: policy.MaxRequestsPerConnection}
+	}
+}
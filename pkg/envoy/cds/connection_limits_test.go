@@ -0,0 +1,30 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetInboundConnectionLimits(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Connections are not limited by default
+	cluster := &xds_cluster.Cluster{}
+	setInboundConnectionLimits(cluster, trafficpolicy.InboundConnectionPolicy{})
+	assert.Nil(cluster.CircuitBreakers)
+	assert.Nil(cluster.MaxRequestsPerConnection)
+
+	limited := &xds_cluster.Cluster{Name: "default/bookstore-local"}
+	setInboundConnectionLimits(limited, trafficpolicy.InboundConnectionPolicy{
+		MaxConnections:           10,
+		MaxRequestsPerConnection: 1,
+	})
+	assert.Nil(limited.Validate())
+	assert.Len(limited.CircuitBreakers.Thresholds, 1)
+	assert.Equal(uint32(10), limited.CircuitBreakers.Thresholds[0].MaxConnections.GetValue())
+	assert.Equal(uint32(1), limited.MaxRequestsPerConnection.GetValue())
+}
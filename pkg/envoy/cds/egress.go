@@ -0,0 +1,160 @@
+package cds
+
+import (
+	"sort"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// getEgressClusters returns the clusters of the external destinations allowed by the given egress policy: a cluster
+// resolving each allowed host per port with DNS, and clusters per port forwarding the connections to the allowed
+// wildcard domains and IP ranges to their original destination. The clusters of the hosts TLS is originated to
+// originate TLS on behalf of the proxy with the given identity. When an egress gateway is given, the clusters forward
+// the connections to the egress gateway instead, which originates TLS to the hosts.
+func getEgressClusters(policy *trafficpolicy.EgressPolicy, proxyIdentity service.K8sServiceAccount, egressGateway *egressGatewayUpstream) ([]envoy.NamedResource, error) {
+	var clusters []envoy.NamedResource
+
+	// A host allowed on the same port by the http and https protocols shares its cluster
+	hostClusters := make(map[string]bool)
+	for _, portHosts := range []map[uint32][]string{policy.HTTPHosts, policy.HTTPSHosts} {
+		for port, hosts := range portHosts {
+			for _, host := range hosts {
+				clusterName := envoy.GetEgressHostClusterName(host, port)
+				if hostClusters[clusterName] {
+					continue
+				}
+				hostClusters[clusterName] = true
+				if envoy.IsWildcardHost(host) {
+					// The application resolved the subdomain it connects to, which the wildcard domain cannot be resolved to
+					cluster := getEgressOriginalDestinationCluster(clusterName)
+					if err := egressGateway.route(cluster, ""); err != nil {
+						return nil, err
+					}
+					clusters = append(clusters, newNamedCluster(cluster, "Egress wildcard hosts on port %d", port))
+					continue
+				}
+				cluster := getEgressHostCluster(host, port)
+				if egressGateway == nil {
+					if err := originateEgressTLS(cluster, host, policy.TLSOriginations[port][host], proxyIdentity); err != nil {
+						return nil, err
+					}
+				}
+				if err := egressGateway.route(cluster, host); err != nil {
+					return nil, err
+				}
+				clusters = append(clusters, newNamedCluster(cluster, "Egress host %s on port %d", host, port))
+			}
+		}
+	}
+
+	for port := range policy.IPRanges {
+		cluster := getEgressOriginalDestinationCluster(envoy.GetEgressIPRangesClusterName(port))
+		if err := egressGateway.route(cluster, ""); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, newNamedCluster(cluster, "Egress IP ranges on port %d", port))
+	}
+
+	// The clusters are built from maps, so they are sorted by name
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters, nil
+}
+
+// getEgressHostCluster returns an Envoy Cluster resolving the given external host with DNS, and connecting to it on the
+// given port. The addresses of external hosts, such as SaaS endpoints, may change frequently and be numerous, so a new
+// connection is made to the first address the host currently resolves to, and the host is resolved again when its DNS
+// records expire.
+func getEgressHostCluster(host string, port uint32) *xds_cluster.Cluster {
+	clusterName := envoy.GetEgressHostClusterName(host, port)
+	return &xds_cluster.Cluster{
+		Name:           clusterName,
+		AltStatName:    envoy.GetEgressHostClusterStatName(host, port),
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_LOGICAL_DNS,
+		},
+		RespectDnsTtl:  true,
+		LbPolicy:       xds_cluster.Cluster_ROUND_ROBIN,
+		LoadAssignment: getEgressHostLoadAssignment(clusterName, host, port),
+	}
+}
+
+// getEgressHostLoadAssignment returns the load assignment of the cluster with the given name connecting to the given
+// external host and port
+func getEgressHostLoadAssignment(clusterName, host string, port uint32) *xds_endpoint.ClusterLoadAssignment {
+	return &xds_endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*xds_endpoint.LbEndpoint{{
+					HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+						Endpoint: &xds_endpoint.Endpoint{
+							Address: envoy.GetAddress(host, port),
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+// originateEgressTLS changes the given cluster of the given external host to originate the given TLS connections to
+// the host, presenting the certificate issued by the mesh to the given identity when the TLS origination requires it.
+// The cluster is left unchanged when TLS is not originated to the host.
+func originateEgressTLS(cluster *xds_cluster.Cluster, host string, tlsOrigination *trafficpolicy.EgressTLSOrigination, clientIdentity service.K8sServiceAccount) error {
+	if tlsOrigination == nil {
+		return nil
+	}
+
+	var clientCert *envoy.SDSCert
+	if tlsOrigination.ClientCertSecret != "" {
+		clientCert = &envoy.SDSCert{
+			Name:     tlsOrigination.ClientCertSecret,
+			CertType: envoy.EgressClientCertType,
+		}
+	} else if tlsOrigination.MeshClientCert {
+		clientCert = &envoy.SDSCert{
+			Name:     clientIdentity.String(),
+			CertType: envoy.ServiceCertType,
+		}
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetEgressUpstreamTLSContext(host, tlsOrigination.CABundleSecret, clientCert))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling UpstreamTlsContext for cluster %s", cluster.Name)
+		return err
+	}
+
+	cluster.LoadAssignment = getEgressHostLoadAssignment(cluster.Name, host, tlsOrigination.Port)
+	cluster.TransportSocket = &xds_core.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledUpstreamTLSContext,
+		},
+	}
+	return nil
+}
+
+// getEgressOriginalDestinationCluster returns an Envoy Cluster with the given name forwarding the connections to their
+// original destination, used for the external IP ranges and wildcard domains allowed on a port
+func getEgressOriginalDestinationCluster(clusterName string) *xds_cluster.Cluster {
+	return &xds_cluster.Cluster{
+		Name:           clusterName,
+		AltStatName:    clusterName,
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_ORIGINAL_DST,
+		},
+		LbPolicy: xds_cluster.Cluster_CLUSTER_PROVIDED,
+	}
+}
@@ -0,0 +1,147 @@
+package cds
+
+import (
+	"fmt"
+	"sort"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// egressGatewayUpstream is the egress gateway the egress traffic of a sidecar proxy is forwarded to
+type egressGatewayUpstream struct {
+	// downstreamIdentity is the identity of the sidecar proxy, presented to the egress gateway
+	downstreamIdentity service.K8sServiceAccount
+
+	// service is the service fronting the egress gateway
+	service service.MeshService
+}
+
+// newEgressGatewayUpstream returns the egress gateway in the given OSM namespace, to which the sidecar proxy with the
+// given identity forwards its egress traffic
+func newEgressGatewayUpstream(downstreamIdentity service.K8sServiceAccount, osmNamespace string) *egressGatewayUpstream {
+	return &egressGatewayUpstream{
+		downstreamIdentity: downstreamIdentity,
+		service:            catalog.GetEgressGatewayService(osmNamespace),
+	}
+}
+
+// route changes the given egress cluster to forward its connections to the egress gateway over mTLS, instead of to
+// their destination. The PROXY protocol header sent ahead of the TLS handshake carries the original destination of the
+// connections, and the given server name, when set, is the external host the egress gateway forwards the connections
+// to. The cluster is left unchanged when there is no egress gateway.
+func (g *egressGatewayUpstream) route(cluster *xds_cluster.Cluster, serverName string) error {
+	if g == nil {
+		return nil
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetEgressGatewayUpstreamTLSContext(g.downstreamIdentity, g.service, serverName))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling UpstreamTlsContext for cluster %s", cluster.Name)
+		return err
+	}
+
+	// The egress gateway service is headless, so that the connections are balanced across the egress gateway replicas
+	cluster.ClusterDiscoveryType = &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_STRICT_DNS}
+	cluster.LbPolicy = xds_cluster.Cluster_ROUND_ROBIN
+	cluster.RespectDnsTtl = false
+	cluster.LoadAssignment = &xds_endpoint.ClusterLoadAssignment{
+		ClusterName: cluster.Name,
+		Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+			{
+				LbEndpoints: []*xds_endpoint.LbEndpoint{{
+					HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+						Endpoint: &xds_endpoint.Endpoint{
+							Address: envoy.GetAddress(fmt.Sprintf("%s.%s.svc.cluster.local", g.service.Name, g.service.Namespace), constants.EgressGatewayPort),
+						},
+					},
+				}},
+			},
+		},
+	}
+	cluster.TransportSocket = &xds_core.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledUpstreamTLSContext,
+		},
+	}
+
+	return setUpstreamProxyProtocol(cluster, constants.ProxyProtocolV2)
+}
+
+// NewEgressGatewayResponse creates a new Cluster Discovery Response for the egress gateway.
+// The response contains a cluster resolving each external host allowed by the Egress policies with DNS per port, and
+// a cluster forwarding the egress traffic to other destinations to its original destination.
+func NewEgressGatewayResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	clusters := []*xds_cluster.Cluster{getEgressOriginalDestinationCluster(envoy.EgressGatewayOriginalDestinationCluster)}
+	if egressPolicy := meshCatalog.GetEgressGatewayPolicy(); egressPolicy != nil {
+		hostClusters, err := getEgressGatewayHostClusters(egressPolicy, catalog.GetEgressGatewayServiceAccount(cfg.GetOSMNamespace()))
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to construct the egress clusters of Egress policies %v for egress gateway with XDS Certificate SerialNumber=%s",
+				egressPolicy.Names, proxy.GetCertificateSerialNumber())
+			return nil, err
+		}
+		clusters = append(clusters, hostClusters...)
+	}
+
+	// The clusters are built from maps, so they are sorted by name
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	resp := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeCDS),
+	}
+	for _, cluster := range clusters {
+		marshalledCluster, err := ptypes.MarshalAny(cluster)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to marshal cluster %s for egress gateway with XDS Certificate SerialNumber=%s",
+				cluster.Name, proxy.GetCertificateSerialNumber())
+			return nil, err
+		}
+		resp.Resources = append(resp.Resources, marshalledCluster)
+	}
+
+	return resp, nil
+}
+
+// getEgressGatewayHostClusters returns the clusters of the external hosts allowed by the given egress policy, resolving
+// each host with DNS per port. The connections to wildcard domains are forwarded to their original destination. The
+// clusters of the hosts TLS is originated to originate TLS, presenting the certificate issued by the mesh to the given
+// identity of the egress gateway when the TLS origination requires it.
+func getEgressGatewayHostClusters(egressPolicy *trafficpolicy.EgressPolicy, gatewayIdentity service.K8sServiceAccount) ([]*xds_cluster.Cluster, error) {
+	var clusters []*xds_cluster.Cluster
+
+	// A host allowed on the same port by the http and https protocols shares its cluster
+	hostClusters := make(map[string]bool)
+	for _, portHosts := range []map[uint32][]string{egressPolicy.HTTPHosts, egressPolicy.HTTPSHosts} {
+		for port, hosts := range portHosts {
+			for _, host := range hosts {
+				clusterName := envoy.GetEgressHostClusterName(host, port)
+				if envoy.IsWildcardHost(host) || hostClusters[clusterName] {
+					continue
+				}
+				hostClusters[clusterName] = true
+				cluster := getEgressHostCluster(host, port)
+				if err := originateEgressTLS(cluster, host, egressPolicy.TLSOriginations[port][host], gatewayIdentity); err != nil {
+					return nil, err
+				}
+				clusters = append(clusters, cluster)
+			}
+		}
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,128 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressClustersWithEgressGateway(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := &trafficpolicy.EgressPolicy{
+		Names:      []string{"curl/httpbin"},
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"*.github.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{5432: {"203.0.113.0/24"}},
+		TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+			80: {"httpbin.org": {Port: 443, MeshClientCert: true}},
+		},
+	}
+
+	clusters, err := getEgressClusters(policy, tests.BookbuyerServiceAccount, newEgressGatewayUpstream(tests.BookbuyerServiceAccount, "osm-system"))
+	assert.Nil(err)
+	assert.Len(clusters, 4)
+
+	// Every cluster forwards its connections to the egress gateway, which is told the external host by the server name.
+	// TLS is originated by the egress gateway rather than by the sidecar.
+	expectedServerNames := map[string]string{
+		"egress-ip-ranges:5432":     "",
+		"egress-wildcard-hosts:443": "",
+		"egress:httpbin.org:443":    "httpbin.org",
+		"egress:httpbin.org:80":     "httpbin.org",
+	}
+	for _, namedCluster := range clusters {
+		cluster := namedCluster.Resource.(*xds_cluster.Cluster)
+		assert.Equal(xds_cluster.Cluster_STRICT_DNS, cluster.GetType())
+		assert.Equal(xds_cluster.Cluster_ROUND_ROBIN, cluster.LbPolicy)
+		address := cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+		assert.Equal("osm-egress-gateway.osm-system.svc.cluster.local", address.Address)
+		assert.Equal(uint32(15006), address.GetPortValue())
+
+		// The PROXY protocol header carrying the original destination is sent ahead of the mTLS handshake
+		assert.Equal(upstreamProxyProtocolTransportSocket, cluster.TransportSocket.Name)
+		proxyProtocol := &xds_proxy_protocol.ProxyProtocolUpstreamTransport{}
+		assert.Nil(ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), proxyProtocol))
+		assert.Equal(xds_core.ProxyProtocolConfig_V2, proxyProtocol.Config.Version)
+		assert.Equal(wellknown.TransportSocketTls, proxyProtocol.TransportSocket.Name)
+
+		tlsContext := &xds_auth.UpstreamTlsContext{}
+		assert.Nil(ptypes.UnmarshalAny(proxyProtocol.TransportSocket.GetTypedConfig(), tlsContext))
+		assert.Equal(expectedServerNames[cluster.Name], tlsContext.Sni, cluster.Name)
+		assert.Equal("service-cert:default/bookbuyer", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+		assert.Equal("root-cert-for-mtls-outbound:osm-system/osm-egress-gateway", tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name)
+	}
+}
+
+func TestNewEgressGatewayResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	proxy := envoy.NewProxy("", "", nil)
+
+	getClusters := func() []*xds_cluster.Cluster {
+		actual, err := NewEgressGatewayResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+		assert.Nil(err)
+		var clusters []*xds_cluster.Cluster
+		for _, resource := range actual.Resources {
+			cluster := &xds_cluster.Cluster{}
+			assert.Nil(ptypes.UnmarshalAny(resource, cluster))
+			clusters = append(clusters, cluster)
+		}
+		return clusters
+	}
+
+	// Without Egress policies, the egress traffic is forwarded to its original destination
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(nil)
+	clusters := getClusters()
+	assert.Len(clusters, 1)
+	assert.Equal(envoy.EgressGatewayOriginalDestinationCluster, clusters[0].Name)
+	assert.Equal(xds_cluster.Cluster_ORIGINAL_DST, clusters[0].GetType())
+
+	// The external hosts allowed by the Egress policies are resolved with DNS, except for wildcard domains
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(&trafficpolicy.EgressPolicy{
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}, 443: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"*.github.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{5432: {"203.0.113.0/24"}},
+		TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+			80: {"httpbin.org": {Port: 443, MeshClientCert: true}},
+		},
+	})
+	clusters = getClusters()
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	assert.Equal([]string{
+		"egress-gateway-original-destination",
+		"egress:httpbin.org:443",
+		"egress:httpbin.org:80",
+	}, names)
+	assert.Equal(xds_cluster.Cluster_LOGICAL_DNS, clusters[1].GetType())
+	assert.Nil(clusters[1].TransportSocket)
+
+	// The egress gateway originates TLS to the hosts, presenting its own certificate issued by the mesh
+	address := clusters[2].LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal(uint32(443), address.GetPortValue())
+	tlsContext := &xds_auth.UpstreamTlsContext{}
+	assert.Nil(ptypes.UnmarshalAny(clusters[2].TransportSocket.GetTypedConfig(), tlsContext))
+	assert.Equal("httpbin.org", tlsContext.Sni)
+	assert.Equal("service-cert:osm-system/osm-egress-gateway", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+}
@@ -0,0 +1,123 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressClusters(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := &trafficpolicy.EgressPolicy{
+		Names:      []string{"curl/httpbin"},
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}, 443: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"*.github.com", "*.githubusercontent.com", "example.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{5432: {"203.0.113.0/24"}},
+	}
+
+	clusters, err := getEgressClusters(policy, tests.BookbuyerServiceAccount, nil)
+	assert.Nil(err)
+
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	// The cluster of httpbin.org on port 443 is shared by the http and https protocols, and the wildcard domains on port
+	// 443 share a cluster
+	assert.Equal([]string{
+		"egress-ip-ranges:5432",
+		"egress-wildcard-hosts:443",
+		"egress:example.com:443",
+		"egress:httpbin.org:443",
+		"egress:httpbin.org:80",
+	}, names)
+
+	ipRangesCluster := clusters[0].Resource.(*xds_cluster.Cluster)
+	assert.Equal(xds_cluster.Cluster_ORIGINAL_DST, ipRangesCluster.GetType())
+	assert.Equal(xds_cluster.Cluster_CLUSTER_PROVIDED, ipRangesCluster.LbPolicy)
+	assert.Nil(ipRangesCluster.LoadAssignment)
+
+	wildcardHostsCluster := clusters[1].Resource.(*xds_cluster.Cluster)
+	assert.Equal(xds_cluster.Cluster_ORIGINAL_DST, wildcardHostsCluster.GetType())
+	assert.Nil(wildcardHostsCluster.LoadAssignment)
+
+	hostCluster := clusters[3].Resource.(*xds_cluster.Cluster)
+	assert.Equal(xds_cluster.Cluster_LOGICAL_DNS, hostCluster.GetType())
+	assert.True(hostCluster.RespectDnsTtl)
+	assert.Equal("egress.httpbin.org_443", hostCluster.AltStatName)
+	assert.Equal("egress:httpbin.org:443", hostCluster.LoadAssignment.ClusterName)
+	address := hostCluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal("httpbin.org", address.Address)
+	assert.Equal(uint32(443), address.GetPortValue())
+	assert.Nil(hostCluster.TransportSocket)
+}
+
+func TestGetEgressClustersWithTLSOrigination(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := &trafficpolicy.EgressPolicy{
+		Names:     []string{"curl/httpbin"},
+		HTTPHosts: map[uint32][]string{80: {"example.com", "httpbin.org"}, 8080: {"httpbin.org"}},
+		TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+			80:   {"httpbin.org": {Port: 443, MeshClientCert: true}},
+			8080: {"httpbin.org": {Port: 8443, CABundleSecret: "curl/httpbin-ca", ClientCertSecret: "curl/httpbin-client"}},
+		},
+	}
+
+	clusters, err := getEgressClusters(policy, tests.BookbuyerServiceAccount, nil)
+	assert.Nil(err)
+	assert.Len(clusters, 3)
+
+	// TLS is not originated to the hosts without TLS origination
+	exampleCluster := clusters[0].Resource.(*xds_cluster.Cluster)
+	assert.Equal("egress:example.com:80", exampleCluster.Name)
+	assert.Nil(exampleCluster.TransportSocket)
+
+	testCases := []struct {
+		cluster                 *xds_cluster.Cluster
+		expectedName            string
+		expectedPort            uint32
+		expectedClientCert      string
+		expectedValidationFiles bool
+	}{
+		{
+			cluster:                 clusters[1].Resource.(*xds_cluster.Cluster),
+			expectedName:            "egress:httpbin.org:80",
+			expectedPort:            443,
+			expectedClientCert:      "service-cert:default/bookbuyer",
+			expectedValidationFiles: true,
+		},
+		{
+			cluster:            clusters[2].Resource.(*xds_cluster.Cluster),
+			expectedName:       "egress:httpbin.org:8080",
+			expectedPort:       8443,
+			expectedClientCert: "egress-client-cert:curl/httpbin-client",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedName, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			assert.Equal(tc.expectedName, tc.cluster.Name)
+			address := tc.cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+			assert.Equal("httpbin.org", address.Address)
+			assert.Equal(tc.expectedPort, address.GetPortValue())
+
+			assert.Equal(wellknown.TransportSocketTls, tc.cluster.TransportSocket.Name)
+			tlsContext := &xds_auth.UpstreamTlsContext{}
+			assert.Nil(ptypes.UnmarshalAny(tc.cluster.TransportSocket.GetTypedConfig(), tlsContext))
+			assert.Equal("httpbin.org", tlsContext.Sni)
+			assert.Equal(tc.expectedClientCert, tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+			assert.Equal(tc.expectedValidationFiles, tlsContext.CommonTlsContext.GetValidationContext() != nil)
+		})
+	}
+}
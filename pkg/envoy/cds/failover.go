@@ -0,0 +1,36 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// rawBufferTransportSocket is the name of Envoy's plaintext transport socket
+const rawBufferTransportSocket = "envoy.transport_sockets.raw_buffer"
+
+// setFailoverTransportSocket configures the given cluster of an upstream service to connect in plaintext to the backup
+// endpoints outside of the mesh that the service fails over to, while the endpoints of the service are connected to
+// with mTLS. Clusters with the original destination of the requests as their only endpoint have no backup endpoints.
+func setFailoverTransportSocket(cluster *xds_cluster.Cluster, policy *trafficpolicy.FailoverPolicy) {
+	if policy == nil || cluster.GetType() == xds_cluster.Cluster_ORIGINAL_DST {
+		return
+	}
+
+	cluster.TransportSocketMatches = []*xds_cluster.Cluster_TransportSocketMatch{
+		{
+			Name: envoy.FailoverEndpointMetadataKey,
+			Match: &structpb.Struct{
+				Fields: map[string]*structpb.Value{
+					envoy.FailoverEndpointMetadataKey: {Kind: &structpb.Value_BoolValue{BoolValue: true}},
+				},
+			},
+			TransportSocket: &xds_core.TransportSocket{
+				Name: rawBufferTransportSocket,
+			},
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package cds
+
+import (
+	"net"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetFailoverTransportSocket(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := &trafficpolicy.FailoverPolicy{
+		Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("203.0.113.10"), Port: 80}},
+	}
+
+	// A service without backup endpoints
+	cluster := &xds_cluster.Cluster{ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_EDS}}
+	setFailoverTransportSocket(cluster, nil)
+	assert.Nil(cluster.TransportSocketMatches)
+
+	// A service with backup endpoints
+	cluster = &xds_cluster.Cluster{ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_EDS}}
+	setFailoverTransportSocket(cluster, policy)
+	assert.Len(cluster.TransportSocketMatches, 1)
+	assert.Nil(cluster.TransportSocketMatches[0].Validate())
+	assert.True(cluster.TransportSocketMatches[0].Match.Fields[envoy.FailoverEndpointMetadataKey].GetBoolValue())
+	assert.Equal(rawBufferTransportSocket, cluster.TransportSocketMatches[0].TransportSocket.Name)
+
+	// The original destination of the requests has no backup endpoints
+	cluster = &xds_cluster.Cluster{ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_ORIGINAL_DST}}
+	setFailoverTransportSocket(cluster, policy)
+	assert.Nil(cluster.TransportSocketMatches)
+}
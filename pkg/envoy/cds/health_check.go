@@ -0,0 +1,58 @@
+package cds
+
+import (
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// grpcHealthCheckTimeout is the time Envoy waits for the response to a gRPC health check
+	grpcHealthCheckTimeout = 1 * time.Second
+
+	// grpcHealthCheckInterval is the interval between the gRPC health checks of an endpoint
+	grpcHealthCheckInterval = 10 * time.Second
+
+	// grpcHealthCheckUnhealthyThreshold is the number of failed gRPC health checks after which an endpoint is unhealthy
+	grpcHealthCheckUnhealthyThreshold = 3
+
+	// grpcHealthCheckHealthyThreshold is the number of passed gRPC health checks after which an endpoint is healthy again
+	grpcHealthCheckHealthyThreshold = 1
+)
+
+// setGRPCHealthCheck configures the given cluster of an upstream service to actively health check the endpoints of the
+// service with the gRPC health checking protocol. Clusters with the original destination of the requests as their only
+// endpoint cannot be health checked.
+func setGRPCHealthCheck(cluster *xds_cluster.Cluster, policy *trafficpolicy.GRPCHealthCheckPolicy) {
+	if policy == nil || cluster.GetType() == xds_cluster.Cluster_ORIGINAL_DST {
+		return
+	}
+
+	cluster.HealthChecks = []*xds_core.HealthCheck{
+		{
+			Timeout:            ptypes.DurationProto(grpcHealthCheckTimeout),
+			Interval:           ptypes.DurationProto(grpcHealthCheckInterval),
+			UnhealthyThreshold: &wrappers.UInt32Value{Value: grpcHealthCheckUnhealthyThreshold},
+			HealthyThreshold:   &wrappers.UInt32Value{Value: grpcHealthCheckHealthyThreshold},
+			HealthChecker: &xds_core.HealthCheck_GrpcHealthCheck_{
+				GrpcHealthCheck: &xds_core.HealthCheck_GrpcHealthCheck{
+					ServiceName: policy.ServiceName,
+					Authority:   policy.Authority,
+				},
+			},
+		},
+	}
+
+	if policy.GateNewEndpoints {
+		// New endpoints are not load balanced to until their first health check passed, so that the endpoints of
+		// servers that are not ready to serve yet do not receive traffic
+		cluster.CommonLbConfig = &xds_cluster.Cluster_CommonLbConfig{
+			IgnoreNewHostsUntilFirstHc: true,
+		}
+	}
+}
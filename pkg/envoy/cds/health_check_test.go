@@ -0,0 +1,57 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetGRPCHealthCheck(t *testing.T) {
+	assert := tassert.New(t)
+
+	newCluster := func(clusterType xds_cluster.Cluster_DiscoveryType) *xds_cluster.Cluster {
+		return &xds_cluster.Cluster{
+			Name:                 "bookstore-ns/bookstore",
+			ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: clusterType},
+		}
+	}
+
+	// A service that is not health checked
+	cluster := newCluster(xds_cluster.Cluster_EDS)
+	setGRPCHealthCheck(cluster, nil)
+	assert.Nil(cluster.HealthChecks)
+
+	// A health checked service without gating new endpoints
+	cluster = newCluster(xds_cluster.Cluster_EDS)
+	setGRPCHealthCheck(cluster, &trafficpolicy.GRPCHealthCheckPolicy{
+		ServiceName: "bookstore.v1.Bookstore",
+		Authority:   "bookstore.bookstore-ns",
+	})
+	assert.Len(cluster.HealthChecks, 1)
+	assert.Nil(cluster.HealthChecks[0].Validate())
+	assert.Equal("bookstore.v1.Bookstore", cluster.HealthChecks[0].GetGrpcHealthCheck().ServiceName)
+	assert.Equal("bookstore.bookstore-ns", cluster.HealthChecks[0].GetGrpcHealthCheck().Authority)
+	assert.False(cluster.GetCommonLbConfig().GetIgnoreNewHostsUntilFirstHc())
+
+	// A health checked service gating new endpoints
+	cluster = newCluster(xds_cluster.Cluster_EDS)
+	setGRPCHealthCheck(cluster, &trafficpolicy.GRPCHealthCheckPolicy{
+		Authority:        "bookstore.bookstore-ns",
+		GateNewEndpoints: true,
+	})
+	assert.Len(cluster.HealthChecks, 1)
+	assert.Equal("", cluster.HealthChecks[0].GetGrpcHealthCheck().ServiceName)
+	assert.True(cluster.GetCommonLbConfig().GetIgnoreNewHostsUntilFirstHc())
+
+	// The original destination of the requests cannot be health checked
+	cluster = newCluster(xds_cluster.Cluster_ORIGINAL_DST)
+	setGRPCHealthCheck(cluster, &trafficpolicy.GRPCHealthCheckPolicy{
+		Authority:        "bookstore.bookstore-ns",
+		GateNewEndpoints: true,
+	})
+	assert.Nil(cluster.HealthChecks)
+	assert.Nil(cluster.CommonLbConfig)
+}
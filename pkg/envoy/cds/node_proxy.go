@@ -0,0 +1,110 @@
+package cds
+
+import (
+	"sort"
+
+	mapset "github.com/deckarep/golang-set"
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewNodeProxyResponse creates a new Cluster Discovery Response for a per-node proxy.
+// The response contains an inbound cluster forwarding traffic to the node proxy mode pods on the proxy's node,
+// and an outbound cluster per service account of these pods that originates mTLS on their behalf.
+func NewNodeProxyResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	pods, err := meshCatalog.ListPodsForNodeProxy(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing pods for node proxy with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	resp := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeCDS),
+	}
+	if len(pods) == 0 {
+		return resp, nil
+	}
+
+	clusters := []*xds_cluster.Cluster{getNodeProxyInboundCluster()}
+	alreadyAdded := mapset.NewSet()
+	for _, pod := range pods {
+		svcAccount := service.K8sServiceAccount{
+			Name:      pod.Spec.ServiceAccountName,
+			Namespace: pod.Namespace,
+		}
+		if alreadyAdded.Contains(svcAccount) {
+			continue
+		}
+		alreadyAdded.Add(svcAccount)
+
+		cluster, err := getNodeProxyOutboundCluster(svcAccount)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to construct node proxy outbound cluster for service account %s", svcAccount)
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	// The pods are listed in no particular order, so the clusters are sorted by name
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	for _, cluster := range clusters {
+		marshalledCluster, err := ptypes.MarshalAny(cluster)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to marshal cluster %s for node proxy with XDS Certificate SerialNumber=%s",
+				cluster.Name, proxy.GetCertificateSerialNumber())
+			return nil, err
+		}
+		resp.Resources = append(resp.Resources, marshalledCluster)
+	}
+
+	return resp, nil
+}
+
+// getNodeProxyInboundCluster returns an Envoy cluster used by a per-node proxy to forward inbound traffic
+// to the original destination pod once mTLS has been terminated
+func getNodeProxyInboundCluster() *xds_cluster.Cluster {
+	return &xds_cluster.Cluster{
+		Name:           envoy.NodeProxyInboundCluster,
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_ORIGINAL_DST,
+		},
+		LbPolicy: xds_cluster.Cluster_CLUSTER_PROVIDED,
+	}
+}
+
+// getNodeProxyOutboundCluster returns an Envoy cluster used by a per-node proxy to forward outbound traffic
+// sent by pods with the given identity to its original destination over mTLS
+func getNodeProxyOutboundCluster(downstreamIdentity service.K8sServiceAccount) (*xds_cluster.Cluster, error) {
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetNodeProxyUpstreamTLSContext(downstreamIdentity))
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_cluster.Cluster{
+		Name:           envoy.GetNodeProxyOutboundClusterName(downstreamIdentity),
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_ORIGINAL_DST,
+		},
+		LbPolicy: xds_cluster.Cluster_CLUSTER_PROVIDED,
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledUpstreamTLSContext,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,67 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestNewNodeProxyResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	proxy := envoy.NewProxy("", "", nil)
+
+	newPod := func(name, svcAccount string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: svcAccount,
+			},
+		}
+	}
+
+	// No node proxy mode pods on the node
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return(nil, nil)
+	actual, err := NewNodeProxyResponse(mockCatalog, proxy, nil, nil, nil)
+	assert.Nil(err)
+	assert.Empty(actual.Resources)
+
+	// Pods sharing a service account share an outbound cluster
+	pods := []*corev1.Pod{
+		newPod("bookbuyer-1", "bookbuyer"),
+		newPod("bookbuyer-2", "bookbuyer"),
+		newPod("bookstore-1", "bookstore"),
+	}
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return(pods, nil)
+	actual, err = NewNodeProxyResponse(mockCatalog, proxy, nil, nil, nil)
+	assert.Nil(err)
+	assert.Len(actual.Resources, 3)
+
+	var clusterNames []string
+	for _, resource := range actual.Resources {
+		cluster := xds_cluster.Cluster{}
+		assert.Nil(ptypes.UnmarshalAny(resource, &cluster))
+		assert.Equal(xds_cluster.Cluster_ORIGINAL_DST, cluster.GetType())
+		assert.Equal(xds_cluster.Cluster_CLUSTER_PROVIDED, cluster.LbPolicy)
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	assert.Equal([]string{
+		envoy.NodeProxyInboundCluster,
+		"node-proxy-outbound:default/bookbuyer",
+		"node-proxy-outbound:default/bookstore",
+	}, clusterNames)
+}
@@ -0,0 +1,50 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// trafficPriorityThresholds are the circuit breaking thresholds of the clusters of upstream services for each traffic
+// priority class. The normal priority class keeps Envoy's default thresholds: 1024 connections, pending requests and
+// requests, and 3 concurrent retries.
+var trafficPriorityThresholds = map[string]*xds_cluster.CircuitBreakers_Thresholds{
+	// Critical traffic is queued rather than rejected, and retried while up to half of the requests are retries
+	constants.TrafficPriorityCritical: {
+		Priority:           xds_core.RoutingPriority_DEFAULT,
+		MaxConnections:     &wrappers.UInt32Value{Value: 4096},
+		MaxPendingRequests: &wrappers.UInt32Value{Value: 4096},
+		MaxRequests:        &wrappers.UInt32Value{Value: 4096},
+		RetryBudget: &xds_cluster.CircuitBreakers_Thresholds_RetryBudget{
+			BudgetPercent:       &xds_type.Percent{Value: 50},
+			MinRetryConcurrency: &wrappers.UInt32Value{Value: 10},
+		},
+	},
+	// Best effort traffic is rejected early when the upstream service is slow, and barely retried
+	constants.TrafficPriorityBestEffort: {
+		Priority:           xds_core.RoutingPriority_DEFAULT,
+		MaxConnections:     &wrappers.UInt32Value{Value: 256},
+		MaxPendingRequests: &wrappers.UInt32Value{Value: 64},
+		MaxRequests:        &wrappers.UInt32Value{Value: 256},
+		RetryBudget: &xds_cluster.CircuitBreakers_Thresholds_RetryBudget{
+			BudgetPercent:       &xds_type.Percent{Value: 10},
+			MinRetryConcurrency: &wrappers.UInt32Value{Value: 1},
+		},
+	},
+}
+
+// setTrafficPriority sets the circuit breaking thresholds of the given cluster of an upstream service to the thresholds
+// of the given traffic priority class
+func setTrafficPriority(cluster *xds_cluster.Cluster, priority string) {
+	thresholds, ok := trafficPriorityThresholds[priority]
+	if !ok {
+		return
+	}
+	cluster.CircuitBreakers = &xds_cluster.CircuitBreakers{
+		Thresholds: []*xds_cluster.CircuitBreakers_Thresholds{thresholds},
+	}
+}
@@ -0,0 +1,35 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestSetTrafficPriority(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Normal traffic keeps Envoy's default thresholds
+	cluster := &xds_cluster.Cluster{}
+	setTrafficPriority(cluster, constants.TrafficPriorityNormal)
+	assert.Nil(cluster.CircuitBreakers)
+
+	critical := &xds_cluster.Cluster{}
+	setTrafficPriority(critical, constants.TrafficPriorityCritical)
+	assert.Len(critical.CircuitBreakers.Thresholds, 1)
+	assert.Nil(critical.CircuitBreakers.Validate())
+
+	bestEffort := &xds_cluster.Cluster{}
+	setTrafficPriority(bestEffort, constants.TrafficPriorityBestEffort)
+	assert.Len(bestEffort.CircuitBreakers.Thresholds, 1)
+
+	// Best effort traffic is shed before critical traffic, and retried less
+	criticalThresholds := critical.CircuitBreakers.Thresholds[0]
+	bestEffortThresholds := bestEffort.CircuitBreakers.Thresholds[0]
+	assert.Less(bestEffortThresholds.MaxPendingRequests.Value, criticalThresholds.MaxPendingRequests.Value)
+	assert.Less(bestEffortThresholds.MaxRequests.Value, criticalThresholds.MaxRequests.Value)
+	assert.Less(bestEffortThresholds.RetryBudget.BudgetPercent.Value, criticalThresholds.RetryBudget.BudgetPercent.Value)
+}
@@ -0,0 +1,48 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// upstreamProxyProtocolTransportSocket is the name of the transport socket emitting the PROXY protocol header
+	// before the data of the wrapped transport socket
+	upstreamProxyProtocolTransportSocket = "envoy.transport_sockets.upstream_proxy_protocol"
+)
+
+var proxyProtocolVersions = map[string]xds_core.ProxyProtocolConfig_Version{
+	constants.ProxyProtocolV1: xds_core.ProxyProtocolConfig_V1,
+	constants.ProxyProtocolV2: xds_core.ProxyProtocolConfig_V2,
+}
+
+// setUpstreamProxyProtocol wraps the transport socket of the given cluster to emit the PROXY protocol header, in the given
+// version, on the connections to the cluster. The header is sent ahead of the TLS handshake of the wrapped transport socket.
+func setUpstreamProxyProtocol(cluster *xds_cluster.Cluster, version string) error {
+	proxyProtocolVersion, ok := proxyProtocolVersions[version]
+	if !ok {
+		return errors.Errorf("unsupported PROXY protocol version %q", version)
+	}
+
+	marshalledProxyProtocol, err := ptypes.MarshalAny(&xds_proxy_protocol.ProxyProtocolUpstreamTransport{
+		Config:          &xds_core.ProxyProtocolConfig{Version: proxyProtocolVersion},
+		TransportSocket: cluster.TransportSocket,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling ProxyProtocolUpstreamTransport for cluster %s", cluster.Name)
+		return err
+	}
+
+	cluster.TransportSocket = &xds_core.TransportSocket{
+		Name: upstreamProxyProtocolTransportSocket,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledProxyProtocol,
+		},
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestSetUpstreamProxyProtocol(t *testing.T) {
+	testCases := []struct {
+		name            string
+		version         string
+		expectedVersion xds_core.ProxyProtocolConfig_Version
+		expectError     bool
+	}{
+		{
+			name:            "version 1",
+			version:         constants.ProxyProtocolV1,
+			expectedVersion: xds_core.ProxyProtocolConfig_V1,
+		},
+		{
+			name:            "version 2",
+			version:         constants.ProxyProtocolV2,
+			expectedVersion: xds_core.ProxyProtocolConfig_V2,
+		},
+		{
+			name:        "unsupported version",
+			version:     "v3",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			tlsTransportSocket := &xds_core.TransportSocket{Name: wellknown.TransportSocketTls}
+			cluster := &xds_cluster.Cluster{
+				Name:            "bookstore/bookstore",
+				TransportSocket: tlsTransportSocket,
+			}
+
+			err := setUpstreamProxyProtocol(cluster, tc.version)
+			assert.Equal(tc.expectError, err != nil)
+			if tc.expectError {
+				// The cluster is left unchanged
+				assert.Equal(tlsTransportSocket, cluster.TransportSocket)
+				return
+			}
+
+			assert.Equal(upstreamProxyProtocolTransportSocket, cluster.TransportSocket.Name)
+			proxyProtocol := &xds_proxy_protocol.ProxyProtocolUpstreamTransport{}
+			err = ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), proxyProtocol)
+			assert.Nil(err)
+			assert.Equal(tc.expectedVersion, proxyProtocol.Config.Version)
+			// The TLS transport socket is wrapped
+			assert.Equal(wellknown.TransportSocketTls, proxyProtocol.TransportSocket.Name)
+		})
+	}
+}
@@ -1,26 +1,35 @@
 package cds
 
 import (
-	mapset "github.com/deckarep/golang-set"
+	"fmt"
+	"sort"
+
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes"
 
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 // NewResponse creates a new Cluster Discovery Response.
 func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
 	svcList, err := meshCatalog.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
-	if err != nil {
+	if err != nil && proxy.IsDevProxy() {
+		// A dev proxy is not backed by a Pod, so it does not front any service
+		svcList = nil
+	} else if err != nil {
 		log.Error().Err(err).Msgf("Error looking up MeshService for Envoy with SerialNumber=%s on Pod with UID=%s", proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		return nil, err
 	}
 
-	var clusters []*xds_cluster.Cluster
+	var clusters []envoy.NamedResource
 
 	proxyIdentity, err := catalog.GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
@@ -38,49 +47,170 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 			return nil, err
 		}
 
-		clusters = append(clusters, cluster)
+		if version := meshCatalog.GetUpstreamProxyProtocolVersion(dstService); version != "" {
+			if err := setUpstreamProxyProtocol(cluster, version); err != nil {
+				log.Error().Err(err).Msgf("Failed to configure PROXY protocol on cluster for service %s for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+					dstService, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+				return nil, err
+			}
+		}
+
+		setTrafficPriority(cluster, meshCatalog.GetTrafficPriority(dstService))
+		setUpstreamConnectionLimits(cluster, meshCatalog.GetUpstreamConnectionPolicy(dstService))
+		setGRPCHealthCheck(cluster, meshCatalog.GetGRPCHealthCheckPolicy(dstService))
+		setFailoverTransportSocket(cluster, meshCatalog.GetFailoverPolicy(dstService))
+		setIdleTimeout(cluster, meshCatalog.GetTimeoutPolicy(dstService))
+
+		clusters = append(clusters, newNamedCluster(cluster, "upstream service %s", dstService))
 	}
 
 	// Create a local cluster for each service behind the proxy.
-	// The local cluster will be used to handle incoming traffic.
+	// The local cluster will be used to handle incoming HTTP traffic.
 	for _, proxyService := range svcList {
 		localClusterName := envoy.GetLocalClusterNameForService(proxyService)
-		localCluster, err := getLocalServiceCluster(meshCatalog, proxyService, localClusterName)
+		localCluster, err := getLocalServiceCluster(meshCatalog, proxyService, localClusterName, proxy.HasIPv6PodIP())
 		if err != nil {
 			log.Error().Err(err).Msgf("Failed to get local cluster config for proxy %s", proxyService)
 			return nil, err
 		}
-		clusters = append(clusters, localCluster)
+		setInboundConnectionLimits(localCluster, meshCatalog.GetInboundConnectionPolicy(proxyService))
+		setIdleTimeout(localCluster, meshCatalog.GetTimeoutPolicy(proxyService))
+		clusters = append(clusters, newNamedCluster(localCluster, "local service %s", proxyService))
+	}
+
+	// Incoming TCP traffic, and plaintext traffic from outside the mesh on services that allow it, is forwarded to the
+	// local cluster for the target port it was sent to. Ingress traffic to a port of a service with multiple target
+	// ports is routed to the local cluster for its target port. Ports shared by multiple services must only be
+	// configured once.
+	localPorts := make(map[uint32]bool)
+	for _, proxyService := range svcList {
+		svcPorts, err := meshCatalog.ListServicePorts(proxyService)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to get ports for service %s", proxyService)
+			return nil, err
+		}
+		externalPlaintextTrafficAllowed := meshCatalog.IsExternalPlaintextTrafficAllowed(proxyService)
+		targetPorts := service.DedupTargetPorts(svcPorts)
+		for _, svcPort := range targetPorts {
+			port := svcPort.TargetPort
+			if localPorts[port] {
+				continue
+			}
+			if isTCPAppProtocol(svcPort.Protocol) {
+				localPorts[port] = true
+				clusters = append(clusters, newNamedCluster(getLocalPortCluster(port, proxy.HasIPv6PodIP()), "TCP port %d of service %s", port, proxyService))
+			} else if externalPlaintextTrafficAllowed {
+				localPorts[port] = true
+				clusters = append(clusters, newNamedCluster(getLocalPortCluster(port, proxy.HasIPv6PodIP()), "external traffic to port %d of service %s", port, proxyService))
+			} else if len(targetPorts) > 1 {
+				localPorts[port] = true
+				clusters = append(clusters, newNamedCluster(getLocalPortCluster(port, proxy.HasIPv6PodIP()), "ingress traffic to port %d of service %s", port, proxyService))
+			}
+		}
+
+		// Ingress traffic on the tcp and tls-passthrough ports listed by the IngressBackend of the service is proxied to
+		// the local cluster for the port
+		ingressBackendPolicy, err := meshCatalog.GetIngressBackendPolicy(proxyService)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to get IngressBackend policy for service %s", proxyService)
+			continue
+		}
+		if ingressBackendPolicy == nil {
+			continue
+		}
+		var ingressPorts []uint32
+		for port, protocol := range ingressBackendPolicy.Ports {
+			if !localPorts[port] && (protocol == policyV1alpha1.ProtocolTCP || protocol == policyV1alpha1.ProtocolTLSPassthrough) {
+				ingressPorts = append(ingressPorts, port)
+			}
+		}
+		sort.Slice(ingressPorts, func(i, j int) bool {
+			return ingressPorts[i] < ingressPorts[j]
+		})
+		for _, port := range ingressPorts {
+			localPorts[port] = true
+			clusters = append(clusters, newNamedCluster(getLocalPortCluster(port, proxy.HasIPv6PodIP()), "ingress %s traffic to port %d of service %s",
+				ingressBackendPolicy.Ports[port], port, proxyService))
+		}
 	}
 
-	// Add an outbound passthrough cluster for egress
-	if cfg.IsEgressEnabled() {
-		clusters = append(clusters, getOutboundPassthroughCluster())
+	// A pod that is not selected by any service can still accept traffic on its declared container ports
+	// in permissive mode. Create a local cluster for each of these ports.
+	if len(svcList) == 0 && cfg.IsPermissiveTrafficPolicyMode() && !proxy.IsDevProxy() {
+		ports, err := meshCatalog.ListContainerPortsFromEnvoyCertificate(proxy.GetCertificateCommonName())
+		if err != nil {
+			log.Error().Err(err).Msgf("Error looking up container ports for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			return nil, err
+		}
+		for _, port := range ports {
+			clusters = append(clusters, newNamedCluster(getLocalPortCluster(port, proxy.HasIPv6PodIP()), "container port %d", port))
+		}
+	}
+
+	// Egress traffic is forwarded to the egress gateway instead of its destination when the egress gateway is enabled
+	var egressGateway *egressGatewayUpstream
+	if featureflags.IsEgressGatewayEnabled() {
+		egressGateway = newEgressGatewayUpstream(proxyIdentity, cfg.GetOSMNamespace())
+	}
+
+	// Add an outbound passthrough cluster for egress, unless disabled for the namespace or the pod of the proxy. The
+	// egress connections not allowed by the Egress policies of the proxy are forwarded to it in egress audit mode.
+	if meshCatalog.GetProxyOverrides(proxy).Bool(constants.EgressAnnotation, cfg.IsEgressEnabled()) || meshCatalog.IsEgressAuditEnabled(proxy) {
+		passthroughCluster := getOutboundPassthroughCluster()
+		if err := egressGateway.route(passthroughCluster, ""); err != nil {
+			log.Error().Err(err).Msgf("Failed to route the egress passthrough cluster through the egress gateway for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			return nil, err
+		}
+		clusters = append(clusters, newNamedCluster(passthroughCluster, "egress"))
+	}
+
+	// Add the outbound clusters of the external destinations allowed by the Egress policies of the proxy
+	if egressPolicy := meshCatalog.GetEgressPolicy(proxyIdentity); egressPolicy != nil {
+		egressClusters, err := getEgressClusters(egressPolicy, proxyIdentity, egressGateway)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to construct the egress clusters of Egress policies %v for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				egressPolicy.Names, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			return nil, err
+		}
+		clusters = append(clusters, egressClusters...)
 	}
 
 	// Add an inbound prometheus cluster (from Prometheus to localhost)
 	if cfg.IsPrometheusScrapingEnabled() {
-		clusters = append(clusters, getPrometheusCluster())
+		clusters = append(clusters, newNamedCluster(getPrometheusCluster(), "Prometheus scraping"))
 	}
 
 	// Add an outbound tracing cluster (from localhost to tracing sink)
 	if cfg.IsTracingEnabled() {
-		clusters = append(clusters, getTracingCluster(cfg))
+		clusters = append(clusters, newNamedCluster(getTracingCluster(cfg), "tracing"))
+	}
+
+	// Add an outbound cluster to fetch the WAF WASM module (from localhost to the module server)
+	if moduleURL := cfg.GetWAFModuleURL(); moduleURL != "" {
+		wafCluster, err := getWAFModuleCluster(moduleURL)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to construct WAF module cluster for Envoy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+			return nil, err
+		}
+		clusters = append(clusters, newNamedCluster(wafCluster, "WAF module %s", moduleURL))
 	}
 
 	resp := &xds_discovery.DiscoveryResponse{
 		TypeUrl: string(envoy.TypeCDS),
 	}
 
-	alreadyAdded := mapset.NewSet()
-	for _, cluster := range clusters {
-		if alreadyAdded.Contains(cluster.Name) {
-			log.Error().Msgf("Found duplicate clusters with name %s; Duplicate will not be sent to Envoy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
-				cluster.Name, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
-			continue
-		}
-		alreadyAdded.Add(cluster.Name)
-		marshalledClusters, err := ptypes.MarshalAny(cluster)
+	// Clusters are sorted by name so that the response does not depend on the iteration order of the maps some of them
+	// are built from
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	// Clusters with colliding names are dropped as Envoy would reject the response
+	for _, cluster := range envoy.RemoveConflictingResources(proxy, envoy.ClusterResourceKind, clusters) {
+		marshalledClusters, err := ptypes.MarshalAny(cluster.Resource)
 		if err != nil {
 			log.Error().Err(err).Msgf("Failed to marshal cluster %s for Envoy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
 				cluster.Name, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
@@ -91,3 +221,12 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 
 	return resp, nil
 }
+
+// newNamedCluster returns the given cluster along with the source of its config, described by the given format
+func newNamedCluster(cluster *xds_cluster.Cluster, sourceFormat string, args ...interface{}) envoy.NamedResource {
+	return envoy.NamedResource{
+		Name:     cluster.Name,
+		Source:   fmt.Sprintf(sourceFormat, args...),
+		Resource: cluster,
+	}
+}
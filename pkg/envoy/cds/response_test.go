@@ -19,6 +19,8 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	trequire "github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -27,6 +29,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestNewResponse(t *testing.T) {
@@ -45,11 +48,25 @@ func TestNewResponse(t *testing.T) {
 
 	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(xdsCertificate).Return([]service.MeshService{tests.BookbuyerService}, nil).AnyTimes()
 	mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tests.BookbuyerServiceAccount).Return([]service.MeshService{tests.BookstoreV1Service, tests.BookstoreV2Service}).AnyTimes()
-	mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tests.BookbuyerService).Return(map[uint32]string{uint32(80): "protocol"}, nil)
+	mockCatalog.EXPECT().ListServicePorts(tests.BookbuyerService).Return([]service.MeshService{
+		{Namespace: tests.BookbuyerService.Namespace, Name: tests.BookbuyerService.Name, Port: 80, TargetPort: 80, Protocol: "protocol"},
+	}, nil).AnyTimes()
+	mockCatalog.EXPECT().IsExternalPlaintextTrafficAllowed(tests.BookbuyerService).Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetUpstreamProxyProtocolVersion(gomock.Any()).Return("").AnyTimes()
+	mockCatalog.EXPECT().GetTrafficPriority(gomock.Any()).Return(constants.TrafficPriorityNormal).AnyTimes()
+	mockCatalog.EXPECT().GetGRPCHealthCheckPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetFailoverPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetTimeoutPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetUpstreamConnectionPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetInboundConnectionPolicy(tests.BookbuyerService).Return(trafficpolicy.InboundConnectionPolicy{}).AnyTimes()
+	mockCatalog.EXPECT().GetIngressBackendPolicy(tests.BookbuyerService).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetProxyOverrides(proxy).Return(configurator.NewOverrides()).AnyTimes()
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().IsEgressEnabled().Return(true).AnyTimes()
 	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(true).AnyTimes()
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
 	mockConfigurator.EXPECT().GetTracingHost().Return(constants.DefaultTracingHost).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingPort().Return(constants.DefaultTracingPort).AnyTimes()
 
@@ -343,3 +360,163 @@ func TestNewResponse(t *testing.T) {
 
 	assert.ElementsMatch(expectedClusters, foundClusters)
 }
+
+func TestNewResponseForDevProxy(t *testing.T) {
+	assert := tassert.New(t)
+	require := trequire.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	xdsCertificate := catalog.NewDevProxyCertCommonName(uuid.New(), tests.BookbuyerServiceAccountName, tests.Namespace)
+	proxy := envoy.NewProxy(xdsCertificate, "123456", nil)
+
+	// A dev proxy is not backed by a Pod
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(xdsCertificate).Return(nil, catalog.ErrDidNotFindPodForCertificate).AnyTimes()
+	mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tests.BookbuyerServiceAccount).Return([]service.MeshService{tests.BookstoreV1Service}).AnyTimes()
+	mockCatalog.EXPECT().GetUpstreamProxyProtocolVersion(tests.BookstoreV1Service).Return("").AnyTimes()
+	mockCatalog.EXPECT().GetTrafficPriority(tests.BookstoreV1Service).Return(constants.TrafficPriorityNormal).AnyTimes()
+	mockCatalog.EXPECT().GetGRPCHealthCheckPolicy(tests.BookstoreV1Service).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetFailoverPolicy(tests.BookstoreV1Service).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetTimeoutPolicy(tests.BookstoreV1Service).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetUpstreamConnectionPolicy(tests.BookstoreV1Service).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetProxyOverrides(proxy).Return(configurator.NewOverrides()).AnyTimes()
+	mockCatalog.EXPECT().IsEgressAuditEnabled(proxy).Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+
+	resp, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	require.Nil(err)
+
+	// Only the upstream cluster is programmed
+	assert.Len(resp.Resources, 1)
+	cl := xds_cluster.Cluster{}
+	require.Nil(ptypes.UnmarshalAny(resp.Resources[0], &cl))
+	assert.Equal(tests.BookstoreV1Service.String(), cl.Name)
+}
+
+func TestNewResponseWithEgressOverride(t *testing.T) {
+	assert := tassert.New(t)
+	require := trequire.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	xdsCertificate := catalog.NewCertCommonNameWithProxyID(uuid.New(), tests.BookbuyerServiceAccountName, tests.Namespace)
+	proxy := envoy.NewProxy(xdsCertificate, "123456", nil)
+
+	// Egress is disabled mesh-wide, but enabled for the namespace of the proxy
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tests.Namespace,
+			Annotations: map[string]string{constants.EgressAnnotation: "true"},
+		},
+	}
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(xdsCertificate).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetProxyOverrides(proxy).Return(configurator.NewOverrides(namespace)).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+
+	resp, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	require.Nil(err)
+
+	// Only the passthrough cluster for egress is programmed
+	assert.Len(resp.Resources, 1)
+	cl := xds_cluster.Cluster{}
+	require.Nil(ptypes.UnmarshalAny(resp.Resources[0], &cl))
+	assert.Equal(envoy.OutboundPassthroughCluster, cl.Name)
+}
+
+func TestNewResponseWithEgressAudit(t *testing.T) {
+	assert := tassert.New(t)
+	require := trequire.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	xdsCertificate := catalog.NewCertCommonNameWithProxyID(uuid.New(), tests.BookbuyerServiceAccountName, tests.Namespace)
+	proxy := envoy.NewProxy(xdsCertificate, "123456", nil)
+
+	// Egress is disabled, but the Egress policies of the proxy are evaluated in audit mode
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(xdsCertificate).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetProxyOverrides(proxy).Return(configurator.NewOverrides()).AnyTimes()
+	mockCatalog.EXPECT().IsEgressAuditEnabled(proxy).Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+
+	resp, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	require.Nil(err)
+
+	// The connections not allowed by the Egress policies are forwarded to the passthrough cluster
+	assert.Len(resp.Resources, 1)
+	cl := xds_cluster.Cluster{}
+	require.Nil(ptypes.UnmarshalAny(resp.Resources[0], &cl))
+	assert.Equal(envoy.OutboundPassthroughCluster, cl.Name)
+}
+
+func TestNewResponseForPodWithoutService(t *testing.T) {
+	assert := tassert.New(t)
+	require := trequire.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	xdsCertificate := catalog.NewCertCommonNameWithProxyID(uuid.New(), tests.BookbuyerServiceAccountName, tests.Namespace)
+	proxy := envoy.NewProxy(xdsCertificate, "123456", nil)
+
+	// The pod of the proxy is not selected by any service, and declares container ports
+	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(xdsCertificate).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().ListContainerPortsFromEnvoyCertificate(xdsCertificate).Return([]uint32{8080, 9090}, nil).Times(1)
+	mockCatalog.EXPECT().ListAllowedOutboundServicesForIdentity(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(tests.BookbuyerServiceAccount).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetProxyOverrides(proxy).Return(configurator.NewOverrides()).AnyTimes()
+	mockCatalog.EXPECT().IsEgressAuditEnabled(proxy).Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+
+	resp, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	require.Nil(err)
+
+	// A local cluster is programmed for each container port in permissive mode
+	var actualClusters []*xds_cluster.Cluster
+	for _, resource := range resp.Resources {
+		cl := &xds_cluster.Cluster{}
+		require.Nil(ptypes.UnmarshalAny(resource, cl))
+		actualClusters = append(actualClusters, cl)
+	}
+	expectedClusters := []*xds_cluster.Cluster{getLocalPortCluster(8080, false), getLocalPortCluster(9090, false)}
+	assert.Truef(cmp.Equal(expectedClusters, actualClusters, protocmp.Transform()), cmp.Diff(expectedClusters, actualClusters, protocmp.Transform()))
+
+	// No local cluster is programmed for the container ports outside of permissive mode
+	mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+
+	resp, err = NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	require.Nil(err)
+	assert.Empty(resp.Resources)
+}
@@ -0,0 +1,20 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// setIdleTimeout closes the connections of the given cluster that go without requests for the idle timeout of the given
+// timeout policy. The idle timeout of Envoy applies when the policy does not set it.
+func setIdleTimeout(cluster *xds_cluster.Cluster, policy *trafficpolicy.TimeoutPolicy) {
+	if policy == nil || policy.IdleTimeout == nil {
+		return
+	}
+	cluster.CommonHttpProtocolOptions = &xds_core.HttpProtocolOptions{
+		IdleTimeout: ptypes.DurationProto(*policy.IdleTimeout),
+	}
+}
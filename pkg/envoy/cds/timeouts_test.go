@@ -0,0 +1,31 @@
+package cds
+
+import (
+	"testing"
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetIdleTimeout(t *testing.T) {
+	assert := tassert.New(t)
+
+	// The idle timeout of Envoy applies by default
+	cluster := &xds_cluster.Cluster{}
+	setIdleTimeout(cluster, nil)
+	assert.Nil(cluster.CommonHttpProtocolOptions)
+
+	requestTimeout := time.Minute
+	setIdleTimeout(cluster, &trafficpolicy.TimeoutPolicy{RequestTimeout: &requestTimeout})
+	assert.Nil(cluster.CommonHttpProtocolOptions)
+
+	idleTimeout := 10 * time.Minute
+	timed := &xds_cluster.Cluster{Name: "default/bookstore-local"}
+	setIdleTimeout(timed, &trafficpolicy.TimeoutPolicy{IdleTimeout: &idleTimeout})
+	assert.Nil(timed.Validate())
+	assert.Equal(ptypes.DurationProto(idleTimeout), timed.CommonHttpProtocolOptions.IdleTimeout)
+}
@@ -8,3 +8,8 @@ import (
 var (
 	log = logger.New("envoy/cds")
 )
+
+const (
+	// tcpAppProtocol is the application protocol of the service ports proxied as opaque TCP traffic
+	tcpAppProtocol = "tcp"
+)
@@ -0,0 +1,49 @@
+package cds
+
+import (
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// setUpstreamConnectionLimits sets the limits of the given upstream connection policy on the circuit breaking
+// thresholds of the given cluster of an upstream service, overriding the thresholds of the traffic priority of the
+// service. A max number of retries replaces the retry budget of the traffic priority, which Envoy would otherwise
+// enforce instead.
+func setUpstreamConnectionLimits(cluster *xds_cluster.Cluster, policy *trafficpolicy.UpstreamConnectionPolicy) {
+	if policy == nil {
+		return
+	}
+
+	thresholds := &xds_cluster.CircuitBreakers_Thresholds{
+		Priority: xds_core.RoutingPriority_DEFAULT,
+	}
+	if cluster.CircuitBreakers != nil && len(cluster.CircuitBreakers.Thresholds) > 0 {
+		// The thresholds of the traffic priorities are shared by the clusters, so they are copied before being modified
+		thresholds = proto.Clone(cluster.CircuitBreakers.Thresholds[0]).(*xds_cluster.CircuitBreakers_Thresholds)
+	}
+
+	if policy.MaxConnections != nil {
+		thresholds.MaxConnections = &wrappers.UInt32Value{Value: *policy.MaxConnections}
+	}
+	if policy.MaxPendingRequests != nil {
+		thresholds.MaxPendingRequests = &wrappers.UInt32Value{Value: *policy.MaxPendingRequests}
+	}
+	if policy.MaxRequests != nil {
+		thresholds.MaxRequests = &wrappers.UInt32Value{Value: *policy.MaxRequests}
+	}
+	if policy.MaxRetries != nil {
+		thresholds.MaxRetries = &wrappers.UInt32Value{Value: *policy.MaxRetries}
+		thresholds.RetryBudget = nil
+	}
+	cluster.CircuitBreakers = &xds_cluster.CircuitBreakers{
+		Thresholds: []*xds_cluster.CircuitBreakers_Thresholds{thresholds},
+	}
+
+	if policy.MaxRequestsPerConnection != nil {
+		cluster.MaxRequestsPerConnection = &wrappers.UInt32Value{Value: *policy.MaxRequestsPerConnection}
+	}
+}
@@ -0,0 +1,62 @@
+package cds
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetUpstreamConnectionLimits(t *testing.T) {
+	assert := tassert.New(t)
+
+	maxConnections := uint32(100)
+	maxPendingRequests := uint32(10)
+	maxRetries := uint32(2)
+	maxRequestsPerConnection := uint32(1)
+
+	// The thresholds are not set without an upstream connection policy
+	cluster := &xds_cluster.Cluster{}
+	setUpstreamConnectionLimits(cluster, nil)
+	assert.Nil(cluster.CircuitBreakers)
+	assert.Nil(cluster.MaxRequestsPerConnection)
+
+	// The limits of the policy are set on the default thresholds of Envoy
+	limited := &xds_cluster.Cluster{Name: "default/bookstore"}
+	setUpstreamConnectionLimits(limited, &trafficpolicy.UpstreamConnectionPolicy{
+		MaxConnections:           &maxConnections,
+		MaxPendingRequests:       &maxPendingRequests,
+		MaxRetries:               &maxRetries,
+		MaxRequestsPerConnection: &maxRequestsPerConnection,
+	})
+	assert.Nil(limited.Validate())
+	assert.Len(limited.CircuitBreakers.Thresholds, 1)
+	thresholds := limited.CircuitBreakers.Thresholds[0]
+	assert.Equal(maxConnections, thresholds.MaxConnections.GetValue())
+	assert.Equal(maxPendingRequests, thresholds.MaxPendingRequests.GetValue())
+	assert.Equal(maxRetries, thresholds.MaxRetries.GetValue())
+	assert.Nil(thresholds.MaxRequests)
+	assert.Equal(maxRequestsPerConnection, limited.MaxRequestsPerConnection.GetValue())
+
+	// The limits of the policy override the thresholds of the traffic priority, without modifying the thresholds of
+	// the other clusters of the priority
+	critical := &xds_cluster.Cluster{Name: "default/bookstore"}
+	setTrafficPriority(critical, constants.TrafficPriorityCritical)
+	setUpstreamConnectionLimits(critical, &trafficpolicy.UpstreamConnectionPolicy{
+		MaxConnections: &maxConnections,
+		MaxRetries:     &maxRetries,
+	})
+	assert.Nil(critical.Validate())
+	thresholds = critical.CircuitBreakers.Thresholds[0]
+	assert.Equal(maxConnections, thresholds.MaxConnections.GetValue())
+	assert.Equal(maxRetries, thresholds.MaxRetries.GetValue())
+	assert.Nil(thresholds.RetryBudget)
+	priorityThresholds := trafficPriorityThresholds[constants.TrafficPriorityCritical]
+	assert.Equal(priorityThresholds.MaxPendingRequests.GetValue(), thresholds.MaxPendingRequests.GetValue())
+	assert.Equal(uint32(4096), priorityThresholds.MaxConnections.GetValue())
+	assert.NotNil(priorityThresholds.RetryBudget)
+	assert.Nil(critical.MaxRequestsPerConnection)
+}
@@ -0,0 +1,82 @@
+package cds
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// getWAFModuleCluster returns the cluster used by the WASM filter of the WAF to fetch its module from the given URL
+func getWAFModuleCluster(moduleURL string) (*xds_cluster.Cluster, error) {
+	u, err := url.Parse(moduleURL)
+	if err != nil {
+		return nil, errors.Errorf("invalid WAF module URL %s: %s", moduleURL, err)
+	}
+
+	port := uint32(80)
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		p, err := strconv.ParseUint(u.Port(), 10, 16)
+		if err != nil {
+			return nil, errors.Errorf("invalid port in WAF module URL %s: %s", moduleURL, err)
+		}
+		port = uint32(p)
+	}
+
+	cluster := &xds_cluster.Cluster{
+		Name:           constants.EnvoyWAFModuleCluster,
+		AltStatName:    constants.EnvoyWAFModuleCluster,
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_LOGICAL_DNS,
+		},
+		LbPolicy: xds_cluster.Cluster_ROUND_ROBIN,
+		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+			ClusterName: constants.EnvoyWAFModuleCluster,
+			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(u.Hostname(), port),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if u.Scheme == "https" {
+		sni := u.Hostname()
+		if net.ParseIP(sni) != nil {
+			// SNI must be a host name
+			sni = ""
+		}
+		marshalledUpstreamTLSContext, err := ptypes.MarshalAny(&xds_auth.UpstreamTlsContext{Sni: sni})
+		if err != nil {
+			return nil, err
+		}
+		cluster.TransportSocket = &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledUpstreamTLSContext,
+			},
+		}
+	}
+
+	return cluster, nil
+}
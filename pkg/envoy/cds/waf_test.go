@@ -0,0 +1,76 @@
+package cds
+
+import (
+	"testing"
+
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetWAFModuleCluster(t *testing.T) {
+	testCases := []struct {
+		name         string
+		moduleURL    string
+		expectedHost string
+		expectedPort uint32
+		expectedSNI  string
+		expectTLS    bool
+		expectError  bool
+	}{
+		{
+			name:         "https URL without port",
+			moduleURL:    "https://example.com/coraza.wasm",
+			expectedHost: "example.com",
+			expectedPort: 443,
+			expectedSNI:  "example.com",
+			expectTLS:    true,
+		},
+		{
+			name:         "http URL with port",
+			moduleURL:    "http://waf-modules.osm-system.svc.cluster.local:8080/coraza.wasm",
+			expectedHost: "waf-modules.osm-system.svc.cluster.local",
+			expectedPort: 8080,
+			expectTLS:    false,
+		},
+		{
+			name:         "https URL with IP address",
+			moduleURL:    "https://10.0.0.1:8443/coraza.wasm",
+			expectedHost: "10.0.0.1",
+			expectedPort: 8443,
+			expectedSNI:  "",
+			expectTLS:    true,
+		},
+		{
+			name:        "URL with invalid port",
+			moduleURL:   "http://example.com:99999/coraza.wasm",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			cluster, err := getWAFModuleCluster(tc.moduleURL)
+			assert.Equal(tc.expectError, err != nil)
+			if tc.expectError {
+				return
+			}
+
+			assert.Equal(constants.EnvoyWAFModuleCluster, cluster.Name)
+			address := cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+			assert.Equal(tc.expectedHost, address.Address)
+			assert.Equal(tc.expectedPort, address.GetPortValue())
+
+			assert.Equal(tc.expectTLS, cluster.TransportSocket != nil)
+			if tc.expectTLS {
+				tlsContext := &xds_auth.UpstreamTlsContext{}
+				assert.Nil(ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), tlsContext))
+				assert.Equal(tc.expectedSNI, tlsContext.Sni)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package envoy
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/proto"
+)
+
+// memoryPressureMutex guards the memory pressure recorded on proxies, which is refreshed off the xDS push path of the
+// proxies
+var memoryPressureMutex sync.RWMutex
+
+// sentConfig is the record of the last xDS response of a type sent to a proxy, used to tell whether a new response only
+// adds resources to it
+type sentConfig struct {
+	// size is the size in bytes of the response
+	size int64
+
+	// digests are the digests of the resources of the response
+	digests map[[sha256.Size]byte]bool
+}
+
+// SetMemoryPressure records the share of its memory limit the proxy reports using
+func (p *Proxy) SetMemoryPressure(pressure float64) {
+	memoryPressureMutex.Lock()
+	defer memoryPressureMutex.Unlock()
+	p.memoryPressure = pressure
+	p.memoryPressureKnown = true
+}
+
+// ForgetMemoryPressure records that the memory pressure of the proxy is unknown
+func (p *Proxy) ForgetMemoryPressure() {
+	memoryPressureMutex.Lock()
+	defer memoryPressureMutex.Unlock()
+	p.memoryPressure = 0
+	p.memoryPressureKnown = false
+}
+
+// GetMemoryPressure returns the share of its memory limit the proxy last reported using, and false if it is unknown
+func (p *Proxy) GetMemoryPressure() (float64, bool) {
+	memoryPressureMutex.RLock()
+	defer memoryPressureMutex.RUnlock()
+	return p.memoryPressure, p.memoryPressureKnown
+}
+
+// RecordSentConfig records the size and the digests of the resources of an xDS response of the given type sent to the
+// proxy. Computing the digests re-marshals every resource, so they are only recorded while responses may be withheld
+// from the proxies.
+func (p *Proxy) RecordSentConfig(typeURI TypeURI, size int64, resources []*any.Any) {
+	if p.sentConfigs == nil {
+		p.sentConfigs = make(map[TypeURI]*sentConfig)
+	}
+	p.sentConfigs[typeURI] = &sentConfig{
+		size:    size,
+		digests: getResourceDigests(resources),
+	}
+}
+
+// ForgetSentConfig forgets the xDS responses of the given type sent to the proxy
+func (p *Proxy) ForgetSentConfig(typeURI TypeURI) {
+	delete(p.sentConfigs, typeURI)
+}
+
+// GetSentConfigSize returns the size in bytes of the last recorded xDS response of the given type sent to the proxy, or
+// 0 if none was recorded
+func (p *Proxy) GetSentConfigSize(typeURI TypeURI) int64 {
+	if sent, ok := p.sentConfigs[typeURI]; ok {
+		return sent.size
+	}
+	return 0
+}
+
+// IsAdditiveConfig returns true if the given resources of an xDS response of the given type include all the resources
+// of the last recorded response of this type sent to the proxy unchanged, i.e. the response only adds resources. It
+// returns false if no response of this type was recorded, as the resources the proxy has are then unknown.
+func (p *Proxy) IsAdditiveConfig(typeURI TypeURI, resources []*any.Any) bool {
+	sent, ok := p.sentConfigs[typeURI]
+	if !ok {
+		return false
+	}
+	digests := getResourceDigests(resources)
+	for digest := range sent.digests {
+		if !digests[digest] {
+			return false
+		}
+	}
+	return true
+}
+
+// getResourceDigests returns the digests of the given resources. The resources are marshaled deterministically, as the
+// order of the entries of the map fields of a resource differs between two marshalings of the same resource otherwise.
+func getResourceDigests(resources []*any.Any) map[[sha256.Size]byte]bool {
+	digests := make(map[[sha256.Size]byte]bool, len(resources))
+	for _, resource := range resources {
+		value := resource.Value
+		if message, err := resource.UnmarshalNew(); err == nil {
+			if b, err := (proto.MarshalOptions{Deterministic: true}).Marshal(message); err == nil {
+				value = b
+			}
+		}
+		digests[sha256.Sum256(append([]byte(resource.TypeUrl), value...))] = true
+	}
+	return digests
+}
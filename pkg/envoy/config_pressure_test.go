@@ -0,0 +1,98 @@
+package envoy
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/golang/protobuf/proto"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+func TestMemoryPressure(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy := NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+	_, ok := proxy.GetMemoryPressure()
+	assert.False(ok)
+
+	proxy.SetMemoryPressure(0.9)
+	pressure, ok := proxy.GetMemoryPressure()
+	assert.True(ok)
+	assert.Equal(0.9, pressure)
+
+	proxy.ForgetMemoryPressure()
+	_, ok = proxy.GetMemoryPressure()
+	assert.False(ok)
+}
+
+func TestIsAdditiveConfig(t *testing.T) {
+	cluster1 := &xds_cluster.Cluster{Name: "cluster-1"}
+	cluster2 := &xds_cluster.Cluster{Name: "cluster-2"}
+	changedCluster1 := &xds_cluster.Cluster{Name: "cluster-1", AltStatName: "changed"}
+
+	testCases := []struct {
+		name             string
+		recorded         bool
+		resources        []proto.Message
+		expectedAdditive bool
+	}{
+		{
+			name:             "no response recorded",
+			recorded:         false,
+			resources:        []proto.Message{cluster1, cluster2},
+			expectedAdditive: false,
+		},
+		{
+			name:             "same resources",
+			recorded:         true,
+			resources:        []proto.Message{cluster1},
+			expectedAdditive: true,
+		},
+		{
+			name:             "added resource",
+			recorded:         true,
+			resources:        []proto.Message{cluster2, cluster1},
+			expectedAdditive: true,
+		},
+		{
+			name:             "removed resource",
+			recorded:         true,
+			resources:        []proto.Message{cluster2},
+			expectedAdditive: false,
+		},
+		{
+			name:             "changed resource",
+			recorded:         true,
+			resources:        []proto.Message{changedCluster1, cluster2},
+			expectedAdditive: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			proxy := NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+			if tc.recorded {
+				proxy.RecordSentConfig(TypeCDS, 42, marshalResources(t, cluster1))
+			}
+
+			assert.Equal(tc.expectedAdditive, proxy.IsAdditiveConfig(TypeCDS, marshalResources(t, tc.resources...)))
+		})
+	}
+}
+
+func TestGetSentConfigSize(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy := NewProxy(certificate.CommonName("abcd.sa.ns"), certificate.SerialNumber("123456"), nil)
+	assert.Equal(int64(0), proxy.GetSentConfigSize(TypeLDS))
+
+	proxy.RecordSentConfig(TypeLDS, 42, nil)
+	assert.Equal(int64(42), proxy.GetSentConfigSize(TypeLDS))
+
+	proxy.ForgetSentConfig(TypeLDS)
+	assert.Equal(int64(0), proxy.GetSentConfigSize(TypeLDS))
+}
@@ -1,6 +1,9 @@
 package eds
 
 import (
+	"bytes"
+	"sort"
+
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 
@@ -35,7 +38,7 @@ func newClusterLoadAssignment(serviceName service.MeshService, serviceEndpoints
 	}
 	weight := uint32(100 / lenIPs)
 
-	for _, meshEndpoint := range serviceEndpoints {
+	for _, meshEndpoint := range sortEndpoints(serviceEndpoints) {
 		log.Trace().Msgf("[EDS][ClusterLoadAssignment] Adding Endpoint: Cluster=%s, Services=%s, Endpoint=%+v, Weight=%d", serviceName.String(), serviceName.String(), meshEndpoint, weight)
 		lbEpt := xds_endpoint.LbEndpoint{
 			HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
@@ -52,3 +55,16 @@ func newClusterLoadAssignment(serviceName service.MeshService, serviceEndpoints
 	log.Debug().Msgf("[EDS] Constructed ClusterLoadAssignment: %+v", cla)
 	return cla
 }
+
+// sortEndpoints returns a copy of the given endpoints sorted by IP address and port, so that the cluster load assignment
+// does not depend on the order in which the endpoint providers list them
+func sortEndpoints(endpoints []endpoint.Endpoint) []endpoint.Endpoint {
+	sorted := append([]endpoint.Endpoint{}, endpoints...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if c := bytes.Compare(sorted[i].IP.To16(), sorted[j].IP.To16()); c != 0 {
+			return c < 0
+		}
+		return sorted[i].Port < sorted[j].Port
+	})
+	return sorted
+}
@@ -43,5 +43,21 @@ var _ = Describe("Testing Cluster Load Assignment", func() {
 			Expect(cla2.Endpoints[0].LbEndpoints[0].GetLoadBalancingWeight().Value).To(Equal(uint32(50)))
 			Expect(cla2.Endpoints[0].LbEndpoints[1].GetLoadBalancingWeight().Value).To(Equal(uint32(50)))
 		})
+
+		It("Returns the endpoints in the same order regardless of their input order", func() {
+			meshService := service.MeshService{Namespace: "osm", Name: "bookstore"}
+			endpoints := []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.2"), Port: 80},
+				{IP: net.ParseIP("10.0.0.10"), Port: 80},
+				{IP: net.ParseIP("10.0.0.2"), Port: 70},
+			}
+			reversed := []endpoint.Endpoint{endpoints[2], endpoints[1], endpoints[0]}
+
+			Expect(sortEndpoints(endpoints)).To(Equal([]endpoint.Endpoint{endpoints[2], endpoints[0], endpoints[1]}))
+			Expect(newClusterLoadAssignment(meshService, endpoints)).To(Equal(newClusterLoadAssignment(meshService, reversed)))
+
+			// The input is not modified
+			Expect(endpoints[0].Port).To(Equal(endpoint.Port(80)))
+		})
 	})
 })
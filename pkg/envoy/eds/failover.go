@@ -0,0 +1,64 @@
+package eds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// failoverPriority is the priority of the backup endpoints, lower than the priority 0 of the endpoints of the service
+	failoverPriority = 1
+
+	// transportSocketMatchFilter is the endpoint metadata filter matched by the transport socket matches of a cluster
+	transportSocketMatchFilter = "envoy.transport_socket_match"
+)
+
+// addFailoverEndpoints adds the backup endpoints of the given failover policy to the given cluster load assignment, at
+// a lower priority than the endpoints of the service. Envoy sends traffic to the backup endpoints when the percentage
+// of healthy endpoints of the service, multiplied by the overprovisioning factor, drops below 100%.
+func addFailoverEndpoints(cla *xds_endpoint.ClusterLoadAssignment, policy *trafficpolicy.FailoverPolicy) {
+	if policy == nil || len(policy.Endpoints) == 0 {
+		return
+	}
+
+	failoverEndpoints := &xds_endpoint.LocalityLbEndpoints{
+		Locality: &xds_core.Locality{
+			Zone: zone,
+		},
+		Priority: failoverPriority,
+	}
+	for _, backupEndpoint := range policy.Endpoints {
+		failoverEndpoints.LbEndpoints = append(failoverEndpoints.LbEndpoints, &xds_endpoint.LbEndpoint{
+			HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+				Endpoint: &xds_endpoint.Endpoint{
+					Address: envoy.GetAddress(backupEndpoint.IP.String(), uint32(backupEndpoint.Port)),
+				},
+			},
+			// The backup endpoints are outside of the mesh, and are connected to with the transport socket of the cluster
+			// matching this metadata
+			Metadata: &xds_core.Metadata{
+				FilterMetadata: map[string]*structpb.Struct{
+					transportSocketMatchFilter: {
+						Fields: map[string]*structpb.Value{
+							envoy.FailoverEndpointMetadataKey: {Kind: &structpb.Value_BoolValue{BoolValue: true}},
+						},
+					},
+				},
+			},
+		})
+	}
+	cla.Endpoints = append(cla.Endpoints, failoverEndpoints)
+
+	if policy.Threshold > 0 {
+		// The traffic starts failing over when the percentage of healthy endpoints drops below 100 divided by the
+		// overprovisioning factor
+		cla.Policy = &xds_endpoint.ClusterLoadAssignment_Policy{
+			OverprovisioningFactor: &wrappers.UInt32Value{Value: 100 * 100 / policy.Threshold},
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package eds
+
+import (
+	"net"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestAddFailoverEndpoints(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := service.MeshService{Namespace: "bookstore-ns", Name: "bookstore"}
+	serviceEndpoints := []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.1"), Port: 8080}}
+
+	// A service without backup endpoints
+	cla := newClusterLoadAssignment(svc, serviceEndpoints)
+	addFailoverEndpoints(cla, nil)
+	assert.Len(cla.Endpoints, 1)
+	assert.Nil(cla.Policy)
+
+	// A service with backup endpoints and the default threshold
+	cla = newClusterLoadAssignment(svc, serviceEndpoints)
+	addFailoverEndpoints(cla, &trafficpolicy.FailoverPolicy{
+		Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("203.0.113.10"), Port: 80}},
+	})
+	assert.Nil(cla.Validate())
+	assert.Len(cla.Endpoints, 2)
+	assert.Equal(uint32(0), cla.Endpoints[0].Priority)
+	assert.Equal(uint32(failoverPriority), cla.Endpoints[1].Priority)
+	assert.Len(cla.Endpoints[1].LbEndpoints, 1)
+	backupEndpoint := cla.Endpoints[1].LbEndpoints[0]
+	assert.Equal("203.0.113.10", backupEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetAddress())
+	assert.Equal(uint32(80), backupEndpoint.GetEndpoint().GetAddress().GetSocketAddress().GetPortValue())
+	assert.True(backupEndpoint.Metadata.FilterMetadata[transportSocketMatchFilter].Fields[envoy.FailoverEndpointMetadataKey].GetBoolValue())
+	assert.Nil(cla.Policy)
+
+	// A service with backup endpoints and a threshold of 50%
+	cla = newClusterLoadAssignment(svc, serviceEndpoints)
+	addFailoverEndpoints(cla, &trafficpolicy.FailoverPolicy{
+		Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("203.0.113.10"), Port: 80}},
+		Threshold: 50,
+	})
+	assert.Len(cla.Endpoints, 2)
+	assert.Equal(uint32(200), cla.Policy.OverprovisioningFactor.Value)
+}
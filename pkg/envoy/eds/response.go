@@ -1,6 +1,9 @@
 package eds
 
 import (
+	"sort"
+
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
@@ -27,9 +30,22 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		return nil, err
 	}
 
-	var protos []*any.Any
+	var loadAssignments []*xds_endpoint.ClusterLoadAssignment
 	for svc, endpoints := range allowedEndpoints {
+		// Honor the topology keys of the upstream service so that node-local traffic stays on the node
+		endpoints = meshCatalog.FilterEndpointsByTopology(proxy.GetCertificateCommonName(), svc, endpoints)
 		loadAssignment := newClusterLoadAssignment(svc, endpoints)
+		addFailoverEndpoints(loadAssignment, meshCatalog.GetFailoverPolicy(svc))
+		loadAssignments = append(loadAssignments, loadAssignment)
+	}
+
+	// The load assignments are built from a map, so they are sorted by cluster name for the response to be deterministic
+	sort.Slice(loadAssignments, func(i, j int) bool {
+		return loadAssignments[i].ClusterName < loadAssignments[j].ClusterName
+	})
+
+	var protos []*any.Any
+	for _, loadAssignment := range loadAssignments {
 		proto, err := ptypes.MarshalAny(loadAssignment)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error marshalling EDS payload for proxy with SerialNumber=%s on Pod with UID=%s", proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
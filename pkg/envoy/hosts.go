@@ -0,0 +1,60 @@
+package envoy
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/idna"
+)
+
+// hostProfile converts the hosts to their ASCII form as looked up by clients, mapping them to lower case and encoding
+// the internationalized domain names with punycode. Underscores are allowed as they are used in the names of some
+// services.
+var hostProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// NormalizeHost returns the given host, optionally suffixed by a port, in the canonical form the hosts of the requests
+// are matched against: lower case, without trailing dots, and with internationalized domain names encoded with
+// punycode, such as xn--bcher-kva.example for Bücher.example. The wildcard prefix of a wildcard domain, the any host
+// wildcard * and IPv6 literals in brackets are kept as is, apart from their case. An error is returned if the host is
+// not a valid domain name.
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" || host == "*" {
+		return host, nil
+	}
+
+	name, port := splitHostPort(host)
+	if strings.HasPrefix(name, "[") {
+		return strings.ToLower(name) + port, nil
+	}
+
+	prefix := ""
+	if IsWildcardHost(name) {
+		prefix = WildcardHostPrefix
+		name = strings.TrimPrefix(name, WildcardHostPrefix)
+	}
+	name = strings.TrimRight(name, ".")
+	if name == "" {
+		return "", errors.Errorf("Invalid host %q, the domain name is empty", host)
+	}
+
+	ascii, err := hostProfile.ToASCII(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "Invalid host %q", host)
+	}
+	return prefix + ascii + port, nil
+}
+
+// splitHostPort returns the name of the given host and its port suffix, including the colon, or an empty port if the
+// host does not end with a port
+func splitHostPort(host string) (string, string) {
+	sep := strings.LastIndex(host, ":")
+	if sep < 0 || strings.Contains(host[sep:], "]") {
+		return host, ""
+	}
+	port := host[sep+1:]
+	if port == "" || strings.Trim(port, "0123456789") != "" {
+		return host, ""
+	}
+	return host[:sep], host[sep:]
+}
@@ -0,0 +1,53 @@
+package envoy
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	testCases := []struct {
+		host          string
+		expectedHost  string
+		expectedError bool
+	}{
+		{host: "", expectedHost: ""},
+		{host: "*", expectedHost: "*"},
+		{host: "bookstore.bookstore.svc.cluster.local", expectedHost: "bookstore.bookstore.svc.cluster.local"},
+		{host: "bookstore.bookstore:8888", expectedHost: "bookstore.bookstore:8888"},
+		{host: " Bookstore.Example.COM ", expectedHost: "bookstore.example.com"},
+		{host: "bookstore.example.com.", expectedHost: "bookstore.example.com"},
+		{host: "Bookstore.Example.com.:8080", expectedHost: "bookstore.example.com:8080"},
+		{host: "*.Example.com.", expectedHost: "*.example.com"},
+		{host: "Bücher.example", expectedHost: "xn--bcher-kva.example"},
+		{host: "*.münchen.de:443", expectedHost: "*.xn--mnchen-3ya.de:443"},
+		{host: "xn--bcher-kva.example", expectedHost: "xn--bcher-kva.example"},
+		{host: "_grpc.example.com", expectedHost: "_grpc.example.com"},
+		{host: "[2001:DB8::1]:80", expectedHost: "[2001:db8::1]:80"},
+		{host: "[::1]", expectedHost: "[::1]"},
+		{host: ".", expectedError: true},
+		{host: "*.", expectedError: true},
+		{host: "-bookstore.example.com", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := NormalizeHost(tc.host)
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedHost, actual)
+		})
+	}
+
+	// Normalizing a normalized host returns the same host
+	for _, tc := range testCases {
+		if tc.expectedError {
+			continue
+		}
+		actual, err := NormalizeHost(tc.expectedHost)
+		tassert.Nil(t, err)
+		tassert.Equal(t, tc.expectedHost, actual)
+	}
+}
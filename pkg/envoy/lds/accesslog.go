@@ -0,0 +1,253 @@
+package lds
+
+import (
+	"net/http"
+
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+const (
+	// Access log fields identifying the mesh identities and policy associated with a request or connection
+	accessLogSourceIdentityField      = "source_identity"
+	accessLogDestinationIdentityField = "destination_identity"
+	accessLogRouteNameField           = "route_name"
+	accessLogRBACPolicyField          = "rbac_policy"
+	accessLogRBACResultField          = "rbac_result"
+	accessLogRequestedServerNameField = "requested_server_name"
+
+	// downstreamPeerSubjectOperator formats the subject of the certificate presented by the downstream, which holds the
+	// identity of the downstream proxy in the mesh as its common name
+	downstreamPeerSubjectOperator = "%DOWNSTREAM_PEER_SUBJECT%"
+
+	// requestedServerNameOperator formats the server name requested by the downstream in the TLS handshake
+	requestedServerNameOperator = "%REQUESTED_SERVER_NAME%"
+
+	// routeNameOperator formats the name of the route matched by the request
+	routeNameOperator = "%ROUTE_NAME%"
+
+	// networkRBACPolicyOperator and networkRBACResultOperator format the name of the RBAC policy that matched the
+	// connection and the result of the RBAC engine, as recorded in the dynamic metadata by the shadow rules of the network
+	// RBAC filter. The shadow rules mirror the enforced rules, so their result is the one applied to the connection.
+	networkRBACPolicyOperator = "%DYNAMIC_METADATA(" + wellknown.RoleBasedAccessControl + ":shadow_effective_policy_id)%"
+	networkRBACResultOperator = "%DYNAMIC_METADATA(" + wellknown.RoleBasedAccessControl + ":shadow_engine_result)%"
+
+	// rbacDenialStatusRuntimeKey is the runtime key of the status code of the requests reported to the controller
+	rbacDenialStatusRuntimeKey = "osm.rbac_denial_status"
+
+	// rbacShadowEngineResultKey is the key of the result of the shadow rules of the HTTP and network RBAC filters in the
+	// dynamic metadata of the request or connection, and rbacShadowDeniedResult is the result of the requests and
+	// connections the shadow rules would deny
+	rbacShadowEngineResultKey = "shadow_engine_result"
+	rbacShadowDeniedResult    = "denied"
+
+	// tcpGRPCAccessLogName is the name of the gRPC access logger of TCP connections
+	tcpGRPCAccessLogName = "envoy.access_loggers.tcp_grpc"
+)
+
+// getProxyIdentity returns the identity of the proxy in the mesh, as presented in its certificate
+func (lb *listenerBuilder) getProxyIdentity() string {
+	return identity.GetKubernetesServiceIdentity(lb.svcAccount, identity.ClusterLocalTrustDomain).String()
+}
+
+// getInboundHTTPAccessLog returns the access log of the inbound HTTP traffic from downstream proxies in the mesh
+func (lb *listenerBuilder) getInboundHTTPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogSourceIdentityField:      downstreamPeerSubjectOperator,
+		accessLogDestinationIdentityField: lb.getProxyIdentity(),
+		accessLogRouteNameField:           routeNameOperator,
+	})
+}
+
+// getIngressHTTPAccessLog returns the access log of the inbound HTTP traffic from ingress, whose source has no mesh identity
+func (lb *listenerBuilder) getIngressHTTPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogDestinationIdentityField: lb.getProxyIdentity(),
+		accessLogRouteNameField:           routeNameOperator,
+	})
+}
+
+// getOutboundHTTPAccessLog returns the access log of the outbound HTTP traffic. The destination of a request is
+// identified by the matched route, which is named after the upstream service.
+func (lb *listenerBuilder) getOutboundHTTPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogSourceIdentityField: lb.getProxyIdentity(),
+		accessLogRouteNameField:      routeNameOperator,
+	})
+}
+
+// getInboundTCPAccessLog returns the access log of the inbound TCP traffic from downstream proxies in the mesh,
+// including the RBAC policy, named after the SMI TrafficTarget, that allowed or denied the connection.
+func (lb *listenerBuilder) getInboundTCPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogSourceIdentityField:      downstreamPeerSubjectOperator,
+		accessLogDestinationIdentityField: lb.getProxyIdentity(),
+		accessLogRBACPolicyField:          networkRBACPolicyOperator,
+		accessLogRBACResultField:          networkRBACResultOperator,
+	})
+}
+
+// getIngressTCPAccessLog returns the access log of the inbound TCP traffic from ingress, whose source has no mesh identity
+func (lb *listenerBuilder) getIngressTCPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogDestinationIdentityField: lb.getProxyIdentity(),
+	})
+}
+
+// getEgressGatewayTCPAccessLog returns the access log of the egress traffic forwarded by the egress gateway, including
+// the identity of the sidecar proxy that sent it and the external host it requested
+func getEgressGatewayTCPAccessLog() []*xds_accesslog_filter.AccessLog {
+	return envoy.GetAccessLogWithFields(map[string]string{
+		accessLogSourceIdentityField:      downstreamPeerSubjectOperator,
+		accessLogRequestedServerNameField: requestedServerNameOperator,
+	})
+}
+
+// getRBACDenialAccessLog returns the access log reporting the inbound HTTP requests denied with a 403 status to the
+// Access Log Service of the controller, which records the requests denied by RBAC policies.
+func getRBACDenialAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerAccessLog(envoy.RBACDenialAccessLogName, &xds_accesslog_filter.AccessLogFilter{
+		FilterSpecifier: &xds_accesslog_filter.AccessLogFilter_StatusCodeFilter{
+			StatusCodeFilter: &xds_accesslog_filter.StatusCodeFilter{
+				Comparison: &xds_accesslog_filter.ComparisonFilter{
+					Op: xds_accesslog_filter.ComparisonFilter_EQ,
+					Value: &xds_core.RuntimeUInt32{
+						DefaultValue: http.StatusForbidden,
+						RuntimeKey:   rbacDenialStatusRuntimeKey,
+					},
+				},
+			},
+		},
+	})
+}
+
+// getRBACShadowDenialAccessLog returns the access log reporting the inbound HTTP requests that would be denied by the
+// RBAC policies of SMI TrafficTargets in shadow mode to the Access Log Service of the controller, as recorded in the
+// dynamic metadata of the requests by the shadow rules of the HTTP RBAC filter.
+func getRBACShadowDenialAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerAccessLog(envoy.RBACShadowDenialAccessLogName, getRBACShadowDeniedFilter(wellknown.HTTPRoleBasedAccessControl))
+}
+
+// getRBACShadowDeniedFilter returns the access log filter matching the requests or connections the shadow rules of the
+// RBAC filter with the given name would deny, as recorded in their dynamic metadata
+func getRBACShadowDeniedFilter(rbacFilterName string) *xds_accesslog_filter.AccessLogFilter {
+	return &xds_accesslog_filter.AccessLogFilter{
+		FilterSpecifier: &xds_accesslog_filter.AccessLogFilter_MetadataFilter{
+			MetadataFilter: &xds_accesslog_filter.MetadataFilter{
+				Matcher: &xds_matcher.MetadataMatcher{
+					Filter: rbacFilterName,
+					Path: []*xds_matcher.MetadataMatcher_PathSegment{
+						{
+							Segment: &xds_matcher.MetadataMatcher_PathSegment_Key{Key: rbacShadowEngineResultKey},
+						},
+					},
+					Value: &xds_matcher.ValueMatcher{
+						MatchPattern: &xds_matcher.ValueMatcher_StringMatch{
+							StringMatch: &xds_matcher.StringMatcher{
+								MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: rbacShadowDeniedResult},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getPolicyRecorderAccessLog returns the access log reporting all the inbound HTTP requests to the Access Log Service
+// of the controller, which records the requests observed in permissive traffic policy mode as SMI policies.
+func getPolicyRecorderAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerAccessLog(envoy.PolicyRecorderAccessLogName, nil)
+}
+
+// getEgressMetricsHTTPAccessLog returns the access log reporting the egress HTTP requests to the Access Log Service of
+// the controller, which aggregates them in metrics per external host.
+func getEgressMetricsHTTPAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerAccessLog(envoy.EgressMetricsAccessLogName, nil)
+}
+
+// getEgressMetricsTCPAccessLog returns the access log reporting the egress TCP connections to the Access Log Service of
+// the controller, which aggregates them in metrics per external host.
+func getEgressMetricsTCPAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerTCPAccessLog(envoy.EgressMetricsAccessLogName, nil)
+}
+
+// getEgressAuditHTTPAccessLog returns the access log reporting the egress HTTP requests not allowed by Egress policies
+// in audit mode to the Access Log Service of the controller, as recorded in the dynamic metadata of the requests by the
+// shadow rules of the HTTP RBAC filter.
+func getEgressAuditHTTPAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerAccessLog(envoy.EgressAuditAccessLogName, getRBACShadowDeniedFilter(wellknown.HTTPRoleBasedAccessControl))
+}
+
+// getEgressAuditTCPAccessLog returns the access log reporting the egress TCP connections not allowed by Egress policies
+// in audit mode to the Access Log Service of the controller, as recorded in the dynamic metadata of the connections by
+// the shadow rules of the network RBAC filter.
+func getEgressAuditTCPAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	return getControllerTCPAccessLog(envoy.EgressAuditAccessLogName, getRBACShadowDeniedFilter(wellknown.RoleBasedAccessControl))
+}
+
+// getControllerAccessLog returns an access log streaming the requests matching the given filter, or all the requests if
+// the filter is nil, to the Access Log Service of the controller under the given log name.
+func getControllerAccessLog(logName string, filter *xds_accesslog_filter.AccessLogFilter) (*xds_accesslog_filter.AccessLog, error) {
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{
+		CommonConfig: getControllerGRPCAccessLogConfig(logName),
+	}
+	marshalledGRPCAccessLog, err := ptypes.MarshalAny(grpcAccessLog)
+	if err != nil {
+		log.Error().Err(err).Msg("Error marshalling HttpGrpcAccessLogConfig object")
+		return nil, err
+	}
+
+	return &xds_accesslog_filter.AccessLog{
+		Name:   wellknown.HTTPGRPCAccessLog,
+		Filter: filter,
+		ConfigType: &xds_accesslog_filter.AccessLog_TypedConfig{
+			TypedConfig: marshalledGRPCAccessLog,
+		},
+	}, nil
+}
+
+// getControllerTCPAccessLog returns an access log streaming the TCP connections matching the given filter, or all the
+// connections if the filter is nil, to the Access Log Service of the controller under the given log name.
+func getControllerTCPAccessLog(logName string, filter *xds_accesslog_filter.AccessLogFilter) (*xds_accesslog_filter.AccessLog, error) {
+	grpcAccessLog := &xds_accesslog_grpc.TcpGrpcAccessLogConfig{
+		CommonConfig: getControllerGRPCAccessLogConfig(logName),
+	}
+	marshalledGRPCAccessLog, err := ptypes.MarshalAny(grpcAccessLog)
+	if err != nil {
+		log.Error().Err(err).Msg("Error marshalling TcpGrpcAccessLogConfig object")
+		return nil, err
+	}
+
+	return &xds_accesslog_filter.AccessLog{
+		Name:   tcpGRPCAccessLogName,
+		Filter: filter,
+		ConfigType: &xds_accesslog_filter.AccessLog_TypedConfig{
+			TypedConfig: marshalledGRPCAccessLog,
+		},
+	}, nil
+}
+
+// getControllerGRPCAccessLogConfig returns the config of a gRPC access log streamed to the Access Log Service of the
+// controller under the given log name
+func getControllerGRPCAccessLogConfig(logName string) *xds_accesslog_grpc.CommonGrpcAccessLogConfig {
+	return &xds_accesslog_grpc.CommonGrpcAccessLogConfig{
+		LogName: logName,
+		GrpcService: &xds_core.GrpcService{
+			TargetSpecifier: &xds_core.GrpcService_EnvoyGrpc_{
+				EnvoyGrpc: &xds_core.GrpcService_EnvoyGrpc{
+					ClusterName: constants.OSMControllerName,
+				},
+			},
+		},
+		TransportApiVersion: xds_core.ApiVersion_V3,
+	}
+}
@@ -0,0 +1,166 @@
+package lds
+
+import (
+	"testing"
+
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+// getAccessLogFields returns the fields of the JSON formatted file access log
+func getAccessLogFields(t *testing.T, accessLog []*xds_accesslog_filter.AccessLog) map[string]string {
+	assert := tassert.New(t)
+	assert.Len(accessLog, 1)
+
+	fileAccessLog := &xds_accesslog.FileAccessLog{}
+	err := ptypes.UnmarshalAny(accessLog[0].GetTypedConfig(), fileAccessLog)
+	assert.Nil(err)
+
+	fields := make(map[string]string)
+	for name, value := range fileAccessLog.GetLogFormat().GetJsonFormat().GetFields() {
+		fields[name] = value.GetStringValue()
+	}
+	return fields
+}
+
+func TestAccessLogIdentityFields(t *testing.T) {
+	lb := &listenerBuilder{svcAccount: tests.BookstoreServiceAccount}
+	proxyIdentity := "bookstore.default.cluster.local"
+
+	testCases := []struct {
+		name           string
+		accessLog      []*xds_accesslog_filter.AccessLog
+		expectedFields map[string]string
+		absentFields   []string
+	}{
+		{
+			name:      "inbound HTTP",
+			accessLog: lb.getInboundHTTPAccessLog(),
+			expectedFields: map[string]string{
+				accessLogSourceIdentityField:      downstreamPeerSubjectOperator,
+				accessLogDestinationIdentityField: proxyIdentity,
+				accessLogRouteNameField:           routeNameOperator,
+			},
+			absentFields: []string{accessLogRBACPolicyField},
+		},
+		{
+			name:      "ingress HTTP",
+			accessLog: lb.getIngressHTTPAccessLog(),
+			expectedFields: map[string]string{
+				accessLogDestinationIdentityField: proxyIdentity,
+				accessLogRouteNameField:           routeNameOperator,
+			},
+			absentFields: []string{accessLogSourceIdentityField},
+		},
+		{
+			name:      "outbound HTTP",
+			accessLog: lb.getOutboundHTTPAccessLog(),
+			expectedFields: map[string]string{
+				accessLogSourceIdentityField: proxyIdentity,
+				accessLogRouteNameField:      routeNameOperator,
+			},
+			absentFields: []string{accessLogDestinationIdentityField},
+		},
+		{
+			name:      "inbound TCP",
+			accessLog: lb.getInboundTCPAccessLog(),
+			expectedFields: map[string]string{
+				accessLogSourceIdentityField:      downstreamPeerSubjectOperator,
+				accessLogDestinationIdentityField: proxyIdentity,
+				accessLogRBACPolicyField:          "%DYNAMIC_METADATA(envoy.filters.network.rbac:shadow_effective_policy_id)%",
+				accessLogRBACResultField:          "%DYNAMIC_METADATA(envoy.filters.network.rbac:shadow_engine_result)%",
+			},
+			absentFields: []string{accessLogRouteNameField},
+		},
+		{
+			name:      "ingress TCP",
+			accessLog: lb.getIngressTCPAccessLog(),
+			expectedFields: map[string]string{
+				accessLogDestinationIdentityField: proxyIdentity,
+			},
+			absentFields: []string{accessLogSourceIdentityField, accessLogRouteNameField},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			fields := getAccessLogFields(t, tc.accessLog)
+			for name, value := range tc.expectedFields {
+				assert.Equal(value, fields[name], name)
+			}
+			for _, name := range tc.absentFields {
+				assert.NotContains(fields, name)
+			}
+		})
+	}
+}
+
+func TestGetRBACDenialAccessLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	accessLog, err := getRBACDenialAccessLog()
+	assert.Nil(err)
+	assert.Equal(wellknown.HTTPGRPCAccessLog, accessLog.Name)
+
+	// Only the requests denied with a 403 status are reported
+	comparison := accessLog.GetFilter().GetStatusCodeFilter().GetComparison()
+	assert.Equal(xds_accesslog_filter.ComparisonFilter_EQ, comparison.GetOp())
+	assert.Equal(uint32(403), comparison.GetValue().GetDefaultValue())
+
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	err = ptypes.UnmarshalAny(accessLog.GetTypedConfig(), grpcAccessLog)
+	assert.Nil(err)
+	assert.Equal(envoy.RBACDenialAccessLogName, grpcAccessLog.GetCommonConfig().GetLogName())
+	assert.Equal(constants.OSMControllerName, grpcAccessLog.GetCommonConfig().GetGrpcService().GetEnvoyGrpc().GetClusterName())
+	assert.Equal(xds_core.ApiVersion_V3, grpcAccessLog.GetCommonConfig().GetTransportApiVersion())
+}
+
+func TestGetRBACShadowDenialAccessLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	accessLog, err := getRBACShadowDenialAccessLog()
+	assert.Nil(err)
+	assert.Equal(wellknown.HTTPGRPCAccessLog, accessLog.Name)
+
+	// Only the requests the shadow rules of the HTTP RBAC filter would deny are reported
+	matcher := accessLog.GetFilter().GetMetadataFilter().GetMatcher()
+	assert.Equal(wellknown.HTTPRoleBasedAccessControl, matcher.GetFilter())
+	assert.Len(matcher.GetPath(), 1)
+	assert.Equal("shadow_engine_result", matcher.GetPath()[0].GetKey())
+	assert.Equal("denied", matcher.GetValue().GetStringMatch().GetExact())
+
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	err = ptypes.UnmarshalAny(accessLog.GetTypedConfig(), grpcAccessLog)
+	assert.Nil(err)
+	assert.Equal(envoy.RBACShadowDenialAccessLogName, grpcAccessLog.GetCommonConfig().GetLogName())
+	assert.Equal(constants.OSMControllerName, grpcAccessLog.GetCommonConfig().GetGrpcService().GetEnvoyGrpc().GetClusterName())
+}
+
+func TestGetPolicyRecorderAccessLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	accessLog, err := getPolicyRecorderAccessLog()
+	assert.Nil(err)
+	assert.Equal(wellknown.HTTPGRPCAccessLog, accessLog.Name)
+
+	// All the requests are reported
+	assert.Nil(accessLog.GetFilter())
+
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	err = ptypes.UnmarshalAny(accessLog.GetTypedConfig(), grpcAccessLog)
+	assert.Nil(err)
+	assert.Equal(envoy.PolicyRecorderAccessLogName, grpcAccessLog.GetCommonConfig().GetLogName())
+	assert.Equal(constants.OSMControllerName, grpcAccessLog.GetCommonConfig().GetGrpcService().GetEnvoyGrpc().GetClusterName())
+	assert.Equal(xds_core.ApiVersion_V3, grpcAccessLog.GetCommonConfig().GetTransportApiVersion())
+}
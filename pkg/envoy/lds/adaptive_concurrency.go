@@ -0,0 +1,58 @@
+package lds
+
+import (
+	"time"
+
+	xds_adaptive_concurrency "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// adaptiveConcurrencyFilterName is the name of Envoy's adaptive concurrency HTTP filter
+	adaptiveConcurrencyFilterName = "envoy.filters.http.adaptive_concurrency"
+
+	// adaptiveConcurrencyUpdateInterval is the interval at which the concurrency limit is recalculated from the sampled
+	// latencies of the responses
+	adaptiveConcurrencyUpdateInterval = 100 * time.Millisecond
+
+	// adaptiveConcurrencyMinRTTInterval is the interval at which the latency of the service without load is measured
+	adaptiveConcurrencyMinRTTInterval = 60 * time.Second
+)
+
+// getAdaptiveConcurrencyFilter returns the HTTP filter shedding the load of a service with the given adaptive
+// concurrency limits. Requests exceeding the concurrency limit, which decreases as the latency of the responses of the
+// service grows, are rejected with a 503 status by the sidecar without reaching the service.
+func getAdaptiveConcurrencyFilter(policy trafficpolicy.AdaptiveConcurrencyPolicy) (*xds_hcm.HttpFilter, error) {
+	adaptiveConcurrency := &xds_adaptive_concurrency.AdaptiveConcurrency{
+		ConcurrencyControllerConfig: &xds_adaptive_concurrency.AdaptiveConcurrency_GradientControllerConfig{
+			GradientControllerConfig: &xds_adaptive_concurrency.GradientControllerConfig{
+				ConcurrencyLimitParams: &xds_adaptive_concurrency.GradientControllerConfig_ConcurrencyLimitCalculationParams{
+					MaxConcurrencyLimit:       &wrappers.UInt32Value{Value: policy.MaxConcurrencyLimit},
+					ConcurrencyUpdateInterval: ptypes.DurationProto(adaptiveConcurrencyUpdateInterval),
+				},
+				MinRttCalcParams: &xds_adaptive_concurrency.GradientControllerConfig_MinimumRTTCalculationParams{
+					Interval: ptypes.DurationProto(adaptiveConcurrencyMinRTTInterval),
+				},
+				SampleAggregatePercentile: &xds_type.Percent{Value: 50},
+			},
+		},
+	}
+
+	adaptiveConcurrencyAny, err := ptypes.MarshalAny(adaptiveConcurrency)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling adaptive concurrency filter config")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: adaptiveConcurrencyFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: adaptiveConcurrencyAny,
+		},
+	}, nil
+}
@@ -0,0 +1,75 @@
+package lds
+
+import (
+	"testing"
+	"time"
+
+	xds_adaptive_concurrency "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetAdaptiveConcurrencyFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	filter, err := getAdaptiveConcurrencyFilter(trafficpolicy.AdaptiveConcurrencyPolicy{Enabled: true, MaxConcurrencyLimit: 200})
+	assert.Nil(err)
+	assert.Equal(adaptiveConcurrencyFilterName, filter.Name)
+
+	adaptiveConcurrency := &xds_adaptive_concurrency.AdaptiveConcurrency{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), adaptiveConcurrency))
+	assert.Nil(adaptiveConcurrency.Validate())
+
+	limitParams := adaptiveConcurrency.GetGradientControllerConfig().GetConcurrencyLimitParams()
+	assert.Equal(uint32(200), limitParams.GetMaxConcurrencyLimit().GetValue())
+	assert.Equal(ptypes.DurationProto(adaptiveConcurrencyUpdateInterval), limitParams.GetConcurrencyUpdateInterval())
+}
+
+func TestGetInboundHTTPFiltersWithAdaptiveConcurrency(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyService := tests.BookstoreV1Service
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetBandwidthLimitForService(proxyService).Return(trafficpolicy.BandwidthLimit{EgressKiBps: 1024}).Times(1)
+	mockCatalog.EXPECT().GetAdaptiveConcurrencyPolicy(proxyService).Return(trafficpolicy.AdaptiveConcurrencyPolicy{Enabled: true, MaxConcurrencyLimit: 1000}).Times(1)
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		cfg:         mockConfigurator,
+	}
+
+	filters, err := lb.getInboundHTTPFilters(proxyService)
+	assert.Nil(err)
+	assert.Len(filters, 1)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filters[0].GetTypedConfig(), connManager))
+
+	var filterNames []string
+	for _, filter := range connManager.HttpFilters {
+		filterNames = append(filterNames, filter.Name)
+	}
+	assert.Equal([]string{wellknown.HTTPRoleBasedAccessControl, wellknown.Fault, adaptiveConcurrencyFilterName, wellknown.Router}, filterNames)
+}
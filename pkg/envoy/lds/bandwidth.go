@@ -0,0 +1,48 @@
+package lds
+
+import (
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/envoy/route"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// getIngressBandwidthLimit returns the ingress bandwidth limit, in KiB/s, of the proxy fronting the given services. A pod
+// selected by services with different limits gets the lowest one. A limit of 0 does not limit the rate.
+func (lb *listenerBuilder) getIngressBandwidthLimit(svcList []service.MeshService) uint64 {
+	var limit uint64
+	for _, svc := range svcList {
+		svcLimit := lb.meshCatalog.GetBandwidthLimitForService(svc).IngressKiBps
+		if svcLimit > 0 && (limit == 0 || svcLimit < limit) {
+			limit = svcLimit
+		}
+	}
+	return limit
+}
+
+// getBandwidthLimitFilter returns the HTTP filter limiting to the given rate, in KiB/s, the transfer of the body of each
+// HTTP response. The limit is overridden by the bandwidth limits of the routes, and a limit of 0 only enables the limits
+// of the routes.
+func getBandwidthLimitFilter(limitKiBps uint64) (*xds_hcm.HttpFilter, error) {
+	faultAny, err := ptypes.MarshalAny(route.BuildBandwidthLimitFault(limitKiBps))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling bandwidth limit fault filter config")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.Fault,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: faultAny,
+		},
+	}, nil
+}
+
+// addHTTPFilterBeforeRouter adds the given HTTP filter to the given HTTP connection manager, right before the Router
+// filter which must be the last filter
+func addHTTPFilterBeforeRouter(connManager *xds_hcm.HttpConnectionManager, filter *xds_hcm.HttpFilter) {
+	routerIdx := len(connManager.HttpFilters) - 1
+	connManager.HttpFilters = append(connManager.HttpFilters[:routerIdx:routerIdx], filter, connManager.HttpFilters[routerIdx])
+}
@@ -0,0 +1,88 @@
+package lds
+
+import (
+	"testing"
+
+	xds_fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetIngressBandwidthLimit(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	lb := &listenerBuilder{meshCatalog: mockCatalog}
+
+	mockCatalog.EXPECT().GetBandwidthLimitForService(tests.BookstoreV1Service).Return(trafficpolicy.BandwidthLimit{IngressKiBps: 2048}).AnyTimes()
+	mockCatalog.EXPECT().GetBandwidthLimitForService(tests.BookstoreV2Service).Return(trafficpolicy.BandwidthLimit{EgressKiBps: 512}).AnyTimes()
+	mockCatalog.EXPECT().GetBandwidthLimitForService(tests.BookstoreApexService).Return(trafficpolicy.BandwidthLimit{IngressKiBps: 1024}).AnyTimes()
+
+	assert.Equal(uint64(0), lb.getIngressBandwidthLimit(nil))
+	assert.Equal(uint64(0), lb.getIngressBandwidthLimit([]service.MeshService{tests.BookstoreV2Service}))
+	assert.Equal(uint64(2048), lb.getIngressBandwidthLimit([]service.MeshService{tests.BookstoreV1Service, tests.BookstoreV2Service}))
+	// The lowest limit applies to a pod selected by multiple services
+	assert.Equal(uint64(1024), lb.getIngressBandwidthLimit([]service.MeshService{tests.BookstoreV1Service, tests.BookstoreApexService}))
+}
+
+func TestGetBandwidthLimitFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	filter, err := getBandwidthLimitFilter(1024)
+	assert.Nil(err)
+	assert.Equal(wellknown.Fault, filter.Name)
+
+	fault := &xds_fault.HTTPFault{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), fault))
+	assert.Equal(uint64(1024), fault.ResponseRateLimit.GetFixedLimit().GetLimitKbps())
+	assert.Equal(uint32(100), fault.ResponseRateLimit.GetPercentage().GetNumerator())
+	// Only the rate of the responses is limited, no fault is injected
+	assert.Nil(fault.Delay)
+	assert.Nil(fault.Abort)
+
+	// Without a limit for the service, the filter only applies the limits of the routes
+	filter, err = getBandwidthLimitFilter(0)
+	assert.Nil(err)
+	assert.Equal(wellknown.Fault, filter.Name)
+
+	fault = &xds_fault.HTTPFault{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), fault))
+	assert.Nil(fault.ResponseRateLimit)
+}
+
+func TestGetOutboundHTTPFilterWithBandwidthLimit(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+
+	lb := &listenerBuilder{
+		cfg:                        mockConfigurator,
+		ingressBandwidthLimitKiBps: 1024,
+	}
+
+	filter, err := lb.getOutboundHTTPFilter()
+	assert.Nil(err)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), connManager))
+
+	var filterNames []string
+	for _, httpFilter := range connManager.HttpFilters {
+		filterNames = append(filterNames, httpFilter.Name)
+	}
+	assert.Equal([]string{wellknown.HTTPRoleBasedAccessControl, wellknown.Fault, wellknown.Router}, filterNames)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/featureflags"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 const (
@@ -115,6 +116,54 @@ func getHTTPConnectionManager(routeName string, cfg configurator.Configurator, h
 	return connManager
 }
 
+// setForwardClientCertDetails configures the given inbound connection manager to forward the given fields of the verified
+// client certificate to the application in the x-forwarded-client-cert header. Any value of the header set by the client
+// is removed, so that the application can trust the header to describe the immediate downstream proxy.
+func setForwardClientCertDetails(connManager *xds_hcm.HttpConnectionManager, fields []string) {
+	if len(fields) == 0 {
+		connManager.ForwardClientCertDetails = xds_hcm.HttpConnectionManager_SANITIZE
+		return
+	}
+
+	details := &xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails{}
+	for _, field := range fields {
+		switch field {
+		case constants.ClientCertFieldSubject:
+			details.Subject = &wrappers.BoolValue{Value: true}
+		case constants.ClientCertFieldURI:
+			details.Uri = true
+		case constants.ClientCertFieldDNS:
+			details.Dns = true
+		case constants.ClientCertFieldCert:
+			details.Cert = true
+		case constants.ClientCertFieldChain:
+			details.Chain = true
+		}
+	}
+
+	connManager.ForwardClientCertDetails = xds_hcm.HttpConnectionManager_SANITIZE_SET
+	connManager.SetCurrentClientCertDetails = details
+}
+
+// getMeshForwardedHeaderPolicy returns the mesh-wide forwarded header policy configured in the OSM ConfigMap
+func getMeshForwardedHeaderPolicy(cfg configurator.Configurator) trafficpolicy.ForwardedHeaderPolicy {
+	return trafficpolicy.ForwardedHeaderPolicy{
+		XFFNumTrustedHops: cfg.GetXFFNumTrustedHops(),
+		UseRemoteAddress:  cfg.UseRemoteAddress(),
+		SkipXFFAppend:     cfg.SkipXFFAppend(),
+	}
+}
+
+// setForwardedHeaderPolicy configures how the given inbound connection manager determines the client address of the
+// requests it receives and handles their x-forwarded-for header
+func setForwardedHeaderPolicy(connManager *xds_hcm.HttpConnectionManager, policy trafficpolicy.ForwardedHeaderPolicy) {
+	connManager.XffNumTrustedHops = policy.XFFNumTrustedHops
+	connManager.SkipXffAppend = policy.SkipXFFAppend
+	if policy.UseRemoteAddress {
+		connManager.UseRemoteAddress = &wrappers.BoolValue{Value: true}
+	}
+}
+
 func getPrometheusConnectionManager() *xds_hcm.HttpConnectionManager {
 	return &xds_hcm.HttpConnectionManager{
 		StatPrefix: prometheusHTTPConnManagerStatPrefix,
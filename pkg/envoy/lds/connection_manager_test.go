@@ -0,0 +1,103 @@
+package lds
+
+import (
+	"testing"
+
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetForwardClientCertDetails(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fields          []string
+		expectedForward xds_hcm.HttpConnectionManager_ForwardClientCertDetails
+		expectedDetails *xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails
+	}{
+		{
+			name:            "no fields forwarded",
+			fields:          nil,
+			expectedForward: xds_hcm.HttpConnectionManager_SANITIZE,
+			expectedDetails: nil,
+		},
+		{
+			name:            "identity forwarded",
+			fields:          []string{constants.ClientCertFieldDNS},
+			expectedForward: xds_hcm.HttpConnectionManager_SANITIZE_SET,
+			expectedDetails: &xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails{
+				Dns: true,
+			},
+		},
+		{
+			name: "all fields forwarded",
+			fields: []string{constants.ClientCertFieldSubject, constants.ClientCertFieldURI, constants.ClientCertFieldDNS,
+				constants.ClientCertFieldCert, constants.ClientCertFieldChain},
+			expectedForward: xds_hcm.HttpConnectionManager_SANITIZE_SET,
+			expectedDetails: &xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails{
+				Subject: &wrappers.BoolValue{Value: true},
+				Uri:     true,
+				Dns:     true,
+				Cert:    true,
+				Chain:   true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			connManager := &xds_hcm.HttpConnectionManager{}
+			setForwardClientCertDetails(connManager, tc.fields)
+
+			assert.Equal(tc.expectedForward, connManager.ForwardClientCertDetails)
+			assert.Equal(tc.expectedDetails, connManager.SetCurrentClientCertDetails)
+		})
+	}
+}
+
+func TestSetForwardedHeaderPolicy(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		policy                   trafficpolicy.ForwardedHeaderPolicy
+		expectedXFFNumTrustedHop uint32
+		expectedUseRemoteAddress *wrappers.BoolValue
+		expectedSkipXFFAppend    bool
+	}{
+		{
+			name:                     "default policy",
+			policy:                   trafficpolicy.ForwardedHeaderPolicy{},
+			expectedXFFNumTrustedHop: 0,
+			expectedUseRemoteAddress: nil,
+			expectedSkipXFFAppend:    false,
+		},
+		{
+			name: "client address from remote address behind trusted hops",
+			policy: trafficpolicy.ForwardedHeaderPolicy{
+				XFFNumTrustedHops: 2,
+				UseRemoteAddress:  true,
+				SkipXFFAppend:     true,
+			},
+			expectedXFFNumTrustedHop: 2,
+			expectedUseRemoteAddress: &wrappers.BoolValue{Value: true},
+			expectedSkipXFFAppend:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			connManager := &xds_hcm.HttpConnectionManager{}
+			setForwardedHeaderPolicy(connManager, tc.policy)
+
+			assert.Equal(tc.expectedXFFNumTrustedHop, connManager.XffNumTrustedHops)
+			assert.Equal(tc.expectedUseRemoteAddress, connManager.UseRemoteAddress)
+			assert.Equal(tc.expectedSkipXFFAppend, connManager.SkipXffAppend)
+		})
+	}
+}
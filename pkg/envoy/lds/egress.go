@@ -0,0 +1,257 @@
+package lds
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/route"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	outboundEgressHTTPFilterChainPrefix     = "outbound-egress-http-filter-chain"
+	outboundEgressHTTPSFilterChainPrefix    = "outbound-egress-https-filter-chain"
+	outboundEgressIPRangesFilterChainPrefix = "outbound-egress-ip-ranges-filter-chain"
+	outboundEgressTCPProxyStatPrefix        = "outbound-egress-tcp-proxy"
+	egressHTTPProxyListenerName             = "egress-http-proxy-listener"
+)
+
+// getEgressFilterChains returns the outbound filter chains of the external destinations allowed by the given Egress
+// policy. The filter chains match on the destination port only, so that the filter chains of the upstream services,
+// which also match on the IP addresses of their endpoints, take precedence over them.
+// 1. HTTP hosts are matched by the Host header in the route config of their port
+// 2. HTTPS hosts are matched by the SNI of the TLS connection to their port, wildcard domains by any subdomain
+// 3. IP ranges are matched by the destination IP address of connections to their port
+func (lb *listenerBuilder) getEgressFilterChains(egressPolicy *trafficpolicy.EgressPolicy) ([]*xds_listener.FilterChain, error) {
+	var filterChains []*xds_listener.FilterChain
+
+	// The connections to the HTTPS hosts and IP ranges are reported to the controller when egress metrics are enabled
+	var tcpAccessLogs []*xds_accesslog_filter.AccessLog
+	if lb.cfg.IsEgressMetricsEnabled() {
+		egressMetricsAccessLog, err := getEgressMetricsTCPAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		tcpAccessLogs = append(tcpAccessLogs, egressMetricsAccessLog)
+	}
+
+	for _, port := range sortedPorts(egressPolicy.HTTPHosts) {
+		filterChain, err := lb.getEgressHTTPFilterChain(port)
+		if err != nil {
+			return nil, err
+		}
+		filterChains = append(filterChains, filterChain)
+	}
+
+	for _, port := range sortedPorts(egressPolicy.HTTPSHosts) {
+		for _, host := range egressPolicy.HTTPSHosts[port] {
+			filterChain, err := getEgressHTTPSFilterChain(host, port, tcpAccessLogs)
+			if err != nil {
+				return nil, err
+			}
+			filterChains = append(filterChains, filterChain)
+		}
+	}
+
+	for _, port := range sortedPorts(egressPolicy.IPRanges) {
+		filterChain, err := getEgressIPRangesFilterChain(egressPolicy.IPRanges[port], port, tcpAccessLogs)
+		if err != nil {
+			return nil, err
+		}
+		filterChains = append(filterChains, filterChain)
+	}
+
+	return filterChains, nil
+}
+
+func (lb *listenerBuilder) getEgressHTTPFilterChain(port uint32) (*xds_listener.FilterChain, error) {
+	connManager := getHTTPConnectionManager(route.GetEgressRouteConfigName(port), lb.cfg, lb.statsHeaders)
+	accessLogs, err := lb.getEgressHTTPAccessLogs()
+	if err != nil {
+		return nil, err
+	}
+	// The requests for the hosts not allowed by the Egress policies are matched by the shadow rules of the route config
+	// of the port in egress audit mode
+	if lb.egressAudit {
+		egressAuditAccessLog, err := getEgressAuditHTTPAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		accessLogs = append(accessLogs, egressAuditAccessLog)
+	}
+	connManager.AccessLog = accessLogs
+
+	marshalledConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling HTTP connection manager object for egress port %d", port)
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s:%d", outboundEgressHTTPFilterChainPrefix, port),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			DestinationPort: &wrapperspb.UInt32Value{Value: port},
+		},
+		Filters: []*xds_listener.Filter{
+			{
+				Name:       wellknown.HTTPConnectionManager,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledConnManager},
+			},
+		},
+	}, nil
+}
+
+func getEgressHTTPSFilterChain(host string, port uint32, accessLogs []*xds_accesslog_filter.AccessLog) (*xds_listener.FilterChain, error) {
+	filter, err := getEgressTCPProxyFilter(envoy.GetEgressHostClusterName(host, port), accessLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s:%s:%d", outboundEgressHTTPSFilterChainPrefix, host, port),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			DestinationPort:   &wrapperspb.UInt32Value{Value: port},
+			ServerNames:       []string{host},
+			TransportProtocol: envoy.TransportProtocolTLS,
+		},
+		Filters: []*xds_listener.Filter{filter},
+	}, nil
+}
+
+func getEgressIPRangesFilterChain(ipRanges []string, port uint32, accessLogs []*xds_accesslog_filter.AccessLog) (*xds_listener.FilterChain, error) {
+	filter, err := getEgressTCPProxyFilter(envoy.GetEgressIPRangesClusterName(port), accessLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	filterChainMatch := &xds_listener.FilterChainMatch{
+		DestinationPort: &wrapperspb.UInt32Value{Value: port},
+	}
+	for _, ipRange := range ipRanges {
+		_, ipNet, err := net.ParseCIDR(ipRange)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error parsing egress IP range %s", ipRange)
+			return nil, err
+		}
+		prefixLen, _ := ipNet.Mask.Size()
+		filterChainMatch.PrefixRanges = append(filterChainMatch.PrefixRanges, &xds_core.CidrRange{
+			AddressPrefix: ipNet.IP.String(),
+			PrefixLen:     &wrapperspb.UInt32Value{Value: uint32(prefixLen)},
+		})
+	}
+
+	return &xds_listener.FilterChain{
+		Name:             fmt.Sprintf("%s:%d", outboundEgressIPRangesFilterChainPrefix, port),
+		FilterChainMatch: filterChainMatch,
+		Filters:          []*xds_listener.Filter{filter},
+	}, nil
+}
+
+func getEgressTCPProxyFilter(cluster string, accessLogs []*xds_accesslog_filter.AccessLog) (*xds_listener.Filter, error) {
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", outboundEgressTCPProxyStatPrefix, cluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: cluster},
+		AccessLog:        accessLogs,
+	}
+
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for egress cluster %s", cluster)
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.TCPProxy,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+	}, nil
+}
+
+// newEgressHTTPProxyListener returns the listener on which the proxy acts as an explicit HTTP proxy for the external
+// hosts allowed to be reached through it. The listener is bound to the localhost port the applications are configured
+// to use as their HTTP proxy, which is not redirected to the outbound listener, and its route config only allows the
+// plaintext HTTP requests and the HTTP CONNECT requests for the allowed hosts.
+func (lb *listenerBuilder) newEgressHTTPProxyListener() (*xds_listener.Listener, error) {
+	connManager := getHTTPConnectionManager(route.EgressHTTPProxyRouteConfigName, lb.cfg, lb.statsHeaders)
+	accessLogs, err := lb.getEgressHTTPAccessLogs()
+	if err != nil {
+		return nil, err
+	}
+	connManager.AccessLog = accessLogs
+	// CONNECT requests are rejected unless the CONNECT upgrade is enabled on the connection manager
+	connManager.UpgradeConfigs = []*xds_hcm.HttpConnectionManager_UpgradeConfig{
+		{UpgradeType: envoy.ConnectUpgradeType},
+	}
+
+	marshalledConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling HTTP connection manager object for the egress HTTP proxy")
+		return nil, err
+	}
+
+	return &xds_listener.Listener{
+		Name:             egressHTTPProxyListenerName,
+		Address:          envoy.GetAddress(envoy.GetLocalhostIPAddress(lb.ipv6), constants.EnvoyHTTPProxyListenerPort),
+		TrafficDirection: xds_core.TrafficDirection_OUTBOUND,
+		FilterChains: []*xds_listener.FilterChain{
+			{
+				Filters: []*xds_listener.Filter{
+					{
+						Name:       wellknown.HTTPConnectionManager,
+						ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledConnManager},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// getEgressHTTPAccessLogs returns the access logs of the egress HTTP traffic, which is reported to the controller when
+// egress metrics are enabled
+func (lb *listenerBuilder) getEgressHTTPAccessLogs() ([]*xds_accesslog_filter.AccessLog, error) {
+	accessLogs := lb.getOutboundHTTPAccessLog()
+	if lb.cfg.IsEgressMetricsEnabled() {
+		egressMetricsAccessLog, err := getEgressMetricsHTTPAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		accessLogs = append(accessLogs, egressMetricsAccessLog)
+	}
+	return accessLogs, nil
+}
+
+// getEgressTLSInspectorFilter returns the TLS inspector listener filter required to match the SNI of the HTTPS hosts of
+// the given Egress policy, or nil if there are none. The filter is only enabled on the ports of the HTTPS hosts so that
+// it does not delay server-first protocols on the other ports of the outbound listener.
+func getEgressTLSInspectorFilter(egressPolicy *trafficpolicy.EgressPolicy) *xds_listener.ListenerFilter {
+	ports := sortedPorts(egressPolicy.HTTPSHosts)
+	if len(ports) == 0 {
+		return nil
+	}
+
+	return &xds_listener.ListenerFilter{
+		Name: wellknown.TlsInspector,
+		// The filter is disabled for connections to any other port
+		FilterDisabled: getOtherDestinationPortsPredicate(ports),
+	}
+}
+
+func sortedPorts(portMap map[uint32][]string) []uint32 {
+	var ports []uint32
+	for port := range portMap {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
@@ -0,0 +1,78 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
+)
+
+const (
+	outboundEgressAuditFilterChainName = "outbound-egress-audit-filter-chain"
+	egressAuditStatPrefix              = "egress-audit"
+
+	// egressAuditRBACPolicyName is the name of the RBAC policy of the shadow rules that would deny the egress traffic not
+	// allowed by Egress policies in audit mode
+	egressAuditRBACPolicyName = "egress-audit"
+)
+
+// buildEgressAuditFilterChain returns the default filter chain of the outbound listener in egress audit mode, matching
+// the egress connections not allowed by the Egress policies of the proxy. The connections are forwarded to their
+// original destination, and reported to the controller as recorded by the shadow rules of a network RBAC filter that
+// would deny any of them.
+func (lb *listenerBuilder) buildEgressAuditFilterChain() (*xds_listener.FilterChain, error) {
+	networkRBAC := &xds_network_rbac.RBAC{
+		StatPrefix:  fmt.Sprintf("%s.", egressAuditStatPrefix), // will be displayed as egress-audit.rbac.<path>
+		ShadowRules: rbac.GetDenyAnyRules(egressAuditRBACPolicyName),
+	}
+	marshalledNetworkRBAC, err := ptypes.MarshalAny(networkRBAC)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling RBAC object for egress audit filter chain")
+		return nil, err
+	}
+
+	egressAuditAccessLog, err := getEgressAuditTCPAccessLog()
+	if err != nil {
+		return nil, err
+	}
+	accessLogs := []*xds_accesslog_filter.AccessLog{egressAuditAccessLog}
+	if lb.cfg.IsEgressMetricsEnabled() {
+		egressMetricsAccessLog, err := getEgressMetricsTCPAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		accessLogs = append(accessLogs, egressMetricsAccessLog)
+	}
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", egressAuditStatPrefix, envoy.OutboundPassthroughCluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: envoy.OutboundPassthroughCluster},
+		AccessLog:        accessLogs,
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for egress audit filter chain")
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: outboundEgressAuditFilterChainName,
+		Filters: []*xds_listener.Filter{
+			{
+				Name:       wellknown.RoleBasedAccessControl,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledNetworkRBAC},
+			},
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,88 @@
+package lds
+
+import (
+	"testing"
+
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildEgressAuditFilterChain(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsEgressMetricsEnabled().Return(false).AnyTimes()
+	lb := &listenerBuilder{
+		svcAccount:  tests.BookbuyerServiceAccount,
+		cfg:         mockConfigurator,
+		egressAudit: true,
+	}
+
+	filterChain, err := lb.buildEgressAuditFilterChain()
+	assert.Nil(err)
+	assert.Equal(outboundEgressAuditFilterChainName, filterChain.Name)
+	assert.Len(filterChain.Filters, 2)
+
+	// The connections are not denied, but the shadow rules of the RBAC filter would deny any of them
+	assert.Equal(wellknown.RoleBasedAccessControl, filterChain.Filters[0].Name)
+	networkRBAC := &xds_network_rbac.RBAC{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), networkRBAC))
+	assert.Nil(networkRBAC.Rules)
+	assert.Equal(xds_rbac.RBAC_DENY, networkRBAC.ShadowRules.Action)
+	assert.Contains(networkRBAC.ShadowRules.Policies, egressAuditRBACPolicyName)
+
+	// The connections are forwarded to their original destination and reported to the controller
+	assert.Equal(wellknown.TCPProxy, filterChain.Filters[1].Name)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[1].GetTypedConfig(), tcpProxy))
+	assert.Equal(envoy.OutboundPassthroughCluster, tcpProxy.GetCluster())
+	assert.Len(tcpProxy.AccessLog, 1)
+	assert.Equal(wellknown.RoleBasedAccessControl, tcpProxy.AccessLog[0].GetFilter().GetMetadataFilter().GetMatcher().GetFilter())
+	tcpGRPCAccessLog := &xds_accesslog_grpc.TcpGrpcAccessLogConfig{}
+	assert.Nil(ptypes.UnmarshalAny(tcpProxy.AccessLog[0].GetTypedConfig(), tcpGRPCAccessLog))
+	assert.Equal(envoy.EgressAuditAccessLogName, tcpGRPCAccessLog.CommonConfig.LogName)
+}
+
+func TestGetEgressFilterChainsWithEgressAudit(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressMetricsEnabled().Return(false).AnyTimes()
+	lb := &listenerBuilder{
+		svcAccount:  tests.BookbuyerServiceAccount,
+		cfg:         mockConfigurator,
+		egressAudit: true,
+	}
+
+	filterChains, err := lb.getEgressFilterChains(&trafficpolicy.EgressPolicy{
+		HTTPHosts: map[uint32][]string{80: {"httpbin.org"}},
+	})
+	assert.Nil(err)
+	assert.Len(filterChains, 1)
+
+	// The HTTP requests for the hosts not allowed by the policy are reported to the controller
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filterChains[0].Filters[0].GetTypedConfig(), connManager))
+	assert.Len(connManager.AccessLog, 2)
+	assert.Equal(wellknown.HTTPRoleBasedAccessControl, connManager.AccessLog[1].GetFilter().GetMetadataFilter().GetMatcher().GetFilter())
+	httpGRPCAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	assert.Nil(ptypes.UnmarshalAny(connManager.AccessLog[1].GetTypedConfig(), httpGRPCAccessLog))
+	assert.Equal(envoy.EgressAuditAccessLogName, httpGRPCAccessLog.CommonConfig.LogName)
+}
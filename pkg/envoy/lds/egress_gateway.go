@@ -0,0 +1,157 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	egressGatewayListenerName       = "egress-gateway-listener"
+	egressGatewayFilterChainPrefix  = "egress-gateway-filter-chain"
+	egressGatewayTCPProxyStatPrefix = "egress-gateway-tcp-proxy"
+)
+
+// NewEgressGatewayResponse creates a new Listener Discovery Response for the egress gateway.
+// The response builds a Listener terminating the mTLS connections of the sidecar proxies forwarding their egress traffic,
+// which is preceded by a PROXY protocol header carrying its original destination:
+// 1. Traffic to an external host allowed by the Egress policies is matched by its server name and port, and forwarded to the host
+// 2. Other traffic is forwarded to its original destination
+func NewEgressGatewayResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	lb := newListenerBuilder(meshCatalog, catalog.GetEgressGatewayServiceAccount(cfg.GetOSMNamespace()), cfg, nil, false)
+
+	listener, err := lb.newEgressGatewayListener(meshCatalog.GetEgressGatewayPolicy())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building listener for egress gateway with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	resp := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeLDS),
+	}
+	marshalledListener, err := ptypes.MarshalAny(listener)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling listener for egress gateway with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+	resp.Resources = append(resp.Resources, marshalledListener)
+
+	return resp, nil
+}
+
+// newEgressGatewayListener returns the listener of the egress gateway, with a filter chain per external host and port
+// allowed by the given Egress policy, which may be nil, and a filter chain for the traffic to other destinations
+func (lb *listenerBuilder) newEgressGatewayListener(egressPolicy *trafficpolicy.EgressPolicy) (*xds_listener.Listener, error) {
+	marshalledProxyProtocol, err := ptypes.MarshalAny(&xds_proxy_protocol.ProxyProtocol{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling ProxyProtocol listener filter")
+		return nil, err
+	}
+
+	listener := &xds_listener.Listener{
+		Name:             egressGatewayListenerName,
+		Address:          envoy.GetListenerAddress(constants.EgressGatewayPort, false),
+		TrafficDirection: xds_core.TrafficDirection_OUTBOUND,
+		ListenerFilters: []*xds_listener.ListenerFilter{
+			{
+				// The PROXY protocol header restores the original destination of the traffic, which the filter chains
+				// match on the port of, and precedes the TLS handshake
+				Name:       wellknown.ProxyProtocol,
+				ConfigType: &xds_listener.ListenerFilter_TypedConfig{TypedConfig: marshalledProxyProtocol},
+			},
+			{
+				Name: wellknown.TlsInspector,
+			},
+		},
+	}
+
+	if egressPolicy != nil {
+		// A host allowed on the same port by the http and https protocols shares its filter chain
+		hostFilterChains := make(map[string]bool)
+		for _, portHosts := range []map[uint32][]string{egressPolicy.HTTPHosts, egressPolicy.HTTPSHosts} {
+			for _, port := range sortedPorts(portHosts) {
+				for _, host := range portHosts[port] {
+					clusterName := envoy.GetEgressHostClusterName(host, port)
+					if envoy.IsWildcardHost(host) || hostFilterChains[clusterName] {
+						continue
+					}
+					hostFilterChains[clusterName] = true
+
+					filterChain, err := lb.getEgressGatewayFilterChain(clusterName, &xds_listener.FilterChainMatch{
+						DestinationPort: &wrapperspb.UInt32Value{Value: port},
+						ServerNames:     []string{host},
+					})
+					if err != nil {
+						return nil, err
+					}
+					listener.FilterChains = append(listener.FilterChains, filterChain)
+				}
+			}
+		}
+	}
+
+	// Traffic not matched by the filter chains of the external hosts is forwarded to its original destination
+	filterChain, err := lb.getEgressGatewayFilterChain(envoy.EgressGatewayOriginalDestinationCluster, &xds_listener.FilterChainMatch{})
+	if err != nil {
+		return nil, err
+	}
+	listener.FilterChains = append(listener.FilterChains, filterChain)
+
+	sortFilterChains(listener)
+	return listener, nil
+}
+
+// getEgressGatewayFilterChain returns a filter chain of the egress gateway terminating the mTLS connections matched by
+// the given filter chain match, and forwarding them to the given cluster
+func (lb *listenerBuilder) getEgressGatewayFilterChain(cluster string, filterChainMatch *xds_listener.FilterChainMatch) (*xds_listener.FilterChain, error) {
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", egressGatewayTCPProxyStatPrefix, cluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: cluster},
+		AccessLog:        getEgressGatewayTCPAccessLog(),
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for egress gateway cluster %s", cluster)
+		return nil, err
+	}
+
+	// Only the sidecar proxies of the mesh, which present a certificate issued by the mesh CA, may use the egress gateway
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.svcAccount, true /* mTLS */))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling DownstreamTLSContext for egress gateway cluster %s", cluster)
+		return nil, err
+	}
+
+	// In-mesh proxies will advertise this, set in the UpstreamTlsContext
+	filterChainMatch.TransportProtocol = envoy.TransportProtocolTLS
+	filterChainMatch.ApplicationProtocols = envoy.ALPNInMesh
+
+	return &xds_listener.FilterChain{
+		Name:             fmt.Sprintf("%s:%s", egressGatewayFilterChainPrefix, cluster),
+		FilterChainMatch: filterChainMatch,
+		Filters: []*xds_listener.Filter{{
+			Name:       wellknown.TCPProxy,
+			ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+		}},
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		},
+	}, nil
+}
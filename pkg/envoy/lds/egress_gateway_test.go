@@ -0,0 +1,84 @@
+package lds
+
+import (
+	"testing"
+
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestNewEgressGatewayResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	proxy := envoy.NewProxy("", "", nil)
+
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(&trafficpolicy.EgressPolicy{
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}, 443: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"*.github.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{5432: {"203.0.113.0/24"}},
+	})
+
+	actual, err := NewEgressGatewayResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	assert.Nil(err)
+	assert.Len(actual.Resources, 1)
+
+	listener := &xds_listener.Listener{}
+	assert.Nil(ptypes.UnmarshalAny(actual.Resources[0], listener))
+	assert.Equal(egressGatewayListenerName, listener.Name)
+	assert.Equal(uint32(15006), listener.Address.GetSocketAddress().GetPortValue())
+
+	// The PROXY protocol header precedes the TLS handshake
+	assert.Len(listener.ListenerFilters, 2)
+	assert.Equal(wellknown.ProxyProtocol, listener.ListenerFilters[0].Name)
+	assert.Equal(wellknown.TlsInspector, listener.ListenerFilters[1].Name)
+
+	// A filter chain per external host and port, and a filter chain for the traffic to other destinations
+	var names []string
+	for _, filterChain := range listener.FilterChains {
+		names = append(names, filterChain.Name)
+		assert.Equal(envoy.TransportProtocolTLS, filterChain.FilterChainMatch.TransportProtocol)
+		assert.Equal(envoy.ALPNInMesh, filterChain.FilterChainMatch.ApplicationProtocols)
+		assert.Equal(wellknown.TCPProxy, filterChain.Filters[0].Name)
+
+		// The egress gateway requires a certificate issued by the mesh CA
+		tlsContext := &xds_auth.DownstreamTlsContext{}
+		assert.Nil(ptypes.UnmarshalAny(filterChain.TransportSocket.GetTypedConfig(), tlsContext))
+		assert.True(tlsContext.RequireClientCertificate.Value)
+		assert.Equal("service-cert:osm-system/osm-egress-gateway", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+	}
+	assert.Equal([]string{
+		"egress-gateway-filter-chain:egress-gateway-original-destination",
+		"egress-gateway-filter-chain:egress:httpbin.org:443",
+		"egress-gateway-filter-chain:egress:httpbin.org:80",
+	}, names)
+
+	hostFilterChain := listener.FilterChains[1]
+	assert.Equal([]string{"httpbin.org"}, hostFilterChain.FilterChainMatch.ServerNames)
+	assert.Equal(uint32(443), hostFilterChain.FilterChainMatch.DestinationPort.GetValue())
+
+	originalDestinationFilterChain := listener.FilterChains[0]
+	assert.Empty(originalDestinationFilterChain.FilterChainMatch.ServerNames)
+	assert.Nil(originalDestinationFilterChain.FilterChainMatch.DestinationPort)
+
+	// Without Egress policies, only the traffic to other destinations is matched
+	mockCatalog.EXPECT().GetEgressGatewayPolicy().Return(nil)
+	actual, err = NewEgressGatewayResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	assert.Nil(err)
+	listener = &xds_listener.Listener{}
+	assert.Nil(ptypes.UnmarshalAny(actual.Resources[0], listener))
+	assert.Len(listener.FilterChains, 1)
+}
@@ -0,0 +1,184 @@
+package lds
+
+import (
+	"testing"
+
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressMetricsEnabled().Return(false).AnyTimes()
+	lb := &listenerBuilder{
+		svcAccount: tests.BookbuyerServiceAccount,
+		cfg:        mockConfigurator,
+	}
+
+	egressPolicy := &trafficpolicy.EgressPolicy{
+		Names:      []string{"default/httpbin"},
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"*.github.com", "httpbin.org"}},
+		IPRanges:   map[uint32][]string{443: {"10.0.0.5/24"}, 5432: {"192.168.1.10/32"}},
+	}
+
+	filterChains, err := lb.getEgressFilterChains(egressPolicy)
+	assert.Nil(err)
+	assert.Len(filterChains, 5)
+
+	// HTTP hosts are routed by the route config of their port
+	assert.Equal("outbound-egress-http-filter-chain:80", filterChains[0].Name)
+	assert.Equal(uint32(80), filterChains[0].FilterChainMatch.DestinationPort.Value)
+	assert.Empty(filterChains[0].FilterChainMatch.PrefixRanges)
+	assert.Equal(wellknown.HTTPConnectionManager, filterChains[0].Filters[0].Name)
+	connManager := &xds_hcm.HttpConnectionManager{}
+	err = ptypes.UnmarshalAny(filterChains[0].Filters[0].GetTypedConfig(), connManager)
+	assert.Nil(err)
+	assert.Equal("rds-egress.80", connManager.GetRds().RouteConfigName)
+	assert.Len(connManager.AccessLog, 1)
+
+	// HTTPS hosts are matched by SNI, and wildcard domains by the SNI of any of their subdomains
+	assert.Equal("outbound-egress-https-filter-chain:*.github.com:443", filterChains[1].Name)
+	assert.Equal([]string{"*.github.com"}, filterChains[1].FilterChainMatch.ServerNames)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChains[1].Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Equal("egress-wildcard-hosts:443", tcpProxy.GetCluster())
+	assert.Empty(tcpProxy.AccessLog)
+	assert.Equal("outbound-egress-https-filter-chain:httpbin.org:443", filterChains[2].Name)
+	assert.Equal(uint32(443), filterChains[2].FilterChainMatch.DestinationPort.Value)
+	assert.Equal([]string{"httpbin.org"}, filterChains[2].FilterChainMatch.ServerNames)
+	assert.Equal(envoy.TransportProtocolTLS, filterChains[2].FilterChainMatch.TransportProtocol)
+	tcpProxy = &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChains[2].Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Equal("egress:httpbin.org:443", tcpProxy.GetCluster())
+
+	// IP ranges are matched by the destination IP address
+	assert.Equal("outbound-egress-ip-ranges-filter-chain:443", filterChains[3].Name)
+	assert.Len(filterChains[3].FilterChainMatch.PrefixRanges, 1)
+	assert.Equal("10.0.0.0", filterChains[3].FilterChainMatch.PrefixRanges[0].AddressPrefix)
+	assert.Equal(uint32(24), filterChains[3].FilterChainMatch.PrefixRanges[0].PrefixLen.Value)
+	assert.Equal("outbound-egress-ip-ranges-filter-chain:5432", filterChains[4].Name)
+	tcpProxy = &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChains[4].Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Equal("egress-ip-ranges:5432", tcpProxy.GetCluster())
+
+	// Invalid IP ranges are an error
+	_, err = lb.getEgressFilterChains(&trafficpolicy.EgressPolicy{IPRanges: map[uint32][]string{80: {"10.0.0.5"}}})
+	assert.NotNil(err)
+}
+
+func TestGetEgressFilterChainsWithEgressMetrics(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressMetricsEnabled().Return(true).AnyTimes()
+	lb := &listenerBuilder{
+		svcAccount: tests.BookbuyerServiceAccount,
+		cfg:        mockConfigurator,
+	}
+
+	filterChains, err := lb.getEgressFilterChains(&trafficpolicy.EgressPolicy{
+		HTTPHosts:  map[uint32][]string{80: {"httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"httpbin.org"}},
+	})
+	assert.Nil(err)
+	assert.Len(filterChains, 2)
+
+	// The HTTP requests are reported to the controller in addition to the access log of the proxy
+	connManager := &xds_hcm.HttpConnectionManager{}
+	err = ptypes.UnmarshalAny(filterChains[0].Filters[0].GetTypedConfig(), connManager)
+	assert.Nil(err)
+	assert.Len(connManager.AccessLog, 2)
+	assert.Equal(wellknown.HTTPGRPCAccessLog, connManager.AccessLog[1].Name)
+	httpGRPCAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	err = ptypes.UnmarshalAny(connManager.AccessLog[1].GetTypedConfig(), httpGRPCAccessLog)
+	assert.Nil(err)
+	assert.Equal(envoy.EgressMetricsAccessLogName, httpGRPCAccessLog.CommonConfig.LogName)
+
+	// The TCP connections are reported to the controller
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChains[1].Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Len(tcpProxy.AccessLog, 1)
+	assert.Equal(tcpGRPCAccessLogName, tcpProxy.AccessLog[0].Name)
+	tcpGRPCAccessLog := &xds_accesslog_grpc.TcpGrpcAccessLogConfig{}
+	err = ptypes.UnmarshalAny(tcpProxy.AccessLog[0].GetTypedConfig(), tcpGRPCAccessLog)
+	assert.Nil(err)
+	assert.Equal(envoy.EgressMetricsAccessLogName, tcpGRPCAccessLog.CommonConfig.LogName)
+	assert.Equal("osm-controller", tcpGRPCAccessLog.CommonConfig.GrpcService.GetEnvoyGrpc().ClusterName)
+}
+
+func TestGetEgressTLSInspectorFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	// No HTTPS hosts do not require the TLS inspector
+	assert.Nil(getEgressTLSInspectorFilter(&trafficpolicy.EgressPolicy{HTTPHosts: map[uint32][]string{80: {"httpbin.org"}}}))
+
+	filter := getEgressTLSInspectorFilter(&trafficpolicy.EgressPolicy{
+		HTTPSHosts: map[uint32][]string{8443: {"httpbin.org"}, 443: {"httpbin.org"}},
+	})
+	assert.Equal(wellknown.TlsInspector, filter.Name)
+
+	// The filter is disabled for connections to ports other than the ports of the HTTPS hosts
+	portRules := filter.FilterDisabled.GetNotMatch().GetOrMatch().GetRules()
+	assert.Len(portRules, 2)
+	assert.Equal(int32(443), portRules[0].GetDestinationPortRange().Start)
+	assert.Equal(int32(8443), portRules[1].GetDestinationPortRange().Start)
+}
+
+func TestNewEgressHTTPProxyListener(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressMetricsEnabled().Return(false).AnyTimes()
+	lb := &listenerBuilder{
+		svcAccount: tests.BookbuyerServiceAccount,
+		cfg:        mockConfigurator,
+	}
+
+	listener, err := lb.newEgressHTTPProxyListener()
+	assert.Nil(err)
+	assert.Equal("egress-http-proxy-listener", listener.Name)
+
+	// The listener is bound to the localhost HTTP proxy port
+	assert.Equal("127.0.0.1", listener.Address.GetSocketAddress().Address)
+	assert.Equal(uint32(15004), listener.Address.GetSocketAddress().GetPortValue())
+
+	assert.Len(listener.FilterChains, 1)
+	connManager := &xds_hcm.HttpConnectionManager{}
+	err = ptypes.UnmarshalAny(listener.FilterChains[0].Filters[0].GetTypedConfig(), connManager)
+	assert.Nil(err)
+	assert.Equal("rds-egress-http-proxy", connManager.GetRds().RouteConfigName)
+	assert.Len(connManager.UpgradeConfigs, 1)
+	assert.Equal("CONNECT", connManager.UpgradeConfigs[0].UpgradeType)
+
+	// IPv6 pods bind the listener to the IPv6 localhost address
+	lb.ipv6 = true
+	listener, err = lb.newEgressHTTPProxyListener()
+	assert.Nil(err)
+	assert.Equal("::1", listener.Address.GetSocketAddress().Address)
+}
@@ -0,0 +1,7 @@
+package lds
+
+import (
+	"github.com/pkg/errors"
+)
+
+var errWAFModuleChecksumNotSet = errors.New("WAF module checksum not set")
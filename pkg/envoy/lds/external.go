@@ -0,0 +1,52 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// inboundExternalPlaintextFilterChainPrefix is the prefix of the filter chains that handle plaintext traffic
+	// originating outside the mesh on NodePort or LoadBalancer services
+	inboundExternalPlaintextFilterChainPrefix = "inbound-external-plaintext-filter-chain"
+)
+
+// getExternalPlaintextFilterChains returns a plaintext filter chain per target port of the given services that allow
+// traffic arriving on a NodePort or LoadBalancer directly from outside the mesh. The filter chains only match non-TLS
+// connections so that they do not shadow the in-mesh mTLS filter chains on the same ports.
+func (lb *listenerBuilder) getExternalPlaintextFilterChains(svcList []service.MeshService) []*xds_listener.FilterChain {
+	var filterChains []*xds_listener.FilterChain
+
+	// Multiple services selecting the same pod can share a target port, and a filter chain match must be unique
+	externalPorts := make(map[uint32]string)
+	for _, svc := range svcList {
+		if !lb.meshCatalog.IsExternalPlaintextTrafficAllowed(svc) {
+			continue
+		}
+
+		svcPorts, err := lb.meshCatalog.ListServicePorts(svc)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error retrieving ports for service %s", svc)
+			continue
+		}
+		for _, svcPort := range service.DedupTargetPorts(svcPorts) {
+			externalPorts[svcPort.TargetPort] = svcPort.Protocol
+		}
+	}
+
+	for port := range externalPorts {
+		filterChain, err := newInboundPortTCPFilterChain(fmt.Sprintf("%s:%d", inboundExternalPlaintextFilterChainPrefix, port), port)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building external plaintext filter chain for port %d", port)
+			continue
+		}
+		filterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolRawBuffer
+		filterChains = append(filterChains, filterChain)
+	}
+
+	return filterChains
+}
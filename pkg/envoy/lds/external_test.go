@@ -0,0 +1,87 @@
+package lds
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetExternalPlaintextFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name                     string
+		allowedServices          map[service.MeshService]bool
+		svcPortToProtocolMap     map[service.MeshService]map[uint32]string
+		expectedFilterChainPorts []uint32
+	}{
+		{
+			name:                     "service does not allow external plaintext traffic",
+			allowedServices:          map[service.MeshService]bool{tests.BookstoreV1Service: false},
+			expectedFilterChainPorts: nil,
+		},
+		{
+			name:            "service allows external plaintext traffic",
+			allowedServices: map[service.MeshService]bool{tests.BookstoreV1Service: true},
+			svcPortToProtocolMap: map[service.MeshService]map[uint32]string{
+				tests.BookstoreV1Service: {80: "http", 90: "tcp"},
+			},
+			expectedFilterChainPorts: []uint32{80, 90},
+		},
+		{
+			name:            "services sharing a target port",
+			allowedServices: map[service.MeshService]bool{tests.BookstoreV1Service: true, tests.BookstoreV2Service: true},
+			svcPortToProtocolMap: map[service.MeshService]map[uint32]string{
+				tests.BookstoreV1Service: {80: "http"},
+				tests.BookstoreV2Service: {80: "http", 90: "tcp"},
+			},
+			expectedFilterChainPorts: []uint32{80, 90},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+			}
+
+			var svcList []service.MeshService
+			for svc, allowed := range tc.allowedServices {
+				svcList = append(svcList, svc)
+				mockCatalog.EXPECT().IsExternalPlaintextTrafficAllowed(svc).Return(allowed).Times(1)
+				if !allowed {
+					continue
+				}
+
+				var svcPorts []service.MeshService
+				for port, appProtocol := range tc.svcPortToProtocolMap[svc] {
+					svcPorts = append(svcPorts, service.MeshService{Namespace: svc.Namespace, Name: svc.Name, Port: port, TargetPort: port, Protocol: appProtocol})
+				}
+				mockCatalog.EXPECT().ListServicePorts(svc).Return(svcPorts, nil).Times(1)
+			}
+
+			filterChains := lb.getExternalPlaintextFilterChains(svcList)
+
+			assert.Len(filterChains, len(tc.expectedFilterChainPorts))
+			var actualPorts []uint32
+			for _, filterChain := range filterChains {
+				port := filterChain.FilterChainMatch.DestinationPort.GetValue()
+				actualPorts = append(actualPorts, port)
+				assert.Equal(fmt.Sprintf("%s:%d", inboundExternalPlaintextFilterChainPrefix, port), filterChain.Name)
+				assert.Equal(envoy.TransportProtocolRawBuffer, filterChain.FilterChainMatch.TransportProtocol)
+				assert.Nil(filterChain.TransportSocket)
+			}
+			assert.ElementsMatch(tc.expectedFilterChainPorts, actualPorts)
+		})
+	}
+}
@@ -2,18 +2,30 @@ package lds
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	xds_lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
+	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/envoy/route"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 const (
@@ -22,6 +34,16 @@ const (
 
 	// inboundIngressNonSNIFilterChain is the name of the ingress filter chain that handles either HTTP or HTTPS traffic without SNI set
 	inboundIngressNonSNIFilterChain = "inbound-ingress-non-sni-filter-chain"
+
+	// inboundIngressTCPFilterChain is the name of the ingress filter chain that handles plaintext TCP traffic
+	inboundIngressTCPFilterChain = "inbound-ingress-tcp-filter-chain"
+
+	// inboundIngressTLSPassthroughFilterChain is the name of the ingress filter chain that handles TLS traffic proxied
+	// to the backend without TLS termination
+	inboundIngressTLSPassthroughFilterChain = "inbound-ingress-tls-passthrough-filter-chain"
+
+	// inboundIngressTCPProxyStatPrefix is the stat prefix of the TCP proxy filter of the ingress TCP filter chains
+	inboundIngressTCPProxyStatPrefix = "inbound-ingress-tcp-proxy"
 )
 
 func getIngressTransportProtocol(forHTTPS bool) string {
@@ -31,14 +53,36 @@ func getIngressTransportProtocol(forHTTPS bool) string {
 	return ""
 }
 
-func (lb *listenerBuilder) newIngressHTTPFilterChain(cfg configurator.Configurator, svc service.MeshService, svcPort uint32) *xds_listener.FilterChain {
-	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.svcAccount, false /* TLS */))
+// newIngressHTTPFilterChain returns a filter chain for ingress traffic to the given port of the service. When forHTTPS is set,
+// the traffic is TLS terminated, and when requireClientCert is also set, the ingress clients must present a certificate
+// issued by the CA bundle stored in the caBundleSecret secret, or by the mesh CA when caBundleSecret is empty.
+func (lb *listenerBuilder) newIngressHTTPFilterChain(cfg configurator.Configurator, svc service.MeshService, svcPort uint32, forHTTPS bool, requireClientCert bool, caBundleSecret string) *xds_listener.FilterChain {
+	// The client certificates are validated without SAN matching, the ingress sources allowed to reach the service
+	// are enforced by the RBAC filter of the IngressBackend
+	downstreamTLSContext := envoy.GetIngressDownstreamTLSContext(lb.svcAccount, requireClientCert, caBundleSecret)
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(downstreamTLSContext)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error marshalling DownstreamTLSContext object for proxy %s", svc)
 		return nil
 	}
 
 	inboundConnManager := getHTTPConnectionManager(route.InboundRouteConfigName, cfg, nil)
+	inboundConnManager.AccessLog = lb.getIngressHTTPAccessLog()
+	ingressBackendHeaderFilter, err := getRemoveIngressBackendHeaderFilter()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building filter removing the %s header for proxy %s", envoy.IngressBackendHeader, svc)
+		return nil
+	}
+	inboundConnManager.HttpFilters = append([]*xds_hcm.HttpFilter{ingressBackendHeaderFilter}, inboundConnManager.HttpFilters...)
+	setForwardedHeaderPolicy(inboundConnManager, lb.meshCatalog.GetIngressForwardedHeaderPolicy(svc))
+	if maxRequestBytes := lb.meshCatalog.GetIngressAnnotationPolicy(svc).MaxRequestBytes; maxRequestBytes > 0 {
+		bufferFilter, err := getBufferFilter(maxRequestBytes)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building buffer filter for proxy %s", svc)
+			return nil
+		}
+		addHTTPFilterBeforeRouter(inboundConnManager, bufferFilter)
+	}
 	marshalledInboundConnManager, err := ptypes.MarshalAny(inboundConnManager)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error marshalling inbound HttpConnectionManager object for proxy %s", svc)
@@ -51,9 +95,9 @@ func (lb *listenerBuilder) newIngressHTTPFilterChain(cfg configurator.Configurat
 			DestinationPort: &wrapperspb.UInt32Value{
 				Value: svcPort,
 			},
-			TransportProtocol: getIngressTransportProtocol(cfg.UseHTTPSIngress()),
+			TransportProtocol: getIngressTransportProtocol(forHTTPS),
 		},
-		TransportSocket: getIngressTransportSocket(cfg.UseHTTPSIngress(), marshalledDownstreamTLSContext),
+		TransportSocket: getIngressTransportSocket(forHTTPS, marshalledDownstreamTLSContext),
 		Filters: []*xds_listener.Filter{
 			{
 				Name: wellknown.HTTPConnectionManager,
@@ -65,42 +109,225 @@ func (lb *listenerBuilder) newIngressHTTPFilterChain(cfg configurator.Configurat
 	}
 }
 
+// getRemoveIngressBackendHeaderFilter returns the HTTP filter removing the header of the requests forwarded between the
+// backends of a TrafficSplit from the requests received from ingress. It must be the first filter, so that the header is
+// removed before the route of the request is selected, or else ingress clients could bypass the TrafficSplit by setting
+// the header themselves.
+func getRemoveIngressBackendHeaderFilter() (*xds_hcm.HttpFilter, error) {
+	lua := &xds_lua.Lua{
+		InlineCode: fmt.Sprintf("function envoy_on_request(request_handle)\n  request_handle:headers():remove(%q)\nend", envoy.IngressBackendHeader),
+	}
+
+	luaAny, err := ptypes.MarshalAny(lua)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling Lua filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.Lua,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: luaAny,
+		},
+	}, nil
+}
+
+// newIngressTCPFilterChain returns a filter chain for ingress traffic to the given port of the service that is proxied to
+// the local cluster for the port without HTTP routing. When tlsPassthrough is set, the filter chain only matches TLS
+// connections, which are proxied to the service without being terminated.
+func (lb *listenerBuilder) newIngressTCPFilterChain(svc service.MeshService, svcPort uint32, tlsPassthrough bool) *xds_listener.FilterChain {
+	localPortCluster := envoy.GetLocalClusterNameForPort(svcPort)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", inboundIngressTCPProxyStatPrefix, localPortCluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: localPortCluster},
+		AccessLog:        lb.getIngressTCPAccessLog(),
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for ingress filter chain of proxy %s", svc)
+		return nil
+	}
+
+	return &xds_listener.FilterChain{
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			DestinationPort: &wrapperspb.UInt32Value{
+				Value: svcPort,
+			},
+			TransportProtocol: getIngressTransportProtocol(tlsPassthrough),
+		},
+		Filters: []*xds_listener.Filter{
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		},
+	}
+}
+
 func (lb *listenerBuilder) getIngressFilterChains(svc service.MeshService) []*xds_listener.FilterChain {
 	var ingressFilterChains []*xds_listener.FilterChain
 
-	protocolToPortMap, err := lb.meshCatalog.GetTargetPortToProtocolMappingForService(svc)
+	ingressBackendPolicy, err := lb.meshCatalog.GetIngressBackendPolicy(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error retrieving IngressBackend policy for service %s", svc)
+		return ingressFilterChains
+	}
+	if ingressBackendPolicy != nil {
+		return lb.getIngressBackendFilterChains(svc, ingressBackendPolicy)
+	}
+
+	svcPorts, err := lb.meshCatalog.ListServicePorts(svc)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error retrieving port to protocol mapping for service %s", svc)
+		log.Error().Err(err).Msgf("Error retrieving ports for service %s", svc)
 		return ingressFilterChains
 	}
 
-	// Create protocol specific inbound filter chains per port to handle different ports serving different protocols
-	for port, appProtocol := range protocolToPortMap {
-		switch appProtocol {
+	// The backend protocol annotations of the ingress controller override the mesh-wide HTTPS ingress setting
+	forHTTPS := lb.cfg.UseHTTPSIngress()
+	if backendProtocol := lb.meshCatalog.GetIngressAnnotationPolicy(svc).BackendProtocol; backendProtocol != "" {
+		forHTTPS = backendProtocol == policyV1alpha1.ProtocolHTTPS
+	}
+
+	// Create protocol specific inbound filter chains per target port to handle different ports serving different protocols
+	for _, svcPort := range service.DedupTargetPorts(svcPorts) {
+		port := svcPort.TargetPort
+		switch strings.ToLower(svcPort.Protocol) {
 		case httpAppProtocol:
 			// Ingress filter chain for HTTP port
-			if lb.cfg.UseHTTPSIngress() {
+			if forHTTPS {
 				// Filter chain with SNI matching enabled for HTTPS clients that set the SNI
-				ingressFilterChainWithSNI := lb.newIngressHTTPFilterChain(lb.cfg, svc, port)
+				ingressFilterChainWithSNI := lb.newIngressHTTPFilterChain(lb.cfg, svc, port, forHTTPS, false, "")
 				ingressFilterChainWithSNI.Name = fmt.Sprintf("%s:%d", inboundIngressHTTPSFilterChain, port)
 				ingressFilterChainWithSNI.FilterChainMatch.ServerNames = []string{svc.ServerName()}
 				ingressFilterChains = append(ingressFilterChains, ingressFilterChainWithSNI)
 			}
 
 			// Filter chain without SNI matching enabled for HTTP clients and HTTPS clients that don't set the SNI
-			ingressFilterChainWithoutSNI := lb.newIngressHTTPFilterChain(lb.cfg, svc, port)
+			ingressFilterChainWithoutSNI := lb.newIngressHTTPFilterChain(lb.cfg, svc, port, forHTTPS, false, "")
 			ingressFilterChainWithoutSNI.Name = fmt.Sprintf("%s:%d", inboundIngressNonSNIFilterChain, port)
 			ingressFilterChains = append(ingressFilterChains, ingressFilterChainWithoutSNI)
 
+		case tcpAppProtocol:
+			// Ingress only routes HTTP traffic, the TCP ports of the service are only reachable within the mesh
+			log.Debug().Msgf("Skipping ingress filter chain for TCP port %d of service %s", port, svc)
+
 		default:
 			log.Error().Msgf("Cannot build inbound filter chain. Protocol %s is not supported for service %s on port %d",
-				appProtocol, svc, port)
+				svcPort.Protocol, svc, port)
+		}
+	}
+
+	return ingressFilterChains
+}
+
+// getIngressBackendFilterChains returns the ingress filter chains for the ports of the service listed in its IngressBackend
+// policy. Each filter chain only allows connections from the ingress sources listed in the policy. The traffic on the tcp
+// and tls-passthrough ports is proxied to the service without HTTP routing.
+func (lb *listenerBuilder) getIngressBackendFilterChains(svc service.MeshService, policy *trafficpolicy.IngressBackendPolicy) []*xds_listener.FilterChain {
+	var ingressFilterChains []*xds_listener.FilterChain
+
+	rbacFilter, err := buildIngressBackendRBACFilter(policy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building RBAC filter for IngressBackend %s of service %s", policy.Name, svc)
+		return ingressFilterChains
+	}
+
+	var ports []uint32
+	for port := range policy.Ports {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i] < ports[j]
+	})
+
+	for _, port := range ports {
+		var filterChain *xds_listener.FilterChain
+		switch policy.Ports[port] {
+		case policyV1alpha1.ProtocolHTTPS:
+			filterChain = lb.newIngressHTTPFilterChain(lb.cfg, svc, port, true, policy.RequireClientCert, policy.CABundleSecret)
+			if filterChain == nil {
+				continue
+			}
+			filterChain.Name = fmt.Sprintf("%s:%d", inboundIngressHTTPSFilterChain, port)
+
+		case policyV1alpha1.ProtocolTCP, policyV1alpha1.ProtocolTLSPassthrough:
+			// The connections are not TLS terminated by the sidecar, so the ingress sources can only be identified by their address
+			if policy.RequireClientCert {
+				log.Error().Msgf("Skipping ingress filter chain for %s port %d of service %s, IngressBackend %s requires client certificates which require HTTPS",
+					policy.Ports[port], port, svc, policy.Name)
+				continue
+			}
+			tlsPassthrough := policy.Ports[port] == policyV1alpha1.ProtocolTLSPassthrough
+			filterChain = lb.newIngressTCPFilterChain(svc, port, tlsPassthrough)
+			if filterChain == nil {
+				continue
+			}
+			if tlsPassthrough {
+				filterChain.Name = fmt.Sprintf("%s:%d", inboundIngressTLSPassthroughFilterChain, port)
+			} else {
+				filterChain.Name = fmt.Sprintf("%s:%d", inboundIngressTCPFilterChain, port)
+			}
+
+		default:
+			// Client certificates, which authenticated principals are verified from, can only be presented over HTTPS
+			if policy.RequireClientCert {
+				log.Error().Msgf("Skipping ingress filter chain for HTTP port %d of service %s, IngressBackend %s requires client certificates which require HTTPS",
+					port, svc, policy.Name)
+				continue
+			}
+			filterChain = lb.newIngressHTTPFilterChain(lb.cfg, svc, port, false, false, "")
+			if filterChain == nil {
+				continue
+			}
+			filterChain.Name = fmt.Sprintf("%s:%d", inboundIngressNonSNIFilterChain, port)
 		}
+
+		filterChain.Filters = append([]*xds_listener.Filter{rbacFilter}, filterChain.Filters...)
+		ingressFilterChains = append(ingressFilterChains, filterChain)
 	}
 
 	return ingressFilterChains
 }
 
+// buildIngressBackendRBACFilter builds a network RBAC filter allowing connections from the ingress sources of the given
+// IngressBackend policy, identified by their authenticated principal or their remote IP address
+func buildIngressBackendRBACFilter(policy *trafficpolicy.IngressBackendPolicy) (*xds_listener.Filter, error) {
+	var sourceRules []rbac.Rule
+	for _, principal := range policy.Principals {
+		sourceRules = append(sourceRules, rbac.Rule{Attribute: rbac.DownstreamAuthPrincipal, Value: principal})
+	}
+	for _, ipRange := range policy.IPRanges {
+		sourceRules = append(sourceRules, rbac.Rule{Attribute: rbac.DownstreamRemoteIP, Value: ipRange})
+	}
+
+	rbacPolicies := make(map[string]*xds_rbac.Policy)
+	// An IngressBackend without sources does not allow any ingress traffic
+	if len(sourceRules) > 0 {
+		rbacPolicy, err := (&rbac.Policy{Principals: []rbac.RulesList{{OrRules: sourceRules}}}).Generate()
+		if err != nil {
+			return nil, err
+		}
+		rbacPolicies[policy.Name] = rbacPolicy
+	}
+
+	networkRBACPolicy := &xds_network_rbac.RBAC{
+		StatPrefix: "ingress-backend-", // will be displayed as ingress-backend-rbac.<path>
+		Rules: &xds_rbac.RBAC{
+			Action:   xds_rbac.RBAC_ALLOW,
+			Policies: rbacPolicies,
+		},
+	}
+
+	marshalledNetworkRBACPolicy, err := ptypes.MarshalAny(networkRBACPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.RoleBasedAccessControl,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledNetworkRBACPolicy},
+	}, nil
+}
+
 func getIngressTransportSocket(forHTTPS bool, marshalledDownstreamTLSContext *any.Any) *xds_core.TransportSocket {
 	if forHTTPS {
 		return &xds_core.TransportSocket{
@@ -112,3 +339,23 @@ func getIngressTransportSocket(forHTTPS bool, marshalledDownstreamTLSContext *an
 	}
 	return nil
 }
+
+// getBufferFilter returns the HTTP filter rejecting the requests whose body is larger than the given number of bytes.
+// The body of the requests is buffered by the filter before the requests are routed.
+func getBufferFilter(maxRequestBytes uint32) (*xds_hcm.HttpFilter, error) {
+	buffer := &xds_buffer.Buffer{
+		MaxRequestBytes: &wrapperspb.UInt32Value{Value: maxRequestBytes},
+	}
+
+	bufferAny, err := ptypes.MarshalAny(buffer)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling buffer filter config")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.Buffer,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: bufferAny,
+		},
+	}, nil
+}
@@ -8,12 +8,22 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	xds_lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestGetIngressFilterChains(t *testing.T) {
@@ -28,6 +38,8 @@ func TestGetIngressFilterChains(t *testing.T) {
 		httpsIngress         bool // true for https, false for http
 		svcPortToProtocolMap map[uint32]string
 		portToProtocolErr    error // error to return if port:protocol mapping returns an error
+		ingressBackendPolicy *trafficpolicy.IngressBackendPolicy
+		annotationPolicy     trafficpolicy.IngressAnnotationPolicy
 
 		expectedFilterChainCount               int
 		expectedFilterNamesPerFilterChain      []string
@@ -84,6 +96,145 @@ func TestGetIngressFilterChains(t *testing.T) {
 				},
 			},
 		},
+
+		{
+			// Test case 3
+			name:                 "HTTP ingress filter chain for service with an HTTP port and a TCP port",
+			httpsIngress:         false,
+			svcPortToProtocolMap: map[uint32]string{80: "http", 5432: "tcp"},
+			portToProtocolErr:    nil,
+
+			expectedFilterChainCount:          1, // TCP ports are not exposed through ingress
+			expectedFilterNamesPerFilterChain: []string{wellknown.HTTPConnectionManager},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+					TransportProtocol: "",
+				},
+			},
+		},
+
+		{
+			// Test case 4
+			name:         "IngressBackend filter chains for the HTTP and HTTPS ports of the service",
+			httpsIngress: false,
+			ingressBackendPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:     "bookstore-ns/bookstore",
+				Ports:    map[uint32]string{80: "http", 443: "https"},
+				IPRanges: []string{"10.0.0.0/8"},
+			},
+
+			expectedFilterChainCount:          2, // 1 per port listed in the IngressBackend
+			expectedFilterNamesPerFilterChain: []string{wellknown.RoleBasedAccessControl, wellknown.HTTPConnectionManager},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+					TransportProtocol: "",
+				},
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 443},
+					TransportProtocol: "tls",
+				},
+			},
+		},
+
+		{
+			// Test case 5
+			name:         "IngressBackend with authenticated principals only allows HTTPS ports",
+			httpsIngress: false,
+			ingressBackendPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:              "bookstore-ns/bookstore",
+				Ports:             map[uint32]string{80: "http", 443: "https"},
+				Principals:        []string{"ingress-nginx.ingress-ns.cluster.local"},
+				RequireClientCert: true,
+			},
+
+			expectedFilterChainCount:          1, // The HTTP port cannot authenticate the ingress sources
+			expectedFilterNamesPerFilterChain: []string{wellknown.RoleBasedAccessControl, wellknown.HTTPConnectionManager},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 443},
+					TransportProtocol: "tls",
+				},
+			},
+		},
+
+		{
+			// Test case 6
+			name:         "IngressBackend filter chains for the TCP and TLS passthrough ports of the service",
+			httpsIngress: false,
+			ingressBackendPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:     "bookstore-ns/bookstore",
+				Ports:    map[uint32]string{5432: "tcp", 8443: "tls-passthrough"},
+				IPRanges: []string{"10.0.0.0/8"},
+			},
+
+			expectedFilterChainCount:          2, // 1 per port listed in the IngressBackend
+			expectedFilterNamesPerFilterChain: []string{wellknown.RoleBasedAccessControl, wellknown.TCPProxy},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 5432},
+					TransportProtocol: "",
+				},
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 8443},
+					TransportProtocol: "tls",
+				},
+			},
+		},
+
+		{
+			// Test case 7
+			name:         "IngressBackend with authenticated principals does not allow TCP ports",
+			httpsIngress: false,
+			ingressBackendPolicy: &trafficpolicy.IngressBackendPolicy{
+				Name:              "bookstore-ns/bookstore",
+				Ports:             map[uint32]string{5432: "tcp", 8443: "tls-passthrough"},
+				Principals:        []string{"ingress-nginx.ingress-ns.cluster.local"},
+				RequireClientCert: true,
+			},
+
+			expectedFilterChainCount: 0, // The TCP ports cannot authenticate the ingress sources
+		},
+
+		{
+			// Test case 8
+			name:                 "HTTPS ingress filter chain for service with an HTTPS backend protocol annotation",
+			httpsIngress:         false,
+			svcPortToProtocolMap: map[uint32]string{80: "http"},
+			annotationPolicy:     trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "https"},
+
+			expectedFilterChainCount:          2, // with and without SNI matching
+			expectedFilterNamesPerFilterChain: []string{wellknown.HTTPConnectionManager},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+					TransportProtocol: "tls",
+				},
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+					TransportProtocol: "tls",
+					ServerNames:       []string{proxyService.ServerName()},
+				},
+			},
+		},
+
+		{
+			// Test case 9
+			name:                 "HTTP ingress filter chain for service with an HTTP backend protocol annotation",
+			httpsIngress:         true,
+			svcPortToProtocolMap: map[uint32]string{80: "http"},
+			annotationPolicy:     trafficpolicy.IngressAnnotationPolicy{BackendProtocol: "http"},
+
+			expectedFilterChainCount:          1,
+			expectedFilterNamesPerFilterChain: []string{wellknown.HTTPConnectionManager},
+			expectedFilterChainMatchPerFilterChain: []*xds_listener.FilterChainMatch{
+				{
+					DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+					TransportProtocol: "",
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -97,12 +248,22 @@ func TestGetIngressFilterChains(t *testing.T) {
 				svcAccount:  tests.BookstoreServiceAccount,
 			}
 
-			// Mock catalog call to get port:protocol mapping for service
-			mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(proxyService).Return(tc.svcPortToProtocolMap, tc.portToProtocolErr).Times(1)
+			// Mock catalog call to get the IngressBackend policy for service
+			mockCatalog.EXPECT().GetIngressBackendPolicy(proxyService).Return(tc.ingressBackendPolicy, nil).Times(1)
+			// Mock catalog call to get the ports of the service, only used without an IngressBackend policy
+			if tc.ingressBackendPolicy == nil {
+				var svcPorts []service.MeshService
+				for port, appProtocol := range tc.svcPortToProtocolMap {
+					svcPorts = append(svcPorts, service.MeshService{Namespace: proxyService.Namespace, Name: proxyService.Name, Port: port, TargetPort: port, Protocol: appProtocol})
+				}
+				mockCatalog.EXPECT().ListServicePorts(proxyService).Return(svcPorts, tc.portToProtocolErr).Times(1)
+			}
 			// Mock configurator calls to determine HTTP vs HTTPS ingress
 			mockConfigurator.EXPECT().UseHTTPSIngress().Return(tc.httpsIngress).AnyTimes()
 			// Mock calls used to build the HTTP connection manager
 			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockCatalog.EXPECT().GetIngressForwardedHeaderPolicy(proxyService).Return(trafficpolicy.ForwardedHeaderPolicy{}).AnyTimes()
+			mockCatalog.EXPECT().GetIngressAnnotationPolicy(proxyService).Return(tc.annotationPolicy).AnyTimes()
 
 			filterChains := lb.getIngressFilterChains(proxyService)
 
@@ -123,6 +284,98 @@ func TestGetIngressFilterChains(t *testing.T) {
 	}
 }
 
+func TestGetIngressBackendFilterChainsClientCert(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyService := tests.BookstoreV1Service
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetIngressForwardedHeaderPolicy(proxyService).Return(trafficpolicy.ForwardedHeaderPolicy{}).AnyTimes()
+	mockCatalog.EXPECT().GetIngressAnnotationPolicy(proxyService).Return(trafficpolicy.IngressAnnotationPolicy{}).AnyTimes()
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		cfg:         mockConfigurator,
+		svcAccount:  tests.BookstoreServiceAccount,
+	}
+
+	filterChains := lb.getIngressBackendFilterChains(proxyService, &trafficpolicy.IngressBackendPolicy{
+		Name:              "bookstore-ns/bookstore",
+		Ports:             map[uint32]string{443: "https"},
+		IPRanges:          []string{"10.0.0.0/8"},
+		RequireClientCert: true,
+		CABundleSecret:    "bookstore-ns/ingress-ca",
+	})
+	assert.Len(filterChains, 1)
+
+	downstreamTLSContext := &xds_auth.DownstreamTlsContext{}
+	assert.Nil(ptypes.UnmarshalAny(filterChains[0].TransportSocket.GetTypedConfig(), downstreamTLSContext))
+	assert.True(downstreamTLSContext.RequireClientCertificate.GetValue())
+	assert.Equal(envoy.SDSCert{Name: "bookstore-ns/ingress-ca", CertType: envoy.RootCertTypeForIngressMTLS}.String(),
+		downstreamTLSContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name)
+}
+
+func TestBuildIngressBackendRBACFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		name                 string
+		policy               *trafficpolicy.IngressBackendPolicy
+		expectedPolicyCount  int
+		expectedPrincipalIDs int
+		expectError          bool
+	}{
+		{
+			name: "principals and IP ranges are allowed by a single policy",
+			policy: &trafficpolicy.IngressBackendPolicy{
+				Name:       "bookstore-ns/bookstore",
+				Principals: []string{"ingress-nginx.ingress-ns.cluster.local"},
+				IPRanges:   []string{"10.0.0.0/8", "192.168.0.0/16"},
+			},
+			expectedPolicyCount:  1,
+			expectedPrincipalIDs: 3,
+		},
+		{
+			name:                "no sources denies all ingress traffic",
+			policy:              &trafficpolicy.IngressBackendPolicy{Name: "bookstore-ns/bookstore"},
+			expectedPolicyCount: 0,
+		},
+		{
+			name: "invalid IP range",
+			policy: &trafficpolicy.IngressBackendPolicy{
+				Name:     "bookstore-ns/bookstore",
+				IPRanges: []string{"10.0.0.1"},
+			},
+			expectError: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			filter, err := buildIngressBackendRBACFilter(tc.policy)
+			assert.Equal(tc.expectError, err != nil)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(wellknown.RoleBasedAccessControl, filter.Name)
+			networkRBAC := &xds_network_rbac.RBAC{}
+			assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), networkRBAC))
+			assert.Equal(xds_rbac.RBAC_ALLOW, networkRBAC.Rules.Action)
+			assert.Len(networkRBAC.Rules.Policies, tc.expectedPolicyCount)
+			if tc.expectedPolicyCount > 0 {
+				rbacPolicy := networkRBAC.Rules.Policies[tc.policy.Name]
+				assert.NotNil(rbacPolicy)
+				assert.Len(rbacPolicy.Principals, 1)
+				assert.Len(rbacPolicy.Principals[0].GetOrIds().Ids, tc.expectedPrincipalIDs)
+			}
+		})
+	}
+}
+
 func TestGetIngressTransportProtocol(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -150,3 +403,71 @@ func TestGetIngressTransportProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestNewIngressHTTPFilterChainMaxRequestBytes(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyService := tests.BookstoreV1Service
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetIngressForwardedHeaderPolicy(proxyService).Return(trafficpolicy.ForwardedHeaderPolicy{}).AnyTimes()
+	mockCatalog.EXPECT().GetIngressAnnotationPolicy(proxyService).Return(trafficpolicy.IngressAnnotationPolicy{MaxRequestBytes: 8 << 20}).AnyTimes()
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		cfg:         mockConfigurator,
+		svcAccount:  tests.BookstoreServiceAccount,
+	}
+
+	filterChain := lb.newIngressHTTPFilterChain(lb.cfg, proxyService, 80, false, false, "")
+	assert.NotNil(filterChain)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), connManager))
+	// The buffer filter is added right before the router filter
+	assert.Len(connManager.HttpFilters, 4)
+	assert.Equal(wellknown.Buffer, connManager.HttpFilters[2].Name)
+	assert.Equal(wellknown.Router, connManager.HttpFilters[3].Name)
+
+	buffer := &xds_buffer.Buffer{}
+	assert.Nil(ptypes.UnmarshalAny(connManager.HttpFilters[2].GetTypedConfig(), buffer))
+	assert.Equal(uint32(8<<20), buffer.MaxRequestBytes.GetValue())
+}
+
+func TestNewIngressHTTPFilterChainRemovesIngressBackendHeader(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyService := tests.BookstoreV1Service
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetIngressForwardedHeaderPolicy(proxyService).Return(trafficpolicy.ForwardedHeaderPolicy{}).AnyTimes()
+	mockCatalog.EXPECT().GetIngressAnnotationPolicy(proxyService).Return(trafficpolicy.IngressAnnotationPolicy{}).AnyTimes()
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		cfg:         mockConfigurator,
+		svcAccount:  tests.BookstoreServiceAccount,
+	}
+
+	filterChain := lb.newIngressHTTPFilterChain(lb.cfg, proxyService, 80, false, false, "")
+	assert.NotNil(filterChain)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), connManager))
+
+	// A client cannot bypass a TrafficSplit by setting the header of the requests forwarded between its backends,
+	// since the header is removed by the first filter, before the route of the request is selected
+	assert.Len(connManager.HttpFilters, 3)
+	assert.Equal(wellknown.Lua, connManager.HttpFilters[0].Name)
+	lua := &xds_lua.Lua{}
+	assert.Nil(ptypes.UnmarshalAny(connManager.HttpFilters[0].GetTypedConfig(), lua))
+	assert.Contains(lua.InlineCode, fmt.Sprintf("request_handle:headers():remove(%q)", envoy.IngressBackendHeader))
+	assert.Equal(wellknown.HTTPRoleBasedAccessControl, connManager.HttpFilters[1].Name)
+	assert.Equal(wellknown.Router, connManager.HttpFilters[2].Name)
+}
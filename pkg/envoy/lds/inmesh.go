@@ -28,6 +28,8 @@ const (
 	outboundMeshTCPFilterChainPrefix  = "outbound-mesh-tcp-filter-chain"
 	inboundMeshTCPProxyStatPrefix     = "inbound-mesh-tcp-proxy"
 	outboundMeshTCPProxyStatPrefix    = "outbound-mesh-tcp-proxy"
+	inboundPortTCPFilterChainPrefix   = "inbound-port-tcp-filter-chain"
+	inboundPortTCPProxyStatPrefix     = "inbound-port-tcp-proxy"
 	httpAppProtocol                   = "http"
 	tcpAppProtocol                    = "tcp"
 	gRPCAppProtocol                   = "grpc"
@@ -36,15 +38,16 @@ const (
 func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshService) []*xds_listener.FilterChain {
 	var filterChains []*xds_listener.FilterChain
 
-	protocolToPortMap, err := lb.meshCatalog.GetTargetPortToProtocolMappingForService(proxyService)
+	svcPorts, err := lb.meshCatalog.ListServicePorts(proxyService)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error retrieving port to protocol mapping for service %s", proxyService)
+		log.Error().Err(err).Msgf("Error retrieving ports for service %s", proxyService)
 		return filterChains
 	}
 
-	// Create protocol specific inbound filter chains per port to handle different ports serving different protocols
-	for port, appProtocol := range protocolToPortMap {
-		switch strings.ToLower(appProtocol) {
+	// Create protocol specific inbound filter chains per target port to handle different ports serving different protocols
+	for _, svcPort := range service.DedupTargetPorts(svcPorts) {
+		port := svcPort.TargetPort
+		switch strings.ToLower(svcPort.Protocol) {
 		case httpAppProtocol, gRPCAppProtocol:
 			// Filter chain for HTTP port
 			filterChainForPort, err := lb.getInboundMeshHTTPFilterChain(proxyService, port)
@@ -63,13 +66,61 @@ func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshS
 			filterChains = append(filterChains, filterChainForPort)
 
 		default:
-			log.Error().Msgf("Cannot build inbound filter chain, unsupported protocol %s for proxy:port %s:%d", appProtocol, proxyService, port)
+			log.Error().Msgf("Cannot build inbound filter chain, unsupported protocol %s for proxy:port %s:%d", svcPort.Protocol, proxyService, port)
 		}
 	}
 
 	return filterChains
 }
 
+// getInboundPortFilterChains returns inbound filter chains for the given container ports of a pod that is not
+// selected by any service. Since such a pod cannot be addressed as an upstream service within the mesh, traffic
+// on these ports is plaintext and is proxied to the local port as is.
+func (lb *listenerBuilder) getInboundPortFilterChains(ports []uint32) []*xds_listener.FilterChain {
+	var filterChains []*xds_listener.FilterChain
+
+	for _, port := range ports {
+		filterChain, err := newInboundPortTCPFilterChain(fmt.Sprintf("%s:%d", inboundPortTCPFilterChainPrefix, port), port)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building inbound TCP filter chain for port %d", port)
+			continue
+		}
+		filterChains = append(filterChains, filterChain)
+	}
+
+	return filterChains
+}
+
+// newInboundPortTCPFilterChain returns a plaintext filter chain matching the given destination port that proxies
+// traffic to the local cluster for the port.
+func newInboundPortTCPFilterChain(filterChainName string, port uint32) (*xds_listener.FilterChain, error) {
+	localPortCluster := envoy.GetLocalClusterNameForPort(port)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", inboundPortTCPProxyStatPrefix, localPortCluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: localPortCluster},
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for inbound filter chain on port %d", port)
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: filterChainName,
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			DestinationPort: &wrapperspb.UInt32Value{
+				Value: port,
+			},
+		},
+		Filters: []*xds_listener.Filter{
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		},
+	}, nil
+}
+
 func (lb *listenerBuilder) getInboundHTTPFilters(proxyService service.MeshService) ([]*xds_listener.Filter, error) {
 	var filters []*xds_listener.Filter
 
@@ -87,6 +138,65 @@ func (lb *listenerBuilder) getInboundHTTPFilters(proxyService service.MeshServic
 
 	// Apply the HTTP Connection Manager Filter
 	inboundConnManager := getHTTPConnectionManager(route.InboundRouteConfigName, lb.cfg, lb.statsHeaders)
+	inboundConnManager.AccessLog = lb.getInboundHTTPAccessLog()
+	if lb.cfg.IsRBACDenyReportingEnabled() {
+		rbacDenialAccessLog, err := getRBACDenialAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		inboundConnManager.AccessLog = append(inboundConnManager.AccessLog, rbacDenialAccessLog)
+	}
+	// The requests that would be denied by TrafficTargets in shadow mode are reported when shadow mode is enabled
+	if lb.cfg.GetTrafficTargetShadowDuration() > 0 && !lb.cfg.IsPermissiveTrafficPolicyMode() {
+		rbacShadowDenialAccessLog, err := getRBACShadowDenialAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		inboundConnManager.AccessLog = append(inboundConnManager.AccessLog, rbacShadowDenialAccessLog)
+	}
+	// Requests are only recorded in permissive traffic policy mode, in which they are not restricted by SMI policies
+	if lb.cfg.IsPolicyRecorderEnabled() && lb.cfg.IsPermissiveTrafficPolicyMode() {
+		policyRecorderAccessLog, err := getPolicyRecorderAccessLog()
+		if err != nil {
+			return nil, err
+		}
+		inboundConnManager.AccessLog = append(inboundConnManager.AccessLog, policyRecorderAccessLog)
+	}
+
+	// Apply the WAF filter when enabled for the service. The WAF filter must precede the Router filter, which is last.
+	wafFilter, err := lb.getWAFFilter(proxyService)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error building WAF filter for proxy service %s", proxyService)
+		return nil, err
+	}
+	if wafFilter != nil {
+		addHTTPFilterBeforeRouter(inboundConnManager, wafFilter)
+	}
+
+	// Limit the rate of the responses sent by the service to its clients
+	if limit := lb.meshCatalog.GetBandwidthLimitForService(proxyService); limit.EgressKiBps > 0 || len(limit.RouteEgressKiBps) > 0 {
+		bandwidthLimitFilter, err := getBandwidthLimitFilter(limit.EgressKiBps)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building egress bandwidth limit filter for proxy service %s", proxyService)
+			return nil, err
+		}
+		addHTTPFilterBeforeRouter(inboundConnManager, bandwidthLimitFilter)
+	}
+
+	// Shed the load of the service when it is overloaded
+	if policy := lb.meshCatalog.GetAdaptiveConcurrencyPolicy(proxyService); policy.Enabled {
+		adaptiveConcurrencyFilter, err := getAdaptiveConcurrencyFilter(policy)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building adaptive concurrency filter for proxy service %s", proxyService)
+			return nil, err
+		}
+		addHTTPFilterBeforeRouter(inboundConnManager, adaptiveConcurrencyFilter)
+	}
+
+	// Forward the identity of the downstream proxy verified with mTLS to the application
+	setForwardClientCertDetails(inboundConnManager, lb.cfg.GetForwardClientCertDetails())
+	setForwardedHeaderPolicy(inboundConnManager, getMeshForwardedHeaderPolicy(lb.cfg))
+
 	marshalledInboundConnManager, err := ptypes.MarshalAny(inboundConnManager)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error marshalling inbound HttpConnectionManager for proxy  service %s", proxyService)
@@ -154,7 +264,7 @@ func (lb *listenerBuilder) getInboundMeshHTTPFilterChain(proxyService service.Me
 
 func (lb *listenerBuilder) getInboundMeshTCPFilterChain(proxyService service.MeshService, servicePort uint32) (*xds_listener.FilterChain, error) {
 	// Construct TCP filters
-	filters, err := lb.getInboundTCPFilters(proxyService)
+	filters, err := lb.getInboundTCPFilters(proxyService, servicePort)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error constructing inbound TCP filters for proxy service %s", proxyService)
 		return nil, err
@@ -196,7 +306,9 @@ func (lb *listenerBuilder) getInboundMeshTCPFilterChain(proxyService service.Mes
 	}, nil
 }
 
-func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService) ([]*xds_listener.Filter, error) {
+// getInboundTCPFilters returns the filters proxying the TCP traffic on the given service port to the local cluster for
+// the port, so that the traffic reaches the port it was sent to rather than any port of the service.
+func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService, servicePort uint32) ([]*xds_listener.Filter, error) {
 	var filters []*xds_listener.Filter
 
 	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter in the list of filters.
@@ -212,10 +324,11 @@ func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService
 	}
 
 	// Apply the TCP Proxy Filter
-	localServiceCluster := envoy.GetLocalClusterNameForService(proxyService)
+	localPortCluster := envoy.GetLocalClusterNameForPort(servicePort)
 	tcpProxy := &xds_tcp_proxy.TcpProxy{
-		StatPrefix:       fmt.Sprintf("%s.%s", inboundMeshTCPProxyStatPrefix, localServiceCluster),
-		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: localServiceCluster},
+		StatPrefix:       fmt.Sprintf("%s.%s", inboundMeshTCPProxyStatPrefix, envoy.GetLocalClusterNameForService(proxyService)),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: localPortCluster},
+		AccessLog:        lb.getInboundTCPAccessLog(),
 	}
 	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
 	if err != nil {
@@ -236,8 +349,20 @@ func (lb *listenerBuilder) getOutboundHTTPFilter() (*xds_listener.Filter, error)
 	var marshalledFilter *any.Any
 	var err error
 
-	marshalledFilter, err = ptypes.MarshalAny(
-		getHTTPConnectionManager(route.OutboundRouteConfigName, lb.cfg, lb.statsHeaders))
+	outboundConnManager := getHTTPConnectionManager(route.OutboundRouteConfigName, lb.cfg, lb.statsHeaders)
+	outboundConnManager.AccessLog = lb.getOutboundHTTPAccessLog()
+
+	// Limit the rate of the responses received by the services of the proxy from their upstream services
+	if lb.ingressBandwidthLimitKiBps > 0 {
+		bandwidthLimitFilter, err := getBandwidthLimitFilter(lb.ingressBandwidthLimitKiBps)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building ingress bandwidth limit filter")
+			return nil, err
+		}
+		addHTTPFilterBeforeRouter(outboundConnManager, bandwidthLimitFilter)
+	}
+
+	marshalledFilter, err = ptypes.MarshalAny(outboundConnManager)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error marshalling HTTP connection manager object")
 		return nil, err
@@ -289,7 +414,7 @@ func (lb *listenerBuilder) getOutboundFilterChainMatchForService(dstSvc service.
 		filterMatch.PrefixRanges = append(filterMatch.PrefixRanges, &xds_core.CidrRange{
 			AddressPrefix: ip,
 			PrefixLen: &wrapperspb.UInt32Value{
-				Value: singleIpv4Mask,
+				Value: getSingleIPMask(ip),
 			},
 		})
 	}
@@ -452,15 +577,16 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 	// Iterate all destination services
 	for upstream := range dstServicesSet {
 		log.Trace().Msgf("Building outbound filter chain for upstream service %s for proxy with identity %s", upstream, lb.svcAccount)
-		protocolToPortMap, err := lb.meshCatalog.GetPortToProtocolMappingForService(upstream)
+		svcPorts, err := lb.meshCatalog.ListServicePorts(upstream)
 		if err != nil {
-			log.Error().Err(err).Msgf("Error retrieving port to protocol mapping for upstream service %s", upstream)
+			log.Error().Err(err).Msgf("Error retrieving ports for upstream service %s", upstream)
 			continue
 		}
 
-		// Create protocol specific inbound filter chains per port to handle different ports serving different protocols
-		for port, appProtocol := range protocolToPortMap {
-			switch strings.ToLower(appProtocol) {
+		// Create protocol specific outbound filter chains per port to handle different ports serving different protocols
+		for _, svcPort := range svcPorts {
+			port := svcPort.Port
+			switch strings.ToLower(svcPort.Protocol) {
 			case httpAppProtocol, gRPCAppProtocol:
 				// Construct HTTP filter chain
 				if httpFilterChain, err := lb.getOutboundHTTPFilterChainForService(upstream, port); err != nil {
@@ -478,7 +604,7 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 				}
 
 			default:
-				log.Error().Msgf("Cannot build outbound filter chain, unsupported protocol %s for upstream:port %s:%d", appProtocol, upstream, port)
+				log.Error().Msgf("Cannot build outbound filter chain, unsupported protocol %s for upstream:port %s:%d", svcPort.Protocol, upstream, port)
 			}
 		}
 	}
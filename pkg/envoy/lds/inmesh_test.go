@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	tassert "github.com/stretchr/testify/assert"
@@ -37,6 +38,14 @@ func TestGetOutboundHTTPFilterChainForService(t *testing.T) {
 
 	// Mock calls used to build the HTTP connection manager
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
 
 	lb := &listenerBuilder{
@@ -202,7 +211,17 @@ func TestGetInboundMeshHTTPFilterChain(t *testing.T) {
 
 	// Mock calls used to build the HTTP connection manager
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
+	mockCatalog.EXPECT().GetBandwidthLimitForService(gomock.Any()).Return(trafficpolicy.BandwidthLimit{}).AnyTimes()
+	mockCatalog.EXPECT().GetAdaptiveConcurrencyPolicy(gomock.Any()).Return(trafficpolicy.AdaptiveConcurrencyPolicy{}).AnyTimes()
 
 	lb := &listenerBuilder{
 		meshCatalog: mockCatalog,
@@ -292,6 +311,14 @@ func TestGetInboundMeshTCPFilterChain(t *testing.T) {
 
 	// Mock calls used to build the HTTP connection manager
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
 
 	lb := &listenerBuilder{
@@ -368,6 +395,12 @@ func TestGetInboundMeshTCPFilterChain(t *testing.T) {
 			for i, filter := range filterChain.Filters {
 				assert.Equal(filter.Name, tc.expectedFilterNames[i])
 			}
+
+			// The traffic is proxied to the local cluster for the port it was sent to
+			tcpProxy := &xds_tcp_proxy.TcpProxy{}
+			err = ptypes.UnmarshalAny(filterChain.Filters[len(filterChain.Filters)-1].GetTypedConfig(), tcpProxy)
+			assert.Nil(err)
+			assert.Equal(fmt.Sprintf("port-%d-local", tc.port), tcpProxy.GetCluster())
 		})
 	}
 }
@@ -380,7 +413,7 @@ func TestGetOutboundFilterChainMatchForService(t *testing.T) {
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
 
-	lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceAccount, mockConfigurator, nil)
+	lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceAccount, mockConfigurator, nil, false)
 
 	testCases := []struct {
 		name        string
@@ -723,7 +756,7 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 
 			mockMeshSpec.EXPECT().ListTrafficSplits().Return(tc.trafficSplits).Times(1)
 
-			lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceAccount, mockConfigurator, nil)
+			lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceAccount, mockConfigurator, nil, false)
 			filter, err := lb.getOutboundTCPFilter(tc.upstream)
 
 			assert.Equal(tc.expectError, err != nil)
@@ -738,3 +771,24 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestGetInboundPortFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+
+	lb := &listenerBuilder{}
+	filterChains := lb.getInboundPortFilterChains([]uint32{8080})
+
+	assert.Len(filterChains, 1)
+	assert.Equal("inbound-port-tcp-filter-chain:8080", filterChains[0].Name)
+	assert.Equal(uint32(8080), filterChains[0].FilterChainMatch.DestinationPort.GetValue())
+	assert.Empty(filterChains[0].FilterChainMatch.ServerNames)
+	assert.Nil(filterChains[0].TransportSocket)
+
+	assert.Len(filterChains[0].Filters, 1)
+	assert.Equal(wellknown.TCPProxy, filterChains[0].Filters[0].Name)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	err := ptypes.UnmarshalAny(filterChains[0].Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Equal("port-8080-local", tcpProxy.GetCluster())
+	assert.Equal("inbound-port-tcp-proxy.port-8080-local", tcpProxy.StatPrefix)
+}
@@ -2,6 +2,8 @@ package lds
 
 import (
 	"fmt"
+	"net"
+	"sort"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
@@ -21,6 +23,7 @@ const (
 	outboundEgressFilterChainName = "outbound-egress-filter-chain"
 	egressTCPProxyStatPrefix      = "egress-tcp-proxy"
 	singleIpv4Mask                = 32
+	singleIpv6Mask                = 128
 )
 
 func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error) {
@@ -28,7 +31,7 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 
 	listener := &xds_listener.Listener{
 		Name:             outboundListenerName,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyOutboundListenerPort),
+		Address:          envoy.GetListenerAddress(constants.EnvoyOutboundListenerPort, lb.ipv6),
 		TrafficDirection: xds_core.TrafficDirection_OUTBOUND,
 		FilterChains:     serviceFilterChains,
 		ListenerFilters: []*xds_listener.ListenerFilter{
@@ -43,13 +46,36 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	// Create filter chain for egress if egress is enabled
 	// This filter chain matches any traffic not filtered by allow rules, it will be treated as egress
 	// traffic when enabled
-	if lb.cfg.IsEgressEnabled() {
+	if lb.egressEnabled {
 		egressFilterChain, err := buildEgressFilterChain()
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting filter chain for Egress")
 			return nil, err
 		}
 		listener.DefaultFilterChain = egressFilterChain
+	} else if lb.egressAudit {
+		// In egress audit mode, the traffic not allowed by the Egress policies of the proxy is reported and still
+		// forwarded to its original destination
+		egressAuditFilterChain, err := lb.buildEgressAuditFilterChain()
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting filter chain for Egress audit mode")
+			return nil, err
+		}
+		listener.DefaultFilterChain = egressAuditFilterChain
+	}
+
+	// Create filter chains for the external destinations allowed by the Egress policies of the proxy
+	if egressPolicy := lb.meshCatalog.GetEgressPolicy(lb.svcAccount); egressPolicy != nil {
+		egressFilterChains, err := lb.getEgressFilterChains(egressPolicy)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting filter chains for Egress policies of proxy with identity %s", lb.svcAccount)
+			return nil, err
+		}
+		listener.FilterChains = append(listener.FilterChains, egressFilterChains...)
+
+		if tlsInspectorFilter := getEgressTLSInspectorFilter(egressPolicy); tlsInspectorFilter != nil {
+			listener.ListenerFilters = append(listener.ListenerFilters, tlsInspectorFilter)
+		}
 	}
 
 	if len(listener.FilterChains) == 0 && listener.DefaultFilterChain == nil {
@@ -63,10 +89,10 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	return listener, nil
 }
 
-func newInboundListener() *xds_listener.Listener {
+func newInboundListener(ipv6 bool) *xds_listener.Listener {
 	return &xds_listener.Listener{
 		Name:             inboundListenerName,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyInboundListenerPort),
+		Address:          envoy.GetListenerAddress(constants.EnvoyInboundListenerPort, ipv6),
 		TrafficDirection: xds_core.TrafficDirection_INBOUND,
 		FilterChains:     []*xds_listener.FilterChain{},
 		ListenerFilters: []*xds_listener.ListenerFilter{
@@ -83,7 +109,7 @@ func newInboundListener() *xds_listener.Listener {
 	}
 }
 
-func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_listener.Listener, error) {
+func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager, ipv6 bool) (*xds_listener.Listener, error) {
 	marshalledConnManager, err := ptypes.MarshalAny(connManager)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error marshalling HttpConnectionManager object")
@@ -93,7 +119,7 @@ func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_l
 	return &xds_listener.Listener{
 		Name:             prometheusListenerName,
 		TrafficDirection: xds_core.TrafficDirection_INBOUND,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyPrometheusInboundListenerPort),
+		Address:          envoy.GetListenerAddress(constants.EnvoyPrometheusInboundListenerPort, ipv6),
 		FilterChains: []*xds_listener.FilterChain{
 			{
 				Filters: []*xds_listener.Filter{
@@ -130,3 +156,20 @@ func buildEgressFilterChain() (*xds_listener.FilterChain, error) {
 		},
 	}, nil
 }
+
+// sortFilterChains sorts the filter chains of the given listener by name, so that the listener does not depend on the
+// iteration order of the maps some of them are built from. Envoy selects the filter chain of a connection by the
+// specificity of its match criteria, not by its position, so the order is not significant.
+func sortFilterChains(listener *xds_listener.Listener) {
+	sort.SliceStable(listener.FilterChains, func(i, j int) bool {
+		return listener.FilterChains[i].Name < listener.FilterChains[j].Name
+	})
+}
+
+// getSingleIPMask returns the prefix length of a CIDR range matching only the given IP address
+func getSingleIPMask(ip string) uint32 {
+	if parsedIP := net.ParseIP(ip); parsedIP != nil && parsedIP.To4() == nil {
+		return singleIpv6Mask
+	}
+	return singleIpv4Mask
+}
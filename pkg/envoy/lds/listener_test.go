@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
@@ -66,18 +67,25 @@ var _ = Describe("Construct inbound listeners", func() {
 
 	Context("Test creation of inbound listener", func() {
 		It("Tests the inbound listener config", func() {
-			listener := newInboundListener()
+			listener := newInboundListener(false)
 			Expect(listener.Address).To(Equal(envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyInboundListenerPort)))
 			Expect(len(listener.ListenerFilters)).To(Equal(2)) // TlsInspector, OriginalDestination listener filter
 			Expect(listener.ListenerFilters[0].Name).To(Equal(wellknown.TlsInspector))
 			Expect(listener.TrafficDirection).To(Equal(xds_core.TrafficDirection_INBOUND))
 		})
+
+		It("Tests the inbound listener config for an IPv6 pod", func() {
+			listener := newInboundListener(true)
+			Expect(listener.Address.GetSocketAddress().Address).To(Equal(constants.WildcardIPv6Addr))
+			Expect(listener.Address.GetSocketAddress().Ipv4Compat).To(BeTrue())
+			Expect(listener.Address.GetSocketAddress().GetPortValue()).To(Equal(uint32(constants.EnvoyInboundListenerPort)))
+		})
 	})
 
 	Context("Test creation of Prometheus listener", func() {
 		It("Tests the Prometheus listener config", func() {
 			connManager := getPrometheusConnectionManager()
-			listener, _ := buildPrometheusListener(connManager)
+			listener, _ := buildPrometheusListener(connManager, false)
 			Expect(listener.Address).To(Equal(envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyPrometheusInboundListenerPort)))
 			Expect(len(listener.ListenerFilters)).To(Equal(0)) //  no listener filters
 			Expect(listener.TrafficDirection).To(Equal(xds_core.TrafficDirection_INBOUND))
@@ -211,3 +219,34 @@ var _ = Describe("Test getHTTPConnectionManager", func() {
 		})
 	})
 })
+
+func TestGetSingleIPMask(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(uint32(singleIpv4Mask), getSingleIPMask("10.0.0.1"))
+	assert.Equal(uint32(singleIpv6Mask), getSingleIPMask("fd00::1"))
+	assert.Equal(uint32(singleIpv4Mask), getSingleIPMask("::ffff:10.0.0.1"))
+}
+
+func TestSortFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+
+	listener := &xds_listener.Listener{
+		FilterChains: []*xds_listener.FilterChain{
+			{Name: "outbound-mesh-http-filter-chain:default/bookstore-v2"},
+			{Name: "outbound-egress-filter-chain"},
+			{Name: "outbound-mesh-http-filter-chain:default/bookstore-v1"},
+		},
+	}
+	sortFilterChains(listener)
+
+	var names []string
+	for _, filterChain := range listener.FilterChains {
+		names = append(names, filterChain.Name)
+	}
+	assert.Equal([]string{
+		"outbound-egress-filter-chain",
+		"outbound-mesh-http-filter-chain:default/bookstore-v1",
+		"outbound-mesh-http-filter-chain:default/bookstore-v2",
+	}, names)
+}
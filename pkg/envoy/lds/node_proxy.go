@@ -0,0 +1,203 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	nodeProxyInboundListenerName        = "node-proxy-inbound-listener"
+	nodeProxyOutboundListenerName       = "node-proxy-outbound-listener"
+	nodeProxyInboundFilterChainPrefix   = "node-proxy-inbound-filter-chain"
+	nodeProxyOutboundFilterChainPrefix  = "node-proxy-outbound-filter-chain"
+	nodeProxyInboundTCPProxyStatPrefix  = "node-proxy-inbound-tcp-proxy"
+	nodeProxyOutboundTCPProxyStatPrefix = "node-proxy-outbound-tcp-proxy"
+)
+
+// NewNodeProxyResponse creates a new Listener Discovery Response for a per-node proxy.
+// The response builds 2 Listeners that handle L4 traffic on behalf of the node proxy mode pods on the proxy's node:
+// 1. Inbound listener to terminate mTLS for traffic destined to the pods
+// 2. Outbound listener to originate mTLS for traffic sent by the pods
+func NewNodeProxyResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	pods, err := meshCatalog.ListPodsForNodeProxy(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing pods for node proxy with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	resp := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeLDS),
+	}
+
+	var inboundFilterChains, outboundFilterChains []*xds_listener.FilterChain
+	for _, pod := range pods {
+		svcAccount := service.K8sServiceAccount{
+			Name:      pod.Spec.ServiceAccountName,
+			Namespace: pod.Namespace,
+		}
+		lb := newListenerBuilder(meshCatalog, svcAccount, cfg, nil, false)
+
+		if inboundFilterChain, err := lb.getNodeProxyInboundFilterChain(pod); err != nil {
+			log.Error().Err(err).Msgf("Error building node proxy inbound filter chain for pod %s/%s", pod.Namespace, pod.Name)
+		} else {
+			inboundFilterChains = append(inboundFilterChains, inboundFilterChain)
+		}
+
+		if outboundFilterChain, err := lb.getNodeProxyOutboundFilterChain(pod); err != nil {
+			log.Error().Err(err).Msgf("Error building node proxy outbound filter chain for pod %s/%s", pod.Namespace, pod.Name)
+		} else {
+			outboundFilterChains = append(outboundFilterChains, outboundFilterChain)
+		}
+	}
+
+	// Programming a listener with no filter chains is an error, so listeners are only
+	// programmed once there are pods in node proxy mode on the proxy's node.
+	if len(inboundFilterChains) > 0 {
+		inboundListener := newNodeProxyListener(nodeProxyInboundListenerName, constants.EnvoyInboundListenerPort, xds_core.TrafficDirection_INBOUND)
+		inboundListener.ListenerFilters = append([]*xds_listener.ListenerFilter{{Name: wellknown.TlsInspector}}, inboundListener.ListenerFilters...)
+		inboundListener.FilterChains = inboundFilterChains
+		sortFilterChains(inboundListener)
+		if marshalledInbound, err := ptypes.MarshalAny(inboundListener); err != nil {
+			log.Error().Err(err).Msgf("Error marshalling inbound listener config for node proxy with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		} else {
+			resp.Resources = append(resp.Resources, marshalledInbound)
+		}
+	}
+
+	if len(outboundFilterChains) > 0 {
+		outboundListener := newNodeProxyListener(nodeProxyOutboundListenerName, constants.EnvoyOutboundListenerPort, xds_core.TrafficDirection_OUTBOUND)
+		outboundListener.FilterChains = outboundFilterChains
+		sortFilterChains(outboundListener)
+		if marshalledOutbound, err := ptypes.MarshalAny(outboundListener); err != nil {
+			log.Error().Err(err).Msgf("Error marshalling outbound listener config for node proxy with XDS Certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		} else {
+			resp.Resources = append(resp.Resources, marshalledOutbound)
+		}
+	}
+
+	return resp, nil
+}
+
+func newNodeProxyListener(name string, port uint32, direction xds_core.TrafficDirection) *xds_listener.Listener {
+	return &xds_listener.Listener{
+		Name:             name,
+		Address:          envoy.GetListenerAddress(port, false),
+		TrafficDirection: direction,
+		ListenerFilters: []*xds_listener.ListenerFilter{
+			{
+				// The OriginalDestination ListenerFilter is used to restore the original destination address
+				// of the traffic redirected to the node proxy by the node's traffic redirection rules.
+				Name: wellknown.OriginalDestination,
+			},
+		},
+	}
+}
+
+// getNodeProxyInboundFilterChain returns a filter chain that terminates mTLS for traffic destined to the given pod
+// using the certificate of the pod's service account, and forwards it to its original destination.
+func (lb *listenerBuilder) getNodeProxyInboundFilterChain(pod *corev1.Pod) (*xds_listener.FilterChain, error) {
+	var filters []*xds_listener.Filter
+
+	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter in the list of filters.
+	if !lb.cfg.IsPermissiveTrafficPolicyMode() {
+		rbacFilter, err := lb.buildRBACFilter()
+		if err != nil {
+			log.Error().Err(err).Msgf("Error applying RBAC filter for pod %s/%s", pod.Namespace, pod.Name)
+			return nil, err
+		}
+		filters = append(filters, rbacFilter)
+	}
+
+	tcpProxyFilter, err := newNodeProxyTCPProxyFilter(nodeProxyInboundTCPProxyStatPrefix, envoy.NodeProxyInboundCluster)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, tcpProxyFilter)
+
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.svcAccount, true /* mTLS */))
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling DownstreamTLSContext for pod %s/%s", pod.Namespace, pod.Name)
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s:%s/%s", nodeProxyInboundFilterChainPrefix, pod.Namespace, pod.Name),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			// Match traffic destined to the pod
+			PrefixRanges: []*xds_core.CidrRange{getSingleIPCidrRange(pod.Status.PodIP)},
+
+			// Only match when transport protocol is TLS
+			TransportProtocol: envoy.TransportProtocolTLS,
+
+			// In-mesh proxies will advertise this, set in the UpstreamTlsContext
+			ApplicationProtocols: envoy.ALPNInMesh,
+		},
+		Filters: filters,
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		},
+	}, nil
+}
+
+// getNodeProxyOutboundFilterChain returns a filter chain that matches traffic sent by the given pod and forwards it
+// to its original destination over mTLS, using the certificate of the pod's service account.
+func (lb *listenerBuilder) getNodeProxyOutboundFilterChain(pod *corev1.Pod) (*xds_listener.FilterChain, error) {
+	tcpProxyFilter, err := newNodeProxyTCPProxyFilter(nodeProxyOutboundTCPProxyStatPrefix, envoy.GetNodeProxyOutboundClusterName(lb.svcAccount))
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s:%s/%s", nodeProxyOutboundFilterChainPrefix, pod.Namespace, pod.Name),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			// Match traffic sent by the pod
+			SourcePrefixRanges: []*xds_core.CidrRange{getSingleIPCidrRange(pod.Status.PodIP)},
+		},
+		Filters: []*xds_listener.Filter{tcpProxyFilter},
+	}, nil
+}
+
+func newNodeProxyTCPProxyFilter(statPrefix string, cluster string) (*xds_listener.Filter, error) {
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", statPrefix, cluster),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: cluster},
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling TcpProxy object for cluster %s", cluster)
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.TCPProxy,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+	}, nil
+}
+
+// getSingleIPCidrRange returns a CIDR range matching only the given IP address
+func getSingleIPCidrRange(ip string) *xds_core.CidrRange {
+	return &xds_core.CidrRange{
+		AddressPrefix: ip,
+		PrefixLen: &wrapperspb.UInt32Value{
+			Value: getSingleIPMask(ip),
+		},
+	}
+}
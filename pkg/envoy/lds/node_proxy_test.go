@@ -0,0 +1,90 @@
+package lds
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestNewNodeProxyResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	proxy := envoy.NewProxy("", "", nil)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookbuyer-abcde",
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "bookbuyer",
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.244.1.1",
+		},
+	}
+
+	// No node proxy mode pods on the node
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return(nil, nil)
+	actual, err := NewNodeProxyResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	assert.Nil(err)
+	assert.Empty(actual.Resources)
+
+	// A node proxy mode pod on the node
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return([]*corev1.Pod{pod}, nil)
+	mockCatalog.EXPECT().ListInboundTrafficTargetsWithRoutes(service.K8sServiceAccount{Name: "bookbuyer", Namespace: "default"}).Return([]trafficpolicy.TrafficTargetWithRoutes{}, nil)
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+
+	actual, err = NewNodeProxyResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
+	assert.Nil(err)
+	assert.Len(actual.Resources, 2)
+
+	listener := xds_listener.Listener{}
+
+	// validating inbound listener
+	err = ptypes.UnmarshalAny(actual.Resources[0], &listener)
+	assert.Nil(err)
+	assert.Equal(nodeProxyInboundListenerName, listener.Name)
+	assert.Equal(xds_core.TrafficDirection_INBOUND, listener.TrafficDirection)
+	assert.Len(listener.ListenerFilters, 2)
+	assert.Equal(wellknown.TlsInspector, listener.ListenerFilters[0].Name)
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[1].Name)
+	assert.Len(listener.FilterChains, 1)
+	assert.Equal("10.244.1.1", listener.FilterChains[0].FilterChainMatch.PrefixRanges[0].AddressPrefix)
+	assert.Equal(uint32(32), listener.FilterChains[0].FilterChainMatch.PrefixRanges[0].PrefixLen.Value)
+	assert.Equal(envoy.TransportProtocolTLS, listener.FilterChains[0].FilterChainMatch.TransportProtocol)
+	assert.NotNil(listener.FilterChains[0].TransportSocket)
+	assert.Len(listener.FilterChains[0].Filters, 2)
+	assert.Equal(wellknown.RoleBasedAccessControl, listener.FilterChains[0].Filters[0].Name)
+	assert.Equal(wellknown.TCPProxy, listener.FilterChains[0].Filters[1].Name)
+
+	// validating outbound listener
+	err = ptypes.UnmarshalAny(actual.Resources[1], &listener)
+	assert.Nil(err)
+	assert.Equal(nodeProxyOutboundListenerName, listener.Name)
+	assert.Equal(xds_core.TrafficDirection_OUTBOUND, listener.TrafficDirection)
+	assert.Len(listener.ListenerFilters, 1)
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[0].Name)
+	assert.Len(listener.FilterChains, 1)
+	assert.Equal("10.244.1.1", listener.FilterChains[0].FilterChainMatch.SourcePrefixRanges[0].AddressPrefix)
+	assert.Nil(listener.FilterChains[0].TransportSocket)
+	assert.Len(listener.FilterChains[0].Filters, 1)
+	assert.Equal(wellknown.TCPProxy, listener.FilterChains[0].Filters[0].Name)
+}
@@ -0,0 +1,88 @@
+package lds
+
+import (
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/proxy_protocol/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// getInboundProxyProtocolPorts returns the target ports of the given services on which the PROXY protocol is required
+func (lb *listenerBuilder) getInboundProxyProtocolPorts(svcList []service.MeshService) []uint32 {
+	var ports []uint32
+	// Multiple services selecting the same pod can share a target port
+	seen := make(map[uint32]bool)
+	for _, svc := range svcList {
+		for _, port := range lb.meshCatalog.GetInboundProxyProtocolPorts(svc) {
+			if !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
+
+// setInboundProxyProtocol configures the given inbound listener to require the PROXY protocol on connections to the given
+// destination ports. The PROXY protocol header precedes the TLS handshake, so it is read after the original destination
+// is restored, which the port match relies on, and before the TLS inspector.
+func setInboundProxyProtocol(listener *xds_listener.Listener, ports []uint32) error {
+	if len(ports) == 0 {
+		return nil
+	}
+
+	marshalledProxyProtocol, err := ptypes.MarshalAny(&xds_proxy_protocol.ProxyProtocol{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling ProxyProtocol listener filter")
+		return err
+	}
+
+	proxyProtocolFilter := &xds_listener.ListenerFilter{
+		Name:       wellknown.ProxyProtocol,
+		ConfigType: &xds_listener.ListenerFilter_TypedConfig{TypedConfig: marshalledProxyProtocol},
+		// The filter is disabled for connections to any other port
+		FilterDisabled: getOtherDestinationPortsPredicate(ports),
+	}
+
+	var listenerFilters, postProxyProtocolFilters []*xds_listener.ListenerFilter
+	for _, filter := range listener.ListenerFilters {
+		if filter.Name == wellknown.OriginalDestination {
+			listenerFilters = append(listenerFilters, filter)
+		} else {
+			postProxyProtocolFilters = append(postProxyProtocolFilters, filter)
+		}
+	}
+	listenerFilters = append(listenerFilters, proxyProtocolFilter)
+	listener.ListenerFilters = append(listenerFilters, postProxyProtocolFilters...)
+
+	return nil
+}
+
+// getOtherDestinationPortsPredicate returns a listener filter predicate matching connections to any destination port
+// other than the given ports, used to only enable a listener filter on the given ports
+func getOtherDestinationPortsPredicate(ports []uint32) *xds_listener.ListenerFilterChainMatchPredicate {
+	var portRules []*xds_listener.ListenerFilterChainMatchPredicate
+	for _, port := range ports {
+		portRules = append(portRules, &xds_listener.ListenerFilterChainMatchPredicate{
+			Rule: &xds_listener.ListenerFilterChainMatchPredicate_DestinationPortRange{
+				DestinationPortRange: &xds_type.Int32Range{
+					Start: int32(port),
+					End:   int32(port) + 1, // exclusive
+				},
+			},
+		})
+	}
+
+	return &xds_listener.ListenerFilterChainMatchPredicate{
+		Rule: &xds_listener.ListenerFilterChainMatchPredicate_NotMatch{
+			NotMatch: &xds_listener.ListenerFilterChainMatchPredicate{
+				Rule: &xds_listener.ListenerFilterChainMatchPredicate_OrMatch{
+					OrMatch: &xds_listener.ListenerFilterChainMatchPredicate_MatchSet{Rules: portRules},
+				},
+			},
+		},
+	}
+}
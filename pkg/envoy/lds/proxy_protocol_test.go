@@ -0,0 +1,58 @@
+package lds
+
+import (
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetInboundProxyProtocolPorts(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	lb := &listenerBuilder{meshCatalog: mockCatalog}
+
+	// Ports shared by multiple services selecting the pod are only returned once
+	mockCatalog.EXPECT().GetInboundProxyProtocolPorts(tests.BookstoreV1Service).Return([]uint32{80, 8443}).Times(1)
+	mockCatalog.EXPECT().GetInboundProxyProtocolPorts(tests.BookstoreApexService).Return([]uint32{80}).Times(1)
+
+	ports := lb.getInboundProxyProtocolPorts([]service.MeshService{tests.BookstoreV1Service, tests.BookstoreApexService})
+	assert.Equal([]uint32{80, 8443}, ports)
+}
+
+func TestSetInboundProxyProtocol(t *testing.T) {
+	assert := tassert.New(t)
+
+	// No ports leave the listener filters unchanged
+	listener := newInboundListener(false)
+	err := setInboundProxyProtocol(listener, nil)
+	assert.Nil(err)
+	assert.Len(listener.ListenerFilters, 2)
+	assert.Equal(wellknown.TlsInspector, listener.ListenerFilters[0].Name)
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[1].Name)
+
+	listener = newInboundListener(false)
+	err = setInboundProxyProtocol(listener, []uint32{80, 8443})
+	assert.Nil(err)
+	assert.Len(listener.ListenerFilters, 3)
+	// The PROXY protocol is read after the original destination is restored, and before the TLS inspector
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[0].Name)
+	assert.Equal(wellknown.ProxyProtocol, listener.ListenerFilters[1].Name)
+	assert.Equal(wellknown.TlsInspector, listener.ListenerFilters[2].Name)
+
+	// The filter is disabled for connections to ports other than the given ports
+	portRules := listener.ListenerFilters[1].FilterDisabled.GetNotMatch().GetOrMatch().GetRules()
+	assert.Len(portRules, 2)
+	assert.Equal(int32(80), portRules[0].GetDestinationPortRange().Start)
+	assert.Equal(int32(81), portRules[0].GetDestinationPortRange().End)
+	assert.Equal(int32(8443), portRules[1].GetDestinationPortRange().Start)
+	assert.Equal(int32(8444), portRules[1].GetDestinationPortRange().End)
+}
@@ -47,12 +47,25 @@ func (lb *listenerBuilder) buildInboundRBACPolicies() (*xds_network_rbac.RBAC, e
 	}
 
 	rbacPolicies := make(map[string]*xds_rbac.Policy)
+	// The policies of the TrafficTargets in shadow mode allow any principal, and are only evaluated as shadow rules
+	enforcedRBACPolicies := make(map[string]*xds_rbac.Policy)
 	// Build an RBAC policies based on SMI TrafficTarget policies
 	for _, targetPolicy := range trafficTargets {
-		if policy, err := buildRBACPolicyFromTrafficTarget(targetPolicy); err != nil {
+		policy, err := buildRBACPolicyFromTrafficTarget(targetPolicy)
+		if err != nil {
 			log.Error().Err(err).Msgf("Error building RBAC policy for proxy identity %s from TrafficTarget %s", proxyIdentity, targetPolicy.Name)
-		} else {
-			rbacPolicies[targetPolicy.Name] = policy
+			continue
+		}
+		rbacPolicies[targetPolicy.Name] = policy
+		enforcedRBACPolicies[targetPolicy.Name] = policy
+
+		if targetPolicy.Shadow {
+			shadowTargetPolicy := targetPolicy
+			shadowTargetPolicy.Sources = nil
+			if enforcedRBACPolicies[targetPolicy.Name], err = buildRBACPolicyFromTrafficTarget(shadowTargetPolicy); err != nil {
+				log.Error().Err(err).Msgf("Error building RBAC policy for proxy identity %s from TrafficTarget %s in shadow mode", proxyIdentity, targetPolicy.Name)
+				delete(enforcedRBACPolicies, targetPolicy.Name)
+			}
 		}
 	}
 
@@ -63,6 +76,13 @@ func (lb *listenerBuilder) buildInboundRBACPolicies() (*xds_network_rbac.RBAC, e
 		StatPrefix: "network-", // will be displayed as network-rbac.<path>
 		Rules: &xds_rbac.RBAC{
 			Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
+			Policies: enforcedRBACPolicies,
+		},
+		// Shadow rules record the policy that matched the connection and the result of the RBAC engine in the dynamic
+		// metadata, so that they can be included in access logs. They mirror the enforced rules, except for the
+		// TrafficTargets in shadow mode, whose result is recorded without being enforced.
+		ShadowRules: &xds_rbac.RBAC{
+			Action:   xds_rbac.RBAC_ALLOW,
 			Policies: rbacPolicies,
 		},
 	}
@@ -84,6 +104,7 @@ func buildRBACPolicyFromTrafficTarget(trafficTarget trafficpolicy.TrafficTargetW
 		}
 		principalRuleList = append(principalRuleList, principalRule)
 	}
+	// A traffic target without sources allows any principal
 	policy.Principals = principalRuleList
 
 	// Create the list of permissions for this policy
@@ -231,10 +231,60 @@ func TestBuildInboundRBACPolicies(t *testing.T) {
 				actualPolicyKeys = append(actualPolicyKeys, key)
 			}
 			assert.ElementsMatch(tc.expectedPolicyKeys, actualPolicyKeys)
+
+			// The shadow rules mirror the enforced rules
+			assert.Equal(policy.Rules, policy.ShadowRules)
 		})
 	}
 }
 
+func TestBuildInboundRBACPoliciesWithShadowTrafficTarget(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	proxySvcAccount := service.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		svcAccount:  proxySvcAccount,
+	}
+
+	mockCatalog.EXPECT().ListInboundTrafficTargetsWithRoutes(proxySvcAccount).Return([]trafficpolicy.TrafficTargetWithRoutes{
+		{
+			Name:        "ns-1/enforced",
+			Destination: identity.ServiceIdentity("sa-1.ns-1.cluster.local"),
+			Sources: []identity.ServiceIdentity{
+				identity.ServiceIdentity("sa-2.ns-2.cluster.local"),
+			},
+		},
+		{
+			Name:        "ns-1/shadow",
+			Destination: identity.ServiceIdentity("sa-1.ns-1.cluster.local"),
+			Sources: []identity.ServiceIdentity{
+				identity.ServiceIdentity("sa-3.ns-3.cluster.local"),
+			},
+			Shadow: true,
+		},
+	}, nil).Times(1)
+
+	policy, err := lb.buildInboundRBACPolicies()
+	assert.Nil(err)
+
+	anyPrincipal := []*xds_rbac.Principal{{Identifier: &xds_rbac.Principal_Any{Any: true}}}
+
+	// The TrafficTarget in shadow mode allows any principal in the enforced rules
+	assert.Len(policy.Rules.Policies, 2)
+	assert.NotEqual(anyPrincipal, policy.Rules.Policies["ns-1/enforced"].Principals)
+	assert.Equal(anyPrincipal, policy.Rules.Policies["ns-1/shadow"].Principals)
+
+	// The shadow rules only allow the sources of the TrafficTargets
+	assert.Len(policy.ShadowRules.Policies, 2)
+	assert.Equal(policy.Rules.Policies["ns-1/enforced"], policy.ShadowRules.Policies["ns-1/enforced"])
+	assert.NotEqual(anyPrincipal, policy.ShadowRules.Policies["ns-1/shadow"].Principals)
+}
+
 func TestBuildRBACFilter(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
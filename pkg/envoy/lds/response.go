@@ -1,12 +1,15 @@
 package lds
 
 import (
+	"fmt"
+
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/featureflags"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -17,9 +20,13 @@ import (
 // 1. Inbound listener to handle incoming traffic
 // 2. Outbound listener to handle outgoing traffic
 // 3. Prometheus listener for metrics
+// The egress HTTP proxy listener is also built when the Egress policies of the proxy allow hosts to be reached through it.
 func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
 	svcList, err := meshCatalog.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
-	if err != nil {
+	if err != nil && proxy.IsDevProxy() {
+		// A dev proxy is not backed by a Pod, so it does not front any service
+		svcList = nil
+	} else if err != nil {
 		log.Error().Err(err).Msgf("Error looking up MeshService for Envoy certificate SerialNumber=%s on Pod with UID=%s", proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		return nil, err
 	}
@@ -30,16 +37,17 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		return nil, err
 	}
 
-	resp := &xds_discovery.DiscoveryResponse{
-		TypeUrl: string(envoy.TypeLDS),
-	}
+	var listeners []envoy.NamedResource
 
 	var statsHeaders map[string]string
 	if featureflags.IsWASMStatsEnabled() {
 		statsHeaders = proxy.StatsHeaders()
 	}
 
-	lb := newListenerBuilder(meshCatalog, svcAccount, cfg, statsHeaders)
+	lb := newListenerBuilder(meshCatalog, svcAccount, cfg, statsHeaders, proxy.HasIPv6PodIP())
+	lb.ingressBandwidthLimitKiBps = lb.getIngressBandwidthLimit(svcList)
+	lb.egressEnabled = meshCatalog.GetProxyOverrides(proxy).Bool(constants.EgressAnnotation, cfg.IsEgressEnabled())
+	lb.egressAudit = meshCatalog.IsEgressAuditEnabled(proxy)
 
 	// --- OUTBOUND -------------------
 	outboundListener, err := lb.newOutboundListener()
@@ -53,17 +61,31 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 			log.Debug().Msgf("Not programming Outbound listener for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
 				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		} else {
-			if marshalledOutbound, err := ptypes.MarshalAny(outboundListener); err != nil {
-				log.Error().Err(err).Msgf("Failed to marshal outbound listener config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
-					proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
-			} else {
-				resp.Resources = append(resp.Resources, marshalledOutbound)
-			}
+			sortFilterChains(outboundListener)
+			listeners = append(listeners, envoy.NamedResource{
+				Name:     outboundListener.Name,
+				Source:   fmt.Sprintf("upstream services of identity %s", svcAccount),
+				Resource: outboundListener,
+			})
+		}
+	}
+
+	// --- EGRESS HTTP PROXY -------------------
+	if egressPolicy := meshCatalog.GetEgressPolicy(svcAccount); egressPolicy.HasHTTPProxyHosts() {
+		if httpProxyListener, err := lb.newEgressHTTPProxyListener(); err != nil {
+			log.Error().Err(err).Msgf("Error making egress HTTP proxy listener config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+		} else {
+			listeners = append(listeners, envoy.NamedResource{
+				Name:     httpProxyListener.Name,
+				Source:   fmt.Sprintf("Egress policies %v", egressPolicy.Names),
+				Resource: httpProxyListener,
+			})
 		}
 	}
 
 	// --- INBOUND -------------------
-	inboundListener := newInboundListener()
+	inboundListener := newInboundListener(proxy.HasIPv6PodIP())
 	// Create inbound filter chains per service behind proxy
 	for _, proxyService := range svcList {
 		// Create in-mesh filter chains
@@ -86,41 +108,76 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		}
 	}
 
-	if len(inboundListener.FilterChains) > 0 {
-		// Inbound filter chains can be empty if the there both ingress and in-mesh policies are not configured.
-		// Configuring a listener without a filter chain is an error.
-		if marshalledInbound, err := ptypes.MarshalAny(inboundListener); err != nil {
-			log.Error().Err(err).Msgf("Error marshalling inbound listener config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+	// Traffic arriving directly from outside the mesh on a NodePort or LoadBalancer is plaintext and does not match
+	// the in-mesh filter chains, so it is rejected unless the service explicitly allows it.
+	inboundListener.FilterChains = append(inboundListener.FilterChains, lb.getExternalPlaintextFilterChains(svcList)...)
+
+	// Connections forwarded by L4 load balancers carry the original client address in the PROXY protocol header
+	if err := setInboundProxyProtocol(inboundListener, lb.getInboundProxyProtocolPorts(svcList)); err != nil {
+		log.Error().Err(err).Msgf("Error configuring PROXY protocol on inbound listener for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+			proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+	}
+
+	// A pod that is not selected by any service does not have in-mesh filter chains. In permissive mode,
+	// allow inbound traffic on the ports declared by its containers.
+	if len(svcList) == 0 && cfg.IsPermissiveTrafficPolicyMode() && !proxy.IsDevProxy() {
+		if ports, err := meshCatalog.ListContainerPortsFromEnvoyCertificate(proxy.GetCertificateCommonName()); err != nil {
+			log.Error().Err(err).Msgf("Error looking up container ports for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
 				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		} else {
-			resp.Resources = append(resp.Resources, marshalledInbound)
+			inboundListener.FilterChains = append(inboundListener.FilterChains, lb.getInboundPortFilterChains(ports)...)
 		}
 	}
 
+	if len(inboundListener.FilterChains) > 0 {
+		// Inbound filter chains can be empty if the there both ingress and in-mesh policies are not configured.
+		// Configuring a listener without a filter chain is an error.
+		sortFilterChains(inboundListener)
+		listeners = append(listeners, envoy.NamedResource{
+			Name:     inboundListener.Name,
+			Source:   fmt.Sprintf("services %v", svcList),
+			Resource: inboundListener,
+		})
+	}
+
 	if cfg.IsPrometheusScrapingEnabled() {
 		// Build Prometheus listener config
 		prometheusConnManager := getPrometheusConnectionManager()
-		if prometheusListener, err := buildPrometheusListener(prometheusConnManager); err != nil {
+		if prometheusListener, err := buildPrometheusListener(prometheusConnManager, proxy.HasIPv6PodIP()); err != nil {
 			log.Error().Err(err).Msgf("Error building Prometheus listener config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
 				proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
 		} else {
-			if marshalledPrometheus, err := ptypes.MarshalAny(prometheusListener); err != nil {
-				log.Error().Err(err).Msgf("Error marshalling Prometheus listener config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
-					proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
-			} else {
-				resp.Resources = append(resp.Resources, marshalledPrometheus)
-			}
+			listeners = append(listeners, envoy.NamedResource{
+				Name:     prometheusListener.Name,
+				Source:   "Prometheus scraping",
+				Resource: prometheusListener,
+			})
+		}
+	}
+
+	resp := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeLDS),
+	}
+
+	// Listeners with colliding names are dropped as Envoy would reject the response
+	for _, listener := range envoy.RemoveConflictingResources(proxy, envoy.ListenerResourceKind, listeners) {
+		if marshalledListener, err := ptypes.MarshalAny(listener.Resource); err != nil {
+			log.Error().Err(err).Msgf("Error marshalling listener %s config for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
+				listener.Name, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+		} else {
+			resp.Resources = append(resp.Resources, marshalledListener)
 		}
 	}
 
 	return resp, nil
 }
 
-func newListenerBuilder(meshCatalog catalog.MeshCataloger, svcAccount service.K8sServiceAccount, cfg configurator.Configurator, statsHeaders map[string]string) *listenerBuilder {
+func newListenerBuilder(meshCatalog catalog.MeshCataloger, svcAccount service.K8sServiceAccount, cfg configurator.Configurator, statsHeaders map[string]string, ipv6 bool) *listenerBuilder {
 	return &listenerBuilder{
 		meshCatalog:  meshCatalog,
 		svcAccount:   svcAccount,
 		cfg:          cfg,
 		statsHeaders: statsHeaders,
+		ipv6:         ipv6,
 	}
 }
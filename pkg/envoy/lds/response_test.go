@@ -3,6 +3,7 @@ package lds
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
@@ -67,6 +68,14 @@ func TestListenerConfiguration(t *testing.T) {
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().IsPrometheusScrapingEnabled().Return(true).AnyTimes()
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().IsEgressEnabled().Return(true).AnyTimes()
 
 	actual, err := NewResponse(meshCatalog, proxy, nil, mockConfigurator, nil)
@@ -18,4 +18,17 @@ type listenerBuilder struct {
 	meshCatalog  catalog.MeshCataloger
 	cfg          configurator.Configurator
 	statsHeaders map[string]string
+
+	// ipv6 is true if the primary IP of the proxy's pod is an IPv6 address
+	ipv6 bool
+
+	// ingressBandwidthLimitKiBps limits the rate of the responses received by the services of the proxy, 0 if unlimited
+	ingressBandwidthLimitKiBps uint64
+
+	// egressEnabled is true if the proxy allows egress traffic to any destination outside the mesh
+	egressEnabled bool
+
+	// egressAudit is true if the egress traffic of the proxy not allowed by its Egress policies is reported rather than
+	// denied, as its Egress policies are evaluated in audit mode
+	egressAudit bool
 }
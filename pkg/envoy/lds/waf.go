@@ -0,0 +1,112 @@
+package lds
+
+import (
+	"encoding/json"
+	"time"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_wasm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/wasm/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_wasm_ext "github.com/envoyproxy/go-control-plane/envoy/extensions/wasm/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// wafVMID is the ID of the WASM VM running the WAF module, shared by the WAF filters of all the services of a proxy
+	wafVMID = "osm-waf"
+
+	// wafModuleFetchTimeout is the timeout used by Envoy to fetch the WAF module
+	wafModuleFetchTimeout = 30 * time.Second
+)
+
+// wafPluginConfig is the configuration of the Coraza WASM plugin
+type wafPluginConfig struct {
+	DirectivesMap     map[string][]string `json:"directives_map"`
+	DefaultDirectives string              `json:"default_directives"`
+}
+
+// getWAFFilter returns the WASM filter enforcing the WAF ruleset referenced by the given service on its inbound HTTP traffic.
+// No filter is returned if the WAF module is not configured or the service does not enable the WAF.
+func (lb *listenerBuilder) getWAFFilter(proxyService service.MeshService) (*xds_hcm.HttpFilter, error) {
+	moduleURL := lb.cfg.GetWAFModuleURL()
+	if moduleURL == "" {
+		return nil, nil
+	}
+
+	ruleset, err := lb.meshCatalog.GetWAFRulesetForService(proxyService)
+	if err != nil {
+		return nil, err
+	}
+	if ruleset == "" {
+		return nil, nil
+	}
+
+	moduleSHA256 := lb.cfg.GetWAFModuleSHA256()
+	if moduleSHA256 == "" {
+		return nil, errWAFModuleChecksumNotSet
+	}
+
+	return getWAFFilter(proxyService, ruleset, moduleURL, moduleSHA256)
+}
+
+func getWAFFilter(proxyService service.MeshService, ruleset, moduleURL, moduleSHA256 string) (*xds_hcm.HttpFilter, error) {
+	pluginConfig, err := json.Marshal(wafPluginConfig{
+		DirectivesMap:     map[string][]string{proxyService.String(): {ruleset}},
+		DefaultDirectives: proxyService.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling WAF plugin config")
+	}
+
+	pluginConfigAny, err := ptypes.MarshalAny(&wrappers.StringValue{Value: string(pluginConfig)})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling WAF plugin config")
+	}
+
+	wasmPlug := &xds_wasm.Wasm{
+		Config: &xds_wasm_ext.PluginConfig{
+			Name:          "waf." + proxyService.String(),
+			Configuration: pluginConfigAny,
+			// Reject requests rather than letting them through unprotected if the WAF module fails
+			FailOpen: false,
+			Vm: &xds_wasm_ext.PluginConfig_VmConfig{
+				VmConfig: &xds_wasm_ext.VmConfig{
+					VmId:    wafVMID,
+					Runtime: "envoy.wasm.runtime.v8",
+					Code: &xds_core.AsyncDataSource{
+						Specifier: &xds_core.AsyncDataSource_Remote{
+							Remote: &xds_core.RemoteDataSource{
+								HttpUri: &xds_core.HttpUri{
+									Uri: moduleURL,
+									HttpUpstreamType: &xds_core.HttpUri_Cluster{
+										Cluster: constants.EnvoyWAFModuleCluster,
+									},
+									Timeout: ptypes.DurationProto(wafModuleFetchTimeout),
+								},
+								Sha256: moduleSHA256,
+							},
+						},
+					},
+					AllowPrecompiled: true,
+				},
+			},
+		},
+	}
+
+	wasmAny, err := ptypes.MarshalAny(wasmPlug)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling WAF Wasm config")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: "envoy.filters.http.wasm",
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: wasmAny,
+		},
+	}, nil
+}
@@ -0,0 +1,152 @@
+package lds
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	xds_wasm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/wasm/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	testWAFModuleURL    = "https://example.com/coraza.wasm"
+	testWAFModuleSHA256 = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testWAFRuleset      = "SecRuleEngine On\nSecRule ARGS \"@contains attack\" \"id:1,phase:2,deny,status:403\""
+)
+
+func TestGetWAFFilter(t *testing.T) {
+	proxyService := tests.BookstoreV1Service
+
+	testCases := []struct {
+		name           string
+		moduleURL      string
+		moduleSHA256   string
+		ruleset        string
+		expectedFilter bool
+		expectedError  error
+	}{
+		{
+			name:           "WAF module not configured",
+			moduleURL:      "",
+			expectedFilter: false,
+		},
+		{
+			name:           "service without WAF ruleset",
+			moduleURL:      testWAFModuleURL,
+			moduleSHA256:   testWAFModuleSHA256,
+			ruleset:        "",
+			expectedFilter: false,
+		},
+		{
+			name:           "service with WAF ruleset",
+			moduleURL:      testWAFModuleURL,
+			moduleSHA256:   testWAFModuleSHA256,
+			ruleset:        testWAFRuleset,
+			expectedFilter: true,
+		},
+		{
+			name:          "WAF module checksum not configured",
+			moduleURL:     testWAFModuleURL,
+			moduleSHA256:  "",
+			ruleset:       testWAFRuleset,
+			expectedError: errWAFModuleChecksumNotSet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetWAFModuleURL().Return(tc.moduleURL).AnyTimes()
+			mockConfigurator.EXPECT().GetWAFModuleSHA256().Return(tc.moduleSHA256).AnyTimes()
+			mockCatalog.EXPECT().GetWAFRulesetForService(proxyService).Return(tc.ruleset, nil).AnyTimes()
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+				cfg:         mockConfigurator,
+			}
+
+			filter, err := lb.getWAFFilter(proxyService)
+			assert.Equal(tc.expectedError, err)
+			assert.Equal(tc.expectedFilter, filter != nil)
+			if filter == nil {
+				return
+			}
+
+			wasmFilter := &xds_wasm.Wasm{}
+			assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), wasmFilter))
+			assert.False(wasmFilter.Config.FailOpen)
+
+			remote := wasmFilter.Config.GetVmConfig().GetCode().GetRemote()
+			assert.Equal(testWAFModuleURL, remote.HttpUri.Uri)
+			assert.Equal(constants.EnvoyWAFModuleCluster, remote.HttpUri.GetCluster())
+			assert.Equal(testWAFModuleSHA256, remote.Sha256)
+
+			pluginConfigStr := &wrappers.StringValue{}
+			assert.Nil(ptypes.UnmarshalAny(wasmFilter.Config.Configuration, pluginConfigStr))
+			var pluginConfig wafPluginConfig
+			assert.Nil(json.Unmarshal([]byte(pluginConfigStr.Value), &pluginConfig))
+			assert.Equal(proxyService.String(), pluginConfig.DefaultDirectives)
+			assert.Equal([]string{testWAFRuleset}, pluginConfig.DirectivesMap[proxyService.String()])
+		})
+	}
+}
+
+func TestGetInboundHTTPFiltersWithWAF(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyService := tests.BookstoreV1Service
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleURL().Return(testWAFModuleURL).AnyTimes()
+	mockConfigurator.EXPECT().GetWAFModuleSHA256().Return(testWAFModuleSHA256).AnyTimes()
+	mockConfigurator.EXPECT().GetForwardClientCertDetails().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetXFFNumTrustedHops().Return(uint32(0)).AnyTimes()
+	mockConfigurator.EXPECT().UseRemoteAddress().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().SkipXFFAppend().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsRBACDenyReportingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTrafficTargetShadowDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsPolicyRecorderEnabled().Return(false).AnyTimes()
+	mockCatalog.EXPECT().GetWAFRulesetForService(proxyService).Return(testWAFRuleset, nil).Times(1)
+	mockCatalog.EXPECT().GetBandwidthLimitForService(proxyService).Return(trafficpolicy.BandwidthLimit{}).Times(1)
+	mockCatalog.EXPECT().GetAdaptiveConcurrencyPolicy(proxyService).Return(trafficpolicy.AdaptiveConcurrencyPolicy{}).Times(1)
+
+	lb := &listenerBuilder{
+		meshCatalog: mockCatalog,
+		cfg:         mockConfigurator,
+	}
+
+	filters, err := lb.getInboundHTTPFilters(proxyService)
+	assert.Nil(err)
+	assert.Len(filters, 1)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filters[0].GetTypedConfig(), connManager))
+
+	var filterNames []string
+	for _, filter := range connManager.HttpFilters {
+		filterNames = append(filterNames, filter.Name)
+	}
+	assert.Equal([]string{wellknown.HTTPRoleBasedAccessControl, "envoy.filters.http.wasm", wellknown.Router}, filterNames)
+}
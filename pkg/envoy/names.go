@@ -0,0 +1,114 @@
+package envoy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxNameLength is the maximum length of the names generated for policies, virtual hosts and routes. Longer names,
+	// such as the names built from long ingress hosts and paths, are bounded with BoundedName.
+	MaxNameLength = 128
+
+	// boundedNameHashLength is the number of hex characters of the hash suffixing a bounded name
+	boundedNameHashLength = 16
+
+	// boundedNameRetention is how long the name a bounded name was generated from is kept after the bounded name was
+	// last generated. The names are generated from user resources, such as ingress hosts, so the bounded names of the
+	// configs that are no longer generated are pruned instead of being kept for the lifetime of the control plane.
+	boundedNameRetention = 24 * time.Hour
+
+	// boundedNamesPruneInterval is the minimum interval between the prunings of the bounded names
+	boundedNamesPruneInterval = time.Hour
+)
+
+// boundedNames maps the bounded names generated by BoundedName to the names they were generated from, and to the time
+// they were last generated at
+var (
+	boundedNames         = make(map[string]boundedNameEntry)
+	boundedNamesPrunedAt time.Time
+	boundedNamesMutex    sync.RWMutex
+)
+
+type boundedNameEntry struct {
+	name            string
+	lastGeneratedAt time.Time
+}
+
+// BoundedNameMapping is a name bounded to MaxNameLength, along with the name it was generated from.
+type BoundedNameMapping struct {
+	// BoundedName is the name sent to proxies, shown in their stats and access logs
+	BoundedName string `json:"bounded_name"`
+
+	// Name is the name the bounded name was generated from
+	Name string `json:"name"`
+}
+
+// BoundedName returns the given name if it is at most MaxNameLength long. Longer names are truncated and suffixed with
+// a hash of the whole name, so that the bounded name is stable across control plane restarts and distinct names
+// sharing a long prefix are not collapsed into the same name. The name a bounded name was generated from can be looked
+// up with LookupBoundedName while the bounded name is generated at least once per boundedNameRetention.
+func BoundedName(name string) string {
+	if len(name) <= MaxNameLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := "~" + hex.EncodeToString(hash[:])[:boundedNameHashLength]
+
+	// Truncate on a rune boundary, as xDS names must be valid UTF-8
+	prefixLength := MaxNameLength - len(suffix)
+	for prefixLength > 0 && !utf8.RuneStart(name[prefixLength]) {
+		prefixLength--
+	}
+	bounded := name[:prefixLength] + suffix
+
+	now := time.Now()
+	boundedNamesMutex.Lock()
+	boundedNames[bounded] = boundedNameEntry{name: name, lastGeneratedAt: now}
+	if now.Sub(boundedNamesPrunedAt) >= boundedNamesPruneInterval {
+		pruneBoundedNames(now)
+	}
+	boundedNamesMutex.Unlock()
+	return bounded
+}
+
+// pruneBoundedNames removes the bounded names that were last generated more than boundedNameRetention before the given
+// time. The caller must hold boundedNamesMutex.
+func pruneBoundedNames(now time.Time) {
+	for bounded, entry := range boundedNames {
+		if now.Sub(entry.lastGeneratedAt) > boundedNameRetention {
+			delete(boundedNames, bounded)
+		}
+	}
+	boundedNamesPrunedAt = now
+}
+
+// LookupBoundedName returns the name the given bounded name was generated from by BoundedName, and whether the
+// bounded name is known
+func LookupBoundedName(bounded string) (string, bool) {
+	boundedNamesMutex.RLock()
+	defer boundedNamesMutex.RUnlock()
+
+	entry, ok := boundedNames[bounded]
+	return entry.name, ok
+}
+
+// ListBoundedNames returns the names bounded by BoundedName that are not pruned yet, sorted by bounded name.
+func ListBoundedNames() []BoundedNameMapping {
+	boundedNamesMutex.RLock()
+	defer boundedNamesMutex.RUnlock()
+
+	mappings := make([]BoundedNameMapping, 0, len(boundedNames))
+	for bounded, entry := range boundedNames {
+		mappings = append(mappings, BoundedNameMapping{BoundedName: bounded, Name: entry.name})
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].BoundedName < mappings[j].BoundedName
+	})
+	return mappings
+}
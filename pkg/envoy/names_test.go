@@ -0,0 +1,83 @@
+package envoy
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestBoundedName(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Names within the maximum length are unchanged and not recorded
+	short := "bookstore.bookstore-ns|bookstore.example.com"
+	assert.Equal(short, BoundedName(short))
+	_, ok := LookupBoundedName(short)
+	assert.False(ok)
+
+	// Long names are bounded, stable, and distinct for names sharing a long prefix
+	long := "bookstore.bookstore-ns|" + strings.Repeat("a", MaxNameLength) + ".example.com"
+	otherLong := "bookstore.bookstore-ns|" + strings.Repeat("a", MaxNameLength) + ".example.org"
+	bounded := BoundedName(long)
+	assert.Len(bounded, MaxNameLength)
+	assert.Equal(bounded, BoundedName(long))
+	assert.NotEqual(bounded, BoundedName(otherLong))
+	assert.True(strings.HasPrefix(bounded, "bookstore.bookstore-ns|aaaa"))
+
+	name, ok := LookupBoundedName(bounded)
+	assert.True(ok)
+	assert.Equal(long, name)
+	assert.Contains(ListBoundedNames(), BoundedNameMapping{BoundedName: bounded, Name: long})
+
+	// Long names are truncated on a rune boundary
+	multiByte := strings.Repeat("é", MaxNameLength)
+	bounded = BoundedName(multiByte)
+	assert.True(utf8.ValidString(bounded))
+	assert.LessOrEqual(len(bounded), MaxNameLength)
+}
+
+func TestPruneBoundedNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	stale := BoundedName(strings.Repeat("s", MaxNameLength+1))
+	fresh := BoundedName(strings.Repeat("f", MaxNameLength+1))
+
+	boundedNamesMutex.Lock()
+	staleEntry := boundedNames[stale]
+	staleEntry.lastGeneratedAt = time.Now().Add(-boundedNameRetention - time.Minute)
+	boundedNames[stale] = staleEntry
+	pruneBoundedNames(time.Now())
+	boundedNamesMutex.Unlock()
+
+	_, ok := LookupBoundedName(stale)
+	assert.False(ok)
+	_, ok = LookupBoundedName(fresh)
+	assert.True(ok)
+
+	// A pruned bounded name is known again once it is generated again
+	BoundedName(strings.Repeat("s", MaxNameLength+1))
+	_, ok = LookupBoundedName(stale)
+	assert.True(ok)
+}
+
+func TestBoundedClusterNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	longHost := strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + ".example.com"
+	clusterName := GetEgressHostClusterName(longHost, 443)
+	assert.LessOrEqual(len(clusterName), MaxNameLength)
+	name, ok := LookupBoundedName(clusterName)
+	assert.True(ok)
+	assert.Equal("egress:"+longHost+":443", name)
+
+	host, ok := GetEgressHostFromClusterName(clusterName)
+	assert.True(ok)
+	assert.Equal(longHost, host)
+
+	longService := strings.Repeat("n", 63) + "/" + strings.Repeat("s", 63)
+	assert.LessOrEqual(len(GetLocalClusterNameForServiceCluster(longService)), MaxNameLength)
+	assert.Equal("bookstore-ns/bookstore-local", GetLocalClusterNameForServiceCluster("bookstore-ns/bookstore"))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
 	service "github.com/openservicemesh/osm/pkg/service"
 )
 
@@ -28,6 +29,37 @@ type Proxy struct {
 	lastAppliedVersion map[TypeURI]uint64
 	lastNonce          map[TypeURI]string
 
+	// The time the certificate of the proxy was rotated, while the rotated certificate is not acknowledged by the proxy
+	certRotatedAt time.Time
+
+	// The first SDS version including the rotated certificate of the proxy
+	certRotationVersion uint64
+
+	// The configuration generation last broadcast to the proxy, while it is not acknowledged by the proxy
+	pendingConfigGeneration *ConfigGeneration
+
+	// The versions of the xDS responses including the pending configuration generation, per type not yet acknowledged
+	pendingConfigGenerationVersions map[TypeURI]uint64
+
+	// The ID of the last configuration generation acknowledged by the proxy
+	appliedConfigGeneration uint64
+
+	// The conflicts between the names of the resources last generated for the proxy, per kind of resource
+	resourceConflicts map[ResourceKind][]ResourceConflict
+
+	// The time of the last warning event recorded for an xDS response withheld from the proxy
+	lastThrottledEventAt time.Time
+
+	// The share of its memory limit the proxy last reported using, and whether it is known
+	memoryPressure      float64
+	memoryPressureKnown bool
+
+	// The last xDS responses sent to the proxy whose size is limited by the max proxy config size, per type
+	sentConfigs map[TypeURI]*sentConfig
+
+	// The last xDS responses sent to the proxy, per type, the oldest first
+	pushHistory map[TypeURI][]*configPush
+
 	// Records metadata around the Kubernetes Pod on which this Envoy Proxy is installed.
 	// This could be nil if the Envoy is not operating in a Kubernetes cluster (VM for example)
 	// NOTE: This field may be not be set at the time Proxy struct is initialized. This would
@@ -58,6 +90,14 @@ func (p *Proxy) HasPodMetadata() bool {
 	return p.PodMetadata != nil
 }
 
+// IsDevProxy returns true if the given Envoy proxy runs outside Kubernetes to join a local development process
+// to the mesh. Such a proxy is not backed by a Pod, and is only programmed to reach the upstreams allowed for its identity.
+// It is determined by the verified xDS certificate of the proxy, which is only issued for a dev proxy by the holders of
+// the mesh root certificate, and never by the service node ID the proxy reports.
+func (p *Proxy) IsDevProxy() bool {
+	return strings.HasSuffix(p.xDSCertificateCommonName.String(), constants.DomainDelimiter+constants.DevProxyCertificateCommonNameSuffix)
+}
+
 // StatsHeaders returns the headers required for SMI metrics
 func (p *Proxy) StatsHeaders() map[string]string {
 	unknown := "unknown"
@@ -92,13 +132,23 @@ func (p *Proxy) StatsHeaders() map[string]string {
 	}
 
 	return map[string]string{
-		"osm-stats-pod":       podName,
-		"osm-stats-namespace": podNamespace,
-		"osm-stats-kind":      podControllerKind,
-		"osm-stats-name":      podControllerName,
+		statsHeaderPrefix + "pod":       podName,
+		statsHeaderPrefix + "namespace": podNamespace,
+		statsHeaderPrefix + "kind":      podControllerKind,
+		statsHeaderPrefix + "name":      podControllerName,
 	}
 }
 
+// StatsPeerHeaders returns the headers the proxy adds to the requests it sends, for the proxy receiving the requests to
+// attribute the SMI metrics it records to the source workload
+func (p *Proxy) StatsPeerHeaders() map[string]string {
+	peerHeaders := make(map[string]string)
+	for k, v := range p.StatsHeaders() {
+		peerHeaders[statsPeerHeaderPrefix+strings.TrimPrefix(k, statsHeaderPrefix)] = v
+	}
+	return peerHeaders
+}
+
 // SetLastAppliedVersion records the version of the given Envoy proxy that was last acknowledged.
 func (p *Proxy) SetLastAppliedVersion(typeURI TypeURI, version uint64) {
 	p.lastAppliedVersion[typeURI] = version
@@ -141,6 +191,84 @@ func (p *Proxy) SetNewNonce(typeURI TypeURI) string {
 	return p.lastNonce[typeURI]
 }
 
+// ShouldRecordThrottledEvent returns true if no warning event was recorded for an xDS response withheld from the proxy
+// within the given interval before the given time, in which case the given time is recorded as the time of the last
+// event. The events of a proxy under memory pressure are rate limited, as every response pushed to it is withheld.
+func (p *Proxy) ShouldRecordThrottledEvent(now time.Time, interval time.Duration) bool {
+	if !p.lastThrottledEventAt.IsZero() && now.Sub(p.lastThrottledEventAt) < interval {
+		return false
+	}
+	p.lastThrottledEventAt = now
+	return true
+}
+
+// SetPendingCertificateRotation records that the certificate of the proxy was rotated at the given time, and is pushed to
+// the proxy in the SDS responses starting with the given version. When several rotations are pending, the time of the
+// earliest rotation is kept.
+func (p *Proxy) SetPendingCertificateRotation(rotatedAt time.Time, version uint64) {
+	if p.certRotatedAt.IsZero() {
+		p.certRotatedAt = rotatedAt
+	}
+	p.certRotationVersion = version
+}
+
+// AcknowledgeCertificateRotation records the SDS version acknowledged by the proxy. It returns the time the certificate
+// of the proxy was rotated, and true if the acknowledged version includes the pending rotated certificate.
+func (p *Proxy) AcknowledgeCertificateRotation(ackVersion uint64) (time.Time, bool) {
+	if p.certRotatedAt.IsZero() || ackVersion < p.certRotationVersion {
+		return time.Time{}, false
+	}
+	rotatedAt := p.certRotatedAt
+	p.certRotatedAt = time.Time{}
+	p.certRotationVersion = 0
+	return rotatedAt, true
+}
+
+// SetPendingConfigGeneration records that the given configuration generation was broadcast to the proxy, in the xDS
+// responses last sent to the proxy. A newer generation replaces the pending one.
+func (p *Proxy) SetPendingConfigGeneration(generation ConfigGeneration) {
+	p.pendingConfigGeneration = &generation
+	p.pendingConfigGenerationVersions = make(map[TypeURI]uint64)
+	for typeURI, version := range p.lastSentVersion {
+		// Versions already acknowledged do not need to be acknowledged again
+		if version > p.lastAppliedVersion[typeURI] {
+			p.pendingConfigGenerationVersions[typeURI] = version
+		}
+	}
+	p.acknowledgeIfApplied()
+}
+
+// AcknowledgeConfigGeneration records the version of the given type acknowledged by the proxy. It returns the pending
+// configuration generation, and true if the proxy acknowledged all the xDS responses including it.
+func (p *Proxy) AcknowledgeConfigGeneration(typeURI TypeURI, ackVersion uint64) (ConfigGeneration, bool) {
+	if p.pendingConfigGeneration == nil {
+		return ConfigGeneration{}, false
+	}
+	if version, ok := p.pendingConfigGenerationVersions[typeURI]; ok && ackVersion >= version {
+		delete(p.pendingConfigGenerationVersions, typeURI)
+	}
+	generation := *p.pendingConfigGeneration
+	return generation, p.acknowledgeIfApplied()
+}
+
+// acknowledgeIfApplied records the pending configuration generation as applied once all the xDS responses including it
+// are acknowledged, and returns true if it did
+func (p *Proxy) acknowledgeIfApplied() bool {
+	if p.pendingConfigGeneration == nil || len(p.pendingConfigGenerationVersions) > 0 {
+		return false
+	}
+	p.appliedConfigGeneration = p.pendingConfigGeneration.ID
+	p.pendingConfigGeneration = nil
+	p.pendingConfigGenerationVersions = nil
+	return true
+}
+
+// GetAppliedConfigGeneration returns the ID of the last configuration generation acknowledged by the proxy, or 0 if the
+// proxy did not acknowledge any generation yet.
+func (p *Proxy) GetAppliedConfigGeneration() uint64 {
+	return p.appliedConfigGeneration
+}
+
 // GetPodUID returns the UID of the pod, which the connected Envoy proxy is fronting.
 func (p Proxy) GetPodUID() string {
 	if p.PodMetadata == nil {
@@ -149,6 +277,16 @@ func (p Proxy) GetPodUID() string {
 	return p.PodMetadata.UID
 }
 
+// HasIPv6PodIP returns true if the primary IP of the Pod on which the Envoy proxy is installed is an IPv6 address.
+// This is the case for pods on IPv6-only clusters and IPv6-primary dual-stack clusters.
+func (p Proxy) HasIPv6PodIP() bool {
+	if p.PodMetadata == nil {
+		return false
+	}
+	ip := net.ParseIP(p.PodMetadata.IP)
+	return ip != nil && ip.To4() == nil
+}
+
 // GetCertificateCommonName returns the Subject Common Name from the mTLS certificate of the Envoy proxy connected to xDS.
 func (p Proxy) GetCertificateCommonName() certificate.CommonName {
 	return p.xDSCertificateCommonName
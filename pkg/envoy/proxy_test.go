@@ -3,6 +3,7 @@ package envoy
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
 
@@ -101,6 +103,33 @@ var _ = Describe("Test proxy methods", func() {
 		})
 	})
 
+	Context("test HasIPv6PodIP()", func() {
+		It("returns false without Pod metadata", func() {
+			newProxy := NewProxy(certificate.CommonName("cn"), certificate.SerialNumber("123"), nil)
+			Expect(newProxy.HasIPv6PodIP()).To(BeFalse())
+		})
+
+		It("returns correct values based on the Pod IP", func() {
+			ipv4Proxy := NewProxy(certificate.CommonName("cn"), certificate.SerialNumber("123"), nil)
+			ipv4Proxy.PodMetadata = &PodMetadata{IP: "10.0.0.1"}
+			Expect(ipv4Proxy.HasIPv6PodIP()).To(BeFalse())
+
+			ipv6Proxy := NewProxy(certificate.CommonName("cn"), certificate.SerialNumber("123"), nil)
+			ipv6Proxy.PodMetadata = &PodMetadata{IP: "fd00:10:244::5"}
+			Expect(ipv6Proxy.HasIPv6PodIP()).To(BeTrue())
+		})
+	})
+
+	Context("test ShouldRecordThrottledEvent()", func() {
+		It("rate limits the throttled events of the proxy", func() {
+			newProxy := NewProxy(certificate.CommonName("cn"), certificate.SerialNumber("123"), nil)
+			now := time.Now()
+			Expect(newProxy.ShouldRecordThrottledEvent(now, time.Minute)).To(BeTrue())
+			Expect(newProxy.ShouldRecordThrottledEvent(now.Add(30*time.Second), time.Minute)).To(BeFalse())
+			Expect(newProxy.ShouldRecordThrottledEvent(now.Add(time.Minute), time.Minute)).To(BeTrue())
+		})
+	})
+
 	Context("test StatsHeaders()", func() {
 		It("returns correct values", func() {
 			actual := proxy.StatsHeaders()
@@ -218,3 +247,100 @@ func TestStatsHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsPeerHeaders(t *testing.T) {
+	proxy := Proxy{
+		PodMetadata: &PodMetadata{
+			Name:         "pod",
+			Namespace:    "ns",
+			WorkloadKind: "kind",
+			WorkloadName: "name",
+		},
+	}
+	expected := map[string]string{
+		"osm-stats-peer-kind":      "kind",
+		"osm-stats-peer-name":      "name",
+		"osm-stats-peer-namespace": "ns",
+		"osm-stats-peer-pod":       "pod",
+	}
+	assert.Equal(t, expected, proxy.StatsPeerHeaders())
+}
+
+func TestIsDevProxy(t *testing.T) {
+	sidecarCN := certificate.CommonName("52883c80-6e0d-4c64-b901-cbcb75134949.bookbuyer.bookbuyer")
+	devProxyCN := certificate.CommonName("52883c80-6e0d-4c64-b901-cbcb75134949.bookbuyer.bookbuyer." + constants.DevProxyCertificateCommonNameSuffix)
+
+	assert.False(t, (&Proxy{}).IsDevProxy())
+	assert.False(t, NewProxy(sidecarCN, "123", nil).IsDevProxy())
+	assert.True(t, NewProxy(devProxyCN, "123", nil).IsDevProxy())
+
+	// The service node ID reported by a sidecar does not make it a dev proxy
+	sidecar := NewProxy(sidecarCN, "123", nil)
+	sidecar.PodMetadata = &PodMetadata{WorkloadKind: constants.DevProxyWorkloadKind}
+	assert.False(t, sidecar.IsDevProxy())
+}
+
+func TestCertificateRotation(t *testing.T) {
+	proxy := &Proxy{}
+	firstRotation := time.Now().Add(-time.Minute)
+
+	// No rotation is pending
+	_, ok := proxy.AcknowledgeCertificateRotation(1)
+	assert.False(t, ok)
+
+	// The time of the earliest pending rotation is kept
+	proxy.SetPendingCertificateRotation(firstRotation, 3)
+	proxy.SetPendingCertificateRotation(time.Now(), 4)
+
+	// Acknowledging a version sent before the rotation
+	_, ok = proxy.AcknowledgeCertificateRotation(3)
+	assert.False(t, ok)
+
+	rotatedAt, ok := proxy.AcknowledgeCertificateRotation(4)
+	assert.True(t, ok)
+	assert.Equal(t, firstRotation, rotatedAt)
+
+	// The rotation is only acknowledged once
+	_, ok = proxy.AcknowledgeCertificateRotation(5)
+	assert.False(t, ok)
+}
+
+func TestConfigGeneration(t *testing.T) {
+	proxy := NewProxy(certificate.CommonName("foo"), certificate.SerialNumber("123"), nil)
+
+	// No generation is pending
+	_, ok := proxy.AcknowledgeConfigGeneration(TypeCDS, 1)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), proxy.GetAppliedConfigGeneration())
+
+	proxy.SetLastSentVersion(TypeCDS, 2)
+	proxy.SetLastSentVersion(TypeLDS, 5)
+	proxy.SetLastSentVersion(TypeSDS, 1)
+	proxy.SetLastAppliedVersion(TypeSDS, 1)
+	generation := ConfigGeneration{ID: 3, CreatedAt: time.Now(), Changes: []string{"service-updated bookstore-ns/bookstore"}}
+	proxy.SetPendingConfigGeneration(generation)
+
+	// Acknowledging a version sent before the generation
+	_, ok = proxy.AcknowledgeConfigGeneration(TypeCDS, 1)
+	assert.False(t, ok)
+
+	// The generation is applied once every type sent with it is acknowledged
+	_, ok = proxy.AcknowledgeConfigGeneration(TypeCDS, 2)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(0), proxy.GetAppliedConfigGeneration())
+
+	acked, ok := proxy.AcknowledgeConfigGeneration(TypeLDS, 5)
+	assert.True(t, ok)
+	assert.Equal(t, generation, acked)
+	assert.Equal(t, uint64(3), proxy.GetAppliedConfigGeneration())
+
+	// The generation is only acknowledged once
+	_, ok = proxy.AcknowledgeConfigGeneration(TypeLDS, 6)
+	assert.False(t, ok)
+
+	// A generation broadcast without new responses is applied right away
+	proxy.SetLastAppliedVersion(TypeCDS, 2)
+	proxy.SetLastAppliedVersion(TypeLDS, 5)
+	proxy.SetPendingConfigGeneration(ConfigGeneration{ID: 4})
+	assert.Equal(t, uint64(4), proxy.GetAppliedConfigGeneration())
+}
@@ -0,0 +1,289 @@
+package envoy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	protoV1 "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MaxPushHistory is the number of xDS responses of each type recorded per proxy for the debug server
+const MaxPushHistory = 5
+
+// pushHistoryMutex guards the xDS responses recorded on proxies, which are read by the debug server while the proxies
+// are being configured
+var pushHistoryMutex sync.RWMutex
+
+// configPush is a record of an xDS response sent to a proxy
+type configPush struct {
+	version uint64
+	sentAt  time.Time
+
+	// resources are the resources of the response, by name. The content of SDS resources is not recorded, as they hold
+	// the private keys of the proxy, so SDS resources are recorded with a nil message.
+	resources map[string]proto.Message
+}
+
+// PushDiff is the difference between the resources of an xDS response sent to a proxy and the resources of the previous
+// response of the same type.
+type PushDiff struct {
+	// TypeURI is the type of the response
+	TypeURI TypeURI `json:"type_uri"`
+
+	// Version is the version of the response
+	Version uint64 `json:"version"`
+
+	// SentAt is the time the response was sent to the proxy
+	SentAt time.Time `json:"sent_at"`
+
+	// PreviousVersion is the version of the previous response of the same type, or 0 if no previous response is
+	// recorded, in which case all the resources of the response are added
+	PreviousVersion uint64 `json:"previous_version"`
+
+	// Added, Removed and Changed are the resources added, removed and changed by the response, by name
+	Added   []string         `json:"added,omitempty"`
+	Removed []string         `json:"removed,omitempty"`
+	Changed []ResourceChange `json:"changed,omitempty"`
+
+	// Unchanged is the number of resources left unchanged by the response
+	Unchanged int `json:"unchanged"`
+}
+
+// ResourceChange describes the change of a resource between two xDS responses sent to a proxy
+type ResourceChange struct {
+	// Name is the name of the resource
+	Name string `json:"name"`
+
+	// Fields are the top-level fields of the resource that changed
+	Fields []string `json:"fields"`
+
+	// Details are the changes of the nested resources, such as the virtual hosts and routes of a route configuration
+	Details []string `json:"details,omitempty"`
+}
+
+// RecordPush records the resources of the xDS response of the given type and version sent to the proxy, keeping the
+// last MaxPushHistory responses of each type for the debug server.
+func (p *Proxy) RecordPush(typeURI TypeURI, version uint64, resources []*any.Any) {
+	push := &configPush{
+		version:   version,
+		sentAt:    time.Now(),
+		resources: make(map[string]proto.Message, len(resources)),
+	}
+	for _, resource := range resources {
+		var dynamic ptypes.DynamicAny
+		if err := ptypes.UnmarshalAny(resource, &dynamic); err != nil {
+			log.Error().Err(err).Msgf("Error unmarshaling resource of type %s pushed to proxy with SerialNumber=%s on Pod with UID=%s",
+				resource.TypeUrl, p.GetCertificateSerialNumber(), p.GetPodUID())
+			continue
+		}
+		// NOTE: Never record the content of SDS resources - they contain secrets!
+		var message proto.Message
+		if typeURI != TypeSDS {
+			message = protoV1.MessageV2(dynamic.Message)
+		}
+		push.resources[GetResourceName(dynamic.Message)] = message
+	}
+
+	pushHistoryMutex.Lock()
+	defer pushHistoryMutex.Unlock()
+
+	if p.pushHistory == nil {
+		p.pushHistory = make(map[TypeURI][]*configPush)
+	}
+	history := append(p.pushHistory[typeURI], push)
+	if len(history) > MaxPushHistory {
+		history = history[len(history)-MaxPushHistory:]
+	}
+	p.pushHistory[typeURI] = history
+}
+
+// GetPushDiffs returns the differences between the recorded xDS responses sent to the proxy and their previous
+// response of the same type, the most recent first.
+func (p *Proxy) GetPushDiffs() []PushDiff {
+	pushHistoryMutex.RLock()
+	defer pushHistoryMutex.RUnlock()
+
+	var diffs []PushDiff
+	for typeURI, history := range p.pushHistory {
+		for i, push := range history {
+			var previous *configPush
+			if i > 0 {
+				previous = history[i-1]
+			}
+			diffs = append(diffs, diffPushes(typeURI, previous, push))
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if !diffs[i].SentAt.Equal(diffs[j].SentAt) {
+			return diffs[i].SentAt.After(diffs[j].SentAt)
+		}
+		if diffs[i].TypeURI != diffs[j].TypeURI {
+			return diffs[i].TypeURI < diffs[j].TypeURI
+		}
+		return diffs[i].Version > diffs[j].Version
+	})
+	return diffs
+}
+
+// diffPushes returns the difference between the given xDS response and the previous response of the same type, which
+// is nil if no previous response is recorded
+func diffPushes(typeURI TypeURI, previous, current *configPush) PushDiff {
+	diff := PushDiff{
+		TypeURI: typeURI,
+		Version: current.version,
+		SentAt:  current.sentAt,
+	}
+	previousResources := make(map[string]proto.Message)
+	if previous != nil {
+		diff.PreviousVersion = previous.version
+		previousResources = previous.resources
+	}
+
+	for name, resource := range current.resources {
+		previousResource, ok := previousResources[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case resource == nil || previousResource == nil || proto.Equal(previousResource, resource):
+			diff.Unchanged++
+		default:
+			diff.Changed = append(diff.Changed, diffResources(name, previousResource, resource))
+		}
+	}
+	for name := range previousResources {
+		if _, ok := current.resources[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+	return diff
+}
+
+// diffResources returns the change between two versions of the resource with the given name
+func diffResources(name string, previous, current proto.Message) ResourceChange {
+	change := ResourceChange{
+		Name:   name,
+		Fields: getChangedFields(previous, current),
+	}
+	// The routes of a route configuration are detailed, to tell which requests are routed differently
+	if currentRouteConfig, ok := current.(*xds_route.RouteConfiguration); ok {
+		if previousRouteConfig, ok := previous.(*xds_route.RouteConfiguration); ok {
+			change.Details = diffVirtualHosts(previousRouteConfig.VirtualHosts, currentRouteConfig.VirtualHosts)
+		}
+	}
+	return change
+}
+
+// diffVirtualHosts returns the virtual hosts added, removed and changed between two versions of a route configuration,
+// along with the routes added, removed and changed in each changed virtual host
+func diffVirtualHosts(previous, current []*xds_route.VirtualHost) []string {
+	previousByName := make(map[string]*xds_route.VirtualHost)
+	for _, virtualHost := range previous {
+		previousByName[virtualHost.Name] = virtualHost
+	}
+
+	var details []string
+	currentNames := make(map[string]bool)
+	for _, virtualHost := range current {
+		currentNames[virtualHost.Name] = true
+		previousVirtualHost, ok := previousByName[virtualHost.Name]
+		if !ok {
+			details = append(details, fmt.Sprintf("virtual host %s added", virtualHost.Name))
+			continue
+		}
+		if proto.Equal(previousVirtualHost, virtualHost) {
+			continue
+		}
+		details = append(details, fmt.Sprintf("virtual host %s changed: %v", virtualHost.Name, getChangedFields(previousVirtualHost, virtualHost)))
+		for _, routeDetail := range diffRoutes(previousVirtualHost.Routes, virtualHost.Routes) {
+			details = append(details, fmt.Sprintf("virtual host %s: %s", virtualHost.Name, routeDetail))
+		}
+	}
+	for _, virtualHost := range previous {
+		if !currentNames[virtualHost.Name] {
+			details = append(details, fmt.Sprintf("virtual host %s removed", virtualHost.Name))
+		}
+	}
+	return details
+}
+
+// diffRoutes returns the routes added, removed and changed between two versions of a virtual host. Routes are
+// identified by their name, or by their match when they are not named.
+func diffRoutes(previous, current []*xds_route.Route) []string {
+	previousByKey := make(map[string]*xds_route.Route)
+	for _, route := range previous {
+		previousByKey[getRouteKey(route)] = route
+	}
+
+	var details []string
+	currentKeys := make(map[string]bool)
+	for _, route := range current {
+		key := getRouteKey(route)
+		currentKeys[key] = true
+		previousRoute, ok := previousByKey[key]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("route %s added", key))
+		case !proto.Equal(previousRoute, route):
+			details = append(details, fmt.Sprintf("route %s changed: %v", key, getChangedFields(previousRoute, route)))
+		}
+	}
+	for _, route := range previous {
+		if key := getRouteKey(route); !currentKeys[key] {
+			details = append(details, fmt.Sprintf("route %s removed", key))
+		}
+	}
+	return details
+}
+
+func getRouteKey(route *xds_route.Route) string {
+	if route.Name != "" {
+		return route.Name
+	}
+	return fmt.Sprintf("{%s}", strings.TrimSpace(protoV1.CompactTextString(route.Match)))
+}
+
+// getChangedFields returns the names of the top-level fields that differ between two messages of the same type
+func getChangedFields(previous, current proto.Message) []string {
+	previousReflect, currentReflect := previous.ProtoReflect(), current.ProtoReflect()
+
+	var fields []string
+	fieldDescriptors := currentReflect.Descriptor().Fields()
+	for i := 0; i < fieldDescriptors.Len(); i++ {
+		field := fieldDescriptors.Get(i)
+		if !isFieldEqual(field, previousReflect, currentReflect) {
+			fields = append(fields, string(field.Name()))
+		}
+	}
+	return fields
+}
+
+// isFieldEqual returns true if the given field is set to the same value in both messages, by comparing copies of the
+// messages holding only this field
+func isFieldEqual(field protoreflect.FieldDescriptor, a, b protoreflect.Message) bool {
+	if !a.Has(field) && !b.Has(field) {
+		return true
+	}
+	fieldA, fieldB := a.New(), b.New()
+	if a.Has(field) {
+		fieldA.Set(field, a.Get(field))
+	}
+	if b.Has(field) {
+		fieldB.Set(field, b.Get(field))
+	}
+	return proto.Equal(fieldA.Interface(), fieldB.Interface())
+}
@@ -0,0 +1,144 @@
+package envoy
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+func marshalResources(t *testing.T, resources ...proto.Message) []*any.Any {
+	var marshalled []*any.Any
+	for _, resource := range resources {
+		marshalledResource, err := ptypes.MarshalAny(resource)
+		tassert.Nil(t, err)
+		marshalled = append(marshalled, marshalledResource)
+	}
+	return marshalled
+}
+
+func TestRecordPush(t *testing.T) {
+	assert := tassert.New(t)
+	proxy := NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+
+	proxy.RecordPush(TypeCDS, 1, marshalResources(t,
+		&xds_cluster.Cluster{Name: "bookstore/bookstore-v1", AltStatName: "bookstore/bookstore-v1"},
+		&xds_cluster.Cluster{Name: "bookstore/bookstore-v2"},
+	))
+	proxy.RecordPush(TypeCDS, 2, marshalResources(t,
+		&xds_cluster.Cluster{Name: "bookstore/bookstore-v1", AltStatName: "bookstore-v1"},
+		&xds_cluster.Cluster{Name: "bookwarehouse/bookwarehouse"},
+	))
+
+	diffs := proxy.GetPushDiffs()
+	assert.Len(diffs, 2)
+
+	// The most recent push is compared to the previous push of the same type
+	assert.Equal(TypeCDS, diffs[0].TypeURI)
+	assert.Equal(uint64(2), diffs[0].Version)
+	assert.Equal(uint64(1), diffs[0].PreviousVersion)
+	assert.Equal([]string{"bookwarehouse/bookwarehouse"}, diffs[0].Added)
+	assert.Equal([]string{"bookstore/bookstore-v2"}, diffs[0].Removed)
+	assert.Len(diffs[0].Changed, 1)
+	assert.Equal("bookstore/bookstore-v1", diffs[0].Changed[0].Name)
+	assert.Equal([]string{"alt_stat_name"}, diffs[0].Changed[0].Fields)
+	assert.Equal(0, diffs[0].Unchanged)
+
+	// All the resources of the first recorded push are added
+	assert.Equal(uint64(0), diffs[1].PreviousVersion)
+	assert.Equal([]string{"bookstore/bookstore-v1", "bookstore/bookstore-v2"}, diffs[1].Added)
+
+	// Only the last pushes of each type are recorded
+	for version := uint64(3); version < 3+MaxPushHistory; version++ {
+		proxy.RecordPush(TypeCDS, version, marshalResources(t, &xds_cluster.Cluster{Name: "bookstore/bookstore-v1"}))
+	}
+	diffs = proxy.GetPushDiffs()
+	assert.Len(diffs, MaxPushHistory)
+	assert.Equal(uint64(2+MaxPushHistory), diffs[0].Version)
+	assert.Equal(1, diffs[0].Unchanged)
+}
+
+func TestRecordPushSecrets(t *testing.T) {
+	assert := tassert.New(t)
+	proxy := NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+
+	secret := func(name, privateKey string) *xds_auth.Secret {
+		return &xds_auth.Secret{
+			Name: name,
+			Type: &xds_auth.Secret_TlsCertificate{
+				TlsCertificate: &xds_auth.TlsCertificate{
+					PrivateKey: &xds_core.DataSource{Specifier: &xds_core.DataSource_InlineBytes{InlineBytes: []byte(privateKey)}},
+				},
+			},
+		}
+	}
+	proxy.RecordPush(TypeSDS, 1, marshalResources(t, secret("service-cert:default/bookbuyer", "key-1")))
+	proxy.RecordPush(TypeSDS, 2, marshalResources(t, secret("service-cert:default/bookbuyer", "key-2"), secret("root-cert-for-mtls-outbound:default/bookstore", "")))
+
+	// The content of the secrets is not recorded, so only the secrets added and removed are reported
+	for _, push := range proxy.pushHistory[TypeSDS] {
+		for _, resource := range push.resources {
+			assert.Nil(resource)
+		}
+	}
+	diffs := proxy.GetPushDiffs()
+	assert.Equal([]string{"root-cert-for-mtls-outbound:default/bookstore"}, diffs[0].Added)
+	assert.Empty(diffs[0].Changed)
+	assert.Equal(1, diffs[0].Unchanged)
+}
+
+func TestRecordPushRoutes(t *testing.T) {
+	assert := tassert.New(t)
+	proxy := NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+
+	route := func(name, prefix, cluster string) *xds_route.Route {
+		return &xds_route.Route{
+			Name:  name,
+			Match: &xds_route.RouteMatch{PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: prefix}},
+			Action: &xds_route.Route_Route{Route: &xds_route.RouteAction{
+				ClusterSpecifier: &xds_route.RouteAction_Cluster{Cluster: cluster},
+			}},
+		}
+	}
+
+	proxy.RecordPush(TypeRDS, 1, marshalResources(t, &xds_route.RouteConfiguration{
+		Name: "rds-outbound",
+		VirtualHosts: []*xds_route.VirtualHost{
+			{Name: "bookstore", Routes: []*xds_route.Route{route("root", "/", "bookstore/bookstore-v1"), route("books", "/books", "bookstore/bookstore-v1")}},
+			{Name: "bookthief", Routes: []*xds_route.Route{route("root", "/", "bookthief/bookthief")}},
+		},
+	}))
+	proxy.RecordPush(TypeRDS, 2, marshalResources(t, &xds_route.RouteConfiguration{
+		Name: "rds-outbound",
+		VirtualHosts: []*xds_route.VirtualHost{
+			{Name: "bookstore", Routes: []*xds_route.Route{route("root", "/", "bookstore/bookstore-v2"), route("buy", "/buy", "bookstore/bookstore-v1")}},
+			{Name: "bookwarehouse", Routes: []*xds_route.Route{route("root", "/", "bookwarehouse/bookwarehouse")}},
+		},
+	}))
+
+	diffs := proxy.GetPushDiffs()
+	assert.Len(diffs, 2)
+	assert.Len(diffs[0].Changed, 1)
+	change := diffs[0].Changed[0]
+	assert.Equal("rds-outbound", change.Name)
+	assert.Equal([]string{"virtual_hosts"}, change.Fields)
+	assert.Equal([]string{
+		"virtual host bookstore changed: [routes]",
+		"virtual host bookstore: route root changed: [route]",
+		"virtual host bookstore: route buy added",
+		"virtual host bookstore: route books removed",
+		"virtual host bookwarehouse added",
+		"virtual host bookthief removed",
+	}, change.Details)
+
+	// Unnamed routes are identified by their match
+	assert.Contains(getRouteKey(route("", "/books", "bookstore/bookstore-v1")), `"/books"`)
+}
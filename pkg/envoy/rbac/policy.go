@@ -1,11 +1,14 @@
 package rbac
 
 import (
+	"net"
 	"strconv"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // Generate constructs an RBAC policy for the policy object on which this method is called
@@ -33,10 +36,12 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			// Combine all the AND rules for this Principal rule with AND semantics
 			var andPrincipalRules []*xds_rbac.Principal
 			for _, andPrincipalRule := range principalRuleList.AndRules {
-				// Fill in the authenticated principal types
-				if andPrincipalRule.Attribute == DownstreamAuthPrincipal {
-					authPrincipal := GetAuthenticatedPrincipal(andPrincipalRule.Value)
-					andPrincipalRules = append(andPrincipalRules, authPrincipal)
+				principal, err := getPrincipal(andPrincipalRule)
+				if err != nil {
+					return nil, err
+				}
+				if principal != nil {
+					andPrincipalRules = append(andPrincipalRules, principal)
 				}
 			}
 			currentPrincipal = andPrincipals(andPrincipalRules)
@@ -45,10 +50,12 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			// Combine all the OR rules for this Principal rule with OR semantics
 			var orPrincipalRules []*xds_rbac.Principal
 			for _, orPrincipalRule := range principalRuleList.OrRules {
-				// Fill in the authenticated principal types
-				if orPrincipalRule.Attribute == DownstreamAuthPrincipal {
-					authPrincipal := GetAuthenticatedPrincipal(orPrincipalRule.Value)
-					orPrincipalRules = append(orPrincipalRules, authPrincipal)
+				principal, err := getPrincipal(orPrincipalRule)
+				if err != nil {
+					return nil, err
+				}
+				if principal != nil {
+					orPrincipalRules = append(orPrincipalRules, principal)
 				}
 			}
 			currentPrincipal = orPrincipals(orPrincipalRules)
@@ -148,6 +155,41 @@ func GetAuthenticatedPrincipal(principalName string) *xds_rbac.Principal {
 	}
 }
 
+// getPrincipal returns the RBAC principal for the given principal rule, or nil if the rule's attribute
+// does not apply to principals
+func getPrincipal(rule Rule) (*xds_rbac.Principal, error) {
+	switch rule.Attribute {
+	case DownstreamAuthPrincipal:
+		// Fill in the authenticated principal types
+		return GetAuthenticatedPrincipal(rule.Value), nil
+
+	case DownstreamRemoteIP:
+		// Fill in the remote IP principal types
+		return GetRemoteIPPrincipal(rule.Value)
+
+	default:
+		return nil, nil
+	}
+}
+
+// GetRemoteIPPrincipal returns an RBAC principal object matching downstream remote IPs in the given CIDR range
+func GetRemoteIPPrincipal(cidr string) (*xds_rbac.Principal, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.Errorf("Error parsing remote IP CIDR range %s", cidr)
+	}
+	prefixLen, _ := ipNet.Mask.Size()
+
+	return &xds_rbac.Principal{
+		Identifier: &xds_rbac.Principal_RemoteIp{
+			RemoteIp: &xds_core.CidrRange{
+				AddressPrefix: ipNet.IP.String(),
+				PrefixLen:     &wrapperspb.UInt32Value{Value: uint32(prefixLen)},
+			},
+		},
+	}, nil
+}
+
 func orPrincipals(principals []*xds_rbac.Principal) *xds_rbac.Principal {
 	return &xds_rbac.Principal{
 		Identifier: &xds_rbac.Principal_OrIds{
@@ -208,3 +250,18 @@ func GetDestinationPortPermission(port uint32) *xds_rbac.Permission {
 		},
 	}
 }
+
+// GetDenyAnyRules returns RBAC rules with a single policy of the given name denying any request or connection. They are
+// used as shadow rules to record the requests and connections that would be denied in their dynamic metadata, without
+// denying them.
+func GetDenyAnyRules(policyName string) *xds_rbac.RBAC {
+	return &xds_rbac.RBAC{
+		Action: xds_rbac.RBAC_DENY,
+		Policies: map[string]*xds_rbac.Policy{
+			policyName: {
+				Permissions: []*xds_rbac.Permission{getAnyPermission()},
+				Principals:  []*xds_rbac.Principal{getAnyPrincipal()},
+			},
+		},
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 )
 
@@ -273,6 +275,59 @@ func TestGenerate(t *testing.T) {
 			},
 			expectError: false,
 		},
+
+		{
+			name: "testing OR rules for authenticated principals and remote IPs",
+			p: &Policy{
+				Principals: []RulesList{
+					{
+						OrRules: []Rule{
+							{Attribute: DownstreamAuthPrincipal, Value: "foo.domain"},
+							{Attribute: DownstreamRemoteIP, Value: "10.0.0.1/16"},
+						},
+					},
+				},
+			},
+			expectedPrincipals: []*xds_rbac.Principal{
+				{
+					Identifier: &xds_rbac.Principal_OrIds{
+						OrIds: &xds_rbac.Principal_Set{
+							Ids: []*xds_rbac.Principal{
+								GetAuthenticatedPrincipal("foo.domain"),
+								{
+									Identifier: &xds_rbac.Principal_RemoteIp{
+										RemoteIp: &xds_core.CidrRange{
+											AddressPrefix: "10.0.0.0",
+											PrefixLen:     &wrapperspb.UInt32Value{Value: 16},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_Any{Any: true},
+				},
+			},
+			expectError: false,
+		},
+
+		{
+			name: "testing invalid remote IP CIDR range",
+			p: &Policy{
+				Principals: []RulesList{
+					{
+						OrRules: []Rule{
+							{Attribute: DownstreamRemoteIP, Value: "10.0.0.1"},
+						},
+					},
+				},
+			},
+			expectError: true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -290,3 +345,13 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDenyAnyRules(t *testing.T) {
+	assert := tassert.New(t)
+
+	rules := GetDenyAnyRules("egress-audit")
+	assert.Equal(xds_rbac.RBAC_DENY, rules.Action)
+	assert.Len(rules.Policies, 1)
+	assert.Equal([]*xds_rbac.Permission{getAnyPermission()}, rules.Policies["egress-audit"].Permissions)
+	assert.Equal([]*xds_rbac.Principal{getAnyPrincipal()}, rules.Policies["egress-audit"].Principals)
+}
@@ -8,6 +8,9 @@ type RuleAttribute string
 const (
 	// DownstreamAuthPrincipal is the key used for the name of the downstream principal in a policy Rule
 	DownstreamAuthPrincipal RuleAttribute = "downstreamAuthPrincipal"
+
+	// DownstreamRemoteIP is the key used for the CIDR range of the downstream remote IP in a policy Rule
+	DownstreamRemoteIP RuleAttribute = "downstreamRemoteIP"
 )
 
 // Supported attributes for an RBAC permission
@@ -0,0 +1,156 @@
+package rds
+
+import (
+	set "github.com/deckarep/golang-set"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/route"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// localIngressRouteSuffix is the suffix of the name of the route serving the requests forwarded by other backends
+	localIngressRouteSuffix = "|local"
+)
+
+// resolveIngressBackends returns the upstream clusters the given ingress policies of the given service, fronted by the
+// proxy, route requests to. The requests received from ingress for the apex service of a TrafficSplit are split between
+// its backends, and the backends not fronted by the proxy are reached through their upstream clusters. The backends the
+// proxy is not allowed to connect to are removed from the policies. When the proxy fronts none of the backends of a rule,
+// the requests matching the rule are served by the service as if it was not split.
+func resolveIngressBackends(policies []*trafficpolicy.InboundTrafficPolicy, svc service.MeshService, proxyServices []service.MeshService, listAllowedOutboundServices func() []service.MeshService) set.Set {
+	localClusters := set.NewSet()
+	for _, proxyService := range proxyServices {
+		localClusters.Add(service.ClusterName(proxyService.String()))
+	}
+
+	upstreamClusters := set.NewSet()
+	var allowedClusters set.Set
+	for _, policy := range policies {
+		for _, rule := range policy.Rules {
+			weightedClusters := set.NewSet()
+			ruleUpstreamClusters := set.NewSet()
+			servedLocally := false
+			for clusterInterface := range rule.Route.WeightedClusters.Iter() {
+				weightedCluster := clusterInterface.(service.WeightedCluster)
+				// The clusters of the ports of the pod of the proxy are always local
+				if localClusters.Contains(weightedCluster.ClusterName) || envoy.IsClusterNameForPort(string(weightedCluster.ClusterName)) {
+					weightedClusters.Add(weightedCluster)
+					servedLocally = true
+					continue
+				}
+
+				// The allowed outbound services are only listed when a rule routes to a service not fronted by the proxy
+				if allowedClusters == nil {
+					allowedClusters = set.NewSet()
+					for _, allowedService := range listAllowedOutboundServices() {
+						allowedClusters.Add(service.ClusterName(allowedService.String()))
+					}
+				}
+				if !allowedClusters.Contains(weightedCluster.ClusterName) {
+					log.Warn().Msgf("Ignoring backend %s of the requests received from ingress for service %s, not allowed by the traffic policies of the proxy", weightedCluster.ClusterName, svc)
+					continue
+				}
+				weightedClusters.Add(weightedCluster)
+				ruleUpstreamClusters.Add(weightedCluster.ClusterName)
+			}
+
+			if !servedLocally {
+				log.Warn().Msgf("Proxy fronts none of the backends of the requests received from ingress for service %s, serving them without split", svc)
+				rule.Route.WeightedClusters = set.NewSet(service.WeightedCluster{
+					ClusterName: service.ClusterName(svc.String()),
+					Weight:      constants.ClusterWeightAcceptAll,
+				})
+				continue
+			}
+			rule.Route.WeightedClusters = weightedClusters
+			upstreamClusters = upstreamClusters.Union(ruleUpstreamClusters)
+		}
+	}
+	return upstreamClusters
+}
+
+// routeIngressBackendsUpstream updates the inbound routes of the given route configurations splitting the requests
+// received from ingress between backends, so that the given upstream clusters are reached through the mesh instead of
+// as local clusters. Each route is preceded by a route serving the requests forwarded by the proxies of other backends
+// with the backends fronted by the proxy.
+func routeIngressBackendsUpstream(routeConfigurations []*xds_route.RouteConfiguration, upstreamClusters set.Set) {
+	if upstreamClusters.Cardinality() == 0 {
+		return
+	}
+
+	upstreamClusterByLocalName := make(map[string]string)
+	for clusterInterface := range upstreamClusters.Iter() {
+		clusterName := string(clusterInterface.(service.ClusterName))
+		upstreamClusterByLocalName[envoy.GetLocalClusterNameForServiceCluster(clusterName)] = clusterName
+	}
+
+	for _, routeConfig := range routeConfigurations {
+		if routeConfig.Name != route.InboundRouteConfigName {
+			continue
+		}
+		for _, virtualHost := range routeConfig.VirtualHosts {
+			var routes []*xds_route.Route
+			for _, xdsRoute := range virtualHost.Routes {
+				if localRoute := splitIngressRoute(xdsRoute, upstreamClusterByLocalName); localRoute != nil {
+					routes = append(routes, localRoute)
+				}
+				routes = append(routes, xdsRoute)
+			}
+			virtualHost.Routes = routes
+		}
+	}
+}
+
+// splitIngressRoute updates the clusters of the given route that are reached upstream, and returns the route serving the
+// requests forwarded by the proxies of other backends with the local clusters of the route. It returns nil when the
+// route does not route to any upstream cluster.
+func splitIngressRoute(xdsRoute *xds_route.Route, upstreamClusterByLocalName map[string]string) *xds_route.Route {
+	weightedClusters := xdsRoute.GetRoute().GetWeightedClusters()
+	if weightedClusters == nil {
+		return nil
+	}
+
+	localRoute := proto.Clone(xdsRoute).(*xds_route.Route)
+	var localClusters []*xds_route.WeightedCluster_ClusterWeight
+	var localWeight uint32
+	for _, cluster := range weightedClusters.Clusters {
+		upstreamCluster, ok := upstreamClusterByLocalName[cluster.Name]
+		if !ok {
+			localClusters = append(localClusters, proto.Clone(cluster).(*xds_route.WeightedCluster_ClusterWeight))
+			localWeight += cluster.Weight.GetValue()
+			continue
+		}
+		cluster.Name = upstreamCluster
+		cluster.RequestHeadersToAdd = append(cluster.RequestHeadersToAdd, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:   envoy.IngressBackendHeader,
+				Value: upstreamCluster,
+			},
+		})
+	}
+	if len(localClusters) == len(weightedClusters.Clusters) {
+		return nil
+	}
+
+	localRoute.Name = envoy.BoundedName(xdsRoute.Name + localIngressRouteSuffix)
+	localRoute.Match.Headers = append(localRoute.Match.Headers, &xds_route.HeaderMatcher{
+		Name: envoy.IngressBackendHeader,
+		HeaderMatchSpecifier: &xds_route.HeaderMatcher_PresentMatch{
+			PresentMatch: true,
+		},
+	})
+	localRoute.GetRoute().ClusterSpecifier = &xds_route.RouteAction_WeightedClusters{
+		WeightedClusters: &xds_route.WeightedCluster{
+			Clusters:    localClusters,
+			TotalWeight: &wrappers.UInt32Value{Value: localWeight},
+		},
+	}
+	return localRoute
+}
@@ -0,0 +1,124 @@
+package rds
+
+import (
+	"testing"
+
+	set "github.com/deckarep/golang-set"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/route"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func newIngressSplitPolicy() *trafficpolicy.InboundTrafficPolicy {
+	policy := trafficpolicy.NewInboundTrafficPolicy("bookstore-apex-ingress|*", []string{"*"})
+	policy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{
+		{ClusterName: service.ClusterName(tests.BookstoreV1Service.String()), Weight: tests.Weight90},
+		{ClusterName: service.ClusterName(tests.BookstoreV2Service.String()), Weight: tests.Weight10},
+	}), service.K8sServiceAccount{})
+	return policy
+}
+
+func TestResolveIngressBackends(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		proxyServices            []service.MeshService
+		allowedOutboundServices  []service.MeshService
+		portClusters             bool
+		expectedWeightedClusters set.Set
+		expectedUpstreamClusters set.Set
+	}{
+		{
+			name:                    "backends not fronted by the proxy are reached upstream",
+			proxyServices:           []service.MeshService{tests.BookstoreApexService, tests.BookstoreV1Service},
+			allowedOutboundServices: []service.MeshService{tests.BookstoreV2Service},
+			expectedWeightedClusters: set.NewSet(
+				service.WeightedCluster{ClusterName: service.ClusterName(tests.BookstoreV1Service.String()), Weight: tests.Weight90},
+				service.WeightedCluster{ClusterName: service.ClusterName(tests.BookstoreV2Service.String()), Weight: tests.Weight10},
+			),
+			expectedUpstreamClusters: set.NewSet(service.ClusterName(tests.BookstoreV2Service.String())),
+		},
+		{
+			name:          "backends the proxy is not allowed to connect to are removed",
+			proxyServices: []service.MeshService{tests.BookstoreApexService, tests.BookstoreV1Service},
+			expectedWeightedClusters: set.NewSet(
+				service.WeightedCluster{ClusterName: service.ClusterName(tests.BookstoreV1Service.String()), Weight: tests.Weight90},
+			),
+			expectedUpstreamClusters: set.NewSet(),
+		},
+		{
+			name:          "backends of a port of the pod of the proxy are local",
+			proxyServices: []service.MeshService{tests.BookstoreApexService, tests.BookstoreV1Service},
+			portClusters:  true,
+			expectedWeightedClusters: set.NewSet(
+				service.WeightedCluster{ClusterName: service.ClusterName(envoy.GetClusterNameForPort(8080)), Weight: tests.Weight90},
+				service.WeightedCluster{ClusterName: service.ClusterName(envoy.GetClusterNameForPort(8081)), Weight: tests.Weight10},
+			),
+			expectedUpstreamClusters: set.NewSet(),
+		},
+		{
+			name:                    "requests are not split when the proxy fronts none of the backends",
+			proxyServices:           []service.MeshService{tests.BookstoreApexService},
+			allowedOutboundServices: []service.MeshService{tests.BookstoreV1Service, tests.BookstoreV2Service},
+			expectedWeightedClusters: set.NewSet(
+				service.WeightedCluster{ClusterName: service.ClusterName(tests.BookstoreApexService.String()), Weight: constants.ClusterWeightAcceptAll},
+			),
+			expectedUpstreamClusters: set.NewSet(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			policy := newIngressSplitPolicy()
+			if tc.portClusters {
+				policy.Rules[0].Route.WeightedClusters = set.NewSet(
+					service.WeightedCluster{ClusterName: service.ClusterName(envoy.GetClusterNameForPort(8080)), Weight: tests.Weight90},
+					service.WeightedCluster{ClusterName: service.ClusterName(envoy.GetClusterNameForPort(8081)), Weight: tests.Weight10},
+				)
+			}
+			upstreamClusters := resolveIngressBackends([]*trafficpolicy.InboundTrafficPolicy{policy}, tests.BookstoreApexService, tc.proxyServices, func() []service.MeshService {
+				return tc.allowedOutboundServices
+			})
+
+			assert.True(tc.expectedWeightedClusters.Equal(policy.Rules[0].Route.WeightedClusters))
+			assert.True(tc.expectedUpstreamClusters.Equal(upstreamClusters))
+		})
+	}
+}
+
+func TestRouteIngressBackendsUpstream(t *testing.T) {
+	assert := tassert.New(t)
+
+	routeConfigurations := route.BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{newIngressSplitPolicy()}, nil, nil, nil)
+	routeIngressBackendsUpstream(routeConfigurations, set.NewSet(service.ClusterName(tests.BookstoreV2Service.String())))
+
+	assert.Len(routeConfigurations, 1)
+	assert.Len(routeConfigurations[0].VirtualHosts, 1)
+	routes := routeConfigurations[0].VirtualHosts[0].Routes
+	assert.Len(routes, 2)
+
+	// The requests forwarded by the proxies of other backends are served by the local backend
+	localRoute := routes[0]
+	assert.Equal(routes[1].Name+localIngressRouteSuffix, localRoute.Name)
+	assert.Equal(envoy.IngressBackendHeader, localRoute.Match.Headers[len(localRoute.Match.Headers)-1].Name)
+	localClusters := localRoute.GetRoute().GetWeightedClusters()
+	assert.Len(localClusters.Clusters, 1)
+	assert.Equal("default/bookstore-v1-local", localClusters.Clusters[0].Name)
+	assert.Equal(uint32(tests.Weight90), localClusters.TotalWeight.GetValue())
+
+	// The requests received from ingress are split between the local backend and the upstream backend
+	splitClusters := routes[1].GetRoute().GetWeightedClusters()
+	assert.Len(splitClusters.Clusters, 2)
+	assert.Equal("default/bookstore-v1-local", splitClusters.Clusters[0].Name)
+	assert.Empty(splitClusters.Clusters[0].RequestHeadersToAdd)
+	assert.Equal("default/bookstore-v2", splitClusters.Clusters[1].Name)
+	assert.Len(splitClusters.Clusters[1].RequestHeadersToAdd, 1)
+	assert.Equal(envoy.IngressBackendHeader, splitClusters.Clusters[1].RequestHeadersToAdd[0].Header.Key)
+	assert.Equal(uint32(100), splitClusters.TotalWeight.GetValue())
+}
@@ -1,6 +1,7 @@
 package rds
 
 import (
+	set "github.com/deckarep/golang-set"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/golang/protobuf/ptypes"
 
@@ -9,14 +10,12 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/route"
+	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // NewResponse creates a new Route Discovery Response.
-func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
-	var inboundTrafficPolicies []*trafficpolicy.InboundTrafficPolicy
-	var outboundTrafficPolicies []*trafficpolicy.OutboundTrafficPolicy
-
+func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
 	proxyIdentity, err := catalog.GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
 		log.Error().Err(err).Msgf("Error looking up Service Account for Envoy with serial number=%q", proxy.GetCertificateSerialNumber())
@@ -24,27 +23,56 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_dis
 	}
 
 	services, err := cataloger.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
-	if err != nil {
+	if err != nil && proxy.IsDevProxy() {
+		// A dev proxy is not backed by a Pod, so it does not front any service
+		services = nil
+	} else if err != nil {
 		log.Error().Err(err).Msgf("Error looking up services for Envoy with serial number=%q", proxy.GetCertificateSerialNumber())
 		return nil, err
 	}
 
 	// Build traffic policies from  either SMI Traffic Target and Traffic Split or service discovery
-	// depending on whether permissive mode is enabled or not
-	inboundTrafficPolicies = cataloger.ListInboundTrafficPolicies(proxyIdentity, services)
-	outboundTrafficPolicies = cataloger.ListOutboundTrafficPolicies(proxyIdentity)
+	// depending on whether permissive mode is enabled or not. The inbound policies are compiled once the policies
+	// of the ingress source are added to them.
+	inboundTrafficPolicies := cataloger.ListInboundPolicySet(proxyIdentity, services)
+	outboundTrafficPolicies := cataloger.ListOutboundTrafficPolicies(proxyIdentity)
 
 	// Get Ingress inbound policies for the proxy
+	listAllowedOutboundServices := func() []service.MeshService {
+		return cataloger.ListAllowedOutboundServicesForIdentity(proxyIdentity)
+	}
+	ingressUpstreamClusters := set.NewSet()
 	for _, svc := range services {
 		ingressInboundPolicies, err := cataloger.GetIngressPoliciesForService(svc)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error looking up ingress policies for service=%s", svc.String())
 			return nil, err
 		}
-		inboundTrafficPolicies = trafficpolicy.MergeInboundPolicies(true, inboundTrafficPolicies, ingressInboundPolicies...)
+		ingressUpstreamClusters = ingressUpstreamClusters.Union(resolveIngressBackends(ingressInboundPolicies, svc, services, listAllowedOutboundServices))
+		inboundTrafficPolicies.Add(trafficpolicy.IngressSource, ingressInboundPolicies...)
+	}
+
+	// Get the SMI policies the routes are attributed to, for the route stats to be broken down per policy
+	routingPolicies := cataloger.ListRoutingPolicies(proxyIdentity)
+
+	routeConfiguration := route.BuildRouteConfiguration(inboundTrafficPolicies.Policies(), outboundTrafficPolicies, proxy, routingPolicies)
+	routeIngressBackendsUpstream(routeConfiguration, ingressUpstreamClusters)
+
+	// Add the route configs of the external HTTP destinations allowed by the Egress policies of the proxy
+	if egressPolicy := cataloger.GetEgressPolicy(proxyIdentity); egressPolicy != nil {
+		egressRouteConfiguration, err := route.BuildEgressRouteConfiguration(egressPolicy, cataloger.IsEgressAuditEnabled(proxy))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building egress route configs of Egress policies %v for proxy with identity %s", egressPolicy.Names, proxyIdentity)
+			return nil, err
+		}
+		routeConfiguration = append(routeConfiguration, egressRouteConfiguration...)
+
+		// Add the route config of the explicit HTTP proxy if the Egress policies allow hosts to be reached through it
+		if httpProxyRouteConfig := route.BuildEgressHTTPProxyRouteConfiguration(egressPolicy); httpProxyRouteConfig != nil {
+			routeConfiguration = append(routeConfiguration, httpProxyRouteConfig)
+		}
 	}
 
-	routeConfiguration := route.BuildRouteConfiguration(inboundTrafficPolicies, outboundTrafficPolicies, proxy)
 	resp := &xds_discovery.DiscoveryResponse{
 		TypeUrl: string(envoy.TypeRDS),
 	}
@@ -270,9 +270,13 @@ func TestNewResponse(t *testing.T) {
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 
 			mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return([]service.MeshService{tests.BookstoreV1Service}, nil).AnyTimes()
-			mockCatalog.EXPECT().ListInboundTrafficPolicies(gomock.Any(), gomock.Any()).Return(tc.expectedInboundPolicies).AnyTimes()
+			inboundPolicies := trafficpolicy.NewInboundPolicySet()
+			inboundPolicies.Add(trafficpolicy.TrafficTargetSource, tc.expectedInboundPolicies...)
+			mockCatalog.EXPECT().ListInboundPolicySet(gomock.Any(), gomock.Any()).Return(inboundPolicies).AnyTimes()
 			mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Return(tc.expectedOutboundPolicies).AnyTimes()
 			mockCatalog.EXPECT().GetIngressPoliciesForService(gomock.Any()).Return(tc.ingressInboundPolicies, nil).AnyTimes()
+			mockCatalog.EXPECT().ListRoutingPolicies(gomock.Any()).Return(nil).AnyTimes()
+			mockCatalog.EXPECT().GetEgressPolicy(gomock.Any()).Return(nil).AnyTimes()
 
 			actual, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
 			assert.Nil(err)
@@ -290,35 +294,35 @@ func TestNewResponse(t *testing.T) {
 				t.Fatal(unmarshallErr)
 			}
 
-			// The rds-inbound will have the following virtual hosts :
-			// inbound_virtual-host|bookstore-v1.default
+			// The rds-inbound will have the following virtual hosts, sorted by name:
 			// inbound_virtual-host|bookstore-apex
+			// inbound_virtual-host|bookstore-v1.default
 			// inbound_virtual-host|bookstore-v1.default|*
 			assert.Equal("rds-inbound", routeConfig.Name)
 			assert.Equal(3, len(routeConfig.VirtualHosts))
 
-			assert.Equal("inbound_virtual-host|bookstore-v1.default", routeConfig.VirtualHosts[0].Name)
-			assert.Equal(tests.BookstoreV1Hostnames, routeConfig.VirtualHosts[0].Domains)
-			assert.Equal(3, len(routeConfig.VirtualHosts[0].Routes))
+			assert.Equal("inbound_virtual-host|bookstore-apex", routeConfig.VirtualHosts[0].Name)
+			assert.Equal(tests.BookstoreApexHostnames, routeConfig.VirtualHosts[0].Domains)
+			assert.Equal(2, len(routeConfig.VirtualHosts[0].Routes))
 			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[0].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes[0].GetRoute().GetWeightedClusters().Clusters))
 			assert.Equal(routeConfig.VirtualHosts[0].Routes[0].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
 			assert.Equal(tests.BookstoreSellHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[1].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes[1].GetRoute().GetWeightedClusters().Clusters))
 			assert.Equal(routeConfig.VirtualHosts[0].Routes[1].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
-			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[2].GetMatch().GetSafeRegex().Regex)
-			assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes[2].GetRoute().GetWeightedClusters().Clusters))
-			assert.Equal(routeConfig.VirtualHosts[0].Routes[2].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
 
-			assert.Equal("inbound_virtual-host|bookstore-apex", routeConfig.VirtualHosts[1].Name)
-			assert.Equal(tests.BookstoreApexHostnames, routeConfig.VirtualHosts[1].Domains)
-			assert.Equal(2, len(routeConfig.VirtualHosts[1].Routes))
+			assert.Equal("inbound_virtual-host|bookstore-v1.default", routeConfig.VirtualHosts[1].Name)
+			assert.Equal(tests.BookstoreV1Hostnames, routeConfig.VirtualHosts[1].Domains)
+			assert.Equal(3, len(routeConfig.VirtualHosts[1].Routes))
 			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[1].Routes[0].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[1].Routes[0].GetRoute().GetWeightedClusters().Clusters))
 			assert.Equal(routeConfig.VirtualHosts[1].Routes[0].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
 			assert.Equal(tests.BookstoreSellHTTPRoute.Path, routeConfig.VirtualHosts[1].Routes[1].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[1].Routes[1].GetRoute().GetWeightedClusters().Clusters))
 			assert.Equal(routeConfig.VirtualHosts[1].Routes[1].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
+			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[1].Routes[2].GetMatch().GetSafeRegex().Regex)
+			assert.Equal(1, len(routeConfig.VirtualHosts[1].Routes[2].GetRoute().GetWeightedClusters().Clusters))
+			assert.Equal(routeConfig.VirtualHosts[1].Routes[2].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
 
 			assert.Equal("inbound_virtual-host|bookstore-v1.default|*", routeConfig.VirtualHosts[2].Name)
 			assert.Equal([]string{"*"}, routeConfig.VirtualHosts[2].Domains)
@@ -499,9 +503,13 @@ func TestNewResponseWithPermissiveMode(t *testing.T) {
 	}
 
 	mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return([]service.MeshService{tests.BookstoreV1Service}, nil).AnyTimes()
-	mockCatalog.EXPECT().ListInboundTrafficPolicies(gomock.Any(), gomock.Any()).Return(testPermissiveInbound).AnyTimes()
+	inboundPolicies := trafficpolicy.NewInboundPolicySet()
+	inboundPolicies.Add(trafficpolicy.PermissiveSource, testPermissiveInbound...)
+	mockCatalog.EXPECT().ListInboundPolicySet(gomock.Any(), gomock.Any()).Return(inboundPolicies).AnyTimes()
 	mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Return(testPermissiveOutbound).AnyTimes()
 	mockCatalog.EXPECT().GetIngressPoliciesForService(gomock.Any()).Return(testIngressInbound, nil).AnyTimes()
+	mockCatalog.EXPECT().ListRoutingPolicies(gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressPolicy(gomock.Any()).Return(nil).AnyTimes()
 
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
 
@@ -519,8 +527,9 @@ func TestNewResponseWithPermissiveMode(t *testing.T) {
 	assert.Equal("inbound_virtual-host|bookstore-v1.default", routeConfig.VirtualHosts[0].Name)
 	assert.Equal(tests.BookstoreV1Hostnames, routeConfig.VirtualHosts[0].Domains)
 	assert.Equal(2, len(routeConfig.VirtualHosts[0].Routes))
-	assert.Equal(constants.RegexMatchAll, routeConfig.VirtualHosts[0].Routes[0].GetMatch().GetSafeRegex().Regex)
-	assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[1].GetMatch().GetSafeRegex().Regex)
+	// The ingress route is not shadowed by the wildcard route of permissive mode
+	assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[0].GetMatch().GetSafeRegex().Regex)
+	assert.Equal(constants.RegexMatchAll, routeConfig.VirtualHosts[0].Routes[1].GetMatch().GetSafeRegex().Regex)
 
 	assert.Equal("inbound_virtual-host|bookstore-v1.default|*", routeConfig.VirtualHosts[1].Name)
 	assert.Equal([]string{"*"}, routeConfig.VirtualHosts[1].Domains)
@@ -0,0 +1,149 @@
+package envoy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// ResourceKind is a kind of xDS resource whose names must be unique among the resources sent to a proxy
+type ResourceKind string
+
+const (
+	// ClusterResourceKind is the kind of the clusters sent to proxies over CDS
+	ClusterResourceKind ResourceKind = "cluster"
+
+	// ListenerResourceKind is the kind of the listeners sent to proxies over LDS
+	ListenerResourceKind ResourceKind = "listener"
+)
+
+// resourceConflictsMutex guards the resource conflicts recorded on proxies, which are read by the debug server while
+// the proxies are being configured
+var resourceConflictsMutex sync.RWMutex
+
+// NamedResource is an xDS resource generated for a proxy, along with the source of its config.
+type NamedResource struct {
+	// Name is the name of the resource, which Envoy requires to be unique among the resources of its kind
+	Name string
+
+	// Source identifies the config the resource was generated from, such as a service or a port
+	Source string
+
+	// Resource is the generated resource
+	Resource proto.Message
+}
+
+// ResourceConflict is a record of resources of the same kind generated for a proxy with the same name.
+type ResourceConflict struct {
+	// Proxy is the common name of the certificate of the proxy the resources were generated for
+	Proxy certificate.CommonName `json:"proxy"`
+
+	// Kind is the kind of the resources
+	Kind ResourceKind `json:"kind"`
+
+	// Name is the name shared by the resources
+	Name string `json:"name"`
+
+	// KeptSource is the source of the resource sent to the proxy
+	KeptSource string `json:"kept_source"`
+
+	// DroppedSources are the sources of the resources not sent to the proxy
+	DroppedSources []string `json:"dropped_sources"`
+
+	// DetectedAt is the time the conflict was last detected
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// RemoveConflictingResources returns the given resources keeping a single resource of each name, in the order of the
+// first resource of each name. Envoy rejects a response whose resources do not have unique names, with an error that
+// does not tell where the resources came from. Of the resources sharing a name, the one whose source sorts first is
+// kept, so that the proxy receives the same resource regardless of the order the resources were generated in. The
+// conflicts are logged, counted, and recorded on the proxy for the debug server, naming the sources of the colliding
+// resources.
+func RemoveConflictingResources(proxy *Proxy, kind ResourceKind, resources []NamedResource) []NamedResource {
+	var names []string
+	resourcesByName := make(map[string][]NamedResource)
+	for _, resource := range resources {
+		if _, ok := resourcesByName[resource.Name]; !ok {
+			names = append(names, resource.Name)
+		}
+		resourcesByName[resource.Name] = append(resourcesByName[resource.Name], resource)
+	}
+
+	kept := make([]NamedResource, 0, len(names))
+	var conflicts []ResourceConflict
+	now := time.Now()
+	for _, name := range names {
+		colliding := resourcesByName[name]
+		sort.SliceStable(colliding, func(i, j int) bool {
+			return colliding[i].Source < colliding[j].Source
+		})
+		kept = append(kept, colliding[0])
+		if len(colliding) == 1 {
+			continue
+		}
+
+		conflict := ResourceConflict{
+			Proxy:      proxy.GetCertificateCommonName(),
+			Kind:       kind,
+			Name:       name,
+			KeptSource: colliding[0].Source,
+			DetectedAt: now,
+		}
+		for _, resource := range colliding[1:] {
+			conflict.DroppedSources = append(conflict.DroppedSources, resource.Source)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	for _, conflict := range conflicts {
+		log.Error().Msgf("Found %d %ss named %s for proxy with SerialNumber=%s on Pod with UID=%s; keeping the %s generated from %s, dropping the ones generated from %s",
+			len(conflict.DroppedSources)+1, kind, conflict.Name, proxy.GetCertificateSerialNumber(), proxy.GetPodUID(),
+			kind, conflict.KeptSource, strings.Join(conflict.DroppedSources, ", "))
+		metricsstore.DefaultMetricsStore.ProxyResourceConflictCount.WithLabelValues(string(kind)).Add(float64(len(conflict.DroppedSources)))
+	}
+
+	proxy.setResourceConflicts(kind, conflicts)
+	return kept
+}
+
+// setResourceConflicts records the conflicts between the names of the resources of the given kind last generated for
+// the proxy, replacing the conflicts previously recorded for this kind
+func (p *Proxy) setResourceConflicts(kind ResourceKind, conflicts []ResourceConflict) {
+	resourceConflictsMutex.Lock()
+	defer resourceConflictsMutex.Unlock()
+
+	if len(conflicts) == 0 {
+		delete(p.resourceConflicts, kind)
+		return
+	}
+	if p.resourceConflicts == nil {
+		p.resourceConflicts = make(map[ResourceKind][]ResourceConflict)
+	}
+	p.resourceConflicts[kind] = conflicts
+}
+
+// GetResourceConflicts returns the conflicts between the names of the resources last generated for the proxy, sorted
+// by kind and name.
+func (p *Proxy) GetResourceConflicts() []ResourceConflict {
+	resourceConflictsMutex.RLock()
+	defer resourceConflictsMutex.RUnlock()
+
+	var conflicts []ResourceConflict
+	for _, kindConflicts := range p.resourceConflicts {
+		conflicts = append(conflicts, kindConflicts...)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind < conflicts[j].Kind
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts
+}
@@ -0,0 +1,57 @@
+package envoy
+
+import (
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+func TestRemoveConflictingResources(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy := NewProxy(certificate.CommonName("bookbuyer"), certificate.SerialNumber("1"), nil)
+	newCluster := func(name, source string) NamedResource {
+		return NamedResource{Name: name, Source: source, Resource: &xds_cluster.Cluster{Name: name}}
+	}
+
+	resources := []NamedResource{
+		newCluster("bookstore/bookstore", "upstream service bookstore/bookstore"),
+		newCluster("passthrough-outbound", "egress"),
+		newCluster("bookstore/bookstore", "external traffic to port 80 of service bookstore/bookstore"),
+		newCluster("bookstore/bookstore", "WAF module http://waf.example.com"),
+	}
+
+	kept := RemoveConflictingResources(proxy, ClusterResourceKind, resources)
+	assert.Equal([]NamedResource{resources[3], resources[1]}, kept)
+
+	// The kept resource does not depend on the order the resources were generated in
+	reversed := []NamedResource{resources[3], resources[2], resources[1], resources[0]}
+	assert.Equal([]NamedResource{resources[3], resources[1]}, RemoveConflictingResources(proxy, ClusterResourceKind, reversed))
+
+	conflicts := proxy.GetResourceConflicts()
+	assert.Len(conflicts, 1)
+	assert.Equal(certificate.CommonName("bookbuyer"), conflicts[0].Proxy)
+	assert.Equal(ClusterResourceKind, conflicts[0].Kind)
+	assert.Equal("bookstore/bookstore", conflicts[0].Name)
+	assert.Equal("WAF module http://waf.example.com", conflicts[0].KeptSource)
+	assert.Equal([]string{
+		"external traffic to port 80 of service bookstore/bookstore",
+		"upstream service bookstore/bookstore",
+	}, conflicts[0].DroppedSources)
+
+	// Conflicts of other kinds are recorded separately
+	RemoveConflictingResources(proxy, ListenerResourceKind, []NamedResource{
+		{Name: "inbound-listener", Source: "services [bookbuyer/bookbuyer]"},
+		{Name: "inbound-listener", Source: "Prometheus scraping"},
+	})
+	assert.Len(proxy.GetResourceConflicts(), 2)
+
+	// Resolved conflicts are no longer reported
+	RemoveConflictingResources(proxy, ClusterResourceKind, resources[:2])
+	conflicts = proxy.GetResourceConflicts()
+	assert.Len(conflicts, 1)
+	assert.Equal(ListenerResourceKind, conflicts[0].Kind)
+}
@@ -0,0 +1,51 @@
+package route
+
+import (
+	xds_fault_common "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	xds_fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/pkg/errors"
+)
+
+// BuildBandwidthLimitFault returns the configuration of the fault injection filter limiting to the given rate, in KiB/s,
+// the transfer of the body of each HTTP response. Envoy's bandwidth limit filter is not available in the Envoy versions
+// supported by OSM, so the limit is enforced by the response rate limit of the fault injection filter, applied to every
+// response. No fault is injected for a limit of 0, so that the filter only applies the limits of the routes.
+func BuildBandwidthLimitFault(limitKiBps uint64) *xds_fault.HTTPFault {
+	if limitKiBps == 0 {
+		return &xds_fault.HTTPFault{}
+	}
+
+	return &xds_fault.HTTPFault{
+		ResponseRateLimit: &xds_fault_common.FaultRateLimit{
+			LimitType: &xds_fault_common.FaultRateLimit_FixedLimit_{
+				FixedLimit: &xds_fault_common.FaultRateLimit_FixedLimit{
+					LimitKbps: limitKiBps,
+				},
+			},
+			Percentage: &xds_type.FractionalPercent{
+				Numerator:   100,
+				Denominator: xds_type.FractionalPercent_HUNDRED,
+			},
+		},
+	}
+}
+
+// addBandwidthLimitPerFilterConfig adds to the given per filter configs of a route the configuration of the fault
+// injection filter limiting the responses to the requests matching the route to the given rate, in KiB/s, overriding the
+// limit of the HTTP connection manager. Nothing is added for a limit of 0.
+func addBandwidthLimitPerFilterConfig(perFilterConfig map[string]*any.Any, limitKiBps uint64) error {
+	if limitKiBps == 0 {
+		return nil
+	}
+
+	faultAny, err := ptypes.MarshalAny(BuildBandwidthLimitFault(limitKiBps))
+	if err != nil {
+		return errors.Wrap(err, "Error marshalling bandwidth limit fault filter config of route")
+	}
+	perFilterConfig[wellknown.Fault] = faultAny
+	return nil
+}
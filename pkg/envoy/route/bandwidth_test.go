@@ -0,0 +1,38 @@
+package route
+
+import (
+	"testing"
+
+	xds_fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestBuildBandwidthLimitFault(t *testing.T) {
+	assert := tassert.New(t)
+
+	fault := BuildBandwidthLimitFault(256)
+	assert.Equal(uint64(256), fault.ResponseRateLimit.GetFixedLimit().GetLimitKbps())
+	assert.Equal(uint32(100), fault.ResponseRateLimit.GetPercentage().GetNumerator())
+	assert.Nil(fault.Delay)
+	assert.Nil(fault.Abort)
+
+	assert.Nil(BuildBandwidthLimitFault(0).ResponseRateLimit)
+}
+
+func TestAddBandwidthLimitPerFilterConfig(t *testing.T) {
+	assert := tassert.New(t)
+
+	perFilterConfig := map[string]*any.Any{}
+	assert.Nil(addBandwidthLimitPerFilterConfig(perFilterConfig, 0))
+	assert.Empty(perFilterConfig)
+
+	assert.Nil(addBandwidthLimitPerFilterConfig(perFilterConfig, 128))
+	assert.Len(perFilterConfig, 1)
+
+	fault := &xds_fault.HTTPFault{}
+	assert.Nil(ptypes.UnmarshalAny(perFilterConfig[wellknown.Fault], fault))
+	assert.Equal(uint64(128), fault.ResponseRateLimit.GetFixedLimit().GetLimitKbps())
+}
@@ -0,0 +1,25 @@
+package route
+
+import (
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// buildRequestMirrorPolicies returns the policies copying the given percentage of the requests of an outbound route to
+// the shadow service of a dark launch. Envoy discards the responses of the shadow service.
+func buildRequestMirrorPolicies(darkLaunch trafficpolicy.DarkLaunchPolicy) []*xds_route.RouteAction_RequestMirrorPolicy {
+	return []*xds_route.RouteAction_RequestMirrorPolicy{
+		{
+			Cluster: string(darkLaunch.ShadowCluster),
+			RuntimeFraction: &core.RuntimeFractionalPercent{
+				DefaultValue: &xds_type.FractionalPercent{
+					Numerator:   darkLaunch.Percentage,
+					Denominator: xds_type.FractionalPercent_HUNDRED,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package route
+
+import (
+	"testing"
+
+	set "github.com/deckarep/golang-set"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildRouteConfigurationDarkLaunch(t *testing.T) {
+	assert := tassert.New(t)
+
+	outbound := []*trafficpolicy.OutboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1",
+			Hostnames: tests.BookstoreV1Hostnames,
+			Routes: []*trafficpolicy.RouteWeightedClusters{
+				{
+					HTTPRouteMatch:   tests.WildCardRouteMatch,
+					WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+			},
+		},
+		{
+			Name:      "bookbuyer",
+			Hostnames: []string{"bookbuyer"},
+			Routes: []*trafficpolicy.RouteWeightedClusters{
+				{
+					HTTPRouteMatch:   tests.WildCardRouteMatch,
+					WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+			},
+		},
+	}
+	routingPolicies := &trafficpolicy.RoutingPolicies{
+		OutboundDarkLaunches: map[string]trafficpolicy.DarkLaunchPolicy{
+			"bookstore-v1": {
+				ShadowCluster: "default/bookstore-v2",
+				Percentage:    25,
+			},
+		},
+	}
+
+	actual := BuildRouteConfiguration(nil, outbound, nil, routingPolicies)
+	assert.Len(actual, 1)
+	assert.Len(actual[0].VirtualHosts, 2)
+
+	// The requests to the dark launched service are copied to the shadow service
+	darkLaunchedRoute := actual[0].VirtualHosts[1].Routes[0].GetRoute()
+	assert.Len(darkLaunchedRoute.RequestMirrorPolicies, 1)
+	mirrorPolicy := darkLaunchedRoute.RequestMirrorPolicies[0]
+	assert.Equal("default/bookstore-v2", mirrorPolicy.Cluster)
+	assert.Equal(uint32(25), mirrorPolicy.RuntimeFraction.DefaultValue.Numerator)
+	assert.Equal(xds_type.FractionalPercent_HUNDRED, mirrorPolicy.RuntimeFraction.DefaultValue.Denominator)
+
+	// The requests to other services are not copied
+	assert.Empty(actual[0].VirtualHosts[0].Routes[0].GetRoute().RequestMirrorPolicies)
+}
@@ -0,0 +1,229 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// egressRouteConfigPrefix is the prefix of the names of the route configs of the HTTP egress traffic
+	egressRouteConfigPrefix = "rds-egress"
+
+	// egressVirtualHost is the name of the virtual hosts on the egress route configurations
+	egressVirtualHost = "egress_virtual-host"
+
+	// egressAuditVirtualHost and egressAuditRouteName are the names of the virtual host and route matching the requests
+	// for the hosts not allowed by an egress policy evaluated in audit mode
+	egressAuditVirtualHost = "egress-audit_virtual-host"
+	egressAuditRouteName   = "egress-audit"
+
+	// EgressHTTPProxyRouteConfigName is the name of the route config of the explicit HTTP proxy for the external hosts
+	EgressHTTPProxyRouteConfigName = "rds-egress-http-proxy"
+
+	// egressHTTPProxyVirtualHost is the name of the virtual hosts on the route config of the explicit HTTP proxy
+	egressHTTPProxyVirtualHost = "egress-http-proxy_virtual-host"
+
+	// defaultHTTPPort is the port of the HTTP requests whose authority does not include a port
+	defaultHTTPPort = 80
+)
+
+// egressHostRouteMatch matches all the requests for an external host
+var egressHostRouteMatch = trafficpolicy.HTTPRouteMatch{
+	Path:          "/",
+	PathMatchType: trafficpolicy.PathMatchPrefix,
+}
+
+// GetEgressRouteConfigName returns the name of the route config of the HTTP egress traffic on the given port
+func GetEgressRouteConfigName(port uint32) string {
+	return fmt.Sprintf("%s.%d", egressRouteConfigPrefix, port)
+}
+
+// BuildEgressRouteConfiguration returns a route config per port of the external HTTP destinations allowed by the given
+// egress policy, with a virtual host per allowed host routing its requests to the cluster of the host. Wildcard domains
+// match the requests for any of their subdomains, unless the subdomain is allowed by its own virtual host. Requests for
+// other hosts do not match any virtual host, and are rejected, unless the policy is evaluated in audit mode: they are
+// then matched by a virtual host forwarding them to their original destination, whose RBAC shadow rules record them as
+// denied.
+func BuildEgressRouteConfiguration(policy *trafficpolicy.EgressPolicy, audit bool) ([]*xds_route.RouteConfiguration, error) {
+	var ports []uint32
+	for port := range policy.HTTPHosts {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i] < ports[j]
+	})
+
+	var routeConfigs []*xds_route.RouteConfiguration
+	for _, port := range ports {
+		routeConfig := NewRouteConfigurationStub(GetEgressRouteConfigName(port))
+		hostPolicies := trafficpolicy.NewOutboundPolicySet()
+		hostPolicies.Add(trafficpolicy.EgressSource, buildEgressHostPolicies(policy.HTTPHosts[port], port)...)
+		for _, hostPolicy := range hostPolicies.Policies() {
+			virtualHost := buildVirtualHostStub(egressVirtualHost, hostPolicy.Name, hostPolicy.Hostnames)
+			for _, hostRoute := range hostPolicy.Routes {
+				virtualHost.Routes = append(virtualHost.Routes, buildEgressHostRoute(hostPolicy.Name, hostRoute))
+			}
+			routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+		}
+		if audit {
+			auditVirtualHost, err := buildEgressAuditVirtualHost()
+			if err != nil {
+				return nil, err
+			}
+			routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, auditVirtualHost)
+		}
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+	return routeConfigs, nil
+}
+
+// buildEgressHostPolicies returns the outbound traffic policies of the given external hosts on the given port, routing
+// all the requests for a host to its cluster
+func buildEgressHostPolicies(hosts []string, port uint32) []*trafficpolicy.OutboundTrafficPolicy {
+	var policies []*trafficpolicy.OutboundTrafficPolicy
+	for _, host := range hosts {
+		policy := trafficpolicy.NewOutboundTrafficPolicy(host, []string{host, fmt.Sprintf("%s:%d", host, port)})
+		policy.Routes = append(policy.Routes, trafficpolicy.NewRouteWeightedCluster(egressHostRouteMatch, []service.WeightedCluster{
+			{ClusterName: service.ClusterName(envoy.GetEgressHostClusterName(host, port)), Weight: 100},
+		}))
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// buildEgressHostRoute returns the route of the given route of an external host, routing its requests to the cluster of
+// the host
+func buildEgressHostRoute(host string, hostRoute *trafficpolicy.RouteWeightedClusters) *xds_route.Route {
+	var cluster string
+	for wc := range hostRoute.WeightedClusters.Iter() {
+		cluster = wc.(service.WeightedCluster).ClusterName.String()
+	}
+	return &xds_route.Route{
+		Name: envoy.BoundedName(host),
+		Match: &xds_route.RouteMatch{
+			PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: hostRoute.HTTPRouteMatch.Path},
+		},
+		Action: &xds_route.Route_Route{
+			Route: &xds_route.RouteAction{
+				ClusterSpecifier: &xds_route.RouteAction_Cluster{Cluster: cluster},
+			},
+		},
+	}
+}
+
+// buildEgressAuditVirtualHost returns the virtual host matching the requests for the hosts not allowed by an egress
+// policy evaluated in audit mode. The requests are forwarded to their original destination, and recorded as denied in
+// their dynamic metadata by the shadow rules of the RBAC filter, for the proxy to report them to the controller.
+func buildEgressAuditVirtualHost() (*xds_route.VirtualHost, error) {
+	rbacFilter, err := buildEgressAuditRBACFilter()
+	if err != nil {
+		log.Error().Err(err).Msg("Error building RBAC per route filter for the egress audit virtual host")
+		return nil, err
+	}
+
+	// The virtual hosts with more specific domains take precedence over the wildcard domain
+	virtualHost := buildVirtualHostStub(egressAuditVirtualHost, "*", []string{"*"})
+	virtualHost.Routes = []*xds_route.Route{
+		{
+			Name: egressAuditRouteName,
+			Match: &xds_route.RouteMatch{
+				PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: "/"},
+			},
+			Action: &xds_route.Route_Route{
+				Route: &xds_route.RouteAction{
+					ClusterSpecifier: &xds_route.RouteAction_Cluster{Cluster: envoy.OutboundPassthroughCluster},
+				},
+			},
+			TypedPerFilterConfig: rbacFilter,
+		},
+	}
+	return virtualHost, nil
+}
+
+// BuildEgressHTTPProxyRouteConfiguration returns the route config of the explicit HTTP proxy of the proxy, with a
+// virtual host per external host and port reachable through it as allowed by the given egress policy. The plaintext
+// HTTP requests for the HTTP hosts are routed to the cluster of the host, and the HTTP CONNECT requests to the HTTPS
+// hosts are terminated and their payload tunneled to the cluster of the host. The authority of the requests for the
+// default HTTP port 80 may not include the port. Nil is returned when the policy does not allow any host to be reached
+// through the HTTP proxy.
+func BuildEgressHTTPProxyRouteConfiguration(policy *trafficpolicy.EgressPolicy) *xds_route.RouteConfiguration {
+	if !policy.HasHTTPProxyHosts() {
+		return nil
+	}
+
+	type hostPort struct {
+		host string
+		port uint32
+	}
+	virtualHosts := make(map[hostPort]*xds_route.VirtualHost)
+	getVirtualHost := func(host string, port uint32) *xds_route.VirtualHost {
+		key := hostPort{host: host, port: port}
+		if virtualHost, ok := virtualHosts[key]; ok {
+			return virtualHost
+		}
+		authority := fmt.Sprintf("%s:%d", host, port)
+		domains := []string{authority}
+		if port == defaultHTTPPort {
+			domains = []string{host, authority}
+		}
+		virtualHosts[key] = buildVirtualHostStub(egressHTTPProxyVirtualHost, authority, domains)
+		return virtualHosts[key]
+	}
+
+	// CONNECT requests only match the routes with a CONNECT matcher, so they are added first
+	for port, hosts := range policy.HTTPSProxyHosts {
+		for _, host := range hosts {
+			virtualHost := getVirtualHost(host, port)
+			virtualHost.Routes = append(virtualHost.Routes, &xds_route.Route{
+				Name: envoy.BoundedName(fmt.Sprintf("%s|%s", envoy.ConnectUpgradeType, host)),
+				Match: &xds_route.RouteMatch{
+					PathSpecifier: &xds_route.RouteMatch_ConnectMatcher_{ConnectMatcher: &xds_route.RouteMatch_ConnectMatcher{}},
+				},
+				Action: &xds_route.Route_Route{
+					Route: &xds_route.RouteAction{
+						ClusterSpecifier: &xds_route.RouteAction_Cluster{Cluster: envoy.GetEgressHostClusterName(host, port)},
+						UpgradeConfigs: []*xds_route.RouteAction_UpgradeConfig{
+							{
+								UpgradeType:   envoy.ConnectUpgradeType,
+								ConnectConfig: &xds_route.RouteAction_UpgradeConfig_ConnectConfig{},
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	for port, hosts := range policy.HTTPProxyHosts {
+		for _, host := range hosts {
+			virtualHost := getVirtualHost(host, port)
+			virtualHost.Routes = append(virtualHost.Routes, &xds_route.Route{
+				Name: envoy.BoundedName(host),
+				Match: &xds_route.RouteMatch{
+					PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: "/"},
+				},
+				Action: &xds_route.Route_Route{
+					Route: &xds_route.RouteAction{
+						ClusterSpecifier: &xds_route.RouteAction_Cluster{Cluster: envoy.GetEgressHostClusterName(host, port)},
+					},
+				},
+			})
+		}
+	}
+
+	routeConfig := NewRouteConfigurationStub(EgressHTTPProxyRouteConfigName)
+	for _, virtualHost := range virtualHosts {
+		routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+	}
+	// The virtual hosts are built from maps, so they are sorted by name
+	sort.Slice(routeConfig.VirtualHosts, func(i, j int) bool {
+		return routeConfig.VirtualHosts[i].Name < routeConfig.VirtualHosts[j].Name
+	})
+	return routeConfig
+}
@@ -0,0 +1,126 @@
+package route
+
+import (
+	"testing"
+
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_http_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildEgressRouteConfiguration(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := &trafficpolicy.EgressPolicy{
+		Names:      []string{"curl/httpbin"},
+		HTTPHosts:  map[uint32][]string{8080: {"example.com"}, 80: {"example.com", "httpbin.org"}},
+		HTTPSHosts: map[uint32][]string{443: {"httpbin.org"}},
+	}
+
+	routeConfigs, err := BuildEgressRouteConfiguration(policy, false)
+	assert.Nil(err)
+
+	// A route config is built per port of the HTTP hosts, sorted by port
+	assert.Len(routeConfigs, 2)
+	assert.Equal("rds-egress.80", routeConfigs[0].Name)
+	assert.Equal("rds-egress.8080", routeConfigs[1].Name)
+
+	virtualHosts := routeConfigs[0].VirtualHosts
+	assert.Len(virtualHosts, 2)
+	assert.Equal("egress_virtual-host|example.com", virtualHosts[0].Name)
+	assert.Equal([]string{"example.com", "example.com:80"}, virtualHosts[0].Domains)
+	assert.Equal("egress_virtual-host|httpbin.org", virtualHosts[1].Name)
+	assert.Equal([]string{"httpbin.org", "httpbin.org:80"}, virtualHosts[1].Domains)
+
+	assert.Len(virtualHosts[1].Routes, 1)
+	route := virtualHosts[1].Routes[0]
+	assert.Equal("httpbin.org", route.Name)
+	assert.Equal("/", route.Match.GetPrefix())
+	assert.Equal("egress:httpbin.org:80", route.GetRoute().GetCluster())
+
+	// Wildcard domains are routed to the cluster forwarding the requests to their original destination
+	routeConfigs, err = BuildEgressRouteConfiguration(&trafficpolicy.EgressPolicy{HTTPHosts: map[uint32][]string{80: {"*.github.com"}}}, false)
+	assert.Nil(err)
+	assert.Len(routeConfigs, 1)
+	assert.Equal([]string{"*.github.com", "*.github.com:80"}, routeConfigs[0].VirtualHosts[0].Domains)
+	assert.Equal("egress-wildcard-hosts:80", routeConfigs[0].VirtualHosts[0].Routes[0].GetRoute().GetCluster())
+
+	// The hosts allowed by several Egress resources are compiled into a single virtual host
+	routeConfigs, err = BuildEgressRouteConfiguration(&trafficpolicy.EgressPolicy{HTTPHosts: map[uint32][]string{80: {"httpbin.org", "httpbin.org"}}}, false)
+	assert.Nil(err)
+	assert.Len(routeConfigs, 1)
+	assert.Len(routeConfigs[0].VirtualHosts, 1)
+	assert.Len(routeConfigs[0].VirtualHosts[0].Routes, 1)
+
+	routeConfigs, err = BuildEgressRouteConfiguration(&trafficpolicy.EgressPolicy{}, false)
+	assert.Nil(err)
+	assert.Empty(routeConfigs)
+
+	// In audit mode, the requests for the hosts not allowed by the policy are forwarded to their original destination
+	// and recorded as denied by the shadow rules of the RBAC filter
+	routeConfigs, err = BuildEgressRouteConfiguration(policy, true)
+	assert.Nil(err)
+	assert.Len(routeConfigs, 2)
+	for _, routeConfig := range routeConfigs {
+		auditVirtualHost := routeConfig.VirtualHosts[len(routeConfig.VirtualHosts)-1]
+		assert.Equal("egress-audit_virtual-host|*", auditVirtualHost.Name)
+		assert.Equal([]string{"*"}, auditVirtualHost.Domains)
+		assert.Len(auditVirtualHost.Routes, 1)
+		assert.Equal(envoy.OutboundPassthroughCluster, auditVirtualHost.Routes[0].GetRoute().GetCluster())
+
+		rbacPerRoute := &xds_http_rbac.RBACPerRoute{}
+		assert.Nil(ptypes.UnmarshalAny(auditVirtualHost.Routes[0].TypedPerFilterConfig[wellknown.HTTPRoleBasedAccessControl], rbacPerRoute))
+		assert.Nil(rbacPerRoute.Rbac.Rules)
+		assert.Equal(xds_rbac.RBAC_DENY, rbacPerRoute.Rbac.ShadowRules.Action)
+	}
+}
+
+func TestBuildEgressHTTPProxyRouteConfiguration(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Nil(BuildEgressHTTPProxyRouteConfiguration(nil))
+	assert.Nil(BuildEgressHTTPProxyRouteConfiguration(&trafficpolicy.EgressPolicy{HTTPHosts: map[uint32][]string{80: {"httpbin.org"}}}))
+
+	policy := &trafficpolicy.EgressPolicy{
+		Names:           []string{"curl/httpbin"},
+		HTTPHosts:       map[uint32][]string{80: {"httpbin.org"}, 8080: {"example.com"}},
+		HTTPSHosts:      map[uint32][]string{443: {"httpbin.org"}},
+		HTTPProxyHosts:  map[uint32][]string{80: {"httpbin.org"}, 8080: {"example.com"}},
+		HTTPSProxyHosts: map[uint32][]string{443: {"httpbin.org"}},
+	}
+
+	routeConfig := BuildEgressHTTPProxyRouteConfiguration(policy)
+	assert.Equal("rds-egress-http-proxy", routeConfig.Name)
+
+	// A virtual host is built per host and port, sorted by name
+	virtualHosts := routeConfig.VirtualHosts
+	assert.Len(virtualHosts, 3)
+	assert.Equal("egress-http-proxy_virtual-host|example.com:8080", virtualHosts[0].Name)
+	assert.Equal([]string{"example.com:8080"}, virtualHosts[0].Domains)
+	assert.Equal("egress-http-proxy_virtual-host|httpbin.org:443", virtualHosts[1].Name)
+	assert.Equal([]string{"httpbin.org:443"}, virtualHosts[1].Domains)
+	// The authority of the requests for the default HTTP port may not include the port
+	assert.Equal("egress-http-proxy_virtual-host|httpbin.org:80", virtualHosts[2].Name)
+	assert.Equal([]string{"httpbin.org", "httpbin.org:80"}, virtualHosts[2].Domains)
+
+	// Plaintext HTTP requests are routed to the cluster of the host
+	assert.Len(virtualHosts[0].Routes, 1)
+	assert.Equal("/", virtualHosts[0].Routes[0].Match.GetPrefix())
+	assert.Equal("egress:example.com:8080", virtualHosts[0].Routes[0].GetRoute().GetCluster())
+	assert.Empty(virtualHosts[0].Routes[0].GetRoute().UpgradeConfigs)
+
+	// CONNECT requests are terminated and their payload tunneled to the cluster of the host
+	assert.Len(virtualHosts[1].Routes, 1)
+	connectRoute := virtualHosts[1].Routes[0]
+	assert.Equal("CONNECT|httpbin.org", connectRoute.Name)
+	assert.NotNil(connectRoute.Match.GetConnectMatcher())
+	assert.Equal("egress:httpbin.org:443", connectRoute.GetRoute().GetCluster())
+	assert.Len(connectRoute.GetRoute().UpgradeConfigs, 1)
+	assert.Equal("CONNECT", connectRoute.GetRoute().UpgradeConfigs[0].UpgradeType)
+	assert.NotNil(connectRoute.GetRoute().UpgradeConfigs[0].ConnectConfig)
+}
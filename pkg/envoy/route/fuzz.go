@@ -0,0 +1,107 @@
+// +build gofuzz
+
+package route
+
+import (
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests/fuzz"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// maxFuzzPolicies is the max number of traffic policies derived from fuzzed data
+	maxFuzzPolicies = 16
+
+	// maxFuzzRules is the max number of rules or routes of a traffic policy derived from fuzzed data, large enough for
+	// route configurations with enormous numbers of routes
+	maxFuzzRules = 4096
+
+	// maxFuzzStringLen is the max length of a string derived from fuzzed data
+	maxFuzzStringLen = 64
+)
+
+// FuzzBuildRouteConfiguration fuzzes the building of the route configurations of a proxy from its traffic policies,
+// with malformed paths and headers, unusual hosts and methods, invalid weights and large numbers of rules
+func FuzzBuildRouteConfiguration(data []byte) int {
+	c := fuzz.NewConsumer(data)
+	routingPolicies := &trafficpolicy.RoutingPolicies{
+		InboundTrafficTargets: make(map[string][]trafficpolicy.HTTPRouteMatch),
+		OutboundTrafficSplits: make(map[string]string),
+		OutboundDarkLaunches:  make(map[string]trafficpolicy.DarkLaunchPolicy),
+	}
+
+	var inbound []*trafficpolicy.InboundTrafficPolicy
+	for i := c.Intn(maxFuzzPolicies + 1); i > 0; i-- {
+		policy := trafficpolicy.NewInboundTrafficPolicy(c.String(maxFuzzStringLen), []string{c.Pick(maxFuzzStringLen, fuzz.Hosts...)})
+		for j := c.Intn(maxFuzzRules + 1); j > 0; j-- {
+			routeMatch := newFuzzRouteMatch(c)
+			policy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, newFuzzWeightedClusters(c)), service.K8sServiceAccount{
+				Name:      c.Pick(maxFuzzStringLen, "", "bookbuyer"),
+				Namespace: c.Pick(maxFuzzStringLen, "", "bookbuyer-ns"),
+			})
+			if c.Bool() {
+				trafficTarget := c.Pick(maxFuzzStringLen, "bookstore-ns/bookstore-target")
+				routingPolicies.InboundTrafficTargets[trafficTarget] = append(routingPolicies.InboundTrafficTargets[trafficTarget], routeMatch)
+			}
+		}
+		inbound = append(inbound, policy)
+	}
+
+	var outbound []*trafficpolicy.OutboundTrafficPolicy
+	for i := c.Intn(maxFuzzPolicies + 1); i > 0; i-- {
+		policy := trafficpolicy.NewOutboundTrafficPolicy(c.String(maxFuzzStringLen), []string{c.Pick(maxFuzzStringLen, fuzz.Hosts...)})
+		for j := c.Intn(maxFuzzRules + 1); j > 0; j-- {
+			// Errors adding conflicting routes are expected, the fuzz target only checks that building does not panic
+			_ = policy.AddRoute(newFuzzRouteMatch(c), newFuzzWeightedClusters(c)...)
+		}
+		if c.Bool() {
+			routingPolicies.OutboundTrafficSplits[policy.Name] = c.String(maxFuzzStringLen)
+		}
+		if c.Bool() {
+			routingPolicies.OutboundDarkLaunches[policy.Name] = trafficpolicy.DarkLaunchPolicy{
+				ShadowCluster: service.ClusterName(c.String(maxFuzzStringLen)),
+				Percentage:    uint32(c.Intn(256)),
+			}
+		}
+		outbound = append(outbound, policy)
+	}
+
+	if c.Bool() {
+		routingPolicies = nil
+	}
+
+	proxy := envoy.NewProxy(certificate.CommonName("abcd.bookstore.bookstore-ns"), certificate.SerialNumber("123456"), nil)
+	if len(BuildRouteConfiguration(inbound, outbound, proxy, routingPolicies)) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// newFuzzRouteMatch returns an HTTP route match derived from fuzzed data, including invalid path match types
+func newFuzzRouteMatch(c *fuzz.Consumer) trafficpolicy.HTTPRouteMatch {
+	routeMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          c.Pick(maxFuzzStringLen, fuzz.Paths...),
+		PathMatchType: trafficpolicy.PathMatchType(c.Intn(int(trafficpolicy.PathMatchPrefix) + 2)),
+	}
+	for i := c.Intn(len(fuzz.Methods) + 1); i > 0; i-- {
+		routeMatch.Methods = append(routeMatch.Methods, c.Pick(maxFuzzStringLen, fuzz.Methods...))
+	}
+	if c.Bool() {
+		routeMatch.Headers = map[string]string{c.Pick(maxFuzzStringLen, httpHostHeader, "user-agent"): c.Pick(maxFuzzStringLen, fuzz.Paths...)}
+	}
+	return routeMatch
+}
+
+// newFuzzWeightedClusters returns weighted clusters derived from fuzzed data, with weights that may not add up to 100
+func newFuzzWeightedClusters(c *fuzz.Consumer) []service.WeightedCluster {
+	var weightedClusters []service.WeightedCluster
+	for i := c.Intn(4); i >= 0; i-- {
+		weightedClusters = append(weightedClusters, service.WeightedCluster{
+			ClusterName: service.ClusterName(c.Pick(maxFuzzStringLen, "bookstore-ns/bookstore-v1", "bookstore-ns/bookstore-v2")),
+			Weight:      c.Intn(101),
+		})
+	}
+	return weightedClusters
+}
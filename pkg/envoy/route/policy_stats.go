@@ -0,0 +1,153 @@
+package route
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// TrafficTargetStatsPrefix is the prefix of the name of the virtual clusters counting the requests allowed by SMI TrafficTargets.
+	// The stats of these virtual clusters are tagged with the 'osm_traffic_target' tag by the bootstrap config of the proxies.
+	TrafficTargetStatsPrefix = "osm-traffic-target="
+
+	// TrafficSplitStatsPrefix is the prefix of the name of the virtual clusters counting the requests routed by SMI TrafficSplits.
+	// The stats of these virtual clusters are tagged with the 'osm_traffic_split' tag by the bootstrap config of the proxies.
+	TrafficSplitStatsPrefix = "osm-traffic-split="
+
+	// policyMetadataFilter is the filter metadata namespace of the routes holding the SMI policies the routes are built from
+	policyMetadataFilter = "openservicemesh.io"
+
+	// trafficTargetsMetadataKey is the route metadata key listing the SMI TrafficTargets allowing the route
+	trafficTargetsMetadataKey = "traffic_targets"
+
+	// trafficSplitMetadataKey is the route metadata key of the SMI TrafficSplit routing the route
+	trafficSplitMetadataKey = "traffic_split"
+
+	// policyStatsNameSeparator separates the names of the policies attributed a virtual cluster
+	policyStatsNameSeparator = ","
+
+	// pathHeaderKey is the key of the pseudo header matched by virtual clusters for HTTP paths
+	pathHeaderKey = ":path"
+
+	// queryStringRegex matches an optional query string following the path matched by a virtual cluster
+	queryStringRegex = `(\?.*)?`
+)
+
+// getTrafficTargetsForRoute returns the sorted names of the TrafficTargets allowing the given route
+func getTrafficTargetsForRoute(routeMatch trafficpolicy.HTTPRouteMatch, routingPolicies *trafficpolicy.RoutingPolicies) []string {
+	if routingPolicies == nil {
+		return nil
+	}
+
+	var trafficTargets []string
+	for trafficTarget, routeMatches := range routingPolicies.InboundTrafficTargets {
+		for _, match := range routeMatches {
+			if reflect.DeepEqual(match, routeMatch) {
+				trafficTargets = append(trafficTargets, trafficTarget)
+				break
+			}
+		}
+	}
+	sort.Strings(trafficTargets)
+	return trafficTargets
+}
+
+// getPolicyStatsName returns the name of a virtual cluster counting the requests attributed to the given policies.
+// Dots are replaced because Envoy splits stat names on dots, which would prevent the stats tags from extracting the names.
+// Names attributing requests to many policies are bounded, keeping the stat names and tag values of a practical length.
+func getPolicyStatsName(prefix string, policyNames []string) string {
+	return envoy.BoundedName(prefix + strings.ReplaceAll(strings.Join(policyNames, policyStatsNameSeparator), ".", "_"))
+}
+
+// buildInboundVirtualClusters returns the virtual clusters counting the requests of the given inbound rules per TrafficTarget
+// allowing them. Rules not allowed by a TrafficTarget, such as ingress rules or rules in permissive mode, are not counted.
+func buildInboundVirtualClusters(rules []*trafficpolicy.Rule, routingPolicies *trafficpolicy.RoutingPolicies) []*xds_route.VirtualCluster {
+	var virtualClusters []*xds_route.VirtualCluster
+	for _, rule := range rules {
+		trafficTargets := getTrafficTargetsForRoute(rule.Route.HTTPRouteMatch, routingPolicies)
+		if len(trafficTargets) == 0 {
+			continue
+		}
+
+		for _, method := range sanitizeHTTPMethods(rule.Route.HTTPRouteMatch.Methods) {
+			virtualClusters = append(virtualClusters, &xds_route.VirtualCluster{
+				Name:    getPolicyStatsName(TrafficTargetStatsPrefix, trafficTargets),
+				Headers: append([]*xds_route.HeaderMatcher{getPathHeaderMatcher(rule.Route.HTTPRouteMatch.PathMatchType, rule.Route.HTTPRouteMatch.Path)}, getHeadersForRoute(method, rule.Route.HTTPRouteMatch.Headers)...),
+			})
+		}
+	}
+	return virtualClusters
+}
+
+// buildOutboundVirtualCluster returns the virtual cluster counting all the requests routed by the given TrafficSplit
+func buildOutboundVirtualCluster(trafficSplit string) *xds_route.VirtualCluster {
+	return &xds_route.VirtualCluster{
+		Name:    getPolicyStatsName(TrafficSplitStatsPrefix, []string{trafficSplit}),
+		Headers: []*xds_route.HeaderMatcher{getPathHeaderMatcher(trafficpolicy.PathMatchPrefix, "/")},
+	}
+}
+
+// getPathHeaderMatcher returns a matcher of the ':path' header equivalent to the given route path match.
+// Unlike routes, virtual clusters match the path with the query string, which is allowed by the regex matchers.
+func getPathHeaderMatcher(pathMatchType trafficpolicy.PathMatchType, path string) *xds_route.HeaderMatcher {
+	pathMatcher := &xds_route.HeaderMatcher{
+		Name: pathHeaderKey,
+	}
+
+	switch pathMatchType {
+	case trafficpolicy.PathMatchPrefix:
+		pathMatcher.HeaderMatchSpecifier = &xds_route.HeaderMatcher_PrefixMatch{
+			PrefixMatch: path,
+		}
+
+	case trafficpolicy.PathMatchExact:
+		path = regexp.QuoteMeta(path)
+		fallthrough
+
+	default:
+		pathMatcher.HeaderMatchSpecifier = &xds_route.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: &xds_matcher.RegexMatcher{
+				EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+				Regex:      path + queryStringRegex,
+			},
+		}
+	}
+
+	return pathMatcher
+}
+
+// buildTrafficTargetsMetadata returns the metadata of a route allowed by the given TrafficTargets
+func buildTrafficTargetsMetadata(trafficTargets []string) *core.Metadata {
+	var values []*structpb.Value
+	for _, trafficTarget := range trafficTargets {
+		values = append(values, &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: trafficTarget}})
+	}
+	return buildPolicyMetadata(trafficTargetsMetadataKey, &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: values}}})
+}
+
+// buildTrafficSplitMetadata returns the metadata of a route routed by the given TrafficSplit
+func buildTrafficSplitMetadata(trafficSplit string) *core.Metadata {
+	return buildPolicyMetadata(trafficSplitMetadataKey, &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: trafficSplit}})
+}
+
+func buildPolicyMetadata(key string, value *structpb.Value) *core.Metadata {
+	return &core.Metadata{
+		FilterMetadata: map[string]*structpb.Struct{
+			policyMetadataFilter: {
+				Fields: map[string]*structpb.Value{
+					key: value,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package route
+
+import (
+	"testing"
+
+	set "github.com/deckarep/golang-set"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildRouteConfigurationPolicyStats(t *testing.T) {
+	assert := tassert.New(t)
+
+	inbound := &trafficpolicy.InboundTrafficPolicy{
+		Name:      "bookstore-v1.default",
+		Hostnames: tests.BookstoreV1Hostnames,
+		Rules: []*trafficpolicy.Rule{
+			{
+				Route: trafficpolicy.RouteWeightedClusters{
+					HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+					WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+				AllowedServiceAccounts: set.NewSet(tests.BookbuyerServiceAccount),
+			},
+			{
+				Route: trafficpolicy.RouteWeightedClusters{
+					HTTPRouteMatch:   tests.BookstoreSellHTTPRoute,
+					WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+				AllowedServiceAccounts: set.NewSet(tests.BookbuyerServiceAccount),
+			},
+		},
+	}
+	outbound := &trafficpolicy.OutboundTrafficPolicy{
+		Name:      "bookstore-apex",
+		Hostnames: tests.BookstoreApexHostnames,
+		Routes: []*trafficpolicy.RouteWeightedClusters{
+			{
+				HTTPRouteMatch:   tests.WildCardRouteMatch,
+				WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+			},
+		},
+	}
+	routingPolicies := &trafficpolicy.RoutingPolicies{
+		InboundTrafficTargets: map[string][]trafficpolicy.HTTPRouteMatch{
+			"default/bookbuyer-access-bookstore": {tests.BookstoreBuyHTTPRoute, tests.BookstoreSellHTTPRoute},
+			"default/bookstore.buy":              {tests.BookstoreBuyHTTPRoute},
+		},
+		OutboundTrafficSplits: map[string]string{
+			"bookstore-apex": "default/bookstore-split",
+		},
+	}
+
+	actual := BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{inbound}, []*trafficpolicy.OutboundTrafficPolicy{outbound}, nil, routingPolicies)
+	assert.Len(actual, 2)
+
+	inboundVirtualHost := actual[0].VirtualHosts[0]
+	assert.Len(inboundVirtualHost.Routes, 2)
+	assert.Equal([]string{"default/bookbuyer-access-bookstore", "default/bookstore.buy"}, getStringValues(inboundVirtualHost.Routes[0].Metadata.FilterMetadata[policyMetadataFilter].Fields[trafficTargetsMetadataKey].GetListValue().GetValues()))
+	assert.Equal([]string{"default/bookbuyer-access-bookstore"}, getStringValues(inboundVirtualHost.Routes[1].Metadata.FilterMetadata[policyMetadataFilter].Fields[trafficTargetsMetadataKey].GetListValue().GetValues()))
+
+	assert.Len(inboundVirtualHost.VirtualClusters, 2)
+	assert.Equal("osm-traffic-target=default/bookbuyer-access-bookstore,default/bookstore_buy", inboundVirtualHost.VirtualClusters[0].Name)
+	assert.Equal(pathHeaderKey, inboundVirtualHost.VirtualClusters[0].Headers[0].Name)
+	assert.Equal(tests.BookstoreBuyPath+queryStringRegex, inboundVirtualHost.VirtualClusters[0].Headers[0].GetSafeRegexMatch().Regex)
+	assert.Equal(MethodHeaderKey, inboundVirtualHost.VirtualClusters[0].Headers[1].Name)
+	assert.Equal("osm-traffic-target=default/bookbuyer-access-bookstore", inboundVirtualHost.VirtualClusters[1].Name)
+	assert.Equal(tests.BookstoreSellPath+queryStringRegex, inboundVirtualHost.VirtualClusters[1].Headers[0].GetSafeRegexMatch().Regex)
+
+	outboundVirtualHost := actual[1].VirtualHosts[0]
+	assert.Len(outboundVirtualHost.Routes, 1)
+	assert.Equal("default/bookstore-split", outboundVirtualHost.Routes[0].Metadata.FilterMetadata[policyMetadataFilter].Fields[trafficSplitMetadataKey].GetStringValue())
+	assert.Len(outboundVirtualHost.VirtualClusters, 1)
+	assert.Equal("osm-traffic-split=default/bookstore-split", outboundVirtualHost.VirtualClusters[0].Name)
+	assert.Equal("/", outboundVirtualHost.VirtualClusters[0].Headers[0].GetPrefixMatch())
+
+	// Without routing policies, routes and their stats are not attributed to policies
+	actual = BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{inbound}, []*trafficpolicy.OutboundTrafficPolicy{outbound}, nil, nil)
+	assert.Len(actual, 2)
+	assert.Nil(actual[0].VirtualHosts[0].Routes[0].Metadata)
+	assert.Empty(actual[0].VirtualHosts[0].VirtualClusters)
+	assert.Nil(actual[1].VirtualHosts[0].Routes[0].Metadata)
+	assert.Empty(actual[1].VirtualHosts[0].VirtualClusters)
+}
+
+func TestGetPathHeaderMatcher(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pathMatchType  trafficpolicy.PathMatchType
+		path           string
+		expectedRegex  string
+		expectedPrefix string
+	}{
+		{
+			name:          "regex path",
+			pathMatchType: trafficpolicy.PathMatchRegex,
+			path:          "/books/.*",
+			expectedRegex: "/books/.*" + queryStringRegex,
+		},
+		{
+			name:          "exact path",
+			pathMatchType: trafficpolicy.PathMatchExact,
+			path:          "/books.json",
+			expectedRegex: `/books\.json` + queryStringRegex,
+		},
+		{
+			name:           "prefix path",
+			pathMatchType:  trafficpolicy.PathMatchPrefix,
+			path:           "/books",
+			expectedPrefix: "/books",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := getPathHeaderMatcher(tc.pathMatchType, tc.path)
+			assert.Equal(pathHeaderKey, actual.Name)
+			assert.Equal(tc.expectedRegex, actual.GetSafeRegexMatch().GetRegex())
+			assert.Equal(tc.expectedPrefix, actual.GetPrefixMatch())
+		})
+	}
+}
+
+func getStringValues(values []*structpb.Value) []string {
+	var stringValues []string
+	for _, value := range values {
+		stringValues = append(stringValues, value.GetStringValue())
+	}
+	return stringValues
+}
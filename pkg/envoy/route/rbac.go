@@ -1,6 +1,7 @@
 package route
 
 import (
+	set "github.com/deckarep/golang-set"
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	xds_http_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
@@ -16,21 +17,73 @@ import (
 
 const (
 	rbacPerRoutePolicyName = "rbac-for-route"
+
+	// egressAuditRBACPolicyName is the name of the RBAC policy of the shadow rules that would deny the egress requests
+	// not allowed by Egress policies in audit mode
+	egressAuditRBACPolicyName = "egress-audit"
 )
 
 // buildInboundRBACFilterForRule builds an HTTP RBAC per route filter based on the given traffic policy rule.
 // The principals in the RBAC policy are derived from the allowed service accounts specified in the given rule.
 // The permissions in the RBAC policy are implicitly set to ANY (all permissions).
+// When the rule has service accounts evaluated in shadow mode, the principals derived from them are evaluated as
+// shadow rules, whose result is recorded in the dynamic metadata of the request without being enforced.
 func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule) (map[string]*any.Any, error) {
 	if rule.AllowedServiceAccounts == nil {
 		return nil, errors.Errorf("traffipolicy.Rule.AllowedServiceAccounts not set")
 	}
 
+	rbacRules, err := buildRBACRulesForServiceAccounts(rule.AllowedServiceAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map generic RBAC policy to HTTP RBAC policy
+	httpRBAC := &xds_http_rbac.RBAC{
+		Rules: rbacRules,
+	}
+	if rule.ShadowServiceAccounts != nil {
+		if httpRBAC.ShadowRules, err = buildRBACRulesForServiceAccounts(rule.ShadowServiceAccounts); err != nil {
+			return nil, err
+		}
+	}
+	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{
+		Rbac: httpRBAC,
+	}
+
+	marshalled, err := ptypes.MarshalAny(httpRBACPerRoute)
+	if err != nil {
+		return nil, err
+	}
+
+	rbacFilter := map[string]*any.Any{wellknown.HTTPRoleBasedAccessControl: marshalled}
+	return rbacFilter, nil
+}
+
+// buildEgressAuditRBACFilter builds an HTTP RBAC per route filter without enforced rules, whose shadow rules would deny
+// any request. The requests of the route are recorded as denied in their dynamic metadata without being denied.
+func buildEgressAuditRBACFilter() (map[string]*any.Any, error) {
+	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{
+		Rbac: &xds_http_rbac.RBAC{
+			ShadowRules: rbac.GetDenyAnyRules(egressAuditRBACPolicyName),
+		},
+	}
+
+	marshalled, err := ptypes.MarshalAny(httpRBACPerRoute)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]*any.Any{wellknown.HTTPRoleBasedAccessControl: marshalled}, nil
+}
+
+// buildRBACRulesForServiceAccounts builds RBAC rules allowing the given downstream service accounts
+func buildRBACRulesForServiceAccounts(serviceAccounts set.Set) (*xds_rbac.RBAC, error) {
 	policy := &rbac.Policy{}
 
 	// Create the list of principals for this policy
 	var principalRuleList []rbac.RulesList
-	for downstream := range rule.AllowedServiceAccounts.Iter() {
+	for downstream := range serviceAccounts.Iter() {
 		var principalRule rbac.RulesList
 		downstreamIdentity := downstream.(service.K8sServiceAccount)
 
@@ -64,22 +117,8 @@ func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule) (map[string]*any.An
 	// A single RBAC policy per route
 	rbacPolicyMap := map[string]*xds_rbac.Policy{rbacPerRoutePolicyName: rbacPolicy}
 
-	// Map generic RBAC policy to HTTP RBAC policy
-	httpRBAC := &xds_http_rbac.RBAC{
-		Rules: &xds_rbac.RBAC{
-			Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
-			Policies: rbacPolicyMap,
-		},
-	}
-	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{
-		Rbac: httpRBAC,
-	}
-
-	marshalled, err := ptypes.MarshalAny(httpRBACPerRoute)
-	if err != nil {
-		return nil, err
-	}
-
-	rbacFilter := map[string]*any.Any{wellknown.HTTPRoleBasedAccessControl: marshalled}
-	return rbacFilter, nil
+	return &xds_rbac.RBAC{
+		Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
+		Policies: rbacPolicyMap,
+	}, nil
 }
@@ -132,3 +132,48 @@ func TestBuildInboundRBACFilterForRule(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInboundRBACFilterForShadowRule(t *testing.T) {
+	assert := tassert.New(t)
+
+	rule := &trafficpolicy.Rule{
+		Route: trafficpolicy.RouteWeightedClusters{
+			HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+			WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+		},
+		AllowedServiceAccounts: set.NewSetFromSlice([]interface{}{
+			service.K8sServiceAccount{Name: "foo", Namespace: "ns-1"},
+			service.K8sServiceAccount{},
+		}),
+		ShadowServiceAccounts: set.NewSetFromSlice([]interface{}{
+			service.K8sServiceAccount{Name: "foo", Namespace: "ns-1"},
+		}),
+	}
+
+	rbacFilter, err := buildInboundRBACFilterForRule(rule)
+	assert.Nil(err)
+
+	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{}
+	err = ptypes.UnmarshalAny(rbacFilter[wellknown.HTTPRoleBasedAccessControl], httpRBACPerRoute)
+	assert.Nil(err)
+
+	// Any downstream is allowed by the enforced rules
+	enforcedPolicy := httpRBACPerRoute.Rbac.Rules.Policies[rbacPerRoutePolicyName]
+	assert.Contains(enforcedPolicy.Principals, &xds_rbac.Principal{Identifier: &xds_rbac.Principal_Any{Any: true}})
+
+	// Only the downstreams allowed by the rule are allowed by the shadow rules
+	shadowRules := httpRBACPerRoute.Rbac.ShadowRules
+	assert.NotNil(shadowRules)
+	assert.Equal(xds_rbac.RBAC_ALLOW, shadowRules.Action)
+	assert.Equal([]*xds_rbac.Principal{
+		{
+			Identifier: &xds_rbac.Principal_OrIds{
+				OrIds: &xds_rbac.Principal_Set{
+					Ids: []*xds_rbac.Principal{
+						rbac.GetAuthenticatedPrincipal("foo.ns-1.cluster.local"),
+					},
+				},
+			},
+		},
+	}, shadowRules.Policies[rbacPerRoutePolicyName].Principals)
+}
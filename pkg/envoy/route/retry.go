@@ -0,0 +1,61 @@
+package route
+
+import (
+	"time"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// defaultRetryBackoffBaseInterval is the default base interval of the back-off between retries of Envoy, set
+// explicitly when only the max interval of the back-off is specified
+const defaultRetryBackoffBaseInterval = 25 * time.Millisecond
+
+// setRetryPolicyAndTimeout sets the retry policy and the timeouts of the given route to the ones of the given route
+// policy. The default retry policy and timeouts of the proxy apply when the policy does not specify them.
+func setRetryPolicyAndTimeout(route *xds_route.Route, routePolicy trafficpolicy.RouteWeightedClusters) {
+	routeAction := route.GetRoute()
+	if routeAction == nil {
+		return
+	}
+
+	if retryPolicy := routePolicy.RetryPolicy; retryPolicy != nil {
+		routeAction.RetryPolicy = buildRetryPolicy(retryPolicy)
+	}
+	if timeout := routePolicy.Timeout; timeout != nil {
+		routeAction.Timeout = ptypes.DurationProto(*timeout)
+	}
+	if streamIdleTimeout := routePolicy.StreamIdleTimeout; streamIdleTimeout != nil {
+		routeAction.IdleTimeout = ptypes.DurationProto(*streamIdleTimeout)
+	}
+}
+
+// buildRetryPolicy returns the retry policy of a route for the given retry policy. The requests are retried once when
+// the number of retries is not specified, with the default back-off of Envoy when its intervals are not specified.
+func buildRetryPolicy(retryPolicy *trafficpolicy.RetryPolicy) *xds_route.RetryPolicy {
+	xdsRetryPolicy := &xds_route.RetryPolicy{
+		RetryOn: retryPolicy.RetryOn,
+	}
+	if retryPolicy.NumRetries > 0 {
+		xdsRetryPolicy.NumRetries = &wrappers.UInt32Value{Value: retryPolicy.NumRetries}
+	}
+	if retryPolicy.PerTryTimeout != nil {
+		xdsRetryPolicy.PerTryTimeout = ptypes.DurationProto(*retryPolicy.PerTryTimeout)
+	}
+	if retryPolicy.RetryBackoffBaseInterval != nil || retryPolicy.RetryBackoffMaxInterval != nil {
+		baseInterval := defaultRetryBackoffBaseInterval
+		if retryPolicy.RetryBackoffBaseInterval != nil {
+			baseInterval = *retryPolicy.RetryBackoffBaseInterval
+		}
+		xdsRetryPolicy.RetryBackOff = &xds_route.RetryPolicy_RetryBackOff{
+			BaseInterval: ptypes.DurationProto(baseInterval),
+		}
+		if retryPolicy.RetryBackoffMaxInterval != nil {
+			xdsRetryPolicy.RetryBackOff.MaxInterval = ptypes.DurationProto(*retryPolicy.RetryBackoffMaxInterval)
+		}
+	}
+	return xdsRetryPolicy
+}
@@ -0,0 +1,103 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestSetRetryPolicyAndTimeout(t *testing.T) {
+	twoSeconds := 2 * time.Second
+	thirtySeconds := 30 * time.Second
+	oneHour := time.Hour
+
+	testCases := []struct {
+		name                            string
+		routePolicy                     trafficpolicy.RouteWeightedClusters
+		expectedRetryPolicy             *xds_route.RetryPolicy
+		expectedTimeoutSecond           int64
+		expectedStreamIdleTimeoutSecond int64
+	}{
+		{
+			name:        "default retry policy and timeout",
+			routePolicy: trafficpolicy.RouteWeightedClusters{},
+		},
+		{
+			name: "retry policy without number of retries",
+			routePolicy: trafficpolicy.RouteWeightedClusters{
+				RetryPolicy: &trafficpolicy.RetryPolicy{RetryOn: "5xx,reset"},
+			},
+			expectedRetryPolicy: &xds_route.RetryPolicy{RetryOn: "5xx,reset"},
+		},
+		{
+			name: "retry policy and timeout",
+			routePolicy: trafficpolicy.RouteWeightedClusters{
+				RetryPolicy: &trafficpolicy.RetryPolicy{RetryOn: "connect-failure", NumRetries: 3, PerTryTimeout: &twoSeconds},
+				Timeout:     &thirtySeconds,
+			},
+			expectedRetryPolicy:   buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "connect-failure", NumRetries: 3, PerTryTimeout: &twoSeconds}),
+			expectedTimeoutSecond: 30,
+		},
+		{
+			name: "disabled timeout and stream idle timeout",
+			routePolicy: trafficpolicy.RouteWeightedClusters{
+				Timeout:           new(time.Duration),
+				StreamIdleTimeout: &oneHour,
+			},
+			expectedStreamIdleTimeoutSecond: 3600,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			route := &xds_route.Route{
+				Action: &xds_route.Route_Route{Route: &xds_route.RouteAction{}},
+			}
+			setRetryPolicyAndTimeout(route, tc.routePolicy)
+
+			assert.Equal(tc.expectedRetryPolicy, route.GetRoute().RetryPolicy)
+			assert.Equal(tc.expectedTimeoutSecond, route.GetRoute().GetTimeout().GetSeconds())
+			assert.Equal(tc.expectedStreamIdleTimeoutSecond, route.GetRoute().GetIdleTimeout().GetSeconds())
+		})
+	}
+}
+
+func TestBuildRetryPolicy(t *testing.T) {
+	assert := tassert.New(t)
+
+	perTryTimeout := 2 * time.Second
+	retryPolicy := buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "5xx", NumRetries: 3, PerTryTimeout: &perTryTimeout})
+
+	assert.Equal("5xx", retryPolicy.RetryOn)
+	assert.Equal(uint32(3), retryPolicy.NumRetries.GetValue())
+	assert.Equal(int64(2), retryPolicy.PerTryTimeout.GetSeconds())
+}
+
+func TestBuildRetryPolicyBackoff(t *testing.T) {
+	assert := tassert.New(t)
+
+	tenMilliseconds := 10 * time.Millisecond
+	oneSecond := time.Second
+
+	retryPolicy := buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "5xx"})
+	assert.Nil(retryPolicy.RetryBackOff)
+
+	retryPolicy = buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "5xx", RetryBackoffBaseInterval: &tenMilliseconds})
+	assert.Equal(int32(10000000), retryPolicy.RetryBackOff.BaseInterval.GetNanos())
+	assert.Nil(retryPolicy.RetryBackOff.MaxInterval)
+
+	retryPolicy = buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "5xx", RetryBackoffBaseInterval: &tenMilliseconds, RetryBackoffMaxInterval: &oneSecond})
+	assert.Equal(int32(10000000), retryPolicy.RetryBackOff.BaseInterval.GetNanos())
+	assert.Equal(int64(1), retryPolicy.RetryBackOff.MaxInterval.GetSeconds())
+
+	// The base interval is required by Envoy when the max interval is specified
+	retryPolicy = buildRetryPolicy(&trafficpolicy.RetryPolicy{RetryOn: "5xx", RetryBackoffMaxInterval: &oneSecond})
+	assert.Equal(int32(25000000), retryPolicy.RetryBackOff.BaseInterval.GetNanos())
+	assert.Equal(int64(1), retryPolicy.RetryBackOff.MaxInterval.GetSeconds())
+}
@@ -48,29 +48,34 @@ const (
 	httpHostHeader = "host"
 )
 
-// BuildRouteConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing inbound and outbound routes
-func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outbound []*trafficpolicy.OutboundTrafficPolicy, proxy *envoy.Proxy) []*xds_route.RouteConfiguration {
+// BuildRouteConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing inbound and outbound routes.
+// The routes and their stats are attributed to the SMI TrafficTargets and TrafficSplits in the given routing policies, and
+// the outbound routes of dark launched services copy their requests to the shadow services.
+func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outbound []*trafficpolicy.OutboundTrafficPolicy, proxy *envoy.Proxy, routingPolicies *trafficpolicy.RoutingPolicies) []*xds_route.RouteConfiguration {
 	routeConfiguration := []*xds_route.RouteConfiguration{}
 
 	if len(inbound) > 0 {
 		inboundRouteConfig := NewRouteConfigurationStub(InboundRouteConfigName)
 		for _, in := range inbound {
 			virtualHost := buildVirtualHostStub(inboundVirtualHost, in.Name, in.Hostnames)
-			virtualHost.Routes = buildInboundRoutes(in.Rules)
+			virtualHost.Routes = buildInboundRoutes(in.Name, in.Rules, routingPolicies)
+			virtualHost.VirtualClusters = buildInboundVirtualClusters(in.Rules, routingPolicies)
 			inboundRouteConfig.VirtualHosts = append(inboundRouteConfig.VirtualHosts, virtualHost)
 		}
 
 		if featureflags.IsWASMStatsEnabled() {
-			for k, v := range proxy.StatsHeaders() {
+			statsHeaders := proxy.StatsHeaders()
+			for _, k := range sortedKeys(statsHeaders) {
 				inboundRouteConfig.ResponseHeadersToAdd = append(inboundRouteConfig.ResponseHeadersToAdd, &core.HeaderValueOption{
 					Header: &core.HeaderValue{
 						Key:   k,
-						Value: v,
+						Value: statsHeaders[k],
 					},
 				})
 			}
 		}
 
+		sortVirtualHosts(inboundRouteConfig)
 		routeConfiguration = append(routeConfiguration, inboundRouteConfig)
 	}
 	if len(outbound) > 0 {
@@ -78,9 +83,39 @@ func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outb
 
 		for _, out := range outbound {
 			virtualHost := buildVirtualHostStub(outboundVirtualHost, out.Name, out.Hostnames)
-			virtualHost.Routes = buildOutboundRoutes(out.Routes)
+			virtualHost.Routes = buildOutboundRoutes(out.Name, out.Routes)
+			if routingPolicies != nil {
+				if trafficSplit, ok := routingPolicies.OutboundTrafficSplits[out.Name]; ok {
+					for _, route := range virtualHost.Routes {
+						route.Metadata = buildTrafficSplitMetadata(trafficSplit)
+					}
+					virtualHost.VirtualClusters = append(virtualHost.VirtualClusters, buildOutboundVirtualCluster(trafficSplit))
+				}
+				if darkLaunch, ok := routingPolicies.OutboundDarkLaunches[out.Name]; ok {
+					for _, route := range virtualHost.Routes {
+						route.GetRoute().RequestMirrorPolicies = buildRequestMirrorPolicies(darkLaunch)
+					}
+				}
+			}
 			outboundRouteConfig.VirtualHosts = append(outboundRouteConfig.VirtualHosts, virtualHost)
 		}
+
+		// The metadata of the workload is sent to the proxies receiving the requests, for their stats to identify the
+		// source workload. Headers set by the application are overwritten, so that they cannot be spoofed.
+		if featureflags.IsWASMStatsEnabled() {
+			peerHeaders := proxy.StatsPeerHeaders()
+			for _, k := range sortedKeys(peerHeaders) {
+				outboundRouteConfig.RequestHeadersToAdd = append(outboundRouteConfig.RequestHeadersToAdd, &core.HeaderValueOption{
+					Header: &core.HeaderValue{
+						Key:   k,
+						Value: peerHeaders[k],
+					},
+					Append: &wrappers.BoolValue{Value: false},
+				})
+			}
+		}
+
+		sortVirtualHosts(outboundRouteConfig)
 		routeConfiguration = append(routeConfiguration, outboundRouteConfig)
 	}
 
@@ -100,19 +135,65 @@ func NewRouteConfigurationStub(routeConfigName string) *xds_route.RouteConfigura
 	return &routeConfiguration
 }
 
+// sortVirtualHosts sorts the virtual hosts of the given route configuration by name, so that the route configuration
+// does not depend on the order in which the traffic policies are listed. Envoy selects the virtual host of a request
+// by the specificity of its domains, not by its position, so the order is not significant. The routes of each virtual
+// host are ordered by the specificity of their route match when they are built, as Envoy selects the first matching route.
+func sortVirtualHosts(routeConfig *xds_route.RouteConfiguration) {
+	sort.SliceStable(routeConfig.VirtualHosts, func(i, j int) bool {
+		return routeConfig.VirtualHosts[i].Name < routeConfig.VirtualHosts[j].Name
+	})
+}
+
+// sortedKeys returns the keys of the given map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func buildVirtualHostStub(namePrefix string, host string, domains []string) *xds_route.VirtualHost {
-	name := fmt.Sprintf("%s|%s", namePrefix, host)
+	// Virtual host names are bounded as the host of an ingress policy can be long
+	name := envoy.BoundedName(fmt.Sprintf("%s|%s", namePrefix, host))
 	virtualHost := xds_route.VirtualHost{
 		Name:    name,
-		Domains: domains,
+		Domains: getVirtualHostDomains(name, domains),
 	}
 	return &virtualHost
 }
 
-// buildInboundRoutes takes a route information from the given inbound traffic policy and returns a list of xds routes
-func buildInboundRoutes(rules []*trafficpolicy.Rule) []*xds_route.Route {
+// getVirtualHostDomains returns the given domains of a virtual host normalized with envoy.NormalizeHost, so that the
+// requests for a host written in a different case or as an internationalized domain name match the domain. Envoy
+// rejects a virtual host listing the same domain twice, so the domains normalized to the same domain are only listed
+// once, and the invalid domains are ignored.
+func getVirtualHostDomains(virtualHostName string, domains []string) []string {
+	var normalizedDomains []string
+	seen := make(map[string]bool)
+	for _, domain := range domains {
+		normalized, err := envoy.NormalizeHost(domain)
+		if err != nil {
+			log.Error().Err(err).Msgf("Ignoring invalid domain %q of virtual host %s", domain, virtualHostName)
+			continue
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		normalizedDomains = append(normalizedDomains, normalized)
+	}
+	return normalizedDomains
+}
+
+// buildInboundRoutes takes a route information from the given inbound traffic policy and returns a list of xds routes.
+// Each route is named after the policy, HTTP method and path it matches, so that access logs identify the matched route.
+// Route names longer than envoy.MaxNameLength are bounded with envoy.BoundedName. The routes are ordered from the most to
+// the least specific route match.
+func buildInboundRoutes(policyName string, rules []*trafficpolicy.Rule, routingPolicies *trafficpolicy.RoutingPolicies) []*xds_route.Route {
 	var routes []*xds_route.Route
-	for _, rule := range rules {
+	for _, rule := range sortRulesBySpecificity(rules) {
 		// For a given route path, sanitize the methods in case there
 		// is wildcard or if there are duplicates
 		allowedMethods := sanitizeHTTPMethods(rule.Route.HTTPRouteMatch.Methods)
@@ -125,21 +206,53 @@ func buildInboundRoutes(rules []*trafficpolicy.Rule) []*xds_route.Route {
 			continue
 		}
 
+		// The responses to the requests matching the route are limited to the bandwidth limit of the route
+		if err := addBandwidthLimitPerFilterConfig(rbacPolicyForRoute, rule.Route.BandwidthLimitKiBps); err != nil {
+			log.Error().Err(err).Msgf("Error building bandwidth limit for rule [%v], skipping route addition", rule)
+			continue
+		}
+
+		trafficTargets := getTrafficTargetsForRoute(rule.Route.HTTPRouteMatch, routingPolicies)
+
 		// Each HTTP method corresponds to a separate route
 		for _, method := range allowedMethods {
 			route := buildRoute(rule.Route.HTTPRouteMatch.PathMatchType, rule.Route.HTTPRouteMatch.Path, method, rule.Route.HTTPRouteMatch.Headers, rule.Route.WeightedClusters, 100, InboundRoute)
+			route.Name = envoy.BoundedName(fmt.Sprintf("%s|%s|%s", policyName, method, rule.Route.HTTPRouteMatch.Path))
 			route.TypedPerFilterConfig = rbacPolicyForRoute
+			if pathRewrite := rule.Route.HTTPRouteMatch.PathRewrite; pathRewrite != nil {
+				route.GetRoute().RegexRewrite = buildRegexRewrite(pathRewrite)
+			}
+			setRetryPolicyAndTimeout(route, rule.Route)
+			if len(trafficTargets) > 0 {
+				route.Metadata = buildTrafficTargetsMetadata(trafficTargets)
+			}
 			routes = append(routes, route)
 		}
 	}
 	return routes
 }
 
-func buildOutboundRoutes(outRoutes []*trafficpolicy.RouteWeightedClusters) []*xds_route.Route {
+// buildRegexRewrite returns the rewrite of the path of the requests matching a route for the given path rewrite
+func buildRegexRewrite(pathRewrite *trafficpolicy.PathRewrite) *xds_matcher.RegexMatchAndSubstitute {
+	return &xds_matcher.RegexMatchAndSubstitute{
+		Pattern: &xds_matcher.RegexMatcher{
+			EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+			Regex:      pathRewrite.Pattern,
+		},
+		Substitution: pathRewrite.Substitution,
+	}
+}
+
+// buildOutboundRoutes returns the xds routes for the given routes of an outbound traffic policy, named after the policy.
+// The requests matching the routes are retried by the retry policies of the routes.
+func buildOutboundRoutes(policyName string, outRoutes []*trafficpolicy.RouteWeightedClusters) []*xds_route.Route {
 	var routes []*xds_route.Route
 	for _, outRoute := range outRoutes {
 		emptyHeaders := map[string]string{}
-		routes = append(routes, buildRoute(trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, emptyHeaders, outRoute.WeightedClusters, outRoute.TotalClustersWeight(), OutboundRoute))
+		route := buildRoute(trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, emptyHeaders, outRoute.WeightedClusters, outRoute.TotalClustersWeight(), OutboundRoute)
+		route.Name = envoy.BoundedName(policyName)
+		setRetryPolicyAndTimeout(route, *outRoute)
+		routes = append(routes, route)
 	}
 	return routes
 }
@@ -253,8 +366,9 @@ func getHeadersForRoute(method string, headersMap map[string]string) []*xds_rout
 	}
 	headers = append(headers, methodsHeader)
 
-	// add all other custom headers
-	for headerKey, headerValue := range headersMap {
+	// add all other custom headers, sorted by name
+	for _, headerKey := range sortedKeys(headersMap) {
+		headerValue := headersMap[headerKey]
 		// omit the host header as we have already configured this
 		if headerKey == httpHostHeader {
 			continue
@@ -2,11 +2,15 @@ package route
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	set "github.com/deckarep/golang-set"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	tassert "github.com/stretchr/testify/assert"
 
@@ -89,7 +93,7 @@ func TestBuildRouteConfiguration(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := BuildRouteConfiguration(tc.inbound, tc.outbound, nil)
+			actual := BuildRouteConfiguration(tc.inbound, tc.outbound, nil, nil)
 			assert.Equal(tc.expectedRouteConfigLen, len(actual))
 		})
 	}
@@ -98,16 +102,19 @@ func TestBuildRouteConfiguration(t *testing.T) {
 		name                      string
 		wasmEnabled               bool
 		expectedResponseHeaderLen int
+		expectedRequestHeaderLen  int
 	}{
 		{
-			name:                      "response headers added when WASM enabled",
+			name:                      "response and peer request headers added when WASM enabled",
 			wasmEnabled:               true,
 			expectedResponseHeaderLen: len((&envoy.Proxy{}).StatsHeaders()),
+			expectedRequestHeaderLen:  len((&envoy.Proxy{}).StatsPeerHeaders()),
 		},
 		{
-			name:                      "response headers not added when WASM disabled",
+			name:                      "response and peer request headers not added when WASM disabled",
 			wasmEnabled:               false,
 			expectedResponseHeaderLen: 0,
+			expectedRequestHeaderLen:  0,
 		},
 	}
 
@@ -116,14 +123,19 @@ func TestBuildRouteConfiguration(t *testing.T) {
 			oldWASMflag := featureflags.IsWASMStatsEnabled()
 			featureflags.Features.WASMStats = tc.wasmEnabled
 
-			actual := BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{testInbound}, nil, &envoy.Proxy{})
-			tassert.Len(t, actual, 1)
+			actual := BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{testInbound}, []*trafficpolicy.OutboundTrafficPolicy{testOutbound}, &envoy.Proxy{}, nil)
+			tassert.Len(t, actual, 2)
 			tassert.Len(t, actual[0].ResponseHeadersToAdd, tc.expectedResponseHeaderLen)
+			tassert.Len(t, actual[1].RequestHeadersToAdd, tc.expectedRequestHeaderLen)
+			for _, header := range actual[1].RequestHeadersToAdd {
+				tassert.False(t, header.Append.GetValue())
+			}
 
 			featureflags.Features.WASMStats = oldWASMflag
 		})
 	}
 }
+
 func TestBuildVirtualHostStub(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -148,6 +160,13 @@ func TestBuildVirtualHostStub(t *testing.T) {
 			domains:      []string{"domain1", "domain2"},
 			expectedName: "outbound_virtual-host|host",
 		},
+		{
+			name:         "inbound virtual host with a long host",
+			namePrefix:   inboundVirtualHost,
+			host:         strings.Repeat("a", envoy.MaxNameLength),
+			domains:      []string{"domain1"},
+			expectedName: envoy.BoundedName("inbound_virtual-host|" + strings.Repeat("a", envoy.MaxNameLength)),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -158,6 +177,23 @@ func TestBuildVirtualHostStub(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVirtualHostDomains(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Domains are normalized, and listed once
+	actual := getVirtualHostDomains("inbound_virtual-host|host", []string{
+		"bookstore.example.com",
+		"Bookstore.Example.com.",
+		"bookstore.example.com:8080",
+		"*.Bücher.example",
+		"-invalid.example.com",
+		"*",
+	})
+	assert.Equal([]string{"bookstore.example.com", "bookstore.example.com:8080", "*.xn--bcher-kva.example", "*"}, actual)
+
+	assert.Nil(getVirtualHostDomains("inbound_virtual-host|host", nil))
+}
 func TestBuildInboundRoutes(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -191,6 +227,7 @@ func TestBuildInboundRoutes(t *testing.T) {
 			},
 			expectFunc: func(actual []*xds_route.Route) {
 				assert.Equal(1, len(actual))
+				assert.Equal("bookstore-v1.default|GET|/hello", actual[0].Name)
 				assert.Equal("/hello", actual[0].GetMatch().GetSafeRegex().Regex)
 				assert.Equal("GET", actual[0].GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
 				assert.Equal(1, len(actual[0].GetRoute().GetWeightedClusters().Clusters))
@@ -198,6 +235,62 @@ func TestBuildInboundRoutes(t *testing.T) {
 				assert.Equal("testCluster-local", actual[0].GetRoute().GetWeightedClusters().Clusters[0].Name)
 				assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().Clusters[0].Weight.GetValue())
 				assert.NotNil(actual[0].TypedPerFilterConfig)
+				assert.NotContains(actual[0].TypedPerFilterConfig, wellknown.Fault)
+				assert.Nil(actual[0].GetRoute().RegexRewrite)
+			},
+		},
+		{
+			name: "route rule with bandwidth limit",
+			inputRules: []*trafficpolicy.Rule{
+				{
+					Route: trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+							Path:          "/hello",
+							PathMatchType: trafficpolicy.PathMatchRegex,
+							Methods:       []string{"GET"},
+						},
+						WeightedClusters:    set.NewSet(testWeightedCluster),
+						BandwidthLimitKiBps: 128,
+					},
+					AllowedServiceAccounts: set.NewSetFromSlice(
+						[]interface{}{service.K8sServiceAccount{Name: "foo", Namespace: "bar"}},
+					),
+				},
+			},
+			expectFunc: func(actual []*xds_route.Route) {
+				assert.Equal(1, len(actual))
+				assert.Contains(actual[0].TypedPerFilterConfig, wellknown.HTTPRoleBasedAccessControl)
+
+				fault := &xds_fault.HTTPFault{}
+				assert.Nil(ptypes.UnmarshalAny(actual[0].TypedPerFilterConfig[wellknown.Fault], fault))
+				assert.Equal(uint64(128), fault.ResponseRateLimit.GetFixedLimit().GetLimitKbps())
+			},
+		},
+		{
+			name: "route rule with path rewrite",
+			inputRules: []*trafficpolicy.Rule{
+				{
+					Route: trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+							Path:          "/api/v1(/.*)?$",
+							PathMatchType: trafficpolicy.PathMatchRegex,
+							Methods:       []string{"GET"},
+							PathRewrite: &trafficpolicy.PathRewrite{
+								Pattern:      "^/api/v1/?",
+								Substitution: "/",
+							},
+						},
+						WeightedClusters: set.NewSet(testWeightedCluster),
+					},
+					AllowedServiceAccounts: set.NewSetFromSlice(
+						[]interface{}{service.K8sServiceAccount{Name: "foo", Namespace: "bar"}},
+					),
+				},
+			},
+			expectFunc: func(actual []*xds_route.Route) {
+				assert.Equal(1, len(actual))
+				assert.Equal("^/api/v1/?", actual[0].GetRoute().GetRegexRewrite().GetPattern().GetRegex())
+				assert.Equal("/", actual[0].GetRoute().GetRegexRewrite().GetSubstitution())
 			},
 		},
 		{
@@ -224,7 +317,7 @@ func TestBuildInboundRoutes(t *testing.T) {
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			actual := buildInboundRoutes(tc.inputRules)
+			actual := buildInboundRoutes("bookstore-v1.default", tc.inputRules, nil)
 			tc.expectFunc(actual)
 		})
 	}
@@ -248,14 +341,22 @@ func TestBuildOutboundRoutes(t *testing.T) {
 			WeightedClusters: set.NewSet(testWeightedCluster),
 		},
 	}
-	actual := buildOutboundRoutes(input)
+	actual := buildOutboundRoutes("bookstore-v1.default", input)
 	assert.Equal(1, len(actual))
+	assert.Equal("bookstore-v1.default", actual[0].Name)
 	assert.Equal(".*", actual[0].GetMatch().GetSafeRegex().Regex)
 	assert.Equal(".*", actual[0].GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
 	assert.Equal(1, len(actual[0].GetRoute().GetWeightedClusters().Clusters))
 	assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().TotalWeight.GetValue())
 	assert.Equal("testCluster", actual[0].GetRoute().GetWeightedClusters().Clusters[0].Name)
 	assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().Clusters[0].Weight.GetValue())
+	assert.Nil(actual[0].GetRoute().RetryPolicy)
+
+	// The requests are retried by the retry policy of the route
+	input[0].RetryPolicy = &trafficpolicy.RetryPolicy{RetryOn: "5xx", NumRetries: 3}
+	actual = buildOutboundRoutes("bookstore-v1.default", input)
+	assert.Equal("5xx", actual[0].GetRoute().GetRetryPolicy().RetryOn)
+	assert.Equal(uint32(3), actual[0].GetRoute().GetRetryPolicy().GetNumRetries().GetValue())
 }
 
 func TestBuildRoute(t *testing.T) {
@@ -669,6 +770,19 @@ func TestGetHeadersForRoute(t *testing.T) {
 	assert.Equal(2, len(actual))
 	assert.Equal(MethodHeaderKey, actual[0].Name)
 	assert.Equal(routePolicy.Methods[0], actual[0].GetSafeRegexMatch().Regex)
+
+	// Returns the custom headers sorted by name
+	headers := map[string]string{
+		"x-version":     "v2",
+		userAgentHeader: tests.HTTPUserAgent,
+		"accept":        "application/json",
+	}
+	actual = getHeadersForRoute("GET", headers)
+	assert.Equal(4, len(actual))
+	assert.Equal(MethodHeaderKey, actual[0].Name)
+	assert.Equal("accept", actual[1].Name)
+	assert.Equal(userAgentHeader, actual[2].Name)
+	assert.Equal("x-version", actual[3].Name)
 }
 
 func TestLen(t *testing.T) {
@@ -0,0 +1,93 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// The specificity classes of the path of a route match, from the most to the least specific. Envoy selects the first
+// route of a virtual host matching a request, so the routes are ordered by the specificity of their path.
+const (
+	exactPathSpecificity = iota
+	prefixPathSpecificity
+	regexPathSpecificity
+	wildcardPathSpecificity
+)
+
+// sortRulesBySpecificity returns the given rules ordered from the most to the least specific route match, so that a
+// broad route, such as the prefix of an ingress rule, does not shadow a more specific route of the virtual host
+// regardless of the order in which their policies are listed. The rules with the same route match keep their order,
+// which is the precedence of the policies they are compiled from. The given slice is not modified.
+func sortRulesBySpecificity(rules []*trafficpolicy.Rule) []*trafficpolicy.Rule {
+	sorted := make([]*trafficpolicy.Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return isMoreSpecific(sorted[i].Route.HTTPRouteMatch, sorted[j].Route.HTTPRouteMatch)
+	})
+	return sorted
+}
+
+// isMoreSpecific returns true if the first given route match must be evaluated before the second one. Exact paths are
+// more specific than prefixes, longer prefixes than shorter ones, prefixes than regular expressions, and regular
+// expressions than the paths matching any request. Matches of the same path specificity are ordered by the number of
+// headers they match, then with the matches of specific methods first. The remaining ties are broken by comparing the
+// paths, methods and headers of the matches, so that the order is total for different matches.
+func isMoreSpecific(first, second trafficpolicy.HTTPRouteMatch) bool {
+	firstSpecificity, secondSpecificity := getPathSpecificity(first), getPathSpecificity(second)
+	if firstSpecificity != secondSpecificity {
+		return firstSpecificity < secondSpecificity
+	}
+	if firstSpecificity == prefixPathSpecificity && len(first.Path) != len(second.Path) {
+		return len(first.Path) > len(second.Path)
+	}
+	if len(first.Headers) != len(second.Headers) {
+		return len(first.Headers) > len(second.Headers)
+	}
+	firstMethods, secondMethods := sanitizeHTTPMethods(first.Methods), sanitizeHTTPMethods(second.Methods)
+	if firstWildcard, secondWildcard := isWildcardMethod(firstMethods), isWildcardMethod(secondMethods); firstWildcard != secondWildcard {
+		return secondWildcard
+	}
+	if first.Path != second.Path {
+		return first.Path < second.Path
+	}
+	if firstKey, secondKey := strings.Join(firstMethods, ","), strings.Join(secondMethods, ","); firstKey != secondKey {
+		return firstKey < secondKey
+	}
+	return getHeadersKey(first.Headers) < getHeadersKey(second.Headers)
+}
+
+// getPathSpecificity returns the specificity class of the path of the given route match
+func getPathSpecificity(match trafficpolicy.HTTPRouteMatch) int {
+	switch match.PathMatchType {
+	case trafficpolicy.PathMatchExact:
+		return exactPathSpecificity
+	case trafficpolicy.PathMatchPrefix:
+		if match.Path == "/" {
+			return wildcardPathSpecificity
+		}
+		return prefixPathSpecificity
+	default:
+		if match.Path == constants.RegexMatchAll {
+			return wildcardPathSpecificity
+		}
+		return regexPathSpecificity
+	}
+}
+
+// isWildcardMethod returns true if the given sanitized methods match any method
+func isWildcardMethod(methods []string) bool {
+	return len(methods) == 0 || methods[0] == constants.WildcardHTTPMethod
+}
+
+// getHeadersKey returns the given headers as a string, sorted by name
+func getHeadersKey(headers map[string]string) string {
+	var pairs []string
+	for _, name := range sortedKeys(headers) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, headers[name]))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,144 @@
+package route
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	set "github.com/deckarep/golang-set"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// specificityTestMatches are route matches of every specificity, listed from the most to the least specific
+var specificityTestMatches = []trafficpolicy.HTTPRouteMatch{
+	{Path: "/books/1", PathMatchType: trafficpolicy.PathMatchExact, Methods: []string{"GET"}},
+	{Path: "/books/2", PathMatchType: trafficpolicy.PathMatchExact, Methods: []string{"GET"}},
+	{Path: "/books/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"GET"}, Headers: map[string]string{"user-agent": "bookbuyer"}},
+	{Path: "/books/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"GET"}},
+	{Path: "/books/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{constants.WildcardHTTPMethod}},
+	{Path: "/books", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"GET"}},
+	{Path: "/b", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"GET", "POST"}},
+	{Path: "/b", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"POST"}},
+	{Path: "/buy", PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{"GET"}, Headers: map[string]string{"user-agent": "bookbuyer"}},
+	{Path: "/buy", PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{"GET"}},
+	{Path: "/sell", PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{"GET"}},
+	{Path: "/buy", PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{constants.WildcardHTTPMethod}},
+	{Path: constants.RegexMatchAll, PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{"GET"}, Headers: map[string]string{"user-agent": "bookbuyer"}},
+	{Path: constants.RegexMatchAll, PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{"GET"}},
+	{Path: "/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"GET"}},
+	{Path: constants.RegexMatchAll, PathMatchType: trafficpolicy.PathMatchRegex, Methods: []string{constants.WildcardHTTPMethod}},
+}
+
+func newSpecificityTestRules(matches []trafficpolicy.HTTPRouteMatch) []*trafficpolicy.Rule {
+	var rules []*trafficpolicy.Rule
+	for _, match := range matches {
+		rules = append(rules, &trafficpolicy.Rule{
+			Route: trafficpolicy.RouteWeightedClusters{
+				HTTPRouteMatch:   match,
+				WeightedClusters: set.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+			},
+			AllowedServiceAccounts: set.NewSet(tests.BookbuyerServiceAccount),
+		})
+	}
+	return rules
+}
+
+func TestSortRulesBySpecificity(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Whatever the order of the rules, they are sorted from the most to the least specific route match
+	sortsAnyOrder := func(seed int64) bool {
+		rules := newSpecificityTestRules(specificityTestMatches)
+		rand.New(rand.NewSource(seed)).Shuffle(len(rules), func(i, j int) {
+			rules[i], rules[j] = rules[j], rules[i]
+		})
+
+		sorted := sortRulesBySpecificity(rules)
+		if len(sorted) != len(specificityTestMatches) {
+			return false
+		}
+		for i, rule := range sorted {
+			if !assert.Equal(specificityTestMatches[i], rule.Route.HTTPRouteMatch) {
+				return false
+			}
+		}
+		return true
+	}
+	assert.Nil(quick.Check(sortsAnyOrder, nil))
+
+	// A broad ingress prefix listed first does not shadow a more specific SMI route
+	rules := newSpecificityTestRules([]trafficpolicy.HTTPRouteMatch{
+		{Path: "/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{constants.WildcardHTTPMethod}},
+		tests.BookstoreBuyHTTPRoute,
+	})
+	sorted := sortRulesBySpecificity(rules)
+	assert.Equal(tests.BookstoreBuyHTTPRoute, sorted[0].Route.HTTPRouteMatch)
+	assert.Equal("/", rules[0].Route.HTTPRouteMatch.Path, "the given rules must not be modified")
+
+	// Rules with the same route match keep their order
+	first := newSpecificityTestRules([]trafficpolicy.HTTPRouteMatch{tests.BookstoreBuyHTTPRoute})[0]
+	second := newSpecificityTestRules([]trafficpolicy.HTTPRouteMatch{tests.BookstoreBuyHTTPRoute})[0]
+	second.Route.WeightedClusters = set.NewSet(tests.BookstoreV2DefaultWeightedCluster)
+	assert.Equal([]*trafficpolicy.Rule{first, second}, sortRulesBySpecificity([]*trafficpolicy.Rule{first, second}))
+	assert.Equal([]*trafficpolicy.Rule{second, first}, sortRulesBySpecificity([]*trafficpolicy.Rule{second, first}))
+}
+
+func TestIsMoreSpecific(t *testing.T) {
+	assert := tassert.New(t)
+
+	generate := func(r *rand.Rand) trafficpolicy.HTTPRouteMatch {
+		return specificityTestMatches[r.Intn(len(specificityTestMatches))]
+	}
+
+	// The order is a strict total order of the route matches
+	isStrictTotalOrder := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		a, b, c := generate(r), generate(r), generate(r)
+		if isMoreSpecific(a, a) {
+			return false
+		}
+		if isMoreSpecific(a, b) && isMoreSpecific(b, a) {
+			return false
+		}
+		if isMoreSpecific(a, b) && isMoreSpecific(b, c) && !isMoreSpecific(a, c) {
+			return false
+		}
+		return isMoreSpecific(a, b) || isMoreSpecific(b, a) || assert.Equal(a, b)
+	}
+	assert.Nil(quick.Check(isStrictTotalOrder, &quick.Config{MaxCount: 1000}))
+}
+
+func TestBuildInboundRoutesOrder(t *testing.T) {
+	assert := tassert.New(t)
+
+	expected := buildInboundRoutes("bookstore-v1.default", newSpecificityTestRules(specificityTestMatches), nil)
+
+	// The routes of a virtual host do not depend on the order of the rules of its policy
+	buildsSameRoutes := func(seed int64) bool {
+		rules := newSpecificityTestRules(specificityTestMatches)
+		rand.New(rand.NewSource(seed)).Shuffle(len(rules), func(i, j int) {
+			rules[i], rules[j] = rules[j], rules[i]
+		})
+
+		actual := buildInboundRoutes("bookstore-v1.default", rules, nil)
+		if len(actual) != len(expected) {
+			return false
+		}
+		for i := range actual {
+			if actual[i].Name != expected[i].Name || !assert.Equal(expected[i].Match, actual[i].Match) {
+				return false
+			}
+		}
+		return true
+	}
+	assert.Nil(quick.Check(buildsSameRoutes, nil))
+
+	// The exact routes precede the prefix routes, which precede the regex routes and the wildcard routes
+	assert.Equal("/books/1", expected[0].GetMatch().GetPath())
+	assert.Equal("/books/", expected[2].GetMatch().GetPrefix())
+	assert.Equal(constants.RegexMatchAll, expected[len(expected)-1].GetMatch().GetSafeRegex().GetRegex())
+}
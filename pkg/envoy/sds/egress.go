@@ -0,0 +1,90 @@
+package sds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// getEgressTLSSecret creates the secret originating TLS to external hosts from the Kubernetes secret named by the given
+// SDS cert: a validation context secret trusting the CA bundle of a root-cert-for-egress-tls cert, or a TLS certificate
+// secret of an egress-client-cert cert. The Kubernetes secret must be referenced by the TLS originations of the given
+// egress policy, so that a proxy can only read the secrets configured for its egress traffic.
+func getEgressTLSSecret(meshCatalog catalog.MeshCataloger, sdscert envoy.SDSCert, egressPolicy *trafficpolicy.EgressPolicy) (*xds_auth.Secret, error) {
+	var caBundleSecrets, clientCertSecrets []string
+	if egressPolicy != nil {
+		caBundleSecrets, clientCertSecrets = egressPolicy.GetTLSOriginationSecrets()
+	}
+
+	switch sdscert.CertType {
+	case envoy.RootCertTypeForEgressTLS:
+		if !contains(caBundleSecrets, sdscert.Name) {
+			log.Error().Err(errGotUnexpectedCertRequest).Msgf("Request for SDS cert %s is not referenced by the TLS origination of an Egress policy", sdscert)
+			return nil, errGotUnexpectedCertRequest
+		}
+
+		caBundle, err := meshCatalog.GetEgressCABundle(sdscert.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &xds_auth.Secret{
+			// The Name field must match the tls_context.common_tls_context.validation_context_sds_secret_config.name
+			Name: sdscert.String(),
+			Type: &xds_auth.Secret_ValidationContext{
+				ValidationContext: &xds_auth.CertificateValidationContext{
+					TrustedCa: &xds_core.DataSource{
+						Specifier: &xds_core.DataSource_InlineBytes{
+							InlineBytes: caBundle,
+						},
+					},
+				},
+			},
+		}, nil
+
+	case envoy.EgressClientCertType:
+		if !contains(clientCertSecrets, sdscert.Name) {
+			log.Error().Err(errGotUnexpectedCertRequest).Msgf("Request for SDS cert %s is not referenced by the TLS origination of an Egress policy", sdscert)
+			return nil, errGotUnexpectedCertRequest
+		}
+
+		certChain, privateKey, err := meshCatalog.GetEgressClientCertificate(sdscert.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &xds_auth.Secret{
+			// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
+			Name: sdscert.String(),
+			Type: &xds_auth.Secret_TlsCertificate{
+				TlsCertificate: &xds_auth.TlsCertificate{
+					CertificateChain: &xds_core.DataSource{
+						Specifier: &xds_core.DataSource_InlineBytes{
+							InlineBytes: certChain,
+						},
+					},
+					PrivateKey: &xds_core.DataSource{
+						Specifier: &xds_core.DataSource_InlineBytes{
+							InlineBytes: privateKey,
+						},
+					},
+				},
+			},
+		}, nil
+
+	default:
+		return nil, errGotUnexpectedCertRequest
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
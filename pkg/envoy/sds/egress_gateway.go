@@ -0,0 +1,102 @@
+package sds
+
+import (
+	"sort"
+
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewEgressGatewayResponse creates a new Secrets Discovery Response for the egress gateway.
+// The egress gateway presents the service certificate of its service account to the sidecar proxies, and accepts the
+// certificate of any sidecar proxy of the mesh, as the sidecar proxies only forward the egress traffic allowed by
+// the Egress policies of their service account. The CA bundles and client certificates originating TLS to external
+// hosts are served as referenced by the Egress policies.
+func NewEgressGatewayResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	log.Info().Msgf("Composing SDS Discovery Response for egress gateway with certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+
+	svcAccount := catalog.GetEgressGatewayServiceAccount(cfg.GetOSMNamespace())
+	si := identity.GetKubernetesServiceIdentity(svcAccount, identity.ClusterLocalTrustDomain)
+	cert, err := certManager.IssueCertificate(certificate.CommonName(si), cfg.GetServiceCertValidityPeriod())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing a certificate for egress gateway with certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	discoveryResponse := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeSDS),
+	}
+
+	// The secrets are sent in the order of their names, for the response not to depend on the order of the requested certs
+	requestedCerts := append([]string{}, request.ResourceNames...)
+	sort.Strings(requestedCerts)
+
+	for _, requestedCertificate := range requestedCerts {
+		sdsCert, err := envoy.UnmarshalSDSCert(requestedCertificate)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid resource kind requested: %q", requestedCertificate)
+			continue
+		}
+
+		// The secrets originating TLS to external hosts are named after the Kubernetes secrets referenced by the Egress
+		// policies
+		if sdsCert.CertType == envoy.RootCertTypeForEgressTLS || sdsCert.CertType == envoy.EgressClientCertType {
+			envoySecret, err := getEgressTLSSecret(meshCatalog, *sdsCert, meshCatalog.GetEgressGatewayPolicy())
+			if err != nil {
+				log.Error().Err(err).Msgf("Error creating cert %s for egress gateway with certificate SerialNumber=%s", requestedCertificate, proxy.GetCertificateSerialNumber())
+				continue
+			}
+			marshalledSecret, err := ptypes.MarshalAny(envoySecret)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error marshaling Envoy secret %s for egress gateway with certificate SerialNumber=%s", envoySecret.Name, proxy.GetCertificateSerialNumber())
+				continue
+			}
+			discoveryResponse.Resources = append(discoveryResponse.Resources, marshalledSecret)
+			continue
+		}
+
+		// Every other secret served to the egress gateway is named after its service account
+		requestedSvcAccount, err := service.UnmarshalK8sServiceAccount(sdsCert.Name)
+		if err != nil || *requestedSvcAccount != svcAccount {
+			log.Error().Err(errGotUnexpectedCertRequest).Msgf("Request for SDS cert %s does not belong to egress gateway with certificate SerialNumber=%s",
+				requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+
+		var envoySecret *xds_auth.Secret
+		switch sdsCert.CertType {
+		case envoy.ServiceCertType:
+			envoySecret, err = getServiceCertSecret(cert, requestedCertificate)
+
+		case envoy.RootCertTypeForMTLSInbound:
+			// Any sidecar proxy of the mesh may forward its egress traffic to the egress gateway, so downstream
+			// certificates are validated against the root certificate without SAN matching.
+			envoySecret = getRootCertSecret(cert, *sdsCert)
+
+		default:
+			log.Error().Msgf("Unsupported SDS cert %s requested by egress gateway with certificate SerialNumber=%s", requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Error creating cert %s for egress gateway with certificate SerialNumber=%s", requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+
+		marshalledSecret, err := ptypes.MarshalAny(envoySecret)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling Envoy secret %s for egress gateway with certificate SerialNumber=%s", envoySecret.Name, proxy.GetCertificateSerialNumber())
+			continue
+		}
+		discoveryResponse.Resources = append(discoveryResponse.Resources, marshalledSecret)
+	}
+
+	return discoveryResponse, nil
+}
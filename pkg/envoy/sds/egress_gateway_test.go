@@ -0,0 +1,60 @@
+package sds
+
+import (
+	"testing"
+	"time"
+
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestNewEgressGatewayResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	certManager := tresor.NewFakeCertManager(mockConfigurator)
+	proxy := envoy.NewProxy("", "", nil)
+
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(1 * time.Hour).AnyTimes()
+
+	request := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+		ResourceNames: []string{
+			envoy.SDSCert{Name: "osm-system/osm-egress-gateway", CertType: envoy.ServiceCertType}.String(),
+			envoy.SDSCert{Name: "osm-system/osm-egress-gateway", CertType: envoy.RootCertTypeForMTLSInbound}.String(),
+
+			// Not supported for the egress gateway
+			envoy.SDSCert{Name: "osm-system/osm-egress-gateway", CertType: envoy.RootCertTypeForMTLSOutbound}.String(),
+
+			// Service account other than the one of the egress gateway
+			envoy.SDSCert{Name: "default/bookstore", CertType: envoy.ServiceCertType}.String(),
+		},
+	}
+
+	actual, err := NewEgressGatewayResponse(mockCatalog, proxy, request, mockConfigurator, certManager)
+	assert.Nil(err)
+	assert.Len(actual.Resources, 2)
+
+	rootCert := &xds_auth.Secret{}
+	assert.Nil(ptypes.UnmarshalAny(actual.Resources[0], rootCert))
+	assert.Equal("root-cert-for-mtls-inbound:osm-system/osm-egress-gateway", rootCert.Name)
+	// Any sidecar proxy of the mesh is accepted
+	assert.Empty(rootCert.GetValidationContext().MatchSubjectAltNames)
+
+	serviceCert := &xds_auth.Secret{}
+	assert.Nil(ptypes.UnmarshalAny(actual.Resources[1], serviceCert))
+	assert.Equal("service-cert:osm-system/osm-egress-gateway", serviceCert.Name)
+	assert.NotNil(serviceCert.GetTlsCertificate())
+}
@@ -0,0 +1,56 @@
+package sds
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressTLSSecret(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	egressPolicy := &trafficpolicy.EgressPolicy{
+		TLSOriginations: map[uint32]map[string]*trafficpolicy.EgressTLSOrigination{
+			80: {"httpbin.org": {Port: 443, CABundleSecret: "curl/httpbin-ca", ClientCertSecret: "curl/httpbin-client"}},
+		},
+	}
+
+	// The CA bundle referenced by the TLS origination is served as a validation context
+	caBundleCert := envoy.SDSCert{Name: "curl/httpbin-ca", CertType: envoy.RootCertTypeForEgressTLS}
+	mockCatalog.EXPECT().GetEgressCABundle("curl/httpbin-ca").Return([]byte("ca-bundle"), nil).Times(1)
+	secret, err := getEgressTLSSecret(mockCatalog, caBundleCert, egressPolicy)
+	assert.Nil(err)
+	assert.Equal("root-cert-for-egress-tls:curl/httpbin-ca", secret.Name)
+	assert.Equal([]byte("ca-bundle"), secret.GetValidationContext().TrustedCa.GetInlineBytes())
+
+	// The client certificate referenced by the TLS origination is served as a TLS certificate
+	clientCert := envoy.SDSCert{Name: "curl/httpbin-client", CertType: envoy.EgressClientCertType}
+	mockCatalog.EXPECT().GetEgressClientCertificate("curl/httpbin-client").Return([]byte("cert"), []byte("key"), nil).Times(1)
+	secret, err = getEgressTLSSecret(mockCatalog, clientCert, egressPolicy)
+	assert.Nil(err)
+	assert.Equal("egress-client-cert:curl/httpbin-client", secret.Name)
+	assert.Equal([]byte("cert"), secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+	assert.Equal([]byte("key"), secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+
+	// Secrets not referenced by the TLS originations of the proxy are not served
+	_, err = getEgressTLSSecret(mockCatalog, envoy.SDSCert{Name: "curl/other", CertType: envoy.RootCertTypeForEgressTLS}, egressPolicy)
+	assert.Equal(errGotUnexpectedCertRequest, err)
+	_, err = getEgressTLSSecret(mockCatalog, envoy.SDSCert{Name: "curl/httpbin-ca", CertType: envoy.EgressClientCertType}, egressPolicy)
+	assert.Equal(errGotUnexpectedCertRequest, err)
+	_, err = getEgressTLSSecret(mockCatalog, caBundleCert, nil)
+	assert.Equal(errGotUnexpectedCertRequest, err)
+
+	// Errors reading the secret are returned
+	mockCatalog.EXPECT().GetEgressCABundle("curl/httpbin-ca").Return(nil, errors.New("secret not found")).Times(1)
+	_, err = getEgressTLSSecret(mockCatalog, caBundleCert, egressPolicy)
+	assert.NotNil(err)
+}
@@ -0,0 +1,110 @@
+package sds
+
+import (
+	"sort"
+
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewNodeProxyResponse creates a new Secrets Discovery Response for a per-node proxy.
+// A per-node proxy presents the service certificates of the node proxy mode pods on its node, so it is only
+// served secrets for the service accounts of these pods.
+func NewNodeProxyResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager) (*xds_discovery.DiscoveryResponse, error) {
+	log.Info().Msgf("Composing SDS Discovery Response for node proxy with certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+
+	pods, err := meshCatalog.ListPodsForNodeProxy(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing pods for node proxy with certificate SerialNumber=%s", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	localSvcAccounts := make(map[service.K8sServiceAccount]bool)
+	for _, pod := range pods {
+		localSvcAccounts[service.K8sServiceAccount{Name: pod.Spec.ServiceAccountName, Namespace: pod.Namespace}] = true
+	}
+
+	discoveryResponse := &xds_discovery.DiscoveryResponse{
+		TypeUrl: string(envoy.TypeSDS),
+	}
+
+	// Service certificates are issued once per service account
+	certs := make(map[service.K8sServiceAccount]certificate.Certificater)
+
+	// The secrets are sent in the order of their names, for the response not to depend on the order of the requested certs
+	requestedCerts := append([]string{}, request.ResourceNames...)
+	sort.Strings(requestedCerts)
+
+	for _, requestedCertificate := range requestedCerts {
+		sdsCert, err := envoy.UnmarshalSDSCert(requestedCertificate)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid resource kind requested: %q", requestedCertificate)
+			continue
+		}
+
+		// Every secret served to a node proxy is named after the service account of a pod on its node
+		svcAccount, err := service.UnmarshalK8sServiceAccount(sdsCert.Name)
+		if err != nil || !localSvcAccounts[*svcAccount] {
+			log.Error().Err(errGotUnexpectedCertRequest).Msgf("Request for SDS cert %s does not belong to a pod served by node proxy with certificate SerialNumber=%s",
+				requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+
+		cert, ok := certs[*svcAccount]
+		if !ok {
+			si := identity.GetKubernetesServiceIdentity(*svcAccount, identity.ClusterLocalTrustDomain)
+			cert, err = certManager.IssueCertificate(certificate.CommonName(si), cfg.GetServiceCertValidityPeriod())
+			if err != nil {
+				log.Error().Err(err).Msgf("Error issuing a certificate for service account %s for node proxy with certificate SerialNumber=%s",
+					svcAccount, proxy.GetCertificateSerialNumber())
+				return nil, err
+			}
+			certs[*svcAccount] = cert
+		}
+
+		var envoySecret *xds_auth.Secret
+		switch sdsCert.CertType {
+		case envoy.ServiceCertType:
+			envoySecret, err = getServiceCertSecret(cert, requestedCertificate)
+
+		case envoy.RootCertTypeForMTLSInbound:
+			s := &sdsImpl{
+				meshCatalog: meshCatalog,
+				certManager: certManager,
+				cfg:         cfg,
+				svcAccount:  *svcAccount,
+			}
+			envoySecret, err = s.getRootCert(cert, *sdsCert)
+
+		case envoy.RootCertTypeForMTLSOutbound:
+			// The upstream of a node proxy is only known by its original destination address, so
+			// upstream certificates are validated against the root certificate without SAN matching.
+			envoySecret = getRootCertSecret(cert, *sdsCert)
+
+		default:
+			log.Error().Msgf("Unsupported SDS cert %s requested by node proxy with certificate SerialNumber=%s", requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Error creating cert %s for node proxy with certificate SerialNumber=%s", requestedCertificate, proxy.GetCertificateSerialNumber())
+			continue
+		}
+
+		marshalledSecret, err := ptypes.MarshalAny(envoySecret)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling Envoy secret %s for node proxy with certificate SerialNumber=%s", envoySecret.Name, proxy.GetCertificateSerialNumber())
+			continue
+		}
+		discoveryResponse.Resources = append(discoveryResponse.Resources, marshalledSecret)
+	}
+
+	return discoveryResponse, nil
+}
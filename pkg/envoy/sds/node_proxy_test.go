@@ -0,0 +1,78 @@
+package sds
+
+import (
+	"testing"
+	"time"
+
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestNewNodeProxyResponse(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	certManager := tresor.NewFakeCertManager(mockConfigurator)
+	proxy := envoy.NewProxy("", "", nil)
+
+	localSvcAccount := service.K8sServiceAccount{Name: "bookbuyer", Namespace: "default"}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookbuyer-abcde",
+			Namespace: localSvcAccount.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: localSvcAccount.Name,
+		},
+	}
+
+	mockCatalog.EXPECT().ListPodsForNodeProxy(proxy).Return([]*corev1.Pod{pod}, nil)
+	mockCatalog.EXPECT().ListAllowedInboundServiceAccounts(localSvcAccount).Return([]service.K8sServiceAccount{{Name: "bookstore", Namespace: "default"}}, nil)
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(1 * time.Hour).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+
+	request := &xds_discovery.DiscoveryRequest{
+		TypeUrl: string(envoy.TypeSDS),
+		ResourceNames: []string{
+			envoy.SDSCert{Name: localSvcAccount.String(), CertType: envoy.ServiceCertType}.String(),
+			envoy.SDSCert{Name: localSvcAccount.String(), CertType: envoy.RootCertTypeForMTLSInbound}.String(),
+			envoy.SDSCert{Name: localSvcAccount.String(), CertType: envoy.RootCertTypeForMTLSOutbound}.String(),
+
+			// Not supported for node proxies
+			envoy.SDSCert{Name: localSvcAccount.String(), CertType: envoy.RootCertTypeForHTTPS}.String(),
+
+			// Service account of a pod that is not served by the node proxy
+			envoy.SDSCert{Name: "default/bookstore", CertType: envoy.ServiceCertType}.String(),
+		},
+	}
+
+	actual, err := NewNodeProxyResponse(mockCatalog, proxy, request, mockConfigurator, certManager)
+	assert.Nil(err)
+	assert.Len(actual.Resources, 3)
+
+	secrets := make(map[string]*xds_auth.Secret)
+	for _, resource := range actual.Resources {
+		secret := &xds_auth.Secret{}
+		assert.Nil(ptypes.UnmarshalAny(resource, secret))
+		secrets[secret.Name] = secret
+	}
+
+	assert.NotNil(secrets["service-cert:default/bookbuyer"].GetTlsCertificate())
+	assert.Equal([]string{"bookstore.default.cluster.local"},
+		subjectAltNamesToStr(secrets["root-cert-for-mtls-inbound:default/bookbuyer"].GetValidationContext().MatchSubjectAltNames))
+	assert.Empty(secrets["root-cert-for-mtls-outbound:default/bookbuyer"].GetValidationContext().MatchSubjectAltNames)
+}
@@ -1,6 +1,8 @@
 package sds
 
 import (
+	"sort"
+
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -11,6 +13,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/featureflags"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 )
@@ -53,7 +56,14 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request
 
 	// 2. Create SDS secret resources based on the requested certs in the DiscoveryRequest
 	// request.ResourceNames is expected to be a list of either "service-cert:namespace/service" or "root-cert:namespace/service"
-	for _, envoyProto := range s.getSDSSecrets(cert, requestedCerts, proxy) {
+	secrets := s.getSDSSecrets(cert, requestedCerts, proxy)
+
+	// The secrets are sorted by name for the response not to depend on the order of the requested certs
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	for _, envoyProto := range secrets {
 		marshalledSecret, err := ptypes.MarshalAny(envoyProto)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error marshaling Envoy secret %s for proxy with certificate SerialNumber=%s on Pod with UID=%s", envoyProto.Name, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
@@ -71,6 +81,8 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 	// - "root-cert-for-mtls-outbound:namespace/service"
 	// - "root-cert-for-mtls-inbound:namespace/service-service-account"
 	// - "root-cert-for-https:namespace/service-service-account"
+	// - "root-cert-for-egress-tls:namespace/secret"
+	// - "egress-client-cert:namespace/secret"
 
 	// The Envoy makes a request for a list of resources (aka certificates), which we will send as a response to the SDS request.
 	for _, requestedCertificate := range requestedCerts {
@@ -102,12 +114,77 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 				continue
 			}
 			certs = append(certs, envoySecret)
+
+		// The CA bundle used to validate the client certificates of ingress sources is requested
+		case envoy.RootCertTypeForIngressMTLS:
+			envoySecret, err := s.getIngressCABundleSecret(*sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error creating cert %s for Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s",
+					requestedCertificate, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+				continue
+			}
+			certs = append(certs, envoySecret)
+
+		// The CA bundle or the client certificate used to originate TLS to an external host is requested
+		case envoy.RootCertTypeForEgressTLS, envoy.EgressClientCertType:
+			envoySecret, err := getEgressTLSSecret(s.meshCatalog, *sdsCert, s.meshCatalog.GetEgressPolicy(s.svcAccount))
+			if err != nil {
+				log.Error().Err(err).Msgf("Error creating cert %s for Envoy with xDS Certificate SerialNumber=%s on Pod with UID=%s",
+					requestedCertificate, proxy.GetCertificateSerialNumber(), proxy.GetPodUID())
+				continue
+			}
+			certs = append(certs, envoySecret)
 		}
 	}
 
 	return certs
 }
 
+// getIngressCABundleSecret creates a validation context secret trusting the CA bundle stored in the Kubernetes secret
+// named by the given SDS cert. The Kubernetes secret must be referenced by the IngressBackend of one of the services
+// of the proxy, so that a proxy can only read the CA bundles configured for it.
+func (s *sdsImpl) getIngressCABundleSecret(sdscert envoy.SDSCert, proxy *envoy.Proxy) (*xds_auth.Secret, error) {
+	svcList, err := s.meshCatalog.GetServicesFromEnvoyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := false
+	for _, svc := range svcList {
+		policy, err := s.meshCatalog.GetIngressBackendPolicy(svc)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil && policy.CABundleSecret == sdscert.Name {
+			referenced = true
+			break
+		}
+	}
+	if !referenced {
+		log.Error().Err(errGotUnexpectedCertRequest).Msgf("Request for SDS cert %s is not referenced by an IngressBackend of the services of proxy with identity %s", sdscert, s.svcAccount)
+		return nil, errGotUnexpectedCertRequest
+	}
+
+	caBundle, err := s.meshCatalog.GetIngressCABundle(sdscert.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_auth.Secret{
+		// The Name field must match the tls_context.common_tls_context.validation_context_sds_secret_config.name
+		Name: sdscert.String(),
+		Type: &xds_auth.Secret_ValidationContext{
+			ValidationContext: &xds_auth.CertificateValidationContext{
+				TrustedCa: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: caBundle,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 // getServiceCertSecret creates the struct with certificates for the service, which the
 // connected Envoy proxy belongs to.
 func getServiceCertSecret(cert certificate.Certificater, name string) (*xds_auth.Secret, error) {
@@ -132,8 +209,9 @@ func getServiceCertSecret(cert certificate.Certificater, name string) (*xds_auth
 	return secret, nil
 }
 
-func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert envoy.SDSCert) (*xds_auth.Secret, error) {
-	secret := &xds_auth.Secret{
+// getRootCertSecret creates a validation context secret trusting the CA that issued the given certificate
+func getRootCertSecret(cert certificate.Certificater, sdscert envoy.SDSCert) *xds_auth.Secret {
+	return &xds_auth.Secret{
 		// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
 		Name: sdscert.String(),
 		Type: &xds_auth.Secret_ValidationContext{
@@ -146,6 +224,10 @@ func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert envoy.SDSCe
 			},
 		},
 	}
+}
+
+func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert envoy.SDSCert) (*xds_auth.Secret, error) {
+	secret := getRootCertSecret(cert, sdscert)
 
 	if s.cfg.IsPermissiveTrafficPolicyMode() {
 		// In permissive mode, there are no SMI TrafficTarget policies, so
@@ -164,6 +246,13 @@ func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert envoy.SDSCe
 			log.Error().Err(err).Msgf("Error unmarshalling upstream service for outbound cert %s", sdscert)
 			return nil, err
 		}
+		if featureflags.IsEgressGatewayEnabled() && *meshSvc == catalog.GetEgressGatewayService(s.cfg.GetOSMNamespace()) {
+			// The egress gateway runs outside of the monitored namespaces, so its identity is known rather than looked up
+			secret.GetValidationContext().MatchSubjectAltNames = getSubjectAltNamesFromSvcAccount([]service.K8sServiceAccount{
+				catalog.GetEgressGatewayServiceAccount(s.cfg.GetOSMNamespace()),
+			})
+			return secret, nil
+		}
 		svcAccounts, err := s.meshCatalog.ListServiceAccountsForService(*meshSvc)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error listing service accounts for service %s", meshSvc)
@@ -217,6 +306,11 @@ func getSubjectAltNamesFromSvcAccount(svcAccounts []service.K8sServiceAccount) [
 		matchSANs = append(matchSANs, &match)
 	}
 
+	// The service accounts are listed from the SMI policies in no particular order
+	sort.Slice(matchSANs, func(i, j int) bool {
+		return matchSANs[i].GetExact() < matchSANs[j].GetExact()
+	})
+
 	return matchSANs
 }
 
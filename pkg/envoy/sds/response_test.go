@@ -17,6 +17,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // TestNewResponse sets up a fake kube client, then a pod and makes an SDS request,
@@ -291,6 +292,7 @@ func TestGetSDSSecrets(t *testing.T) {
 		// - "root-cert-for-mtls-outbound:namespace/service"
 		// - "root-cert-for-mtls-inbound:namespace/service"
 		// - "root-cert-for-https:namespace/service"
+		// - "root-cert-for-ingress-mtls:namespace/secret"
 		requestedCerts []string
 
 		// expectations
@@ -401,6 +403,47 @@ func TestGetSDSSecrets(t *testing.T) {
 			expectedSecretCount: 0, // error is logged and no SDS secret is created
 		},
 		// Test case 5 end -------------------------------
+
+		// Test case 6: root-cert-for-ingress-mtls requested -------------------------------
+		{
+			name:            "test root-cert-for-ingress-mtls cert type request",
+			proxySvcAccount: service.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"},
+
+			prepare: func(d *dynamicMock) {
+				svc := service.MeshService{Name: "service-1", Namespace: "ns-1"}
+				d.mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return([]service.MeshService{svc}, nil).Times(1)
+				d.mockCatalog.EXPECT().GetIngressBackendPolicy(svc).Return(&trafficpolicy.IngressBackendPolicy{CABundleSecret: "ns-1/ingress-ca"}, nil).Times(1)
+				d.mockCatalog.EXPECT().GetIngressCABundle("ns-1/ingress-ca").Return([]byte("ca-bundle"), nil).Times(1)
+			},
+
+			sdsCertType:    envoy.RootCertTypeForIngressMTLS,
+			requestedCerts: []string{"root-cert-for-ingress-mtls:ns-1/ingress-ca"}, // CA bundle requested
+
+			// expectations
+			expectedSANs:        []string{},
+			expectedSecretCount: 1,
+		},
+		// Test case 6 end -------------------------------
+
+		// Test case 7: root-cert-for-ingress-mtls not referenced by the proxy's services requested -------------------------------
+		{
+			name:            "test root-cert-for-ingress-mtls cert type request for an unreferenced secret",
+			proxySvcAccount: service.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"},
+
+			prepare: func(d *dynamicMock) {
+				svc := service.MeshService{Name: "service-1", Namespace: "ns-1"}
+				d.mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return([]service.MeshService{svc}, nil).Times(1)
+				d.mockCatalog.EXPECT().GetIngressBackendPolicy(svc).Return(nil, nil).Times(1)
+			},
+
+			sdsCertType:    envoy.RootCertTypeForIngressMTLS,
+			requestedCerts: []string{"root-cert-for-ingress-mtls:ns-2/other-ca"}, // CA bundle requested
+
+			// expectations
+			expectedSANs:        []string{},
+			expectedSecretCount: 0, // error is logged and no SDS secret is created
+		},
+		// Test case 7 end -------------------------------
 	}
 
 	for i, tc := range testCases {
@@ -455,6 +498,9 @@ func TestGetSDSSecrets(t *testing.T) {
 				// Check trusted CA
 				assert.NotNil(sdsSecret.GetValidationContext().GetTrustedCa().GetInlineBytes())
 
+			case envoy.RootCertTypeForIngressMTLS:
+				assert.Equal([]byte("ca-bundle"), sdsSecret.GetValidationContext().GetTrustedCa().GetInlineBytes())
+
 			case envoy.ServiceCertType:
 				assert.NotNil(sdsSecret.GetTlsCertificate().GetCertificateChain().GetInlineBytes())
 				assert.NotNil(sdsSecret.GetTlsCertificate().GetPrivateKey().GetInlineBytes())
@@ -474,8 +520,8 @@ func TestGetSubjectAltNamesFromSvcAccount(t *testing.T) {
 	testCases := []testCase{
 		{
 			svcAccounts: []service.K8sServiceAccount{
-				{Name: "sa-1", Namespace: "ns-1"},
 				{Name: "sa-2", Namespace: "ns-2"},
+				{Name: "sa-1", Namespace: "ns-1"},
 			},
 			expectedSANMatchers: []*xds_matcher.StringMatcher{
 				{
@@ -495,7 +541,7 @@ func TestGetSubjectAltNamesFromSvcAccount(t *testing.T) {
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d", i), func(t *testing.T) {
 			actual := getSubjectAltNamesFromSvcAccount(tc.svcAccounts)
-			assert.ElementsMatch(actual, tc.expectedSANMatchers)
+			assert.Equal(tc.expectedSANMatchers, actual)
 		})
 	}
 }
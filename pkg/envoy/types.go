@@ -3,7 +3,10 @@
 package envoy
 
 import (
+	"time"
+
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 var (
@@ -69,7 +72,115 @@ const (
 
 	accessLogPath = "/dev/stdout"
 
+	// IngressBackendHeader is the header added to the requests received from ingress that a proxy forwards to another
+	// backend of a TrafficSplit. The proxy of that backend serves the requests with this header itself, so that the
+	// requests are not split again. The header is removed from the requests received from ingress before they are routed.
+	IngressBackendHeader = "x-osm-ingress-backend"
+
 	// localClusterSuffix is the tag to append to the local cluster name corresponding to a service cluster.
 	// The local cluster refers to the cluster corresponding to the service the proxy is fronting, accessible over localhost by the proxy.
 	localClusterSuffix = "-local"
+
+	// portClusterNameFormat is the format of the name of the cluster for a container port of the pod of a proxy
+	portClusterNameFormat = "port-%d"
+
+	// statsHeaderPrefix is the prefix of the headers carrying the metadata of the workload of a proxy to its stats extension
+	statsHeaderPrefix = "osm-stats-"
+
+	// statsPeerHeaderPrefix is the prefix of the headers carrying the metadata of the workload of a proxy to the stats
+	// extension of the proxies receiving its requests
+	statsPeerHeaderPrefix = "osm-stats-peer-"
+
+	// RBACDenialAccessLogName is the name of the access log reporting the requests denied by RBAC policies to the controller
+	RBACDenialAccessLogName = "rbac-denials"
+
+	// RBACShadowDenialAccessLogName is the name of the access log reporting the requests that would be denied by the RBAC
+	// policies of SMI TrafficTargets in shadow mode to the controller
+	RBACShadowDenialAccessLogName = "rbac-shadow-denials"
+
+	// PolicyRecorderAccessLogName is the name of the access log reporting the requests observed in permissive traffic
+	// policy mode to the policy recorder of the controller
+	PolicyRecorderAccessLogName = "policy-recorder"
+
+	// EgressMetricsAccessLogName is the name of the access log reporting the egress traffic of proxies to the controller,
+	// which aggregates it in metrics per external host
+	EgressMetricsAccessLogName = "egress-metrics"
+
+	// EgressAuditAccessLogName is the name of the access log reporting the egress traffic not allowed by the Egress
+	// policies of proxies in egress audit mode to the controller
+	EgressAuditAccessLogName = "egress-audit"
 )
+
+// RBACDenial is a record of the requests from a source identity to a route of a destination identity that were
+// denied by the RBAC policies of the destination's proxy.
+type RBACDenial struct {
+	// SourceIdentity is the identity of the client denied, as presented in its certificate
+	SourceIdentity string `json:"source_identity"`
+
+	// DestinationIdentity is the identity of the proxy that denied the requests
+	DestinationIdentity string `json:"destination_identity"`
+
+	// Route is the name of the route the requests were denied on
+	Route string `json:"route"`
+
+	// Count is the number of requests denied
+	Count uint64 `json:"count"`
+
+	// LastDenied is the time the last request was denied
+	LastDenied time.Time `json:"last_denied"`
+
+	// Shadow is true when the requests would be denied by the RBAC policies of SMI TrafficTargets in shadow mode,
+	// but were not denied
+	Shadow bool `json:"shadow,omitempty"`
+}
+
+// ObservedRequest is a record of the requests from a source identity to a path of a destination identity that were
+// observed by the proxy of the destination in permissive traffic policy mode.
+type ObservedRequest struct {
+	// SourceIdentity is the identity of the client, as presented in its certificate
+	SourceIdentity string `json:"source_identity"`
+
+	// DestinationIdentity is the identity of the proxy that observed the requests
+	DestinationIdentity string `json:"destination_identity"`
+
+	// Method is the HTTP method of the requests
+	Method string `json:"method"`
+
+	// Path is the HTTP path of the requests, without the query string
+	Path string `json:"path"`
+
+	// Count is the number of requests observed
+	Count uint64 `json:"count"`
+
+	// LastObserved is the time the last request was observed
+	LastObserved time.Time `json:"last_observed"`
+}
+
+// ConfigRejection is a record of an xDS response rejected by a proxy.
+type ConfigRejection struct {
+	// Proxy is the proxy that rejected the response
+	Proxy *Proxy
+
+	// TypeURL is the type URL of the rejected response
+	TypeURL string
+
+	// Message is the error reported by the proxy
+	Message string
+}
+
+// ConfigGeneration is a generation of the configuration of the mesh. A new generation is stamped on each set of
+// coalesced configuration changes broadcast to the proxies.
+type ConfigGeneration struct {
+	// ID is the sequence number of the generation, incremented with each generation
+	ID uint64 `json:"id"`
+
+	// CreatedAt is the time the generation was broadcast to the proxies
+	CreatedAt time.Time `json:"created_at"`
+
+	// Changes describes the configuration changes included in the generation
+	Changes []string `json:"changes"`
+
+	// Services are the services whose proxies are affected by the changes included in the generation, or nil if the
+	// changes may affect any proxy. The generation is not sent to the proxies that do not front any of these services.
+	Services []service.MeshService `json:"services,omitempty"`
+}
@@ -9,6 +9,7 @@ import (
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	structpb "github.com/golang/protobuf/ptypes/struct"
@@ -48,6 +49,18 @@ const (
 
 	// RootCertTypeForHTTPS is the prefix for the HTTPS root certificate resource name. Example: "root-cert-https:webservice"
 	RootCertTypeForHTTPS SDSCertType = "root-cert-https"
+
+	// RootCertTypeForIngressMTLS is the prefix for the resource name of the CA bundle validating the client certificates of
+	// ingress sources, stored in the secret referenced by an IngressBackend. Example: "root-cert-for-ingress-mtls:namespace/secret"
+	RootCertTypeForIngressMTLS SDSCertType = "root-cert-for-ingress-mtls"
+
+	// RootCertTypeForEgressTLS is the prefix for the resource name of the CA bundle validating the certificates of the
+	// external hosts TLS is originated to, stored in the secret referenced by an Egress. Example: "root-cert-for-egress-tls:namespace/secret"
+	RootCertTypeForEgressTLS SDSCertType = "root-cert-for-egress-tls"
+
+	// EgressClientCertType is the prefix for the resource name of the client certificate presented to the external
+	// hosts TLS is originated to, stored in the secret referenced by an Egress. Example: "egress-client-cert:namespace/secret"
+	EgressClientCertType SDSCertType = "egress-client-cert"
 )
 
 const (
@@ -57,8 +70,45 @@ const (
 	// TransportProtocolTLS is the TLS transport protocol used in Envoy configurations
 	TransportProtocolTLS = "tls"
 
+	// TransportProtocolRawBuffer is the transport protocol detected by Envoy for plaintext connections
+	TransportProtocolRawBuffer = "raw_buffer"
+
+	// ConnectUpgradeType is the upgrade type of the HTTP CONNECT requests tunneling TCP connections through Envoy
+	ConnectUpgradeType = "CONNECT"
+
 	// OutboundPassthroughCluster is the outbound passthrough cluster name
 	OutboundPassthroughCluster = "passthrough-outbound"
+
+	// NodeProxyInboundCluster is the cluster used by a per-node proxy to forward inbound traffic to its original destination
+	NodeProxyInboundCluster = "node-proxy-inbound"
+
+	// nodeProxyOutboundClusterPrefix is the prefix of the clusters used by a per-node proxy to originate mTLS on behalf of its pods
+	nodeProxyOutboundClusterPrefix = "node-proxy-outbound"
+
+	// EgressGatewayOriginalDestinationCluster is the cluster used by the egress gateway to forward the egress traffic
+	// that is not destined to an external host to its original destination
+	EgressGatewayOriginalDestinationCluster = "egress-gateway-original-destination"
+
+	// egressHostClusterPrefix is the prefix of the clusters of the external hosts allowed by Egress policies
+	egressHostClusterPrefix = "egress"
+
+	// egressIPRangesClusterPrefix is the prefix of the clusters of the external IP ranges allowed by Egress policies
+	egressIPRangesClusterPrefix = "egress-ip-ranges"
+
+	// egressWildcardHostsClusterPrefix is the prefix of the clusters of the external wildcard domains allowed by Egress
+	// policies
+	egressWildcardHostsClusterPrefix = "egress-wildcard-hosts"
+
+	// WildcardHostPrefix is the prefix of the wildcard domains matching any subdomain of a domain, such as *.example.com
+	WildcardHostPrefix = "*."
+
+	// SystemCABundlePath is the path of the system CA bundle in the sidecar proxy image, validating the certificates of
+	// external hosts when no CA bundle is configured
+	SystemCABundlePath = "/etc/ssl/certs/ca-certificates.crt"
+
+	// FailoverEndpointMetadataKey is the transport socket match metadata key of the backup endpoints outside of the mesh
+	// that the clusters of upstream services fail over to
+	FailoverEndpointMetadataKey = "osm-failover"
 )
 
 // Defines valid cert types
@@ -67,6 +117,9 @@ var validCertTypes = map[SDSCertType]interface{}{
 	RootCertTypeForMTLSOutbound: nil,
 	RootCertTypeForMTLSInbound:  nil,
 	RootCertTypeForHTTPS:        nil,
+	RootCertTypeForIngressMTLS:  nil,
+	RootCertTypeForEgressTLS:    nil,
+	EgressClientCertType:        nil,
 }
 
 // ALPNInMesh indicates that the proxy is connecting to an in-mesh destination.
@@ -129,6 +182,26 @@ func GetAddress(address string, port uint32) *xds_core.Address {
 	}
 }
 
+// GetListenerAddress returns the wildcard address for a listener on the given port. Listeners on IPv6 pods bind to the
+// IPv6 wildcard address and also accept IPv4 connections, so that both address families are handled on dual-stack pods.
+func GetListenerAddress(port uint32, ipv6 bool) *xds_core.Address {
+	if !ipv6 {
+		return GetAddress(constants.WildcardIPAddr, port)
+	}
+
+	address := GetAddress(constants.WildcardIPv6Addr, port)
+	address.GetSocketAddress().Ipv4Compat = true
+	return address
+}
+
+// GetLocalhostIPAddress returns the loopback address matching the IP family of the pod
+func GetLocalhostIPAddress(ipv6 bool) string {
+	if ipv6 {
+		return constants.LocalhostIPv6Address
+	}
+	return constants.LocalhostIPAddress
+}
+
 // GetTLSParams creates Envoy TlsParameters struct.
 func GetTLSParams() *xds_auth.TlsParameters {
 	return &xds_auth.TlsParameters{
@@ -139,7 +212,13 @@ func GetTLSParams() *xds_auth.TlsParameters {
 
 // GetAccessLog creates an Envoy AccessLog struct.
 func GetAccessLog() []*xds_accesslog_filter.AccessLog {
-	accessLog, err := ptypes.MarshalAny(getFileAccessLog())
+	return GetAccessLogWithFields(nil)
+}
+
+// GetAccessLogWithFields creates an Envoy AccessLog struct whose log lines include the given fields, keyed by name
+// and formatted with Envoy command operators, in addition to the default fields.
+func GetAccessLogWithFields(fields map[string]string) []*xds_accesslog_filter.AccessLog {
+	accessLog, err := ptypes.MarshalAny(getFileAccessLog(fields))
 	if err != nil {
 		log.Error().Err(err).Msg("Error marshalling AccessLog object")
 		return nil
@@ -152,7 +231,7 @@ func GetAccessLog() []*xds_accesslog_filter.AccessLog {
 	}
 }
 
-func getFileAccessLog() *xds_accesslog.FileAccessLog {
+func getFileAccessLog(fields map[string]string) *xds_accesslog.FileAccessLog {
 	accessLogger := &xds_accesslog.FileAccessLog{
 		Path: accessLogPath,
 		AccessLogFormat: &xds_accesslog.FileAccessLog_LogFormat{
@@ -185,6 +264,12 @@ func getFileAccessLog() *xds_accesslog.FileAccessLog {
 			},
 		},
 	}
+
+	jsonFormat := accessLogger.GetLogFormat().GetJsonFormat()
+	for name, format := range fields {
+		jsonFormat.Fields[name] = pbStringValue(format)
+	}
+
 	return accessLogger
 }
 
@@ -250,6 +335,31 @@ func GetDownstreamTLSContext(upstreamIdentity service.K8sServiceAccount, mTLS bo
 	return tlsConfig
 }
 
+// GetIngressDownstreamTLSContext creates a downstream Envoy TLS Context to be configured on the upstream with the given
+// identity for the traffic received from ingress. Client certificates are required when requireClientCert is set, and
+// are validated with the CA bundle stored in the given secret, or with the mesh's root certificate when caBundleSecret
+// is empty.
+func GetIngressDownstreamTLSContext(upstreamIdentity service.K8sServiceAccount, requireClientCert bool, caBundleSecret string) *xds_auth.DownstreamTlsContext {
+	tlsConfig := GetDownstreamTLSContext(upstreamIdentity, false /* TLS */)
+	tlsConfig.RequireClientCertificate = &wrappers.BoolValue{Value: requireClientCert}
+
+	if caBundleSecret != "" {
+		// The SAN of the client certificates is not matched against the identities of the downstreams, ingress sources
+		// are authorized by the RBAC filter of the IngressBackend instead
+		tlsConfig.CommonTlsContext.ValidationContextType = &xds_auth.CommonTlsContext_ValidationContextSdsSecretConfig{
+			ValidationContextSdsSecretConfig: &xds_auth.SdsSecretConfig{
+				Name: SDSCert{
+					Name:     caBundleSecret,
+					CertType: RootCertTypeForIngressMTLS,
+				}.String(),
+				SdsConfig: GetADSConfigSource(),
+			},
+		}
+	}
+
+	return tlsConfig
+}
+
 // GetUpstreamTLSContext creates an upstream Envoy TLS Context for the given downstream identity and upstream service pair
 func GetUpstreamTLSContext(downstreamIdentity service.K8sServiceAccount, upstreamSvc service.MeshService) *xds_auth.UpstreamTlsContext {
 	downstreamSDSCert := SDSCert{
@@ -275,6 +385,99 @@ func GetUpstreamTLSContext(downstreamIdentity service.K8sServiceAccount, upstrea
 	return tlsConfig
 }
 
+// GetNodeProxyUpstreamTLSContext creates an upstream Envoy TLS Context used by a per-node proxy to originate mTLS
+// on behalf of pods with the given identity. The upstream is only known by its original destination address, so the
+// upstream certificate is validated against the mesh root certificate only and authorization is left to the upstream.
+func GetNodeProxyUpstreamTLSContext(downstreamIdentity service.K8sServiceAccount) *xds_auth.UpstreamTlsContext {
+	downstreamSDSCert := SDSCert{
+		Name:     downstreamIdentity.String(),
+		CertType: ServiceCertType,
+	}
+	upstreamPeerValidationSDSCert := SDSCert{
+		Name:     downstreamIdentity.String(),
+		CertType: RootCertTypeForMTLSOutbound,
+	}
+	commonTLSContext := getCommonTLSContext(downstreamSDSCert, upstreamPeerValidationSDSCert)
+	commonTLSContext.AlpnProtocols = ALPNInMesh
+
+	return &xds_auth.UpstreamTlsContext{
+		CommonTlsContext: commonTLSContext,
+	}
+}
+
+// GetEgressGatewayUpstreamTLSContext creates an upstream Envoy TLS Context used by a sidecar proxy with the given identity
+// to originate mTLS to the egress gateway fronted by the given service. The given server name, when set, is the external
+// host the traffic is destined to, which the egress gateway matches to forward the traffic to the host.
+func GetEgressGatewayUpstreamTLSContext(downstreamIdentity service.K8sServiceAccount, egressGatewaySvc service.MeshService, serverName string) *xds_auth.UpstreamTlsContext {
+	downstreamSDSCert := SDSCert{
+		Name:     downstreamIdentity.String(),
+		CertType: ServiceCertType,
+	}
+	upstreamPeerValidationSDSCert := SDSCert{
+		Name:     egressGatewaySvc.String(),
+		CertType: RootCertTypeForMTLSOutbound,
+	}
+	commonTLSContext := getCommonTLSContext(downstreamSDSCert, upstreamPeerValidationSDSCert)
+	commonTLSContext.AlpnProtocols = ALPNInMesh
+
+	return &xds_auth.UpstreamTlsContext{
+		CommonTlsContext: commonTLSContext,
+		Sni:              serverName,
+	}
+}
+
+// GetEgressUpstreamTLSContext creates an upstream Envoy TLS Context used to originate TLS to the given external host.
+// The certificate of the host must be issued to the host, and is validated with the CA bundle stored in the given
+// secret, or with the system CA bundle of the proxy when caBundleSecret is empty. The given client certificate, when
+// set, is presented to the host.
+func GetEgressUpstreamTLSContext(host string, caBundleSecret string, clientCert *SDSCert) *xds_auth.UpstreamTlsContext {
+	validationContext := &xds_auth.CertificateValidationContext{
+		MatchSubjectAltNames: []*xds_matcher.StringMatcher{{
+			MatchPattern: &xds_matcher.StringMatcher_Exact{
+				Exact: host,
+			},
+		}},
+	}
+
+	commonTLSContext := &xds_auth.CommonTlsContext{
+		TlsParams: GetTLSParams(),
+	}
+	if caBundleSecret == "" {
+		validationContext.TrustedCa = &xds_core.DataSource{
+			Specifier: &xds_core.DataSource_Filename{
+				Filename: SystemCABundlePath,
+			},
+		}
+		commonTLSContext.ValidationContextType = &xds_auth.CommonTlsContext_ValidationContext{
+			ValidationContext: validationContext,
+		}
+	} else {
+		commonTLSContext.ValidationContextType = &xds_auth.CommonTlsContext_CombinedValidationContext{
+			CombinedValidationContext: &xds_auth.CommonTlsContext_CombinedCertificateValidationContext{
+				DefaultValidationContext: validationContext,
+				ValidationContextSdsSecretConfig: &xds_auth.SdsSecretConfig{
+					Name: SDSCert{
+						Name:     caBundleSecret,
+						CertType: RootCertTypeForEgressTLS,
+					}.String(),
+					SdsConfig: GetADSConfigSource(),
+				},
+			},
+		}
+	}
+	if clientCert != nil {
+		commonTLSContext.TlsCertificateSdsSecretConfigs = []*xds_auth.SdsSecretConfig{{
+			Name:      clientCert.String(),
+			SdsConfig: GetADSConfigSource(),
+		}}
+	}
+
+	return &xds_auth.UpstreamTlsContext{
+		CommonTlsContext: commonTLSContext,
+		Sni:              host,
+	}
+}
+
 // GetADSConfigSource creates an Envoy ConfigSource struct.
 func GetADSConfigSource() *xds_core.ConfigSource {
 	return &xds_core.ConfigSource{
@@ -332,8 +535,104 @@ func GetLocalClusterNameForService(proxyService service.MeshService) string {
 	return GetLocalClusterNameForServiceCluster(proxyService.String())
 }
 
+// GetLocalClusterNameForPort returns the name of the local cluster for the given container port.
+// The local cluster refers to the cluster corresponding to an application port on a pod that is not fronted by any service.
+func GetLocalClusterNameForPort(port uint32) string {
+	return GetLocalClusterNameForServiceCluster(GetClusterNameForPort(port))
+}
+
+// GetClusterNameForPort returns the name of the cluster for the given container port of the pod of a proxy, routed to
+// by the inbound routes of the proxy through the local cluster for the port.
+func GetClusterNameForPort(port uint32) string {
+	return fmt.Sprintf(portClusterNameFormat, port)
+}
+
+// IsClusterNameForPort returns whether the given cluster name is the name of the cluster for a container port
+func IsClusterNameForPort(clusterName string) bool {
+	var port uint32
+	if _, err := fmt.Sscanf(clusterName, portClusterNameFormat, &port); err != nil {
+		return false
+	}
+	return GetClusterNameForPort(port) == clusterName
+}
+
 // GetLocalClusterNameForServiceCluster returns the name of the local cluster for the given service cluster.
 // The local cluster refers to the cluster corresponding to the service the proxy is fronting, accessible over localhost by the proxy.
+// The name is bounded with BoundedName, as the suffix can take the name of a service cluster past MaxNameLength.
 func GetLocalClusterNameForServiceCluster(clusterName string) string {
-	return fmt.Sprintf("%s%s", clusterName, localClusterSuffix)
+	return BoundedName(fmt.Sprintf("%s%s", clusterName, localClusterSuffix))
+}
+
+// GetNodeProxyOutboundClusterName returns the name of the cluster used by a per-node proxy to originate mTLS
+// on behalf of pods with the given identity.
+func GetNodeProxyOutboundClusterName(downstreamIdentity service.K8sServiceAccount) string {
+	return BoundedName(fmt.Sprintf("%s:%s", nodeProxyOutboundClusterPrefix, downstreamIdentity))
+}
+
+// GetEgressHostClusterName returns the name of the cluster of the given external host and port, allowed by an Egress policy.
+// Wildcard domains cannot be resolved with DNS, so the wildcard domains allowed on a port share a cluster. The name is
+// bounded with BoundedName, as external hosts can be up to 253 characters long.
+func GetEgressHostClusterName(host string, port uint32) string {
+	if IsWildcardHost(host) {
+		return fmt.Sprintf("%s:%d", egressWildcardHostsClusterPrefix, port)
+	}
+	return BoundedName(fmt.Sprintf("%s:%s:%d", egressHostClusterPrefix, host, port))
+}
+
+// GetEgressHostClusterStatName returns the name the stats of the cluster of the given external host and port are
+// emitted under, Ex. egress.httpbin.org_443, from which the tags of the stats extract the host and port
+func GetEgressHostClusterStatName(host string, port uint32) string {
+	return fmt.Sprintf("%s.%s_%d", egressHostClusterPrefix, host, port)
+}
+
+// GetEgressHostStatsTagRegex returns the regex extracting the external host from the stats of the cluster of the host.
+// The host and the port are removed from the name of the stats, leaving the cluster name tag set to egress.
+func GetEgressHostStatsTagRegex() string {
+	return fmt.Sprintf("^cluster[.]%s[.]((.+?)_)[0-9]+[.]", egressHostClusterPrefix)
+}
+
+// GetEgressPortStatsTagRegex returns the regex extracting the port from the stats of the cluster of an external host
+func GetEgressPortStatsTagRegex() string {
+	return fmt.Sprintf("^cluster[.]%s[.].+?_(([0-9]+)[.])", egressHostClusterPrefix)
+}
+
+// GetEgressHostFromClusterName returns the external host of the cluster with the given name, and false if the cluster
+// is not the cluster of an external host
+func GetEgressHostFromClusterName(clusterName string) (string, bool) {
+	if name, ok := LookupBoundedName(clusterName); ok {
+		clusterName = name
+	}
+	hostPort := strings.TrimPrefix(clusterName, egressHostClusterPrefix+":")
+	if hostPort == clusterName {
+		return "", false
+	}
+	sep := strings.LastIndex(hostPort, ":")
+	if sep <= 0 {
+		return "", false
+	}
+	return hostPort[:sep], true
+}
+
+// IsWildcardHost returns true if the given host is a wildcard domain, such as *.example.com
+func IsWildcardHost(host string) bool {
+	return strings.HasPrefix(host, WildcardHostPrefix)
+}
+
+// GetEgressIPRangesClusterName returns the name of the cluster of the external IP ranges allowed by Egress policies on
+// the given port
+func GetEgressIPRangesClusterName(port uint32) string {
+	return fmt.Sprintf("%s:%d", egressIPRangesClusterPrefix, port)
+}
+
+// GetResourceName returns the name of the given xDS resource, which identifies the source of its config, such as the
+// service of a cluster or the port of a listener
+func GetResourceName(resource interface{}) string {
+	switch r := resource.(type) {
+	case interface{ GetName() string }:
+		return r.GetName()
+	case interface{ GetClusterName() string }:
+		return r.GetClusterName()
+	default:
+		return ""
+	}
 }
@@ -1,10 +1,16 @@
 package envoy
 
 import (
+	"regexp"
 	"testing"
 
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	tassert "github.com/stretchr/testify/assert"
@@ -12,6 +18,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
@@ -23,6 +30,134 @@ func TestGetLocalClusterNameForService(t *testing.T) {
 	assert.Equal(actual, "default/bookbuyer-local")
 }
 
+func TestGetLocalClusterNameForPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := GetLocalClusterNameForPort(8080)
+	assert.Equal("port-8080-local", actual)
+}
+
+func TestIsClusterNameForPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("port-8080", GetClusterNameForPort(8080))
+	assert.True(IsClusterNameForPort(GetClusterNameForPort(8080)))
+	assert.False(IsClusterNameForPort(GetLocalClusterNameForPort(8080)))
+	assert.False(IsClusterNameForPort(tests.BookbuyerService.String()))
+}
+
+func TestGetNodeProxyOutboundClusterName(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := GetNodeProxyOutboundClusterName(tests.BookbuyerServiceAccount)
+	assert.Equal("node-proxy-outbound:default/bookbuyer", actual)
+}
+
+func TestGetEgressClusterNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("egress:httpbin.org:443", GetEgressHostClusterName("httpbin.org", 443))
+	assert.Equal("egress-ip-ranges:5432", GetEgressIPRangesClusterName(5432))
+
+	// Wildcard domains on the same port share a cluster
+	assert.Equal("egress-wildcard-hosts:443", GetEgressHostClusterName("*.github.com", 443))
+	assert.Equal("egress-wildcard-hosts:443", GetEgressHostClusterName("*.githubusercontent.com", 443))
+	assert.True(IsWildcardHost("*.github.com"))
+	assert.False(IsWildcardHost("github.com"))
+}
+
+func TestGetEgressHostFromClusterName(t *testing.T) {
+	assert := tassert.New(t)
+
+	host, ok := GetEgressHostFromClusterName("egress:httpbin.org:443")
+	assert.True(ok)
+	assert.Equal("httpbin.org", host)
+
+	// The clusters of wildcard domains and IP ranges are not the cluster of a single host
+	for _, clusterName := range []string{"egress-wildcard-hosts:443", "egress-ip-ranges:5432", "bookstore/bookstore-v1", "egress:"} {
+		_, ok = GetEgressHostFromClusterName(clusterName)
+		assert.False(ok, clusterName)
+	}
+}
+
+func TestGetEgressHostStatsTagRegex(t *testing.T) {
+	assert := tassert.New(t)
+
+	statName := "cluster." + GetEgressHostClusterStatName("api.github.com", 443) + ".upstream_rq_2xx"
+	assert.Equal("cluster.egress.api.github.com_443.upstream_rq_2xx", statName)
+
+	hostMatch := regexp.MustCompile(GetEgressHostStatsTagRegex()).FindStringSubmatch(statName)
+	assert.Equal("api.github.com", hostMatch[2])
+	portMatch := regexp.MustCompile(GetEgressPortStatsTagRegex()).FindStringSubmatch(statName)
+	assert.Equal("443", portMatch[2])
+
+	// The stats of other clusters are not tagged
+	assert.Nil(regexp.MustCompile(GetEgressHostStatsTagRegex()).FindStringSubmatch("cluster.bookstore/bookstore-v1.upstream_rq_2xx"))
+}
+
+func TestGetNodeProxyUpstreamTLSContext(t *testing.T) {
+	assert := tassert.New(t)
+
+	tlsContext := GetNodeProxyUpstreamTLSContext(tests.BookbuyerServiceAccount)
+	assert.Empty(tlsContext.Sni)
+	assert.Equal(ALPNInMesh, tlsContext.CommonTlsContext.AlpnProtocols)
+	assert.Equal("service-cert:default/bookbuyer", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+	assert.Equal("root-cert-for-mtls-outbound:default/bookbuyer", tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name)
+}
+
+func TestGetEgressGatewayUpstreamTLSContext(t *testing.T) {
+	assert := tassert.New(t)
+
+	egressGatewaySvc := service.MeshService{Name: "osm-egress-gateway", Namespace: "osm-system"}
+	tlsContext := GetEgressGatewayUpstreamTLSContext(tests.BookbuyerServiceAccount, egressGatewaySvc, "httpbin.org")
+	assert.Equal("httpbin.org", tlsContext.Sni)
+	assert.Equal(ALPNInMesh, tlsContext.CommonTlsContext.AlpnProtocols)
+	assert.Equal("service-cert:default/bookbuyer", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+	assert.Equal("root-cert-for-mtls-outbound:osm-system/osm-egress-gateway", tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name)
+
+	// Traffic to its original destination is not matched by server name
+	tlsContext = GetEgressGatewayUpstreamTLSContext(tests.BookbuyerServiceAccount, egressGatewaySvc, "")
+	assert.Empty(tlsContext.Sni)
+}
+
+func TestGetEgressUpstreamTLSContext(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Without a CA bundle, the external host is validated against the CA bundle of the system
+	tlsContext := GetEgressUpstreamTLSContext("httpbin.org", "", nil)
+	assert.Equal("httpbin.org", tlsContext.Sni)
+	assert.Empty(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs)
+	validationContext := tlsContext.CommonTlsContext.GetValidationContext()
+	assert.Equal(SystemCABundlePath, validationContext.TrustedCa.GetFilename())
+	assert.Equal("httpbin.org", validationContext.MatchSubjectAltNames[0].GetExact())
+
+	// The CA bundle and the client certificate are served by SDS
+	tlsContext = GetEgressUpstreamTLSContext("httpbin.org", "curl/httpbin-ca", &SDSCert{Name: "curl/httpbin-client", CertType: EgressClientCertType})
+	assert.Equal("egress-client-cert:curl/httpbin-client", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+	combinedValidationContext := tlsContext.CommonTlsContext.GetCombinedValidationContext()
+	assert.Equal("root-cert-for-egress-tls:curl/httpbin-ca", combinedValidationContext.ValidationContextSdsSecretConfig.Name)
+	assert.Equal("httpbin.org", combinedValidationContext.DefaultValidationContext.MatchSubjectAltNames[0].GetExact())
+}
+
+func TestGetListenerAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := GetListenerAddress(15003, false)
+	assert.Equal(GetAddress(constants.WildcardIPAddr, 15003), actual)
+
+	actual = GetListenerAddress(15003, true)
+	assert.Equal(constants.WildcardIPv6Addr, actual.GetSocketAddress().Address)
+	assert.Equal(uint32(15003), actual.GetSocketAddress().GetPortValue())
+	assert.True(actual.GetSocketAddress().Ipv4Compat)
+}
+
+func TestGetLocalhostIPAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(constants.LocalhostIPAddress, GetLocalhostIPAddress(false))
+	assert.Equal(constants.LocalhostIPv6Address, GetLocalhostIPAddress(true))
+}
+
 func TestGetAccessLog(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -30,6 +165,21 @@ func TestGetAccessLog(t *testing.T) {
 	assert.NotNil(res)
 }
 
+func TestGetAccessLogWithFields(t *testing.T) {
+	assert := tassert.New(t)
+
+	res := GetAccessLogWithFields(map[string]string{"source_identity": "%DOWNSTREAM_PEER_SUBJECT%"})
+	assert.Len(res, 1)
+
+	fileAccessLog := &xds_accesslog.FileAccessLog{}
+	err := ptypes.UnmarshalAny(res[0].GetTypedConfig(), fileAccessLog)
+	assert.Nil(err)
+	fields := fileAccessLog.GetLogFormat().GetJsonFormat().GetFields()
+	assert.Equal("%DOWNSTREAM_PEER_SUBJECT%", fields["source_identity"].GetStringValue())
+	// The default fields are preserved
+	assert.Equal("%UPSTREAM_CLUSTER%", fields["upstream_cluster"].GetStringValue())
+}
+
 var _ = Describe("Test Envoy tools", func() {
 	Context("Test GetLocalClusterNameForServiceCluster", func() {
 		It("", func() {
@@ -100,6 +250,13 @@ var _ = Describe("Test Envoy tools", func() {
 			Expect(actual.Name).To(Equal("namespace-test/blahBlahBlahCert"))
 		})
 
+		It("returns root cert for ingress mTLS", func() {
+			actual, err := UnmarshalSDSCert("root-cert-for-ingress-mtls:namespace-test/blahBlahBlahSecret")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual.CertType).To(Equal(RootCertTypeForIngressMTLS))
+			Expect(actual.Name).To(Equal("namespace-test/blahBlahBlahSecret"))
+		})
+
 		It("returns an error (invalid formatting)", func() {
 			_, err := UnmarshalSDSCert("blahBlahBlahCert")
 			Expect(err).To(HaveOccurred())
@@ -191,6 +348,28 @@ var _ = Describe("Test Envoy tools", func() {
 		})
 	})
 
+	Context("Test GetIngressDownstreamTLSContext()", func() {
+		It("should validate client certificates with the mesh's root certificate without a CA bundle secret", func() {
+			tlsContext := GetIngressDownstreamTLSContext(tests.BookstoreServiceAccount, true, "")
+			Expect(tlsContext.RequireClientCertificate).To(Equal(&wrappers.BoolValue{Value: true}))
+			Expect(tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name).To(Equal(SDSCert{
+				Name:     tests.BookstoreServiceAccount.String(),
+				CertType: RootCertTypeForHTTPS,
+			}.String()))
+		})
+
+		It("should validate client certificates with the CA bundle secret", func() {
+			tlsContext := GetIngressDownstreamTLSContext(tests.BookstoreServiceAccount, true, "ingress-ns/ingress-ca")
+			Expect(tlsContext.RequireClientCertificate).To(Equal(&wrappers.BoolValue{Value: true}))
+			Expect(tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name).To(Equal("root-cert-for-ingress-mtls:ingress-ns/ingress-ca"))
+		})
+
+		It("should not require client certificates", func() {
+			tlsContext := GetIngressDownstreamTLSContext(tests.BookstoreServiceAccount, false, "")
+			Expect(tlsContext.RequireClientCertificate).To(Equal(&wrappers.BoolValue{Value: false}))
+		})
+	})
+
 	Context("Test GetUpstreamTLSContext()", func() {
 		It("should return TLS context", func() {
 			sni := "bookstore-v1.default.svc.cluster.local"
@@ -400,3 +579,11 @@ var _ = Describe("Test Envoy tools", func() {
 		})
 	})
 })
+
+func TestGetResourceName(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("bookstore/bookstore", GetResourceName(&xds_cluster.Cluster{Name: "bookstore/bookstore"}))
+	assert.Equal("bookstore/bookstore", GetResourceName(&xds_endpoint.ClusterLoadAssignment{ClusterName: "bookstore/bookstore"}))
+	assert.Equal("", GetResourceName(&xds_discovery.DiscoveryRequest{}))
+}
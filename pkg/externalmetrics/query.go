@@ -0,0 +1,137 @@
+package externalmetrics
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openservicemesh/osm/pkg/promquery"
+)
+
+const (
+	// requestCountMetric is the metric of the sidecar proxies counting the requests completed by their clusters, per
+	// class of response code
+	requestCountMetric = "envoy_cluster_upstream_rq_xx"
+
+	// activeRequestsMetric is the metric of the sidecar proxies gauging the requests being processed by their clusters
+	activeRequestsMetric = "envoy_cluster_upstream_rq_active"
+
+	// clusterNameLabel is the label of the metrics of the sidecar proxies naming their cluster
+	clusterNameLabel = "envoy_cluster_name"
+
+	// localClusterSuffix suffixes the names of the clusters of the sidecar proxies forwarding the requests received by
+	// a service to its workload, named <namespace>/<service>-local
+	localClusterSuffix = "-local"
+)
+
+// externalMetric is a metric of the External Metrics API, and the PromQL expression it is queried with. The metrics of
+// a service are recorded by the local clusters of the sidecar proxies of its pods, so that the requests received by the
+// service are counted once, whether they are sent by a client in the mesh or by an ingress.
+type externalMetric struct {
+	// windowed is true if the metric is computed over the window
+	windowed bool
+
+	// expr returns the expression of the metric over the given window for the clusters selected by the given label
+	// matcher, aggregated by cluster
+	expr func(matcher, window string) string
+
+	// quantity returns the given value of the metric as a quantity
+	quantity func(value float64) *resource.Quantity
+}
+
+// externalMetrics are the metrics served for the services of the mesh, keyed by name
+var externalMetrics = map[string]externalMetric{
+	RequestsPerSecondMetric: {
+		windowed: true,
+		expr: func(matcher, window string) string {
+			return fmt.Sprintf(`sum by (%s) (rate(%s{%s}[%s]))`, clusterNameLabel, requestCountMetric, matcher, window)
+		},
+		quantity: func(value float64) *resource.Quantity {
+			return resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+		},
+	},
+	ConcurrencyMetric: {
+		expr: func(matcher, _ string) string {
+			return fmt.Sprintf(`sum by (%s) (%s{%s})`, clusterNameLabel, activeRequestsMetric, matcher)
+		},
+		quantity: func(value float64) *resource.Quantity {
+			return resource.NewQuantity(int64(math.Round(value)), resource.DecimalSI)
+		},
+	},
+}
+
+// localClustersMatcher returns the label matcher selecting the local clusters of the services of the given namespace
+func localClustersMatcher(namespace string) string {
+	return fmt.Sprintf("%s=~%s", clusterNameLabel, strconv.Quote(regexp.QuoteMeta(namespace+"/")+".+"+regexp.QuoteMeta(localClusterSuffix)))
+}
+
+// getServiceName returns the name of the service of the given local cluster of the given namespace
+func getServiceName(clusterName, namespace string) (string, bool) {
+	if !strings.HasPrefix(clusterName, namespace+"/") || !strings.HasSuffix(clusterName, localClusterSuffix) {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(clusterName, namespace+"/"), localClusterSuffix)
+	return name, name != ""
+}
+
+// getMetricValues returns the values of the given metric for the services of the given namespace whose labels match the
+// given selector, sorted by service. When the selector names a service, its value is returned even if it did not
+// receive any request, so that the workloads of idle services can be scaled down.
+func (s *Server) getMetricValues(metricsURL string, metric externalMetric, name, namespace string, selector labels.Selector) (*ExternalMetricValueList, error) {
+	window := fmt.Sprintf("%ds", int64(s.window.Seconds()))
+	samples, err := promquery.Query(s.client, metricsURL, metric.expr(localClustersMatcher(namespace), window))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range samples {
+		svc, ok := getServiceName(sample.Labels[clusterNameLabel], namespace)
+		if !ok || math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			continue
+		}
+		values[svc] += sample.Value
+	}
+	if svc, ok := selector.RequiresExactMatch(ServiceLabel); ok {
+		if _, found := values[svc]; !found {
+			values[svc] = 0
+		}
+	}
+
+	var services []string
+	for svc := range values {
+		if selector.Matches(labels.Set{ServiceLabel: svc}) {
+			services = append(services, svc)
+		}
+	}
+	sort.Strings(services)
+
+	now := metav1.NewTime(time.Now())
+	var windowSeconds *int64
+	if metric.windowed {
+		seconds := int64(s.window.Seconds())
+		windowSeconds = &seconds
+	}
+
+	list := &ExternalMetricValueList{Items: []ExternalMetricValue{}}
+	list.Kind = externalMetricValueListKind
+	list.APIVersion = APIGroup + "/" + APIVersion
+	for _, svc := range services {
+		list.Items = append(list.Items, ExternalMetricValue{
+			MetricName:    name,
+			MetricLabels:  map[string]string{ServiceLabel: svc},
+			Timestamp:     now,
+			WindowSeconds: windowSeconds,
+			Value:         *metric.quantity(values[svc]),
+		})
+	}
+	return list, nil
+}
@@ -0,0 +1,147 @@
+package externalmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// newPrometheusServer returns a Prometheus server answering the queries with the given result, and recording the
+// queries it received
+func newPrometheusServer(t *testing.T, result string, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tassert.Equal(t, "/api/v1/query", r.URL.Path)
+		*queries = append(*queries, r.URL.Query().Get("query"))
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, result)
+	}))
+}
+
+func TestLocalClustersMatcher(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(`envoy_cluster_name=~"bookstore/.+-local"`, localClustersMatcher("bookstore"))
+}
+
+func TestGetServiceName(t *testing.T) {
+	testCases := []struct {
+		clusterName  string
+		expectedName string
+		expectedOk   bool
+	}{
+		{clusterName: "bookstore/bookstore-v1-local", expectedName: "bookstore-v1", expectedOk: true},
+		{clusterName: "bookstore/bookstore-local-local", expectedName: "bookstore-local", expectedOk: true},
+		{clusterName: "bookstore/bookstore-v1", expectedOk: false},
+		{clusterName: "bookthief/bookthief-local", expectedOk: false},
+		{clusterName: "bookstore/-local", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.clusterName, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			name, ok := getServiceName(tc.clusterName, "bookstore")
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedName, name)
+		})
+	}
+}
+
+func TestGetMetricValues(t *testing.T) {
+	result := `{"metric":{"envoy_cluster_name":"bookstore/bookstore-v2-local"},"value":[1616000000,"2.5"]},
+		{"metric":{"envoy_cluster_name":"bookstore/bookstore-v1-local"},"value":[1616000000,"10.1234"]},
+		{"metric":{"envoy_cluster_name":"bookstore/mysql"},"value":[1616000000,"3"]}`
+
+	testCases := []struct {
+		name             string
+		metric           string
+		selector         string
+		expectedQuery    string
+		expectedServices []string
+		expectedValues   []string
+		expectedWindow   bool
+	}{
+		{
+			name:             "request rate of all the services",
+			metric:           RequestsPerSecondMetric,
+			expectedQuery:    `sum by (envoy_cluster_name) (rate(envoy_cluster_upstream_rq_xx{envoy_cluster_name=~"bookstore/.+-local"}[60s]))`,
+			expectedServices: []string{"bookstore-v1", "bookstore-v2"},
+			expectedValues:   []string{"10123m", "2500m"},
+			expectedWindow:   true,
+		},
+		{
+			name:             "concurrency of a service",
+			metric:           ConcurrencyMetric,
+			selector:         "service=bookstore-v2",
+			expectedQuery:    `sum by (envoy_cluster_name) (envoy_cluster_upstream_rq_active{envoy_cluster_name=~"bookstore/.+-local"})`,
+			expectedServices: []string{"bookstore-v2"},
+			expectedValues:   []string{"3"},
+		},
+		{
+			name:             "service without requests",
+			metric:           RequestsPerSecondMetric,
+			selector:         "service=bookstore-v3",
+			expectedQuery:    `sum by (envoy_cluster_name) (rate(envoy_cluster_upstream_rq_xx{envoy_cluster_name=~"bookstore/.+-local"}[60s]))`,
+			expectedServices: []string{"bookstore-v3"},
+			expectedValues:   []string{"0"},
+			expectedWindow:   true,
+		},
+		{
+			name:             "services matching a set selector",
+			metric:           RequestsPerSecondMetric,
+			selector:         "service in (bookstore-v1, bookstore-v3)",
+			expectedQuery:    `sum by (envoy_cluster_name) (rate(envoy_cluster_upstream_rq_xx{envoy_cluster_name=~"bookstore/.+-local"}[60s]))`,
+			expectedServices: []string{"bookstore-v1"},
+			expectedValues:   []string{"10123m"},
+			expectedWindow:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var queries []string
+			prometheus := newPrometheusServer(t, result, &queries)
+			defer prometheus.Close()
+
+			selector, err := labels.Parse(tc.selector)
+			assert.Nil(err)
+
+			s := NewServer(nil)
+			list, err := s.getMetricValues(prometheus.URL, externalMetrics[tc.metric], tc.metric, "bookstore", selector)
+			assert.Nil(err)
+			assert.Equal([]string{tc.expectedQuery}, queries)
+			assert.Equal(externalMetricValueListKind, list.Kind)
+			assert.Len(list.Items, len(tc.expectedServices))
+
+			for i, item := range list.Items {
+				assert.Equal(tc.metric, item.MetricName)
+				assert.Equal(map[string]string{ServiceLabel: tc.expectedServices[i]}, item.MetricLabels)
+				assert.Equal(tc.expectedValues[i], item.Value.String())
+				if tc.expectedWindow {
+					assert.Equal(int64(60), *item.WindowSeconds)
+				} else {
+					assert.Nil(item.WindowSeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	assert := tassert.New(t)
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+	}))
+	defer prometheus.Close()
+
+	s := NewServer(nil)
+	_, err := s.getMetricValues(prometheus.URL, externalMetrics[ConcurrencyMetric], ConcurrencyMetric, "bookstore", labels.Everything())
+	assert.NotNil(err)
+}
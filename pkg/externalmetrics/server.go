@@ -0,0 +1,95 @@
+package externalmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/aggregatedapi"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// apiPath is the path of the served version of the External Metrics API
+	apiPath = "/apis/" + APIGroup + "/" + APIVersion
+
+	// labelSelectorParam is the query parameter selecting the metrics by their labels
+	labelSelectorParam = "labelSelector"
+)
+
+// NewServer returns a server of the External Metrics API querying the metrics from the Prometheus server configured in
+// the OSM ConfigMap.
+func NewServer(cfg configurator.Configurator) *Server {
+	return &Server{
+		cfg:    cfg,
+		client: &http.Client{Timeout: metricsQueryTimeout},
+		window: defaultWindow,
+	}
+}
+
+// Start serves the External Metrics API over TLS until the stop channel is closed, and registers the CA of the
+// certificate of the server with the APIService of the API.
+func (s *Server) Start(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, certManager certificate.Manager, osmNamespace string, stop <-chan struct{}) error {
+	return aggregatedapi.NewServer("External Metrics API", APIServiceName, constants.OSMExternalMetricsPort, s).Start(kubeClient, dynamicClient, certManager, osmNamespace, stop)
+}
+
+// ServeHTTP serves the GET requests for the External Metrics API proxied by the Kubernetes API server:
+// 1. /apis/external.metrics.k8s.io/v1beta1 lists the served metrics
+// 2. /apis/external.metrics.k8s.io/v1beta1/namespaces/<namespace>/<metric>[?labelSelector=<selector>] serves the values of a metric for the services of a namespace
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if path == apiPath {
+		aggregatedapi.WriteJSON(w, getAPIResourceList())
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, apiPath+"/"), "/")
+	if !strings.HasPrefix(path, apiPath+"/") || len(parts) != 3 || parts[0] != "namespaces" {
+		aggregatedapi.WriteStatus(w, http.StatusNotFound, fmt.Sprintf("Path %s not found", req.URL.Path))
+		return
+	}
+	namespace, metricName := parts[1], parts[2]
+	metric, ok := externalMetrics[metricName]
+	if !ok {
+		aggregatedapi.WriteStatus(w, http.StatusNotFound, fmt.Sprintf("Metric %s not found", metricName))
+		return
+	}
+
+	selector, err := labels.Parse(req.URL.Query().Get(labelSelectorParam))
+	if err != nil {
+		aggregatedapi.WriteStatus(w, http.StatusBadRequest, fmt.Sprintf("Invalid label selector: %s", err))
+		return
+	}
+
+	metricsURL := s.cfg.GetPolicyUsageMetricsURL()
+	if metricsURL == "" {
+		aggregatedapi.WriteStatus(w, http.StatusServiceUnavailable, "The URL of the Prometheus server the metrics are queried from is not configured in the OSM ConfigMap")
+		return
+	}
+
+	list, err := s.getMetricValues(metricsURL, metric, metricName, namespace, selector)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error querying external metric %s for namespace %s", metricName, namespace)
+		aggregatedapi.WriteStatus(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	aggregatedapi.WriteJSON(w, list)
+}
+
+// getAPIResourceList returns the metrics served by the External Metrics API, for the discovery of the API
+func getAPIResourceList() *metav1.APIResourceList {
+	var resources []metav1.APIResource
+	for _, name := range []string{ConcurrencyMetric, RequestsPerSecondMetric} {
+		resources = append(resources,
+			metav1.APIResource{Name: name, Namespaced: true, Kind: externalMetricValueListKind, Verbs: metav1.Verbs{"get"}},
+		)
+	}
+	return aggregatedapi.NewAPIResourceList(APIGroup+"/"+APIVersion, resources...)
+}
@@ -0,0 +1,106 @@
+package externalmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func TestServeHTTP(t *testing.T) {
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer prometheus.Close()
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		metricsURL     string
+		expectedStatus int
+		expectedKind   string
+	}{
+		{
+			name:           "discovery",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1",
+			expectedStatus: http.StatusOK,
+			expectedKind:   "APIResourceList",
+		},
+		{
+			name:           "request rate",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore/osm_service_requests_per_second?labelSelector=service%3Dbookstore",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   externalMetricValueListKind,
+		},
+		{
+			name:           "concurrency",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore/osm_service_concurrency/",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   externalMetricValueListKind,
+		},
+		{
+			name:           "unknown metric",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore/queue_length",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "invalid label selector",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore/osm_service_concurrency?labelSelector=service+in+%28bookstore",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusBadRequest,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "Prometheus not configured",
+			method:         http.MethodGet,
+			path:           "/apis/external.metrics.k8s.io/v1beta1/namespaces/bookstore/osm_service_concurrency",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedKind:   "Status",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetPolicyUsageMetricsURL().Return(tc.metricsURL).AnyTimes()
+
+			s := NewServer(mockConfigurator)
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(tc.expectedStatus, w.Code)
+			var typeMeta metav1.TypeMeta
+			assert.Nil(json.Unmarshal(w.Body.Bytes(), &typeMeta))
+			assert.Equal(tc.expectedKind, typeMeta.Kind)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Package externalmetrics implements the Kubernetes External Metrics API (external.metrics.k8s.io/v1beta1), served by
+// osm-controller as an aggregated API of the Kubernetes API server. It exposes the request rate and the concurrency of
+// the services of the mesh, as observed by their sidecar proxies, so that HorizontalPodAutoscalers can scale workloads
+// on their traffic. The metrics are queried from the Prometheus server scraping the sidecar proxies.
+package externalmetrics
+
+import (
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("external-metrics")
+
+const (
+	// APIGroup is the API group of the External Metrics API
+	APIGroup = "external.metrics.k8s.io"
+
+	// APIVersion is the version of the External Metrics API served by OSM
+	APIVersion = "v1beta1"
+
+	// APIServiceName is the name of the APIService registering the External Metrics API with the Kubernetes API server
+	APIServiceName = APIVersion + "." + APIGroup
+
+	// RequestsPerSecondMetric is the metric of the number of requests per second received by a service
+	RequestsPerSecondMetric = "osm_service_requests_per_second"
+
+	// ConcurrencyMetric is the metric of the number of requests being processed by a service
+	ConcurrencyMetric = "osm_service_concurrency"
+
+	// ServiceLabel is the label of the metrics naming the service they are for
+	ServiceLabel = "service"
+
+	// defaultWindow is the window over which the request rate is computed
+	defaultWindow = time.Minute
+
+	// metricsQueryTimeout is the timeout of a query to the Prometheus server
+	metricsQueryTimeout = 10 * time.Second
+
+	externalMetricValueListKind = "ExternalMetricValueList"
+)
+
+// ExternalMetricValue is the value of a metric for the service named by its labels.
+type ExternalMetricValue struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// MetricName is the name of the metric
+	MetricName string `json:"metricName"`
+
+	// MetricLabels are the labels of the metric, naming the service the value is for
+	MetricLabels map[string]string `json:"metricLabels"`
+
+	// Timestamp is the time at which the value was computed
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// WindowSeconds is the window over which the value was computed, nil for instantaneous values
+	WindowSeconds *int64 `json:"window,omitempty"`
+
+	// Value is the value of the metric
+	Value resource.Quantity `json:"value"`
+}
+
+// ExternalMetricValueList is a list of ExternalMetricValues.
+type ExternalMetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ExternalMetricValue `json:"items"`
+}
+
+// Server serves the External Metrics API.
+type Server struct {
+	cfg    configurator.Configurator
+	client *http.Client
+	window time.Duration
+}
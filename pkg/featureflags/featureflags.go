@@ -7,7 +7,9 @@ import (
 
 // OptionalFeatures is a struct to enable/disable optional features
 type OptionalFeatures struct {
-	WASMStats bool
+	WASMStats     bool
+	NodeProxyMode bool
+	EgressGateway bool
 }
 
 var (
@@ -35,3 +37,13 @@ func IsFeatureNameEnabled() bool {
 func IsWASMStatsEnabled() bool {
 	return Features.WASMStats
 }
+
+// IsNodeProxyModeEnabled returns a boolean indicating if pods can be served by a per-node proxy instead of a sidecar proxy
+func IsNodeProxyModeEnabled() bool {
+	return Features.NodeProxyMode
+}
+
+// IsEgressGatewayEnabled returns a boolean indicating if the egress traffic of the sidecar proxies is routed through the egress gateway
+func IsEgressGatewayEnabled() bool {
+	return Features.EgressGateway
+}
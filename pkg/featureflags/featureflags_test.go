@@ -11,21 +11,27 @@ var _ = Describe("Test FeatureFlags", func() {
 
 			defaultWASMStats := IsWASMStatsEnabled()
 			Expect(defaultWASMStats).ToNot(BeTrue())
+			defaultNodeProxyMode := IsNodeProxyModeEnabled()
+			Expect(defaultNodeProxyMode).ToNot(BeTrue())
 
-			optionalFeatures := OptionalFeatures{WASMStats: true}
+			optionalFeatures := OptionalFeatures{WASMStats: true, NodeProxyMode: true}
 			Initialize(optionalFeatures)
 
 			initializedWASMStats := IsWASMStatsEnabled()
 			Expect(initializedWASMStats).To(BeTrue())
+			initializedNodeProxyMode := IsNodeProxyModeEnabled()
+			Expect(initializedNodeProxyMode).To(BeTrue())
 
 		})
 
 		It("should not re-initialize OptionalFeatures", func() {
-			optionalFeatures2 := OptionalFeatures{WASMStats: false}
+			optionalFeatures2 := OptionalFeatures{WASMStats: false, NodeProxyMode: false}
 			Initialize(optionalFeatures2)
 
 			WASMStats := IsWASMStatsEnabled()
 			Expect(WASMStats).To(BeTrue())
+			NodeProxyMode := IsNodeProxyModeEnabled()
+			Expect(NodeProxyMode).To(BeTrue())
 		})
 	})
 })
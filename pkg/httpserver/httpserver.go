@@ -3,6 +3,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"time"
@@ -25,6 +26,7 @@ type HTTPServer struct {
 	server       *http.Server
 	httpServeMux *http.ServeMux // Used to restart the server once stopped
 	port         uint16         // Used to restart the server once stopped
+	tlsConfig    *tls.Config    // Used to restart the server once stopped, nil when serving plaintext HTTP
 	stopSyncChan chan struct{}
 }
 
@@ -44,6 +46,15 @@ func NewHTTPServer(port uint16) *HTTPServer {
 	}
 }
 
+// NewHTTPSServer creates a new API server serving HTTPS with the given TLS configuration. Clients are authenticated
+// according to the configuration, plaintext clients are rejected.
+func NewHTTPSServer(port uint16, tlsConfig *tls.Config) *HTTPServer {
+	s := NewHTTPServer(port)
+	s.tlsConfig = tlsConfig
+	s.server.TLSConfig = tlsConfig
+	return s
+}
+
 // AddHandler adds an HTTP handlers for the given path on the HTTPServer
 // For changes to be effective, server requires restart
 func (s *HTTPServer) AddHandler(url string, handler http.Handler) {
@@ -66,7 +77,14 @@ func (s *HTTPServer) Start() error {
 
 	go func() {
 		log.Info().Msgf("Starting API Server on %s", s.server.Addr)
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.tlsConfig != nil {
+			// The certificates are provided by the TLS configuration
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			events.GenericEventRecorder().FatalEvent(err, events.InitializationError,
 				"Error starting HTTP server")
 		}
@@ -97,8 +115,9 @@ func (s *HTTPServer) Stop() error {
 	// Free and reset the server, so it can be started again
 	s.started = false
 	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: s.httpServeMux,
+		Addr:      fmt.Sprintf(":%d", s.port),
+		Handler:   s.httpServeMux,
+		TLSConfig: s.tlsConfig,
 	}
 
 	return nil
@@ -3,6 +3,8 @@ package identity
 import (
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
@@ -18,3 +20,14 @@ func GetKubernetesServiceIdentity(svcAccount service.K8sServiceAccount, trustDom
 	si := strings.Join([]string{svcAccount.Name, svcAccount.Namespace, trustDomain}, identityDelimiter)
 	return ServiceIdentity(si)
 }
+
+// GetKubernetesServiceAccount returns the Kubernetes ServiceAccount of the given ServiceIdentity, of the form
+// <name>.<namespace>.<trust-domain>
+func GetKubernetesServiceAccount(si ServiceIdentity) (service.K8sServiceAccount, error) {
+	// The name and namespace of a ServiceAccount can not contain the delimiter, unlike the trust domain
+	chunks := strings.SplitN(si.String(), identityDelimiter, 3)
+	if len(chunks) != 3 || chunks[0] == "" || chunks[1] == "" || chunks[2] == "" {
+		return service.K8sServiceAccount{}, errors.Errorf("Invalid service identity %s, expected <name>.<namespace>.<trust-domain>", si)
+	}
+	return service.K8sServiceAccount{Name: chunks[0], Namespace: chunks[1]}, nil
+}
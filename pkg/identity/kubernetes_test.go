@@ -38,3 +38,42 @@ func TestGetKubernetesServiceIdentity(t *testing.T) {
 
 	assert.Equal(ServiceIdentity("foo").String(), "foo")
 }
+
+func TestGetKubernetesServiceAccount(t *testing.T) {
+	testCases := []struct {
+		serviceIdentity    ServiceIdentity
+		expectedSvcAccount service.K8sServiceAccount
+		expectedErr        bool
+	}{
+		{
+			ServiceIdentity("foo.bar.cluster.local"),
+			service.K8sServiceAccount{Name: "foo", Namespace: "bar"},
+			false,
+		},
+		{
+			ServiceIdentity("foo.bar.baz"),
+			service.K8sServiceAccount{Name: "foo", Namespace: "bar"},
+			false,
+		},
+		{
+			ServiceIdentity("foo.bar"),
+			service.K8sServiceAccount{},
+			true,
+		},
+		{
+			ServiceIdentity(""),
+			service.K8sServiceAccount{},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing GetKubernetesServiceAccount for test case: %v", tc), func(t *testing.T) {
+			assert := tassert.New(t)
+
+			svcAccount, err := GetKubernetesServiceAccount(tc.serviceIdentity)
+			assert.Equal(tc.expectedSvcAccount, svcAccount)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
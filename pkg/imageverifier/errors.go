@@ -0,0 +1,20 @@
+package imageverifier
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrInvalidImage is an error for when an image reference cannot be parsed.
+	ErrInvalidImage = errors.New("invalid image reference")
+
+	// ErrInvalidDigest is an error for when a digest is not of the form sha256:<hex>.
+	ErrInvalidDigest = errors.New("invalid digest")
+
+	// ErrDigestMismatch is an error for when an image is pinned to a digest other than the one required by the policy.
+	ErrDigestMismatch = errors.New("image digest does not match the pinned digest")
+
+	// ErrInvalidPublicKey is an error for when a cosign public key is not a PEM encoded ECDSA public key.
+	ErrInvalidPublicKey = errors.New("invalid cosign public key")
+
+	// ErrSignatureNotVerified is an error for when no cosign signature of an image could be verified.
+	ErrSignatureNotVerified = errors.New("no valid cosign signature found for image")
+)
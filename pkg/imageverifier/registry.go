@@ -0,0 +1,157 @@
+package imageverifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dockerHubDomain is the domain of Docker Hub images, which is served by dockerHubRegistry
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+
+	// maxRegistryResponseSize is the maximum size of a manifest or blob read from a registry
+	maxRegistryResponseSize = 4 * 1024 * 1024
+)
+
+// manifestMediaTypes are the media types of the manifests accepted from registries. Image indexes are accepted so that the
+// digest resolved for a multi-arch image is the digest of its index, which is the digest a container runtime pulls.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// authChallengeParamRegex matches the parameters of a WWW-Authenticate challenge, Ex: realm="https://auth.docker.io/token"
+var authChallengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// httpRegistry is a registryClient for registries implementing the OCI distribution API over HTTPS.
+// Only anonymous access and the bearer token authentication used for anonymous pulls are supported.
+type httpRegistry struct {
+	client *http.Client
+}
+
+func (r *httpRegistry) getManifest(repository reference.Named, tagOrDigest string) ([]byte, string, error) {
+	body, header, err := r.get(repository, "manifests", tagOrDigest, strings.Join(manifestMediaTypes, ","))
+	if err != nil {
+		return nil, "", err
+	}
+
+	manifestDigest := header.Get("Docker-Content-Digest")
+	if manifestDigest == "" {
+		manifestDigest = digest.FromBytes(body).String()
+	}
+	return body, manifestDigest, nil
+}
+
+func (r *httpRegistry) getBlob(repository reference.Named, blobDigest string) ([]byte, error) {
+	body, _, err := r.get(repository, "blobs", blobDigest, "")
+	return body, err
+}
+
+func (r *httpRegistry) get(repository reference.Named, kind, tagOrDigest, accept string) ([]byte, http.Header, error) {
+	domain := reference.Domain(repository)
+	if domain == dockerHubDomain {
+		domain = dockerHubRegistry
+	}
+	path := reference.Path(repository)
+	requestURL := fmt.Sprintf("https://%s/v2/%s/%s/%s", domain, path, kind, tagOrDigest)
+
+	resp, err := r.do(requestURL, accept, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		_ = resp.Body.Close()
+		token, err := r.getToken(resp.Header.Get("WWW-Authenticate"), path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp, err = r.do(requestURL, accept, token); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("GET %s returned status %d", requestURL, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRegistryResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Header, nil
+}
+
+func (r *httpRegistry) do(requestURL, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r.client.Do(req)
+}
+
+// getToken returns an anonymous bearer token to pull from the given repository path, as requested by the given challenge.
+func (r *httpRegistry) getToken(challenge, path string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", errors.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, match := range authChallengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", errors.Errorf("registry authentication challenge %q has no realm", challenge)
+	}
+
+	query := url.Values{}
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope, ok := params["scope"]
+	if !ok {
+		scope = fmt.Sprintf("repository:%s:pull", path)
+	}
+	query.Set("scope", scope)
+
+	resp, err := r.do(params["realm"]+"?"+query.Encode(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("registry token request returned status %d", resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRegistryResponseSize)).Decode(&tokenResponse); err != nil {
+		return "", errors.Errorf("error decoding registry token: %s", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
@@ -0,0 +1,55 @@
+// Package imageverifier implements the verification of container images against a pinned digest and cosign signatures.
+// It is used by the sidecar injector to verify the Envoy image before injecting it into pods.
+package imageverifier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("image-verifier")
+
+const (
+	// verifiedImageCacheTTL is the duration for which a successful signature verification is cached
+	verifiedImageCacheTTL = 10 * time.Minute
+
+	// registryRequestTimeout is the timeout of a request to a container registry
+	registryRequestTimeout = 5 * time.Second
+)
+
+// Policy is the verification policy of an image.
+type Policy struct {
+	// Digest is the digest the image must be pinned to, of the form sha256:<hex>
+	Digest string
+
+	// CosignPublicKey is the PEM encoded public key the cosign signature of the image must be verified with
+	CosignPublicKey string
+}
+
+// IsEnabled returns true if the policy requires images to be verified.
+func (p Policy) IsEnabled() bool {
+	return p.Digest != "" || p.CosignPublicKey != ""
+}
+
+// Verifier verifies container images against a verification policy.
+type Verifier struct {
+	registry registryClient
+
+	// verifiedImages caches the expiration of successful signature verifications, keyed by the
+	// digested image reference and public key
+	verifiedImages     map[string]time.Time
+	verifiedImagesLock sync.Mutex
+}
+
+// registryClient is the interface of the client used to fetch manifests and blobs from container registries.
+type registryClient interface {
+	// getManifest returns the manifest of the given repository with the given tag or digest, and its digest
+	getManifest(repository reference.Named, tagOrDigest string) ([]byte, string, error)
+
+	// getBlob returns the blob of the given repository with the given digest
+	getBlob(repository reference.Named, digest string) ([]byte, error)
+}
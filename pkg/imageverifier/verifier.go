@@ -0,0 +1,229 @@
+package imageverifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation of a signature manifest layer holding the base64 encoded signature of the layer
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// ociManifest is the subset of an OCI image manifest used to read cosign signatures
+type ociManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// cosignPayload is the subset of the simple signing payload signed by cosign
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// NewVerifier returns a new Verifier fetching signatures from container registries over HTTPS.
+func NewVerifier() *Verifier {
+	return newVerifier(&httpRegistry{
+		client: &http.Client{Timeout: registryRequestTimeout},
+	})
+}
+
+func newVerifier(registry registryClient) *Verifier {
+	return &Verifier{
+		registry:       registry,
+		verifiedImages: make(map[string]time.Time),
+	}
+}
+
+// Verify verifies the given image against the given policy, and returns the image reference pinned to the verified digest.
+// When the policy pins a digest, the image must not be pinned to another digest. When the policy requires a cosign
+// signature, the digest of the image is resolved from its registry if it is not pinned, and a signature of that digest
+// must be verified with the public key of the policy. An error is returned if the image cannot be verified.
+func (v *Verifier) Verify(image string, policy Policy) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidImage, "%s: %s", image, err)
+	}
+
+	var imageDigest digest.Digest
+	if digested, ok := named.(reference.Digested); ok {
+		imageDigest = digested.Digest()
+	}
+
+	if policy.Digest != "" {
+		pinnedDigest, err := ParseDigest(policy.Digest)
+		if err != nil {
+			return "", err
+		}
+		if imageDigest != "" && imageDigest != pinnedDigest {
+			return "", errors.Wrapf(ErrDigestMismatch, "image %s, pinned digest %s", image, pinnedDigest)
+		}
+		imageDigest = pinnedDigest
+	}
+
+	repository := reference.TrimNamed(named)
+
+	if policy.CosignPublicKey != "" {
+		publicKey, err := ParseCosignPublicKey(policy.CosignPublicKey)
+		if err != nil {
+			return "", err
+		}
+
+		if imageDigest == "" {
+			// Resolve the digest the tag currently points to, so that the verified digest is the one injected
+			tag := reference.TagNameOnly(named).(reference.Tagged).Tag()
+			_, resolvedDigest, err := v.registry.getManifest(repository, tag)
+			if err != nil {
+				return "", errors.Errorf("Error resolving digest of image %s: %s", image, err)
+			}
+			if imageDigest, err = ParseDigest(resolvedDigest); err != nil {
+				return "", err
+			}
+		}
+
+		if err := v.verifySignature(repository, imageDigest, publicKey, policy.CosignPublicKey); err != nil {
+			return "", err
+		}
+	}
+
+	pinned, err := reference.WithDigest(repository, imageDigest)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidImage, "%s: %s", image, err)
+	}
+	return reference.FamiliarString(pinned), nil
+}
+
+// verifySignature verifies that the image with the given digest has a cosign signature that can be verified with the given public key.
+func (v *Verifier) verifySignature(repository reference.Named, imageDigest digest.Digest, publicKey *ecdsa.PublicKey, publicKeyPEM string) error {
+	cacheKey := fmt.Sprintf("%s@%s/%s", repository.Name(), imageDigest, publicKeyPEM)
+	if v.isVerified(cacheKey) {
+		return nil
+	}
+
+	// cosign stores the signatures of an image in the repository of the image, tagged with the digest of the image
+	signatureTag := fmt.Sprintf("%s-%s.sig", imageDigest.Algorithm(), imageDigest.Hex())
+	manifestBytes, _, err := v.registry.getManifest(repository, signatureTag)
+	if err != nil {
+		return errors.Wrapf(ErrSignatureNotVerified, "%s@%s: error fetching signatures: %s", repository.Name(), imageDigest, err)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return errors.Wrapf(ErrSignatureNotVerified, "%s@%s: error decoding signatures manifest: %s", repository.Name(), imageDigest, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := v.registry.getBlob(repository, layer.Digest)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error fetching signature payload %s of image %s@%s", layer.Digest, repository.Name(), imageDigest)
+			continue
+		}
+
+		if err := verifyCosignSignature(payload, layer.Digest, signature, imageDigest, publicKey); err != nil {
+			log.Debug().Err(err).Msgf("Signature payload %s of image %s@%s could not be verified", layer.Digest, repository.Name(), imageDigest)
+			continue
+		}
+
+		log.Debug().Msgf("Verified signature of image %s@%s", repository.Name(), imageDigest)
+		v.setVerified(cacheKey)
+		return nil
+	}
+
+	return errors.Wrapf(ErrSignatureNotVerified, "%s@%s", repository.Name(), imageDigest)
+}
+
+// verifyCosignSignature verifies the given base64 encoded signature of the given simple signing payload, and that the
+// payload was signed for the given image digest.
+func verifyCosignSignature(payload []byte, payloadDigest string, signature string, imageDigest digest.Digest, publicKey *ecdsa.PublicKey) error {
+	if digest.FromBytes(payload).String() != payloadDigest {
+		return errors.Errorf("payload does not match its digest %s", payloadDigest)
+	}
+
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Errorf("error decoding signature: %s", err)
+	}
+
+	hash := sha256.Sum256(payload)
+	if !ecdsa.VerifyASN1(publicKey, hash[:], signatureBytes) {
+		return errors.New("invalid signature")
+	}
+
+	var signedPayload cosignPayload
+	if err := json.Unmarshal(payload, &signedPayload); err != nil {
+		return errors.Errorf("error decoding payload: %s", err)
+	}
+	if signedPayload.Critical.Image.DockerManifestDigest != imageDigest.String() {
+		return errors.Errorf("payload was signed for digest %s", signedPayload.Critical.Image.DockerManifestDigest)
+	}
+
+	return nil
+}
+
+func (v *Verifier) isVerified(cacheKey string) bool {
+	v.verifiedImagesLock.Lock()
+	defer v.verifiedImagesLock.Unlock()
+
+	expiration, ok := v.verifiedImages[cacheKey]
+	return ok && time.Now().Before(expiration)
+}
+
+func (v *Verifier) setVerified(cacheKey string) {
+	v.verifiedImagesLock.Lock()
+	defer v.verifiedImagesLock.Unlock()
+
+	v.verifiedImages[cacheKey] = time.Now().Add(verifiedImageCacheTTL)
+}
+
+// ParseDigest parses the given digest of the form sha256:<hex>.
+func ParseDigest(digestStr string) (digest.Digest, error) {
+	d, err := digest.Parse(digestStr)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidDigest, "%s: %s", digestStr, err)
+	}
+	if d.Algorithm() != digest.SHA256 {
+		return "", errors.Wrapf(ErrInvalidDigest, "%s: unsupported algorithm %s", digestStr, d.Algorithm())
+	}
+	return d, nil
+}
+
+// ParseCosignPublicKey parses the given PEM encoded ECDSA public key, as generated by 'cosign generate-key-pair'.
+func ParseCosignPublicKey(publicKeyPEM string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.Wrap(ErrInvalidPublicKey, "no PEM block found")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+	}
+
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "unsupported key type %T", publicKey)
+	}
+	return ecdsaPublicKey, nil
+}
@@ -0,0 +1,281 @@
+package imageverifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+const (
+	testImage       = "envoyproxy/envoy-alpine:v1.17.1"
+	testRepository  = "docker.io/envoyproxy/envoy-alpine"
+	testImageDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	otherDigest     = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+)
+
+// fakeRegistry is a registryClient serving manifests and blobs from memory
+type fakeRegistry struct {
+	manifests map[string][]byte
+	digests   map[string]string
+	blobs     map[string][]byte
+	requests  int
+
+	signatureLayers map[string][]map[string]interface{}
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		manifests: make(map[string][]byte),
+		digests:   make(map[string]string),
+		blobs:     make(map[string][]byte),
+
+		signatureLayers: make(map[string][]map[string]interface{}),
+	}
+}
+
+func (r *fakeRegistry) getManifest(repository reference.Named, tagOrDigest string) ([]byte, string, error) {
+	r.requests++
+	key := repository.Name() + ":" + tagOrDigest
+	manifest, ok := r.manifests[key]
+	if !ok {
+		return nil, "", errors.Errorf("manifest %s not found", key)
+	}
+	return manifest, r.digests[key], nil
+}
+
+func (r *fakeRegistry) getBlob(repository reference.Named, blobDigest string) ([]byte, error) {
+	r.requests++
+	blob, ok := r.blobs[repository.Name()+"@"+blobDigest]
+	if !ok {
+		return nil, errors.Errorf("blob %s not found", blobDigest)
+	}
+	return blob, nil
+}
+
+// addSignature adds a cosign signature of the given image digest, signed with the given key, to the registry
+func (r *fakeRegistry) addSignature(imageDigest string, signedDigest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, testRepository, signedDigest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		panic(err)
+	}
+	payloadDigest := digest.FromBytes(payload).String()
+	r.blobs[testRepository+"@"+payloadDigest] = payload
+
+	d := digest.Digest(imageDigest)
+	signatureTag := fmt.Sprintf("%s:%s-%s.sig", testRepository, d.Algorithm(), d.Hex())
+	r.signatureLayers[signatureTag] = append(r.signatureLayers[signatureTag], map[string]interface{}{
+		"mediaType":   "application/vnd.dev.cosign.simplesigning.v1+json",
+		"digest":      payloadDigest,
+		"annotations": map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+	})
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"layers":        r.signatureLayers[signatureTag],
+	})
+	if err != nil {
+		panic(err)
+	}
+	r.manifests[signatureTag] = manifest
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestVerifyDigest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		image         string
+		policy        Policy
+		expectedImage string
+		expectedError error
+	}{
+		{
+			name:          "tagged image is pinned to the digest",
+			image:         testImage,
+			policy:        Policy{Digest: testImageDigest},
+			expectedImage: "envoyproxy/envoy-alpine@" + testImageDigest,
+		},
+		{
+			name:          "image pinned to the same digest",
+			image:         "envoyproxy/envoy-alpine@" + testImageDigest,
+			policy:        Policy{Digest: testImageDigest},
+			expectedImage: "envoyproxy/envoy-alpine@" + testImageDigest,
+		},
+		{
+			name:          "image pinned to another digest",
+			image:         "envoyproxy/envoy-alpine:v1.17.1@" + otherDigest,
+			policy:        Policy{Digest: testImageDigest},
+			expectedError: ErrDigestMismatch,
+		},
+		{
+			name:          "invalid pinned digest",
+			image:         testImage,
+			policy:        Policy{Digest: "sha256:1234"},
+			expectedError: ErrInvalidDigest,
+		},
+		{
+			name:          "invalid image",
+			image:         "Invalid Image",
+			policy:        Policy{Digest: testImageDigest},
+			expectedError: ErrInvalidImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			registry := newFakeRegistry()
+			v := newVerifier(registry)
+
+			image, err := v.Verify(tc.image, tc.policy)
+			assert.Equal(tc.expectedError, errors.Cause(err))
+			assert.Equal(tc.expectedImage, image)
+			// Verifying a pinned digest does not access the registry
+			assert.Zero(registry.requests)
+		})
+	}
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	key, publicKey := newTestKey(t)
+	otherKey, otherPublicKey := newTestKey(t)
+
+	testCases := []struct {
+		name          string
+		image         string
+		policy        Policy
+		setup         func(*fakeRegistry)
+		expectedImage string
+		expectedError error
+	}{
+		{
+			name:   "tag resolved and signature verified",
+			image:  testImage,
+			policy: Policy{CosignPublicKey: publicKey},
+			setup: func(r *fakeRegistry) {
+				r.manifests[testRepository+":v1.17.1"] = []byte("{}")
+				r.digests[testRepository+":v1.17.1"] = testImageDigest
+				r.addSignature(testImageDigest, testImageDigest, key)
+			},
+			expectedImage: "envoyproxy/envoy-alpine@" + testImageDigest,
+		},
+		{
+			name:   "pinned digest and signature verified",
+			image:  testImage,
+			policy: Policy{Digest: testImageDigest, CosignPublicKey: publicKey},
+			setup: func(r *fakeRegistry) {
+				r.addSignature(testImageDigest, testImageDigest, otherKey)
+				r.addSignature(testImageDigest, testImageDigest, key)
+			},
+			expectedImage: "envoyproxy/envoy-alpine@" + testImageDigest,
+		},
+		{
+			name:   "signed with another key",
+			image:  testImage,
+			policy: Policy{Digest: testImageDigest, CosignPublicKey: otherPublicKey},
+			setup: func(r *fakeRegistry) {
+				r.addSignature(testImageDigest, testImageDigest, key)
+			},
+			expectedError: ErrSignatureNotVerified,
+		},
+		{
+			name:   "signature of another digest",
+			image:  testImage,
+			policy: Policy{Digest: testImageDigest, CosignPublicKey: publicKey},
+			setup: func(r *fakeRegistry) {
+				r.addSignature(testImageDigest, otherDigest, key)
+			},
+			expectedError: ErrSignatureNotVerified,
+		},
+		{
+			name:          "image not signed",
+			image:         testImage,
+			policy:        Policy{Digest: testImageDigest, CosignPublicKey: publicKey},
+			setup:         func(r *fakeRegistry) {},
+			expectedError: ErrSignatureNotVerified,
+		},
+		{
+			name:          "invalid public key",
+			image:         testImage,
+			policy:        Policy{Digest: testImageDigest, CosignPublicKey: "invalid"},
+			setup:         func(r *fakeRegistry) {},
+			expectedError: ErrInvalidPublicKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			registry := newFakeRegistry()
+			tc.setup(registry)
+			v := newVerifier(registry)
+
+			image, err := v.Verify(tc.image, tc.policy)
+			assert.Equal(tc.expectedError, errors.Cause(err))
+			assert.Equal(tc.expectedImage, image)
+		})
+	}
+}
+
+func TestVerifyCosignSignatureCached(t *testing.T) {
+	assert := tassert.New(t)
+
+	key, publicKey := newTestKey(t)
+	registry := newFakeRegistry()
+	registry.addSignature(testImageDigest, testImageDigest, key)
+	v := newVerifier(registry)
+
+	policy := Policy{Digest: testImageDigest, CosignPublicKey: publicKey}
+	_, err := v.Verify(testImage, policy)
+	assert.Nil(err)
+	requests := registry.requests
+
+	_, err = v.Verify(testImage, policy)
+	assert.Nil(err)
+	assert.Equal(requests, registry.requests)
+}
+
+func TestParseCosignPublicKey(t *testing.T) {
+	assert := tassert.New(t)
+
+	_, publicKey := newTestKey(t)
+	_, err := ParseCosignPublicKey(publicKey)
+	assert.Nil(err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.Nil(err)
+	_, err = ParseCosignPublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	assert.Equal(ErrInvalidPublicKey, errors.Cause(err))
+
+	_, err = ParseCosignPublicKey("not a PEM key")
+	assert.Equal(ErrInvalidPublicKey, errors.Cause(err))
+}
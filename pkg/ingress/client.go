@@ -1,34 +1,78 @@
 package ingress
 
 import (
-	"reflect"
+	"sort"
 
+	networkingV1 "k8s.io/api/networking/v1"
 	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
+	gatewayV1alpha1 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
-// NewIngressClient implements ingress.Monitor and creates the Kubernetes client to monitor Ingress resources.
-func NewIngressClient(kubeClient kubernetes.Interface, kubeController k8s.Controller, stop chan struct{}, cfg configurator.Configurator) (Monitor, error) {
+// NewIngressClient implements ingress.Monitor and creates the Kubernetes client to monitor Ingress, IngressBackend, and
+// Gateway API HTTPRoute and Gateway resources.
+func NewIngressClient(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, kubeController k8s.Controller, stop chan struct{}, cfg configurator.Configurator) (Monitor, error) {
+	v1Supported, err := k8s.IsKindServed(kubeClient.Discovery(), networkingV1.SchemeGroupVersion, "Ingress")
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the ingress API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	ingressBackendSupported, err := k8s.IsKindServed(kubeClient.Discovery(), policyV1alpha1.SchemeGroupVersion, policyV1alpha1.IngressBackendKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the IngressBackend API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	httpRouteSupported, err := k8s.IsKindServed(kubeClient.Discovery(), gatewayV1alpha1.SchemeGroupVersion, gatewayV1alpha1.HTTPRouteKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the Gateway API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	gatewaySupported, err := k8s.IsKindServed(kubeClient.Discovery(), gatewayV1alpha1.SchemeGroupVersion, gatewayV1alpha1.GatewayKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the Gateway API versions served by the Kubernetes API server")
+		return nil, err
+	}
+
 	informerFactory := informers.NewSharedInformerFactory(kubeClient, k8s.DefaultKubeEventResyncInterval)
-	informer := informerFactory.Networking().V1beta1().Ingresses().Informer()
 
 	client := Client{
-		informer:       informer,
-		cache:          informer.GetStore(),
 		cacheSynced:    make(chan interface{}),
 		kubeController: kubeController,
+		cfg:            cfg,
+	}
+
+	// Kubernetes 1.19+ serves the ingress resources with the networking.k8s.io/v1 API version, which older versions
+	// of Kubernetes do not support. Watching a single API version ensures each ingress resource is only seen once.
+	var informer cache.SharedIndexInformer
+	if v1Supported {
+		log.Info().Msgf("Watching ingress resources with API version %s", networkingV1.SchemeGroupVersion)
+		client.informerV1 = informerFactory.Networking().V1().Ingresses().Informer()
+		client.cacheV1 = client.informerV1.GetStore()
+		informer = client.informerV1
+	} else {
+		log.Info().Msgf("Watching ingress resources with API version %s", networkingV1beta1.SchemeGroupVersion)
+		client.informerV1beta1 = informerFactory.Networking().V1beta1().Ingresses().Informer()
+		client.cacheV1beta1 = client.informerV1beta1.GetStore()
+		informer = client.informerV1beta1
 	}
 
 	shouldObserve := func(obj interface{}) bool {
-		ns := reflect.ValueOf(obj).Elem().FieldByName("ObjectMeta").FieldByName("Namespace").String()
-		return kubeController.IsMonitoredNamespace(ns)
+		object, ok := obj.(metav1.Object)
+		return ok && kubeController.IsMonitoredNamespace(object.GetNamespace())
 	}
 
 	ingrEventTypes := k8s.EventTypes{
@@ -37,8 +81,57 @@ func NewIngressClient(kubeClient kubernetes.Interface, kubeController k8s.Contro
 		Delete: announcements.IngressDeleted,
 	}
 	informer.AddEventHandler(k8s.GetKubernetesEventHandlers("Ingress", "Kubernetes", shouldObserve, ingrEventTypes))
+	informersToSync := []cache.SharedIndexInformer{informer}
+
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, k8s.DefaultKubeEventResyncInterval)
+
+	// The IngressBackend CRD is optional, ingress traffic to backends not listed by an IngressBackend is not restricted
+	if ingressBackendSupported {
+		log.Info().Msgf("Watching IngressBackend resources with API version %s", policyV1alpha1.SchemeGroupVersion)
+		client.informerIngressBackend = dynamicInformerFactory.ForResource(policyV1alpha1.IngressBackendResource).Informer()
+		client.cacheIngressBackend = client.informerIngressBackend.GetStore()
+
+		ingressBackendEventTypes := k8s.EventTypes{
+			Add:    announcements.IngressBackendAdded,
+			Update: announcements.IngressBackendUpdated,
+			Delete: announcements.IngressBackendDeleted,
+		}
+		client.informerIngressBackend.AddEventHandler(k8s.GetKubernetesEventHandlers("IngressBackend", "OSM", shouldObserve, ingressBackendEventTypes))
+		informersToSync = append(informersToSync, client.informerIngressBackend)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching IngressBackend resources", policyV1alpha1.SchemeGroupVersion)
+	}
+
+	// The Gateway API CRDs are optional, HTTPRoutes are only programmed when both HTTPRoute and Gateway resources are
+	// served as a route receives traffic once bound to a Gateway
+	if httpRouteSupported && gatewaySupported {
+		log.Info().Msgf("Watching HTTPRoute and Gateway resources with API version %s", gatewayV1alpha1.SchemeGroupVersion)
+		client.informerHTTPRoute = dynamicInformerFactory.ForResource(gatewayV1alpha1.HTTPRouteResource).Informer()
+		client.cacheHTTPRoute = client.informerHTTPRoute.GetStore()
+		client.informerGateway = dynamicInformerFactory.ForResource(gatewayV1alpha1.GatewayResource).Informer()
+		client.cacheGateway = client.informerGateway.GetStore()
+
+		httpRouteEventTypes := k8s.EventTypes{
+			Add:    announcements.GatewayHTTPRouteAdded,
+			Update: announcements.GatewayHTTPRouteUpdated,
+			Delete: announcements.GatewayHTTPRouteDeleted,
+		}
+		client.informerHTTPRoute.AddEventHandler(k8s.GetKubernetesEventHandlers("HTTPRoute", "Gateway API", shouldObserve, httpRouteEventTypes))
 
-	if err := client.run(stop); err != nil {
+		// Gateways bind the routes of monitored namespaces and are commonly deployed in the namespace of the ingress
+		// controller, which is not necessarily monitored
+		gatewayEventTypes := k8s.EventTypes{
+			Add:    announcements.GatewayAdded,
+			Update: announcements.GatewayUpdated,
+			Delete: announcements.GatewayDeleted,
+		}
+		client.informerGateway.AddEventHandler(k8s.GetKubernetesEventHandlers("Gateway", "Gateway API", nil, gatewayEventTypes))
+		informersToSync = append(informersToSync, client.informerHTTPRoute, client.informerGateway)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching HTTPRoute and Gateway resources", gatewayV1alpha1.SchemeGroupVersion)
+	}
+
+	if err := client.run(stop, informersToSync...); err != nil {
 		log.Error().Err(err).Msg("Could not start Kubernetes Ingress client")
 		return nil, err
 	}
@@ -47,16 +140,21 @@ func NewIngressClient(kubeClient kubernetes.Interface, kubeController k8s.Contro
 }
 
 // run executes informer collection.
-func (c *Client) run(stop <-chan struct{}) error {
+func (c *Client) run(stop <-chan struct{}, informers ...cache.SharedIndexInformer) error {
 	log.Info().Msg("Ingress client started")
 
-	if c.informer == nil {
-		return errInitInformers
+	var hasSynced []cache.InformerSynced
+	for _, informer := range informers {
+		if informer == nil {
+			return errInitInformers
+		}
+
+		go informer.Run(stop)
+		hasSynced = append(hasSynced, informer.HasSynced)
 	}
 
-	go c.informer.Run(stop)
 	log.Info().Msgf("Waiting for Ingress informer cache sync")
-	if !cache.WaitForCacheSync(stop, c.informer.HasSynced) {
+	if !cache.WaitForCacheSync(stop, hasSynced...) {
 		return errSyncingCaches
 	}
 
@@ -67,10 +165,16 @@ func (c *Client) run(stop <-chan struct{}) error {
 	return nil
 }
 
-// GetIngressResources returns the ingress resources whose backends correspond to the service
-func (c Client) GetIngressResources(meshService service.MeshService) ([]*networkingV1beta1.Ingress, error) {
+// ListIngressesNetworkingV1beta1 returns the networking.k8s.io/v1beta1 ingress resources of the monitored namespaces
+// OSM programs ingress policies for
+func (c Client) ListIngressesNetworkingV1beta1() []*networkingV1beta1.Ingress {
+	if c.cacheV1beta1 == nil {
+		// Ingress resources are watched with the networking.k8s.io/v1 API version
+		return nil
+	}
+
 	var ingressResources []*networkingV1beta1.Ingress
-	for _, ingressInterface := range c.cache.List() {
+	for _, ingressInterface := range c.cacheV1beta1.List() {
 		ingress, ok := ingressInterface.(*networkingV1beta1.Ingress)
 		if !ok {
 			log.Error().Msg("Failed type assertion for Ingress in ingress cache")
@@ -81,7 +185,19 @@ func (c Client) GetIngressResources(meshService service.MeshService) ([]*network
 		if !c.kubeController.IsMonitoredNamespace(ingress.Namespace) {
 			continue
 		}
+		if !matchesIngressClass(ingress.Spec.IngressClassName, ingress.Annotations, c.cfg.GetIngressClass()) {
+			// The ingress resource is handled by an ingress controller other than the one OSM programs policies for
+			continue
+		}
+		ingressResources = append(ingressResources, ingress)
+	}
+	return ingressResources
+}
 
+// GetIngressNetworkingV1beta1 returns the networking.k8s.io/v1beta1 ingress resources whose backends correspond to the service
+func (c Client) GetIngressNetworkingV1beta1(meshService service.MeshService) ([]*networkingV1beta1.Ingress, error) {
+	var ingressResources []*networkingV1beta1.Ingress
+	for _, ingress := range c.ListIngressesNetworkingV1beta1() {
 		// Check if the ingress resource belongs to the same namespace as the service
 		if ingress.Namespace != meshService.Namespace {
 			// The ingress resource does not belong to the namespace of the service
@@ -95,6 +211,9 @@ func (c Client) GetIngressResources(meshService service.MeshService) ([]*network
 
 	ingressRule:
 		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
 			for _, path := range rule.HTTP.Paths {
 				if path.Backend.ServiceName == meshService.Name {
 					ingressResources = append(ingressResources, ingress)
@@ -105,3 +224,270 @@ func (c Client) GetIngressResources(meshService service.MeshService) ([]*network
 	}
 	return ingressResources, nil
 }
+
+// ListIngressesNetworkingV1 returns the networking.k8s.io/v1 ingress resources of the monitored namespaces OSM
+// programs ingress policies for
+func (c Client) ListIngressesNetworkingV1() []*networkingV1.Ingress {
+	if c.cacheV1 == nil {
+		// Ingress resources are watched with the networking.k8s.io/v1beta1 API version
+		return nil
+	}
+
+	var ingressResources []*networkingV1.Ingress
+	for _, ingressInterface := range c.cacheV1.List() {
+		ingress, ok := ingressInterface.(*networkingV1.Ingress)
+		if !ok {
+			log.Error().Msg("Failed type assertion for Ingress in ingress cache")
+			continue
+		}
+
+		// Extra safety - make sure we do not pay attention to Ingresses outside of observed namespaces
+		if !c.kubeController.IsMonitoredNamespace(ingress.Namespace) {
+			continue
+		}
+		if !matchesIngressClass(ingress.Spec.IngressClassName, ingress.Annotations, c.cfg.GetIngressClass()) {
+			// The ingress resource is handled by an ingress controller other than the one OSM programs policies for
+			continue
+		}
+		ingressResources = append(ingressResources, ingress)
+	}
+	return ingressResources
+}
+
+// GetIngressNetworkingV1 returns the networking.k8s.io/v1 ingress resources whose backends correspond to the service
+func (c Client) GetIngressNetworkingV1(meshService service.MeshService) ([]*networkingV1.Ingress, error) {
+	var ingressResources []*networkingV1.Ingress
+	for _, ingress := range c.ListIngressesNetworkingV1() {
+		// Check if the ingress resource belongs to the same namespace as the service
+		if ingress.Namespace != meshService.Namespace {
+			// The ingress resource does not belong to the namespace of the service
+			continue
+		}
+		if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil && backend.Service.Name == meshService.Name {
+			// Default backend service
+			ingressResources = append(ingressResources, ingress)
+			continue
+		}
+
+	ingressRule:
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				// A backend may reference a resource other than a service, such as a storage bucket
+				if path.Backend.Service != nil && path.Backend.Service.Name == meshService.Name {
+					ingressResources = append(ingressResources, ingress)
+					break ingressRule
+				}
+			}
+		}
+	}
+	return ingressResources, nil
+}
+
+// GetIngressBackend returns the IngressBackend resource listing the service as a backend, or nil if there is none.
+// When multiple IngressBackend resources list the service, the first one by name is returned.
+func (c Client) GetIngressBackend(meshService service.MeshService) (*policyV1alpha1.IngressBackend, error) {
+	if c.cacheIngressBackend == nil {
+		// The IngressBackend API is not served
+		return nil, nil
+	}
+
+	var ingressBackends []*policyV1alpha1.IngressBackend
+	for _, ingressBackendInterface := range c.cacheIngressBackend.List() {
+		unstructuredIngressBackend, ok := ingressBackendInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for IngressBackend in IngressBackend cache")
+			continue
+		}
+		if unstructuredIngressBackend.GetNamespace() != meshService.Namespace || !c.kubeController.IsMonitoredNamespace(meshService.Namespace) {
+			continue
+		}
+
+		ingressBackend := &policyV1alpha1.IngressBackend{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredIngressBackend.UnstructuredContent(), ingressBackend); err != nil {
+			log.Error().Err(err).Msgf("Error converting IngressBackend %s/%s", unstructuredIngressBackend.GetNamespace(), unstructuredIngressBackend.GetName())
+			continue
+		}
+
+		for _, backend := range ingressBackend.Spec.Backends {
+			if backend.Name == meshService.Name {
+				ingressBackends = append(ingressBackends, ingressBackend)
+				break
+			}
+		}
+	}
+
+	if len(ingressBackends) == 0 {
+		return nil, nil
+	}
+	sort.Slice(ingressBackends, func(i, j int) bool {
+		return ingressBackends[i].Name < ingressBackends[j].Name
+	})
+	if len(ingressBackends) > 1 {
+		log.Warn().Msgf("Found %d IngressBackend resources for service %s, applying IngressBackend %s/%s",
+			len(ingressBackends), meshService, ingressBackends[0].Namespace, ingressBackends[0].Name)
+	}
+	return ingressBackends[0], nil
+}
+
+// GetHTTPRoutes returns the Gateway API HTTPRoute resources in the namespace of the service forwarding requests to the
+// service, sorted by name. Only the routes bound to a Gateway are returned, as the routes not bound to any Gateway
+// do not receive traffic.
+func (c Client) GetHTTPRoutes(meshService service.MeshService) ([]*gatewayV1alpha1.HTTPRoute, error) {
+	if c.cacheHTTPRoute == nil || c.cacheGateway == nil {
+		// The Gateway API is not served
+		return nil, nil
+	}
+	if !c.kubeController.IsMonitoredNamespace(meshService.Namespace) {
+		return nil, nil
+	}
+
+	var gateways []*gatewayV1alpha1.Gateway
+	for _, gatewayInterface := range c.cacheGateway.List() {
+		unstructuredGateway, ok := gatewayInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for Gateway in Gateway cache")
+			continue
+		}
+
+		gateway := &gatewayV1alpha1.Gateway{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredGateway.UnstructuredContent(), gateway); err != nil {
+			log.Error().Err(err).Msgf("Error converting Gateway %s/%s", unstructuredGateway.GetNamespace(), unstructuredGateway.GetName())
+			continue
+		}
+		gateways = append(gateways, gateway)
+	}
+
+	var httpRoutes []*gatewayV1alpha1.HTTPRoute
+	for _, httpRouteInterface := range c.cacheHTTPRoute.List() {
+		unstructuredHTTPRoute, ok := httpRouteInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for HTTPRoute in HTTPRoute cache")
+			continue
+		}
+		if unstructuredHTTPRoute.GetNamespace() != meshService.Namespace {
+			continue
+		}
+
+		httpRoute := &gatewayV1alpha1.HTTPRoute{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredHTTPRoute.UnstructuredContent(), httpRoute); err != nil {
+			log.Error().Err(err).Msgf("Error converting HTTPRoute %s/%s", unstructuredHTTPRoute.GetNamespace(), unstructuredHTTPRoute.GetName())
+			continue
+		}
+		if !forwardsToService(httpRoute, meshService) {
+			continue
+		}
+
+		for _, gateway := range gateways {
+			if routeAllowsGateway(httpRoute, gateway) && c.gatewaySelectsRoute(gateway, httpRoute) {
+				httpRoutes = append(httpRoutes, httpRoute)
+				break
+			}
+		}
+	}
+
+	sort.Slice(httpRoutes, func(i, j int) bool {
+		return httpRoutes[i].Name < httpRoutes[j].Name
+	})
+	return httpRoutes, nil
+}
+
+// forwardsToService returns true if a rule of the given HTTPRoute forwards requests to the service
+func forwardsToService(httpRoute *gatewayV1alpha1.HTTPRoute, meshService service.MeshService) bool {
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, forwardTo := range rule.ForwardTo {
+			if forwardTo.ServiceName != nil && *forwardTo.ServiceName == meshService.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routeAllowsGateway returns true if the given HTTPRoute may be bound to the given Gateway, which by default is only
+// the case for the Gateways in the namespace of the route
+func routeAllowsGateway(httpRoute *gatewayV1alpha1.HTTPRoute, gateway *gatewayV1alpha1.Gateway) bool {
+	allow := gatewayV1alpha1.GatewayAllowSameNamespace
+	if httpRoute.Spec.Gateways != nil && httpRoute.Spec.Gateways.Allow != nil {
+		allow = *httpRoute.Spec.Gateways.Allow
+	}
+
+	switch allow {
+	case gatewayV1alpha1.GatewayAllowAll:
+		return true
+	case gatewayV1alpha1.GatewayAllowFromList:
+		for _, gatewayRef := range httpRoute.Spec.Gateways.GatewayRefs {
+			if gatewayRef.Name == gateway.Name && gatewayRef.Namespace == gateway.Namespace {
+				return true
+			}
+		}
+		return false
+	case gatewayV1alpha1.GatewayAllowSameNamespace:
+		return httpRoute.Namespace == gateway.Namespace
+	default:
+		log.Error().Msgf("Ignoring HTTPRoute %s/%s, unsupported gateways.allow value %s", httpRoute.Namespace, httpRoute.Name, allow)
+		return false
+	}
+}
+
+// gatewaySelectsRoute returns true if a listener of the given Gateway selects the given HTTPRoute
+func (c Client) gatewaySelectsRoute(gateway *gatewayV1alpha1.Gateway, httpRoute *gatewayV1alpha1.HTTPRoute) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		routes := listener.Routes
+		if routes.Kind != gatewayV1alpha1.HTTPRouteKind || (routes.Group != nil && *routes.Group != gatewayV1alpha1.SchemeGroupVersion.Group) {
+			continue
+		}
+
+		from := gatewayV1alpha1.RouteSelectSame
+		if routes.Namespaces != nil && routes.Namespaces.From != nil {
+			from = *routes.Namespaces.From
+		}
+		switch from {
+		case gatewayV1alpha1.RouteSelectAll:
+		case gatewayV1alpha1.RouteSelectSame:
+			if httpRoute.Namespace != gateway.Namespace {
+				continue
+			}
+		case gatewayV1alpha1.RouteSelectSelector:
+			namespace := c.kubeController.GetNamespace(httpRoute.Namespace)
+			if namespace == nil || !matchesLabelSelector(routes.Namespaces.Selector, namespace.Labels) {
+				continue
+			}
+		default:
+			log.Error().Msgf("Ignoring listener of Gateway %s/%s, unsupported routes.namespaces.from value %s", gateway.Namespace, gateway.Name, from)
+			continue
+		}
+
+		if routes.Selector == nil || matchesLabelSelector(routes.Selector, httpRoute.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLabelSelector returns true if the given labels match the given label selector, an invalid selector matching
+// no labels
+func matchesLabelSelector(labelSelector *metav1.LabelSelector, objectLabels map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid label selector")
+		return false
+	}
+	return selector.Matches(labels.Set(objectLabels))
+}
+
+// matchesIngressClass returns true if an ingress resource with the given class name and annotations belongs to the
+// configured ingress class. The spec.ingressClassName field takes precedence over the legacy ingress class annotation.
+// All ingress resources match when no ingress class is configured, and ingress resources without a class do not match
+// a configured ingress class.
+func matchesIngressClass(ingressClassName *string, annotations map[string]string, ingressClass string) bool {
+	if ingressClass == "" {
+		return true
+	}
+	if ingressClassName != nil {
+		return *ingressClassName == ingressClass
+	}
+	return annotations[ingressClassAnnotation] == ingressClass
+}
@@ -0,0 +1,323 @@
+package ingress
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	gatewayV1alpha1 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestMatchesIngressClass(t *testing.T) {
+	nginx := "nginx"
+	traefik := "traefik"
+
+	testCases := []struct {
+		name             string
+		ingressClassName *string
+		annotations      map[string]string
+		ingressClass     string
+		expected         bool
+	}{
+		{
+			name:         "no ingress class configured matches ingress resources without a class",
+			ingressClass: "",
+			expected:     true,
+		},
+		{
+			name:             "no ingress class configured matches ingress resources with a class",
+			ingressClassName: &traefik,
+			ingressClass:     "",
+			expected:         true,
+		},
+		{
+			name:             "ingress class name matches the configured ingress class",
+			ingressClassName: &nginx,
+			ingressClass:     "nginx",
+			expected:         true,
+		},
+		{
+			name:             "ingress class name does not match the configured ingress class",
+			ingressClassName: &traefik,
+			ingressClass:     "nginx",
+			expected:         false,
+		},
+		{
+			name:         "ingress class annotation matches the configured ingress class",
+			annotations:  map[string]string{ingressClassAnnotation: "nginx"},
+			ingressClass: "nginx",
+			expected:     true,
+		},
+		{
+			name:         "ingress class annotation does not match the configured ingress class",
+			annotations:  map[string]string{ingressClassAnnotation: "traefik"},
+			ingressClass: "nginx",
+			expected:     false,
+		},
+		{
+			name:             "ingress class name takes precedence over the ingress class annotation",
+			ingressClassName: &traefik,
+			annotations:      map[string]string{ingressClassAnnotation: "nginx"},
+			ingressClass:     "nginx",
+			expected:         false,
+		},
+		{
+			name:         "ingress resources without a class do not match the configured ingress class",
+			ingressClass: "nginx",
+			expected:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, matchesIngressClass(tc.ingressClassName, tc.annotations, tc.ingressClass))
+		})
+	}
+}
+
+func TestGetIngressNetworkingV1IngressClass(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(true).AnyTimes()
+	mockConfigurator.EXPECT().GetIngressClass().Return("nginx").AnyTimes()
+
+	nginx := "nginx"
+	traefik := "traefik"
+	meshService := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	newIngress := func(name string, ingressClassName *string) *networkingV1.Ingress {
+		return &networkingV1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: meshService.Namespace},
+			Spec: networkingV1.IngressSpec{
+				IngressClassName: ingressClassName,
+				DefaultBackend: &networkingV1.IngressBackend{
+					Service: &networkingV1.IngressServiceBackend{Name: meshService.Name},
+				},
+			},
+		}
+	}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newIngress("nginx-ingress", &nginx)))
+	assert.Nil(store.Add(newIngress("traefik-ingress", &traefik)))
+	assert.Nil(store.Add(newIngress("classless-ingress", nil)))
+
+	client := Client{
+		cacheV1:        store,
+		kubeController: mockKubeController,
+		cfg:            mockConfigurator,
+	}
+
+	ingresses, err := client.GetIngressNetworkingV1(meshService)
+	assert.Nil(err)
+	assert.Len(ingresses, 1)
+	assert.Equal("nginx-ingress", ingresses[0].Name)
+}
+
+func TestGetIngressBackend(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(true).AnyTimes()
+
+	newIngressBackend := func(name, namespace string, backends ...string) *unstructured.Unstructured {
+		var backendSpecs []interface{}
+		for _, backend := range backends {
+			backendSpecs = append(backendSpecs, map[string]interface{}{
+				"name": backend,
+				"port": map[string]interface{}{"number": int64(80), "protocol": policyV1alpha1.ProtocolHTTP},
+			})
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": policyV1alpha1.SchemeGroupVersion.String(),
+			"kind":       policyV1alpha1.IngressBackendKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"backends": backendSpecs,
+				"sources": []interface{}{
+					map[string]interface{}{"kind": policyV1alpha1.KindIPRange, "name": "10.0.0.0/8"},
+				},
+			},
+		}}
+	}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newIngressBackend("b", "bookstore-ns", "bookstore")))
+	assert.Nil(store.Add(newIngressBackend("a", "bookstore-ns", "bookstore-v2", "bookstore")))
+	assert.Nil(store.Add(newIngressBackend("c", "other-ns", "bookstore")))
+
+	client := Client{
+		cacheIngressBackend: store,
+		kubeController:      mockKubeController,
+	}
+
+	// The first IngressBackend by name in the namespace of the service listing it is returned
+	ingressBackend, err := client.GetIngressBackend(service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"})
+	assert.Nil(err)
+	assert.NotNil(ingressBackend)
+	assert.Equal("a", ingressBackend.Name)
+	assert.Equal([]policyV1alpha1.BackendSpec{
+		{Name: "bookstore-v2", Port: policyV1alpha1.PortSpec{Number: 80, Protocol: policyV1alpha1.ProtocolHTTP}},
+		{Name: "bookstore", Port: policyV1alpha1.PortSpec{Number: 80, Protocol: policyV1alpha1.ProtocolHTTP}},
+	}, ingressBackend.Spec.Backends)
+	assert.Equal([]policyV1alpha1.IngressSourceSpec{{Kind: policyV1alpha1.KindIPRange, Name: "10.0.0.0/8"}}, ingressBackend.Spec.Sources)
+
+	ingressBackend, err = client.GetIngressBackend(service.MeshService{Name: "bookbuyer", Namespace: "bookstore-ns"})
+	assert.Nil(err)
+	assert.Nil(ingressBackend)
+
+	// No IngressBackend is returned when the IngressBackend API is not served
+	ingressBackend, err = Client{kubeController: mockKubeController}.GetIngressBackend(service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"})
+	assert.Nil(err)
+	assert.Nil(ingressBackend)
+}
+
+func TestGetHTTPRoutes(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(true).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace("bookstore-ns").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore-ns", Labels: map[string]string{"gateway": "shared"}},
+	}).AnyTimes()
+
+	newHTTPRoute := func(name, namespace string, gateways map[string]interface{}, backends ...string) *unstructured.Unstructured {
+		var forwardTo []interface{}
+		for _, backend := range backends {
+			forwardTo = append(forwardTo, map[string]interface{}{"serviceName": backend, "port": int64(80)})
+		}
+		spec := map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"forwardTo": forwardTo},
+			},
+		}
+		if gateways != nil {
+			spec["gateways"] = gateways
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": gatewayV1alpha1.SchemeGroupVersion.String(),
+			"kind":       gatewayV1alpha1.HTTPRouteKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec":       spec,
+		}}
+	}
+	newGateway := func(name, namespace string, namespaces map[string]interface{}) *unstructured.Unstructured {
+		routes := map[string]interface{}{"kind": gatewayV1alpha1.HTTPRouteKind}
+		if namespaces != nil {
+			routes["namespaces"] = namespaces
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": gatewayV1alpha1.SchemeGroupVersion.String(),
+			"kind":       gatewayV1alpha1.GatewayKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"gatewayClassName": "acme",
+				"listeners": []interface{}{
+					map[string]interface{}{"port": int64(80), "protocol": "HTTP", "routes": routes},
+				},
+			},
+		}}
+	}
+	fromList := map[string]interface{}{
+		"allow":       gatewayV1alpha1.GatewayAllowFromList,
+		"gatewayRefs": []interface{}{map[string]interface{}{"name": "shared", "namespace": "gateway-ns"}},
+	}
+
+	testCases := []struct {
+		name               string
+		httpRoutes         []*unstructured.Unstructured
+		gateways           []*unstructured.Unstructured
+		expectedHTTPRoutes []string
+	}{
+		{
+			name: "routes forwarding to the service bound to a Gateway in their namespace",
+			httpRoutes: []*unstructured.Unstructured{
+				newHTTPRoute("b", "bookstore-ns", nil, "bookstore"),
+				newHTTPRoute("a", "bookstore-ns", nil, "bookstore-v2", "bookstore"),
+				newHTTPRoute("c", "bookstore-ns", nil, "bookbuyer"),
+				newHTTPRoute("d", "other-ns", nil, "bookstore"),
+			},
+			gateways:           []*unstructured.Unstructured{newGateway("local", "bookstore-ns", nil)},
+			expectedHTTPRoutes: []string{"a", "b"},
+		},
+		{
+			name:               "route not bound to any Gateway",
+			httpRoutes:         []*unstructured.Unstructured{newHTTPRoute("a", "bookstore-ns", nil, "bookstore")},
+			gateways:           []*unstructured.Unstructured{newGateway("shared", "gateway-ns", map[string]interface{}{"from": gatewayV1alpha1.RouteSelectAll})},
+			expectedHTTPRoutes: nil,
+		},
+		{
+			name:               "route allowing a Gateway in another namespace selecting routes in all namespaces",
+			httpRoutes:         []*unstructured.Unstructured{newHTTPRoute("a", "bookstore-ns", fromList, "bookstore")},
+			gateways:           []*unstructured.Unstructured{newGateway("shared", "gateway-ns", map[string]interface{}{"from": gatewayV1alpha1.RouteSelectAll})},
+			expectedHTTPRoutes: []string{"a"},
+		},
+		{
+			name:       "route allowing a Gateway in another namespace selecting routes by namespace labels",
+			httpRoutes: []*unstructured.Unstructured{newHTTPRoute("a", "bookstore-ns", fromList, "bookstore")},
+			gateways: []*unstructured.Unstructured{newGateway("shared", "gateway-ns", map[string]interface{}{
+				"from":     gatewayV1alpha1.RouteSelectSelector,
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"gateway": "shared"}},
+			})},
+			expectedHTTPRoutes: []string{"a"},
+		},
+		{
+			name:               "route allowing a Gateway in another namespace selecting routes in its namespace",
+			httpRoutes:         []*unstructured.Unstructured{newHTTPRoute("a", "bookstore-ns", fromList, "bookstore")},
+			gateways:           []*unstructured.Unstructured{newGateway("shared", "gateway-ns", nil)},
+			expectedHTTPRoutes: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			httpRouteStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			for _, httpRoute := range tc.httpRoutes {
+				assert.Nil(httpRouteStore.Add(httpRoute))
+			}
+			gatewayStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			for _, gateway := range tc.gateways {
+				assert.Nil(gatewayStore.Add(gateway))
+			}
+
+			client := Client{
+				cacheHTTPRoute: httpRouteStore,
+				cacheGateway:   gatewayStore,
+				kubeController: mockKubeController,
+			}
+
+			httpRoutes, err := client.GetHTTPRoutes(service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"})
+			assert.Nil(err)
+			var names []string
+			for _, httpRoute := range httpRoutes {
+				names = append(names, httpRoute.Name)
+			}
+			assert.Equal(tc.expectedHTTPRoutes, names)
+		})
+	}
+
+	// No HTTPRoute is returned when the Gateway API is not served
+	httpRoutes, err := Client{kubeController: mockKubeController}.GetHTTPRoutes(service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"})
+	assert.Nil(err)
+	assert.Nil(httpRoutes)
+}
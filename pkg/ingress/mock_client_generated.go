@@ -8,7 +8,10 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1alpha10 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	v1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	service "github.com/openservicemesh/osm/pkg/service"
+	v1 "k8s.io/api/networking/v1"
 	v1beta1 "k8s.io/api/networking/v1beta1"
 )
 
@@ -35,17 +38,90 @@ func (m *MockMonitor) EXPECT() *MockMonitorMockRecorder {
 	return m.recorder
 }
 
-// GetIngressResources mocks base method
-func (m *MockMonitor) GetIngressResources(arg0 service.MeshService) ([]*v1beta1.Ingress, error) {
+// GetHTTPRoutes mocks base method
+func (m *MockMonitor) GetHTTPRoutes(arg0 service.MeshService) ([]*v1alpha10.HTTPRoute, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIngressResources", arg0)
+	ret := m.ctrl.Call(m, "GetHTTPRoutes", arg0)
+	ret0, _ := ret[0].([]*v1alpha10.HTTPRoute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHTTPRoutes indicates an expected call of GetHTTPRoutes
+func (mr *MockMonitorMockRecorder) GetHTTPRoutes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHTTPRoutes", reflect.TypeOf((*MockMonitor)(nil).GetHTTPRoutes), arg0)
+}
+
+// GetIngressBackend mocks base method
+func (m *MockMonitor) GetIngressBackend(arg0 service.MeshService) (*v1alpha1.IngressBackend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressBackend", arg0)
+	ret0, _ := ret[0].(*v1alpha1.IngressBackend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngressBackend indicates an expected call of GetIngressBackend
+func (mr *MockMonitorMockRecorder) GetIngressBackend(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressBackend", reflect.TypeOf((*MockMonitor)(nil).GetIngressBackend), arg0)
+}
+
+// GetIngressNetworkingV1 mocks base method
+func (m *MockMonitor) GetIngressNetworkingV1(arg0 service.MeshService) ([]*v1.Ingress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressNetworkingV1", arg0)
+	ret0, _ := ret[0].([]*v1.Ingress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngressNetworkingV1 indicates an expected call of GetIngressNetworkingV1
+func (mr *MockMonitorMockRecorder) GetIngressNetworkingV1(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressNetworkingV1", reflect.TypeOf((*MockMonitor)(nil).GetIngressNetworkingV1), arg0)
+}
+
+// GetIngressNetworkingV1beta1 mocks base method
+func (m *MockMonitor) GetIngressNetworkingV1beta1(arg0 service.MeshService) ([]*v1beta1.Ingress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressNetworkingV1beta1", arg0)
 	ret0, _ := ret[0].([]*v1beta1.Ingress)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIngressResources indicates an expected call of GetIngressResources
-func (mr *MockMonitorMockRecorder) GetIngressResources(arg0 interface{}) *gomock.Call {
+// GetIngressNetworkingV1beta1 indicates an expected call of GetIngressNetworkingV1beta1
+func (mr *MockMonitorMockRecorder) GetIngressNetworkingV1beta1(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressNetworkingV1beta1", reflect.TypeOf((*MockMonitor)(nil).GetIngressNetworkingV1beta1), arg0)
+}
+
+// ListIngressesNetworkingV1 mocks base method
+func (m *MockMonitor) ListIngressesNetworkingV1() []*v1.Ingress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngressesNetworkingV1")
+	ret0, _ := ret[0].([]*v1.Ingress)
+	return ret0
+}
+
+// ListIngressesNetworkingV1 indicates an expected call of ListIngressesNetworkingV1
+func (mr *MockMonitorMockRecorder) ListIngressesNetworkingV1() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngressesNetworkingV1", reflect.TypeOf((*MockMonitor)(nil).ListIngressesNetworkingV1))
+}
+
+// ListIngressesNetworkingV1beta1 mocks base method
+func (m *MockMonitor) ListIngressesNetworkingV1beta1() []*v1beta1.Ingress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngressesNetworkingV1beta1")
+	ret0, _ := ret[0].([]*v1beta1.Ingress)
+	return ret0
+}
+
+// ListIngressesNetworkingV1beta1 indicates an expected call of ListIngressesNetworkingV1beta1
+func (mr *MockMonitorMockRecorder) ListIngressesNetworkingV1beta1() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressResources", reflect.TypeOf((*MockMonitor)(nil).GetIngressResources), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngressesNetworkingV1beta1", reflect.TypeOf((*MockMonitor)(nil).ListIngressesNetworkingV1beta1))
 }
@@ -1,10 +1,14 @@
-// Package ingress implements functionality to monitor and retrieve Kubernetes Ingress resources.
+// Package ingress implements functionality to monitor and retrieve Kubernetes Ingress and Gateway API resources.
 package ingress
 
 import (
+	networkingV1 "k8s.io/api/networking/v1"
 	networkingV1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/client-go/tools/cache"
 
+	gatewayV1alpha1 "github.com/openservicemesh/osm/pkg/apis/gateway/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -14,16 +18,50 @@ var (
 	log = logger.New("kube-ingress")
 )
 
+// ingressClassAnnotation is the annotation used to specify the class of an ingress resource before the
+// spec.ingressClassName field was introduced, which is still used by some ingress controllers
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
 // Client is a struct for all components necessary to connect to and maintain state of a Kubernetes cluster.
 type Client struct {
-	informer       cache.SharedIndexInformer
-	cache          cache.Store
-	cacheSynced    chan interface{}
-	kubeController k8s.Controller
+	// Only one of the networking.k8s.io/v1 and networking.k8s.io/v1beta1 informers is initialized, as both API
+	// versions serve the same ingress resources
+	informerV1      cache.SharedIndexInformer
+	cacheV1         cache.Store
+	informerV1beta1 cache.SharedIndexInformer
+	cacheV1beta1    cache.Store
+	cacheSynced     chan interface{}
+	kubeController  k8s.Controller
+	cfg             configurator.Configurator
+
+	// The IngressBackend informer is only initialized when the IngressBackend API is served
+	informerIngressBackend cache.SharedIndexInformer
+	cacheIngressBackend    cache.Store
+
+	// The HTTPRoute and Gateway informers are only initialized when the Gateway API is served
+	informerHTTPRoute cache.SharedIndexInformer
+	cacheHTTPRoute    cache.Store
+	informerGateway   cache.SharedIndexInformer
+	cacheGateway      cache.Store
 }
 
 // Monitor is the client interface for K8s Ingress resource
 type Monitor interface {
-	// GetIngressResources returns the ingress resources whose backends correspond to the service
-	GetIngressResources(service.MeshService) ([]*networkingV1beta1.Ingress, error)
+	// GetIngressNetworkingV1beta1 returns the networking.k8s.io/v1beta1 ingress resources whose backends correspond to the service
+	GetIngressNetworkingV1beta1(service.MeshService) ([]*networkingV1beta1.Ingress, error)
+
+	// GetIngressNetworkingV1 returns the networking.k8s.io/v1 ingress resources whose backends correspond to the service
+	GetIngressNetworkingV1(service.MeshService) ([]*networkingV1.Ingress, error)
+
+	// ListIngressesNetworkingV1beta1 returns the networking.k8s.io/v1beta1 ingress resources OSM programs ingress policies for
+	ListIngressesNetworkingV1beta1() []*networkingV1beta1.Ingress
+
+	// ListIngressesNetworkingV1 returns the networking.k8s.io/v1 ingress resources OSM programs ingress policies for
+	ListIngressesNetworkingV1() []*networkingV1.Ingress
+
+	// GetIngressBackend returns the IngressBackend resource listing the service as a backend, or nil if there is none
+	GetIngressBackend(service.MeshService) (*policyV1alpha1.IngressBackend, error)
+
+	// GetHTTPRoutes returns the Gateway API HTTPRoute resources bound to a Gateway whose backends correspond to the service
+	GetHTTPRoutes(service.MeshService) ([]*gatewayV1alpha1.HTTPRoute, error)
 }
@@ -0,0 +1,26 @@
+package injector
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// createEgressGatewayBootstrapConfig creates the Envoy bootstrap config secret mounted by the egress gateway.
+// The egress gateway replicas are pods of a Deployment, so they all share this bootstrap config and the xDS certificate in it.
+func (wh *mutatingWebhook) createEgressGatewayBootstrapConfig() error {
+	cn := catalog.NewCertCommonNameWithProxyID(uuid.New(), constants.EgressGatewayName, wh.osmNamespace)
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for egress gateway with CN=%s", cn)
+		return err
+	}
+
+	if _, err := wh.createEnvoyBootstrapConfig(constants.EgressGatewayBootstrapSecretName, wh.osmNamespace, wh.osmNamespace, bootstrapCertificate, healthProbes{}, constants.TrafficPriorityNormal); err != nil {
+		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for egress gateway with certificate CN=%s", cn)
+		return err
+	}
+
+	return nil
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/route"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
@@ -53,6 +56,8 @@ func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Config
 	}
 
 	m["static_resources"] = getStaticResources(config)
+	m["stats_config"] = getStatsConfig()
+	m["overload_manager"] = getOverloadManager(config.TrafficPriority)
 
 	configYAML, err := yaml.Marshal(&m)
 	if err != nil {
@@ -62,10 +67,37 @@ func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Config
 	return configYAML, err
 }
 
+// getStatsConfig returns the stats config included in the bootstrap Envoy config.
+// It tags the stats of the virtual clusters programmed per SMI TrafficTarget and TrafficSplit with the name of the policy,
+// so that the requests and errors of the proxies can be attributed to the policies routing them.
+func getStatsConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"stats_tags": []map[string]interface{}{
+			{
+				"tag_name": constants.TrafficTargetStatsTag,
+				"regex":    fmt.Sprintf("^vhost[.].*[.]vcluster[.](%s([^.]+)[.])", route.TrafficTargetStatsPrefix),
+			},
+			{
+				"tag_name": constants.TrafficSplitStatsTag,
+				"regex":    fmt.Sprintf("^vhost[.].*[.]vcluster[.](%s([^.]+)[.])", route.TrafficSplitStatsPrefix),
+			},
+			{
+				"tag_name": constants.EgressHostStatsTag,
+				"regex":    envoy.GetEgressHostStatsTagRegex(),
+			},
+			{
+				"tag_name": constants.EgressPortStatsTag,
+				"regex":    envoy.GetEgressPortStatsTagRegex(),
+			},
+		},
+	}
+}
+
 // getStaticResources returns STATIC resources included in the bootstrap Envoy config.
 // These will not change during the lifetime of the Pod.
 func getStaticResources(config envoyBootstrapConfigMeta) map[string]interface{} {
-	// This slice is the list of listeners for liveness, readiness, startup IF these have been configured in the Pod Spec
+	// This slice is the list of listeners for the lifecycle API, and for liveness, readiness, startup IF these have been
+	// configured in the Pod Spec
 	var listeners []map[string]interface{}
 
 	// There will ALWAYS be an xDS cluster
@@ -73,6 +105,10 @@ func getStaticResources(config envoyBootstrapConfigMeta) map[string]interface{}
 		getXdsCluster(config),
 	}
 
+	// There will ALWAYS be a lifecycle API for the containers of the Pod to coordinate with the proxy
+	listeners = append(listeners, getLifecycleListener())
+	clusters = append(clusters, getLifecycleAdminCluster(config.EnvoyAdminPort))
+
 	// Is there a liveness probe in the Pod Spec?
 	if config.OriginalHealthProbes.liveness != nil {
 		listeners = append(listeners, getLivenessListener(config.OriginalHealthProbes.liveness))
@@ -91,18 +127,13 @@ func getStaticResources(config envoyBootstrapConfigMeta) map[string]interface{}
 		clusters = append(clusters, getStartupCluster(config.OriginalHealthProbes.startup))
 	}
 
-	staticResources := map[string]interface{}{
-		"clusters": clusters,
-	}
-
-	if len(listeners) > 0 {
-		staticResources["listeners"] = listeners
+	return map[string]interface{}{
+		"clusters":  clusters,
+		"listeners": listeners,
 	}
-
-	return staticResources
 }
 
-func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes) (*corev1.Secret, error) {
+func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes, trafficPriority string) (*corev1.Secret, error) {
 	configMeta := envoyBootstrapConfigMeta{
 		EnvoyAdminPort: constants.EnvoyAdminPort,
 		XDSClusterName: constants.OSMControllerName,
@@ -117,6 +148,8 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamesp
 		// OriginalHealthProbes stores the path and port for liveness, readiness, and startup health probes as initially
 		// defined on the Pod Spec.
 		OriginalHealthProbes: originalHealthProbes,
+
+		TrafficPriority: trafficPriority,
 	}
 	yamlContent, err := getEnvoyConfigYAML(configMeta, wh.configurator)
 	if err != nil {
@@ -137,9 +170,12 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamesp
 			envoyBootstrapConfigFile: yamlContent,
 		},
 	}
+	k8s.SetControlPlaneGeneratedMetadata(&secret.ObjectMeta)
+
 	if existing, err := wh.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{}); err == nil {
 		log.Debug().Msgf("Updating bootstrap config Envoy: name=%s, namespace=%s", name, namespace)
 		existing.Data = secret.Data
+		k8s.SetControlPlaneGeneratedMetadata(&existing.ObjectMeta)
 		return wh.kubeClient.CoreV1().Secrets(namespace).Update(context.Background(), existing, metav1.UpdateOptions{})
 	}
 
@@ -0,0 +1,148 @@
+package injector
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	lifecycleListener     = "lifecycle_listener"
+	lifecycleAdminCluster = "lifecycle_admin_cluster"
+
+	// lifecycleQuitPath is the path of the lifecycle API shutting down the proxy, for jobs to stop their sidecar when done
+	lifecycleQuitPath = "/quitquitquit"
+
+	// lifecycleDrainPath is the path of the lifecycle API gracefully draining the inbound connections of the proxy, for
+	// applications to stop receiving requests before shutting down
+	lifecycleDrainPath = "/drain"
+
+	// lifecycleReadyPath is the path of the lifecycle API returning 200 once the proxy is ready to serve traffic, for
+	// applications to wait for the proxy before connecting to other services
+	lifecycleReadyPath = "/ready"
+)
+
+// getLifecycleAdminCluster returns the cluster of the admin interface of the proxy, to which the lifecycle API is routed
+func getLifecycleAdminCluster(adminPort int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":            lifecycleAdminCluster,
+		"connect_timeout": "1s",
+		"type":            "STATIC",
+		"lb_policy":       "ROUND_ROBIN",
+		"load_assignment": map[string]interface{}{
+			"cluster_name": lifecycleAdminCluster,
+			"endpoints": []map[string]interface{}{
+				{
+					"lb_endpoints": []map[string]interface{}{
+						{
+							"endpoint": map[string]interface{}{
+								"address": map[string]interface{}{
+									"socket_address": map[string]interface{}{
+										"address":    constants.LocalhostIPAddress,
+										"port_value": adminPort,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getLifecycleListener returns the listener serving the lifecycle API to the containers of the pod on localhost. Only
+// the lifecycle endpoints of the admin interface are exposed, and the endpoints changing the state of the proxy only
+// accept POST requests.
+func getLifecycleListener() map[string]interface{} {
+	return map[string]interface{}{
+		"name": lifecycleListener,
+		"address": map[string]interface{}{
+			"socket_address": map[string]interface{}{
+				"address":    constants.LocalhostIPAddress,
+				"port_value": constants.EnvoyLifecyclePort,
+			},
+		},
+		"filter_chains": []map[string]interface{}{
+			{
+				"filters": []map[string]interface{}{
+					{
+						"name": "envoy.filters.network.http_connection_manager",
+						"typed_config": map[string]interface{}{
+							"@type":       "type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager",
+							"stat_prefix": "lifecycle_http",
+							"access_log":  getAccessLog(),
+							"codec_type":  "AUTO",
+							"route_config": map[string]interface{}{
+								"name": "lifecycle_route",
+								"virtual_hosts": []map[string]interface{}{
+									{
+										"name": "lifecycle",
+										"domains": []string{
+											"*",
+										},
+										"routes": []map[string]interface{}{
+											getLifecycleRoute(lifecycleQuitPath, "/quitquitquit", true),
+											getLifecycleRoute(lifecycleDrainPath, "/drain_listeners?graceful&inboundonly", true),
+											getLifecycleRoute(lifecycleReadyPath, "/ready", false),
+										},
+									},
+								},
+							},
+							"http_filters": []map[string]interface{}{
+								{
+									"name": "envoy.filters.http.router",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getLifecycleRoute returns the route of the given path of the lifecycle API to the given path of the admin interface
+func getLifecycleRoute(path, adminPath string, postOnly bool) map[string]interface{} {
+	match := map[string]interface{}{
+		"path": path,
+	}
+	if postOnly {
+		match["headers"] = []map[string]interface{}{
+			{
+				"name":        ":method",
+				"exact_match": "POST",
+			},
+		}
+	}
+	return map[string]interface{}{
+		"match": match,
+		"route": map[string]interface{}{
+			"cluster":        lifecycleAdminCluster,
+			"prefix_rewrite": adminPath,
+		},
+	}
+}
+
+// setLifecycleURLEnv sets the URL of the lifecycle API of the proxy in the environment of the given containers, unless
+// already set by the containers
+func setLifecycleURLEnv(containers []corev1.Container) {
+	lifecycleURL := fmt.Sprintf("http://%s:%d", constants.LocalhostIPAddress, constants.EnvoyLifecyclePort)
+	for i := range containers {
+		isSet := false
+		for _, env := range containers[i].Env {
+			if env.Name == constants.EnvoyLifecycleURLEnvVar {
+				isSet = true
+				break
+			}
+		}
+		if !isSet {
+			containers[i].Env = append(containers[i].Env, corev1.EnvVar{
+				Name:  constants.EnvoyLifecycleURLEnvVar,
+				Value: lifecycleURL,
+			})
+		}
+	}
+}
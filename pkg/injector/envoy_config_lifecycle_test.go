@@ -0,0 +1,61 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetLifecycleRoute(t *testing.T) {
+	assert := tassert.New(t)
+
+	quit := getLifecycleRoute(lifecycleQuitPath, "/quitquitquit", true)
+	assert.Equal(map[string]interface{}{
+		"match": map[string]interface{}{
+			"path": lifecycleQuitPath,
+			"headers": []map[string]interface{}{
+				{
+					"name":        ":method",
+					"exact_match": "POST",
+				},
+			},
+		},
+		"route": map[string]interface{}{
+			"cluster":        lifecycleAdminCluster,
+			"prefix_rewrite": "/quitquitquit",
+		},
+	}, quit)
+
+	// Reading the state of the proxy does not require POST requests
+	ready := getLifecycleRoute(lifecycleReadyPath, "/ready", false)
+	assert.NotContains(ready["match"], "headers")
+}
+
+func TestSetLifecycleURLEnv(t *testing.T) {
+	assert := tassert.New(t)
+
+	containers := []corev1.Container{
+		{
+			Name: "app",
+		},
+		{
+			Name: "job-wrapper",
+			Env: []corev1.EnvVar{
+				{Name: constants.EnvoyLifecycleURLEnvVar, Value: "http://127.0.0.1:15021"},
+			},
+		},
+	}
+	setLifecycleURLEnv(containers)
+
+	assert.Equal([]corev1.EnvVar{
+		{Name: constants.EnvoyLifecycleURLEnvVar, Value: "http://127.0.0.1:15020"},
+	}, containers[0].Env)
+
+	// The URL set by a container is kept
+	assert.Equal([]corev1.EnvVar{
+		{Name: constants.EnvoyLifecycleURLEnvVar, Value: "http://127.0.0.1:15021"},
+	}, containers[1].Env)
+}
@@ -0,0 +1,127 @@
+package injector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+const (
+	// overloadMaxHeapSizeBytes is the heap size of the proxy the fixed heap resource monitor of the overload manager
+	// computes the memory pressure of the proxy against
+	overloadMaxHeapSizeBytes = 1024 * 1024 * 1024
+
+	fixedHeapResourceMonitor = "envoy.resource_monitors.fixed_heap"
+
+	shrinkHeapOverloadAction            = "envoy.overload_actions.shrink_heap"
+	disableHTTPKeepaliveOverloadAction  = "envoy.overload_actions.disable_http_keepalive"
+	stopAcceptingRequestsOverloadAction = "envoy.overload_actions.stop_accepting_requests"
+)
+
+// overloadThresholds are the memory pressures at which the overload manager of a proxy acts
+type overloadThresholds struct {
+	// disableHTTPKeepalive is the memory pressure at which the proxy closes the HTTP connections of its clients after
+	// their current requests, spreading the load to the other endpoints of the service
+	disableHTTPKeepalive float64
+
+	// stopAcceptingRequests is the memory pressure at which the proxy rejects the new requests of its clients
+	stopAcceptingRequests float64
+}
+
+// shrinkHeapThreshold is the memory pressure at which the proxy frees the unused memory of its heap, for any priority
+const shrinkHeapThreshold = 0.90
+
+// trafficPriorityOverloadThresholds are the overload thresholds of the proxies of the pods of services of each traffic
+// priority class. The proxies of best effort services shed their load first, while the proxies of critical services
+// keep serving requests until they are about to run out of memory.
+var trafficPriorityOverloadThresholds = map[string]overloadThresholds{
+	constants.TrafficPriorityCritical: {
+		disableHTTPKeepalive:  0.95,
+		stopAcceptingRequests: 0.98,
+	},
+	constants.TrafficPriorityNormal: {
+		disableHTTPKeepalive:  0.90,
+		stopAcceptingRequests: 0.95,
+	},
+	constants.TrafficPriorityBestEffort: {
+		disableHTTPKeepalive:  0.80,
+		stopAcceptingRequests: 0.90,
+	},
+}
+
+// trafficPriorityRank orders the traffic priority classes from the lowest to the highest priority
+var trafficPriorityRank = map[string]int{
+	constants.TrafficPriorityBestEffort: 0,
+	constants.TrafficPriorityNormal:     1,
+	constants.TrafficPriorityCritical:   2,
+}
+
+// getOverloadManager returns the overload manager included in the bootstrap Envoy config, shedding the load of the proxy
+// as its heap grows at the thresholds of the given traffic priority class. The thresholds of the normal priority class
+// are used for an unknown priority class.
+func getOverloadManager(priority string) map[string]interface{} {
+	thresholds, ok := trafficPriorityOverloadThresholds[priority]
+	if !ok {
+		thresholds = trafficPriorityOverloadThresholds[constants.TrafficPriorityNormal]
+	}
+
+	return map[string]interface{}{
+		"refresh_interval": "0.25s",
+		"resource_monitors": []map[string]interface{}{
+			{
+				"name": fixedHeapResourceMonitor,
+				"typed_config": map[string]interface{}{
+					"@type":               "type.googleapis.com/envoy.config.resource_monitor.fixed_heap.v2alpha.FixedHeapConfig",
+					"max_heap_size_bytes": overloadMaxHeapSizeBytes,
+				},
+			},
+		},
+		"actions": []map[string]interface{}{
+			getOverloadAction(shrinkHeapOverloadAction, shrinkHeapThreshold),
+			getOverloadAction(disableHTTPKeepaliveOverloadAction, thresholds.disableHTTPKeepalive),
+			getOverloadAction(stopAcceptingRequestsOverloadAction, thresholds.stopAcceptingRequests),
+		},
+	}
+}
+
+// getOverloadAction returns the overload action with the given name, triggered when the memory pressure reported by the
+// fixed heap resource monitor reaches the given threshold
+func getOverloadAction(name string, threshold float64) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"triggers": []map[string]interface{}{
+			{
+				"name": fixedHeapResourceMonitor,
+				"threshold": map[string]interface{}{
+					"value": threshold,
+				},
+			},
+		},
+	}
+}
+
+// getPodTrafficPriority returns the highest traffic priority class of the services in the given namespace selecting the
+// given pod, so that the proxy of a pod serving critical traffic is shed last. The normal priority class is returned if
+// no service selects the pod.
+func getPodTrafficPriority(pod *corev1.Pod, namespace string, kubeController k8s.Controller) string {
+	priority := ""
+	for _, svc := range kubeController.ListServices() {
+		if svc.Namespace != namespace || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		if !labels.Set(svc.Spec.Selector).AsSelector().Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		svcPriority := catalog.GetServiceTrafficPriority(svc)
+		if priority == "" || trafficPriorityRank[svcPriority] > trafficPriorityRank[priority] {
+			priority = svcPriority
+		}
+	}
+	if priority == "" {
+		return constants.TrafficPriorityNormal
+	}
+	return priority
+}
@@ -0,0 +1,121 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+func TestGetOverloadManager(t *testing.T) {
+	assert := tassert.New(t)
+
+	getThresholds := func(overloadManager map[string]interface{}) map[string]float64 {
+		thresholds := map[string]float64{}
+		for _, action := range overloadManager["actions"].([]map[string]interface{}) {
+			trigger := action["triggers"].([]map[string]interface{})[0]
+			assert.Equal(fixedHeapResourceMonitor, trigger["name"])
+			thresholds[action["name"].(string)] = trigger["threshold"].(map[string]interface{})["value"].(float64)
+		}
+		return thresholds
+	}
+
+	critical := getOverloadManager(constants.TrafficPriorityCritical)
+	assert.Equal([]map[string]interface{}{
+		{
+			"name": fixedHeapResourceMonitor,
+			"typed_config": map[string]interface{}{
+				"@type":               "type.googleapis.com/envoy.config.resource_monitor.fixed_heap.v2alpha.FixedHeapConfig",
+				"max_heap_size_bytes": overloadMaxHeapSizeBytes,
+			},
+		},
+	}, critical["resource_monitors"])
+	assert.Equal(map[string]float64{
+		shrinkHeapOverloadAction:            0.90,
+		disableHTTPKeepaliveOverloadAction:  0.95,
+		stopAcceptingRequestsOverloadAction: 0.98,
+	}, getThresholds(critical))
+
+	// Best effort proxies shed their load before the proxies of other priority classes
+	assert.Equal(map[string]float64{
+		shrinkHeapOverloadAction:            0.90,
+		disableHTTPKeepaliveOverloadAction:  0.80,
+		stopAcceptingRequestsOverloadAction: 0.90,
+	}, getThresholds(getOverloadManager(constants.TrafficPriorityBestEffort)))
+
+	// An unknown priority class gets the thresholds of the normal priority class
+	assert.Equal(getOverloadManager(constants.TrafficPriorityNormal), getOverloadManager(""))
+}
+
+func TestGetPodTrafficPriority(t *testing.T) {
+	newService := func(name, namespace, priority string, selector map[string]string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+			},
+		}
+		if priority != "" {
+			svc.Annotations = map[string]string{constants.TrafficPriorityAnnotation: priority}
+		}
+		return svc
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "bookstore"},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		services         []*corev1.Service
+		expectedPriority string
+	}{
+		{
+			name:             "no service selects the pod",
+			services:         []*corev1.Service{newService("bookbuyer", "default", constants.TrafficPriorityCritical, map[string]string{"app": "bookbuyer"})},
+			expectedPriority: constants.TrafficPriorityNormal,
+		},
+		{
+			name:             "service selecting the pod in another namespace",
+			services:         []*corev1.Service{newService("bookstore", "other", constants.TrafficPriorityCritical, map[string]string{"app": "bookstore"})},
+			expectedPriority: constants.TrafficPriorityNormal,
+		},
+		{
+			name:             "best effort service",
+			services:         []*corev1.Service{newService("bookstore", "default", constants.TrafficPriorityBestEffort, map[string]string{"app": "bookstore"})},
+			expectedPriority: constants.TrafficPriorityBestEffort,
+		},
+		{
+			name: "highest priority of the services selecting the pod",
+			services: []*corev1.Service{
+				newService("bookstore-batch", "default", constants.TrafficPriorityBestEffort, map[string]string{"app": "bookstore"}),
+				newService("bookstore", "default", constants.TrafficPriorityCritical, map[string]string{"app": "bookstore"}),
+				newService("bookstore-v1", "default", "", map[string]string{"app": "bookstore"}),
+			},
+			expectedPriority: constants.TrafficPriorityCritical,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().ListServices().Return(tc.services).Times(1)
+
+			assert.Equal(tc.expectedPriority, getPodTrafficPriority(pod, "default", mockKubeController))
+		})
+	}
+}
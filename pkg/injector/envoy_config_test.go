@@ -162,7 +162,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			namespace := "a"
 			osmNamespace := "b"
 
-			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes)
+			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes, constants.TrafficPriorityNormal)
 			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Secret{
@@ -179,6 +179,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					envoyBootstrapConfigFile: []byte(getExpectedEnvoyYAML(expectedEnvoyBootstrapConfigFileName)),
 				},
 			}
+			k8s.SetControlPlaneGeneratedMetadata(&expected.ObjectMeta)
 
 			// Contains only the "bootstrap.yaml" key
 			Expect(len(secret.Data)).To(Equal(1))
@@ -245,17 +246,39 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 	Context("test getEnvoySidecarContainerSpec()", func() {
 		It("creates Envoy sidecar spec", func() {
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("debug").Times(1)
-			actual := getEnvoySidecarContainerSpec(pod, envoyImage, mockConfigurator, originalHealthProbes)
-
+			mockConfigurator.EXPECT().GetProxyUID().Return(int64(2102)).Times(1)
+			mockConfigurator.EXPECT().GetProxyGID().Return(int64(2103)).Times(1)
+			mockConfigurator.EXPECT().GetProxySeccompProfile().Return(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}).Times(1)
+			mockConfigurator.EXPECT().GetProxySELinuxOptions().Return(&corev1.SELinuxOptions{Type: "container_t"}).Times(1)
+			actual := getEnvoySidecarContainerSpec(pod, nil, envoyImage, mockConfigurator, originalHealthProbes)
+
+			trueVal := true
+			falseVal := false
 			expected := corev1.Container{
 				Name:            constants.EnvoyContainerName,
 				Image:           envoyImage,
 				ImagePullPolicy: corev1.PullAlways,
 				SecurityContext: &corev1.SecurityContext{
 					RunAsUser: func() *int64 {
-						uid := constants.EnvoyUID
+						uid := int64(2102)
 						return &uid
 					}(),
+					RunAsGroup: func() *int64 {
+						gid := int64(2103)
+						return &gid
+					}(),
+					RunAsNonRoot:             &trueVal,
+					ReadOnlyRootFilesystem:   &trueVal,
+					AllowPrivilegeEscalation: &falseVal,
+					Capabilities: &corev1.Capabilities{
+						Drop: []corev1.Capability{"ALL"},
+					},
+					SeccompProfile: &corev1.SeccompProfile{
+						Type: corev1.SeccompProfileTypeRuntimeDefault,
+					},
+					SELinuxOptions: &corev1.SELinuxOptions{
+						Type: "container_t",
+					},
 				},
 				Ports: expectedRewrittenContainerPorts,
 				VolumeMounts: []corev1.VolumeMount{
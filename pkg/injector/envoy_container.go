@@ -16,7 +16,13 @@ const (
 	envoyProxyConfigPath     = "/etc/envoy"
 )
 
-func getEnvoySidecarContainerSpec(pod *corev1.Pod, envoyImage string, cfg configurator.Configurator, originalHealthProbes healthProbes) corev1.Container {
+// getEnvoyLogLevel returns the log level of the sidecar proxy of the given pod. The mesh-wide log level configured in the
+// OSM ConfigMap is overridden by the annotation of the pod's namespace, then by the annotation of the pod.
+func getEnvoyLogLevel(pod *corev1.Pod, namespace *corev1.Namespace, cfg configurator.Configurator) string {
+	return configurator.NewOverrides(namespace, pod).String(constants.EnvoyLogLevelAnnotation, cfg.GetEnvoyLogLevel(), configurator.ValidEnvoyLogLevels...)
+}
+
+func getEnvoySidecarContainerSpec(pod *corev1.Pod, namespace *corev1.Namespace, envoyImage string, cfg configurator.Configurator, originalHealthProbes healthProbes) corev1.Container {
 	// nodeID and clusterID are required for Envoy proxy to start.
 	nodeID := pod.Spec.ServiceAccountName
 	// cluster ID will be used as an identifier to the tracing sink
@@ -36,13 +42,8 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, envoyImage string, cfg config
 		Name:            constants.EnvoyContainerName,
 		Image:           envoyImage,
 		ImagePullPolicy: corev1.PullAlways,
-		SecurityContext: &corev1.SecurityContext{
-			RunAsUser: func() *int64 {
-				uid := constants.EnvoyUID
-				return &uid
-			}(),
-		},
-		Ports: getEnvoyContainerPorts(originalHealthProbes),
+		SecurityContext: getEnvoySecurityContext(cfg.GetProxyUID(), cfg.GetProxyGID(), cfg.GetProxySeccompProfile(), cfg.GetProxySELinuxOptions()),
+		Ports:           getEnvoyContainerPorts(originalHealthProbes),
 		VolumeMounts: []corev1.VolumeMount{{
 			Name:      envoyBootstrapConfigVolume,
 			ReadOnly:  true,
@@ -50,7 +51,7 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, envoyImage string, cfg config
 		}},
 		Command: []string{"envoy"},
 		Args: []string{
-			"--log-level", cfg.GetEnvoyLogLevel(),
+			"--log-level", getEnvoyLogLevel(pod, namespace, cfg),
 			"--config-path", strings.Join([]string{envoyProxyConfigPath, envoyBootstrapConfigFile}, "/"),
 			"--service-node", envoy.GetEnvoyServiceNodeID(nodeID, workloadKind, workloadName),
 			"--service-cluster", clusterID,
@@ -101,6 +102,27 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, envoyImage string, cfg config
 	}
 }
 
+// getEnvoySecurityContext returns the security context of the Envoy sidecar running as the given user and group IDs,
+// with the given seccomp profile and SELinux options. The sidecar runs as a non-root user with a read-only root
+// filesystem and no capabilities, as required by the restricted Pod Security Standard.
+func getEnvoySecurityContext(uid, gid int64, seccompProfile *corev1.SeccompProfile, seLinuxOptions *corev1.SELinuxOptions) *corev1.SecurityContext {
+	runAsNonRoot := true
+	readOnlyRootFilesystem := true
+	allowPrivilegeEscalation := false
+	return &corev1.SecurityContext{
+		RunAsUser:                &uid,
+		RunAsGroup:               &gid,
+		RunAsNonRoot:             &runAsNonRoot,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: seccompProfile,
+		SELinuxOptions: seLinuxOptions,
+	}
+}
+
 func getEnvoyContainerPorts(originalHealthProbes healthProbes) []corev1.ContainerPort {
 	containerPorts := []corev1.ContainerPort{
 		{
@@ -146,3 +168,47 @@ func getEnvoyContainerPorts(originalHealthProbes healthProbes) []corev1.Containe
 
 	return containerPorts
 }
+
+// GetSidecarImage returns the sidecar image for the given pod. When the pod is constrained to nodes of a single
+// architecture, and an image is configured for that architecture, that image is used. Otherwise the default image,
+// which is expected to be a multi-arch image, is used.
+func GetSidecarImage(pod *corev1.Pod, defaultImage string, imageByArch map[string]string) string {
+	arch := getPodNodeArch(pod)
+	if arch == "" {
+		return defaultImage
+	}
+	if image, ok := imageByArch[arch]; ok && image != "" {
+		log.Debug().Msgf("Using sidecar image %s for pod %s/%s constrained to %s nodes", image, pod.Namespace, pod.Name, arch)
+		return image
+	}
+	return defaultImage
+}
+
+// getPodNodeArch returns the node architecture the pod is constrained to by its nodeSelector or required node affinity,
+// or an empty string if the pod is not constrained to a single architecture.
+func getPodNodeArch(pod *corev1.Pod) string {
+	if arch, ok := pod.Spec.NodeSelector[corev1.LabelArchStable]; ok {
+		return arch
+	}
+
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	// Node selector terms are ORed, so the pod is constrained to a single architecture only if every term is
+	var arch string
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		termArch := ""
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelArchStable && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				termArch = expr.Values[0]
+				break
+			}
+		}
+		if termArch == "" || (arch != "" && arch != termArch) {
+			return ""
+		}
+		arch = termArch
+	}
+	return arch
+}
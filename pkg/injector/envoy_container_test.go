@@ -0,0 +1,157 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetSidecarImage(t *testing.T) {
+	defaultImage := "-default-image-"
+	imageByArch := map[string]string{
+		"arm64": "-arm64-image-",
+	}
+
+	archAffinity := func(archs ...[]string) *corev1.Affinity {
+		var terms []corev1.NodeSelectorTerm
+		for _, values := range archs {
+			terms = append(terms, corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{
+						Key:      "kubernetes.io/os",
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{"linux"},
+					},
+					{
+						Key:      corev1.LabelArchStable,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   values,
+					},
+				},
+			})
+		}
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: terms,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		podSpec       corev1.PodSpec
+		expectedImage string
+	}{
+		{
+			name:          "pod not constrained to an architecture",
+			podSpec:       corev1.PodSpec{},
+			expectedImage: defaultImage,
+		},
+		{
+			name: "pod constrained to arm64 by its nodeSelector",
+			podSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+			},
+			expectedImage: "-arm64-image-",
+		},
+		{
+			name: "pod constrained to an architecture without a configured image",
+			podSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{corev1.LabelArchStable: "amd64"},
+			},
+			expectedImage: defaultImage,
+		},
+		{
+			name: "pod constrained to arm64 by its node affinity",
+			podSpec: corev1.PodSpec{
+				Affinity: archAffinity([]string{"arm64"}, []string{"arm64"}),
+			},
+			expectedImage: "-arm64-image-",
+		},
+		{
+			name: "pod node affinity allows multiple architectures in a term",
+			podSpec: corev1.PodSpec{
+				Affinity: archAffinity([]string{"arm64", "amd64"}),
+			},
+			expectedImage: defaultImage,
+		},
+		{
+			name: "pod node affinity allows different architectures across terms",
+			podSpec: corev1.PodSpec{
+				Affinity: archAffinity([]string{"arm64"}, []string{"amd64"}),
+			},
+			expectedImage: defaultImage,
+		},
+		{
+			name: "pod node affinity without an architecture constraint",
+			podSpec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{},
+				},
+			},
+			expectedImage: defaultImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{Spec: tc.podSpec}
+			assert.Equal(tc.expectedImage, GetSidecarImage(pod, defaultImage, imageByArch))
+		})
+	}
+}
+
+func TestGetEnvoyLogLevel(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		namespaceAnnotations map[string]string
+		podAnnotations       map[string]string
+		expectedLogLevel     string
+	}{
+		{
+			name:             "mesh-wide log level",
+			expectedLogLevel: "error",
+		},
+		{
+			name:                 "namespace log level",
+			namespaceAnnotations: map[string]string{constants.EnvoyLogLevelAnnotation: "debug"},
+			expectedLogLevel:     "debug",
+		},
+		{
+			name:                 "pod log level overriding the namespace log level",
+			namespaceAnnotations: map[string]string{constants.EnvoyLogLevelAnnotation: "debug"},
+			podAnnotations:       map[string]string{constants.EnvoyLogLevelAnnotation: "trace"},
+			expectedLogLevel:     "trace",
+		},
+		{
+			name:             "invalid pod log level",
+			podAnnotations:   map[string]string{constants.EnvoyLogLevelAnnotation: "verbose"},
+			expectedLogLevel: "error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("error").Times(1)
+
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: tc.namespaceAnnotations}}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", Annotations: tc.podAnnotations}}
+			assert.Equal(tc.expectedLogLevel, getEnvoyLogLevel(pod, namespace, mockConfigurator))
+		})
+	}
+}
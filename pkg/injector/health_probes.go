@@ -5,12 +5,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openservicemesh/osm/pkg/constants"
 )
 
 const (
-	livenessProbePort  = int32(15901)
-	readinessProbePort = int32(15902)
-	startupProbePort   = int32(15903)
+	livenessProbePort  = int32(constants.EnvoyLivenessProbePort)
+	readinessProbePort = int32(constants.EnvoyReadinessProbePort)
+	startupProbePort   = int32(constants.EnvoyStartupProbePort)
 
 	livenessProbePath  = "/osm-liveness-probe"
 	readinessProbePath = "/osm-readiness-probe"
@@ -0,0 +1,27 @@
+package injector
+
+import (
+	"github.com/openservicemesh/osm/pkg/imageverifier"
+)
+
+// verifySidecarImage verifies the given sidecar image against the verification policy configured in the OSM ConfigMap,
+// and returns the image to inject. When verification is enabled, the returned image is pinned to the verified digest.
+// An error is returned when the image cannot be verified, so that injection fails closed.
+func (wh *mutatingWebhook) verifySidecarImage(image string) (string, error) {
+	policy := imageverifier.Policy{
+		Digest:          wh.configurator.GetSidecarImageDigest(),
+		CosignPublicKey: wh.configurator.GetSidecarImageCosignPublicKey(),
+	}
+	if !policy.IsEnabled() {
+		return image, nil
+	}
+
+	verifiedImage, err := wh.imageVerifier.Verify(image, policy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error verifying sidecar image %s", image)
+		return "", err
+	}
+
+	log.Trace().Msgf("Verified sidecar image %s as %s", image, verifiedImage)
+	return verifiedImage, nil
+}
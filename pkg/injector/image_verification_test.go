@@ -0,0 +1,70 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
+)
+
+func TestVerifySidecarImage(t *testing.T) {
+	const (
+		image  = "envoyproxy/envoy-alpine:v1.17.1"
+		digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	)
+
+	testCases := []struct {
+		name          string
+		image         string
+		pinnedDigest  string
+		expectedImage string
+		expectedError error
+	}{
+		{
+			name:          "verification disabled",
+			image:         image,
+			pinnedDigest:  "",
+			expectedImage: image,
+			expectedError: nil,
+		},
+		{
+			name:          "image pinned to the configured digest",
+			image:         image,
+			pinnedDigest:  digest,
+			expectedImage: "envoyproxy/envoy-alpine@" + digest,
+			expectedError: nil,
+		},
+		{
+			name:          "image pinned to another digest fails closed",
+			image:         "envoyproxy/envoy-alpine@sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			pinnedDigest:  digest,
+			expectedImage: "",
+			expectedError: imageverifier.ErrDigestMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetSidecarImageDigest().Return(tc.pinnedDigest).Times(1)
+			mockConfigurator.EXPECT().GetSidecarImageCosignPublicKey().Return("").Times(1)
+
+			wh := &mutatingWebhook{
+				configurator:  mockConfigurator,
+				imageVerifier: imageverifier.NewVerifier(),
+			}
+
+			actual, err := wh.verifySidecarImage(tc.image)
+			assert.Equal(tc.expectedError, errors.Cause(err))
+			assert.Equal(tc.expectedImage, actual)
+		})
+	}
+}
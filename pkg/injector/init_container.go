@@ -1,14 +1,19 @@
 package injector
 
 import (
+	"fmt"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
-func getInitContainerSpec(containerName string, containerImage string, outboundIPRangeExclusionList []string, enablePrivilegedInitContainer bool) corev1.Container {
-	iptablesInitCommandsList := generateIptablesCommands(outboundIPRangeExclusionList)
-	iptablesInitCommand := strings.Join(iptablesInitCommandsList, " && ")
+func getInitContainerSpec(containerName string, containerImage string, outboundIPRangeExclusionList []string, enablePrivilegedInitContainer bool, proxyUID int64, seccompProfile *corev1.SeccompProfile, seLinuxOptions *corev1.SELinuxOptions) corev1.Container {
+	iptablesInitCommand := strings.Join(generateIptablesCommands(proxyUID, outboundIPRangeExclusionList), " && ")
+	ip6tablesInitCommand := strings.Join(generateIp6tablesCommands(proxyUID, outboundIPRangeExclusionList), " && ")
+
+	// IPv6 traffic is only redirected on pods whose primary IP is an IPv6 address, since the proxy's listeners
+	// only accept IPv6 connections on such pods.
+	initCommand := fmt.Sprintf(`%s && %s && case "$POD_IP" in *:*) %s ;; esac`, iptablesBackendSelection, iptablesInitCommand, ip6tablesInitCommand)
 
 	return corev1.Container{
 		Name:  containerName,
@@ -20,11 +25,23 @@ func getInitContainerSpec(containerName string, containerImage string, outboundI
 					"NET_ADMIN",
 				},
 			},
+			SeccompProfile: seccompProfile,
+			SELinuxOptions: seLinuxOptions,
 		},
 		Command: []string{"/bin/sh"},
 		Args: []string{
 			"-c",
-			iptablesInitCommand,
+			initCommand,
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: "POD_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "status.podIP",
+					},
+				},
+			},
 		},
 	}
 }
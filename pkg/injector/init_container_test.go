@@ -6,6 +6,8 @@ import (
 
 	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
 )
 
 func TestGetInitContainerSpec(t *testing.T) {
@@ -33,7 +35,17 @@ func TestGetInitContainerSpec(t *testing.T) {
 				Command: []string{"/bin/sh"},
 				Args: []string{
 					"-c",
-					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
+					"LEGACY_RULES=$(iptables-legacy-save 2>/dev/null | grep -c '^-'); NFT_RULES=$(iptables-nft-save 2>/dev/null | grep -c '^-'); if [ \"$LEGACY_RULES\" -gt \"$NFT_RULES\" ] || ! iptables-nft -t nat -L -n >/dev/null 2>&1; then IPTABLES=iptables-legacy IP6TABLES=ip6tables-legacy; else IPTABLES=iptables-nft IP6TABLES=ip6tables-nft; fi && $IPTABLES -t nat -N PROXY_INBOUND && $IPTABLES -t nat -N PROXY_IN_REDIRECT && $IPTABLES -t nat -N PROXY_OUTPUT && $IPTABLES -t nat -N PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IPTABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IPTABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IPTABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && case \"$POD_IP\" in *:*) $IP6TABLES -t nat -N PROXY_INBOUND && $IP6TABLES -t nat -N PROXY_IN_REDIRECT && $IP6TABLES -t nat -N PROXY_OUTPUT && $IP6TABLES -t nat -N PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IP6TABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IP6TABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -d ::1/128 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IP6TABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT ;; esac",
+				},
+				Env: []v1.EnvVar{
+					{
+						Name: "POD_IP",
+						ValueFrom: &v1.EnvVarSource{
+							FieldRef: &v1.ObjectFieldSelector{
+								FieldPath: "status.podIP",
+							},
+						},
+					},
 				},
 				WorkingDir: "",
 				Resources:  v1.ResourceRequirements{},
@@ -60,7 +72,54 @@ func TestGetInitContainerSpec(t *testing.T) {
 				Command: []string{"/bin/sh"},
 				Args: []string{
 					"-c",
-					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && iptables -t nat -I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN && iptables -t nat -I PROXY_OUTPUT -d 10.0.0.10/24 -j RETURN",
+					"LEGACY_RULES=$(iptables-legacy-save 2>/dev/null | grep -c '^-'); NFT_RULES=$(iptables-nft-save 2>/dev/null | grep -c '^-'); if [ \"$LEGACY_RULES\" -gt \"$NFT_RULES\" ] || ! iptables-nft -t nat -L -n >/dev/null 2>&1; then IPTABLES=iptables-legacy IP6TABLES=ip6tables-legacy; else IPTABLES=iptables-nft IP6TABLES=ip6tables-nft; fi && $IPTABLES -t nat -N PROXY_INBOUND && $IPTABLES -t nat -N PROXY_IN_REDIRECT && $IPTABLES -t nat -N PROXY_OUTPUT && $IPTABLES -t nat -N PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IPTABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IPTABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IPTABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && $IPTABLES -t nat -I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN && $IPTABLES -t nat -I PROXY_OUTPUT -d 10.0.0.10/24 -j RETURN && case \"$POD_IP\" in *:*) $IP6TABLES -t nat -N PROXY_INBOUND && $IP6TABLES -t nat -N PROXY_IN_REDIRECT && $IP6TABLES -t nat -N PROXY_OUTPUT && $IP6TABLES -t nat -N PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IP6TABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IP6TABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -d ::1/128 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IP6TABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT ;; esac",
+				},
+				Env: []v1.EnvVar{
+					{
+						Name: "POD_IP",
+						ValueFrom: &v1.EnvVarSource{
+							FieldRef: &v1.ObjectFieldSelector{
+								FieldPath: "status.podIP",
+							},
+						},
+					},
+				},
+				WorkingDir: "",
+				Resources:  v1.ResourceRequirements{},
+				SecurityContext: &v1.SecurityContext{
+					Capabilities: &v1.Capabilities{
+						Add: []v1.Capability{
+							"NET_ADMIN",
+						},
+					},
+					Privileged: &privilegedFalse,
+				},
+				Stdin:     false,
+				StdinOnce: false,
+				TTY:       false,
+			},
+		},
+		{
+			name:                         "init container with IPv4 and IPv6 outbound exclusion list",
+			outboundIPRangeExclusionList: []string{"10.0.0.10/24", "fd00::/64"},
+			privileged:                   privilegedFalse,
+			expectedSpec: v1.Container{
+				Name:    "-container-name-",
+				Image:   "-init-container-image-",
+				Command: []string{"/bin/sh"},
+				Args: []string{
+					"-c",
+					"LEGACY_RULES=$(iptables-legacy-save 2>/dev/null | grep -c '^-'); NFT_RULES=$(iptables-nft-save 2>/dev/null | grep -c '^-'); if [ \"$LEGACY_RULES\" -gt \"$NFT_RULES\" ] || ! iptables-nft -t nat -L -n >/dev/null 2>&1; then IPTABLES=iptables-legacy IP6TABLES=ip6tables-legacy; else IPTABLES=iptables-nft IP6TABLES=ip6tables-nft; fi && $IPTABLES -t nat -N PROXY_INBOUND && $IPTABLES -t nat -N PROXY_IN_REDIRECT && $IPTABLES -t nat -N PROXY_OUTPUT && $IPTABLES -t nat -N PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IPTABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IPTABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IPTABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && $IPTABLES -t nat -I PROXY_OUTPUT -d 10.0.0.10/24 -j RETURN && case \"$POD_IP\" in *:*) $IP6TABLES -t nat -N PROXY_INBOUND && $IP6TABLES -t nat -N PROXY_IN_REDIRECT && $IP6TABLES -t nat -N PROXY_OUTPUT && $IP6TABLES -t nat -N PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IP6TABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IP6TABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -d ::1/128 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IP6TABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && $IP6TABLES -t nat -I PROXY_OUTPUT -d fd00::/64 -j RETURN ;; esac",
+				},
+				Env: []v1.EnvVar{
+					{
+						Name: "POD_IP",
+						ValueFrom: &v1.EnvVarSource{
+							FieldRef: &v1.ObjectFieldSelector{
+								FieldPath: "status.podIP",
+							},
+						},
+					},
 				},
 				WorkingDir: "",
 				Resources:  v1.ResourceRequirements{},
@@ -87,7 +146,17 @@ func TestGetInitContainerSpec(t *testing.T) {
 				Command: []string{"/bin/sh"},
 				Args: []string{
 					"-c",
-					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
+					"LEGACY_RULES=$(iptables-legacy-save 2>/dev/null | grep -c '^-'); NFT_RULES=$(iptables-nft-save 2>/dev/null | grep -c '^-'); if [ \"$LEGACY_RULES\" -gt \"$NFT_RULES\" ] || ! iptables-nft -t nat -L -n >/dev/null 2>&1; then IPTABLES=iptables-legacy IP6TABLES=ip6tables-legacy; else IPTABLES=iptables-nft IP6TABLES=ip6tables-nft; fi && $IPTABLES -t nat -N PROXY_INBOUND && $IPTABLES -t nat -N PROXY_IN_REDIRECT && $IPTABLES -t nat -N PROXY_OUTPUT && $IPTABLES -t nat -N PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IPTABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IPTABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IPTABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && $IPTABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IPTABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IPTABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IPTABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && case \"$POD_IP\" in *:*) $IP6TABLES -t nat -N PROXY_INBOUND && $IP6TABLES -t nat -N PROXY_IN_REDIRECT && $IP6TABLES -t nat -N PROXY_OUTPUT && $IP6TABLES -t nat -N PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && $IP6TABLES -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && $IP6TABLES -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && $IP6TABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -d ::1/128 -j RETURN && $IP6TABLES -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && $IP6TABLES -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && $IP6TABLES -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && $IP6TABLES -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT ;; esac",
+				},
+				Env: []v1.EnvVar{
+					{
+						Name: "POD_IP",
+						ValueFrom: &v1.EnvVarSource{
+							FieldRef: &v1.ObjectFieldSelector{
+								FieldPath: "status.podIP",
+							},
+						},
+					},
 				},
 				WorkingDir: "",
 				Resources:  v1.ResourceRequirements{},
@@ -108,8 +177,30 @@ func TestGetInitContainerSpec(t *testing.T) {
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			actual := getInitContainerSpec(containerName, containerImage, tc.outboundIPRangeExclusionList, tc.privileged)
+			actual := getInitContainerSpec(containerName, containerImage, tc.outboundIPRangeExclusionList, tc.privileged, constants.EnvoyUID, nil, nil)
 			assert.Equal(tc.expectedSpec, actual)
 		})
 	}
 }
+
+func TestGetInitContainerSpecWithProxyUID(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := getInitContainerSpec("-container-name-", "-init-container-image-", nil, false, 2102, nil, nil)
+
+	assert.Len(actual.Args, 2)
+	assert.Contains(actual.Args[1], "$IPTABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 2102 -j RETURN")
+	assert.Contains(actual.Args[1], "$IP6TABLES -t nat -A PROXY_OUTPUT -m owner --uid-owner 2102 -j RETURN")
+	assert.NotContains(actual.Args[1], fmt.Sprintf("--uid-owner %d", constants.EnvoyUID))
+}
+
+func TestGetInitContainerSpecWithSecurityProfiles(t *testing.T) {
+	assert := tassert.New(t)
+
+	seccompProfile := &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}
+	seLinuxOptions := &v1.SELinuxOptions{Type: "spc_t"}
+	actual := getInitContainerSpec("-container-name-", "-init-container-image-", nil, false, constants.EnvoyUID, seccompProfile, seLinuxOptions)
+
+	assert.Equal(seccompProfile, actual.SecurityContext.SeccompProfile)
+	assert.Equal(seLinuxOptions, actual.SecurityContext.SELinuxOptions)
+}
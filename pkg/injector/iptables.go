@@ -2,90 +2,143 @@ package injector
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 )
 
-// iptablesRedirectionChains is the list of iptables chains created for traffic redirection via the proxy sidecar
-var iptablesRedirectionChains = []string{
-	// Chain to intercept inbound traffic
-	"iptables -t nat -N PROXY_INBOUND",
+const (
+	// iptablesCmd is the command used to program IPv4 traffic redirection rules, selected by iptablesBackendSelection
+	iptablesCmd = "$IPTABLES"
+
+	// ip6tablesCmd is the command used to program IPv6 traffic redirection rules, selected by iptablesBackendSelection
+	ip6tablesCmd = "$IP6TABLES"
+
+	// iptablesBackendSelection selects the backend of the iptables commands programming the traffic redirection rules.
+	// The backend already holding the most rules is used, as the backend the kubelet and the CNI plugin program, so
+	// that the rules of the pod are not split across both backends. The legacy backend loads wherever its kernel
+	// modules are available, including on nodes using the nftables backend, so it is only used when it holds more
+	// rules, or when the nftables backend is not supported by the kernel of the node.
+	iptablesBackendSelection = `LEGACY_RULES=$(iptables-legacy-save 2>/dev/null | grep -c '^-'); NFT_RULES=$(iptables-nft-save 2>/dev/null | grep -c '^-'); ` +
+		`if [ "$LEGACY_RULES" -gt "$NFT_RULES" ] || ! iptables-nft -t nat -L -n >/dev/null 2>&1; then IPTABLES=iptables-legacy IP6TABLES=ip6tables-legacy; ` +
+		`else IPTABLES=iptables-nft IP6TABLES=ip6tables-nft; fi`
+)
+
+// getRedirectionChains returns the list of iptables chains created for traffic redirection via the proxy sidecar
+func getRedirectionChains(cmd string) []string {
+	return []string{
+		// Chain to intercept inbound traffic
+		fmt.Sprintf("%s -t nat -N PROXY_INBOUND", cmd),
 
-	// Chain to redirect inbound traffic to the proxy
-	"iptables -t nat -N PROXY_IN_REDIRECT",
+		// Chain to redirect inbound traffic to the proxy
+		fmt.Sprintf("%s -t nat -N PROXY_IN_REDIRECT", cmd),
 
-	// Chain to intercept outbound traffic
-	"iptables -t nat -N PROXY_OUTPUT",
+		// Chain to intercept outbound traffic
+		fmt.Sprintf("%s -t nat -N PROXY_OUTPUT", cmd),
 
-	// Chain to redirect outbound traffic to the proxy
-	"iptables -t nat -N PROXY_REDIRECT",
+		// Chain to redirect outbound traffic to the proxy
+		fmt.Sprintf("%s -t nat -N PROXY_REDIRECT", cmd),
+	}
 }
 
-// iptablesOutboundStaticRules is the list of iptables rules related to outbound traffic interception and redirection
-var iptablesOutboundStaticRules = []string{
-	// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port
-	fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+// getOutboundStaticRules returns the list of iptables rules related to outbound traffic interception and redirection.
+// The traffic of the proxy, running as the given user ID, is not redirected.
+func getOutboundStaticRules(cmd string, loopbackCIDR string, proxyUID int64) []string {
+	return []string{
+		// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port
+		fmt.Sprintf("%s -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port %d", cmd, constants.EnvoyOutboundListenerPort),
 
-	// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
-	fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
+		// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
+		fmt.Sprintf("%s -t nat -A PROXY_REDIRECT -p tcp --dport %d -j ACCEPT", cmd, constants.EnvoyAdminPort),
 
-	// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain
-	"iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT",
+		// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain
+		fmt.Sprintf("%s -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT", cmd),
 
-	// TODO(#1266): Redirect app back calls to itself using PROXY_UID
+		// TODO(#1266): Redirect app back calls to itself using PROXY_UID
 
-	// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
-	fmt.Sprintf("iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
+		// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
+		fmt.Sprintf("%s -t nat -A PROXY_OUTPUT -m owner --uid-owner %d -j RETURN", cmd, proxyUID),
 
-	// Skip localhost traffic, doesn't need to be routed via the proxy
-	"iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN",
+		// Skip localhost traffic, doesn't need to be routed via the proxy
+		fmt.Sprintf("%s -t nat -A PROXY_OUTPUT -d %s -j RETURN", cmd, loopbackCIDR),
 
-	// Redirect remaining outbound traffic to Envoy
-	"iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT",
+		// Redirect remaining outbound traffic to Envoy
+		fmt.Sprintf("%s -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT", cmd),
+	}
 }
 
-// iptablesInboundStaticRules is the list of iptables rules related to inbound traffic interception and redirection
-var iptablesInboundStaticRules = []string{
-	// Redirects inbound TCP traffic hitting the PROXY_IN_REDIRECT chain to Envoy's inbound listener port
-	fmt.Sprintf("iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyInboundListenerPort),
+// getInboundStaticRules returns the list of iptables rules related to inbound traffic interception and redirection
+func getInboundStaticRules(cmd string) []string {
+	return []string{
+		// Redirects inbound TCP traffic hitting the PROXY_IN_REDIRECT chain to Envoy's inbound listener port
+		fmt.Sprintf("%s -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port %d", cmd, constants.EnvoyInboundListenerPort),
 
-	// For inbound traffic jump from PREROUTING chain to PROXY_INBOUND chain
-	"iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND",
+		// For inbound traffic jump from PREROUTING chain to PROXY_INBOUND chain
+		fmt.Sprintf("%s -t nat -A PREROUTING -p tcp -j PROXY_INBOUND", cmd),
 
-	// Skip metrics query traffic being directed to Envoy's inbound prometheus listener port
-	fmt.Sprintf("iptables -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", constants.EnvoyPrometheusInboundListenerPort),
+		// Skip metrics query traffic being directed to Envoy's inbound prometheus listener port
+		fmt.Sprintf("%s -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", cmd, constants.EnvoyPrometheusInboundListenerPort),
 
-	// Skip inbound health probes; These ports will be explicitly handled by listeners configured on the
-	// Envoy proxy IF any health probes have been configured in the Pod Spec.
-	// TODO(draychev): Do not add these if no health probes have been defined (https://github.com/openservicemesh/osm/issues/2243)
-	fmt.Sprintf("iptables -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", livenessProbePort),
-	fmt.Sprintf("iptables -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", readinessProbePort),
-	fmt.Sprintf("iptables -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", startupProbePort),
+		// Skip inbound health probes; These ports will be explicitly handled by listeners configured on the
+		// Envoy proxy IF any health probes have been configured in the Pod Spec.
+		// TODO(draychev): Do not add these if no health probes have been defined (https://github.com/openservicemesh/osm/issues/2243)
+		fmt.Sprintf("%s -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", cmd, livenessProbePort),
+		fmt.Sprintf("%s -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", cmd, readinessProbePort),
+		fmt.Sprintf("%s -t nat -A PROXY_INBOUND -p tcp --dport %d -j RETURN", cmd, startupProbePort),
 
-	// Redirect remaining inbound traffic to Envoy
-	"iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
+		// Redirect remaining inbound traffic to Envoy
+		fmt.Sprintf("%s -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT", cmd),
+	}
 }
 
 // generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection
-func generateIptablesCommands(outboundIPRangeExclusionList []string) []string {
+// of IPv4 traffic. IPv6 CIDRs in the outbound exclusion list are ignored.
+func generateIptablesCommands(proxyUID int64, outboundIPRangeExclusionList []string) []string {
+	return generateCommands(iptablesCmd, constants.LocalhostIPAddress+"/32", proxyUID, filterCIDRsByIPFamily(outboundIPRangeExclusionList, false))
+}
+
+// generateIp6tablesCommands generates a list of ip6tables commands to set up sidecar interception and redirection
+// of IPv6 traffic. IPv4 CIDRs in the outbound exclusion list are ignored.
+func generateIp6tablesCommands(proxyUID int64, outboundIPRangeExclusionList []string) []string {
+	return generateCommands(ip6tablesCmd, constants.LocalhostIPv6Address+"/128", proxyUID, filterCIDRsByIPFamily(outboundIPRangeExclusionList, true))
+}
+
+func generateCommands(cmdName string, loopbackCIDR string, proxyUID int64, outboundIPRangeExclusionList []string) []string {
 	var cmd []string
 
 	// 1. Create redirection chains
-	cmd = append(cmd, iptablesRedirectionChains...)
+	cmd = append(cmd, getRedirectionChains(cmdName)...)
 
 	// 2. Create outbound rules
-	cmd = append(cmd, iptablesOutboundStaticRules...)
+	cmd = append(cmd, getOutboundStaticRules(cmdName, loopbackCIDR, proxyUID)...)
 
 	// 3. Create inbound rules
-	cmd = append(cmd, iptablesInboundStaticRules...)
+	cmd = append(cmd, getInboundStaticRules(cmdName)...)
 
 	// 4. Create dynamic outbound exclusion rules
 	for _, cidr := range outboundIPRangeExclusionList {
 		// *Note: it is important to use the insert option '-I' instead of the append option '-A' to ensure the exclusion
 		// rules take precedence over the static redirection rules. Iptables rules are evaluated in order.
-		rule := fmt.Sprintf("iptables -t nat -I PROXY_OUTPUT -d %s -j RETURN", cidr)
+		rule := fmt.Sprintf("%s -t nat -I PROXY_OUTPUT -d %s -j RETURN", cmdName, cidr)
 		cmd = append(cmd, rule)
 	}
 
 	return cmd
 }
+
+// filterCIDRsByIPFamily returns the CIDRs in the given list that belong to the IPv6 family if ipv6 is true,
+// and to the IPv4 family otherwise
+func filterCIDRsByIPFamily(cidrs []string, ipv6 bool) []string {
+	var filtered []string
+	for _, cidr := range cidrs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid CIDR %s in outbound IP range exclusion list, skipping it", cidr)
+			continue
+		}
+		if (ip.To4() == nil) == ipv6 {
+			filtered = append(filtered, cidr)
+		}
+	}
+	return filtered
+}
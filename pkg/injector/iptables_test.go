@@ -0,0 +1,149 @@
+package injector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// fakeIptablesBin writes fake iptables binaries to the given directory. The save commands print the given number of
+// rules, iptables-nft fails unless nftSupported is set, and the commands programming rules log their invocation.
+func fakeIptablesBin(dir string, legacyRules int, nftRules int, nftSupported bool) error {
+	rules := func(count int) string {
+		return strings.Repeat(`echo "-A KUBE-SERVICES -j RETURN"; `, count)
+	}
+	nftStatus := 1
+	if nftSupported {
+		nftStatus = 0
+	}
+
+	scripts := map[string]string{
+		"iptables-legacy-save": fmt.Sprintf(`echo "*nat"; %s echo "COMMIT"`, rules(legacyRules)),
+		"iptables-nft-save":    fmt.Sprintf(`echo "*nat"; %s echo "COMMIT"`, rules(nftRules)),
+		"iptables-nft":         fmt.Sprintf(`case "$*" in "-t nat -L -n") exit %d ;; esac; echo "iptables-nft $*" >> "$LOG"`, nftStatus),
+		"iptables-legacy":      `echo "iptables-legacy $*" >> "$LOG"`,
+		"ip6tables-nft":        `echo "ip6tables-nft $*" >> "$LOG"`,
+		"ip6tables-legacy":     `echo "ip6tables-legacy $*" >> "$LOG"`,
+	}
+	for name, script := range scripts {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil { // #nosec G306
+			return err
+		}
+	}
+	return nil
+}
+
+func TestIptablesInitScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The init script requires a POSIX shell")
+	}
+	grepPath, err := exec.LookPath("grep")
+	if err != nil {
+		t.Skip("The init script requires grep")
+	}
+
+	exclusions := []string{"1.1.1.1/32", "fd00::/64"}
+
+	testCases := []struct {
+		name              string
+		legacyRules       int
+		nftRules          int
+		nftSupported      bool
+		podIP             string
+		expectedIptables  string
+		expectedIp6tables string
+	}{
+		{
+			name:             "nftables backend holding the rules of the node",
+			legacyRules:      0,
+			nftRules:         12,
+			nftSupported:     true,
+			podIP:            "10.0.0.1",
+			expectedIptables: "iptables-nft",
+		},
+		{
+			name:             "legacy backend holding the rules of the node",
+			legacyRules:      12,
+			nftRules:         0,
+			nftSupported:     true,
+			podIP:            "10.0.0.1",
+			expectedIptables: "iptables-legacy",
+		},
+		{
+			name:             "no rules on either backend",
+			legacyRules:      0,
+			nftRules:         0,
+			nftSupported:     true,
+			podIP:            "10.0.0.1",
+			expectedIptables: "iptables-nft",
+		},
+		{
+			name:             "nftables backend not supported by the kernel",
+			legacyRules:      0,
+			nftRules:         0,
+			nftSupported:     false,
+			podIP:            "10.0.0.1",
+			expectedIptables: "iptables-legacy",
+		},
+		{
+			name:              "IPv6 pod on the nftables backend",
+			legacyRules:       2,
+			nftRules:          12,
+			nftSupported:      true,
+			podIP:             "fd00::1",
+			expectedIptables:  "iptables-nft",
+			expectedIp6tables: "ip6tables-nft",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			dir, err := ioutil.TempDir("", "iptables")
+			assert.Nil(err)
+			defer os.RemoveAll(dir) //nolint: errcheck
+			assert.Nil(fakeIptablesBin(dir, tc.legacyRules, tc.nftRules, tc.nftSupported))
+			logFile := filepath.Join(dir, "log")
+
+			spec := getInitContainerSpec("-container-name-", "-init-container-image-", exclusions, false, constants.EnvoyUID, nil, nil)
+			// #nosec G204
+			cmd := exec.Command(spec.Command[0], spec.Args...)
+			cmd.Env = []string{
+				"PATH=" + dir + string(os.PathListSeparator) + filepath.Dir(grepPath),
+				"LOG=" + logFile,
+				"POD_IP=" + tc.podIP,
+			}
+			out, err := cmd.CombinedOutput()
+			assert.Nil(err, string(out))
+
+			logged, err := ioutil.ReadFile(logFile) // #nosec G304
+			assert.Nil(err)
+
+			// Every rule of each redirected IP family is programmed, in order, with the command of the selected backend
+			expected := withCommand(generateIptablesCommands(constants.EnvoyUID, exclusions), iptablesCmd, tc.expectedIptables)
+			if tc.expectedIp6tables != "" {
+				expected = append(expected, withCommand(generateIp6tablesCommands(constants.EnvoyUID, exclusions), ip6tablesCmd, tc.expectedIp6tables)...)
+			}
+			assert.Equal(expected, strings.Split(strings.TrimSpace(string(logged)), "\n"))
+		})
+	}
+}
+
+// withCommand returns the given rules with the given command variable replaced by the given command
+func withCommand(rules []string, variable string, cmd string) []string {
+	var result []string
+	for _, rule := range rules {
+		result = append(result, strings.Replace(rule, variable, cmd, 1))
+	}
+	return result
+}
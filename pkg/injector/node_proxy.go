@@ -0,0 +1,26 @@
+package injector
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// createNodeProxyBootstrapConfig creates the Envoy bootstrap config secret mounted by the experimental per-node proxies.
+// The per-node proxies are pods of a DaemonSet, so they all share this bootstrap config and the xDS certificate in it.
+func (wh *mutatingWebhook) createNodeProxyBootstrapConfig() error {
+	cn := catalog.NewCertCommonNameWithProxyID(uuid.New(), constants.NodeProxyName, wh.osmNamespace)
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for node proxy with CN=%s", cn)
+		return err
+	}
+
+	if _, err := wh.createEnvoyBootstrapConfig(constants.NodeProxyBootstrapSecretName, wh.osmNamespace, wh.osmNamespace, bootstrapCertificate, healthProbes{}, constants.TrafficPriorityNormal); err != nil {
+		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for node proxy with certificate CN=%s", cn)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package injector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestCreateNodeProxyBootstrapConfig(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	osmNamespace := "osm-system"
+	kubeClient := fake.NewSimpleClientset()
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	wh := &mutatingWebhook{
+		kubeClient:   kubeClient,
+		certManager:  tresor.NewFakeCertManager(mockConfigurator),
+		osmNamespace: osmNamespace,
+		meshName:     "osm",
+		configurator: mockConfigurator,
+	}
+
+	err := wh.createNodeProxyBootstrapConfig()
+	assert.Nil(err)
+
+	secret, err := kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), constants.NodeProxyBootstrapSecretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Contains(secret.Data, envoyBootstrapConfigFile)
+	assert.Equal(constants.OSMAppManagedByLabelValue, secret.Labels[constants.OSMAppManagedByLabelKey])
+	firstConfig := secret.Data[envoyBootstrapConfigFile]
+
+	// A restart of the injector must update the existing bootstrap config
+	err = wh.createNodeProxyBootstrapConfig()
+	assert.Nil(err)
+
+	secret, err = kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), constants.NodeProxyBootstrapSecretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.NotEqual(firstConfig, secret.Data[envoyBootstrapConfigFile])
+}
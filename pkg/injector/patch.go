@@ -20,6 +20,12 @@ import (
 func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID) ([]byte, error) {
 	namespace := req.Namespace
 
+	// Verify the sidecar image before making any change, so that the pod is rejected if the image cannot be verified
+	sidecarImage, err := wh.verifySidecarImage(GetSidecarImage(pod, wh.config.SidecarImage, wh.config.SidecarImageByArch))
+	if err != nil {
+		return nil, err
+	}
+
 	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
 	cn := catalog.NewCertCommonNameWithProxyID(proxyUUID, pod.Spec.ServiceAccountName, namespace)
 	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
@@ -45,7 +51,7 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 	// Ref: https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#side-effects
 	if req.DryRun != nil && *req.DryRun {
 		log.Debug().Msgf("Skipping envoy bootstrap config creation for dry-run request: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
-	} else if _, err = wh.createEnvoyBootstrapConfig(envoyBootstrapConfigName, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes); err != nil {
+	} else if _, err = wh.createEnvoyBootstrapConfig(envoyBootstrapConfigName, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes, getPodTrafficPriority(pod, namespace, wh.kubeController)); err != nil {
 		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
 		return nil, err
 	}
@@ -54,13 +60,25 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 	pod.Spec.Volumes = append(pod.Spec.Volumes, getVolumeSpec(envoyBootstrapConfigName)...)
 
 	// Add the Init Container
-	initContainer := getInitContainerSpec(constants.InitContainerName, wh.config.InitContainerImage, wh.configurator.GetOutboundIPRangeExclusionList(), wh.configurator.IsPrivilegedInitContainer())
+	initContainer := getInitContainerSpec(constants.InitContainerName, wh.config.InitContainerImage, wh.configurator.GetOutboundIPRangeExclusionList(), wh.configurator.IsPrivilegedInitContainer(), wh.configurator.GetProxyUID(), wh.configurator.GetProxySeccompProfile(), wh.configurator.GetProxySELinuxOptions())
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
 
+	// Point the containers of the pod to the lifecycle API of the Envoy sidecar
+	setLifecycleURLEnv(pod.Spec.Containers)
+
 	// Add the Envoy sidecar
-	sidecar := getEnvoySidecarContainerSpec(pod, wh.config.SidecarImage, wh.configurator, originalHealthProbes)
+	sidecar := getEnvoySidecarContainerSpec(pod, wh.kubeController.GetNamespace(namespace), sidecarImage, wh.configurator, originalHealthProbes)
 	pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
 
+	// The AppArmor profile of a container is set by an annotation of its pod
+	if appArmorProfile := wh.configurator.GetProxyAppArmorProfile(); appArmorProfile != "" {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[constants.AppArmorProfileAnnotationPrefix+constants.InitContainerName] = appArmorProfile
+		pod.Annotations[constants.AppArmorProfileAnnotationPrefix+constants.EnvoyContainerName] = appArmorProfile
+	}
+
 	enableMetrics, err := wh.isMetricsEnabled(namespace)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error checking if namespace %s is enabled for metrics", namespace)
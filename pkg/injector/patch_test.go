@@ -17,6 +17,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
@@ -36,7 +37,8 @@ var _ = Describe("Test all patch operations", func() {
 			mockCtrl := gomock.NewController(GinkgoT())
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			mockNsController := k8s.NewMockController(mockCtrl)
-			mockNsController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{})
+			mockNsController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(2)
+			mockNsController.EXPECT().ListServices().Return(nil).Times(1)
 			testNamespace := &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "default",
@@ -55,8 +57,15 @@ var _ = Describe("Test all patch operations", func() {
 
 			pod := tests.NewPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil)
 			pod.Annotations = nil
+			mockConfigurator.EXPECT().GetSidecarImageDigest().Return("").Times(1)
+			mockConfigurator.EXPECT().GetSidecarImageCosignPublicKey().Return("").Times(1)
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("").Times(1)
 			mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).Times(1)
+			mockConfigurator.EXPECT().GetProxyUID().Return(constants.EnvoyUID).Times(2)
+			mockConfigurator.EXPECT().GetProxyGID().Return(constants.EnvoyGID).Times(1)
+			mockConfigurator.EXPECT().GetProxySeccompProfile().Return(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}).Times(2)
+			mockConfigurator.EXPECT().GetProxySELinuxOptions().Return(nil).Times(2)
+			mockConfigurator.EXPECT().GetProxyAppArmorProfile().Return("").Times(1)
 			mockConfigurator.EXPECT().GetOutboundIPRangeExclusionList().Return(nil).Times(1)
 
 			req := &admissionv1.AdmissionRequest{Namespace: namespace}
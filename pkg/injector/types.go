@@ -8,6 +8,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
@@ -28,6 +29,7 @@ type mutatingWebhook struct {
 	meshName       string
 	cert           certificate.Certificater
 	configurator   configurator.Configurator
+	imageVerifier  *imageverifier.Verifier
 
 	nonInjectNamespaces mapset.Set
 }
@@ -40,6 +42,9 @@ type Config struct {
 	InitContainerImage string
 
 	SidecarImage string
+
+	// SidecarImageByArch maps a node architecture to the sidecar image used for pods constrained to nodes of that architecture
+	SidecarImageByArch map[string]string
 }
 
 // Context needed to compose the Envoy bootstrap YAML.
@@ -57,4 +62,8 @@ type envoyBootstrapConfigMeta struct {
 	// The bootstrap Envoy config will be affected by the liveness, readiness, startup probes set on
 	// the pod this Envoy is fronting.
 	OriginalHealthProbes healthProbes
+
+	// TrafficPriority is the traffic priority class of the pod this Envoy is fronting, setting the thresholds at which
+	// the overload manager of the Envoy sheds its load.
+	TrafficPriority string
 }
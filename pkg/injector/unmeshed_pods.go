@@ -0,0 +1,89 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// getUnmeshedPodReason returns the reason the given pod, which is not injected with a sidecar, is excluded from the mesh
+// although its namespace is enabled for sidecar injection. An empty reason is returned if the pod is not excluded from the mesh.
+//
+// A pod is excluded from the mesh when either of the following is true:
+// 1. The pod uses the host network, which cannot be redirected to a sidecar, or
+// 2. The pod is explicitly annotated with disabled/no/false for sidecar injection
+func (wh *mutatingWebhook) getUnmeshedPodReason(pod *corev1.Pod, namespace string) (string, error) {
+	var reason string
+	if pod.Spec.HostNetwork {
+		reason = constants.UnmeshedReasonHostNetwork
+	} else if podInjectAnnotationExists, podInject, _ := isAnnotatedForInjection(pod.Annotations, pod.Kind, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)); podInjectAnnotationExists && !podInject {
+		reason = constants.UnmeshedReasonOptOut
+	} else {
+		return "", nil
+	}
+
+	if !wh.isNamespaceInjectable(namespace) {
+		return "", nil
+	}
+
+	ns := wh.kubeController.GetNamespace(namespace)
+	if ns == nil {
+		log.Error().Err(errNamespaceNotFound).Msgf("Error retrieving namespace %s", namespace)
+		return "", errNamespaceNotFound
+	}
+	nsInjectAnnotationExists, nsInject, err := isAnnotatedForInjection(ns.Annotations, ns.Kind, ns.Name)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error determining if namespace %s is enabled for sidecar injection", namespace)
+		return "", err
+	}
+	if !nsInjectAnnotationExists || !nsInject {
+		return "", nil
+	}
+
+	return reason, nil
+}
+
+// applyUnmeshedPodPolicy applies the unmeshed pod policy configured in the OSM ConfigMap to the admission response of
+// the given pod, excluded from the mesh for the given reason.
+func (wh *mutatingWebhook) applyUnmeshedPodPolicy(resp *admissionv1.AdmissionResponse, pod *corev1.Pod, req *admissionv1.AdmissionRequest, reason string) *admissionv1.AdmissionResponse {
+	switch wh.configurator.GetUnmeshedPodPolicy() {
+	case constants.UnmeshedPodPolicyDeny:
+		log.Warn().Msgf("Rejecting pod %s/%s excluded from the mesh (%s) in namespace %s enabled for sidecar injection", req.Namespace, getPodName(pod), reason, req.Namespace)
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf("Pod excluded from the mesh (%s) in namespace %s enabled for sidecar injection is not allowed by the unmeshed pod policy", reason, req.Namespace),
+		}
+
+	case constants.UnmeshedPodPolicyAudit:
+		log.Info().Msgf("Labeling pod %s/%s excluded from the mesh (%s) with %s", req.Namespace, getPodName(pod), reason, constants.UnmeshedPodLabel)
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels[constants.UnmeshedPodLabel] = reason
+		patchBytes, err := json.Marshal(makePatches(req, pod))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error creating patch to label pod %s/%s excluded from the mesh", req.Namespace, getPodName(pod))
+			return resp
+		}
+		patchAdmissionResponse(resp, patchBytes)
+	}
+
+	return resp
+}
+
+// getPodName returns the name of the given pod, or its generate name if the name is not yet set
+func getPodName(pod *corev1.Pod) string {
+	if pod.Name != "" {
+		return pod.Name
+	}
+	return pod.GenerateName
+}
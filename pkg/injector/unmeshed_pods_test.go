@@ -0,0 +1,191 @@
+package injector
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+func TestGetUnmeshedPodReason(t *testing.T) {
+	const namespace = "test"
+
+	injectedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        namespace,
+			Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+		},
+	}
+	monitoredNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		pod            *corev1.Pod
+		isMonitored    bool
+		ns             *corev1.Namespace
+		expectedReason string
+	}{
+		{
+			name:           "pod without opt-out",
+			pod:            &corev1.Pod{},
+			expectedReason: "",
+		},
+		{
+			name: "pod opted out in namespace enabled for sidecar injection",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{constants.SidecarInjectionAnnotation: "disabled"},
+				},
+			},
+			isMonitored:    true,
+			ns:             injectedNamespace,
+			expectedReason: constants.UnmeshedReasonOptOut,
+		},
+		{
+			name: "pod using the host network in namespace enabled for sidecar injection",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{HostNetwork: true},
+			},
+			isMonitored:    true,
+			ns:             injectedNamespace,
+			expectedReason: constants.UnmeshedReasonHostNetwork,
+		},
+		{
+			name: "pod using the host network in namespace not enabled for sidecar injection",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{HostNetwork: true},
+			},
+			isMonitored:    true,
+			ns:             monitoredNamespace,
+			expectedReason: "",
+		},
+		{
+			name: "pod opted out in namespace not monitored",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{constants.SidecarInjectionAnnotation: "disabled"},
+				},
+			},
+			isMonitored:    false,
+			expectedReason: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(tc.isMonitored).AnyTimes()
+			mockKubeController.EXPECT().GetNamespace(namespace).Return(tc.ns).AnyTimes()
+
+			wh := &mutatingWebhook{
+				kubeController:      mockKubeController,
+				nonInjectNamespaces: mapset.NewSet(),
+			}
+
+			reason, err := wh.getUnmeshedPodReason(tc.pod, namespace)
+			assert.Nil(err)
+			assert.Equal(tc.expectedReason, reason)
+		})
+	}
+}
+
+func TestMutateUnmeshedPod(t *testing.T) {
+	const namespace = "test"
+
+	testCases := []struct {
+		name            string
+		policy          string
+		expectedAllowed bool
+		expectedPatch   bool
+	}{
+		{
+			name:            "allow policy admits the pod unchanged",
+			policy:          constants.UnmeshedPodPolicyAllow,
+			expectedAllowed: true,
+			expectedPatch:   false,
+		},
+		{
+			name:            "audit policy admits and labels the pod",
+			policy:          constants.UnmeshedPodPolicyAudit,
+			expectedAllowed: true,
+			expectedPatch:   true,
+		},
+		{
+			name:            "deny policy rejects the pod",
+			policy:          constants.UnmeshedPodPolicyDeny,
+			expectedAllowed: false,
+			expectedPatch:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true).AnyTimes()
+			mockKubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+				},
+			}).AnyTimes()
+			mockConfigurator.EXPECT().GetUnmeshedPodPolicy().Return(tc.policy).Times(1)
+
+			wh := &mutatingWebhook{
+				kubeController:      mockKubeController,
+				configurator:        mockConfigurator,
+				nonInjectNamespaces: mapset.NewSet(),
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod",
+					Namespace: namespace,
+				},
+				Spec: corev1.PodSpec{HostNetwork: true},
+			}
+			raw, err := json.Marshal(pod)
+			assert.Nil(err)
+			req := &admissionv1.AdmissionRequest{
+				UID:       "11111111-2222-3333-4444-555555555555",
+				Namespace: namespace,
+				Object:    runtime.RawExtension{Raw: raw},
+			}
+
+			resp := wh.mutate(req, uuid.New())
+			assert.Equal(req.UID, resp.UID)
+			assert.Equal(tc.expectedAllowed, resp.Allowed)
+			assert.Equal(tc.expectedPatch, resp.Patch != nil)
+			if tc.expectedPatch {
+				assert.Contains(string(resp.Patch), constants.UnmeshedReasonHostNetwork)
+			}
+			if !tc.expectedAllowed {
+				assert.Equal(int32(http.StatusForbidden), resp.Result.Code)
+			}
+		})
+	}
+}
@@ -22,10 +22,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	admissionRegistrationTypes "k8s.io/client-go/kubernetes/typed/admissionregistration/v1"
 
+	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/featureflags"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 	"github.com/openservicemesh/osm/pkg/webhook"
 )
@@ -83,6 +86,7 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 		meshName:       meshName,
 		cert:           webhookHandlerCert,
 		configurator:   cfg,
+		imageVerifier:  imageverifier.NewVerifier(),
 
 		// Envoy sidecars should never be injected in these namespaces
 		nonInjectNamespaces: mapset.NewSetFromSlice([]interface{}{
@@ -92,6 +96,20 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 		}),
 	}
 
+	// Create the bootstrap config shared by the experimental per-node proxies
+	if featureflags.IsNodeProxyModeEnabled() {
+		if err = wh.createNodeProxyBootstrapConfig(); err != nil {
+			return errors.Errorf("Error creating node proxy bootstrap config: %+v", err)
+		}
+	}
+
+	// Create the bootstrap config shared by the egress gateway replicas
+	if featureflags.IsEgressGatewayEnabled() {
+		if err = wh.createEgressGatewayBootstrapConfig(); err != nil {
+			return errors.Errorf("Error creating egress gateway bootstrap config: %+v", err)
+		}
+	}
+
 	// Start the MutatingWebhook web server
 	go wh.run(stop)
 
@@ -246,6 +264,16 @@ func (wh *mutatingWebhook) mutate(req *admissionv1.AdmissionRequest, proxyUUID u
 		return webhook.AdmissionError(err)
 	} else if !inject {
 		log.Trace().Msgf("Skipping sidecar injection for pod with UUID %s in namespace %s", proxyUUID, req.Namespace)
+
+		// Apply the unmeshed pod policy to pods excluded from the mesh in namespaces enabled for sidecar injection
+		reason, err := wh.getUnmeshedPodReason(&pod, req.Namespace)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error checking if pod with UUID %s in namespace %s is excluded from the mesh", proxyUUID, req.Namespace)
+			return webhook.AdmissionError(err)
+		}
+		if reason != "" {
+			return wh.applyUnmeshedPodPolicy(resp, &pod, req, reason)
+		}
 		return resp
 	}
 
@@ -270,7 +298,8 @@ func (wh *mutatingWebhook) isNamespaceInjectable(namespace string) bool {
 
 // mustInject determines whether the sidecar must be injected.
 //
-// The sidecar injection is performed when the namespace is labeled for monitoring and either of the following is true:
+// The sidecar injection is performed when the namespace is labeled for monitoring, the pod does not use the host network,
+// and either of the following is true:
 // 1. The pod is explicitly annotated with enabled/yes/true for sidecar injection, or
 // 2. The namespace is annotated for sidecar injection and the pod is not explicitly annotated with disabled/no/false
 //
@@ -281,6 +310,19 @@ func (wh *mutatingWebhook) mustInject(pod *corev1.Pod, namespace string) (bool,
 		return false, nil
 	}
 
+	// Pods in node proxy mode are served by the per-node proxy on their node instead of a sidecar. Their traffic is
+	// redirected to the node proxy by the osm-node-proxy-redirect container of the node proxy DaemonSet.
+	if featureflags.IsNodeProxyModeEnabled() && catalog.IsNodeProxyModePod(pod) {
+		log.Debug().Msgf("Mutation request is for pod with UID %s in node proxy mode; Sidecar injection is not required", pod.ObjectMeta.UID)
+		return false, nil
+	}
+
+	// Pods using the host network share the network namespace of their node, whose traffic must not be redirected to a sidecar
+	if pod.Spec.HostNetwork {
+		log.Debug().Msgf("Mutation request is for pod with UID %s using the host network; Sidecar injection is not permitted", pod.ObjectMeta.UID)
+		return false, nil
+	}
+
 	// Check if the pod is annotated for injection
 	podInjectAnnotationExists, podInject, err := isAnnotatedForInjection(pod.Annotations, pod.Kind, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
 	if err != nil {
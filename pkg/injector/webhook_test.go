@@ -24,6 +24,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/featureflags"
 	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
 )
 
@@ -395,6 +396,52 @@ var _ = Describe("Testing mustInject, isNamespaceInjectable", func() {
 		Expect(inject).To(BeFalse())
 	})
 
+	It("should return false when the pod is in node proxy mode and node proxy mode is enabled", func() {
+		featureflags.Initialize(featureflags.OptionalFeatures{NodeProxyMode: true})
+
+		podInNodeProxyMode := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-in-node-proxy-mode",
+				Annotations: map[string]string{
+					constants.SidecarInjectionAnnotation: "enabled",
+					constants.ProxyModeAnnotation:        constants.ProxyModeNode,
+				},
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "test-SA",
+			},
+		}
+
+		mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true).Times(1)
+
+		inject, err := wh.mustInject(podInNodeProxyMode, namespace)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inject).To(BeFalse())
+	})
+
+	It("should return false when the pod uses the host network", func() {
+		podWithHostNetwork := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-with-host-network",
+				Annotations: map[string]string{
+					constants.SidecarInjectionAnnotation: "enabled",
+				},
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "test-SA",
+				HostNetwork:        true,
+			},
+		}
+
+		mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true).Times(1)
+
+		inject, err := wh.mustInject(podWithHostNetwork, namespace)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inject).To(BeFalse())
+	})
+
 	It("Should allow a monitored app namespace", func() {
 		testNamespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,243 @@
+package introspection
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/introspection/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// NewServer creates a new introspection server
+func NewServer(meshCatalog catalog.MeshCataloger, meshCatalogDebugger debugger.MeshCatalogDebugger, certDebugger debugger.CertificateManagerDebugger, imageChecker ProxyImageChecker, cfg configurator.Configurator) *Server {
+	return &Server{
+		meshCatalog:         meshCatalog,
+		meshCatalogDebugger: meshCatalogDebugger,
+		certDebugger:        certDebugger,
+		imageChecker:        imageChecker,
+		cfg:                 cfg,
+	}
+}
+
+// Start starts the introspection server
+func (s *Server) Start(ctx context.Context, cancel context.CancelFunc, port int, cert certificate.Certificater) error {
+	grpcServer, lis, err := utils.NewGrpc(ServerType, port, cert.GetCertificateChain(), cert.GetPrivateKey(), cert.GetIssuingCA())
+	if err != nil {
+		log.Error().Err(err).Msg("Error starting introspection server")
+		return err
+	}
+
+	v1alpha1.RegisterIntrospectionServer(grpcServer, s)
+	go utils.GrpcServe(ctx, grpcServer, lis, cancel, ServerType, nil)
+
+	return nil
+}
+
+// authorize verifies that the client certificate of the call is signed by the mesh CA and is allowed to call the API.
+// No client is allowed unless the allowed clients are configured.
+func (s *Server) authorize(ctx context.Context) error {
+	allowedClients := s.cfg.GetIntrospectionAllowedClients()
+	if len(allowedClients) == 0 {
+		return status.Error(codes.PermissionDenied, "no client is allowed to call the introspection API")
+	}
+
+	allowedCommonNames := make(map[string]interface{})
+	for _, cn := range allowedClients {
+		allowedCommonNames[cn] = nil
+	}
+
+	cn, _, err := utils.ValidateClient(ctx, allowedCommonNames)
+	if err != nil {
+		return err
+	}
+
+	log.Trace().Msgf("Authorized introspection client with CN=%s", cn)
+	return nil
+}
+
+// ListProxies implements v1alpha1.IntrospectionServer
+func (s *Server) ListProxies(ctx context.Context, _ *v1alpha1.ListProxiesRequest) (*v1alpha1.ListProxiesResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &v1alpha1.ListProxiesResponse{}
+	for cn, p := range s.meshCatalogDebugger.ListConnectedProxies() {
+		info := &v1alpha1.Proxy{
+			CommonName:   cn.String(),
+			SerialNumber: p.GetCertificateSerialNumber().String(),
+			PodUid:       p.GetPodUID(),
+			ConnectedAt:  timestamppb.New(p.GetConnectedAt()),
+		}
+		if svcAccount, err := catalog.GetServiceAccountFromProxyCertificate(cn); err == nil {
+			info.ServiceAccount = svcAccount.String()
+		}
+		if ip := p.GetIP(); ip != nil {
+			info.Ip = ip.String()
+		}
+		res.Proxies = append(res.Proxies, info)
+	}
+
+	sort.Slice(res.Proxies, func(i, j int) bool {
+		return res.Proxies[i].CommonName < res.Proxies[j].CommonName
+	})
+
+	return res, nil
+}
+
+// GetComputedPolicies implements v1alpha1.IntrospectionServer
+func (s *Server) GetComputedPolicies(ctx context.Context, req *v1alpha1.GetComputedPoliciesRequest) (*v1alpha1.ComputedPolicies, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	svcAccount, err := service.UnmarshalK8sServiceAccount(req.GetServiceAccount())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid service account %q, expected <namespace>/<name>", req.GetServiceAccount())
+	}
+
+	services, err := s.meshCatalog.GetServicesForServiceAccount(*svcAccount)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up services for service account %s", svcAccount)
+		return nil, status.Errorf(codes.Internal, "error looking up services for service account %s", svcAccount)
+	}
+
+	policies := &v1alpha1.ComputedPolicies{
+		ServiceAccount: svcAccount.String(),
+	}
+	for _, in := range s.meshCatalog.ListInboundTrafficPolicies(*svcAccount, services) {
+		policy := &v1alpha1.InboundPolicy{
+			Name:      in.Name,
+			Hostnames: in.Hostnames,
+		}
+		for _, rule := range in.Rules {
+			var allowed []string
+			for sa := range rule.AllowedServiceAccounts.Iter() {
+				allowed = append(allowed, sa.(service.K8sServiceAccount).String())
+			}
+			sort.Strings(allowed)
+			policy.Rules = append(policy.Rules, &v1alpha1.InboundRule{
+				Route:                  newRoute(rule.Route),
+				AllowedServiceAccounts: allowed,
+			})
+		}
+		policies.Inbound = append(policies.Inbound, policy)
+	}
+	for _, out := range s.meshCatalog.ListOutboundTrafficPolicies(*svcAccount) {
+		policy := &v1alpha1.OutboundPolicy{
+			Name:      out.Name,
+			Hostnames: out.Hostnames,
+		}
+		for _, r := range out.Routes {
+			policy.Routes = append(policy.Routes, newRoute(*r))
+		}
+		policies.Outbound = append(policies.Outbound, policy)
+	}
+
+	return policies, nil
+}
+
+// ListCertificates implements v1alpha1.IntrospectionServer
+func (s *Server) ListCertificates(ctx context.Context, _ *v1alpha1.ListCertificatesRequest) (*v1alpha1.ListCertificatesResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &v1alpha1.ListCertificatesResponse{}
+	for _, cert := range s.certDebugger.ListIssuedCertificates() {
+		res.Certificates = append(res.Certificates, &v1alpha1.Certificate{
+			CommonName:      cert.GetCommonName().String(),
+			SerialNumber:    cert.GetSerialNumber().String(),
+			Expiration:      timestamppb.New(cert.GetExpiration()),
+			IssuingCaSha256: fmt.Sprintf("%x", sha256.Sum256(cert.GetIssuingCA())),
+		})
+	}
+
+	sort.Slice(res.Certificates, func(i, j int) bool {
+		return res.Certificates[i].CommonName < res.Certificates[j].CommonName
+	})
+
+	return res, nil
+}
+
+// ListOutdatedProxies implements v1alpha1.IntrospectionServer
+func (s *Server) ListOutdatedProxies(ctx context.Context, _ *v1alpha1.ListOutdatedProxiesRequest) (*v1alpha1.ListOutdatedProxiesResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &v1alpha1.ListOutdatedProxiesResponse{}
+	for cn, p := range s.meshCatalogDebugger.ListConnectedProxies() {
+		// Proxies not running as sidecars, as node proxies and development proxies, are not injected and are skipped
+		status, err := s.imageChecker.Check(cn)
+		if err != nil {
+			log.Debug().Err(err).Msgf("Skipping sidecar image check of proxy with CN=%s", cn)
+			continue
+		}
+		if !status.Outdated {
+			continue
+		}
+
+		info := &v1alpha1.OutdatedProxy{
+			CommonName:    cn.String(),
+			PodUid:        p.GetPodUID(),
+			Image:         status.Image,
+			ExpectedImage: status.ExpectedImage,
+		}
+		if svcAccount, err := catalog.GetServiceAccountFromProxyCertificate(cn); err == nil {
+			info.ServiceAccount = svcAccount.String()
+		}
+		res.Proxies = append(res.Proxies, info)
+	}
+
+	sort.Slice(res.Proxies, func(i, j int) bool {
+		return res.Proxies[i].CommonName < res.Proxies[j].CommonName
+	})
+
+	return res, nil
+}
+
+func newRoute(r trafficpolicy.RouteWeightedClusters) *v1alpha1.Route {
+	res := &v1alpha1.Route{
+		Path:          r.HTTPRouteMatch.Path,
+		PathMatchType: pathMatchTypeName(r.HTTPRouteMatch.PathMatchType),
+		Methods:       r.HTTPRouteMatch.Methods,
+		Headers:       r.HTTPRouteMatch.Headers,
+	}
+	if r.WeightedClusters != nil {
+		for wc := range r.WeightedClusters.Iter() {
+			cluster := wc.(service.WeightedCluster)
+			res.WeightedClusters = append(res.WeightedClusters, &v1alpha1.WeightedCluster{
+				ClusterName: cluster.ClusterName.String(),
+				Weight:      int32(cluster.Weight),
+			})
+		}
+	}
+	sort.Slice(res.WeightedClusters, func(i, j int) bool {
+		return res.WeightedClusters[i].ClusterName < res.WeightedClusters[j].ClusterName
+	})
+	return res
+}
+
+func pathMatchTypeName(pathMatchType trafficpolicy.PathMatchType) string {
+	switch pathMatchType {
+	case trafficpolicy.PathMatchExact:
+		return "exact"
+	case trafficpolicy.PathMatchPrefix:
+		return "prefix"
+	default:
+		return "regex"
+	}
+}
@@ -0,0 +1,256 @@
+package introspection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	set "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/introspection/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/proxyimage"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const clientCommonName = "portal.example.com"
+
+func newClientContext(t *testing.T, certManager certificate.Manager) context.Context {
+	clientCert, err := certManager.IssueCertificate(clientCommonName, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := certificate.DecodePEMCertificate(clientCert.GetCertificateChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: tests.NewMockAuthInfo(cert)})
+}
+
+func TestAuthorize(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	s := NewServer(nil, nil, nil, nil, mockConfigurator)
+	ctx := newClientContext(t, tresor.NewFakeCertManager(nil))
+
+	testCases := []struct {
+		name           string
+		allowedClients []string
+		ctx            context.Context
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "no client allowed",
+			allowedClients: nil,
+			ctx:            ctx,
+			expectedCode:   codes.PermissionDenied,
+		},
+		{
+			name:           "client not allowed",
+			allowedClients: []string{"other.example.com"},
+			ctx:            ctx,
+			expectedCode:   codes.Unauthenticated,
+		},
+		{
+			name:           "no client certificate",
+			allowedClients: []string{clientCommonName},
+			ctx:            context.Background(),
+			expectedCode:   codes.Unauthenticated,
+		},
+		{
+			name:           "client allowed",
+			allowedClients: []string{"other.example.com", clientCommonName},
+			ctx:            ctx,
+			expectedCode:   codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockConfigurator.EXPECT().GetIntrospectionAllowedClients().Return(tc.allowedClients).Times(1)
+			assert.Equal(tc.expectedCode, status.Code(s.authorize(tc.ctx)))
+		})
+	}
+}
+
+func TestListProxies(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockMeshCatalogDebugger := debugger.NewMockMeshCatalogDebugger(mockCtrl)
+	s := NewServer(nil, mockMeshCatalogDebugger, nil, nil, mockConfigurator)
+
+	cn := certificate.CommonName("8b0d9c4a-f5fa-4f2b-8e8e-0e1f4b1c1d6e.bookbuyer.bookbuyer.cluster.local")
+	p := envoy.NewProxy(cn, "123", tests.NewMockAddress("10.0.0.1"))
+	mockConfigurator.EXPECT().GetIntrospectionAllowedClients().Return([]string{clientCommonName}).Times(1)
+	mockMeshCatalogDebugger.EXPECT().ListConnectedProxies().Return(map[certificate.CommonName]*envoy.Proxy{cn: p}).Times(1)
+
+	res, err := s.ListProxies(newClientContext(t, tresor.NewFakeCertManager(nil)), &v1alpha1.ListProxiesRequest{})
+	assert.Nil(err)
+
+	assert.Len(res.GetProxies(), 1)
+	proxy := res.GetProxies()[0]
+	assert.Equal(cn.String(), proxy.GetCommonName())
+	assert.Equal("123", proxy.GetSerialNumber())
+	assert.Equal("bookbuyer/bookbuyer", proxy.GetServiceAccount())
+	assert.Equal("10.0.0.1", proxy.GetIp())
+	assert.Equal(p.GetConnectedAt().Unix(), proxy.GetConnectedAt().GetSeconds())
+}
+
+func TestGetComputedPolicies(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	s := NewServer(mockCatalog, nil, nil, nil, mockConfigurator)
+	ctx := newClientContext(t, tresor.NewFakeCertManager(nil))
+	mockConfigurator.EXPECT().GetIntrospectionAllowedClients().Return([]string{clientCommonName}).AnyTimes()
+
+	_, err := s.GetComputedPolicies(ctx, &v1alpha1.GetComputedPoliciesRequest{ServiceAccount: "bookstore"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	svcAccount := service.K8sServiceAccount{Namespace: "bookstore", Name: "bookstore"}
+	services := []service.MeshService{{Namespace: "bookstore", Name: "bookstore"}}
+	routeMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          "/books-bought",
+		PathMatchType: trafficpolicy.PathMatchRegex,
+		Methods:       []string{"GET"},
+	}
+	mockCatalog.EXPECT().GetServicesForServiceAccount(svcAccount).Return(services, nil).Times(1)
+	mockCatalog.EXPECT().ListInboundTrafficPolicies(svcAccount, services).Return([]*trafficpolicy.InboundTrafficPolicy{
+		{
+			Name:      "bookstore.bookstore",
+			Hostnames: []string{"bookstore", "bookstore.bookstore"},
+			Rules: []*trafficpolicy.Rule{
+				{
+					Route: trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch:   routeMatch,
+						WeightedClusters: set.NewSet(service.WeightedCluster{ClusterName: "bookstore/bookstore-local", Weight: 100}),
+					},
+					AllowedServiceAccounts: set.NewSet(tests.BookbuyerServiceAccount),
+				},
+			},
+		},
+	}).Times(1)
+	mockCatalog.EXPECT().ListOutboundTrafficPolicies(svcAccount).Return(nil).Times(1)
+
+	res, err := s.GetComputedPolicies(ctx, &v1alpha1.GetComputedPoliciesRequest{ServiceAccount: "bookstore/bookstore"})
+	assert.Nil(err)
+	assert.True(proto.Equal(&v1alpha1.ComputedPolicies{
+		ServiceAccount: "bookstore/bookstore",
+		Inbound: []*v1alpha1.InboundPolicy{
+			{
+				Name:      "bookstore.bookstore",
+				Hostnames: []string{"bookstore", "bookstore.bookstore"},
+				Rules: []*v1alpha1.InboundRule{
+					{
+						Route: &v1alpha1.Route{
+							Path:          "/books-bought",
+							PathMatchType: "regex",
+							Methods:       []string{"GET"},
+							WeightedClusters: []*v1alpha1.WeightedCluster{
+								{ClusterName: "bookstore/bookstore-local", Weight: 100},
+							},
+						},
+						AllowedServiceAccounts: []string{tests.BookbuyerServiceAccount.String()},
+					},
+				},
+			},
+		},
+	}, res), "unexpected computed policies: %s", res)
+}
+
+func TestListCertificates(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCertDebugger := debugger.NewMockCertificateManagerDebugger(mockCtrl)
+	s := NewServer(nil, nil, mockCertDebugger, nil, mockConfigurator)
+
+	certManager := tresor.NewFakeCertManager(nil)
+	cert, err := certManager.IssueCertificate("bookstore.bookstore.cluster.local", time.Hour)
+	assert.Nil(err)
+	mockConfigurator.EXPECT().GetIntrospectionAllowedClients().Return([]string{clientCommonName}).Times(1)
+	mockCertDebugger.EXPECT().ListIssuedCertificates().Return([]certificate.Certificater{cert}).Times(1)
+
+	res, err := s.ListCertificates(newClientContext(t, certManager), &v1alpha1.ListCertificatesRequest{})
+	assert.Nil(err)
+
+	assert.Len(res.GetCertificates(), 1)
+	c := res.GetCertificates()[0]
+	assert.Equal("bookstore.bookstore.cluster.local", c.GetCommonName())
+	assert.Equal(cert.GetSerialNumber().String(), c.GetSerialNumber())
+	assert.Equal(cert.GetExpiration().Unix(), c.GetExpiration().GetSeconds())
+	assert.Len(c.GetIssuingCaSha256(), 64)
+}
+
+type fakeImageChecker map[certificate.CommonName]*proxyimage.Status
+
+func (c fakeImageChecker) Check(cn certificate.CommonName) (*proxyimage.Status, error) {
+	status, ok := c[cn]
+	if !ok {
+		return nil, errors.New("not a sidecar")
+	}
+	return status, nil
+}
+
+func TestListOutdatedProxies(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockMeshCatalogDebugger := debugger.NewMockMeshCatalogDebugger(mockCtrl)
+
+	outdatedCN := certificate.CommonName("8b0d9c4a-f5fa-4f2b-8e8e-0e1f4b1c1d6e.bookbuyer.bookbuyer.cluster.local")
+	currentCN := certificate.CommonName("1c2d3e4f-f5fa-4f2b-8e8e-0e1f4b1c1d6e.bookstore.bookstore.cluster.local")
+	nodeProxyCN := certificate.CommonName("node-proxy")
+	imageChecker := fakeImageChecker{
+		outdatedCN: {Image: "envoyproxy/envoy-alpine:v1.16.0", ExpectedImage: "envoyproxy/envoy-alpine:v1.17.2", Outdated: true},
+		currentCN:  {Image: "envoyproxy/envoy-alpine:v1.17.2", ExpectedImage: "envoyproxy/envoy-alpine:v1.17.2"},
+	}
+	s := NewServer(nil, mockMeshCatalogDebugger, nil, imageChecker, mockConfigurator)
+
+	mockConfigurator.EXPECT().GetIntrospectionAllowedClients().Return([]string{clientCommonName}).Times(1)
+	mockMeshCatalogDebugger.EXPECT().ListConnectedProxies().Return(map[certificate.CommonName]*envoy.Proxy{
+		outdatedCN:  envoy.NewProxy(outdatedCN, "123", tests.NewMockAddress("10.0.0.1")),
+		currentCN:   envoy.NewProxy(currentCN, "456", tests.NewMockAddress("10.0.0.2")),
+		nodeProxyCN: envoy.NewProxy(nodeProxyCN, "789", tests.NewMockAddress("10.0.0.3")),
+	}).Times(1)
+
+	res, err := s.ListOutdatedProxies(newClientContext(t, tresor.NewFakeCertManager(nil)), &v1alpha1.ListOutdatedProxiesRequest{})
+	assert.Nil(err)
+	assert.True(proto.Equal(&v1alpha1.ListOutdatedProxiesResponse{
+		Proxies: []*v1alpha1.OutdatedProxy{
+			{
+				CommonName:     outdatedCN.String(),
+				ServiceAccount: "bookbuyer/bookbuyer",
+				Image:          "envoyproxy/envoy-alpine:v1.16.0",
+				ExpectedImage:  "envoyproxy/envoy-alpine:v1.17.2",
+			},
+		},
+	}, res), "unexpected outdated proxies: %s", res)
+}
@@ -0,0 +1,34 @@
+// Package introspection implements the gRPC API of the OSM controller used by internal platforms to introspect the mesh:
+// the connected proxies, the traffic policies computed for service accounts, and the issued certificates.
+package introspection
+
+import (
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/proxyimage"
+)
+
+var log = logger.New("introspection")
+
+const (
+	// ServerType is the type identifier for the introspection server
+	ServerType = "Introspection"
+)
+
+// Server implements the introspection gRPC service
+type Server struct {
+	meshCatalog         catalog.MeshCataloger
+	meshCatalogDebugger debugger.MeshCatalogDebugger
+	certDebugger        debugger.CertificateManagerDebugger
+	imageChecker        ProxyImageChecker
+	cfg                 configurator.Configurator
+}
+
+// ProxyImageChecker checks whether the sidecar images of the proxies are the images currently injected
+type ProxyImageChecker interface {
+	// Check returns the sidecar image status of the proxy with the given xDS certificate common name
+	Check(certificate.CommonName) (*proxyimage.Status, error)
+}
@@ -0,0 +1,201 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// meshResourceNamespaces, meshResourceServices and meshResourceProxies are the values of the resource label of the
+	// mesh limit metrics
+	meshResourceNamespaces = "namespaces"
+	meshResourceServices   = "services"
+	meshResourceProxies    = "proxies"
+)
+
+// namespaceAdmission holds the namespaces admitted to the mesh within the limits on the size of the mesh
+type namespaceAdmission struct {
+	sync.RWMutex
+
+	// initialized is true once the namespaces present when the caches synced have been admitted
+	initialized bool
+
+	// admitted and notAdmitted are the sets of namespaces labeled for monitoring that are, and are not, admitted
+	admitted    map[string]bool
+	notAdmitted map[string]bool
+}
+
+// meshSize is the number of namespaces, services and proxies in the namespaces admitted to the mesh
+type meshSize struct {
+	namespaces int
+	services   int
+	proxies    int
+}
+
+// isAdmittedNamespace returns true if the given namespace labeled for monitoring is admitted to the mesh.
+// All the namespaces are admitted until the namespaces present when the caches synced have been admitted.
+func (c Client) isAdmittedNamespace(namespace string) bool {
+	if c.meshLimits == nil {
+		return true
+	}
+
+	c.admission.RLock()
+	defer c.admission.RUnlock()
+	return !c.admission.initialized || c.admission.admitted[namespace]
+}
+
+// admitNamespaces admits the namespaces labeled for monitoring to the mesh in the order they were created, as long as
+// the mesh stays within its limits once a namespace is admitted. Admitted namespaces are never evicted from the mesh,
+// even if they grow beyond the limits, so that reaching a limit only prevents new namespaces from joining the mesh.
+func (c Client) admitNamespaces() {
+	if c.meshLimits == nil {
+		return
+	}
+	limits := meshSize{
+		namespaces: c.meshLimits.GetMaxMonitoredNamespaces(),
+		services:   c.meshLimits.GetMaxServices(),
+		proxies:    c.meshLimits.GetMaxProxies(),
+	}
+
+	c.admission.Lock()
+	defer c.admission.Unlock()
+
+	var candidates []*corev1.Namespace
+	labeled := make(map[string]bool)
+	for _, nsIf := range c.informers[Namespaces].GetStore().List() {
+		ns, ok := nsIf.(*corev1.Namespace)
+		if !ok || c.isExcludedNamespace(ns.Name) {
+			continue
+		}
+		labeled[ns.Name] = true
+		if !c.admission.admitted[ns.Name] {
+			candidates = append(candidates, ns)
+		}
+	}
+
+	// Namespaces no longer labeled for monitoring leave the mesh
+	changed := false
+	for ns := range c.admission.admitted {
+		if !labeled[ns] {
+			delete(c.admission.admitted, ns)
+			changed = true
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].CreationTimestamp.Equal(&candidates[j].CreationTimestamp) {
+			return candidates[i].CreationTimestamp.Before(&candidates[j].CreationTimestamp)
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	size := meshSize{namespaces: len(c.admission.admitted)}
+	for ns := range c.admission.admitted {
+		size.services += c.countServices(ns)
+		size.proxies += c.countProxies(ns)
+	}
+
+	notAdmitted := make(map[string]bool)
+	for _, ns := range candidates {
+		admittedSize := meshSize{
+			namespaces: size.namespaces + 1,
+			services:   size.services + c.countServices(ns.Name),
+			proxies:    size.proxies + c.countProxies(ns.Name),
+		}
+		if reason := admittedSize.exceeds(limits); reason != "" {
+			notAdmitted[ns.Name] = true
+			if !c.admission.notAdmitted[ns.Name] {
+				events.GenericEventRecorder().WarnEvent(events.NamespaceNotAdmitted,
+					"Namespace %s is labeled for monitoring but is not admitted to the mesh: %s", ns.Name, reason)
+			}
+			continue
+		}
+
+		c.admission.admitted[ns.Name] = true
+		size = admittedSize
+		changed = true
+		if c.admission.notAdmitted[ns.Name] {
+			log.Info().Msgf("Namespace %s is admitted to the mesh", ns.Name)
+		}
+	}
+	c.admission.notAdmitted = notAdmitted
+
+	updateMeshLimitMetrics(limits, size, len(notAdmitted))
+
+	// Namespaces admitted after the caches synced change the resources that are part of the mesh
+	if changed && c.admission.initialized {
+		events.GetPubSubInstance().Publish(events.PubSubMessage{
+			AnnouncementType: announcements.ScheduleProxyBroadcast,
+		})
+	}
+	c.admission.initialized = true
+}
+
+// exceeds returns the reason why the mesh of the given size exceeds the given limits, or an empty string if it does not
+func (s meshSize) exceeds(limits meshSize) string {
+	switch {
+	case limits.namespaces > 0 && s.namespaces > limits.namespaces:
+		return fmt.Sprintf("the mesh is limited to %d namespaces by the max_monitored_namespaces setting", limits.namespaces)
+	case limits.services > 0 && s.services > limits.services:
+		return fmt.Sprintf("the mesh would have %d services, beyond the max_services setting of %d", s.services, limits.services)
+	case limits.proxies > 0 && s.proxies > limits.proxies:
+		return fmt.Sprintf("the mesh would have %d proxies, beyond the max_proxies setting of %d", s.proxies, limits.proxies)
+	default:
+		return ""
+	}
+}
+
+// countServices returns the number of services in the given namespace
+func (c Client) countServices(namespace string) int {
+	informer, ok := c.informers[Services]
+	if !ok {
+		return 0
+	}
+	services, err := informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error counting the services in namespace %s", namespace)
+		return 0
+	}
+	return len(services)
+}
+
+// countProxies returns the number of pods injected with a sidecar proxy in the given namespace
+func (c Client) countProxies(namespace string) int {
+	informer, ok := c.informers[Pods]
+	if !ok {
+		return 0
+	}
+	pods, err := informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error counting the proxies in namespace %s", namespace)
+		return 0
+	}
+
+	count := 0
+	for _, podIf := range pods {
+		if pod, ok := podIf.(*corev1.Pod); ok && labels.Set(pod.Labels).Has(constants.EnvoyUniqueIDLabelName) {
+			count++
+		}
+	}
+	return count
+}
+
+func updateMeshLimitMetrics(limits meshSize, size meshSize, notAdmitted int) {
+	metricsstore.DefaultMetricsStore.K8sMeshLimit.WithLabelValues(meshResourceNamespaces).Set(float64(limits.namespaces))
+	metricsstore.DefaultMetricsStore.K8sMeshLimit.WithLabelValues(meshResourceServices).Set(float64(limits.services))
+	metricsstore.DefaultMetricsStore.K8sMeshLimit.WithLabelValues(meshResourceProxies).Set(float64(limits.proxies))
+	metricsstore.DefaultMetricsStore.K8sMeshSize.WithLabelValues(meshResourceNamespaces).Set(float64(size.namespaces))
+	metricsstore.DefaultMetricsStore.K8sMeshSize.WithLabelValues(meshResourceServices).Set(float64(size.services))
+	metricsstore.DefaultMetricsStore.K8sMeshSize.WithLabelValues(meshResourceProxies).Set(float64(size.proxies))
+	metricsstore.DefaultMetricsStore.K8sNamespaceNotAdmittedCount.Set(float64(notAdmitted))
+}
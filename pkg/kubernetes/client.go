@@ -7,8 +7,10 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -18,14 +20,25 @@ import (
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
-// NewKubernetesController returns a new kubernetes.Controller which means to provide access to locally-cached k8s resources
-func NewKubernetesController(kubeClient kubernetes.Interface, meshName string, stop chan struct{}, selectInformers ...InformerKey) (Controller, error) {
+// NewKubernetesController returns a new kubernetes.Controller which means to provide access to locally-cached k8s resources.
+// Namespaces excluded by the given namespaceExclusions are never monitored, even if they are labeled for monitoring.
+// A nil namespaceExclusions excludes no namespace.
+// Namespaces labeled for monitoring are only admitted to the mesh within the given meshLimits, which count the services
+// and proxies of the mesh with the Services and Pods informers. A nil meshLimits admits all the namespaces.
+// The dynamic client watches the fields of Services not known to the Service type of client-go. It can be nil when the
+// Services informer is not selected, and the internal traffic policy of all the Services is then Cluster, as it is when
+// the API server does not serve the field.
+func NewKubernetesController(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, meshName string, namespaceExclusions NamespaceExclusions, meshLimits MeshLimits, stop chan struct{}, selectInformers ...InformerKey) (Controller, error) {
 	// Initialize client object
 	client := Client{
-		kubeClient:  kubeClient,
-		meshName:    meshName,
-		informers:   informerCollection{},
-		cacheSynced: make(chan interface{}),
+		kubeClient:          kubeClient,
+		dynamicClient:       dynamicClient,
+		meshName:            meshName,
+		informers:           informerCollection{},
+		cacheSynced:         make(chan interface{}),
+		namespaceExclusions: namespaceExclusions,
+		meshLimits:          meshLimits,
+		admission:           &namespaceAdmission{admitted: make(map[string]bool)},
 	}
 
 	// Initialize informers
@@ -75,6 +88,25 @@ func (c *Client) initNamespaceMonitor() {
 		Delete: announcements.NamespaceDeleted,
 	}
 	c.informers[Namespaces].AddEventHandler(GetKubernetesEventHandlers((string)(Namespaces), providerName, nil, nsEventTypes))
+
+	// Admit the namespaces added after the caches synced to the mesh, and periodically retry admitting the namespaces
+	// not admitted on the resync of the informer
+	c.informers[Namespaces].AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { c.admitPendingNamespaces() },
+		UpdateFunc: func(_, _ interface{}) { c.admitPendingNamespaces() },
+		DeleteFunc: func(_ interface{}) { c.admitPendingNamespaces() },
+	})
+}
+
+// admitPendingNamespaces admits the namespaces labeled for monitoring to the mesh once the namespaces present when the
+// caches synced have been admitted
+func (c *Client) admitPendingNamespaces() {
+	c.admission.RLock()
+	initialized := c.admission.initialized
+	c.admission.RUnlock()
+	if initialized {
+		c.admitNamespaces()
+	}
 }
 
 // Function to filter K8s meta Objects by OSM's isMonitoredNamespace
@@ -94,6 +126,14 @@ func (c *Client) initServicesMonitor() {
 		Delete: announcements.ServiceDeleted,
 	}
 	c.informers[Services].AddEventHandler(GetKubernetesEventHandlers((string)(Services), providerName, c.shouldObserve, svcEventTypes))
+
+	// The internalTrafficPolicy field is not part of the Service type of client-go, so Services are also watched as
+	// unstructured objects to read it when the API server serves it. A change of the field is announced by the Services
+	// informer, whose update events are published for any change of the resource version of a Service.
+	if c.dynamicClient == nil || !isInternalTrafficPolicyServed(c.kubeClient.Discovery()) {
+		return
+	}
+	c.informers[unstructuredServices] = newInternalTrafficPolicyInformer(c.dynamicClient)
 }
 
 // Initializes Service Account monitoring
@@ -112,6 +152,9 @@ func (c *Client) initServiceAccountsMonitor() {
 func (c *Client) initPodMonitor() {
 	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, DefaultKubeEventResyncInterval)
 	c.informers[Pods] = informerFactory.Core().V1().Pods().Informer()
+	if err := c.informers[Pods].AddIndexers(cache.Indexers{podIPIndex: podIPIndexFunc}); err != nil {
+		log.Error().Err(err).Msgf("Error adding the %s index to the Pods informer", podIPIndex)
+	}
 
 	podEventTypes := EventTypes{
 		Add:    announcements.PodAdded,
@@ -157,6 +200,9 @@ func (c *Client) run(stop <-chan struct{}) error {
 		return errSyncingCaches
 	}
 
+	// Admit the namespaces present in the synced caches to the mesh in the order they were created
+	c.admitNamespaces()
+
 	// Closing the cacheSynced channel signals to the rest of the system that caches have synced.
 	close(c.cacheSynced)
 	log.Info().Msgf("Caches for %+s synced successfully", names)
@@ -166,8 +212,16 @@ func (c *Client) run(stop <-chan struct{}) error {
 
 // IsMonitoredNamespace returns a boolean indicating if the namespace is among the list of monitored namespaces
 func (c Client) IsMonitoredNamespace(namespace string) bool {
+	if c.isExcludedNamespace(namespace) {
+		return false
+	}
 	_, exists, _ := c.informers[Namespaces].GetStore().GetByKey(namespace)
-	return exists
+	return exists && c.isAdmittedNamespace(namespace)
+}
+
+// isExcludedNamespace returns a boolean indicating if the namespace is excluded from the mesh regardless of its labels
+func (c Client) isExcludedNamespace(namespace string) bool {
+	return c.namespaceExclusions != nil && c.namespaceExclusions.IsExcludedNamespace(namespace)
 }
 
 // ListMonitoredNamespaces returns all namespaces that the mesh is monitoring.
@@ -180,6 +234,9 @@ func (c Client) ListMonitoredNamespaces() ([]string, error) {
 			log.Error().Err(errListingNamespaces).Msg("Failed to list monitored namespaces")
 			continue
 		}
+		if c.isExcludedNamespace(namespace.Name) || !c.isAdmittedNamespace(namespace.Name) {
+			continue
+		}
 		namespaces = append(namespaces, namespace.Name)
 	}
 	return namespaces, nil
@@ -252,6 +309,65 @@ func (c Client) ListPods() []*corev1.Pod {
 	return pods
 }
 
+// ListPodsByIP returns the pods part of the mesh with the given IP
+func (c Client) ListPodsByIP(ip string) []*corev1.Pod {
+	podInterfaces, err := c.informers[Pods].GetIndexer().ByIndex(podIPIndex, ip)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error looking up pods with IP %s", ip)
+		return nil
+	}
+
+	var pods []*corev1.Pod
+	for _, podInterface := range podInterfaces {
+		pod := podInterface.(*corev1.Pod)
+		if !c.IsMonitoredNamespace(pod.Namespace) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// podIPIndexFunc indexes the pods by their IPs
+func podIPIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+
+	ips := mapset.NewSet()
+	if pod.Status.PodIP != "" {
+		ips.Add(pod.Status.PodIP)
+	}
+	for _, podIP := range pod.Status.PodIPs {
+		ips.Add(podIP.IP)
+	}
+
+	var indexValues []string
+	for ip := range ips.Iter() {
+		indexValues = append(indexValues, ip.(string))
+	}
+	return indexValues, nil
+}
+
+// GetServiceInternalTrafficPolicy returns the internal traffic policy of the given service, Cluster if it is not set
+func (c Client) GetServiceInternalTrafficPolicy(svc service.MeshService) string {
+	informer, ok := c.informers[unstructuredServices]
+	if !ok {
+		return ServiceInternalTrafficPolicyCluster
+	}
+
+	svcIf, exists, err := informer.GetStore().GetByKey(svc.String())
+	if !exists || err != nil {
+		return ServiceInternalTrafficPolicyCluster
+	}
+	policy, found, err := unstructured.NestedString(svcIf.(*unstructured.Unstructured).Object, "spec", "internalTrafficPolicy")
+	if !found || err != nil || policy == "" {
+		return ServiceInternalTrafficPolicyCluster
+	}
+	return policy
+}
+
 // GetEndpoints returns the endpoint for a given service, otherwise returns nil if not found
 // or error if the API errored out.
 func (c Client) GetEndpoints(svc service.MeshService) (*corev1.Endpoints, error) {
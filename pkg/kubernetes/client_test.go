@@ -11,6 +11,12 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
@@ -34,7 +40,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 			// Create namespace controller
 			kubeClient := testclient.NewSimpleClientset()
 			stop := make(chan struct{})
-			kubeController, err := NewKubernetesController(kubeClient, testMeshName, stop)
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, stop)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 
@@ -62,7 +68,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 			// Create namespace controller
 			kubeClient := testclient.NewSimpleClientset()
 			stop := make(chan struct{})
-			kubeController, err := NewKubernetesController(kubeClient, testMeshName, stop)
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, stop)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 
@@ -100,7 +106,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 			// Create namespace controller
 			kubeClient := testclient.NewSimpleClientset()
 			stop := make(chan struct{})
-			kubeController, err := NewKubernetesController(kubeClient, testMeshName, stop)
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, stop)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 
@@ -123,6 +129,39 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 			fakeNamespaceIsMonitored := kubeController.IsMonitoredNamespace("fake")
 			Expect(fakeNamespaceIsMonitored).To(BeFalse())
 		})
+
+		It("should ignore excluded namespaces", func() {
+			// Create namespace controller excluding a namespace
+			kubeClient := testclient.NewSimpleClientset()
+			stop := make(chan struct{})
+			excludedNamespaceName := fmt.Sprintf("%s-excluded", tests.Namespace)
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, fakeNamespaceExclusions{excludedNamespaceName}, nil, stop)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeController).ToNot(BeNil())
+
+			// Create monitored namespaces, one of which is excluded
+			testNamespaceName := fmt.Sprintf("%s-1", tests.Namespace)
+			for _, name := range []string{testNamespaceName, excludedNamespaceName} {
+				testNamespace := corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+					},
+				}
+				_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &testNamespace, metav1.CreateOptions{})
+				Expect(err).To(BeNil())
+			}
+
+			Eventually(func() bool {
+				return kubeController.IsMonitoredNamespace(testNamespaceName)
+			}, nsInformerSyncTimeout).Should(BeTrue())
+			Eventually(func() *corev1.Namespace {
+				return kubeController.GetNamespace(excludedNamespaceName)
+			}, nsInformerSyncTimeout).ShouldNot(BeNil())
+
+			Expect(kubeController.IsMonitoredNamespace(excludedNamespaceName)).To(BeFalse())
+			Expect(kubeController.ListMonitoredNamespaces()).To(Equal([]string{testNamespaceName}))
+		})
 	})
 
 	Context("service controller", func() {
@@ -132,7 +171,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 
 		BeforeEach(func() {
 			kubeClient = testclient.NewSimpleClientset()
-			kubeController, err = NewKubernetesController(kubeClient, testMeshName, make(chan struct{}))
+			kubeController, err = NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, make(chan struct{}))
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 		})
@@ -248,7 +287,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 
 		BeforeEach(func() {
 			kubeClient = testclient.NewSimpleClientset()
-			kubeController, err = NewKubernetesController(kubeClient, testMeshName, make(chan struct{}))
+			kubeController, err = NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, make(chan struct{}))
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 		})
@@ -352,7 +391,7 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 
 		BeforeEach(func() {
 			kubeClient = testclient.NewSimpleClientset()
-			kubeController, err = NewKubernetesController(kubeClient, testMeshName, make(chan struct{}))
+			kubeController, err = NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, make(chan struct{}))
 			Expect(err).ToNot(HaveOccurred())
 			Expect(kubeController).ToNot(BeNil())
 		})
@@ -551,4 +590,217 @@ var _ = Describe("Test Namespace KubeController Methods", func() {
 
 	})
 
+	Context("Testing ListPodsByIP", func() {
+		It("should return the pods of monitored namespaces with the given IP", func() {
+			kubeClient := testclient.NewSimpleClientset()
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, nil, nil, make(chan struct{}))
+			Expect(err).ToNot(HaveOccurred())
+
+			testNamespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   tests.Namespace,
+					Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+				},
+			}
+			_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), testNamespace, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() bool {
+				return kubeController.IsMonitoredNamespace(testNamespace.Name)
+			}, nsInformerSyncTimeout).Should(BeTrue())
+
+			pod := tests.NewPodFixture(tests.Namespace, "bookstore", tests.BookstoreServiceAccountName, nil)
+			pod.Status.PodIP = "10.0.0.1"
+			pod.Status.PodIPs = []corev1.PodIP{{IP: "10.0.0.1"}, {IP: "fd00::1"}}
+			_, err = kubeClient.CoreV1().Pods(tests.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			unmonitoredPod := tests.NewPodFixture("unmonitored", "bookstore", tests.BookstoreServiceAccountName, nil)
+			unmonitoredPod.Status.PodIP = "10.0.0.1"
+			_, err = kubeClient.CoreV1().Pods(unmonitoredPod.Namespace).Create(context.TODO(), &unmonitoredPod, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() []*corev1.Pod {
+				return kubeController.ListPodsByIP("10.0.0.1")
+			}, nsInformerSyncTimeout).Should(ConsistOf(&pod))
+			Eventually(func() []*corev1.Pod {
+				return kubeController.ListPodsByIP("fd00::1")
+			}, nsInformerSyncTimeout).Should(ConsistOf(&pod))
+			Expect(kubeController.ListPodsByIP("10.0.0.2")).To(BeEmpty())
+		})
+	})
+
+	Context("Testing GetServiceInternalTrafficPolicy", func() {
+		It("should return the internal traffic policy of the service", func() {
+			svc := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name":      tests.BookstoreV1Service.Name,
+					"namespace": tests.BookstoreV1Service.Namespace,
+				},
+				"spec": map[string]interface{}{
+					"internalTrafficPolicy": ServiceInternalTrafficPolicyLocal,
+				},
+			}}
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{corev1.SchemeGroupVersion.WithResource("services"): "ServiceList"}, svc)
+			kubeClient := testclient.NewSimpleClientset()
+			kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.22.0"}
+
+			kubeController, err := NewKubernetesController(kubeClient, dynamicClient, testMeshName, nil, nil, make(chan struct{}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(kubeController.GetServiceInternalTrafficPolicy(tests.BookstoreV1Service)).To(Equal(ServiceInternalTrafficPolicyLocal))
+			Expect(kubeController.GetServiceInternalTrafficPolicy(tests.BookstoreV2Service)).To(Equal(ServiceInternalTrafficPolicyCluster))
+		})
+
+		It("should not watch Services as unstructured objects when the API server does not serve the field", func() {
+			kubeClient := testclient.NewSimpleClientset()
+			kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.20.5"}
+
+			kubeController, err := NewKubernetesController(kubeClient, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), testMeshName, nil, nil, make(chan struct{}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(kubeController.(Client).informers).ToNot(HaveKey(unstructuredServices))
+			Expect(kubeController.GetServiceInternalTrafficPolicy(tests.BookstoreV1Service)).To(Equal(ServiceInternalTrafficPolicyCluster))
+		})
+
+		It("should return Cluster when Services are not watched as unstructured objects", func() {
+			kubeController, err := NewKubernetesController(testclient.NewSimpleClientset(), nil, testMeshName, nil, nil, make(chan struct{}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(kubeController.GetServiceInternalTrafficPolicy(tests.BookstoreV1Service)).To(Equal(ServiceInternalTrafficPolicyCluster))
+		})
+	})
+
+	Context("Testing mesh limits", func() {
+		// newLimitedController returns a controller limited by the given limits, for namespaces created a minute apart
+		// in the order of the given services and proxies per namespace
+		newLimitedController := func(limits fakeMeshLimits, services, proxies []int) (*testclient.Clientset, Controller) {
+			kubeClient := testclient.NewSimpleClientset()
+			created := time.Now().Add(-time.Hour)
+			for i := range services {
+				ns := fmt.Sprintf("%s-%d", tests.Namespace, i)
+				_, err := kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              ns,
+						Labels:            map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+						CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Minute)),
+					},
+				}, metav1.CreateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+
+				for j := 0; j < services[i]; j++ {
+					_, err = kubeClient.CoreV1().Services(ns).Create(context.TODO(), &corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("svc-%d", j), Namespace: ns},
+					}, metav1.CreateOptions{})
+					Expect(err).ToNot(HaveOccurred())
+				}
+				for j := 0; j < proxies[i]; j++ {
+					_, err = kubeClient.CoreV1().Pods(ns).Create(context.TODO(), &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      fmt.Sprintf("pod-%d", j),
+							Namespace: ns,
+							Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: uuid.New().String()},
+						},
+					}, metav1.CreateOptions{})
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				// Pods without a sidecar proxy are not counted
+				_, err = kubeClient.CoreV1().Pods(ns).Create(context.TODO(), &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "unmeshed", Namespace: ns},
+				}, metav1.CreateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, nil, limits, make(chan struct{}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeController).ToNot(BeNil())
+			return kubeClient, kubeController
+		}
+
+		It("should admit all the namespaces when no limit is set", func() {
+			_, kubeController := newLimitedController(fakeMeshLimits{}, []int{1, 2, 3}, []int{1, 2, 3})
+
+			Expect(kubeController.ListMonitoredNamespaces()).To(ConsistOf(
+				fmt.Sprintf("%s-0", tests.Namespace), fmt.Sprintf("%s-1", tests.Namespace), fmt.Sprintf("%s-2", tests.Namespace)))
+		})
+
+		It("should admit the oldest namespaces within the limits", func() {
+			_, kubeController := newLimitedController(fakeMeshLimits{namespaces: 2, services: 4}, []int{2, 3, 1, 1}, []int{0, 0, 0, 0})
+
+			// The second namespace would take the mesh beyond 4 services, and the fourth beyond 2 namespaces
+			Expect(kubeController.ListMonitoredNamespaces()).To(ConsistOf(fmt.Sprintf("%s-0", tests.Namespace), fmt.Sprintf("%s-2", tests.Namespace)))
+			Expect(kubeController.IsMonitoredNamespace(fmt.Sprintf("%s-1", tests.Namespace))).To(BeFalse())
+			Expect(kubeController.IsMonitoredNamespace(fmt.Sprintf("%s-3", tests.Namespace))).To(BeFalse())
+		})
+
+		It("should stop admitting new namespaces once the proxy limit is reached", func() {
+			kubeClient, kubeController := newLimitedController(fakeMeshLimits{proxies: 2}, []int{0, 0}, []int{2, 1})
+
+			Expect(kubeController.ListMonitoredNamespaces()).To(Equal([]string{fmt.Sprintf("%s-0", tests.Namespace)}))
+
+			// Namespaces created after the caches synced that would take the mesh beyond the limit are not admitted either
+			newNamespaceName := fmt.Sprintf("%s-new", tests.Namespace)
+			_, err := kubeClient.CoreV1().Pods(newNamespaceName).Create(context.TODO(), &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-0",
+					Namespace: newNamespaceName,
+					Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: uuid.New().String()},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() int {
+				return kubeController.(Client).countProxies(newNamespaceName)
+			}, nsInformerSyncTimeout).Should(Equal(1))
+
+			_, err = kubeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   newNamespaceName,
+					Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() *corev1.Namespace {
+				return kubeController.GetNamespace(newNamespaceName)
+			}, nsInformerSyncTimeout).ShouldNot(BeNil())
+			Consistently(func() bool {
+				return kubeController.IsMonitoredNamespace(newNamespaceName)
+			}, time.Second).Should(BeFalse())
+		})
+	})
+
 })
+
+// fakeMeshLimits is a MeshLimits with the given limits
+type fakeMeshLimits struct {
+	namespaces int
+	services   int
+	proxies    int
+}
+
+func (l fakeMeshLimits) GetMaxMonitoredNamespaces() int {
+	return l.namespaces
+}
+
+func (l fakeMeshLimits) GetMaxServices() int {
+	return l.services
+}
+
+func (l fakeMeshLimits) GetMaxProxies() int {
+	return l.proxies
+}
+
+// fakeNamespaceExclusions is a NamespaceExclusions excluding the namespaces in the list
+type fakeNamespaceExclusions []string
+
+func (e fakeNamespaceExclusions) IsExcludedNamespace(namespace string) bool {
+	for _, ns := range e {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
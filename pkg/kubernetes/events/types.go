@@ -28,6 +28,24 @@ const (
 	CertificateIssuanceFailure = "FatalCertificateIssuanceFailure"
 )
 
+// Kubernetes Warning Event reasons
+const (
+	// ProxyConfigThrottled signifies that an xDS response was withheld from a proxy because it exceeded the configured max size
+	ProxyConfigThrottled = "ProxyConfigThrottled"
+
+	// ProxyConfigInvalid signifies that an xDS response was withheld from a proxy because it had resources violating the validation rules of the Envoy API
+	ProxyConfigInvalid = "ProxyConfigInvalid"
+
+	// IngressPathMatchOverridden signifies that an ImplementationSpecific path of an ingress resource is matched differently than guessed from the characters of the path
+	IngressPathMatchOverridden = "IngressPathMatchOverridden"
+
+	// NamespaceNotAdmitted signifies that a namespace labeled for monitoring is not admitted to the mesh because the mesh reached its configured limits
+	NamespaceNotAdmitted = "NamespaceNotAdmitted"
+
+	// NodeProxyRedirectionMissing signifies that a pod in node proxy mode runs on a node where the redirection of its traffic to the node proxy is not programmed, so its traffic bypasses the mesh
+	NodeProxyRedirectionMissing = "NodeProxyRedirectionMissing"
+)
+
 // PubSubMessage represents a common messages abstraction to pass through the PubSub interface
 type PubSubMessage struct {
 	AnnouncementType announcements.AnnouncementType
@@ -0,0 +1,83 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+// internalTrafficPolicyMinServerVersion is the first Kubernetes version whose API server serves the
+// internalTrafficPolicy field of Services
+var internalTrafficPolicyMinServerVersion = utilversion.MustParseGeneric("v1.21.0")
+
+// isInternalTrafficPolicyServed returns true if the Kubernetes API server serves the internalTrafficPolicy field of
+// Services. Servers whose version cannot be determined are considered not to serve it.
+func isInternalTrafficPolicyServed(client discovery.ServerVersionInterface) bool {
+	info, err := client.ServerVersion()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting the version of the Kubernetes API server, the internal traffic policy of Services is not watched")
+		return false
+	}
+	serverVersion, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing the version %s of the Kubernetes API server, the internal traffic policy of Services is not watched", info.GitVersion)
+		return false
+	}
+	return serverVersion.AtLeast(internalTrafficPolicyMinServerVersion)
+}
+
+// newInternalTrafficPolicyInformer returns an informer watching Services as unstructured objects to read their
+// internalTrafficPolicy field, which is not part of the Service type of client-go. Only the metadata identifying the
+// Services and the field itself are kept in its cache, so that Services are not cached twice along with the Services
+// informer.
+func newInternalTrafficPolicyInformer(dynamicClient dynamic.Interface) cache.SharedIndexInformer {
+	services := dynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("services"))
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			list, err := services.List(context.Background(), options)
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				list.Items[i] = *withInternalTrafficPolicyOnly(&list.Items[i])
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := services.Watch(context.Background(), options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if svc, ok := event.Object.(*unstructured.Unstructured); ok {
+					event.Object = withInternalTrafficPolicyOnly(svc)
+				}
+				return event, true
+			}), nil
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, DefaultKubeEventResyncInterval, cache.Indexers{})
+}
+
+// withInternalTrafficPolicyOnly returns a copy of the given unstructured Service only retaining its name, namespace,
+// resource version and internal traffic policy
+func withInternalTrafficPolicyOnly(svc *unstructured.Unstructured) *unstructured.Unstructured {
+	stripped := &unstructured.Unstructured{}
+	stripped.SetAPIVersion(svc.GetAPIVersion())
+	stripped.SetKind(svc.GetKind())
+	stripped.SetName(svc.GetName())
+	stripped.SetNamespace(svc.GetNamespace())
+	stripped.SetResourceVersion(svc.GetResourceVersion())
+	if policy, found, err := unstructured.NestedString(svc.Object, "spec", "internalTrafficPolicy"); found && err == nil {
+		_ = unstructured.SetNestedField(stripped.Object, policy, "spec", "internalTrafficPolicy")
+	}
+	return stripped
+}
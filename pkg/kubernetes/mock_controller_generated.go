@@ -78,6 +78,20 @@ func (mr *MockControllerMockRecorder) GetService(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetService", reflect.TypeOf((*MockController)(nil).GetService), arg0)
 }
 
+// GetServiceInternalTrafficPolicy mocks base method
+func (m *MockController) GetServiceInternalTrafficPolicy(arg0 service.MeshService) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceInternalTrafficPolicy", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetServiceInternalTrafficPolicy indicates an expected call of GetServiceInternalTrafficPolicy
+func (mr *MockControllerMockRecorder) GetServiceInternalTrafficPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceInternalTrafficPolicy", reflect.TypeOf((*MockController)(nil).GetServiceInternalTrafficPolicy), arg0)
+}
+
 // IsMonitoredNamespace mocks base method
 func (m *MockController) IsMonitoredNamespace(arg0 string) bool {
 	m.ctrl.T.Helper()
@@ -121,6 +135,20 @@ func (mr *MockControllerMockRecorder) ListPods() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPods", reflect.TypeOf((*MockController)(nil).ListPods))
 }
 
+// ListPodsByIP mocks base method
+func (m *MockController) ListPodsByIP(arg0 string) []*v1.Pod {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPodsByIP", arg0)
+	ret0, _ := ret[0].([]*v1.Pod)
+	return ret0
+}
+
+// ListPodsByIP indicates an expected call of ListPodsByIP
+func (mr *MockControllerMockRecorder) ListPodsByIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPodsByIP", reflect.TypeOf((*MockController)(nil).ListPodsByIP), arg0)
+}
+
 // ListServiceAccounts mocks base method
 func (m *MockController) ListServiceAccounts() []*v1.ServiceAccount {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -57,6 +58,23 @@ const (
 	Endpoints InformerKey = "Endpoints"
 	// ServiceAccounts lookup identifier
 	ServiceAccounts InformerKey = "ServiceAccounts"
+
+	// unstructuredServices lookup identifier of the informer watching Services as unstructured objects, initialized
+	// along with the Services informer
+	unstructuredServices InformerKey = "UnstructuredServices"
+)
+
+const (
+	// ServiceInternalTrafficPolicyCluster is the internal traffic policy of a Service routing the traffic from within
+	// the cluster to all its endpoints
+	ServiceInternalTrafficPolicyCluster = "Cluster"
+
+	// ServiceInternalTrafficPolicyLocal is the internal traffic policy of a Service only routing the traffic from within
+	// the cluster to the endpoints on the node of the client
+	ServiceInternalTrafficPolicyLocal = "Local"
+
+	// podIPIndex is the name of the index of the Pods informer by the IPs of the pods
+	podIPIndex = "podIP"
 )
 
 // informerCollection is the type holding the collection of informers we keep
@@ -64,10 +82,33 @@ type informerCollection map[InformerKey]cache.SharedIndexInformer
 
 // Client is a struct for all components necessary to connect to and maintain state of a Kubernetes cluster.
 type Client struct {
-	meshName    string
-	kubeClient  kubernetes.Interface
-	informers   informerCollection
-	cacheSynced chan interface{}
+	meshName            string
+	kubeClient          kubernetes.Interface
+	dynamicClient       dynamic.Interface
+	informers           informerCollection
+	cacheSynced         chan interface{}
+	namespaceExclusions NamespaceExclusions
+	meshLimits          MeshLimits
+	admission           *namespaceAdmission
+}
+
+// NamespaceExclusions determines the namespaces excluded from the mesh regardless of their labels
+type NamespaceExclusions interface {
+	// IsExcludedNamespace returns true if the given namespace is excluded from the mesh regardless of its labels
+	IsExcludedNamespace(namespace string) bool
+}
+
+// MeshLimits determines the limits on the size of the mesh beyond which no new namespace is admitted to the mesh.
+// A limit of 0 indicates there is no limit.
+type MeshLimits interface {
+	// GetMaxMonitoredNamespaces returns the maximum number of namespaces admitted to the mesh
+	GetMaxMonitoredNamespaces() int
+
+	// GetMaxServices returns the number of services in the mesh beyond which no new namespace is admitted to the mesh
+	GetMaxServices() int
+
+	// GetMaxProxies returns the number of proxies in the mesh beyond which no new namespace is admitted to the mesh
+	GetMaxProxies() int
 }
 
 // Controller is the controller interface for K8s services
@@ -94,6 +135,12 @@ type Controller interface {
 	// ListPods returns a list of pods part of the mesh
 	ListPods() []*corev1.Pod
 
+	// ListPodsByIP returns the pods part of the mesh with the given IP
+	ListPodsByIP(ip string) []*corev1.Pod
+
+	// GetServiceInternalTrafficPolicy returns the internal traffic policy of the given service, Cluster if it is not set
+	GetServiceInternalTrafficPolicy(svc service.MeshService) string
+
 	// ListServiceAccountsForService lists ServiceAccounts associated with the given service
 	ListServiceAccountsForService(svc service.MeshService) ([]service.K8sServiceAccount, error)
 
@@ -4,7 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/openservicemesh/osm/pkg/constants"
 )
 
 const (
@@ -77,3 +84,42 @@ func GetAppProtocolFromPortName(portName string) string {
 		return defaultAppProtocol
 	}
 }
+
+// SetControlPlaneGeneratedMetadata labels and annotates the given object as generated at runtime by the OSM control plane.
+// GitOps tools such as Argo CD track the resources of an application by the 'app.kubernetes.io/instance' label, which is
+// also set on the resources generated by OSM. The annotations prevent these tools from reporting the generated resources
+// as out of sync or pruning them, so they don't fight the control plane over the resources it manages.
+func SetControlPlaneGeneratedMetadata(meta *metav1.ObjectMeta) {
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	meta.Labels[constants.OSMAppManagedByLabelKey] = constants.OSMAppManagedByLabelValue
+
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[constants.ArgoCDCompareOptionsAnnotation] = "IgnoreExtraneous"
+	meta.Annotations[constants.ArgoCDSyncOptionsAnnotation] = "Prune=false"
+}
+
+// IsKindServed returns true if the Kubernetes API server serves resources of the given kind with the given API version
+func IsKindServed(client discovery.ServerResourcesInterface, gv schema.GroupVersion, kind string) (bool, error) {
+	groupVersion := gv.String()
+	resources, err := client.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "Error retrieving the resources of API version %s", groupVersion)
+	}
+	if resources == nil {
+		return false, nil
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Kind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
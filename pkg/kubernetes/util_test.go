@@ -6,7 +6,15 @@ import (
 
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+	testclient "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
 
@@ -139,3 +147,98 @@ func TestGetAppProtocolFromPortName(t *testing.T) {
 		})
 	}
 }
+
+func TestSetControlPlaneGeneratedMetadata(t *testing.T) {
+	assert := tassert.New(t)
+
+	meta := metav1.ObjectMeta{
+		Name:   "foo",
+		Labels: map[string]string{constants.OSMAppInstanceLabelKey: "osm"},
+	}
+	SetControlPlaneGeneratedMetadata(&meta)
+
+	assert.Equal(map[string]string{
+		constants.OSMAppInstanceLabelKey:  "osm",
+		constants.OSMAppManagedByLabelKey: constants.OSMAppManagedByLabelValue,
+	}, meta.Labels)
+	assert.Equal(map[string]string{
+		constants.ArgoCDCompareOptionsAnnotation: "IgnoreExtraneous",
+		constants.ArgoCDSyncOptionsAnnotation:    "Prune=false",
+	}, meta.Annotations)
+}
+
+// notFoundDiscovery is a fake discovery client returning the NotFound error of the API server for the API versions
+// it does not serve, while the fake discovery client of client-go returns an untyped error
+type notFoundDiscovery struct {
+	*fakeDiscovery.FakeDiscovery
+}
+
+func (d notFoundDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, resources := range d.Resources {
+		if resources.GroupVersion == groupVersion {
+			return resources, nil
+		}
+	}
+	return nil, k8sErrors.NewNotFound(schema.GroupResource{}, groupVersion)
+}
+
+func TestIsKindServed(t *testing.T) {
+	testCases := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		expected  bool
+	}{
+		{
+			name: "networking.k8s.io/v1 serves ingress resources",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: networkingV1beta1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "Ingress"}},
+				},
+				{
+					GroupVersion: networkingV1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "NetworkPolicy"}, {Kind: "Ingress"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "networking.k8s.io/v1 does not serve ingress resources",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: networkingV1beta1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "Ingress"}},
+				},
+				{
+					GroupVersion: networkingV1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "NetworkPolicy"}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "networking.k8s.io/v1 is not served",
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: networkingV1beta1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Kind: "Ingress"}},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := testclient.NewSimpleClientset()
+			discoveryClient := kubeClient.Discovery().(*fakeDiscovery.FakeDiscovery)
+			discoveryClient.Resources = tc.resources
+
+			supported, err := IsKindServed(notFoundDiscovery{discoveryClient}, networkingV1.SchemeGroupVersion, "Ingress")
+			assert.Nil(err)
+			assert.Equal(tc.expected, supported)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package lifecyclewebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+// NewNotifier creates a new notifier posting the mesh lifecycle events to the webhooks configured in the OSM ConfigMap.
+// The events are signed with the key in the lifecycle webhook secret of the OSM namespace.
+func NewNotifier(kubeClient kubernetes.Interface, cfg configurator.Configurator, osmNamespace string) *Notifier {
+	return &Notifier{
+		kubeClient:   kubeClient,
+		cfg:          cfg,
+		osmNamespace: osmNamespace,
+		client:       &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Start subscribes the notifier to the mesh lifecycle events until the stop channel is closed
+func (n *Notifier) Start(stop <-chan struct{}) {
+	eventSub := events.GetPubSubInstance().Subscribe(
+		announcements.CertificateRotated,
+		announcements.ProxyConnected,
+		announcements.ProxyDisconnected,
+		announcements.ProxyConfigRejected,
+	)
+
+	go func() {
+		defer events.GetPubSubInstance().Unsub(eventSub)
+		for {
+			select {
+			case <-stop:
+				return
+			case msg, ok := <-eventSub:
+				if !ok {
+					return
+				}
+				n.handle(msg)
+			}
+		}
+	}()
+}
+
+func (n *Notifier) handle(msg interface{}) {
+	psubMsg, ok := msg.(events.PubSubMessage)
+	if !ok {
+		log.Error().Msgf("Unexpected message type %T received on lifecycle events subscription", msg)
+		return
+	}
+
+	urls := n.cfg.GetLifecycleWebhookURLs()
+	if len(urls) == 0 || !n.isEventEnabled(psubMsg.AnnouncementType) {
+		return
+	}
+
+	event, err := newEvent(psubMsg)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error creating lifecycle event for announcement %s", psubMsg.AnnouncementType)
+		return
+	}
+
+	// Deliver the event asynchronously so that a slow webhook does not delay the next events
+	go n.deliver(urls, event)
+}
+
+// isEventEnabled returns whether events of the given type are posted to the webhooks
+func (n *Notifier) isEventEnabled(eventType announcements.AnnouncementType) bool {
+	enabledEvents := n.cfg.GetLifecycleWebhookEvents()
+	if len(enabledEvents) == 0 {
+		return true
+	}
+	for _, enabled := range enabledEvents {
+		if enabled == eventType.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// newEvent creates the event posted to the webhooks for the given announcement
+func newEvent(msg events.PubSubMessage) (*Event, error) {
+	event := &Event{
+		Type: msg.AnnouncementType.String(),
+		Time: time.Now().UTC(),
+	}
+
+	switch msg.AnnouncementType {
+	case announcements.CertificateRotated:
+		newCert, ok := msg.NewObj.(certificate.Certificater)
+		if !ok {
+			return nil, errors.Errorf("Unexpected object type %T for rotated certificate", msg.NewObj)
+		}
+		data := certificateRotatedData{
+			CommonName:      newCert.GetCommonName().String(),
+			NewSerialNumber: newCert.GetSerialNumber().String(),
+			Expiration:      newCert.GetExpiration(),
+		}
+		if oldCert, ok := msg.OldObj.(certificate.Certificater); ok && oldCert != nil {
+			data.OldSerialNumber = oldCert.GetSerialNumber().String()
+		}
+		event.Data = data
+
+	case announcements.ProxyConnected:
+		proxy, ok := msg.NewObj.(*envoy.Proxy)
+		if !ok {
+			return nil, errors.Errorf("Unexpected object type %T for connected proxy", msg.NewObj)
+		}
+		event.Data = newProxyData(proxy)
+
+	case announcements.ProxyDisconnected:
+		proxy, ok := msg.OldObj.(*envoy.Proxy)
+		if !ok {
+			return nil, errors.Errorf("Unexpected object type %T for disconnected proxy", msg.OldObj)
+		}
+		event.Data = newProxyData(proxy)
+
+	case announcements.ProxyConfigRejected:
+		rejection, ok := msg.NewObj.(*envoy.ConfigRejection)
+		if !ok {
+			return nil, errors.Errorf("Unexpected object type %T for rejected proxy config", msg.NewObj)
+		}
+		event.Data = proxyConfigRejectedData{
+			proxyData: newProxyData(rejection.Proxy),
+			TypeURL:   rejection.TypeURL,
+			Error:     rejection.Message,
+		}
+
+	default:
+		return nil, errors.Errorf("Unsupported lifecycle event type %s", msg.AnnouncementType)
+	}
+
+	return event, nil
+}
+
+func newProxyData(proxy *envoy.Proxy) proxyData {
+	data := proxyData{
+		CommonName:   proxy.GetCertificateCommonName().String(),
+		SerialNumber: proxy.GetCertificateSerialNumber().String(),
+		PodUID:       proxy.GetPodUID(),
+	}
+	if svcAccount, err := catalog.GetServiceAccountFromProxyCertificate(proxy.GetCertificateCommonName()); err == nil {
+		data.ServiceAccount = svcAccount.String()
+	}
+	return data
+}
+
+// deliver posts the given event to the given webhooks. Events are posted once: a failed delivery is logged and not retried.
+func (n *Notifier) deliver(urls []string, event *Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshaling lifecycle event %s", event.Type)
+		return
+	}
+
+	key, err := n.getSigningKey()
+	if err != nil {
+		log.Error().Err(err).Msgf("Not posting lifecycle event %s, the signing key could not be read", event.Type)
+		return
+	}
+	signature := Sign(key, body)
+
+	for _, url := range urls {
+		if err := n.post(url, event.Type, body, signature); err != nil {
+			log.Error().Err(err).Msgf("Error posting lifecycle event %s to %s", event.Type, url)
+			continue
+		}
+		log.Debug().Msgf("Posted lifecycle event %s to %s", event.Type, url)
+	}
+}
+
+func (n *Notifier) post(url string, eventType string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, eventType)
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("Webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// getSigningKey returns the key the events are signed with, read from the lifecycle webhook secret
+func (n *Notifier) getSigningKey() ([]byte, error) {
+	secret, err := n.kubeClient.CoreV1().Secrets(n.osmNamespace).Get(context.Background(), constants.LifecycleWebhookSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting secret %s/%s", n.osmNamespace, constants.LifecycleWebhookSecretName)
+	}
+
+	key, ok := secret.Data[constants.LifecycleWebhookSecretKey]
+	if !ok || len(key) == 0 {
+		return nil, errors.Errorf("Secret %s/%s has no %s key", n.osmNamespace, constants.LifecycleWebhookSecretName, constants.LifecycleWebhookSecretKey)
+	}
+	return key, nil
+}
+
+// Sign returns the signature of the given request body with the given key, as set in the SignatureHeader header of the
+// requests posting events. Webhooks verify the requests by comparing the header with the signature of the body.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,206 @@
+package lifecyclewebhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+const osmNamespace = "osm-system"
+
+func newSigningKeySecret(key string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: osmNamespace,
+			Name:      constants.LifecycleWebhookSecretName,
+		},
+		Data: map[string][]byte{
+			constants.LifecycleWebhookSecretKey: []byte(key),
+		},
+	}
+}
+
+func TestIsEventEnabled(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	n := NewNotifier(fake.NewSimpleClientset(), mockConfigurator, osmNamespace)
+
+	mockConfigurator.EXPECT().GetLifecycleWebhookEvents().Return(nil).Times(1)
+	assert.True(n.isEventEnabled(announcements.ProxyConnected))
+
+	mockConfigurator.EXPECT().GetLifecycleWebhookEvents().Return([]string{"certificate-rotated", "proxy-config-rejected"}).Times(2)
+	assert.True(n.isEventEnabled(announcements.ProxyConfigRejected))
+	assert.False(n.isEventEnabled(announcements.ProxyConnected))
+}
+
+func TestNewEvent(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyCN := certificate.CommonName("8b0d9c4a-f5fa-4f2b-8e8e-0e1f4b1c1d6e.bookbuyer.bookbuyer.cluster.local")
+	proxy := envoy.NewProxy(proxyCN, "123", tests.NewMockAddress("10.0.0.1"))
+	expectedProxyData := proxyData{
+		CommonName:     proxyCN.String(),
+		SerialNumber:   "123",
+		ServiceAccount: "bookbuyer/bookbuyer",
+	}
+
+	certManager := tresor.NewFakeCertManager(nil)
+	oldCert, err := certManager.IssueCertificate("bookstore.bookstore.cluster.local", time.Hour)
+	assert.Nil(err)
+	newCert, err := certManager.IssueCertificate("bookstore.bookstore.cluster.local", time.Hour)
+	assert.Nil(err)
+
+	testCases := []struct {
+		name         string
+		msg          events.PubSubMessage
+		expectedData interface{}
+		expectErr    bool
+	}{
+		{
+			name: "certificate rotated",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.CertificateRotated,
+				NewObj:           newCert,
+				OldObj:           oldCert,
+			},
+			expectedData: certificateRotatedData{
+				CommonName:      "bookstore.bookstore.cluster.local",
+				OldSerialNumber: oldCert.GetSerialNumber().String(),
+				NewSerialNumber: newCert.GetSerialNumber().String(),
+				Expiration:      newCert.GetExpiration(),
+			},
+		},
+		{
+			name: "proxy connected",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyConnected,
+				NewObj:           proxy,
+			},
+			expectedData: expectedProxyData,
+		},
+		{
+			name: "proxy disconnected",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyDisconnected,
+				OldObj:           proxy,
+			},
+			expectedData: expectedProxyData,
+		},
+		{
+			name: "proxy config rejected",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyConfigRejected,
+				NewObj: &envoy.ConfigRejection{
+					Proxy:   proxy,
+					TypeURL: envoy.TypeLDS.String(),
+					Message: "Error adding/updating listener(s) inbound-listener",
+				},
+			},
+			expectedData: proxyConfigRejectedData{
+				proxyData: expectedProxyData,
+				TypeURL:   envoy.TypeLDS.String(),
+				Error:     "Error adding/updating listener(s) inbound-listener",
+			},
+		},
+		{
+			name: "unexpected object",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyConnected,
+				NewObj:           "proxy",
+			},
+			expectErr: true,
+		},
+		{
+			name: "unsupported announcement",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.PodAdded,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := newEvent(tc.msg)
+			if tc.expectErr {
+				assert.NotNil(err)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.msg.AnnouncementType.String(), event.Type)
+			assert.Equal(tc.expectedData, event.Data)
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	assert := tassert.New(t)
+
+	type receivedRequest struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan receivedRequest, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- receivedRequest{header: r.Header, body: body}
+	}))
+	defer server.Close()
+
+	event := &Event{
+		Type: announcements.ProxyConnected.String(),
+		Time: time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Data: proxyData{CommonName: "proxy", SerialNumber: "123"},
+	}
+
+	// Events are not posted without a signing key
+	n := NewNotifier(fake.NewSimpleClientset(), nil, osmNamespace)
+	n.deliver([]string{server.URL}, event)
+	assert.Len(received, 0)
+
+	n = NewNotifier(fake.NewSimpleClientset(newSigningKeySecret("s3cr3t")), nil, osmNamespace)
+	n.deliver([]string{server.URL, server.URL + "/second"}, event)
+	assert.Len(received, 2)
+
+	req := <-received
+	assert.Equal("application/json", req.header.Get("Content-Type"))
+	assert.Equal("proxy-connected", req.header.Get(EventTypeHeader))
+	assert.Equal(Sign([]byte("s3cr3t"), req.body), req.header.Get(SignatureHeader))
+
+	var posted map[string]interface{}
+	assert.Nil(json.Unmarshal(req.body, &posted))
+	assert.Equal(map[string]interface{}{
+		"type": "proxy-connected",
+		"time": "2021-03-01T00:00:00Z",
+		"data": map[string]interface{}{"common_name": "proxy", "serial_number": "123"},
+	}, posted)
+}
+
+func TestSign(t *testing.T) {
+	assert := tassert.New(t)
+
+	// HMAC-SHA256 test vector from RFC 4231, test case 2
+	assert.Equal("sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		Sign([]byte("Jefe"), []byte("what do ya want for nothing?")))
+}
@@ -0,0 +1,75 @@
+// Package lifecyclewebhook implements the webhooks the OSM controller posts mesh lifecycle events to, such as certificate
+// rotations, proxy connections and disconnections, and configurations rejected by proxies, so that platform automation
+// can react to these events without polling the control plane.
+package lifecyclewebhook
+
+import (
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("lifecycle-webhook")
+
+const (
+	// SignatureHeader is the header of the requests posting events, holding the HMAC-SHA256 signature of the request
+	// body formatted as sha256=<hex>
+	SignatureHeader = "X-OSM-Signature"
+
+	// EventTypeHeader is the header of the requests posting events, holding the type of the event
+	EventTypeHeader = "X-OSM-Event"
+
+	// signaturePrefix is the prefix of the signature in the SignatureHeader header
+	signaturePrefix = "sha256="
+
+	// deliveryTimeout is the timeout of a request posting an event to a webhook
+	deliveryTimeout = 10 * time.Second
+)
+
+// Notifier posts the mesh lifecycle events to the configured webhooks.
+type Notifier struct {
+	kubeClient   kubernetes.Interface
+	cfg          configurator.Configurator
+	osmNamespace string
+	client       *http.Client
+}
+
+// Event is the payload posted to the webhooks for a mesh lifecycle event.
+type Event struct {
+	// Type is the type of the event
+	Type string `json:"type"`
+
+	// Time is the time the event was observed by the controller
+	Time time.Time `json:"time"`
+
+	// Data holds the details of the event, depending on its type
+	Data interface{} `json:"data"`
+}
+
+// certificateRotatedData is the data of a certificate-rotated event
+type certificateRotatedData struct {
+	CommonName      string    `json:"common_name"`
+	OldSerialNumber string    `json:"old_serial_number,omitempty"`
+	NewSerialNumber string    `json:"new_serial_number"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+// proxyData is the data of proxy-connected and proxy-disconnected events
+type proxyData struct {
+	CommonName     string `json:"common_name"`
+	SerialNumber   string `json:"serial_number"`
+	ServiceAccount string `json:"service_account,omitempty"`
+	PodUID         string `json:"pod_uid,omitempty"`
+}
+
+// proxyConfigRejectedData is the data of a proxy-config-rejected event
+type proxyConfigRejectedData struct {
+	proxyData
+
+	TypeURL string `json:"type_url"`
+	Error   string `json:"error"`
+}
@@ -0,0 +1,170 @@
+package metricsaggregator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// aggregation sums the metrics of the proxies of the same workload, whose series only differ by the pod they were
+// scraped from
+type aggregation struct {
+	families map[string]*dto.MetricFamily
+
+	// metrics indexes the aggregated metrics of each family by their label set
+	metrics map[string]map[string]*dto.Metric
+}
+
+func newAggregation() *aggregation {
+	return &aggregation{
+		families: make(map[string]*dto.MetricFamily),
+		metrics:  make(map[string]map[string]*dto.Metric),
+	}
+}
+
+// add adds the metric families scraped from a proxy of the given workload to the aggregation
+func (agg *aggregation) add(w workload, families map[string]*dto.MetricFamily) {
+	for name, family := range families {
+		aggregated, ok := agg.families[name]
+		if !ok {
+			aggregated = &dto.MetricFamily{
+				Name: proto.String(name),
+				Help: family.Help,
+				Type: family.Type,
+			}
+			agg.families[name] = aggregated
+			agg.metrics[name] = make(map[string]*dto.Metric)
+		}
+		if aggregated.GetType() != family.GetType() {
+			log.Warn().Msgf("Skipping metric family %s of type %s, already aggregated with type %s", name, family.GetType(), aggregated.GetType())
+			continue
+		}
+
+		for _, metric := range family.Metric {
+			labels := withWorkloadLabels(metric.Label, w)
+			key := labelSetKey(labels)
+			existing, ok := agg.metrics[name][key]
+			if !ok {
+				existing = &dto.Metric{Label: labels}
+				initMetric(existing, family.GetType())
+				agg.metrics[name][key] = existing
+				aggregated.Metric = append(aggregated.Metric, existing)
+			}
+			mergeMetric(existing, metric, family.GetType())
+		}
+	}
+}
+
+// metricFamilies returns the aggregated metric families sorted by name, with their metrics sorted by label set
+func (agg *aggregation) metricFamilies() []*dto.MetricFamily {
+	families := make([]*dto.MetricFamily, 0, len(agg.families))
+	for _, family := range agg.families {
+		sort.Slice(family.Metric, func(i, j int) bool {
+			return labelSetKey(family.Metric[i].Label) < labelSetKey(family.Metric[j].Label)
+		})
+		families = append(families, family)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+	return families
+}
+
+// withWorkloadLabels returns the given labels with the labels identifying the given workload, sorted by name
+func withWorkloadLabels(labels []*dto.LabelPair, w workload) []*dto.LabelPair {
+	result := []*dto.LabelPair{
+		{Name: proto.String(sourceNamespaceLabel), Value: proto.String(w.namespace)},
+		{Name: proto.String(sourceWorkloadKindLabel), Value: proto.String(w.kind)},
+		{Name: proto.String(sourceWorkloadNameLabel), Value: proto.String(w.name)},
+	}
+	for _, label := range labels {
+		switch label.GetName() {
+		case sourceNamespaceLabel, sourceWorkloadKindLabel, sourceWorkloadNameLabel:
+			// The workload of the pod takes precedence over the labels exposed by the proxy
+			continue
+		}
+		result = append(result, &dto.LabelPair{Name: proto.String(label.GetName()), Value: proto.String(label.GetValue())})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result
+}
+
+// labelSetKey returns a key uniquely identifying the given labels, sorted by name
+func labelSetKey(labels []*dto.LabelPair) string {
+	var sb strings.Builder
+	for _, label := range labels {
+		sb.WriteString(label.GetName())
+		sb.WriteByte('=')
+		sb.WriteString(label.GetValue())
+		sb.WriteByte(0xff)
+	}
+	return sb.String()
+}
+
+// initMetric sets the zero value of the given metric type on the metric
+func initMetric(m *dto.Metric, metricType dto.MetricType) {
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		m.Counter = &dto.Counter{Value: proto.Float64(0)}
+	case dto.MetricType_GAUGE:
+		m.Gauge = &dto.Gauge{Value: proto.Float64(0)}
+	case dto.MetricType_HISTOGRAM:
+		m.Histogram = &dto.Histogram{SampleCount: proto.Uint64(0), SampleSum: proto.Float64(0)}
+	case dto.MetricType_SUMMARY:
+		m.Summary = &dto.Summary{SampleCount: proto.Uint64(0), SampleSum: proto.Float64(0)}
+	default:
+		m.Untyped = &dto.Untyped{Value: proto.Float64(0)}
+	}
+}
+
+// mergeMetric adds the values of the given metric to the aggregated metric. Counters, gauges and untyped metrics are
+// summed, as are the counts and sums of histograms and summaries. The cumulative counts of histogram buckets are
+// summed by upper bound. The quantiles of summaries cannot be aggregated and are dropped.
+func mergeMetric(aggregated, m *dto.Metric, metricType dto.MetricType) {
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		aggregated.Counter.Value = proto.Float64(aggregated.Counter.GetValue() + m.GetCounter().GetValue())
+
+	case dto.MetricType_GAUGE:
+		aggregated.Gauge.Value = proto.Float64(aggregated.Gauge.GetValue() + m.GetGauge().GetValue())
+
+	case dto.MetricType_HISTOGRAM:
+		h := aggregated.Histogram
+		h.SampleCount = proto.Uint64(h.GetSampleCount() + m.GetHistogram().GetSampleCount())
+		h.SampleSum = proto.Float64(h.GetSampleSum() + m.GetHistogram().GetSampleSum())
+		h.Bucket = mergeBuckets(h.Bucket, m.GetHistogram().GetBucket())
+
+	case dto.MetricType_SUMMARY:
+		s := aggregated.Summary
+		s.SampleCount = proto.Uint64(s.GetSampleCount() + m.GetSummary().GetSampleCount())
+		s.SampleSum = proto.Float64(s.GetSampleSum() + m.GetSummary().GetSampleSum())
+
+	default:
+		aggregated.Untyped.Value = proto.Float64(aggregated.Untyped.GetValue() + m.GetUntyped().GetValue())
+	}
+}
+
+// mergeBuckets returns the given histogram buckets with their cumulative counts summed by upper bound, sorted by
+// upper bound
+func mergeBuckets(aggregated, buckets []*dto.Bucket) []*dto.Bucket {
+	counts := make(map[float64]uint64, len(aggregated))
+	for _, b := range aggregated {
+		counts[b.GetUpperBound()] += b.GetCumulativeCount()
+	}
+	for _, b := range buckets {
+		counts[b.GetUpperBound()] += b.GetCumulativeCount()
+	}
+
+	result := make([]*dto.Bucket, 0, len(counts))
+	for upperBound, count := range counts {
+		result = append(result, &dto.Bucket{UpperBound: proto.Float64(upperBound), CumulativeCount: proto.Uint64(count)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetUpperBound() < result[j].GetUpperBound()
+	})
+	return result
+}
@@ -0,0 +1,161 @@
+package metricsaggregator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+// NewAggregator creates a new aggregator of the metrics of the sidecar proxies of the meshed pods running on the
+// given node, scraped at the given interval once started
+func NewAggregator(kubeClient kubernetes.Interface, nodeName string, scrapeInterval time.Duration, stop <-chan struct{}) (*Aggregator, error) {
+	// Only watch the meshed pods scheduled on this node
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+			listOptions.LabelSelector = constants.EnvoyUniqueIDLabelName
+		}))
+	informer := informerFactory.Core().V1().Pods().Informer()
+	go informer.Run(stop)
+	if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+		return nil, errors.Errorf("Failed to sync the pods of node %s", nodeName)
+	}
+
+	return &Aggregator{
+		nodeName:       nodeName,
+		scrapeInterval: scrapeInterval,
+		podStore:       informer.GetStore(),
+		httpClient:     &http.Client{Timeout: scrapeTimeout},
+		scrapeURL: func(podIP string) string {
+			return fmt.Sprintf("http://%s:%d%s", podIP, constants.EnvoyPrometheusInboundListenerPort, constants.PrometheusScrapePath)
+		},
+	}, nil
+}
+
+// Start scrapes the proxies of the node at the scrape interval until the stop channel is closed
+func (a *Aggregator) Start(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(a.scrapeInterval)
+		defer ticker.Stop()
+
+		a.scrapeAll()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				a.scrapeAll()
+			}
+		}
+	}()
+}
+
+// Handler returns the HTTP handler serving the metrics aggregated by the last scrape in the Prometheus exposition format
+func (a *Aggregator) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		families := a.families
+		a.mu.RUnlock()
+
+		format := expfmt.Negotiate(r.Header)
+		w.Header().Set("Content-Type", string(format))
+		encoder := expfmt.NewEncoder(w, format)
+		for _, family := range families {
+			if err := encoder.Encode(family); err != nil {
+				log.Error().Err(err).Msgf("Error encoding metric family %s", family.GetName())
+				return
+			}
+		}
+	})
+}
+
+// scrapeAll scrapes the proxies of the running meshed pods of the node concurrently, and replaces the aggregated
+// metrics with theirs. The proxies that fail to be scraped are left out of the aggregation until their next scrape.
+func (a *Aggregator) scrapeAll() {
+	var pods []*corev1.Pod
+	for _, obj := range a.podStore.List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	scraped := make([]map[string]*dto.MetricFamily, len(pods))
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func(i int, pod *corev1.Pod) {
+			defer wg.Done()
+			families, err := a.scrape(pod)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error scraping the proxy of pod %s/%s", pod.Namespace, pod.Name)
+				return
+			}
+			scraped[i] = families
+		}(i, pod)
+	}
+	wg.Wait()
+
+	aggregated := newAggregation()
+	for i, pod := range pods {
+		if scraped[i] != nil {
+			aggregated.add(getWorkload(pod), scraped[i])
+		}
+	}
+
+	a.mu.Lock()
+	a.families = aggregated.metricFamilies()
+	a.mu.Unlock()
+	log.Trace().Msgf("Aggregated the metrics of %d proxies on node %s", len(pods), a.nodeName)
+}
+
+// scrape returns the metric families exposed by the proxy of the given pod
+func (a *Aggregator) scrape(pod *corev1.Pod) (map[string]*dto.MetricFamily, error) {
+	resp, err := a.httpClient.Get(a.scrapeURL(pod.Status.PodIP))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// getWorkload returns the workload of the given pod, named after its controller. As in the Prometheus scrape
+// configuration of the OSM chart, the pods of a ReplicaSet are assumed to be controlled by a Deployment. Pods without
+// a controller are their own workload.
+func getWorkload(pod *corev1.Pod) workload {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return workload{namespace: pod.Namespace, kind: "Pod", name: pod.Name}
+	}
+
+	w := workload{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name}
+	if w.kind == "ReplicaSet" {
+		if hyp := strings.LastIndex(w.name, "-"); hyp >= 0 {
+			w.kind = "Deployment"
+			w.name = w.name[:hyp]
+		}
+	}
+	return w
+}
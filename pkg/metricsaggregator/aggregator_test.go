@@ -0,0 +1,191 @@
+package metricsaggregator
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func parseMetrics(t *testing.T, text string) map[string]*dto.MetricFamily {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	tassert.Nil(t, err)
+	return families
+}
+
+func getLabels(m *dto.Metric) map[string]string {
+	labels := make(map[string]string)
+	for _, label := range m.Label {
+		labels[label.GetName()] = label.GetValue()
+	}
+	return labels
+}
+
+func TestAggregation(t *testing.T) {
+	assert := tassert.New(t)
+
+	bookbuyer := workload{namespace: "bookbuyer", kind: "Deployment", name: "bookbuyer"}
+	bookstore := workload{namespace: "bookstore", kind: "Deployment", name: "bookstore"}
+
+	agg := newAggregation()
+	agg.add(bookbuyer, parseMetrics(t, `# TYPE envoy_cluster_upstream_rq_xx counter
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="2",envoy_cluster_name="bookstore/bookstore"} 3
+# TYPE envoy_server_live gauge
+envoy_server_live{} 1
+# TYPE envoy_cluster_upstream_rq_time histogram
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="5"} 1
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="10"} 2
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="+Inf"} 3
+envoy_cluster_upstream_rq_time_sum{envoy_cluster_name="bookstore/bookstore"} 20
+envoy_cluster_upstream_rq_time_count{envoy_cluster_name="bookstore/bookstore"} 3
+`))
+	agg.add(bookbuyer, parseMetrics(t, `# TYPE envoy_cluster_upstream_rq_xx counter
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="2",envoy_cluster_name="bookstore/bookstore"} 4
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="5",envoy_cluster_name="bookstore/bookstore"} 1
+# TYPE envoy_server_live gauge
+envoy_server_live{} 1
+# TYPE envoy_cluster_upstream_rq_time histogram
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="5"} 0
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="10"} 1
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore",le="+Inf"} 1
+envoy_cluster_upstream_rq_time_sum{envoy_cluster_name="bookstore/bookstore"} 8
+envoy_cluster_upstream_rq_time_count{envoy_cluster_name="bookstore/bookstore"} 1
+`))
+	agg.add(bookstore, parseMetrics(t, `# TYPE envoy_server_live gauge
+envoy_server_live{} 1
+`))
+
+	families := agg.metricFamilies()
+	assert.Len(families, 3)
+
+	// Counters of the same workload and labels are summed
+	rq := families[1]
+	assert.Equal("envoy_cluster_upstream_rq_xx", rq.GetName())
+	assert.Equal(dto.MetricType_COUNTER, rq.GetType())
+	assert.Len(rq.Metric, 2)
+	assert.Equal(map[string]string{
+		"envoy_cluster_name":        "bookstore/bookstore",
+		"envoy_response_code_class": "2",
+		sourceNamespaceLabel:        "bookbuyer",
+		sourceWorkloadKindLabel:     "Deployment",
+		sourceWorkloadNameLabel:     "bookbuyer",
+	}, getLabels(rq.Metric[0]))
+	assert.Equal(7.0, rq.Metric[0].GetCounter().GetValue())
+	assert.Equal("5", getLabels(rq.Metric[1])["envoy_response_code_class"])
+	assert.Equal(1.0, rq.Metric[1].GetCounter().GetValue())
+
+	// Histograms of the same workload and labels are summed by bucket
+	rqTime := families[0]
+	assert.Equal("envoy_cluster_upstream_rq_time", rqTime.GetName())
+	assert.Len(rqTime.Metric, 1)
+	h := rqTime.Metric[0].GetHistogram()
+	assert.Equal(uint64(4), h.GetSampleCount())
+	assert.Equal(28.0, h.GetSampleSum())
+	var buckets []string
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), +1) {
+			// The +Inf bucket is checked with the sample count
+			continue
+		}
+		buckets = append(buckets, fmt.Sprintf("%v:%d", b.GetUpperBound(), b.GetCumulativeCount()))
+	}
+	assert.Equal([]string{"5:1", "10:3"}, buckets)
+
+	// Gauges are summed per workload
+	live := families[2]
+	assert.Equal("envoy_server_live", live.GetName())
+	assert.Len(live.Metric, 2)
+	assert.Equal("bookbuyer", getLabels(live.Metric[0])[sourceWorkloadNameLabel])
+	assert.Equal(2.0, live.Metric[0].GetGauge().GetValue())
+	assert.Equal("bookstore", getLabels(live.Metric[1])[sourceWorkloadNameLabel])
+	assert.Equal(1.0, live.Metric[1].GetGauge().GetValue())
+}
+
+func TestGetWorkload(t *testing.T) {
+	trueVal := true
+
+	testCases := []struct {
+		name             string
+		owners           []metav1.OwnerReference
+		expectedWorkload workload
+	}{
+		{
+			name:             "pod without a controller",
+			expectedWorkload: workload{namespace: "ns", kind: "Pod", name: "pod-1"},
+		},
+		{
+			name:             "pod of a ReplicaSet",
+			owners:           []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "bookstore-v1-5d4b8c9f7", Controller: &trueVal}},
+			expectedWorkload: workload{namespace: "ns", kind: "Deployment", name: "bookstore-v1"},
+		},
+		{
+			name:             "pod of a StatefulSet",
+			owners:           []metav1.OwnerReference{{Kind: "StatefulSet", Name: "mysql", Controller: &trueVal}},
+			expectedWorkload: workload{namespace: "ns", kind: "StatefulSet", name: "mysql"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns", OwnerReferences: tc.owners},
+			}
+			assert.Equal(tc.expectedWorkload, getWorkload(pod))
+		})
+	}
+}
+
+func TestScrapeAll(t *testing.T) {
+	assert := tassert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pod") == "failing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("# TYPE envoy_server_live gauge\nenvoy_server_live{} 1\n"))
+	}))
+	defer server.Close()
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, name := range []string{"running", "failing", "pending"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: name},
+		}
+		if name == "pending" {
+			pod.Status.Phase = corev1.PodPending
+		}
+		assert.Nil(store.Add(pod))
+	}
+
+	a := &Aggregator{
+		nodeName:   "node-1",
+		podStore:   store,
+		httpClient: server.Client(),
+		scrapeURL: func(podIP string) string {
+			return server.URL + "?pod=" + podIP
+		},
+	}
+	a.scrapeAll()
+
+	// Only the running pod whose proxy was scraped successfully is aggregated
+	recorder := httptest.NewRecorder()
+	a.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(http.StatusOK, recorder.Code)
+	families := parseMetrics(t, recorder.Body.String())
+	assert.Len(families, 1)
+	assert.Len(families["envoy_server_live"].Metric, 1)
+	assert.Equal("running", getLabels(families["envoy_server_live"].Metric[0])[sourceWorkloadNameLabel])
+}
@@ -0,0 +1,51 @@
+// Package metricsaggregator implements the per-node aggregator of the metrics of the sidecar proxies. The aggregator
+// scrapes the proxies of the meshed pods running on its node and exposes their metrics summed per workload, so that
+// Prometheus scrapes one aggregator per node instead of every sidecar of large meshes.
+package metricsaggregator
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("metrics-aggregator")
+
+const (
+	// scrapeTimeout is the timeout of the scrape of a single proxy
+	scrapeTimeout = 5 * time.Second
+
+	// Labels identifying the workload of the aggregated series, matching the labels added to the series of the
+	// sidecars by the Prometheus scrape configuration of the OSM chart
+	sourceNamespaceLabel    = "source_namespace"
+	sourceWorkloadKindLabel = "source_workload_kind"
+	sourceWorkloadNameLabel = "source_workload_name"
+)
+
+// Aggregator scrapes the sidecar proxies of the meshed pods running on a node and serves their metrics aggregated per
+// workload
+type Aggregator struct {
+	nodeName       string
+	scrapeInterval time.Duration
+	podStore       cache.Store
+	httpClient     *http.Client
+
+	// scrapeURL returns the URL the metrics of the proxy of the given pod IP are scraped from
+	scrapeURL func(podIP string) string
+
+	// families are the metric families aggregated by the last scrape
+	families []*dto.MetricFamily
+	mu       sync.RWMutex
+}
+
+// workload identifies the workload of a pod whose metrics are aggregated together
+type workload struct {
+	namespace string
+	kind      string
+	name      string
+}
@@ -28,6 +28,22 @@ type MetricsStore struct {
 	// K8sMeshPodCount is the metric for the number of pods participating in the mesh
 	K8sMeshPodCount prometheus.Gauge
 
+	// K8sMeshLimit is the metric for the limits on the number of namespaces, services and proxies in the mesh
+	K8sMeshLimit *prometheus.GaugeVec
+
+	// K8sMeshSize is the metric for the number of namespaces, services and proxies in the namespaces admitted to the mesh
+	K8sMeshSize *prometheus.GaugeVec
+
+	// K8sNamespaceNotAdmittedCount is the metric for the number of namespaces labeled for monitoring that are not
+	// admitted to the mesh because of its limits
+	K8sNamespaceNotAdmittedCount prometheus.Gauge
+
+	/*
+	 * SMI metrics
+	 */
+	// SMITemporaryAccessGrantCount is the metric for the number of SMI TrafficTargets granting temporary access that have not expired
+	SMITemporaryAccessGrantCount prometheus.Gauge
+
 	/*
 	 * Proxy metrics
 	 */
@@ -37,6 +53,32 @@ type MetricsStore struct {
 	// ProxyConfigUpdateTime is the histogram to track time spent for proxy configuration and its occurrences
 	ProxyConfigUpdateTime *prometheus.HistogramVec
 
+	// ProxyConfigThrottledCount is the metric counter for the number of xDS responses withheld from proxies for exceeding the max config size
+	ProxyConfigThrottledCount *prometheus.CounterVec
+
+	// ProxyConfigInvalidCount is the metric counter for the number of xDS responses withheld from proxies for having invalid resources
+	ProxyConfigInvalidCount *prometheus.CounterVec
+
+	// ProxyResourceConflictCount is the metric counter for the number of resources dropped from the config of proxies for colliding with the name of another resource
+	ProxyResourceConflictCount *prometheus.CounterVec
+
+	// ProxyRBACDenyCount is the metric counter for the number of requests denied by the RBAC policies of proxies
+	ProxyRBACDenyCount *prometheus.CounterVec
+
+	// ProxyRBACShadowDenyCount is the metric counter for the number of requests that would be denied by the RBAC policies
+	// of SMI TrafficTargets in shadow mode
+	ProxyRBACShadowDenyCount *prometheus.CounterVec
+
+	// ProxyEgressRequestCount is the metric counter for the number of HTTP requests sent by proxies to external hosts
+	ProxyEgressRequestCount *prometheus.CounterVec
+
+	// ProxyEgressBytesCount is the metric counter for the number of bytes exchanged by proxies with external hosts
+	ProxyEgressBytesCount *prometheus.CounterVec
+
+	// ProxyEgressAuditDenyCount is the metric counter for the number of egress requests and connections that would be
+	// denied by Egress policies in audit mode
+	ProxyEgressAuditDenyCount *prometheus.CounterVec
+
 	/*
 	 * Injector metrics
 	 */
@@ -55,6 +97,55 @@ type MetricsStore struct {
 	// CertXdsIssuedCounter the histogram to track the time to issue a certificates
 	CertIssuedTime *prometheus.HistogramVec
 
+	// CertRotationPropagationTime is the histogram to track the time from the rotation of a certificate to its
+	// acknowledgement by the proxies using it
+	CertRotationPropagationTime *prometheus.HistogramVec
+
+	/*
+	 * Configuration metrics
+	 */
+	// ConfigGeneration is the metric for the ID of the configuration generation last broadcast to the proxies
+	ConfigGeneration prometheus.Gauge
+
+	// ConfigGenerationPropagationTime is the histogram to track the time from the broadcast of a configuration
+	// generation to its acknowledgement by each proxy
+	ConfigGenerationPropagationTime *prometheus.HistogramVec
+
+	// ConfigGenerationSkippedCount is the metric counter for the number of configuration generations not sent to proxies
+	// unaffected by their changes
+	ConfigGenerationSkippedCount prometheus.Counter
+
+	/*
+	 * eBPF metrics
+	 */
+	// EBPFAccelerationEnabled is the metric for whether the eBPF programs accelerating the local hops of the traffic of
+	// the meshed pods are loaded on the node
+	EBPFAccelerationEnabled prometheus.Gauge
+
+	// EBPFAcceleratedPodCount is the metric for the number of meshed pods of the node whose sockets are accelerated
+	EBPFAcceleratedPodCount prometheus.Gauge
+
+	// EBPFRedirectedPodCount is the metric for the number of meshed pods of the node whose outbound connections are
+	// redirected to their sidecar proxy by the eBPF programs
+	EBPFRedirectedPodCount prometheus.Gauge
+
+	/*
+	 * Node proxy metrics
+	 */
+	// NodeProxyRedirectedPodCount is the metric for the number of node proxy mode pods of the node whose traffic is
+	// redirected to the node proxy
+	NodeProxyRedirectedPodCount prometheus.Gauge
+
+	// NodeProxyUnredirectedPodCount is the metric for the number of node proxy mode pods of the mesh running on nodes
+	// where the redirection of their traffic to the node proxy is not programmed
+	NodeProxyUnredirectedPodCount prometheus.Gauge
+
+	/*
+	 * Process metrics
+	 */
+	// ProcessCollector collects the CPU, memory and file descriptor usage of the process
+	ProcessCollector prometheus.Collector
+
 	/*
 	 * MetricsStore internals should be defined below --------------
 	 */
@@ -91,6 +182,40 @@ func init() {
 		Name:      "mesh_pod_count",
 		Help:      "represents the number of pods part of the mesh managed by OSM controller",
 	})
+	defaultMetricsStore.K8sMeshLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "mesh_limit",
+			Help:      "represents the limit on the number of resources of the given type in the mesh, 0 if unlimited",
+		},
+		[]string{"resource"},
+	)
+	defaultMetricsStore.K8sMeshSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "mesh_size",
+			Help:      "represents the number of resources of the given type in the namespaces admitted to the mesh",
+		},
+		[]string{"resource"},
+	)
+	defaultMetricsStore.K8sNamespaceNotAdmittedCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "k8s",
+		Name:      "namespace_not_admitted_count",
+		Help:      "represents the number of namespaces labeled for monitoring that are not admitted to the mesh because of its limits",
+	})
+
+	/*
+	 * SMI metrics
+	 */
+	defaultMetricsStore.SMITemporaryAccessGrantCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "smi",
+		Name:      "temporary_access_grant_count",
+		Help:      "represents the number of SMI TrafficTargets granting temporary access that have not expired",
+	})
 
 	/*
 	 * Proxy metrics
@@ -115,6 +240,103 @@ func init() {
 			"success",       // further labels if the operation succeeded or not
 		})
 
+	defaultMetricsStore.ProxyConfigThrottledCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "config_throttled_count",
+			Help:      "represents the number of xDS responses withheld from proxies for exceeding the max proxy config size",
+		},
+		[]string{
+			"resource_type", // identifies a typeURI resource
+		})
+
+	defaultMetricsStore.ProxyConfigInvalidCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "config_invalid_count",
+			Help:      "represents the number of xDS responses withheld from proxies for having resources violating the validation rules of the Envoy API",
+		},
+		[]string{
+			"resource_type", // identifies a typeURI resource
+		})
+
+	defaultMetricsStore.ProxyResourceConflictCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "resource_conflict_count",
+			Help:      "represents the number of resources dropped from the config of proxies for colliding with the name of another resource of the same kind",
+		},
+		[]string{
+			"resource_kind", // identifies the kind of resource, e.g. cluster or listener
+		})
+
+	defaultMetricsStore.ProxyRBACDenyCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "rbac_deny_count",
+			Help:      "represents the number of requests denied by the RBAC policies of proxies",
+		},
+		[]string{
+			"source_identity",      // identity of the client denied
+			"destination_identity", // identity of the proxy denying the request
+			"route",                // name of the route denied
+		})
+
+	defaultMetricsStore.ProxyRBACShadowDenyCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "rbac_shadow_deny_count",
+			Help:      "represents the number of requests that would be denied by the RBAC policies of SMI TrafficTargets in shadow mode",
+		},
+		[]string{
+			"source_identity",      // identity of the client that would be denied
+			"destination_identity", // identity of the proxy evaluating the request
+			"route",                // name of the route requested
+		})
+
+	defaultMetricsStore.ProxyEgressRequestCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "egress_request_count",
+			Help:      "represents the number of HTTP requests sent by proxies to external hosts allowed by Egress policies",
+		},
+		[]string{
+			"source_identity", // identity of the proxy sending the request
+			"host",            // external host requested
+			"response_class",  // class of the response code, e.g. 2xx, or none if no response was received
+		})
+
+	defaultMetricsStore.ProxyEgressBytesCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "egress_bytes_count",
+			Help:      "represents the number of bytes exchanged by proxies with external hosts allowed by Egress policies",
+		},
+		[]string{
+			"source_identity", // identity of the proxy connecting to the external host
+			"host",            // external host connected to
+			"direction",       // sent to or received from the external host
+		})
+
+	defaultMetricsStore.ProxyEgressAuditDenyCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "egress_audit_deny_count",
+			Help:      "represents the number of egress requests and connections that would be denied by Egress policies in audit mode",
+		},
+		[]string{
+			"source_identity", // identity of the proxy sending the request or connection
+			"host",            // external host or IP address requested
+		})
+
 	/*
 	 * Injector metrics
 	 */
@@ -156,6 +378,90 @@ func init() {
 			Help:      "Histogram to track time spent to issue xds certificate",
 		},
 		[]string{})
+
+	defaultMetricsStore.CertRotationPropagationTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "rotation_propagation_time",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 40, 90},
+			Help:      "Histogram to track time from the rotation of a certificate to its acknowledgement by the proxies using it",
+		},
+		[]string{})
+
+	/*
+	 * Configuration metrics
+	 */
+	defaultMetricsStore.ConfigGeneration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "config",
+		Name:      "generation",
+		Help:      "represents the ID of the configuration generation last broadcast to the proxies",
+	})
+
+	defaultMetricsStore.ConfigGenerationPropagationTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "config",
+			Name:      "generation_propagation_time",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 40, 90},
+			Help:      "Histogram to track time from the broadcast of a configuration generation to its acknowledgement by each proxy",
+		},
+		[]string{})
+
+	defaultMetricsStore.ConfigGenerationSkippedCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "config",
+		Name:      "generation_skipped_count",
+		Help:      "represents the number of configuration generations not sent to proxies unaffected by their changes",
+	})
+
+	/*
+	 * eBPF metrics
+	 */
+	defaultMetricsStore.EBPFAccelerationEnabled = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "ebpf",
+		Name:      "acceleration_enabled",
+		Help:      "represents whether the eBPF programs accelerating the local hops of the traffic of the meshed pods are loaded on the node, 1 if loaded and 0 otherwise",
+	})
+
+	defaultMetricsStore.EBPFAcceleratedPodCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "ebpf",
+		Name:      "accelerated_pod_count",
+		Help:      "represents the number of meshed pods of the node whose sockets are accelerated by the eBPF programs",
+	})
+
+	defaultMetricsStore.EBPFRedirectedPodCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "ebpf",
+		Name:      "redirected_pod_count",
+		Help:      "represents the number of meshed pods of the node whose outbound connections are redirected to their sidecar proxy by the eBPF programs",
+	})
+
+	/*
+	 * Node proxy metrics
+	 */
+	defaultMetricsStore.NodeProxyRedirectedPodCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "node_proxy",
+		Name:      "redirected_pod_count",
+		Help:      "represents the number of node proxy mode pods of the node whose traffic is redirected to the node proxy",
+	})
+
+	defaultMetricsStore.NodeProxyUnredirectedPodCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "node_proxy",
+		Name:      "unredirected_pod_count",
+		Help:      "represents the number of node proxy mode pods running on nodes where the redirection of their traffic to the node proxy is not programmed, whose traffic bypasses the mesh",
+	})
+
+	/*
+	 * Process metrics
+	 */
+	defaultMetricsStore.ProcessCollector = prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{})
+
 	defaultMetricsStore.registry = prometheus.NewRegistry()
 }
 
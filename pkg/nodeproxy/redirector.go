@@ -0,0 +1,310 @@
+package nodeproxy
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// NewRedirector returns a redirector of the traffic of the node proxy mode pods of the given node, in the namespaces
+// monitored by the given mesh, to the node proxy listening on the given IP address of the node
+func NewRedirector(kubeClient kubernetes.Interface, nodeName string, nodeIP string, meshName string, stop <-chan struct{}) (*Redirector, error) {
+	if ip := net.ParseIP(nodeIP); ip == nil || ip.To4() == nil {
+		return nil, errors.Errorf("Invalid IPv4 address %s of node %s", nodeIP, nodeName)
+	}
+
+	r := &Redirector{
+		nodeName:    nodeName,
+		nodeIP:      nodeIP,
+		meshName:    meshName,
+		run:         run,
+		reconcileCh: make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	r.iptables = r.selectIptables()
+
+	// Only watch the pods scheduled on this node
+	podInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+		}))
+	podInformer := podInformerFactory.Core().V1().Pods().Informer()
+
+	// Only watch the namespaces monitored by the mesh
+	namespaceInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = labels.SelectorFromSet(labels.Set{constants.OSMKubeResourceMonitorAnnotation: meshName}).String()
+		}))
+	namespaceInformer := namespaceInformerFactory.Core().V1().Namespaces().Informer()
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { r.triggerReconcile() },
+		UpdateFunc: func(interface{}, interface{}) { r.triggerReconcile() },
+		DeleteFunc: func(interface{}) { r.triggerReconcile() },
+	}
+	podInformer.AddEventHandler(handler)
+	namespaceInformer.AddEventHandler(handler)
+
+	go podInformer.Run(stop)
+	go namespaceInformer.Run(stop)
+	if !cache.WaitForCacheSync(stop, podInformer.HasSynced, namespaceInformer.HasSynced) {
+		return nil, errors.Errorf("Failed to sync the pods of node %s", nodeName)
+	}
+	r.podStore = podInformer.GetStore()
+	r.namespaceStore = namespaceInformer.GetStore()
+
+	return r, nil
+}
+
+// Start programs the redirection rules of the node proxy mode pods of the node whenever the pods change and at the
+// reconcile interval, until the stop channel is closed. The rules are then removed, and the channel returned by Done
+// is closed.
+func (r *Redirector) Start(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(reconcileInterval)
+		defer ticker.Stop()
+		defer close(r.done)
+
+		r.reconcile()
+		for {
+			select {
+			case <-stop:
+				r.cleanup()
+				return
+			case <-ticker.C:
+				r.reconcile()
+			case <-r.reconcileCh:
+				r.reconcile()
+			}
+		}
+	}()
+}
+
+// Done returns a channel closed once the redirector has stopped and removed the redirection rules
+func (r *Redirector) Done() <-chan struct{} {
+	return r.done
+}
+
+// Liveness is the liveness probe of the redirector
+func (r *Redirector) Liveness() bool {
+	return true
+}
+
+// Readiness returns true once the redirection rules of the node proxy mode pods of the node are programmed. The
+// osm-controller reports the node proxy mode pods of the nodes whose redirector is not ready as not redirected.
+func (r *Redirector) Readiness() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.programmed
+}
+
+// GetID returns the ID of the probe
+func (r *Redirector) GetID() string {
+	return constants.NodeProxyRedirectName
+}
+
+func (r *Redirector) triggerReconcile() {
+	select {
+	case r.reconcileCh <- struct{}{}:
+	default:
+		// A reconciliation is already pending
+	}
+}
+
+// reconcile programs the redirection rules of the node proxy mode pods of the node
+func (r *Redirector) reconcile() {
+	pods := r.listRedirectedPods()
+	rules := getRules(r.nodeIP, pods)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.program(rules); err != nil {
+		log.Error().Err(err).Msgf("Error programming the redirection of the node proxy mode pods of node %s, retrying in %s", r.nodeName, reconcileInterval)
+		r.programmed = false
+		metricsstore.DefaultMetricsStore.NodeProxyRedirectedPodCount.Set(0)
+		return
+	}
+	if !r.programmed || !equalRules(r.rules, rules) {
+		log.Info().Msgf("Redirecting the traffic of %d node proxy mode pods of node %s to its node proxy", len(pods), r.nodeName)
+	}
+	r.programmed = true
+	r.rules = rules
+	metricsstore.DefaultMetricsStore.NodeProxyRedirectedPodCount.Set(float64(len(pods)))
+}
+
+// listRedirectedPods returns the running node proxy mode pods of the node in the namespaces monitored by the mesh,
+// indexed by their IPv4 address. Pods in the network namespace of the node cannot be redirected.
+func (r *Redirector) listRedirectedPods() map[string]*corev1.Pod {
+	pods := make(map[string]*corev1.Pod)
+	for _, obj := range r.podStore.List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase != corev1.PodRunning || pod.Spec.HostNetwork || !catalog.IsNodeProxyModePod(pod) {
+			continue
+		}
+		if _, exists, _ := r.namespaceStore.GetByKey(pod.Namespace); !exists {
+			continue
+		}
+		ip := net.ParseIP(pod.Status.PodIP)
+		if ip == nil || ip.To4() == nil {
+			log.Warn().Msgf("Pod %s/%s has no IPv4 address, its traffic is not redirected to the node proxy", pod.Namespace, pod.Name)
+			continue
+		}
+		pods[ip.String()] = pod
+	}
+	return pods
+}
+
+// getRules returns the rules of the nat chain redirecting the TCP traffic sent by and destined to the pods with the given
+// IP addresses to the outbound and inbound listeners of the node proxy listening on the given IP address of the node.
+// The traffic is DNAT'ed to the address of the node, as the host side of the pod interfaces may have no address to
+// REDIRECT it to. The node proxy finds the original destination of the connections with SO_ORIGINAL_DST.
+func getRules(nodeIP string, pods map[string]*corev1.Pod) []string {
+	ips := make([]string, 0, len(pods))
+	for ip := range pods {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	var rules []string
+	for _, ip := range ips {
+		rules = append(rules,
+			// The DNS traffic of the pod reaches the cluster DNS directly
+			fmt.Sprintf("-A %s -s %s/32 -p tcp --dport %d -j RETURN", chainName, ip, dnsPort),
+			// The traffic of the pod to itself is not proxied
+			fmt.Sprintf("-A %s -s %s/32 -d %s/32 -p tcp -j RETURN", chainName, ip, ip),
+			fmt.Sprintf("-A %s -s %s/32 -p tcp -j DNAT --to-destination %s:%d", chainName, ip, nodeIP, constants.EnvoyOutboundListenerPort),
+			fmt.Sprintf("-A %s -d %s/32 -p tcp -j DNAT --to-destination %s:%d", chainName, ip, nodeIP, constants.EnvoyInboundListenerPort),
+		)
+	}
+	return rules
+}
+
+// program replaces the rules of the nat chain with the given rules atomically, and jumps to the chain from the
+// PREROUTING chain. The traffic sent by the node proxy itself originates from the network namespace of the node, so
+// it does not traverse the PREROUTING chain and is not redirected again.
+func (r *Redirector) program(rules []string) error {
+	var input strings.Builder
+	input.WriteString("*nat\n")
+	// Declaring the chain creates it if needed and flushes it, without touching the other chains with --noflush
+	fmt.Fprintf(&input, ":%s - [0:0]\n", chainName)
+	for _, rule := range rules {
+		input.WriteString(rule + "\n")
+	}
+	input.WriteString("COMMIT\n")
+	if out, err := r.run(input.String(), r.iptables+"-restore", "--noflush"); err != nil {
+		return errors.Wrapf(err, "Error writing chain %s: %s", chainName, out)
+	}
+
+	jump := []string{"-t", "nat", "PREROUTING", "-p", "tcp", "-j", chainName}
+	if _, err := r.run("", r.iptables, withOp(jump, "-C")...); err == nil {
+		return nil
+	}
+	if out, err := r.run("", r.iptables, withOp(jump, "-I")...); err != nil {
+		return errors.Wrapf(err, "Error jumping to chain %s from the PREROUTING chain: %s", chainName, out)
+	}
+	return nil
+}
+
+// cleanup removes the redirection rules, leaving the traffic of the node proxy mode pods unproxied once the redirector
+// exits. The osm-controller reports the pods as not redirected from then on.
+func (r *Redirector) cleanup() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.programmed = false
+	metricsstore.DefaultMetricsStore.NodeProxyRedirectedPodCount.Set(0)
+
+	jump := []string{"-t", "nat", "PREROUTING", "-p", "tcp", "-j", chainName}
+	// Remove all the jumps in case several were inserted
+	for {
+		if _, err := r.run("", r.iptables, withOp(jump, "-C")...); err != nil {
+			break
+		}
+		if out, err := r.run("", r.iptables, withOp(jump, "-D")...); err != nil {
+			log.Error().Err(err).Msgf("Error removing the jump to chain %s from the PREROUTING chain: %s", chainName, out)
+			break
+		}
+	}
+	if out, err := r.run("", r.iptables, "-t", "nat", "-F", chainName); err != nil {
+		log.Error().Err(err).Msgf("Error flushing chain %s: %s", chainName, out)
+		return
+	}
+	if out, err := r.run("", r.iptables, "-t", "nat", "-X", chainName); err != nil {
+		log.Error().Err(err).Msgf("Error deleting chain %s: %s", chainName, out)
+	}
+}
+
+// selectIptables returns the iptables command of the backend used by the node: the backend already holding the most
+// rules, or iptables-nft if the kernel supports it and neither backend holds rules, as done by the init container of
+// the pods
+func (r *Redirector) selectIptables() string {
+	legacyRules := r.countRules("iptables-legacy-save")
+	nftRules := r.countRules("iptables-nft-save")
+	if legacyRules > nftRules {
+		return "iptables-legacy"
+	}
+	if _, err := r.run("", "iptables-nft", "-t", "nat", "-L", "-n"); err != nil {
+		return "iptables-legacy"
+	}
+	return "iptables-nft"
+}
+
+// countRules returns the number of rules listed by the given iptables save command, 0 if it fails
+func (r *Redirector) countRules(save string) int {
+	out, err := r.run("", save)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "-") {
+			count++
+		}
+	}
+	return count
+}
+
+// withOp returns the given iptables rule specification with the given operation inserted before the chain name
+func withOp(spec []string, op string) []string {
+	// The specification starts with the table, followed by the chain name
+	return append(append(append([]string{}, spec[:2]...), op), spec[2:]...)
+}
+
+func equalRules(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func run(stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...) // #nosec G204
+	if stdin != "" {
+		cmd.Stdin = bytes.NewBufferString(stdin)
+	}
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,227 @@
+package nodeproxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// fakeIptables records the commands run by the redirector, and fails the commands matching the failing prefixes
+type fakeIptables struct {
+	commands []string
+	stdin    []string
+	failing  []string
+	outputs  map[string]string
+}
+
+func (f *fakeIptables) run(stdin string, name string, args ...string) ([]byte, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, command)
+	if stdin != "" {
+		f.stdin = append(f.stdin, stdin)
+	}
+	for _, prefix := range f.failing {
+		if strings.HasPrefix(command, prefix) {
+			return nil, errors.New("failed")
+		}
+	}
+	return []byte(f.outputs[command]), nil
+}
+
+func TestGetRules(t *testing.T) {
+	assert := tassert.New(t)
+
+	pods := map[string]*corev1.Pod{
+		"10.0.0.12": {},
+		"10.0.0.11": {},
+	}
+	assert.Equal([]string{
+		"-A OSM_NODE_PROXY -s 10.0.0.11/32 -p tcp --dport 53 -j RETURN",
+		"-A OSM_NODE_PROXY -s 10.0.0.11/32 -d 10.0.0.11/32 -p tcp -j RETURN",
+		"-A OSM_NODE_PROXY -s 10.0.0.11/32 -p tcp -j DNAT --to-destination 192.168.0.1:15001",
+		"-A OSM_NODE_PROXY -d 10.0.0.11/32 -p tcp -j DNAT --to-destination 192.168.0.1:15003",
+		"-A OSM_NODE_PROXY -s 10.0.0.12/32 -p tcp --dport 53 -j RETURN",
+		"-A OSM_NODE_PROXY -s 10.0.0.12/32 -d 10.0.0.12/32 -p tcp -j RETURN",
+		"-A OSM_NODE_PROXY -s 10.0.0.12/32 -p tcp -j DNAT --to-destination 192.168.0.1:15001",
+		"-A OSM_NODE_PROXY -d 10.0.0.12/32 -p tcp -j DNAT --to-destination 192.168.0.1:15003",
+	}, getRules("192.168.0.1", pods))
+
+	assert.Empty(getRules("192.168.0.1", nil))
+}
+
+func TestSelectIptables(t *testing.T) {
+	testCases := []struct {
+		name     string
+		outputs  map[string]string
+		failing  []string
+		expected string
+	}{
+		{
+			name: "nft backend holds the most rules",
+			outputs: map[string]string{
+				"iptables-legacy-save": "*nat\n:PREROUTING ACCEPT [0:0]\nCOMMIT\n",
+				"iptables-nft-save":    "*nat\n:PREROUTING ACCEPT [0:0]\n-A PREROUTING -j KUBE-SERVICES\nCOMMIT\n",
+			},
+			expected: "iptables-nft",
+		},
+		{
+			name: "legacy backend holds the most rules",
+			outputs: map[string]string{
+				"iptables-legacy-save": "*nat\n-A PREROUTING -j KUBE-SERVICES\n-A OUTPUT -j KUBE-SERVICES\nCOMMIT\n",
+				"iptables-nft-save":    "*nat\n-A PREROUTING -j KUBE-SERVICES\nCOMMIT\n",
+			},
+			expected: "iptables-legacy",
+		},
+		{
+			name:     "no rules and nft supported",
+			expected: "iptables-nft",
+		},
+		{
+			name:     "nft not supported by the kernel",
+			failing:  []string{"iptables-nft"},
+			expected: "iptables-legacy",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			fake := &fakeIptables{outputs: tc.outputs, failing: tc.failing}
+			r := &Redirector{run: fake.run}
+			assert.Equal(tc.expected, r.selectIptables())
+		})
+	}
+}
+
+func TestProgram(t *testing.T) {
+	rules := []string{"-A OSM_NODE_PROXY -s 10.0.0.11/32 -p tcp -j DNAT --to-destination 192.168.0.1:15001"}
+
+	testCases := []struct {
+		name             string
+		failing          []string
+		expectedCommands []string
+		expectedErr      bool
+	}{
+		{
+			name: "jump already present",
+			expectedCommands: []string{
+				"iptables-nft-restore --noflush",
+				"iptables-nft -t nat -C PREROUTING -p tcp -j OSM_NODE_PROXY",
+			},
+		},
+		{
+			name:    "jump missing",
+			failing: []string{"iptables-nft -t nat -C"},
+			expectedCommands: []string{
+				"iptables-nft-restore --noflush",
+				"iptables-nft -t nat -C PREROUTING -p tcp -j OSM_NODE_PROXY",
+				"iptables-nft -t nat -I PREROUTING -p tcp -j OSM_NODE_PROXY",
+			},
+		},
+		{
+			name:             "restore failure",
+			failing:          []string{"iptables-nft-restore"},
+			expectedCommands: []string{"iptables-nft-restore --noflush"},
+			expectedErr:      true,
+		},
+		{
+			name:    "jump failure",
+			failing: []string{"iptables-nft -t nat"},
+			expectedCommands: []string{
+				"iptables-nft-restore --noflush",
+				"iptables-nft -t nat -C PREROUTING -p tcp -j OSM_NODE_PROXY",
+				"iptables-nft -t nat -I PREROUTING -p tcp -j OSM_NODE_PROXY",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			fake := &fakeIptables{failing: tc.failing}
+			r := &Redirector{iptables: "iptables-nft", run: fake.run}
+			err := r.program(rules)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedCommands, fake.commands)
+			assert.Equal([]string{"*nat\n:OSM_NODE_PROXY - [0:0]\n" + rules[0] + "\nCOMMIT\n"}, fake.stdin)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	assert := tassert.New(t)
+
+	newPod := func(name, namespace, ip string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	nodeMode := map[string]string{constants.ProxyModeAnnotation: constants.ProxyModeNode}
+
+	podStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	namespaceStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(namespaceStore.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitored"}}))
+
+	redirected := newPod("redirected", "monitored", "10.0.0.11", nodeMode)
+	sidecar := newPod("sidecar", "monitored", "10.0.0.12", nil)
+	unmonitored := newPod("unmonitored", "other", "10.0.0.13", nodeMode)
+	pending := newPod("pending", "monitored", "10.0.0.14", nodeMode)
+	pending.Status.Phase = corev1.PodPending
+	hostNetwork := newPod("host-network", "monitored", "192.168.0.1", nodeMode)
+	hostNetwork.Spec.HostNetwork = true
+	ipv6 := newPod("ipv6", "monitored", "fd00::1", nodeMode)
+	for _, pod := range []*corev1.Pod{redirected, sidecar, unmonitored, pending, hostNetwork, ipv6} {
+		assert.Nil(podStore.Add(pod))
+	}
+
+	fake := &fakeIptables{}
+	r := &Redirector{
+		nodeIP:         "192.168.0.1",
+		iptables:       "iptables-nft",
+		run:            fake.run,
+		podStore:       podStore,
+		namespaceStore: namespaceStore,
+	}
+	assert.False(r.Readiness())
+
+	r.reconcile()
+	assert.True(r.Readiness())
+	assert.Equal(getRules("192.168.0.1", map[string]*corev1.Pod{"10.0.0.11": redirected}), r.rules)
+
+	// The redirector is no longer ready once the rules fail to be programmed
+	fake.failing = []string{"iptables-nft-restore"}
+	r.reconcile()
+	assert.False(r.Readiness())
+
+	// The jump is found once, then removed along with the chain
+	jumps := 0
+	r.run = func(stdin string, name string, args ...string) ([]byte, error) {
+		if len(args) > 2 && args[2] == "-C" {
+			if jumps++; jumps > 1 {
+				return nil, errors.New("not found")
+			}
+		}
+		return fake.run(stdin, name, args...)
+	}
+	fake.failing = nil
+	fake.commands = nil
+	r.cleanup()
+	assert.False(r.Readiness())
+	assert.Equal([]string{
+		"iptables-nft -t nat -C PREROUTING -p tcp -j OSM_NODE_PROXY",
+		"iptables-nft -t nat -D PREROUTING -p tcp -j OSM_NODE_PROXY",
+		"iptables-nft -t nat -F OSM_NODE_PROXY",
+		"iptables-nft -t nat -X OSM_NODE_PROXY",
+	}, fake.commands)
+}
@@ -0,0 +1,56 @@
+// Package nodeproxy implements the traffic redirection of the pods in the experimental node proxy mode. The redirector
+// runs next to the per-node proxy of every node of the cluster, in the network namespace of the node, and programs
+// iptables rules redirecting the TCP traffic sent by and destined to the node proxy mode pods of its node to the
+// per-node proxy.
+package nodeproxy
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("node-proxy-redirect")
+
+const (
+	// reconcileInterval is the interval at which the redirection rules are reconciled with the node proxy mode pods of
+	// the node, in addition to the changes of the pods
+	reconcileInterval = 30 * time.Second
+
+	// chainName is the name of the nat chain holding the redirection rules of the node proxy mode pods of the node
+	chainName = "OSM_NODE_PROXY"
+
+	// dnsPort is the port of the DNS traffic of the pods, which is not redirected to the node proxy
+	dnsPort = 53
+)
+
+// runFunc runs the given command with the given standard input and returns its combined output
+type runFunc func(stdin string, name string, args ...string) ([]byte, error)
+
+// Redirector programs the iptables rules redirecting the traffic of the node proxy mode pods of a node to the node proxy
+type Redirector struct {
+	nodeName string
+	nodeIP   string
+	meshName string
+
+	podStore       cache.Store
+	namespaceStore cache.Store
+
+	// iptables is the iptables command of the backend used by the node, selected on startup
+	iptables string
+	run      runFunc
+
+	// reconcileCh triggers a reconciliation of the rules with the pods of the node
+	reconcileCh chan struct{}
+	// done is closed once the redirector has stopped
+	done chan struct{}
+
+	mu sync.RWMutex
+	// programmed is true once the rules of the node proxy mode pods of the node are programmed, and until they fail to be
+	programmed bool
+	// rules are the last programmed rules
+	rules []string
+}
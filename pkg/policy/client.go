@@ -0,0 +1,334 @@
+package policy
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewPolicyClient implements policy.Monitor and creates the Kubernetes client to monitor Egress, Retry,
+// UpstreamTrafficSetting and BandwidthLimit resources.
+func NewPolicyClient(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, kubeController k8s.Controller, stop chan struct{}) (Monitor, error) {
+	egressSupported, err := k8s.IsKindServed(kubeClient.Discovery(), policyV1alpha1.SchemeGroupVersion, policyV1alpha1.EgressKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the Egress API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	retrySupported, err := k8s.IsKindServed(kubeClient.Discovery(), policyV1alpha1.SchemeGroupVersion, policyV1alpha1.RetryKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the Retry API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	upstreamTrafficSettingSupported, err := k8s.IsKindServed(kubeClient.Discovery(), policyV1alpha1.SchemeGroupVersion, policyV1alpha1.UpstreamTrafficSettingKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the UpstreamTrafficSetting API versions served by the Kubernetes API server")
+		return nil, err
+	}
+	bandwidthLimitSupported, err := k8s.IsKindServed(kubeClient.Discovery(), policyV1alpha1.SchemeGroupVersion, policyV1alpha1.BandwidthLimitKind)
+	if err != nil {
+		log.Error().Err(err).Msg("Error retrieving the BandwidthLimit API versions served by the Kubernetes API server")
+		return nil, err
+	}
+
+	client := Client{
+		cacheSynced:    make(chan interface{}),
+		kubeController: kubeController,
+	}
+
+	shouldObserve := func(obj interface{}) bool {
+		object, ok := obj.(metav1.Object)
+		return ok && kubeController.IsMonitoredNamespace(object.GetNamespace())
+	}
+
+	// The Egress CRD is optional, the egress traffic of the mesh is then only configured by the global egress setting
+	var informersToSync []cache.SharedIndexInformer
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, k8s.DefaultKubeEventResyncInterval)
+	if egressSupported {
+		log.Info().Msgf("Watching Egress resources with API version %s", policyV1alpha1.SchemeGroupVersion)
+		client.informerEgress = dynamicInformerFactory.ForResource(policyV1alpha1.EgressResource).Informer()
+		client.cacheEgress = client.informerEgress.GetStore()
+
+		egressEventTypes := k8s.EventTypes{
+			Add:    announcements.EgressAdded,
+			Update: announcements.EgressUpdated,
+			Delete: announcements.EgressDeleted,
+		}
+		client.informerEgress.AddEventHandler(k8s.GetKubernetesEventHandlers("Egress", "OSM", shouldObserve, egressEventTypes))
+		informersToSync = append(informersToSync, client.informerEgress)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching Egress resources", policyV1alpha1.SchemeGroupVersion)
+	}
+
+	// The Retry CRD is optional, the requests are then only retried by the default retry policy of the sidecar proxies
+	if retrySupported {
+		log.Info().Msgf("Watching Retry resources with API version %s", policyV1alpha1.SchemeGroupVersion)
+		client.informerRetry = dynamicInformerFactory.ForResource(policyV1alpha1.RetryResource).Informer()
+		client.cacheRetry = client.informerRetry.GetStore()
+
+		retryEventTypes := k8s.EventTypes{
+			Add:    announcements.RetryPolicyAdded,
+			Update: announcements.RetryPolicyUpdated,
+			Delete: announcements.RetryPolicyDeleted,
+		}
+		client.informerRetry.AddEventHandler(k8s.GetKubernetesEventHandlers("Retry", "OSM", shouldObserve, retryEventTypes))
+		informersToSync = append(informersToSync, client.informerRetry)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching Retry resources", policyV1alpha1.SchemeGroupVersion)
+	}
+
+	// The UpstreamTrafficSetting CRD is optional, the traffic to the upstream services is then only limited by their
+	// traffic priority
+	if upstreamTrafficSettingSupported {
+		log.Info().Msgf("Watching UpstreamTrafficSetting resources with API version %s", policyV1alpha1.SchemeGroupVersion)
+		client.informerUpstreamTrafficSetting = dynamicInformerFactory.ForResource(policyV1alpha1.UpstreamTrafficSettingResource).Informer()
+		client.cacheUpstreamTrafficSetting = client.informerUpstreamTrafficSetting.GetStore()
+
+		upstreamTrafficSettingEventTypes := k8s.EventTypes{
+			Add:    announcements.UpstreamTrafficSettingAdded,
+			Update: announcements.UpstreamTrafficSettingUpdated,
+			Delete: announcements.UpstreamTrafficSettingDeleted,
+		}
+		client.informerUpstreamTrafficSetting.AddEventHandler(k8s.GetKubernetesEventHandlers("UpstreamTrafficSetting", "OSM", shouldObserve, upstreamTrafficSettingEventTypes))
+		informersToSync = append(informersToSync, client.informerUpstreamTrafficSetting)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching UpstreamTrafficSetting resources", policyV1alpha1.SchemeGroupVersion)
+	}
+
+	// The BandwidthLimit CRD is optional, the HTTP responses of the services are then not limited
+	if bandwidthLimitSupported {
+		log.Info().Msgf("Watching BandwidthLimit resources with API version %s", policyV1alpha1.SchemeGroupVersion)
+		client.informerBandwidthLimit = dynamicInformerFactory.ForResource(policyV1alpha1.BandwidthLimitResource).Informer()
+		client.cacheBandwidthLimit = client.informerBandwidthLimit.GetStore()
+
+		bandwidthLimitEventTypes := k8s.EventTypes{
+			Add:    announcements.BandwidthLimitAdded,
+			Update: announcements.BandwidthLimitUpdated,
+			Delete: announcements.BandwidthLimitDeleted,
+		}
+		client.informerBandwidthLimit.AddEventHandler(k8s.GetKubernetesEventHandlers("BandwidthLimit", "OSM", shouldObserve, bandwidthLimitEventTypes))
+		informersToSync = append(informersToSync, client.informerBandwidthLimit)
+	} else {
+		log.Info().Msgf("API version %s is not served, not watching BandwidthLimit resources", policyV1alpha1.SchemeGroupVersion)
+	}
+
+	if err := client.run(stop, informersToSync...); err != nil {
+		log.Error().Err(err).Msg("Could not start policy client")
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// run executes informer collection.
+func (c *Client) run(stop <-chan struct{}, informers ...cache.SharedIndexInformer) error {
+	log.Info().Msg("Policy client started")
+
+	var hasSynced []cache.InformerSynced
+	for _, informer := range informers {
+		if informer == nil {
+			return errInitInformers
+		}
+
+		go informer.Run(stop)
+		hasSynced = append(hasSynced, informer.HasSynced)
+	}
+
+	log.Info().Msgf("Waiting for policy informer cache sync")
+	if !cache.WaitForCacheSync(stop, hasSynced...) {
+		return errSyncingCaches
+	}
+
+	// Closing the cacheSynced channel signals to the rest of the system that... caches have been synced.
+	close(c.cacheSynced)
+
+	log.Info().Msgf("Cache sync finished for policy informers")
+	return nil
+}
+
+// ListEgressPolicies returns the Egress resources of the monitored namespaces, sorted by namespace and name.
+func (c Client) ListEgressPolicies() []*policyV1alpha1.Egress {
+	return c.listEgressPolicies(func(*policyV1alpha1.Egress) bool {
+		return true
+	})
+}
+
+// ListEgressPoliciesForSourceIdentity returns the Egress resources of the monitored namespaces whose sources include
+// the given service account, sorted by namespace and name.
+func (c Client) ListEgressPoliciesForSourceIdentity(source service.K8sServiceAccount) []*policyV1alpha1.Egress {
+	return c.listEgressPolicies(func(egress *policyV1alpha1.Egress) bool {
+		for _, sourceSpec := range egress.Spec.Sources {
+			if sourceSpec.Kind == policyV1alpha1.KindServiceAccount && sourceSpec.Name == source.Name && sourceSpec.Namespace == source.Namespace {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// listEgressPolicies returns the Egress resources of the monitored namespaces matching the given filter, sorted by
+// namespace and name.
+func (c Client) listEgressPolicies(filter func(*policyV1alpha1.Egress) bool) []*policyV1alpha1.Egress {
+	if c.cacheEgress == nil {
+		// The Egress API is not served
+		return nil
+	}
+
+	var egresses []*policyV1alpha1.Egress
+	for _, egressInterface := range c.cacheEgress.List() {
+		unstructuredEgress, ok := egressInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for Egress in Egress cache")
+			continue
+		}
+		if !c.kubeController.IsMonitoredNamespace(unstructuredEgress.GetNamespace()) {
+			continue
+		}
+
+		egress := &policyV1alpha1.Egress{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredEgress.UnstructuredContent(), egress); err != nil {
+			log.Error().Err(err).Msgf("Error converting Egress %s/%s", unstructuredEgress.GetNamespace(), unstructuredEgress.GetName())
+			continue
+		}
+
+		if filter(egress) {
+			egresses = append(egresses, egress)
+		}
+	}
+
+	sort.Slice(egresses, func(i, j int) bool {
+		if egresses[i].Namespace != egresses[j].Namespace {
+			return egresses[i].Namespace < egresses[j].Namespace
+		}
+		return egresses[i].Name < egresses[j].Name
+	})
+	return egresses
+}
+
+// ListRetryPoliciesForSourceIdentity returns the Retry resources of the monitored namespaces whose source is the given
+// service account, sorted by namespace and name.
+func (c Client) ListRetryPoliciesForSourceIdentity(source service.K8sServiceAccount) []*policyV1alpha1.Retry {
+	if c.cacheRetry == nil {
+		// The Retry API is not served
+		return nil
+	}
+
+	var retries []*policyV1alpha1.Retry
+	for _, retryInterface := range c.cacheRetry.List() {
+		unstructuredRetry, ok := retryInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for Retry in Retry cache")
+			continue
+		}
+		if !c.kubeController.IsMonitoredNamespace(unstructuredRetry.GetNamespace()) {
+			continue
+		}
+
+		retry := &policyV1alpha1.Retry{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredRetry.UnstructuredContent(), retry); err != nil {
+			log.Error().Err(err).Msgf("Error converting Retry %s/%s", unstructuredRetry.GetNamespace(), unstructuredRetry.GetName())
+			continue
+		}
+
+		sourceSpec := retry.Spec.Source
+		if sourceSpec.Kind == policyV1alpha1.KindServiceAccount && sourceSpec.Name == source.Name && sourceSpec.Namespace == source.Namespace {
+			retries = append(retries, retry)
+		}
+	}
+
+	sort.Slice(retries, func(i, j int) bool {
+		if retries[i].Namespace != retries[j].Namespace {
+			return retries[i].Namespace < retries[j].Namespace
+		}
+		return retries[i].Name < retries[j].Name
+	})
+	return retries
+}
+
+// GetUpstreamTrafficSetting returns the UpstreamTrafficSetting resource of the given upstream service, which is the
+// first one by name in the namespace of the service whose host is the fully qualified domain name of the service. Nil
+// is returned if the service has none, or if its namespace is not monitored.
+func (c Client) GetUpstreamTrafficSetting(svc service.MeshService) *policyV1alpha1.UpstreamTrafficSetting {
+	if c.cacheUpstreamTrafficSetting == nil {
+		// The UpstreamTrafficSetting API is not served
+		return nil
+	}
+	if !c.kubeController.IsMonitoredNamespace(svc.Namespace) {
+		return nil
+	}
+
+	var upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+	for _, settingInterface := range c.cacheUpstreamTrafficSetting.List() {
+		unstructuredSetting, ok := settingInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for UpstreamTrafficSetting in UpstreamTrafficSetting cache")
+			continue
+		}
+		if unstructuredSetting.GetNamespace() != svc.Namespace {
+			continue
+		}
+
+		setting := &policyV1alpha1.UpstreamTrafficSetting{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredSetting.UnstructuredContent(), setting); err != nil {
+			log.Error().Err(err).Msgf("Error converting UpstreamTrafficSetting %s/%s", unstructuredSetting.GetNamespace(), unstructuredSetting.GetName())
+			continue
+		}
+
+		if setting.Spec.Host != svc.ServerName() {
+			continue
+		}
+		if upstreamTrafficSetting == nil || setting.Name < upstreamTrafficSetting.Name {
+			upstreamTrafficSetting = setting
+		}
+	}
+	return upstreamTrafficSetting
+}
+
+// GetBandwidthLimit returns the BandwidthLimit resource of the given service, which is the first one by name in the
+// namespace of the service whose host is the fully qualified domain name of the service. Nil is returned if the service
+// has none, or if its namespace is not monitored.
+func (c Client) GetBandwidthLimit(svc service.MeshService) *policyV1alpha1.BandwidthLimit {
+	if c.cacheBandwidthLimit == nil {
+		// The BandwidthLimit API is not served
+		return nil
+	}
+	if !c.kubeController.IsMonitoredNamespace(svc.Namespace) {
+		return nil
+	}
+
+	var bandwidthLimit *policyV1alpha1.BandwidthLimit
+	for _, limitInterface := range c.cacheBandwidthLimit.List() {
+		unstructuredLimit, ok := limitInterface.(*unstructured.Unstructured)
+		if !ok {
+			log.Error().Msg("Failed type assertion for BandwidthLimit in BandwidthLimit cache")
+			continue
+		}
+		if unstructuredLimit.GetNamespace() != svc.Namespace {
+			continue
+		}
+
+		limit := &policyV1alpha1.BandwidthLimit{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredLimit.UnstructuredContent(), limit); err != nil {
+			log.Error().Err(err).Msgf("Error converting BandwidthLimit %s/%s", unstructuredLimit.GetNamespace(), unstructuredLimit.GetName())
+			continue
+		}
+
+		if limit.Spec.Host != svc.ServerName() {
+			continue
+		}
+		if bandwidthLimit == nil || limit.Name < bandwidthLimit.Name {
+			bandwidthLimit = limit
+		}
+	}
+	return bandwidthLimit
+}
@@ -0,0 +1,272 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestListEgressPoliciesForSourceIdentity(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("curl").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("egress").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	newEgress := func(name, namespace string, sources ...service.K8sServiceAccount) *unstructured.Unstructured {
+		var sourceSpecs []interface{}
+		for _, source := range sources {
+			sourceSpecs = append(sourceSpecs, map[string]interface{}{
+				"kind":      policyV1alpha1.KindServiceAccount,
+				"name":      source.Name,
+				"namespace": source.Namespace,
+			})
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": policyV1alpha1.SchemeGroupVersion.String(),
+			"kind":       policyV1alpha1.EgressKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"sources": sourceSpecs,
+				"hosts":   []interface{}{"httpbin.org"},
+				"ports": []interface{}{
+					map[string]interface{}{"number": int64(443), "protocol": policyV1alpha1.ProtocolHTTPS},
+				},
+			},
+		}}
+	}
+
+	curl := service.K8sServiceAccount{Name: "curl", Namespace: "curl"}
+	bookbuyer := service.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newEgress("b", "curl", curl)))
+	assert.Nil(store.Add(newEgress("a", "egress", bookbuyer, curl)))
+	assert.Nil(store.Add(newEgress("c", "egress", bookbuyer)))
+	assert.Nil(store.Add(newEgress("d", "unmonitored", curl)))
+
+	client := Client{
+		cacheEgress:    store,
+		kubeController: mockKubeController,
+	}
+
+	// The Egress resources of the monitored namespaces listing the service account as a source are returned, sorted by
+	// namespace and name
+	egresses := client.ListEgressPoliciesForSourceIdentity(curl)
+	assert.Len(egresses, 2)
+	assert.Equal("curl", egresses[0].Namespace)
+	assert.Equal("b", egresses[0].Name)
+	assert.Equal("egress", egresses[1].Namespace)
+	assert.Equal("a", egresses[1].Name)
+	assert.Equal([]string{"httpbin.org"}, egresses[1].Spec.Hosts)
+	assert.Equal([]policyV1alpha1.PortSpec{{Number: 443, Protocol: policyV1alpha1.ProtocolHTTPS}}, egresses[1].Spec.Ports)
+
+	assert.Empty(client.ListEgressPoliciesForSourceIdentity(service.K8sServiceAccount{Name: "bookstore", Namespace: "bookstore"}))
+
+	// All the Egress resources of the monitored namespaces are listed regardless of their sources
+	egresses = client.ListEgressPolicies()
+	assert.Len(egresses, 3)
+	assert.Equal("b", egresses[0].Name)
+	assert.Equal("a", egresses[1].Name)
+	assert.Equal("c", egresses[2].Name)
+
+	// No Egress resource is returned when the Egress API is not served
+	assert.Nil(Client{kubeController: mockKubeController}.ListEgressPoliciesForSourceIdentity(curl))
+	assert.Nil(Client{kubeController: mockKubeController}.ListEgressPolicies())
+}
+
+func TestListRetryPoliciesForSourceIdentity(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("bookbuyer").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	newRetry := func(name, namespace string, source service.K8sServiceAccount) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": policyV1alpha1.SchemeGroupVersion.String(),
+			"kind":       policyV1alpha1.RetryKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{
+					"kind":      policyV1alpha1.KindServiceAccount,
+					"name":      source.Name,
+					"namespace": source.Namespace,
+				},
+				"destinations": []interface{}{
+					map[string]interface{}{"kind": policyV1alpha1.KindService, "name": "bookstore", "namespace": "bookstore"},
+				},
+				"retryPolicy": map[string]interface{}{
+					"retryOn":                  "5xx",
+					"numRetries":               int64(3),
+					"perTryTimeout":            "1s",
+					"retryBackoffBaseInterval": "10ms",
+				},
+			},
+		}}
+	}
+
+	bookbuyer := service.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}
+	bookthief := service.K8sServiceAccount{Name: "bookthief", Namespace: "bookbuyer"}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newRetry("b", "bookbuyer", bookbuyer)))
+	assert.Nil(store.Add(newRetry("a", "bookbuyer", bookbuyer)))
+	assert.Nil(store.Add(newRetry("c", "bookbuyer", bookthief)))
+	assert.Nil(store.Add(newRetry("d", "unmonitored", bookbuyer)))
+
+	client := Client{
+		cacheRetry:     store,
+		kubeController: mockKubeController,
+	}
+
+	// The Retry resources of the monitored namespaces whose source is the service account are returned, sorted by
+	// namespace and name
+	retries := client.ListRetryPoliciesForSourceIdentity(bookbuyer)
+	assert.Len(retries, 2)
+	assert.Equal("a", retries[0].Name)
+	assert.Equal("b", retries[1].Name)
+	assert.Equal([]policyV1alpha1.RetryDestinationSpec{{Kind: policyV1alpha1.KindService, Name: "bookstore", Namespace: "bookstore"}}, retries[0].Spec.Destinations)
+	assert.Equal("5xx", retries[0].Spec.RetryPolicy.RetryOn)
+	assert.Equal(uint32(3), retries[0].Spec.RetryPolicy.NumRetries)
+	assert.Equal(time.Second, retries[0].Spec.RetryPolicy.PerTryTimeout.Duration)
+	assert.Equal(10*time.Millisecond, retries[0].Spec.RetryPolicy.RetryBackoffBaseInterval.Duration)
+	assert.Nil(retries[0].Spec.RetryPolicy.RetryBackoffMaxInterval)
+
+	assert.Empty(client.ListRetryPoliciesForSourceIdentity(service.K8sServiceAccount{Name: "bookstore", Namespace: "bookstore"}))
+
+	// No Retry resource is returned when the Retry API is not served
+	assert.Nil(Client{kubeController: mockKubeController}.ListRetryPoliciesForSourceIdentity(bookbuyer))
+}
+
+func TestGetUpstreamTrafficSetting(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("bookstore").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	newUpstreamTrafficSetting := func(name, namespace, host string, maxConnections int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": policyV1alpha1.SchemeGroupVersion.String(),
+			"kind":       policyV1alpha1.UpstreamTrafficSettingKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"host": host,
+				"connectionSettings": map[string]interface{}{
+					"tcp": map[string]interface{}{"maxConnections": maxConnections},
+					"http": map[string]interface{}{
+						"maxPendingRequests": int64(10),
+						"maxRetries":         int64(2),
+					},
+				},
+			},
+		}}
+	}
+
+	bookstore := service.MeshService{Name: "bookstore", Namespace: "bookstore"}
+	unmonitored := service.MeshService{Name: "bookstore", Namespace: "unmonitored"}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newUpstreamTrafficSetting("b", "bookstore", "bookstore.bookstore.svc.cluster.local", 100)))
+	assert.Nil(store.Add(newUpstreamTrafficSetting("a", "bookstore", "bookstore.bookstore.svc.cluster.local", 50)))
+	assert.Nil(store.Add(newUpstreamTrafficSetting("c", "bookstore", "bookstore-v2.bookstore.svc.cluster.local", 10)))
+	assert.Nil(store.Add(newUpstreamTrafficSetting("d", "bookbuyer", "bookstore.bookstore.svc.cluster.local", 10)))
+	assert.Nil(store.Add(newUpstreamTrafficSetting("e", "unmonitored", "bookstore.unmonitored.svc.cluster.local", 10)))
+
+	client := Client{
+		cacheUpstreamTrafficSetting: store,
+		kubeController:              mockKubeController,
+	}
+
+	// The first UpstreamTrafficSetting by name in the namespace of the service whose host is the service is returned
+	setting := client.GetUpstreamTrafficSetting(bookstore)
+	assert.NotNil(setting)
+	assert.Equal("a", setting.Name)
+	assert.Equal(uint32(50), *setting.Spec.ConnectionSettings.TCP.MaxConnections)
+	assert.Equal(uint32(10), *setting.Spec.ConnectionSettings.HTTP.MaxPendingRequests)
+	assert.Equal(uint32(2), *setting.Spec.ConnectionSettings.HTTP.MaxRetries)
+	assert.Nil(setting.Spec.ConnectionSettings.HTTP.MaxRequests)
+	assert.Nil(setting.Spec.ConnectionSettings.HTTP.MaxRequestsPerConnection)
+
+	assert.Nil(client.GetUpstreamTrafficSetting(service.MeshService{Name: "bookstore-v1", Namespace: "bookstore"}))
+	assert.Nil(client.GetUpstreamTrafficSetting(unmonitored))
+
+	// No UpstreamTrafficSetting is returned when the UpstreamTrafficSetting API is not served
+	assert.Nil(Client{kubeController: mockKubeController}.GetUpstreamTrafficSetting(bookstore))
+}
+
+func TestGetBandwidthLimit(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("bookstore").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	newBandwidthLimit := func(name, namespace, host string, egressKiBps int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": policyV1alpha1.SchemeGroupVersion.String(),
+			"kind":       policyV1alpha1.BandwidthLimitKind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"spec": map[string]interface{}{
+				"host":        host,
+				"egressKiBps": egressKiBps,
+				"routes": []interface{}{
+					map[string]interface{}{
+						"httpRouteGroup": "bookstore-service-routes",
+						"matches":        []interface{}{"books-bought"},
+						"egressKiBps":    int64(128),
+					},
+				},
+			},
+		}}
+	}
+
+	bookstore := service.MeshService{Name: "bookstore", Namespace: "bookstore"}
+	unmonitored := service.MeshService{Name: "bookstore", Namespace: "unmonitored"}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.Nil(store.Add(newBandwidthLimit("b", "bookstore", "bookstore.bookstore.svc.cluster.local", 2048)))
+	assert.Nil(store.Add(newBandwidthLimit("a", "bookstore", "bookstore.bookstore.svc.cluster.local", 1024)))
+	assert.Nil(store.Add(newBandwidthLimit("c", "bookstore", "bookstore-v2.bookstore.svc.cluster.local", 512)))
+	assert.Nil(store.Add(newBandwidthLimit("d", "bookbuyer", "bookstore.bookstore.svc.cluster.local", 512)))
+	assert.Nil(store.Add(newBandwidthLimit("e", "unmonitored", "bookstore.unmonitored.svc.cluster.local", 512)))
+
+	client := Client{
+		cacheBandwidthLimit: store,
+		kubeController:      mockKubeController,
+	}
+
+	// The first BandwidthLimit by name in the namespace of the service whose host is the service is returned
+	limit := client.GetBandwidthLimit(bookstore)
+	assert.NotNil(limit)
+	assert.Equal("a", limit.Name)
+	assert.Equal(uint64(1024), *limit.Spec.EgressKiBps)
+	assert.Nil(limit.Spec.IngressKiBps)
+	assert.Equal([]policyV1alpha1.RouteBandwidthLimitSpec{
+		{HTTPRouteGroup: "bookstore-service-routes", Matches: []string{"books-bought"}, EgressKiBps: 128},
+	}, limit.Spec.Routes)
+
+	assert.Nil(client.GetBandwidthLimit(service.MeshService{Name: "bookstore-v1", Namespace: "bookstore"}))
+	assert.Nil(client.GetBandwidthLimit(unmonitored))
+
+	// No BandwidthLimit is returned when the BandwidthLimit API is not served
+	assert.Nil(Client{kubeController: mockKubeController}.GetBandwidthLimit(bookstore))
+}
@@ -0,0 +1,8 @@
+package policy
+
+import "github.com/pkg/errors"
+
+var (
+	errSyncingCaches = errors.New("Failed initial cache sync for policy informers")
+	errInitInformers = errors.New("Policy informer not initialized")
+)
@@ -0,0 +1,57 @@
+// Package policy implements functionality to monitor and retrieve OSM policy resources, which configure the traffic
+// between the mesh and the destinations outside of it, and the retries and limits of the traffic within the mesh.
+package policy
+
+import (
+	"k8s.io/client-go/tools/cache"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var (
+	log = logger.New("policy-controller")
+)
+
+// Client is a struct for all components necessary to connect to and maintain state of OSM policy resources.
+type Client struct {
+	cacheSynced    chan interface{}
+	kubeController k8s.Controller
+
+	// The Egress informer is only initialized when the Egress API is served
+	informerEgress cache.SharedIndexInformer
+	cacheEgress    cache.Store
+
+	// The Retry informer is only initialized when the Retry API is served
+	informerRetry cache.SharedIndexInformer
+	cacheRetry    cache.Store
+
+	// The UpstreamTrafficSetting informer is only initialized when the UpstreamTrafficSetting API is served
+	informerUpstreamTrafficSetting cache.SharedIndexInformer
+	cacheUpstreamTrafficSetting    cache.Store
+
+	// The BandwidthLimit informer is only initialized when the BandwidthLimit API is served
+	informerBandwidthLimit cache.SharedIndexInformer
+	cacheBandwidthLimit    cache.Store
+}
+
+// Monitor is the client interface for OSM policy resources
+type Monitor interface {
+	// ListEgressPolicies returns all the Egress resources
+	ListEgressPolicies() []*policyV1alpha1.Egress
+
+	// ListEgressPoliciesForSourceIdentity returns the Egress resources whose sources include the given service account
+	ListEgressPoliciesForSourceIdentity(service.K8sServiceAccount) []*policyV1alpha1.Egress
+
+	// ListRetryPoliciesForSourceIdentity returns the Retry resources whose source is the given service account
+	ListRetryPoliciesForSourceIdentity(service.K8sServiceAccount) []*policyV1alpha1.Retry
+
+	// GetUpstreamTrafficSetting returns the UpstreamTrafficSetting resource of the given upstream service, or nil if it
+	// has none
+	GetUpstreamTrafficSetting(service.MeshService) *policyV1alpha1.UpstreamTrafficSetting
+
+	// GetBandwidthLimit returns the BandwidthLimit resource of the given service, or nil if it has none
+	GetBandwidthLimit(service.MeshService) *policyV1alpha1.BandwidthLimit
+}
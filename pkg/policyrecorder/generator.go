@@ -0,0 +1,230 @@
+package policyrecorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// invalidMatchNameChars are the characters of a path replaced in the name of the match generated for the path
+var invalidMatchNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// routeKey is the method and path of an observed request
+type routeKey struct {
+	method string
+	path   string
+}
+
+// GeneratePolicies returns the HTTPRouteGroups and TrafficTargets allowing the given observed requests, and only them.
+// A HTTPRouteGroup is generated per destination, with a match per method and path the destination received requests on,
+// and a TrafficTarget is generated per source and destination, allowing the matches the source sent requests to.
+// Requests whose source or destination identity is not a Kubernetes service account are ignored.
+func GeneratePolicies(requests []envoy.ObservedRequest) *Policies {
+	// destination -> source -> observed routes
+	routesByDestination := make(map[service.K8sServiceAccount]map[service.K8sServiceAccount]map[routeKey]struct{})
+	for _, request := range requests {
+		source, err := identity.GetKubernetesServiceAccount(identity.ServiceIdentity(request.SourceIdentity))
+		if err != nil {
+			log.Debug().Err(err).Msgf("Ignoring request observed from source %s", request.SourceIdentity)
+			continue
+		}
+		destination, err := identity.GetKubernetesServiceAccount(identity.ServiceIdentity(request.DestinationIdentity))
+		if err != nil {
+			log.Debug().Err(err).Msgf("Ignoring request observed by destination %s", request.DestinationIdentity)
+			continue
+		}
+
+		if _, ok := routesByDestination[destination]; !ok {
+			routesByDestination[destination] = make(map[service.K8sServiceAccount]map[routeKey]struct{})
+		}
+		if _, ok := routesByDestination[destination][source]; !ok {
+			routesByDestination[destination][source] = make(map[routeKey]struct{})
+		}
+		routesByDestination[destination][source][routeKey{method: request.Method, path: request.Path}] = struct{}{}
+	}
+
+	var destinations []service.K8sServiceAccount
+	for destination := range routesByDestination {
+		destinations = append(destinations, destination)
+	}
+	sortServiceAccounts(destinations)
+
+	policies := &Policies{}
+	for _, destination := range destinations {
+		sources := routesByDestination[destination]
+
+		var sortedSources []service.K8sServiceAccount
+		routes := make(map[routeKey]struct{})
+		for source, sourceRoutes := range sources {
+			sortedSources = append(sortedSources, source)
+			for route := range sourceRoutes {
+				routes[route] = struct{}{}
+			}
+		}
+		sortServiceAccounts(sortedSources)
+
+		routeGroup, matchNames := buildHTTPRouteGroup(destination, routes)
+		policies.HTTPRouteGroups = append(policies.HTTPRouteGroups, routeGroup)
+
+		for _, source := range sortedSources {
+			var matches []string
+			for route := range sources[source] {
+				matches = append(matches, matchNames[route])
+			}
+			sort.Strings(matches)
+			policies.TrafficTargets = append(policies.TrafficTargets, buildTrafficTarget(source, destination, routeGroup.Name, matches))
+		}
+	}
+
+	return policies
+}
+
+// buildHTTPRouteGroup returns the HTTPRouteGroup of the given destination matching the given routes, and the name of
+// the match of each route
+func buildHTTPRouteGroup(destination service.K8sServiceAccount, routes map[routeKey]struct{}) (*spec.HTTPRouteGroup, map[routeKey]string) {
+	var sortedRoutes []routeKey
+	for route := range routes {
+		sortedRoutes = append(sortedRoutes, route)
+	}
+	sort.Slice(sortedRoutes, func(i, j int) bool {
+		if sortedRoutes[i].path != sortedRoutes[j].path {
+			return sortedRoutes[i].path < sortedRoutes[j].path
+		}
+		return sortedRoutes[i].method < sortedRoutes[j].method
+	})
+
+	routeGroup := &spec.HTTPRouteGroup{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: httpRouteGroupAPIVersion,
+			Kind:       httpRouteGroupKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      destination.Name + httpRouteGroupNameSuffix,
+			Namespace: destination.Namespace,
+		},
+	}
+	matchNames := make(map[routeKey]string)
+	usedNames := make(map[string]bool)
+	for _, route := range sortedRoutes {
+		name := getMatchName(route)
+		// Distinct paths, such as /books_bought and /books-bought, may result in the same name
+		for i := 2; usedNames[name]; i++ {
+			name = fmt.Sprintf("%s-%d", getMatchName(route), i)
+		}
+		usedNames[name] = true
+		matchNames[route] = name
+
+		routeGroup.Spec.Matches = append(routeGroup.Spec.Matches, spec.HTTPMatch{
+			Name:      name,
+			PathRegex: regexp.QuoteMeta(route.path),
+			Methods:   []string{route.method},
+		})
+	}
+
+	return routeGroup, matchNames
+}
+
+// getMatchName returns the name of the match of the given route, Ex. get-books-bought for GET /books-bought
+func getMatchName(route routeKey) string {
+	name := strings.ToLower(route.method)
+	if path := strings.Trim(invalidMatchNameChars.ReplaceAllString(strings.ToLower(route.path), "-"), "-"); path != "" {
+		name += "-" + path
+	}
+	return name
+}
+
+// buildTrafficTarget returns the TrafficTarget allowing the given source to access the given matches of the given
+// HTTPRouteGroup of the given destination
+func buildTrafficTarget(source, destination service.K8sServiceAccount, routeGroupName string, matches []string) *access.TrafficTarget {
+	return &access.TrafficTarget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: trafficTargetAPIVersion,
+			Kind:       trafficTargetKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-access-%s", source.Name, source.Namespace, destination.Name),
+			Namespace: destination.Namespace,
+		},
+		Spec: access.TrafficTargetSpec{
+			Destination: access.IdentityBindingSubject{
+				Kind:      serviceAccountKind,
+				Name:      destination.Name,
+				Namespace: destination.Namespace,
+			},
+			Sources: []access.IdentityBindingSubject{{
+				Kind:      serviceAccountKind,
+				Name:      source.Name,
+				Namespace: source.Namespace,
+			}},
+			Rules: []access.TrafficTargetRule{{
+				Kind:    httpRouteGroupKind,
+				Name:    routeGroupName,
+				Matches: matches,
+			}},
+		},
+	}
+}
+
+// sortServiceAccounts sorts the given service accounts by namespace and name
+func sortServiceAccounts(svcAccounts []service.K8sServiceAccount) {
+	sort.Slice(svcAccounts, func(i, j int) bool {
+		return svcAccounts[i].String() < svcAccounts[j].String()
+	})
+}
+
+// MarshalPolicies returns the given policies as a multi-document YAML, which can be reviewed and applied with kubectl
+func MarshalPolicies(policies *Policies) ([]byte, error) {
+	var objects []interface{}
+	for _, routeGroup := range policies.HTTPRouteGroups {
+		objects = append(objects, routeGroup)
+	}
+	for _, trafficTarget := range policies.TrafficTargets {
+		objects = append(objects, trafficTarget)
+	}
+
+	var documents []string
+	for _, obj := range objects {
+		document, err := marshalObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, string(document))
+	}
+	return []byte(strings.Join(documents, "---\n")), nil
+}
+
+// marshalObject returns the given Kubernetes object as YAML. The object is marshalled to JSON first so that the fields
+// are named after their JSON tags, as expected by the Kubernetes API.
+func marshalObject(obj interface{}) ([]byte, error) {
+	jsonObj, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error marshalling %+v to JSON", obj)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(jsonObj, &fields); err != nil {
+		return nil, errors.Wrapf(err, "Error unmarshalling %s", jsonObj)
+	}
+	// The generated policies are not created yet
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+
+	yamlObj, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error marshalling %s to YAML", jsonObj)
+	}
+	return yamlObj, nil
+}
@@ -0,0 +1,120 @@
+package policyrecorder
+
+import (
+	"strings"
+	"testing"
+
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+var observedRequests = []envoy.ObservedRequest{
+	{
+		SourceIdentity:      "bookbuyer.bookbuyer.cluster.local",
+		DestinationIdentity: "bookstore.bookstore.cluster.local",
+		Method:              "GET",
+		Path:                "/books-bought",
+		Count:               10,
+	},
+	{
+		SourceIdentity:      "bookthief.bookthief.cluster.local",
+		DestinationIdentity: "bookstore.bookstore.cluster.local",
+		Method:              "GET",
+		Path:                "/books-bought",
+		Count:               2,
+	},
+	{
+		SourceIdentity:      "bookbuyer.bookbuyer.cluster.local",
+		DestinationIdentity: "bookstore.bookstore.cluster.local",
+		Method:              "POST",
+		Path:                "/buy-a-book/new",
+		Count:               5,
+	},
+	{
+		SourceIdentity:      "bookbuyer.bookbuyer.cluster.local",
+		DestinationIdentity: "bookstore.bookstore.cluster.local",
+		Method:              "GET",
+		Path:                "/books_bought",
+		Count:               1,
+	},
+	{
+		// Ignored, the source is not a Kubernetes service account
+		SourceIdentity:      "ingress",
+		DestinationIdentity: "bookstore.bookstore.cluster.local",
+		Method:              "GET",
+		Path:                "/",
+		Count:               1,
+	},
+}
+
+func TestGeneratePolicies(t *testing.T) {
+	assert := tassert.New(t)
+
+	policies := GeneratePolicies(observedRequests)
+
+	assert.Equal([]*spec.HTTPRouteGroup{
+		{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "specs.smi-spec.io/v1alpha4",
+				Kind:       "HTTPRouteGroup",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bookstore-recorded-routes",
+				Namespace: "bookstore",
+			},
+			Spec: spec.HTTPRouteGroupSpec{
+				Matches: []spec.HTTPMatch{
+					{Name: "get-books-bought", PathRegex: "/books-bought", Methods: []string{"GET"}},
+					{Name: "get-books-bought-2", PathRegex: "/books_bought", Methods: []string{"GET"}},
+					{Name: "post-buy-a-book-new", PathRegex: "/buy-a-book/new", Methods: []string{"POST"}},
+				},
+			},
+		},
+	}, policies.HTTPRouteGroups)
+
+	assert.Len(policies.TrafficTargets, 2)
+	assert.Equal("bookbuyer-bookbuyer-access-bookstore", policies.TrafficTargets[0].Name)
+	assert.Equal("bookstore", policies.TrafficTargets[0].Namespace)
+	assert.Equal(access.IdentityBindingSubject{Kind: "ServiceAccount", Name: "bookstore", Namespace: "bookstore"}, policies.TrafficTargets[0].Spec.Destination)
+	assert.Equal([]access.IdentityBindingSubject{{Kind: "ServiceAccount", Name: "bookbuyer", Namespace: "bookbuyer"}}, policies.TrafficTargets[0].Spec.Sources)
+	assert.Equal([]access.TrafficTargetRule{{
+		Kind:    "HTTPRouteGroup",
+		Name:    "bookstore-recorded-routes",
+		Matches: []string{"get-books-bought", "get-books-bought-2", "post-buy-a-book-new"},
+	}}, policies.TrafficTargets[0].Spec.Rules)
+
+	// The bookthief is only allowed the route it was observed sending requests to
+	assert.Equal("bookthief-bookthief-access-bookstore", policies.TrafficTargets[1].Name)
+	assert.Equal([]access.TrafficTargetRule{{
+		Kind:    "HTTPRouteGroup",
+		Name:    "bookstore-recorded-routes",
+		Matches: []string{"get-books-bought"},
+	}}, policies.TrafficTargets[1].Spec.Rules)
+}
+
+func TestGetMatchName(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("get-books-bought", getMatchName(routeKey{method: "GET", path: "/books-bought"}))
+	assert.Equal("post-v1-books-isbn-123", getMatchName(routeKey{method: "POST", path: "/v1/Books/ISBN.123/"}))
+	assert.Equal("get", getMatchName(routeKey{method: "GET", path: "/"}))
+}
+
+func TestMarshalPolicies(t *testing.T) {
+	assert := tassert.New(t)
+
+	policiesYAML, err := MarshalPolicies(GeneratePolicies(observedRequests))
+	assert.Nil(err)
+
+	// A HTTPRouteGroup and two TrafficTargets
+	documents := strings.Split(string(policiesYAML), "---\n")
+	assert.Len(documents, 3)
+	assert.True(strings.HasPrefix(documents[0], "apiVersion: specs.smi-spec.io/v1alpha4\nkind: HTTPRouteGroup\nmetadata:\n  name: bookstore-recorded-routes\n  namespace: bookstore\n"))
+	assert.True(strings.HasPrefix(documents[1], "apiVersion: access.smi-spec.io/v1alpha3\nkind: TrafficTarget\nmetadata:\n  name: bookbuyer-bookbuyer-access-bookstore\n  namespace: bookstore\n"))
+	assert.Contains(documents[0], "pathRegex: /books-bought\n")
+	assert.NotContains(string(policiesYAML), "creationTimestamp")
+}
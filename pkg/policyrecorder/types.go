@@ -0,0 +1,34 @@
+// Package policyrecorder implements the generation of candidate SMI policies from the requests observed by proxies in
+// permissive traffic policy mode, to bootstrap least privilege policies before permissive traffic policy mode is disabled.
+package policyrecorder
+
+import (
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("policy-recorder")
+
+const (
+	// API versions and kinds of the generated policies
+	trafficTargetAPIVersion  = "access.smi-spec.io/v1alpha3"
+	trafficTargetKind        = "TrafficTarget"
+	httpRouteGroupAPIVersion = "specs.smi-spec.io/v1alpha4"
+	httpRouteGroupKind       = "HTTPRouteGroup"
+	serviceAccountKind       = "ServiceAccount"
+
+	// httpRouteGroupNameSuffix is the suffix of the name of the HTTPRouteGroup generated for a destination, named after
+	// the service account of the destination
+	httpRouteGroupNameSuffix = "-recorded-routes"
+)
+
+// Policies are the SMI policies allowing the requests observed by proxies.
+type Policies struct {
+	// HTTPRouteGroups are the routes observed per destination, with a match per method and path
+	HTTPRouteGroups []*spec.HTTPRouteGroup
+
+	// TrafficTargets are the policies allowing each source to access the routes it was observed sending requests to
+	TrafficTargets []*access.TrafficTarget
+}
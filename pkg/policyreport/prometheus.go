@@ -0,0 +1,32 @@
+package policyreport
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/promquery"
+)
+
+// policyRequestsMetric is the metric of the sidecar proxies counting the requests matched by the virtual clusters
+// programmed per SMI policy, tagged with the policy
+const policyRequestsMetric = "envoy_vhost_vcluster_upstream_rq_xx"
+
+// prometheusQuerier is a usageQuerier querying the HTTP API of a Prometheus server.
+type prometheusQuerier struct {
+	client *http.Client
+}
+
+func (p *prometheusQuerier) getRequestCounts(metricsURL string, tag string, window time.Duration) (map[string]float64, error) {
+	query := fmt.Sprintf(`sum by (%s) (increase(%s{%s!=""}[%ds]))`, tag, policyRequestsMetric, tag, int64(window.Seconds()))
+	samples, err := promquery.Query(p.client, metricsURL, query)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]float64)
+	for _, sample := range samples {
+		counts[sample.Labels[tag]] += sample.Value
+	}
+	return counts, nil
+}
@@ -0,0 +1,66 @@
+package policyreport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestGetRequestCounts(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         int
+		body           string
+		expectedCounts map[string]float64
+		expectedErr    bool
+	}{
+		{
+			name:   "request counts per policy",
+			status: http.StatusOK,
+			body: `{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"osm_traffic_target":"default/bookbuyer-access-bookstore"},"value":[1614556800,"42"]},` +
+				`{"metric":{"osm_traffic_target":"default/bookstore-access"},"value":[1614556800,"0"]}]}}`,
+			expectedCounts: map[string]float64{
+				"default/bookbuyer-access-bookstore": 42,
+				"default/bookstore-access":           0,
+			},
+		},
+		{
+			name:        "query error",
+			status:      http.StatusBadRequest,
+			body:        `{"status":"error","errorType":"bad_data","error":"invalid query"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid response",
+			status:      http.StatusBadGateway,
+			body:        `Bad Gateway`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var query string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("/api/v1/query", r.URL.Path)
+				query = r.URL.Query().Get("query")
+				w.WriteHeader(tc.status)
+				_, _ = fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			p := &prometheusQuerier{client: server.Client()}
+			counts, err := p.getRequestCounts(server.URL+"/", "osm_traffic_target", 24*time.Hour)
+			assert.Equal(`sum by (osm_traffic_target) (increase(envoy_vhost_vcluster_upstream_rq_xx{osm_traffic_target!=""}[86400s]))`, query)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedCounts, counts)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package policyreport
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/pkg/errors"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+// NewReporter returns a new Reporter querying the usage of policies from the Prometheus server configured in the OSM ConfigMap.
+func NewReporter(meshSpec smi.MeshSpec, kubeController k8s.Controller, cfg configurator.Configurator) *Reporter {
+	return &Reporter{
+		meshSpec:       meshSpec,
+		kubeController: kubeController,
+		cfg:            cfg,
+		usage: &prometheusQuerier{
+			client: &http.Client{Timeout: metricsQueryTimeout},
+		},
+	}
+}
+
+// GetPolicyReport returns a report of the SMI policies that have not matched any traffic over the unused policy window,
+// and of the SMI policies referencing resources that do not exist.
+// Policies created less than a window ago are not reported as unused, as their usage is not known over the whole window.
+func (r *Reporter) GetPolicyReport() *Report {
+	window := r.cfg.GetUnusedPolicyWindow()
+	report := &Report{
+		GeneratedAt:       time.Now(),
+		Window:            window.String(),
+		UnusedPolicies:    []Policy{},
+		InvalidReferences: []InvalidReference{},
+	}
+
+	trafficTargets := r.meshSpec.ListTrafficTargets()
+	trafficSplits := r.meshSpec.ListTrafficSplits()
+
+	for _, t := range trafficTargets {
+		report.InvalidReferences = append(report.InvalidReferences, r.getTrafficTargetInvalidReferences(t)...)
+	}
+	for _, s := range trafficSplits {
+		report.InvalidReferences = append(report.InvalidReferences, r.getTrafficSplitInvalidReferences(s)...)
+	}
+
+	unusedPolicies, err := r.getUnusedPolicies(trafficTargets, trafficSplits, report.GeneratedAt.Add(-window), window)
+	if err != nil {
+		log.Error().Err(err).Msg("Error querying the usage of SMI policies")
+		report.UsageError = err.Error()
+	} else {
+		report.UnusedPolicies = unusedPolicies
+	}
+
+	sort.Slice(report.UnusedPolicies, func(i, j int) bool {
+		return lessPolicy(report.UnusedPolicies[i], report.UnusedPolicies[j])
+	})
+	sort.SliceStable(report.InvalidReferences, func(i, j int) bool {
+		return lessPolicy(report.InvalidReferences[i].Policy, report.InvalidReferences[j].Policy)
+	})
+
+	return report
+}
+
+// getUnusedPolicies returns the given policies created before the given time that have not matched any traffic over the given window
+func (r *Reporter) getUnusedPolicies(trafficTargets []*access.TrafficTarget, trafficSplits []*split.TrafficSplit, createdBefore time.Time, window time.Duration) ([]Policy, error) {
+	metricsURL := r.cfg.GetPolicyUsageMetricsURL()
+	if metricsURL == "" {
+		return nil, errors.New("the policy usage metrics URL is not configured")
+	}
+
+	unused := []Policy{}
+
+	// Requests are not attributed to TrafficTargets in permissive mode, where they are not allowed by TrafficTargets
+	if !r.cfg.IsPermissiveTrafficPolicyMode() {
+		usedTrafficTargets, err := r.getUsedPolicies(metricsURL, constants.TrafficTargetStatsTag, window)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range trafficTargets {
+			// Only the requests matching HTTP routes are attributed to TrafficTargets
+			if !hasHTTPRouteRule(t) || !t.CreationTimestamp.Time.Before(createdBefore) {
+				continue
+			}
+			if !usedTrafficTargets.Contains(getPolicyStatsName(t.Namespace, t.Name)) {
+				unused = append(unused, Policy{Kind: trafficTargetKind, Namespace: t.Namespace, Name: t.Name})
+			}
+		}
+	}
+
+	usedTrafficSplits, err := r.getUsedPolicies(metricsURL, constants.TrafficSplitStatsTag, window)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range trafficSplits {
+		if !s.CreationTimestamp.Time.Before(createdBefore) {
+			continue
+		}
+		if !usedTrafficSplits.Contains(getPolicyStatsName(s.Namespace, s.Name)) {
+			unused = append(unused, Policy{Kind: trafficSplitKind, Namespace: s.Namespace, Name: s.Name})
+		}
+	}
+
+	return unused, nil
+}
+
+// getUsedPolicies returns the stats names of the policies that matched requests over the given window
+func (r *Reporter) getUsedPolicies(metricsURL string, tag string, window time.Duration) (mapset.Set, error) {
+	counts, err := r.usage.getRequestCounts(metricsURL, tag, window)
+	if err != nil {
+		return nil, err
+	}
+
+	used := mapset.NewSet()
+	for tagValue, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		// Requests allowed by several TrafficTargets are attributed to all of them
+		for _, policy := range strings.Split(tagValue, ",") {
+			used.Add(policy)
+		}
+	}
+	return used, nil
+}
+
+func (r *Reporter) getTrafficTargetInvalidReferences(t *access.TrafficTarget) []InvalidReference {
+	policy := Policy{Kind: trafficTargetKind, Namespace: t.Namespace, Name: t.Name}
+	var invalid []InvalidReference
+
+	identities := append([]access.IdentityBindingSubject{t.Spec.Destination}, t.Spec.Sources...)
+	for _, identity := range identities {
+		if identity.Kind != serviceAccountKind {
+			continue
+		}
+		if reason := r.checkServiceAccount(identity.Namespace, identity.Name); reason != "" {
+			invalid = append(invalid, newInvalidReference(policy, serviceAccountKind, identity.Namespace, identity.Name, reason))
+		}
+	}
+
+	for _, rule := range t.Spec.Rules {
+		switch rule.Kind {
+		case httpRouteGroupKind:
+			invalid = append(invalid, r.checkHTTPRouteGroup(policy, rule)...)
+
+		case tcpRouteKind:
+			if !r.tcpRouteExists(t.Namespace, rule.Name) {
+				invalid = append(invalid, newInvalidReference(policy, tcpRouteKind, t.Namespace, rule.Name, reasonNotFound))
+			}
+
+		default:
+			invalid = append(invalid, newInvalidReference(policy, rule.Kind, t.Namespace, rule.Name, reasonUnsupportedRuleKind))
+		}
+	}
+
+	return invalid
+}
+
+func (r *Reporter) getTrafficSplitInvalidReferences(s *split.TrafficSplit) []InvalidReference {
+	policy := Policy{Kind: trafficSplitKind, Namespace: s.Namespace, Name: s.Name}
+	var invalid []InvalidReference
+
+	services := []string{k8s.GetServiceFromHostname(s.Spec.Service)}
+	for _, backend := range s.Spec.Backends {
+		services = append(services, backend.Service)
+	}
+	for _, svc := range services {
+		if reason := r.checkService(s.Namespace, svc); reason != "" {
+			invalid = append(invalid, newInvalidReference(policy, serviceKind, s.Namespace, svc, reason))
+		}
+	}
+
+	return invalid
+}
+
+// checkServiceAccount returns the reason the given service account cannot be referenced, or an empty string if it exists in the mesh
+func (r *Reporter) checkServiceAccount(namespace, name string) string {
+	if !r.kubeController.IsMonitoredNamespace(namespace) {
+		return reasonNamespaceNotInMesh
+	}
+	for _, sa := range r.kubeController.ListServiceAccounts() {
+		if sa.Namespace == namespace && sa.Name == name {
+			return ""
+		}
+	}
+	return reasonNotFound
+}
+
+// checkService returns the reason the given service cannot be referenced, or an empty string if it exists in the mesh
+func (r *Reporter) checkService(namespace, name string) string {
+	if !r.kubeController.IsMonitoredNamespace(namespace) {
+		return reasonNamespaceNotInMesh
+	}
+	if r.kubeController.GetService(service.MeshService{Namespace: namespace, Name: name}) == nil {
+		return reasonNotFound
+	}
+	return ""
+}
+
+// checkHTTPRouteGroup returns the invalid references of the given TrafficTarget rule to an HTTPRouteGroup and its matches
+func (r *Reporter) checkHTTPRouteGroup(policy Policy, rule access.TrafficTargetRule) []InvalidReference {
+	for _, routeGroup := range r.meshSpec.ListHTTPTrafficSpecs() {
+		if routeGroup.Namespace != policy.Namespace || routeGroup.Name != rule.Name {
+			continue
+		}
+
+		matches := mapset.NewSet()
+		for _, match := range routeGroup.Spec.Matches {
+			matches.Add(match.Name)
+		}
+
+		var invalid []InvalidReference
+		for _, match := range rule.Matches {
+			if !matches.Contains(match) {
+				invalid = append(invalid, newInvalidReference(policy, httpRouteGroupKind, policy.Namespace, fmt.Sprintf("%s#%s", rule.Name, match), reasonRouteMatchNotFound))
+			}
+		}
+		return invalid
+	}
+
+	return []InvalidReference{newInvalidReference(policy, httpRouteGroupKind, policy.Namespace, rule.Name, reasonNotFound)}
+}
+
+func (r *Reporter) tcpRouteExists(namespace, name string) bool {
+	for _, tcpRoute := range r.meshSpec.ListTCPTrafficSpecs() {
+		if tcpRoute.Namespace == namespace && tcpRoute.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newInvalidReference(policy Policy, kind, namespace, name, reason string) InvalidReference {
+	return InvalidReference{
+		Policy:        policy,
+		ReferenceKind: kind,
+		Reference:     fmt.Sprintf("%s/%s", namespace, name),
+		Reason:        reason,
+	}
+}
+
+// hasHTTPRouteRule returns true if the given TrafficTarget allows HTTP routes
+func hasHTTPRouteRule(t *access.TrafficTarget) bool {
+	for _, rule := range t.Spec.Rules {
+		if rule.Kind == httpRouteGroupKind {
+			return true
+		}
+	}
+	return false
+}
+
+// getPolicyStatsName returns the name of the given policy in the stats tags of the sidecar proxies, where dots are
+// replaced because Envoy splits stat names on dots.
+func getPolicyStatsName(namespace, name string) string {
+	return strings.ReplaceAll(fmt.Sprintf("%s/%s", namespace, name), ".", "_")
+}
+
+func lessPolicy(a, b Policy) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
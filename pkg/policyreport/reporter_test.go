@@ -0,0 +1,220 @@
+package policyreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+const testMetricsURL = "http://osm-prometheus.osm-system.svc:7070"
+
+// fakeUsage is a usageQuerier returning request counts from memory
+type fakeUsage struct {
+	counts map[string]map[string]float64
+	err    error
+}
+
+func (f *fakeUsage) getRequestCounts(metricsURL string, tag string, window time.Duration) (map[string]float64, error) {
+	return f.counts[tag], f.err
+}
+
+func TestGetPolicyReport(t *testing.T) {
+	window := 24 * time.Hour
+	old := metav1.NewTime(time.Now().Add(-2 * window))
+	recent := metav1.NewTime(time.Now().Add(-window / 2))
+
+	newTrafficTarget := func(name string, created metav1.Time, destination string, ruleKind string, ruleName string, matches ...string) *access.TrafficTarget {
+		return &access.TrafficTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: created},
+			Spec: access.TrafficTargetSpec{
+				Destination: access.IdentityBindingSubject{Kind: "ServiceAccount", Name: destination, Namespace: "default"},
+				Sources:     []access.IdentityBindingSubject{{Kind: "ServiceAccount", Name: "bookbuyer", Namespace: "default"}},
+				Rules:       []access.TrafficTargetRule{{Kind: ruleKind, Name: ruleName, Matches: matches}},
+			},
+		}
+	}
+	newTrafficSplit := func(name string, created metav1.Time, apex string, backends ...string) *split.TrafficSplit {
+		s := &split.TrafficSplit{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: created},
+			Spec:       split.TrafficSplitSpec{Service: apex},
+		}
+		for _, backend := range backends {
+			s.Spec.Backends = append(s.Spec.Backends, split.TrafficSplitBackend{Service: backend, Weight: 50})
+		}
+		return s
+	}
+
+	trafficTargets := []*access.TrafficTarget{
+		newTrafficTarget("bookstore-used.v1", old, "bookstore", "HTTPRouteGroup", "routes", "buy"),
+		newTrafficTarget("bookstore-unused", old, "bookstore", "HTTPRouteGroup", "routes", "buy"),
+		newTrafficTarget("bookstore-recent", recent, "bookstore", "HTTPRouteGroup", "routes", "buy"),
+		newTrafficTarget("bookstore-tcp", old, "bookstore", "TCPRoute", "tcp-route"),
+		newTrafficTarget("bookstore-invalid", recent, "bookstore-deleted", "HTTPRouteGroup", "routes", "sell"),
+		newTrafficTarget("bookstore-no-routes", recent, "bookstore", "HTTPRouteGroup", "routes-deleted"),
+	}
+	trafficSplits := []*split.TrafficSplit{
+		newTrafficSplit("bookstore-split", old, "bookstore-apex.default.svc.cluster.local", "bookstore-v1", "bookstore-v2"),
+		newTrafficSplit("bookstore-split-unused", old, "bookstore-apex", "bookstore-v1", "bookstore-v3"),
+	}
+	routeGroups := []*spec.HTTPRouteGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "routes", Namespace: "default"},
+			Spec:       spec.HTTPRouteGroupSpec{Matches: []spec.HTTPMatch{{Name: "buy"}}},
+		},
+	}
+	tcpRoutes := []*spec.TCPRoute{
+		{ObjectMeta: metav1.ObjectMeta{Name: "tcp-route", Namespace: "default"}},
+	}
+	serviceAccounts := []*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer", Namespace: "default"}},
+	}
+	services := map[string]bool{"bookstore-apex": true, "bookstore-v1": true, "bookstore-v2": true}
+
+	usage := &fakeUsage{
+		counts: map[string]map[string]float64{
+			constants.TrafficTargetStatsTag: {
+				"default/bookstore-used_v1,default/other": 10,
+				"default/bookstore-unused":                0,
+			},
+			constants.TrafficSplitStatsTag: {
+				"default/bookstore-split": 5,
+			},
+		},
+	}
+
+	expectedInvalidReferences := []InvalidReference{
+		{
+			Policy:        Policy{Kind: "TrafficSplit", Namespace: "default", Name: "bookstore-split-unused"},
+			ReferenceKind: "Service",
+			Reference:     "default/bookstore-v3",
+			Reason:        reasonNotFound,
+		},
+		{
+			Policy:        Policy{Kind: "TrafficTarget", Namespace: "default", Name: "bookstore-invalid"},
+			ReferenceKind: "ServiceAccount",
+			Reference:     "default/bookstore-deleted",
+			Reason:        reasonNotFound,
+		},
+		{
+			Policy:        Policy{Kind: "TrafficTarget", Namespace: "default", Name: "bookstore-invalid"},
+			ReferenceKind: "HTTPRouteGroup",
+			Reference:     "default/routes#sell",
+			Reason:        reasonRouteMatchNotFound,
+		},
+		{
+			Policy:        Policy{Kind: "TrafficTarget", Namespace: "default", Name: "bookstore-no-routes"},
+			ReferenceKind: "HTTPRouteGroup",
+			Reference:     "default/routes-deleted",
+			Reason:        reasonNotFound,
+		},
+	}
+
+	testCases := []struct {
+		name                   string
+		metricsURL             string
+		permissiveMode         bool
+		usageErr               error
+		expectedUnusedPolicies []Policy
+		expectedUsageError     bool
+	}{
+		{
+			name:       "unused policies reported",
+			metricsURL: testMetricsURL,
+			expectedUnusedPolicies: []Policy{
+				{Kind: "TrafficSplit", Namespace: "default", Name: "bookstore-split-unused"},
+				{Kind: "TrafficTarget", Namespace: "default", Name: "bookstore-unused"},
+			},
+		},
+		{
+			name:           "TrafficTargets not reported as unused in permissive mode",
+			metricsURL:     testMetricsURL,
+			permissiveMode: true,
+			expectedUnusedPolicies: []Policy{
+				{Kind: "TrafficSplit", Namespace: "default", Name: "bookstore-split-unused"},
+			},
+		},
+		{
+			name:                   "usage unknown without metrics URL",
+			metricsURL:             "",
+			expectedUnusedPolicies: []Policy{},
+			expectedUsageError:     true,
+		},
+		{
+			name:                   "usage unknown on query error",
+			metricsURL:             testMetricsURL,
+			usageErr:               errors.New("connection refused"),
+			expectedUnusedPolicies: []Policy{},
+			expectedUsageError:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+			mockMeshSpec.EXPECT().ListTrafficTargets().Return(trafficTargets).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficSplits().Return(trafficSplits).AnyTimes()
+			mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return(routeGroups).AnyTimes()
+			mockMeshSpec.EXPECT().ListTCPTrafficSpecs().Return(tcpRoutes).AnyTimes()
+			mockKubeController.EXPECT().IsMonitoredNamespace("default").Return(true).AnyTimes()
+			mockKubeController.EXPECT().ListServiceAccounts().Return(serviceAccounts).AnyTimes()
+			mockKubeController.EXPECT().GetService(gomock.Any()).DoAndReturn(func(svc service.MeshService) *corev1.Service {
+				if !services[svc.Name] {
+					return nil
+				}
+				return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: svc.Name, Namespace: svc.Namespace}}
+			}).AnyTimes()
+			mockConfigurator.EXPECT().GetUnusedPolicyWindow().Return(window).AnyTimes()
+			mockConfigurator.EXPECT().GetPolicyUsageMetricsURL().Return(tc.metricsURL).AnyTimes()
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
+
+			usage.err = tc.usageErr
+			r := &Reporter{
+				meshSpec:       mockMeshSpec,
+				kubeController: mockKubeController,
+				cfg:            mockConfigurator,
+				usage:          usage,
+			}
+
+			report := r.GetPolicyReport()
+			assert.Equal("24h0m0s", report.Window)
+			assert.Equal(tc.expectedUnusedPolicies, report.UnusedPolicies)
+			assert.Equal(tc.expectedUsageError, report.UsageError != "")
+			assert.Equal(expectedInvalidReferences, report.InvalidReferences)
+		})
+	}
+}
+
+func TestCheckReferencesInUnmonitoredNamespace(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("other").Return(false).AnyTimes()
+
+	r := &Reporter{kubeController: mockKubeController}
+	assert.Equal(reasonNamespaceNotInMesh, r.checkServiceAccount("other", "bookbuyer"))
+	assert.Equal(reasonNamespaceNotInMesh, r.checkService("other", "bookstore"))
+}
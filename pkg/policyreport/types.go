@@ -0,0 +1,87 @@
+// Package policyreport implements the report of the SMI policies that can be cleaned up: policies that have not matched
+// any traffic over a configurable window, and policies referencing services, service accounts or routes that do not exist.
+package policyreport
+
+import (
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+var log = logger.New("policy-report")
+
+const (
+	// metricsQueryTimeout is the timeout of a query to the Prometheus server for the usage of policies
+	metricsQueryTimeout = 10 * time.Second
+
+	// Kinds of the policies and resources in the report
+	trafficTargetKind  = "TrafficTarget"
+	trafficSplitKind   = "TrafficSplit"
+	httpRouteGroupKind = "HTTPRouteGroup"
+	tcpRouteKind       = "TCPRoute"
+	serviceAccountKind = "ServiceAccount"
+	serviceKind        = "Service"
+
+	// Reasons for a reference to be reported as invalid
+	reasonNotFound            = "not found"
+	reasonNamespaceNotInMesh  = "namespace not monitored by the mesh"
+	reasonRouteMatchNotFound  = "match not found in route group"
+	reasonUnsupportedRuleKind = "unsupported rule kind"
+)
+
+// Reporter generates reports of the SMI policies that can be cleaned up.
+type Reporter struct {
+	meshSpec       smi.MeshSpec
+	kubeController k8s.Controller
+	cfg            configurator.Configurator
+	usage          usageQuerier
+}
+
+// Report is a report of the SMI policies that can be cleaned up.
+type Report struct {
+	// GeneratedAt is the time the report was generated
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Window is the window over which the usage of the policies was queried
+	Window string `json:"window"`
+
+	// UsageError is the error querying the usage of the policies, in which case unused policies are not reported
+	UsageError string `json:"usage_error,omitempty"`
+
+	// UnusedPolicies are the policies that have not matched any traffic over the window
+	UnusedPolicies []Policy `json:"unused_policies"`
+
+	// InvalidReferences are the references of policies to resources that do not exist
+	InvalidReferences []InvalidReference `json:"invalid_references"`
+}
+
+// Policy identifies an SMI policy.
+type Policy struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// InvalidReference is a reference of an SMI policy to a resource that does not exist.
+type InvalidReference struct {
+	Policy
+
+	// ReferenceKind is the kind of the referenced resource
+	ReferenceKind string `json:"reference_kind"`
+
+	// Reference is the namespaced name of the referenced resource
+	Reference string `json:"reference"`
+
+	// Reason is the reason the reference is invalid
+	Reason string `json:"reason"`
+}
+
+// usageQuerier is the interface of the client used to query the number of requests matched by policies.
+type usageQuerier interface {
+	// getRequestCounts returns the number of requests counted over the given window for the stats tagged with the given
+	// tag, keyed by the value of the tag
+	getRequestCounts(metricsURL string, tag string, window time.Duration) (map[string]float64, error)
+}
@@ -0,0 +1,78 @@
+// Package promquery runs instant queries against the HTTP API of a Prometheus server, for the components of the
+// control plane reading the metrics of the sidecar proxies.
+package promquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("promquery")
+
+// Sample is a sample of the instant vector returned by a query
+type Sample struct {
+	// Labels are the labels of the series of the sample
+	Labels map[string]string
+
+	// Value is the value of the sample
+	Value float64
+}
+
+// response is the subset of the response of a Prometheus instant query used to read the samples
+type response struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query returns the samples of the given instant query to the Prometheus server at the given URL, sent with the given
+// client. The samples whose value cannot be parsed are skipped.
+func Query(client *http.Client, metricsURL, query string) ([]Sample, error) {
+	queryURL := fmt.Sprintf("%s/api/v1/query?%s", strings.TrimSuffix(metricsURL, "/"), url.Values{"query": []string{query}}.Encode())
+
+	resp, err := client.Get(queryURL)
+	if err != nil {
+		return nil, errors.Errorf("Error querying %s: %s", metricsURL, err)
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	var promResp response
+	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
+		return nil, errors.Errorf("Error decoding response of %s with status %d: %s", metricsURL, resp.StatusCode, err)
+	}
+	if promResp.Status != "success" {
+		return nil, errors.Errorf("Query to %s failed with status %d: %s", metricsURL, resp.StatusCode, promResp.Error)
+	}
+
+	var samples []Sample
+	for _, result := range promResp.Data.Result {
+		// The value of an instant vector sample is a [<timestamp>, "<value>"] pair
+		if len(result.Value) != 2 {
+			continue
+		}
+		valueStr, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error parsing value %q of series %v of query %s", valueStr, result.Metric, query)
+			continue
+		}
+		samples = append(samples, Sample{Labels: result.Metric, Value: value})
+	}
+	return samples, nil
+}
@@ -0,0 +1,83 @@
+package promquery
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	testCases := []struct {
+		name            string
+		status          int
+		body            string
+		expectedSamples []Sample
+		expectedErr     bool
+	}{
+		{
+			name:   "samples",
+			status: http.StatusOK,
+			body: `{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-local"},"value":[1614556800,"42"]},` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-v2-local"},"value":[1614556800,"0.5"]}]}}`,
+			expectedSamples: []Sample{
+				{Labels: map[string]string{"envoy_cluster_name": "bookstore/bookstore-local"}, Value: 42},
+				{Labels: map[string]string{"envoy_cluster_name": "bookstore/bookstore-v2-local"}, Value: 0.5},
+			},
+		},
+		{
+			name:   "invalid samples are skipped",
+			status: http.StatusOK,
+			body: `{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-local"},"value":[1614556800,"invalid"]},` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-v2-local"},"value":[1614556800]},` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-v3-local"},"value":[1614556800,3]},` +
+				`{"metric":{"envoy_cluster_name":"bookstore/bookstore-v4-local"},"value":[1614556800,"4"]}]}}`,
+			expectedSamples: []Sample{
+				{Labels: map[string]string{"envoy_cluster_name": "bookstore/bookstore-v4-local"}, Value: 4},
+			},
+		},
+		{
+			name:        "query error",
+			status:      http.StatusBadRequest,
+			body:        `{"status":"error","errorType":"bad_data","error":"invalid query"}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid response",
+			status:      http.StatusBadGateway,
+			body:        `Bad Gateway`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var query string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("/api/v1/query", r.URL.Path)
+				query = r.URL.Query().Get("query")
+				w.WriteHeader(tc.status)
+				_, _ = fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			samples, err := Query(server.Client(), server.URL+"/", `sum by (envoy_cluster_name) (envoy_cluster_upstream_rq_active)`)
+			assert.Equal(`sum by (envoy_cluster_name) (envoy_cluster_upstream_rq_active)`, query)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedSamples, samples)
+		})
+	}
+}
+
+func TestQueryUnreachable(t *testing.T) {
+	assert := tassert.New(t)
+
+	_, err := Query(http.DefaultClient, "http://127.0.0.1:0", "up")
+	assert.NotNil(err)
+}
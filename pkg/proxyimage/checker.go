@@ -0,0 +1,100 @@
+package proxyimage
+
+import (
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
+	"github.com/openservicemesh/osm/pkg/injector"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+// NewChecker creates a new checker of the sidecar images of the proxies. The sidecar images are the images the
+// injector is configured with.
+func NewChecker(kubeController k8s.Controller, cfg configurator.Configurator, sidecarImage string, sidecarImageByArch map[string]string) *Checker {
+	return &Checker{
+		kubeController:     kubeController,
+		cfg:                cfg,
+		sidecarImage:       sidecarImage,
+		sidecarImageByArch: sidecarImageByArch,
+	}
+}
+
+// Check returns the sidecar image status of the proxy with the given xDS certificate common name. An error is returned
+// when the proxy does not run in a pod with a sidecar, as node proxies and development proxies.
+func (c *Checker) Check(cn certificate.CommonName) (*Status, error) {
+	if c.sidecarImage == "" {
+		return nil, errors.New("The sidecar image is not configured")
+	}
+
+	pod, err := catalog.GetPodFromCertificate(cn, c.kubeController)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := getSidecarContainerImage(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedImage := injector.GetSidecarImage(pod, c.sidecarImage, c.sidecarImageByArch)
+	return &Status{
+		Image:         image,
+		ExpectedImage: expectedImage,
+		Outdated:      !isSameImage(image, expectedImage, c.getVerificationPolicy()),
+	}, nil
+}
+
+func (c *Checker) getVerificationPolicy() imageverifier.Policy {
+	return imageverifier.Policy{
+		Digest:          c.cfg.GetSidecarImageDigest(),
+		CosignPublicKey: c.cfg.GetSidecarImageCosignPublicKey(),
+	}
+}
+
+func getSidecarContainerImage(pod *corev1.Pod) (string, error) {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == constants.EnvoyContainerName {
+			return container.Image, nil
+		}
+	}
+	return "", errors.Errorf("Pod %s/%s has no %s container", pod.Namespace, pod.Name, constants.EnvoyContainerName)
+}
+
+// isSameImage returns whether the sidecar image of a pod is the expected image. When the image verification of the
+// injector is enabled, the injected images are pinned to the verified digest instead of the tag of the expected image:
+// such an image is the expected image if it is from the same repository and pinned to the digest of the policy, or to
+// any digest when the policy only verifies signatures, as the digest the tag points to is not resolved.
+func isSameImage(image string, expectedImage string, policy imageverifier.Policy) bool {
+	if image == expectedImage {
+		return true
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing sidecar image %s", image)
+		return false
+	}
+	expectedNamed, err := reference.ParseNormalizedNamed(expectedImage)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing expected sidecar image %s", expectedImage)
+		return false
+	}
+	if named.String() == expectedNamed.String() {
+		return true
+	}
+	if named.Name() != expectedNamed.Name() {
+		return false
+	}
+
+	digested, ok := named.(reference.Digested)
+	if !ok || !policy.IsEnabled() {
+		return false
+	}
+	return policy.Digest == "" || digested.Digest().String() == policy.Digest
+}
@@ -0,0 +1,141 @@
+package proxyimage
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/imageverifier"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+const (
+	sidecarImage = "envoyproxy/envoy-alpine:v1.17.2"
+	testDigest   = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestCheck(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetSidecarImageDigest().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetSidecarImageCosignPublicKey().Return("").AnyTimes()
+
+	newPod := func(name string, proxyUUID uuid.UUID, image string) *corev1.Pod {
+		pod := tests.NewPodFixture(tests.Namespace, name, tests.BookstoreServiceAccountName, map[string]string{
+			constants.EnvoyUniqueIDLabelName: proxyUUID.String(),
+		})
+		pod.Spec.Containers = []corev1.Container{
+			{Name: "bookstore", Image: "bookstore:v1"},
+			{Name: constants.EnvoyContainerName, Image: image},
+		}
+		return &pod
+	}
+	currentUUID, outdatedUUID, unknownUUID := uuid.New(), uuid.New(), uuid.New()
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{
+		newPod("current", currentUUID, "docker.io/envoyproxy/envoy-alpine:v1.17.2"),
+		newPod("outdated", outdatedUUID, "envoyproxy/envoy-alpine:v1.16.0"),
+	}).AnyTimes()
+
+	c := NewChecker(mockKubeController, mockConfigurator, sidecarImage, nil)
+
+	status, err := c.Check(catalog.NewCertCommonNameWithProxyID(currentUUID, tests.BookstoreServiceAccountName, tests.Namespace))
+	assert.Nil(err)
+	assert.Equal(&Status{Image: "docker.io/envoyproxy/envoy-alpine:v1.17.2", ExpectedImage: sidecarImage, Outdated: false}, status)
+
+	status, err = c.Check(catalog.NewCertCommonNameWithProxyID(outdatedUUID, tests.BookstoreServiceAccountName, tests.Namespace))
+	assert.Nil(err)
+	assert.Equal(&Status{Image: "envoyproxy/envoy-alpine:v1.16.0", ExpectedImage: sidecarImage, Outdated: true}, status)
+
+	_, err = c.Check(catalog.NewCertCommonNameWithProxyID(unknownUUID, tests.BookstoreServiceAccountName, tests.Namespace))
+	assert.NotNil(err)
+
+	// Nothing can be reported without the sidecar image
+	_, err = NewChecker(mockKubeController, mockConfigurator, "", nil).Check(catalog.NewCertCommonNameWithProxyID(currentUUID, tests.BookstoreServiceAccountName, tests.Namespace))
+	assert.NotNil(err)
+}
+
+func TestIsSameImage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		image         string
+		expectedImage string
+		policy        imageverifier.Policy
+		expected      bool
+	}{
+		{
+			name:          "same image",
+			image:         sidecarImage,
+			expectedImage: sidecarImage,
+			expected:      true,
+		},
+		{
+			name:          "same image with a normalized name",
+			image:         "docker.io/" + sidecarImage,
+			expectedImage: sidecarImage,
+			expected:      true,
+		},
+		{
+			name:          "other tag",
+			image:         "envoyproxy/envoy-alpine:v1.16.0",
+			expectedImage: sidecarImage,
+			expected:      false,
+		},
+		{
+			name:          "other repository",
+			image:         "envoyproxy/envoy:v1.17.2",
+			expectedImage: sidecarImage,
+			expected:      false,
+		},
+		{
+			name:          "image pinned to a digest without verification",
+			image:         "envoyproxy/envoy-alpine@" + testDigest,
+			expectedImage: sidecarImage,
+			expected:      false,
+		},
+		{
+			name:          "image pinned to the digest of the verification policy",
+			image:         "envoyproxy/envoy-alpine@" + testDigest,
+			expectedImage: sidecarImage,
+			policy:        imageverifier.Policy{Digest: testDigest},
+			expected:      true,
+		},
+		{
+			name:          "image pinned to another digest than the verification policy",
+			image:         "envoyproxy/envoy-alpine@sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			expectedImage: sidecarImage,
+			policy:        imageverifier.Policy{Digest: testDigest},
+			expected:      false,
+		},
+		{
+			name:          "image pinned to a digest with signature verification",
+			image:         "envoyproxy/envoy-alpine@" + testDigest,
+			expectedImage: sidecarImage,
+			policy:        imageverifier.Policy{CosignPublicKey: "-key-"},
+			expected:      true,
+		},
+		{
+			name:          "invalid image",
+			image:         "Invalid Image",
+			expectedImage: sidecarImage,
+			expected:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, isSameImage(tc.image, tc.expectedImage, tc.policy))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package proxyimage detects the proxies running a sidecar image other than the image the injector currently injects,
+// such as after an upgrade of the sidecar image. Sidecars are not upgraded in place: the pods of the workloads running
+// outdated proxies must be restarted to run the current image.
+package proxyimage
+
+import (
+	"github.com/openservicemesh/osm/pkg/configurator"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("proxy-image")
+
+// Checker compares the sidecar images of the proxies with the image the injector currently injects
+type Checker struct {
+	kubeController     k8s.Controller
+	cfg                configurator.Configurator
+	sidecarImage       string
+	sidecarImageByArch map[string]string
+}
+
+// Status is the sidecar image status of a proxy
+type Status struct {
+	// Image is the sidecar image the proxy runs
+	Image string
+
+	// ExpectedImage is the sidecar image the injector currently injects in the pod of the proxy
+	ExpectedImage string
+
+	// Outdated is whether the proxy runs an image other than the expected image
+	Outdated bool
+}
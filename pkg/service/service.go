@@ -38,3 +38,47 @@ func UnmarshalMeshService(str string) (*MeshService, error) {
 		Name:      slices[1],
 	}, nil
 }
+
+// DedupMeshServices returns the given list of services without duplicates, preserving the order
+// in which the services first appear. Multiple services can select the same workload, so lists of
+// services built by walking workloads or providers may contain the same service more than once.
+func DedupMeshServices(services []MeshService) []MeshService {
+	var deduped []MeshService
+	seen := make(map[MeshService]struct{}, len(services))
+	for _, svc := range services {
+		if _, ok := seen[svc]; ok {
+			continue
+		}
+		seen[svc] = struct{}{}
+		deduped = append(deduped, svc)
+	}
+	return deduped
+}
+
+// WithoutPort returns the service the given MeshService belongs to, without the port fields set
+func (ms MeshService) WithoutPort() MeshService {
+	return MeshService{
+		Namespace: ms.Namespace,
+		Name:      ms.Name,
+	}
+}
+
+// DedupTargetPorts returns the given list of service ports keeping a single port per target port, preserving the order
+// in which the target ports first appear. Multiple ports of a service can forward traffic to the same target port, while
+// the inbound traffic of a proxy must only be configured once per target port. Ports whose target port could not be
+// resolved are dropped.
+func DedupTargetPorts(ports []MeshService) []MeshService {
+	var deduped []MeshService
+	seen := make(map[uint32]struct{}, len(ports))
+	for _, port := range ports {
+		if port.TargetPort == 0 {
+			continue
+		}
+		if _, ok := seen[port.TargetPort]; ok {
+			continue
+		}
+		seen[port.TargetPort] = struct{}{}
+		deduped = append(deduped, port)
+	}
+	return deduped
+}
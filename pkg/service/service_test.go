@@ -163,3 +163,89 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestDedupMeshServices(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc1 := MeshService{Namespace: "default", Name: "bookstore"}
+	svc2 := MeshService{Namespace: "default", Name: "bookstore-v1"}
+	svc3 := MeshService{Namespace: "other", Name: "bookstore"}
+
+	testCases := []struct {
+		name     string
+		services []MeshService
+		expected []MeshService
+	}{
+		{
+			name:     "no services",
+			services: nil,
+			expected: nil,
+		},
+		{
+			name:     "no duplicates",
+			services: []MeshService{svc1, svc2, svc3},
+			expected: []MeshService{svc1, svc2, svc3},
+		},
+		{
+			name:     "duplicates are removed and order is preserved",
+			services: []MeshService{svc2, svc1, svc2, svc3, svc1},
+			expected: []MeshService{svc2, svc1, svc3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, DedupMeshServices(tc.services))
+		})
+	}
+}
+
+func TestWithoutPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	svcPort := MeshService{Namespace: "default", Name: "bookstore", Port: 80, TargetPort: 8080, Protocol: "http"}
+	assert.Equal(MeshService{Namespace: "default", Name: "bookstore"}, svcPort.WithoutPort())
+	assert.Equal(svcPort.String(), svcPort.WithoutPort().String())
+}
+
+func TestDedupTargetPorts(t *testing.T) {
+	assert := tassert.New(t)
+
+	http := MeshService{Namespace: "default", Name: "bookstore", Port: 80, TargetPort: 8080, Protocol: "http"}
+	httpAlt := MeshService{Namespace: "default", Name: "bookstore", Port: 8080, TargetPort: 8080, Protocol: "http"}
+	tcp := MeshService{Namespace: "default", Name: "bookstore", Port: 5432, TargetPort: 5432, Protocol: "tcp"}
+	unresolved := MeshService{Namespace: "default", Name: "bookstore", Port: 9090, Protocol: "http"}
+
+	testCases := []struct {
+		name     string
+		ports    []MeshService
+		expected []MeshService
+	}{
+		{
+			name:     "no ports",
+			ports:    nil,
+			expected: nil,
+		},
+		{
+			name:     "distinct target ports",
+			ports:    []MeshService{http, tcp},
+			expected: []MeshService{http, tcp},
+		},
+		{
+			name:     "ports sharing a target port are deduped and order is preserved",
+			ports:    []MeshService{tcp, httpAlt, http},
+			expected: []MeshService{tcp, httpAlt},
+		},
+		{
+			name:     "ports with an unresolved target port are dropped",
+			ports:    []MeshService{unresolved, http},
+			expected: []MeshService{http},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.expected, DedupTargetPorts(tc.ports))
+		})
+	}
+}
@@ -19,6 +19,19 @@ type MeshService struct {
 
 	// The name of the service
 	Name string
+
+	// Port is the port of the service that clients send traffic to, ie. 'spec.ports[].port' for a Kubernetes service.
+	// The port fields are only set on the MeshService objects returned per port of a service, and are zero when the
+	// MeshService refers to the service as a whole.
+	Port uint32
+
+	// TargetPort is the port of the service's endpoints that the traffic sent to Port is forwarded to, ie.
+	// 'spec.ports[].targetPort' for a Kubernetes service, resolved to a port number. It is zero when a named target port
+	// could not be resolved from the service's endpoints.
+	TargetPort uint32
+
+	// Protocol is the application protocol of the service's port
+	Protocol string
 }
 
 // K8sServiceAccount is a type for a namespaced service account
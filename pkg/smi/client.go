@@ -3,6 +3,7 @@ package smi
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
@@ -118,6 +119,8 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 		announcements:  make(chan a.Announcement),
 		osmNamespace:   osmNamespace,
 		kubeController: kubeController,
+		expiryTimers:   make(map[string]*expiryTimer),
+		scheduleTimers: make(map[string]*scheduleTimer),
 	}
 
 	shouldObserve := func(obj interface{}) bool {
@@ -131,6 +134,7 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 		Delete: a.TrafficSplitDeleted,
 	}
 	informerCollection.TrafficSplit.AddEventHandler(k8s.GetKubernetesEventHandlers("TrafficSplit", "SMI", shouldObserve, splitEventTypes))
+	informerCollection.TrafficSplit.AddEventHandler(client.getPolicyScheduleEventHandlers("TrafficSplit"))
 
 	routeGroupEventTypes := k8s.EventTypes{
 		Add:    a.RouteGroupAdded,
@@ -152,6 +156,8 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 		Delete: a.TrafficTargetDeleted,
 	}
 	informerCollection.TrafficTarget.AddEventHandler(k8s.GetKubernetesEventHandlers("TrafficTarget", "SMI", shouldObserve, trafficTargetEventTypes))
+	informerCollection.TrafficTarget.AddEventHandler(client.getTrafficTargetExpiryEventHandlers())
+	informerCollection.TrafficTarget.AddEventHandler(client.getPolicyScheduleEventHandlers("TrafficTarget"))
 
 	err := client.run(stop)
 	if err != nil {
@@ -161,7 +167,7 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 	return &client, err
 }
 
-// ListTrafficSplits implements mesh.MeshSpec by returning the list of traffic splits.
+// ListTrafficSplits implements mesh.MeshSpec by returning the list of traffic splits, in their order of precedence.
 func (c *Client) ListTrafficSplits() []*smiSplit.TrafficSplit {
 	var trafficSplits []*smiSplit.TrafficSplit
 	for _, splitIface := range c.caches.TrafficSplit.List() {
@@ -170,8 +176,13 @@ func (c *Client) ListTrafficSplits() []*smiSplit.TrafficSplit {
 		if !c.kubeController.IsMonitoredNamespace(trafficSplit.Namespace) {
 			continue
 		}
+		// TrafficSplits applying on a schedule only split traffic during their time windows
+		if !isPolicyActive(trafficSplit, time.Now()) {
+			continue
+		}
 		trafficSplits = append(trafficSplits, trafficSplit)
 	}
+	sortTrafficSplits(trafficSplits)
 	return trafficSplits
 }
 
@@ -223,6 +234,11 @@ func (c *Client) ListTrafficTargets() []*smiAccess.TrafficTarget {
 		if !c.kubeController.IsMonitoredNamespace(trafficTarget.Namespace) {
 			continue
 		}
+		// Expired TrafficTargets no longer grant access, and TrafficTargets applying on a schedule only grant access
+		// during their time windows
+		if isTrafficTargetExpired(trafficTarget, time.Now()) || !isPolicyActive(trafficTarget, time.Now()) {
+			continue
+		}
 		trafficTargets = append(trafficTargets, trafficTarget)
 	}
 	return trafficTargets
@@ -234,7 +250,15 @@ func (c *Client) ListServiceAccounts() []service.K8sServiceAccount {
 	for _, targetIface := range c.caches.TrafficTarget.List() {
 		trafficTarget := targetIface.(*smiAccess.TrafficTarget)
 
+		if isTrafficTargetExpired(trafficTarget, time.Now()) || !isPolicyActive(trafficTarget, time.Now()) {
+			continue
+		}
+
 		for _, sources := range trafficTarget.Spec.Sources {
+			// Wildcard sources do not name a service account
+			if sources.Name == WildcardIdentity {
+				continue
+			}
 			// Only monitor sources in namespaces OSM is observing
 			if !c.kubeController.IsMonitoredNamespace(sources.Namespace) {
 				// Doesn't belong to namespaces we are observing
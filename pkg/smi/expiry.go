@@ -0,0 +1,140 @@
+package smi
+
+import (
+	"time"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	"k8s.io/client-go/tools/cache"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// GetTrafficTargetExpiry returns the time at which the given TrafficTarget expires, as set by its expires-at annotation.
+// The second return value is false if the TrafficTarget does not expire, and an error is returned if the annotation is invalid.
+func GetTrafficTargetExpiry(trafficTarget *smiAccess.TrafficTarget) (time.Time, bool, error) {
+	expiresAt, ok := trafficTarget.Annotations[constants.TrafficTargetExpiresAtAnnotation]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	expiry, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return time.Time{}, true, err
+	}
+	return expiry, true, nil
+}
+
+// isTrafficTargetExpired returns true if the given TrafficTarget has expired at the given time.
+// A TrafficTarget with an invalid expires-at annotation is considered expired, so that a malformed
+// temporary access grant never results in permanent access.
+func isTrafficTargetExpired(trafficTarget *smiAccess.TrafficTarget, now time.Time) bool {
+	expiry, expires, err := GetTrafficTargetExpiry(trafficTarget)
+	if err != nil {
+		log.Error().Err(err).Msgf("Invalid %s annotation on TrafficTarget %s/%s, ignoring it",
+			constants.TrafficTargetExpiresAtAnnotation, trafficTarget.Namespace, trafficTarget.Name)
+		return true
+	}
+	return expires && !now.Before(expiry)
+}
+
+// getTrafficTargetExpiryEventHandlers returns the event handlers scheduling the expiry of TrafficTargets granting temporary access
+func (c *Client) getTrafficTargetExpiryEventHandlers() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.scheduleTrafficTargetExpiry(obj.(*smiAccess.TrafficTarget))
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.scheduleTrafficTargetExpiry(newObj.(*smiAccess.TrafficTarget))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if trafficTarget, ok := obj.(*smiAccess.TrafficTarget); ok {
+				c.cancelTrafficTargetExpiry(trafficTarget)
+			}
+		},
+	}
+}
+
+// scheduleTrafficTargetExpiry schedules an announcement at the expiry of the given TrafficTarget, so that the
+// access it grants is removed from the proxy configurations when it expires. A previously scheduled expiry of
+// the TrafficTarget is replaced.
+func (c *Client) scheduleTrafficTargetExpiry(trafficTarget *smiAccess.TrafficTarget) {
+	key := trafficTarget.Namespace + "/" + trafficTarget.Name
+
+	c.expiryTimersLock.Lock()
+	defer c.expiryTimersLock.Unlock()
+
+	expiry, expires, err := GetTrafficTargetExpiry(trafficTarget)
+	// Only TrafficTargets in monitored namespaces grant access
+	expires = expires && c.kubeController.IsMonitoredNamespace(trafficTarget.Namespace)
+	if existing, ok := c.expiryTimers[key]; ok {
+		if expires && err == nil && existing.expiry.Equal(expiry) {
+			return
+		}
+		existing.timer.Stop()
+		delete(c.expiryTimers, key)
+	}
+	defer c.updateTemporaryAccessGrantCount()
+
+	if !expires || err != nil {
+		return
+	}
+
+	untilExpiry := time.Until(expiry)
+	if untilExpiry <= 0 {
+		return
+	}
+
+	log.Debug().Msgf("TrafficTarget %s grants temporary access until %s", key, expiry.Format(time.RFC3339))
+	c.expiryTimers[key] = &expiryTimer{
+		expiry: expiry,
+		timer: time.AfterFunc(untilExpiry, func() {
+			c.expireTrafficTarget(key, expiry, trafficTarget)
+		}),
+	}
+}
+
+// expireTrafficTarget announces the expiry of the given TrafficTarget if its scheduled expiry was not replaced
+func (c *Client) expireTrafficTarget(key string, expiry time.Time, trafficTarget *smiAccess.TrafficTarget) {
+	c.expiryTimersLock.Lock()
+	existing, ok := c.expiryTimers[key]
+	if !ok || !existing.expiry.Equal(expiry) {
+		c.expiryTimersLock.Unlock()
+		return
+	}
+	delete(c.expiryTimers, key)
+	c.updateTemporaryAccessGrantCount()
+	c.expiryTimersLock.Unlock()
+
+	log.Info().Msgf("TrafficTarget %s expired at %s, removing the access it grants", key, expiry.Format(time.RFC3339))
+	events.GetPubSubInstance().Publish(events.PubSubMessage{
+		AnnouncementType: a.TrafficTargetExpired,
+		NewObj:           nil,
+		OldObj:           trafficTarget,
+	})
+}
+
+// cancelTrafficTargetExpiry cancels the scheduled expiry of the given deleted TrafficTarget
+func (c *Client) cancelTrafficTargetExpiry(trafficTarget *smiAccess.TrafficTarget) {
+	key := trafficTarget.Namespace + "/" + trafficTarget.Name
+
+	c.expiryTimersLock.Lock()
+	defer c.expiryTimersLock.Unlock()
+
+	if existing, ok := c.expiryTimers[key]; ok {
+		existing.timer.Stop()
+		delete(c.expiryTimers, key)
+		c.updateTemporaryAccessGrantCount()
+	}
+}
+
+// updateTemporaryAccessGrantCount updates the metric for the number of active temporary access grants.
+// The caller must hold expiryTimersLock.
+func (c *Client) updateTemporaryAccessGrantCount() {
+	metricsstore.DefaultMetricsStore.SMITemporaryAccessGrantCount.Set(float64(len(c.expiryTimers)))
+}
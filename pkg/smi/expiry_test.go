@@ -0,0 +1,126 @@
+package smi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+func newExpiringTrafficTarget(expiresAt string) *smiAccess.TrafficTarget {
+	trafficTarget := &smiAccess.TrafficTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "temporary-access",
+			Namespace: testNamespaceName,
+		},
+	}
+	if expiresAt != "" {
+		trafficTarget.Annotations = map[string]string{constants.TrafficTargetExpiresAtAnnotation: expiresAt}
+	}
+	return trafficTarget
+}
+
+func TestIsTrafficTargetExpired(t *testing.T) {
+	now := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		expiresAt       string
+		expectedExpired bool
+	}{
+		{
+			name:            "no expiry",
+			expiresAt:       "",
+			expectedExpired: false,
+		},
+		{
+			name:            "expires in the future",
+			expiresAt:       "2021-04-01T13:00:00Z",
+			expectedExpired: false,
+		},
+		{
+			name:            "expired in the past",
+			expiresAt:       "2021-04-01T11:00:00Z",
+			expectedExpired: true,
+		},
+		{
+			name:            "expires now",
+			expiresAt:       "2021-04-01T12:00:00Z",
+			expectedExpired: true,
+		},
+		{
+			name:            "expires in the future with a time zone offset",
+			expiresAt:       "2021-04-01T13:30:00+01:00",
+			expectedExpired: false,
+		},
+		{
+			name:            "invalid expiry",
+			expiresAt:       "tomorrow",
+			expectedExpired: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedExpired, isTrafficTargetExpired(newExpiringTrafficTarget(tc.expiresAt), now))
+		})
+	}
+}
+
+func TestScheduleTrafficTargetExpiry(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(testNamespaceName).Return(true).AnyTimes()
+
+	c := &Client{
+		kubeController: mockKubeController,
+		expiryTimers:   make(map[string]*expiryTimer),
+	}
+
+	expiredEvents := events.GetPubSubInstance().Subscribe(announcements.TrafficTargetExpired)
+	defer events.GetPubSubInstance().Unsub(expiredEvents)
+
+	// A TrafficTarget without expiry is not scheduled to expire
+	c.scheduleTrafficTargetExpiry(newExpiringTrafficTarget(""))
+	assert.Len(c.expiryTimers, 0)
+
+	// A TrafficTarget that already expired is not scheduled to expire
+	c.scheduleTrafficTargetExpiry(newExpiringTrafficTarget(time.Now().Add(-time.Hour).Format(time.RFC3339)))
+	assert.Len(c.expiryTimers, 0)
+
+	// A TrafficTarget deleted before its expiry does not expire
+	c.scheduleTrafficTargetExpiry(newExpiringTrafficTarget(time.Now().Add(time.Hour).Format(time.RFC3339)))
+	assert.Len(c.expiryTimers, 1)
+	c.cancelTrafficTargetExpiry(newExpiringTrafficTarget(""))
+	assert.Len(c.expiryTimers, 0)
+
+	// A TrafficTarget expiring in the future is announced at its expiry
+	c.scheduleTrafficTargetExpiry(newExpiringTrafficTarget(time.Now().Add(2 * time.Second).Format(time.RFC3339)))
+	assert.Len(c.expiryTimers, 1)
+
+	select {
+	case msg := <-expiredEvents:
+		pubSubMessage, ok := msg.(events.PubSubMessage)
+		assert.True(ok)
+		assert.Equal(announcements.TrafficTargetExpired, pubSubMessage.AnnouncementType)
+		assert.Equal("temporary-access", pubSubMessage.OldObj.(*smiAccess.TrafficTarget).Name)
+	case <-time.After(5 * time.Second):
+		assert.Fail("TrafficTarget expiry was not announced")
+	}
+
+	c.expiryTimersLock.Lock()
+	defer c.expiryTimersLock.Unlock()
+	assert.Len(c.expiryTimers, 0)
+}
@@ -0,0 +1,313 @@
+package smi
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+const (
+	// minutesPerDay is the number of minutes in a day
+	minutesPerDay = 24 * 60
+
+	// daysPerWeek is the number of days in a week
+	daysPerWeek = 7
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is the schedule of an SMI policy, the set of recurring time windows during which the policy applies
+type Schedule []scheduleWindow
+
+// scheduleWindow is a time window recurring on the given days of the week, in UTC. A window ending at or before its
+// start time ends on the next day.
+type scheduleWindow struct {
+	days  [daysPerWeek]bool
+	start int // minutes since midnight
+	end   int // minutes since midnight
+}
+
+// ParseSchedule parses a schedule from the comma separated list of time windows of the active-schedule annotation.
+// Each window is of the form [<day>[-<day>] ]<HH:MM>-<HH:MM>, e.g. "Mon-Fri 22:00-06:00", in UTC. A window without
+// days recurs every day, and a window ending at 24:00 ends at midnight.
+func ParseSchedule(scheduleStr string) (Schedule, error) {
+	var schedule Schedule
+	for _, windowStr := range strings.Split(scheduleStr, ",") {
+		windowStr = strings.TrimSpace(windowStr)
+		if windowStr == "" {
+			continue
+		}
+		window, err := parseScheduleWindow(windowStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid time window %q", windowStr)
+		}
+		schedule = append(schedule, window)
+	}
+	if len(schedule) == 0 {
+		return nil, errors.New("no time window")
+	}
+	return schedule, nil
+}
+
+func parseScheduleWindow(windowStr string) (scheduleWindow, error) {
+	var window scheduleWindow
+
+	fields := strings.Fields(windowStr)
+	var timesStr string
+	switch len(fields) {
+	case 1:
+		for day := range window.days {
+			window.days[day] = true
+		}
+		timesStr = fields[0]
+	case 2:
+		if err := parseScheduleDays(fields[0], &window.days); err != nil {
+			return window, err
+		}
+		timesStr = fields[1]
+	default:
+		return window, errors.New("must be of the form [<day>[-<day>] ]<HH:MM>-<HH:MM>")
+	}
+
+	times := strings.Split(timesStr, "-")
+	if len(times) != 2 {
+		return window, errors.Errorf("invalid times %q, must be of the form <HH:MM>-<HH:MM>", timesStr)
+	}
+	var err error
+	if window.start, err = parseScheduleTime(times[0]); err != nil {
+		return window, err
+	}
+	if window.end, err = parseScheduleTime(times[1]); err != nil {
+		return window, err
+	}
+	if window.start == window.end {
+		return window, errors.New("start and end times must differ")
+	}
+	return window, nil
+}
+
+func parseScheduleDays(daysStr string, days *[daysPerWeek]bool) error {
+	bounds := strings.Split(daysStr, "-")
+	if len(bounds) > 2 {
+		return errors.Errorf("invalid days %q, must be of the form <day>[-<day>]", daysStr)
+	}
+
+	var boundDays []time.Weekday
+	for _, bound := range bounds {
+		day, ok := weekdays[strings.ToLower(bound)]
+		if !ok {
+			return errors.Errorf("invalid day %q, must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun", bound)
+		}
+		boundDays = append(boundDays, day)
+	}
+
+	// A day range wraps around the end of the week, e.g. Fri-Mon
+	first, last := boundDays[0], boundDays[len(boundDays)-1]
+	for day := first; ; day = (day + 1) % daysPerWeek {
+		days[day] = true
+		if day == last {
+			return nil
+		}
+	}
+}
+
+func parseScheduleTime(timeStr string) (int, error) {
+	// A window may end at the end of the day
+	if timeStr == "24:00" {
+		return minutesPerDay, nil
+	}
+	t, err := time.Parse("15:04", timeStr)
+	if err != nil {
+		return 0, errors.Errorf("invalid time %q, must be of the form HH:MM", timeStr)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// IsActive returns whether the given time is within a time window of the schedule
+func (s Schedule) IsActive(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	previousDay := (day + daysPerWeek - 1) % daysPerWeek
+
+	for _, window := range s {
+		if window.start < window.end {
+			if window.days[day] && minute >= window.start && minute < window.end {
+				return true
+			}
+			continue
+		}
+		// The window ends on the day after it starts
+		if (window.days[day] && minute >= window.start) || (window.days[previousDay] && minute < window.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextBoundary returns the first time after the given time at which a time window of the schedule starts or ends
+func (s Schedule) NextBoundary(t time.Time) time.Time {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	var next time.Time
+	// Windows starting the day before may end today, and every window recurs within a week
+	for dayOffset := -1; dayOffset <= daysPerWeek; dayOffset++ {
+		day := midnight.AddDate(0, 0, dayOffset)
+		for _, window := range s {
+			if !window.days[day.Weekday()] {
+				continue
+			}
+			start := day.Add(time.Duration(window.start) * time.Minute)
+			end := day.Add(time.Duration(window.end) * time.Minute)
+			if window.end <= window.start {
+				end = end.Add(minutesPerDay * time.Minute)
+			}
+			for _, boundary := range []time.Time{start, end} {
+				if boundary.After(t) && (next.IsZero() || boundary.Before(next)) {
+					next = boundary
+				}
+			}
+		}
+	}
+	return next
+}
+
+// GetPolicySchedule returns the schedule of the given SMI policy, as set by its active-schedule annotation. The second
+// return value is false if the policy always applies, and an error is returned if the annotation is invalid.
+func GetPolicySchedule(policy metav1.Object) (Schedule, bool, error) {
+	scheduleStr, ok := policy.GetAnnotations()[constants.PolicyScheduleAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+
+	schedule, err := ParseSchedule(scheduleStr)
+	if err != nil {
+		return nil, true, err
+	}
+	return schedule, true, nil
+}
+
+// isPolicyActive returns true if the given SMI policy applies at the given time. A policy with an invalid
+// active-schedule annotation never applies, so that a malformed schedule never results in permanent access.
+func isPolicyActive(policy metav1.Object, now time.Time) bool {
+	schedule, scheduled, err := GetPolicySchedule(policy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Invalid %s annotation on %s/%s, ignoring it",
+			constants.PolicyScheduleAnnotation, policy.GetNamespace(), policy.GetName())
+		return false
+	}
+	return !scheduled || schedule.IsActive(now)
+}
+
+// getPolicyScheduleEventHandlers returns the event handlers scheduling the boundaries of the time windows of the SMI
+// policies of the given kind
+func (c *Client) getPolicyScheduleEventHandlers(kind string) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if policy, ok := obj.(metav1.Object); ok {
+				c.schedulePolicyBoundary(kind, policy)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if policy, ok := newObj.(metav1.Object); ok {
+				c.schedulePolicyBoundary(kind, policy)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if policy, ok := obj.(metav1.Object); ok {
+				c.cancelPolicyBoundary(kind, policy)
+			}
+		},
+	}
+}
+
+// schedulePolicyBoundary schedules an announcement at the next boundary of the time windows of the given SMI policy, so
+// that the proxy configurations are recomputed when the policy starts or stops applying. A previously scheduled
+// boundary of the policy is replaced.
+func (c *Client) schedulePolicyBoundary(kind string, policy metav1.Object) {
+	key := kind + "/" + policy.GetNamespace() + "/" + policy.GetName()
+
+	c.scheduleTimersLock.Lock()
+	defer c.scheduleTimersLock.Unlock()
+
+	var boundary time.Time
+	schedule, scheduled, err := GetPolicySchedule(policy)
+	// Only policies in monitored namespaces apply
+	if scheduled && err == nil && c.kubeController.IsMonitoredNamespace(policy.GetNamespace()) {
+		boundary = schedule.NextBoundary(time.Now())
+	}
+	if existing, ok := c.scheduleTimers[key]; ok {
+		if !boundary.IsZero() && existing.boundary.Equal(boundary) {
+			return
+		}
+		existing.timer.Stop()
+		delete(c.scheduleTimers, key)
+	}
+
+	if boundary.IsZero() {
+		return
+	}
+
+	log.Debug().Msgf("%s applies on schedule %q, next boundary at %s",
+		key, policy.GetAnnotations()[constants.PolicyScheduleAnnotation], boundary.Format(time.RFC3339))
+	c.scheduleTimers[key] = &scheduleTimer{
+		boundary: boundary,
+		timer: time.AfterFunc(time.Until(boundary), func() {
+			c.announcePolicyBoundary(kind, key, boundary, policy)
+		}),
+	}
+}
+
+// announcePolicyBoundary announces the boundary of a time window of the given SMI policy if its scheduled boundary was
+// not replaced, and schedules the next boundary
+func (c *Client) announcePolicyBoundary(kind string, key string, boundary time.Time, policy metav1.Object) {
+	c.scheduleTimersLock.Lock()
+	existing, ok := c.scheduleTimers[key]
+	if !ok || !existing.boundary.Equal(boundary) {
+		c.scheduleTimersLock.Unlock()
+		return
+	}
+	delete(c.scheduleTimers, key)
+	c.scheduleTimersLock.Unlock()
+
+	log.Info().Msgf("%s reached a boundary of its schedule at %s, recomputing proxy configurations", key, boundary.Format(time.RFC3339))
+	events.GetPubSubInstance().Publish(events.PubSubMessage{
+		AnnouncementType: a.PolicyScheduleBoundary,
+		NewObj:           policy,
+		OldObj:           nil,
+	})
+
+	c.schedulePolicyBoundary(kind, policy)
+}
+
+// cancelPolicyBoundary cancels the scheduled boundary of the given deleted SMI policy
+func (c *Client) cancelPolicyBoundary(kind string, policy metav1.Object) {
+	key := kind + "/" + policy.GetNamespace() + "/" + policy.GetName()
+
+	c.scheduleTimersLock.Lock()
+	defer c.scheduleTimersLock.Unlock()
+
+	if existing, ok := c.scheduleTimers[key]; ok {
+		existing.timer.Stop()
+		delete(c.scheduleTimers, key)
+	}
+}
@@ -0,0 +1,292 @@
+package smi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+func newScheduledTrafficSplit(schedule string) *smiSplit.TrafficSplit {
+	trafficSplit := &smiSplit.TrafficSplit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "maintenance",
+			Namespace: testNamespaceName,
+		},
+	}
+	if schedule != "" {
+		trafficSplit.Annotations = map[string]string{constants.PolicyScheduleAnnotation: schedule}
+	}
+	return trafficSplit
+}
+
+func TestParseSchedule(t *testing.T) {
+	testCases := []struct {
+		schedule    string
+		expectedErr bool
+	}{
+		{schedule: "09:00-17:00", expectedErr: false},
+		{schedule: "Mon-Fri 09:00-17:00", expectedErr: false},
+		{schedule: "Sat 00:00-24:00, Sun 00:00-24:00", expectedErr: false},
+		{schedule: "fri-mon 22:00-06:00", expectedErr: false},
+		{schedule: "", expectedErr: true},
+		{schedule: "Mon-Fri", expectedErr: true},
+		{schedule: "Mon-Fri 9am-5pm", expectedErr: true},
+		{schedule: "Someday 09:00-17:00", expectedErr: true},
+		{schedule: "Mon-Wed-Fri 09:00-17:00", expectedErr: true},
+		{schedule: "09:00-09:00", expectedErr: true},
+		{schedule: "25:00-26:00", expectedErr: true},
+		{schedule: "Mon 09:00-17:00 UTC", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schedule, func(t *testing.T) {
+			assert := tassert.New(t)
+			_, err := ParseSchedule(tc.schedule)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestScheduleIsActive(t *testing.T) {
+	// 2021-04-02 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2021, 4, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name           string
+		schedule       string
+		time           time.Time
+		expectedActive bool
+	}{
+		{
+			name:           "within a daily window",
+			schedule:       "09:00-17:00",
+			time:           friday(12, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "at the start of a window",
+			schedule:       "09:00-17:00",
+			time:           friday(9, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "at the end of a window",
+			schedule:       "09:00-17:00",
+			time:           friday(17, 0),
+			expectedActive: false,
+		},
+		{
+			name:           "on a day outside of the window days",
+			schedule:       "Mon-Thu 09:00-17:00",
+			time:           friday(12, 0),
+			expectedActive: false,
+		},
+		{
+			name:           "within a day range wrapping around the end of the week",
+			schedule:       "Fri-Mon 09:00-17:00",
+			time:           friday(12, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "before midnight in a window crossing midnight",
+			schedule:       "Fri 22:00-06:00",
+			time:           friday(23, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "after midnight in a window crossing midnight",
+			schedule:       "Fri 22:00-06:00",
+			time:           friday(23, 0).Add(2 * time.Hour),
+			expectedActive: true,
+		},
+		{
+			name:           "after midnight in a window crossing midnight that started the day before",
+			schedule:       "Thu 22:00-06:00",
+			time:           friday(5, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "after midnight in a window crossing midnight that did not start the day before",
+			schedule:       "Fri 22:00-06:00",
+			time:           friday(5, 0),
+			expectedActive: false,
+		},
+		{
+			name:           "within the second window",
+			schedule:       "Mon 09:00-17:00, Fri 09:00-17:00",
+			time:           friday(12, 0),
+			expectedActive: true,
+		},
+		{
+			name:           "in a time zone other than UTC",
+			schedule:       "09:00-17:00",
+			time:           friday(12, 0).In(time.FixedZone("UTC-10", -10*60*60)),
+			expectedActive: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			schedule, err := ParseSchedule(tc.schedule)
+			assert.Nil(err)
+			assert.Equal(tc.expectedActive, schedule.IsActive(tc.time))
+		})
+	}
+}
+
+func TestScheduleNextBoundary(t *testing.T) {
+	// 2021-04-02 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2021, 4, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name             string
+		schedule         string
+		time             time.Time
+		expectedBoundary time.Time
+	}{
+		{
+			name:             "before the start of a window",
+			schedule:         "09:00-17:00",
+			time:             friday(8, 0),
+			expectedBoundary: friday(9, 0),
+		},
+		{
+			name:             "at the start of a window",
+			schedule:         "09:00-17:00",
+			time:             friday(9, 0),
+			expectedBoundary: friday(17, 0),
+		},
+		{
+			name:             "after the end of a daily window",
+			schedule:         "09:00-17:00",
+			time:             friday(18, 0),
+			expectedBoundary: friday(9, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:             "after the end of a window on weekdays",
+			schedule:         "Mon-Fri 09:00-17:00",
+			time:             friday(18, 0),
+			expectedBoundary: friday(9, 0).AddDate(0, 0, 3),
+		},
+		{
+			name:             "within a window crossing midnight that started the day before",
+			schedule:         "Thu 22:00-06:00",
+			time:             friday(1, 0),
+			expectedBoundary: friday(6, 0),
+		},
+		{
+			name:             "within a window crossing midnight",
+			schedule:         "Fri 22:00-06:00",
+			time:             friday(23, 0),
+			expectedBoundary: friday(6, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:             "after the end of a weekly window",
+			schedule:         "Fri 09:00-17:00",
+			time:             friday(18, 0),
+			expectedBoundary: friday(9, 0).AddDate(0, 0, 7),
+		},
+		{
+			name:             "within a window ending at midnight",
+			schedule:         "Fri 09:00-24:00",
+			time:             friday(18, 0),
+			expectedBoundary: friday(0, 0).AddDate(0, 0, 1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			schedule, err := ParseSchedule(tc.schedule)
+			assert.Nil(err)
+			assert.Equal(tc.expectedBoundary, schedule.NextBoundary(tc.time))
+		})
+	}
+}
+
+func TestIsPolicyActive(t *testing.T) {
+	assert := tassert.New(t)
+	now := time.Date(2021, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	assert.True(isPolicyActive(newScheduledTrafficSplit(""), now))
+	assert.True(isPolicyActive(newScheduledTrafficSplit("09:00-17:00"), now))
+	assert.False(isPolicyActive(newScheduledTrafficSplit("22:00-06:00"), now))
+	// A policy with an invalid schedule never applies
+	assert.False(isPolicyActive(newScheduledTrafficSplit("during deploys"), now))
+}
+
+func TestSchedulePolicyBoundary(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(testNamespaceName).Return(true).AnyTimes()
+
+	c := &Client{
+		kubeController: mockKubeController,
+		scheduleTimers: make(map[string]*scheduleTimer),
+	}
+
+	boundaryEvents := events.GetPubSubInstance().Subscribe(announcements.PolicyScheduleBoundary)
+	defer events.GetPubSubInstance().Unsub(boundaryEvents)
+
+	// A policy without schedule has no boundary
+	c.schedulePolicyBoundary("TrafficSplit", newScheduledTrafficSplit(""))
+	assert.Len(c.scheduleTimers, 0)
+
+	// A policy with an invalid schedule has no boundary
+	c.schedulePolicyBoundary("TrafficSplit", newScheduledTrafficSplit("during deploys"))
+	assert.Len(c.scheduleTimers, 0)
+
+	// A policy deleted before its next boundary is not announced
+	c.schedulePolicyBoundary("TrafficSplit", newScheduledTrafficSplit("Mon 09:00-17:00"))
+	assert.Len(c.scheduleTimers, 1)
+	c.cancelPolicyBoundary("TrafficSplit", newScheduledTrafficSplit(""))
+	assert.Len(c.scheduleTimers, 0)
+
+	// A policy reaching the boundary of a window is announced, and its next boundary is scheduled
+	policy := newScheduledTrafficSplit("Mon 09:00-17:00")
+	key := "TrafficSplit/" + testNamespaceName + "/maintenance"
+	c.schedulePolicyBoundary("TrafficSplit", policy)
+	assert.Len(c.scheduleTimers, 1)
+	boundary := c.scheduleTimers[key].boundary
+	c.scheduleTimers[key].timer.Stop()
+	c.announcePolicyBoundary("TrafficSplit", key, boundary, policy)
+
+	select {
+	case msg := <-boundaryEvents:
+		pubSubMessage, ok := msg.(events.PubSubMessage)
+		assert.True(ok)
+		assert.Equal(announcements.PolicyScheduleBoundary, pubSubMessage.AnnouncementType)
+		assert.Equal("maintenance", pubSubMessage.NewObj.(*smiSplit.TrafficSplit).Name)
+	case <-time.After(5 * time.Second):
+		assert.Fail("TrafficSplit schedule boundary was not announced")
+	}
+	assert.Len(c.scheduleTimers, 1)
+
+	// A replaced boundary is not announced
+	c.announcePolicyBoundary("TrafficSplit", key, boundary.Add(-time.Hour), policy)
+	select {
+	case <-boundaryEvents:
+		assert.Fail("Replaced TrafficSplit schedule boundary was announced")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	c.cancelPolicyBoundary("TrafficSplit", policy)
+	assert.Len(c.scheduleTimers, 0)
+}
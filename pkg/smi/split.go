@@ -0,0 +1,33 @@
+package smi
+
+import (
+	"sort"
+
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sortTrafficSplits sorts the given TrafficSplits in their order of precedence. Only the first TrafficSplit of an apex
+// service routes its traffic, so the order must not depend on the order of the informer cache, which changes as the
+// TrafficSplits are updated, for the TrafficSplit routing the traffic of an apex service not to change each time the
+// traffic policies are recomputed:
+// 1. TrafficSplits managed by a controller, such as the TrafficSplits of the Flagger Canaries and Argo Rollouts
+// 2. Older TrafficSplits
+// 3. TrafficSplits by namespace and name
+func sortTrafficSplits(trafficSplits []*smiSplit.TrafficSplit) {
+	sort.SliceStable(trafficSplits, func(i, j int) bool {
+		a, b := trafficSplits[i], trafficSplits[j]
+
+		aManaged, bManaged := metav1.GetControllerOf(a) != nil, metav1.GetControllerOf(b) != nil
+		if aManaged != bManaged {
+			return aManaged
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
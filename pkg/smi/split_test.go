@@ -0,0 +1,50 @@
+package smi
+
+import (
+	"testing"
+	"time"
+
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSortTrafficSplits(t *testing.T) {
+	assert := tassert.New(t)
+
+	created := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+	newTrafficSplit := func(namespace, name string, createdAt time.Time, ownerKind string) *smiSplit.TrafficSplit {
+		trafficSplit := &smiSplit.TrafficSplit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+		}
+		if ownerKind != "" {
+			isController := true
+			trafficSplit.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name, Controller: &isController}}
+		}
+		return trafficSplit
+	}
+
+	manual := newTrafficSplit("bookstore", "bookstore-split", created, "")
+	newerManual := newTrafficSplit("bookstore", "bookstore-split-2", created.Add(time.Hour), "")
+	sameTimeManual := newTrafficSplit("bookstore", "a-bookstore-split", created, "")
+	otherNamespaceManual := newTrafficSplit("bookbuyer", "bookbuyer-split", created, "")
+	canary := newTrafficSplit("bookstore", "bookstore", created.Add(2*time.Hour), "Canary")
+	rollout := newTrafficSplit("bookstore", "bookstore-rollout", created.Add(time.Hour), "Rollout")
+
+	trafficSplits := []*smiSplit.TrafficSplit{manual, newerManual, canary, sameTimeManual, rollout, otherNamespaceManual}
+	sortTrafficSplits(trafficSplits)
+
+	// TrafficSplits managed by a controller take precedence, then older TrafficSplits
+	assert.Equal([]*smiSplit.TrafficSplit{rollout, canary, otherNamespaceManual, sameTimeManual, manual, newerManual}, trafficSplits)
+
+	// TrafficSplits owned by a resource that is not their controller are not managed
+	owned := newTrafficSplit("bookstore", "owned", created.Add(3*time.Hour), "")
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "ConfigMap", Name: "owner"}}
+	trafficSplits = []*smiSplit.TrafficSplit{owned, manual}
+	sortTrafficSplits(trafficSplits)
+	assert.Equal([]*smiSplit.TrafficSplit{manual, owned}, trafficSplits)
+}
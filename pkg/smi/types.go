@@ -3,6 +3,9 @@
 package smi
 
 import (
+	"sync"
+	"time"
+
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
@@ -19,6 +22,11 @@ var (
 	log = logger.New("mesh-spec")
 )
 
+// WildcardIdentity is the name and namespace of a TrafficTarget source matching more than one service account.
+// A source with the wildcard name matches any service account in its namespace, and a source with the wildcard
+// name and namespace matches any service account in the namespaces monitored by the mesh.
+const WildcardIdentity = "*"
+
 // informerCollection is a struct of the Kubernetes informers used for SMI resources
 type informerCollection struct {
 	TrafficSplit   cache.SharedIndexInformer
@@ -44,11 +52,32 @@ type Client struct {
 	announcements  chan announcements.Announcement
 	osmNamespace   string
 	kubeController k8s.Controller
+
+	// expiryTimers are the timers scheduling the expiry of TrafficTargets granting temporary access, keyed by <namespace>/<name>
+	expiryTimers     map[string]*expiryTimer
+	expiryTimersLock sync.Mutex
+
+	// scheduleTimers are the timers scheduling the next boundary of the time windows of SMI policies applying on a
+	// schedule, keyed by <kind>/<namespace>/<name>
+	scheduleTimers     map[string]*scheduleTimer
+	scheduleTimersLock sync.Mutex
+}
+
+// expiryTimer is a timer scheduled to fire at the expiry of a TrafficTarget
+type expiryTimer struct {
+	expiry time.Time
+	timer  *time.Timer
+}
+
+// scheduleTimer is a timer scheduled to fire at the next boundary of the time windows of an SMI policy
+type scheduleTimer struct {
+	boundary time.Time
+	timer    *time.Timer
 }
 
 // MeshSpec is an interface declaring functions, which provide the specs for a service mesh declared with SMI.
 type MeshSpec interface {
-	// ListTrafficSplits lists SMI TrafficSplit resources
+	// ListTrafficSplits lists SMI TrafficSplit resources, the first TrafficSplit of an apex service routing its traffic
 	ListTrafficSplits() []*split.TrafficSplit
 
 	// ListServiceAccounts lists ServiceAccount resources specified in SMI TrafficTarget resources
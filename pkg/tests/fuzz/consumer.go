@@ -0,0 +1,63 @@
+// Package fuzz implements utility routines used by fuzz targets to derive structured input from fuzzed data.
+package fuzz
+
+// Consumer derives values from fuzzed data, consuming the data from its beginning. Once the data is exhausted, zero
+// values are returned, so that a fuzz target always derives a complete input from any data.
+type Consumer struct {
+	data []byte
+}
+
+// NewConsumer returns a consumer of the given fuzzed data
+func NewConsumer(data []byte) *Consumer {
+	return &Consumer{data: data}
+}
+
+// Remaining returns the number of bytes of data not consumed yet
+func (c *Consumer) Remaining() int {
+	return len(c.data)
+}
+
+// Byte returns the next byte of data
+func (c *Consumer) Byte() byte {
+	if len(c.data) == 0 {
+		return 0
+	}
+	b := c.data[0]
+	c.data = c.data[1:]
+	return b
+}
+
+// Bool returns a boolean derived from the next byte of data
+func (c *Consumer) Bool() bool {
+	return c.Byte()&1 == 1
+}
+
+// Intn returns an integer in [0, n) derived from the next two bytes of data, or 0 if n is not positive
+func (c *Consumer) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	value := int(c.Byte())<<8 | int(c.Byte())
+	return value % n
+}
+
+// String returns a string of at most maxLen bytes of data, which are not necessarily valid UTF-8
+func (c *Consumer) String(maxLen int) string {
+	length := c.Intn(maxLen + 1)
+	if length > len(c.data) {
+		length = len(c.data)
+	}
+	s := string(c.data[:length])
+	c.data = c.data[length:]
+	return s
+}
+
+// Pick returns one of the given values, or a string of at most maxLen bytes of data. Fuzz targets pick from values
+// known to be meaningful, such as wildcards or path separators, to reach the code handling them sooner.
+func (c *Consumer) Pick(maxLen int, values ...string) string {
+	i := c.Intn(len(values) + 1)
+	if i == len(values) {
+		return c.String(maxLen)
+	}
+	return values[i]
+}
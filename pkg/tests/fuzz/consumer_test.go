@@ -0,0 +1,34 @@
+package fuzz
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestConsumer(t *testing.T) {
+	assert := tassert.New(t)
+
+	c := NewConsumer([]byte{1, 0, 7, 0, 3, 'a', 'b', 'c', 0, 1})
+	assert.Equal(10, c.Remaining())
+	assert.True(c.Bool())
+	assert.Equal(7, c.Intn(10))
+	assert.Equal("abc", c.String(5))
+	assert.Equal("b", c.Pick(5, "a", "b"))
+
+	// Once the data is exhausted, zero values are returned
+	assert.Equal(0, c.Remaining())
+	assert.Equal(byte(0), c.Byte())
+	assert.False(c.Bool())
+	assert.Equal(0, c.Intn(10))
+	assert.Equal("", c.String(5))
+	assert.Equal("a", c.Pick(5, "a", "b"))
+}
+
+func TestIntn(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(0, NewConsumer([]byte{1, 1}).Intn(0))
+	assert.Equal(257%1000, NewConsumer([]byte{1, 1}).Intn(1000))
+	assert.Equal(2, NewConsumer([]byte{0, 2}).Intn(3))
+}
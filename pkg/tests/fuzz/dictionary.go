@@ -0,0 +1,13 @@
+package fuzz
+
+var (
+	// Hosts are hosts known to be meaningful to the computation of policies, for fuzz targets to pick from
+	Hosts = []string{"", "*", "*.example.com", "bookstore", "bookstore.bookstore-ns", "bookstore.bookstore-ns.svc.cluster.local", "bookstore:8888", "..", "[::1]:80"}
+
+	// Paths are HTTP paths known to be meaningful to the computation of routes, for fuzz targets to pick from, such as
+	// paths with regex characters or invalid regexes
+	Paths = []string{"", "/", "/books", "/books/", "/*", ".*", "/books/[a-z]+", "(/.*)?$", "/books(", "/%", "\\", "^$"}
+
+	// Methods are HTTP methods known to be meaningful to the computation of routes, for fuzz targets to pick from
+	Methods = []string{"", "*", "GET", "POST", "get"}
+)
@@ -0,0 +1,194 @@
+// Package golden implements a harness for golden file tests of the xDS configuration generated for Envoy proxies.
+//
+// A golden file test renders the xDS configuration generated for a proxy of a canned mesh state, and compares it to the
+// configuration previously rendered for the same state and checked in as a golden file. Unintended changes of the
+// generated configuration, such as those introduced by refactors, fail the test with a diff of the configuration.
+// Intended changes are accepted by running the test with the -update-golden flag, which rewrites the golden files, and
+// reviewing the diff of the golden files.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/cds"
+	"github.com/openservicemesh/osm/pkg/envoy/eds"
+	"github.com/openservicemesh/osm/pkg/envoy/lds"
+	"github.com/openservicemesh/osm/pkg/envoy/rds"
+)
+
+// diffContextLines is the number of lines around the first difference shown by a failing golden file test
+const diffContextLines = 5
+
+var update = flag.Bool("update-golden", false, "Update the golden files with the rendered xDS configuration")
+
+// Handler generates the xDS response of a given type for a proxy
+type Handler func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager) (*xds_discovery.DiscoveryResponse, error)
+
+// Handlers are the xDS handlers whose responses are rendered, keyed by their type URI. SDS is not rendered, as the
+// certificates it serves are issued anew for each test.
+var Handlers = map[envoy.TypeURI]Handler{
+	envoy.TypeCDS: cds.NewResponse,
+	envoy.TypeEDS: eds.NewResponse,
+	envoy.TypeLDS: lds.NewResponse,
+	envoy.TypeRDS: rds.NewResponse,
+}
+
+// Render returns the xDS configuration generated for the given proxy by the given handlers, rendered as JSON
+func Render(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, cfg configurator.Configurator, certManager certificate.Manager, handlers map[envoy.TypeURI]Handler) ([]byte, error) {
+	var responses []*xds_discovery.DiscoveryResponse
+	for typeURI, handler := range handlers {
+		response, err := handler(meshCatalog, proxy, &xds_discovery.DiscoveryRequest{TypeUrl: string(typeURI)}, cfg, certManager)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error generating %s response", envoy.XDSShortURINames[typeURI])
+		}
+		responses = append(responses, response)
+	}
+	return RenderResponses(responses...)
+}
+
+// RenderResponses renders the resources of the given xDS responses as JSON, keyed by the short name of their type and
+// sorted by name, so that the rendered configuration does not depend on the order in which resources are generated
+func RenderResponses(responses ...*xds_discovery.DiscoveryResponse) ([]byte, error) {
+	rendered := make(map[string][]json.RawMessage)
+	for _, response := range responses {
+		typeName := envoy.XDSShortURINames[envoy.TypeURI(response.TypeUrl)]
+		if typeName == "" {
+			typeName = response.TypeUrl
+		}
+
+		resources := make([]json.RawMessage, 0, len(response.Resources))
+		names := make([]string, 0, len(response.Resources))
+		for _, resource := range response.Resources {
+			var dynamic ptypes.DynamicAny
+			if err := ptypes.UnmarshalAny(resource, &dynamic); err != nil {
+				return nil, errors.Wrapf(err, "error unmarshaling %s resource", typeName)
+			}
+			marshaled, err := protojson.Marshal(resource)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error marshaling %s resource", typeName)
+			}
+			resources = append(resources, marshaled)
+			names = append(names, getResourceName(dynamic.Message))
+		}
+		sort.Sort(byName{resources: resources, names: names})
+		rendered[typeName] = append(rendered[typeName], resources...)
+	}
+
+	// Marshaling the JSON again normalizes the whitespace of the JSON marshaled by protojson, which is not stable
+	marshaled, err := json.MarshalIndent(rendered, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(marshaled, '\n'), nil
+}
+
+// Assert compares the given rendered xDS configuration to the given golden file, and fails the test with the first
+// difference if they differ. With the -update-golden flag, the golden file is rewritten with the rendered configuration
+// instead.
+func Assert(t testing.TB, goldenFile string, rendered []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0750); err != nil {
+			t.Fatalf("Error creating the directory of golden file %s: %s", goldenFile, err)
+		}
+		if err := ioutil.WriteFile(goldenFile, rendered, 0600); err != nil {
+			t.Fatalf("Error updating golden file %s: %s", goldenFile, err)
+		}
+		return
+	}
+
+	golden, err := ioutil.ReadFile(filepath.Clean(goldenFile))
+	if err != nil {
+		t.Fatalf("Error reading golden file %s, run the test with -update-golden to create it: %s", goldenFile, err)
+		return
+	}
+	if !bytes.Equal(golden, rendered) {
+		t.Errorf("Rendered xDS configuration differs from golden file %s, run the test with -update-golden to accept the changes:\n%s",
+			goldenFile, diff(string(golden), string(rendered)))
+	}
+}
+
+// diff returns the lines around the first difference between the given golden and rendered configurations
+func diff(golden, rendered string) string {
+	goldenLines := strings.Split(golden, "\n")
+	renderedLines := strings.Split(rendered, "\n")
+
+	first := 0
+	for first < len(goldenLines) && first < len(renderedLines) && goldenLines[first] == renderedLines[first] {
+		first++
+	}
+
+	start := first - diffContextLines
+	if start < 0 {
+		start = 0
+	}
+
+	var sb strings.Builder
+	for i := start; i < first+diffContextLines; i++ {
+		switch {
+		case i < first:
+			fmt.Fprintf(&sb, "  %d: %s\n", i+1, goldenLines[i])
+		default:
+			if i < len(goldenLines) {
+				fmt.Fprintf(&sb, "- %d: %s\n", i+1, goldenLines[i])
+			}
+			if i < len(renderedLines) {
+				fmt.Fprintf(&sb, "+ %d: %s\n", i+1, renderedLines[i])
+			}
+		}
+	}
+	return sb.String()
+}
+
+// getResourceName returns the name of the given xDS resource
+func getResourceName(resource interface{}) string {
+	switch r := resource.(type) {
+	case interface{ GetName() string }:
+		return r.GetName()
+	case interface{ GetClusterName() string }:
+		return r.GetClusterName()
+	default:
+		return ""
+	}
+}
+
+// byName sorts rendered resources by their name, and then by their rendered configuration
+type byName struct {
+	resources []json.RawMessage
+	names     []string
+}
+
+func (b byName) Len() int {
+	return len(b.resources)
+}
+
+func (b byName) Less(i, j int) bool {
+	if b.names[i] != b.names[j] {
+		return b.names[i] < b.names[j]
+	}
+	return bytes.Compare(b.resources[i], b.resources[j]) < 0
+}
+
+func (b byName) Swap(i, j int) {
+	b.resources[i], b.resources[j] = b.resources[j], b.resources[i]
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
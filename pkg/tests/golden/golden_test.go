@@ -0,0 +1,136 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+// recordingT records the failures of a test instead of failing it
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Fatalf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func newResponse(t *testing.T, typeURI envoy.TypeURI, resources ...proto.Message) *xds_discovery.DiscoveryResponse {
+	response := &xds_discovery.DiscoveryResponse{TypeUrl: string(typeURI)}
+	for _, resource := range resources {
+		marshaled, err := ptypes.MarshalAny(resource)
+		tassert.Nil(t, err)
+		response.Resources = append(response.Resources, marshaled)
+	}
+	return response
+}
+
+func TestRenderResponses(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Resources are rendered sorted by name, regardless of the order in which they are generated
+	rendered, err := RenderResponses(
+		newResponse(t, envoy.TypeEDS, &xds_endpoint.ClusterLoadAssignment{ClusterName: "bookstore/bookstore"}),
+		newResponse(t, envoy.TypeCDS, &xds_cluster.Cluster{Name: "bookstore/bookstore-v2"}, &xds_cluster.Cluster{Name: "bookstore/bookstore"}),
+	)
+	assert.Nil(err)
+	Assert(t, filepath.Join("testdata", "render_responses.golden.json"), rendered)
+}
+
+func TestAssert(t *testing.T) {
+	assert := tassert.New(t)
+
+	goldenFile := filepath.Join(t.TempDir(), "proxy.golden.json")
+	rendered := []byte("{\n  \"CDS\": []\n}\n")
+
+	// A missing golden file fails the test
+	recorder := &recordingT{TB: t}
+	Assert(recorder, goldenFile, rendered)
+	assert.Len(recorder.failures, 1)
+
+	// The golden file is created with the -update-golden flag
+	*update = true
+	recorder = &recordingT{TB: t}
+	Assert(recorder, goldenFile, rendered)
+	*update = false
+	assert.Empty(recorder.failures)
+	golden, err := ioutil.ReadFile(filepath.Clean(goldenFile))
+	assert.Nil(err)
+	assert.Equal(rendered, golden)
+
+	// The same configuration matches the golden file
+	recorder = &recordingT{TB: t}
+	Assert(recorder, goldenFile, rendered)
+	assert.Empty(recorder.failures)
+
+	// A different configuration fails the test with the difference
+	recorder = &recordingT{TB: t}
+	Assert(recorder, goldenFile, []byte("{\n  \"CDS\": [],\n  \"EDS\": []\n}\n"))
+	assert.Len(recorder.failures, 1)
+	assert.Contains(recorder.failures[0], "- 2:   \"CDS\": []\n")
+	assert.Contains(recorder.failures[0], "+ 2:   \"CDS\": [],")
+}
+
+func TestDiff(t *testing.T) {
+	assert := tassert.New(t)
+
+	golden := "a\nb\nc\nd\n"
+	rendered := "a\nb\nx\nd\ne\n"
+	assert.Equal("  1: a\n  2: b\n- 3: c\n+ 3: x\n- 4: d\n+ 4: d\n- 5: \n+ 5: e\n+ 6: \n", diff(golden, rendered))
+}
+
+func TestRender(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := testclient.NewSimpleClientset()
+	meshCatalog := catalog.NewFakeMeshCatalog(kubeClient)
+
+	pod := tests.NewPodFixture(tests.Namespace, "bookstore-v1-pod", tests.BookstoreServiceAccountName, tests.PodLabels)
+	pod.Labels[constants.EnvoyUniqueIDLabelName] = tests.ProxyUUID
+	_, err := kubeClient.CoreV1().Pods(tests.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{})
+	assert.Nil(err)
+	svc := tests.NewServiceFixture(tests.BookstoreV1ServiceName, tests.Namespace, map[string]string{constants.EnvoyUniqueIDLabelName: tests.ProxyUUID})
+	_, err = kubeClient.CoreV1().Services(tests.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
+	assert.Nil(err)
+	svc = tests.NewServiceFixture(tests.BookstoreApexService.Name, tests.BookstoreApexService.Namespace, nil)
+	_, err = kubeClient.CoreV1().Services(tests.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
+	assert.Nil(err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	cfg := configurator.NewConfigurator(kubeClient, stop, "-test-osm-namespace-", "-test-osm-config-map-")
+	certManager := tresor.NewFakeCertManager(cfg)
+
+	certCommonName := certificate.CommonName(fmt.Sprintf("%s.%s.%s", tests.ProxyUUID, tests.BookstoreServiceAccountName, tests.Namespace))
+	proxy := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+
+	rendered, err := Render(meshCatalog, proxy, cfg, certManager, Handlers)
+	assert.Nil(err)
+	Assert(t, filepath.Join("testdata", "bookstore-v1.golden.json"), rendered)
+}
@@ -0,0 +1,306 @@
+package trafficmetrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/promquery"
+)
+
+const (
+	// requestTotalMetric is the metric of the sidecar proxies counting the requests sent by their workload, tagged with
+	// the response code and the workloads of the client and the server
+	requestTotalMetric = "osm_request_total"
+
+	// requestDurationMetric is the histogram of the duration of the requests sent by the workload of the sidecar
+	// proxies, tagged with the workloads of the client and the server
+	requestDurationMetric = "osm_request_duration_ms_bucket"
+
+	// sourceSide and destinationSide prefix the labels identifying the workloads of the client and the server
+	sourceSide      = "source"
+	destinationSide = "destination"
+
+	// unknownLabelValue is the value of the labels of a workload unknown to the proxy, such as the server of requests
+	// answered by the proxy of the client
+	unknownLabelValue = "unknown"
+)
+
+// trafficMetric is a metric of the SMI Traffic Metrics API, and the PromQL expression it is queried with.
+type trafficMetric struct {
+	name string
+	unit string
+
+	// expr returns the expression of the metric over the given window for the requests selected by the given label
+	// matchers, aggregated by the given labels
+	expr func(matchers, by, window string) string
+}
+
+// trafficMetrics are the metrics served for a resource or an edge
+var trafficMetrics = []trafficMetric{
+	{name: "p99_response_latency", unit: "ms", expr: latencyQuantileExpr(0.99)},
+	{name: "p90_response_latency", unit: "ms", expr: latencyQuantileExpr(0.9)},
+	{name: "p50_response_latency", unit: "ms", expr: latencyQuantileExpr(0.5)},
+	{name: "success_count", expr: requestCountExpr(`response_code!~"5.."`)},
+	{name: "failure_count", expr: requestCountExpr(`response_code=~"5.."`)},
+}
+
+func latencyQuantileExpr(quantile float64) func(matchers, by, window string) string {
+	return func(matchers, by, window string) string {
+		return fmt.Sprintf(`histogram_quantile(%g, sum by (le, %s) (rate(%s{%s}[%s])))`, quantile, by, requestDurationMetric, matchers, window)
+	}
+}
+
+func requestCountExpr(responseCodeMatcher string) func(matchers, by, window string) string {
+	return func(matchers, by, window string) string {
+		return fmt.Sprintf(`sum by (%s) (increase(%s{%s}[%s]))`, by, requestTotalMetric, joinMatchers(matchers, responseCodeMatcher), window)
+	}
+}
+
+// isCount returns true if the metric counts requests, in which case it is zero when no request was sent
+func (m trafficMetric) isCount() bool {
+	return m.unit == ""
+}
+
+// quantity returns the given value of the metric as a quantity, counts being rounded to integers as they are
+// extrapolated over the window by Prometheus
+func (m trafficMetric) quantity(value float64) *resource.Quantity {
+	if m.isCount() {
+		return resource.NewQuantity(int64(math.Round(value)), resource.DecimalSI)
+	}
+	return resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+}
+
+// resourceKind is a kind of resource the traffic metrics are served for.
+type resourceKind struct {
+	kind string
+
+	// nameLabel is the label naming a resource of the kind, prefixed by the side of the traffic. Namespaces are named
+	// by the namespace label.
+	nameLabel string
+
+	// matchKind is true if the resources of the kind are matched by the kind label, prefixed by the side of the traffic
+	matchKind bool
+}
+
+// resourceKinds are the kinds of the resources the traffic metrics are served for, keyed by resource name
+var resourceKinds = map[string]resourceKind{
+	"namespaces":   {kind: "Namespace"},
+	"pods":         {kind: "Pod", nameLabel: "pod"},
+	"deployments":  {kind: "Deployment", nameLabel: "name", matchKind: true},
+	"daemonsets":   {kind: "DaemonSet", nameLabel: "name", matchKind: true},
+	"statefulsets": {kind: "StatefulSet", nameLabel: "name", matchKind: true},
+}
+
+// namespaced returns true if the resources of the kind are namespaced
+func (k resourceKind) namespaced() bool {
+	return k.nameLabel != ""
+}
+
+// matchers returns the label matchers selecting the requests of the resources of the kind on the given side of the
+// traffic, restricted to the given namespace and name when they are not empty. The name of a namespace is its name.
+func (k resourceKind) matchers(side, namespace, name string) string {
+	if !k.namespaced() {
+		namespace = name
+	}
+
+	var matchers []string
+	if namespace != "" {
+		matchers = append(matchers, labelMatcher(side+"_namespace", "=", namespace))
+	} else {
+		matchers = append(matchers, labelMatcher(side+"_namespace", "!=", unknownLabelValue))
+	}
+	if k.matchKind {
+		matchers = append(matchers, labelMatcher(side+"_kind", "=", k.kind))
+	}
+	if k.namespaced() && name != "" {
+		matchers = append(matchers, labelMatcher(side+"_"+k.nameLabel, "=", name))
+	}
+	return joinMatchers(matchers...)
+}
+
+// labels returns the labels identifying a resource of the kind on the given side of the traffic
+func (k resourceKind) labels(side string) []string {
+	if !k.namespaced() {
+		return []string{side + "_namespace"}
+	}
+	return []string{side + "_namespace", side + "_" + k.nameLabel}
+}
+
+// reference returns the reference of the resource of the kind identified by the given labels on the given side of
+// the traffic
+func (k resourceKind) reference(side string, labels map[string]string) *corev1.ObjectReference {
+	if !k.namespaced() {
+		return &corev1.ObjectReference{Kind: k.kind, Name: labels[side+"_namespace"]}
+	}
+	return &corev1.ObjectReference{Kind: k.kind, Namespace: labels[side+"_namespace"], Name: labels[side+"_"+k.nameLabel]}
+}
+
+// labelMatcher returns a matcher of the given label and value. The proxies convert the '-' and '.' characters of the
+// label values of their metrics to '_', so the value is converted the same way.
+func labelMatcher(label, op, value string) string {
+	return fmt.Sprintf("%s%s%s", label, op, strconv.Quote(strings.NewReplacer("-", "_", ".", "_").Replace(value)))
+}
+
+func joinMatchers(matchers ...string) string {
+	var nonEmpty []string
+	for _, matcher := range matchers {
+		if matcher != "" {
+			nonEmpty = append(nonEmpty, matcher)
+		}
+	}
+	return strings.Join(nonEmpty, ",")
+}
+
+// metricsGroup is the set of metrics of the requests sharing the values of the labels they are aggregated by
+type metricsGroup struct {
+	labels map[string]string
+	values map[string]float64
+}
+
+// metrics returns the metrics of the group. Latencies are omitted if no request was sent over the window.
+func (g *metricsGroup) metrics() []*Metric {
+	var metrics []*Metric
+	for _, m := range trafficMetrics {
+		value, ok := g.values[m.name]
+		if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+			if !m.isCount() {
+				continue
+			}
+			value = 0
+		}
+		metrics = append(metrics, &Metric{Name: m.name, Unit: m.unit, Value: m.quantity(value)})
+	}
+	return metrics
+}
+
+// queryMetrics returns the metrics of the requests selected by the given label matchers, aggregated by the given labels,
+// sorted by the values of the labels
+func (s *Server) queryMetrics(metricsURL, matchers string, by []string) ([]*metricsGroup, error) {
+	groups := make(map[string]*metricsGroup)
+	window := fmt.Sprintf("%ds", int64(s.window.Seconds()))
+
+	for _, m := range trafficMetrics {
+		results, err := promquery.Query(s.client, metricsURL, m.expr(matchers, strings.Join(by, ", "), window))
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			var values []string
+			for _, label := range by {
+				values = append(values, result.Labels[label])
+			}
+			key := strings.Join(values, "/")
+			group, ok := groups[key]
+			if !ok {
+				group = &metricsGroup{labels: result.Labels, values: make(map[string]float64)}
+				groups[key] = group
+			}
+			group.values[m.name] = result.Value
+		}
+	}
+
+	var keys []string
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*metricsGroup, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, groups[key])
+	}
+	return sorted, nil
+}
+
+// newTrafficMetrics returns the given metrics of the given resource, or of its edge with a peer when the edge is set
+func (s *Server) newTrafficMetrics(ref *corev1.ObjectReference, edge *Edge, metrics []*Metric, now time.Time) *TrafficMetrics {
+	trafficMetrics := &TrafficMetrics{
+		Resource:  ref,
+		Edge:      edge,
+		Timestamp: metav1.NewTime(now),
+		Window:    metav1.Duration{Duration: s.window},
+		Metrics:   metrics,
+	}
+	trafficMetrics.Kind = trafficMetricsKind
+	trafficMetrics.APIVersion = APIGroup + "/" + APIVersion
+	trafficMetrics.Name = ref.Name
+	trafficMetrics.Namespace = ref.Namespace
+	trafficMetrics.CreationTimestamp = trafficMetrics.Timestamp
+	return trafficMetrics
+}
+
+// getResourceMetrics returns the metrics of the requests received by the given resource
+func (s *Server) getResourceMetrics(metricsURL string, kind resourceKind, namespace, name string) (*TrafficMetrics, error) {
+	groups, err := s.queryMetrics(metricsURL, kind.matchers(destinationSide, namespace, name), kind.labels(destinationSide))
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &corev1.ObjectReference{Kind: kind.kind, Namespace: namespace, Name: name}
+	group := &metricsGroup{}
+	if len(groups) > 0 {
+		group = groups[0]
+	}
+	return s.newTrafficMetrics(ref, nil, group.metrics(), time.Now()), nil
+}
+
+// listResourceMetrics returns the metrics of the requests received by the resources of the given kind in the given
+// namespace, or in all the namespaces if empty. The resources that did not receive any request are not listed.
+func (s *Server) listResourceMetrics(metricsURL string, kind resourceKind, namespace string) (*TrafficMetricsList, error) {
+	groups, err := s.queryMetrics(metricsURL, kind.matchers(destinationSide, namespace, ""), kind.labels(destinationSide))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	list := s.newTrafficMetricsList(&corev1.ObjectReference{Kind: kind.kind, Namespace: namespace})
+	for _, group := range groups {
+		list.Items = append(list.Items, s.newTrafficMetrics(kind.reference(destinationSide, group.labels), nil, group.metrics(), now))
+	}
+	return list, nil
+}
+
+// getEdgeMetrics returns the metrics of the requests exchanged between the given resource and its peers of the same
+// kind: the requests sent by the peers to the resource, and the requests sent by the resource to the peers
+func (s *Server) getEdgeMetrics(metricsURL string, kind resourceKind, namespace, name string) (*TrafficMetricsList, error) {
+	now := time.Now()
+	ref := &corev1.ObjectReference{Kind: kind.kind, Namespace: namespace, Name: name}
+	list := s.newTrafficMetricsList(ref)
+
+	for _, edge := range []struct {
+		direction Direction
+		side      string
+		peerSide  string
+	}{
+		{direction: DirectionFrom, side: destinationSide, peerSide: sourceSide},
+		{direction: DirectionTo, side: sourceSide, peerSide: destinationSide},
+	} {
+		matchers := joinMatchers(kind.matchers(edge.side, namespace, name), kind.matchers(edge.peerSide, "", ""))
+		groups, err := s.queryMetrics(metricsURL, matchers, kind.labels(edge.peerSide))
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			peer := &Edge{Direction: edge.direction, Resource: kind.reference(edge.peerSide, group.labels)}
+			list.Items = append(list.Items, s.newTrafficMetrics(ref, peer, group.metrics(), now))
+		}
+	}
+	return list, nil
+}
+
+func (s *Server) newTrafficMetricsList(ref *corev1.ObjectReference) *TrafficMetricsList {
+	list := &TrafficMetricsList{
+		Resource: ref,
+		Items:    []*TrafficMetrics{},
+	}
+	list.Kind = trafficMetricsListKind
+	list.APIVersion = APIGroup + "/" + APIVersion
+	return list
+}
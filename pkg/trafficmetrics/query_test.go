@@ -0,0 +1,183 @@
+package trafficmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newPrometheusServer returns a Prometheus server answering the queries of the given metrics with the given results,
+// and recording the queries it received
+func newPrometheusServer(t *testing.T, results map[string]string, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tassert.Equal(t, "/api/v1/query", r.URL.Path)
+		query := r.URL.Query().Get("query")
+		*queries = append(*queries, query)
+
+		result := ""
+		for metric, res := range results {
+			if strings.HasPrefix(query, metric) {
+				result = res
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, result)
+	}))
+}
+
+func TestResourceKindMatchers(t *testing.T) {
+	testCases := []struct {
+		name             string
+		resource         string
+		namespace        string
+		resourceName     string
+		expectedMatchers string
+	}{
+		{
+			name:             "all namespaces",
+			resource:         "namespaces",
+			expectedMatchers: `destination_namespace!="unknown"`,
+		},
+		{
+			name:             "namespace",
+			resource:         "namespaces",
+			resourceName:     "book-store",
+			expectedMatchers: `destination_namespace="book_store"`,
+		},
+		{
+			name:             "pods in namespace",
+			resource:         "pods",
+			namespace:        "bookstore",
+			expectedMatchers: `destination_namespace="bookstore"`,
+		},
+		{
+			name:             "pod",
+			resource:         "pods",
+			namespace:        "bookstore",
+			resourceName:     "bookstore-v1-5c7b4d8f9-x2x4z",
+			expectedMatchers: `destination_namespace="bookstore",destination_pod="bookstore_v1_5c7b4d8f9_x2x4z"`,
+		},
+		{
+			name:             "deployment",
+			resource:         "deployments",
+			namespace:        "bookstore",
+			resourceName:     "bookstore-v1",
+			expectedMatchers: `destination_namespace="bookstore",destination_kind="Deployment",destination_name="bookstore_v1"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedMatchers, resourceKinds[tc.resource].matchers(destinationSide, tc.namespace, tc.resourceName))
+		})
+	}
+}
+
+func TestGetResourceMetrics(t *testing.T) {
+	assert := tassert.New(t)
+
+	var queries []string
+	prometheus := newPrometheusServer(t, map[string]string{
+		"histogram_quantile(0.99": `{"metric":{"destination_namespace":"bookstore","destination_name":"bookstore_v1"},"value":[1614556800,"12.5"]}`,
+		"histogram_quantile(0.5":  `{"metric":{"destination_namespace":"bookstore","destination_name":"bookstore_v1"},"value":[1614556800,"NaN"]}`,
+		"sum by":                  `{"metric":{"destination_namespace":"bookstore","destination_name":"bookstore_v1"},"value":[1614556800,"41.8"]}`,
+	}, &queries)
+	defer prometheus.Close()
+
+	s := NewServer(nil)
+	metrics, err := s.getResourceMetrics(prometheus.URL, resourceKinds["deployments"], "bookstore", "bookstore-v1")
+	assert.Nil(err)
+
+	assert.Len(queries, len(trafficMetrics))
+	matchers := `destination_namespace="bookstore",destination_kind="Deployment",destination_name="bookstore_v1"`
+	assert.Equal(`histogram_quantile(0.99, sum by (le, destination_namespace, destination_name) (rate(osm_request_duration_ms_bucket{`+matchers+`}[30s])))`, queries[0])
+	assert.Equal(`sum by (destination_namespace, destination_name) (increase(osm_request_total{`+matchers+`,response_code!~"5.."}[30s]))`, queries[3])
+	assert.Equal(`sum by (destination_namespace, destination_name) (increase(osm_request_total{`+matchers+`,response_code=~"5.."}[30s]))`, queries[4])
+
+	assert.Equal(trafficMetricsKind, metrics.Kind)
+	assert.Equal("metrics.smi-spec.io/v1alpha1", metrics.APIVersion)
+	assert.Equal(&corev1.ObjectReference{Kind: "Deployment", Namespace: "bookstore", Name: "bookstore-v1"}, metrics.Resource)
+	assert.Nil(metrics.Edge)
+	assert.Equal(defaultWindow, metrics.Window.Duration)
+
+	// The p50 latency is not a number and the p90 latency has no result, so both are omitted
+	assert.Len(metrics.Metrics, 3)
+	assert.Equal("p99_response_latency", metrics.Metrics[0].Name)
+	assert.Equal("ms", metrics.Metrics[0].Unit)
+	assert.Equal("12500m", metrics.Metrics[0].Value.String())
+	assert.Equal("success_count", metrics.Metrics[1].Name)
+	assert.Equal("42", metrics.Metrics[1].Value.String())
+	assert.Equal("failure_count", metrics.Metrics[2].Name)
+	assert.Equal("42", metrics.Metrics[2].Value.String())
+}
+
+func TestListResourceMetrics(t *testing.T) {
+	assert := tassert.New(t)
+
+	var queries []string
+	prometheus := newPrometheusServer(t, map[string]string{
+		"sum by": `{"metric":{"destination_namespace":"bookstore","destination_pod":"bookstore_v2"},"value":[1614556800,"3"]},` +
+			`{"metric":{"destination_namespace":"bookstore","destination_pod":"bookstore_v1"},"value":[1614556800,"5"]}`,
+	}, &queries)
+	defer prometheus.Close()
+
+	s := NewServer(nil)
+	list, err := s.listResourceMetrics(prometheus.URL, resourceKinds["pods"], "bookstore")
+	assert.Nil(err)
+
+	assert.Equal(trafficMetricsListKind, list.Kind)
+	assert.Equal(&corev1.ObjectReference{Kind: "Pod", Namespace: "bookstore"}, list.Resource)
+	assert.Len(list.Items, 2)
+	assert.Equal("bookstore_v1", list.Items[0].Name)
+	assert.Equal("bookstore_v2", list.Items[1].Name)
+
+	// Counts without a result are zero
+	assert.Equal("success_count", list.Items[0].Metrics[0].Name)
+	assert.Equal("5", list.Items[0].Metrics[0].Value.String())
+}
+
+func TestGetEdgeMetrics(t *testing.T) {
+	assert := tassert.New(t)
+
+	var queries []string
+	prometheus := newPrometheusServer(t, map[string]string{
+		"sum by (source_namespace":      `{"metric":{"source_namespace":"bookbuyer"},"value":[1614556800,"10"]}`,
+		"sum by (destination_namespace": `{"metric":{"destination_namespace":"bookwarehouse"},"value":[1614556800,"4"]}`,
+	}, &queries)
+	defer prometheus.Close()
+
+	s := NewServer(nil)
+	list, err := s.getEdgeMetrics(prometheus.URL, resourceKinds["namespaces"], "", "bookstore")
+	assert.Nil(err)
+
+	assert.Contains(queries, `sum by (source_namespace) (increase(osm_request_total{destination_namespace="bookstore",source_namespace!="unknown",response_code!~"5.."}[30s]))`)
+	assert.Contains(queries, `sum by (destination_namespace) (increase(osm_request_total{source_namespace="bookstore",destination_namespace!="unknown",response_code!~"5.."}[30s]))`)
+
+	assert.Len(list.Items, 2)
+	assert.Equal(DirectionFrom, list.Items[0].Edge.Direction)
+	assert.Equal(&corev1.ObjectReference{Kind: "Namespace", Name: "bookbuyer"}, list.Items[0].Edge.Resource)
+	assert.Equal(DirectionTo, list.Items[1].Edge.Direction)
+	assert.Equal(&corev1.ObjectReference{Kind: "Namespace", Name: "bookwarehouse"}, list.Items[1].Edge.Resource)
+	for _, item := range list.Items {
+		assert.Equal("bookstore", item.Resource.Name)
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	assert := tassert.New(t)
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"invalid query"}`)
+	}))
+	defer prometheus.Close()
+
+	s := NewServer(nil)
+	_, err := s.getResourceMetrics(prometheus.URL, resourceKinds["pods"], "bookstore", "bookstore-v1")
+	assert.NotNil(err)
+}
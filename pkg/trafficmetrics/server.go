@@ -0,0 +1,115 @@
+package trafficmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/aggregatedapi"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// apiPath is the path of the served version of the SMI Traffic Metrics API
+	apiPath = "/apis/" + APIGroup + "/" + APIVersion
+
+	// edgesSubresource is the subresource of a resource listing the metrics of its edges
+	edgesSubresource = "edges"
+)
+
+// NewServer returns a server of the SMI Traffic Metrics API querying the metrics from the Prometheus server configured in
+// the OSM ConfigMap.
+func NewServer(cfg configurator.Configurator) *Server {
+	return &Server{
+		cfg:    cfg,
+		client: &http.Client{Timeout: metricsQueryTimeout},
+		window: defaultWindow,
+	}
+}
+
+// Start serves the SMI Traffic Metrics API over TLS until the stop channel is closed, and registers the CA of the
+// certificate of the server with the APIService of the API.
+func (s *Server) Start(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, certManager certificate.Manager, osmNamespace string, stop <-chan struct{}) error {
+	return aggregatedapi.NewServer("SMI Traffic Metrics API", APIServiceName, constants.OSMTrafficMetricsPort, s).Start(kubeClient, dynamicClient, certManager, osmNamespace, stop)
+}
+
+// ServeHTTP serves the GET requests for the SMI Traffic Metrics API proxied by the Kubernetes API server:
+// 1. /apis/metrics.smi-spec.io/v1alpha1 lists the served resources
+// 2. /apis/metrics.smi-spec.io/v1alpha1/namespaces[/<namespace>[/edges]] serves the metrics of namespaces
+// 3. /apis/metrics.smi-spec.io/v1alpha1/namespaces/<namespace>/<resource>[/<name>[/edges]] serves the other resources
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if path == apiPath {
+		aggregatedapi.WriteJSON(w, getAPIResourceList())
+		return
+	}
+	if !strings.HasPrefix(path, apiPath+"/") {
+		aggregatedapi.WriteStatus(w, http.StatusNotFound, fmt.Sprintf("Path %s not found", req.URL.Path))
+		return
+	}
+
+	metricsURL := s.cfg.GetPolicyUsageMetricsURL()
+	if metricsURL == "" {
+		aggregatedapi.WriteStatus(w, http.StatusServiceUnavailable, "The URL of the Prometheus server the metrics are queried from is not configured in the OSM ConfigMap")
+		return
+	}
+
+	var result interface{}
+	var err error
+	namespaces := resourceKinds["namespaces"]
+	switch parts := strings.Split(strings.TrimPrefix(path, apiPath+"/"), "/"); {
+	case len(parts) == 1 && parts[0] == "namespaces":
+		result, err = s.listResourceMetrics(metricsURL, namespaces, "")
+
+	case len(parts) == 2 && parts[0] == "namespaces":
+		result, err = s.getResourceMetrics(metricsURL, namespaces, "", parts[1])
+
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[2] == edgesSubresource:
+		result, err = s.getEdgeMetrics(metricsURL, namespaces, "", parts[1])
+
+	case len(parts) >= 3 && len(parts) <= 5 && parts[0] == "namespaces":
+		kind, ok := resourceKinds[parts[2]]
+		if !ok || !kind.namespaced() || (len(parts) == 5 && parts[4] != edgesSubresource) {
+			aggregatedapi.WriteStatus(w, http.StatusNotFound, fmt.Sprintf("Path %s not found", req.URL.Path))
+			return
+		}
+		switch len(parts) {
+		case 3:
+			result, err = s.listResourceMetrics(metricsURL, kind, parts[1])
+		case 4:
+			result, err = s.getResourceMetrics(metricsURL, kind, parts[1], parts[3])
+		default:
+			result, err = s.getEdgeMetrics(metricsURL, kind, parts[1], parts[3])
+		}
+
+	default:
+		aggregatedapi.WriteStatus(w, http.StatusNotFound, fmt.Sprintf("Path %s not found", req.URL.Path))
+		return
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msgf("Error querying traffic metrics for %s", req.URL.Path)
+		aggregatedapi.WriteStatus(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	aggregatedapi.WriteJSON(w, result)
+}
+
+// getAPIResourceList returns the resources served by the SMI Traffic Metrics API, for the discovery of the API
+func getAPIResourceList() *metav1.APIResourceList {
+	var resources []metav1.APIResource
+	for _, name := range []string{"daemonsets", "deployments", "namespaces", "pods", "statefulsets"} {
+		kind := resourceKinds[name]
+		resources = append(resources,
+			metav1.APIResource{Name: name, Namespaced: kind.namespaced(), Kind: trafficMetricsKind, Verbs: metav1.Verbs{"get", "list"}},
+			metav1.APIResource{Name: name + "/" + edgesSubresource, Namespaced: kind.namespaced(), Kind: trafficMetricsListKind, Verbs: metav1.Verbs{"get"}},
+		)
+	}
+	return aggregatedapi.NewAPIResourceList(APIGroup+"/"+APIVersion, resources...)
+}
@@ -0,0 +1,130 @@
+package trafficmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func TestServeHTTP(t *testing.T) {
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer prometheus.Close()
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		metricsURL     string
+		expectedStatus int
+		expectedKind   string
+	}{
+		{
+			name:           "discovery",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1",
+			expectedStatus: http.StatusOK,
+			expectedKind:   "APIResourceList",
+		},
+		{
+			name:           "namespaces",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsListKind,
+		},
+		{
+			name:           "namespace",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsKind,
+		},
+		{
+			name:           "namespace edges",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/edges",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsListKind,
+		},
+		{
+			name:           "deployments",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/deployments",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsListKind,
+		},
+		{
+			name:           "pod",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/pods/bookstore-v1/",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsKind,
+		},
+		{
+			name:           "statefulset edges",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/statefulsets/mysql/edges",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusOK,
+			expectedKind:   trafficMetricsListKind,
+		},
+		{
+			name:           "unknown resource",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/services/bookstore",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "unknown subresource",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces/bookstore/pods/bookstore-v1/logs",
+			metricsURL:     prometheus.URL,
+			expectedStatus: http.StatusNotFound,
+			expectedKind:   "Status",
+		},
+		{
+			name:           "Prometheus not configured",
+			method:         http.MethodGet,
+			path:           "/apis/metrics.smi-spec.io/v1alpha1/namespaces",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedKind:   "Status",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetPolicyUsageMetricsURL().Return(tc.metricsURL).AnyTimes()
+
+			s := NewServer(mockConfigurator)
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(tc.expectedStatus, w.Code)
+			var typeMeta metav1.TypeMeta
+			assert.Nil(json.Unmarshal(w.Body.Bytes(), &typeMeta))
+			assert.Equal(tc.expectedKind, typeMeta.Kind)
+		})
+	}
+}
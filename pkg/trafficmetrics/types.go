@@ -0,0 +1,106 @@
+// Package trafficmetrics implements the SMI Traffic Metrics API (metrics.smi-spec.io/v1alpha1), served by osm-controller
+// as an aggregated API of the Kubernetes API server. The metrics of the traffic between the workloads of the mesh are
+// queried from the Prometheus server scraping the request metrics recorded by the sidecar proxies.
+package trafficmetrics
+
+import (
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("traffic-metrics")
+
+const (
+	// APIGroup is the API group of the SMI Traffic Metrics API
+	APIGroup = "metrics.smi-spec.io"
+
+	// APIVersion is the version of the SMI Traffic Metrics API served by OSM
+	APIVersion = "v1alpha1"
+
+	// APIServiceName is the name of the APIService registering the SMI Traffic Metrics API with the Kubernetes API server
+	APIServiceName = APIVersion + "." + APIGroup
+
+	// defaultWindow is the window over which the metrics are computed
+	defaultWindow = 30 * time.Second
+
+	// metricsQueryTimeout is the timeout of a query to the Prometheus server
+	metricsQueryTimeout = 10 * time.Second
+
+	trafficMetricsKind     = "TrafficMetrics"
+	trafficMetricsListKind = "TrafficMetricsList"
+)
+
+// Direction is the direction of the traffic on an edge, relative to the resource of the metrics
+type Direction string
+
+const (
+	// DirectionFrom is the direction of the traffic sent by the peer of an edge to the resource
+	DirectionFrom Direction = "from"
+
+	// DirectionTo is the direction of the traffic sent by the resource to the peer of an edge
+	DirectionTo Direction = "to"
+)
+
+// TrafficMetrics are the metrics of the traffic received by a resource, or exchanged between a resource and a peer
+// when an edge is set.
+type TrafficMetrics struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Resource is the resource the metrics are for
+	Resource *corev1.ObjectReference `json:"resource"`
+
+	// Edge is the edge between the resource and a peer the metrics are for, nil for the metrics of the resource
+	Edge *Edge `json:"edge"`
+
+	// Timestamp is the time at which the metrics were computed
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Window is the window over which the metrics were computed
+	Window metav1.Duration `json:"window"`
+
+	// Metrics are the metrics of the traffic
+	Metrics []*Metric `json:"metrics"`
+}
+
+// Edge is an edge of the traffic graph between a resource and a peer.
+type Edge struct {
+	// Direction is the direction of the traffic, relative to the resource
+	Direction Direction `json:"direction"`
+
+	// Resource is the peer of the resource
+	Resource *corev1.ObjectReference `json:"resource"`
+}
+
+// Metric is a metric of the traffic.
+type Metric struct {
+	Name  string             `json:"name"`
+	Unit  string             `json:"unit,omitempty"`
+	Value *resource.Quantity `json:"value"`
+}
+
+// TrafficMetricsList is a list of TrafficMetrics.
+type TrafficMetricsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Resource is the resource the list is for: a kind of resource for the metrics of the resources of the kind, or a
+	// resource for the metrics of its edges
+	Resource *corev1.ObjectReference `json:"resource"`
+
+	Items []*TrafficMetrics `json:"items"`
+}
+
+// Server serves the SMI Traffic Metrics API.
+type Server struct {
+	cfg    configurator.Configurator
+	client *http.Client
+	window time.Duration
+}
@@ -0,0 +1,239 @@
+package trafficpolicy
+
+import (
+	"reflect"
+	"sort"
+)
+
+// PolicySource is the kind of configuration inbound and outbound traffic policies are built from. The sources are
+// ordered by increasing precedence: when the policies of several sources route the same requests differently, the
+// routes of the source of highest precedence are kept.
+type PolicySource int
+
+const (
+	// PermissiveSource is the source of the policies built from service discovery in permissive traffic policy mode
+	PermissiveSource PolicySource = iota
+
+	// TrafficTargetSource is the source of the policies built from SMI TrafficTargets
+	TrafficTargetSource
+
+	// TrafficSplitSource is the source of the policies built from SMI TrafficSplits, whose weighted clusters override
+	// the ones of the services they split the traffic of
+	TrafficSplitSource
+
+	// IngressSource is the source of the policies built from Ingress resources. Their hostnames may only be a subset of
+	// the hostnames of the service they route to, as they are the hosts of the requests received from ingress.
+	IngressSource
+
+	// EgressSource is the source of the outbound policies built from Egress resources, routing the requests for the
+	// external hosts they allow to the clusters of the hosts
+	EgressSource
+)
+
+// InboundPolicySet is the normalized representation of the inbound traffic policies of a proxy, compiled from the
+// policies built from each of their sources. The policies added to the set are never modified, and the policies
+// compiled from the set are the same regardless of the order their sources are added in:
+//  1. the policies of a source are merged into the policies of the same source or of a source of lower precedence with
+//     the same hostnames, the service accounts allowed to access the same route being combined
+//  2. the policies of IngressSource are also merged into the policies of the sources of lower precedence whose
+//     hostnames include their hostnames
+//  3. the rules of the compiled policies are ordered by the precedence of their source, then by the order they were added in
+type InboundPolicySet struct {
+	policies []inboundSourcePolicy
+}
+
+type inboundSourcePolicy struct {
+	source PolicySource
+	policy *InboundTrafficPolicy
+}
+
+// OutboundPolicySet is the normalized representation of the outbound traffic policies of a proxy, compiled from the
+// policies built from each of their sources. The policies added to the set are never modified, and the policies
+// compiled from the set are the same regardless of the order their sources are added in:
+//  1. the policies with the same hostnames are merged, with one route per HTTP route match
+//  2. the weighted clusters of a route are the ones of the source of highest precedence routing its HTTP route match,
+//     or of the policy added last for the sources of the same precedence
+type OutboundPolicySet struct {
+	policies []outboundSourcePolicy
+}
+
+type outboundSourcePolicy struct {
+	source PolicySource
+	policy *OutboundTrafficPolicy
+}
+
+// NewInboundPolicySet returns an empty *InboundPolicySet
+func NewInboundPolicySet() *InboundPolicySet {
+	return &InboundPolicySet{}
+}
+
+// Add adds the given inbound traffic policies built from the given source to the set
+func (s *InboundPolicySet) Add(source PolicySource, policies ...*InboundTrafficPolicy) {
+	for _, policy := range policies {
+		s.policies = append(s.policies, inboundSourcePolicy{source: source, policy: policy})
+	}
+}
+
+// Policies returns the inbound traffic policies compiled from the set
+func (s *InboundPolicySet) Policies() []*InboundTrafficPolicy {
+	sourcePolicies := make([]inboundSourcePolicy, len(s.policies))
+	copy(sourcePolicies, s.policies)
+	sort.SliceStable(sourcePolicies, func(i, j int) bool {
+		return sourcePolicies[i].source < sourcePolicies[j].source
+	})
+
+	compiled := []*InboundTrafficPolicy{}
+	var compiledSources []PolicySource
+	for _, latest := range sourcePolicies {
+		merged := false
+		for i, policy := range compiled {
+			partialHostnamesMatch := latest.source == IngressSource && compiledSources[i] != IngressSource
+			if sameHostnames(policy.Hostnames, latest.policy.Hostnames) || (partialHostnamesMatch && subset(policy.Hostnames, latest.policy.Hostnames)) {
+				policy.Rules = mergeRules(policy.Rules, latest.policy.Rules)
+				merged = true
+			}
+		}
+		if !merged {
+			compiled = append(compiled, latest.policy.clone())
+			compiledSources = append(compiledSources, latest.source)
+		}
+	}
+	return compiled
+}
+
+// NewOutboundPolicySet returns an empty *OutboundPolicySet
+func NewOutboundPolicySet() *OutboundPolicySet {
+	return &OutboundPolicySet{}
+}
+
+// Add adds the given outbound traffic policies built from the given source to the set
+func (s *OutboundPolicySet) Add(source PolicySource, policies ...*OutboundTrafficPolicy) {
+	for _, policy := range policies {
+		s.policies = append(s.policies, outboundSourcePolicy{source: source, policy: policy})
+	}
+}
+
+// Policies returns the outbound traffic policies compiled from the set
+func (s *OutboundPolicySet) Policies() []*OutboundTrafficPolicy {
+	sourcePolicies := make([]outboundSourcePolicy, len(s.policies))
+	copy(sourcePolicies, s.policies)
+	sort.SliceStable(sourcePolicies, func(i, j int) bool {
+		return sourcePolicies[i].source < sourcePolicies[j].source
+	})
+
+	compiled := []*OutboundTrafficPolicy{}
+	for _, latest := range sourcePolicies {
+		merged := false
+		for _, policy := range compiled {
+			if sameHostnames(policy.Hostnames, latest.policy.Hostnames) {
+				policy.Routes = mergeRoutesWeightedClusters(policy.Routes, latest.policy.Routes)
+				merged = true
+			}
+		}
+		if !merged {
+			compiled = append(compiled, latest.policy.clone())
+		}
+	}
+	return compiled
+}
+
+// mergeRules merges the given slices of rules such that there is one Rule for a Route with all allowed service accounts
+// listed in the returned slice of rules. The latest rules are not modified.
+func mergeRules(originalRules, latestRules []*Rule) []*Rule {
+	for _, latest := range latestRules {
+		foundRoute := false
+		for _, original := range originalRules {
+			if reflect.DeepEqual(latest.Route, original.Route) {
+				foundRoute = true
+				if original.ShadowServiceAccounts != nil || latest.ShadowServiceAccounts != nil {
+					original.ShadowServiceAccounts = original.GetEnforcedServiceAccounts().Union(latest.GetEnforcedServiceAccounts())
+				}
+				original.AllowedServiceAccounts = original.AllowedServiceAccounts.Union(latest.AllowedServiceAccounts)
+				break
+			}
+		}
+		if !foundRoute {
+			originalRules = append(originalRules, latest.clone())
+		}
+	}
+	return originalRules
+}
+
+// mergeRoutesWeightedClusters merges two slices of RouteWeightedClusters and returns a slice where there is one RouteWeightedCluster
+// for any HTTPRouteMatch. Where there is an overlap in HTTPRouteMatch between the originalRoutes and latestRoutes, the WeightedClusters
+// specified in the latestRoutes will be kept since there can only be one set of WeightedClusters per HTTPRouteMatch.
+// The latest routes are not modified.
+func mergeRoutesWeightedClusters(originalRoutes, latestRoutes []*RouteWeightedClusters) []*RouteWeightedClusters {
+	for _, latest := range latestRoutes {
+		foundRoute := false
+		for _, original := range originalRoutes {
+			if reflect.DeepEqual(original.HTTPRouteMatch, latest.HTTPRouteMatch) {
+				foundRoute = true
+				original.WeightedClusters = latest.WeightedClusters
+				break
+			}
+		}
+		if !foundRoute {
+			route := *latest
+			originalRoutes = append(originalRoutes, &route)
+		}
+	}
+	return originalRoutes
+}
+
+// clone returns a copy of the inbound traffic policy whose rules can be merged without modifying the policy
+func (in *InboundTrafficPolicy) clone() *InboundTrafficPolicy {
+	clone := &InboundTrafficPolicy{
+		Name:      in.Name,
+		Hostnames: cloneHostnames(in.Hostnames),
+	}
+	if in.Rules != nil {
+		clone.Rules = make([]*Rule, 0, len(in.Rules))
+	}
+	for _, rule := range in.Rules {
+		clone.Rules = append(clone.Rules, rule.clone())
+	}
+	return clone
+}
+
+// clone returns a copy of the outbound traffic policy whose routes can be merged without modifying the policy
+func (out *OutboundTrafficPolicy) clone() *OutboundTrafficPolicy {
+	clone := &OutboundTrafficPolicy{
+		Name:      out.Name,
+		Hostnames: cloneHostnames(out.Hostnames),
+	}
+	if out.Routes != nil {
+		clone.Routes = make([]*RouteWeightedClusters, 0, len(out.Routes))
+	}
+	for _, route := range out.Routes {
+		routeClone := *route
+		clone.Routes = append(clone.Routes, &routeClone)
+	}
+	return clone
+}
+
+// clone returns a copy of the rule whose service accounts can be merged without modifying the rule
+func (r *Rule) clone() *Rule {
+	clone := &Rule{Route: r.Route}
+	if r.AllowedServiceAccounts != nil {
+		clone.AllowedServiceAccounts = r.AllowedServiceAccounts.Clone()
+	}
+	if r.ShadowServiceAccounts != nil {
+		clone.ShadowServiceAccounts = r.ShadowServiceAccounts.Clone()
+	}
+	return clone
+}
+
+func cloneHostnames(hostnames []string) []string {
+	if hostnames == nil {
+		return nil
+	}
+	clone := make([]string, len(hostnames))
+	copy(clone, hostnames)
+	return clone
+}
+
+// sameHostnames returns true if the given hostnames are the same, regardless of their order
+func sameHostnames(first, second []string) bool {
+	return len(first) == len(second) && subset(first, second) && subset(second, first)
+}
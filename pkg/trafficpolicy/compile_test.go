@@ -0,0 +1,595 @@
+package trafficpolicy
+
+import (
+	"testing"
+
+	set "github.com/deckarep/golang-set"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestInboundPolicySet(t *testing.T) {
+	assert := tassert.New(t)
+
+	testRule1 := Rule{
+		Route:                  testRoute,
+		AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+	}
+	testRule2 := Rule{
+		Route:                  testRoute2,
+		AllowedServiceAccounts: set.NewSet(testServiceAccount2),
+	}
+	testRule1Modified := Rule{
+		Route: RouteWeightedClusters{
+			HTTPRouteMatch: HTTPRouteMatch{
+				Path:          "/hello",
+				PathMatchType: PathMatchRegex,
+				Methods:       []string{"*"},
+			},
+			WeightedClusters: set.NewSet(testWeightedCluster),
+		},
+		AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+	}
+	testCases := []struct {
+		name            string
+		originalInbound []*InboundTrafficPolicy
+		latestSource    PolicySource
+		latestInbound   []*InboundTrafficPolicy
+		expectedInbound []*InboundTrafficPolicy
+	}{
+		{
+			name: "hostnames match",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames match in a different order",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1},
+				},
+			},
+			latestSource: TrafficSplitSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: []string{testHostnames[2], testHostnames[1], testHostnames[0]},
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames do not match",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames2,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+				{
+					Hostnames: testHostnames2,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames are a subset",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: []string{"testHostname1"},
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1},
+				},
+				{
+					Hostnames: []string{"testHostname1"},
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames in ingress match",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: IngressSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames in ingress is a subset",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: IngressSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: []string{"testHostname1"},
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+		},
+		{
+			name: "hostnames in ingress is a subset but rules differ",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: IngressSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: []string{"testHostname1"},
+					Rules:     []*Rule{&testRule1Modified},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2, &testRule1Modified},
+				},
+			},
+		},
+		{
+			name: "hostnames in ingress do not match",
+			originalInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+			},
+			latestSource: IngressSource,
+			latestInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames2,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+			expectedInbound: []*InboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Rules:     []*Rule{&testRule1, &testRule2},
+				},
+				{
+					Hostnames: testHostnames2,
+					Rules:     []*Rule{&testRule2},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policies := NewInboundPolicySet()
+			policies.Add(TrafficTargetSource, tc.originalInbound...)
+			policies.Add(tc.latestSource, tc.latestInbound...)
+			assert.Equal(tc.expectedInbound, policies.Policies())
+
+			// The compiled policies do not depend on the order the sources are added in
+			policies = NewInboundPolicySet()
+			policies.Add(tc.latestSource, tc.latestInbound...)
+			policies.Add(TrafficTargetSource, tc.originalInbound...)
+			if tc.latestSource != TrafficTargetSource {
+				assert.Equal(tc.expectedInbound, policies.Policies())
+			}
+		})
+	}
+}
+
+func TestInboundPolicySetDoesNotModifyPolicies(t *testing.T) {
+	assert := tassert.New(t)
+
+	servicePolicies := []*InboundTrafficPolicy{
+		{
+			Hostnames: testHostnames,
+			Rules: []*Rule{{
+				Route:                  testRoute,
+				AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+			}},
+		},
+		{
+			Hostnames: []string{"testHostname1", "testHostname2"},
+			Rules: []*Rule{{
+				Route:                  testRoute,
+				AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+			}},
+		},
+	}
+	ingressPolicy := &InboundTrafficPolicy{
+		Hostnames: []string{"testHostname1"},
+		Rules: []*Rule{{
+			Route:                  testRoute2,
+			AllowedServiceAccounts: set.NewSet(wildcardServiceAccount),
+		}},
+	}
+
+	policies := NewInboundPolicySet()
+	policies.Add(TrafficTargetSource, servicePolicies...)
+	policies.Add(IngressSource, ingressPolicy)
+	compiled := policies.Policies()
+	assert.Len(compiled, 2)
+
+	// The ingress rule is merged into the policies of both services, each with its own copy of the rule
+	assert.Len(compiled[0].Rules, 2)
+	assert.Len(compiled[1].Rules, 2)
+	assert.NotSame(compiled[0].Rules[1], compiled[1].Rules[1])
+	compiled[0].Rules[1].AllowedServiceAccounts.Add(testServiceAccount2)
+	assert.False(compiled[1].Rules[1].AllowedServiceAccounts.Contains(testServiceAccount2))
+
+	// The policies added to the set are left untouched
+	assert.Len(servicePolicies[0].Rules, 1)
+	assert.Len(servicePolicies[1].Rules, 1)
+	assert.Equal(set.NewSet(wildcardServiceAccount), ingressPolicy.Rules[0].AllowedServiceAccounts)
+}
+
+func TestMergeRules(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		name          string
+		originalRules []*Rule
+		newRules      []*Rule
+		expectedRules []*Rule
+	}{
+		{
+			name: "routes match",
+			originalRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			newRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount2),
+				},
+			},
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1, testServiceAccount2),
+				},
+			},
+		},
+		{
+			name: "routes match but with duplicate allowed service accounts",
+			originalRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			newRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
+				},
+			},
+		},
+		{
+			name: "routes match and one of the rules is in shadow mode",
+			originalRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			newRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount2, wildcardServiceAccount),
+					ShadowServiceAccounts:  set.NewSet(testServiceAccount2),
+				},
+			},
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1, testServiceAccount2, wildcardServiceAccount),
+					ShadowServiceAccounts:  set.NewSetWith(testServiceAccount1, testServiceAccount2),
+				},
+			},
+		},
+		{
+			name: "routes don't match, add rule",
+			originalRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			newRules: []*Rule{
+				{
+					Route:                  testRoute2,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
+				},
+				{
+					Route:                  testRoute2,
+					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := mergeRules(tc.originalRules, tc.newRules)
+			assert.ElementsMatch(tc.expectedRules, actual)
+		})
+	}
+}
+
+func TestOutboundPolicySet(t *testing.T) {
+	assert := tassert.New(t)
+
+	testRouteWithWeightedCluster2 := RouteWeightedClusters{
+		HTTPRouteMatch:   testHTTPRouteMatch,
+		WeightedClusters: set.NewSet(testWeightedCluster2),
+	}
+	testCases := []struct {
+		name                             string
+		originalSource, latestSource     PolicySource
+		originalPolicies, latestPolicies []*OutboundTrafficPolicy
+		expectedPolicies                 []*OutboundTrafficPolicy
+	}{
+		{
+			name:           "hostnames don't match",
+			originalSource: TrafficTargetSource,
+			originalPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames2,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			expectedPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+				{
+					Hostnames: testHostnames2,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+		},
+		{
+			name:           "hostnames match",
+			originalSource: TrafficTargetSource,
+			originalPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute2},
+				},
+			},
+			expectedPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute, &testRoute2},
+				},
+			},
+		},
+		{
+			name:           "hostnames match, routes match",
+			originalSource: TrafficTargetSource,
+			originalPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			expectedPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+		},
+		{
+			name:           "hostnames match, routes have same match conditions but diff weighted clusters, apply latest weighted clusters",
+			originalSource: TrafficTargetSource,
+			originalPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRouteWithWeightedCluster2},
+				},
+			},
+			expectedPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRouteWithWeightedCluster2},
+				},
+			},
+		},
+		{
+			name:           "routes have same match conditions but diff weighted clusters, apply weighted clusters of source of highest precedence",
+			originalSource: TrafficSplitSource,
+			originalPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRouteWithWeightedCluster2},
+				},
+			},
+			latestSource: TrafficTargetSource,
+			latestPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRoute},
+				},
+			},
+			expectedPolicies: []*OutboundTrafficPolicy{
+				{
+					Hostnames: testHostnames,
+					Routes:    []*RouteWeightedClusters{&testRouteWithWeightedCluster2},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policies := NewOutboundPolicySet()
+			policies.Add(tc.originalSource, tc.originalPolicies...)
+			policies.Add(tc.latestSource, tc.latestPolicies...)
+			assert.ElementsMatch(tc.expectedPolicies, policies.Policies())
+		})
+	}
+
+	// The policies added to the set are left untouched
+	assert.Equal(set.NewSet(testWeightedCluster), testRoute.WeightedClusters)
+}
+
+func TestMergeRouteWeightedClusters(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		name                                         string
+		originalRoutes, latestRoutes, expectedRoutes []*RouteWeightedClusters
+	}{
+		{
+			name:           "merge routes with different match conditions",
+			originalRoutes: []*RouteWeightedClusters{{HTTPRouteMatch: testHTTPRouteMatch, WeightedClusters: set.NewSet(testWeightedCluster)}},
+			latestRoutes:   []*RouteWeightedClusters{&testRoute2},
+			expectedRoutes: []*RouteWeightedClusters{&testRoute, &testRoute2},
+		},
+		{
+			name:           "collapse routes with same match conditions and weighted clusters",
+			originalRoutes: []*RouteWeightedClusters{{HTTPRouteMatch: testHTTPRouteMatch, WeightedClusters: set.NewSet(testWeightedCluster)}},
+			latestRoutes:   []*RouteWeightedClusters{&testRoute},
+			expectedRoutes: []*RouteWeightedClusters{&testRoute},
+		},
+		{
+			name:           "routes have same match conditions but different weighted clusters, apply latest weighted clusters",
+			originalRoutes: []*RouteWeightedClusters{{HTTPRouteMatch: testHTTPRouteMatch, WeightedClusters: set.NewSet(testWeightedCluster)}},
+			latestRoutes: []*RouteWeightedClusters{{
+				HTTPRouteMatch:   testHTTPRouteMatch,
+				WeightedClusters: set.NewSet(testWeightedCluster2),
+			}},
+			expectedRoutes: []*RouteWeightedClusters{{
+				HTTPRouteMatch:   testHTTPRouteMatch,
+				WeightedClusters: set.NewSet(testWeightedCluster2),
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := mergeRoutesWeightedClusters(tc.originalRoutes, tc.latestRoutes)
+			assert.Equal(tc.expectedRoutes, actual)
+		})
+	}
+}
+
+func TestSameHostnames(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.True(sameHostnames(nil, []string{}))
+	assert.True(sameHostnames(testHostnames, []string{"testHostname3", "testHostname1", "testHostname2"}))
+	assert.False(sameHostnames(testHostnames, []string{"testHostname1", "testHostname2"}))
+	assert.False(sameHostnames(testHostnames, testHostnames2))
+}
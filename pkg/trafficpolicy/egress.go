@@ -0,0 +1,33 @@
+package trafficpolicy
+
+import (
+	"sort"
+)
+
+// GetTLSOriginationSecrets returns the namespaced names of the secrets storing the CA bundles and the client
+// certificates referenced by the TLS originations of the policy, sorted and without duplicates
+func (p *EgressPolicy) GetTLSOriginationSecrets() (caBundleSecrets []string, clientCertSecrets []string) {
+	caBundles := make(map[string]bool)
+	clientCerts := make(map[string]bool)
+	for _, hosts := range p.TLSOriginations {
+		for _, tlsOrigination := range hosts {
+			if tlsOrigination.CABundleSecret != "" && !caBundles[tlsOrigination.CABundleSecret] {
+				caBundles[tlsOrigination.CABundleSecret] = true
+				caBundleSecrets = append(caBundleSecrets, tlsOrigination.CABundleSecret)
+			}
+			if tlsOrigination.ClientCertSecret != "" && !clientCerts[tlsOrigination.ClientCertSecret] {
+				clientCerts[tlsOrigination.ClientCertSecret] = true
+				clientCertSecrets = append(clientCertSecrets, tlsOrigination.ClientCertSecret)
+			}
+		}
+	}
+
+	sort.Strings(caBundleSecrets)
+	sort.Strings(clientCertSecrets)
+	return caBundleSecrets, clientCertSecrets
+}
+
+// HasHTTPProxyHosts returns true if the policy allows hosts to be reached through the explicit HTTP proxy of the proxy
+func (p *EgressPolicy) HasHTTPProxyHosts() bool {
+	return p != nil && (len(p.HTTPProxyHosts) > 0 || len(p.HTTPSProxyHosts) > 0)
+}
@@ -0,0 +1,40 @@
+package trafficpolicy
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestGetTLSOriginationSecrets(t *testing.T) {
+	assert := tassert.New(t)
+
+	caBundleSecrets, clientCertSecrets := (&EgressPolicy{}).GetTLSOriginationSecrets()
+	assert.Nil(caBundleSecrets)
+	assert.Nil(clientCertSecrets)
+
+	policy := &EgressPolicy{
+		TLSOriginations: map[uint32]map[string]*EgressTLSOrigination{
+			80: {
+				"httpbin.org": {Port: 443, CABundleSecret: "curl/httpbin-ca", ClientCertSecret: "curl/httpbin-client"},
+				"example.com": {Port: 443, MeshClientCert: true},
+			},
+			8080: {
+				"httpbin.org": {Port: 8443, CABundleSecret: "curl/httpbin-ca", ClientCertSecret: "egress/client"},
+			},
+		},
+	}
+	caBundleSecrets, clientCertSecrets = policy.GetTLSOriginationSecrets()
+	assert.Equal([]string{"curl/httpbin-ca"}, caBundleSecrets)
+	assert.Equal([]string{"curl/httpbin-client", "egress/client"}, clientCertSecrets)
+}
+
+func TestHasHTTPProxyHosts(t *testing.T) {
+	assert := tassert.New(t)
+
+	var policy *EgressPolicy
+	assert.False(policy.HasHTTPProxyHosts())
+	assert.False((&EgressPolicy{HTTPHosts: map[uint32][]string{80: {"httpbin.org"}}}).HasHTTPProxyHosts())
+	assert.True((&EgressPolicy{HTTPProxyHosts: map[uint32][]string{80: {"httpbin.org"}}}).HasHTTPProxyHosts())
+	assert.True((&EgressPolicy{HTTPSProxyHosts: map[uint32][]string{443: {"httpbin.org"}}}).HasHTTPProxyHosts())
+}
@@ -0,0 +1,119 @@
+// +build gofuzz
+
+package trafficpolicy
+
+import (
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests/fuzz"
+)
+
+const (
+	// maxFuzzPolicies is the max number of traffic policies derived from fuzzed data
+	maxFuzzPolicies = 32
+
+	// maxFuzzRoutes is the max number of rules or routes of a traffic policy derived from fuzzed data
+	maxFuzzRoutes = 256
+
+	// maxFuzzStringLen is the max length of a string derived from fuzzed data
+	maxFuzzStringLen = 64
+)
+
+// FuzzInboundPolicySet fuzzes the compilation of the inbound traffic policies built from SMI and ingress policies.
+// Hosts, routes and service accounts are mostly picked from small sets, for policies and rules to be merged.
+func FuzzInboundPolicySet(data []byte) int {
+	c := fuzz.NewConsumer(data)
+
+	policies := NewInboundPolicySet()
+	added := 0
+	for i := c.Intn(maxFuzzPolicies + 1); i > 0; i-- {
+		policies.Add(newFuzzPolicySource(c), newFuzzInboundPolicy(c))
+		added++
+	}
+
+	if len(policies.Policies()) == added {
+		return 0
+	}
+	return 1
+}
+
+// FuzzOutboundPolicySet fuzzes the compilation of the outbound traffic policies built from SMI policies. Hosts and
+// routes are mostly picked from small sets, for policies and routes to be merged.
+func FuzzOutboundPolicySet(data []byte) int {
+	c := fuzz.NewConsumer(data)
+
+	policies := NewOutboundPolicySet()
+	added := 0
+	for i := c.Intn(maxFuzzPolicies + 1); i > 0; i-- {
+		policies.Add(newFuzzPolicySource(c), newFuzzOutboundPolicy(c))
+		added++
+	}
+
+	if len(policies.Policies()) == added {
+		return 0
+	}
+	return 1
+}
+
+// newFuzzPolicySource returns a policy source derived from fuzzed data
+func newFuzzPolicySource(c *fuzz.Consumer) PolicySource {
+	return PolicySource(c.Intn(int(IngressSource) + 1))
+}
+
+// newFuzzInboundPolicy returns an inbound traffic policy derived from fuzzed data
+func newFuzzInboundPolicy(c *fuzz.Consumer) *InboundTrafficPolicy {
+	policy := NewInboundTrafficPolicy(c.String(maxFuzzStringLen), newFuzzHostnames(c))
+	for i := c.Intn(maxFuzzRoutes + 1); i > 0; i-- {
+		route := NewRouteWeightedCluster(newFuzzRouteMatch(c), newFuzzWeightedClusters(c))
+		policy.AddRule(*route, service.K8sServiceAccount{
+			Name:      c.Pick(maxFuzzStringLen, "", "bookbuyer"),
+			Namespace: c.Pick(maxFuzzStringLen, "", "bookbuyer-ns"),
+		})
+	}
+	return policy
+}
+
+// newFuzzOutboundPolicy returns an outbound traffic policy derived from fuzzed data
+func newFuzzOutboundPolicy(c *fuzz.Consumer) *OutboundTrafficPolicy {
+	policy := NewOutboundTrafficPolicy(c.String(maxFuzzStringLen), newFuzzHostnames(c))
+	for i := c.Intn(maxFuzzRoutes + 1); i > 0; i-- {
+		// Errors adding conflicting routes are expected, the fuzz target only checks that merging does not panic
+		_ = policy.AddRoute(newFuzzRouteMatch(c), newFuzzWeightedClusters(c)...)
+	}
+	return policy
+}
+
+// newFuzzHostnames returns hostnames derived from fuzzed data
+func newFuzzHostnames(c *fuzz.Consumer) []string {
+	var hostnames []string
+	for i := c.Intn(len(fuzz.Hosts) + 1); i > 0; i-- {
+		hostnames = append(hostnames, c.Pick(maxFuzzStringLen, fuzz.Hosts...))
+	}
+	return hostnames
+}
+
+// newFuzzRouteMatch returns an HTTP route match derived from fuzzed data
+func newFuzzRouteMatch(c *fuzz.Consumer) HTTPRouteMatch {
+	routeMatch := HTTPRouteMatch{
+		Path:          c.Pick(maxFuzzStringLen, fuzz.Paths...),
+		PathMatchType: PathMatchType(c.Intn(int(PathMatchPrefix) + 2)),
+	}
+	for i := c.Intn(len(fuzz.Methods) + 1); i > 0; i-- {
+		routeMatch.Methods = append(routeMatch.Methods, c.Pick(maxFuzzStringLen, fuzz.Methods...))
+	}
+	if c.Bool() {
+		routeMatch.Headers = map[string]string{c.Pick(maxFuzzStringLen, "host", "user-agent"): c.Pick(maxFuzzStringLen, fuzz.Paths...)}
+	}
+	return routeMatch
+}
+
+// newFuzzWeightedClusters returns weighted clusters derived from fuzzed data, with weights that may not add up to 100
+func newFuzzWeightedClusters(c *fuzz.Consumer) []service.WeightedCluster {
+	var weightedClusters []service.WeightedCluster
+	for i := c.Intn(4); i >= 0; i-- {
+		weightedClusters = append(weightedClusters, service.WeightedCluster{
+			ClusterName: service.ClusterName(c.Pick(maxFuzzStringLen, "bookstore-ns/bookstore-v1", "bookstore-ns/bookstore-v2")),
+			Weight:      c.Intn(101),
+		})
+	}
+	return weightedClusters
+}
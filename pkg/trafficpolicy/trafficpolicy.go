@@ -10,6 +10,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
+// wildcardServiceAccount is the service account of a Rule allowing any service account to access its route
+var wildcardServiceAccount = service.K8sServiceAccount{}
+
 // WildCardRouteMatch represents a wildcard HTTP route match condition
 var WildCardRouteMatch HTTPRouteMatch = HTTPRouteMatch{
 	Path:          constants.RegexMatchAll,
@@ -65,6 +68,9 @@ func (in *InboundTrafficPolicy) AddRule(route RouteWeightedClusters, allowedServ
 		if reflect.DeepEqual(rule.Route, route) {
 			routeExists = true
 			rule.AllowedServiceAccounts.Add(allowedServiceAccount)
+			if rule.ShadowServiceAccounts != nil {
+				rule.ShadowServiceAccounts.Add(allowedServiceAccount)
+			}
 			break
 		}
 	}
@@ -76,6 +82,31 @@ func (in *InboundTrafficPolicy) AddRule(route RouteWeightedClusters, allowedServ
 	}
 }
 
+// AddShadowRule adds a Rule to an InboundTrafficPolicy like AddRule, for a service account allowed by an SMI TrafficTarget
+// evaluated in shadow mode. While the TrafficTarget is in shadow mode, any service account is allowed to access the route,
+// and the given service account is only added to the service accounts the route is restricted to in shadow mode.
+func (in *InboundTrafficPolicy) AddShadowRule(route RouteWeightedClusters, allowedServiceAccount service.K8sServiceAccount) {
+	in.AddRule(route, allowedServiceAccount)
+	for _, rule := range in.Rules {
+		if reflect.DeepEqual(rule.Route, route) {
+			if rule.ShadowServiceAccounts == nil {
+				rule.ShadowServiceAccounts = rule.AllowedServiceAccounts.Clone()
+			}
+			rule.AllowedServiceAccounts.Add(wildcardServiceAccount)
+			return
+		}
+	}
+}
+
+// GetEnforcedServiceAccounts returns the service accounts allowed to access the route of the Rule once the SMI
+// TrafficTargets evaluated in shadow mode are enforced
+func (r *Rule) GetEnforcedServiceAccounts() set.Set {
+	if r.ShadowServiceAccounts != nil {
+		return r.ShadowServiceAccounts
+	}
+	return r.AllowedServiceAccounts
+}
+
 // AddRoute adds a route to an OutboundTrafficPolicy given an HTTP route match and weighted cluster. If a Route with the given HTTP route match
 //	already exists, an error will be returned. If a Route with the given HTTP route match does not exist,
 //	a Route with the given HTTP route match and weighted clusters will be added to the Routes on the OutboundTrafficPolicy
@@ -101,94 +132,6 @@ func (out *OutboundTrafficPolicy) AddRoute(httpRouteMatch HTTPRouteMatch, weight
 	return nil
 }
 
-// MergeInboundPolicies merges latest InboundTrafficPolicies into a slice of InboundTrafficPolicies that already exists (original)
-// allowPartialHostnamesMatch is set to true when we intend to merge ingress policies with traffic policies
-func MergeInboundPolicies(allowPartialHostnamesMatch bool, original []*InboundTrafficPolicy, latest ...*InboundTrafficPolicy) []*InboundTrafficPolicy {
-	for _, l := range latest {
-		foundHostnames := false
-		for _, or := range original {
-			if !allowPartialHostnamesMatch {
-				// For an traffic target inbound policy the hostnames list should fully intersect
-				// to merge the rules
-				if reflect.DeepEqual(or.Hostnames, l.Hostnames) {
-					foundHostnames = true
-					or.Rules = mergeRules(or.Rules, l.Rules)
-				}
-			} else {
-				// When an inbound traffic policy is being merged with an ingress traffic policy the hostnames is not the entire comprehensive list of kubernetes service names
-				// and will just be a subset to merge the rules
-				if subset(or.Hostnames, l.Hostnames) {
-					foundHostnames = true
-					or.Rules = mergeRules(or.Rules, l.Rules)
-				}
-			}
-		}
-		if !foundHostnames {
-			original = append(original, l)
-		}
-	}
-	return original
-}
-
-// MergeOutboundPolicies merges two slices of *OutboundTrafficPolicies so that there is only one traffic policy for a given set of a hostnames
-func MergeOutboundPolicies(original []*OutboundTrafficPolicy, latest ...*OutboundTrafficPolicy) []*OutboundTrafficPolicy {
-	for _, l := range latest {
-		foundHostnames := false
-		for _, or := range original {
-			if reflect.DeepEqual(or.Hostnames, l.Hostnames) {
-				foundHostnames = true
-				mergedRoutes := mergeRoutesWeightedClusters(or.Routes, l.Routes)
-				or.Routes = mergedRoutes
-			}
-		}
-		if !foundHostnames {
-			original = append(original, l)
-		}
-	}
-	return original
-}
-
-// mergeRules merges the give slices of rules such that there is one Rule for a Route with all allowed service accounts listed in the
-//	returned slice of rules
-func mergeRules(originalRules, latestRules []*Rule) []*Rule {
-	for _, latest := range latestRules {
-		foundRoute := false
-		for _, original := range originalRules {
-			if reflect.DeepEqual(latest.Route, original.Route) {
-				foundRoute = true
-				original.AllowedServiceAccounts = original.AllowedServiceAccounts.Union(latest.AllowedServiceAccounts)
-				break
-			}
-		}
-		if !foundRoute {
-			originalRules = append(originalRules, latest)
-		}
-	}
-	return originalRules
-}
-
-// mergeRoutesWeightedClusters merges two slices of RouteWeightedClusters and returns a slice where there is one RouteWeightedCluster
-//	for any HTTPRouteMatch. Where there is an overlap in HTTPRouteMatch between the originalRoutes and latestRoutes, the WeightedClusters
-//  specified in the latestRoutes will be kept since there can only be one set of WeightedClusters per HTTPRouteMatch.
-func mergeRoutesWeightedClusters(originalRoutes, latestRoutes []*RouteWeightedClusters) []*RouteWeightedClusters {
-	for _, latest := range latestRoutes {
-		foundRoute := false
-		for _, original := range originalRoutes {
-			if reflect.DeepEqual(original.HTTPRouteMatch, latest.HTTPRouteMatch) {
-				foundRoute = true
-				if !reflect.DeepEqual(original.WeightedClusters, latest.WeightedClusters) {
-					original.WeightedClusters = latest.WeightedClusters
-				}
-				continue
-			}
-		}
-		if !foundRoute {
-			originalRoutes = append(originalRoutes, latest)
-		}
-	}
-	return originalRoutes
-}
-
 // subset returns true if the second array is completely contained in the first array
 func subset(first, second []string) bool {
 	set := make(map[string]bool)
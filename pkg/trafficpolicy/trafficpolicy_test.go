@@ -125,6 +125,74 @@ func TestAddRule(t *testing.T) {
 	}
 }
 
+func TestAddShadowRule(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		name                  string
+		existingRules         []*Rule
+		allowedServiceAccount service.K8sServiceAccount
+		route                 RouteWeightedClusters
+		expectedRules         []*Rule
+	}{
+		{
+			name:                  "rule for route does not exist",
+			existingRules:         []*Rule{},
+			allowedServiceAccount: testServiceAccount1,
+			route:                 testRoute,
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1, wildcardServiceAccount),
+					ShadowServiceAccounts:  set.NewSet(testServiceAccount1),
+				},
+			},
+		},
+		{
+			name: "enforced rule exists for route",
+			existingRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
+				},
+			},
+			allowedServiceAccount: testServiceAccount2,
+			route:                 testRoute,
+			expectedRules: []*Rule{
+				{
+					Route:                  testRoute,
+					AllowedServiceAccounts: set.NewSet(testServiceAccount1, testServiceAccount2, wildcardServiceAccount),
+					ShadowServiceAccounts:  set.NewSet(testServiceAccount1, testServiceAccount2),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inboundPolicy := newTestInboundPolicy(tc.name, tc.existingRules)
+			inboundPolicy.AddShadowRule(tc.route, tc.allowedServiceAccount)
+			assert.Equal(tc.expectedRules, inboundPolicy.Rules)
+		})
+	}
+}
+
+func TestAddRuleToShadowRule(t *testing.T) {
+	assert := tassert.New(t)
+
+	inboundPolicy := newTestInboundPolicy("shadow", []*Rule{})
+	inboundPolicy.AddShadowRule(testRoute, testServiceAccount1)
+	inboundPolicy.AddRule(testRoute, testServiceAccount2)
+
+	assert.Equal([]*Rule{
+		{
+			Route:                  testRoute,
+			AllowedServiceAccounts: set.NewSet(testServiceAccount1, testServiceAccount2, wildcardServiceAccount),
+			ShadowServiceAccounts:  set.NewSet(testServiceAccount1, testServiceAccount2),
+		},
+	}, inboundPolicy.Rules)
+}
+
 func TestAddRoute(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -245,436 +313,6 @@ func TestAddRoute(t *testing.T) {
 	}
 }
 
-func TestMergeInboundPolicies(t *testing.T) {
-	assert := tassert.New(t)
-
-	testRule1 := Rule{
-		Route:                  testRoute,
-		AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-	}
-	testRule2 := Rule{
-		Route:                  testRoute2,
-		AllowedServiceAccounts: set.NewSet(testServiceAccount2),
-	}
-	testCases := []struct {
-		name            string
-		originalInbound []*InboundTrafficPolicy
-		newInbound      []*InboundTrafficPolicy
-		expectedInbound []*InboundTrafficPolicy
-	}{
-		{
-			name: "hostnames match",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule2},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-		},
-		{
-			name: "hostnames do not match",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames2,
-					Rules:     []*Rule{&testRule2},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames2,
-					Rules:     []*Rule{&testRule2},
-				},
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := MergeInboundPolicies(false, tc.originalInbound, tc.newInbound...)
-			assert.ElementsMatch(tc.expectedInbound, actual)
-		})
-	}
-}
-
-func TestMergeInboundPoliciesWithIngress(t *testing.T) {
-	assert := tassert.New(t)
-
-	testRule1 := Rule{
-		Route:                  testRoute,
-		AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-	}
-	testRule2 := Rule{
-		Route:                  testRoute2,
-		AllowedServiceAccounts: set.NewSet(testServiceAccount2),
-	}
-	testRule1Modified := Rule{
-		Route: RouteWeightedClusters{
-			HTTPRouteMatch: HTTPRouteMatch{
-				Path:          "/hello",
-				PathMatchType: PathMatchRegex,
-				Methods:       []string{"*"},
-			},
-			WeightedClusters: set.NewSet(testWeightedCluster),
-		},
-	}
-	testCases := []struct {
-		name              string
-		originalInbound   []*InboundTrafficPolicy
-		newIngressInbound []*InboundTrafficPolicy
-		expectedInbound   []*InboundTrafficPolicy
-	}{
-		{
-			name: "hostnames match",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newIngressInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule2},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-		},
-		{
-			name: "hostnames do not match",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newIngressInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames2,
-					Rules:     []*Rule{&testRule2},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames2,
-					Rules:     []*Rule{&testRule2},
-				},
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-		},
-		{
-			name: "hostnames in ingress is a subset",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newIngressInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: []string{"testHostname1"},
-					Rules:     []*Rule{&testRule2},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-		},
-		{
-			name: "hostnames in ingress is a subset but rules differ",
-			originalInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2},
-				},
-			},
-			newIngressInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: []string{"testHostname1"},
-					Rules:     []*Rule{&testRule1Modified},
-				},
-			},
-			expectedInbound: []*InboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Rules:     []*Rule{&testRule1, &testRule2, &testRule1Modified},
-				},
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := MergeInboundPolicies(true, tc.originalInbound, tc.newIngressInbound...)
-			assert.ElementsMatch(tc.expectedInbound, actual)
-		})
-	}
-}
-
-func TestMergeRules(t *testing.T) {
-	assert := tassert.New(t)
-
-	testCases := []struct {
-		name          string
-		originalRules []*Rule
-		newRules      []*Rule
-		expectedRules []*Rule
-	}{
-		{
-			name: "routes match",
-			originalRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-				},
-			},
-			newRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount2),
-				},
-			},
-			expectedRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1, testServiceAccount2),
-				},
-			},
-		},
-		{
-			name: "routes match but with duplicate allowed service accounts",
-			originalRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-				},
-			},
-			newRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-				},
-			},
-			expectedRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
-				},
-			},
-		},
-		{
-			name: "routes don't match, add rule",
-			originalRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-				},
-			},
-			newRules: []*Rule{
-				{
-					Route:                  testRoute2,
-					AllowedServiceAccounts: set.NewSet(testServiceAccount1),
-				},
-			},
-			expectedRules: []*Rule{
-				{
-					Route:                  testRoute,
-					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
-				},
-				{
-					Route:                  testRoute2,
-					AllowedServiceAccounts: set.NewSetWith(testServiceAccount1),
-				},
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := mergeRules(tc.originalRules, tc.newRules)
-			assert.ElementsMatch(tc.expectedRules, actual)
-		})
-	}
-}
-
-func TestMergeOutboundPolicies(t *testing.T) {
-	assert := tassert.New(t)
-
-	testCases := []struct {
-		name                                               string
-		originalPolicies, latestPolicies, expectedPolicies []*OutboundTrafficPolicy
-	}{
-		{
-			name: "hostnames don't match",
-			originalPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			latestPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames2,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			expectedPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-				{
-					Hostnames: testHostnames2,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-		},
-		{
-			name: "hostnames match",
-			originalPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			latestPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute2},
-				},
-			},
-			expectedPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute, &testRoute2},
-				},
-			},
-		},
-		{
-			name: "hostnames match, routes match",
-			originalPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			latestPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			expectedPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-		},
-		{
-			name: "hostnames match, routes have same match conditions but diff weighted clusters",
-			originalPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-			latestPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes: []*RouteWeightedClusters{{
-						HTTPRouteMatch:   testHTTPRouteMatch,
-						WeightedClusters: set.NewSet(testWeightedCluster2),
-					}},
-				},
-			},
-			expectedPolicies: []*OutboundTrafficPolicy{
-				{
-					Hostnames: testHostnames,
-					Routes:    []*RouteWeightedClusters{&testRoute},
-				},
-			},
-		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := MergeOutboundPolicies(tc.originalPolicies, tc.latestPolicies...)
-			assert.ElementsMatch(tc.expectedPolicies, actual)
-		})
-	}
-}
-
-func TestMergeRouteWeightedClusters(t *testing.T) {
-	assert := tassert.New(t)
-
-	testCases := []struct {
-		name                                         string
-		originalRoutes, latestRoutes, expectedRoutes []*RouteWeightedClusters
-	}{
-		{
-			name:           "merge routes with different match conditions",
-			originalRoutes: []*RouteWeightedClusters{&testRoute},
-			latestRoutes:   []*RouteWeightedClusters{&testRoute2},
-			expectedRoutes: []*RouteWeightedClusters{&testRoute, &testRoute2},
-		},
-		{
-			name:           "collapse routes with same match conditions and weighted clusters",
-			originalRoutes: []*RouteWeightedClusters{&testRoute},
-			latestRoutes:   []*RouteWeightedClusters{&testRoute},
-			expectedRoutes: []*RouteWeightedClusters{&testRoute},
-		},
-		{
-			name:           "routes have same match conditions but different weighted clusters, apply latest weighted clusters",
-			originalRoutes: []*RouteWeightedClusters{&testRoute},
-			latestRoutes: []*RouteWeightedClusters{{
-				HTTPRouteMatch:   testHTTPRouteMatch,
-				WeightedClusters: set.NewSet(testWeightedCluster2),
-			}},
-			expectedRoutes: []*RouteWeightedClusters{{
-				HTTPRouteMatch:   testHTTPRouteMatch,
-				WeightedClusters: set.NewSet(testWeightedCluster2),
-			}},
-		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := mergeRoutesWeightedClusters(tc.originalRoutes, tc.latestRoutes)
-			assert.Equal(tc.expectedRoutes, actual)
-		})
-	}
-}
 func TestNewInboundTrafficPolicy(t *testing.T) {
 	assert := tassert.New(t)
 
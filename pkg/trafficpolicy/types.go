@@ -3,9 +3,13 @@
 package trafficpolicy
 
 import (
+	"time"
+
 	set "github.com/deckarep/golang-set"
 
+	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 // TrafficSpecName is the namespaced name of the SMI TrafficSpec
@@ -28,12 +32,21 @@ const (
 	PathMatchPrefix PathMatchType = iota
 )
 
-// HTTPRouteMatch is a struct to represent an HTTP route match comprised of an HTTP path, path matching type, methods, and headers
+// HTTPRouteMatch is a struct to represent an HTTP route match comprised of an HTTP path, path matching type, methods, and headers.
+// The path of the requests matching the route is rewritten by the optional path rewrite.
 type HTTPRouteMatch struct {
 	Path          string            `json:"path:omitempty"`
 	PathMatchType PathMatchType     `json:"path_match_type:omitempty"`
 	Methods       []string          `json:"methods:omitempty"`
 	Headers       map[string]string `json:"headers:omitempty"`
+	PathRewrite   *PathRewrite      `json:"path_rewrite:omitempty"`
+}
+
+// PathRewrite is a struct to represent the rewrite of the path of a request, replacing the part of the path matching
+// the regular expression pattern with the substitution
+type PathRewrite struct {
+	Pattern      string `json:"pattern:omitempty"`
+	Substitution string `json:"substitution:omitempty"`
 }
 
 // TCPRouteMatch is a struct to represent a TCP route matching based on ports
@@ -41,10 +54,41 @@ type TCPRouteMatch struct {
 	Ports []int `json:"ports:omitempty"`
 }
 
-// RouteWeightedClusters is a struct of an HTTPRoute, associated weighted clusters and the domains
+// RouteWeightedClusters is a struct of an HTTPRoute, associated weighted clusters and the domains.
+// The requests matching the route are retried by the optional retry policy, and time out after the optional timeout,
+// or after going without activity for the optional stream idle timeout. Their responses are limited to the optional
+// bandwidth limit.
 type RouteWeightedClusters struct {
-	HTTPRouteMatch   HTTPRouteMatch `json:"http_route_match:omitempty"`
-	WeightedClusters set.Set        `json:"weighted_clusters:omitempty"`
+	HTTPRouteMatch      HTTPRouteMatch `json:"http_route_match:omitempty"`
+	WeightedClusters    set.Set        `json:"weighted_clusters:omitempty"`
+	RetryPolicy         *RetryPolicy   `json:"retry_policy:omitempty"`
+	Timeout             *time.Duration `json:"timeout:omitempty"`
+	StreamIdleTimeout   *time.Duration `json:"stream_idle_timeout:omitempty"`
+	BandwidthLimitKiBps uint64         `json:"bandwidth_limit_kibps:omitempty"`
+}
+
+// TimeoutPolicy is a struct to represent the timeouts of the requests to a service, or matching a route. The timeouts
+// that are not set are the ones of the proxy, and zero timeouts are disabled.
+type TimeoutPolicy struct {
+	// RequestTimeout is the timeout of the requests, including their retries
+	RequestTimeout *time.Duration `json:"request_timeout,omitempty"`
+
+	// StreamIdleTimeout is the timeout of the requests that go without sending or receiving data
+	StreamIdleTimeout *time.Duration `json:"stream_idle_timeout,omitempty"`
+
+	// IdleTimeout is how long the connections to the service are kept open without requests. It is only set for a service.
+	IdleTimeout *time.Duration `json:"idle_timeout,omitempty"`
+}
+
+// RetryPolicy is a struct to represent the retries of the requests matching a route, retried on the comma separated
+// Envoy retry conditions of RetryOn up to NumRetries times, each try timing out after the optional PerTryTimeout.
+// The retries are spaced by an exponential back-off with the optional base and max intervals.
+type RetryPolicy struct {
+	RetryOn                  string         `json:"retry_on:omitempty"`
+	NumRetries               uint32         `json:"num_retries:omitempty"`
+	PerTryTimeout            *time.Duration `json:"per_try_timeout:omitempty"`
+	RetryBackoffBaseInterval *time.Duration `json:"retry_backoff_base_interval:omitempty"`
+	RetryBackoffMaxInterval  *time.Duration `json:"retry_backoff_max_interval:omitempty"`
 }
 
 // InboundTrafficPolicy is a struct that associates incoming traffic on a set of Hostnames with a list of Rules
@@ -58,6 +102,10 @@ type InboundTrafficPolicy struct {
 type Rule struct {
 	Route                  RouteWeightedClusters `json:"route:omitempty"`
 	AllowedServiceAccounts set.Set               `json:"allowed_service_accounts:omitempty"`
+
+	// ShadowServiceAccounts are the Service Accounts that will be allowed to access the Route once the SMI TrafficTargets
+	// evaluated in shadow mode are enforced. It is nil when none of the TrafficTargets of the Rule is in shadow mode.
+	ShadowServiceAccounts set.Set `json:"shadow_service_accounts:omitempty"`
 }
 
 // OutboundTrafficPolicy is a struct that associates a list of Routes with outbound traffic on a set of Hostnames
@@ -73,4 +121,206 @@ type TrafficTargetWithRoutes struct {
 	Destination     identity.ServiceIdentity   `json:"destination:omitempty"`
 	Sources         []identity.ServiceIdentity `json:"sources:omitempty"`
 	TCPRouteMatches []TCPRouteMatch            `json:"tcp_route_matches:omitempty"`
+
+	// Shadow is true when the traffic target is evaluated in shadow mode: the requests it would deny are reported,
+	// but not denied
+	Shadow bool `json:"shadow:omitempty"`
+}
+
+// ForwardedHeaderPolicy is a struct to represent how a proxy determines the client address of the requests it receives
+// and handles their x-forwarded-for header
+type ForwardedHeaderPolicy struct {
+	// XFFNumTrustedHops is the number of proxy hops in front of the proxy, whose addresses in the x-forwarded-for header are trusted
+	XFFNumTrustedHops uint32 `json:"xff_num_trusted_hops:omitempty"`
+
+	// UseRemoteAddress determines the client address from the remote address of the connection instead of the x-forwarded-for header
+	UseRemoteAddress bool `json:"use_remote_address:omitempty"`
+
+	// SkipXFFAppend skips appending the remote address of the connection to the x-forwarded-for header
+	SkipXFFAppend bool `json:"skip_xff_append:omitempty"`
+}
+
+// IngressBackendPolicy is a struct to represent the ingress sources allowed to reach a service, and the ports and
+// protocols they reach it on. Ingress traffic from other sources, or on other ports, is not allowed.
+type IngressBackendPolicy struct {
+	// Name is the namespaced name of the IngressBackend the policy is built from
+	Name string `json:"name"`
+
+	// Ports maps the target ports of the service receiving traffic from ingress to their protocol, http, https, tcp or
+	// tls-passthrough
+	Ports map[uint32]string `json:"ports"`
+
+	// Principals are the subject alternative names of the client certificates of the ingress sources
+	Principals []string `json:"principals,omitempty"`
+
+	// IPRanges are the CIDR ranges of the addresses of the ingress sources
+	IPRanges []string `json:"ip_ranges,omitempty"`
+
+	// RequireClientCert requires the ingress sources to present a client certificate on the https ports
+	RequireClientCert bool `json:"require_client_cert,omitempty"`
+
+	// CABundleSecret is the namespaced name of the secret storing the CA bundle validating the client certificates of
+	// the ingress sources, or empty to validate them with the mesh's root certificate
+	CABundleSecret string `json:"ca_bundle_secret,omitempty"`
+}
+
+// EgressPolicy is a struct to represent the external destinations a service account is allowed to reach, per port.
+// Egress traffic to other destinations is only allowed when egress is globally enabled.
+type EgressPolicy struct {
+	// Names are the namespaced names of the Egress resources the policy is built from
+	Names []string `json:"names"`
+
+	// HTTPHosts maps the ports of the external HTTP destinations to the hosts of the requests allowed on them
+	HTTPHosts map[uint32][]string `json:"http_hosts,omitempty"`
+
+	// HTTPSHosts maps the ports of the external TLS destinations to the server names of the connections allowed on them
+	HTTPSHosts map[uint32][]string `json:"https_hosts,omitempty"`
+
+	// IPRanges maps the ports of the external destinations to the CIDR ranges of the addresses allowed on them
+	IPRanges map[uint32][]string `json:"ip_ranges,omitempty"`
+
+	// TLSOriginations maps the ports of the external HTTP destinations to the hosts whose requests are originated over
+	// TLS, and to the TLS connections originated to them
+	TLSOriginations map[uint32]map[string]*EgressTLSOrigination `json:"tls_originations,omitempty"`
+
+	// HTTPProxyHosts maps the ports of the external HTTP destinations to the hosts the proxy forwards the requests for as
+	// an explicit HTTP proxy
+	HTTPProxyHosts map[uint32][]string `json:"http_proxy_hosts,omitempty"`
+
+	// HTTPSProxyHosts maps the ports of the external TLS destinations to the hosts the proxy tunnels the HTTP CONNECT
+	// requests to as an explicit HTTP proxy
+	HTTPSProxyHosts map[uint32][]string `json:"https_proxy_hosts,omitempty"`
+}
+
+// EgressTLSOrigination is a struct to represent the TLS connections originated to an external host for the plaintext
+// HTTP requests sent to it.
+type EgressTLSOrigination struct {
+	// Port is the port of the host the TLS connections are originated to
+	Port uint32 `json:"port"`
+
+	// CABundleSecret is the namespaced name of the secret storing the CA bundle validating the certificate of the host,
+	// or empty to validate it with the system CA bundle of the proxy
+	CABundleSecret string `json:"ca_bundle_secret,omitempty"`
+
+	// MeshClientCert is true if the certificate issued by the mesh to the proxy is presented to the host
+	MeshClientCert bool `json:"mesh_client_cert,omitempty"`
+
+	// ClientCertSecret is the namespaced name of the secret storing the client certificate presented to the host, or
+	// empty if none or the certificate issued by the mesh is presented
+	ClientCertSecret string `json:"client_cert_secret,omitempty"`
+}
+
+// IngressAnnotationPolicy is a struct to represent the settings of ingress controllers, specified by the annotations of
+// the ingress resources of a service and of the service, that are applied to the traffic the service receives from ingress
+type IngressAnnotationPolicy struct {
+	// BackendProtocol is the protocol of the traffic received from ingress, http or https, or empty to use the mesh-wide
+	// HTTPS ingress setting
+	BackendProtocol string `json:"backend_protocol,omitempty"`
+
+	// MaxRequestBytes is the maximum size, in bytes, of the body of the requests received from ingress. A limit of 0
+	// does not limit the size.
+	MaxRequestBytes uint32 `json:"max_request_bytes,omitempty"`
+}
+
+// BandwidthLimit is a struct to represent the limits, in KiB/s, of the rate of the HTTP response data received and sent
+// by a service. A limit of 0 does not limit the rate.
+type BandwidthLimit struct {
+	// IngressKiBps limits the rate of the response data received by the service from its upstream services
+	IngressKiBps uint64 `json:"ingress_kibps,omitempty"`
+
+	// EgressKiBps limits the rate of the response data sent by the service to its clients
+	EgressKiBps uint64 `json:"egress_kibps,omitempty"`
+
+	// RouteEgressKiBps limits the rate of the response data sent by the service to the requests matching the matches of
+	// HTTPRouteGroups, overriding EgressKiBps
+	RouteEgressKiBps map[TrafficSpecName]map[TrafficSpecMatchName]uint64 `json:"route_egress_kibps,omitempty"`
+}
+
+// AdaptiveConcurrencyPolicy is a struct to represent the adaptive concurrency limits shedding the load of an overloaded
+// service, limiting the number of concurrent requests to the service based on the latency of its responses
+type AdaptiveConcurrencyPolicy struct {
+	// Enabled determines whether the load of the service is shed with adaptive concurrency limits
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MaxConcurrencyLimit is the maximum concurrency limit allowed when the service is not overloaded
+	MaxConcurrencyLimit uint32 `json:"max_concurrency_limit,omitempty"`
+}
+
+// InboundConnectionPolicy is a struct to represent the limits of the connections the sidecar proxy of a service opens
+// to the service to forward the requests it receives. A limit of 0 does not limit the connections.
+type InboundConnectionPolicy struct {
+	// MaxConnections is the maximum number of connections to the service
+	MaxConnections uint32 `json:"max_connections,omitempty"`
+
+	// MaxRequestsPerConnection is the maximum number of requests sent to the service over a single connection
+	MaxRequestsPerConnection uint32 `json:"max_requests_per_connection,omitempty"`
+}
+
+// UpstreamConnectionPolicy is a struct to represent the limits of the connections and requests of each client of an
+// upstream service to the service, enforced by the sidecar proxies of the clients. A nil limit keeps the limit of the
+// traffic priority of the service.
+type UpstreamConnectionPolicy struct {
+	// MaxConnections is the maximum number of connections to the service
+	MaxConnections *uint32 `json:"max_connections,omitempty"`
+
+	// MaxPendingRequests is the maximum number of requests queued while waiting for a connection to the service
+	MaxPendingRequests *uint32 `json:"max_pending_requests,omitempty"`
+
+	// MaxRequests is the maximum number of concurrent requests to the service
+	MaxRequests *uint32 `json:"max_requests,omitempty"`
+
+	// MaxRequestsPerConnection is the maximum number of requests sent to the service over a single connection
+	MaxRequestsPerConnection *uint32 `json:"max_requests_per_connection,omitempty"`
+
+	// MaxRetries is the maximum number of concurrent retries to the service
+	MaxRetries *uint32 `json:"max_retries,omitempty"`
+}
+
+// DarkLaunchPolicy is a struct to represent the dark launch of a shadow service, receiving a copy of the requests to a
+// primary service while its responses are discarded
+type DarkLaunchPolicy struct {
+	// ShadowCluster is the cluster of the shadow service the requests are copied to
+	ShadowCluster service.ClusterName `json:"shadow_cluster,omitempty"`
+
+	// Percentage is the percentage of the requests to the primary service copied to the shadow service
+	Percentage uint32 `json:"percentage,omitempty"`
+}
+
+// GRPCHealthCheckPolicy is a struct to represent the active health checks of the endpoints of a service with the gRPC
+// health checking protocol
+type GRPCHealthCheckPolicy struct {
+	// ServiceName is the name of the gRPC service checked, or empty to check the overall health of the server
+	ServiceName string `json:"service_name,omitempty"`
+
+	// Authority is the value of the :authority header of the health check requests
+	Authority string `json:"authority,omitempty"`
+
+	// GateNewEndpoints determines whether new endpoints receive traffic only once they passed their first health check
+	GateNewEndpoints bool `json:"gate_new_endpoints,omitempty"`
+}
+
+// FailoverPolicy is a struct to represent the backup endpoints outside of the mesh that the traffic to a service fails
+// over to when the endpoints of the service are unhealthy
+type FailoverPolicy struct {
+	// Endpoints are the backup endpoints the traffic fails over to
+	Endpoints []endpoint.Endpoint `json:"endpoints,omitempty"`
+
+	// Threshold is the percentage of healthy endpoints of the service below which the traffic starts failing over, or
+	// 0 to use Envoy's default threshold
+	Threshold uint32 `json:"threshold,omitempty"`
+}
+
+// RoutingPolicies is a struct to represent the SMI policies routing the traffic of a proxy, used to attribute the stats
+// of the traffic to the policies that routed it, and the dark launches its outbound traffic is copied to
+type RoutingPolicies struct {
+	// InboundTrafficTargets maps the namespaced name of each SMI TrafficTarget allowing HTTP traffic to the proxy to the
+	// HTTP routes it allows
+	InboundTrafficTargets map[string][]HTTPRouteMatch `json:"inbound_traffic_targets:omitempty"`
+
+	// OutboundTrafficSplits maps the name of the outbound traffic policy of each apex service to the namespaced name of
+	// the SMI TrafficSplit routing its traffic
+	OutboundTrafficSplits map[string]string `json:"outbound_traffic_splits:omitempty"`
+
+	// OutboundDarkLaunches maps the name of the outbound traffic policy of each dark launched service to its dark launch
+	OutboundDarkLaunches map[string]DarkLaunchPolicy `json:"outbound_dark_launches:omitempty"`
 }
@@ -0,0 +1,60 @@
+package trustbundle
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+)
+
+// Distribute implements Distributor by creating or updating the osm-trust-bundle ConfigMap of the namespace
+func (d *configMapDistributor) Distribute(namespace string, bundle []byte) error {
+	configMaps := d.kubeClient.CoreV1().ConfigMaps(namespace)
+
+	existing, err := configMaps.Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.TrustBundleConfigMapName,
+				Namespace: namespace,
+				Labels: map[string]string{
+					constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				},
+			},
+			Data: map[string]string{
+				constants.TrustBundleConfigMapKey: string(bundle),
+			},
+		}
+		k8s.SetControlPlaneGeneratedMetadata(&configMap.ObjectMeta)
+
+		if _, err := configMaps.Create(context.Background(), configMap, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "Error creating ConfigMap %s/%s", namespace, constants.TrustBundleConfigMapName)
+		}
+		log.Info().Msgf("Created trust bundle ConfigMap %s/%s", namespace, constants.TrustBundleConfigMapName)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error getting ConfigMap %s/%s", namespace, constants.TrustBundleConfigMapName)
+	}
+
+	if existing.Data[constants.TrustBundleConfigMapKey] == string(bundle) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Data = map[string]string{
+		constants.TrustBundleConfigMapKey: string(bundle),
+	}
+	k8s.SetControlPlaneGeneratedMetadata(&updated.ObjectMeta)
+
+	if _, err := configMaps.Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "Error updating ConfigMap %s/%s", namespace, constants.TrustBundleConfigMapName)
+	}
+	log.Info().Msgf("Updated trust bundle ConfigMap %s/%s", namespace, constants.TrustBundleConfigMapName)
+	return nil
+}
@@ -0,0 +1,152 @@
+package trustbundle
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+)
+
+// NewPublisher creates a new publisher of the trust bundle of the mesh to the osm-trust-bundle ConfigMap of every
+// monitored namespace. The trust bundle is only published when enabled in the OSM ConfigMap.
+func NewPublisher(kubeClient kubernetes.Interface, kubeController k8s.Controller, certManager certificate.Manager, cfg configurator.Configurator) *Publisher {
+	return &Publisher{
+		kubeController: kubeController,
+		certManager:    certManager,
+		cfg:            cfg,
+		distributor:    &configMapDistributor{kubeClient: kubeClient},
+	}
+}
+
+// Start publishes the trust bundle and keeps it in sync with the CA certificates and the monitored namespaces of the
+// mesh until the stop channel is closed
+func (p *Publisher) Start(stop <-chan struct{}) {
+	eventSub := events.GetPubSubInstance().Subscribe(
+		announcements.NamespaceAdded,
+		announcements.NamespaceUpdated,
+		announcements.CertificateRotated,
+		announcements.ConfigMapAdded,
+		announcements.ConfigMapUpdated,
+	)
+
+	go func() {
+		defer events.GetPubSubInstance().Unsub(eventSub)
+		ticker := time.NewTicker(resyncInterval)
+		defer ticker.Stop()
+
+		p.publishAll()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p.publishAll()
+			case msg, ok := <-eventSub:
+				if !ok {
+					return
+				}
+				p.handle(msg)
+			}
+		}
+	}()
+}
+
+func (p *Publisher) handle(msg interface{}) {
+	psubMsg, ok := msg.(events.PubSubMessage)
+	if !ok {
+		log.Error().Msgf("Unexpected message type %T received on trust bundle subscription", msg)
+		return
+	}
+
+	switch psubMsg.AnnouncementType {
+	case announcements.NamespaceAdded, announcements.NamespaceUpdated:
+		// A namespace added to the mesh gets the trust bundle without waiting for the next resync
+		ns, ok := psubMsg.NewObj.(*corev1.Namespace)
+		if !ok {
+			log.Error().Msgf("Unexpected object type %T for namespace", psubMsg.NewObj)
+			return
+		}
+		if !p.cfg.IsTrustBundlePublished() || !p.kubeController.IsMonitoredNamespace(ns.Name) {
+			return
+		}
+		p.publish([]string{ns.Name})
+
+	case announcements.CertificateRotated:
+		cert, ok := psubMsg.NewObj.(certificate.Certificater)
+		if !ok {
+			log.Error().Msgf("Unexpected object type %T for rotated certificate", psubMsg.NewObj)
+			return
+		}
+		// Most rotations renew a certificate with the same CA, which does not change the trust bundle
+		if bytes.Equal(cert.GetIssuingCA(), p.issuingCA) {
+			return
+		}
+		p.issuingCA = cert.GetIssuingCA()
+		p.publishAll()
+
+	default:
+		// The OSM ConfigMap changed, possibly enabling the publishing of the trust bundle
+		p.publishAll()
+	}
+}
+
+// publishAll publishes the trust bundle to all the monitored namespaces when enabled. Trust bundles already published
+// are left in place when publishing is disabled: the ConfigMaps are only deleted with their namespace.
+func (p *Publisher) publishAll() {
+	if !p.cfg.IsTrustBundlePublished() {
+		return
+	}
+
+	namespaces, err := p.kubeController.ListMonitoredNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing monitored namespaces, not publishing the trust bundle")
+		return
+	}
+	p.publish(namespaces)
+}
+
+// publish publishes the trust bundle to the given namespaces
+func (p *Publisher) publish(namespaces []string) {
+	bundle, err := p.getTrustBundle()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting the trust bundle of the mesh")
+		return
+	}
+
+	for _, ns := range namespaces {
+		if err := p.distributor.Distribute(ns, bundle); err != nil {
+			log.Error().Err(err).Msgf("Error publishing the trust bundle to namespace %s", ns)
+			continue
+		}
+		log.Trace().Msgf("Published the trust bundle to namespace %s", ns)
+	}
+}
+
+// getTrustBundle returns the PEM encoded trust bundle of the mesh: the root certificate, followed by the issuing CA of
+// the rotated certificates when it is not part of the root certificate chain
+func (p *Publisher) getTrustBundle() ([]byte, error) {
+	root, err := p.certManager.GetRootCertificate()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the root certificate")
+	}
+
+	bundle := append([]byte{}, root.GetCertificateChain()...)
+	if len(bundle) == 0 {
+		return nil, errors.New("The root certificate is empty")
+	}
+	if len(p.issuingCA) > 0 && !bytes.Contains(bundle, bytes.TrimSpace(p.issuingCA)) {
+		if !bytes.HasSuffix(bundle, []byte("\n")) {
+			bundle = append(bundle, '\n')
+		}
+		bundle = append(bundle, p.issuingCA...)
+	}
+	return bundle, nil
+}
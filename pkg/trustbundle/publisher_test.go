@@ -0,0 +1,117 @@
+package trustbundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/kubernetes/events"
+	"github.com/openservicemesh/osm/pkg/tests/certificates"
+)
+
+func getTrustBundle(assert *tassert.Assertions, kubeClient *fake.Clientset, namespace string) string {
+	configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	assert.Nil(err)
+	return configMap.Data[constants.TrustBundleConfigMapKey]
+}
+
+func TestDistribute(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	d := &configMapDistributor{kubeClient: kubeClient}
+
+	// The ConfigMap is created when missing
+	assert.Nil(d.Distribute("bookstore", []byte("bundle-1")))
+	assert.Equal("bundle-1", getTrustBundle(assert, kubeClient, "bookstore"))
+
+	configMap, err := kubeClient.CoreV1().ConfigMaps("bookstore").Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(constants.OSMAppManagedByLabelValue, configMap.Labels[constants.OSMAppManagedByLabelKey])
+	assert.Equal(constants.OSMAppNameLabelValue, configMap.Labels[constants.OSMAppNameLabelKey])
+
+	// The ConfigMap is updated with the new trust bundle
+	assert.Nil(d.Distribute("bookstore", []byte("bundle-2")))
+	assert.Equal("bundle-2", getTrustBundle(assert, kubeClient, "bookstore"))
+
+	// Changes made out of band are reverted
+	configMap.Data = map[string]string{"other": "value"}
+	_, err = kubeClient.CoreV1().ConfigMaps("bookstore").Update(context.Background(), configMap, metav1.UpdateOptions{})
+	assert.Nil(err)
+	assert.Nil(d.Distribute("bookstore", []byte("bundle-2")))
+	updated, err := kubeClient.CoreV1().ConfigMaps("bookstore").Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{constants.TrustBundleConfigMapKey: "bundle-2"}, updated.Data)
+}
+
+func TestHandle(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	kubeClient := fake.NewSimpleClientset()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	certManager := tresor.NewFakeCertManager(nil)
+	p := NewPublisher(kubeClient, mockKubeController, certManager, mockConfigurator)
+
+	root, err := certManager.GetRootCertificate()
+	assert.Nil(err)
+
+	namespaceAdded := func(name string) events.PubSubMessage {
+		return events.PubSubMessage{
+			AnnouncementType: announcements.NamespaceAdded,
+			NewObj:           &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		}
+	}
+
+	// Nothing is published when disabled
+	mockConfigurator.EXPECT().IsTrustBundlePublished().Return(false).Times(2)
+	p.handle(namespaceAdded("bookstore"))
+	p.handle(events.PubSubMessage{AnnouncementType: announcements.ConfigMapUpdated})
+	_, err = kubeClient.CoreV1().ConfigMaps("bookstore").Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	assert.NotNil(err)
+
+	// Only monitored namespaces get the trust bundle
+	mockConfigurator.EXPECT().IsTrustBundlePublished().Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("kube-system").Return(false).Times(1)
+	p.handle(namespaceAdded("kube-system"))
+	_, err = kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.Background(), constants.TrustBundleConfigMapName, metav1.GetOptions{})
+	assert.NotNil(err)
+
+	mockKubeController.EXPECT().IsMonitoredNamespace("bookstore").Return(true).Times(1)
+	p.handle(namespaceAdded("bookstore"))
+	assert.Equal(string(root.GetCertificateChain()), getTrustBundle(assert, kubeClient, "bookstore"))
+
+	// Enabling publishing in the OSM ConfigMap publishes to all the monitored namespaces
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return([]string{"bookstore", "bookbuyer"}, nil).Times(1)
+	p.handle(events.PubSubMessage{AnnouncementType: announcements.ConfigMapUpdated})
+	assert.Equal(string(root.GetCertificateChain()), getTrustBundle(assert, kubeClient, "bookbuyer"))
+
+	// Rotations with the same CA do not publish the trust bundle again
+	cert, err := certManager.IssueCertificate("bookstore.bookstore.cluster.local", time.Hour)
+	assert.Nil(err)
+	p.issuingCA = cert.GetIssuingCA()
+	p.handle(events.PubSubMessage{AnnouncementType: announcements.CertificateRotated, NewObj: cert})
+
+	// Rotations with a new CA add the CA to the trust bundle
+	newCA := certificate.NewMockCertificater(mockCtrl)
+	newCA.EXPECT().GetIssuingCA().Return([]byte(certificates.SampleCertificatePEM)).AnyTimes()
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return([]string{"bookstore"}, nil).Times(1)
+	p.handle(events.PubSubMessage{AnnouncementType: announcements.CertificateRotated, NewObj: newCA})
+	bundle := getTrustBundle(assert, kubeClient, "bookstore")
+	assert.Contains(bundle, string(root.GetCertificateChain()))
+	assert.Contains(bundle, certificates.SampleCertificatePEM)
+}
@@ -0,0 +1,45 @@
+// Package trustbundle publishes the trust bundle of the mesh, the CA certificates the certificates of the mesh are
+// issued by, to the namespaces monitored by the mesh. Applications doing their own TLS to mesh services, and external
+// tooling, read the trust bundle from the namespace they run in instead of from the secrets of the control plane.
+package trustbundle
+
+import (
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	k8s "github.com/openservicemesh/osm/pkg/kubernetes"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("trust-bundle")
+
+// resyncInterval is the interval at which the trust bundle is published to all the monitored namespaces again,
+// restoring the trust bundles modified or deleted out of band
+const resyncInterval = 10 * time.Minute
+
+// Distributor makes the trust bundle of the mesh available in a namespace
+type Distributor interface {
+	// Distribute makes the given PEM encoded trust bundle available in the given namespace
+	Distribute(namespace string, bundle []byte) error
+}
+
+// Publisher publishes the trust bundle of the mesh to the monitored namespaces with a Distributor, keeping the
+// published trust bundles in sync with the CA certificates of the mesh.
+type Publisher struct {
+	kubeController k8s.Controller
+	certManager    certificate.Manager
+	cfg            configurator.Configurator
+	distributor    Distributor
+
+	// issuingCA is the issuing CA of the last rotated certificate, added to the trust bundle when it differs from the
+	// root certificate, as when an external issuer rotates its CA. Only accessed by the publishing goroutine.
+	issuingCA []byte
+}
+
+// configMapDistributor distributes the trust bundle as the osm-trust-bundle ConfigMap of the namespace
+type configMapDistributor struct {
+	kubeClient kubernetes.Interface
+}
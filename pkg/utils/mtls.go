@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
 )
 
 func setupMutualTLS(insecure bool, serverName string, certPem []byte, keyPem []byte, ca []byte) (grpc.ServerOption, error) {
@@ -68,3 +70,78 @@ func ValidateClient(ctx context.Context, allowedCommonNames map[string]interface
 	certificateSerialNumber := tlsAuth.State.VerifiedChains[0][0].SerialNumber.String()
 	return certificate.CommonName(cn), certificate.SerialNumber(certificateSerialNumber), nil
 }
+
+// GetControlPlaneCommonName returns the common name of the certificates of the given control plane component, the DNS
+// name of its service in the OSM namespace
+func GetControlPlaneCommonName(component string, osmNamespace string) certificate.CommonName {
+	return certificate.CommonName(fmt.Sprintf("%s.%s.svc", component, osmNamespace))
+}
+
+// NewControlPlaneTLSConfig returns the TLS configuration of the servers and clients of the given control plane component,
+// authenticating each other with certificates issued by the mesh CA. The certificate of the component is issued by the
+// certificate manager and read from it at every handshake, so that rotated certificates are used without restarting
+// the servers. Peers are rejected unless they present a certificate issued for a control plane identity.
+func NewControlPlaneTLSConfig(certManager certificate.Manager, component string, osmNamespace string) (*tls.Config, error) {
+	root, err := certManager.GetRootCertificate()
+	if err != nil {
+		return nil, errors.Wrap(err, "[mTLS] Error getting the root certificate")
+	}
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(root.GetCertificateChain()); !ok {
+		return nil, errors.New("[mTLS] Failed to append the root certificate")
+	}
+
+	cn := GetControlPlaneCommonName(component, osmNamespace)
+	getCertificate := func() (*tls.Certificate, error) {
+		cert, err := certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[mTLS] Error issuing certificate for CN=%s", cn)
+		}
+		tlsCert, err := tls.X509KeyPair(cert.GetCertificateChain(), cert.GetPrivateKey())
+		if err != nil {
+			return nil, errors.Wrapf(err, "[mTLS] Error loading certificate for CN=%s", cn)
+		}
+		return &tlsCert, nil
+	}
+
+	// Issue the certificate upfront so that the component fails to start rather than to handshake
+	if _, err := getCertificate(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return getCertificate()
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return getCertificate()
+		},
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  certPool,
+		RootCAs:    certPool,
+		VerifyPeerCertificate: func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+			return verifyControlPlanePeer(verifiedChains, osmNamespace)
+		},
+	}, nil
+}
+
+// controlPlaneComponents are the control plane components authenticating each other with control plane certificates
+var controlPlaneComponents = []string{constants.OSMControllerName, constants.OSMInjectorName}
+
+// verifyControlPlanePeer rejects peers whose certificate, already verified against the mesh CA, is not issued for a
+// control plane identity. Certificates issued to proxies are signed by the same CA but are not control plane identities,
+// and their CN can end with the suffix of a control plane CN, so the CN must match a control plane identity exactly.
+func verifyControlPlanePeer(verifiedChains [][]*x509.Certificate, osmNamespace string) error {
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+		return errors.New("[mTLS] Could not verify peer certificate")
+	}
+	cn := certificate.CommonName(verifiedChains[0][0].Subject.CommonName)
+	for _, component := range controlPlaneComponents {
+		if cn == GetControlPlaneCommonName(component, osmNamespace) {
+			return nil
+		}
+	}
+	log.Error().Msgf("[mTLS] Rejected peer with CN=%s, not a control plane identity", cn)
+	return errors.Errorf("[mTLS] Peer with CN=%s is not a control plane identity", cn)
+}
@@ -2,10 +2,15 @@ package utils
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	tassert "github.com/stretchr/testify/assert"
@@ -13,9 +18,13 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
 
@@ -95,3 +104,101 @@ func TestValidateClient(t *testing.T) {
 		}
 	}
 }
+
+func TestControlPlaneTLSConfig(t *testing.T) {
+	assert := tassert.New(t)
+
+	const osmNamespace = "osm-system"
+	certManager := tresor.NewFakeCertManager(nil)
+
+	serverConfig, err := NewControlPlaneTLSConfig(certManager, "osm-controller", osmNamespace)
+	assert.Nil(err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = serverConfig
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(clientConfig *tls.Config) error {
+		clientConfig.ServerName = GetControlPlaneCommonName("osm-controller", osmNamespace).String()
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// Control plane components authenticate each other
+	clientConfig, err := NewControlPlaneTLSConfig(certManager, "osm-injector", osmNamespace)
+	assert.Nil(err)
+	assert.Nil(get(clientConfig))
+
+	// Clients without a certificate are rejected
+	noCertConfig := clientConfig.Clone()
+	noCertConfig.GetClientCertificate = nil
+	assert.NotNil(get(noCertConfig))
+
+	// Clients with a certificate issued by the mesh CA for a proxy are rejected, including the xDS certificate of a
+	// proxy with the service account osm-system in the namespace svc, whose CN ends like a control plane CN
+	proxyCNs := []certificate.CommonName{
+		"bookstore.bookstore.cluster.local",
+		certificate.CommonName(fmt.Sprintf("%s.%s.svc", uuid.New(), osmNamespace)),
+	}
+	for _, proxyCN := range proxyCNs {
+		proxyCert, err := certManager.IssueCertificate(proxyCN, time.Hour)
+		assert.Nil(err)
+		proxyTLSCert, err := tls.X509KeyPair(proxyCert.GetCertificateChain(), proxyCert.GetPrivateKey())
+		assert.Nil(err)
+		proxyConfig := clientConfig.Clone()
+		proxyConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &proxyTLSCert, nil
+		}
+		assert.NotNil(get(proxyConfig), "peer with CN %s accepted as control plane", proxyCN)
+	}
+
+	// Plaintext clients are rejected
+	resp, err := http.Get(strings.Replace(server.URL, "https://", "http://", 1))
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Nil(resp.Body.Close())
+}
+
+func TestControlPlaneConfigMTLS(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const osmNamespace = "osm-system"
+	certManager := tresor.NewFakeCertManager(nil)
+
+	// osm-controller serves the OSM ConfigMap with its control plane certificate
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMConfigMap().Return(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: osmNamespace, Name: "osm-config", ResourceVersion: "1"},
+		Data:       map[string]string{"egress": "true"},
+	}).AnyTimes()
+	serverConfig, err := NewControlPlaneTLSConfig(certManager, constants.OSMControllerName, osmNamespace)
+	assert.Nil(err)
+	server := httptest.NewUnstartedServer(configurator.NewConfigMapHandler(mockConfigurator))
+	server.TLS = serverConfig
+	server.StartTLS()
+	defer server.Close()
+
+	// osm-injector reads it with its control plane certificate
+	clientConfig, err := NewControlPlaneTLSConfig(certManager, constants.OSMInjectorName, osmNamespace)
+	assert.Nil(err)
+	clientConfig.ServerName = GetControlPlaneCommonName(constants.OSMControllerName, osmNamespace).String()
+	remoteCfg := configurator.NewRemoteConfigurator(strings.TrimPrefix(server.URL, "https://"), osmNamespace, "osm-config")
+	stop := make(chan struct{})
+	defer close(stop)
+	remoteCfg.Start(clientConfig, stop)
+	assert.True(remoteCfg.IsEgressEnabled())
+
+	// Plaintext callers are rejected
+	resp, err := http.Get(strings.Replace(server.URL, "https://", "http://", 1) + configurator.ConfigMapPath)
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Nil(resp.Body.Close())
+}
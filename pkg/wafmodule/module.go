@@ -0,0 +1,67 @@
+package wafmodule
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// Path is the path the packaged module is served at
+const Path = "/coraza-proxy-wasm.wasm"
+
+// Load loads the packaged module at the given path of the image. The release of coraza-proxy-wasm and the checksum of
+// its source pinned in the chart must match the ones the module was built from, so that the chart and the image cannot
+// silently drift apart.
+func Load(path, expectedVersion, expectedSum, osmNamespace string) (*Module, error) {
+	if version == "" || sum == "" {
+		return nil, errors.New("No WAF module was packaged at build time")
+	}
+	if expectedVersion != version || expectedSum != sum {
+		return nil, errors.Errorf("The WAF module is built from coraza-proxy-wasm %s (%s), not the pinned %s (%s)", version, sum, expectedVersion, expectedSum)
+	}
+
+	module, err := ioutil.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading the WAF module at %s", path)
+	}
+	checksum := sha256.Sum256(module)
+
+	return &Module{
+		URL:     fmt.Sprintf("http://%s.%s.svc.cluster.local:%d%s", constants.OSMControllerName, osmNamespace, constants.OSMWAFModulePort, Path),
+		SHA256:  hex.EncodeToString(checksum[:]),
+		Version: version,
+		module:  module,
+	}, nil
+}
+
+// ServeHTTP serves the module
+func (m *Module) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/wasm")
+	if _, err := w.Write(m.module); err != nil {
+		log.Error().Err(err).Msg("Error serving the WAF module")
+	}
+}
+
+// SetPackagedModule records the packaged module served by this process, used by the sidecar proxies unless a WAF module
+// URL is configured
+func SetPackagedModule(m *Module) {
+	packagedModuleMutex.Lock()
+	defer packagedModuleMutex.Unlock()
+	packagedModule = m
+	if m != nil {
+		log.Info().Msgf("Serving the WAF module built from coraza-proxy-wasm %s at %s with SHA256 %s", m.Version, m.URL, m.SHA256)
+	}
+}
+
+// GetPackagedModule returns the packaged module served by this process, nil if none is served
+func GetPackagedModule() *Module {
+	packagedModuleMutex.RLock()
+	defer packagedModuleMutex.RUnlock()
+	return packagedModule
+}
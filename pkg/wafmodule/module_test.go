@@ -0,0 +1,114 @@
+package wafmodule
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "waf-module")
+	tassert.Nil(t, err)
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	modulePath := filepath.Join(dir, "coraza-proxy-wasm.wasm")
+	tassert.Nil(t, ioutil.WriteFile(modulePath, []byte("module"), 0600))
+
+	testCases := []struct {
+		name            string
+		builtVersion    string
+		builtSum        string
+		path            string
+		expectedVersion string
+		expectedSum     string
+		expectedErr     bool
+	}{
+		{
+			name:            "no module packaged at build time",
+			path:            modulePath,
+			expectedVersion: "v0.1.1",
+			expectedSum:     "h1:abc=",
+			expectedErr:     true,
+		},
+		{
+			name:            "module built from another release",
+			builtVersion:    "v0.1.0",
+			builtSum:        "h1:def=",
+			path:            modulePath,
+			expectedVersion: "v0.1.1",
+			expectedSum:     "h1:abc=",
+			expectedErr:     true,
+		},
+		{
+			name:            "module built from another source",
+			builtVersion:    "v0.1.1",
+			builtSum:        "h1:def=",
+			path:            modulePath,
+			expectedVersion: "v0.1.1",
+			expectedSum:     "h1:abc=",
+			expectedErr:     true,
+		},
+		{
+			name:            "module not found",
+			builtVersion:    "v0.1.1",
+			builtSum:        "h1:abc=",
+			path:            filepath.Join(dir, "missing.wasm"),
+			expectedVersion: "v0.1.1",
+			expectedSum:     "h1:abc=",
+			expectedErr:     true,
+		},
+		{
+			name:            "module built from the pinned release",
+			builtVersion:    "v0.1.1",
+			builtSum:        "h1:abc=",
+			path:            modulePath,
+			expectedVersion: "v0.1.1",
+			expectedSum:     "h1:abc=",
+			expectedErr:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			oldVersion, oldSum := version, sum
+			version, sum = tc.builtVersion, tc.builtSum
+			defer func() {
+				version, sum = oldVersion, oldSum
+			}()
+
+			module, err := Load(tc.path, tc.expectedVersion, tc.expectedSum, "osm-system")
+			assert.Equal(tc.expectedErr, err != nil)
+			if tc.expectedErr {
+				return
+			}
+			assert.Equal("http://osm-controller.osm-system.svc.cluster.local:9097/coraza-proxy-wasm.wasm", module.URL)
+			// sha256sum of "module"
+			assert.Equal("120970d812836f19888625587a4606a5ad23cef31c8684e601771552548fc6b9", module.SHA256)
+			assert.Equal("v0.1.1", module.Version)
+
+			w := httptest.NewRecorder()
+			module.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+			assert.Equal(http.StatusOK, w.Code)
+			assert.Equal("application/wasm", w.Header().Get("Content-Type"))
+			assert.Equal("module", w.Body.String())
+		})
+	}
+}
+
+func TestPackagedModule(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Nil(GetPackagedModule())
+
+	module := &Module{URL: "http://osm-controller.osm-system.svc.cluster.local:9097/coraza-proxy-wasm.wasm", SHA256: "abc"}
+	SetPackagedModule(module)
+	defer SetPackagedModule(nil)
+	assert.Equal(module, GetPackagedModule())
+}
@@ -0,0 +1,43 @@
+// Package wafmodule serves the Coraza WAF WASM module packaged in the osm-controller image to the sidecar proxies. The
+// module is built from a release of coraza-proxy-wasm pinned in the chart, so that enabling the WAF does not require
+// hosting the module and computing its checksum.
+package wafmodule
+
+import (
+	"sync"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var log = logger.New("waf-module")
+
+var (
+	// version is the release of coraza-proxy-wasm the packaged module is built from, set at build time
+	version string
+
+	// sum is the checksum of the source of the release of coraza-proxy-wasm in the Go checksum database, set at build
+	// time
+	sum string
+)
+
+var (
+	// packagedModuleMutex guards the packaged module served by this process
+	packagedModuleMutex sync.RWMutex
+
+	// packagedModule is the packaged module served by this process, nil if none is served
+	packagedModule *Module
+)
+
+// Module is the packaged WAF WASM module
+type Module struct {
+	// URL is the URL the sidecar proxies fetch the module from
+	URL string
+
+	// SHA256 is the hex encoded SHA256 checksum of the module
+	SHA256 string
+
+	// Version is the release of coraza-proxy-wasm the module is built from
+	Version string
+
+	module []byte
+}
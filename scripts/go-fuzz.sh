@@ -0,0 +1,43 @@
+#!/bin/bash
+
+# Runs each fuzz target of the controller for FUZZ_TIME, and fails if the fuzzer found crashers.
+# The fuzz targets are built with go-fuzz, installed with:
+#   go get github.com/dvyukov/go-fuzz/go-fuzz github.com/dvyukov/go-fuzz/go-fuzz-build
+
+set -uo pipefail
+
+FUZZ_TIME="${FUZZ_TIME:-60s}"
+FUZZ_WORKDIR="${FUZZ_WORKDIR:-/tmp/osm-fuzz}"
+
+targets=(
+    "./pkg/catalog FuzzGetIngressPoliciesForService"
+    "./pkg/trafficpolicy FuzzInboundPolicySet"
+    "./pkg/trafficpolicy FuzzOutboundPolicySet"
+    "./pkg/envoy/route FuzzBuildRouteConfiguration"
+)
+
+if ! command -v go-fuzz-build > /dev/null || ! command -v go-fuzz > /dev/null; then
+    echo "go-fuzz is not installed, install it with: go get github.com/dvyukov/go-fuzz/go-fuzz github.com/dvyukov/go-fuzz/go-fuzz-build"
+    exit 1
+fi
+
+failed=0
+for target in "${targets[@]}"; do
+    read -r pkg func <<< "$target"
+    workdir="$FUZZ_WORKDIR/$func"
+    mkdir -p "$workdir"
+
+    echo "Fuzzing $func in $pkg for $FUZZ_TIME"
+    if ! go-fuzz-build -func "$func" -o "$workdir/fuzz.zip" "$pkg"; then
+        failed=1
+        continue
+    fi
+    timeout -s INT "$FUZZ_TIME" go-fuzz -bin "$workdir/fuzz.zip" -workdir "$workdir"
+
+    if [ -n "$(ls -A "$workdir/crashers" 2> /dev/null)" ]; then
+        echo "Fuzzing $func found crashers in $workdir/crashers"
+        failed=1
+    fi
+done
+
+exit $failed
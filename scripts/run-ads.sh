@@ -2,10 +2,13 @@
 
 set -aueo pipefail
 
-rm -rf ./bin/osm-controller
+# Binaries are built per architecture, as by the build-osm-controller make target
+ARCH="${ARCH:-$(go env GOARCH)}"
+
+rm -rf "./bin/osm-controller/$ARCH"
 
 NAME="osm-controller"
-CGO_ENABLED=0 go build -v -o ./bin/osm-controller ./cmd/osm-controller
+CGO_ENABLED=0 go build -v -o "./bin/osm-controller/$ARCH/osm-controller" ./cmd/osm-controller
 
 # GRPC_TRACE=all GRPC_VERBOSITY=DEBUG GODEBUG='http2debug=2,gctrace=1,netdns=go+1'
 
@@ -24,7 +27,7 @@ mkdir -p "./certificates/$NAME"
            --keyout "./certificates/$NAME/key.pem" \
            --out "./certificates/$NAME/cert.pem"
 
-./bin/osm-controller \
+"./bin/osm-controller/$ARCH/osm-controller" \
     --kubeconfig="$HOME/.kube/config" \
     --certpem="./certificates/ads/cert.pem" \
     --keypem="./certificates/ads/key.pem" \
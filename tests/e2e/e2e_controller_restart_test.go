@@ -72,9 +72,9 @@ func testHTTPTrafficWithControllerRestart() {
 			})
 
 		// Configs have to be put into a monitored NS
-		_, err = Td.CreateHTTPRouteGroup(sourceName, httpRG)
+		_, err = Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = Td.CreateTrafficTarget(sourceName, trafficTarget)
+		_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 		Expect(err).NotTo(HaveOccurred())
 
 		// All ready. Expect client to reach server
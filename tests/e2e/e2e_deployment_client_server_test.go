@@ -117,9 +117,9 @@ var _ = OSMDescribe("Test HTTP traffic from N deployment client -> 1 deployment
 						})
 
 					// Configs have to be put into a monitored NS, and osm-system can't be by cli
-					_, err = Td.CreateHTTPRouteGroup(srcClient, httpRG)
+					_, err = Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 				}
 
@@ -84,9 +84,9 @@ func testSecureGRPCTraffic() {
 			}, grpcbinSecurePort)
 
 		// Create SMI policies
-		_, err = Td.CreateTCPRoute(sourceName, tcpRoute)
+		_, err = Td.CreateTCPRoute(trafficTarget.Spec.Destination.Namespace, tcpRoute)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = Td.CreateTrafficTarget(sourceName, trafficTarget)
+		_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 		Expect(err).NotTo(HaveOccurred())
 
 		// All ready. Expect client to reach server
@@ -98,9 +98,9 @@ var _ = OSMDescribe("Custom WASM metrics between one client pod and one server",
 				})
 
 			// SMI is formally deployed on destination NS
-			_, err = Td.CreateHTTPRouteGroup(destNs, httpRG)
+			_, err = Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 			Expect(err).NotTo(HaveOccurred())
-			_, err = Td.CreateTrafficTarget(destNs, trafficTarget)
+			_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 			Expect(err).NotTo(HaveOccurred())
 
 			srcPods, err := Td.Client.CoreV1().Pods(sourceNs).List(context.Background(), metav1.ListOptions{})
@@ -95,9 +95,9 @@ var _ = OSMDescribe("Test HTTP traffic with SMI TrafficTarget",
 				// Deploy policies to allow 'sourceOne' to access destination at HTTP path '/anything'
 				anythingPath := "/anything"
 				httpRGOne, trafficTargetOne := createPolicyForRoutePath(sourceOne, destName, anythingPath)
-				_, err = Td.CreateHTTPRouteGroup(sourceOne, httpRGOne)
+				_, err = Td.CreateHTTPRouteGroup(trafficTargetOne.Spec.Destination.Namespace, httpRGOne)
 				Expect(err).NotTo(HaveOccurred())
-				_, err = Td.CreateTrafficTarget(sourceOne, trafficTargetOne)
+				_, err = Td.CreateTrafficTarget(trafficTargetOne.Spec.Destination.Namespace, trafficTargetOne)
 				Expect(err).NotTo(HaveOccurred())
 
 				// Deploy policies to allow 'sourceTwo' to access destination at HTTP path '/foo'
@@ -105,9 +105,9 @@ var _ = OSMDescribe("Test HTTP traffic with SMI TrafficTarget",
 				// path '/anything' which is used to demonstrate RBAC per route.
 				fooPath := "/foo"
 				httpRGTwo, trafficTargetTwo := createPolicyForRoutePath(sourceTwo, destName, fooPath)
-				_, err = Td.CreateHTTPRouteGroup(sourceTwo, httpRGTwo)
+				_, err = Td.CreateHTTPRouteGroup(trafficTargetTwo.Spec.Destination.Namespace, httpRGTwo)
 				Expect(err).NotTo(HaveOccurred())
-				_, err = Td.CreateTrafficTarget(sourceTwo, trafficTargetTwo)
+				_, err = Td.CreateTrafficTarget(trafficTargetTwo.Spec.Destination.Namespace, trafficTargetTwo)
 				Expect(err).NotTo(HaveOccurred())
 
 				// HTTP request from 'sourceOne': http://<address>/anything
@@ -185,9 +185,10 @@ var _ = OSMDescribe("Test HTTP traffic with SMI TrafficTarget",
 		})
 	})
 
-// createPolicyForRoutePath creates an HTTPRouteGroup and TrafficTarget policy for the given source, destination and HTTP path regex
+// createPolicyForRoutePath creates an HTTPRouteGroup and TrafficTarget policy for the given source, destination and HTTP path regex.
+// The HTTPRouteGroup is named after the source, since the route groups of all the sources live in the destination namespace.
 func createPolicyForRoutePath(source string, destination string, pathRegex string) (smiSpecs.HTTPRouteGroup, smiAccess.TrafficTarget) {
-	routeGroupName := "test-route"
+	routeGroupName := fmt.Sprintf("%s-route", source)
 	routeMatchName := "allowed-route"
 
 	routeGroup := smiSpecs.HTTPRouteGroup{
@@ -96,9 +96,9 @@ func testTCPTraffic(permissiveMode bool) {
 			)
 
 			// Configs have to be put into a monitored NS
-			_, err = Td.CreateTCPRoute(sourceName, tcpRoute)
+			_, err = Td.CreateTCPRoute(trafficTarget.Spec.Destination.Namespace, tcpRoute)
 			Expect(err).NotTo(HaveOccurred())
-			_, err = Td.CreateTrafficTarget(sourceName, trafficTarget)
+			_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 			Expect(err).NotTo(HaveOccurred())
 		}
 
@@ -181,9 +181,9 @@ func testRecursiveTrafficSplit(appProtocol string) {
 						})
 
 					// Configs have to be put into same NS as server/destination
-					_, err := Td.CreateHTTPRouteGroup(srcClient, httpRG)
+					_, err := Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 
 				// TCP traffic
@@ -203,9 +203,9 @@ func testRecursiveTrafficSplit(appProtocol string) {
 					)
 
 					// Configs have to be put into same NS as server/destination
-					_, err := Td.CreateTCPRoute(srcClient, tcpRoute)
+					_, err := Td.CreateTCPRoute(trafficTarget.Spec.Destination.Namespace, tcpRoute)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 
 				default:
@@ -154,9 +154,9 @@ var _ = OSMDescribe("Test TrafficSplit where each backend shares the same Servic
 							DestinationSvcAccountName: svcAcc.Name,
 						})
 
-					_, err := Td.CreateHTTPRouteGroup(srcClient, httpRG)
+					_, err := Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 				}
 
@@ -163,9 +163,9 @@ func testTrafficSplit(appProtocol string) {
 							DestinationSvcAccountName: dstServer,
 						})
 
-					_, err := Td.CreateHTTPRouteGroup(srcClient, httpRG)
+					_, err := Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 
 				// TCP traffic
@@ -185,9 +185,9 @@ func testTrafficSplit(appProtocol string) {
 					)
 
 					// Configs have to be put into a monitored NS
-					_, err := Td.CreateTCPRoute(srcClient, tcpRoute)
+					_, err := Td.CreateTCPRoute(trafficTarget.Spec.Destination.Namespace, tcpRoute)
 					Expect(err).NotTo(HaveOccurred())
-					_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+					_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 					Expect(err).NotTo(HaveOccurred())
 
 				default:
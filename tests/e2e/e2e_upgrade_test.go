@@ -140,9 +140,9 @@ var _ = OSMDescribe("Upgrade from latest",
 					DestinationSvcAccountName: "server",
 				},
 			)
-			_, err = Td.CreateHTTPRouteGroup(ns, httpRG)
+			_, err = Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 			Expect(err).NotTo(HaveOccurred())
-			_, err = Td.CreateTrafficTarget(ns, trafficTarget)
+			_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 			Expect(err).NotTo(HaveOccurred())
 
 			// All ready. Expect client to reach server
@@ -235,9 +235,9 @@ var _ = OSMDescribe("Upgrade from latest",
 			Expect(err).NotTo(HaveOccurred())
 
 			// Re-deploy policies
-			_, err = Td.CreateHTTPRouteGroup(ns, httpRG)
+			_, err = Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 			Expect(err).NotTo(HaveOccurred())
-			_, err = Td.CreateTrafficTarget(ns, trafficTarget)
+			_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 			Expect(err).NotTo(HaveOccurred())
 
 			checkClientToServerOK()
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -205,6 +206,7 @@ type OsmTestData struct {
 	CleanupKindClusterBetweenTests bool   // Clean and re-create kind cluster between tests
 	CleanupKindCluster             bool   // Cleanup kind cluster upon test finish
 	ClusterVersion                 string // Kind cluster version, ex. v1.20.2
+	ClusterIPFamily                string // Kind cluster IP family, ex. ipv4, ipv6 or dual
 
 	// Cluster handles and rest config
 	Env             *cli.EnvSettings
@@ -230,6 +232,7 @@ func registerFlags(td *OsmTestData) {
 	flag.BoolVar(&td.CleanupKindCluster, "cleanupKindCluster", true, "Cleanup kind cluster upon exit")
 	flag.BoolVar(&td.CleanupKindClusterBetweenTests, "cleanupKindClusterBetweenTests", false, "Cleanup kind cluster between tests")
 	flag.StringVar(&td.ClusterVersion, "kindClusterVersion", "", "Kind cluster version, ex. v.1.20.2")
+	flag.StringVar(&td.ClusterIPFamily, "kindClusterIPFamily", "", "Kind cluster IP family, ex. ipv4, ipv6 or dual. Dual-stack requires a Kind version supporting it")
 
 	flag.StringVar(&td.CtrRegistryServer, "ctrRegistry", os.Getenv("CTR_REGISTRY"), "Container registry")
 	flag.StringVar(&td.CtrRegistryUser, "ctrRegistryUser", os.Getenv("CTR_REGISTRY_USER"), "Container registry")
@@ -340,6 +343,9 @@ nodeRegistration:
 		if Td.ClusterVersion != "" {
 			clusterConfig.Nodes[0].Image = fmt.Sprintf("kindest/node:%s", td.ClusterVersion)
 		}
+		if td.ClusterIPFamily != "" {
+			clusterConfig.Networking.IPFamily = v1alpha4.ClusterIPFamily(td.ClusterIPFamily)
+		}
 		if err := td.ClusterProvider.Create(td.ClusterName, cluster.CreateWithV1Alpha4Config(clusterConfig)); err != nil {
 			return errors.Wrap(err, "failed to create kind cluster")
 		}
@@ -663,6 +669,52 @@ func (td *OsmTestData) GetConfigMap(name, namespace string) (*corev1.ConfigMap,
 	return configmap, nil
 }
 
+// GetClusterIPFamilies returns the IP families of the cluster, based on the IP families of the default Kubernetes API service.
+// The first IP family returned is the cluster's primary IP family.
+func (td *OsmTestData) GetClusterIPFamilies() ([]corev1.IPFamily, error) {
+	svc, err := td.Client.CoreV1().Services(metav1.NamespaceDefault).Get(context.Background(), "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(svc.Spec.IPFamilies) > 0 {
+		return svc.Spec.IPFamilies, nil
+	}
+
+	// Clusters without dual-stack support do not set the IP families of services
+	if ip := net.ParseIP(svc.Spec.ClusterIP); ip != nil && ip.To4() == nil {
+		return []corev1.IPFamily{corev1.IPv6Protocol}, nil
+	}
+	return []corev1.IPFamily{corev1.IPv4Protocol}, nil
+}
+
+// IsDualStackCluster returns true if the cluster supports both IPv4 and IPv6
+func (td *OsmTestData) IsDualStackCluster() (bool, error) {
+	ipFamilies, err := td.GetClusterIPFamilies()
+	if err != nil {
+		return false, err
+	}
+	return len(ipFamilies) > 1, nil
+}
+
+// GetPodIPs returns the IP addresses of the given pod, one per IP family on dual-stack clusters.
+// The first IP address returned is the pod's primary IP.
+func (td *OsmTestData) GetPodIPs(ns, podName string) ([]string, error) {
+	pod, err := td.Client.CoreV1().Pods(ns).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var podIPs []string
+	for _, podIP := range pod.Status.PodIPs {
+		podIPs = append(podIPs, podIP.IP)
+	}
+	if len(podIPs) == 0 && pod.Status.PodIP != "" {
+		podIPs = append(podIPs, pod.Status.PodIP)
+	}
+	return podIPs, nil
+}
+
 // LoadOSMImagesIntoKind loads the OSM images to the node for Kind clusters
 func (td *OsmTestData) LoadOSMImagesIntoKind() error {
 	imageNames := []string{
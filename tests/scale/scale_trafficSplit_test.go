@@ -170,9 +170,9 @@ var _ = Describe("Scales a setup with client-servers and traffic splits til fail
 								DestinationSvcAccountName: dstServer,
 							})
 
-						_, err := Td.CreateHTTPRouteGroup(srcClient, httpRG)
+						_, err := Td.CreateHTTPRouteGroup(trafficTarget.Spec.Destination.Namespace, httpRG)
 						Expect(err).NotTo(HaveOccurred())
-						_, err = Td.CreateTrafficTarget(srcClient, trafficTarget)
+						_, err = Td.CreateTrafficTarget(trafficTarget.Spec.Destination.Namespace, trafficTarget)
 						Expect(err).NotTo(HaveOccurred())
 					}
 				}
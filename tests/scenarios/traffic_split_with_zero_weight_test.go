@@ -234,9 +234,13 @@ func TestRDSRespose(t *testing.T) {
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 
 			mockCatalog.EXPECT().GetServicesFromEnvoyCertificate(gomock.Any()).Return([]service.MeshService{tests.BookstoreV1Service}, nil).AnyTimes()
-			mockCatalog.EXPECT().ListInboundTrafficPolicies(gomock.Any(), gomock.Any()).Return(tc.expectedInboundPolicies).AnyTimes()
+			inboundPolicies := trafficpolicy.NewInboundPolicySet()
+			inboundPolicies.Add(trafficpolicy.TrafficTargetSource, tc.expectedInboundPolicies...)
+			mockCatalog.EXPECT().ListInboundPolicySet(gomock.Any(), gomock.Any()).Return(inboundPolicies).AnyTimes()
 			mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Return(tc.expectedOutboundPolicies).AnyTimes()
 			mockCatalog.EXPECT().GetIngressPoliciesForService(gomock.Any()).Return([]*trafficpolicy.InboundTrafficPolicy{}, nil).AnyTimes()
+			mockCatalog.EXPECT().ListRoutingPolicies(gomock.Any()).Return(nil).AnyTimes()
+			mockCatalog.EXPECT().GetEgressPolicy(gomock.Any()).Return(nil).AnyTimes()
 
 			actual, err := rds.NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil)
 			assert.Nil(err)
@@ -254,14 +258,14 @@ func TestRDSRespose(t *testing.T) {
 				t.Fatal(unmarshallErr)
 			}
 
-			// The rds-inbound will have the following virtual hosts :
-			// inbound_virtual-host|bookstore-v1.default
+			// The rds-inbound will have the following virtual hosts, sorted by name :
 			// inbound_virtual-host|bookstore-apex
+			// inbound_virtual-host|bookstore-v1.default
 			assert.Equal("rds-inbound", routeConfig.Name)
 			assert.Equal(2, len(routeConfig.VirtualHosts))
 
-			assert.Equal("inbound_virtual-host|bookstore-v1.default", routeConfig.VirtualHosts[0].Name)
-			assert.Equal(tests.BookstoreV1Hostnames, routeConfig.VirtualHosts[0].Domains)
+			assert.Equal("inbound_virtual-host|bookstore-apex", routeConfig.VirtualHosts[0].Name)
+			assert.Equal(tests.BookstoreApexHostnames, routeConfig.VirtualHosts[0].Domains)
 			assert.Equal(2, len(routeConfig.VirtualHosts[0].Routes))
 			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[0].Routes[0].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes[0].GetRoute().GetWeightedClusters().Clusters))
@@ -270,8 +274,8 @@ func TestRDSRespose(t *testing.T) {
 			assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes[1].GetRoute().GetWeightedClusters().Clusters))
 			assert.Equal(routeConfig.VirtualHosts[0].Routes[1].GetRoute().GetWeightedClusters().TotalWeight, &wrappers.UInt32Value{Value: uint32(100)})
 
-			assert.Equal("inbound_virtual-host|bookstore-apex", routeConfig.VirtualHosts[1].Name)
-			assert.Equal(tests.BookstoreApexHostnames, routeConfig.VirtualHosts[1].Domains)
+			assert.Equal("inbound_virtual-host|bookstore-v1.default", routeConfig.VirtualHosts[1].Name)
+			assert.Equal(tests.BookstoreV1Hostnames, routeConfig.VirtualHosts[1].Domains)
 			assert.Equal(2, len(routeConfig.VirtualHosts[1].Routes))
 			assert.Equal(tests.BookstoreBuyHTTPRoute.Path, routeConfig.VirtualHosts[1].Routes[0].GetMatch().GetSafeRegex().Regex)
 			assert.Equal(1, len(routeConfig.VirtualHosts[1].Routes[0].GetRoute().GetWeightedClusters().Clusters))
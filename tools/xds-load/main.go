@@ -0,0 +1,288 @@
+// Package main implements xds-load, a load test of the xDS server of the OSM controller. It connects a swarm of
+// fake proxies to the controller, subscribing to their configuration the way Envoy does, and measures the time
+// the controller takes to configure them and to push them configuration updates, along with its CPU and memory usage.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/bootstrap"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var log = logger.NewPretty("xds-load")
+
+const (
+	// triggerServiceName is the name of the service created and deleted to trigger configuration updates
+	triggerServiceName = "xds-load-trigger"
+
+	// pollInterval is the interval at which the fake proxies are polled for their progress
+	pollInterval = 250 * time.Millisecond
+
+	// certificateValidity is the validity period of the xDS certificates of the fake proxies
+	certificateValidity = 24 * time.Hour
+)
+
+var (
+	kubeConfigFile     = flag.String("kubeconfig", "", "Path to Kubernetes config file")
+	osmNamespace       = flag.String("osm-namespace", "osm-system", "Namespace of the OSM control plane")
+	caBundleSecretName = flag.String("ca-bundle-secret-name", "osm-ca-bundle", "Name of the Kubernetes Secret for the OSM CA bundle")
+	xdsAddress         = flag.String("xds-address", "localhost:15128", "Address (host:port) of the xDS server of the OSM controller")
+	metricsURL         = flag.String("metrics-url", "http://localhost:9091/metrics", "URL of the metrics endpoint of the OSM controller, empty to not report its resource usage")
+	numProxies         = flag.Int("proxies", 1000, "Number of fake proxies")
+	serviceAccounts    = flag.String("service-accounts", "bookbuyer/bookbuyer", "Comma separated list of NAMESPACE/NAME service accounts the fake proxies are evenly spread across")
+	connectRate        = flag.Int("connect-rate", 100, "Number of fake proxies connecting per second")
+	issueConcurrency   = flag.Int("issue-concurrency", 8, "Number of xDS certificates of the fake proxies issued concurrently")
+	numPushes          = flag.Int("pushes", 3, "Number of configuration updates triggered once the fake proxies are configured")
+	triggerNamespace   = flag.String("trigger-namespace", "bookbuyer", "Monitored namespace in which a service is created and deleted to trigger configuration updates")
+	phaseTimeout       = flag.Duration("timeout", 5*time.Minute, "Max time to wait for all the fake proxies to be configured, and for each configuration update to reach them")
+	maxConfigTime      = flag.Duration("max-p99-config-time", 0, "Fail if the p99 time for the fake proxies to receive their initial configuration exceeds this duration, 0 to disable")
+	maxUpdateTime      = flag.Duration("max-p99-update-time", 0, "Fail if the p99 time for a configuration update to reach the fake proxies exceeds this duration, 0 to disable")
+)
+
+func main() {
+	flag.Parse()
+
+	if err := run(); err != nil {
+		log.Error().Err(err).Msg("Load test failed")
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *numProxies <= 0 || *connectRate <= 0 || *issueConcurrency <= 0 {
+		return errors.New("The number of proxies, the connect rate and the issue concurrency must be positive")
+	}
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", *kubeConfigFile)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating kube config (kubeconfig=%s)", *kubeConfigFile)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
+
+	accounts, err := parseServiceAccounts(*serviceAccounts)
+	if err != nil {
+		return err
+	}
+
+	certManager, err := bootstrap.NewDevProxyCertManager(kubeClient, *osmNamespace, *caBundleSecretName)
+	if err != nil {
+		return errors.Wrapf(err, "Error loading the CA bundle in secret %s/%s, fake proxies require the Tresor certificate manager",
+			*osmNamespace, *caBundleSecretName)
+	}
+
+	log.Info().Msgf("Issuing the xDS certificates of %d fake proxies", *numProxies)
+	proxies, err := newFakeProxies(certManager, accounts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	report := &loadReport{proxies: proxies}
+	usage := scrapeControllerUsage()
+
+	// Connect the fake proxies at the configured rate, and wait for them to receive their initial configuration
+	log.Info().Msgf("Connecting %d fake proxies to %s at %d proxies/s", len(proxies), *xdsAddress, *connectRate)
+	var wg sync.WaitGroup
+	ticker := time.NewTicker(time.Second / time.Duration(*connectRate))
+	for _, proxy := range proxies {
+		<-ticker.C
+		wg.Add(1)
+		go func(proxy *fakeProxy) {
+			defer wg.Done()
+			if err := proxy.run(ctx, *xdsAddress); err != nil {
+				log.Debug().Err(err).Msgf("Fake proxy %s failed", proxy.name)
+			}
+		}(proxy)
+	}
+	ticker.Stop()
+
+	report.configTimes = waitForProxies(proxies, (*fakeProxy).getConfigTime)
+	report.connectUsage = measureUsageSince(usage)
+	usage = report.connectUsage
+
+	// Trigger configuration updates by alternately creating and deleting a service, and wait for each of them to reach
+	// the fake proxies
+	for i := 0; i < *numPushes; i++ {
+		markedAt := time.Now()
+		for _, proxy := range proxies {
+			proxy.mark(markedAt)
+		}
+
+		log.Info().Msgf("Triggering configuration update %d/%d", i+1, *numPushes)
+		if err := toggleTriggerService(kubeClient, i%2 == 0); err != nil {
+			return err
+		}
+
+		push := pushReport{updateTimes: waitForProxies(proxies, (*fakeProxy).getUpdateTime)}
+		push.usage = measureUsageSince(usage)
+		usage = push.usage
+		report.pushes = append(report.pushes, push)
+	}
+
+	// Leave the mesh as it was found
+	if *numPushes%2 == 1 {
+		if err := toggleTriggerService(kubeClient, false); err != nil {
+			log.Error().Err(err).Msgf("Error deleting service %s/%s", *triggerNamespace, triggerServiceName)
+		}
+	}
+
+	cancel()
+	wg.Wait()
+
+	report.print(os.Stdout)
+	return report.check()
+}
+
+// newFakeProxies returns the fake proxies, spread across the given service accounts
+func newFakeProxies(certManager certificate.Manager, accounts []service.K8sServiceAccount) ([]*fakeProxy, error) {
+	proxies := make([]*fakeProxy, *numProxies)
+	errs := make(chan error, *numProxies)
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < *issueConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				config := bootstrap.DevProxyConfig{
+					ProxyID:        uuid.New(),
+					Name:           fmt.Sprintf("xds-load-%d", i),
+					ServiceAccount: accounts[i%len(accounts)],
+				}
+				cn := catalog.NewCertCommonNameWithProxyID(config.ProxyID, config.ServiceAccount.Name, config.ServiceAccount.Namespace)
+				cert, err := certManager.IssueCertificate(cn, certificateValidity)
+				if err != nil {
+					errs <- errors.Wrapf(err, "Error issuing the xDS certificate of fake proxy %s", config.Name)
+					continue
+				}
+				proxies[i] = newFakeProxy(config.Name, bootstrap.GetDevProxyServiceNodeID(config), cert)
+			}
+		}()
+	}
+
+	for i := range proxies {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return proxies, nil
+}
+
+// waitForProxies waits until the given progress is reported by all the fake proxies still connected, or until the
+// phase times out, and returns the durations reported
+func waitForProxies(proxies []*fakeProxy, progress func(*fakeProxy) (time.Duration, bool)) []time.Duration {
+	deadline := time.Now().Add(*phaseTimeout)
+	for {
+		var durations []time.Duration
+		pending := 0
+		for _, proxy := range proxies {
+			if duration, ok := progress(proxy); ok {
+				durations = append(durations, duration)
+			} else if proxy.getError() == nil {
+				pending++
+			}
+		}
+
+		if pending == 0 || time.Now().After(deadline) {
+			if pending > 0 {
+				log.Warn().Msgf("Timed out waiting for %d fake proxies", pending)
+			}
+			return durations
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// toggleTriggerService creates or deletes the service triggering configuration updates
+func toggleTriggerService(kubeClient kubernetes.Interface, create bool) error {
+	services := kubeClient.CoreV1().Services(*triggerNamespace)
+	if !create {
+		err := services.Delete(context.Background(), triggerServiceName, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Error deleting service %s/%s", *triggerNamespace, triggerServiceName)
+		}
+		return nil
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      triggerServiceName,
+			Namespace: *triggerNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": triggerServiceName},
+			Ports: []corev1.ServicePort{
+				{
+					Name: "http",
+					Port: 80,
+				},
+			},
+		},
+	}
+	if _, err := services.Create(context.Background(), svc, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "Error creating service %s/%s", *triggerNamespace, triggerServiceName)
+	}
+	return nil
+}
+
+// scrapeControllerUsage returns the resource usage of the OSM controller, or nil if it is not reported
+func scrapeControllerUsage() *controllerUsage {
+	if *metricsURL == "" {
+		return nil
+	}
+	usage, err := getControllerUsage(*metricsURL)
+	if err != nil {
+		log.Warn().Err(err).Msg("Error scraping the resource usage of the OSM controller")
+		return nil
+	}
+	return &usage
+}
+
+// measureUsageSince returns the resource usage of the OSM controller, with the CPU usage averaged since the given
+// usage was scraped
+func measureUsageSince(previous *controllerUsage) *controllerUsage {
+	usage := scrapeControllerUsage()
+	if usage != nil && previous != nil {
+		usage.cpuCores = usage.cpuCoresSince(*previous)
+	}
+	return usage
+}
+
+// parseServiceAccounts parses the given comma separated list of NAMESPACE/NAME service accounts
+func parseServiceAccounts(list string) ([]service.K8sServiceAccount, error) {
+	var accounts []service.K8sServiceAccount
+	for _, item := range strings.Split(list, ",") {
+		chunks := strings.Split(strings.TrimSpace(item), "/")
+		if len(chunks) != 2 || chunks[0] == "" || chunks[1] == "" {
+			return nil, errors.Errorf("Invalid service account %q, expected NAMESPACE/NAME", item)
+		}
+		accounts = append(accounts, service.K8sServiceAccount{Namespace: chunks[0], Name: chunks[1]})
+	}
+	return accounts, nil
+}
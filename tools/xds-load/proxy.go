@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"sync"
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// fakeProxy is an ADS client subscribing to the xDS resources of a dev proxy the way Envoy does:
+// it subscribes to all the clusters and listeners, then to the endpoints of the EDS clusters, to the
+// route configurations of the listeners and to the secrets referenced by the clusters and listeners.
+type fakeProxy struct {
+	name   string
+	nodeID string
+	cert   certificate.Certificater
+
+	mu sync.Mutex
+
+	// connectedAt is the time at which the stream to the controller was opened
+	connectedAt time.Time
+
+	// readyAt is the time at which the proxy received its initial configuration, zero until then
+	readyAt time.Time
+
+	// configTypes are the types of the responses received with the initial configuration
+	configTypes []envoy.TypeURI
+
+	// markedAt is the time of the last call to mark, and received holds the time at which the first response
+	// of each type was received since then
+	markedAt time.Time
+	received map[envoy.TypeURI]time.Time
+
+	// subscriptions holds the resource names the proxy subscribed to, by type
+	subscriptions map[envoy.TypeURI][]string
+
+	// responseBytes is the total size of the resources received
+	responseBytes int
+
+	// err is the error that closed the stream, if any
+	err error
+}
+
+func newFakeProxy(name, nodeID string, cert certificate.Certificater) *fakeProxy {
+	return &fakeProxy{
+		name:          name,
+		nodeID:        nodeID,
+		cert:          cert,
+		received:      make(map[envoy.TypeURI]time.Time),
+		subscriptions: make(map[envoy.TypeURI][]string),
+	}
+}
+
+// run connects the proxy to the xDS server at the given address, and processes the responses until the context is
+// canceled or the stream fails.
+func (p *fakeProxy) run(ctx context.Context, xdsAddress string) error {
+	tlsConfig, err := p.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.DialContext(ctx, xdsAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return errors.Wrapf(err, "Error connecting proxy %s to %s", p.name, xdsAddress)
+	}
+	defer conn.Close() //nolint: errcheck,gosec
+
+	stream, err := xds_discovery.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "Error opening the ADS stream of proxy %s", p.name)
+	}
+
+	p.mu.Lock()
+	p.connectedAt = time.Now()
+	p.markedAt = p.connectedAt
+	p.mu.Unlock()
+
+	// Like Envoy, the proxy starts by subscribing to all the clusters and listeners, and only sets the node
+	// on its first request.
+	for i, typeURI := range []envoy.TypeURI{envoy.TypeCDS, envoy.TypeLDS} {
+		request := &xds_discovery.DiscoveryRequest{TypeUrl: typeURI.String()}
+		if i == 0 {
+			request.Node = &xds_core.Node{Id: p.nodeID}
+		}
+		if err := stream.Send(request); err != nil {
+			return p.closed(errors.Wrapf(err, "Error sending %s request of proxy %s", envoy.XDSShortURINames[typeURI], p.name))
+		}
+	}
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return p.closed(errors.Wrapf(err, "Error receiving response of proxy %s", p.name))
+		}
+
+		requests, err := p.handleResponse(response)
+		if err != nil {
+			return p.closed(err)
+		}
+		for _, request := range requests {
+			if err := stream.Send(request); err != nil {
+				return p.closed(errors.Wrapf(err, "Error sending %s request of proxy %s", request.TypeUrl, p.name))
+			}
+		}
+	}
+}
+
+func (p *fakeProxy) tlsConfig() (*tls.Config, error) {
+	clientCert, err := tls.X509KeyPair(p.cert.GetCertificateChain(), p.cert.GetPrivateKey())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error loading the xDS certificate of proxy %s", p.name)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if ok := rootCAs.AppendCertsFromPEM(p.cert.GetIssuingCA()); !ok {
+		return nil, errors.Errorf("Error loading the issuing CA of the xDS certificate of proxy %s", p.name)
+	}
+
+	return &tls.Config{
+		ServerName:   constants.XDSServerCertificateCommonName,
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// handleResponse records the given response, and returns the requests acknowledging it and updating the
+// subscriptions of the proxy to the resources it references.
+func (p *fakeProxy) handleResponse(response *xds_discovery.DiscoveryResponse) ([]*xds_discovery.DiscoveryRequest, error) {
+	typeURI := envoy.TypeURI(response.TypeUrl)
+
+	// The resources subscribed to following this response, by type
+	subscribe := make(map[envoy.TypeURI][]string)
+	var err error
+	switch typeURI {
+	case envoy.TypeCDS:
+		subscribe[envoy.TypeEDS], subscribe[envoy.TypeSDS], err = getClusterSubscriptions(response)
+	case envoy.TypeLDS:
+		subscribe[envoy.TypeRDS], subscribe[envoy.TypeSDS], err = getListenerSubscriptions(response)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading %s response of proxy %s", envoy.XDSShortURINames[typeURI], p.name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := p.received[typeURI]; !ok {
+		p.received[typeURI] = now
+	}
+	for _, resource := range response.Resources {
+		p.responseBytes += len(resource.Value)
+	}
+
+	requests := []*xds_discovery.DiscoveryRequest{
+		{
+			TypeUrl:       response.TypeUrl,
+			VersionInfo:   response.VersionInfo,
+			ResponseNonce: response.Nonce,
+			ResourceNames: p.subscriptions[typeURI],
+		},
+	}
+
+	for _, subscribedType := range []envoy.TypeURI{envoy.TypeEDS, envoy.TypeRDS, envoy.TypeSDS} {
+		names, ok := subscribe[subscribedType]
+		if !ok {
+			continue
+		}
+		// The secrets are referenced by both the clusters and the listeners
+		if subscribedType == envoy.TypeSDS {
+			names = mergeNames(p.subscriptions[envoy.TypeSDS], names)
+		}
+		if equalNames(names, p.subscriptions[subscribedType]) {
+			continue
+		}
+		p.subscriptions[subscribedType] = names
+		requests = append(requests, &xds_discovery.DiscoveryRequest{
+			TypeUrl:       subscribedType.String(),
+			ResourceNames: names,
+		})
+	}
+
+	if p.readyAt.IsZero() && p.isReady() {
+		p.readyAt = now
+		for typeURI := range p.received {
+			p.configTypes = append(p.configTypes, typeURI)
+		}
+	}
+
+	return requests, nil
+}
+
+// isReady returns true once the proxy received the clusters and listeners, and a response for each of its other
+// subscriptions. The caller must hold the lock.
+func (p *fakeProxy) isReady() bool {
+	for _, typeURI := range []envoy.TypeURI{envoy.TypeCDS, envoy.TypeLDS} {
+		if _, ok := p.received[typeURI]; !ok {
+			return false
+		}
+	}
+	for typeURI, names := range p.subscriptions {
+		if _, ok := p.received[typeURI]; len(names) > 0 && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *fakeProxy) closed(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+	return err
+}
+
+// getConfigTime returns the time the proxy took to receive its initial configuration, and false if it did not
+// receive it yet.
+func (p *fakeProxy) getConfigTime() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.readyAt.IsZero() {
+		return 0, false
+	}
+	return p.readyAt.Sub(p.connectedAt), true
+}
+
+// mark starts measuring the time until the proxy receives the next update of its configuration
+func (p *fakeProxy) mark(at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.markedAt = at
+	p.received = make(map[envoy.TypeURI]time.Time)
+}
+
+// getUpdateTime returns the time from the last call to mark until the proxy received a response for each type of its
+// initial configuration, and false if it did not receive all of them yet.
+func (p *fakeProxy) getUpdateTime() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.readyAt.IsZero() {
+		return 0, false
+	}
+
+	var updatedAt time.Time
+	for _, typeURI := range p.configTypes {
+		receivedAt, ok := p.received[typeURI]
+		if !ok {
+			return 0, false
+		}
+		if receivedAt.After(updatedAt) {
+			updatedAt = receivedAt
+		}
+	}
+	return updatedAt.Sub(p.markedAt), true
+}
+
+func (p *fakeProxy) getResponseBytes() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.responseBytes
+}
+
+func (p *fakeProxy) getError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// getClusterSubscriptions returns the names of the EDS clusters and of the secrets referenced by the clusters of the
+// given CDS response
+func getClusterSubscriptions(response *xds_discovery.DiscoveryResponse) (clusterNames []string, secretNames []string, err error) {
+	for _, resource := range response.Resources {
+		cluster := &xds_cluster.Cluster{}
+		if err := ptypes.UnmarshalAny(resource, cluster); err != nil {
+			return nil, nil, err
+		}
+
+		if cluster.GetType() == xds_cluster.Cluster_EDS {
+			clusterNames = append(clusterNames, cluster.Name)
+		}
+
+		if cluster.TransportSocket == nil || cluster.TransportSocket.GetTypedConfig() == nil {
+			continue
+		}
+		tlsContext := &xds_auth.UpstreamTlsContext{}
+		if err := ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), tlsContext); err != nil {
+			return nil, nil, err
+		}
+		secretNames = append(secretNames, getSecretNames(tlsContext.CommonTlsContext)...)
+	}
+	return uniqueNames(clusterNames), uniqueNames(secretNames), nil
+}
+
+// getListenerSubscriptions returns the names of the route configurations and of the secrets referenced by the
+// listeners of the given LDS response
+func getListenerSubscriptions(response *xds_discovery.DiscoveryResponse) (routeNames []string, secretNames []string, err error) {
+	for _, resource := range response.Resources {
+		listener := &xds_listener.Listener{}
+		if err := ptypes.UnmarshalAny(resource, listener); err != nil {
+			return nil, nil, err
+		}
+
+		for _, filterChain := range listener.FilterChains {
+			for _, filter := range filterChain.Filters {
+				if filter.Name != wellknown.HTTPConnectionManager || filter.GetTypedConfig() == nil {
+					continue
+				}
+				hcm := &xds_hcm.HttpConnectionManager{}
+				if err := ptypes.UnmarshalAny(filter.GetTypedConfig(), hcm); err != nil {
+					return nil, nil, err
+				}
+				if rds := hcm.GetRds(); rds != nil {
+					routeNames = append(routeNames, rds.RouteConfigName)
+				}
+			}
+
+			if filterChain.TransportSocket == nil || filterChain.TransportSocket.GetTypedConfig() == nil {
+				continue
+			}
+			tlsContext := &xds_auth.DownstreamTlsContext{}
+			if err := ptypes.UnmarshalAny(filterChain.TransportSocket.GetTypedConfig(), tlsContext); err != nil {
+				return nil, nil, err
+			}
+			secretNames = append(secretNames, getSecretNames(tlsContext.CommonTlsContext)...)
+		}
+	}
+	return uniqueNames(routeNames), uniqueNames(secretNames), nil
+}
+
+func getSecretNames(tlsContext *xds_auth.CommonTlsContext) []string {
+	var names []string
+	if tlsContext == nil {
+		return names
+	}
+	for _, sdsConfig := range tlsContext.TlsCertificateSdsSecretConfigs {
+		names = append(names, sdsConfig.Name)
+	}
+	if sdsConfig := tlsContext.GetValidationContextSdsSecretConfig(); sdsConfig != nil {
+		names = append(names, sdsConfig.Name)
+	}
+	return names
+}
+
+// uniqueNames returns the given names sorted, without duplicates
+func uniqueNames(names []string) []string {
+	sort.Strings(names)
+	unique := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
+func mergeNames(names, otherNames []string) []string {
+	merged := append(append([]string{}, names...), otherNames...)
+	return uniqueNames(merged)
+}
+
+func equalNames(names, otherNames []string) bool {
+	if len(names) != len(otherNames) {
+		return false
+	}
+	for i := range names {
+		if names[i] != otherNames[i] {
+			return false
+		}
+	}
+	return true
+}
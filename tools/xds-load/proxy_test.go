@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestHandleResponse(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy := newFakeProxy("xds-load-0", "node-id", tresor.NewFakeCertificate())
+	proxy.mark(time.Now())
+
+	// The clusters subscribe the proxy to the endpoints of the EDS clusters, and to the secrets of the clusters
+	requests, err := proxy.handleResponse(newResponse(t, envoy.TypeCDS, "1", "nonce-1",
+		&xds_cluster.Cluster{
+			Name:                 "bookstore/bookstore",
+			ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_EDS},
+			TransportSocket:      newTransportSocket(t, &xds_auth.UpstreamTlsContext{CommonTlsContext: newTLSContext("service-cert:bookbuyer/bookbuyer", "root-cert-for-mtls-outbound:bookstore/bookstore")}),
+		},
+		&xds_cluster.Cluster{
+			Name:                 "passthrough-outbound",
+			ClusterDiscoveryType: &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_ORIGINAL_DST},
+		},
+	))
+	assert.Nil(err)
+	assert.Equal([]*xds_discovery.DiscoveryRequest{
+		{TypeUrl: envoy.TypeCDS.String(), VersionInfo: "1", ResponseNonce: "nonce-1"},
+		{TypeUrl: envoy.TypeEDS.String(), ResourceNames: []string{"bookstore/bookstore"}},
+		{TypeUrl: envoy.TypeSDS.String(), ResourceNames: []string{"root-cert-for-mtls-outbound:bookstore/bookstore", "service-cert:bookbuyer/bookbuyer"}},
+	}, requests)
+
+	// The listeners subscribe the proxy to their route configurations, and add their secrets to its subscriptions
+	requests, err = proxy.handleResponse(newResponse(t, envoy.TypeLDS, "1", "nonce-2",
+		&xds_listener.Listener{
+			Name: "outbound-listener",
+			FilterChains: []*xds_listener.FilterChain{
+				{
+					Filters: []*xds_listener.Filter{
+						{
+							Name:       wellknown.HTTPConnectionManager,
+							ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: newAny(t, newHCM("RDS_Outbound"))},
+						},
+					},
+				},
+				{
+					Filters: []*xds_listener.Filter{
+						{
+							Name:       wellknown.HTTPConnectionManager,
+							ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: newAny(t, newHCM("RDS_Inbound"))},
+						},
+					},
+					TransportSocket: newTransportSocket(t, &xds_auth.DownstreamTlsContext{CommonTlsContext: newTLSContext("service-cert:bookbuyer/bookbuyer", "root-cert-for-mtls-inbound:bookbuyer/bookbuyer")}),
+				},
+			},
+		},
+	))
+	assert.Nil(err)
+	assert.Equal([]*xds_discovery.DiscoveryRequest{
+		{TypeUrl: envoy.TypeLDS.String(), VersionInfo: "1", ResponseNonce: "nonce-2"},
+		{TypeUrl: envoy.TypeRDS.String(), ResourceNames: []string{"RDS_Inbound", "RDS_Outbound"}},
+		{TypeUrl: envoy.TypeSDS.String(), ResourceNames: []string{"root-cert-for-mtls-inbound:bookbuyer/bookbuyer", "root-cert-for-mtls-outbound:bookstore/bookstore", "service-cert:bookbuyer/bookbuyer"}},
+	}, requests)
+
+	// The proxy is configured once it received a response for each of its subscriptions
+	for _, typeURI := range []envoy.TypeURI{envoy.TypeEDS, envoy.TypeRDS} {
+		_, ok := proxy.getConfigTime()
+		assert.False(ok)
+
+		requests, err = proxy.handleResponse(newResponse(t, typeURI, "1", "nonce-3"))
+		assert.Nil(err)
+		assert.Len(requests, 1)
+		assert.Equal(proxy.subscriptions[typeURI], requests[0].ResourceNames)
+	}
+	_, ok := proxy.getConfigTime()
+	assert.False(ok)
+
+	_, err = proxy.handleResponse(newResponse(t, envoy.TypeSDS, "1", "nonce-4"))
+	assert.Nil(err)
+	_, ok = proxy.getConfigTime()
+	assert.True(ok)
+	assert.ElementsMatch([]envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS, envoy.TypeSDS}, proxy.configTypes)
+
+	// An update reaches the proxy once it received a response for each type of its initial configuration
+	proxy.mark(time.Now())
+	_, ok = proxy.getUpdateTime()
+	assert.False(ok)
+	for _, typeURI := range envoy.XDSResponseOrder {
+		_, ok = proxy.getUpdateTime()
+		assert.False(ok)
+		_, err = proxy.handleResponse(newResponse(t, typeURI, "2", "nonce-5"))
+		assert.Nil(err)
+	}
+	_, ok = proxy.getUpdateTime()
+	assert.True(ok)
+}
+
+func TestUniqueNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Nil(uniqueNames(nil))
+	assert.Equal([]string{"a", "b", "c"}, uniqueNames([]string{"c", "a", "b", "a", "c"}))
+	assert.Equal([]string{"a", "b"}, mergeNames([]string{"b"}, []string{"a", "b"}))
+	assert.True(equalNames(nil, []string{}))
+	assert.False(equalNames([]string{"a"}, []string{"b"}))
+}
+
+func newResponse(t *testing.T, typeURI envoy.TypeURI, version, nonce string, resources ...proto.Message) *xds_discovery.DiscoveryResponse {
+	response := &xds_discovery.DiscoveryResponse{
+		TypeUrl:     typeURI.String(),
+		VersionInfo: version,
+		Nonce:       nonce,
+	}
+	for _, resource := range resources {
+		response.Resources = append(response.Resources, newAny(t, resource))
+	}
+	return response
+}
+
+func newAny(t *testing.T, msg proto.Message) *any.Any {
+	marshalled, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return marshalled
+}
+
+func newTransportSocket(t *testing.T, tlsContext proto.Message) *xds_core.TransportSocket {
+	return &xds_core.TransportSocket{
+		Name:       wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{TypedConfig: newAny(t, tlsContext)},
+	}
+}
+
+func newTLSContext(certName, validationName string) *xds_auth.CommonTlsContext {
+	return &xds_auth.CommonTlsContext{
+		TlsCertificateSdsSecretConfigs: []*xds_auth.SdsSecretConfig{{Name: certName}},
+		ValidationContextType: &xds_auth.CommonTlsContext_ValidationContextSdsSecretConfig{
+			ValidationContextSdsSecretConfig: &xds_auth.SdsSecretConfig{Name: validationName},
+		},
+	}
+}
+
+func newHCM(routeConfigName string) *xds_hcm.HttpConnectionManager {
+	return &xds_hcm.HttpConnectionManager{
+		RouteSpecifier: &xds_hcm.HttpConnectionManager_Rds{
+			Rds: &xds_hcm.Rds{RouteConfigName: routeConfigName},
+		},
+	}
+}
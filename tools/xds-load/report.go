@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxReportedErrors is the max number of errors of fake proxies reported
+const maxReportedErrors = 10
+
+// loadReport is the result of a load test
+type loadReport struct {
+	proxies []*fakeProxy
+
+	// configTimes are the times the fake proxies took to receive their initial configuration
+	configTimes []time.Duration
+
+	// connectUsage is the resource usage of the controller once the fake proxies are configured, nil if not reported
+	connectUsage *controllerUsage
+
+	pushes []pushReport
+}
+
+// pushReport is the result of a configuration update pushed to the fake proxies
+type pushReport struct {
+	// updateTimes are the times from the trigger of the update to its receipt by the fake proxies
+	updateTimes []time.Duration
+
+	// usage is the resource usage of the controller once the update reached the fake proxies, nil if not reported
+	usage *controllerUsage
+}
+
+func (r *loadReport) print(w io.Writer) {
+	var failed []error
+	responseBytes := 0
+	for _, proxy := range r.proxies {
+		if err := proxy.getError(); err != nil {
+			failed = append(failed, err)
+		}
+		responseBytes += proxy.getResponseBytes()
+	}
+
+	fmt.Fprintf(w, "Proxies:               %d, %d failed\n", len(r.proxies), len(failed))
+	fmt.Fprintf(w, "Initial configuration: %s\n", summarize(r.configTimes))
+	fmt.Fprintf(w, "Received per proxy:    %d bytes\n", responseBytes/len(r.proxies))
+	printUsage(w, r.connectUsage)
+
+	for i, push := range r.pushes {
+		fmt.Fprintf(w, "Update %d:              %s\n", i+1, summarize(push.updateTimes))
+		printUsage(w, push.usage)
+	}
+
+	for i, err := range failed {
+		if i == maxReportedErrors {
+			fmt.Fprintf(w, "... and %d more errors\n", len(failed)-maxReportedErrors)
+			break
+		}
+		fmt.Fprintf(w, "Error: %s\n", err)
+	}
+}
+
+func printUsage(w io.Writer, usage *controllerUsage) {
+	if usage == nil {
+		return
+	}
+	fmt.Fprintf(w, "  Controller:          %.2f CPU cores, %.0f MiB\n", usage.cpuCores, usage.memoryMiB())
+}
+
+// check returns an error if a fake proxy failed or did not receive its configuration, or if the configured latency
+// thresholds are exceeded
+func (r *loadReport) check() error {
+	if failed := countFailed(r.proxies); failed > 0 {
+		return errors.Errorf("%d fake proxies failed", failed)
+	}
+
+	if len(r.configTimes) < len(r.proxies) {
+		return errors.Errorf("%d fake proxies did not receive their initial configuration", len(r.proxies)-len(r.configTimes))
+	}
+	if p99 := summarize(r.configTimes).p99; *maxConfigTime > 0 && p99 > *maxConfigTime {
+		return errors.Errorf("p99 initial configuration time %s exceeds %s", round(p99), *maxConfigTime)
+	}
+
+	for i, push := range r.pushes {
+		if len(push.updateTimes) < len(r.proxies) {
+			return errors.Errorf("Update %d did not reach %d fake proxies", i+1, len(r.proxies)-len(push.updateTimes))
+		}
+		if p99 := summarize(push.updateTimes).p99; *maxUpdateTime > 0 && p99 > *maxUpdateTime {
+			return errors.Errorf("p99 time for update %d to reach the fake proxies %s exceeds %s", i+1, round(p99), *maxUpdateTime)
+		}
+	}
+	return nil
+}
+
+func countFailed(proxies []*fakeProxy) int {
+	failed := 0
+	for _, proxy := range proxies {
+		if proxy.getError() != nil {
+			failed++
+		}
+	}
+	return failed
+}
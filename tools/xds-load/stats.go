@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// Metrics of the process collector of the OSM controller
+	processCPUSecondsMetric     = "process_cpu_seconds_total"
+	processResidentMemoryMetric = "process_resident_memory_bytes"
+)
+
+// latencySummary summarizes a set of latencies
+type latencySummary struct {
+	count int
+	min   time.Duration
+	p50   time.Duration
+	p90   time.Duration
+	p99   time.Duration
+	max   time.Duration
+}
+
+// summarize returns the summary of the given latencies
+func summarize(latencies []time.Duration) latencySummary {
+	if len(latencies) == 0 {
+		return latencySummary{}
+	}
+
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return latencySummary{
+		count: len(sorted),
+		min:   sorted[0],
+		p50:   percentile(sorted, 50),
+		p90:   percentile(sorted, 90),
+		p99:   percentile(sorted, 99),
+		max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the given percentile of the given sorted latencies, using the nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (s latencySummary) String() string {
+	if s.count == 0 {
+		return "n=0"
+	}
+	return fmt.Sprintf("n=%d min=%s p50=%s p90=%s p99=%s max=%s",
+		s.count, round(s.min), round(s.p50), round(s.p90), round(s.p99), round(s.max))
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// controllerUsage is the resource usage of the OSM controller at a point in time
+type controllerUsage struct {
+	at          time.Time
+	cpuSeconds  float64
+	memoryBytes float64
+
+	// cpuCores is the average number of CPU cores used by the controller since the previous scrape
+	cpuCores float64
+}
+
+// getControllerUsage scrapes the resource usage of the OSM controller from its metrics endpoint
+func getControllerUsage(metricsURL string) (controllerUsage, error) {
+	usage := controllerUsage{at: time.Now()}
+
+	// #nosec G107: Potential HTTP request made with variable url
+	resp, err := http.Get(metricsURL)
+	if err != nil {
+		return usage, errors.Wrapf(err, "Error scraping the OSM controller metrics at %s", metricsURL)
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return usage, errors.Errorf("Error scraping the OSM controller metrics at %s: %s", metricsURL, resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return usage, errors.Wrapf(err, "Error parsing the OSM controller metrics at %s", metricsURL)
+	}
+
+	for name, value := range map[string]*float64{
+		processCPUSecondsMetric:     &usage.cpuSeconds,
+		processResidentMemoryMetric: &usage.memoryBytes,
+	} {
+		family, ok := families[name]
+		if !ok || len(family.Metric) == 0 {
+			return usage, errors.Errorf("Metric %s is not exposed by the OSM controller at %s", name, metricsURL)
+		}
+		metric := family.Metric[0]
+		switch {
+		case metric.Counter != nil:
+			*value = metric.Counter.GetValue()
+		case metric.Gauge != nil:
+			*value = metric.Gauge.GetValue()
+		}
+	}
+	return usage, nil
+}
+
+// cpuCoresSince returns the average number of CPU cores used by the controller since the given usage was scraped
+func (u controllerUsage) cpuCoresSince(previous controllerUsage) float64 {
+	elapsed := u.at.Sub(previous.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return (u.cpuSeconds - previous.cpuSeconds) / elapsed
+}
+
+func (u controllerUsage) memoryMiB() float64 {
+	return u.memoryBytes / (1 << 20)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		name      string
+		latencies []time.Duration
+		expected  latencySummary
+	}{
+		{
+			name:      "no latencies",
+			latencies: nil,
+			expected:  latencySummary{},
+		},
+		{
+			name:      "single latency",
+			latencies: []time.Duration{time.Second},
+			expected: latencySummary{
+				count: 1,
+				min:   time.Second,
+				p50:   time.Second,
+				p90:   time.Second,
+				p99:   time.Second,
+				max:   time.Second,
+			},
+		},
+		{
+			name: "unsorted latencies",
+			latencies: []time.Duration{
+				10 * time.Millisecond, 1 * time.Millisecond, 9 * time.Millisecond, 2 * time.Millisecond, 8 * time.Millisecond,
+				3 * time.Millisecond, 7 * time.Millisecond, 4 * time.Millisecond, 6 * time.Millisecond, 5 * time.Millisecond,
+			},
+			expected: latencySummary{
+				count: 10,
+				min:   1 * time.Millisecond,
+				p50:   5 * time.Millisecond,
+				p90:   9 * time.Millisecond,
+				p99:   10 * time.Millisecond,
+				max:   10 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, summarize(tc.latencies))
+		})
+	}
+}
+
+func TestLatencySummaryString(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("n=0", latencySummary{}.String())
+	assert.Equal("n=2 min=1ms p50=1ms p90=2ms p99=2ms max=2ms",
+		summarize([]time.Duration{1200 * time.Microsecond, 2 * time.Millisecond}).String())
+}
+
+func TestGetControllerUsage(t *testing.T) {
+	assert := tassert.New(t)
+
+	metrics := `# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 12.5
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+08
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, metrics)
+	}))
+	defer server.Close()
+
+	usage, err := getControllerUsage(server.URL + "/metrics")
+	assert.Nil(err)
+	assert.Equal(12.5, usage.cpuSeconds)
+	assert.Equal(100.0, usage.memoryMiB())
+
+	_, err = getControllerUsage(server.URL + "/unknown")
+	assert.NotNil(err)
+
+	previous := controllerUsage{at: usage.at.Add(-5 * time.Second), cpuSeconds: 10}
+	assert.Equal(0.5, usage.cpuCoresSince(previous))
+}
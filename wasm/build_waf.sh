@@ -0,0 +1,23 @@
+#!/bin/sh
+# Builds the WAF WASM module packaged in the osm-controller image from the given release of coraza-proxy-wasm, whose
+# source must match the given checksum of the Go checksum database. Runs in the TinyGo image used by the CI of the
+# release.
+set -e
+
+VERSION="$1"
+SUM="$2"
+OUT="$3"
+
+go mod download -json "github.com/corazawaf/coraza-proxy-wasm@${VERSION}" > /tmp/coraza-proxy-wasm.json
+if ! grep -q "\"Sum\": \"${SUM}\"" /tmp/coraza-proxy-wasm.json; then
+    echo "The source of coraza-proxy-wasm ${VERSION} does not match the pinned checksum ${SUM}" >&2
+    exit 1
+fi
+
+SRC=$(sed -n 's/.*"Dir": "\(.*\)",/\1/p' /tmp/coraza-proxy-wasm.json)
+cp -r "${SRC}" /tmp/coraza-proxy-wasm
+chmod -R u+w /tmp/coraza-proxy-wasm
+cd /tmp/coraza-proxy-wasm
+
+# The build flags of the release: the module relies on the custom allocator of nottinygc tuned for Envoy
+tinygo build -o "${OUT}" -gc=custom -opt=2 -scheduler=none -target=wasi -tags='custommalloc nottinygc_envoy' .
@@ -10,32 +10,56 @@ static bool isInbound()
   return direction == 1;
 }
 
+// Returns the value of the given request header, removing it from the request
+static std::string takeRequestHeader(std::string_view key)
+{
+  std::string value = getRequestHeader(key).get()->toString();
+  removeRequestHeader(key);
+  if (value.empty())
+  {
+    return "unknown";
+  }
+  return value;
+}
+
 using RqTotalCounter = Counter<std::string, std::string, std::string, std::string, std::string, std::string, std::string, std::string, std::string>;
 using RqDurationHist = Histogram<std::string, std::string, std::string, std::string, std::string, std::string, std::string, std::string>;
 
+static RqTotalCounter *newRqTotalCounter(std::string_view name)
+{
+  return RqTotalCounter::New(name,
+                             "response_code",
+                             "source_namespace",
+                             "source_kind",
+                             "source_name",
+                             "source_pod",
+                             "destination_namespace",
+                             "destination_kind",
+                             "destination_name",
+                             "destination_pod");
+}
+
+static RqDurationHist *newRqDurationHist(std::string_view name)
+{
+  return RqDurationHist::New(name,
+                             "source_namespace",
+                             "source_kind",
+                             "source_name",
+                             "source_pod",
+                             "destination_namespace",
+                             "destination_kind",
+                             "destination_name",
+                             "destination_pod");
+}
+
 class StatsContext : public Context
 {
 public:
   explicit StatsContext(uint32_t id, RootContext *root) : Context(id, root),
-                                                          rq_total(RqTotalCounter::New("osm_request_total",
-                                                                                       "response_code",
-                                                                                       "source_namespace",
-                                                                                       "source_kind",
-                                                                                       "source_name",
-                                                                                       "source_pod",
-                                                                                       "destination_namespace",
-                                                                                       "destination_kind",
-                                                                                       "destination_name",
-                                                                                       "destination_pod")),
-                                                          rq_duration(RqDurationHist::New("osm_request_duration_ms",
-                                                                                          "source_namespace",
-                                                                                          "source_kind",
-                                                                                          "source_name",
-                                                                                          "source_pod",
-                                                                                          "destination_namespace",
-                                                                                          "destination_kind",
-                                                                                          "destination_name",
-                                                                                          "destination_pod"))
+                                                          rq_total(newRqTotalCounter("osm_request_total")),
+                                                          rq_duration(newRqDurationHist("osm_request_duration_ms")),
+                                                          inbound_rq_total(newRqTotalCounter("osm_inbound_request_total")),
+                                                          inbound_rq_duration(newRqDurationHist("osm_inbound_request_duration_ms"))
   {
   }
 
@@ -46,8 +70,12 @@ public:
   FilterHeadersStatus onResponseHeaders(uint32_t headers, bool end_of_stream) override;
 
 private:
+  // Requests made by the proxy, recorded by the client
   RqTotalCounter *rq_total;
   RqDurationHist *rq_duration;
+  // Requests received by the proxy, recorded by the server
+  RqTotalCounter *inbound_rq_total;
+  RqDurationHist *inbound_rq_duration;
   std::string source_pod, source_namespace, source_kind, source_name;
   std::string destination_namespace, destination_kind, destination_name, destination_pod;
   uint64_t start_time;
@@ -56,57 +84,61 @@ static RegisterContextFactory register_StatsContext(CONTEXT_FACTORY(StatsContext
 
 void StatsContext::onCreate()
 {
-  if (isInbound())
-  {
-    return;
-  }
-
   start_time = getCurrentTimeNanoseconds();
 }
 
 void StatsContext::onDone()
 {
-  if (isInbound())
-  {
-    return;
-  }
-
   uint64_t duration_ns = getCurrentTimeNanoseconds() - start_time;
   int64_t duration_ms = duration_ns / 1000 / 1000;
 
-  rq_duration->record(duration_ms,
-                      source_namespace, source_kind, source_name, source_pod,
-                      destination_namespace, destination_kind, destination_name, destination_pod);
+  RqDurationHist *duration = isInbound() ? inbound_rq_duration : rq_duration;
+  duration->record(duration_ms,
+                   source_namespace, source_kind, source_name, source_pod,
+                   destination_namespace, destination_kind, destination_name, destination_pod);
 }
 
 FilterHeadersStatus StatsContext::onRequestHeaders(uint32_t headers, bool end_of_stream)
 {
   if (isInbound())
   {
+    // The source is described by the peer headers added by the proxy of the client, and the destination by the
+    // headers added by this proxy
+    source_namespace = takeRequestHeader("osm-stats-peer-namespace");
+    source_kind = takeRequestHeader("osm-stats-peer-kind");
+    source_name = takeRequestHeader("osm-stats-peer-name");
+    source_pod = takeRequestHeader("osm-stats-peer-pod");
+
+    destination_namespace = takeRequestHeader("osm-stats-namespace");
+    destination_kind = takeRequestHeader("osm-stats-kind");
+    destination_name = takeRequestHeader("osm-stats-name");
+    destination_pod = takeRequestHeader("osm-stats-pod");
+
     return FilterHeadersStatus::Continue;
   }
 
-  source_namespace = getRequestHeader("osm-stats-namespace").get()->toString();
-  source_kind = getRequestHeader("osm-stats-kind").get()->toString();
-  source_name = getRequestHeader("osm-stats-name").get()->toString();
-  source_pod = getRequestHeader("osm-stats-pod").get()->toString();
-
-  removeRequestHeader("osm-stats-namespace");
-  removeRequestHeader("osm-stats-kind");
-  removeRequestHeader("osm-stats-name");
-  removeRequestHeader("osm-stats-pod");
+  source_namespace = takeRequestHeader("osm-stats-namespace");
+  source_kind = takeRequestHeader("osm-stats-kind");
+  source_name = takeRequestHeader("osm-stats-name");
+  source_pod = takeRequestHeader("osm-stats-pod");
 
   return FilterHeadersStatus::Continue;
 }
 
 FilterHeadersStatus StatsContext::onResponseHeaders(uint32_t headers, bool end_of_stream)
 {
+  std::string response_code = getResponseHeader(":status").get()->toString();
+
   if (isInbound())
   {
+    // The headers describing this proxy are added to the response for the proxy of the client, and must not be removed
+    inbound_rq_total->increment(1, response_code,
+                                source_namespace, source_kind, source_name, source_pod,
+                                destination_namespace, destination_kind, destination_name, destination_pod);
+
     return FilterHeadersStatus::Continue;
   }
 
-  std::string response_code = getResponseHeader(":status").get()->toString();
   destination_namespace = getResponseHeader("osm-stats-namespace").get()->toString();
   destination_kind = getResponseHeader("osm-stats-kind").get()->toString();
   destination_name = getResponseHeader("osm-stats-name").get()->toString();